@@ -8,16 +8,18 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // Users' summary timezones; the runtime image has no zoneinfo
 
 	"github.com/sungminna/upbit-trading-platform/internal/api/router"
-	"github.com/sungminna/upbit-trading-platform/internal/upbit/quotation"
+	"github.com/sungminna/upbit-trading-platform/internal/app"
 )
 
 func main() {
-	// Configuration (in production, use environment variables or config file)
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		jwtSecret = "your-secret-key-change-this-in-production"
+	// APP_PROFILE (prod, paper or test) picks the storage and exchange;
+	// everything else is configured with environment variables
+	settings, err := app.SettingsFromEnv()
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
 	}
 
 	port := os.Getenv("PORT")
@@ -25,15 +27,14 @@ func main() {
 		port = "8080"
 	}
 
-	// Initialize Upbit clients
-	quotationClient := quotation.NewClient()
+	application, err := app.New(context.Background(), settings)
+	if err != nil {
+		log.Fatalf("Failed to start: %v", err)
+	}
+	defer application.Close()
 
 	// Setup router
-	r := router.Setup(&router.Config{
-		JWTSecret:       jwtSecret,
-		JWTExpiry:       24 * time.Hour,
-		QuotationClient: quotationClient,
-	})
+	r := router.Setup(application.RouterConfig())
 
 	// Create server
 	srv := &http.Server{
@@ -55,6 +56,8 @@ func main() {
 	<-quit
 	log.Println("Shutting down server...")
 
+	application.StopTrading()
+
 	// Graceful shutdown with 5 second timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -0,0 +1,296 @@
+// Command smoketest checks a deployed instance end to end, for use as a
+// deployment gate: it signs up a fresh user, opens their paper account,
+// buys into a market, guards the position with a drawdown guard and replays
+// a historical crash into the live price feed, expecting the guard to sell.
+// It exits non-zero at the first step that fails.
+//
+// The instance must run a profile other than prod, so replays may reach the
+// live feed, and share JWT_SECRET (and JWT_ISSUER/JWT_AUDIENCE, if set) and
+// ADMIN_TOKEN with the smoke test.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	jwtpkg "github.com/sungminna/upbit-trading-platform/pkg/jwt"
+)
+
+// pollInterval is how often the smoke test checks for an outcome
+const pollInterval = time.Second
+
+// config is what the smoke test runs against
+type config struct {
+	baseURL     string
+	adminToken  string
+	market      string
+	percent     float64 // Of the paper account bought into the market
+	maxDrawdown float64
+	replayFrom  time.Time
+	replayTo    time.Time
+	timeout     time.Duration
+}
+
+func main() {
+	cfg := config{
+		adminToken: os.Getenv("ADMIN_TOKEN"),
+	}
+	var from, to string
+	flag.StringVar(&cfg.baseURL, "url", "http://localhost:8080", "base URL of the instance")
+	flag.StringVar(&cfg.market, "market", "KRW-BTC", "KRW market to buy and guard")
+	flag.Float64Var(&cfg.percent, "percent", 1, "percent of the paper account to buy")
+	flag.Float64Var(&cfg.maxDrawdown, "max-drawdown", 5, "drawdown guard limit, in percent below the peak")
+	// Prices in early 2020 are far below today's, so replaying them breaches
+	// any reasonable guard
+	flag.StringVar(&from, "replay-from", "2020-03-12T00:00:00Z", "start of the replayed history")
+	flag.StringVar(&to, "replay-to", "2020-03-13T00:00:00Z", "end of the replayed history")
+	flag.DurationVar(&cfg.timeout, "timeout", 2*time.Minute, "how long to wait for the whole run")
+	flag.Parse()
+
+	var err error
+	if cfg.replayFrom, err = time.Parse(time.RFC3339, from); err != nil {
+		log.Fatalf("Invalid -replay-from: %v", err)
+	}
+	if cfg.replayTo, err = time.Parse(time.RFC3339, to); err != nil {
+		log.Fatalf("Invalid -replay-to: %v", err)
+	}
+	if cfg.adminToken == "" {
+		log.Fatal("ADMIN_TOKEN is required to replay prices")
+	}
+	if !strings.HasPrefix(cfg.market, "KRW-") {
+		log.Fatalf("Invalid -market %q: use a KRW market", cfg.market)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.timeout)
+	defer cancel()
+
+	if err := run(ctx, cfg); err != nil {
+		log.Fatalf("Smoke test failed: %v", err)
+	}
+	log.Println("Smoke test passed")
+}
+
+// run performs every step against the instance
+func run(ctx context.Context, cfg config) error {
+	c := &client{baseURL: strings.TrimRight(cfg.baseURL, "/"), http: &http.Client{Timeout: 30 * time.Second}}
+
+	if err := c.do(ctx, http.MethodGet, "/health", nil, http.StatusOK, nil); err != nil {
+		return fmt.Errorf("health check: %w", err)
+	}
+
+	// Users sign up with the identity provider issuing the platform's tokens,
+	// so a fresh user is a token for a new ID
+	token, userID, err := signUp()
+	if err != nil {
+		return fmt.Errorf("sign up: %w", err)
+	}
+	c.token = token
+	log.Printf("Signed up user %s", userID)
+
+	// The paper account is the user's sandbox API key
+	var onboarding struct {
+		Mode string `json:"mode"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/api/v1/onboarding/paper-account", nil, http.StatusCreated, &onboarding); err != nil {
+		return fmt.Errorf("open paper account: %w", err)
+	}
+	if onboarding.Mode != "paper" {
+		return fmt.Errorf("open paper account: trading mode is %q, want paper", onboarding.Mode)
+	}
+	log.Println("Opened a paper account")
+
+	// A one-off rebalance places the buy through the trading engine
+	currency := strings.TrimPrefix(cfg.market, "KRW-")
+	target := map[string]any{"weights": []model.TargetWeight{
+		{Currency: "KRW", Percent: 100 - cfg.percent},
+		{Currency: currency, Percent: cfg.percent},
+	}}
+	if err := c.do(ctx, http.MethodPut, "/api/v1/rebalance/target", target, http.StatusOK, nil); err != nil {
+		return fmt.Errorf("set target portfolio: %w", err)
+	}
+	var rebalance struct {
+		Orders []model.Order `json:"orders"`
+		Failed []struct {
+			Error string `json:"error"`
+		} `json:"failed"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/api/v1/rebalance", nil, http.StatusOK, &rebalance); err != nil {
+		return fmt.Errorf("place paper order: %w", err)
+	}
+	if len(rebalance.Failed) > 0 {
+		return fmt.Errorf("place paper order: %s", rebalance.Failed[0].Error)
+	}
+	if len(rebalance.Orders) == 0 {
+		return errors.New("place paper order: no order was placed")
+	}
+	log.Printf("Placed paper order %s", rebalance.Orders[0].ID)
+
+	var position model.Position
+	err = poll(ctx, func() (bool, error) {
+		var positions []model.Position
+		if err := c.do(ctx, http.MethodGet, "/api/v1/positions?status=open", nil, http.StatusOK, &positions); err != nil {
+			return false, err
+		}
+		for _, p := range positions {
+			if p.Market == cfg.market && p.Quantity > 0 {
+				position = p
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return fmt.Errorf("wait for the order to fill: %w", err)
+	}
+	log.Printf("Opened position %s of %g %s", position.ID, position.Quantity, currency)
+
+	guardPath := "/api/v1/positions/" + position.ID.String() + "/drawdown-guard"
+	if err := c.do(ctx, http.MethodPut, guardPath, map[string]any{"max_drawdown": cfg.maxDrawdown}, http.StatusOK, nil); err != nil {
+		return fmt.Errorf("attach drawdown guard: %w", err)
+	}
+	log.Printf("Attached a %g%% drawdown guard", cfg.maxDrawdown)
+
+	c.admin = cfg.adminToken
+	replay := map[string]any{
+		"markets":  []string{cfg.market},
+		"interval": model.CandleInterval1h,
+		"from":     cfg.replayFrom,
+		"to":       cfg.replayTo,
+		"live":     true,
+	}
+	if err := c.do(ctx, http.MethodPost, "/api/v1/admin/replay", replay, http.StatusAccepted, nil); err != nil {
+		return fmt.Errorf("replay prices: %w", err)
+	}
+	c.admin = ""
+	log.Printf("Replaying %s from %s to %s", cfg.market, cfg.replayFrom.Format(time.RFC3339), cfg.replayTo.Format(time.RFC3339))
+
+	var guard model.DrawdownGuard
+	err = poll(ctx, func() (bool, error) {
+		if err := c.do(ctx, http.MethodGet, guardPath, nil, http.StatusOK, &guard); err != nil {
+			return false, err
+		}
+		return guard.TriggeredAt != nil, nil
+	})
+	if err != nil {
+		return fmt.Errorf("wait for the guard to trigger: %w", err)
+	}
+	if guard.ExitOrderID == nil {
+		return errors.New("guard triggered without an exit order")
+	}
+	log.Printf("Guard triggered at %g with exit order %s", *guard.TriggerPrice, guard.ExitOrderID)
+
+	err = poll(ctx, func() (bool, error) {
+		var positions []model.Position
+		if err := c.do(ctx, http.MethodGet, "/api/v1/positions?status=closed", nil, http.StatusOK, &positions); err != nil {
+			return false, err
+		}
+		for _, p := range positions {
+			if p.ID == position.ID {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return fmt.Errorf("wait for the exit to fill: %w", err)
+	}
+	log.Println("Position closed by the guard")
+	return nil
+}
+
+// signUp returns a token for a new user, signed like the server's tokens
+func signUp() (string, uuid.UUID, error) {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		return "", uuid.Nil, errors.New("JWT_SECRET is required")
+	}
+	manager := jwtpkg.NewManager(secret, time.Hour).WithIssuer(os.Getenv("JWT_ISSUER"))
+	if audience := os.Getenv("JWT_AUDIENCE"); audience != "" {
+		manager.WithAudience(strings.Split(audience, ",")...)
+	}
+
+	userID := uuid.New()
+	token, err := manager.Generate(userID, "smoketest+"+userID.String()+"@example.com")
+	return token, userID, err
+}
+
+// poll calls check every pollInterval until it reports done, fails or ctx ends
+func poll(ctx context.Context, check func() (bool, error)) error {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		done, err := check()
+		if err != nil || done {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// client calls the instance's API as the signed up user, or as an admin
+// while admin is set
+type client struct {
+	baseURL string
+	http    *http.Client
+	token   string
+	admin   string
+}
+
+// do sends a JSON request and decodes the response into out, failing unless
+// the response has the wanted status
+func (c *client) do(ctx context.Context, method, path string, body any, want int, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.admin != "" {
+		req.Header.Set("X-Admin-Token", c.admin)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != want {
+		return fmt.Errorf("%s %s returned %d: %s", method, path, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(data, out)
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sungminna/upbit-trading-platform/internal/api/middleware"
+	"github.com/sungminna/upbit-trading-platform/internal/service/balance"
+)
+
+// AccountHandler handles exchange account endpoints
+type AccountHandler struct {
+	balances *balance.Service
+}
+
+// NewAccountHandler creates a new account handler
+func NewAccountHandler(balances *balance.Service) *AccountHandler {
+	return &AccountHandler{balances: balances}
+}
+
+// GetAccounts returns the user's balance of each currency: free and locked
+// on Upbit, how much of the locked funds the platform's open orders hold, what
+// accepted orders not yet submitted reserve, and what remains available
+// GET /api/v1/accounts?refresh=true
+func (h *AccountHandler) GetAccounts(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	if c.Query("refresh") == "true" {
+		h.balances.Invalidate(c.Request.Context(), userID)
+	}
+
+	available, err := h.balances.Available(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, available)
+}
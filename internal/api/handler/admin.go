@@ -0,0 +1,272 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/queue"
+	"github.com/sungminna/upbit-trading-platform/internal/service/retention"
+	"github.com/sungminna/upbit-trading-platform/internal/service/scheduler"
+	"github.com/sungminna/upbit-trading-platform/pkg/callstats"
+	"github.com/sungminna/upbit-trading-platform/pkg/httppool"
+	"github.com/sungminna/upbit-trading-platform/pkg/latency"
+	"github.com/sungminna/upbit-trading-platform/pkg/ratelimit"
+	"github.com/sungminna/upbit-trading-platform/pkg/recovery"
+)
+
+// AdminHandler handles operator endpoints
+type AdminHandler struct {
+	marketData repository.MarketDataMaintenance
+	jobs       *scheduler.Scheduler
+	queue      *queue.Queue
+	rateLimits map[string]*ratelimit.Metrics
+	apiCalls   map[string]*callstats.Recorder
+	transports map[string]*httppool.Transport
+	latency    *latency.Recorder
+	candles    repository.CandleStoreMonitor
+	retention  *retention.Service
+	panics     *recovery.Recorder
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(marketData repository.MarketDataMaintenance) *AdminHandler {
+	return &AdminHandler{
+		marketData: marketData,
+	}
+}
+
+// WithJobs enables reporting the status of scheduled jobs
+func (h *AdminHandler) WithJobs(jobs *scheduler.Scheduler) *AdminHandler {
+	h.jobs = jobs
+	return h
+}
+
+// GetJobs reports the schedule and recent runs of every scheduled job
+// GET /api/v1/admin/jobs
+func (h *AdminHandler) GetJobs(c *gin.Context) {
+	c.JSON(http.StatusOK, h.jobs.Statuses())
+}
+
+// WithQueue enables inspecting and retrying queued jobs
+func (h *AdminHandler) WithQueue(q *queue.Queue) *AdminHandler {
+	h.queue = q
+	return h
+}
+
+// ListQueuedJobs lists queued jobs, newest first
+// GET /api/v1/admin/queue/jobs?kind=order.submit&status=failed&limit=20
+func (h *AdminHandler) ListQueuedJobs(c *gin.Context) {
+	filter := repository.JobQueueFilter{
+		Kind:   c.Query("kind"),
+		Status: model.QueuedJobStatus(c.Query("status")),
+	}
+	if s := c.Query("limit"); s != "" {
+		var err error
+		if filter.Limit, err = strconv.Atoi(s); err != nil || filter.Limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit parameter"})
+			return
+		}
+	}
+
+	jobs, err := h.queue.List(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if jobs == nil {
+		jobs = []*model.QueuedJob{}
+	}
+
+	c.JSON(http.StatusOK, jobs)
+}
+
+// RetryQueuedJob gives a failed job a fresh set of attempts
+// POST /api/v1/admin/queue/jobs/:id/retry
+func (h *AdminHandler) RetryQueuedJob(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid job id"})
+		return
+	}
+
+	job, err := h.queue.Retry(c.Request.Context(), id)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "job not found"})
+		return
+	case errors.Is(err, queue.ErrNotFailed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+// WithRateLimits enables reporting the metrics of the rate limiters, by API
+func (h *AdminHandler) WithRateLimits(rateLimits map[string]*ratelimit.Metrics) *AdminHandler {
+	h.rateLimits = rateLimits
+	return h
+}
+
+// GetRateLimits reports calls, throttling, queued calls and token levels of
+// the Upbit rate limiters, to spot saturated quotas before calls time out
+// GET /api/v1/admin/rate-limits
+func (h *AdminHandler) GetRateLimits(c *gin.Context) {
+	snapshots := make(map[string]ratelimit.MetricsSnapshot, len(h.rateLimits))
+	for name, metrics := range h.rateLimits {
+		snapshots[name] = metrics.Snapshot()
+	}
+	c.JSON(http.StatusOK, snapshots)
+}
+
+// WithAPICalls enables reporting the outcomes of Upbit API calls, by API
+func (h *AdminHandler) WithAPICalls(apiCalls map[string]*callstats.Recorder) *AdminHandler {
+	h.apiCalls = apiCalls
+	return h
+}
+
+// WithConnections enables reporting the connection pools of the Upbit APIs,
+// by API
+func (h *AdminHandler) WithConnections(transports map[string]*httppool.Transport) *AdminHandler {
+	h.transports = transports
+	return h
+}
+
+// GetConnections reports, per Upbit API, the connections its clients share:
+// how many are open, were dialed or failed to dial, how many requests reused
+// one and how many TLS handshakes resumed a session
+// GET /api/v1/admin/connections
+func (h *AdminHandler) GetConnections(c *gin.Context) {
+	snapshots := make(map[string]httppool.Snapshot, len(h.transports))
+	for name, transport := range h.transports {
+		snapshots[name] = transport.Snapshot()
+	}
+	c.JSON(http.StatusOK, gin.H{"apis": snapshots})
+}
+
+// GetAPICalls reports, per Upbit API and endpoint, how many calls succeeded,
+// were rejected (4xx, 429), failed upstream (5xx) or got no response, with a
+// latency histogram, so a degrading exchange shows up before users notice
+// GET /api/v1/admin/api-calls
+func (h *AdminHandler) GetAPICalls(c *gin.Context) {
+	snapshots := make(map[string][]callstats.EndpointSnapshot, len(h.apiCalls))
+	for name, recorder := range h.apiCalls {
+		snapshots[name] = recorder.Snapshot()
+	}
+	c.JSON(http.StatusOK, gin.H{"latency_buckets_ms": callstats.LatencyBucketsMs, "apis": snapshots})
+}
+
+// WithLatency enables reporting how far local processing trails Upbit
+func (h *AdminHandler) WithLatency(recorder *latency.Recorder) *AdminHandler {
+	h.latency = recorder
+	return h
+}
+
+// GetLatency reports, per pipeline stage, histograms of the time from a
+// trade's timestamp on Upbit to its price reaching the feed and to the
+// decisions taken on it, to quantify how stale triggers are
+// GET /api/v1/admin/latency
+func (h *AdminHandler) GetLatency(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"buckets_ms": latency.BucketsMs, "stages": h.latency.Snapshot()})
+}
+
+// WithPanics enables reporting the panics recovered in background work
+func (h *AdminHandler) WithPanics(panics *recovery.Recorder) *AdminHandler {
+	h.panics = panics
+	return h
+}
+
+// GetPanics reports, per background task, how many panics were recovered and
+// the latest one, so a worker failing on some input shows up without
+// reading the logs
+// GET /api/v1/admin/panics
+func (h *AdminHandler) GetPanics(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"tasks": h.panics.Snapshot()})
+}
+
+// WithCandleStatus enables reporting whether candles reach ClickHouse
+func (h *AdminHandler) WithCandleStatus(candles repository.CandleStoreMonitor) *AdminHandler {
+	h.candles = candles
+	return h
+}
+
+// GetCandleStorage reports whether ClickHouse is reachable and, while it is
+// down, how many candle writes are buffered and how many were dropped
+// GET /api/v1/admin/storage/candles
+func (h *AdminHandler) GetCandleStorage(c *gin.Context) {
+	c.JSON(http.StatusOK, h.candles.Status())
+}
+
+// WithRetention enables monitoring and triggering the trading record
+// retention cleanup
+func (h *AdminHandler) WithRetention(service *retention.Service) *AdminHandler {
+	h.retention = service
+	return h
+}
+
+// GetRetention reports the trading record retention policy, the latest
+// cleanup and how many records have expired since
+// GET /api/v1/admin/retention
+func (h *AdminHandler) GetRetention(c *gin.Context) {
+	status, err := h.retention.Status(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// RunRetention archives or purges expired trading records now
+// POST /api/v1/admin/retention/run
+func (h *AdminHandler) RunRetention(c *gin.Context) {
+	err := h.retention.Trigger(c.Request.Context())
+	if errors.Is(err, retention.ErrRunning) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"status": "cleanup started"})
+}
+
+// GetStorageTables reports the size of the market data tables
+// GET /api/v1/admin/storage/tables
+func (h *AdminHandler) GetStorageTables(c *gin.Context) {
+	sizes, err := h.marketData.TableSizes(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sizes)
+}
+
+// CleanupStorage removes expired market data now
+// POST /api/v1/admin/storage/cleanup?table=ticks
+func (h *AdminHandler) CleanupStorage(c *gin.Context) {
+	table := c.Query("table")
+
+	err := h.marketData.Cleanup(c.Request.Context(), table)
+	if errors.Is(err, repository.ErrUnknownTable) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"status": "cleanup started"})
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/api/middleware"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/alert"
+	"github.com/sungminna/upbit-trading-platform/pkg/symbol"
+)
+
+// AlertHandler handles price alert endpoints
+type AlertHandler struct {
+	alerts  *alert.Service
+	symbols *symbol.Registry // Optional; accepts normalized symbols such as BTC/KRW
+}
+
+// NewAlertHandler creates a new alert handler
+func NewAlertHandler(alerts *alert.Service) *AlertHandler {
+	return &AlertHandler{
+		alerts: alerts,
+	}
+}
+
+// WithSymbols makes the handler accept normalized symbols as alert markets
+func (h *AlertHandler) WithSymbols(symbols *symbol.Registry) *AlertHandler {
+	h.symbols = symbols
+	return h
+}
+
+// CreateAlertRequest is the body of a create alert request
+type CreateAlertRequest struct {
+	Market    string               `json:"market"`
+	Condition model.AlertCondition `json:"condition"`
+	Price     float64              `json:"price"`
+	Mode      model.AlertMode      `json:"mode"` // Defaults to once
+}
+
+// CreateAlert creates a price alert
+// POST /api/v1/alerts
+func (h *AlertHandler) CreateAlert(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req CreateAlertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Mode == "" {
+		req.Mode = model.AlertModeOnce
+	}
+	if req.Market, err = resolveMarket(h.symbols, req.Market); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	priceAlert := model.NewPriceAlert(userID, req.Market, req.Condition, req.Price, req.Mode, time.Now())
+	err = h.alerts.Create(c.Request.Context(), priceAlert)
+	var alertErr *alert.AlertError
+	switch {
+	case errors.As(err, &alertErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, priceAlert)
+}
+
+// ListAlerts lists the user's price alerts, newest first
+// GET /api/v1/alerts
+func (h *AlertHandler) ListAlerts(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	alerts, err := h.alerts.List(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if alerts == nil {
+		alerts = []*model.PriceAlert{}
+	}
+
+	c.JSON(http.StatusOK, alerts)
+}
+
+// DeleteAlert deletes one of the user's price alerts
+// DELETE /api/v1/alerts/:id
+func (h *AlertHandler) DeleteAlert(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid alert id"})
+		return
+	}
+
+	err = h.alerts.Delete(c.Request.Context(), userID, id)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "alert not found"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
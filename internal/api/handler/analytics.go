@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/service/analytics"
+	"github.com/sungminna/upbit-trading-platform/pkg/symbol"
+)
+
+// AnalyticsHandler handles market analytics endpoints
+type AnalyticsHandler struct {
+	analytics *analytics.Service
+	symbols   *symbol.Registry // Optional; accepts normalized symbols such as BTC/KRW
+}
+
+// NewAnalyticsHandler creates a new analytics handler
+func NewAnalyticsHandler(analytics *analytics.Service) *AnalyticsHandler {
+	return &AnalyticsHandler{analytics: analytics}
+}
+
+// WithSymbols makes the handler accept normalized symbols, e.g. BTC/KRW, as
+// well as Upbit market codes
+func (h *AnalyticsHandler) WithSymbols(symbols *symbol.Registry) *AnalyticsHandler {
+	h.symbols = symbols
+	return h
+}
+
+// GetCorrelation returns the correlation matrix of markets' returns
+// GET /api/v1/analytics/correlation?markets=KRW-BTC,ETH/KRW&interval=1d&window=90
+func (h *AnalyticsHandler) GetCorrelation(c *gin.Context) {
+	var markets []string
+	if s := c.Query("markets"); s != "" {
+		markets = strings.Split(s, ",")
+	}
+	for i, market := range markets {
+		resolved, err := resolveMarket(h.symbols, strings.TrimSpace(market))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		markets[i] = resolved
+	}
+
+	var window int
+	if s := c.Query("window"); s != "" {
+		var err error
+		if window, err = strconv.Atoi(s); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid window parameter"})
+			return
+		}
+	}
+	interval := model.CandleInterval(c.DefaultQuery("interval", string(model.CandleInterval1d)))
+
+	matrix, err := h.analytics.Correlation(c.Request.Context(), markets, interval, window)
+	if err != nil {
+		var analyticsErr *analytics.AnalyticsError
+		if errors.As(err, &analyticsErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, matrix)
+}
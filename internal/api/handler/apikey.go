@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/api/middleware"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/apikey"
+)
+
+// APIKeyHandler handles endpoints about users' Upbit API keys
+type APIKeyHandler struct {
+	apiKeys *apikey.Service
+}
+
+// NewAPIKeyHandler creates a new API key handler
+func NewAPIKeyHandler(apiKeys *apikey.Service) *APIKeyHandler {
+	return &APIKeyHandler{apiKeys: apiKeys}
+}
+
+// GetPermissions returns which scopes one of the user's API keys has on
+// Upbit and when it expires, with a warning when that is within 30 days
+// GET /api/v1/users/api-keys/:id/permissions
+func (h *APIKeyHandler) GetPermissions(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	keyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid API key id"})
+		return
+	}
+
+	perms, err := h.apiKeys.Permissions(c.Request.Context(), userID, keyID)
+	if err != nil {
+		writeAPIKeyError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, perms)
+}
+
+func writeAPIKeyError(c *gin.Context, err error) {
+	var keyErr *apikey.APIKeyError
+	switch {
+	case errors.As(err, &keyErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
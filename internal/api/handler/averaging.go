@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/api/middleware"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/averaging"
+	"github.com/sungminna/upbit-trading-platform/internal/service/trading"
+)
+
+// AveragingHandler handles average-down planning endpoints
+type AveragingHandler struct {
+	planner *averaging.Planner
+}
+
+// NewAveragingHandler creates a new averaging handler
+func NewAveragingHandler(planner *averaging.Planner) *AveragingHandler {
+	return &AveragingHandler{planner: planner}
+}
+
+// Preview computes the limit buy that brings one of the user's positions to
+// a target average entry, placing it when execute is set
+// POST /api/v1/positions/:id/average-down/preview
+func (h *AveragingHandler) Preview(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	positionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid position id"})
+		return
+	}
+
+	var req averaging.Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	plan, err := h.planner.Plan(c.Request.Context(), userID, positionID, req)
+	var averagingErr *averaging.AveragingError
+	var tradingErr *trading.TradingError
+	switch {
+	case errors.As(err, &averagingErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "position not found"})
+	case errors.Is(err, trading.ErrVelocityLimit):
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	case errors.Is(err, trading.ErrMaintenance):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	case errors.As(err, &tradingErr), errors.Is(err, trading.ErrInsufficientFunds):
+		// The plan is sound but the engine refused the order
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusOK, plan)
+	}
+}
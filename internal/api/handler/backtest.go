@@ -0,0 +1,346 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/api/middleware"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/backtest"
+	"github.com/sungminna/upbit-trading-platform/pkg/symbol"
+)
+
+// maxComparedRuns bounds the runs in a single comparison
+const maxComparedRuns = 10
+
+// BacktestHandler handles backtesting endpoints
+type BacktestHandler struct {
+	backtester *backtest.Backtester
+	runs       repository.BacktestRepository // Optional; runs aren't stored when nil
+	divergence *backtest.DivergenceTracker   // Optional; requires stored runs and orders
+	symbols    *symbol.Registry              // Optional; accepts normalized symbols such as BTC/KRW
+	jobs       *backtest.Jobs                // Optional; runs backtests on the job queue with ?async=true
+}
+
+// NewBacktestHandler creates a new backtest handler
+func NewBacktestHandler(backtester *backtest.Backtester, runs repository.BacktestRepository, divergence *backtest.DivergenceTracker) *BacktestHandler {
+	return &BacktestHandler{
+		backtester: backtester,
+		runs:       runs,
+		divergence: divergence,
+	}
+}
+
+// WithSymbols makes the handler accept normalized symbols as backtest markets
+func (h *BacktestHandler) WithSymbols(symbols *symbol.Registry) *BacktestHandler {
+	h.symbols = symbols
+	return h
+}
+
+// WithJobs lets clients queue backtests with ?async=true instead of waiting
+// for them
+func (h *BacktestHandler) WithJobs(jobs *backtest.Jobs) *BacktestHandler {
+	h.jobs = jobs
+	return h
+}
+
+// GetStrategies lists the strategies available to backtests
+// GET /api/v1/backtests/strategies
+func (h *BacktestHandler) GetStrategies(c *gin.Context) {
+	c.JSON(http.StatusOK, backtest.StrategyNames())
+}
+
+// RunBacktest runs a single backtest, or queues it with ?async=true
+// POST /api/v1/backtests/run
+func (h *BacktestHandler) RunBacktest(c *gin.Context) {
+	var cfg backtest.Config
+	if err := c.ShouldBindJSON(&cfg); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !h.resolveMarket(c, &cfg.Market) {
+		return
+	}
+	if c.Query("async") == "true" {
+		h.enqueue(c, func(ctx context.Context, userID uuid.UUID) (*model.QueuedJob, error) {
+			return h.jobs.EnqueueRun(ctx, userID, cfg)
+		})
+		return
+	}
+
+	result, err := h.backtester.Run(c.Request.Context(), cfg)
+	if err != nil {
+		respondBacktestError(c, err)
+		return
+	}
+
+	if h.runs != nil {
+		userID, err := middleware.GetUserID(c)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		if err := backtest.SaveRun(c.Request.Context(), h.runs, userID, result); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ListBacktests lists the user's stored runs, newest first
+// GET /api/v1/backtests?market=KRW-BTC&strategy=trailing_stop&limit=20
+func (h *BacktestHandler) ListBacktests(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter := repository.BacktestFilter{
+		Market:   c.Query("market"),
+		Strategy: c.Query("strategy"),
+	}
+	if !h.resolveMarket(c, &filter.Market) {
+		return
+	}
+	if s := c.Query("limit"); s != "" {
+		if filter.Limit, err = strconv.Atoi(s); err != nil || filter.Limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit parameter"})
+			return
+		}
+	}
+
+	runs, err := h.runs.ListByUser(c.Request.Context(), userID, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if runs == nil {
+		runs = []*model.BacktestRun{}
+	}
+
+	c.JSON(http.StatusOK, runs)
+}
+
+// GetBacktest returns a stored run with its trades
+// GET /api/v1/backtests/:id
+func (h *BacktestHandler) GetBacktest(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid backtest id"})
+		return
+	}
+
+	run, ok := h.getOwnedRun(c, id)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, run)
+}
+
+// CompareBacktests diffs the metrics of stored runs against the first one
+// GET /api/v1/backtests/compare?ids=<baseline>,<id>,...
+func (h *BacktestHandler) CompareBacktests(c *gin.Context) {
+	parts := strings.Split(c.Query("ids"), ",")
+	if len(parts) < 2 || len(parts) > maxComparedRuns {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("ids must list 2 to %d backtests", maxComparedRuns)})
+		return
+	}
+
+	runs := make([]*model.BacktestRun, 0, len(parts))
+	for _, part := range parts {
+		id, err := uuid.Parse(strings.TrimSpace(part))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid backtest id"})
+			return
+		}
+
+		run, ok := h.getOwnedRun(c, id)
+		if !ok {
+			return
+		}
+		runs = append(runs, run)
+	}
+
+	c.JSON(http.StatusOK, backtest.Compare(runs))
+}
+
+// GetBacktestDivergence compares the run's strategy, replayed over a live
+// window, with the user's executions in the run's market. The window defaults
+// to the time since the run and fills may lag a trigger by one candle.
+// GET /api/v1/backtests/:id/divergence?from=...&to=...&tolerance=5m
+func (h *BacktestHandler) GetBacktestDivergence(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid backtest id"})
+		return
+	}
+
+	run, ok := h.getOwnedRun(c, id)
+	if !ok {
+		return
+	}
+
+	from, to := run.CreatedAt, time.Now()
+	if s := c.Query("from"); s != "" {
+		if from, err = time.Parse(time.RFC3339, s); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from parameter"})
+			return
+		}
+	}
+	if s := c.Query("to"); s != "" {
+		if to, err = time.Parse(time.RFC3339, s); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to parameter"})
+			return
+		}
+	}
+	tolerance := run.Interval.Duration()
+	if s := c.Query("tolerance"); s != "" {
+		if tolerance, err = time.ParseDuration(s); err != nil || tolerance < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tolerance parameter"})
+			return
+		}
+	}
+
+	report, err := h.divergence.Report(c.Request.Context(), run, from, to, tolerance)
+	if err != nil {
+		respondBacktestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// getOwnedRun loads a run of the authenticated user, responding with an
+// error when it doesn't exist or belongs to someone else
+func (h *BacktestHandler) getOwnedRun(c *gin.Context, id uuid.UUID) (*model.BacktestRun, bool) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	run, err := h.runs.GetByID(c.Request.Context(), id)
+	if errors.Is(err, repository.ErrNotFound) || err == nil && run.UserID != userID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "backtest not found"})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return run, true
+}
+
+// OptimizeBacktest grid-searches strategy parameters and returns ranked
+// results, or queues the search with ?async=true
+// POST /api/v1/backtests/optimize
+func (h *BacktestHandler) OptimizeBacktest(c *gin.Context) {
+	var cfg backtest.OptimizeConfig
+	if err := c.ShouldBindJSON(&cfg); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !h.resolveMarket(c, &cfg.Base.Market) {
+		return
+	}
+	if c.Query("async") == "true" {
+		h.enqueue(c, func(ctx context.Context, userID uuid.UUID) (*model.QueuedJob, error) {
+			return h.jobs.EnqueueOptimize(ctx, userID, cfg)
+		})
+		return
+	}
+
+	results, err := h.backtester.Optimize(c.Request.Context(), cfg)
+	if err != nil {
+		respondBacktestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// WalkForwardBacktest compares optimized in-sample results with out-of-sample
+// results on rolling windows to expose overfitting, or queues the analysis
+// with ?async=true
+// POST /api/v1/backtests/walk-forward
+func (h *BacktestHandler) WalkForwardBacktest(c *gin.Context) {
+	var cfg backtest.WalkForwardConfig
+	if err := c.ShouldBindJSON(&cfg); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !h.resolveMarket(c, &cfg.Optimize.Base.Market) {
+		return
+	}
+	if c.Query("async") == "true" {
+		h.enqueue(c, func(ctx context.Context, userID uuid.UUID) (*model.QueuedJob, error) {
+			return h.jobs.EnqueueWalkForward(ctx, userID, cfg)
+		})
+		return
+	}
+
+	result, err := h.backtester.WalkForward(c.Request.Context(), cfg)
+	if err != nil {
+		respondBacktestError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// enqueue queues a backtest for the user and responds 202 with its job, to be
+// followed under /api/v1/jobs
+func (h *BacktestHandler) enqueue(c *gin.Context, enqueue func(ctx context.Context, userID uuid.UUID) (*model.QueuedJob, error)) {
+	if h.jobs == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "background backtests require trading storage"})
+		return
+	}
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	job, err := enqueue(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+// resolveMarket replaces a normalized symbol with its Upbit market code,
+// responding 400 if it is invalid
+func (h *BacktestHandler) resolveMarket(c *gin.Context, market *string) bool {
+	resolved, err := resolveMarket(h.symbols, *market)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+	*market = resolved
+	return true
+}
+
+// respondBacktestError maps configuration errors to 400 and anything else,
+// such as failing to load candles, to 500
+func respondBacktestError(c *gin.Context, err error) {
+	var backtestErr *backtest.BacktestError
+	if errors.As(err, &backtestErr) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/api/middleware"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/service/export"
+)
+
+// ExportHandler handles CSV export endpoints
+type ExportHandler struct {
+	export *export.Service
+	jobs   *export.Jobs // Optional; runs exports on the job queue
+}
+
+// NewExportHandler creates a new export handler
+func NewExportHandler(export *export.Service) *ExportHandler {
+	return &ExportHandler{export: export}
+}
+
+// WithJobs lets clients queue exports instead of streaming them
+func (h *ExportHandler) WithJobs(jobs *export.Jobs) *ExportHandler {
+	h.jobs = jobs
+	return h
+}
+
+// exportFunc writes one kind of export
+type exportFunc func(ctx context.Context, w io.Writer, userID uuid.UUID, from, to time.Time) error
+
+// ExportOrders streams the user's orders as CSV
+// GET /api/v1/export/orders.csv?from=2025-01-01T00:00:00Z&to=...
+func (h *ExportHandler) ExportOrders(c *gin.Context) {
+	h.stream(c, "orders", h.export.Orders)
+}
+
+// ExportExecutions streams the fills of the user's orders as CSV
+// GET /api/v1/export/executions.csv?from=2025-01-01T00:00:00Z&to=...
+func (h *ExportHandler) ExportExecutions(c *gin.Context) {
+	h.stream(c, "executions", h.export.Executions)
+}
+
+// ExportTrades streams the user's round trips with their realized PnL as CSV
+// GET /api/v1/export/trades.csv?from=2025-01-01T00:00:00Z&to=...
+func (h *ExportHandler) ExportTrades(c *gin.Context) {
+	h.stream(c, "trades", h.export.Trades)
+}
+
+// stream writes an export covering [from, to), all history by default. Once
+// rows have been sent an error can no longer change the response, so it is
+// logged and the download is cut short.
+func (h *ExportHandler) stream(c *gin.Context, name string, write exportFunc) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	from, to, ok := parseExportRange(c)
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+".csv"))
+	if err := write(c.Request.Context(), c.Writer, userID, from, to); err != nil {
+		if !c.Writer.Written() {
+			c.Header("Content-Disposition", "")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Error exporting %s for user %s: %v", name, userID, err)
+	}
+}
+
+// QueueOrderExport queues an export of the user's orders, with the same
+// parameters as streaming it; the CSV is the job's result
+// POST /api/v1/export/orders.csv?from=2025-01-01T00:00:00Z&to=...
+func (h *ExportHandler) QueueOrderExport(c *gin.Context) {
+	h.enqueue(c, export.OrdersJob)
+}
+
+// QueueExecutionExport queues an export of the fills of the user's orders
+// POST /api/v1/export/executions.csv?from=2025-01-01T00:00:00Z&to=...
+func (h *ExportHandler) QueueExecutionExport(c *gin.Context) {
+	h.enqueue(c, export.ExecutionsJob)
+}
+
+// QueueTradeExport queues an export of the user's round trips
+// POST /api/v1/export/trades.csv?from=2025-01-01T00:00:00Z&to=...
+func (h *ExportHandler) QueueTradeExport(c *gin.Context) {
+	h.enqueue(c, export.TradesJob)
+}
+
+// enqueue queues a history export covering [from, to) and responds 202 with
+// its job, to be followed under /api/v1/jobs
+func (h *ExportHandler) enqueue(c *gin.Context, kind string) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	from, to, ok := parseExportRange(c)
+	if !ok {
+		return
+	}
+
+	job, err := h.jobs.EnqueueHistory(c.Request.Context(), userID, kind, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+// ExportCandles streams a market's candles as CSV. Unlike the history
+// exports, from is required since candles are fetched page by page from it.
+// GET /api/v1/export/candles.csv?market=KRW-BTC&interval=1h&from=2025-01-01T00:00:00Z&to=...
+func (h *ExportHandler) ExportCandles(c *gin.Context) {
+	market, interval, from, to, ok := parseCandleExport(c)
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("candles-%s-%s.csv", market, interval)))
+	if err := h.export.Candles(c.Request.Context(), c.Writer, market, interval, from, to); err != nil {
+		if !c.Writer.Written() {
+			c.Header("Content-Disposition", "")
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("Error exporting %s %s candles: %v", market, interval, err)
+	}
+}
+
+// QueueCandleExport queues an export of a market's candles
+// POST /api/v1/export/candles.csv?market=KRW-BTC&interval=1h&from=2025-01-01T00:00:00Z&to=...
+func (h *ExportHandler) QueueCandleExport(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	market, interval, from, to, ok := parseCandleExport(c)
+	if !ok {
+		return
+	}
+
+	job, err := h.jobs.EnqueueCandles(c.Request.Context(), userID, market, interval, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+// parseCandleExport reads the market, interval and range of a candle export
+// and writes a 400 response when they are invalid
+func parseCandleExport(c *gin.Context) (string, model.CandleInterval, time.Time, time.Time, bool) {
+	var from, to time.Time
+	market := c.Query("market")
+	if market == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "market parameter is required"})
+		return "", "", from, to, false
+	}
+	interval := model.CandleInterval(c.DefaultQuery("interval", string(model.CandleInterval1m)))
+	if interval.Duration() == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid interval parameter"})
+		return "", "", from, to, false
+	}
+	if c.Query("from") == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from parameter is required"})
+		return "", "", from, to, false
+	}
+	from, to, ok := parseExportRange(c)
+	return market, interval, from, to, ok
+}
+
+// parseExportRange reads the from and to query parameters, all history up to
+// now by default, and writes a 400 response when they are invalid
+func parseExportRange(c *gin.Context) (time.Time, time.Time, bool) {
+	var from time.Time
+	to := time.Now()
+	var err error
+	if s := c.Query("from"); s != "" {
+		if from, err = time.Parse(time.RFC3339, s); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from parameter"})
+			return from, to, false
+		}
+	}
+	if s := c.Query("to"); s != "" {
+		if to, err = time.Parse(time.RFC3339, s); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to parameter"})
+			return from, to, false
+		}
+	}
+	if !from.Before(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must be before to"})
+		return from, to, false
+	}
+	return from, to, true
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/api/middleware"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/guard"
+)
+
+// GuardHandler handles drawdown guard endpoints
+type GuardHandler struct {
+	guards *guard.Service
+}
+
+// NewGuardHandler creates a new drawdown guard handler
+func NewGuardHandler(guards *guard.Service) *GuardHandler {
+	return &GuardHandler{guards: guards}
+}
+
+// AttachGuardRequest is the body of an attach drawdown guard request
+type AttachGuardRequest struct {
+	MaxDrawdown     float64              `json:"max_drawdown"`     // Percent below the peak
+	MaxPriceAge     int                  `json:"max_price_age"`    // Seconds; defaults to 30
+	ConfirmInterval model.CandleInterval `json:"confirm_interval"` // e.g. "5m" to trigger only on 5 minute closes
+	DryRun          bool                 `json:"dry_run"`          // Notify on triggers without selling
+}
+
+// AttachGuard attaches a drawdown guard to one of the user's open positions,
+// replacing any guard it has
+// PUT /api/v1/positions/:id/drawdown-guard
+func (h *GuardHandler) AttachGuard(c *gin.Context) {
+	userID, positionID, ok := guardParams(c)
+	if !ok {
+		return
+	}
+
+	var req AttachGuardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	drawdownGuard, err := h.guards.Attach(c.Request.Context(), userID, positionID, guard.AttachOptions{
+		MaxDrawdown:     req.MaxDrawdown,
+		MaxPriceAge:     req.MaxPriceAge,
+		ConfirmInterval: req.ConfirmInterval,
+		DryRun:          req.DryRun,
+	})
+	if err != nil {
+		writeGuardError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, drawdownGuard)
+}
+
+// GetGuard returns the drawdown guard of one of the user's positions
+// GET /api/v1/positions/:id/drawdown-guard
+func (h *GuardHandler) GetGuard(c *gin.Context) {
+	userID, positionID, ok := guardParams(c)
+	if !ok {
+		return
+	}
+
+	drawdownGuard, err := h.guards.Get(c.Request.Context(), userID, positionID)
+	if err != nil {
+		writeGuardError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, drawdownGuard)
+}
+
+// GetHistory returns how the trigger level of the drawdown guard of one of
+// the user's positions moved over the last hours (24 by default), and how far
+// the market is from it now
+// GET /api/v1/positions/:id/drawdown-guard/history?hours=24
+func (h *GuardHandler) GetHistory(c *gin.Context) {
+	userID, positionID, ok := guardParams(c)
+	if !ok {
+		return
+	}
+
+	hours := 24
+	if s := c.Query("hours"); s != "" {
+		var err error
+		if hours, err = strconv.Atoi(s); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hours parameter"})
+			return
+		}
+	}
+
+	history, err := h.guards.History(c.Request.Context(), userID, positionID, time.Duration(hours)*time.Hour)
+	if err != nil {
+		writeGuardError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// DetachGuard removes the drawdown guard of one of the user's positions
+// DELETE /api/v1/positions/:id/drawdown-guard
+func (h *GuardHandler) DetachGuard(c *gin.Context) {
+	userID, positionID, ok := guardParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.guards.Detach(c.Request.Context(), userID, positionID); err != nil {
+		writeGuardError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListGuards lists the user's drawdown guards, newest first
+// GET /api/v1/drawdown-guards
+func (h *GuardHandler) ListGuards(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	guards, err := h.guards.List(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if guards == nil {
+		guards = []*model.DrawdownGuard{}
+	}
+
+	c.JSON(http.StatusOK, guards)
+}
+
+// guardParams returns the authenticated user and the position in the path,
+// writing an error response if either is missing
+func guardParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	positionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid position id"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, positionID, true
+}
+
+func writeGuardError(c *gin.Context, err error) {
+	var guardErr *guard.GuardError
+	switch {
+	case errors.As(err, &guardErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
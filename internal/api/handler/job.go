@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/api/middleware"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/queue"
+)
+
+// JobHandler handles the endpoints users follow their queued jobs with, such
+// as backtests and exports run in the background
+type JobHandler struct {
+	queue *queue.Queue
+}
+
+// NewJobHandler creates a new job handler
+func NewJobHandler(q *queue.Queue) *JobHandler {
+	return &JobHandler{queue: q}
+}
+
+// ListJobs lists the jobs the user started, newest first
+// GET /api/v1/jobs?kind=backtest.run&status=succeeded&limit=20
+func (h *JobHandler) ListJobs(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter := repository.JobQueueFilter{
+		Kind:   c.Query("kind"),
+		Status: model.QueuedJobStatus(c.Query("status")),
+	}
+	if s := c.Query("limit"); s != "" {
+		if filter.Limit, err = strconv.Atoi(s); err != nil || filter.Limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit parameter"})
+			return
+		}
+	}
+
+	jobs, err := h.queue.ListForUser(c.Request.Context(), userID, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if jobs == nil {
+		jobs = []*model.QueuedJob{}
+	}
+
+	c.JSON(http.StatusOK, jobs)
+}
+
+// GetJob returns a job the user started, with its status and last error
+// GET /api/v1/jobs/:id
+func (h *JobHandler) GetJob(c *gin.Context) {
+	userID, id, ok := h.parseJob(c)
+	if !ok {
+		return
+	}
+
+	job, err := h.queue.GetForUser(c.Request.Context(), userID, id)
+	if err != nil {
+		writeJobError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// GetJobResult downloads the result of a job the user started once it has
+// succeeded, e.g. a backtest report as JSON or an export as CSV
+// GET /api/v1/jobs/:id/result
+func (h *JobHandler) GetJobResult(c *gin.Context) {
+	userID, id, ok := h.parseJob(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.queue.Result(c.Request.Context(), userID, id)
+	if err != nil {
+		writeJobError(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, result.ContentType, result.Data)
+}
+
+// parseJob reads the user and the job ID, writing a response when either is
+// missing or invalid
+func (h *JobHandler) parseJob(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid job id"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, id, true
+}
+
+// writeJobError maps job lookup errors to HTTP responses
+func writeJobError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "job not found"})
+	case errors.Is(err, queue.ErrNoResult):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/api/middleware"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/journal"
+)
+
+// JournalHandler handles position and order listing and journal endpoints
+type JournalHandler struct {
+	journal *journal.Service
+}
+
+// NewJournalHandler creates a new journal handler
+func NewJournalHandler(journal *journal.Service) *JournalHandler {
+	return &JournalHandler{journal: journal}
+}
+
+// ListPositions lists the user's positions, newest first
+// GET /api/v1/positions?tag=swing&status=open
+func (h *JournalHandler) ListPositions(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	positions, err := h.journal.Positions(c.Request.Context(), userID, journal.PositionFilter{
+		Tag:    c.Query("tag"),
+		Status: model.PositionStatus(c.Query("status")),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, positions)
+}
+
+// PositionHistory returns how one of the user's positions evolved: its
+// opening, increases, reductions, closing and strategy attachments
+// GET /api/v1/positions/:id/history
+func (h *JournalHandler) PositionHistory(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid position id"})
+		return
+	}
+
+	events, err := h.journal.History(c.Request.Context(), userID, id)
+	if err != nil {
+		writeJournalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+// ListOrders lists the user's orders, newest first
+// GET /api/v1/orders?tag=bot-x&market=KRW-BTC&status=filled
+func (h *JournalHandler) ListOrders(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	orders, err := h.journal.Orders(c.Request.Context(), userID, journal.OrderFilter{
+		Tag:    c.Query("tag"),
+		Market: c.Query("market"),
+		Status: model.OrderStatus(c.Query("status")),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, orders)
+}
+
+// AnnotatePosition replaces the tags and notes of one of the user's positions
+// PUT /api/v1/positions/:id/journal
+func (h *JournalHandler) AnnotatePosition(c *gin.Context) {
+	userID, id, entry, ok := journalParams(c, "invalid position id")
+	if !ok {
+		return
+	}
+
+	position, err := h.journal.AnnotatePosition(c.Request.Context(), userID, id, entry)
+	if err != nil {
+		writeJournalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, position)
+}
+
+// AnnotateOrder replaces the tags and notes of one of the user's orders
+// PUT /api/v1/orders/:id/journal
+func (h *JournalHandler) AnnotateOrder(c *gin.Context) {
+	userID, id, entry, ok := journalParams(c, "invalid order id")
+	if !ok {
+		return
+	}
+
+	order, err := h.journal.AnnotateOrder(c.Request.Context(), userID, id, entry)
+	if err != nil {
+		writeJournalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// journalParams returns the authenticated user, the ID in the path and the
+// journal entry in the body, writing an error response if any is missing
+func journalParams(c *gin.Context, invalidID string) (uuid.UUID, uuid.UUID, journal.Entry, bool) {
+	var entry journal.Entry
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return uuid.Nil, uuid.Nil, entry, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": invalidID})
+		return uuid.Nil, uuid.Nil, entry, false
+	}
+
+	if err := c.ShouldBindJSON(&entry); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return uuid.Nil, uuid.Nil, entry, false
+	}
+	return userID, id, entry, true
+}
+
+func writeJournalError(c *gin.Context, err error) {
+	var journalErr *journal.JournalError
+	switch {
+	case errors.As(err, &journalErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
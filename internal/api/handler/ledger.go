@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sungminna/upbit-trading-platform/internal/api/middleware"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/ledger"
+)
+
+// LedgerHandler handles cash ledger endpoints
+type LedgerHandler struct {
+	ledger *ledger.Service
+}
+
+// NewLedgerHandler creates a new ledger handler
+func NewLedgerHandler(ledger *ledger.Service) *LedgerHandler {
+	return &LedgerHandler{ledger: ledger}
+}
+
+// ListEntries returns the user's cash ledger entries, newest first. The
+// window defaults to the last 30 days.
+// GET /api/v1/ledger?from=2025-01-01T00:00:00Z&to=...
+func (h *LedgerHandler) ListEntries(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	to := time.Now()
+	if s := c.Query("to"); s != "" {
+		if to, err = time.Parse(time.RFC3339, s); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to parameter"})
+			return
+		}
+	}
+	from := to.Add(-defaultReportWindow)
+	if s := c.Query("from"); s != "" {
+		if from, err = time.Parse(time.RFC3339, s); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from parameter"})
+			return
+		}
+	}
+
+	entries, err := h.ledger.Entries(c.Request.Context(), userID, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if entries == nil {
+		entries = []*model.CashLedgerEntry{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"entries": entries})
+}
+
+// Reconcile records the user's new cash movements and compares the ledger
+// balance with their exchange KRW balance
+// GET /api/v1/ledger/reconciliation
+func (h *LedgerHandler) Reconcile(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	reconciliation, err := h.ledger.Sync(c.Request.Context(), userID)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "no active API key"})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusOK, reconciliation)
+	}
+}
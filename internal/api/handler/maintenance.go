@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/maintenance"
+)
+
+// MaintenanceHandler handles Upbit maintenance endpoints
+type MaintenanceHandler struct {
+	detector *maintenance.Detector
+}
+
+// NewMaintenanceHandler creates a new maintenance handler
+func NewMaintenanceHandler(detector *maintenance.Detector) *MaintenanceHandler {
+	return &MaintenanceHandler{detector: detector}
+}
+
+// CreateMaintenanceWindowRequest is the body of a create maintenance window request
+type CreateMaintenanceWindowRequest struct {
+	Market   string    `json:"market"` // Empty for every market
+	Reason   string    `json:"reason"`
+	StartsAt time.Time `json:"starts_at" binding:"required"`
+	EndsAt   time.Time `json:"ends_at" binding:"required"`
+}
+
+// GetStatus returns the detected outage and the maintenance windows in
+// effect or upcoming
+// GET /api/v1/maintenance
+func (h *MaintenanceHandler) GetStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, h.detector.Status())
+}
+
+// CreateWindow announces a maintenance window, during which orders for the
+// market wait
+// POST /api/v1/admin/maintenance/windows
+func (h *MaintenanceHandler) CreateWindow(c *gin.Context) {
+	var req CreateMaintenanceWindowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	window, err := h.detector.CreateWindow(c.Request.Context(), &model.MaintenanceWindow{
+		Market:   req.Market,
+		Reason:   req.Reason,
+		StartsAt: req.StartsAt,
+		EndsAt:   req.EndsAt,
+	})
+	var maintenanceErr *maintenance.MaintenanceError
+	switch {
+	case errors.As(err, &maintenanceErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusCreated, window)
+	}
+}
+
+// DeleteWindow removes a maintenance window, ending it if it is in effect
+// DELETE /api/v1/admin/maintenance/windows/:id
+func (h *MaintenanceHandler) DeleteWindow(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid maintenance window id"})
+		return
+	}
+
+	err = h.detector.DeleteWindow(c.Request.Context(), id)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "maintenance window not found"})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	default:
+		c.Status(http.StatusNoContent)
+	}
+}
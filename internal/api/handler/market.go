@@ -1,27 +1,61 @@
 package handler
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/gateway"
 	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
 	"github.com/sungminna/upbit-trading-platform/internal/upbit/quotation"
+	"github.com/sungminna/upbit-trading-platform/pkg/cache"
+	"github.com/sungminna/upbit-trading-platform/pkg/symbol"
+)
+
+const (
+	// tickerCacheTTL bounds how stale a cached ticker may be
+	tickerCacheTTL = 1 * time.Second
+	// maxResampledCandles bounds the candles of one resampled request
+	maxResampledCandles = 1000
 )
 
 // MarketHandler handles market-related endpoints
 type MarketHandler struct {
-	quotationClient *quotation.Client
+	quotationClient gateway.QuotationAPI
+	cache           cache.Cache
+	symbols         *symbol.Registry            // Optional; accepts normalized symbols such as BTC/KRW
+	candles         repository.CandleRepository // Optional; enables custom intervals
 }
 
-// NewMarketHandler creates a new market handler
-func NewMarketHandler(quotationClient *quotation.Client) *MarketHandler {
+// NewMarketHandler creates a new market handler. The cache is optional;
+// when nil tickers are always fetched from Upbit.
+func NewMarketHandler(quotationClient gateway.QuotationAPI, tickerCache cache.Cache) *MarketHandler {
 	return &MarketHandler{
 		quotationClient: quotationClient,
+		cache:           tickerCache,
 	}
 }
 
+// WithSymbols makes the handler accept normalized symbols, e.g. BTC/KRW, as
+// well as Upbit market codes
+func (h *MarketHandler) WithSymbols(symbols *symbol.Registry) *MarketHandler {
+	h.symbols = symbols
+	return h
+}
+
+// WithCandleStore serves intervals Upbit doesn't offer, e.g. 45m or 6h, by
+// resampling stored 1m candles
+func (h *MarketHandler) WithCandleStore(candles repository.CandleRepository) *MarketHandler {
+	h.candles = candles
+	return h
+}
+
 // GetMarkets returns all available markets
 // GET /api/v1/markets
 func (h *MarketHandler) GetMarkets(c *gin.Context) {
@@ -37,7 +71,11 @@ func (h *MarketHandler) GetMarkets(c *gin.Context) {
 // GetCandles returns candle data for a market
 // GET /api/v1/candles/:market
 func (h *MarketHandler) GetCandles(c *gin.Context) {
-	market := c.Param("market")
+	market, err := resolveMarket(h.symbols, marketParam(c))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	interval := c.DefaultQuery("interval", string(model.CandleInterval1m))
 	count := 100
 
@@ -48,6 +86,11 @@ func (h *MarketHandler) GetCandles(c *gin.Context) {
 		}
 	}
 
+	if model.CandleInterval(interval).Duration() == 0 {
+		h.getResampledCandles(c, market, model.CandleInterval(interval), count)
+		return
+	}
+
 	candles, err := h.quotationClient.GetCandles(c.Request.Context(), market, model.CandleInterval(interval), count)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -57,10 +100,66 @@ func (h *MarketHandler) GetCandles(c *gin.Context) {
 	c.JSON(http.StatusOK, candles)
 }
 
+// getResampledCandles serves candles of a custom interval, newest first like
+// Upbit's, resampled from stored 1m candles. Without from and to the count
+// most recent candles are returned.
+func (h *MarketHandler) getResampledCandles(c *gin.Context, market string, interval model.CandleInterval, count int) {
+	step, err := model.ParseResampleStep(interval)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if h.candles == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "custom intervals require stored candles"})
+		return
+	}
+	if count <= 0 || count > maxResampledCandles {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("count must be between 1 and %d", maxResampledCandles)})
+		return
+	}
+
+	to := time.Now()
+	if s := c.Query("to"); s != "" {
+		if to, err = time.Parse(time.RFC3339, s); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to parameter"})
+			return
+		}
+	}
+	// Start at the step boundary count steps before the one holding to
+	from := time.Unix(0, 0).Add(to.Sub(time.Unix(0, 0)).Truncate(step)).Add(-time.Duration(count-1) * step)
+	if s := c.Query("from"); s != "" {
+		if from, err = time.Parse(time.RFC3339, s); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from parameter"})
+			return
+		}
+	}
+	if !from.Before(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must be before to"})
+		return
+	}
+	if to.Sub(from) > time.Duration(maxResampledCandles)*step {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("range spans more than %d candles", maxResampledCandles)})
+		return
+	}
+
+	candles, err := h.candles.ResampleCandles(c.Request.Context(), market, step, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	slices.Reverse(candles)
+	c.JSON(http.StatusOK, candles)
+}
+
 // GetOrderbook returns orderbook data for a market
 // GET /api/v1/orderbook/:market
 func (h *MarketHandler) GetOrderbook(c *gin.Context) {
-	market := c.Param("market")
+	market, err := resolveMarket(h.symbols, marketParam(c))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	orderbook, err := h.quotationClient.GetOrderbook(c.Request.Context(), market)
 	if err != nil {
@@ -72,7 +171,7 @@ func (h *MarketHandler) GetOrderbook(c *gin.Context) {
 }
 
 // GetTicker returns ticker data for markets
-// GET /api/v1/ticker?markets=KRW-BTC,KRW-ETH
+// GET /api/v1/ticker?markets=KRW-BTC,ETH/KRW
 func (h *MarketHandler) GetTicker(c *gin.Context) {
 	marketsStr := c.Query("markets")
 	if marketsStr == "" {
@@ -81,7 +180,15 @@ func (h *MarketHandler) GetTicker(c *gin.Context) {
 	}
 
 	markets := strings.Split(marketsStr, ",")
-	tickers, err := h.quotationClient.GetTicker(c.Request.Context(), markets)
+	for i, market := range markets {
+		resolved, err := resolveMarket(h.symbols, market)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		markets[i] = resolved
+	}
+	tickers, err := h.getTickers(c.Request.Context(), markets)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -89,3 +196,71 @@ func (h *MarketHandler) GetTicker(c *gin.Context) {
 
 	c.JSON(http.StatusOK, tickers)
 }
+
+// getTickers serves tickers from the cache and fetches only the missing markets
+func (h *MarketHandler) getTickers(ctx context.Context, markets []string) ([]quotation.Ticker, error) {
+	if h.cache == nil {
+		return h.quotationClient.GetTicker(ctx, markets)
+	}
+
+	cached := make(map[string]quotation.Ticker, len(markets))
+	var missing []string
+	for _, market := range markets {
+		data, err := h.cache.Get(ctx, tickerCacheKey(market))
+		if err != nil {
+			missing = append(missing, market)
+			continue
+		}
+
+		var ticker quotation.Ticker
+		if err := json.Unmarshal(data, &ticker); err != nil {
+			missing = append(missing, market)
+			continue
+		}
+		cached[market] = ticker
+	}
+
+	if len(missing) > 0 {
+		fetched, err := h.quotationClient.GetTicker(ctx, missing)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, ticker := range fetched {
+			cached[ticker.Market] = ticker
+			if data, err := json.Marshal(ticker); err == nil {
+				_ = h.cache.Set(ctx, tickerCacheKey(ticker.Market), data, tickerCacheTTL)
+			}
+		}
+	}
+
+	// Preserve the requested order
+	tickers := make([]quotation.Ticker, 0, len(markets))
+	for _, market := range markets {
+		if ticker, ok := cached[market]; ok {
+			tickers = append(tickers, ticker)
+		}
+	}
+
+	return tickers, nil
+}
+
+func tickerCacheKey(market string) string {
+	return "ticker:" + market
+}
+
+// marketParam returns the market path parameter. The route matches the rest
+// of the path so normalized symbols can keep their slash.
+func marketParam(c *gin.Context) string {
+	return strings.TrimPrefix(c.Param("market"), "/")
+}
+
+// resolveMarket returns the Upbit market code of a normalized symbol or
+// market code. Markets pass through unchanged without a registry, and empty
+// ones are left to the caller's validation.
+func resolveMarket(symbols *symbol.Registry, market string) (string, error) {
+	if symbols == nil || market == "" {
+		return market, nil
+	}
+	return symbols.Resolve(symbol.Upbit, market)
+}
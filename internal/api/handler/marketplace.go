@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/api/middleware"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/marketplace"
+)
+
+// MarketplaceHandler handles strategy template catalog endpoints
+type MarketplaceHandler struct {
+	marketplace *marketplace.Service
+}
+
+// NewMarketplaceHandler creates a new strategy template catalog handler
+func NewMarketplaceHandler(marketplace *marketplace.Service) *MarketplaceHandler {
+	return &MarketplaceHandler{marketplace: marketplace}
+}
+
+// PublishTemplateRequest is the body of a publish strategy template request
+type PublishTemplateRequest struct {
+	Name        string                     `json:"name" binding:"required"`
+	Description string                     `json:"description"`
+	Type        model.StrategyTemplateType `json:"type" binding:"required"`
+	Params      map[string]float64         `json:"params"`
+	BacktestID  *uuid.UUID                 `json:"backtest_id"` // One of the user's backtests of the template
+}
+
+// CloneTemplateRequest is the body of a clone strategy template request
+type CloneTemplateRequest struct {
+	PositionIDs []uuid.UUID `json:"position_ids" binding:"required"`
+	DryRun      bool        `json:"dry_run"`
+}
+
+// PublishTemplate publishes a strategy template to the shared catalog
+// POST /api/v1/strategy-templates
+func (h *MarketplaceHandler) PublishTemplate(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req PublishTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	template, err := h.marketplace.Publish(c.Request.Context(), userID, marketplace.PublishRequest{
+		Name:        req.Name,
+		Description: req.Description,
+		Type:        req.Type,
+		Params:      req.Params,
+		BacktestID:  req.BacktestID,
+	})
+	if err != nil {
+		writeMarketplaceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+// ListTemplates returns the catalog, most used templates first. mine=true
+// lists the user's own templates.
+// GET /api/v1/strategy-templates?type=drawdown_guard&mine=true&limit=20
+func (h *MarketplaceHandler) ListTemplates(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter := repository.StrategyTemplateFilter{Type: model.StrategyTemplateType(c.Query("type"))}
+	if c.Query("mine") == "true" {
+		filter.PublisherID = userID
+	}
+	if s := c.Query("limit"); s != "" {
+		if filter.Limit, err = strconv.Atoi(s); err != nil || filter.Limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit parameter"})
+			return
+		}
+	}
+
+	templates, err := h.marketplace.List(c.Request.Context(), filter)
+	if err != nil {
+		writeMarketplaceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"templates": templates})
+}
+
+// GetTemplate returns a template of the catalog
+// GET /api/v1/strategy-templates/:id
+func (h *MarketplaceHandler) GetTemplate(c *gin.Context) {
+	_, id, ok := templateParams(c)
+	if !ok {
+		return
+	}
+
+	template, err := h.marketplace.Get(c.Request.Context(), id)
+	if err != nil {
+		writeMarketplaceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// UnpublishTemplate removes one of the user's templates from the catalog
+// DELETE /api/v1/strategy-templates/:id
+func (h *MarketplaceHandler) UnpublishTemplate(c *gin.Context) {
+	userID, id, ok := templateParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.marketplace.Unpublish(c.Request.Context(), userID, id); err != nil {
+		writeMarketplaceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// CloneTemplate configures a template on positions of the user
+// POST /api/v1/strategy-templates/:id/clone
+func (h *MarketplaceHandler) CloneTemplate(c *gin.Context) {
+	userID, id, ok := templateParams(c)
+	if !ok {
+		return
+	}
+
+	var req CloneTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	results, err := h.marketplace.Clone(c.Request.Context(), userID, id, req.PositionIDs, req.DryRun)
+	if err != nil {
+		writeMarketplaceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": results})
+}
+
+func templateParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid strategy template id"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, id, true
+}
+
+func writeMarketplaceError(c *gin.Context, err error) {
+	var marketplaceErr *marketplace.MarketplaceError
+	switch {
+	case errors.Is(err, marketplace.ErrNotPublisher):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.As(err, &marketplaceErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "strategy template not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
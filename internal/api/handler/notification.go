@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sungminna/upbit-trading-platform/internal/api/middleware"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/notification"
+)
+
+// NotificationHandler handles notification settings endpoints
+type NotificationHandler struct {
+	settings repository.NotificationSettingsRepository
+	notifier *notification.Service
+}
+
+// NewNotificationHandler creates a new notification handler
+func NewNotificationHandler(settings repository.NotificationSettingsRepository, notifier *notification.Service) *NotificationHandler {
+	return &NotificationHandler{
+		settings: settings,
+		notifier: notifier,
+	}
+}
+
+// UpdateNotificationSettingsRequest is the body of an update settings request
+type UpdateNotificationSettingsRequest struct {
+	DailySummary   bool   `json:"daily_summary"`
+	SummaryChannel string `json:"summary_channel"` // Every channel when empty
+	SummaryTime    string `json:"summary_time"`    // "15:04"; defaults to 09:00
+	Timezone       string `json:"timezone"`        // IANA name; defaults to Asia/Seoul
+}
+
+// GetSettings returns the user's notification settings
+// GET /api/v1/notifications/settings
+func (h *NotificationHandler) GetSettings(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	settings, err := h.settings.Get(c.Request.Context(), userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		settings, err = model.DefaultNotificationSettings(userID), nil
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// UpdateSettings replaces the user's notification settings
+// PUT /api/v1/notifications/settings
+func (h *NotificationHandler) UpdateSettings(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req UpdateNotificationSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	settings := model.DefaultNotificationSettings(userID)
+	settings.DailySummary = req.DailySummary
+	settings.SummaryChannel = req.SummaryChannel
+	if req.SummaryTime != "" {
+		settings.SummaryTime = req.SummaryTime
+	}
+	if req.Timezone != "" {
+		settings.Timezone = req.Timezone
+	}
+	settings.UpdatedAt = time.Now()
+
+	if settings.SummaryChannel != "" && !h.notifier.HasChannel(settings.SummaryChannel) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unknown notification channel"})
+		return
+	}
+	if _, err := settings.NextSummary(time.Now()); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "summary_time must be HH:MM and timezone an IANA name"})
+		return
+	}
+
+	if err := h.settings.Save(c.Request.Context(), settings); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
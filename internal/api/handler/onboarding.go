@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sungminna/upbit-trading-platform/internal/api/middleware"
+	"github.com/sungminna/upbit-trading-platform/internal/service/onboarding"
+)
+
+// OnboardingHandler handles onboarding wizard endpoints
+type OnboardingHandler struct {
+	onboarding *onboarding.Service
+}
+
+// NewOnboardingHandler creates a new onboarding handler
+func NewOnboardingHandler(onboarding *onboarding.Service) *OnboardingHandler {
+	return &OnboardingHandler{onboarding: onboarding}
+}
+
+// SetTradingModeRequest is the body of a set trading mode request
+type SetTradingModeRequest struct {
+	Mode string `json:"mode" binding:"required"` // paper or live
+}
+
+// GetOnboarding returns where the user is in onboarding and which account
+// they trade
+// GET /api/v1/onboarding
+func (h *OnboardingHandler) GetOnboarding(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	status, err := h.onboarding.Status(c.Request.Context(), userID)
+	if err != nil {
+		writeOnboardingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// CreatePaperAccount opens the user's paper account with virtual KRW
+// POST /api/v1/onboarding/paper-account
+func (h *OnboardingHandler) CreatePaperAccount(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	status, err := h.onboarding.ProvisionPaperAccount(c.Request.Context(), userID)
+	if err != nil {
+		writeOnboardingError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, status)
+}
+
+// ResetPaperAccount empties the user's paper account back to its starting
+// balance
+// POST /api/v1/onboarding/paper-account/reset
+func (h *OnboardingHandler) ResetPaperAccount(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	status, err := h.onboarding.ResetPaperAccount(c.Request.Context(), userID)
+	if err != nil {
+		writeOnboardingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// SetTradingMode switches the user between paper and live trading
+// PUT /api/v1/onboarding/mode
+func (h *OnboardingHandler) SetTradingMode(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req SetTradingModeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	status, err := h.onboarding.SetMode(c.Request.Context(), userID, req.Mode)
+	if err != nil {
+		writeOnboardingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+func writeOnboardingError(c *gin.Context, err error) {
+	var onboardingErr *onboarding.OnboardingError
+	switch {
+	case errors.Is(err, onboarding.ErrAlreadyProvisioned), errors.Is(err, onboarding.ErrOpenPositions), errors.Is(err, onboarding.ErrOpenOrders):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.As(err, &onboardingErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/api/middleware"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/preset"
+	"github.com/sungminna/upbit-trading-platform/internal/service/trading"
+)
+
+// OrderHandler handles order placement and cancellation endpoints
+type OrderHandler struct {
+	engine  *trading.Engine
+	presets *preset.Service // Optional
+}
+
+// NewOrderHandler creates a new order handler
+func NewOrderHandler(engine *trading.Engine) *OrderHandler {
+	return &OrderHandler{engine: engine}
+}
+
+// WithPresets lets orders be placed by the name of an order preset
+func (h *OrderHandler) WithPresets(presets *preset.Service) *OrderHandler {
+	h.presets = presets
+	return h
+}
+
+// PlacePresetOrderRequest places one of the user's order presets through the
+// order endpoint, with the overrides of PlacePresetRequest
+type PlacePresetOrderRequest struct {
+	Preset string `json:"preset"`
+	PlacePresetRequest
+}
+
+// PlaceOrder places an order, or schedules it when activate_at is in the
+// future. A body naming a preset places the preset's orders instead, as
+// POST /api/v1/order-presets/:name/place does.
+// POST /api/v1/orders
+func (h *OrderHandler) PlaceOrder(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	var presetReq PlacePresetOrderRequest
+	if err := c.ShouldBindBodyWith(&presetReq, binding.JSON); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if presetReq.Preset != "" {
+		if h.presets == nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "order presets are not configured"})
+			return
+		}
+		placePreset(c, h.presets, userID, presetReq.Preset, presetReq.PlacePresetRequest)
+		return
+	}
+
+	var req trading.PlaceOrderRequest
+	if err := c.ShouldBindBodyWith(&req, binding.JSON); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := h.engine.PlaceOrder(c.Request.Context(), userID, req)
+	if err != nil {
+		writeOrderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, order)
+}
+
+// GetOrder returns one of the user's orders
+// GET /api/v1/orders/:id
+func (h *OrderHandler) GetOrder(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order id"})
+		return
+	}
+
+	order, err := h.engine.GetOrder(c.Request.Context(), userID, orderID)
+	if err != nil {
+		writeOrderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// CancelOrder cancels one of the user's open or scheduled orders. Orders on
+// the exchange are marked cancelled once Upbit confirms it; scheduled orders
+// are cancelled at once.
+// DELETE /api/v1/orders/:id
+func (h *OrderHandler) CancelOrder(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	orderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid order id"})
+		return
+	}
+
+	order, err := h.engine.CancelOrder(c.Request.Context(), userID, orderID)
+	if err != nil {
+		writeOrderError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+func writeOrderError(c *gin.Context, err error) {
+	var tradingErr *trading.TradingError
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+	case errors.Is(err, trading.ErrVelocityLimit):
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	case errors.Is(err, trading.ErrExchangeDown):
+		// Maintenance matches ErrExchangeDown too
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	case errors.Is(err, trading.ErrOrderNotOpen), errors.Is(err, trading.ErrOrderActivating), errors.Is(err, trading.ErrPositionBusy):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.As(err, &tradingErr), errors.Is(err, trading.ErrInsufficientFunds):
+		// Invalid, or refused by a halt, risk limit or funds check
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,218 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sungminna/upbit-trading-platform/internal/api/middleware"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/gateway"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/portfolio"
+	"github.com/sungminna/upbit-trading-platform/internal/service/valuation"
+	"github.com/sungminna/upbit-trading-platform/pkg/perf"
+)
+
+// defaultPerformanceWindow is how far back performance is computed without ?from
+const defaultPerformanceWindow = 365 * 24 * time.Hour
+
+// PortfolioHandler handles portfolio analytics endpoints
+type PortfolioHandler struct {
+	snapshots repository.SnapshotRepository
+	positions repository.PositionRepository
+	portfolio *portfolio.Service
+	candles   gateway.QuotationAPI // Prices benchmarks
+}
+
+// NewPortfolioHandler creates a new portfolio handler
+func NewPortfolioHandler(snapshots repository.SnapshotRepository, positions repository.PositionRepository, portfolio *portfolio.Service, candles gateway.QuotationAPI) *PortfolioHandler {
+	return &PortfolioHandler{
+		snapshots: snapshots,
+		positions: positions,
+		portfolio: portfolio,
+		candles:   candles,
+	}
+}
+
+// GetPortfolio returns the user's equity, allocation by asset, open
+// positions and change since the latest daily snapshot, in a display
+// currency (default KRW)
+// GET /api/v1/portfolio?currency=USD
+func (h *PortfolioHandler) GetPortfolio(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	view, err := h.portfolio.Portfolio(c.Request.Context(), userID, time.Now(), c.Query("currency"))
+	if err != nil {
+		writePortfolioError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, view)
+}
+
+// GetEquityCurve returns the user's equity at each snapshot and the
+// time-weighted return, which excludes deposits and withdrawals
+// GET /api/v1/portfolio/equity?interval=1d&from=2025-01-01T00:00:00Z&to=...&currency=USD
+func (h *PortfolioHandler) GetEquityCurve(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	to := time.Now()
+	if s := c.Query("to"); s != "" {
+		if to, err = time.Parse(time.RFC3339, s); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to parameter"})
+			return
+		}
+	}
+	from := to.Add(-defaultPerformanceWindow)
+	if s := c.Query("from"); s != "" {
+		if from, err = time.Parse(time.RFC3339, s); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from parameter"})
+			return
+		}
+	}
+
+	curve, err := h.portfolio.EquityCurve(c.Request.Context(), userID, from, to, c.DefaultQuery("interval", portfolio.IntervalDay), c.Query("currency"))
+	if err != nil {
+		writePortfolioError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, curve)
+}
+
+// GetPerformance returns performance metrics computed from account snapshots
+// and the positions closed in the window, optionally compared with holding a
+// benchmark market over the same snapshots
+// GET /api/v1/portfolio/performance?from=2025-01-01T00:00:00Z&to=...&period=daily&benchmark=KRW-BTC
+func (h *PortfolioHandler) GetPerformance(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	to := time.Now()
+	if s := c.Query("to"); s != "" {
+		if to, err = time.Parse(time.RFC3339, s); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to parameter"})
+			return
+		}
+	}
+	from := to.Add(-defaultPerformanceWindow)
+	if s := c.Query("from"); s != "" {
+		if from, err = time.Parse(time.RFC3339, s); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from parameter"})
+			return
+		}
+	}
+
+	period := model.SnapshotPeriod(c.DefaultQuery("period", string(model.SnapshotPeriodDaily)))
+	if period != model.SnapshotPeriodDaily && period != model.SnapshotPeriodHourly {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid period parameter"})
+		return
+	}
+
+	snapshots, err := h.snapshots.ListByUser(c.Request.Context(), userID, period, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	positions, err := h.positions.ListByUser(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	equity := make([]perf.EquityPoint, len(snapshots))
+	for i, s := range snapshots {
+		equity[i] = perf.EquityPoint{Time: s.TakenAt, Equity: s.Equity}
+	}
+
+	var trades []perf.Trade
+	for _, p := range positions {
+		if p.ClosedAt == nil || p.ClosedAt.Before(from) || !p.ClosedAt.Before(to) {
+			continue
+		}
+		trades = append(trades, perf.Trade{EntryTime: p.CreatedAt, ExitTime: *p.ClosedAt, PnL: p.RealizedPnL})
+	}
+
+	periodsPerYear := float64(perf.DefaultPeriodsPerYear)
+	if period == model.SnapshotPeriodHourly {
+		periodsPerYear *= 24
+	}
+
+	response := gin.H{
+		"from":    from,
+		"to":      to,
+		"period":  period,
+		"metrics": perf.Compute(equity, trades, perf.Options{PeriodsPerYear: periodsPerYear}),
+		"equity":  equity,
+	}
+
+	if market := c.Query("benchmark"); market != "" {
+		interval := model.CandleInterval1d
+		if period == model.SnapshotPeriodHourly {
+			interval = model.CandleInterval1h
+		}
+		candles, err := h.candles.GetCandleRange(c.Request.Context(), market, interval, from.Add(-interval.Duration()), to)
+		if err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": fmt.Sprintf("failed to load benchmark candles: %v", err)})
+			return
+		}
+
+		compared, prices := benchmarkCurve(equity, candles)
+		response["benchmark"] = gin.H{
+			"market":     market,
+			"comparison": perf.Compare(compared, prices),
+			"prices":     prices,
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// benchmarkCurve prices the benchmark at each equity point with the close of
+// the last candle starting before it. Points before the first candle are
+// dropped from both curves so they stay aligned.
+func benchmarkCurve(equity []perf.EquityPoint, candles []model.Candle) ([]perf.EquityPoint, []perf.EquityPoint) {
+	sort.Slice(candles, func(i, j int) bool {
+		return candles[i].Timestamp.Before(candles[j].Timestamp)
+	})
+
+	var compared, prices []perf.EquityPoint
+	next := 0
+	for _, point := range equity {
+		for next < len(candles) && candles[next].Timestamp.Before(point.Time) {
+			next++
+		}
+		if next == 0 {
+			continue
+		}
+		compared = append(compared, point)
+		prices = append(prices, perf.EquityPoint{Time: point.Time, Equity: candles[next-1].ClosePrice})
+	}
+	return compared, prices
+}
+
+func writePortfolioError(c *gin.Context, err error) {
+	var portfolioErr *portfolio.PortfolioError
+	var valuationErr *valuation.ValuationError
+	if errors.As(err, &portfolioErr) || errors.As(err, &valuationErr) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
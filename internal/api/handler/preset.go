@@ -0,0 +1,198 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/api/middleware"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/preset"
+	"github.com/sungminna/upbit-trading-platform/internal/service/trading"
+)
+
+// PresetHandler handles order preset endpoints
+type PresetHandler struct {
+	presets *preset.Service
+}
+
+// NewPresetHandler creates a new order preset handler
+func NewPresetHandler(presets *preset.Service) *PresetHandler {
+	return &PresetHandler{presets: presets}
+}
+
+// SavePresetRequest is the body of a save order preset request
+type SavePresetRequest struct {
+	Market   string                    `json:"market" binding:"required"`
+	Side     model.OrderSide           `json:"side" binding:"required"`
+	Type     model.OrderType           `json:"type" binding:"required"`
+	SizeRule model.OrderPresetSizeRule `json:"size_rule" binding:"required"`
+	Size     float64                   `json:"size" binding:"required"` // Quantity, or KRW for amounts
+	// Algorithm defaults to whole; split and twap place Slices orders
+	Algorithm     model.OrderPresetAlgorithm `json:"algorithm"`
+	Slices        int                        `json:"slices"`
+	SliceInterval int                        `json:"slice_interval"` // Seconds between twap slices
+}
+
+// PlacePresetRequest is the optional body of a place order preset request
+type PlacePresetRequest struct {
+	Price      *float64   `json:"price,omitempty"` // Defaults to the current price
+	Size       *float64   `json:"size,omitempty"`  // Defaults to the preset's
+	PositionID *uuid.UUID `json:"position_id,omitempty"`
+}
+
+// SavePreset creates or replaces the user's order preset of a name
+// PUT /api/v1/order-presets/:name
+func (h *PresetHandler) SavePreset(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req SavePresetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	saved, err := h.presets.Save(c.Request.Context(), &model.OrderPreset{
+		UserID:        userID,
+		Name:          c.Param("name"),
+		Market:        req.Market,
+		Side:          req.Side,
+		Type:          req.Type,
+		SizeRule:      req.SizeRule,
+		Size:          req.Size,
+		Algorithm:     req.Algorithm,
+		Slices:        req.Slices,
+		SliceInterval: req.SliceInterval,
+	})
+	if err != nil {
+		writePresetError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, saved)
+}
+
+// ListPresets returns the user's order presets
+// GET /api/v1/order-presets
+func (h *PresetHandler) ListPresets(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	presets, err := h.presets.List(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if presets == nil {
+		presets = []*model.OrderPreset{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"presets": presets})
+}
+
+// GetPreset returns one of the user's order presets
+// GET /api/v1/order-presets/:name
+func (h *PresetHandler) GetPreset(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	saved, err := h.presets.Get(c.Request.Context(), userID, c.Param("name"))
+	if err != nil {
+		writePresetError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, saved)
+}
+
+// DeletePreset deletes one of the user's order presets
+// DELETE /api/v1/order-presets/:name
+func (h *PresetHandler) DeletePreset(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.presets.Delete(c.Request.Context(), userID, c.Param("name")); err != nil {
+		writePresetError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// PlacePreset places the order of one of the user's presets, in the slices
+// of its algorithm. The body is optional, so a hotkey needs nothing but the
+// preset's name.
+// POST /api/v1/order-presets/:name/place
+func (h *PresetHandler) PlacePreset(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req PlacePresetRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	placePreset(c, h.presets, userID, c.Param("name"), req)
+}
+
+// placePreset places the order of one of the user's presets. When a slice
+// is refused after others were placed, the placed orders are returned with
+// the error.
+func placePreset(c *gin.Context, presets *preset.Service, userID uuid.UUID, name string, req PlacePresetRequest) {
+	orders, err := presets.Place(c.Request.Context(), userID, name, preset.PlaceOptions{
+		Price:      req.Price,
+		Size:       req.Size,
+		PositionID: req.PositionID,
+	})
+	if err != nil && len(orders) == 0 {
+		writePresetError(c, err)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusCreated, gin.H{"orders": orders, "error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"orders": orders})
+}
+
+func writePresetError(c *gin.Context, err error) {
+	var presetErr *preset.PresetError
+	var tradingErr *trading.TradingError
+	switch {
+	case errors.As(err, &presetErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "order preset not found"})
+	case errors.Is(err, trading.ErrVelocityLimit):
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	case errors.Is(err, trading.ErrMaintenance):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	case errors.As(err, &tradingErr), errors.Is(err, trading.ErrInsufficientFunds):
+		// The preset is sound but the engine refused its order
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/sungminna/upbit-trading-platform/internal/api/middleware"
+	"github.com/sungminna/upbit-trading-platform/internal/service/push"
+)
+
+const (
+	// pushWriteTimeout bounds each write to a client
+	pushWriteTimeout = 10 * time.Second
+	// pushPingInterval is how often idle clients are pinged; clients that
+	// don't answer within pushPongTimeout are disconnected
+	pushPingInterval = 30 * time.Second
+	pushPongTimeout  = pushPingInterval + pushWriteTimeout
+)
+
+// PushHandler streams users' notifications and order events over websockets
+type PushHandler struct {
+	hub      *push.Hub
+	upgrader websocket.Upgrader
+}
+
+// NewPushHandler creates a new push handler
+func NewPushHandler(hub *push.Hub) *PushHandler {
+	return &PushHandler{
+		hub: hub,
+		upgrader: websocket.Upgrader{
+			// The API allows every origin; connections authenticate with a
+			// token rather than cookies, so other sites can't ride a session
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+	}
+}
+
+// Connect upgrades to a websocket that receives the user's notifications and
+// order events until either side closes it. Browsers can't set headers on
+// websockets, so the token may be passed as ?access_token= instead.
+// GET /ws
+func (h *PushHandler) Connect(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	// The upgrader answers failed upgrades itself
+	ws, err := h.upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+	defer ws.Close()
+
+	conn := h.hub.Register(userID)
+	defer h.hub.Unregister(conn)
+
+	// Clients only listen; reading detects when they go away
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		ws.SetReadLimit(512)
+		ws.SetReadDeadline(time.Now().Add(pushPongTimeout))
+		ws.SetPongHandler(func(string) error {
+			return ws.SetReadDeadline(time.Now().Add(pushPongTimeout))
+		})
+		for {
+			if _, _, err := ws.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(pushPingInterval)
+	defer ping.Stop()
+	for {
+		select {
+		case <-closed:
+			return
+		case <-conn.Dropped():
+			ws.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "too slow"), time.Now().Add(pushWriteTimeout))
+			return
+		case msg := <-conn.Messages():
+			ws.SetWriteDeadline(time.Now().Add(pushWriteTimeout))
+			if err := ws.WriteJSON(msg); err != nil {
+				return
+			}
+		case <-ping.C:
+			if err := ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(pushWriteTimeout)); err != nil {
+				return
+			}
+		}
+	}
+}
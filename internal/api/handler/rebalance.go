@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sungminna/upbit-trading-platform/internal/api/middleware"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/rebalance"
+)
+
+// RebalanceHandler handles target portfolio and rebalancing endpoints
+type RebalanceHandler struct {
+	rebalance *rebalance.Service
+}
+
+// NewRebalanceHandler creates a new rebalancing handler
+func NewRebalanceHandler(rebalance *rebalance.Service) *RebalanceHandler {
+	return &RebalanceHandler{rebalance: rebalance}
+}
+
+// SaveTargetRequest is the body of a save target portfolio request
+type SaveTargetRequest struct {
+	Weights        []model.TargetWeight `json:"weights" binding:"required"`
+	DriftThreshold float64              `json:"drift_threshold"` // Percentage points; 0 disables
+	IntervalHours  int                  `json:"interval_hours"`  // 0 disables
+	Enabled        bool                 `json:"enabled"`         // Rebalance automatically
+}
+
+// GetTarget returns the user's target portfolio
+// GET /api/v1/rebalance/target
+func (h *RebalanceHandler) GetTarget(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	target, err := h.rebalance.Get(c.Request.Context(), userID)
+	if err != nil {
+		writeRebalanceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, target)
+}
+
+// SaveTarget creates or replaces the user's target portfolio
+// PUT /api/v1/rebalance/target
+func (h *RebalanceHandler) SaveTarget(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req SaveTargetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	target, err := h.rebalance.Save(c.Request.Context(), &model.TargetPortfolio{
+		UserID:         userID,
+		Weights:        req.Weights,
+		DriftThreshold: req.DriftThreshold,
+		IntervalHours:  req.IntervalHours,
+		Enabled:        req.Enabled,
+	})
+	if err != nil {
+		writeRebalanceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, target)
+}
+
+// DeleteTarget removes the user's target portfolio
+// DELETE /api/v1/rebalance/target
+func (h *RebalanceHandler) DeleteTarget(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.rebalance.Delete(c.Request.Context(), userID); err != nil {
+		writeRebalanceError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Preview returns the drift from the target portfolio and the orders a
+// rebalance would place, without placing them
+// GET /api/v1/rebalance/preview
+func (h *RebalanceHandler) Preview(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	plan, err := h.rebalance.Preview(c.Request.Context(), userID)
+	if err != nil {
+		writeRebalanceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, plan)
+}
+
+// Rebalance rebalances the user's account to the target portfolio now
+// POST /api/v1/rebalance
+func (h *RebalanceHandler) Rebalance(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.rebalance.Rebalance(c.Request.Context(), userID)
+	if err != nil {
+		writeRebalanceError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func writeRebalanceError(c *gin.Context, err error) {
+	var rebalanceErr *rebalance.RebalanceError
+	switch {
+	case errors.As(err, &rebalanceErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "no target portfolio"})
+	case errors.Is(err, rebalance.ErrInProgress):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/api/middleware"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/recurring"
+)
+
+// RecurringHandler handles recurring order endpoints
+type RecurringHandler struct {
+	recurring *recurring.Service
+}
+
+// NewRecurringHandler creates a new recurring order handler
+func NewRecurringHandler(recurring *recurring.Service) *RecurringHandler {
+	return &RecurringHandler{recurring: recurring}
+}
+
+// CreateRecurringOrderRequest is the body of a create recurring order request
+type CreateRecurringOrderRequest struct {
+	Market   string          `json:"market" binding:"required"`
+	Side     model.OrderSide `json:"side" binding:"required"`
+	Amount   float64         `json:"amount" binding:"required"` // KRW for buys, quantity for sells
+	Schedule string          `json:"schedule" binding:"required"`
+	TimeZone string          `json:"time_zone"` // Defaults to Asia/Seoul
+}
+
+// CreateRecurringOrder creates a recurring order
+// POST /api/v1/recurring-orders
+func (h *RecurringHandler) CreateRecurringOrder(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req CreateRecurringOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	order, err := h.recurring.Create(c.Request.Context(), &model.RecurringOrder{
+		UserID:   userID,
+		Market:   req.Market,
+		Side:     req.Side,
+		Amount:   req.Amount,
+		Schedule: req.Schedule,
+		TimeZone: req.TimeZone,
+	})
+	if err != nil {
+		writeRecurringError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, order)
+}
+
+// ListRecurringOrders returns the user's recurring orders
+// GET /api/v1/recurring-orders
+func (h *RecurringHandler) ListRecurringOrders(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	orders, err := h.recurring.List(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if orders == nil {
+		orders = []*model.RecurringOrder{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"recurring_orders": orders})
+}
+
+// GetRecurringOrder returns one of the user's recurring orders
+// GET /api/v1/recurring-orders/:id
+func (h *RecurringHandler) GetRecurringOrder(c *gin.Context) {
+	h.withRecurringOrder(c, h.recurring.Get)
+}
+
+// PauseRecurringOrder stops a recurring order from running
+// POST /api/v1/recurring-orders/:id/pause
+func (h *RecurringHandler) PauseRecurringOrder(c *gin.Context) {
+	h.withRecurringOrder(c, h.recurring.Pause)
+}
+
+// ResumeRecurringOrder restarts a paused recurring order from its next
+// scheduled time
+// POST /api/v1/recurring-orders/:id/resume
+func (h *RecurringHandler) ResumeRecurringOrder(c *gin.Context) {
+	h.withRecurringOrder(c, h.recurring.Resume)
+}
+
+// DeleteRecurringOrder deletes a recurring order and its history
+// DELETE /api/v1/recurring-orders/:id
+func (h *RecurringHandler) DeleteRecurringOrder(c *gin.Context) {
+	userID, id, ok := recurringOrderParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.recurring.Delete(c.Request.Context(), userID, id); err != nil {
+		writeRecurringError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListRecurringOrderRuns returns a recurring order's latest runs, newest first
+// GET /api/v1/recurring-orders/:id/runs?limit=50
+func (h *RecurringHandler) ListRecurringOrderRuns(c *gin.Context) {
+	userID, id, ok := recurringOrderParams(c)
+	if !ok {
+		return
+	}
+
+	limit := recurring.DefaultRunLimit
+	if s := c.Query("limit"); s != "" {
+		var err error
+		if limit, err = strconv.Atoi(s); err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit parameter"})
+			return
+		}
+	}
+
+	runs, err := h.recurring.Runs(c.Request.Context(), userID, id, limit)
+	if err != nil {
+		writeRecurringError(c, err)
+		return
+	}
+	if runs == nil {
+		runs = []*model.RecurringOrderRun{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"runs": runs})
+}
+
+func (h *RecurringHandler) withRecurringOrder(c *gin.Context, fn func(ctx context.Context, userID, id uuid.UUID) (*model.RecurringOrder, error)) {
+	userID, id, ok := recurringOrderParams(c)
+	if !ok {
+		return
+	}
+
+	order, err := fn(c.Request.Context(), userID, id)
+	if err != nil {
+		writeRecurringError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+// recurringOrderParams reads the user and recurring order ID of a request,
+// writing the error response if either is missing
+func recurringOrderParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid recurring order id"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, id, true
+}
+
+func writeRecurringError(c *gin.Context, err error) {
+	var recurringErr *recurring.RecurringError
+	switch {
+	case errors.As(err, &recurringErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "recurring order not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sungminna/upbit-trading-platform/internal/service/replay"
+)
+
+// ReplayHandler controls historical replay sessions
+type ReplayHandler struct {
+	replayer *replay.Replayer
+}
+
+// NewReplayHandler creates a new replay handler
+func NewReplayHandler(replayer *replay.Replayer) *ReplayHandler {
+	return &ReplayHandler{
+		replayer: replayer,
+	}
+}
+
+// StartReplay starts streaming historical candles into the replay price feed
+// POST /api/v1/admin/replay
+func (h *ReplayHandler) StartReplay(c *gin.Context) {
+	var cfg replay.Config
+	if err := c.ShouldBindJSON(&cfg); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// The session outlives the request
+	err := h.replayer.Start(context.Background(), cfg)
+	switch {
+	case errors.Is(err, replay.ErrInvalidConfig):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, replay.ErrLiveDisabled):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, replay.ErrAlreadyRunning):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, h.replayer.Status())
+}
+
+// GetReplay returns the progress of the current or last replay
+// GET /api/v1/admin/replay
+func (h *ReplayHandler) GetReplay(c *gin.Context) {
+	c.JSON(http.StatusOK, h.replayer.Status())
+}
+
+// StopReplay stops the running replay
+// DELETE /api/v1/admin/replay
+func (h *ReplayHandler) StopReplay(c *gin.Context) {
+	h.replayer.Stop()
+	c.JSON(http.StatusOK, h.replayer.Status())
+}
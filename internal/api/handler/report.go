@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sungminna/upbit-trading-platform/internal/api/middleware"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/service/report"
+	"github.com/sungminna/upbit-trading-platform/internal/service/valuation"
+)
+
+// defaultReportWindow is how far back reports cover without ?from
+const defaultReportWindow = 30 * 24 * time.Hour
+
+// ReportHandler handles report endpoints
+type ReportHandler struct {
+	reports *report.Service
+}
+
+// NewReportHandler creates a new report handler
+func NewReportHandler(reports *report.Service) *ReportHandler {
+	return &ReportHandler{reports: reports}
+}
+
+// GetPnL returns the PnL realized in a period, net of fees, grouped by
+// market, day, order source or source instance, with the cost basis of the
+// given accounting method, in a display currency (default KRW)
+// GET /api/v1/reports/pnl?from=2025-01-01T00:00:00Z&to=...&group_by=market&method=fifo&currency=USD
+func (h *ReportHandler) GetPnL(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	to := time.Now()
+	if s := c.Query("to"); s != "" {
+		if to, err = time.Parse(time.RFC3339, s); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to parameter"})
+			return
+		}
+	}
+	from := to.Add(-defaultReportWindow)
+	if s := c.Query("from"); s != "" {
+		if from, err = time.Parse(time.RFC3339, s); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from parameter"})
+			return
+		}
+	}
+
+	method := model.AccountingMethod(c.DefaultQuery("method", string(model.AccountingAverage)))
+	pnl, err := h.reports.PnL(c.Request.Context(), userID, from, to, c.DefaultQuery("group_by", report.GroupByMarket), method, c.Query("currency"))
+	if err != nil {
+		writeReportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, pnl)
+}
+
+// ListTrades returns the user's closed round trips, newest exit first
+// GET /api/v1/trades?market=KRW-BTC&from=...&to=...&outcome=win&closed_by=drawdown_guard&limit=50
+func (h *ReportHandler) ListTrades(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	filter := report.TradeFilter{
+		Market:   c.Query("market"),
+		Outcome:  c.Query("outcome"),
+		ClosedBy: c.Query("closed_by"),
+	}
+	if s := c.Query("from"); s != "" {
+		if filter.From, err = time.Parse(time.RFC3339, s); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from parameter"})
+			return
+		}
+	}
+	if s := c.Query("to"); s != "" {
+		if filter.To, err = time.Parse(time.RFC3339, s); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to parameter"})
+			return
+		}
+	}
+	if s := c.Query("limit"); s != "" {
+		if filter.Limit, err = strconv.Atoi(s); err != nil || filter.Limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit parameter"})
+			return
+		}
+	}
+
+	trades, err := h.reports.Trades(c.Request.Context(), userID, filter)
+	if err != nil {
+		writeReportError(c, err)
+		return
+	}
+	if trades == nil {
+		trades = []report.RoundTrip{}
+	}
+
+	c.JSON(http.StatusOK, trades)
+}
+
+func writeReportError(c *gin.Context, err error) {
+	var reportErr *report.ReportError
+	var valuationErr *valuation.ValuationError
+	if errors.As(err, &reportErr) || errors.As(err, &valuationErr) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
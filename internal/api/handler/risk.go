@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sungminna/upbit-trading-platform/internal/api/middleware"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/service/risk"
+)
+
+// RiskHandler handles risk limit endpoints
+type RiskHandler struct {
+	risk *risk.Service
+}
+
+// NewRiskHandler creates a new risk handler
+func NewRiskHandler(risk *risk.Service) *RiskHandler {
+	return &RiskHandler{risk: risk}
+}
+
+// UpdateRiskLimitsRequest is the body of an update risk limits request; zero
+// disables a limit
+type UpdateRiskLimitsRequest struct {
+	MaxOrderNotional  float64 `json:"max_order_notional"`
+	MaxOpenPositions  int     `json:"max_open_positions"`
+	MaxMarketExposure float64 `json:"max_market_exposure"`
+	MaxTotalExposure  float64 `json:"max_total_exposure"`
+	MaxConcentration  float64 `json:"max_concentration"` // Percent of equity per market
+	Downsize          bool    `json:"downsize"`
+	DailyLossLimit    float64 `json:"daily_loss_limit"`
+	FlattenOnLoss     bool    `json:"flatten_on_loss"`
+	DayStart          string  `json:"day_start"` // "15:04"; defaults to 09:00
+	Timezone          string  `json:"timezone"`  // IANA name; defaults to Asia/Seoul
+}
+
+// RiskLimitsResponse is the user's limits, current exposure and today's PnL
+type RiskLimitsResponse struct {
+	*model.RiskLimits
+	Exposure *risk.Exposure   `json:"exposure"`
+	Today    *model.RiskState `json:"today,omitempty"` // Tracked with a daily loss limit
+}
+
+// GetLimits returns the user's risk limits, current exposure and today's PnL
+// GET /api/v1/risk/limits
+func (h *RiskHandler) GetLimits(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	limits, err := h.risk.Limits(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	exposure, err := h.risk.Exposure(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	today, err := h.risk.Today(c.Request.Context(), limits, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, RiskLimitsResponse{RiskLimits: limits, Exposure: exposure, Today: today})
+}
+
+// UpdateLimits replaces the user's risk limits
+// PUT /api/v1/risk/limits
+func (h *RiskHandler) UpdateLimits(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req UpdateRiskLimitsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	limits := &model.RiskLimits{
+		UserID:            userID,
+		MaxOrderNotional:  req.MaxOrderNotional,
+		MaxOpenPositions:  req.MaxOpenPositions,
+		MaxMarketExposure: req.MaxMarketExposure,
+		MaxTotalExposure:  req.MaxTotalExposure,
+		MaxConcentration:  req.MaxConcentration,
+		Downsize:          req.Downsize,
+		DailyLossLimit:    req.DailyLossLimit,
+		FlattenOnLoss:     req.FlattenOnLoss,
+		DayStart:          req.DayStart,
+		Timezone:          req.Timezone,
+	}
+	if err := h.risk.SetLimits(c.Request.Context(), limits); err != nil {
+		var riskErr *risk.RiskError
+		if errors.As(err, &riskErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, limits)
+}
+
+// GetExposure returns the user's open positions marked to market, with each
+// market's and position's share of equity
+// GET /api/v1/risk/exposure
+func (h *RiskHandler) GetExposure(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := h.risk.ExposureReport(c.Request.Context(), userID)
+	if errors.Is(err, risk.ErrNoMarketData) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,410 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/api/middleware"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/signals"
+	"github.com/sungminna/upbit-trading-platform/internal/service/trading"
+)
+
+// maxAlertSize caps the body of inbound alerts
+const maxAlertSize = 64 << 10
+
+// SignalHandler handles signal source, subscription and inbound webhook
+// endpoints
+type SignalHandler struct {
+	signals *signals.Service
+}
+
+// NewSignalHandler creates a new signal handler
+func NewSignalHandler(signals *signals.Service) *SignalHandler {
+	return &SignalHandler{signals: signals}
+}
+
+// CreateSignalSourceRequest is the body of a create signal source request
+type CreateSignalSourceRequest struct {
+	Type           model.SignalSourceType `json:"type" binding:"required"`
+	Name           string                 `json:"name" binding:"required"`
+	Markets        []string               `json:"markets"` // Any KRW market when empty
+	Strategies     []model.SignalStrategy `json:"strategies"`
+	Public         bool                   `json:"public"`
+	TelegramChatID *int64                 `json:"telegram_chat_id"` // Telegram sources
+	Indicator      *model.SignalIndicator `json:"indicator"`        // Indicator sources
+}
+
+// SignalSourceTokenResponse includes a webhook's token, which is only shown
+// when the source is created or its token rotated
+type SignalSourceTokenResponse struct {
+	*model.SignalSource
+	Token string `json:"token,omitempty"`
+}
+
+// CreateSignalSource creates a signal source
+// POST /api/v1/signal-sources
+func (h *SignalHandler) CreateSignalSource(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req CreateSignalSourceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	source, token, err := h.signals.CreateSource(c.Request.Context(), &model.SignalSource{
+		UserID:         userID,
+		Type:           req.Type,
+		Name:           req.Name,
+		Markets:        req.Markets,
+		Strategies:     req.Strategies,
+		Public:         req.Public,
+		TelegramChatID: req.TelegramChatID,
+		Indicator:      req.Indicator,
+	})
+	if err != nil {
+		writeSignalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, SignalSourceTokenResponse{SignalSource: source, Token: token})
+}
+
+// ListSignalSources returns the user's signal sources
+// GET /api/v1/signal-sources
+func (h *SignalHandler) ListSignalSources(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	sources, err := h.signals.ListSources(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if sources == nil {
+		sources = []*model.SignalSource{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"signal_sources": sources})
+}
+
+// ListPublicSignalSources returns the signal sources every user can
+// subscribe to
+// GET /api/v1/signal-sources/public
+func (h *SignalHandler) ListPublicSignalSources(c *gin.Context) {
+	sources, err := h.signals.ListPublicSources(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if sources == nil {
+		sources = []*model.SignalSource{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"signal_sources": sources})
+}
+
+// GetSignalSource returns one of the user's signal sources
+// GET /api/v1/signal-sources/:id
+func (h *SignalHandler) GetSignalSource(c *gin.Context) {
+	h.withSignalSource(c, h.signals.GetSource)
+}
+
+// PauseSignalSource makes a signal source reject signals
+// POST /api/v1/signal-sources/:id/pause
+func (h *SignalHandler) PauseSignalSource(c *gin.Context) {
+	h.withSignalSource(c, func(ctx context.Context, userID, id uuid.UUID) (*model.SignalSource, error) {
+		return h.signals.SetSourceActive(ctx, userID, id, false)
+	})
+}
+
+// ResumeSignalSource makes a paused signal source accept signals again
+// POST /api/v1/signal-sources/:id/resume
+func (h *SignalHandler) ResumeSignalSource(c *gin.Context) {
+	h.withSignalSource(c, func(ctx context.Context, userID, id uuid.UUID) (*model.SignalSource, error) {
+		return h.signals.SetSourceActive(ctx, userID, id, true)
+	})
+}
+
+// RotateSignalSourceToken replaces a webhook source's token
+// POST /api/v1/signal-sources/:id/rotate-token
+func (h *SignalHandler) RotateSignalSourceToken(c *gin.Context) {
+	userID, id, ok := signalParams(c, "invalid signal source id")
+	if !ok {
+		return
+	}
+
+	token, err := h.signals.RotateToken(c.Request.Context(), userID, id)
+	if err != nil {
+		writeSignalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"token": token})
+}
+
+// DeleteSignalSource deletes a signal source and its subscriptions
+// DELETE /api/v1/signal-sources/:id
+func (h *SignalHandler) DeleteSignalSource(c *gin.Context) {
+	userID, id, ok := signalParams(c, "invalid signal source id")
+	if !ok {
+		return
+	}
+
+	if err := h.signals.DeleteSource(c.Request.Context(), userID, id); err != nil {
+		writeSignalError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetSignalSourceLogs returns the latest signals one of the user's sources
+// received, for every subscription
+// GET /api/v1/signal-sources/:id/logs
+func (h *SignalHandler) GetSignalSourceLogs(c *gin.Context) {
+	h.withSignalLogs(c, "invalid signal source id", h.signals.SourceLogs)
+}
+
+// CreateSignalSubscriptionRequest is the body of a create signal
+// subscription request
+type CreateSignalSubscriptionRequest struct {
+	SourceID          uuid.UUID `json:"source_id" binding:"required"`
+	Market            string    `json:"market"`                         // Every market when empty
+	Strategy          string    `json:"strategy"`                       // Every signal when empty
+	OpenAmount        float64   `json:"open_amount" binding:"required"` // KRW per open
+	MaxOrderAmount    float64   `json:"max_order_amount"`
+	MaxPositionAmount float64   `json:"max_position_amount"`
+	MaxDailyOrders    int       `json:"max_daily_orders"`
+}
+
+// CreateSignalSubscription subscribes the user to a signal source
+// POST /api/v1/signal-subscriptions
+func (h *SignalHandler) CreateSignalSubscription(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req CreateSignalSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sub, err := h.signals.Subscribe(c.Request.Context(), &model.SignalSubscription{
+		UserID:            userID,
+		SourceID:          req.SourceID,
+		Market:            req.Market,
+		Strategy:          req.Strategy,
+		OpenAmount:        req.OpenAmount,
+		MaxOrderAmount:    req.MaxOrderAmount,
+		MaxPositionAmount: req.MaxPositionAmount,
+		MaxDailyOrders:    req.MaxDailyOrders,
+	})
+	if err != nil {
+		writeSignalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, sub)
+}
+
+// ListSignalSubscriptions returns the user's signal subscriptions
+// GET /api/v1/signal-subscriptions
+func (h *SignalHandler) ListSignalSubscriptions(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	subs, err := h.signals.ListSubscriptions(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if subs == nil {
+		subs = []*model.SignalSubscription{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"signal_subscriptions": subs})
+}
+
+// GetSignalSubscription returns one of the user's signal subscriptions
+// GET /api/v1/signal-subscriptions/:id
+func (h *SignalHandler) GetSignalSubscription(c *gin.Context) {
+	h.withSignalSubscription(c, h.signals.GetSubscription)
+}
+
+// PauseSignalSubscription stops a subscription following its source
+// POST /api/v1/signal-subscriptions/:id/pause
+func (h *SignalHandler) PauseSignalSubscription(c *gin.Context) {
+	h.withSignalSubscription(c, func(ctx context.Context, userID, id uuid.UUID) (*model.SignalSubscription, error) {
+		return h.signals.SetSubscriptionActive(ctx, userID, id, false)
+	})
+}
+
+// ResumeSignalSubscription makes a paused subscription follow its source
+// again
+// POST /api/v1/signal-subscriptions/:id/resume
+func (h *SignalHandler) ResumeSignalSubscription(c *gin.Context) {
+	h.withSignalSubscription(c, func(ctx context.Context, userID, id uuid.UUID) (*model.SignalSubscription, error) {
+		return h.signals.SetSubscriptionActive(ctx, userID, id, true)
+	})
+}
+
+// DeleteSignalSubscription unsubscribes the user from a signal source.
+// Positions the subscription opened are kept.
+// DELETE /api/v1/signal-subscriptions/:id
+func (h *SignalHandler) DeleteSignalSubscription(c *gin.Context) {
+	userID, id, ok := signalParams(c, "invalid signal subscription id")
+	if !ok {
+		return
+	}
+
+	if err := h.signals.Unsubscribe(c.Request.Context(), userID, id); err != nil {
+		writeSignalError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetSignalSubscriptionLogs returns the latest signals a subscription
+// followed and what they did
+// GET /api/v1/signal-subscriptions/:id/logs
+func (h *SignalHandler) GetSignalSubscriptionLogs(c *gin.Context) {
+	h.withSignalLogs(c, "invalid signal subscription id", h.signals.SubscriptionLogs)
+}
+
+// ReceiveSignal carries out an alert sent to a webhook source's token. The
+// body is read as JSON whatever its content type, as TradingView sends
+// text/plain.
+// POST /api/v1/signals/:token
+func (h *SignalHandler) ReceiveSignal(c *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxAlertSize))
+	if err != nil {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "alert is too large"})
+		return
+	}
+
+	logs, err := h.signals.HandleWebhook(c.Request.Context(), c.Param("token"), json.RawMessage(body))
+	if err != nil {
+		writeSignalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": logs})
+}
+
+func (h *SignalHandler) withSignalSource(c *gin.Context, fn func(ctx context.Context, userID, id uuid.UUID) (*model.SignalSource, error)) {
+	userID, id, ok := signalParams(c, "invalid signal source id")
+	if !ok {
+		return
+	}
+
+	source, err := fn(c.Request.Context(), userID, id)
+	if err != nil {
+		writeSignalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, source)
+}
+
+func (h *SignalHandler) withSignalSubscription(c *gin.Context, fn func(ctx context.Context, userID, id uuid.UUID) (*model.SignalSubscription, error)) {
+	userID, id, ok := signalParams(c, "invalid signal subscription id")
+	if !ok {
+		return
+	}
+
+	sub, err := fn(c.Request.Context(), userID, id)
+	if err != nil {
+		writeSignalError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sub)
+}
+
+func (h *SignalHandler) withSignalLogs(c *gin.Context, invalidID string, fn func(ctx context.Context, userID, id uuid.UUID, limit int) ([]*model.SignalLog, error)) {
+	userID, id, ok := signalParams(c, invalidID)
+	if !ok {
+		return
+	}
+
+	limit := signals.DefaultLogLimit
+	if s := c.Query("limit"); s != "" {
+		var err error
+		if limit, err = strconv.Atoi(s); err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit parameter"})
+			return
+		}
+	}
+
+	logs, err := fn(c.Request.Context(), userID, id, limit)
+	if err != nil {
+		writeSignalError(c, err)
+		return
+	}
+	if logs == nil {
+		logs = []*model.SignalLog{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"logs": logs})
+}
+
+// signalParams reads the user and the ID of a signal source or subscription
+// of a request, writing the error response if either is missing
+func signalParams(c *gin.Context, invalidID string) (uuid.UUID, uuid.UUID, bool) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": invalidID})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, id, true
+}
+
+func writeSignalError(c *gin.Context, err error) {
+	var signalErr *signals.SignalError
+	var tradingErr *trading.TradingError
+	switch {
+	case errors.Is(err, signals.ErrInvalidToken):
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errors.As(err, &signalErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	case errors.Is(err, trading.ErrVelocityLimit):
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	case errors.Is(err, trading.ErrMaintenance):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	case errors.As(err, &tradingErr):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sungminna/upbit-trading-platform/internal/service/sizing"
+)
+
+// SizingHandler handles position sizing endpoints
+type SizingHandler struct {
+	calculator *sizing.Calculator
+}
+
+// NewSizingHandler creates a new sizing handler
+func NewSizingHandler(calculator *sizing.Calculator) *SizingHandler {
+	return &SizingHandler{calculator: calculator}
+}
+
+// Calculate computes the quantity to buy for the given equity and risk
+// POST /api/v1/sizing/calculate
+func (h *SizingHandler) Calculate(c *gin.Context) {
+	var req sizing.Request
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.calculator.Calculate(c.Request.Context(), req)
+	if err != nil {
+		var sizingErr *sizing.SizingError
+		if errors.As(err, &sizingErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sungminna/upbit-trading-platform/internal/api/middleware"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/telegram"
+)
+
+// TelegramHandler handles Telegram account linking endpoints
+type TelegramHandler struct {
+	bot   *telegram.Bot
+	links repository.TelegramLinkRepository
+}
+
+// NewTelegramHandler creates a new Telegram handler
+func NewTelegramHandler(bot *telegram.Bot, links repository.TelegramLinkRepository) *TelegramHandler {
+	return &TelegramHandler{
+		bot:   bot,
+		links: links,
+	}
+}
+
+// CreateLinkCode creates a one-time code the user sends to the bot with /link
+// POST /api/v1/telegram/link-code
+func (h *TelegramHandler) CreateLinkCode(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	code, expiresAt, err := h.bot.NewLinkCode(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"code": code, "expires_at": expiresAt})
+}
+
+// GetLink returns the chat linked to the user
+// GET /api/v1/telegram/link
+func (h *TelegramHandler) GetLink(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	link, err := h.links.GetByUserID(c.Request.Context(), userID)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "telegram not linked"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, link)
+}
+
+// DeleteLink unlinks the user's chat
+// DELETE /api/v1/telegram/link
+func (h *TelegramHandler) DeleteLink(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = h.links.DeleteByUserID(c.Request.Context(), userID)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "telegram not linked"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,230 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/api/middleware"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/trading"
+)
+
+// TradingHandler handles kill switch and order velocity limit endpoints
+type TradingHandler struct {
+	engine *trading.Engine
+}
+
+// NewTradingHandler creates a new trading handler
+func NewTradingHandler(engine *trading.Engine) *TradingHandler {
+	return &TradingHandler{engine: engine}
+}
+
+// HaltRequest is the body of a halt request
+type HaltRequest struct {
+	Reason       string `json:"reason"`
+	CancelOrders bool   `json:"cancel_orders"` // Also cancel resting orders
+}
+
+// HaltStatusResponse reports whether trading is halted
+type HaltStatusResponse struct {
+	Halted bool               `json:"halted"`
+	Halt   *model.TradingHalt `json:"halt,omitempty"`
+}
+
+// GetHalt reports whether the user's trading is halted, by them or globally
+// GET /api/v1/trading/halt
+func (h *TradingHandler) GetHalt(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	halt, err := h.engine.ActiveHalt(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, HaltStatusResponse{Halted: halt != nil, Halt: halt})
+}
+
+// Halt stops the user's order placement until they resume
+// POST /api/v1/trading/halt
+func (h *TradingHandler) Halt(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	h.halt(c, userID, model.HaltedByUser)
+}
+
+// Resume lifts the user's own halt
+// POST /api/v1/trading/resume
+func (h *TradingHandler) Resume(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	h.resume(c, userID)
+}
+
+// GetGlobalHalt reports whether trading is halted for every user
+// GET /api/v1/admin/trading/halt
+func (h *TradingHandler) GetGlobalHalt(c *gin.Context) {
+	halt, err := h.engine.ActiveHalt(c.Request.Context(), uuid.Nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, HaltStatusResponse{Halted: halt != nil, Halt: halt})
+}
+
+// GlobalHalt stops order placement for every user until an admin resumes
+// POST /api/v1/admin/trading/halt
+func (h *TradingHandler) GlobalHalt(c *gin.Context) {
+	h.halt(c, uuid.Nil, model.HaltedByAdmin)
+}
+
+// GlobalResume lifts the global halt
+// POST /api/v1/admin/trading/resume
+func (h *TradingHandler) GlobalResume(c *gin.Context) {
+	h.resume(c, uuid.Nil)
+}
+
+// VelocityLimitsRequest is the body of an admin velocity limit override
+type VelocityLimitsRequest struct {
+	PerMinute int    `json:"per_minute"` // 0 is unlimited
+	PerHour   int    `json:"per_hour"`   // 0 is unlimited
+	Reason    string `json:"reason"`
+}
+
+// GetVelocityLimits returns how many orders the user may place per minute
+// and per hour
+// GET /api/v1/trading/order-limits
+func (h *TradingHandler) GetVelocityLimits(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	h.velocityLimits(c, userID)
+}
+
+// GetUserVelocityLimits returns the velocity limits that apply to a user
+// GET /api/v1/admin/users/:id/order-limits
+func (h *TradingHandler) GetUserVelocityLimits(c *gin.Context) {
+	userID, ok := userParam(c)
+	if !ok {
+		return
+	}
+	h.velocityLimits(c, userID)
+}
+
+// OverrideVelocityLimits replaces the platform velocity limits for a user
+// PUT /api/v1/admin/users/:id/order-limits
+func (h *TradingHandler) OverrideVelocityLimits(c *gin.Context) {
+	userID, ok := userParam(c)
+	if !ok {
+		return
+	}
+
+	var req VelocityLimitsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	limits := &model.VelocityLimits{UserID: userID, PerMinute: req.PerMinute, PerHour: req.PerHour, Reason: req.Reason}
+	err := h.engine.SetVelocityOverride(c.Request.Context(), limits)
+	if errors.Is(err, trading.ErrInvalidVelocityLimits) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, limits)
+}
+
+// ClearVelocityOverride returns a user to the platform velocity limits
+// DELETE /api/v1/admin/users/:id/order-limits
+func (h *TradingHandler) ClearVelocityOverride(c *gin.Context) {
+	userID, ok := userParam(c)
+	if !ok {
+		return
+	}
+
+	err := h.engine.ClearVelocityOverride(c.Request.Context(), userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "no override for this user"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *TradingHandler) velocityLimits(c *gin.Context, userID uuid.UUID) {
+	limits, err := h.engine.VelocityLimits(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, limits)
+}
+
+// userParam returns the user in the path, writing an error response if it
+// is invalid
+func userParam(c *gin.Context) (uuid.UUID, bool) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user id"})
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+func (h *TradingHandler) halt(c *gin.Context, userID uuid.UUID, haltedBy string) {
+	var req HaltRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	result, err := h.engine.Halt(c.Request.Context(), userID, req.Reason, haltedBy, req.CancelOrders)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+func (h *TradingHandler) resume(c *gin.Context, userID uuid.UUID) {
+	err := h.engine.Resume(c.Request.Context(), userID)
+	if errors.Is(err, trading.ErrNotHalted) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
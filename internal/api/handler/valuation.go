@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sungminna/upbit-trading-platform/internal/service/valuation"
+)
+
+// ValuationHandler handles display currency endpoints
+type ValuationHandler struct {
+	valuations *valuation.Service
+}
+
+// NewValuationHandler creates a new valuation handler
+func NewValuationHandler(valuations *valuation.Service) *ValuationHandler {
+	return &ValuationHandler{valuations: valuations}
+}
+
+// GetRates returns the display currencies reports can be requested in and
+// the current KRW rate of each
+// GET /api/v1/rates
+func (h *ValuationHandler) GetRates(c *gin.Context) {
+	rates, err := h.valuations.Rates(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"base":       valuation.Base,
+		"currencies": h.valuations.Currencies(),
+		"rates":      rates,
+	})
+}
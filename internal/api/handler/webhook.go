@@ -0,0 +1,240 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/api/middleware"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/webhook"
+)
+
+// WebhookHandler handles webhook endpoints
+type WebhookHandler struct {
+	webhooks *webhook.Service
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(webhooks *webhook.Service) *WebhookHandler {
+	return &WebhookHandler{
+		webhooks: webhooks,
+	}
+}
+
+// UpdateWebhookRequest is the body of an update webhook request
+type UpdateWebhookRequest struct {
+	webhook.Config
+	Active bool `json:"active"`
+}
+
+// WebhookSecretResponse includes the signing secret, which is only shown when
+// a webhook is created or its secret rotated
+type WebhookSecretResponse struct {
+	*model.Webhook
+	Secret string `json:"secret"`
+}
+
+// CreateWebhook registers a webhook
+// POST /api/v1/webhooks
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req webhook.Config
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hook, err := h.webhooks.Create(c.Request.Context(), userID, req)
+	if err != nil {
+		writeWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, WebhookSecretResponse{Webhook: hook, Secret: hook.Secret})
+}
+
+// ListWebhooks lists the user's webhooks, newest first
+// GET /api/v1/webhooks
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	hooks, err := h.webhooks.List(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if hooks == nil {
+		hooks = []*model.Webhook{}
+	}
+
+	c.JSON(http.StatusOK, hooks)
+}
+
+// GetWebhook returns one of the user's webhooks
+// GET /api/v1/webhooks/:id
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	userID, id, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+
+	hook, err := h.webhooks.Get(c.Request.Context(), userID, id)
+	if err != nil {
+		writeWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, hook)
+}
+
+// UpdateWebhook replaces a webhook's URL, events, retry policy and active flag
+// PUT /api/v1/webhooks/:id
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	userID, id, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	hook, err := h.webhooks.Update(c.Request.Context(), userID, id, req.Config, req.Active)
+	if err != nil {
+		writeWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, hook)
+}
+
+// RotateWebhookSecret replaces a webhook's signing secret
+// POST /api/v1/webhooks/:id/rotate-secret
+func (h *WebhookHandler) RotateWebhookSecret(c *gin.Context) {
+	userID, id, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+
+	hook, err := h.webhooks.RotateSecret(c.Request.Context(), userID, id)
+	if err != nil {
+		writeWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, WebhookSecretResponse{Webhook: hook, Secret: hook.Secret})
+}
+
+// DeleteWebhook deletes one of the user's webhooks
+// DELETE /api/v1/webhooks/:id
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	userID, id, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.webhooks.Delete(c.Request.Context(), userID, id); err != nil {
+		writeWebhookError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeliveries lists a webhook's deliveries, newest first, for debugging
+// GET /api/v1/webhooks/:id/deliveries?status=dead&limit=20
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	userID, id, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+
+	filter := repository.WebhookDeliveryFilter{
+		Status: model.WebhookDeliveryStatus(c.Query("status")),
+	}
+	if s := c.Query("limit"); s != "" {
+		var err error
+		if filter.Limit, err = strconv.Atoi(s); err != nil || filter.Limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit parameter"})
+			return
+		}
+	}
+
+	deliveries, err := h.webhooks.Deliveries(c.Request.Context(), userID, id, filter)
+	if err != nil {
+		writeWebhookError(c, err)
+		return
+	}
+	if deliveries == nil {
+		deliveries = []*model.WebhookDelivery{}
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// Redeliver queues a past delivery's payload again, e.g. a dead delivery
+// POST /api/v1/webhooks/:id/deliveries/:delivery_id/redeliver
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	userID, id, ok := webhookParams(c)
+	if !ok {
+		return
+	}
+
+	deliveryID, err := uuid.Parse(c.Param("delivery_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid delivery id"})
+		return
+	}
+
+	delivery, err := h.webhooks.Redeliver(c.Request.Context(), userID, id, deliveryID)
+	if err != nil {
+		writeWebhookError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, delivery)
+}
+
+// webhookParams returns the authenticated user and the webhook ID, writing an
+// error response if either is missing
+func webhookParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid webhook id"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, id, true
+}
+
+func writeWebhookError(c *gin.Context, err error) {
+	var webhookErr *webhook.WebhookError
+	switch {
+	case errors.As(err, &webhookErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "webhook not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
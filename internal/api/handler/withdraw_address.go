@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/api/middleware"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/addressbook"
+)
+
+// WithdrawAddressHandler handles withdrawal address book endpoints
+type WithdrawAddressHandler struct {
+	addresses *addressbook.Service
+}
+
+// NewWithdrawAddressHandler creates a new withdrawal address book handler
+func NewWithdrawAddressHandler(addresses *addressbook.Service) *WithdrawAddressHandler {
+	return &WithdrawAddressHandler{addresses: addresses}
+}
+
+// AddWithdrawAddressRequest is the body of an add withdrawal address request
+type AddWithdrawAddressRequest struct {
+	Currency         string `json:"currency" binding:"required"`
+	NetType          string `json:"net_type"` // The currency when empty
+	Address          string `json:"address" binding:"required"`
+	SecondaryAddress string `json:"secondary_address"` // Destination tag or memo
+	Label            string `json:"label"`
+}
+
+// ConfirmWithdrawAddressRequest is the body of a confirm withdrawal address
+// request
+type ConfirmWithdrawAddressRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// AddWithdrawAddress adds a pending address to the user's address book and
+// sends its confirmation code to the user's Telegram chat
+// POST /api/v1/withdraw-addresses
+func (h *WithdrawAddressHandler) AddWithdrawAddress(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req AddWithdrawAddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	address, err := h.addresses.Add(c.Request.Context(), &model.WithdrawAddress{
+		UserID:           userID,
+		Currency:         req.Currency,
+		NetType:          req.NetType,
+		Address:          req.Address,
+		SecondaryAddress: req.SecondaryAddress,
+		Label:            req.Label,
+	})
+	if err != nil {
+		writeWithdrawAddressError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, address)
+}
+
+// ListWithdrawAddresses returns the user's address book
+// GET /api/v1/withdraw-addresses
+func (h *WithdrawAddressHandler) ListWithdrawAddresses(c *gin.Context) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	addresses, err := h.addresses.List(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if addresses == nil {
+		addresses = []*model.WithdrawAddress{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"withdraw_addresses": addresses})
+}
+
+// GetWithdrawAddress returns one of the user's addresses
+// GET /api/v1/withdraw-addresses/:id
+func (h *WithdrawAddressHandler) GetWithdrawAddress(c *gin.Context) {
+	userID, id, ok := withdrawAddressParams(c)
+	if !ok {
+		return
+	}
+
+	address, err := h.addresses.Get(c.Request.Context(), userID, id)
+	if err != nil {
+		writeWithdrawAddressError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, address)
+}
+
+// ConfirmWithdrawAddress confirms a pending address with its code
+// POST /api/v1/withdraw-addresses/:id/confirm
+func (h *WithdrawAddressHandler) ConfirmWithdrawAddress(c *gin.Context) {
+	userID, id, ok := withdrawAddressParams(c)
+	if !ok {
+		return
+	}
+
+	var req ConfirmWithdrawAddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	address, err := h.addresses.Confirm(c.Request.Context(), userID, id, req.Code)
+	if err != nil {
+		writeWithdrawAddressError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, address)
+}
+
+// ResendWithdrawAddressCode sends a new confirmation code for a pending
+// address
+// POST /api/v1/withdraw-addresses/:id/resend-code
+func (h *WithdrawAddressHandler) ResendWithdrawAddressCode(c *gin.Context) {
+	userID, id, ok := withdrawAddressParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.addresses.ResendCode(c.Request.Context(), userID, id); err != nil {
+		writeWithdrawAddressError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteWithdrawAddress removes an address from the user's address book
+// DELETE /api/v1/withdraw-addresses/:id
+func (h *WithdrawAddressHandler) DeleteWithdrawAddress(c *gin.Context) {
+	userID, id, ok := withdrawAddressParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.addresses.Delete(c.Request.Context(), userID, id); err != nil {
+		writeWithdrawAddressError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// withdrawAddressParams reads the user and address ID of a request, writing
+// the error response if either is missing
+func withdrawAddressParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid withdraw address id"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, id, true
+}
+
+func writeWithdrawAddressError(c *gin.Context, err error) {
+	var addressErr *addressbook.AddressError
+	switch {
+	case errors.Is(err, addressbook.ErrTooManyAttempts):
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	case errors.As(err, &addressErr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, repository.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "withdraw address not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
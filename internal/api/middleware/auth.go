@@ -14,10 +14,16 @@ const (
 	emailKey  = "email"
 )
 
-// AuthMiddleware creates authentication middleware
+// AuthMiddleware creates authentication middleware. Websocket upgrades may
+// pass the token as ?access_token=, since browsers can't set their headers.
 func AuthMiddleware(jwtManager *jwtpkg.Manager) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" && c.IsWebsocket() {
+			if token := c.Query("access_token"); token != "" {
+				authHeader = "Bearer " + token
+			}
+		}
 		if authHeader == "" {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header required"})
 			c.Abort()
@@ -1,20 +1,100 @@
 package router
 
 import (
-	"time"
-
 	"github.com/gin-gonic/gin"
 	"github.com/sungminna/upbit-trading-platform/internal/api/handler"
 	"github.com/sungminna/upbit-trading-platform/internal/api/middleware"
-	"github.com/sungminna/upbit-trading-platform/internal/upbit/quotation"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/gateway"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/addressbook"
+	"github.com/sungminna/upbit-trading-platform/internal/service/alert"
+	"github.com/sungminna/upbit-trading-platform/internal/service/analytics"
+	"github.com/sungminna/upbit-trading-platform/internal/service/apikey"
+	"github.com/sungminna/upbit-trading-platform/internal/service/averaging"
+	"github.com/sungminna/upbit-trading-platform/internal/service/backtest"
+	"github.com/sungminna/upbit-trading-platform/internal/service/balance"
+	"github.com/sungminna/upbit-trading-platform/internal/service/export"
+	"github.com/sungminna/upbit-trading-platform/internal/service/guard"
+	"github.com/sungminna/upbit-trading-platform/internal/service/journal"
+	"github.com/sungminna/upbit-trading-platform/internal/service/ledger"
+	"github.com/sungminna/upbit-trading-platform/internal/service/maintenance"
+	"github.com/sungminna/upbit-trading-platform/internal/service/marketplace"
+	"github.com/sungminna/upbit-trading-platform/internal/service/notification"
+	"github.com/sungminna/upbit-trading-platform/internal/service/onboarding"
+	"github.com/sungminna/upbit-trading-platform/internal/service/portfolio"
+	"github.com/sungminna/upbit-trading-platform/internal/service/preset"
+	"github.com/sungminna/upbit-trading-platform/internal/service/push"
+	"github.com/sungminna/upbit-trading-platform/internal/service/queue"
+	"github.com/sungminna/upbit-trading-platform/internal/service/rebalance"
+	"github.com/sungminna/upbit-trading-platform/internal/service/recurring"
+	"github.com/sungminna/upbit-trading-platform/internal/service/replay"
+	"github.com/sungminna/upbit-trading-platform/internal/service/report"
+	"github.com/sungminna/upbit-trading-platform/internal/service/retention"
+	"github.com/sungminna/upbit-trading-platform/internal/service/risk"
+	"github.com/sungminna/upbit-trading-platform/internal/service/scheduler"
+	"github.com/sungminna/upbit-trading-platform/internal/service/signals"
+	"github.com/sungminna/upbit-trading-platform/internal/service/sizing"
+	"github.com/sungminna/upbit-trading-platform/internal/service/telegram"
+	"github.com/sungminna/upbit-trading-platform/internal/service/trading"
+	"github.com/sungminna/upbit-trading-platform/internal/service/valuation"
+	"github.com/sungminna/upbit-trading-platform/internal/service/webhook"
+	"github.com/sungminna/upbit-trading-platform/pkg/cache"
+	"github.com/sungminna/upbit-trading-platform/pkg/callstats"
+	"github.com/sungminna/upbit-trading-platform/pkg/httppool"
 	jwtpkg "github.com/sungminna/upbit-trading-platform/pkg/jwt"
+	"github.com/sungminna/upbit-trading-platform/pkg/latency"
+	"github.com/sungminna/upbit-trading-platform/pkg/ratelimit"
+	"github.com/sungminna/upbit-trading-platform/pkg/recovery"
+	"github.com/sungminna/upbit-trading-platform/pkg/symbol"
 )
 
 // Config holds router configuration
 type Config struct {
-	JWTSecret      string
-	JWTExpiry      time.Duration
-	QuotationClient *quotation.Client
+	JWT                  *jwtpkg.Manager
+	QuotationClient      gateway.QuotationAPI
+	Cache                cache.Cache
+	AdminToken           string
+	MarketData           repository.MarketDataMaintenance // Optional; requires ClickHouse
+	Candles              repository.CandleRepository      // Optional; requires ClickHouse
+	CandleStatus         repository.CandleStoreMonitor    // Optional; requires ClickHouse
+	Snapshots            repository.SnapshotRepository    // Optional; requires trading storage
+	Positions            repository.PositionRepository    // Optional; requires trading storage
+	Backtests            repository.BacktestRepository    // Optional; requires trading storage
+	Orders               repository.OrderRepository       // Optional; requires trading storage
+	Executions           repository.OrderExecutionRepository
+	PositionEvents       repository.PositionEventRepository // Optional; enables position history
+	Replayer             *replay.Replayer
+	Alerts               *alert.Service // Optional; requires trading storage
+	Telegram             *telegram.Bot  // Optional; requires a bot token
+	TelegramLinks        repository.TelegramLinkRepository
+	Notifier             *notification.Service
+	NotificationSettings repository.NotificationSettingsRepository // Optional; requires trading storage
+	Webhooks             *webhook.Service                          // Optional; requires trading storage
+	Risk                 *risk.Service                             // Optional; requires trading storage
+	Engine               *trading.Engine                           // Optional; enables the kill switch and placing average-down buys
+	Guards               *guard.Service                            // Optional; requires trading storage
+	Portfolio            *portfolio.Service                        // Optional; requires trading storage
+	Valuation            *valuation.Service                        // Optional; enables display currencies other than KRW
+	Balances             *balance.Service                          // Optional; requires trading storage
+	Rebalance            *rebalance.Service                        // Optional; requires trading storage
+	Ledger               *ledger.Service                           // Optional; requires trading storage
+	Recurring            *recurring.Service                        // Optional; requires trading storage
+	Presets              *preset.Service                           // Optional; requires trading storage
+	Signals              *signals.Service                          // Optional; requires trading storage
+	AddressBook          *addressbook.Service                      // Optional; requires trading storage and the Telegram bot
+	Onboarding           *onboarding.Service                       // Optional; requires trading storage
+	APIKeys              *apikey.Service                           // Optional; requires trading storage
+	Marketplace          *marketplace.Service                      // Optional; requires trading storage
+	Maintenance          *maintenance.Detector                     // Optional; requires trading storage
+	Retention            *retention.Service                        // Optional; requires trading storage
+	Jobs                 *scheduler.Scheduler
+	Queue                *queue.Queue // Optional; requires trading storage
+	RateLimits           map[string]*ratelimit.Metrics
+	APICalls             map[string]*callstats.Recorder
+	Connections          map[string]*httppool.Transport
+	Latency              *latency.Recorder
+	Panics               *recovery.Recorder
+	Push                 *push.Hub // Optional; streams notifications and order events over /ws
 }
 
 // Setup sets up the Gin router
@@ -41,27 +121,397 @@ func Setup(cfg *Config) *gin.Engine {
 		c.JSON(200, gin.H{"status": "ok"})
 	})
 
-	// JWT manager
-	jwtManager := jwtpkg.NewManager(cfg.JWTSecret, cfg.JWTExpiry)
+	// Users' notifications and order events, pushed as they happen
+	if cfg.Push != nil {
+		pushHandler := handler.NewPushHandler(cfg.Push)
+		r.GET("/ws", middleware.AuthMiddleware(cfg.JWT), pushHandler.Connect)
+	}
+
+	// RS256 public keys, so other services can verify tokens
+	r.GET("/.well-known/jwks.json", func(c *gin.Context) {
+		c.JSON(200, cfg.JWT.JWKS())
+	})
+
+	// Requests may name markets by normalized symbol (BTC/KRW) or Upbit code
+	symbols := symbol.NewRegistry()
 
 	// Public API endpoints (no authentication required)
 	publicAPI := r.Group("/api/v1")
 	{
 		// Market data endpoints
-		marketHandler := handler.NewMarketHandler(cfg.QuotationClient)
+		marketHandler := handler.NewMarketHandler(cfg.QuotationClient, cfg.Cache).WithSymbols(symbols)
+		if cfg.Candles != nil {
+			marketHandler.WithCandleStore(cfg.Candles)
+		}
 		publicAPI.GET("/markets", marketHandler.GetMarkets)
-		publicAPI.GET("/candles/:market", marketHandler.GetCandles)
-		publicAPI.GET("/orderbook/:market", marketHandler.GetOrderbook)
+		publicAPI.GET("/candles/*market", marketHandler.GetCandles)
+		publicAPI.GET("/orderbook/*market", marketHandler.GetOrderbook)
 		publicAPI.GET("/ticker", marketHandler.GetTicker)
+
+		// Display currency rates
+		if cfg.Valuation != nil {
+			publicAPI.GET("/rates", handler.NewValuationHandler(cfg.Valuation).GetRates)
+		}
+
+		// Inbound signals authenticate with their source's token, as
+		// providers such as TradingView can't send an Authorization header
+		if cfg.Signals != nil {
+			signalHandler := handler.NewSignalHandler(cfg.Signals)
+			publicAPI.POST("/signals/:token", signalHandler.ReceiveSignal)
+		}
 	}
 
 	// Protected API endpoints (authentication required)
 	protectedAPI := r.Group("/api/v1")
-	protectedAPI.Use(middleware.AuthMiddleware(jwtManager))
+	protectedAPI.Use(middleware.AuthMiddleware(cfg.JWT))
 	{
 		// User endpoints would go here
-		// Position endpoints would go here
-		// Order endpoints would go here
+
+		// Position and order endpoints
+		if cfg.Positions != nil && cfg.Orders != nil {
+			journalService := journal.NewService(cfg.Positions, cfg.Orders).WithEvents(cfg.PositionEvents)
+			journalHandler := handler.NewJournalHandler(journalService)
+			protectedAPI.GET("/positions", journalHandler.ListPositions)
+			if cfg.PositionEvents != nil {
+				protectedAPI.GET("/positions/:id/history", journalHandler.PositionHistory)
+			}
+
+			planner := averaging.NewPlanner(cfg.Positions)
+			if cfg.Engine != nil {
+				planner.WithPlacer(cfg.Engine)
+			}
+			averagingHandler := handler.NewAveragingHandler(planner)
+			protectedAPI.POST("/positions/:id/average-down/preview", averagingHandler.Preview)
+			protectedAPI.PUT("/positions/:id/journal", journalHandler.AnnotatePosition)
+			protectedAPI.GET("/orders", journalHandler.ListOrders)
+			protectedAPI.PUT("/orders/:id/journal", journalHandler.AnnotateOrder)
+		}
+
+		// Market analytics endpoints
+		analyticsHandler := handler.NewAnalyticsHandler(analytics.NewService(cfg.QuotationClient)).WithSymbols(symbols)
+		protectedAPI.GET("/analytics/correlation", analyticsHandler.GetCorrelation)
+
+		// Backtesting endpoints
+		backtester := backtest.NewBacktester(cfg.QuotationClient)
+		var divergence *backtest.DivergenceTracker
+		if cfg.Backtests != nil && cfg.Orders != nil && cfg.Executions != nil {
+			divergence = backtest.NewDivergenceTracker(backtester, cfg.Orders, cfg.Executions)
+		}
+		backtestHandler := handler.NewBacktestHandler(backtester, cfg.Backtests, divergence).WithSymbols(symbols)
+		if cfg.Queue != nil {
+			// Backtests queued with ?async=true run on any instance's queue workers
+			backtestHandler.WithJobs(backtest.NewJobs(backtester, cfg.Backtests, cfg.Queue))
+		}
+		protectedAPI.GET("/backtests/strategies", backtestHandler.GetStrategies)
+		protectedAPI.POST("/backtests/run", backtestHandler.RunBacktest)
+		protectedAPI.POST("/backtests/optimize", backtestHandler.OptimizeBacktest)
+		protectedAPI.POST("/backtests/walk-forward", backtestHandler.WalkForwardBacktest)
+		if cfg.Backtests != nil {
+			protectedAPI.GET("/backtests", backtestHandler.ListBacktests)
+			protectedAPI.GET("/backtests/compare", backtestHandler.CompareBacktests)
+			protectedAPI.GET("/backtests/:id", backtestHandler.GetBacktest)
+		}
+		if divergence != nil {
+			protectedAPI.GET("/backtests/:id/divergence", backtestHandler.GetBacktestDivergence)
+		}
+
+		// Position sizing endpoints
+		sizingHandler := handler.NewSizingHandler(sizing.NewCalculator(cfg.QuotationClient))
+		protectedAPI.POST("/sizing/calculate", sizingHandler.Calculate)
+
+		// Price alert endpoints
+		if cfg.Alerts != nil {
+			alertHandler := handler.NewAlertHandler(cfg.Alerts).WithSymbols(symbols)
+			protectedAPI.POST("/alerts", alertHandler.CreateAlert)
+			protectedAPI.GET("/alerts", alertHandler.ListAlerts)
+			protectedAPI.DELETE("/alerts/:id", alertHandler.DeleteAlert)
+		}
+
+		// Notification settings endpoints
+		if cfg.NotificationSettings != nil {
+			notificationHandler := handler.NewNotificationHandler(cfg.NotificationSettings, cfg.Notifier)
+			protectedAPI.GET("/notifications/settings", notificationHandler.GetSettings)
+			protectedAPI.PUT("/notifications/settings", notificationHandler.UpdateSettings)
+		}
+
+		// Webhook endpoints
+		if cfg.Webhooks != nil {
+			webhookHandler := handler.NewWebhookHandler(cfg.Webhooks)
+			protectedAPI.POST("/webhooks", webhookHandler.CreateWebhook)
+			protectedAPI.GET("/webhooks", webhookHandler.ListWebhooks)
+			protectedAPI.GET("/webhooks/:id", webhookHandler.GetWebhook)
+			protectedAPI.PUT("/webhooks/:id", webhookHandler.UpdateWebhook)
+			protectedAPI.DELETE("/webhooks/:id", webhookHandler.DeleteWebhook)
+			protectedAPI.POST("/webhooks/:id/rotate-secret", webhookHandler.RotateWebhookSecret)
+			protectedAPI.GET("/webhooks/:id/deliveries", webhookHandler.ListDeliveries)
+			protectedAPI.POST("/webhooks/:id/deliveries/:delivery_id/redeliver", webhookHandler.Redeliver)
+		}
+
+		// Risk limit endpoints
+		if cfg.Risk != nil {
+			riskHandler := handler.NewRiskHandler(cfg.Risk)
+			protectedAPI.GET("/risk/limits", riskHandler.GetLimits)
+			protectedAPI.PUT("/risk/limits", riskHandler.UpdateLimits)
+			protectedAPI.GET("/risk/exposure", riskHandler.GetExposure)
+		}
+
+		// Order placement endpoints
+		if cfg.Engine != nil {
+			orderHandler := handler.NewOrderHandler(cfg.Engine)
+			if cfg.Presets != nil {
+				orderHandler.WithPresets(cfg.Presets)
+			}
+			protectedAPI.POST("/orders", orderHandler.PlaceOrder)
+			protectedAPI.GET("/orders/:id", orderHandler.GetOrder)
+			protectedAPI.DELETE("/orders/:id", orderHandler.CancelOrder)
+		}
+
+		// Kill switch and order velocity limit endpoints
+		if cfg.Engine != nil {
+			tradingHandler := handler.NewTradingHandler(cfg.Engine)
+			protectedAPI.GET("/trading/halt", tradingHandler.GetHalt)
+			protectedAPI.POST("/trading/halt", tradingHandler.Halt)
+			protectedAPI.POST("/trading/resume", tradingHandler.Resume)
+			protectedAPI.GET("/trading/order-limits", tradingHandler.GetVelocityLimits)
+		}
+
+		// Drawdown guard endpoints
+		if cfg.Guards != nil {
+			guardHandler := handler.NewGuardHandler(cfg.Guards)
+			protectedAPI.GET("/drawdown-guards", guardHandler.ListGuards)
+			protectedAPI.GET("/positions/:id/drawdown-guard", guardHandler.GetGuard)
+			protectedAPI.GET("/positions/:id/drawdown-guard/history", guardHandler.GetHistory)
+			protectedAPI.PUT("/positions/:id/drawdown-guard", guardHandler.AttachGuard)
+			protectedAPI.DELETE("/positions/:id/drawdown-guard", guardHandler.DetachGuard)
+		}
+
+		// Telegram account linking endpoints
+		if cfg.Telegram != nil {
+			telegramHandler := handler.NewTelegramHandler(cfg.Telegram, cfg.TelegramLinks)
+			protectedAPI.POST("/telegram/link-code", telegramHandler.CreateLinkCode)
+			protectedAPI.GET("/telegram/link", telegramHandler.GetLink)
+			protectedAPI.DELETE("/telegram/link", telegramHandler.DeleteLink)
+		}
+
+		// Portfolio analytics endpoints
+		if cfg.Snapshots != nil && cfg.Positions != nil {
+			portfolioHandler := handler.NewPortfolioHandler(cfg.Snapshots, cfg.Positions, cfg.Portfolio, cfg.QuotationClient)
+			protectedAPI.GET("/portfolio/performance", portfolioHandler.GetPerformance)
+			if cfg.Portfolio != nil {
+				protectedAPI.GET("/portfolio", portfolioHandler.GetPortfolio)
+				protectedAPI.GET("/portfolio/equity", portfolioHandler.GetEquityCurve)
+			}
+		}
+
+		// Rebalancing endpoints
+		if cfg.Rebalance != nil {
+			rebalanceHandler := handler.NewRebalanceHandler(cfg.Rebalance)
+			protectedAPI.GET("/rebalance/target", rebalanceHandler.GetTarget)
+			protectedAPI.PUT("/rebalance/target", rebalanceHandler.SaveTarget)
+			protectedAPI.DELETE("/rebalance/target", rebalanceHandler.DeleteTarget)
+			protectedAPI.GET("/rebalance/preview", rebalanceHandler.Preview)
+			protectedAPI.POST("/rebalance", rebalanceHandler.Rebalance)
+		}
+
+		// Exchange balances with the funds committed by open orders
+		if cfg.Balances != nil {
+			accountHandler := handler.NewAccountHandler(cfg.Balances)
+			protectedAPI.GET("/accounts", accountHandler.GetAccounts)
+		}
+
+		// Cash ledger endpoints
+		if cfg.Ledger != nil {
+			ledgerHandler := handler.NewLedgerHandler(cfg.Ledger)
+			protectedAPI.GET("/ledger", ledgerHandler.ListEntries)
+			protectedAPI.GET("/ledger/reconciliation", ledgerHandler.Reconcile)
+		}
+
+		// Upbit maintenance status
+		if cfg.Maintenance != nil {
+			maintenanceHandler := handler.NewMaintenanceHandler(cfg.Maintenance)
+			protectedAPI.GET("/maintenance", maintenanceHandler.GetStatus)
+		}
+
+		// Recurring order endpoints
+		if cfg.Recurring != nil {
+			recurringHandler := handler.NewRecurringHandler(cfg.Recurring)
+			protectedAPI.POST("/recurring-orders", recurringHandler.CreateRecurringOrder)
+			protectedAPI.GET("/recurring-orders", recurringHandler.ListRecurringOrders)
+			protectedAPI.GET("/recurring-orders/:id", recurringHandler.GetRecurringOrder)
+			protectedAPI.DELETE("/recurring-orders/:id", recurringHandler.DeleteRecurringOrder)
+			protectedAPI.POST("/recurring-orders/:id/pause", recurringHandler.PauseRecurringOrder)
+			protectedAPI.POST("/recurring-orders/:id/resume", recurringHandler.ResumeRecurringOrder)
+			protectedAPI.GET("/recurring-orders/:id/runs", recurringHandler.ListRecurringOrderRuns)
+		}
+
+		// Order preset endpoints
+		if cfg.Presets != nil {
+			presetHandler := handler.NewPresetHandler(cfg.Presets)
+			protectedAPI.GET("/order-presets", presetHandler.ListPresets)
+			protectedAPI.GET("/order-presets/:name", presetHandler.GetPreset)
+			protectedAPI.PUT("/order-presets/:name", presetHandler.SavePreset)
+			protectedAPI.DELETE("/order-presets/:name", presetHandler.DeletePreset)
+			protectedAPI.POST("/order-presets/:name/place", presetHandler.PlacePreset)
+		}
+
+		// Signal source and subscription endpoints
+		if cfg.Signals != nil {
+			signalHandler := handler.NewSignalHandler(cfg.Signals)
+			protectedAPI.POST("/signal-sources", signalHandler.CreateSignalSource)
+			protectedAPI.GET("/signal-sources", signalHandler.ListSignalSources)
+			protectedAPI.GET("/signal-sources/public", signalHandler.ListPublicSignalSources)
+			protectedAPI.GET("/signal-sources/:id", signalHandler.GetSignalSource)
+			protectedAPI.DELETE("/signal-sources/:id", signalHandler.DeleteSignalSource)
+			protectedAPI.POST("/signal-sources/:id/pause", signalHandler.PauseSignalSource)
+			protectedAPI.POST("/signal-sources/:id/resume", signalHandler.ResumeSignalSource)
+			protectedAPI.POST("/signal-sources/:id/rotate-token", signalHandler.RotateSignalSourceToken)
+			protectedAPI.GET("/signal-sources/:id/logs", signalHandler.GetSignalSourceLogs)
+			protectedAPI.POST("/signal-subscriptions", signalHandler.CreateSignalSubscription)
+			protectedAPI.GET("/signal-subscriptions", signalHandler.ListSignalSubscriptions)
+			protectedAPI.GET("/signal-subscriptions/:id", signalHandler.GetSignalSubscription)
+			protectedAPI.DELETE("/signal-subscriptions/:id", signalHandler.DeleteSignalSubscription)
+			protectedAPI.POST("/signal-subscriptions/:id/pause", signalHandler.PauseSignalSubscription)
+			protectedAPI.POST("/signal-subscriptions/:id/resume", signalHandler.ResumeSignalSubscription)
+			protectedAPI.GET("/signal-subscriptions/:id/logs", signalHandler.GetSignalSubscriptionLogs)
+		}
+
+		// Withdrawal address book endpoints
+		if cfg.AddressBook != nil {
+			addressHandler := handler.NewWithdrawAddressHandler(cfg.AddressBook)
+			protectedAPI.POST("/withdraw-addresses", addressHandler.AddWithdrawAddress)
+			protectedAPI.GET("/withdraw-addresses", addressHandler.ListWithdrawAddresses)
+			protectedAPI.GET("/withdraw-addresses/:id", addressHandler.GetWithdrawAddress)
+			protectedAPI.DELETE("/withdraw-addresses/:id", addressHandler.DeleteWithdrawAddress)
+			protectedAPI.POST("/withdraw-addresses/:id/confirm", addressHandler.ConfirmWithdrawAddress)
+			protectedAPI.POST("/withdraw-addresses/:id/resend-code", addressHandler.ResendWithdrawAddressCode)
+		}
+
+		// Strategy template catalog endpoints
+		if cfg.Marketplace != nil {
+			marketplaceHandler := handler.NewMarketplaceHandler(cfg.Marketplace)
+			protectedAPI.POST("/strategy-templates", marketplaceHandler.PublishTemplate)
+			protectedAPI.GET("/strategy-templates", marketplaceHandler.ListTemplates)
+			protectedAPI.GET("/strategy-templates/:id", marketplaceHandler.GetTemplate)
+			protectedAPI.DELETE("/strategy-templates/:id", marketplaceHandler.UnpublishTemplate)
+			protectedAPI.POST("/strategy-templates/:id/clone", marketplaceHandler.CloneTemplate)
+		}
+
+		// Onboarding wizard endpoints
+		if cfg.Onboarding != nil {
+			onboardingHandler := handler.NewOnboardingHandler(cfg.Onboarding)
+			protectedAPI.GET("/onboarding", onboardingHandler.GetOnboarding)
+			protectedAPI.POST("/onboarding/paper-account", onboardingHandler.CreatePaperAccount)
+			protectedAPI.POST("/onboarding/paper-account/reset", onboardingHandler.ResetPaperAccount)
+			protectedAPI.PUT("/onboarding/mode", onboardingHandler.SetTradingMode)
+		}
+
+		// Upbit API key endpoints
+		if cfg.APIKeys != nil {
+			apiKeyHandler := handler.NewAPIKeyHandler(cfg.APIKeys)
+			protectedAPI.GET("/users/api-keys/:id/permissions", apiKeyHandler.GetPermissions)
+		}
+
+		// Report endpoints
+		if cfg.Orders != nil && cfg.Executions != nil {
+			reports := report.NewService(cfg.Orders, cfg.Executions).WithPositions(cfg.Positions)
+			if cfg.Guards != nil {
+				reports.WithGuards(cfg.Guards)
+			}
+			if cfg.Valuation != nil {
+				reports.WithValuation(cfg.Valuation)
+			}
+			reportHandler := handler.NewReportHandler(reports)
+			protectedAPI.GET("/reports/pnl", reportHandler.GetPnL)
+			protectedAPI.GET("/trades", reportHandler.ListTrades)
+
+			exports := export.NewService(cfg.Orders, cfg.Executions, reports).WithCandles(cfg.QuotationClient)
+			exportHandler := handler.NewExportHandler(exports)
+			protectedAPI.GET("/export/orders.csv", exportHandler.ExportOrders)
+			protectedAPI.GET("/export/executions.csv", exportHandler.ExportExecutions)
+			protectedAPI.GET("/export/trades.csv", exportHandler.ExportTrades)
+			protectedAPI.GET("/export/candles.csv", exportHandler.ExportCandles)
+			if cfg.Queue != nil {
+				exportHandler.WithJobs(export.NewJobs(exports, cfg.Queue))
+				protectedAPI.POST("/export/orders.csv", exportHandler.QueueOrderExport)
+				protectedAPI.POST("/export/executions.csv", exportHandler.QueueExecutionExport)
+				protectedAPI.POST("/export/trades.csv", exportHandler.QueueTradeExport)
+				protectedAPI.POST("/export/candles.csv", exportHandler.QueueCandleExport)
+			}
+		}
+
+		// Background jobs users started, such as queued backtests and exports
+		if cfg.Queue != nil {
+			jobHandler := handler.NewJobHandler(cfg.Queue)
+			protectedAPI.GET("/jobs", jobHandler.ListJobs)
+			protectedAPI.GET("/jobs/:id", jobHandler.GetJob)
+			protectedAPI.GET("/jobs/:id/result", jobHandler.GetJobResult)
+		}
+	}
+
+	// Admin endpoints (operator token required)
+	adminAPI := r.Group("/api/v1/admin")
+	adminAPI.Use(middleware.AdminMiddleware(cfg.AdminToken))
+	{
+		adminHandler := handler.NewAdminHandler(cfg.MarketData).WithJobs(cfg.Jobs).WithQueue(cfg.Queue).
+			WithRateLimits(cfg.RateLimits).WithAPICalls(cfg.APICalls).WithConnections(cfg.Connections).WithLatency(cfg.Latency).WithCandleStatus(cfg.CandleStatus).
+			WithRetention(cfg.Retention).WithPanics(cfg.Panics)
+		if cfg.MarketData != nil {
+			adminAPI.GET("/storage/tables", adminHandler.GetStorageTables)
+			adminAPI.POST("/storage/cleanup", adminHandler.CleanupStorage)
+		}
+		if cfg.CandleStatus != nil {
+			adminAPI.GET("/storage/candles", adminHandler.GetCandleStorage)
+		}
+		if cfg.Jobs != nil {
+			adminAPI.GET("/jobs", adminHandler.GetJobs)
+		}
+		if cfg.RateLimits != nil {
+			adminAPI.GET("/rate-limits", adminHandler.GetRateLimits)
+		}
+		if cfg.APICalls != nil {
+			adminAPI.GET("/api-calls", adminHandler.GetAPICalls)
+		}
+		if cfg.Connections != nil {
+			adminAPI.GET("/connections", adminHandler.GetConnections)
+		}
+		if cfg.Latency != nil {
+			adminAPI.GET("/latency", adminHandler.GetLatency)
+		}
+		if cfg.Panics != nil {
+			adminAPI.GET("/panics", adminHandler.GetPanics)
+		}
+		if cfg.Queue != nil {
+			adminAPI.GET("/queue/jobs", adminHandler.ListQueuedJobs)
+			adminAPI.POST("/queue/jobs/:id/retry", adminHandler.RetryQueuedJob)
+		}
+		if cfg.Retention != nil {
+			adminAPI.GET("/retention", adminHandler.GetRetention)
+			adminAPI.POST("/retention/run", adminHandler.RunRetention)
+		}
+
+		if cfg.Engine != nil {
+			tradingHandler := handler.NewTradingHandler(cfg.Engine)
+			adminAPI.GET("/trading/halt", tradingHandler.GetGlobalHalt)
+			adminAPI.POST("/trading/halt", tradingHandler.GlobalHalt)
+			adminAPI.POST("/trading/resume", tradingHandler.GlobalResume)
+			adminAPI.GET("/users/:id/order-limits", tradingHandler.GetUserVelocityLimits)
+			adminAPI.PUT("/users/:id/order-limits", tradingHandler.OverrideVelocityLimits)
+			adminAPI.DELETE("/users/:id/order-limits", tradingHandler.ClearVelocityOverride)
+		}
+
+		if cfg.Maintenance != nil {
+			maintenanceHandler := handler.NewMaintenanceHandler(cfg.Maintenance)
+			adminAPI.GET("/maintenance", maintenanceHandler.GetStatus)
+			adminAPI.POST("/maintenance/windows", maintenanceHandler.CreateWindow)
+			adminAPI.DELETE("/maintenance/windows/:id", maintenanceHandler.DeleteWindow)
+		}
+
+		if cfg.Replayer != nil {
+			replayHandler := handler.NewReplayHandler(cfg.Replayer)
+			adminAPI.POST("/replay", replayHandler.StartReplay)
+			adminAPI.GET("/replay", replayHandler.GetReplay)
+			adminAPI.DELETE("/replay", replayHandler.StopReplay)
+		}
 	}
 
 	return r
@@ -0,0 +1,291 @@
+// Package app is the composition root of the server. It builds the
+// repositories, services and background workers a profile calls for, in
+// dependency order, and hands them to the router. Subsystems that need
+// storage or credentials that aren't configured are left out, and the
+// routes that need them aren't registered.
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sungminna/upbit-trading-platform/internal/api/router"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/gateway"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/addressbook"
+	"github.com/sungminna/upbit-trading-platform/internal/service/alert"
+	"github.com/sungminna/upbit-trading-platform/internal/service/apikey"
+	"github.com/sungminna/upbit-trading-platform/internal/service/balance"
+	"github.com/sungminna/upbit-trading-platform/internal/service/event"
+	"github.com/sungminna/upbit-trading-platform/internal/service/guard"
+	"github.com/sungminna/upbit-trading-platform/internal/service/ledger"
+	"github.com/sungminna/upbit-trading-platform/internal/service/maintenance"
+	"github.com/sungminna/upbit-trading-platform/internal/service/marketplace"
+	"github.com/sungminna/upbit-trading-platform/internal/service/notification"
+	"github.com/sungminna/upbit-trading-platform/internal/service/onboarding"
+	"github.com/sungminna/upbit-trading-platform/internal/service/outbox"
+	"github.com/sungminna/upbit-trading-platform/internal/service/portfolio"
+	"github.com/sungminna/upbit-trading-platform/internal/service/preset"
+	"github.com/sungminna/upbit-trading-platform/internal/service/pricefeed"
+	"github.com/sungminna/upbit-trading-platform/internal/service/push"
+	"github.com/sungminna/upbit-trading-platform/internal/service/queue"
+	"github.com/sungminna/upbit-trading-platform/internal/service/rebalance"
+	"github.com/sungminna/upbit-trading-platform/internal/service/recurring"
+	"github.com/sungminna/upbit-trading-platform/internal/service/replay"
+	"github.com/sungminna/upbit-trading-platform/internal/service/retention"
+	"github.com/sungminna/upbit-trading-platform/internal/service/risk"
+	"github.com/sungminna/upbit-trading-platform/internal/service/scheduler"
+	"github.com/sungminna/upbit-trading-platform/internal/service/signals"
+	telegramsvc "github.com/sungminna/upbit-trading-platform/internal/service/telegram"
+	"github.com/sungminna/upbit-trading-platform/internal/service/trading"
+	"github.com/sungminna/upbit-trading-platform/internal/service/valuation"
+	webhooksvc "github.com/sungminna/upbit-trading-platform/internal/service/webhook"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/exchange"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/paper"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/quotation"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/sim"
+	"github.com/sungminna/upbit-trading-platform/pkg/cache"
+	"github.com/sungminna/upbit-trading-platform/pkg/callstats"
+	"github.com/sungminna/upbit-trading-platform/pkg/httppool"
+	jwtpkg "github.com/sungminna/upbit-trading-platform/pkg/jwt"
+	"github.com/sungminna/upbit-trading-platform/pkg/latency"
+	"github.com/sungminna/upbit-trading-platform/pkg/ratelimit"
+	"github.com/sungminna/upbit-trading-platform/pkg/recovery"
+)
+
+// App is the server's object graph
+type App struct {
+	settings Settings
+	closers  []func() // Run in reverse by Close
+
+	jwt               *jwtpkg.Manager
+	quotation         gateway.QuotationAPI
+	newExchangeClient gateway.ExchangeClientFactory
+	valuations        *valuation.Service
+	cache             cache.Store
+	eventBus          *event.Bus
+	jobs              *scheduler.Scheduler
+	notifier          *notification.Service
+	push              *push.Hub
+	latency           *latency.Recorder
+	priceFeed         *pricefeed.Feed
+	poller            *pricefeed.Poller
+
+	// Trading storage; every field is nil without it
+	repos         repositories
+	pool          *pgxpool.Pool
+	paperExchange *paper.Exchange
+	engine        *trading.Engine
+	dispatcher    *outbox.Dispatcher
+	jobQueue      *queue.Queue
+
+	webhooks    *webhooksvc.Service
+	risk        *risk.Service
+	balances    *balance.Service
+	portfolio   *portfolio.Service
+	rebalance   *rebalance.Service
+	ledger      *ledger.Service
+	recurring   *recurring.Service
+	presets     *preset.Service
+	signals     *signals.Service
+	maintenance *maintenance.Detector
+	onboarding  *onboarding.Service
+	apiKeys     *apikey.Service
+	retention   *retention.Service
+	telegram    *telegramsvc.Bot
+	addressBook *addressbook.Service
+	alerts      *alert.Service
+	guards      *guard.Service
+	marketplace *marketplace.Service
+
+	// Market data storage; nil without ClickHouse
+	marketData   repository.MarketDataMaintenance
+	rawMessages  repository.RawMessageRepository
+	candles      repository.CandleRepository
+	candleStatus repository.CandleStoreMonitor
+	replayer     *replay.Replayer
+}
+
+// New builds and starts everything settings and the environment call for.
+// Background workers run until Close.
+func New(ctx context.Context, settings Settings) (*App, error) {
+	a := &App{settings: settings}
+	for _, step := range []func(context.Context) error{
+		a.initCore,
+		a.initStorage,
+		a.initWebhooks,
+		a.initTrading,
+		a.initPriceFeed,
+		a.initTelegram,
+		a.initAutomation,
+		a.initMarketData,
+		a.initReplay,
+	} {
+		if err := step(ctx); err != nil {
+			a.Close()
+			return nil, err
+		}
+	}
+
+	a.jobs.Start(ctx)
+	a.onClose(a.jobs.Stop)
+	log.Printf("Started with the %s profile (storage: %s, exchange: %s, live trading: %t)",
+		settings.Profile, settings.Storage, settings.Exchange, settings.LiveTrading)
+	return a, nil
+}
+
+// onClose registers a function Close runs, in reverse order of registration
+func (a *App) onClose(fn func()) {
+	a.closers = append(a.closers, fn)
+}
+
+// StopTrading stops taking and dispatching orders, before the HTTP server
+// drains
+func (a *App) StopTrading() {
+	if a.engine == nil {
+		return
+	}
+	a.jobQueue.Stop()
+	a.engine.Stop()
+	a.dispatcher.Stop()
+}
+
+// Close stops the background workers and closes connections
+func (a *App) Close() {
+	for i := len(a.closers) - 1; i >= 0; i-- {
+		a.closers[i]()
+	}
+	a.closers = nil
+}
+
+// initCore creates the exchange clients, cache and the services everything
+// else publishes through
+func (a *App) initCore(ctx context.Context) error {
+	var err error
+	if a.jwt, err = newJWTManager(); err != nil {
+		return err
+	}
+
+	a.quotation = quotation.NewClient()
+	a.newExchangeClient = gateway.NewUpbitExchangeClient
+	if a.settings.Exchange == ExchangeSim {
+		simExchange, err := sim.Load(os.Getenv("SIM_ORDERBOOKS"), float64(getEnvInt("SIM_KRW_BALANCE", sim.DefaultBalance)))
+		if err != nil {
+			return fmt.Errorf("failed to start the simulated exchange: %w", err)
+		}
+		log.Printf("Trading on a simulated exchange with markets %v", simExchange.Markets())
+		a.quotation = simExchange
+		a.newExchangeClient = simExchange.NewClient
+	}
+	if !a.settings.LiveTrading {
+		a.newExchangeClient = paper.NoLive
+	}
+
+	if a.valuations, err = newValuationService(a.quotation); err != nil {
+		return err
+	}
+
+	// Redis is required for multi-instance deployments
+	a.cache = cache.NewMemoryCache()
+	if addr := os.Getenv("REDIS_ADDR"); addr != "" {
+		redisCache, err := cache.NewRedisCache(ctx, addr, os.Getenv("REDIS_PASSWORD"), 0)
+		if err != nil {
+			return fmt.Errorf("failed to connect to Redis: %w", err)
+		}
+		a.onClose(func() { redisCache.Close() })
+		a.cache = redisCache
+	}
+
+	a.eventBus = event.NewBus()
+	a.jobs = scheduler.NewScheduler()
+	a.notifier = notification.NewService(notification.LogChannel{}).WithThrottle(a.cache, notification.DefaultThrottle)
+
+	// Connected clients see the same notifications and order events live
+	a.push = push.NewHub()
+	a.notifier.AddChannel(a.push)
+	a.eventBus.Subscribe(event.All, a.push.HandleEvent)
+	return nil
+}
+
+// RouterConfig returns the router configuration exposing the app's services
+func (a *App) RouterConfig() *router.Config {
+	return &router.Config{
+		JWT:                  a.jwt,
+		QuotationClient:      a.quotation,
+		Cache:                a.cache,
+		AdminToken:           os.Getenv("ADMIN_TOKEN"),
+		MarketData:           a.marketData,
+		Candles:              a.candles,
+		CandleStatus:         a.candleStatus,
+		Snapshots:            a.repos.snapshots,
+		Positions:            a.repos.positions,
+		Backtests:            a.repos.backtests,
+		Orders:               a.repos.orders,
+		Executions:           a.repos.executions,
+		PositionEvents:       a.repos.positionEvents,
+		Replayer:             a.replayer,
+		Alerts:               a.alerts,
+		Telegram:             a.telegram,
+		TelegramLinks:        a.repos.telegramLinks,
+		Notifier:             a.notifier,
+		NotificationSettings: a.repos.notificationSettings,
+		Webhooks:             a.webhooks,
+		Risk:                 a.risk,
+		Engine:               a.engine,
+		Guards:               a.guards,
+		Portfolio:            a.portfolio,
+		Valuation:            a.valuations,
+		Balances:             a.balances,
+		Rebalance:            a.rebalance,
+		Ledger:               a.ledger,
+		Recurring:            a.recurring,
+		Presets:              a.presets,
+		Signals:              a.signals,
+		AddressBook:          a.addressBook,
+		Onboarding:           a.onboarding,
+		APIKeys:              a.apiKeys,
+		Marketplace:          a.marketplace,
+		Maintenance:          a.maintenance,
+		Retention:            a.retention,
+		Jobs:                 a.jobs,
+		Queue:                a.jobQueue,
+		RateLimits: map[string]*ratelimit.Metrics{
+			"quotation": quotation.RateLimitMetrics,
+			"exchange":  exchange.RateLimitMetrics,
+		},
+		APICalls: map[string]*callstats.Recorder{
+			"quotation": quotation.CallStats,
+			"exchange":  exchange.CallStats,
+		},
+		Connections: map[string]*httppool.Transport{
+			"quotation": quotation.Transport,
+			"exchange":  exchange.Transport,
+		},
+		Latency: a.latency,
+		Panics:  recovery.Panics,
+		Push:    a.push,
+	}
+}
+
+// registerJob registers a job with the scheduler. JOB_SCHEDULE_<NAME>
+// overrides its schedule with a cron expression, e.g.
+// JOB_SCHEDULE_MARKET_DATA_RETENTION="0 3 * * *".
+func (a *App) registerJob(job scheduler.Job) error {
+	name := "JOB_SCHEDULE_" + strings.ToUpper(strings.ReplaceAll(job.Name, "-", "_"))
+	if expr := os.Getenv(name); expr != "" {
+		schedule, err := scheduler.ParseCron(expr)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", name, err)
+		}
+		job.Schedule = schedule
+	}
+
+	if err := a.jobs.Register(job); err != nil {
+		return fmt.Errorf("failed to register job %s: %w", job.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/api/router"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/exchange"
+)
+
+// isolate clears the environment variables that connect to outside services
+func isolate(t *testing.T) {
+	for _, name := range []string{"APP_PROFILE", "STORAGE", "EXCHANGE", "POSTGRES_DSN", "CLICKHOUSE_DSN", "REDIS_ADDR", "TELEGRAM_BOT_TOKEN", "WEBSOCKET_RECORD_MARKETS", "SIM_ORDERBOOKS", "LIVE_TRADING"} {
+		t.Setenv(name, "")
+	}
+}
+
+func TestSettingsFromEnv(t *testing.T) {
+	isolate(t)
+
+	settings, err := SettingsFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, Settings{Profile: ProfileProd, Storage: StoragePostgres, Exchange: ExchangeUpbit, LiveTrading: true}, settings)
+
+	t.Setenv("APP_PROFILE", "paper")
+	t.Setenv("STORAGE", "memory")
+	settings, err = SettingsFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, Settings{Profile: ProfilePaper, Storage: StorageMemory, Exchange: ExchangeUpbit}, settings, "variables override the profile")
+
+	t.Setenv("APP_PROFILE", "test")
+	t.Setenv("STORAGE", "")
+	settings, err = SettingsFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, Settings{Profile: ProfileTest, Storage: StorageMemory, Exchange: ExchangeSim}, settings, "the test profile never trades live by default")
+
+	t.Setenv("LIVE_TRADING", "true")
+	settings, err = SettingsFromEnv()
+	require.NoError(t, err)
+	assert.True(t, settings.LiveTrading, "live trading is opted into explicitly")
+
+	t.Setenv("LIVE_TRADING", "sometimes")
+	_, err = SettingsFromEnv()
+	assert.Error(t, err)
+	t.Setenv("LIVE_TRADING", "")
+
+	t.Setenv("APP_PROFILE", "staging")
+	_, err = SettingsFromEnv()
+	assert.Error(t, err)
+
+	t.Setenv("APP_PROFILE", "")
+	t.Setenv("EXCHANGE", "binance")
+	_, err = SettingsFromEnv()
+	assert.Error(t, err)
+}
+
+func TestNew_TestProfile(t *testing.T) {
+	isolate(t)
+	t.Setenv("SIM_ORDERBOOKS", "../upbit/sim/testdata/orderbooks.jsonl")
+	gin.SetMode(gin.TestMode)
+
+	settings, err := ProfileTest.Settings()
+	require.NoError(t, err)
+	application, err := New(context.Background(), settings)
+	require.NoError(t, err)
+	defer application.Close()
+	defer application.StopTrading()
+
+	assert.NotNil(t, application.engine, "in-memory storage runs the trading engine")
+	assert.NotNil(t, application.onboarding)
+	assert.NotNil(t, application.guards)
+	assert.NotNil(t, application.marketplace)
+	assert.Nil(t, application.telegram, "no bot token")
+	assert.Nil(t, application.candles, "no ClickHouse")
+
+	_, err = application.newExchangeClient("live-access-key", "secret").GetAccounts(context.Background())
+	var apiErr *exchange.APIError
+	require.True(t, errors.As(err, &apiErr), "live keys are rejected unless LIVE_TRADING opts in")
+	assert.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+
+	r := router.Setup(application.RouterConfig())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestNew_PaperProfile(t *testing.T) {
+	isolate(t)
+	gin.SetMode(gin.TestMode)
+
+	settings, err := ProfilePaper.Settings()
+	require.NoError(t, err)
+	settings.Storage = StorageMemory
+	application, err := New(context.Background(), settings)
+	require.NoError(t, err)
+	defer application.Close()
+	defer application.StopTrading()
+
+	_, err = application.newExchangeClient("live-access-key", "secret").GetAccounts(context.Background())
+	var apiErr *exchange.APIError
+	require.True(t, errors.As(err, &apiErr), "live keys are rejected without calling Upbit")
+	assert.Equal(t, http.StatusForbidden, apiErr.StatusCode)
+}
+
+func TestNew_OrderEndpoints(t *testing.T) {
+	isolate(t)
+	t.Setenv("SIM_ORDERBOOKS", "../upbit/sim/testdata/orderbooks.jsonl")
+	gin.SetMode(gin.TestMode)
+
+	settings, err := ProfileTest.Settings()
+	require.NoError(t, err)
+	application, err := New(context.Background(), settings)
+	require.NoError(t, err)
+	defer application.Close()
+	defer application.StopTrading()
+
+	r := router.Setup(application.RouterConfig())
+	token, err := application.jwt.Generate(uuid.New(), "trader@example.com")
+	require.NoError(t, err)
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	require.Equal(t, http.StatusCreated, serve(http.MethodPost, "/api/v1/onboarding/paper-account", "").Code)
+
+	activateAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	w := serve(http.MethodPost, "/api/v1/orders", `{"market": "KRW-BTC", "side": "bid", "type": "limit",
+		"quantity": 0.001, "price": 90000000, "activate_at": "`+activateAt+`"}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var placed model.Order
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &placed))
+	assert.Equal(t, model.OrderStatusScheduled, placed.Status)
+
+	w = serve(http.MethodGet, "/api/v1/orders/"+placed.ID.String(), "")
+	require.Equal(t, http.StatusOK, w.Code)
+	var got model.Order
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+	assert.Equal(t, placed.ID, got.ID)
+
+	w = serve(http.MethodDelete, "/api/v1/orders/"+placed.ID.String(), "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var cancelled model.Order
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &cancelled))
+	assert.Equal(t, model.OrderStatusCancelled, cancelled.Status)
+
+	assert.Equal(t, http.StatusConflict, serve(http.MethodDelete, "/api/v1/orders/"+placed.ID.String(), "").Code, "already cancelled")
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/api/v1/orders/"+uuid.NewString(), "").Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, serve(http.MethodPost, "/api/v1/orders",
+		`{"market": "KRW-BTC", "side": "bid", "type": "limit", "quantity": 0.001}`).Code, "limit orders need a price")
+
+	// Placing by preset name runs the preset's algorithm
+	w = serve(http.MethodPut, "/api/v1/order-presets/btc-twap", `{"market": "KRW-BTC", "side": "bid", "type": "limit",
+		"size_rule": "quantity", "size": 0.001, "algorithm": "twap", "slices": 2, "slice_interval": 3600}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	w = serve(http.MethodPost, "/api/v1/orders", `{"preset": "btc-twap", "price": 90000000}`)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	var byPreset struct {
+		Orders []model.Order `json:"orders"`
+		Error  string        `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &byPreset))
+	assert.Empty(t, byPreset.Error)
+	require.Len(t, byPreset.Orders, 2)
+	assert.Equal(t, model.OrderStatusPending, byPreset.Orders[0].Status)
+	assert.Equal(t, model.OrderStatusScheduled, byPreset.Orders[1].Status)
+	assert.InDelta(t, 0.0005, byPreset.Orders[1].Quantity, 1e-12)
+
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodPost, "/api/v1/orders", `{"preset": "missing"}`).Code)
+}
+
+func TestNew_QueuedExports(t *testing.T) {
+	isolate(t)
+	t.Setenv("SIM_ORDERBOOKS", "../upbit/sim/testdata/orderbooks.jsonl")
+	gin.SetMode(gin.TestMode)
+
+	settings, err := ProfileTest.Settings()
+	require.NoError(t, err)
+	application, err := New(context.Background(), settings)
+	require.NoError(t, err)
+	defer application.Close()
+	defer application.StopTrading()
+
+	r := router.Setup(application.RouterConfig())
+	serveAs := func(userID uuid.UUID, method, path string) *httptest.ResponseRecorder {
+		token, err := application.jwt.Generate(userID, "trader@example.com")
+		require.NoError(t, err)
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	userID := uuid.New()
+
+	w := serveAs(userID, http.MethodPost, "/api/v1/export/orders.csv")
+	require.Equal(t, http.StatusAccepted, w.Code, w.Body.String())
+	var job model.QueuedJob
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+
+	require.Eventually(t, func() bool {
+		w := serveAs(userID, http.MethodGet, "/api/v1/jobs/"+job.ID.String())
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &job))
+		return job.Status == model.QueuedJobSucceeded
+	}, 5*time.Second, 100*time.Millisecond)
+
+	w = serveAs(userID, http.MethodGet, "/api/v1/jobs/"+job.ID.String()+"/result")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.True(t, strings.HasPrefix(w.Body.String(), "id,"))
+
+	assert.Equal(t, http.StatusNotFound, serveAs(uuid.New(), http.MethodGet, "/api/v1/jobs/"+job.ID.String()+"/result").Code,
+		"other users can't see the job")
+}
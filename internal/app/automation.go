@@ -0,0 +1,111 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/service/addressbook"
+	"github.com/sungminna/upbit-trading-platform/internal/service/alert"
+	"github.com/sungminna/upbit-trading-platform/internal/service/guard"
+	"github.com/sungminna/upbit-trading-platform/internal/service/marketplace"
+	"github.com/sungminna/upbit-trading-platform/internal/service/pricefeed"
+	"github.com/sungminna/upbit-trading-platform/internal/service/scheduler"
+	telegramsvc "github.com/sungminna/upbit-trading-platform/internal/service/telegram"
+	"github.com/sungminna/upbit-trading-platform/pkg/latency"
+	"github.com/sungminna/upbit-trading-platform/pkg/telegram"
+)
+
+// initPriceFeed polls the live prices of the markets consumers track into a
+// shared feed. How long they take to arrive and be acted on is measured from
+// Upbit's trade timestamps.
+func (a *App) initPriceFeed(ctx context.Context) error {
+	a.latency = latency.NewRecorder()
+	a.priceFeed = pricefeed.NewFeed().WithLatency(a.latency)
+	a.poller = pricefeed.NewPoller(a.quotation, a.priceFeed, pricefeed.DefaultPollInterval)
+	a.poller.Start(ctx)
+	a.onClose(a.poller.Stop)
+	return nil
+}
+
+// initTelegram starts the Telegram bot (requires trading storage). Only one
+// instance may poll a bot token, so set TELEGRAM_BOT_TOKEN on a single
+// instance.
+func (a *App) initTelegram(ctx context.Context) error {
+	token := os.Getenv("TELEGRAM_BOT_TOKEN")
+	if token == "" || a.engine == nil {
+		return nil
+	}
+
+	a.telegram = telegramsvc.NewBot(
+		telegram.NewClient(token),
+		a.repos.telegramLinks,
+		a.cache,
+		a.repos.positions,
+		a.repos.orders,
+		a.engine,
+		a.quotation,
+	)
+	if a.signals != nil {
+		a.telegram.WithChannelPosts(a.signals)
+	}
+	a.telegram.Start(ctx)
+	a.onClose(a.telegram.Stop)
+	a.notifier.AddChannel(a.telegram)
+
+	// Withdrawal addresses are confirmed with codes sent to the user's linked
+	// Telegram chat, so the address book needs the bot
+	if a.repos.withdrawAddresses != nil {
+		a.addressBook = addressbook.NewService(a.repos.withdrawAddresses, a.repos.telegramLinks, a.notifier).WithNotifier(a.notifier)
+	}
+	return nil
+}
+
+// initAutomation starts what acts on the price feed: price alerts, indicator
+// signals and drawdown guards, and the strategy templates cloned onto them
+func (a *App) initAutomation(ctx context.Context) error {
+	if a.repos.alerts != nil {
+		a.alerts = alert.NewService(a.repos.alerts, a.priceFeed, a.poller, a.notifier)
+		if err := a.alerts.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start price alerts: %w", err)
+		}
+		a.onClose(a.alerts.Stop)
+	}
+
+	// Indicator signal sources are evaluated on the price feed
+	if a.signals != nil {
+		a.signals.WithPriceFeed(a.priceFeed, a.poller)
+		if err := a.signals.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start signal indicators: %w", err)
+		}
+		a.onClose(a.signals.Stop)
+	}
+
+	// Drawdown guards exit positions through the engine, so halts still apply
+	if a.repos.drawdownGuards != nil && a.engine != nil {
+		a.guards = guard.NewService(a.repos.drawdownGuards, a.repos.positions, a.engine, a.priceFeed, a.poller, a.notifier, a.cache).
+			WithCandleCloses(pricefeed.NewAggregator(a.priceFeed)).WithLatency(a.latency).WithHistory(a.repos.drawdownGuardUpdates)
+		if err := a.guards.Start(ctx); err != nil {
+			return fmt.Errorf("failed to start drawdown guards: %w", err)
+		}
+		a.onClose(a.guards.Stop)
+		// Guards of positions that closed by other means are cancelled with the
+		// fill, and stop being evaluated when that is dispatched
+		a.eventBus.Subscribe(model.EventDrawdownGuardCancelled, a.guards.HandleEvent)
+	}
+
+	// Shared strategy templates are cloned onto positions as drawdown guards
+	if a.guards != nil && a.repos.strategyTemplates != nil {
+		a.marketplace = marketplace.NewService(a.repos.strategyTemplates, a.repos.backtests, a.guards)
+	}
+
+	if a.repos.notificationSettings != nil {
+		summaryJob := scheduler.NewDailySummaryJob(a.repos.notificationSettings, a.repos.positions, a.repos.orders,
+			a.repos.executions, a.quotation, a.notifier, a.cache)
+		if err := a.registerJob(summaryJob.Job()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
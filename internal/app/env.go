@@ -0,0 +1,162 @@
+package app
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/gateway"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	chrepo "github.com/sungminna/upbit-trading-platform/internal/infrastructure/clickhouse"
+	"github.com/sungminna/upbit-trading-platform/internal/service/retention"
+	"github.com/sungminna/upbit-trading-platform/internal/service/valuation"
+	jwtpkg "github.com/sungminna/upbit-trading-platform/pkg/jwt"
+)
+
+// getEnvInt returns an integer environment variable or the default if unset or invalid
+func getEnvInt(name string, def int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return def
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Invalid %s=%q, using default %d", name, value, def)
+		return def
+	}
+	return n
+}
+
+// splitEnvList splits a comma-separated environment variable, dropping
+// empty entries
+func splitEnvList(name string) []string {
+	var entries []string
+	for _, entry := range strings.Split(os.Getenv(name), ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// newValuationService creates the display currency service. VALUATION_MARKETS
+// (currency=market,...) adds currencies or changes their reference markets,
+// e.g. ETH=KRW-ETH.
+func newValuationService(prices gateway.QuotationAPI) (*valuation.Service, error) {
+	valuations := valuation.NewService(prices)
+	for _, entry := range splitEnvList("VALUATION_MARKETS") {
+		currency, market, _ := strings.Cut(entry, "=")
+		if _, err := valuations.WithMarket(currency, market); err != nil {
+			return nil, fmt.Errorf("invalid VALUATION_MARKETS entry %q: %w", entry, err)
+		}
+	}
+	return valuations, nil
+}
+
+// newJWTManager creates the JWT manager. JWT_SECRET is the HS256 key tokens
+// are signed with by default; JWT_KEYS (id=secret,...) and JWT_RSA_KEYS
+// (id=/path/to/key.pem,...) add keys, and JWT_SIGNING_KEY picks the one new
+// tokens are signed with. RSA public key files only verify tokens.
+func newJWTManager() (*jwtpkg.Manager, error) {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		secret = "your-secret-key-change-this-in-production"
+	}
+	expiry := 24 * time.Hour
+	if value := os.Getenv("JWT_EXPIRY"); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid JWT_EXPIRY %q", value)
+		}
+		expiry = d
+	}
+
+	m := jwtpkg.NewManager(secret, expiry).WithIssuer(os.Getenv("JWT_ISSUER"))
+	if audience := os.Getenv("JWT_AUDIENCE"); audience != "" {
+		m.WithAudience(strings.Split(audience, ",")...)
+	}
+
+	for _, entry := range splitEnvList("JWT_KEYS") {
+		id, key, ok := strings.Cut(entry, "=")
+		if !ok || id == "" || key == "" {
+			return nil, fmt.Errorf("invalid JWT_KEYS entry: use id=secret")
+		}
+		m.AddHMACKey(id, key)
+	}
+	for _, entry := range splitEnvList("JWT_RSA_KEYS") {
+		id, path, ok := strings.Cut(entry, "=")
+		if !ok || id == "" || path == "" {
+			return nil, fmt.Errorf("invalid JWT_RSA_KEYS entry %q: use id=/path/to/key.pem", entry)
+		}
+		pem, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWT key %s: %w", id, err)
+		}
+		if private, err := jwt.ParseRSAPrivateKeyFromPEM(pem); err == nil {
+			m.AddRSAKey(id, private)
+		} else if public, err := jwt.ParseRSAPublicKeyFromPEM(pem); err == nil {
+			m.AddRSAPublicKey(id, public)
+		} else {
+			return nil, fmt.Errorf("JWT key %s is not an RSA key in PEM format", id)
+		}
+	}
+
+	if id := os.Getenv("JWT_SIGNING_KEY"); id != "" {
+		if err := m.SignWith(id); err != nil {
+			return nil, fmt.Errorf("invalid JWT_SIGNING_KEY: %w", err)
+		}
+	}
+	return m, nil
+}
+
+// retentionPolicyFromEnv overrides the default market data retention with
+// CLICKHOUSE_CANDLE_RETENTION ("1m=30d,1h=730d") and the per-table variables
+func retentionPolicyFromEnv() (chrepo.RetentionPolicy, error) {
+	policy := chrepo.DefaultRetentionPolicy()
+
+	candles, err := chrepo.ParseCandleRetention(os.Getenv("CLICKHOUSE_CANDLE_RETENTION"), policy.Candles)
+	if err != nil {
+		return policy, err
+	}
+	policy.Candles = candles
+
+	for name, target := range map[string]*time.Duration{
+		"CLICKHOUSE_TICK_RETENTION":      &policy.Ticks,
+		"CLICKHOUSE_ORDERBOOK_RETENTION": &policy.Orderbooks,
+		"CLICKHOUSE_TICKER_RETENTION":    &policy.Tickers,
+		"CLICKHOUSE_MESSAGE_RETENTION":   &policy.Messages,
+	} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+
+		retention, err := chrepo.ParseRetention(value)
+		if err != nil {
+			return policy, fmt.Errorf("%s: %w", name, err)
+		}
+		*target = retention
+	}
+
+	return policy, nil
+}
+
+// tradingRetentionFromEnv reads the trading record retention. Records are
+// kept forever unless ORDER_RETENTION_MONTHS or STRATEGY_RETENTION_MONTHS is
+// set, and archived unless TRADING_RETENTION_MODE=purge.
+func tradingRetentionFromEnv() retention.Policy {
+	policy := retention.Policy{
+		Mode:       model.RetentionArchive,
+		Orders:     getEnvInt("ORDER_RETENTION_MONTHS", 0),
+		Strategies: getEnvInt("STRATEGY_RETENTION_MONTHS", 0),
+	}
+	if mode := os.Getenv("TRADING_RETENTION_MODE"); mode != "" {
+		policy.Mode = model.RetentionMode(mode)
+	}
+	return policy
+}
@@ -0,0 +1,100 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	chrepo "github.com/sungminna/upbit-trading-platform/internal/infrastructure/clickhouse"
+	"github.com/sungminna/upbit-trading-platform/internal/service/pricefeed"
+	"github.com/sungminna/upbit-trading-platform/internal/service/replay"
+	"github.com/sungminna/upbit-trading-platform/internal/service/scheduler"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/websocket"
+	"github.com/sungminna/upbit-trading-platform/pkg/database/clickhouse"
+)
+
+// initMarketData opens the market data storage (requires CLICKHOUSE_DSN)
+// and applies its retention
+func (a *App) initMarketData(ctx context.Context) error {
+	dsn := os.Getenv("CLICKHOUSE_DSN")
+	if dsn == "" {
+		return nil
+	}
+	chConfig := clickhouse.DefaultConfig(dsn)
+	chConfig.ConnectAttempts = getEnvInt("DB_CONNECT_ATTEMPTS", chConfig.ConnectAttempts)
+
+	// An unreachable ClickHouse doesn't stop the server: candle writes are
+	// buffered and candle reads served from Upbit until it answers
+	conn, err := clickhouse.NewConn(ctx, chConfig)
+	if err != nil {
+		log.Printf("ClickHouse is unavailable, starting without it: %v", err)
+		if conn, err = clickhouse.Open(chConfig); err != nil {
+			return fmt.Errorf("invalid ClickHouse configuration: %w", err)
+		}
+	}
+
+	policy, err := retentionPolicyFromEnv()
+	if err != nil {
+		return fmt.Errorf("invalid retention configuration: %w", err)
+	}
+
+	maintenance := chrepo.NewMaintenance(conn)
+	if err := maintenance.ApplyRetention(ctx, policy); err != nil {
+		log.Printf("Failed to apply market data retention: %v", err)
+	}
+	a.marketData = maintenance
+	if err := a.registerJob(scheduler.NewRetentionJob(maintenance, 24*time.Hour).Job()); err != nil {
+		return err
+	}
+
+	candles := chrepo.NewFallbackCandleRepository(conn, a.quotation, getEnvInt("CLICKHOUSE_CANDLE_BUFFER", chrepo.DefaultCandleBuffer))
+	candles.Start(ctx)
+	a.onClose(candles.Stop)
+	a.candles, a.candleStatus = candles, candles
+
+	a.rawMessages = chrepo.NewRawMessageRepository(conn)
+	return a.recordMessages(ctx)
+}
+
+// recordMessages records the raw websocket messages of
+// WEBSOCKET_RECORD_MARKETS for bug reproduction and replay
+func (a *App) recordMessages(ctx context.Context) error {
+	markets := splitEnvList("WEBSOCKET_RECORD_MARKETS")
+	if len(markets) == 0 {
+		return nil
+	}
+
+	recorder := replay.NewRecorder(a.rawMessages, getEnvInt("WEBSOCKET_RECORD_BUFFER", replay.DefaultRecorderBuffer))
+	recorder.Start(ctx)
+	a.onClose(recorder.Stop)
+
+	wsClient := websocket.NewClient().WithRecorder(recorder)
+	if err := wsClient.Connect(); err != nil {
+		return fmt.Errorf("failed to connect to the Upbit websocket: %w", err)
+	}
+	a.onClose(func() { wsClient.Close() })
+	for _, msgType := range []websocket.MessageType{websocket.MessageTypeTicker, websocket.MessageTypeTrade} {
+		if err := wsClient.Subscribe(msgType, markets); err != nil {
+			return fmt.Errorf("failed to subscribe to %s messages: %w", msgType, err)
+		}
+	}
+	log.Printf("Recording websocket messages of %v", markets)
+	return nil
+}
+
+// initReplay creates the historical replayer. It publishes into its own feed
+// so it never reaches live trading; outside the prod profile, sessions may
+// ask to drive the live feed, which the smoke test uses to trigger exits.
+func (a *App) initReplay(ctx context.Context) error {
+	a.replayer = replay.NewReplayer(a.quotation, pricefeed.NewFeed())
+	if a.rawMessages != nil {
+		a.replayer.WithMessages(a.rawMessages)
+	}
+	if a.settings.Profile != ProfileProd {
+		a.replayer.WithLiveFeed(a.priceFeed)
+	}
+	a.onClose(a.replayer.Stop)
+	return nil
+}
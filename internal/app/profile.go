@@ -0,0 +1,95 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Profile picks the defaults of a kind of deployment. The environment
+// variables of individual settings still override them.
+type Profile string
+
+const (
+	// ProfileProd trades on Upbit with PostgreSQL storage
+	ProfileProd Profile = "prod"
+	// ProfilePaper stores in PostgreSQL but only paper API keys trade; live
+	// keys are rejected, so the deployment never touches real funds
+	ProfilePaper Profile = "paper"
+	// ProfileTest runs on in-memory storage against the simulated exchange,
+	// for local development and tests. Live trading is off unless
+	// LIVE_TRADING=true opts in.
+	ProfileTest Profile = "test"
+)
+
+// Storage backends
+const (
+	StorageMemory   = "memory"
+	StoragePostgres = "postgres"
+)
+
+// Exchanges
+const (
+	ExchangeUpbit = "upbit"
+	ExchangeSim   = "sim"
+)
+
+// Settings are the choices a profile makes
+type Settings struct {
+	Profile Profile
+	// Storage is StorageMemory or StoragePostgres. PostgreSQL without
+	// POSTGRES_DSN serves market data only.
+	Storage string
+	// Exchange is ExchangeUpbit or ExchangeSim, which replays the orderbooks
+	// recorded in SIM_ORDERBOOKS fully offline
+	Exchange string
+	// LiveTrading lets non-paper API keys trade
+	LiveTrading bool
+}
+
+// Settings returns a profile's defaults
+func (p Profile) Settings() (Settings, error) {
+	switch p {
+	case ProfileProd:
+		return Settings{Profile: p, Storage: StoragePostgres, Exchange: ExchangeUpbit, LiveTrading: true}, nil
+	case ProfilePaper:
+		return Settings{Profile: p, Storage: StoragePostgres, Exchange: ExchangeUpbit}, nil
+	case ProfileTest:
+		return Settings{Profile: p, Storage: StorageMemory, Exchange: ExchangeSim}, nil
+	}
+	return Settings{}, fmt.Errorf("unknown profile %q: use prod, paper or test", p)
+}
+
+// SettingsFromEnv returns the defaults of the APP_PROFILE profile (prod when
+// unset) with STORAGE, EXCHANGE and LIVE_TRADING applied on top
+func SettingsFromEnv() (Settings, error) {
+	profile := Profile(os.Getenv("APP_PROFILE"))
+	if profile == "" {
+		profile = ProfileProd
+	}
+	settings, err := profile.Settings()
+	if err != nil {
+		return settings, err
+	}
+
+	if storage := os.Getenv("STORAGE"); storage != "" {
+		if storage != StorageMemory && storage != StoragePostgres {
+			return settings, fmt.Errorf("invalid STORAGE %q: use memory or postgres", storage)
+		}
+		settings.Storage = storage
+	}
+	if exchange := os.Getenv("EXCHANGE"); exchange != "" {
+		if exchange != ExchangeUpbit && exchange != ExchangeSim {
+			return settings, fmt.Errorf("invalid EXCHANGE %q: use upbit or sim", exchange)
+		}
+		settings.Exchange = exchange
+	}
+	if live := os.Getenv("LIVE_TRADING"); live != "" {
+		enabled, err := strconv.ParseBool(live)
+		if err != nil {
+			return settings, fmt.Errorf("invalid LIVE_TRADING %q: use true or false", live)
+		}
+		settings.LiveTrading = enabled
+	}
+	return settings, nil
+}
@@ -0,0 +1,204 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+	pgrepo "github.com/sungminna/upbit-trading-platform/internal/infrastructure/postgres"
+	"github.com/sungminna/upbit-trading-platform/internal/service/outbox"
+	"github.com/sungminna/upbit-trading-platform/internal/service/queue"
+	"github.com/sungminna/upbit-trading-platform/internal/service/trading"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/paper"
+	"github.com/sungminna/upbit-trading-platform/pkg/database/postgres"
+)
+
+// repositories is the trading storage
+type repositories struct {
+	uow                  repository.UnitOfWork
+	orders               repository.OrderRepository
+	executions           repository.OrderExecutionRepository
+	snapshots            repository.SnapshotRepository
+	positions            repository.PositionRepository
+	positionEvents       repository.PositionEventRepository
+	backtests            repository.BacktestRepository
+	alerts               repository.PriceAlertRepository
+	telegramLinks        repository.TelegramLinkRepository
+	notificationSettings repository.NotificationSettingsRepository
+	webhooks             repository.WebhookRepository
+	webhookDeliveries    repository.WebhookDeliveryRepository
+	riskLimits           repository.RiskLimitsRepository
+	riskStates           repository.RiskStateRepository
+	tradingHalts         repository.TradingHaltRepository
+	apiKeys              repository.UserAPIKeyRepository
+	drawdownGuards       repository.DrawdownGuardRepository
+	drawdownGuardUpdates repository.DrawdownGuardUpdateRepository
+	velocityLimits       repository.VelocityLimitRepository
+	targetPortfolios     repository.TargetPortfolioRepository
+	cashLedger           repository.CashLedgerRepository
+	recurringOrders      repository.RecurringOrderRepository
+	orderPresets         repository.OrderPresetRepository
+	maintenanceWindows   repository.MaintenanceWindowRepository
+	signalSources        repository.SignalSourceRepository
+	signalSubscriptions  repository.SignalSubscriptionRepository
+	signalLogs           repository.SignalLogRepository
+	withdrawAddresses    repository.WithdrawAddressRepository
+	strategyTemplates    repository.StrategyTemplateRepository
+	paperAccounts        repository.PaperAccountRepository
+	retention            repository.TradingRetentionRepository
+	jobs                 repository.JobQueueRepository
+}
+
+// memoryRepositories returns repositories backed by an in-memory store
+func memoryRepositories(store *memory.Store) repositories {
+	return repositories{
+		uow:                  store,
+		orders:               store.Orders(),
+		executions:           store.Executions(),
+		snapshots:            store.Snapshots(),
+		positions:            store.Positions(),
+		positionEvents:       store.PositionEvents(),
+		backtests:            store.Backtests(),
+		alerts:               store.Alerts(),
+		telegramLinks:        store.TelegramLinks(),
+		notificationSettings: store.NotificationSettings(),
+		webhooks:             store.Webhooks(),
+		webhookDeliveries:    store.WebhookDeliveries(),
+		riskLimits:           store.RiskLimits(),
+		riskStates:           store.RiskStates(),
+		tradingHalts:         store.TradingHalts(),
+		apiKeys:              store.APIKeys(),
+		drawdownGuards:       store.DrawdownGuards(),
+		drawdownGuardUpdates: store.DrawdownGuardUpdates(),
+		velocityLimits:       store.VelocityLimits(),
+		targetPortfolios:     store.TargetPortfolios(),
+		cashLedger:           store.CashLedger(),
+		recurringOrders:      store.RecurringOrders(),
+		orderPresets:         store.OrderPresets(),
+		maintenanceWindows:   store.MaintenanceWindows(),
+		signalSources:        store.SignalSources(),
+		signalSubscriptions:  store.SignalSubscriptions(),
+		signalLogs:           store.SignalLogs(),
+		withdrawAddresses:    store.WithdrawAddresses(),
+		strategyTemplates:    store.StrategyTemplates(),
+		paperAccounts:        store.PaperAccounts(),
+		retention:            store.Retention(),
+		jobs:                 store.Jobs(),
+	}
+}
+
+// postgresRepositories returns repositories backed by PostgreSQL. Orders and
+// executions are passed in, as they may read from a replica.
+func postgresRepositories(db pgrepo.DBTX, orders *pgrepo.OrderRepository, executions *pgrepo.OrderExecutionRepository, uow *pgrepo.UnitOfWork) repositories {
+	return repositories{
+		uow:                  uow,
+		orders:               orders,
+		executions:           executions,
+		snapshots:            pgrepo.NewSnapshotRepository(db),
+		positions:            pgrepo.NewPositionRepository(db),
+		positionEvents:       pgrepo.NewPositionEventRepository(db),
+		backtests:            pgrepo.NewBacktestRepository(db),
+		alerts:               pgrepo.NewPriceAlertRepository(db),
+		telegramLinks:        pgrepo.NewTelegramLinkRepository(db),
+		notificationSettings: pgrepo.NewNotificationSettingsRepository(db),
+		webhooks:             pgrepo.NewWebhookRepository(db),
+		webhookDeliveries:    pgrepo.NewWebhookDeliveryRepository(db),
+		riskLimits:           pgrepo.NewRiskLimitsRepository(db),
+		riskStates:           pgrepo.NewRiskStateRepository(db),
+		tradingHalts:         pgrepo.NewTradingHaltRepository(db),
+		apiKeys:              pgrepo.NewUserAPIKeyRepository(db),
+		drawdownGuards:       pgrepo.NewDrawdownGuardRepository(db),
+		drawdownGuardUpdates: pgrepo.NewDrawdownGuardUpdateRepository(db),
+		velocityLimits:       pgrepo.NewVelocityLimitRepository(db),
+		targetPortfolios:     pgrepo.NewTargetPortfolioRepository(db),
+		cashLedger:           pgrepo.NewCashLedgerRepository(db),
+		recurringOrders:      pgrepo.NewRecurringOrderRepository(db),
+		orderPresets:         pgrepo.NewOrderPresetRepository(db),
+		maintenanceWindows:   pgrepo.NewMaintenanceWindowRepository(db),
+		signalSources:        pgrepo.NewSignalSourceRepository(db),
+		signalSubscriptions:  pgrepo.NewSignalSubscriptionRepository(db),
+		signalLogs:           pgrepo.NewSignalLogRepository(db),
+		withdrawAddresses:    pgrepo.NewWithdrawAddressRepository(db),
+		strategyTemplates:    pgrepo.NewStrategyTemplateRepository(db),
+		paperAccounts:        pgrepo.NewPaperAccountRepository(db),
+		retention:            pgrepo.NewTradingRetentionRepository(db),
+		jobs:                 pgrepo.NewJobQueueRepository(db),
+	}
+}
+
+// initStorage opens the trading storage and creates the trading engine and
+// outbox dispatcher on it. PostgreSQL storage without POSTGRES_DSN serves
+// market data only.
+func (a *App) initStorage(ctx context.Context) error {
+	switch a.settings.Storage {
+	case StorageMemory:
+		log.Println("Using in-memory storage (test mode)")
+		a.repos = memoryRepositories(memory.NewStore())
+	case StoragePostgres:
+		dsn := os.Getenv("POSTGRES_DSN")
+		if dsn == "" {
+			return nil
+		}
+		if err := a.openPostgres(ctx, dsn); err != nil {
+			return err
+		}
+	}
+
+	// Paper API keys trade paper accounts; any other key trades on Upbit
+	a.paperExchange = paper.NewExchange(a.repos.paperAccounts, a.quotation, a.cache)
+	a.newExchangeClient = a.paperExchange.Factory(a.newExchangeClient)
+
+	a.engine = trading.NewEngine(a.repos.orders, a.repos.apiKeys, a.repos.uow, a.cache, a.newExchangeClient)
+	a.dispatcher = outbox.NewDispatcher(a.repos.uow, a.eventBus)
+	a.jobQueue = queue.NewQueue(a.repos.jobs)
+
+	// Drop stale state when another instance changes shared records
+	if a.pool != nil {
+		listener := pgrepo.NewListener(a.pool)
+		listener.Subscribe(model.InvalidationAPIKey, func(ctx context.Context, inv model.Invalidation) {
+			a.engine.InvalidateClient(inv.UserID)
+		})
+		listener.Start(ctx)
+		a.onClose(listener.Stop)
+	}
+	return nil
+}
+
+// openPostgres connects to PostgreSQL, and to the optional read replica at
+// POSTGRES_READ_DSN for read-heavy, lag-tolerant queries
+func (a *App) openPostgres(ctx context.Context, dsn string) error {
+	pgConfig := postgres.DefaultConfig(dsn)
+	pgConfig.MaxConns = int32(getEnvInt("POSTGRES_MAX_CONNS", int(pgConfig.MaxConns)))
+	pgConfig.MinConns = int32(getEnvInt("POSTGRES_MIN_CONNS", int(pgConfig.MinConns)))
+	pgConfig.ConnectAttempts = getEnvInt("DB_CONNECT_ATTEMPTS", pgConfig.ConnectAttempts)
+
+	pool, err := postgres.NewPool(ctx, pgConfig)
+	if err != nil {
+		return fmt.Errorf("failed to connect to PostgreSQL: %w", err)
+	}
+	a.onClose(pool.Close)
+	a.pool = pool
+
+	orders := pgrepo.NewOrderRepository(pool)
+	executions := pgrepo.NewOrderExecutionRepository(pool)
+	if readDSN := os.Getenv("POSTGRES_READ_DSN"); readDSN != "" {
+		replicaConfig := pgConfig
+		replicaConfig.DSN = readDSN
+
+		replica, err := postgres.NewPool(ctx, replicaConfig)
+		if err != nil {
+			return fmt.Errorf("failed to connect to PostgreSQL read replica: %w", err)
+		}
+		a.onClose(replica.Close)
+
+		orders = orders.WithReplica(replica)
+		executions = executions.WithReplica(replica)
+	}
+
+	a.repos = postgresRepositories(pool, orders, executions, pgrepo.NewUnitOfWork(pool))
+	return nil
+}
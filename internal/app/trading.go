@@ -0,0 +1,182 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/service/apikey"
+	"github.com/sungminna/upbit-trading-platform/internal/service/balance"
+	"github.com/sungminna/upbit-trading-platform/internal/service/event"
+	"github.com/sungminna/upbit-trading-platform/internal/service/ledger"
+	"github.com/sungminna/upbit-trading-platform/internal/service/maintenance"
+	"github.com/sungminna/upbit-trading-platform/internal/service/onboarding"
+	"github.com/sungminna/upbit-trading-platform/internal/service/portfolio"
+	"github.com/sungminna/upbit-trading-platform/internal/service/preset"
+	"github.com/sungminna/upbit-trading-platform/internal/service/rebalance"
+	"github.com/sungminna/upbit-trading-platform/internal/service/reconcile"
+	"github.com/sungminna/upbit-trading-platform/internal/service/recurring"
+	"github.com/sungminna/upbit-trading-platform/internal/service/retention"
+	"github.com/sungminna/upbit-trading-platform/internal/service/risk"
+	"github.com/sungminna/upbit-trading-platform/internal/service/scheduler"
+	"github.com/sungminna/upbit-trading-platform/internal/service/signals"
+	"github.com/sungminna/upbit-trading-platform/internal/service/trading"
+	"github.com/sungminna/upbit-trading-platform/internal/service/watchdog"
+	webhooksvc "github.com/sungminna/upbit-trading-platform/internal/service/webhook"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/paper"
+)
+
+// initWebhooks delivers notifications and order events to outbound webhooks
+func (a *App) initWebhooks(ctx context.Context) error {
+	if a.repos.webhooks == nil {
+		return nil
+	}
+	a.webhooks = webhooksvc.NewService(a.repos.webhooks, a.repos.webhookDeliveries, a.repos.uow, a.jobQueue,
+		os.Getenv("WEBHOOK_ALLOW_PRIVATE_TARGETS") == "true")
+	a.notifier.AddChannel(a.webhooks)
+	a.eventBus.Subscribe(event.All, a.webhooks.HandleEvent)
+	return nil
+}
+
+// initTrading configures the engine and starts the services that trade
+// through it
+func (a *App) initTrading(ctx context.Context) error {
+	if a.engine == nil {
+		return nil
+	}
+	repos := a.repos
+
+	a.balances = balance.NewService(repos.apiKeys, a.newExchangeClient, a.cache).WithOrders(repos.orders)
+	if err := a.registerJob(a.balances.Job()); err != nil {
+		return err
+	}
+	a.risk = risk.NewService(repos.riskLimits, repos.riskStates, repos.positions, repos.orders).WithMarketData(a.quotation, a.balances)
+	a.portfolio = portfolio.NewService(a.balances, repos.positions, repos.snapshots, a.quotation).
+		WithExecutions(repos.orders, repos.executions).WithValuation(a.valuations)
+	a.engine.WithNotifier(a.notifier).WithRiskChecker(a.risk).WithHalts(repos.tradingHalts).WithBalances(a.balances).WithPositions(repos.positions)
+	a.engine.WithVelocityLimits(model.VelocityLimits{
+		PerMinute: getEnvInt("ORDER_LIMIT_PER_MINUTE", 0),
+		PerHour:   getEnvInt("ORDER_LIMIT_PER_HOUR", 0),
+	}, repos.velocityLimits)
+	a.engine.WithExitProtection(a.quotation, trading.ExitProtection{
+		MaxSpreadBps: float64(getEnvInt("EXIT_MAX_SPREAD_BPS", 0)),
+		CrossBps:     float64(getEnvInt("EXIT_CROSS_BPS", 50)),
+	})
+	if method := model.AccountingMethod(os.Getenv("ACCOUNTING_METHOD")); method != "" {
+		if !method.Valid() {
+			return fmt.Errorf("invalid ACCOUNTING_METHOD %q: use average, fifo or lifo", method)
+		}
+		a.engine.WithAccountingMethod(method)
+	}
+	a.engine.WithQueue(a.jobQueue)
+	if repos.strategyTemplates != nil {
+		a.engine.WithStrategies(repos.strategyTemplates)
+	}
+
+	// Orders wait out Upbit maintenance, announced or detected from its
+	// responses, and resume once it is over
+	a.maintenance = maintenance.NewDetector(repos.maintenanceWindows, a.quotation, repos.apiKeys, repos.orders).WithNotifier(a.notifier)
+	if err := a.maintenance.Refresh(ctx, time.Now()); err != nil {
+		log.Printf("Error loading maintenance windows: %v", err)
+	}
+	a.engine.WithMaintenance(a.maintenance)
+
+	a.engine.Start(ctx)
+	a.dispatcher.Start(ctx)
+	a.jobQueue.Start(ctx)
+
+	lossMonitor := risk.NewLossMonitor(a.risk, a.quotation, a.engine, a.notifier, a.cache)
+
+	watchdogConfig := watchdog.DefaultConfig()
+	watchdogConfig.FailedExits = getEnvInt("WATCHDOG_FAILED_EXITS", watchdogConfig.FailedExits)
+	watchdogConfig.MaxTriggersPerHour = getEnvInt("WATCHDOG_MAX_TRIGGERS_PER_HOUR", watchdogConfig.MaxTriggersPerHour)
+	tradingWatchdog := watchdog.NewWatchdog(watchdogConfig, repos.apiKeys, repos.orders, repos.positions, a.engine, a.notifier, a.cache).
+		WithGuards(repos.drawdownGuards)
+
+	// Positions sold directly on Upbit are flagged or, with
+	// RECONCILE_MODE=reduce, written off
+	reconcileMode := reconcile.ModeFlag
+	if mode := reconcile.Mode(os.Getenv("RECONCILE_MODE")); mode != "" {
+		reconcileMode = mode
+	}
+	if !reconcileMode.Valid() {
+		return fmt.Errorf("invalid RECONCILE_MODE %q: use off, flag or reduce", reconcileMode)
+	}
+	reconciler := reconcile.NewReconciler(reconcileMode, repos.apiKeys, repos.orders, repos.positions, a.balances, repos.uow, a.notifier, a.cache).
+		WithTickers(a.quotation)
+
+	a.rebalance = rebalance.NewService(repos.targetPortfolios, a.balances, a.quotation, a.engine, a.cache).
+		WithNotifier(a.notifier).WithMaintenance(a.maintenance)
+
+	// Cash movements are ledgered so discrepancies with the exchange
+	// balance surface
+	a.ledger = ledger.NewService(repos.cashLedger, repos.apiKeys, repos.orders, repos.executions, a.newExchangeClient)
+
+	a.recurring = recurring.NewService(repos.recurringOrders, a.engine, a.quotation).
+		WithNotifier(a.notifier).WithMaintenance(a.maintenance)
+
+	// Orders users place often are saved as presets, placed by name
+	a.presets = preset.NewService(repos.orderPresets, a.engine, a.quotation)
+
+	// Signal sources, e.g. TradingView alerts, open and close positions
+	// for their subscribers
+	a.signals = signals.NewService(repos.signalSources, repos.signalSubscriptions, repos.signalLogs,
+		repos.positions, repos.orders, a.engine, a.quotation)
+
+	// New users try orders and strategies on a paper account first
+	var err error
+	a.onboarding, err = onboarding.NewService(repos.apiKeys, repos.paperAccounts, repos.positions, repos.orders, a.paperExchange).
+		WithStartingBalance(float64(getEnvInt("PAPER_KRW_BALANCE", paper.DefaultBalance)))
+	if err != nil {
+		return fmt.Errorf("invalid PAPER_KRW_BALANCE: %w", err)
+	}
+	a.onboarding.WithInvalidation(a.engine, a.balances)
+	// Users are reminded before their Upbit key expires and halted once it has
+	a.apiKeys = apikey.NewService(repos.apiKeys, a.newExchangeClient).WithExpiryMonitor(a.engine, a.notifier, a.cache)
+
+	// Cancelled and failed orders and triggered guards are archived or
+	// purged once past their retention
+	a.retention, err = retention.NewService(repos.retention, tradingRetentionFromEnv())
+	if err != nil {
+		return fmt.Errorf("invalid trading retention: %w", err)
+	}
+
+	jobs := []scheduler.Job{
+		a.maintenance.Job(),
+		lossMonitor.Job(),
+		tradingWatchdog.Job(),
+		reconciler.Job(),
+		a.rebalance.Job(),
+		a.ledger.Job(),
+		a.recurring.Job(),
+		a.retention.Job(),
+		a.apiKeys.Job(),
+	}
+	for _, snapshotJob := range a.snapshotJobs() {
+		jobs = append(jobs, snapshotJob.Job())
+	}
+	for _, job := range jobs {
+		if err := a.registerJob(job); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// snapshotJobs creates the daily account snapshot job, plus an hourly one
+// when SNAPSHOT_HOURLY=true
+func (a *App) snapshotJobs() []*scheduler.SnapshotJob {
+	periods := []model.SnapshotPeriod{model.SnapshotPeriodDaily}
+	if os.Getenv("SNAPSHOT_HOURLY") == "true" {
+		periods = append(periods, model.SnapshotPeriodHourly)
+	}
+
+	jobs := make([]*scheduler.SnapshotJob, 0, len(periods))
+	for _, period := range periods {
+		jobs = append(jobs, scheduler.NewSnapshotJob(a.repos.apiKeys, a.repos.positions, a.repos.snapshots, a.quotation, a.newExchangeClient, period))
+	}
+	return jobs
+}
@@ -0,0 +1,68 @@
+// Package gateway defines the interfaces services use to talk to the exchange,
+// so they can be backed by Upbit, a fake server or another exchange.
+package gateway
+
+import (
+	"context"
+	"time"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/exchange"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/quotation"
+)
+
+// ExchangeAPI is the private (authenticated) exchange API
+type ExchangeAPI interface {
+	GetAccounts(ctx context.Context) ([]exchange.Account, error)
+	PlaceOrder(ctx context.Context, req exchange.OrderRequest) (*exchange.OrderResponse, error)
+	GetOrder(ctx context.Context, orderUUID string) (*exchange.OrderResponse, error)
+	CancelOrder(ctx context.Context, orderUUID string) (*exchange.OrderResponse, error)
+	GetOrders(ctx context.Context, market string, state string) ([]exchange.OrderResponse, error)
+}
+
+// TransferAPI lists an account's deposits and withdrawals. Not every
+// ExchangeAPI can, so callers check for it with a type assertion.
+type TransferAPI interface {
+	GetDeposits(ctx context.Context, currency string) ([]exchange.Transfer, error)
+	GetWithdraws(ctx context.Context, currency string) ([]exchange.Transfer, error)
+}
+
+// APIKeyInfoAPI lists the account's API keys with their expiry dates. Not
+// every ExchangeAPI can, so callers check for it with a type assertion.
+type APIKeyInfoAPI interface {
+	GetAPIKeys(ctx context.Context) ([]exchange.APIKey, error)
+}
+
+// OrderLookupAPI finds an order by the identifier it was placed with. Not
+// every ExchangeAPI can, so callers check for it with a type assertion.
+type OrderLookupAPI interface {
+	GetOrderByIdentifier(ctx context.Context, identifier string) (*exchange.OrderResponse, error)
+}
+
+// ExchangeClientFactory creates an ExchangeAPI for a user's API credentials
+type ExchangeClientFactory func(accessKey, secretKey string) ExchangeAPI
+
+// QuotationAPI is the public market data API
+type QuotationAPI interface {
+	GetMarkets(ctx context.Context) ([]quotation.Market, error)
+	GetCandles(ctx context.Context, market string, interval model.CandleInterval, count int) ([]model.Candle, error)
+	GetCandleRange(ctx context.Context, market string, interval model.CandleInterval, from, to time.Time) ([]model.Candle, error)
+	// StreamCandleRange passes the candles of [from, to) to fn oldest first,
+	// a page at a time
+	StreamCandleRange(ctx context.Context, market string, interval model.CandleInterval, from, to time.Time, fn func([]model.Candle) error) error
+	GetOrderbook(ctx context.Context, market string) (*model.Orderbook, error)
+	GetTicker(ctx context.Context, markets []string) ([]quotation.Ticker, error)
+}
+
+var (
+	_ ExchangeAPI    = (*exchange.Client)(nil)
+	_ TransferAPI    = (*exchange.Client)(nil)
+	_ APIKeyInfoAPI  = (*exchange.Client)(nil)
+	_ OrderLookupAPI = (*exchange.Client)(nil)
+	_ QuotationAPI   = (*quotation.Client)(nil)
+)
+
+// NewUpbitExchangeClient is the ExchangeClientFactory for the real Upbit API
+func NewUpbitExchangeClient(accessKey, secretKey string) ExchangeAPI {
+	return exchange.NewClient(accessKey, secretKey)
+}
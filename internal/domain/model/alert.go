@@ -0,0 +1,71 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AlertCondition is the direction a price must cross to trigger an alert
+type AlertCondition string
+
+const (
+	AlertConditionAbove AlertCondition = "above"
+	AlertConditionBelow AlertCondition = "below"
+)
+
+// AlertMode controls what happens after an alert triggers
+type AlertMode string
+
+const (
+	AlertModeOnce      AlertMode = "once"      // Deactivates after triggering
+	AlertModeRecurring AlertMode = "recurring" // Triggers on every cross
+)
+
+// PriceAlert notifies a user when a market's price crosses a level
+type PriceAlert struct {
+	ID              uuid.UUID      `json:"id" db:"id"`
+	UserID          uuid.UUID      `json:"user_id" db:"user_id"`
+	Market          string         `json:"market" db:"market"` // e.g., "KRW-BTC"
+	Condition       AlertCondition `json:"condition" db:"condition"`
+	Price           float64        `json:"price" db:"price"`
+	Mode            AlertMode      `json:"mode" db:"mode"`
+	Active          bool           `json:"active" db:"active"`
+	TriggerCount    int            `json:"trigger_count" db:"trigger_count"`
+	LastTriggeredAt *time.Time     `json:"last_triggered_at,omitempty" db:"last_triggered_at"`
+	CreatedAt       time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// NewPriceAlert creates a new active price alert, created at now
+func NewPriceAlert(userID uuid.UUID, market string, condition AlertCondition, price float64, mode AlertMode, now time.Time) *PriceAlert {
+	return &PriceAlert{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Market:    market,
+		Condition: condition,
+		Price:     price,
+		Mode:      mode,
+		Active:    true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Crossed reports whether a move from previous to current crosses the alert level
+func (a *PriceAlert) Crossed(previous, current float64) bool {
+	if a.Condition == AlertConditionBelow {
+		return previous > a.Price && current <= a.Price
+	}
+	return previous < a.Price && current >= a.Price
+}
+
+// Trigger records that the alert fired, deactivating one-shot alerts
+func (a *PriceAlert) Trigger(at time.Time) {
+	a.TriggerCount++
+	a.LastTriggeredAt = &at
+	a.UpdatedAt = at
+	if a.Mode == AlertModeOnce {
+		a.Active = false
+	}
+}
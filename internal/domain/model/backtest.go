@@ -0,0 +1,40 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/pkg/perf"
+)
+
+// BacktestRun is a stored backtest result
+type BacktestRun struct {
+	ID             uuid.UUID          `json:"id" db:"id"`
+	UserID         uuid.UUID          `json:"user_id" db:"user_id"`
+	Market         string             `json:"market" db:"market"`
+	Interval       CandleInterval     `json:"interval" db:"candle_interval"`
+	Strategy       string             `json:"strategy" db:"strategy"`
+	Params         map[string]float64 `json:"params" db:"params"`
+	From           time.Time          `json:"from" db:"from_time"`
+	To             time.Time          `json:"to" db:"to_time"`
+	Config         json.RawMessage    `json:"config" db:"config"` // Full run configuration, including costs
+	InitialCapital float64            `json:"initial_capital" db:"initial_capital"`
+	FinalEquity    float64            `json:"final_equity" db:"final_equity"`
+	TotalFees      float64            `json:"total_fees" db:"total_fees"`
+	Slippage       float64            `json:"slippage" db:"slippage"`
+	Metrics        perf.Metrics       `json:"metrics" db:"metrics"`
+	Trades         []BacktestTrade    `json:"trades,omitempty" db:"-"` // Only loaded for a single run
+	CreatedAt      time.Time          `json:"created_at" db:"created_at"`
+}
+
+// BacktestTrade is a simulated round trip of a stored backtest run
+type BacktestTrade struct {
+	EntryTime  time.Time `json:"entry_time"`
+	ExitTime   time.Time `json:"exit_time"`
+	EntryPrice float64   `json:"entry_price"`
+	ExitPrice  float64   `json:"exit_price"`
+	Quantity   float64   `json:"quantity"`
+	Fees       float64   `json:"fees"`
+	PnL        float64   `json:"pnl"`
+}
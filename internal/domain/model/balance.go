@@ -0,0 +1,118 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Balance is one currency held on the exchange
+type Balance struct {
+	Currency    string  `json:"currency"`
+	Balance     float64 `json:"balance"` // Free to trade
+	Locked      float64 `json:"locked"`  // Held by open orders
+	AvgBuyPrice float64 `json:"avg_buy_price"`
+}
+
+// AccountBalances is a user's exchange balances as of SyncedAt
+type AccountBalances struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Balances []Balance `json:"balances"`
+	SyncedAt time.Time `json:"synced_at"`
+}
+
+// Held returns the whole balance of currency, including what open orders hold
+func (a *AccountBalances) Held(currency string) float64 {
+	for _, b := range a.Balances {
+		if b.Currency == currency {
+			return b.Balance + b.Locked
+		}
+	}
+	return 0
+}
+
+// Free returns the balance of currency not held by open orders
+func (a *AccountBalances) Free(currency string) float64 {
+	for _, b := range a.Balances {
+		if b.Currency == currency {
+			return b.Balance
+		}
+	}
+	return 0
+}
+
+// AvailableBalance is how much of a currency can still be committed. Locked
+// funds are held by orders resting on the exchange; reserved funds belong to
+// orders accepted by the platform but not yet submitted, which the exchange
+// doesn't know about.
+type AvailableBalance struct {
+	Currency string  `json:"currency"`
+	Free     float64 `json:"free"`   // Not locked on the exchange
+	Locked   float64 `json:"locked"` // By resting orders on the exchange
+	// LockedByPlatform is the part of Locked held by open orders placed
+	// through the platform; the rest belongs to orders placed elsewhere
+	LockedByPlatform float64 `json:"locked_by_platform"`
+	Reserved         float64 `json:"reserved"`
+	Available        float64 `json:"available"` // Free less Reserved
+	OpenOrders       int     `json:"open_orders"`
+}
+
+// AvailableBalances is a user's available balance per currency
+type AvailableBalances struct {
+	UserID   uuid.UUID          `json:"user_id"`
+	Balances []AvailableBalance `json:"balances"`
+	SyncedAt time.Time          `json:"synced_at"` // Of the exchange balances
+}
+
+// NewAvailableBalances derives available balances from exchange balances and
+// the user's orders. Currencies only committed by orders are listed too.
+func NewAvailableBalances(balances *AccountBalances, orders []*Order) *AvailableBalances {
+	available := &AvailableBalances{UserID: balances.UserID, SyncedAt: balances.SyncedAt}
+	index := make(map[string]int, len(balances.Balances))
+	for _, b := range balances.Balances {
+		index[b.Currency] = len(available.Balances)
+		available.Balances = append(available.Balances, AvailableBalance{Currency: b.Currency, Free: b.Balance, Locked: b.Locked})
+	}
+
+	for _, o := range orders {
+		var reserved bool
+		switch o.Status {
+		case OrderStatusPending:
+			reserved = true
+		case OrderStatusSubmitted, OrderStatusPartial:
+		default:
+			continue
+		}
+
+		currency, amount := o.Funds()
+		i, ok := index[currency]
+		if !ok {
+			i = len(available.Balances)
+			index[currency] = i
+			available.Balances = append(available.Balances, AvailableBalance{Currency: currency})
+		}
+		b := &available.Balances[i]
+		b.OpenOrders++
+		if reserved {
+			b.Reserved += amount
+		} else {
+			b.LockedByPlatform += amount
+		}
+	}
+
+	for i := range available.Balances {
+		b := &available.Balances[i]
+		b.Available = max(b.Free-b.Reserved, 0)
+	}
+	return available
+}
+
+// Available returns how much of currency can still be committed
+func (a *AvailableBalances) Available(currency string) float64 {
+	for _, b := range a.Balances {
+		if b.Currency == currency {
+			return b.Available
+		}
+	}
+	return 0
+}
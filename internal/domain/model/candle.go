@@ -1,6 +1,9 @@
 package model
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -8,6 +11,7 @@ import (
 type CandleInterval string
 
 const (
+	CandleInterval1s  CandleInterval = "1s"
 	CandleInterval1m  CandleInterval = "1m"
 	CandleInterval3m  CandleInterval = "3m"
 	CandleInterval5m  CandleInterval = "5m"
@@ -20,35 +24,97 @@ const (
 	CandleInterval1M  CandleInterval = "1M"
 )
 
+// Duration returns the length of a candle; months are approximated as 30 days
+func (i CandleInterval) Duration() time.Duration {
+	switch i {
+	case CandleInterval1s:
+		return time.Second
+	case CandleInterval1m:
+		return time.Minute
+	case CandleInterval3m:
+		return 3 * time.Minute
+	case CandleInterval5m:
+		return 5 * time.Minute
+	case CandleInterval15m:
+		return 15 * time.Minute
+	case CandleInterval30m:
+		return 30 * time.Minute
+	case CandleInterval1h:
+		return time.Hour
+	case CandleInterval4h:
+		return 4 * time.Hour
+	case CandleInterval1d:
+		return 24 * time.Hour
+	case CandleInterval1w:
+		return 7 * 24 * time.Hour
+	case CandleInterval1M:
+		return 30 * 24 * time.Hour
+	}
+	return 0
+}
+
+// MaxResampleStep is the longest interval candles can be resampled to
+const MaxResampleStep = 30 * 24 * time.Hour
+
+// ParseResampleStep parses a custom candle interval of whole minutes, hours
+// or days, e.g. "45m", "2h" or "3d", which stored 1m candles are resampled to
+func ParseResampleStep(interval CandleInterval) (time.Duration, error) {
+	s := string(interval)
+	units := map[string]time.Duration{"m": time.Minute, "h": time.Hour, "d": 24 * time.Hour}
+	for suffix, unit := range units {
+		n, ok := strings.CutSuffix(s, suffix)
+		if !ok {
+			continue
+		}
+		count, err := strconv.Atoi(n)
+		if err != nil || count <= 0 || time.Duration(count)*unit > MaxResampleStep {
+			break
+		}
+		return time.Duration(count) * unit, nil
+	}
+	return 0, fmt.Errorf("invalid interval %q: use whole minutes, hours or days up to 30d, e.g. 45m, 2h or 3d", s)
+}
+
+// ResampleInterval names a resample step, e.g. "45m", "2h" or "3d"
+func ResampleInterval(step time.Duration) CandleInterval {
+	switch {
+	case step%(24*time.Hour) == 0:
+		return CandleInterval(fmt.Sprintf("%dd", step/(24*time.Hour)))
+	case step%time.Hour == 0:
+		return CandleInterval(fmt.Sprintf("%dh", step/time.Hour))
+	}
+	return CandleInterval(fmt.Sprintf("%dm", step/time.Minute))
+}
+
 // Candle represents OHLCV (Open, High, Low, Close, Volume) candlestick data
 type Candle struct {
-	Market          string         `json:"market"`            // e.g., "KRW-BTC"
-	Interval        CandleInterval `json:"interval"`          // e.g., "1m", "5m", "1h"
-	Timestamp       time.Time      `json:"timestamp"`         // Candle start time
-	OpenPrice       float64        `json:"opening_price"`
-	HighPrice       float64        `json:"high_price"`
-	LowPrice        float64        `json:"low_price"`
-	ClosePrice      float64        `json:"trade_price"`       // Last trade price
-	Volume          float64        `json:"candle_acc_trade_volume"` // Accumulated trade volume
-	AccTradePrice   float64        `json:"candle_acc_trade_price"`  // Accumulated trade price
-	PrevClosingPrice float64       `json:"prev_closing_price,omitempty"`
-	Change          string         `json:"change,omitempty"`  // RISE, EVEN, FALL
-	ChangePrice     float64        `json:"change_price,omitempty"`
-	ChangeRate      float64        `json:"change_rate,omitempty"`
+	Market           string         `json:"market"`    // e.g., "KRW-BTC"
+	Interval         CandleInterval `json:"interval"`  // e.g., "1m", "5m", "1h"
+	Timestamp        time.Time      `json:"timestamp"` // Candle start time
+	OpenPrice        float64        `json:"opening_price"`
+	HighPrice        float64        `json:"high_price"`
+	LowPrice         float64        `json:"low_price"`
+	ClosePrice       float64        `json:"trade_price"`             // Last trade price
+	Volume           float64        `json:"candle_acc_trade_volume"` // Accumulated trade volume
+	AccTradePrice    float64        `json:"candle_acc_trade_price"`  // Accumulated trade price
+	PrevClosingPrice float64        `json:"prev_closing_price,omitempty"`
+	Change           string         `json:"change,omitempty"` // RISE, EVEN, FALL
+	ChangePrice      float64        `json:"change_price,omitempty"`
+	ChangeRate       float64        `json:"change_rate,omitempty"`
 }
 
 // Tick represents a single trade tick
 type Tick struct {
-	Market           string    `json:"market"`
-	TradeDateUTC     string    `json:"trade_date_utc"`
-	TradeTimeUTC     string    `json:"trade_time_utc"`
-	Timestamp        int64     `json:"timestamp"`
-	TradePrice       float64   `json:"trade_price"`
-	TradeVolume      float64   `json:"trade_volume"`
-	PrevClosingPrice float64   `json:"prev_closing_price"`
-	ChangePrice      float64   `json:"change_price"`
-	AskBid           string    `json:"ask_bid"` // ASK or BID
-	SequentialID     int64     `json:"sequential_id"`
+	Market           string  `json:"market"`
+	TradeDateUTC     string  `json:"trade_date_utc"`
+	TradeTimeUTC     string  `json:"trade_time_utc"`
+	Timestamp        int64   `json:"timestamp"`
+	TradePrice       float64 `json:"trade_price"`
+	TradeVolume      float64 `json:"trade_volume"`
+	PrevClosingPrice float64 `json:"prev_closing_price"`
+	ChangePrice      float64 `json:"change_price"`
+	AskBid           string  `json:"ask_bid"` // ASK or BID
+	SequentialID     int64   `json:"sequential_id"`
 }
 
 // Orderbook represents the current orderbook (market depth)
@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// CollectorShard is one shard of a sharded candle collector. Markets are
+// spread over shards by hash, and each shard is collected by whichever worker
+// holds its lease.
+type CollectorShard struct {
+	Interval   CandleInterval `json:"interval" db:"interval"`
+	Shard      int            `json:"shard" db:"shard"`
+	Shards     int            `json:"shards" db:"shards"` // Shard count the lease was claimed with
+	Owner      string         `json:"owner" db:"owner"`
+	LeaseUntil time.Time      `json:"lease_until" db:"lease_until"`
+	// Progress of the last collection pass
+	Markets         int        `json:"markets" db:"markets"`
+	Candles         int        `json:"candles" db:"candles"`   // Saved
+	Failures        int        `json:"failures" db:"failures"` // Markets that failed
+	LastError       *string    `json:"last_error,omitempty" db:"last_error"`
+	LastCollectedAt *time.Time `json:"last_collected_at,omitempty" db:"last_collected_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Held reports whether owner holds the shard's lease at now
+func (s *CollectorShard) Held(owner string, now time.Time) bool {
+	return s.Owner == owner && now.Before(s.LeaseUntil)
+}
+
+// CandleBackfill is the progress of collecting a market's historical candles.
+// Candles from From up to Cursor are stored, so an interrupted backfill
+// resumes at Cursor and a later one skips the range.
+type CandleBackfill struct {
+	Market    string         `json:"market" db:"market"`
+	Interval  CandleInterval `json:"interval" db:"interval"`
+	From      time.Time      `json:"from" db:"from"`
+	Cursor    time.Time      `json:"cursor" db:"cursor"`
+	UpdatedAt time.Time      `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,134 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DrawdownGuard force-exits a position once its price falls MaxDrawdown
+// percent below the highest price seen since the guard was attached, or
+// since entry if that was higher. It acts regardless of any other exit
+// rules on the position.
+type DrawdownGuard struct {
+	PositionID      uuid.UUID      `json:"position_id" db:"position_id"`
+	UserID          uuid.UUID      `json:"user_id" db:"user_id"`
+	Market          string         `json:"market" db:"market"`
+	MaxDrawdown     float64        `json:"max_drawdown" db:"max_drawdown"`                   // Percent below the peak
+	MaxPriceAge     int            `json:"max_price_age" db:"max_price_age"`                 // Seconds; older prices are ignored
+	ConfirmInterval CandleInterval `json:"confirm_interval,omitempty" db:"confirm_interval"` // Only closes of these candles trigger; empty acts on every price
+	DryRun          bool           `json:"dry_run" db:"dry_run"`                             // Triggering only records and notifies; no exit order is placed
+	TemplateID      *uuid.UUID     `json:"template_id,omitempty" db:"template_id"`           // The strategy template it was cloned from; its exit is placed as that strategy
+	PeakPrice       float64        `json:"peak_price" db:"peak_price"`
+	Active          bool           `json:"active" db:"active"`
+	TriggeredAt     *time.Time     `json:"triggered_at,omitempty" db:"triggered_at"`
+	TriggerPrice    *float64       `json:"trigger_price,omitempty" db:"trigger_price"`
+	ExitOrderID     *uuid.UUID     `json:"exit_order_id,omitempty" db:"exit_order_id"`
+	CreatedAt       time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// NewDrawdownGuard creates an active guard on a position, starting from its
+// entry price as the peak
+func NewDrawdownGuard(position *Position, maxDrawdown float64, maxPriceAge int, now time.Time) *DrawdownGuard {
+	return &DrawdownGuard{
+		PositionID:  position.ID,
+		UserID:      position.UserID,
+		Market:      position.Market,
+		MaxDrawdown: maxDrawdown,
+		MaxPriceAge: maxPriceAge,
+		PeakPrice:   position.EntryPrice,
+		Active:      true,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// Drawdown returns how far price is below the peak, in percent
+func (g *DrawdownGuard) Drawdown(price float64) float64 {
+	if g.PeakPrice <= 0 || price >= g.PeakPrice {
+		return 0
+	}
+	return (g.PeakPrice - price) / g.PeakPrice * 100
+}
+
+// PriceAge returns how old a price may be for the guard to act on it
+func (g *DrawdownGuard) PriceAge() time.Duration {
+	return time.Duration(g.MaxPriceAge) * time.Second
+}
+
+// Observe raises the peak to price if it is higher and reports whether it
+// did and whether the drawdown from the peak breached the limit
+func (g *DrawdownGuard) Observe(price float64, now time.Time) (peaked, breached bool) {
+	if price > g.PeakPrice {
+		g.PeakPrice = price
+		g.UpdatedAt = now
+		return true, false
+	}
+	return false, g.Breached(price)
+}
+
+// TriggerLevel returns the price at or below which the guard triggers
+func (g *DrawdownGuard) TriggerLevel() float64 {
+	return g.PeakPrice * (1 - g.MaxDrawdown/100)
+}
+
+// Breached reports whether price is at least MaxDrawdown percent below the
+// peak
+func (g *DrawdownGuard) Breached(price float64) bool {
+	return g.Drawdown(price) >= g.MaxDrawdown
+}
+
+// Trigger records that the guard fired and deactivates it. A dry run guard
+// fires the same way but is never given an exit order.
+func (g *DrawdownGuard) Trigger(price float64, at time.Time) {
+	g.Active = false
+	g.TriggeredAt = &at
+	g.TriggerPrice = &price
+	g.UpdatedAt = at
+}
+
+// Cancel deactivates the guard without triggering it, e.g. because its
+// position closed by other means
+func (g *DrawdownGuard) Cancel(at time.Time) {
+	g.Active = false
+	g.UpdatedAt = at
+}
+
+// DrawdownGuardUpdateKind is what changed a guard's trigger level
+type DrawdownGuardUpdateKind string
+
+const (
+	DrawdownGuardAttached  DrawdownGuardUpdateKind = "attached"
+	DrawdownGuardPeak      DrawdownGuardUpdateKind = "peak"
+	DrawdownGuardTriggered DrawdownGuardUpdateKind = "triggered"
+	DrawdownGuardCancelled DrawdownGuardUpdateKind = "cancelled" // Its position closed by other means
+)
+
+// DrawdownGuardUpdate records a change of a guard's trailing state: its
+// attachment, a new peak, its trigger or its cancellation
+type DrawdownGuardUpdate struct {
+	ID           uuid.UUID               `json:"id" db:"id"`
+	PositionID   uuid.UUID               `json:"position_id" db:"position_id"`
+	Kind         DrawdownGuardUpdateKind `json:"kind" db:"kind"`
+	Price        float64                 `json:"price" db:"price"` // The price that caused it; the peak for attachments
+	PeakPrice    float64                 `json:"peak_price" db:"peak_price"`
+	MaxDrawdown  float64                 `json:"max_drawdown" db:"max_drawdown"`
+	TriggerLevel float64                 `json:"trigger_level" db:"trigger_level"`
+	CreatedAt    time.Time               `json:"created_at" db:"created_at"`
+}
+
+// NewDrawdownGuardUpdate records the guard's state after a change caused by
+// price
+func NewDrawdownGuardUpdate(guard *DrawdownGuard, kind DrawdownGuardUpdateKind, price float64, at time.Time) *DrawdownGuardUpdate {
+	return &DrawdownGuardUpdate{
+		ID:           uuid.New(),
+		PositionID:   guard.PositionID,
+		Kind:         kind,
+		Price:        price,
+		PeakPrice:    guard.PeakPrice,
+		MaxDrawdown:  guard.MaxDrawdown,
+		TriggerLevel: guard.TriggerLevel(),
+		CreatedAt:    at,
+	}
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event types written to the outbox
+const (
+	EventOrderActivated = "order.activated" // A scheduled order's time came
+	EventOrderSubmitted = "order.submitted"
+	EventOrderPartial   = "order.partial"
+	EventOrderFilled    = "order.filled"
+	EventOrderCancelled = "order.cancelled"
+	EventOrderFailed    = "order.failed"
+
+	// A position closed while its drawdown guard was active
+	EventDrawdownGuardCancelled = "drawdown_guard.cancelled"
+)
+
+// OutboxEvent represents a domain event stored in the transactional outbox
+type OutboxEvent struct {
+	ID            uuid.UUID       `json:"id" db:"id"`
+	AggregateType string          `json:"aggregate_type" db:"aggregate_type"` // e.g., "order"
+	AggregateID   uuid.UUID       `json:"aggregate_id" db:"aggregate_id"`
+	EventType     string          `json:"event_type" db:"event_type"` // e.g., "order.filled"
+	Payload       json.RawMessage `json:"payload" db:"payload"`
+	Attempts      int             `json:"attempts" db:"attempts"`
+	LastError     *string         `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt     time.Time       `json:"created_at" db:"created_at"`
+	DispatchedAt  *time.Time      `json:"dispatched_at,omitempty" db:"dispatched_at"`
+}
+
+// NewOutboxEvent creates a new outbox event with a JSON encoded payload,
+// created at now
+func NewOutboxEvent(aggregateType string, aggregateID uuid.UUID, eventType string, payload interface{}, now time.Time) (*OutboxEvent, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event payload: %w", err)
+	}
+
+	return &OutboxEvent{
+		ID:            uuid.New(),
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		EventType:     eventType,
+		Payload:       data,
+		CreatedAt:     now,
+	}, nil
+}
+
+// NewOrderEvent creates an outbox event for an order state change
+func NewOrderEvent(eventType string, order *Order, now time.Time) (*OutboxEvent, error) {
+	return NewOutboxEvent("order", order.ID, eventType, order, now)
+}
+
+// NewDrawdownGuardEvent creates an outbox event for a drawdown guard state
+// change
+func NewDrawdownGuardEvent(eventType string, guard *DrawdownGuard, now time.Time) (*OutboxEvent, error) {
+	return NewOutboxEvent("drawdown_guard", guard.PositionID, eventType, guard, now)
+}
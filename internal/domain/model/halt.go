@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TradingHalt stops new order placement until it is explicitly lifted. A halt
+// with uuid.Nil as UserID applies to every user.
+type TradingHalt struct {
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	Reason    string    `json:"reason" db:"reason"`
+	HaltedBy  string    `json:"halted_by" db:"halted_by"` // HaltedByUser, HaltedByAdmin, HaltedByWatchdog or HaltedByKeyExpiry
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// IsGlobal reports whether the halt applies to every user
+func (h *TradingHalt) IsGlobal() bool {
+	return h.UserID == uuid.Nil
+}
+
+// Who halted trading
+const (
+	HaltedByUser      = "user"
+	HaltedByAdmin     = "admin"
+	HaltedByWatchdog  = "watchdog"
+	HaltedByKeyExpiry = "api_key_expiry"
+)
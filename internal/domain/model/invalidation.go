@@ -0,0 +1,19 @@
+package model
+
+import (
+	"github.com/google/uuid"
+)
+
+// Invalidation kinds
+const (
+	InvalidationAPIKey     = "api_key"
+	InvalidationStrategy   = "strategy"
+	InvalidationKillSwitch = "kill_switch"
+)
+
+// Invalidation announces a change that other instances must drop cached state for
+type Invalidation struct {
+	Kind   string    `json:"kind"`
+	ID     uuid.UUID `json:"id"`      // ID of the changed record
+	UserID uuid.UUID `json:"user_id"` // Owner of the record; uuid.Nil for global changes
+}
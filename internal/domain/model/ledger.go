@@ -0,0 +1,57 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CashEntryType is the kind of KRW movement a ledger entry records
+type CashEntryType string
+
+const (
+	CashEntryOpeningBalance  CashEntryType = "opening_balance" // The exchange balance when the ledger was started
+	CashEntryDeposit         CashEntryType = "deposit"
+	CashEntryWithdrawal      CashEntryType = "withdrawal"
+	CashEntryFee             CashEntryType = "fee"              // Trading and withdrawal fees
+	CashEntryTradeSettlement CashEntryType = "trade_settlement" // Paid for a buy or received for a sell
+)
+
+// CashLedgerEntry is one movement of a user's KRW cash. Amounts are signed:
+// money coming in is positive and money going out negative, so the entries
+// sum to the cash the exchange should hold.
+type CashLedgerEntry struct {
+	ID         uuid.UUID     `json:"id" db:"id"`
+	UserID     uuid.UUID     `json:"user_id" db:"user_id"`
+	Type       CashEntryType `json:"type" db:"entry_type"`
+	Amount     float64       `json:"amount" db:"amount"`
+	Reference  string        `json:"reference" db:"reference"` // The execution or transfer the entry comes from
+	OrderID    *uuid.UUID    `json:"order_id,omitempty" db:"order_id"`
+	OccurredAt time.Time     `json:"occurred_at" db:"occurred_at"`
+	CreatedAt  time.Time     `json:"created_at" db:"created_at"`
+}
+
+// NewCashLedgerEntry creates a ledger entry of a movement at occurredAt,
+// recorded at now
+func NewCashLedgerEntry(userID uuid.UUID, entryType CashEntryType, amount float64, reference string, occurredAt, now time.Time) *CashLedgerEntry {
+	return &CashLedgerEntry{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Type:       entryType,
+		Amount:     amount,
+		Reference:  reference,
+		OccurredAt: occurredAt,
+		CreatedAt:  now,
+	}
+}
+
+// CashReconciliation compares the cash a user's ledger accounts for with the
+// cash their exchange account holds
+type CashReconciliation struct {
+	UserID          uuid.UUID `json:"user_id"`
+	LedgerBalance   float64   `json:"ledger_balance"`
+	ExchangeBalance float64   `json:"exchange_balance"` // Including what open orders hold
+	Difference      float64   `json:"difference"`       // ExchangeBalance - LedgerBalance
+	Balanced        bool      `json:"balanced"`
+	CheckedAt       time.Time `json:"checked_at"`
+}
@@ -0,0 +1,118 @@
+package model
+
+import "time"
+
+// AccountingMethod decides which lots a sale consumes, and so the cost basis
+// of realized PnL
+type AccountingMethod string
+
+const (
+	AccountingAverage AccountingMethod = "average" // Moving average cost
+	AccountingFIFO    AccountingMethod = "fifo"    // Oldest lots first
+	AccountingLIFO    AccountingMethod = "lifo"    // Newest lots first
+)
+
+// lotTolerance absorbs float rounding when deciding a lot is used up
+const lotTolerance = 1e-12
+
+// Valid reports whether the method is supported
+func (m AccountingMethod) Valid() bool {
+	switch m {
+	case AccountingAverage, AccountingFIFO, AccountingLIFO:
+		return true
+	}
+	return false
+}
+
+// Lot is quantity acquired in one fill
+type Lot struct {
+	Quantity   float64   `json:"quantity"` // Still held
+	Price      float64   `json:"price"`
+	AcquiredAt time.Time `json:"acquired_at"`
+}
+
+// Lots are a position's holdings, oldest first. Operations return new
+// slices and never modify the receiver.
+type Lots []Lot
+
+// Add returns the lots with a new one appended
+func (l Lots) Add(qty, price float64, at time.Time) Lots {
+	lots := make(Lots, len(l), len(l)+1)
+	copy(lots, l)
+	return append(lots, Lot{Quantity: qty, Price: price, AcquiredAt: at})
+}
+
+// Quantity returns the total quantity held
+func (l Lots) Quantity() float64 {
+	var qty float64
+	for _, lot := range l {
+		qty += lot.Quantity
+	}
+	return qty
+}
+
+// Cost returns the total cost of the quantity held
+func (l Lots) Cost() float64 {
+	var cost float64
+	for _, lot := range l {
+		cost += lot.Quantity * lot.Price
+	}
+	return cost
+}
+
+// AveragePrice returns the average cost of the quantity held
+func (l Lots) AveragePrice() float64 {
+	qty := l.Quantity()
+	if qty <= 0 {
+		return 0
+	}
+	return l.Cost() / qty
+}
+
+// Reduce removes qty using the method and returns the remaining lots and the
+// cost of the quantity removed. Quantity beyond what is held is ignored.
+func (l Lots) Reduce(qty float64, method AccountingMethod) (Lots, float64) {
+	held := l.Quantity()
+	if held <= 0 || qty <= 0 {
+		return l, 0
+	}
+	qty = min(qty, held)
+
+	remaining := make(Lots, 0, len(l))
+	var cost float64
+	switch method {
+	case AccountingFIFO, AccountingLIFO:
+		order := make([]int, len(l))
+		for i := range l {
+			if method == AccountingFIFO {
+				order[i] = i
+			} else {
+				order[i] = len(l) - 1 - i
+			}
+		}
+		left := make([]float64, len(l))
+		for _, i := range order {
+			take := min(qty, l[i].Quantity)
+			cost += take * l[i].Price
+			qty -= take
+			left[i] = l[i].Quantity - take
+		}
+		for i, lot := range l {
+			if left[i] > lotTolerance {
+				lot.Quantity = left[i]
+				remaining = append(remaining, lot)
+			}
+		}
+	default:
+		// Every lot shrinks in proportion, keeping the average cost
+		keep := 1 - qty/held
+		cost = l.Cost() * (qty / held)
+		for _, lot := range l {
+			if lot.Quantity*keep > lotTolerance {
+				lot.Quantity *= keep
+				remaining = append(remaining, lot)
+			}
+		}
+	}
+	return remaining, cost
+}
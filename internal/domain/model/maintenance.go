@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaintenanceWindow is a period Upbit announced maintenance for. Orders for
+// the market aren't placed during it; an empty Market covers every market.
+type MaintenanceWindow struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	Market    string    `json:"market,omitempty" db:"market"`
+	Reason    string    `json:"reason" db:"reason"`
+	StartsAt  time.Time `json:"starts_at" db:"starts_at"`
+	EndsAt    time.Time `json:"ends_at" db:"ends_at"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// Covers reports whether the window blocks a market at the given time
+func (w *MaintenanceWindow) Covers(market string, at time.Time) bool {
+	return (w.Market == "" || w.Market == market) && !at.Before(w.StartsAt) && at.Before(w.EndsAt)
+}
+
+// Maintenance is maintenance blocking orders, either an announced window or
+// an outage detected from Upbit's responses
+type Maintenance struct {
+	Market   string     `json:"market,omitempty"` // Empty for every market
+	Reason   string     `json:"reason"`
+	Detected bool       `json:"detected"` // Detected rather than announced
+	Since    time.Time  `json:"since"`
+	Until    *time.Time `json:"until,omitempty"` // Unknown for detected outages
+}
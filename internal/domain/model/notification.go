@@ -0,0 +1,92 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notification types
+const (
+	NotificationPriceAlert       = "price_alert"
+	NotificationDailySummary     = "daily_summary"
+	NotificationOrderFailed      = "order_failed"
+	NotificationAPIKeyRejected   = "api_key_rejected"
+	NotificationAPIKeyExpiry     = "api_key_expiry"
+	NotificationExchangeDegraded = "exchange_degraded"
+	NotificationExchangeRestored = "exchange_restored"
+	NotificationDailyLossLimit   = "daily_loss_limit"
+	NotificationDrawdownGuard    = "drawdown_guard"
+	NotificationWatchdog         = "watchdog_anomaly"
+	NotificationRebalance        = "rebalance"
+	NotificationPositionMismatch = "position_mismatch"
+	NotificationRecurringOrder   = "recurring_order"
+	NotificationMaintenanceStart = "maintenance_started"
+	NotificationMaintenanceEnd   = "maintenance_ended"
+	NotificationWithdrawAddress  = "withdraw_address"
+)
+
+// Notification is a message delivered to a user through the notification channels
+type Notification struct {
+	ID        uuid.UUID      `json:"id"`
+	UserID    uuid.UUID      `json:"user_id"`
+	Type      string         `json:"type"` // e.g., "price_alert"
+	Title     string         `json:"title"`
+	Message   string         `json:"message"`
+	Data      map[string]any `json:"data,omitempty"`
+	DedupKey  string         `json:"dedup_key,omitempty"` // Subject throttling is applied per, e.g. an alert ID
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// NewNotification creates a new notification, created at now
+func NewNotification(userID uuid.UUID, notificationType, title, message string, data map[string]any, now time.Time) *Notification {
+	return &Notification{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Type:      notificationType,
+		Title:     title,
+		Message:   message,
+		Data:      data,
+		CreatedAt: now,
+	}
+}
+
+// NotificationSettings are a user's notification preferences
+type NotificationSettings struct {
+	UserID         uuid.UUID `json:"user_id" db:"user_id"`
+	DailySummary   bool      `json:"daily_summary" db:"daily_summary"`
+	SummaryChannel string    `json:"summary_channel" db:"summary_channel"` // Every channel when empty
+	SummaryTime    string    `json:"summary_time" db:"summary_time"`       // Local time, "15:04"
+	Timezone       string    `json:"timezone" db:"timezone"`               // IANA name, e.g. "Asia/Seoul"
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// DefaultNotificationSettings returns the settings of a user who hasn't saved
+// any: no daily summary, which is sent at 09:00 KST once enabled
+func DefaultNotificationSettings(userID uuid.UUID) *NotificationSettings {
+	return &NotificationSettings{
+		UserID:      userID,
+		SummaryTime: "09:00",
+		Timezone:    "Asia/Seoul",
+	}
+}
+
+// NextSummary returns the first daily summary time after t in the user's
+// timezone
+func (s *NotificationSettings) NextSummary(t time.Time) (time.Time, error) {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	clock, err := time.Parse("15:04", s.SummaryTime)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	local := t.In(loc)
+	next := time.Date(local.Year(), local.Month(), local.Day(), clock.Hour(), clock.Minute(), 0, 0, loc)
+	if !next.After(local) {
+		next = time.Date(local.Year(), local.Month(), local.Day()+1, clock.Hour(), clock.Minute(), 0, 0, loc)
+	}
+	return next, nil
+}
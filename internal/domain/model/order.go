@@ -1,11 +1,15 @@
 package model
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// MinOrderAmount is Upbit's smallest KRW order
+const MinOrderAmount = 5000
+
 // OrderType represents the type of order
 type OrderType string
 
@@ -26,6 +30,7 @@ const (
 type OrderStatus string
 
 const (
+	OrderStatusScheduled OrderStatus = "scheduled" // Waiting for its activation time
 	OrderStatusPending   OrderStatus = "pending"
 	OrderStatusSubmitted OrderStatus = "submitted" // Submitted to exchange
 	OrderStatusPartial   OrderStatus = "partial"   // Partially filled
@@ -34,16 +39,28 @@ const (
 	OrderStatusFailed    OrderStatus = "failed"
 )
 
+// OrderSource is what placed an order: the user or one of the automations
+type OrderSource string
+
+const (
+	OrderSourceUser          OrderSource = "user"
+	OrderSourceDrawdownGuard OrderSource = "drawdown_guard" // SourceID is the guarded position
+	OrderSourceLossLimit     OrderSource = "loss_limit"     // Flattened on hitting the daily loss limit
+	OrderSourceRebalance     OrderSource = "rebalance"
+	OrderSourceRecurring     OrderSource = "recurring" // SourceID is the recurring order
+	OrderSourceSignal        OrderSource = "signal"    // SourceID is the signal subscription
+)
+
 // Order represents a trading order
 type Order struct {
 	ID               uuid.UUID   `json:"id" db:"id"`
 	UserID           uuid.UUID   `json:"user_id" db:"user_id"`
 	PositionID       *uuid.UUID  `json:"position_id,omitempty" db:"position_id"`
-	Market           string      `json:"market" db:"market"`           // e.g., "KRW-BTC"
-	Side             OrderSide   `json:"side" db:"side"`               // bid or ask
-	Type             OrderType   `json:"type" db:"order_type"`         // limit or market
-	Price            *float64    `json:"price,omitempty" db:"price"`   // Null for market orders
-	Quantity         float64     `json:"quantity" db:"quantity"`       // Original quantity
+	Market           string      `json:"market" db:"market"`         // e.g., "KRW-BTC"
+	Side             OrderSide   `json:"side" db:"side"`             // bid or ask
+	Type             OrderType   `json:"type" db:"order_type"`       // limit or market
+	Price            *float64    `json:"price,omitempty" db:"price"` // Null for market orders
+	Quantity         float64     `json:"quantity" db:"quantity"`     // Original quantity
 	ExecutedQuantity float64     `json:"executed_quantity" db:"executed_quantity"`
 	Status           OrderStatus `json:"status" db:"status"`
 	ExchangeOrderID  *string     `json:"exchange_order_id,omitempty" db:"exchange_order_id"` // Upbit order UUID
@@ -51,11 +68,16 @@ type Order struct {
 	UpdatedAt        time.Time   `json:"updated_at" db:"updated_at"`
 	SubmittedAt      *time.Time  `json:"submitted_at,omitempty" db:"submitted_at"`
 	FilledAt         *time.Time  `json:"filled_at,omitempty" db:"filled_at"`
+	Source           OrderSource `json:"source" db:"source"`
+	SourceID         *uuid.UUID  `json:"source_id,omitempty" db:"source_id"` // The automation instance, if it has several
+	Tags             []string    `json:"tags" db:"tags"`                     // The user's journal, e.g. "bot-x"
+	Notes            string      `json:"notes,omitempty" db:"notes"`
+	ActivateAt       *time.Time  `json:"activate_at,omitempty" db:"activate_at"` // When a scheduled order is submitted
+	StrategyID       *uuid.UUID  `json:"strategy_id,omitempty" db:"strategy_id"` // The strategy that placed it, if any
 }
 
-// NewOrder creates a new order
-func NewOrder(userID uuid.UUID, market string, side OrderSide, orderType OrderType, quantity float64, price *float64) *Order {
-	now := time.Now()
+// NewOrder creates a new order, created at now
+func NewOrder(userID uuid.UUID, market string, side OrderSide, orderType OrderType, quantity float64, price *float64, now time.Time) *Order {
 	return &Order{
 		ID:               uuid.New(),
 		UserID:           userID,
@@ -64,6 +86,8 @@ func NewOrder(userID uuid.UUID, market string, side OrderSide, orderType OrderTy
 		Type:             orderType,
 		Price:            price,
 		Quantity:         quantity,
+		Source:           OrderSourceUser,
+		Tags:             []string{},
 		ExecutedQuantity: 0,
 		Status:           OrderStatusPending,
 		CreatedAt:        now,
@@ -81,17 +105,37 @@ func (o *Order) IsPending() bool {
 	return o.Status == OrderStatusPending || o.Status == OrderStatusSubmitted
 }
 
+// BidFeeReserve is the share of a buy reserved for Upbit's trading fee, which
+// is locked on top of the order amount
+const BidFeeReserve = 0.0005
+
+// Funds returns the currency an order spends and how much of the unfilled
+// part: KRW including the fee for buys, the coin for sells. Market buys
+// without a price are not counted.
+func (o *Order) Funds() (string, float64) {
+	remaining := max(o.Quantity-o.ExecutedQuantity, 0)
+	if o.Side == OrderSideBid {
+		if o.Price == nil {
+			return "KRW", 0
+		}
+		return "KRW", remaining * *o.Price * (1 + BidFeeReserve)
+	}
+	if i := strings.IndexByte(o.Market, '-'); i >= 0 {
+		return o.Market[i+1:], remaining
+	}
+	return o.Market, remaining
+}
+
 // UpdateExecution updates the order with execution information
-func (o *Order) UpdateExecution(executedQty float64) {
+func (o *Order) UpdateExecution(executedQty float64, now time.Time) {
 	o.ExecutedQuantity += executedQty
-	o.UpdatedAt = time.Now()
+	o.UpdatedAt = now
 
+	// Fills arriving for a final order keep its status
 	if o.ExecutedQuantity >= o.Quantity {
-		o.Status = OrderStatusFilled
-		now := time.Now()
-		o.FilledAt = &now
+		_ = o.Transition(OrderStatusFilled, now)
 	} else if o.ExecutedQuantity > 0 {
-		o.Status = OrderStatusPartial
+		_ = o.Transition(OrderStatusPartial, now)
 	}
 }
 
@@ -107,7 +151,7 @@ type OrderExecution struct {
 }
 
 // NewOrderExecution creates a new order execution record
-func NewOrderExecution(orderID uuid.UUID, price, quantity, fee float64) *OrderExecution {
+func NewOrderExecution(orderID uuid.UUID, price, quantity, fee float64, now time.Time) *OrderExecution {
 	return &OrderExecution{
 		ID:        uuid.New(),
 		OrderID:   orderID,
@@ -115,6 +159,6 @@ func NewOrderExecution(orderID uuid.UUID, price, quantity, fee float64) *OrderEx
 		Quantity:  quantity,
 		Fee:       fee,
 		Total:     price * quantity,
-		CreatedAt: time.Now(),
+		CreatedAt: now,
 	}
 }
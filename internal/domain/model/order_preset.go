@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrderPresetSizeRule is how an order preset sizes its orders
+type OrderPresetSizeRule string
+
+const (
+	PresetSizeQuantity OrderPresetSizeRule = "quantity" // Size is the quantity of the coin
+	PresetSizeAmount   OrderPresetSizeRule = "amount"   // Size is KRW, converted at the order's price
+)
+
+// OrderPresetAlgorithm is how a preset's order is executed
+type OrderPresetAlgorithm string
+
+const (
+	PresetAlgorithmWhole OrderPresetAlgorithm = "whole" // One order of the full size
+	PresetAlgorithmSplit OrderPresetAlgorithm = "split" // Equal slices placed at once
+	PresetAlgorithmTWAP  OrderPresetAlgorithm = "twap"  // Equal slices placed SliceInterval apart, the first at once
+)
+
+// OrderPreset is an order a user places often, saved under a name so placing
+// it takes no more than the name, e.g. "btc-dip" buying 50,000 KRW of BTC at
+// market
+type OrderPreset struct {
+	ID       uuid.UUID           `json:"id" db:"id"`
+	UserID   uuid.UUID           `json:"user_id" db:"user_id"`
+	Name     string              `json:"name" db:"name"` // Unique per user
+	Market   string              `json:"market" db:"market"`
+	Side     OrderSide           `json:"side" db:"side"`
+	Type     OrderType           `json:"type" db:"type"`
+	SizeRule OrderPresetSizeRule `json:"size_rule" db:"size_rule"`
+	Size     float64             `json:"size" db:"size"` // In the unit of SizeRule
+	// Algorithm is how the order is executed by default, in Slices orders
+	// for split and twap
+	Algorithm     OrderPresetAlgorithm `json:"algorithm" db:"algorithm"`
+	Slices        int                  `json:"slices" db:"slices"`
+	SliceInterval int                  `json:"slice_interval" db:"slice_interval"` // Seconds between twap slices
+	CreatedAt     time.Time            `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time            `json:"updated_at" db:"updated_at"`
+}
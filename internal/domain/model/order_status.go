@@ -0,0 +1,60 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// orderTransitions lists the statuses an order may move to from each status.
+// Exchange updates may skip a step, e.g. an order whose submission wasn't
+// recorded fills while still pending. Filled, cancelled and failed orders
+// are final. Partial orders stay partial as further fills arrive.
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusScheduled: {OrderStatusPending, OrderStatusCancelled, OrderStatusFailed},
+	OrderStatusPending:   {OrderStatusSubmitted, OrderStatusPartial, OrderStatusFilled, OrderStatusCancelled, OrderStatusFailed},
+	OrderStatusSubmitted: {OrderStatusPartial, OrderStatusFilled, OrderStatusCancelled},
+	OrderStatusPartial:   {OrderStatusPartial, OrderStatusFilled, OrderStatusCancelled},
+}
+
+// CanTransition reports whether an order may move from s to status
+func (s OrderStatus) CanTransition(status OrderStatus) bool {
+	return slices.Contains(orderTransitions[s], status)
+}
+
+// IsFinal reports whether no further transitions are allowed
+func (s OrderStatus) IsFinal() bool {
+	return s == OrderStatusFilled || s == OrderStatusCancelled || s == OrderStatusFailed
+}
+
+// OrderTransitionError reports a status change the order lifecycle doesn't
+// allow, e.g. cancelling a filled order
+type OrderTransitionError struct {
+	OrderID uuid.UUID   `json:"order_id"`
+	From    OrderStatus `json:"from"`
+	To      OrderStatus `json:"to"`
+}
+
+func (e *OrderTransitionError) Error() string {
+	return fmt.Sprintf("order %s can't move from %s to %s", e.OrderID, e.From, e.To)
+}
+
+// Transition moves the order to a status, recording when it was submitted
+// or filled. Illegal transitions leave the order unchanged.
+func (o *Order) Transition(status OrderStatus, at time.Time) error {
+	if !o.Status.CanTransition(status) {
+		return &OrderTransitionError{OrderID: o.ID, From: o.Status, To: status}
+	}
+
+	o.Status = status
+	o.UpdatedAt = at
+	switch status {
+	case OrderStatusSubmitted:
+		o.SubmittedAt = &at
+	case OrderStatusFilled:
+		o.FilledAt = &at
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PaperAccount is a user's paper trading account: a simulated exchange
+// account holding virtual funds, traded through its own paper API key with
+// orders matched against Upbit's live orderbooks
+type PaperAccount struct {
+	ID              uuid.UUID `json:"id" db:"id"`
+	UserID          uuid.UUID `json:"user_id" db:"user_id"`
+	AccessKey       string    `json:"-" db:"access_key"`                      // Of the paper API key
+	StartingBalance float64   `json:"starting_balance" db:"starting_balance"` // Virtual KRW the account opens and resets with
+	// State is the account's balances and orders as the simulated exchange
+	// keeps them
+	State     json.RawMessage `json:"-" db:"state"`
+	ResetAt   *time.Time      `json:"reset_at,omitempty" db:"reset_at"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at" db:"updated_at"`
+}
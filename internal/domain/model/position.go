@@ -33,26 +33,34 @@ type Position struct {
 	Quantity        float64        `json:"quantity" db:"quantity"`       // Current quantity
 	InitialQuantity float64        `json:"initial_quantity" db:"initial_quantity"`
 	RealizedPnL     float64        `json:"realized_pnl" db:"realized_pnl"` // Realized profit/loss
-	CreatedAt       time.Time      `json:"created_at" db:"created_at"`
-	UpdatedAt       time.Time      `json:"updated_at" db:"updated_at"`
-	ClosedAt        *time.Time     `json:"closed_at,omitempty" db:"closed_at"`
+	// AccountingMethod decides the cost basis of RealizedPnL; it is fixed
+	// when the position opens
+	AccountingMethod AccountingMethod `json:"accounting_method" db:"accounting_method"`
+	Lots             Lots             `json:"lots" db:"lots"` // Quantity held by acquisition
+	Tags             []string         `json:"tags" db:"tags"` // The user's journal, e.g. "swing"
+	Notes            string           `json:"notes,omitempty" db:"notes"`
+	CreatedAt        time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time        `json:"updated_at" db:"updated_at"`
+	ClosedAt         *time.Time       `json:"closed_at,omitempty" db:"closed_at"`
 }
 
-// NewPosition creates a new position
-func NewPosition(userID uuid.UUID, market string, side PositionSide, entryPrice, quantity float64) *Position {
-	now := time.Now()
+// NewPosition creates a new position, opened at now
+func NewPosition(userID uuid.UUID, market string, side PositionSide, entryPrice, quantity float64, now time.Time) *Position {
 	return &Position{
-		ID:              uuid.New(),
-		UserID:          userID,
-		Market:          market,
-		Side:            side,
-		Status:          PositionStatusOpen,
-		EntryPrice:      entryPrice,
-		Quantity:        quantity,
-		InitialQuantity: quantity,
-		RealizedPnL:     0,
-		CreatedAt:       now,
-		UpdatedAt:       now,
+		ID:               uuid.New(),
+		UserID:           userID,
+		Market:           market,
+		Side:             side,
+		Status:           PositionStatusOpen,
+		EntryPrice:       entryPrice,
+		Quantity:         quantity,
+		InitialQuantity:  quantity,
+		RealizedPnL:      0,
+		AccountingMethod: AccountingAverage,
+		Lots:             Lots{{Quantity: quantity, Price: entryPrice, AcquiredAt: now}},
+		Tags:             []string{},
+		CreatedAt:        now,
+		UpdatedAt:        now,
 	}
 }
 
@@ -64,29 +72,116 @@ func (p *Position) CalculateUnrealizedPnL(currentPrice float64) float64 {
 	return (p.EntryPrice - currentPrice) * p.Quantity
 }
 
-// UpdateQuantity updates the position quantity and recalculates entry price
-func (p *Position) UpdateQuantity(additionalQty, price float64) {
-	// Recalculate average entry price
-	totalValue := p.EntryPrice*p.Quantity + price*additionalQty
+// UpdateQuantity adds a lot to the position and recalculates entry price
+func (p *Position) UpdateQuantity(additionalQty, price float64, now time.Time) {
+	p.Lots = p.lots().Add(additionalQty, price, now)
 	p.Quantity += additionalQty
-	p.EntryPrice = totalValue / p.Quantity
-	p.UpdatedAt = time.Now()
+	p.EntryPrice = p.Lots.AveragePrice()
+	p.UpdatedAt = now
 }
 
 // ReduceQuantity reduces the position quantity and updates realized PnL
-func (p *Position) ReduceQuantity(qty, exitPrice float64) {
-	pnl := (exitPrice - p.EntryPrice) * qty
+// against the cost of the lots the accounting method sells
+func (p *Position) ReduceQuantity(qty, exitPrice float64, now time.Time) {
+	lots, cost := p.lots().Reduce(qty, p.AccountingMethod)
+	// Quantity beyond the lots (e.g. dust from rounding) is priced at entry
+	if held := p.lots().Quantity(); qty > held {
+		cost += (qty - held) * p.EntryPrice
+	}
+	pnl := exitPrice*qty - cost
 	if p.Side == PositionSideShort {
 		pnl = -pnl
 	}
 
 	p.RealizedPnL += pnl
 	p.Quantity -= qty
-	p.UpdatedAt = time.Now()
+	p.Lots = lots
+	if len(lots) > 0 {
+		p.EntryPrice = lots.AveragePrice()
+	}
+	p.UpdatedAt = now
 
 	if p.Quantity <= 0.00000001 { // Close position if quantity is negligible
 		p.Status = PositionStatusClosed
-		now := time.Now()
 		p.ClosedAt = &now
 	}
 }
+
+// WriteOff removes quantity that left the position at an unknown price, e.g.
+// sold outside the platform. It is taken at cost, so no PnL is realized.
+func (p *Position) WriteOff(qty float64, now time.Time) {
+	_, cost := p.lots().Reduce(qty, p.AccountingMethod)
+	if held := p.lots().Quantity(); qty > held {
+		cost += (qty - held) * p.EntryPrice
+	}
+	price := p.EntryPrice
+	if qty > 0 {
+		price = cost / qty
+	}
+	p.ReduceQuantity(qty, price, now)
+}
+
+// lots returns the position's lots. Positions opened before lots were
+// tracked hold a single lot at the average entry price.
+func (p *Position) lots() Lots {
+	if len(p.Lots) == 0 && p.Quantity > 0 {
+		return Lots{{Quantity: p.Quantity, Price: p.EntryPrice, AcquiredAt: p.CreatedAt}}
+	}
+	return p.Lots
+}
+
+// PositionEventType is what happened to a position
+type PositionEventType string
+
+const (
+	PositionEventOpened           PositionEventType = "opened"
+	PositionEventIncreased        PositionEventType = "increased"
+	PositionEventReduced          PositionEventType = "reduced"
+	PositionEventClosed           PositionEventType = "closed"
+	PositionEventStrategyAttached PositionEventType = "strategy_attached" // A strategy traded the position for the first time
+	// Quantity missing from the exchange balance, e.g. sold directly on
+	// Upbit, was written off at cost
+	PositionEventExternalReduction PositionEventType = "external_reduction"
+)
+
+// PositionEvent records one step of a position's lifecycle and the
+// position's state right after it
+type PositionEvent struct {
+	ID         uuid.UUID         `json:"id" db:"id"`
+	PositionID uuid.UUID         `json:"position_id" db:"position_id"`
+	Type       PositionEventType `json:"type" db:"type"`
+	OrderID    *uuid.UUID        `json:"order_id,omitempty" db:"order_id"` // The order whose fill caused it, if any
+	Source     OrderSource       `json:"source,omitempty" db:"source"`
+	SourceID   *uuid.UUID        `json:"source_id,omitempty" db:"source_id"`
+	StrategyID *uuid.UUID        `json:"strategy_id,omitempty" db:"strategy_id"`
+	Price      float64           `json:"price" db:"price"`       // Fill price; 0 for attachments and external reductions
+	Quantity   float64           `json:"quantity" db:"quantity"` // Filled quantity; 0 for attachments
+	// The position after the event
+	PositionQuantity float64   `json:"position_quantity" db:"position_quantity"`
+	EntryPrice       float64   `json:"entry_price" db:"entry_price"`
+	RealizedPnL      float64   `json:"realized_pnl" db:"realized_pnl"`
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+}
+
+// NewPositionEvent creates an event of a position caused by a fill of order,
+// or by something outside the platform when order is nil
+func NewPositionEvent(position *Position, eventType PositionEventType, order *Order, price, quantity float64, now time.Time) *PositionEvent {
+	event := &PositionEvent{
+		ID:               uuid.New(),
+		PositionID:       position.ID,
+		Type:             eventType,
+		Price:            price,
+		Quantity:         quantity,
+		PositionQuantity: position.Quantity,
+		EntryPrice:       position.EntryPrice,
+		RealizedPnL:      position.RealizedPnL,
+		CreatedAt:        now,
+	}
+	if order != nil {
+		event.OrderID = &order.ID
+		event.Source = order.Source
+		event.SourceID = order.SourceID
+		event.StrategyID = order.StrategyID
+	}
+	return event
+}
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QueuedJobStatus is the state of a queued job
+type QueuedJobStatus string
+
+const (
+	QueuedJobPending   QueuedJobStatus = "pending" // Waiting for its first attempt or a retry
+	QueuedJobRunning   QueuedJobStatus = "running" // Claimed by a worker
+	QueuedJobSucceeded QueuedJobStatus = "succeeded"
+	QueuedJobFailed    QueuedJobStatus = "failed" // Out of attempts
+)
+
+// QueuedJob is a unit of asynchronous work in the durable job queue
+type QueuedJob struct {
+	ID          uuid.UUID       `json:"id" db:"id"`
+	Kind        string          `json:"kind" db:"kind"`                 // Selects the handler
+	UserID      *uuid.UUID      `json:"user_id,omitempty" db:"user_id"` // Set on jobs a user started, who may fetch their result
+	Payload     json.RawMessage `json:"payload" db:"payload"`
+	Status      QueuedJobStatus `json:"status" db:"status"`
+	Attempts    int             `json:"attempts" db:"attempts"` // Including the one running
+	MaxAttempts int             `json:"max_attempts" db:"max_attempts"`
+	// Interrupted is set when the previous attempt's worker stopped without
+	// recording an outcome, so the attempt may have had effects
+	Interrupted bool       `json:"interrupted" db:"interrupted"`
+	LastError   string     `json:"last_error,omitempty" db:"last_error"`
+	RunAt       time.Time  `json:"run_at" db:"run_at"` // When due; the lease expiry while running
+	FinishedAt  *time.Time `json:"finished_at,omitempty" db:"finished_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// QueuedJobResult is the output of a job a user started, e.g. a CSV export
+type QueuedJobResult struct {
+	JobID       uuid.UUID `json:"job_id" db:"job_id"`
+	ContentType string    `json:"content_type" db:"content_type"`
+	Data        []byte    `json:"-" db:"data"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// NewQueuedJob creates a job due at now
+func NewQueuedJob(kind string, payload json.RawMessage, maxAttempts int, now time.Time) *QueuedJob {
+	return &QueuedJob{
+		ID:          uuid.New(),
+		Kind:        kind,
+		Payload:     payload,
+		Status:      QueuedJobPending,
+		MaxAttempts: maxAttempts,
+		RunAt:       now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
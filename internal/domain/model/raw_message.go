@@ -0,0 +1,14 @@
+package model
+
+import (
+	"time"
+)
+
+// RawMessage is a market data message as received from the Upbit websocket,
+// kept verbatim so bugs can be reproduced and sessions replayed
+type RawMessage struct {
+	ReceivedAt time.Time `json:"received_at"`
+	Type       string    `json:"type"` // ticker, trade or orderbook
+	Market     string    `json:"market"`
+	Payload    []byte    `json:"payload"` // The frame as sent, in either format
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TargetPortfolio is the allocation a user wants their account kept at, and
+// when to rebalance it back
+type TargetPortfolio struct {
+	UserID  uuid.UUID      `json:"user_id" db:"user_id"`
+	Weights []TargetWeight `json:"weights" db:"weights"` // Sum to 100; KRW is cash
+	// DriftThreshold rebalances once any currency is this many percentage
+	// points off its target; 0 disables
+	DriftThreshold float64 `json:"drift_threshold" db:"drift_threshold"`
+	// IntervalHours rebalances on a schedule; 0 disables
+	IntervalHours    int        `json:"interval_hours" db:"interval_hours"`
+	Enabled          bool       `json:"enabled" db:"enabled"`
+	PendingBuys      bool       `json:"pending_buys" db:"pending_buys"` // Buys wait for sale proceeds to settle
+	LastRebalancedAt *time.Time `json:"last_rebalanced_at,omitempty" db:"last_rebalanced_at"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// TargetWeight is the share of equity a currency should make up
+type TargetWeight struct {
+	Currency string  `json:"currency"`
+	Percent  float64 `json:"percent"`
+}
+
+// Weight returns the target percent of a currency; currencies left out are 0
+func (t *TargetPortfolio) Weight(currency string) float64 {
+	for _, w := range t.Weights {
+		if w.Currency == currency {
+			return w.Percent
+		}
+	}
+	return 0
+}
+
+// Due reports whether a scheduled rebalance is due
+func (t *TargetPortfolio) Due(now time.Time) bool {
+	if t.IntervalHours <= 0 {
+		return false
+	}
+	return t.LastRebalancedAt == nil || !now.Before(t.LastRebalancedAt.Add(time.Duration(t.IntervalHours)*time.Hour))
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RecurringOrder places a market order on a schedule, e.g. buying 100,000 KRW
+// of BTC every Monday at 09:00 KST
+type RecurringOrder struct {
+	ID     uuid.UUID `json:"id" db:"id"`
+	UserID uuid.UUID `json:"user_id" db:"user_id"`
+	Market string    `json:"market" db:"market"`
+	Side   OrderSide `json:"side" db:"side"`
+	// Amount is KRW to spend for buys and the quantity to sell for sells
+	Amount    float64    `json:"amount" db:"amount"`
+	Schedule  string     `json:"schedule" db:"schedule"`   // Cron expression, e.g. "0 9 * * 1"
+	TimeZone  string     `json:"time_zone" db:"time_zone"` // Of the schedule, e.g. "Asia/Seoul"
+	Active    bool       `json:"active" db:"active"`       // Paused when false
+	NextRunAt time.Time  `json:"next_run_at" db:"next_run_at"`
+	LastRunAt *time.Time `json:"last_run_at,omitempty" db:"last_run_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// RecurringOrderRunStatus is the outcome of a scheduled run
+type RecurringOrderRunStatus string
+
+const (
+	RecurringRunPlaced  RecurringOrderRunStatus = "placed"
+	RecurringRunFailed  RecurringOrderRunStatus = "failed"  // The engine rejected the order
+	RecurringRunSkipped RecurringOrderRunStatus = "skipped" // Too late to place, e.g. after downtime
+)
+
+// RecurringOrderRun is one scheduled run of a recurring order
+type RecurringOrderRun struct {
+	ID               uuid.UUID               `json:"id" db:"id"`
+	RecurringOrderID uuid.UUID               `json:"recurring_order_id" db:"recurring_order_id"`
+	ScheduledAt      time.Time               `json:"scheduled_at" db:"scheduled_at"`
+	Status           RecurringOrderRunStatus `json:"status" db:"status"`
+	OrderID          *uuid.UUID              `json:"order_id,omitempty" db:"order_id"`
+	Error            string                  `json:"error,omitempty" db:"error"`
+	CreatedAt        time.Time               `json:"created_at" db:"created_at"`
+}
+
+// NewRecurringOrderRun creates the run of a recurring order scheduled at a
+// time, created at now
+func NewRecurringOrderRun(recurringOrderID uuid.UUID, scheduledAt, now time.Time) *RecurringOrderRun {
+	return &RecurringOrderRun{
+		ID:               uuid.New(),
+		RecurringOrderID: recurringOrderID,
+		ScheduledAt:      scheduledAt,
+		Status:           RecurringRunPlaced,
+		CreatedAt:        now,
+	}
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RetentionMode is what happens to trading records past their retention
+type RetentionMode string
+
+const (
+	RetentionArchive RetentionMode = "archive" // Moved out of the live tables into the archive
+	RetentionPurge   RetentionMode = "purge"   // Deleted
+)
+
+// Valid reports whether m is a known retention mode
+func (m RetentionMode) Valid() bool {
+	return m == RetentionArchive || m == RetentionPurge
+}
+
+// Kinds of archived records
+const (
+	ArchivedOrder         = "order"
+	ArchivedDrawdownGuard = "drawdown_guard"
+)
+
+// ArchivedRecord is a trading record moved out of the live tables by the
+// retention policy, kept as the JSON of the original row
+type ArchivedRecord struct {
+	Kind       string          `json:"kind" db:"kind"`
+	ID         uuid.UUID       `json:"id" db:"id"`
+	UserID     uuid.UUID       `json:"user_id" db:"user_id"`
+	Data       json.RawMessage `json:"data" db:"data"`
+	ArchivedAt time.Time       `json:"archived_at" db:"archived_at"`
+}
+
+// RetentionRun reports one cleanup of expired trading records
+type RetentionRun struct {
+	Mode       RetentionMode `json:"mode"`
+	Trigger    string        `json:"trigger"` // "schedule" or "admin"
+	StartedAt  time.Time     `json:"started_at"`
+	FinishedAt *time.Time    `json:"finished_at,omitempty"`
+	// Cutoffs of the record kinds with a retention; records that ended
+	// before them are removed
+	OrdersBefore     *time.Time `json:"orders_before,omitempty"`
+	StrategiesBefore *time.Time `json:"strategies_before,omitempty"`
+	Orders           int        `json:"orders"`     // Removed
+	Strategies       int        `json:"strategies"` // Removed
+	Error            string     `json:"error,omitempty"`
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RiskLimits are a user's pre-trade risk limits. Exposure is the KRW cost of
+// open positions plus the unfilled part of open buy orders. Zero disables a
+// limit.
+type RiskLimits struct {
+	UserID            uuid.UUID `json:"user_id" db:"user_id"`
+	MaxOrderNotional  float64   `json:"max_order_notional" db:"max_order_notional"`   // KRW per buy order
+	MaxOpenPositions  int       `json:"max_open_positions" db:"max_open_positions"`   // Including buys that will open one
+	MaxMarketExposure float64   `json:"max_market_exposure" db:"max_market_exposure"` // KRW per market
+	MaxTotalExposure  float64   `json:"max_total_exposure" db:"max_total_exposure"`   // KRW across markets
+	MaxConcentration  float64   `json:"max_concentration" db:"max_concentration"`     // Percent of equity per market, counting open buys
+	Downsize          bool      `json:"downsize" db:"downsize"`                       // Shrink violating buys to fit instead of rejecting them
+	DailyLossLimit    float64   `json:"daily_loss_limit" db:"daily_loss_limit"`       // KRW of realized plus unrealized loss per day
+	FlattenOnLoss     bool      `json:"flatten_on_loss" db:"flatten_on_loss"`         // Sell all positions when the daily loss limit is hit
+	DayStart          string    `json:"day_start" db:"day_start"`                     // Local time the trading day starts, "15:04"
+	Timezone          string    `json:"timezone" db:"timezone"`                       // IANA name, e.g. "Asia/Seoul"
+	UpdatedAt         time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// DefaultRiskLimits returns the limits of a user who hasn't saved any: none,
+// with trading days starting at 09:00 KST like Upbit's daily candles
+func DefaultRiskLimits(userID uuid.UUID) *RiskLimits {
+	return &RiskLimits{
+		UserID:   userID,
+		DayStart: "09:00",
+		Timezone: "Asia/Seoul",
+	}
+}
+
+// TradingDay returns the start of the trading day containing t
+func (l *RiskLimits) TradingDay(t time.Time) (time.Time, error) {
+	loc, err := time.LoadLocation(l.Timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	clock, err := time.Parse("15:04", l.DayStart)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	local := t.In(loc)
+	start := time.Date(local.Year(), local.Month(), local.Day(), clock.Hour(), clock.Minute(), 0, 0, loc)
+	if start.After(local) {
+		start = time.Date(local.Year(), local.Month(), local.Day()-1, clock.Hour(), clock.Minute(), 0, 0, loc)
+	}
+	return start, nil
+}
+
+// RiskState tracks a user's PnL over the current trading day
+type RiskState struct {
+	UserID    uuid.UUID  `json:"user_id" db:"user_id"`
+	Day       time.Time  `json:"day" db:"day"`               // Start of the trading day
+	StartPnL  float64    `json:"-" db:"start_pnl"`           // Realized plus unrealized PnL when the day started
+	DayPnL    float64    `json:"day_pnl" db:"day_pnl"`       // Realized plus unrealized PnL since the day started
+	HaltedAt  *time.Time `json:"halted_at" db:"halted_at"`   // When the daily loss limit was hit; buys are blocked until the next day
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"` // When DayPnL was last evaluated
+}
@@ -0,0 +1,144 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SignalSourceType is where a signal source's signals come from
+type SignalSourceType string
+
+const (
+	SignalSourceWebhook   SignalSourceType = "webhook"   // Token-authenticated HTTP, e.g. TradingView alerts
+	SignalSourceTelegram  SignalSourceType = "telegram"  // Posts to a Telegram channel the bot is in
+	SignalSourceIndicator SignalSourceType = "indicator" // Price levels evaluated on the live price feed
+)
+
+// SignalAction is what a signal asks subscribers to do
+type SignalAction string
+
+const (
+	SignalActionOpen     SignalAction = "open"     // Market buy of a KRW amount
+	SignalActionClose    SignalAction = "close"    // Market sell of the positions the subscription opened
+	SignalActionStrategy SignalAction = "strategy" // Run one of the source's named strategies
+)
+
+// SignalStrategy is a named action a signal can trigger, so signals only need
+// to name it, e.g. "btc-breakout"
+type SignalStrategy struct {
+	Name   string       `json:"name"`
+	Action SignalAction `json:"action"` // open or close
+	// Market traded; the signal's ticker when empty
+	Market string `json:"market,omitempty"`
+	// Amount is KRW to spend on opens; the subscription's OpenAmount when zero
+	Amount float64 `json:"amount,omitempty"`
+}
+
+// SignalIndicator signals when the price of a market crosses a level: an
+// open when it rises through Above and a close when it falls through Below.
+// Either level may be zero to disable it.
+type SignalIndicator struct {
+	Market string  `json:"market"`
+	Above  float64 `json:"above,omitempty"`
+	Below  float64 `json:"below,omitempty"`
+}
+
+// SignalSource is a stream of trading signals users can subscribe to
+type SignalSource struct {
+	ID     uuid.UUID        `json:"id" db:"id"`
+	UserID uuid.UUID        `json:"user_id" db:"user_id"` // Owner
+	Type   SignalSourceType `json:"type" db:"type"`
+	Name   string           `json:"name" db:"name"`
+	// TokenHash is the hex SHA-256 of a webhook's token; the token itself is
+	// only shown to the owner when it is created
+	TokenHash      string           `json:"-" db:"token_hash"`
+	TelegramChatID *int64           `json:"telegram_chat_id,omitempty" db:"telegram_chat_id"`
+	Indicator      *SignalIndicator `json:"indicator,omitempty" db:"indicator"`
+	// Markets signals may trade; any KRW market when empty
+	Markets    []string         `json:"markets" db:"markets"`
+	Strategies []SignalStrategy `json:"strategies" db:"strategies"`
+	// Public sources can be subscribed to by every user, private ones only
+	// by their owner
+	Public       bool       `json:"public" db:"public"`
+	Active       bool       `json:"active" db:"active"`
+	LastSignalAt *time.Time `json:"last_signal_at,omitempty" db:"last_signal_at"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Strategy returns the source's strategy with a name
+func (s *SignalSource) Strategy(name string) (SignalStrategy, bool) {
+	for _, strategy := range s.Strategies {
+		if strategy.Name == name {
+			return strategy, true
+		}
+	}
+	return SignalStrategy{}, false
+}
+
+// SignalSubscription trades a user's account on a source's signals, with its
+// own order size and risk caps. Caps of zero don't apply.
+type SignalSubscription struct {
+	ID       uuid.UUID `json:"id" db:"id"`
+	UserID   uuid.UUID `json:"user_id" db:"user_id"`
+	SourceID uuid.UUID `json:"source_id" db:"source_id"`
+	// Market only follows signals for one market; all of them when empty
+	Market string `json:"market,omitempty" db:"market"`
+	// Strategy only follows one of the source's named strategies; every
+	// signal when empty
+	Strategy string `json:"strategy,omitempty" db:"strategy"`
+	// OpenAmount is KRW spent by opens that don't name an amount
+	OpenAmount float64 `json:"open_amount" db:"open_amount"`
+	// MaxOrderAmount caps the KRW a single open may spend
+	MaxOrderAmount float64 `json:"max_order_amount" db:"max_order_amount"`
+	// MaxPositionAmount caps the KRW cost of the positions the subscription
+	// opened that are still open
+	MaxPositionAmount float64 `json:"max_position_amount" db:"max_position_amount"`
+	// MaxDailyOrders caps the orders placed per UTC day
+	MaxDailyOrders int       `json:"max_daily_orders" db:"max_daily_orders"`
+	Active         bool      `json:"active" db:"active"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// SignalLogStatus is what a received signal did
+type SignalLogStatus string
+
+const (
+	SignalLogExecuted SignalLogStatus = "executed" // Orders were placed
+	SignalLogRejected SignalLogStatus = "rejected" // Invalid, or over a subscription's risk caps
+	SignalLogFailed   SignalLogStatus = "failed"   // The engine refused the order
+	SignalLogIgnored  SignalLogStatus = "ignored"  // No subscription follows it
+)
+
+// SignalLog records a signal a source received and, for each subscription
+// that followed it, the resulting action. Signals that didn't reach a
+// subscription are logged once without one.
+type SignalLog struct {
+	ID             uuid.UUID       `json:"id" db:"id"`
+	SourceID       uuid.UUID       `json:"source_id" db:"source_id"`
+	SubscriptionID *uuid.UUID      `json:"subscription_id,omitempty" db:"subscription_id"`
+	UserID         *uuid.UUID      `json:"user_id,omitempty" db:"user_id"` // Of the subscription
+	Payload        json.RawMessage `json:"payload" db:"payload"`           // The signal as received
+	Action         SignalAction    `json:"action,omitempty" db:"action"`
+	Market         string          `json:"market,omitempty" db:"market"`
+	Strategy       string          `json:"strategy,omitempty" db:"strategy"`
+	Status         SignalLogStatus `json:"status" db:"status"`
+	OrderIDs       []uuid.UUID     `json:"order_ids" db:"order_ids"`
+	Error          string          `json:"error,omitempty" db:"error"`
+	ReceivedAt     time.Time       `json:"received_at" db:"received_at"`
+}
+
+// NewSignalLog creates the log of a signal a source received
+func NewSignalLog(sourceID uuid.UUID, payload json.RawMessage, receivedAt time.Time) *SignalLog {
+	return &SignalLog{
+		ID:         uuid.New(),
+		SourceID:   sourceID,
+		Payload:    payload,
+		Status:     SignalLogIgnored,
+		OrderIDs:   []uuid.UUID{},
+		ReceivedAt: receivedAt,
+	}
+}
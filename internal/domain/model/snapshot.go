@@ -0,0 +1,89 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SnapshotPeriod is how often account snapshots are taken
+type SnapshotPeriod string
+
+const (
+	SnapshotPeriodDaily  SnapshotPeriod = "daily"
+	SnapshotPeriodHourly SnapshotPeriod = "hourly"
+)
+
+// Duration returns the time between snapshots of the period
+func (p SnapshotPeriod) Duration() time.Duration {
+	if p == SnapshotPeriodHourly {
+		return time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// AccountSnapshot records a user's balances, open positions and equity at a point in time
+type AccountSnapshot struct {
+	ID            uuid.UUID          `json:"id" db:"id"`
+	UserID        uuid.UUID          `json:"user_id" db:"user_id"`
+	Period        SnapshotPeriod     `json:"period" db:"period"`
+	TakenAt       time.Time          `json:"taken_at" db:"taken_at"`             // Start of the period the snapshot closes
+	CashBalance   float64            `json:"cash_balance" db:"cash_balance"`     // KRW, including locked funds
+	HoldingsValue float64            `json:"holdings_value" db:"holdings_value"` // Market value of coin balances
+	Equity        float64            `json:"equity" db:"equity"`                 // CashBalance + HoldingsValue
+	UnrealizedPnL float64            `json:"unrealized_pnl" db:"unrealized_pnl"` // Of open positions
+	Balances      []BalanceSnapshot  `json:"balances" db:"balances"`
+	Positions     []PositionSnapshot `json:"positions" db:"positions"`
+	CreatedAt     time.Time          `json:"created_at" db:"created_at"`
+}
+
+// BalanceSnapshot is one currency balance valued in KRW
+type BalanceSnapshot struct {
+	Currency    string  `json:"currency"`
+	Balance     float64 `json:"balance"`
+	Locked      float64 `json:"locked"`
+	AvgBuyPrice float64 `json:"avg_buy_price"`
+	Price       float64 `json:"price"` // KRW price used for valuation
+	Value       float64 `json:"value"`
+}
+
+// PositionSnapshot is an open position marked to market
+type PositionSnapshot struct {
+	PositionID    uuid.UUID    `json:"position_id"`
+	Market        string       `json:"market"`
+	Side          PositionSide `json:"side"`
+	Quantity      float64      `json:"quantity"`
+	EntryPrice    float64      `json:"entry_price"`
+	MarkPrice     float64      `json:"mark_price"`
+	UnrealizedPnL float64      `json:"unrealized_pnl"`
+	RealizedPnL   float64      `json:"realized_pnl"`
+}
+
+// NewAccountSnapshot creates a snapshot, created at now, and computes its
+// totals. Balances must already be valued; the KRW balance counts as cash.
+func NewAccountSnapshot(userID uuid.UUID, period SnapshotPeriod, takenAt time.Time, balances []BalanceSnapshot, positions []PositionSnapshot, now time.Time) *AccountSnapshot {
+	snapshot := &AccountSnapshot{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Period:    period,
+		TakenAt:   takenAt,
+		Balances:  balances,
+		Positions: positions,
+		CreatedAt: now,
+	}
+
+	for _, b := range balances {
+		if b.Currency == "KRW" {
+			snapshot.CashBalance += b.Value
+		} else {
+			snapshot.HoldingsValue += b.Value
+		}
+	}
+	snapshot.Equity = snapshot.CashBalance + snapshot.HoldingsValue
+
+	for _, p := range positions {
+		snapshot.UnrealizedPnL += p.UnrealizedPnL
+	}
+
+	return snapshot
+}
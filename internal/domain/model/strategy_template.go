@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/pkg/perf"
+)
+
+// StrategyTemplateType is the automation a strategy template configures
+type StrategyTemplateType string
+
+const (
+	// StrategyTemplateDrawdownGuard configures drawdown guards; its params
+	// are max_drawdown and optionally max_price_age
+	StrategyTemplateDrawdownGuard StrategyTemplateType = "drawdown_guard"
+)
+
+// StrategyTemplate is a strategy a user published to the shared catalog:
+// only its type and parameters, never the publisher's identity, keys or
+// positions. Other users clone it onto positions of their own.
+type StrategyTemplate struct {
+	ID          uuid.UUID            `json:"id" db:"id"`
+	PublisherID uuid.UUID            `json:"-" db:"publisher_id"`
+	Name        string               `json:"name" db:"name"`
+	Description string               `json:"description" db:"description"`
+	Type        StrategyTemplateType `json:"type" db:"type"`
+	Params      map[string]float64   `json:"params" db:"params"`
+	Backtest    *TemplateBacktest    `json:"backtest,omitempty" db:"backtest"` // Stats of the backtest the publisher shared, if any
+	UsageCount  int                  `json:"usage_count" db:"usage_count"`     // Positions the template was cloned onto
+	CreatedAt   time.Time            `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at" db:"updated_at"`
+}
+
+// TemplateBacktest is what a backtest of a strategy template showed, without
+// the run itself
+type TemplateBacktest struct {
+	Market   string         `json:"market"`
+	Interval CandleInterval `json:"interval"`
+	From     time.Time      `json:"from"`
+	To       time.Time      `json:"to"`
+	Metrics  perf.Metrics   `json:"metrics"`
+}
@@ -0,0 +1,15 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TelegramLink connects a user account to the Telegram chat it is controlled from
+type TelegramLink struct {
+	UserID   uuid.UUID `json:"user_id" db:"user_id"`
+	ChatID   int64     `json:"chat_id" db:"chat_id"`
+	Username string    `json:"username,omitempty" db:"username"` // Telegram username, if set
+	LinkedAt time.Time `json:"linked_at" db:"linked_at"`
+}
@@ -17,19 +17,20 @@ type User struct {
 
 // UserAPIKey represents Upbit API credentials for a user
 type UserAPIKey struct {
-	ID          uuid.UUID `json:"id" db:"id"`
-	UserID      uuid.UUID `json:"user_id" db:"user_id"`
-	AccessKey   string    `json:"access_key" db:"access_key"`
-	SecretKey   string    `json:"-" db:"secret_key"` // Never expose secret in JSON
-	Description string    `json:"description" db:"description"`
-	IsActive    bool      `json:"is_active" db:"is_active"`
-	CreatedAt   time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	ID          uuid.UUID  `json:"id" db:"id"`
+	UserID      uuid.UUID  `json:"user_id" db:"user_id"`
+	AccessKey   string     `json:"access_key" db:"access_key"`
+	SecretKey   string     `json:"-" db:"secret_key"` // Never expose secret in JSON
+	Description string     `json:"description" db:"description"`
+	Paper       bool       `json:"paper" db:"paper"` // Trades the user's paper account instead of Upbit
+	IsActive    bool       `json:"is_active" db:"is_active"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty" db:"expires_at"` // As reported by Upbit; unknown until checked
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
 }
 
-// NewUser creates a new user with generated UUID
-func NewUser(email, passwordHash string) *User {
-	now := time.Now()
+// NewUser creates a new user with generated UUID, created at now
+func NewUser(email, passwordHash string, now time.Time) *User {
 	return &User{
 		ID:        uuid.New(),
 		Email:     email,
@@ -39,9 +40,8 @@ func NewUser(email, passwordHash string) *User {
 	}
 }
 
-// NewUserAPIKey creates a new API key for a user
-func NewUserAPIKey(userID uuid.UUID, accessKey, secretKey, description string) *UserAPIKey {
-	now := time.Now()
+// NewUserAPIKey creates a new API key for a user, created at now
+func NewUserAPIKey(userID uuid.UUID, accessKey, secretKey, description string, now time.Time) *UserAPIKey {
 	return &UserAPIKey{
 		ID:          uuid.New(),
 		UserID:      userID,
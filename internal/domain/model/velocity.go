@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VelocityLimits caps how many orders a user may place per minute and per
+// hour. Zero leaves a window unlimited.
+type VelocityLimits struct {
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	PerMinute int       `json:"per_minute" db:"per_minute"`
+	PerHour   int       `json:"per_hour" db:"per_hour"`
+	Override  bool      `json:"override" db:"-"` // Set by an admin instead of the platform defaults
+	Reason    string    `json:"reason,omitempty" db:"reason"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Webhook is an endpoint a user registered to receive events over HTTP
+type Webhook struct {
+	ID     uuid.UUID `json:"id" db:"id"`
+	UserID uuid.UUID `json:"user_id" db:"user_id"`
+	URL    string    `json:"url" db:"url"`
+	Secret string    `json:"-" db:"secret"` // HMAC key for delivery signatures
+	// Events are the event types delivered, e.g. "order.filled" or
+	// "price_alert"; every event when empty
+	Events         []string  `json:"events" db:"events"`
+	MaxAttempts    int       `json:"max_attempts" db:"max_attempts"`       // Attempts before a delivery is dead
+	InitialBackoff int       `json:"initial_backoff" db:"initial_backoff"` // Seconds before the first retry; doubles after each
+	Active         bool      `json:"active" db:"active"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// Wants reports whether the webhook subscribes to an event type
+func (w *Webhook) Wants(eventType string) bool {
+	return len(w.Events) == 0 || slices.Contains(w.Events, eventType)
+}
+
+// WebhookDeliveryStatus is the state of a webhook delivery
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending" // Waiting for its first attempt or a retry
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryDead      WebhookDeliveryStatus = "dead" // Out of attempts
+)
+
+// WebhookDelivery is one event sent to a webhook, with the outcome of its
+// latest attempt
+type WebhookDelivery struct {
+	ID             uuid.UUID             `json:"id" db:"id"`
+	WebhookID      uuid.UUID             `json:"webhook_id" db:"webhook_id"`
+	EventType      string                `json:"event_type" db:"event_type"`
+	Payload        json.RawMessage       `json:"payload" db:"payload"` // Body that is signed and sent
+	Status         WebhookDeliveryStatus `json:"status" db:"status"`
+	Attempts       int                   `json:"attempts" db:"attempts"`
+	LastStatusCode int                   `json:"last_status_code,omitempty" db:"last_status_code"`
+	LastError      string                `json:"last_error,omitempty" db:"last_error"`
+	NextAttemptAt  *time.Time            `json:"next_attempt_at,omitempty" db:"next_attempt_at"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty" db:"delivered_at"`
+	CreatedAt      time.Time             `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at" db:"updated_at"`
+}
+
+// NewWebhookDelivery creates a delivery due at now
+func NewWebhookDelivery(webhookID uuid.UUID, eventType string, payload json.RawMessage, now time.Time) *WebhookDelivery {
+	return &WebhookDelivery{
+		ID:            uuid.New(),
+		WebhookID:     webhookID,
+		EventType:     eventType,
+		Payload:       payload,
+		Status:        WebhookDeliveryPending,
+		NextAttemptAt: &now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WithdrawAddressStatus is where an address is in its confirmation
+type WithdrawAddressStatus string
+
+const (
+	WithdrawAddressPending   WithdrawAddressStatus = "pending"   // Waiting for the user to enter the code sent to them
+	WithdrawAddressConfirmed WithdrawAddressStatus = "confirmed" // Whitelisted once UsableAt has passed
+)
+
+// WithdrawAddress is an entry of a user's withdrawal address book. Only
+// confirmed addresses past their hold are whitelisted for withdrawals
+// through the platform.
+type WithdrawAddress struct {
+	ID       uuid.UUID `json:"id" db:"id"`
+	UserID   uuid.UUID `json:"user_id" db:"user_id"`
+	Currency string    `json:"currency" db:"currency"` // e.g. "BTC"
+	NetType  string    `json:"net_type" db:"net_type"` // Network, e.g. "BTC" or "TRX"; Upbit's net_type
+	Address  string    `json:"address" db:"address"`
+	// SecondaryAddress is the destination tag or memo some networks require
+	SecondaryAddress string                `json:"secondary_address,omitempty" db:"secondary_address"`
+	Label            string                `json:"label,omitempty" db:"label"`
+	Status           WithdrawAddressStatus `json:"status" db:"status"`
+	// CodeHash is the hex SHA-256 of the pending confirmation code
+	CodeHash      string     `json:"-" db:"code_hash"`
+	CodeExpiresAt *time.Time `json:"-" db:"code_expires_at"`
+	CodeAttempts  int        `json:"-" db:"code_attempts"` // Wrong codes entered for the current code
+	ConfirmedAt   *time.Time `json:"confirmed_at,omitempty" db:"confirmed_at"`
+	// UsableAt is when a confirmed address is whitelisted; the hold gives the
+	// user time to notice an address they didn't add
+	UsableAt  *time.Time `json:"usable_at,omitempty" db:"usable_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Usable reports whether withdrawals to the address are allowed at a time
+func (a *WithdrawAddress) Usable(at time.Time) bool {
+	return a.Status == WithdrawAddressConfirmed && a.UsableAt != nil && !at.Before(*a.UsableAt)
+}
+
+// Matches reports whether the entry is for a destination
+func (a *WithdrawAddress) Matches(currency, netType, address, secondaryAddress string) bool {
+	return a.Currency == currency && a.NetType == netType && a.Address == address && a.SecondaryAddress == secondaryAddress
+}
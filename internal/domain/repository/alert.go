@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// PriceAlertRepository persists price alerts
+type PriceAlertRepository interface {
+	Create(ctx context.Context, alert *model.PriceAlert) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.PriceAlert, error)
+	// Update stores the trigger state of an alert
+	Update(ctx context.Context, alert *model.PriceAlert) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// ListByUser returns a user's alerts, newest first
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.PriceAlert, error)
+	// ListActive returns every active alert
+	ListActive(ctx context.Context) ([]*model.PriceAlert, error)
+}
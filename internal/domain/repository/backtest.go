@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// BacktestFilter narrows a listing of backtest runs; empty fields match everything
+type BacktestFilter struct {
+	Market   string
+	Strategy string
+	Limit    int
+}
+
+// BacktestRepository persists backtest runs and their trades
+type BacktestRepository interface {
+	// Create stores a run together with its trades
+	Create(ctx context.Context, run *model.BacktestRun) error
+	// GetByID returns a run with its trades
+	GetByID(ctx context.Context, id uuid.UUID) (*model.BacktestRun, error)
+	// ListByUser returns a user's runs newest first, without trades
+	ListByUser(ctx context.Context, userID uuid.UUID, filter BacktestFilter) ([]*model.BacktestRun, error)
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// CollectorShardRepository coordinates the workers of a sharded candle
+// collector through leases on its shards
+type CollectorShardRepository interface {
+	// Claim leases one of an interval's first shards shards to owner until
+	// leaseUntil: the one owner already holds, or else the lowest whose lease
+	// expired by now. Returns ErrNotFound when every shard is held by others.
+	Claim(ctx context.Context, interval model.CandleInterval, shards int, owner string, now, leaseUntil time.Time) (*model.CollectorShard, error)
+	// SaveProgress records a collection pass over a shard, returning
+	// ErrNotFound if its owner no longer holds the lease
+	SaveProgress(ctx context.Context, shard *model.CollectorShard) error
+	// Release gives up owner's lease on a shard so another worker can take it
+	Release(ctx context.Context, interval model.CandleInterval, shard int, owner string) error
+	// List returns an interval's shards in order
+	List(ctx context.Context, interval model.CandleInterval) ([]*model.CollectorShard, error)
+}
+
+// CandleBackfillRepository persists the progress of candle backfills
+type CandleBackfillRepository interface {
+	// Get returns a market's backfill progress, or ErrNotFound if it was never
+	// backfilled
+	Get(ctx context.Context, market string, interval model.CandleInterval) (*model.CandleBackfill, error)
+	// Save creates or replaces a market's backfill progress
+	Save(ctx context.Context, backfill *model.CandleBackfill) error
+}
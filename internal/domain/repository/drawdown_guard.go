@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// DrawdownGuardRepository persists drawdown guards, at most one per position
+type DrawdownGuardRepository interface {
+	// Save creates or replaces the guard of a position
+	Save(ctx context.Context, guard *model.DrawdownGuard) error
+	// SavePeak stores the peak of a position's guard while it is active,
+	// returning ErrNotFound once it isn't, so a late peak never reactivates
+	// a triggered or cancelled guard
+	SavePeak(ctx context.Context, guard *model.DrawdownGuard) error
+	GetByPosition(ctx context.Context, positionID uuid.UUID) (*model.DrawdownGuard, error)
+	Delete(ctx context.Context, positionID uuid.UUID) error
+	// ListByUser returns a user's guards, newest first
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.DrawdownGuard, error)
+	// ListActive returns every active guard
+	ListActive(ctx context.Context) ([]*model.DrawdownGuard, error)
+}
+
+// DrawdownGuardUpdateRepository records the trailing state changes of
+// drawdown guards. Updates are removed with their guard.
+type DrawdownGuardUpdateRepository interface {
+	Create(ctx context.Context, update *model.DrawdownGuardUpdate) error
+	// ListSince returns a position's guard updates from since on in
+	// chronological order, preceded by the last update before since, if any,
+	// so the trigger level at since is known
+	ListSince(ctx context.Context, positionID uuid.UUID, since time.Time) ([]*model.DrawdownGuardUpdate, error)
+}
@@ -0,0 +1,16 @@
+package repository
+
+// ErrNotFound is returned when the requested record doesn't exist
+var ErrNotFound = &RepositoryError{message: "record not found"}
+
+// ErrUnknownTable is returned when maintenance targets a table that isn't managed
+var ErrUnknownTable = &RepositoryError{message: "unknown table"}
+
+// RepositoryError represents a repository error
+type RepositoryError struct {
+	message string
+}
+
+func (e *RepositoryError) Error() string {
+	return e.message
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// TradingHaltRepository persists trading halts. The global halt is stored
+// under uuid.Nil.
+type TradingHaltRepository interface {
+	// Get returns the halt of a user, or the global halt for uuid.Nil, or ErrNotFound
+	Get(ctx context.Context, userID uuid.UUID) (*model.TradingHalt, error)
+	// Save creates or replaces a halt
+	Save(ctx context.Context, halt *model.TradingHalt) error
+	// Delete lifts a halt, returning ErrNotFound if there is none
+	Delete(ctx context.Context, userID uuid.UUID) error
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// JobQueueFilter selects queued jobs
+type JobQueueFilter struct {
+	Kind   string                // Any kind when empty
+	Status model.QueuedJobStatus // Any status when empty
+	UserID uuid.UUID             // Only this user's jobs unless uuid.Nil
+	Limit  int
+}
+
+// JobQueueRepository persists the durable job queue
+type JobQueueRepository interface {
+	Enqueue(ctx context.Context, job *model.QueuedJob) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.QueuedJob, error)
+	Update(ctx context.Context, job *model.QueuedJob) error
+	// Claim returns jobs of the given kinds due at now, oldest first, marks
+	// them running and leases them until leaseUntil. Running jobs whose lease
+	// expired are claimed again and marked interrupted.
+	Claim(ctx context.Context, kinds []string, now, leaseUntil time.Time, limit int) ([]*model.QueuedJob, error)
+	// List returns jobs, newest first
+	List(ctx context.Context, filter JobQueueFilter) ([]*model.QueuedJob, error)
+	// SaveResult stores a job's result, replacing one an earlier attempt saved
+	SaveResult(ctx context.Context, result *model.QueuedJobResult) error
+	GetResult(ctx context.Context, jobID uuid.UUID) (*model.QueuedJobResult, error)
+}
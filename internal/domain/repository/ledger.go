@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// CashLedgerRepository persists users' cash ledgers
+type CashLedgerRepository interface {
+	// Record stores an entry. An entry of the same user, type and reference
+	// that already exists is kept, so entries can be derived repeatedly.
+	Record(ctx context.Context, entry *model.CashLedgerEntry) error
+	// GetOpening returns a user's opening balance entry
+	GetOpening(ctx context.Context, userID uuid.UUID) (*model.CashLedgerEntry, error)
+	// ListByUser returns a user's entries that occurred in [from, to), newest first
+	ListByUser(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*model.CashLedgerEntry, error)
+	// Balance returns the sum of a user's entries
+	Balance(ctx context.Context, userID uuid.UUID) (float64, error)
+}
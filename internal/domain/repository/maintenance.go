@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// MaintenanceWindowRepository persists announced Upbit maintenance windows
+type MaintenanceWindowRepository interface {
+	Create(ctx context.Context, window *model.MaintenanceWindow) error
+	// Delete removes a window, returning ErrNotFound if there is none
+	Delete(ctx context.Context, id uuid.UUID) error
+	// ListEndingAfter returns the windows that end after the given time,
+	// earliest start first
+	ListEndingAfter(ctx context.Context, after time.Time) ([]*model.MaintenanceWindow, error)
+}
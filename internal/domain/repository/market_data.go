@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// TableSize reports the storage used by a market data table
+type TableSize struct {
+	Table            string `json:"table"`
+	Rows             uint64 `json:"rows"`
+	Partitions       uint64 `json:"partitions"`
+	BytesOnDisk      uint64 `json:"bytes_on_disk"`
+	UncompressedSize uint64 `json:"uncompressed_bytes"`
+}
+
+// MarketDataMaintenance manages retention of stored market data
+type MarketDataMaintenance interface {
+	TableSizes(ctx context.Context) ([]TableSize, error)
+	// Cleanup removes expired rows from a table right away instead of waiting
+	// for background merges. An empty table name cleans up every table.
+	Cleanup(ctx context.Context, table string) error
+}
+
+// RawMessageRepository stores raw websocket messages
+type RawMessageRepository interface {
+	SaveRawMessages(ctx context.Context, messages []model.RawMessage) error
+	// ListRawMessages returns the messages of the markets received in
+	// [from, to), oldest first. No markets lists every market.
+	ListRawMessages(ctx context.Context, markets []string, from, to time.Time) ([]model.RawMessage, error)
+}
+
+// CandleRepository stores candles
+type CandleRepository interface {
+	SaveCandles(ctx context.Context, candles []model.Candle) error
+	// GetLatestCandle returns ErrNotFound when no candle is stored
+	GetLatestCandle(ctx context.Context, market string, interval model.CandleInterval) (*model.Candle, error)
+	// ResampleCandles aggregates the stored 1m candles of [from, to) into
+	// candles of step, aligned to multiples of step since the Unix epoch,
+	// oldest first. Steps without 1m candles are left out.
+	ResampleCandles(ctx context.Context, market string, step time.Duration, from, to time.Time) ([]model.Candle, error)
+}
+
+// CandleStoreStatus reports whether candles reach their store
+type CandleStoreStatus struct {
+	Available  bool       `json:"available"`
+	DownSince  *time.Time `json:"down_since,omitempty"`
+	LastError  string     `json:"last_error,omitempty"`
+	Buffered   int        `json:"buffered"`    // Candles waiting to be written
+	BufferSize int        `json:"buffer_size"` // Most candles held while the store is down
+	Dropped    int64      `json:"dropped"`     // Candles discarded because the buffer was full
+}
+
+// CandleStoreMonitor reports the status of a candle store that keeps working
+// while its backend is down
+type CandleStoreMonitor interface {
+	Status() CandleStoreStatus
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// NotificationSettingsRepository persists users' notification preferences
+type NotificationSettingsRepository interface {
+	// Get returns the user's settings, or ErrNotFound if none were saved
+	Get(ctx context.Context, userID uuid.UUID) (*model.NotificationSettings, error)
+	// Save creates or replaces the user's settings
+	Save(ctx context.Context, settings *model.NotificationSettings) error
+	// ListDailySummaries returns the settings of users with daily summaries enabled
+	ListDailySummaries(ctx context.Context) ([]*model.NotificationSettings, error)
+}
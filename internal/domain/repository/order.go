@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// OrderRepository persists orders
+type OrderRepository interface {
+	Create(ctx context.Context, order *model.Order) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Order, error)
+	// GetCurrent retrieves an order by ID from the primary, for re-reading it
+	// after taking a lock on it when GetByID may lag behind
+	GetCurrent(ctx context.Context, id uuid.UUID) (*model.Order, error)
+	Update(ctx context.Context, order *model.Order) error
+	// Annotate replaces the user's tags and notes on an order
+	Annotate(ctx context.Context, id uuid.UUID, tags []string, notes string) error
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.Order, error)
+	// ListOpenByUser returns a user's pending, submitted and partially filled
+	// orders. Like the other queries the engine checks new orders against, it
+	// reads from the primary, so orders stored a moment ago are included.
+	ListOpenByUser(ctx context.Context, userID uuid.UUID) ([]*model.Order, error)
+	// ListOpenSellsByPosition returns the pending, submitted and partially
+	// filled sells of a position, from the primary
+	ListOpenSellsByPosition(ctx context.Context, positionID uuid.UUID) ([]*model.Order, error)
+	// CountCreatedSince returns how many orders a user created after since and
+	// when the earliest of them was created, from the primary
+	CountCreatedSince(ctx context.Context, userID uuid.UUID, since time.Time) (int, time.Time, error)
+	// ListOpen returns orders that were submitted to the exchange and are not yet final
+	ListOpen(ctx context.Context) ([]*model.Order, error)
+	// ListDue returns scheduled orders whose activation time is at or before
+	// now, soonest first
+	ListDue(ctx context.Context, now time.Time) ([]*model.Order, error)
+	// ListUnsubmitted returns pending orders without an exchange order ID
+	// that were last updated before the given time, oldest first
+	ListUnsubmitted(ctx context.Context, before time.Time) ([]*model.Order, error)
+}
+
+// OrderExecutionRepository persists order executions (fills)
+type OrderExecutionRepository interface {
+	Create(ctx context.Context, execution *model.OrderExecution) error
+	ListByOrder(ctx context.Context, orderID uuid.UUID) ([]*model.OrderExecution, error)
+}
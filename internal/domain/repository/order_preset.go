@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// OrderPresetRepository persists users' order presets
+type OrderPresetRepository interface {
+	// Save creates or replaces the user's preset of the same name. A replaced
+	// preset keeps its ID and creation time, which are set on preset.
+	Save(ctx context.Context, preset *model.OrderPreset) error
+	// GetByName returns the user's preset of a name, or ErrNotFound
+	GetByName(ctx context.Context, userID uuid.UUID, name string) (*model.OrderPreset, error)
+	// ListByUser returns a user's presets by name
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.OrderPreset, error)
+	// Delete removes the user's preset of a name, returning ErrNotFound if
+	// there is none
+	Delete(ctx context.Context, userID uuid.UUID, name string) error
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// OutboxRepository persists outbox events
+type OutboxRepository interface {
+	Create(ctx context.Context, event *model.OutboxEvent) error
+	// ListPending returns undispatched events, oldest first. Implementations lock
+	// the returned rows for the surrounding transaction so that concurrent
+	// dispatchers don't relay the same event at the same time.
+	ListPending(ctx context.Context, limit int) ([]*model.OutboxEvent, error)
+	MarkDispatched(ctx context.Context, id uuid.UUID) error
+	MarkFailed(ctx context.Context, id uuid.UUID, reason string) error
+}
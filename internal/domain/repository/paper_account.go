@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// PaperAccountRepository persists paper trading accounts, one per user
+type PaperAccountRepository interface {
+	Create(ctx context.Context, account *model.PaperAccount) error
+	GetByUser(ctx context.Context, userID uuid.UUID) (*model.PaperAccount, error)
+	GetByAccessKey(ctx context.Context, accessKey string) (*model.PaperAccount, error)
+	Update(ctx context.Context, account *model.PaperAccount) error
+}
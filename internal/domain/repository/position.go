@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// PositionRepository persists positions
+type PositionRepository interface {
+	Create(ctx context.Context, position *model.Position) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Position, error)
+	// GetCurrent retrieves a position by ID from the primary, for re-reading
+	// it after taking a lock on it when GetByID may lag behind
+	GetCurrent(ctx context.Context, id uuid.UUID) (*model.Position, error)
+	Update(ctx context.Context, position *model.Position) error
+	// Annotate replaces the user's tags and notes on a position
+	Annotate(ctx context.Context, id uuid.UUID, tags []string, notes string) error
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.Position, error)
+}
+
+// PositionEventRepository persists the lifecycle events of positions. Events
+// are never changed once recorded.
+type PositionEventRepository interface {
+	Create(ctx context.Context, event *model.PositionEvent) error
+	// ListByPosition returns a position's events in chronological order
+	ListByPosition(ctx context.Context, positionID uuid.UUID) ([]*model.PositionEvent, error)
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// TargetPortfolioRepository persists users' target portfolios
+type TargetPortfolioRepository interface {
+	// Get returns the target portfolio of a user, or ErrNotFound
+	Get(ctx context.Context, userID uuid.UUID) (*model.TargetPortfolio, error)
+	// Save creates or replaces a target portfolio
+	Save(ctx context.Context, target *model.TargetPortfolio) error
+	// Delete removes a target portfolio, returning ErrNotFound if there is none
+	Delete(ctx context.Context, userID uuid.UUID) error
+	// ListEnabled returns the target portfolios that rebalance automatically
+	ListEnabled(ctx context.Context) ([]*model.TargetPortfolio, error)
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// RecurringOrderRepository persists recurring orders and their runs
+type RecurringOrderRepository interface {
+	Create(ctx context.Context, order *model.RecurringOrder) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.RecurringOrder, error)
+	Update(ctx context.Context, order *model.RecurringOrder) error
+	// Delete removes a recurring order and its runs
+	Delete(ctx context.Context, id uuid.UUID) error
+	// ListByUser returns a user's recurring orders, oldest first
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.RecurringOrder, error)
+	// ListDue returns the active recurring orders whose next run is at or
+	// before now
+	ListDue(ctx context.Context, now time.Time) ([]*model.RecurringOrder, error)
+	// CreateRun records a run unless the recurring order already has one
+	// scheduled at the same time, and reports whether it did. Instances claim
+	// a run this way before placing its order.
+	CreateRun(ctx context.Context, run *model.RecurringOrderRun) (bool, error)
+	UpdateRun(ctx context.Context, run *model.RecurringOrderRun) error
+	// ListRuns returns a recurring order's runs, newest first
+	ListRuns(ctx context.Context, recurringOrderID uuid.UUID, limit int) ([]*model.RecurringOrderRun, error)
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// TradingRetentionRepository removes finished trading records past their
+// retention. Orders count as expired when they were cancelled or failed
+// without a fill before the cutoff; orders with fills are kept, as positions,
+// PnL and tax reports are built from them. Strategies are drawdown guards
+// that triggered before the cutoff.
+type TradingRetentionRepository interface {
+	// ExpireOrders archives or deletes up to limit expired orders and
+	// returns how many it removed
+	ExpireOrders(ctx context.Context, mode model.RetentionMode, cutoff time.Time, limit int) (int, error)
+	// ExpireStrategies archives or deletes up to limit expired drawdown
+	// guards and returns how many it removed
+	ExpireStrategies(ctx context.Context, mode model.RetentionMode, cutoff time.Time, limit int) (int, error)
+	// CountExpired returns how many orders and strategies have expired. A
+	// zero cutoff counts none of that kind.
+	CountExpired(ctx context.Context, ordersBefore, strategiesBefore time.Time) (orders, strategies int, err error)
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// RiskLimitsRepository persists users' risk limits
+type RiskLimitsRepository interface {
+	// Get returns the user's limits, or ErrNotFound if none were saved
+	Get(ctx context.Context, userID uuid.UUID) (*model.RiskLimits, error)
+	// Save creates or replaces the user's limits
+	Save(ctx context.Context, limits *model.RiskLimits) error
+	// ListDailyLossLimits returns the limits of users with a daily loss limit
+	ListDailyLossLimits(ctx context.Context) ([]*model.RiskLimits, error)
+}
+
+// RiskStateRepository persists users' daily PnL tracking
+type RiskStateRepository interface {
+	// Get returns the user's state, or ErrNotFound if none was saved
+	Get(ctx context.Context, userID uuid.UUID) (*model.RiskState, error)
+	// Save creates or replaces the user's state
+	Save(ctx context.Context, state *model.RiskState) error
+}
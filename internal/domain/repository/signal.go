@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// SignalSourceRepository persists signal sources
+type SignalSourceRepository interface {
+	Create(ctx context.Context, source *model.SignalSource) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.SignalSource, error)
+	// GetByTokenHash returns the webhook source whose token hashes to tokenHash
+	GetByTokenHash(ctx context.Context, tokenHash string) (*model.SignalSource, error)
+	Update(ctx context.Context, source *model.SignalSource) error
+	// Delete removes a source with its subscriptions and logs
+	Delete(ctx context.Context, id uuid.UUID) error
+	// ListByUser returns the sources a user owns, oldest first
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.SignalSource, error)
+	// ListPublic returns the active public sources, oldest first
+	ListPublic(ctx context.Context) ([]*model.SignalSource, error)
+	// ListActive returns the active sources of a type
+	ListActive(ctx context.Context, sourceType model.SignalSourceType) ([]*model.SignalSource, error)
+	// ListByTelegramChat returns the active sources reading a Telegram chat
+	ListByTelegramChat(ctx context.Context, chatID int64) ([]*model.SignalSource, error)
+}
+
+// SignalSubscriptionRepository persists users' subscriptions to signal sources
+type SignalSubscriptionRepository interface {
+	Create(ctx context.Context, subscription *model.SignalSubscription) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.SignalSubscription, error)
+	Update(ctx context.Context, subscription *model.SignalSubscription) error
+	// Delete removes a subscription; its logs are kept
+	Delete(ctx context.Context, id uuid.UUID) error
+	// ListByUser returns a user's subscriptions, oldest first
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.SignalSubscription, error)
+	// ListBySource returns the active subscriptions to a source
+	ListBySource(ctx context.Context, sourceID uuid.UUID) ([]*model.SignalSubscription, error)
+}
+
+// SignalLogRepository persists the log of received signals. Logs are never
+// changed once recorded.
+type SignalLogRepository interface {
+	Create(ctx context.Context, log *model.SignalLog) error
+	// ListBySource returns a source's logs, newest first
+	ListBySource(ctx context.Context, sourceID uuid.UUID, limit int) ([]*model.SignalLog, error)
+	// ListBySubscription returns a subscription's logs, newest first
+	ListBySubscription(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]*model.SignalLog, error)
+	// CountOrders returns how many orders a subscription placed on signals
+	// received at or after since
+	CountOrders(ctx context.Context, subscriptionID uuid.UUID, since time.Time) (int, error)
+}
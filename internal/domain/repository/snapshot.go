@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// SnapshotRepository persists account snapshots
+type SnapshotRepository interface {
+	// Create stores a snapshot. A snapshot for the same user, period and time
+	// that already exists is kept, so several instances may take snapshots.
+	Create(ctx context.Context, snapshot *model.AccountSnapshot) error
+	// ListByUser returns a user's snapshots taken in [from, to), oldest first
+	ListByUser(ctx context.Context, userID uuid.UUID, period model.SnapshotPeriod, from, to time.Time) ([]*model.AccountSnapshot, error)
+}
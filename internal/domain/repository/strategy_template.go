@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// StrategyTemplateFilter narrows a listing of strategy templates; empty
+// fields match everything
+type StrategyTemplateFilter struct {
+	Type        model.StrategyTemplateType
+	PublisherID uuid.UUID
+	Limit       int
+}
+
+// StrategyTemplateRepository persists the shared strategy template catalog
+type StrategyTemplateRepository interface {
+	Create(ctx context.Context, template *model.StrategyTemplate) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.StrategyTemplate, error)
+	// List returns templates most used first, then newest first
+	List(ctx context.Context, filter StrategyTemplateFilter) ([]*model.StrategyTemplate, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	// AddUsage adds n to a template's usage count
+	AddUsage(ctx context.Context, id uuid.UUID, n int) error
+}
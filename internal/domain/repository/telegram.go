@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// TelegramLinkRepository persists links between users and Telegram chats.
+// A user has at most one chat and a chat controls at most one user.
+type TelegramLinkRepository interface {
+	// Save stores a link, replacing any existing link of the user or the chat
+	Save(ctx context.Context, link *model.TelegramLink) error
+	GetByUserID(ctx context.Context, userID uuid.UUID) (*model.TelegramLink, error)
+	GetByChatID(ctx context.Context, chatID int64) (*model.TelegramLink, error)
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+)
+
+// Tx exposes repositories bound to a single database transaction
+type Tx interface {
+	Orders() OrderRepository
+	Executions() OrderExecutionRepository
+	Positions() PositionRepository
+	PositionEvents() PositionEventRepository
+	Outbox() OutboxRepository
+	Jobs() JobQueueRepository
+	WebhookDeliveries() WebhookDeliveryRepository
+	DrawdownGuards() DrawdownGuardRepository
+	DrawdownGuardUpdates() DrawdownGuardUpdateRepository
+}
+
+// UnitOfWork runs a set of repository operations atomically.
+// If fn returns an error the transaction is rolled back, otherwise it is committed.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(tx Tx) error) error
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// UserAPIKeyRepository persists users' Upbit API credentials
+type UserAPIKeyRepository interface {
+	Create(ctx context.Context, key *model.UserAPIKey) error
+	// ListByUser returns the user's API keys, newest first
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.UserAPIKey, error)
+	// SetActive activates one of the user's keys and deactivates the others
+	SetActive(ctx context.Context, userID, keyID uuid.UUID) error
+	// GetActiveByUserID returns the user's active API key
+	GetActiveByUserID(ctx context.Context, userID uuid.UUID) (*model.UserAPIKey, error)
+	// ListActiveUserIDs returns the users that have an active API key
+	ListActiveUserIDs(ctx context.Context) ([]uuid.UUID, error)
+	// SetExpiry records when a key expires
+	SetExpiry(ctx context.Context, keyID uuid.UUID, expiresAt time.Time) error
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// VelocityLimitRepository persists admin overrides of users' order velocity
+// limits
+type VelocityLimitRepository interface {
+	// Get returns the override of a user, or ErrNotFound
+	Get(ctx context.Context, userID uuid.UUID) (*model.VelocityLimits, error)
+	// Save creates or replaces an override
+	Save(ctx context.Context, limits *model.VelocityLimits) error
+	// Delete removes an override, returning ErrNotFound if there is none
+	Delete(ctx context.Context, userID uuid.UUID) error
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// WebhookRepository persists users' webhook endpoints
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *model.Webhook) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Webhook, error)
+	Update(ctx context.Context, webhook *model.Webhook) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// ListByUser returns the user's webhooks, newest first
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.Webhook, error)
+}
+
+// WebhookDeliveryFilter selects deliveries of a webhook
+type WebhookDeliveryFilter struct {
+	Status model.WebhookDeliveryStatus // Any status when empty
+	Limit  int
+}
+
+// WebhookDeliveryRepository persists webhook deliveries
+type WebhookDeliveryRepository interface {
+	Create(ctx context.Context, delivery *model.WebhookDelivery) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.WebhookDelivery, error)
+	Update(ctx context.Context, delivery *model.WebhookDelivery) error
+	// ListByWebhook returns a webhook's deliveries, newest first
+	ListByWebhook(ctx context.Context, webhookID uuid.UUID, filter WebhookDeliveryFilter) ([]*model.WebhookDelivery, error)
+}
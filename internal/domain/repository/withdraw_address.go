@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// WithdrawAddressRepository persists users' withdrawal address books
+type WithdrawAddressRepository interface {
+	Create(ctx context.Context, address *model.WithdrawAddress) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.WithdrawAddress, error)
+	Update(ctx context.Context, address *model.WithdrawAddress) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// ListByUser returns the user's addresses, oldest first
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.WithdrawAddress, error)
+}
@@ -0,0 +1,162 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	ch "github.com/sungminna/upbit-trading-platform/pkg/database/clickhouse"
+)
+
+// CandleRepository stores candles in the candles table. Timestamps are
+// exchanged as Unix seconds so the server's timezone doesn't matter.
+type CandleRepository struct {
+	conn *ch.Conn
+}
+
+var _ repository.CandleRepository = (*CandleRepository)(nil)
+
+// candleRow is a candles row
+type candleRow struct {
+	Market           string  `json:"market"`
+	Interval         string  `json:"interval"`
+	Timestamp        int64   `json:"timestamp"`
+	OpenPrice        float64 `json:"opening_price"`
+	HighPrice        float64 `json:"high_price"`
+	LowPrice         float64 `json:"low_price"`
+	ClosePrice       float64 `json:"trade_price"`
+	Volume           float64 `json:"candle_acc_trade_volume"`
+	AccTradePrice    float64 `json:"candle_acc_trade_price"`
+	PrevClosingPrice float64 `json:"prev_closing_price"`
+	Change           string  `json:"change"`
+	ChangePrice      float64 `json:"change_price"`
+	ChangeRate       float64 `json:"change_rate"`
+}
+
+func (r candleRow) candle() model.Candle {
+	return model.Candle{
+		Market:           r.Market,
+		Interval:         model.CandleInterval(r.Interval),
+		Timestamp:        time.Unix(r.Timestamp, 0).UTC(),
+		OpenPrice:        r.OpenPrice,
+		HighPrice:        r.HighPrice,
+		LowPrice:         r.LowPrice,
+		ClosePrice:       r.ClosePrice,
+		Volume:           r.Volume,
+		AccTradePrice:    r.AccTradePrice,
+		PrevClosingPrice: r.PrevClosingPrice,
+		Change:           r.Change,
+		ChangePrice:      r.ChangePrice,
+		ChangeRate:       r.ChangeRate,
+	}
+}
+
+// NewCandleRepository creates a new candle repository
+func NewCandleRepository(conn *ch.Conn) *CandleRepository {
+	return &CandleRepository{conn: conn}
+}
+
+// SaveCandles inserts candles. Candles collected twice are deduplicated when
+// read.
+func (r *CandleRepository) SaveCandles(ctx context.Context, candles []model.Candle) error {
+	rows := make([]any, len(candles))
+	for i, c := range candles {
+		rows[i] = candleRow{
+			Market:           c.Market,
+			Interval:         string(c.Interval),
+			Timestamp:        c.Timestamp.Unix(),
+			OpenPrice:        c.OpenPrice,
+			HighPrice:        c.HighPrice,
+			LowPrice:         c.LowPrice,
+			ClosePrice:       c.ClosePrice,
+			Volume:           c.Volume,
+			AccTradePrice:    c.AccTradePrice,
+			PrevClosingPrice: c.PrevClosingPrice,
+			Change:           c.Change,
+			ChangePrice:      c.ChangePrice,
+			ChangeRate:       c.ChangeRate,
+		}
+	}
+
+	if err := r.conn.Insert(ctx, "candles", rows); err != nil {
+		return fmt.Errorf("failed to save candles: %w", err)
+	}
+	return nil
+}
+
+// GetLatestCandle returns the most recent stored candle of a market and interval
+func (r *CandleRepository) GetLatestCandle(ctx context.Context, market string, interval model.CandleInterval) (*model.Candle, error) {
+	query := `SELECT
+		market, interval, toUnixTimestamp(timestamp) AS timestamp,
+		opening_price, high_price, low_price, trade_price,
+		candle_acc_trade_volume, candle_acc_trade_price,
+		prev_closing_price, change, change_price, change_rate
+	FROM candles
+	WHERE market = {market:String} AND interval = {interval:String}
+	ORDER BY timestamp DESC, created_at DESC
+	LIMIT 1`
+
+	var rows []candleRow
+	err := r.conn.Query(ctx, &rows, query, map[string]any{"market": market, "interval": string(interval)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest candle: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, repository.ErrNotFound
+	}
+
+	candle := rows[0].candle()
+	return &candle, nil
+}
+
+// ResampleCandles aggregates stored 1m candles into candles of step. Opens
+// and closes come from the first and last minute of each step, and the
+// latest copy of a minute collected more than once is used.
+func (r *CandleRepository) ResampleCandles(ctx context.Context, market string, step time.Duration, from, to time.Time) ([]model.Candle, error) {
+	seconds := int64(step / time.Second)
+	if seconds < 60 || step%time.Minute != 0 {
+		return nil, fmt.Errorf("invalid resample step %s: use whole minutes", step)
+	}
+
+	query := `SELECT
+		market,
+		intDiv(ts, {step:UInt32}) * {step:UInt32} AS timestamp,
+		argMin(opening_price, ts) AS opening_price,
+		max(high_price) AS high_price,
+		min(low_price) AS low_price,
+		argMax(trade_price, ts) AS trade_price,
+		sum(candle_acc_trade_volume) AS candle_acc_trade_volume,
+		sum(candle_acc_trade_price) AS candle_acc_trade_price
+	FROM (
+		SELECT market, toUnixTimestamp(timestamp) AS ts, opening_price, high_price, low_price,
+			trade_price, candle_acc_trade_volume, candle_acc_trade_price
+		FROM candles
+		WHERE market = {market:String} AND interval = '1m'
+			AND timestamp >= toDateTime({from:Int64}) AND timestamp < toDateTime({to:Int64})
+		ORDER BY ts, created_at DESC
+		LIMIT 1 BY ts
+	)
+	GROUP BY market, timestamp
+	ORDER BY timestamp`
+
+	var rows []candleRow
+	err := r.conn.Query(ctx, &rows, query, map[string]any{
+		"market": market,
+		"step":   seconds,
+		"from":   from.Unix(),
+		"to":     to.Unix(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to resample candles: %w", err)
+	}
+
+	interval := model.ResampleInterval(step)
+	candles := make([]model.Candle, len(rows))
+	for i, row := range rows {
+		candles[i] = row.candle()
+		candles[i].Interval = interval
+	}
+	return candles, nil
+}
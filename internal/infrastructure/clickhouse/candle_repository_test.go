@@ -0,0 +1,58 @@
+package clickhouse
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	ch "github.com/sungminna/upbit-trading-platform/pkg/database/clickhouse"
+)
+
+func TestCandleRepository_ResampleCandles(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), "FROM candles") {
+			return // Ping
+		}
+
+		assert.Equal(t, "2700", r.URL.Query().Get("param_step"))
+		assert.Equal(t, "KRW-BTC", r.URL.Query().Get("param_market"))
+		assert.Equal(t, "1735689600", r.URL.Query().Get("param_from"))
+		w.Write([]byte(`{"data":[{"market":"KRW-BTC","timestamp":1735689600,"opening_price":100,"high_price":110,"low_price":95,"trade_price":105,"candle_acc_trade_volume":3,"candle_acc_trade_price":310}]}`))
+	}))
+	defer server.Close()
+
+	conn, err := ch.NewConn(context.Background(), ch.DefaultConfig(server.URL))
+	require.NoError(t, err)
+
+	candles, err := NewCandleRepository(conn).ResampleCandles(context.Background(), "KRW-BTC", 45*time.Minute, from, from.Add(24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, candles, 1)
+	assert.Equal(t, model.CandleInterval("45m"), candles[0].Interval)
+	assert.Equal(t, from, candles[0].Timestamp)
+	assert.Equal(t, 105.0, candles[0].ClosePrice)
+
+	_, err = NewCandleRepository(conn).ResampleCandles(context.Background(), "KRW-BTC", 90*time.Second, from, from.Add(time.Hour))
+	assert.Error(t, err)
+}
+
+func TestParseResampleStep(t *testing.T) {
+	for input, expected := range map[string]time.Duration{"45m": 45 * time.Minute, "6h": 6 * time.Hour, "3d": 72 * time.Hour} {
+		step, err := model.ParseResampleStep(model.CandleInterval(input))
+		require.NoError(t, err, input)
+		assert.Equal(t, expected, step)
+		assert.Equal(t, model.CandleInterval(input), model.ResampleInterval(step))
+	}
+	for _, input := range []string{"0m", "1.5h", "31d", "2x", ""} {
+		_, err := model.ParseResampleStep(model.CandleInterval(input))
+		assert.Error(t, err, input)
+	}
+}
@@ -0,0 +1,396 @@
+package clickhouse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	ch "github.com/sungminna/upbit-trading-platform/pkg/database/clickhouse"
+	"github.com/sungminna/upbit-trading-platform/pkg/recovery"
+)
+
+const (
+	// DefaultCandleBuffer is how many candles are held while ClickHouse is down
+	DefaultCandleBuffer = 100_000
+	// healthCheckInterval is how often ClickHouse is probed while down, and
+	// buffered candles are written once it is back
+	healthCheckInterval = 5 * time.Second
+	// flushBatch is the most buffered candles written per insert
+	flushBatch = 10_000
+	// passthroughLimit is the most Upbit candles fetched for one resample
+	// while ClickHouse is down
+	passthroughLimit = 10_000
+)
+
+// ErrUnavailable is returned for reads only ClickHouse can serve while it is down
+var ErrUnavailable = errors.New("clickhouse is unavailable")
+
+// passthroughIntervals are the Upbit candle intervals resamples fall back to,
+// longest first. Each is aligned to multiples of itself since the Unix epoch.
+var passthroughIntervals = []model.CandleInterval{
+	model.CandleInterval1d,
+	model.CandleInterval4h,
+	model.CandleInterval1h,
+	model.CandleInterval30m,
+	model.CandleInterval15m,
+	model.CandleInterval5m,
+	model.CandleInterval3m,
+	model.CandleInterval1m,
+}
+
+// CandleSource provides candles from Upbit; gateway.QuotationAPI satisfies it
+type CandleSource interface {
+	GetCandleRange(ctx context.Context, market string, interval model.CandleInterval, from, to time.Time) ([]model.Candle, error)
+}
+
+// FallbackCandleRepository stores candles in ClickHouse and keeps working
+// while it is down: writes are buffered in memory, up to a bound past which
+// the oldest candles are dropped, and resamples are computed from Upbit
+// candles. Once ClickHouse answers again the buffer is written and reads
+// return to it.
+type FallbackCandleRepository struct {
+	store      *CandleRepository
+	ping       func(ctx context.Context) error
+	upbit      CandleSource
+	bufferSize int
+
+	mu        sync.Mutex
+	downSince *time.Time
+	lastError string
+	buffer    []model.Candle // Oldest first
+	dropped   int64
+	flushMu   sync.Mutex // Keeps buffered candles in order across flushes
+
+	stopChan chan struct{}
+	done     chan struct{}
+}
+
+var (
+	_ repository.CandleRepository   = (*FallbackCandleRepository)(nil)
+	_ repository.CandleStoreMonitor = (*FallbackCandleRepository)(nil)
+)
+
+// NewFallbackCandleRepository creates a candle repository that falls back to
+// a bounded buffer and Upbit while ClickHouse is down
+func NewFallbackCandleRepository(conn *ch.Conn, upbit CandleSource, bufferSize int) *FallbackCandleRepository {
+	if bufferSize <= 0 {
+		bufferSize = DefaultCandleBuffer
+	}
+	return &FallbackCandleRepository{
+		store:      NewCandleRepository(conn),
+		ping:       conn.Ping,
+		upbit:      upbit,
+		bufferSize: bufferSize,
+	}
+}
+
+// SaveCandles inserts candles, or buffers them while ClickHouse is down or
+// earlier candles are still waiting to be written
+func (r *FallbackCandleRepository) SaveCandles(ctx context.Context, candles []model.Candle) error {
+	r.mu.Lock()
+	if r.downSince != nil || len(r.buffer) > 0 {
+		r.bufferLocked(candles)
+		r.mu.Unlock()
+		return nil
+	}
+	r.mu.Unlock()
+
+	err := r.store.SaveCandles(ctx, candles)
+	if !isOutage(err) {
+		return err
+	}
+	r.markDown(err)
+
+	r.mu.Lock()
+	r.bufferLocked(candles)
+	r.mu.Unlock()
+	return nil
+}
+
+// GetLatestCandle returns the most recent candle of a market and interval,
+// buffered ones included. While ClickHouse is down and no candle of the
+// market is buffered it returns ErrUnavailable.
+func (r *FallbackCandleRepository) GetLatestCandle(ctx context.Context, market string, interval model.CandleInterval) (*model.Candle, error) {
+	r.mu.Lock()
+	down := r.downSince != nil
+	var latest *model.Candle
+	for i := len(r.buffer) - 1; i >= 0; i-- {
+		c := r.buffer[i]
+		if c.Market == market && c.Interval == interval && (latest == nil || c.Timestamp.After(latest.Timestamp)) {
+			latest = &c
+		}
+	}
+	r.mu.Unlock()
+
+	// Buffered candles are newer than any stored one
+	if latest != nil {
+		return latest, nil
+	}
+	if down {
+		return nil, ErrUnavailable
+	}
+
+	candle, err := r.store.GetLatestCandle(ctx, market, interval)
+	if isOutage(err) {
+		r.markDown(err)
+		return nil, ErrUnavailable
+	}
+	return candle, err
+}
+
+// ResampleCandles aggregates stored 1m candles into candles of step. While
+// ClickHouse is down the candles are resampled from Upbit instead.
+func (r *FallbackCandleRepository) ResampleCandles(ctx context.Context, market string, step time.Duration, from, to time.Time) ([]model.Candle, error) {
+	if r.Available() {
+		candles, err := r.store.ResampleCandles(ctx, market, step, from, to)
+		if !isOutage(err) {
+			return candles, err
+		}
+		r.markDown(err)
+	}
+	return r.resampleUpbit(ctx, market, step, from, to)
+}
+
+// Available reports whether ClickHouse is reachable
+func (r *FallbackCandleRepository) Available() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.downSince == nil
+}
+
+// Status reports whether ClickHouse is reachable and how many candles wait
+// to be written
+func (r *FallbackCandleRepository) Status() repository.CandleStoreStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return repository.CandleStoreStatus{
+		Available:  r.downSince == nil,
+		DownSince:  r.downSince,
+		LastError:  r.lastError,
+		Buffered:   len(r.buffer),
+		BufferSize: r.bufferSize,
+		Dropped:    r.dropped,
+	}
+}
+
+// Start probes ClickHouse now and then every few seconds, writing buffered
+// candles once it is reachable
+func (r *FallbackCandleRepository) Start(ctx context.Context) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stopChan != nil {
+		return
+	}
+	r.stopChan = make(chan struct{})
+	r.done = make(chan struct{})
+
+	go r.run(ctx, r.stopChan, r.done)
+}
+
+// Stop stops probing; candles still buffered are lost
+func (r *FallbackCandleRepository) Stop() {
+	r.mu.Lock()
+	if r.stopChan == nil {
+		r.mu.Unlock()
+		return
+	}
+	close(r.stopChan)
+	r.stopChan = nil
+	done := r.done
+	r.mu.Unlock()
+
+	<-done
+}
+
+func (r *FallbackCandleRepository) run(ctx context.Context, stopChan <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		recovery.Do("clickhouse.health-check", func() { r.check(ctx) })
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-stopChan:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// check probes ClickHouse while it is down and writes buffered candles once
+// it is up
+func (r *FallbackCandleRepository) check(ctx context.Context) {
+	r.mu.Lock()
+	down := r.downSince != nil
+	r.mu.Unlock()
+
+	if err := r.ping(ctx); err != nil {
+		r.markDown(err)
+		return
+	}
+	if down {
+		r.mu.Lock()
+		log.Printf("ClickHouse is available again after %s; writing %d buffered candles", time.Since(*r.downSince).Round(time.Second), len(r.buffer))
+		r.downSince = nil
+		r.lastError = ""
+		r.mu.Unlock()
+	}
+	r.flush(ctx)
+}
+
+// flush writes buffered candles, oldest first, until the buffer is empty or
+// ClickHouse fails
+func (r *FallbackCandleRepository) flush(ctx context.Context) {
+	r.flushMu.Lock()
+	defer r.flushMu.Unlock()
+
+	for {
+		r.mu.Lock()
+		n := min(len(r.buffer), flushBatch)
+		if n == 0 || r.downSince != nil {
+			r.mu.Unlock()
+			return
+		}
+		batch := r.buffer[:n:n]
+		r.buffer = r.buffer[n:]
+		r.mu.Unlock()
+
+		if err := r.store.SaveCandles(ctx, batch); err != nil {
+			log.Printf("Error writing %d buffered candles: %v", len(batch), err)
+			if !isOutage(err) {
+				// ClickHouse rejected the candles; retrying won't help
+				r.mu.Lock()
+				r.dropped += int64(len(batch))
+				r.mu.Unlock()
+				continue
+			}
+			r.markDown(err)
+
+			// Put the batch back in front of whatever arrived meanwhile
+			r.mu.Lock()
+			r.buffer = append(batch, r.buffer...)
+			r.trimLocked()
+			r.mu.Unlock()
+			return
+		}
+	}
+}
+
+// markDown records that ClickHouse failed
+func (r *FallbackCandleRepository) markDown(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastError = err.Error()
+	if r.downSince == nil {
+		now := time.Now()
+		r.downSince = &now
+		log.Printf("ClickHouse is unavailable, buffering candle writes and reading candles from Upbit: %v", err)
+	}
+}
+
+// bufferLocked appends candles to the buffer; r.mu must be held
+func (r *FallbackCandleRepository) bufferLocked(candles []model.Candle) {
+	r.buffer = append(r.buffer, candles...)
+	r.trimLocked()
+}
+
+// trimLocked drops the oldest buffered candles beyond the buffer size; r.mu
+// must be held
+func (r *FallbackCandleRepository) trimLocked() {
+	excess := len(r.buffer) - r.bufferSize
+	if excess <= 0 {
+		return
+	}
+	if r.dropped == 0 {
+		log.Printf("Candle buffer is full (%d candles); dropping the oldest", r.bufferSize)
+	}
+	r.buffer = append(r.buffer[:0:0], r.buffer[excess:]...)
+	r.dropped += int64(excess)
+}
+
+// resampleUpbit aggregates Upbit candles of the longest interval dividing
+// step into candles of step, aligned like the stored resamples
+func (r *FallbackCandleRepository) resampleUpbit(ctx context.Context, market string, step time.Duration, from, to time.Time) ([]model.Candle, error) {
+	if step < time.Minute || step%time.Minute != 0 {
+		return nil, fmt.Errorf("invalid resample step %s: use whole minutes", step)
+	}
+
+	var interval model.CandleInterval
+	for _, i := range passthroughIntervals {
+		if step%i.Duration() == 0 {
+			interval = i
+			break
+		}
+	}
+	if n := to.Sub(from) / interval.Duration(); n > passthroughLimit {
+		return nil, fmt.Errorf("%w: range needs %d %s candles from Upbit, more than %d", ErrUnavailable, n, interval, passthroughLimit)
+	}
+
+	// GetCandleRange leaves out the candle starting at from
+	source, err := r.upbit.GetCandleRange(ctx, market, interval, from.Add(-time.Second), to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get candles from Upbit: %w", err)
+	}
+	sort.Slice(source, func(i, j int) bool {
+		return source[i].Timestamp.Before(source[j].Timestamp)
+	})
+
+	resampled := model.ResampleInterval(step)
+	var candles []model.Candle
+	for _, c := range source {
+		if c.Timestamp.Before(from) || !c.Timestamp.Before(to) {
+			continue
+		}
+		start := time.Unix(0, 0).Add(c.Timestamp.Sub(time.Unix(0, 0)).Truncate(step)).UTC()
+		if n := len(candles); n > 0 && candles[n-1].Timestamp.Equal(start) {
+			last := &candles[n-1]
+			last.HighPrice = max(last.HighPrice, c.HighPrice)
+			last.LowPrice = min(last.LowPrice, c.LowPrice)
+			last.ClosePrice = c.ClosePrice
+			last.Volume += c.Volume
+			last.AccTradePrice += c.AccTradePrice
+			continue
+		}
+		candles = append(candles, model.Candle{
+			Market:        market,
+			Interval:      resampled,
+			Timestamp:     start,
+			OpenPrice:     c.OpenPrice,
+			HighPrice:     c.HighPrice,
+			LowPrice:      c.LowPrice,
+			ClosePrice:    c.ClosePrice,
+			Volume:        c.Volume,
+			AccTradePrice: c.AccTradePrice,
+		})
+	}
+	return candles, nil
+}
+
+// isOutage reports whether an error means ClickHouse couldn't be reached, as
+// opposed to it rejecting a query
+func isOutage(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var chErr *ch.Error
+	if errors.As(err, &chErr) {
+		return chErr.StatusCode == http.StatusBadGateway || chErr.StatusCode == http.StatusServiceUnavailable ||
+			chErr.StatusCode == http.StatusGatewayTimeout
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
@@ -0,0 +1,101 @@
+package clickhouse
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	ch "github.com/sungminna/upbit-trading-platform/pkg/database/clickhouse"
+)
+
+type stubCandleSource struct {
+	interval model.CandleInterval
+	candles  []model.Candle
+}
+
+func (s *stubCandleSource) GetCandleRange(ctx context.Context, market string, interval model.CandleInterval, from, to time.Time) ([]model.Candle, error) {
+	s.interval = interval
+	return s.candles, nil
+}
+
+func TestFallbackCandleRepository(t *testing.T) {
+	ctx := context.Background()
+	var down atomic.Bool
+	var inserted atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			panic(http.ErrAbortHandler) // Drop the connection
+		}
+		body, _ := io.ReadAll(r.Body)
+		if strings.HasPrefix(string(body), "INSERT INTO candles") {
+			inserted.Add(int64(strings.Count(string(body), "\n") - 1))
+		}
+	}))
+	defer server.Close()
+
+	conn, err := ch.Open(ch.DefaultConfig(server.URL))
+	require.NoError(t, err)
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	upbit := &stubCandleSource{
+		// Newest first, as Upbit returns them
+		candles: []model.Candle{
+			{Timestamp: start.Add(10 * time.Minute), OpenPrice: 104, HighPrice: 106, LowPrice: 103, ClosePrice: 105, Volume: 1},
+			{Timestamp: start.Add(5 * time.Minute), OpenPrice: 102, HighPrice: 108, LowPrice: 101, ClosePrice: 104, Volume: 2},
+			{Timestamp: start, OpenPrice: 100, HighPrice: 103, LowPrice: 99, ClosePrice: 102, Volume: 3},
+		},
+	}
+	repo := NewFallbackCandleRepository(conn, upbit, 2)
+
+	down.Store(true)
+	candles := []model.Candle{
+		{Market: "KRW-BTC", Interval: model.CandleInterval1m, Timestamp: start},
+		{Market: "KRW-BTC", Interval: model.CandleInterval1m, Timestamp: start.Add(time.Minute)},
+		{Market: "KRW-BTC", Interval: model.CandleInterval1m, Timestamp: start.Add(2 * time.Minute)},
+	}
+	require.NoError(t, repo.SaveCandles(ctx, candles), "writes are buffered while down")
+
+	status := repo.Status()
+	assert.False(t, status.Available)
+	assert.NotNil(t, status.DownSince)
+	assert.Equal(t, 2, status.Buffered)
+	assert.Equal(t, int64(1), status.Dropped, "the oldest candle is dropped")
+
+	latest, err := repo.GetLatestCandle(ctx, "KRW-BTC", model.CandleInterval1m)
+	require.NoError(t, err)
+	assert.Equal(t, start.Add(2*time.Minute), latest.Timestamp)
+	_, err = repo.GetLatestCandle(ctx, "KRW-ETH", model.CandleInterval1m)
+	assert.ErrorIs(t, err, ErrUnavailable)
+
+	resampled, err := repo.ResampleCandles(ctx, "KRW-BTC", 10*time.Minute, start, start.Add(20*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, model.CandleInterval5m, upbit.interval)
+	require.Len(t, resampled, 2)
+	assert.Equal(t, model.Candle{
+		Market: "KRW-BTC", Interval: "10m", Timestamp: start,
+		OpenPrice: 100, HighPrice: 108, LowPrice: 99, ClosePrice: 104, Volume: 5,
+	}, resampled[0])
+	assert.Equal(t, start.Add(10*time.Minute), resampled[1].Timestamp)
+
+	repo.check(ctx)
+	assert.False(t, repo.Available(), "still down")
+
+	down.Store(false)
+	repo.check(ctx)
+	status = repo.Status()
+	assert.True(t, status.Available)
+	assert.Nil(t, status.DownSince)
+	assert.Zero(t, status.Buffered)
+	assert.Equal(t, int64(2), inserted.Load(), "buffered candles are written on recovery")
+
+	require.NoError(t, repo.SaveCandles(ctx, candles[:1]))
+	assert.Equal(t, int64(3), inserted.Load(), "writes go straight to ClickHouse again")
+}
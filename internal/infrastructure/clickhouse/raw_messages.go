@@ -0,0 +1,94 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	ch "github.com/sungminna/upbit-trading-platform/pkg/database/clickhouse"
+)
+
+// rawMessageTimeFormat is how received_at values, in UTC, are written
+const rawMessageTimeFormat = "2006-01-02 15:04:05.000"
+
+// RawMessageRepository stores raw websocket messages in the
+// websocket_messages table
+type RawMessageRepository struct {
+	conn *ch.Conn
+}
+
+var _ repository.RawMessageRepository = (*RawMessageRepository)(nil)
+
+// rawMessageRow is a websocket_messages row
+type rawMessageRow struct {
+	ReceivedAt string `json:"received_at"`
+	Type       string `json:"type"`
+	Market     string `json:"market"`
+	Payload    string `json:"payload"`
+}
+
+// NewRawMessageRepository creates a new raw message repository
+func NewRawMessageRepository(conn *ch.Conn) *RawMessageRepository {
+	return &RawMessageRepository{conn: conn}
+}
+
+// SaveRawMessages inserts a batch of messages
+func (r *RawMessageRepository) SaveRawMessages(ctx context.Context, messages []model.RawMessage) error {
+	rows := make([]any, len(messages))
+	for i, msg := range messages {
+		rows[i] = rawMessageRow{
+			ReceivedAt: msg.ReceivedAt.UTC().Format(rawMessageTimeFormat),
+			Type:       msg.Type,
+			Market:     msg.Market,
+			Payload:    string(msg.Payload),
+		}
+	}
+
+	if err := r.conn.Insert(ctx, "websocket_messages", rows); err != nil {
+		return fmt.Errorf("failed to save raw messages: %w", err)
+	}
+	return nil
+}
+
+// ListRawMessages returns the messages of the markets received in [from, to),
+// oldest first
+func (r *RawMessageRepository) ListRawMessages(ctx context.Context, markets []string, from, to time.Time) ([]model.RawMessage, error) {
+	query := `SELECT
+		toUnixTimestamp64Milli(received_at) AS received_at_ms,
+		type, market, payload
+	FROM websocket_messages
+	WHERE received_at >= {from:DateTime64(3)} AND received_at < {to:DateTime64(3)}`
+	params := map[string]any{
+		"from": from.UTC().Format(rawMessageTimeFormat),
+		"to":   to.UTC().Format(rawMessageTimeFormat),
+	}
+	if len(markets) > 0 {
+		query += " AND has({markets:Array(String)}, market)"
+		params["markets"] = arrayParam(markets)
+	}
+	query += " ORDER BY received_at"
+
+	var rows []struct {
+		ReceivedAtMs int64  `json:"received_at_ms"`
+		Type         string `json:"type"`
+		Market       string `json:"market"`
+		Payload      string `json:"payload"`
+	}
+	if err := r.conn.Query(ctx, &rows, query, params); err != nil {
+		return nil, fmt.Errorf("failed to list raw messages: %w", err)
+	}
+
+	messages := make([]model.RawMessage, 0, len(rows))
+	for _, row := range rows {
+		messages = append(messages, model.RawMessage{
+			ReceivedAt: time.UnixMilli(row.ReceivedAtMs).UTC(),
+			Type:       row.Type,
+			Market:     row.Market,
+			Payload:    []byte(row.Payload),
+		})
+	}
+
+	return messages, nil
+}
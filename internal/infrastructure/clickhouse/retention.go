@@ -0,0 +1,267 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	ch "github.com/sungminna/upbit-trading-platform/pkg/database/clickhouse"
+)
+
+// RetentionPolicy configures how long market data is kept. A zero duration
+// keeps data forever.
+type RetentionPolicy struct {
+	Candles    map[model.CandleInterval]time.Duration // Missing intervals are kept forever
+	Ticks      time.Duration
+	Orderbooks time.Duration
+	Tickers    time.Duration
+	Messages   time.Duration // Raw websocket messages
+}
+
+// DefaultRetentionPolicy keeps fine-grained data for less time than coarse data
+func DefaultRetentionPolicy() RetentionPolicy {
+	day := 24 * time.Hour
+	return RetentionPolicy{
+		Candles: map[model.CandleInterval]time.Duration{
+			model.CandleInterval1s:  7 * day,
+			model.CandleInterval1m:  30 * day,
+			model.CandleInterval3m:  90 * day,
+			model.CandleInterval5m:  90 * day,
+			model.CandleInterval15m: 180 * day,
+			model.CandleInterval30m: 180 * day,
+			model.CandleInterval1h:  730 * day,
+			model.CandleInterval4h:  730 * day,
+		},
+		Ticks:      7 * day,
+		Orderbooks: 3 * day,
+		Tickers:    30 * day,
+		Messages:   3 * day,
+	}
+}
+
+// Maintenance applies retention to the market data tables
+type Maintenance struct {
+	conn *ch.Conn
+}
+
+var _ repository.MarketDataMaintenance = (*Maintenance)(nil)
+
+// managedTables are the tables whose retention is managed, in report order
+var managedTables = []string{"candles", "ticks", "orderbook_snapshots", "tickers", "websocket_messages"}
+
+// NewMaintenance creates a new market data maintenance service
+func NewMaintenance(conn *ch.Conn) *Maintenance {
+	return &Maintenance{conn: conn}
+}
+
+// ApplyRetention sets the TTL of every managed table from the policy.
+// Existing rows that have expired are removed by the next merges.
+func (m *Maintenance) ApplyRetention(ctx context.Context, policy RetentionPolicy) error {
+	for _, table := range managedTables {
+		ttl := policy.ttlExpression(table)
+
+		query := fmt.Sprintf("ALTER TABLE %s REMOVE TTL", table)
+		if ttl != "" {
+			query = fmt.Sprintf("ALTER TABLE %s MODIFY TTL %s", table, ttl)
+		}
+
+		if err := m.conn.Exec(ctx, query, nil); err != nil {
+			return fmt.Errorf("failed to apply retention to %s: %w", table, err)
+		}
+	}
+
+	return nil
+}
+
+// TableSizes reports row counts and disk usage of the managed tables
+func (m *Maintenance) TableSizes(ctx context.Context) ([]repository.TableSize, error) {
+	var rows []repository.TableSize
+	query := `SELECT
+		table,
+		sum(rows) AS rows,
+		uniqExact(partition) AS partitions,
+		sum(bytes_on_disk) AS bytes_on_disk,
+		sum(data_uncompressed_bytes) AS uncompressed_bytes
+	FROM system.parts
+	WHERE active AND database = currentDatabase() AND has({tables:Array(String)}, table)
+	GROUP BY table`
+
+	err := m.conn.Query(ctx, &rows, query, map[string]any{"tables": arrayParam(managedTables)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query table sizes: %w", err)
+	}
+
+	// Report empty tables too, in a stable order
+	byTable := make(map[string]repository.TableSize, len(rows))
+	for _, row := range rows {
+		byTable[row.Table] = row
+	}
+
+	sizes := make([]repository.TableSize, 0, len(managedTables))
+	for _, table := range managedTables {
+		size, ok := byTable[table]
+		if !ok {
+			size = repository.TableSize{Table: table}
+		}
+		sizes = append(sizes, size)
+	}
+
+	return sizes, nil
+}
+
+// Cleanup rewrites a table's parts to drop expired rows now. ClickHouse runs
+// the rewrite in the background, so the space is released shortly after.
+func (m *Maintenance) Cleanup(ctx context.Context, table string) error {
+	tables := managedTables
+	if table != "" {
+		if !isManagedTable(table) {
+			return repository.ErrUnknownTable
+		}
+		tables = []string{table}
+	}
+
+	for _, t := range tables {
+		if err := m.conn.Exec(ctx, fmt.Sprintf("ALTER TABLE %s MATERIALIZE TTL", t), nil); err != nil {
+			return fmt.Errorf("failed to clean up %s: %w", t, err)
+		}
+	}
+
+	return nil
+}
+
+// ttlExpression returns the TTL clause for a table, or "" to keep rows forever
+func (p RetentionPolicy) ttlExpression(table string) string {
+	// Tick-level tables store epoch milliseconds
+	msTime := "toDateTime(intDiv(timestamp, 1000))"
+
+	switch table {
+	case "candles":
+		return p.candleTTLExpression()
+	case "ticks":
+		return ttlClause(msTime, p.Ticks)
+	case "orderbook_snapshots":
+		return ttlClause(msTime, p.Orderbooks)
+	case "tickers":
+		return ttlClause(msTime, p.Tickers)
+	case "websocket_messages":
+		return ttlClause("toDateTime(received_at)", p.Messages)
+	}
+	return ""
+}
+
+// candleTTLExpression builds one TTL rule per candle interval
+func (p RetentionPolicy) candleTTLExpression() string {
+	intervals := make([]string, 0, len(p.Candles))
+	for interval, retention := range p.Candles {
+		if retention > 0 {
+			intervals = append(intervals, string(interval))
+		}
+	}
+	sort.Strings(intervals)
+
+	rules := make([]string, 0, len(intervals))
+	for _, interval := range intervals {
+		retention := p.Candles[model.CandleInterval(interval)]
+		rules = append(rules, fmt.Sprintf("%s DELETE WHERE interval = '%s'", ttlClause("timestamp", retention), interval))
+	}
+
+	return strings.Join(rules, ", ")
+}
+
+// ttlClause returns "<column> + INTERVAL n UNIT", or "" for no retention
+func ttlClause(column string, retention time.Duration) string {
+	if retention <= 0 {
+		return ""
+	}
+
+	day := 24 * time.Hour
+	if retention%day == 0 {
+		return fmt.Sprintf("%s + INTERVAL %d DAY", column, retention/day)
+	}
+	return fmt.Sprintf("%s + INTERVAL %d SECOND", column, int64(retention/time.Second))
+}
+
+// ParseRetention parses a retention such as "30d" or "12h". "0" keeps data forever.
+func ParseRetention(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid retention %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid retention %q", s)
+	}
+	return d, nil
+}
+
+// ParseCandleRetention parses per-interval retention such as "1m=30d,1h=730d"
+// on top of the given defaults
+func ParseCandleRetention(s string, defaults map[model.CandleInterval]time.Duration) (map[model.CandleInterval]time.Duration, error) {
+	result := make(map[model.CandleInterval]time.Duration, len(defaults))
+	for interval, retention := range defaults {
+		result[interval] = retention
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		interval, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid candle retention %q (want interval=retention)", pair)
+		}
+
+		candleInterval := model.CandleInterval(strings.TrimSpace(interval))
+		if !isCandleInterval(candleInterval) {
+			return nil, fmt.Errorf("unknown candle interval %q", interval)
+		}
+
+		retention, err := ParseRetention(value)
+		if err != nil {
+			return nil, err
+		}
+		result[candleInterval] = retention
+	}
+
+	return result, nil
+}
+
+func isCandleInterval(interval model.CandleInterval) bool {
+	switch interval {
+	case model.CandleInterval1s, model.CandleInterval1m, model.CandleInterval3m, model.CandleInterval5m,
+		model.CandleInterval15m, model.CandleInterval30m, model.CandleInterval1h,
+		model.CandleInterval4h, model.CandleInterval1d, model.CandleInterval1w,
+		model.CandleInterval1M:
+		return true
+	}
+	return false
+}
+
+func isManagedTable(table string) bool {
+	for _, t := range managedTables {
+		if t == table {
+			return true
+		}
+	}
+	return false
+}
+
+// arrayParam formats strings as a ClickHouse Array(String) parameter
+func arrayParam(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = "'" + strings.ReplaceAll(v, "'", "\\'") + "'"
+	}
+	return "[" + strings.Join(quoted, ",") + "]"
+}
@@ -0,0 +1,75 @@
+package clickhouse
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+func TestRetentionPolicy_TTLExpression(t *testing.T) {
+	policy := RetentionPolicy{
+		Candles: map[model.CandleInterval]time.Duration{
+			model.CandleInterval5m: 90 * 24 * time.Hour,
+			model.CandleInterval1m: 30 * 24 * time.Hour,
+			model.CandleInterval1d: 0,
+		},
+		Ticks:   12 * time.Hour,
+		Tickers: 0,
+	}
+
+	assert.Equal(t,
+		"timestamp + INTERVAL 30 DAY DELETE WHERE interval = '1m', timestamp + INTERVAL 90 DAY DELETE WHERE interval = '5m'",
+		policy.ttlExpression("candles"))
+	assert.Equal(t, "toDateTime(intDiv(timestamp, 1000)) + INTERVAL 43200 SECOND", policy.ttlExpression("ticks"))
+	assert.Empty(t, policy.ttlExpression("tickers"))
+}
+
+func TestParseRetention(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{input: "30d", expected: 30 * 24 * time.Hour},
+		{input: "12h", expected: 12 * time.Hour},
+		{input: "0", expected: 0},
+		{input: "-1d", wantErr: true},
+		{input: "forever", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseRetention(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestParseCandleRetention(t *testing.T) {
+	defaults := map[model.CandleInterval]time.Duration{
+		model.CandleInterval1m: 30 * 24 * time.Hour,
+		model.CandleInterval1h: 730 * 24 * time.Hour,
+	}
+
+	got, err := ParseCandleRetention("1m=7d, 1d=365d, 1s=1d", defaults)
+	require.NoError(t, err)
+	assert.Equal(t, 24*time.Hour, got[model.CandleInterval1s])
+	assert.Equal(t, 7*24*time.Hour, got[model.CandleInterval1m])
+	assert.Equal(t, 730*24*time.Hour, got[model.CandleInterval1h])
+	assert.Equal(t, 365*24*time.Hour, got[model.CandleInterval1d])
+	assert.Equal(t, 30*24*time.Hour, defaults[model.CandleInterval1m], "defaults must not be modified")
+
+	_, err = ParseCandleRetention("2m=7d", defaults)
+	assert.Error(t, err)
+
+	_, err = ParseCandleRetention("1m", defaults)
+	assert.Error(t, err)
+}
@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// PriceAlertRepository is an in-memory implementation of repository.PriceAlertRepository
+type PriceAlertRepository struct {
+	store *Store
+}
+
+var _ repository.PriceAlertRepository = (*PriceAlertRepository)(nil)
+
+// Create stores a new price alert
+func (r *PriceAlertRepository) Create(ctx context.Context, alert *model.PriceAlert) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	a := *alert
+	r.store.alerts[alert.ID] = &a
+	return nil
+}
+
+// GetByID retrieves a price alert by ID
+func (r *PriceAlertRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.PriceAlert, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	alert, exists := r.store.alerts[id]
+	if !exists {
+		return nil, repository.ErrNotFound
+	}
+
+	a := *alert
+	return &a, nil
+}
+
+// Update replaces a stored price alert
+func (r *PriceAlertRepository) Update(ctx context.Context, alert *model.PriceAlert) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.alerts[alert.ID]; !exists {
+		return repository.ErrNotFound
+	}
+
+	a := *alert
+	r.store.alerts[alert.ID] = &a
+	return nil
+}
+
+// Delete removes a price alert
+func (r *PriceAlertRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.alerts[id]; !exists {
+		return repository.ErrNotFound
+	}
+	delete(r.store.alerts, id)
+	return nil
+}
+
+// ListByUser returns a user's alerts, newest first
+func (r *PriceAlertRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.PriceAlert, error) {
+	alerts := r.filter(func(a *model.PriceAlert) bool { return a.UserID == userID })
+	sort.Slice(alerts, func(i, j int) bool {
+		return alerts[i].CreatedAt.After(alerts[j].CreatedAt)
+	})
+	return alerts, nil
+}
+
+// ListActive returns every active alert
+func (r *PriceAlertRepository) ListActive(ctx context.Context) ([]*model.PriceAlert, error) {
+	return r.filter(func(a *model.PriceAlert) bool { return a.Active }), nil
+}
+
+func (r *PriceAlertRepository) filter(match func(a *model.PriceAlert) bool) []*model.PriceAlert {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var alerts []*model.PriceAlert
+	for _, alert := range r.store.alerts {
+		if match(alert) {
+			a := *alert
+			alerts = append(alerts, &a)
+		}
+	}
+	return alerts
+}
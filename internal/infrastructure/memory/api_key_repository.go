@@ -0,0 +1,117 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// UserAPIKeyRepository is an in-memory implementation of repository.UserAPIKeyRepository
+type UserAPIKeyRepository struct {
+	store *Store
+}
+
+var _ repository.UserAPIKeyRepository = (*UserAPIKeyRepository)(nil)
+
+// Create stores a new API key
+func (r *UserAPIKeyRepository) Create(ctx context.Context, key *model.UserAPIKey) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	k := *key
+	r.store.apiKeys[key.ID] = &k
+	return nil
+}
+
+// ListByUser returns the user's API keys, newest first
+func (r *UserAPIKeyRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.UserAPIKey, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var keys []*model.UserAPIKey
+	for _, key := range r.store.apiKeys {
+		if key.UserID == userID {
+			k := *key
+			keys = append(keys, &k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.After(keys[j].CreatedAt)
+	})
+	return keys, nil
+}
+
+// SetActive activates one of the user's keys and deactivates the others
+func (r *UserAPIKeyRepository) SetActive(ctx context.Context, userID, keyID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if key, exists := r.store.apiKeys[keyID]; !exists || key.UserID != userID {
+		return repository.ErrNotFound
+	}
+	now := time.Now()
+	for _, key := range r.store.apiKeys {
+		if key.UserID == userID && key.IsActive != (key.ID == keyID) {
+			key.IsActive = key.ID == keyID
+			key.UpdatedAt = now
+		}
+	}
+	return nil
+}
+
+// SetExpiry records when a key expires
+func (r *UserAPIKeyRepository) SetExpiry(ctx context.Context, keyID uuid.UUID, expiresAt time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key, exists := r.store.apiKeys[keyID]
+	if !exists {
+		return repository.ErrNotFound
+	}
+	key.ExpiresAt = &expiresAt
+	key.UpdatedAt = time.Now()
+	return nil
+}
+
+// GetActiveByUserID returns the most recently created active API key of a user
+func (r *UserAPIKeyRepository) GetActiveByUserID(ctx context.Context, userID uuid.UUID) (*model.UserAPIKey, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var latest *model.UserAPIKey
+	for _, key := range r.store.apiKeys {
+		if key.UserID != userID || !key.IsActive {
+			continue
+		}
+		if latest == nil || key.CreatedAt.After(latest.CreatedAt) {
+			latest = key
+		}
+	}
+
+	if latest == nil {
+		return nil, repository.ErrNotFound
+	}
+
+	k := *latest
+	return &k, nil
+}
+
+// ListActiveUserIDs returns the users that have an active API key
+func (r *UserAPIKeyRepository) ListActiveUserIDs(ctx context.Context) ([]uuid.UUID, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	seen := make(map[uuid.UUID]bool)
+	var userIDs []uuid.UUID
+	for _, key := range r.store.apiKeys {
+		if key.IsActive && !seen[key.UserID] {
+			seen[key.UserID] = true
+			userIDs = append(userIDs, key.UserID)
+		}
+	}
+	return userIDs, nil
+}
@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// defaultBacktestListLimit bounds listings that don't set a limit
+const defaultBacktestListLimit = 100
+
+// BacktestRepository is an in-memory implementation of repository.BacktestRepository
+type BacktestRepository struct {
+	store *Store
+}
+
+var _ repository.BacktestRepository = (*BacktestRepository)(nil)
+
+// Create stores a run together with its trades
+func (r *BacktestRepository) Create(ctx context.Context, run *model.BacktestRun) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.backtests[run.ID] = copyBacktestRun(run, true)
+	return nil
+}
+
+// GetByID returns a run with its trades
+func (r *BacktestRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.BacktestRun, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	run, ok := r.store.backtests[id]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	return copyBacktestRun(run, true), nil
+}
+
+// ListByUser returns a user's runs newest first, without trades
+func (r *BacktestRepository) ListByUser(ctx context.Context, userID uuid.UUID, filter repository.BacktestFilter) ([]*model.BacktestRun, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var runs []*model.BacktestRun
+	for _, run := range r.store.backtests {
+		if run.UserID != userID {
+			continue
+		}
+		if filter.Market != "" && run.Market != filter.Market {
+			continue
+		}
+		if filter.Strategy != "" && run.Strategy != filter.Strategy {
+			continue
+		}
+		runs = append(runs, copyBacktestRun(run, false))
+	}
+
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].CreatedAt.After(runs[j].CreatedAt)
+	})
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultBacktestListLimit
+	}
+	if len(runs) > limit {
+		runs = runs[:limit]
+	}
+	return runs, nil
+}
+
+func copyBacktestRun(run *model.BacktestRun, withTrades bool) *model.BacktestRun {
+	r := *run
+	r.Params = maps.Clone(run.Params)
+	r.Config = slices.Clone(run.Config)
+	r.Trades = nil
+	if withTrades {
+		r.Trades = slices.Clone(run.Trades)
+	}
+	return &r
+}
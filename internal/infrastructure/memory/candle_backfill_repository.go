@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// candleBackfillKey identifies a market's backfill at an interval
+type candleBackfillKey struct {
+	market   string
+	interval model.CandleInterval
+}
+
+// CandleBackfillRepository is an in-memory implementation of repository.CandleBackfillRepository
+type CandleBackfillRepository struct {
+	store *Store
+}
+
+var _ repository.CandleBackfillRepository = (*CandleBackfillRepository)(nil)
+
+// Get returns a market's backfill progress
+func (r *CandleBackfillRepository) Get(ctx context.Context, market string, interval model.CandleInterval) (*model.CandleBackfill, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	backfill, exists := r.store.candleBackfills[candleBackfillKey{market: market, interval: interval}]
+	if !exists {
+		return nil, repository.ErrNotFound
+	}
+	b := *backfill
+	return &b, nil
+}
+
+// Save creates or replaces a market's backfill progress
+func (r *CandleBackfillRepository) Save(ctx context.Context, backfill *model.CandleBackfill) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	b := *backfill
+	r.store.candleBackfills[candleBackfillKey{market: b.Market, interval: b.Interval}] = &b
+	return nil
+}
@@ -0,0 +1,117 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// collectorShardKey identifies a shard of an interval's collector
+type collectorShardKey struct {
+	interval model.CandleInterval
+	shard    int
+}
+
+// CollectorShardRepository is an in-memory implementation of repository.CollectorShardRepository
+type CollectorShardRepository struct {
+	store *Store
+}
+
+var _ repository.CollectorShardRepository = (*CollectorShardRepository)(nil)
+
+// Claim leases a shard to owner, creating the interval's shards on first use
+func (r *CollectorShardRepository) Claim(ctx context.Context, interval model.CandleInterval, shards int, owner string, now, leaseUntil time.Time) (*model.CollectorShard, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var claim *model.CollectorShard
+	for i := 0; i < shards; i++ {
+		key := collectorShardKey{interval: interval, shard: i}
+		shard, exists := r.store.collectorShards[key]
+		if !exists {
+			shard = &model.CollectorShard{Interval: interval, Shard: i, Shards: shards}
+			r.store.collectorShards[key] = shard
+		}
+		if shard.Owner == owner {
+			claim = shard
+			break
+		}
+		if claim == nil && !shard.LeaseUntil.After(now) {
+			claim = shard
+		}
+	}
+	if claim == nil {
+		return nil, repository.ErrNotFound
+	}
+
+	s := *claim
+	s.Owner = owner
+	s.LeaseUntil = leaseUntil
+	s.Shards = shards
+	s.UpdatedAt = now
+	r.store.collectorShards[collectorShardKey{interval: interval, shard: s.Shard}] = &s
+
+	c := s
+	return &c, nil
+}
+
+// SaveProgress records a collection pass over a shard and extends its lease
+func (r *CollectorShardRepository) SaveProgress(ctx context.Context, shard *model.CollectorShard) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key := collectorShardKey{interval: shard.Interval, shard: shard.Shard}
+	existing, exists := r.store.collectorShards[key]
+	if !exists || existing.Owner != shard.Owner {
+		return repository.ErrNotFound
+	}
+
+	s := *existing
+	s.LeaseUntil = shard.LeaseUntil
+	s.Markets = shard.Markets
+	s.Candles = shard.Candles
+	s.Failures = shard.Failures
+	s.LastError = shard.LastError
+	s.LastCollectedAt = shard.LastCollectedAt
+	s.UpdatedAt = shard.UpdatedAt
+	r.store.collectorShards[key] = &s
+	return nil
+}
+
+// Release gives up owner's lease on a shard
+func (r *CollectorShardRepository) Release(ctx context.Context, interval model.CandleInterval, shard int, owner string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key := collectorShardKey{interval: interval, shard: shard}
+	existing, exists := r.store.collectorShards[key]
+	if !exists || existing.Owner != owner {
+		return nil
+	}
+
+	s := *existing
+	s.Owner = ""
+	s.LeaseUntil = time.Time{}
+	s.UpdatedAt = time.Now()
+	r.store.collectorShards[key] = &s
+	return nil
+}
+
+// List returns an interval's shards in order
+func (r *CollectorShardRepository) List(ctx context.Context, interval model.CandleInterval) ([]*model.CollectorShard, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var shards []*model.CollectorShard
+	for key, shard := range r.store.collectorShards {
+		if key.interval == interval {
+			s := *shard
+			shards = append(shards, &s)
+		}
+	}
+	sort.Slice(shards, func(i, j int) bool { return shards[i].Shard < shards[j].Shard })
+	return shards, nil
+}
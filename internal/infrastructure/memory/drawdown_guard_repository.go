@@ -0,0 +1,150 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// DrawdownGuardRepository is an in-memory implementation of repository.DrawdownGuardRepository
+type DrawdownGuardRepository struct {
+	store *Store
+}
+
+var _ repository.DrawdownGuardRepository = (*DrawdownGuardRepository)(nil)
+
+// Save creates or replaces the guard of a position
+func (r *DrawdownGuardRepository) Save(ctx context.Context, guard *model.DrawdownGuard) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	g := *guard
+	r.store.drawdownGuards[guard.PositionID] = &g
+	return nil
+}
+
+// SavePeak stores the peak of a position's guard while it is active
+func (r *DrawdownGuardRepository) SavePeak(ctx context.Context, guard *model.DrawdownGuard) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, exists := r.store.drawdownGuards[guard.PositionID]
+	if !exists || !stored.Active {
+		return repository.ErrNotFound
+	}
+	g := *stored
+	g.PeakPrice = guard.PeakPrice
+	g.UpdatedAt = guard.UpdatedAt
+	r.store.drawdownGuards[guard.PositionID] = &g
+	return nil
+}
+
+// GetByPosition returns the guard of a position
+func (r *DrawdownGuardRepository) GetByPosition(ctx context.Context, positionID uuid.UUID) (*model.DrawdownGuard, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	guard, exists := r.store.drawdownGuards[positionID]
+	if !exists {
+		return nil, repository.ErrNotFound
+	}
+
+	g := *guard
+	return &g, nil
+}
+
+// Delete removes the guard of a position
+func (r *DrawdownGuardRepository) Delete(ctx context.Context, positionID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.drawdownGuards[positionID]; !exists {
+		return repository.ErrNotFound
+	}
+	delete(r.store.drawdownGuards, positionID)
+	r.store.deleteDrawdownGuardUpdates(positionID)
+	return nil
+}
+
+// ListByUser returns a user's guards, newest first
+func (r *DrawdownGuardRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.DrawdownGuard, error) {
+	guards := r.filter(func(g *model.DrawdownGuard) bool { return g.UserID == userID })
+	sort.Slice(guards, func(i, j int) bool {
+		return guards[i].CreatedAt.After(guards[j].CreatedAt)
+	})
+	return guards, nil
+}
+
+// ListActive returns every active guard
+func (r *DrawdownGuardRepository) ListActive(ctx context.Context) ([]*model.DrawdownGuard, error) {
+	return r.filter(func(g *model.DrawdownGuard) bool { return g.Active }), nil
+}
+
+func (r *DrawdownGuardRepository) filter(match func(g *model.DrawdownGuard) bool) []*model.DrawdownGuard {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var guards []*model.DrawdownGuard
+	for _, guard := range r.store.drawdownGuards {
+		if match(guard) {
+			g := *guard
+			guards = append(guards, &g)
+		}
+	}
+	return guards
+}
+
+// DrawdownGuardUpdateRepository is an in-memory implementation of repository.DrawdownGuardUpdateRepository
+type DrawdownGuardUpdateRepository struct {
+	store *Store
+}
+
+var _ repository.DrawdownGuardUpdateRepository = (*DrawdownGuardUpdateRepository)(nil)
+
+// Create stores a new update
+func (r *DrawdownGuardUpdateRepository) Create(ctx context.Context, update *model.DrawdownGuardUpdate) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.drawdownGuards[update.PositionID]; !exists {
+		return repository.ErrNotFound
+	}
+	u := *update
+	r.store.drawdownGuardUpdates[update.ID] = &u
+	return nil
+}
+
+// ListSince returns a position's guard updates from since on in
+// chronological order, preceded by the last update before since
+func (r *DrawdownGuardUpdateRepository) ListSince(ctx context.Context, positionID uuid.UUID, since time.Time) ([]*model.DrawdownGuardUpdate, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var updates []*model.DrawdownGuardUpdate
+	for _, update := range r.store.drawdownGuardUpdates {
+		if update.PositionID == positionID {
+			u := *update
+			updates = append(updates, &u)
+		}
+	}
+	sort.SliceStable(updates, func(i, j int) bool {
+		return updates[i].CreatedAt.Before(updates[j].CreatedAt)
+	})
+
+	start := sort.Search(len(updates), func(i int) bool { return !updates[i].CreatedAt.Before(since) })
+	return updates[max(start-1, 0):], nil
+}
+
+// deleteDrawdownGuardUpdates removes the updates of a position's guard with
+// the guard; s.mu must be held
+func (s *Store) deleteDrawdownGuardUpdates(positionID uuid.UUID) {
+	for id, update := range s.drawdownGuardUpdates {
+		if update.PositionID == positionID {
+			delete(s.drawdownGuardUpdates, id)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+func TestDrawdownGuardRepository_SavePeakRollsBack(t *testing.T) {
+	store := NewStore()
+	ctx := context.Background()
+	position := model.NewPosition(uuid.New(), "KRW-BTC", model.PositionSideLong, 100000000, 0.01, time.Now())
+	guard := model.NewDrawdownGuard(position, 10, 0, time.Now())
+	require.NoError(t, store.DrawdownGuards().Save(ctx, guard))
+
+	errRollback := errors.New("rollback")
+	err := store.Do(ctx, func(tx repository.Tx) error {
+		peaked := *guard
+		peaked.PeakPrice = 110000000
+		require.NoError(t, tx.DrawdownGuards().SavePeak(ctx, &peaked))
+		return errRollback
+	})
+	require.ErrorIs(t, err, errRollback)
+
+	stored, err := store.DrawdownGuards().GetByPosition(ctx, position.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 100000000.0, stored.PeakPrice, "the rolled back peak is not kept")
+}
@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// TradingHaltRepository is an in-memory implementation of repository.TradingHaltRepository
+type TradingHaltRepository struct {
+	store *Store
+}
+
+var _ repository.TradingHaltRepository = (*TradingHaltRepository)(nil)
+
+// Get returns the halt of a user, or the global halt for uuid.Nil
+func (r *TradingHaltRepository) Get(ctx context.Context, userID uuid.UUID) (*model.TradingHalt, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	halt, exists := r.store.tradingHalts[userID]
+	if !exists {
+		return nil, repository.ErrNotFound
+	}
+
+	h := *halt
+	return &h, nil
+}
+
+// Save creates or replaces a halt
+func (r *TradingHaltRepository) Save(ctx context.Context, halt *model.TradingHalt) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	h := *halt
+	r.store.tradingHalts[halt.UserID] = &h
+	return nil
+}
+
+// Delete lifts a halt
+func (r *TradingHaltRepository) Delete(ctx context.Context, userID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.tradingHalts[userID]; !exists {
+		return repository.ErrNotFound
+	}
+	delete(r.store.tradingHalts, userID)
+	return nil
+}
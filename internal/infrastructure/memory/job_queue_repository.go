@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+const defaultQueuedJobListLimit = 100
+
+// JobQueueRepository is an in-memory implementation of repository.JobQueueRepository
+type JobQueueRepository struct {
+	store *Store
+}
+
+var _ repository.JobQueueRepository = (*JobQueueRepository)(nil)
+
+// Enqueue stores a new job
+func (r *JobQueueRepository) Enqueue(ctx context.Context, job *model.QueuedJob) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.queuedJobs[job.ID] = copyQueuedJob(job)
+	return nil
+}
+
+// GetByID retrieves a job by ID
+func (r *JobQueueRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.QueuedJob, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	job, exists := r.store.queuedJobs[id]
+	if !exists {
+		return nil, repository.ErrNotFound
+	}
+	return copyQueuedJob(job), nil
+}
+
+// Update replaces a job
+func (r *JobQueueRepository) Update(ctx context.Context, job *model.QueuedJob) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.queuedJobs[job.ID]; !exists {
+		return repository.ErrNotFound
+	}
+	r.store.queuedJobs[job.ID] = copyQueuedJob(job)
+	return nil
+}
+
+// Claim leases due jobs of the given kinds
+func (r *JobQueueRepository) Claim(ctx context.Context, kinds []string, now, leaseUntil time.Time, limit int) ([]*model.QueuedJob, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var due []*model.QueuedJob
+	for _, job := range r.store.queuedJobs {
+		active := job.Status == model.QueuedJobPending || job.Status == model.QueuedJobRunning
+		if active && slices.Contains(kinds, job.Kind) && !job.RunAt.After(now) {
+			due = append(due, job)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].RunAt.Before(due[j].RunAt) })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	claimed := make([]*model.QueuedJob, 0, len(due))
+	for _, job := range due {
+		j := copyQueuedJob(job)
+		j.Interrupted = job.Status == model.QueuedJobRunning
+		j.Status = model.QueuedJobRunning
+		j.Attempts++
+		j.RunAt = leaseUntil
+		j.UpdatedAt = now
+		r.store.queuedJobs[j.ID] = j
+		claimed = append(claimed, copyQueuedJob(j))
+	}
+	return claimed, nil
+}
+
+// List returns jobs, newest first
+func (r *JobQueueRepository) List(ctx context.Context, filter repository.JobQueueFilter) ([]*model.QueuedJob, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var jobs []*model.QueuedJob
+	for _, job := range r.store.queuedJobs {
+		if filter.Kind != "" && job.Kind != filter.Kind || filter.Status != "" && job.Status != filter.Status {
+			continue
+		}
+		if filter.UserID != uuid.Nil && (job.UserID == nil || *job.UserID != filter.UserID) {
+			continue
+		}
+		jobs = append(jobs, copyQueuedJob(job))
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].CreatedAt.After(jobs[j].CreatedAt) })
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultQueuedJobListLimit
+	}
+	if len(jobs) > limit {
+		jobs = jobs[:limit]
+	}
+	return jobs, nil
+}
+
+// SaveResult stores a job's result, replacing one an earlier attempt saved
+func (r *JobQueueRepository) SaveResult(ctx context.Context, result *model.QueuedJobResult) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.queuedJobs[result.JobID]; !exists {
+		return repository.ErrNotFound
+	}
+	res := *result
+	res.Data = slices.Clone(result.Data)
+	r.store.queuedJobResults[result.JobID] = &res
+	return nil
+}
+
+// GetResult retrieves a job's result
+func (r *JobQueueRepository) GetResult(ctx context.Context, jobID uuid.UUID) (*model.QueuedJobResult, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	result, exists := r.store.queuedJobResults[jobID]
+	if !exists {
+		return nil, repository.ErrNotFound
+	}
+	res := *result
+	res.Data = slices.Clone(result.Data)
+	return &res, nil
+}
+
+func copyQueuedJob(job *model.QueuedJob) *model.QueuedJob {
+	j := *job
+	j.Payload = slices.Clone(job.Payload)
+	return &j
+}
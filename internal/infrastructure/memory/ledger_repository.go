@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// CashLedgerRepository is an in-memory implementation of repository.CashLedgerRepository
+type CashLedgerRepository struct {
+	store *Store
+}
+
+var _ repository.CashLedgerRepository = (*CashLedgerRepository)(nil)
+
+// Record stores an entry unless one already exists for the user, type and reference
+func (r *CashLedgerRepository) Record(ctx context.Context, entry *model.CashLedgerEntry) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.cashLedger {
+		if existing.UserID == entry.UserID && existing.Type == entry.Type && existing.Reference == entry.Reference {
+			return nil
+		}
+	}
+
+	e := *entry
+	r.store.cashLedger[e.ID] = &e
+	return nil
+}
+
+// GetOpening returns a user's opening balance entry
+func (r *CashLedgerRepository) GetOpening(ctx context.Context, userID uuid.UUID) (*model.CashLedgerEntry, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, entry := range r.store.cashLedger {
+		if entry.UserID == userID && entry.Type == model.CashEntryOpeningBalance {
+			e := *entry
+			return &e, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+// ListByUser returns a user's entries that occurred in [from, to), newest first
+func (r *CashLedgerRepository) ListByUser(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*model.CashLedgerEntry, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var entries []*model.CashLedgerEntry
+	for _, entry := range r.store.cashLedger {
+		if entry.UserID != userID || entry.OccurredAt.Before(from) || !entry.OccurredAt.Before(to) {
+			continue
+		}
+		e := *entry
+		entries = append(entries, &e)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].OccurredAt.Equal(entries[j].OccurredAt) {
+			return entries[i].OccurredAt.After(entries[j].OccurredAt)
+		}
+		return entries[i].CreatedAt.After(entries[j].CreatedAt)
+	})
+	return entries, nil
+}
+
+// Balance returns the sum of a user's entries
+func (r *CashLedgerRepository) Balance(ctx context.Context, userID uuid.UUID) (float64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var balance float64
+	for _, entry := range r.store.cashLedger {
+		if entry.UserID == userID {
+			balance += entry.Amount
+		}
+	}
+	return balance, nil
+}
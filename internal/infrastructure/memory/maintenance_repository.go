@@ -0,0 +1,59 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// MaintenanceWindowRepository is an in-memory implementation of repository.MaintenanceWindowRepository
+type MaintenanceWindowRepository struct {
+	store *Store
+}
+
+var _ repository.MaintenanceWindowRepository = (*MaintenanceWindowRepository)(nil)
+
+// Create inserts a new maintenance window
+func (r *MaintenanceWindowRepository) Create(ctx context.Context, window *model.MaintenanceWindow) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	w := *window
+	r.store.maintenanceWindows[w.ID] = &w
+	return nil
+}
+
+// Delete removes a maintenance window
+func (r *MaintenanceWindowRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.maintenanceWindows[id]; !exists {
+		return repository.ErrNotFound
+	}
+	delete(r.store.maintenanceWindows, id)
+	return nil
+}
+
+// ListEndingAfter returns the windows that end after the given time,
+// earliest start first
+func (r *MaintenanceWindowRepository) ListEndingAfter(ctx context.Context, after time.Time) ([]*model.MaintenanceWindow, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var windows []*model.MaintenanceWindow
+	for _, window := range r.store.maintenanceWindows {
+		if window.EndsAt.After(after) {
+			w := *window
+			windows = append(windows, &w)
+		}
+	}
+	sort.Slice(windows, func(i, j int) bool {
+		return windows[i].StartsAt.Before(windows[j].StartsAt)
+	})
+	return windows, nil
+}
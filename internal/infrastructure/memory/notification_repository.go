@@ -0,0 +1,55 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// NotificationSettingsRepository is an in-memory implementation of repository.NotificationSettingsRepository
+type NotificationSettingsRepository struct {
+	store *Store
+}
+
+var _ repository.NotificationSettingsRepository = (*NotificationSettingsRepository)(nil)
+
+// Get returns the user's settings
+func (r *NotificationSettingsRepository) Get(ctx context.Context, userID uuid.UUID) (*model.NotificationSettings, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	settings, exists := r.store.notificationSettings[userID]
+	if !exists {
+		return nil, repository.ErrNotFound
+	}
+
+	s := *settings
+	return &s, nil
+}
+
+// Save creates or replaces the user's settings
+func (r *NotificationSettingsRepository) Save(ctx context.Context, settings *model.NotificationSettings) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	s := *settings
+	r.store.notificationSettings[settings.UserID] = &s
+	return nil
+}
+
+// ListDailySummaries returns the settings of users with daily summaries enabled
+func (r *NotificationSettingsRepository) ListDailySummaries(ctx context.Context) ([]*model.NotificationSettings, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var result []*model.NotificationSettings
+	for _, settings := range r.store.notificationSettings {
+		if settings.DailySummary {
+			s := *settings
+			result = append(result, &s)
+		}
+	}
+	return result, nil
+}
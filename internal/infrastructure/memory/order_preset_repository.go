@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// OrderPresetRepository is an in-memory implementation of repository.OrderPresetRepository
+type OrderPresetRepository struct {
+	store *Store
+}
+
+var _ repository.OrderPresetRepository = (*OrderPresetRepository)(nil)
+
+// Save creates or replaces the user's preset of the same name
+func (r *OrderPresetRepository) Save(ctx context.Context, preset *model.OrderPreset) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if existing := r.find(preset.UserID, preset.Name); existing != nil {
+		delete(r.store.orderPresets, existing.ID)
+		preset.ID = existing.ID
+		preset.CreatedAt = existing.CreatedAt
+	}
+	p := *preset
+	r.store.orderPresets[p.ID] = &p
+	return nil
+}
+
+// GetByName returns the user's preset of a name
+func (r *OrderPresetRepository) GetByName(ctx context.Context, userID uuid.UUID, name string) (*model.OrderPreset, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	preset := r.find(userID, name)
+	if preset == nil {
+		return nil, repository.ErrNotFound
+	}
+	p := *preset
+	return &p, nil
+}
+
+// ListByUser returns a user's presets by name
+func (r *OrderPresetRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.OrderPreset, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var presets []*model.OrderPreset
+	for _, preset := range r.store.orderPresets {
+		if preset.UserID == userID {
+			p := *preset
+			presets = append(presets, &p)
+		}
+	}
+	sort.Slice(presets, func(i, j int) bool {
+		return presets[i].Name < presets[j].Name
+	})
+	return presets, nil
+}
+
+// Delete removes the user's preset of a name
+func (r *OrderPresetRepository) Delete(ctx context.Context, userID uuid.UUID, name string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	preset := r.find(userID, name)
+	if preset == nil {
+		return repository.ErrNotFound
+	}
+	delete(r.store.orderPresets, preset.ID)
+	return nil
+}
+
+// find returns the stored preset of a user and name; the caller holds the lock
+func (r *OrderPresetRepository) find(userID uuid.UUID, name string) *model.OrderPreset {
+	for _, preset := range r.store.orderPresets {
+		if preset.UserID == userID && preset.Name == name {
+			return preset
+		}
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// OrderRepository is an in-memory implementation of repository.OrderRepository
+type OrderRepository struct {
+	store *Store
+}
+
+var _ repository.OrderRepository = (*OrderRepository)(nil)
+
+// Create stores a new order
+func (r *OrderRepository) Create(ctx context.Context, order *model.Order) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	o := *order
+	r.store.orders[order.ID] = &o
+	return nil
+}
+
+// GetByID retrieves an order by ID
+func (r *OrderRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Order, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	order, exists := r.store.orders[id]
+	if !exists {
+		return nil, repository.ErrNotFound
+	}
+
+	o := *order
+	return &o, nil
+}
+
+// GetCurrent retrieves an order by ID. The store has no replica, so it is
+// GetByID.
+func (r *OrderRepository) GetCurrent(ctx context.Context, id uuid.UUID) (*model.Order, error) {
+	return r.GetByID(ctx, id)
+}
+
+// Update replaces a stored order, keeping its tags and notes
+func (r *OrderRepository) Update(ctx context.Context, order *model.Order) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, exists := r.store.orders[order.ID]
+	if !exists {
+		return repository.ErrNotFound
+	}
+
+	o := *order
+	o.Tags, o.Notes = existing.Tags, existing.Notes
+	r.store.orders[order.ID] = &o
+	return nil
+}
+
+// Annotate replaces the tags and notes of an order
+func (r *OrderRepository) Annotate(ctx context.Context, id uuid.UUID, tags []string, notes string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, exists := r.store.orders[id]
+	if !exists {
+		return repository.ErrNotFound
+	}
+
+	o := *existing
+	o.Tags, o.Notes = slices.Clone(tags), notes
+	r.store.orders[id] = &o
+	return nil
+}
+
+// ListByUser returns a user's orders, newest first
+func (r *OrderRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.Order, error) {
+	orders := r.filter(func(o *model.Order) bool {
+		return o.UserID == userID
+	})
+
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].CreatedAt.After(orders[j].CreatedAt)
+	})
+	return orders, nil
+}
+
+// ListOpenByUser returns a user's pending, submitted or partially filled
+// orders, newest first
+func (r *OrderRepository) ListOpenByUser(ctx context.Context, userID uuid.UUID) ([]*model.Order, error) {
+	orders := r.filter(func(o *model.Order) bool {
+		return o.UserID == userID && isOpen(o)
+	})
+
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].CreatedAt.After(orders[j].CreatedAt)
+	})
+	return orders, nil
+}
+
+// ListOpenSellsByPosition returns the pending, submitted or partially filled
+// sells of a position, oldest first
+func (r *OrderRepository) ListOpenSellsByPosition(ctx context.Context, positionID uuid.UUID) ([]*model.Order, error) {
+	orders := r.filter(func(o *model.Order) bool {
+		return o.PositionID != nil && *o.PositionID == positionID && o.Side == model.OrderSideAsk && isOpen(o)
+	})
+
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].CreatedAt.Before(orders[j].CreatedAt)
+	})
+	return orders, nil
+}
+
+// CountCreatedSince counts a user's orders created after since and returns
+// when the earliest was created
+func (r *OrderRepository) CountCreatedSince(ctx context.Context, userID uuid.UUID, since time.Time) (int, time.Time, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var count int
+	var earliest time.Time
+	for _, o := range r.store.orders {
+		if o.UserID != userID || !o.CreatedAt.After(since) {
+			continue
+		}
+		if count == 0 || o.CreatedAt.Before(earliest) {
+			earliest = o.CreatedAt
+		}
+		count++
+	}
+	return count, earliest, nil
+}
+
+// ListOpen returns submitted or partially filled orders, oldest first
+func (r *OrderRepository) ListOpen(ctx context.Context) ([]*model.Order, error) {
+	orders := r.filter(func(o *model.Order) bool {
+		return (o.Status == model.OrderStatusSubmitted || o.Status == model.OrderStatusPartial) && o.ExchangeOrderID != nil
+	})
+
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].CreatedAt.Before(orders[j].CreatedAt)
+	})
+	return orders, nil
+}
+
+// ListDue returns scheduled orders whose activation time is at or before
+// now, soonest first
+func (r *OrderRepository) ListDue(ctx context.Context, now time.Time) ([]*model.Order, error) {
+	orders := r.filter(func(o *model.Order) bool {
+		return o.Status == model.OrderStatusScheduled && o.ActivateAt != nil && !o.ActivateAt.After(now)
+	})
+
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].ActivateAt.Before(*orders[j].ActivateAt)
+	})
+	return orders, nil
+}
+
+// ListUnsubmitted returns pending orders without an exchange order ID last
+// updated before the given time, oldest first
+func (r *OrderRepository) ListUnsubmitted(ctx context.Context, before time.Time) ([]*model.Order, error) {
+	orders := r.filter(func(o *model.Order) bool {
+		return o.Status == model.OrderStatusPending && o.ExchangeOrderID == nil && o.UpdatedAt.Before(before)
+	})
+
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].UpdatedAt.Before(orders[j].UpdatedAt)
+	})
+	return orders, nil
+}
+
+// isOpen reports whether an order was accepted and isn't final yet
+func isOpen(o *model.Order) bool {
+	switch o.Status {
+	case model.OrderStatusPending, model.OrderStatusSubmitted, model.OrderStatusPartial:
+		return true
+	}
+	return false
+}
+
+func (r *OrderRepository) filter(match func(*model.Order) bool) []*model.Order {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var orders []*model.Order
+	for _, order := range r.store.orders {
+		if match(order) {
+			o := *order
+			orders = append(orders, &o)
+		}
+	}
+	return orders
+}
+
+// OrderExecutionRepository is an in-memory implementation of repository.OrderExecutionRepository
+type OrderExecutionRepository struct {
+	store *Store
+}
+
+var _ repository.OrderExecutionRepository = (*OrderExecutionRepository)(nil)
+
+// Create stores a new execution
+func (r *OrderExecutionRepository) Create(ctx context.Context, execution *model.OrderExecution) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	e := *execution
+	r.store.executions[execution.ID] = &e
+	return nil
+}
+
+// ListByOrder returns the executions of an order in chronological order
+func (r *OrderExecutionRepository) ListByOrder(ctx context.Context, orderID uuid.UUID) ([]*model.OrderExecution, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var executions []*model.OrderExecution
+	for _, execution := range r.store.executions {
+		if execution.OrderID == orderID {
+			e := *execution
+			executions = append(executions, &e)
+		}
+	}
+
+	sort.Slice(executions, func(i, j int) bool {
+		return executions[i].CreatedAt.Before(executions[j].CreatedAt)
+	})
+	return executions, nil
+}
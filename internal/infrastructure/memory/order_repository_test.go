@@ -0,0 +1,49 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+func TestOrderRepository_OpenOrderQueries(t *testing.T) {
+	store := NewStore()
+	ctx := context.Background()
+	userID, positionID := uuid.New(), uuid.New()
+	start := time.Now()
+
+	order := func(side model.OrderSide, status model.OrderStatus, position *uuid.UUID, age time.Duration) *model.Order {
+		o := model.NewOrder(userID, "KRW-BTC", side, model.OrderTypeMarket, 0.01, nil, time.Now())
+		o.Status, o.PositionID, o.CreatedAt = status, position, start.Add(-age)
+		require.NoError(t, store.Orders().Create(ctx, o))
+		return o
+	}
+	openSell := order(model.OrderSideAsk, model.OrderStatusSubmitted, &positionID, time.Second)
+	order(model.OrderSideAsk, model.OrderStatusFilled, &positionID, time.Minute)
+	openBuy := order(model.OrderSideBid, model.OrderStatusPending, &positionID, 2*time.Minute)
+	order(model.OrderSideBid, model.OrderStatusCancelled, nil, 2*time.Hour)
+
+	open, err := store.Orders().ListOpenByUser(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, open, 2)
+	assert.Equal(t, []uuid.UUID{openSell.ID, openBuy.ID}, []uuid.UUID{open[0].ID, open[1].ID})
+
+	sells, err := store.Orders().ListOpenSellsByPosition(ctx, positionID)
+	require.NoError(t, err)
+	require.Len(t, sells, 1)
+	assert.Equal(t, openSell.ID, sells[0].ID)
+
+	count, earliest, err := store.Orders().CountCreatedSince(ctx, userID, start.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+	assert.Equal(t, openBuy.CreatedAt, earliest)
+
+	count, _, err = store.Orders().CountCreatedSince(ctx, uuid.New(), start.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// OutboxRepository is an in-memory implementation of repository.OutboxRepository.
+// Row locking isn't needed because UnitOfWork transactions are serialized.
+type OutboxRepository struct {
+	store *Store
+}
+
+var _ repository.OutboxRepository = (*OutboxRepository)(nil)
+
+// Create stores a new event
+func (r *OutboxRepository) Create(ctx context.Context, event *model.OutboxEvent) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	e := *event
+	r.store.outbox[event.ID] = &e
+	return nil
+}
+
+// ListPending returns undispatched events, oldest first
+func (r *OutboxRepository) ListPending(ctx context.Context, limit int) ([]*model.OutboxEvent, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var events []*model.OutboxEvent
+	for _, event := range r.store.outbox {
+		if event.DispatchedAt == nil {
+			e := *event
+			events = append(events, &e)
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].CreatedAt.Before(events[j].CreatedAt)
+	})
+	if len(events) > limit {
+		events = events[:limit]
+	}
+	return events, nil
+}
+
+// MarkDispatched marks an event as delivered
+func (r *OutboxRepository) MarkDispatched(ctx context.Context, id uuid.UUID) error {
+	return r.update(id, func(e *model.OutboxEvent) {
+		now := time.Now()
+		e.DispatchedAt = &now
+		e.Attempts++
+	})
+}
+
+// MarkFailed records a failed delivery attempt
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, reason string) error {
+	return r.update(id, func(e *model.OutboxEvent) {
+		e.Attempts++
+		e.LastError = &reason
+	})
+}
+
+// update applies fn to a copy of the event and stores the copy
+func (r *OutboxRepository) update(id uuid.UUID, fn func(*model.OutboxEvent)) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	event, exists := r.store.outbox[id]
+	if !exists {
+		return repository.ErrNotFound
+	}
+
+	e := *event
+	fn(&e)
+	r.store.outbox[id] = &e
+	return nil
+}
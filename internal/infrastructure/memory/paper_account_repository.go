@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// PaperAccountRepository is an in-memory implementation of repository.PaperAccountRepository
+type PaperAccountRepository struct {
+	store *Store
+}
+
+var _ repository.PaperAccountRepository = (*PaperAccountRepository)(nil)
+
+// Create inserts a new paper account
+func (r *PaperAccountRepository) Create(ctx context.Context, account *model.PaperAccount) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.paperAccounts[account.ID] = clonePaperAccount(account)
+	return nil
+}
+
+// GetByUser retrieves a user's paper account
+func (r *PaperAccountRepository) GetByUser(ctx context.Context, userID uuid.UUID) (*model.PaperAccount, error) {
+	return r.find(func(a *model.PaperAccount) bool { return a.UserID == userID })
+}
+
+// GetByAccessKey retrieves the paper account of a paper API key
+func (r *PaperAccountRepository) GetByAccessKey(ctx context.Context, accessKey string) (*model.PaperAccount, error) {
+	return r.find(func(a *model.PaperAccount) bool { return a.AccessKey == accessKey })
+}
+
+// Update replaces a paper account
+func (r *PaperAccountRepository) Update(ctx context.Context, account *model.PaperAccount) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.paperAccounts[account.ID]; !exists {
+		return repository.ErrNotFound
+	}
+	r.store.paperAccounts[account.ID] = clonePaperAccount(account)
+	return nil
+}
+
+func (r *PaperAccountRepository) find(match func(*model.PaperAccount) bool) (*model.PaperAccount, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, account := range r.store.paperAccounts {
+		if match(account) {
+			return clonePaperAccount(account), nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func clonePaperAccount(account *model.PaperAccount) *model.PaperAccount {
+	a := *account
+	a.State = slices.Clone(account.State)
+	return &a
+}
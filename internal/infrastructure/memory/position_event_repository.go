@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// PositionEventRepository is an in-memory implementation of repository.PositionEventRepository
+type PositionEventRepository struct {
+	store *Store
+}
+
+var _ repository.PositionEventRepository = (*PositionEventRepository)(nil)
+
+// Create stores a new event
+func (r *PositionEventRepository) Create(ctx context.Context, event *model.PositionEvent) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	e := *event
+	r.store.positionEvents[event.ID] = &e
+	return nil
+}
+
+// ListByPosition returns a position's events in chronological order
+func (r *PositionEventRepository) ListByPosition(ctx context.Context, positionID uuid.UUID) ([]*model.PositionEvent, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var events []*model.PositionEvent
+	for _, event := range r.store.positionEvents {
+		if event.PositionID == positionID {
+			e := *event
+			events = append(events, &e)
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].CreatedAt.Before(events[j].CreatedAt)
+	})
+	return events, nil
+}
@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// PositionRepository is an in-memory implementation of repository.PositionRepository
+type PositionRepository struct {
+	store *Store
+}
+
+var _ repository.PositionRepository = (*PositionRepository)(nil)
+
+// Create stores a new position
+func (r *PositionRepository) Create(ctx context.Context, position *model.Position) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	p := *position
+	r.store.positions[position.ID] = &p
+	return nil
+}
+
+// GetByID retrieves a position by ID
+func (r *PositionRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Position, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	position, exists := r.store.positions[id]
+	if !exists {
+		return nil, repository.ErrNotFound
+	}
+
+	p := *position
+	return &p, nil
+}
+
+// GetCurrent retrieves a position by ID. The store has no replica, so it is
+// GetByID.
+func (r *PositionRepository) GetCurrent(ctx context.Context, id uuid.UUID) (*model.Position, error) {
+	return r.GetByID(ctx, id)
+}
+
+// Update replaces a stored position, keeping its tags and notes
+func (r *PositionRepository) Update(ctx context.Context, position *model.Position) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, exists := r.store.positions[position.ID]
+	if !exists {
+		return repository.ErrNotFound
+	}
+
+	p := *position
+	p.Tags, p.Notes = existing.Tags, existing.Notes
+	r.store.positions[position.ID] = &p
+	return nil
+}
+
+// Annotate replaces the tags and notes of a position
+func (r *PositionRepository) Annotate(ctx context.Context, id uuid.UUID, tags []string, notes string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, exists := r.store.positions[id]
+	if !exists {
+		return repository.ErrNotFound
+	}
+
+	p := *existing
+	p.Tags, p.Notes = slices.Clone(tags), notes
+	r.store.positions[id] = &p
+	return nil
+}
+
+// ListByUser returns a user's positions, newest first
+func (r *PositionRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.Position, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var positions []*model.Position
+	for _, position := range r.store.positions {
+		if position.UserID == userID {
+			p := *position
+			positions = append(positions, &p)
+		}
+	}
+
+	sort.Slice(positions, func(i, j int) bool {
+		return positions[i].CreatedAt.After(positions[j].CreatedAt)
+	})
+	return positions, nil
+}
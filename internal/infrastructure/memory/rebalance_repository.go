@@ -0,0 +1,70 @@
+package memory
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// TargetPortfolioRepository is an in-memory implementation of repository.TargetPortfolioRepository
+type TargetPortfolioRepository struct {
+	store *Store
+}
+
+var _ repository.TargetPortfolioRepository = (*TargetPortfolioRepository)(nil)
+
+// Get returns the target portfolio of a user
+func (r *TargetPortfolioRepository) Get(ctx context.Context, userID uuid.UUID) (*model.TargetPortfolio, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	target, exists := r.store.targetPortfolios[userID]
+	if !exists {
+		return nil, repository.ErrNotFound
+	}
+	return copyTargetPortfolio(target), nil
+}
+
+// Save creates or replaces a target portfolio
+func (r *TargetPortfolioRepository) Save(ctx context.Context, target *model.TargetPortfolio) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.targetPortfolios[target.UserID] = copyTargetPortfolio(target)
+	return nil
+}
+
+// Delete removes a target portfolio
+func (r *TargetPortfolioRepository) Delete(ctx context.Context, userID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.targetPortfolios[userID]; !exists {
+		return repository.ErrNotFound
+	}
+	delete(r.store.targetPortfolios, userID)
+	return nil
+}
+
+// ListEnabled returns the target portfolios that rebalance automatically
+func (r *TargetPortfolioRepository) ListEnabled(ctx context.Context) ([]*model.TargetPortfolio, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var targets []*model.TargetPortfolio
+	for _, target := range r.store.targetPortfolios {
+		if target.Enabled {
+			targets = append(targets, copyTargetPortfolio(target))
+		}
+	}
+	return targets, nil
+}
+
+func copyTargetPortfolio(target *model.TargetPortfolio) *model.TargetPortfolio {
+	t := *target
+	t.Weights = slices.Clone(target.Weights)
+	return &t
+}
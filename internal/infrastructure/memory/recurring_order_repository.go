@@ -0,0 +1,156 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// RecurringOrderRepository is an in-memory implementation of repository.RecurringOrderRepository
+type RecurringOrderRepository struct {
+	store *Store
+}
+
+var _ repository.RecurringOrderRepository = (*RecurringOrderRepository)(nil)
+
+// Create stores a new recurring order
+func (r *RecurringOrderRepository) Create(ctx context.Context, order *model.RecurringOrder) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	o := *order
+	r.store.recurringOrders[o.ID] = &o
+	return nil
+}
+
+// GetByID retrieves a recurring order by ID
+func (r *RecurringOrderRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.RecurringOrder, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	order, exists := r.store.recurringOrders[id]
+	if !exists {
+		return nil, repository.ErrNotFound
+	}
+	o := *order
+	return &o, nil
+}
+
+// Update replaces a stored recurring order
+func (r *RecurringOrderRepository) Update(ctx context.Context, order *model.RecurringOrder) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.recurringOrders[order.ID]; !exists {
+		return repository.ErrNotFound
+	}
+	o := *order
+	r.store.recurringOrders[o.ID] = &o
+	return nil
+}
+
+// Delete removes a recurring order and its runs
+func (r *RecurringOrderRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.recurringOrders[id]; !exists {
+		return repository.ErrNotFound
+	}
+	delete(r.store.recurringOrders, id)
+	for runID, run := range r.store.recurringOrderRuns {
+		if run.RecurringOrderID == id {
+			delete(r.store.recurringOrderRuns, runID)
+		}
+	}
+	return nil
+}
+
+// ListByUser returns a user's recurring orders, oldest first
+func (r *RecurringOrderRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.RecurringOrder, error) {
+	orders := r.filter(func(o *model.RecurringOrder) bool {
+		return o.UserID == userID
+	})
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].CreatedAt.Before(orders[j].CreatedAt)
+	})
+	return orders, nil
+}
+
+// ListDue returns the active recurring orders whose next run is at or before now
+func (r *RecurringOrderRepository) ListDue(ctx context.Context, now time.Time) ([]*model.RecurringOrder, error) {
+	orders := r.filter(func(o *model.RecurringOrder) bool {
+		return o.Active && !o.NextRunAt.After(now)
+	})
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].NextRunAt.Before(orders[j].NextRunAt)
+	})
+	return orders, nil
+}
+
+// CreateRun stores a run unless one exists for the recurring order and time
+func (r *RecurringOrderRepository) CreateRun(ctx context.Context, run *model.RecurringOrderRun) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.recurringOrderRuns {
+		if existing.RecurringOrderID == run.RecurringOrderID && existing.ScheduledAt.Equal(run.ScheduledAt) {
+			return false, nil
+		}
+	}
+	rn := *run
+	r.store.recurringOrderRuns[rn.ID] = &rn
+	return true, nil
+}
+
+// UpdateRun records the outcome of a run
+func (r *RecurringOrderRepository) UpdateRun(ctx context.Context, run *model.RecurringOrderRun) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.recurringOrderRuns[run.ID]; !exists {
+		return repository.ErrNotFound
+	}
+	rn := *run
+	r.store.recurringOrderRuns[rn.ID] = &rn
+	return nil
+}
+
+// ListRuns returns a recurring order's runs, newest first
+func (r *RecurringOrderRepository) ListRuns(ctx context.Context, recurringOrderID uuid.UUID, limit int) ([]*model.RecurringOrderRun, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var runs []*model.RecurringOrderRun
+	for _, run := range r.store.recurringOrderRuns {
+		if run.RecurringOrderID == recurringOrderID {
+			rn := *run
+			runs = append(runs, &rn)
+		}
+	}
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].ScheduledAt.After(runs[j].ScheduledAt)
+	})
+	if limit > 0 && len(runs) > limit {
+		runs = runs[:limit]
+	}
+	return runs, nil
+}
+
+func (r *RecurringOrderRepository) filter(match func(*model.RecurringOrder) bool) []*model.RecurringOrder {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var orders []*model.RecurringOrder
+	for _, order := range r.store.recurringOrders {
+		if match(order) {
+			o := *order
+			orders = append(orders, &o)
+		}
+	}
+	return orders
+}
@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// TradingRetentionRepository is an in-memory implementation of repository.TradingRetentionRepository
+type TradingRetentionRepository struct {
+	store *Store
+}
+
+var _ repository.TradingRetentionRepository = (*TradingRetentionRepository)(nil)
+
+// ExpireOrders archives or deletes up to limit cancelled and failed orders
+// without fills last updated before cutoff, oldest first
+func (r *TradingRetentionRepository) ExpireOrders(ctx context.Context, mode model.RetentionMode, cutoff time.Time, limit int) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var expired []*model.Order
+	for _, o := range r.store.orders {
+		if orderExpired(o, cutoff) {
+			expired = append(expired, o)
+		}
+	}
+	sort.Slice(expired, func(i, j int) bool {
+		return expired[i].UpdatedAt.Before(expired[j].UpdatedAt)
+	})
+	if len(expired) > limit {
+		expired = expired[:limit]
+	}
+
+	for _, o := range expired {
+		if mode == model.RetentionArchive {
+			if err := r.archive(model.ArchivedOrder, o.ID, o.UserID, o); err != nil {
+				return 0, err
+			}
+		}
+		delete(r.store.orders, o.ID)
+	}
+	return len(expired), nil
+}
+
+// ExpireStrategies archives or deletes up to limit drawdown guards that
+// triggered before cutoff, oldest first
+func (r *TradingRetentionRepository) ExpireStrategies(ctx context.Context, mode model.RetentionMode, cutoff time.Time, limit int) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var expired []*model.DrawdownGuard
+	for _, g := range r.store.drawdownGuards {
+		if guardExpired(g, cutoff) {
+			expired = append(expired, g)
+		}
+	}
+	sort.Slice(expired, func(i, j int) bool {
+		return expired[i].TriggeredAt.Before(*expired[j].TriggeredAt)
+	})
+	if len(expired) > limit {
+		expired = expired[:limit]
+	}
+
+	for _, g := range expired {
+		if mode == model.RetentionArchive {
+			if err := r.archive(model.ArchivedDrawdownGuard, g.PositionID, g.UserID, g); err != nil {
+				return 0, err
+			}
+		}
+		delete(r.store.drawdownGuards, g.PositionID)
+		r.store.deleteDrawdownGuardUpdates(g.PositionID)
+	}
+	return len(expired), nil
+}
+
+// CountExpired returns how many orders and drawdown guards have expired
+func (r *TradingRetentionRepository) CountExpired(ctx context.Context, ordersBefore, strategiesBefore time.Time) (int, int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var orders, strategies int
+	for _, o := range r.store.orders {
+		if !ordersBefore.IsZero() && orderExpired(o, ordersBefore) {
+			orders++
+		}
+	}
+	for _, g := range r.store.drawdownGuards {
+		if !strategiesBefore.IsZero() && guardExpired(g, strategiesBefore) {
+			strategies++
+		}
+	}
+	return orders, strategies, nil
+}
+
+// archive stores a record in the archive; the store lock must be held
+func (r *TradingRetentionRepository) archive(kind string, id, userID uuid.UUID, record any) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode archived %s: %w", kind, err)
+	}
+	r.store.archivedRecords[id] = &model.ArchivedRecord{
+		Kind:       kind,
+		ID:         id,
+		UserID:     userID,
+		Data:       data,
+		ArchivedAt: time.Now(),
+	}
+	return nil
+}
+
+func orderExpired(o *model.Order, cutoff time.Time) bool {
+	return (o.Status == model.OrderStatusCancelled || o.Status == model.OrderStatusFailed) &&
+		o.ExecutedQuantity == 0 && o.UpdatedAt.Before(cutoff)
+}
+
+func guardExpired(g *model.DrawdownGuard, cutoff time.Time) bool {
+	return g.TriggeredAt != nil && g.TriggeredAt.Before(cutoff)
+}
@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// RiskLimitsRepository is an in-memory implementation of repository.RiskLimitsRepository
+type RiskLimitsRepository struct {
+	store *Store
+}
+
+var _ repository.RiskLimitsRepository = (*RiskLimitsRepository)(nil)
+
+// Get returns the user's limits
+func (r *RiskLimitsRepository) Get(ctx context.Context, userID uuid.UUID) (*model.RiskLimits, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	limits, exists := r.store.riskLimits[userID]
+	if !exists {
+		return nil, repository.ErrNotFound
+	}
+
+	l := *limits
+	return &l, nil
+}
+
+// Save creates or replaces the user's limits
+func (r *RiskLimitsRepository) Save(ctx context.Context, limits *model.RiskLimits) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	l := *limits
+	r.store.riskLimits[limits.UserID] = &l
+	return nil
+}
+
+// ListDailyLossLimits returns the limits of users with a daily loss limit
+func (r *RiskLimitsRepository) ListDailyLossLimits(ctx context.Context) ([]*model.RiskLimits, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var result []*model.RiskLimits
+	for _, limits := range r.store.riskLimits {
+		if limits.DailyLossLimit > 0 {
+			l := *limits
+			result = append(result, &l)
+		}
+	}
+	return result, nil
+}
+
+// RiskStateRepository is an in-memory implementation of repository.RiskStateRepository
+type RiskStateRepository struct {
+	store *Store
+}
+
+var _ repository.RiskStateRepository = (*RiskStateRepository)(nil)
+
+// Get returns the user's state
+func (r *RiskStateRepository) Get(ctx context.Context, userID uuid.UUID) (*model.RiskState, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	state, exists := r.store.riskStates[userID]
+	if !exists {
+		return nil, repository.ErrNotFound
+	}
+
+	s := *state
+	return &s, nil
+}
+
+// Save creates or replaces the user's state
+func (r *RiskStateRepository) Save(ctx context.Context, state *model.RiskState) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	s := *state
+	r.store.riskStates[state.UserID] = &s
+	return nil
+}
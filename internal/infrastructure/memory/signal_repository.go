@@ -0,0 +1,298 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// SignalSourceRepository is an in-memory implementation of repository.SignalSourceRepository
+type SignalSourceRepository struct {
+	store *Store
+}
+
+var _ repository.SignalSourceRepository = (*SignalSourceRepository)(nil)
+
+// Create stores a new signal source
+func (r *SignalSourceRepository) Create(ctx context.Context, source *model.SignalSource) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.signalSources[source.ID] = copySignalSource(source)
+	return nil
+}
+
+// GetByID retrieves a signal source by ID
+func (r *SignalSourceRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.SignalSource, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	source, exists := r.store.signalSources[id]
+	if !exists {
+		return nil, repository.ErrNotFound
+	}
+	return copySignalSource(source), nil
+}
+
+// GetByTokenHash retrieves the webhook source with a token hash
+func (r *SignalSourceRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*model.SignalSource, error) {
+	sources := r.filter(func(s *model.SignalSource) bool {
+		return s.TokenHash != "" && s.TokenHash == tokenHash
+	})
+	if len(sources) == 0 {
+		return nil, repository.ErrNotFound
+	}
+	return sources[0], nil
+}
+
+// Update replaces a stored signal source
+func (r *SignalSourceRepository) Update(ctx context.Context, source *model.SignalSource) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.signalSources[source.ID]; !exists {
+		return repository.ErrNotFound
+	}
+	r.store.signalSources[source.ID] = copySignalSource(source)
+	return nil
+}
+
+// Delete removes a signal source with its subscriptions and logs
+func (r *SignalSourceRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.signalSources[id]; !exists {
+		return repository.ErrNotFound
+	}
+	delete(r.store.signalSources, id)
+	for subID, sub := range r.store.signalSubscriptions {
+		if sub.SourceID == id {
+			delete(r.store.signalSubscriptions, subID)
+		}
+	}
+	for logID, log := range r.store.signalLogs {
+		if log.SourceID == id {
+			delete(r.store.signalLogs, logID)
+		}
+	}
+	return nil
+}
+
+// ListByUser returns the sources a user owns, oldest first
+func (r *SignalSourceRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.SignalSource, error) {
+	return r.filter(func(s *model.SignalSource) bool {
+		return s.UserID == userID
+	}), nil
+}
+
+// ListPublic returns the active public sources, oldest first
+func (r *SignalSourceRepository) ListPublic(ctx context.Context) ([]*model.SignalSource, error) {
+	return r.filter(func(s *model.SignalSource) bool {
+		return s.Public && s.Active
+	}), nil
+}
+
+// ListActive returns the active sources of a type
+func (r *SignalSourceRepository) ListActive(ctx context.Context, sourceType model.SignalSourceType) ([]*model.SignalSource, error) {
+	return r.filter(func(s *model.SignalSource) bool {
+		return s.Type == sourceType && s.Active
+	}), nil
+}
+
+// ListByTelegramChat returns the active sources reading a Telegram chat
+func (r *SignalSourceRepository) ListByTelegramChat(ctx context.Context, chatID int64) ([]*model.SignalSource, error) {
+	return r.filter(func(s *model.SignalSource) bool {
+		return s.Active && s.TelegramChatID != nil && *s.TelegramChatID == chatID
+	}), nil
+}
+
+// filter returns copies of the matching sources, oldest first
+func (r *SignalSourceRepository) filter(match func(*model.SignalSource) bool) []*model.SignalSource {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var sources []*model.SignalSource
+	for _, source := range r.store.signalSources {
+		if match(source) {
+			sources = append(sources, copySignalSource(source))
+		}
+	}
+	sort.Slice(sources, func(i, j int) bool {
+		return sources[i].CreatedAt.Before(sources[j].CreatedAt)
+	})
+	return sources
+}
+
+func copySignalSource(source *model.SignalSource) *model.SignalSource {
+	s := *source
+	s.Markets = slices.Clone(source.Markets)
+	s.Strategies = slices.Clone(source.Strategies)
+	if source.TelegramChatID != nil {
+		chatID := *source.TelegramChatID
+		s.TelegramChatID = &chatID
+	}
+	if source.Indicator != nil {
+		indicator := *source.Indicator
+		s.Indicator = &indicator
+	}
+	return &s
+}
+
+// SignalSubscriptionRepository is an in-memory implementation of repository.SignalSubscriptionRepository
+type SignalSubscriptionRepository struct {
+	store *Store
+}
+
+var _ repository.SignalSubscriptionRepository = (*SignalSubscriptionRepository)(nil)
+
+// Create stores a new subscription
+func (r *SignalSubscriptionRepository) Create(ctx context.Context, subscription *model.SignalSubscription) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	s := *subscription
+	r.store.signalSubscriptions[s.ID] = &s
+	return nil
+}
+
+// GetByID retrieves a subscription by ID
+func (r *SignalSubscriptionRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.SignalSubscription, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	subscription, exists := r.store.signalSubscriptions[id]
+	if !exists {
+		return nil, repository.ErrNotFound
+	}
+	s := *subscription
+	return &s, nil
+}
+
+// Update replaces a stored subscription
+func (r *SignalSubscriptionRepository) Update(ctx context.Context, subscription *model.SignalSubscription) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.signalSubscriptions[subscription.ID]; !exists {
+		return repository.ErrNotFound
+	}
+	s := *subscription
+	r.store.signalSubscriptions[s.ID] = &s
+	return nil
+}
+
+// Delete removes a subscription
+func (r *SignalSubscriptionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.signalSubscriptions[id]; !exists {
+		return repository.ErrNotFound
+	}
+	delete(r.store.signalSubscriptions, id)
+	return nil
+}
+
+// ListByUser returns a user's subscriptions, oldest first
+func (r *SignalSubscriptionRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.SignalSubscription, error) {
+	return r.filter(func(s *model.SignalSubscription) bool {
+		return s.UserID == userID
+	}), nil
+}
+
+// ListBySource returns the active subscriptions to a source
+func (r *SignalSubscriptionRepository) ListBySource(ctx context.Context, sourceID uuid.UUID) ([]*model.SignalSubscription, error) {
+	return r.filter(func(s *model.SignalSubscription) bool {
+		return s.SourceID == sourceID && s.Active
+	}), nil
+}
+
+// filter returns copies of the matching subscriptions, oldest first
+func (r *SignalSubscriptionRepository) filter(match func(*model.SignalSubscription) bool) []*model.SignalSubscription {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var subscriptions []*model.SignalSubscription
+	for _, subscription := range r.store.signalSubscriptions {
+		if match(subscription) {
+			s := *subscription
+			subscriptions = append(subscriptions, &s)
+		}
+	}
+	sort.Slice(subscriptions, func(i, j int) bool {
+		return subscriptions[i].CreatedAt.Before(subscriptions[j].CreatedAt)
+	})
+	return subscriptions
+}
+
+// SignalLogRepository is an in-memory implementation of repository.SignalLogRepository
+type SignalLogRepository struct {
+	store *Store
+}
+
+var _ repository.SignalLogRepository = (*SignalLogRepository)(nil)
+
+// Create stores a signal log
+func (r *SignalLogRepository) Create(ctx context.Context, log *model.SignalLog) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	l := *log
+	l.OrderIDs = slices.Clone(log.OrderIDs)
+	r.store.signalLogs[l.ID] = &l
+	return nil
+}
+
+// ListBySource returns a source's logs, newest first
+func (r *SignalLogRepository) ListBySource(ctx context.Context, sourceID uuid.UUID, limit int) ([]*model.SignalLog, error) {
+	return r.filter(func(l *model.SignalLog) bool {
+		return l.SourceID == sourceID
+	}, limit), nil
+}
+
+// ListBySubscription returns a subscription's logs, newest first
+func (r *SignalLogRepository) ListBySubscription(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]*model.SignalLog, error) {
+	return r.filter(func(l *model.SignalLog) bool {
+		return l.SubscriptionID != nil && *l.SubscriptionID == subscriptionID
+	}, limit), nil
+}
+
+// CountOrders returns how many orders a subscription placed on signals
+// received at or after since
+func (r *SignalLogRepository) CountOrders(ctx context.Context, subscriptionID uuid.UUID, since time.Time) (int, error) {
+	var count int
+	for _, l := range r.filter(func(l *model.SignalLog) bool {
+		return l.SubscriptionID != nil && *l.SubscriptionID == subscriptionID && !l.ReceivedAt.Before(since)
+	}, 0) {
+		count += len(l.OrderIDs)
+	}
+	return count, nil
+}
+
+// filter returns copies of the matching logs, newest first
+func (r *SignalLogRepository) filter(match func(*model.SignalLog) bool, limit int) []*model.SignalLog {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var logs []*model.SignalLog
+	for _, log := range r.store.signalLogs {
+		if match(log) {
+			l := *log
+			l.OrderIDs = slices.Clone(log.OrderIDs)
+			logs = append(logs, &l)
+		}
+	}
+	sort.Slice(logs, func(i, j int) bool {
+		return logs[i].ReceivedAt.After(logs[j].ReceivedAt)
+	})
+	if limit > 0 && len(logs) > limit {
+		logs = logs[:limit]
+	}
+	return logs
+}
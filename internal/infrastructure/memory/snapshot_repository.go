@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// SnapshotRepository is an in-memory implementation of repository.SnapshotRepository
+type SnapshotRepository struct {
+	store *Store
+}
+
+var _ repository.SnapshotRepository = (*SnapshotRepository)(nil)
+
+// Create stores a snapshot unless one already exists for the user, period and time
+func (r *SnapshotRepository) Create(ctx context.Context, snapshot *model.AccountSnapshot) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, existing := range r.store.snapshots {
+		if existing.UserID == snapshot.UserID && existing.Period == snapshot.Period && existing.TakenAt.Equal(snapshot.TakenAt) {
+			return nil
+		}
+	}
+
+	r.store.snapshots[snapshot.ID] = copySnapshot(snapshot)
+	return nil
+}
+
+// ListByUser returns a user's snapshots taken in [from, to), oldest first
+func (r *SnapshotRepository) ListByUser(ctx context.Context, userID uuid.UUID, period model.SnapshotPeriod, from, to time.Time) ([]*model.AccountSnapshot, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var snapshots []*model.AccountSnapshot
+	for _, snapshot := range r.store.snapshots {
+		if snapshot.UserID != userID || snapshot.Period != period {
+			continue
+		}
+		if snapshot.TakenAt.Before(from) || !snapshot.TakenAt.Before(to) {
+			continue
+		}
+		snapshots = append(snapshots, copySnapshot(snapshot))
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].TakenAt.Before(snapshots[j].TakenAt)
+	})
+	return snapshots, nil
+}
+
+func copySnapshot(snapshot *model.AccountSnapshot) *model.AccountSnapshot {
+	s := *snapshot
+	s.Balances = slices.Clone(snapshot.Balances)
+	s.Positions = slices.Clone(snapshot.Positions)
+	return &s
+}
@@ -0,0 +1,451 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// Store is an in-memory backend for all repositories, intended for tests and
+// local runs without PostgreSQL. Records are copied on the way in and out so
+// callers can't mutate stored state without going through a repository.
+type Store struct {
+	orders               map[uuid.UUID]*model.Order
+	executions           map[uuid.UUID]*model.OrderExecution
+	positions            map[uuid.UUID]*model.Position
+	positionEvents       map[uuid.UUID]*model.PositionEvent
+	apiKeys              map[uuid.UUID]*model.UserAPIKey
+	outbox               map[uuid.UUID]*model.OutboxEvent
+	snapshots            map[uuid.UUID]*model.AccountSnapshot
+	backtests            map[uuid.UUID]*model.BacktestRun
+	alerts               map[uuid.UUID]*model.PriceAlert
+	telegramLinks        map[uuid.UUID]*model.TelegramLink         // By user ID
+	notificationSettings map[uuid.UUID]*model.NotificationSettings // By user ID
+	webhooks             map[uuid.UUID]*model.Webhook
+	webhookDeliveries    map[uuid.UUID]*model.WebhookDelivery
+	riskLimits           map[uuid.UUID]*model.RiskLimits    // By user ID
+	riskStates           map[uuid.UUID]*model.RiskState     // By user ID
+	tradingHalts         map[uuid.UUID]*model.TradingHalt   // By user ID; uuid.Nil is the global halt
+	drawdownGuards       map[uuid.UUID]*model.DrawdownGuard // By position ID
+	drawdownGuardUpdates map[uuid.UUID]*model.DrawdownGuardUpdate
+	velocityLimits       map[uuid.UUID]*model.VelocityLimits  // By user ID
+	targetPortfolios     map[uuid.UUID]*model.TargetPortfolio // By user ID
+	collectorShards      map[collectorShardKey]*model.CollectorShard
+	candleBackfills      map[candleBackfillKey]*model.CandleBackfill
+	cashLedger           map[uuid.UUID]*model.CashLedgerEntry
+	recurringOrders      map[uuid.UUID]*model.RecurringOrder
+	recurringOrderRuns   map[uuid.UUID]*model.RecurringOrderRun
+	orderPresets         map[uuid.UUID]*model.OrderPreset
+	maintenanceWindows   map[uuid.UUID]*model.MaintenanceWindow
+	queuedJobs           map[uuid.UUID]*model.QueuedJob
+	queuedJobResults     map[uuid.UUID]*model.QueuedJobResult // By job ID
+	signalSources        map[uuid.UUID]*model.SignalSource
+	signalSubscriptions  map[uuid.UUID]*model.SignalSubscription
+	signalLogs           map[uuid.UUID]*model.SignalLog
+	withdrawAddresses    map[uuid.UUID]*model.WithdrawAddress
+	paperAccounts        map[uuid.UUID]*model.PaperAccount
+	strategyTemplates    map[uuid.UUID]*model.StrategyTemplate
+	archivedRecords      map[uuid.UUID]*model.ArchivedRecord
+	mu                   sync.RWMutex
+	txMu                 sync.Mutex // serializes UnitOfWork transactions
+}
+
+// NewStore creates an empty in-memory store
+func NewStore() *Store {
+	return &Store{
+		orders:               make(map[uuid.UUID]*model.Order),
+		executions:           make(map[uuid.UUID]*model.OrderExecution),
+		positions:            make(map[uuid.UUID]*model.Position),
+		positionEvents:       make(map[uuid.UUID]*model.PositionEvent),
+		apiKeys:              make(map[uuid.UUID]*model.UserAPIKey),
+		outbox:               make(map[uuid.UUID]*model.OutboxEvent),
+		snapshots:            make(map[uuid.UUID]*model.AccountSnapshot),
+		backtests:            make(map[uuid.UUID]*model.BacktestRun),
+		alerts:               make(map[uuid.UUID]*model.PriceAlert),
+		telegramLinks:        make(map[uuid.UUID]*model.TelegramLink),
+		notificationSettings: make(map[uuid.UUID]*model.NotificationSettings),
+		webhooks:             make(map[uuid.UUID]*model.Webhook),
+		webhookDeliveries:    make(map[uuid.UUID]*model.WebhookDelivery),
+		riskLimits:           make(map[uuid.UUID]*model.RiskLimits),
+		riskStates:           make(map[uuid.UUID]*model.RiskState),
+		tradingHalts:         make(map[uuid.UUID]*model.TradingHalt),
+		drawdownGuards:       make(map[uuid.UUID]*model.DrawdownGuard),
+		drawdownGuardUpdates: make(map[uuid.UUID]*model.DrawdownGuardUpdate),
+		velocityLimits:       make(map[uuid.UUID]*model.VelocityLimits),
+		targetPortfolios:     make(map[uuid.UUID]*model.TargetPortfolio),
+		collectorShards:      make(map[collectorShardKey]*model.CollectorShard),
+		candleBackfills:      make(map[candleBackfillKey]*model.CandleBackfill),
+		cashLedger:           make(map[uuid.UUID]*model.CashLedgerEntry),
+		recurringOrders:      make(map[uuid.UUID]*model.RecurringOrder),
+		recurringOrderRuns:   make(map[uuid.UUID]*model.RecurringOrderRun),
+		orderPresets:         make(map[uuid.UUID]*model.OrderPreset),
+		maintenanceWindows:   make(map[uuid.UUID]*model.MaintenanceWindow),
+		queuedJobs:           make(map[uuid.UUID]*model.QueuedJob),
+		queuedJobResults:     make(map[uuid.UUID]*model.QueuedJobResult),
+		signalSources:        make(map[uuid.UUID]*model.SignalSource),
+		signalSubscriptions:  make(map[uuid.UUID]*model.SignalSubscription),
+		signalLogs:           make(map[uuid.UUID]*model.SignalLog),
+		withdrawAddresses:    make(map[uuid.UUID]*model.WithdrawAddress),
+		paperAccounts:        make(map[uuid.UUID]*model.PaperAccount),
+		strategyTemplates:    make(map[uuid.UUID]*model.StrategyTemplate),
+		archivedRecords:      make(map[uuid.UUID]*model.ArchivedRecord),
+	}
+}
+
+var _ repository.UnitOfWork = (*Store)(nil)
+
+// Orders returns the order repository
+func (s *Store) Orders() *OrderRepository {
+	return &OrderRepository{store: s}
+}
+
+// Executions returns the order execution repository
+func (s *Store) Executions() *OrderExecutionRepository {
+	return &OrderExecutionRepository{store: s}
+}
+
+// Positions returns the position repository
+func (s *Store) Positions() *PositionRepository {
+	return &PositionRepository{store: s}
+}
+
+// PositionEvents returns the position event repository
+func (s *Store) PositionEvents() *PositionEventRepository {
+	return &PositionEventRepository{store: s}
+}
+
+// APIKeys returns the API key repository
+func (s *Store) APIKeys() *UserAPIKeyRepository {
+	return &UserAPIKeyRepository{store: s}
+}
+
+// Outbox returns the outbox repository
+func (s *Store) Outbox() *OutboxRepository {
+	return &OutboxRepository{store: s}
+}
+
+// Snapshots returns the account snapshot repository
+func (s *Store) Snapshots() *SnapshotRepository {
+	return &SnapshotRepository{store: s}
+}
+
+// Backtests returns the backtest run repository
+func (s *Store) Backtests() *BacktestRepository {
+	return &BacktestRepository{store: s}
+}
+
+// Alerts returns the price alert repository
+func (s *Store) Alerts() *PriceAlertRepository {
+	return &PriceAlertRepository{store: s}
+}
+
+// TelegramLinks returns the Telegram link repository
+func (s *Store) TelegramLinks() *TelegramLinkRepository {
+	return &TelegramLinkRepository{store: s}
+}
+
+// NotificationSettings returns the notification settings repository
+func (s *Store) NotificationSettings() *NotificationSettingsRepository {
+	return &NotificationSettingsRepository{store: s}
+}
+
+// Webhooks returns the webhook repository
+func (s *Store) Webhooks() *WebhookRepository {
+	return &WebhookRepository{store: s}
+}
+
+// WebhookDeliveries returns the webhook delivery repository
+func (s *Store) WebhookDeliveries() *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{store: s}
+}
+
+// RiskLimits returns the risk limits repository
+func (s *Store) RiskLimits() *RiskLimitsRepository {
+	return &RiskLimitsRepository{store: s}
+}
+
+// RiskStates returns the risk state repository
+func (s *Store) RiskStates() *RiskStateRepository {
+	return &RiskStateRepository{store: s}
+}
+
+// TradingHalts returns the trading halt repository
+func (s *Store) TradingHalts() *TradingHaltRepository {
+	return &TradingHaltRepository{store: s}
+}
+
+// DrawdownGuards returns the drawdown guard repository
+func (s *Store) DrawdownGuards() *DrawdownGuardRepository {
+	return &DrawdownGuardRepository{store: s}
+}
+
+// DrawdownGuardUpdates returns the drawdown guard update repository
+func (s *Store) DrawdownGuardUpdates() *DrawdownGuardUpdateRepository {
+	return &DrawdownGuardUpdateRepository{store: s}
+}
+
+// VelocityLimits returns the order velocity limit repository
+func (s *Store) VelocityLimits() *VelocityLimitRepository {
+	return &VelocityLimitRepository{store: s}
+}
+
+// TargetPortfolios returns the target portfolio repository
+func (s *Store) TargetPortfolios() *TargetPortfolioRepository {
+	return &TargetPortfolioRepository{store: s}
+}
+
+// CollectorShards returns the candle collector shard repository
+func (s *Store) CollectorShards() *CollectorShardRepository {
+	return &CollectorShardRepository{store: s}
+}
+
+// CandleBackfills returns the candle backfill repository
+func (s *Store) CandleBackfills() *CandleBackfillRepository {
+	return &CandleBackfillRepository{store: s}
+}
+
+// CashLedger returns the cash ledger repository
+func (s *Store) CashLedger() *CashLedgerRepository {
+	return &CashLedgerRepository{store: s}
+}
+
+// RecurringOrders returns the recurring order repository
+func (s *Store) RecurringOrders() *RecurringOrderRepository {
+	return &RecurringOrderRepository{store: s}
+}
+
+// OrderPresets returns the order preset repository
+func (s *Store) OrderPresets() *OrderPresetRepository {
+	return &OrderPresetRepository{store: s}
+}
+
+// MaintenanceWindows returns the maintenance window repository
+func (s *Store) MaintenanceWindows() *MaintenanceWindowRepository {
+	return &MaintenanceWindowRepository{store: s}
+}
+
+// SignalSources returns the signal source repository
+func (s *Store) SignalSources() *SignalSourceRepository {
+	return &SignalSourceRepository{store: s}
+}
+
+// SignalSubscriptions returns the signal subscription repository
+func (s *Store) SignalSubscriptions() *SignalSubscriptionRepository {
+	return &SignalSubscriptionRepository{store: s}
+}
+
+// SignalLogs returns the signal log repository
+func (s *Store) SignalLogs() *SignalLogRepository {
+	return &SignalLogRepository{store: s}
+}
+
+// WithdrawAddresses returns the withdrawal address book repository
+func (s *Store) WithdrawAddresses() *WithdrawAddressRepository {
+	return &WithdrawAddressRepository{store: s}
+}
+
+// PaperAccounts returns the paper trading account repository
+func (s *Store) PaperAccounts() *PaperAccountRepository {
+	return &PaperAccountRepository{store: s}
+}
+
+// StrategyTemplates returns the strategy template catalog repository
+func (s *Store) StrategyTemplates() *StrategyTemplateRepository {
+	return &StrategyTemplateRepository{store: s}
+}
+
+// Retention returns the trading retention repository
+func (s *Store) Retention() *TradingRetentionRepository {
+	return &TradingRetentionRepository{store: s}
+}
+
+// Jobs returns the job queue repository
+func (s *Store) Jobs() *JobQueueRepository {
+	return &JobQueueRepository{store: s}
+}
+
+// Do runs fn atomically: transactions are serialized and all changes made by
+// fn are rolled back if it returns an error
+func (s *Store) Do(ctx context.Context, fn func(tx repository.Tx) error) error {
+	s.txMu.Lock()
+	defer s.txMu.Unlock()
+
+	snapshot := s.snapshot()
+	if err := fn(&txRepositories{store: s}); err != nil {
+		s.restore(snapshot)
+		return err
+	}
+	return nil
+}
+
+type storeSnapshot struct {
+	orders               map[uuid.UUID]*model.Order
+	executions           map[uuid.UUID]*model.OrderExecution
+	positions            map[uuid.UUID]*model.Position
+	positionEvents       map[uuid.UUID]*model.PositionEvent
+	apiKeys              map[uuid.UUID]*model.UserAPIKey
+	outbox               map[uuid.UUID]*model.OutboxEvent
+	snapshots            map[uuid.UUID]*model.AccountSnapshot
+	backtests            map[uuid.UUID]*model.BacktestRun
+	alerts               map[uuid.UUID]*model.PriceAlert
+	telegramLinks        map[uuid.UUID]*model.TelegramLink
+	notificationSettings map[uuid.UUID]*model.NotificationSettings
+	webhooks             map[uuid.UUID]*model.Webhook
+	webhookDeliveries    map[uuid.UUID]*model.WebhookDelivery
+	riskLimits           map[uuid.UUID]*model.RiskLimits
+	riskStates           map[uuid.UUID]*model.RiskState
+	tradingHalts         map[uuid.UUID]*model.TradingHalt
+	drawdownGuards       map[uuid.UUID]*model.DrawdownGuard
+	drawdownGuardUpdates map[uuid.UUID]*model.DrawdownGuardUpdate
+	velocityLimits       map[uuid.UUID]*model.VelocityLimits
+	targetPortfolios     map[uuid.UUID]*model.TargetPortfolio
+	collectorShards      map[collectorShardKey]*model.CollectorShard
+	candleBackfills      map[candleBackfillKey]*model.CandleBackfill
+	cashLedger           map[uuid.UUID]*model.CashLedgerEntry
+	recurringOrders      map[uuid.UUID]*model.RecurringOrder
+	recurringOrderRuns   map[uuid.UUID]*model.RecurringOrderRun
+	orderPresets         map[uuid.UUID]*model.OrderPreset
+	maintenanceWindows   map[uuid.UUID]*model.MaintenanceWindow
+	queuedJobs           map[uuid.UUID]*model.QueuedJob
+	queuedJobResults     map[uuid.UUID]*model.QueuedJobResult
+	signalSources        map[uuid.UUID]*model.SignalSource
+	signalSubscriptions  map[uuid.UUID]*model.SignalSubscription
+	signalLogs           map[uuid.UUID]*model.SignalLog
+	withdrawAddresses    map[uuid.UUID]*model.WithdrawAddress
+	paperAccounts        map[uuid.UUID]*model.PaperAccount
+	strategyTemplates    map[uuid.UUID]*model.StrategyTemplate
+	archivedRecords      map[uuid.UUID]*model.ArchivedRecord
+}
+
+// snapshot copies the maps; stored records are never mutated in place so a
+// shallow copy is enough
+func (s *Store) snapshot() storeSnapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return storeSnapshot{
+		orders:               maps.Clone(s.orders),
+		executions:           maps.Clone(s.executions),
+		positions:            maps.Clone(s.positions),
+		positionEvents:       maps.Clone(s.positionEvents),
+		apiKeys:              maps.Clone(s.apiKeys),
+		outbox:               maps.Clone(s.outbox),
+		snapshots:            maps.Clone(s.snapshots),
+		backtests:            maps.Clone(s.backtests),
+		alerts:               maps.Clone(s.alerts),
+		telegramLinks:        maps.Clone(s.telegramLinks),
+		notificationSettings: maps.Clone(s.notificationSettings),
+		webhooks:             maps.Clone(s.webhooks),
+		webhookDeliveries:    maps.Clone(s.webhookDeliveries),
+		riskLimits:           maps.Clone(s.riskLimits),
+		riskStates:           maps.Clone(s.riskStates),
+		tradingHalts:         maps.Clone(s.tradingHalts),
+		drawdownGuards:       maps.Clone(s.drawdownGuards),
+		drawdownGuardUpdates: maps.Clone(s.drawdownGuardUpdates),
+		velocityLimits:       maps.Clone(s.velocityLimits),
+		targetPortfolios:     maps.Clone(s.targetPortfolios),
+		collectorShards:      maps.Clone(s.collectorShards),
+		candleBackfills:      maps.Clone(s.candleBackfills),
+		cashLedger:           maps.Clone(s.cashLedger),
+		recurringOrders:      maps.Clone(s.recurringOrders),
+		recurringOrderRuns:   maps.Clone(s.recurringOrderRuns),
+		orderPresets:         maps.Clone(s.orderPresets),
+		maintenanceWindows:   maps.Clone(s.maintenanceWindows),
+		queuedJobs:           maps.Clone(s.queuedJobs),
+		queuedJobResults:     maps.Clone(s.queuedJobResults),
+		signalSources:        maps.Clone(s.signalSources),
+		signalSubscriptions:  maps.Clone(s.signalSubscriptions),
+		signalLogs:           maps.Clone(s.signalLogs),
+		withdrawAddresses:    maps.Clone(s.withdrawAddresses),
+		paperAccounts:        maps.Clone(s.paperAccounts),
+		strategyTemplates:    maps.Clone(s.strategyTemplates),
+		archivedRecords:      maps.Clone(s.archivedRecords),
+	}
+}
+
+func (s *Store) restore(snapshot storeSnapshot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.orders = snapshot.orders
+	s.executions = snapshot.executions
+	s.positions = snapshot.positions
+	s.positionEvents = snapshot.positionEvents
+	s.apiKeys = snapshot.apiKeys
+	s.outbox = snapshot.outbox
+	s.snapshots = snapshot.snapshots
+	s.backtests = snapshot.backtests
+	s.alerts = snapshot.alerts
+	s.telegramLinks = snapshot.telegramLinks
+	s.notificationSettings = snapshot.notificationSettings
+	s.webhooks = snapshot.webhooks
+	s.webhookDeliveries = snapshot.webhookDeliveries
+	s.riskLimits = snapshot.riskLimits
+	s.riskStates = snapshot.riskStates
+	s.tradingHalts = snapshot.tradingHalts
+	s.drawdownGuards = snapshot.drawdownGuards
+	s.drawdownGuardUpdates = snapshot.drawdownGuardUpdates
+	s.velocityLimits = snapshot.velocityLimits
+	s.targetPortfolios = snapshot.targetPortfolios
+	s.collectorShards = snapshot.collectorShards
+	s.candleBackfills = snapshot.candleBackfills
+	s.cashLedger = snapshot.cashLedger
+	s.recurringOrders = snapshot.recurringOrders
+	s.recurringOrderRuns = snapshot.recurringOrderRuns
+	s.orderPresets = snapshot.orderPresets
+	s.maintenanceWindows = snapshot.maintenanceWindows
+	s.queuedJobs = snapshot.queuedJobs
+	s.queuedJobResults = snapshot.queuedJobResults
+	s.signalSources = snapshot.signalSources
+	s.signalSubscriptions = snapshot.signalSubscriptions
+	s.signalLogs = snapshot.signalLogs
+	s.withdrawAddresses = snapshot.withdrawAddresses
+	s.paperAccounts = snapshot.paperAccounts
+	s.strategyTemplates = snapshot.strategyTemplates
+	s.archivedRecords = snapshot.archivedRecords
+}
+
+// txRepositories exposes the store's repositories inside a transaction
+type txRepositories struct {
+	store *Store
+}
+
+func (t *txRepositories) Orders() repository.OrderRepository {
+	return t.store.Orders()
+}
+
+func (t *txRepositories) Executions() repository.OrderExecutionRepository {
+	return t.store.Executions()
+}
+
+func (t *txRepositories) Positions() repository.PositionRepository {
+	return t.store.Positions()
+}
+
+func (t *txRepositories) PositionEvents() repository.PositionEventRepository {
+	return t.store.PositionEvents()
+}
+
+func (t *txRepositories) Outbox() repository.OutboxRepository {
+	return t.store.Outbox()
+}
+
+func (t *txRepositories) Jobs() repository.JobQueueRepository {
+	return t.store.Jobs()
+}
+
+func (t *txRepositories) WebhookDeliveries() repository.WebhookDeliveryRepository {
+	return t.store.WebhookDeliveries()
+}
+
+func (t *txRepositories) DrawdownGuards() repository.DrawdownGuardRepository {
+	return t.store.DrawdownGuards()
+}
+
+func (t *txRepositories) DrawdownGuardUpdates() repository.DrawdownGuardUpdateRepository {
+	return t.store.DrawdownGuardUpdates()
+}
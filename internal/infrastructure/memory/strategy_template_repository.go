@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// StrategyTemplateRepository is an in-memory implementation of repository.StrategyTemplateRepository
+type StrategyTemplateRepository struct {
+	store *Store
+}
+
+var _ repository.StrategyTemplateRepository = (*StrategyTemplateRepository)(nil)
+
+// Create inserts a new strategy template
+func (r *StrategyTemplateRepository) Create(ctx context.Context, template *model.StrategyTemplate) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.strategyTemplates[template.ID] = cloneStrategyTemplate(template)
+	return nil
+}
+
+// GetByID retrieves a strategy template
+func (r *StrategyTemplateRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.StrategyTemplate, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	template, exists := r.store.strategyTemplates[id]
+	if !exists {
+		return nil, repository.ErrNotFound
+	}
+	return cloneStrategyTemplate(template), nil
+}
+
+// List returns templates most used first, then newest first
+func (r *StrategyTemplateRepository) List(ctx context.Context, filter repository.StrategyTemplateFilter) ([]*model.StrategyTemplate, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var templates []*model.StrategyTemplate
+	for _, template := range r.store.strategyTemplates {
+		if (filter.Type != "" && template.Type != filter.Type) ||
+			(filter.PublisherID != uuid.Nil && template.PublisherID != filter.PublisherID) {
+			continue
+		}
+		templates = append(templates, cloneStrategyTemplate(template))
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		if templates[i].UsageCount != templates[j].UsageCount {
+			return templates[i].UsageCount > templates[j].UsageCount
+		}
+		return templates[i].CreatedAt.After(templates[j].CreatedAt)
+	})
+	if filter.Limit > 0 && len(templates) > filter.Limit {
+		templates = templates[:filter.Limit]
+	}
+	return templates, nil
+}
+
+// Delete removes a strategy template
+func (r *StrategyTemplateRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.strategyTemplates[id]; !exists {
+		return repository.ErrNotFound
+	}
+	delete(r.store.strategyTemplates, id)
+	return nil
+}
+
+// AddUsage adds n to a template's usage count
+func (r *StrategyTemplateRepository) AddUsage(ctx context.Context, id uuid.UUID, n int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	template, exists := r.store.strategyTemplates[id]
+	if !exists {
+		return repository.ErrNotFound
+	}
+	t := cloneStrategyTemplate(template)
+	t.UsageCount += n
+	t.UpdatedAt = time.Now()
+	r.store.strategyTemplates[id] = t
+	return nil
+}
+
+func cloneStrategyTemplate(template *model.StrategyTemplate) *model.StrategyTemplate {
+	t := *template
+	t.Params = maps.Clone(template.Params)
+	if template.Backtest != nil {
+		backtest := *template.Backtest
+		t.Backtest = &backtest
+	}
+	return &t
+}
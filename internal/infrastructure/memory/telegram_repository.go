@@ -0,0 +1,72 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// TelegramLinkRepository is an in-memory implementation of repository.TelegramLinkRepository
+type TelegramLinkRepository struct {
+	store *Store
+}
+
+var _ repository.TelegramLinkRepository = (*TelegramLinkRepository)(nil)
+
+// Save stores a link, replacing any existing link of the user or the chat
+func (r *TelegramLinkRepository) Save(ctx context.Context, link *model.TelegramLink) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for userID, existing := range r.store.telegramLinks {
+		if existing.ChatID == link.ChatID {
+			delete(r.store.telegramLinks, userID)
+		}
+	}
+
+	l := *link
+	r.store.telegramLinks[link.UserID] = &l
+	return nil
+}
+
+// GetByUserID returns the link of a user
+func (r *TelegramLinkRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*model.TelegramLink, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	link, exists := r.store.telegramLinks[userID]
+	if !exists {
+		return nil, repository.ErrNotFound
+	}
+
+	l := *link
+	return &l, nil
+}
+
+// GetByChatID returns the link of a chat
+func (r *TelegramLinkRepository) GetByChatID(ctx context.Context, chatID int64) (*model.TelegramLink, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, link := range r.store.telegramLinks {
+		if link.ChatID == chatID {
+			l := *link
+			return &l, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+// DeleteByUserID removes the link of a user
+func (r *TelegramLinkRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.telegramLinks[userID]; !exists {
+		return repository.ErrNotFound
+	}
+	delete(r.store.telegramLinks, userID)
+	return nil
+}
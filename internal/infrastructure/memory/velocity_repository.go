@@ -0,0 +1,52 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// VelocityLimitRepository is an in-memory implementation of repository.VelocityLimitRepository
+type VelocityLimitRepository struct {
+	store *Store
+}
+
+var _ repository.VelocityLimitRepository = (*VelocityLimitRepository)(nil)
+
+// Get returns the override of a user
+func (r *VelocityLimitRepository) Get(ctx context.Context, userID uuid.UUID) (*model.VelocityLimits, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	limits, exists := r.store.velocityLimits[userID]
+	if !exists {
+		return nil, repository.ErrNotFound
+	}
+
+	l := *limits
+	return &l, nil
+}
+
+// Save creates or replaces an override
+func (r *VelocityLimitRepository) Save(ctx context.Context, limits *model.VelocityLimits) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	l := *limits
+	r.store.velocityLimits[limits.UserID] = &l
+	return nil
+}
+
+// Delete removes an override
+func (r *VelocityLimitRepository) Delete(ctx context.Context, userID uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.velocityLimits[userID]; !exists {
+		return repository.ErrNotFound
+	}
+	delete(r.store.velocityLimits, userID)
+	return nil
+}
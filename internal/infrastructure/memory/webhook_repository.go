@@ -0,0 +1,166 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// defaultWebhookDeliveryListLimit bounds listings that don't set a limit
+const defaultWebhookDeliveryListLimit = 50
+
+// WebhookRepository is an in-memory implementation of repository.WebhookRepository
+type WebhookRepository struct {
+	store *Store
+}
+
+var _ repository.WebhookRepository = (*WebhookRepository)(nil)
+
+// Create inserts a new webhook
+func (r *WebhookRepository) Create(ctx context.Context, webhook *model.Webhook) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.webhooks[webhook.ID] = copyWebhook(webhook)
+	return nil
+}
+
+// GetByID retrieves a webhook by ID
+func (r *WebhookRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Webhook, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	webhook, exists := r.store.webhooks[id]
+	if !exists {
+		return nil, repository.ErrNotFound
+	}
+	return copyWebhook(webhook), nil
+}
+
+// Update stores a webhook's settings and secret
+func (r *WebhookRepository) Update(ctx context.Context, webhook *model.Webhook) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.webhooks[webhook.ID]; !exists {
+		return repository.ErrNotFound
+	}
+	r.store.webhooks[webhook.ID] = copyWebhook(webhook)
+	return nil
+}
+
+// Delete removes a webhook and its deliveries
+func (r *WebhookRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.webhooks[id]; !exists {
+		return repository.ErrNotFound
+	}
+	delete(r.store.webhooks, id)
+	for deliveryID, delivery := range r.store.webhookDeliveries {
+		if delivery.WebhookID == id {
+			delete(r.store.webhookDeliveries, deliveryID)
+		}
+	}
+	return nil
+}
+
+// ListByUser returns the user's webhooks, newest first
+func (r *WebhookRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.Webhook, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var webhooks []*model.Webhook
+	for _, webhook := range r.store.webhooks {
+		if webhook.UserID == userID {
+			webhooks = append(webhooks, copyWebhook(webhook))
+		}
+	}
+	sort.Slice(webhooks, func(i, j int) bool {
+		return webhooks[i].CreatedAt.After(webhooks[j].CreatedAt)
+	})
+	return webhooks, nil
+}
+
+func copyWebhook(webhook *model.Webhook) *model.Webhook {
+	w := *webhook
+	w.Events = slices.Clone(webhook.Events)
+	return &w
+}
+
+// WebhookDeliveryRepository is an in-memory implementation of repository.WebhookDeliveryRepository
+type WebhookDeliveryRepository struct {
+	store *Store
+}
+
+var _ repository.WebhookDeliveryRepository = (*WebhookDeliveryRepository)(nil)
+
+// Create inserts a new delivery
+func (r *WebhookDeliveryRepository) Create(ctx context.Context, delivery *model.WebhookDelivery) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	d := *delivery
+	r.store.webhookDeliveries[delivery.ID] = &d
+	return nil
+}
+
+// GetByID retrieves a delivery by ID
+func (r *WebhookDeliveryRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.WebhookDelivery, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	delivery, exists := r.store.webhookDeliveries[id]
+	if !exists {
+		return nil, repository.ErrNotFound
+	}
+
+	d := *delivery
+	return &d, nil
+}
+
+// Update stores the outcome of a delivery attempt
+func (r *WebhookDeliveryRepository) Update(ctx context.Context, delivery *model.WebhookDelivery) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.webhookDeliveries[delivery.ID]; !exists {
+		return repository.ErrNotFound
+	}
+
+	d := *delivery
+	r.store.webhookDeliveries[delivery.ID] = &d
+	return nil
+}
+
+// ListByWebhook returns a webhook's deliveries, newest first
+func (r *WebhookDeliveryRepository) ListByWebhook(ctx context.Context, webhookID uuid.UUID, filter repository.WebhookDeliveryFilter) ([]*model.WebhookDelivery, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var deliveries []*model.WebhookDelivery
+	for _, delivery := range r.store.webhookDeliveries {
+		if delivery.WebhookID != webhookID || (filter.Status != "" && delivery.Status != filter.Status) {
+			continue
+		}
+		d := *delivery
+		deliveries = append(deliveries, &d)
+	}
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt)
+	})
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultWebhookDeliveryListLimit
+	}
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, nil
+}
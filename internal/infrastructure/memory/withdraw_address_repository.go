@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// WithdrawAddressRepository is an in-memory implementation of repository.WithdrawAddressRepository
+type WithdrawAddressRepository struct {
+	store *Store
+}
+
+var _ repository.WithdrawAddressRepository = (*WithdrawAddressRepository)(nil)
+
+// Create inserts a new address book entry
+func (r *WithdrawAddressRepository) Create(ctx context.Context, address *model.WithdrawAddress) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	a := *address
+	r.store.withdrawAddresses[a.ID] = &a
+	return nil
+}
+
+// GetByID retrieves an address book entry
+func (r *WithdrawAddressRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.WithdrawAddress, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	address, exists := r.store.withdrawAddresses[id]
+	if !exists {
+		return nil, repository.ErrNotFound
+	}
+	a := *address
+	return &a, nil
+}
+
+// Update replaces an address book entry
+func (r *WithdrawAddressRepository) Update(ctx context.Context, address *model.WithdrawAddress) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.withdrawAddresses[address.ID]; !exists {
+		return repository.ErrNotFound
+	}
+	a := *address
+	r.store.withdrawAddresses[a.ID] = &a
+	return nil
+}
+
+// Delete removes an address book entry
+func (r *WithdrawAddressRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.withdrawAddresses[id]; !exists {
+		return repository.ErrNotFound
+	}
+	delete(r.store.withdrawAddresses, id)
+	return nil
+}
+
+// ListByUser returns the user's addresses, oldest first
+func (r *WithdrawAddressRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.WithdrawAddress, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var addresses []*model.WithdrawAddress
+	for _, address := range r.store.withdrawAddresses {
+		if address.UserID == userID {
+			a := *address
+			addresses = append(addresses, &a)
+		}
+	}
+	sort.Slice(addresses, func(i, j int) bool {
+		return addresses[i].CreatedAt.Before(addresses[j].CreatedAt)
+	})
+	return addresses, nil
+}
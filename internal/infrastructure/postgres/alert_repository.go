@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+const priceAlertColumns = `id, user_id, market, condition, price, mode, active, trigger_count,
+	last_triggered_at, created_at, updated_at`
+
+// PriceAlertRepository is a PostgreSQL implementation of repository.PriceAlertRepository
+type PriceAlertRepository struct {
+	db DBTX
+}
+
+// NewPriceAlertRepository creates a new price alert repository
+func NewPriceAlertRepository(db DBTX) *PriceAlertRepository {
+	return &PriceAlertRepository{db: db}
+}
+
+var _ repository.PriceAlertRepository = (*PriceAlertRepository)(nil)
+
+// Create inserts a new price alert
+func (r *PriceAlertRepository) Create(ctx context.Context, alert *model.PriceAlert) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO price_alerts (`+priceAlertColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		alert.ID, alert.UserID, alert.Market, alert.Condition, alert.Price, alert.Mode, alert.Active,
+		alert.TriggerCount, alert.LastTriggeredAt, alert.CreatedAt, alert.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create price alert: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a price alert by ID
+func (r *PriceAlertRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.PriceAlert, error) {
+	row := r.db.QueryRow(ctx, `SELECT `+priceAlertColumns+` FROM price_alerts WHERE id = $1`, id)
+	alert, err := scanPriceAlert(row)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return alert, nil
+}
+
+// Update stores the trigger state of an alert
+func (r *PriceAlertRepository) Update(ctx context.Context, alert *model.PriceAlert) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE price_alerts
+		SET active = $2, trigger_count = $3, last_triggered_at = $4, updated_at = $5
+		WHERE id = $1`,
+		alert.ID, alert.Active, alert.TriggerCount, alert.LastTriggeredAt, alert.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update price alert: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes a price alert
+func (r *PriceAlertRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM price_alerts WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete price alert: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// ListByUser returns a user's alerts, newest first
+func (r *PriceAlertRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.PriceAlert, error) {
+	return r.list(ctx, `SELECT `+priceAlertColumns+` FROM price_alerts WHERE user_id = $1 ORDER BY created_at DESC`, userID)
+}
+
+// ListActive returns every active alert
+func (r *PriceAlertRepository) ListActive(ctx context.Context) ([]*model.PriceAlert, error) {
+	return r.list(ctx, `SELECT `+priceAlertColumns+` FROM price_alerts WHERE active`)
+}
+
+func (r *PriceAlertRepository) list(ctx context.Context, query string, args ...any) ([]*model.PriceAlert, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list price alerts: %w", err)
+	}
+	defer rows.Close()
+
+	var alerts []*model.PriceAlert
+	for rows.Next() {
+		alert, err := scanPriceAlert(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan price alert: %w", err)
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts, rows.Err()
+}
+
+func scanPriceAlert(row pgx.Row) (*model.PriceAlert, error) {
+	var a model.PriceAlert
+	err := row.Scan(
+		&a.ID, &a.UserID, &a.Market, &a.Condition, &a.Price, &a.Mode, &a.Active, &a.TriggerCount,
+		&a.LastTriggeredAt, &a.CreatedAt, &a.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
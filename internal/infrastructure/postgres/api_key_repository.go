@@ -0,0 +1,141 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// UserAPIKeyRepository is a PostgreSQL implementation of repository.UserAPIKeyRepository
+type UserAPIKeyRepository struct {
+	db DBTX
+}
+
+// NewUserAPIKeyRepository creates a new API key repository
+func NewUserAPIKeyRepository(db DBTX) *UserAPIKeyRepository {
+	return &UserAPIKeyRepository{db: db}
+}
+
+var _ repository.UserAPIKeyRepository = (*UserAPIKeyRepository)(nil)
+
+const apiKeyColumns = `id, user_id, access_key, secret_key, description, paper, is_active, expires_at, created_at, updated_at`
+
+// Create inserts a new API key
+func (r *UserAPIKeyRepository) Create(ctx context.Context, k *model.UserAPIKey) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO user_api_keys (`+apiKeyColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		k.ID, k.UserID, k.AccessKey, k.SecretKey, k.Description, k.Paper, k.IsActive, k.ExpiresAt, k.CreatedAt, k.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create API key: %w", err)
+	}
+	return nil
+}
+
+// ListByUser returns the user's API keys, newest first
+func (r *UserAPIKeyRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.UserAPIKey, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+apiKeyColumns+`
+		FROM user_api_keys
+		WHERE user_id = $1
+		ORDER BY created_at DESC`, userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []*model.UserAPIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan API key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// SetActive activates one of the user's keys and deactivates the others
+func (r *UserAPIKeyRepository) SetActive(ctx context.Context, userID, keyID uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE user_api_keys
+		SET is_active = (id = $2), updated_at = NOW()
+		WHERE user_id = $1
+			AND EXISTS (SELECT 1 FROM user_api_keys WHERE id = $2 AND user_id = $1)`,
+		userID, keyID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to activate API key: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// SetExpiry records when a key expires. Unchanged expiries aren't written,
+// so cached clients of the key stay valid.
+func (r *UserAPIKeyRepository) SetExpiry(ctx context.Context, keyID uuid.UUID, expiresAt time.Time) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE user_api_keys SET expires_at = $2
+		WHERE id = $1 AND expires_at IS DISTINCT FROM $2`,
+		keyID, expiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set API key expiry: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		var exists bool
+		if err := r.db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM user_api_keys WHERE id = $1)`, keyID).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to set API key expiry: %w", err)
+		}
+		if !exists {
+			return repository.ErrNotFound
+		}
+	}
+	return nil
+}
+
+// GetActiveByUserID returns the most recently created active API key of a user
+func (r *UserAPIKeyRepository) GetActiveByUserID(ctx context.Context, userID uuid.UUID) (*model.UserAPIKey, error) {
+	key, err := scanAPIKey(r.db.QueryRow(ctx, `
+		SELECT `+apiKeyColumns+`
+		FROM user_api_keys
+		WHERE user_id = $1 AND is_active
+		ORDER BY created_at DESC
+		LIMIT 1`, userID,
+	))
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return key, nil
+}
+
+// ListActiveUserIDs returns the users that have an active API key
+func (r *UserAPIKeyRepository) ListActiveUserIDs(ctx context.Context) ([]uuid.UUID, error) {
+	rows, err := r.db.Query(ctx, `SELECT DISTINCT user_id FROM user_api_keys WHERE is_active`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users with active API keys: %w", err)
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+}
+
+func scanAPIKey(row pgx.Row) (*model.UserAPIKey, error) {
+	var k model.UserAPIKey
+	var description *string
+	err := row.Scan(&k.ID, &k.UserID, &k.AccessKey, &k.SecretKey, &description, &k.Paper, &k.IsActive, &k.ExpiresAt, &k.CreatedAt, &k.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if description != nil {
+		k.Description = *description
+	}
+	return &k, nil
+}
@@ -0,0 +1,145 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// defaultBacktestListLimit bounds listings that don't set a limit
+const defaultBacktestListLimit = 100
+
+// BacktestRepository is a PostgreSQL implementation of repository.BacktestRepository
+type BacktestRepository struct {
+	db DBTX
+}
+
+// NewBacktestRepository creates a new backtest repository
+func NewBacktestRepository(db DBTX) *BacktestRepository {
+	return &BacktestRepository{db: db}
+}
+
+var _ repository.BacktestRepository = (*BacktestRepository)(nil)
+
+// Create inserts a run and its trades in a single statement, so a run is
+// never stored without its trades
+func (r *BacktestRepository) Create(ctx context.Context, run *model.BacktestRun) error {
+	params, err := json.Marshal(run.Params)
+	if err != nil {
+		return fmt.Errorf("failed to marshal params: %w", err)
+	}
+	metrics, err := json.Marshal(run.Metrics)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metrics: %w", err)
+	}
+	trades, err := json.Marshal(run.Trades)
+	if err != nil {
+		return fmt.Errorf("failed to marshal trades: %w", err)
+	}
+	if run.Trades == nil {
+		trades = []byte("[]")
+	}
+
+	_, err = r.db.Exec(ctx, `
+		WITH run AS (
+			INSERT INTO backtest_runs (id, user_id, market, candle_interval, strategy, params, from_time, to_time,
+				config, initial_capital, final_equity, total_fees, slippage, metrics, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+			RETURNING id
+		)
+		INSERT INTO backtest_trades (run_id, entry_time, exit_time, entry_price, exit_price, quantity, fees, pnl)
+		SELECT run.id, t.entry_time, t.exit_time, t.entry_price, t.exit_price, t.quantity, t.fees, t.pnl
+		FROM run, jsonb_to_recordset($16::jsonb) AS t(entry_time TIMESTAMPTZ, exit_time TIMESTAMPTZ,
+			entry_price DECIMAL, exit_price DECIMAL, quantity DECIMAL, fees DECIMAL, pnl DECIMAL)`,
+		run.ID, run.UserID, run.Market, run.Interval, run.Strategy, params, run.From, run.To,
+		[]byte(run.Config), run.InitialCapital, run.FinalEquity, run.TotalFees, run.Slippage, metrics, run.CreatedAt,
+		trades,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create backtest run: %w", err)
+	}
+	return nil
+}
+
+// GetByID returns a run with its trades
+func (r *BacktestRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.BacktestRun, error) {
+	run, err := scanBacktestRun(r.db.QueryRow(ctx, `
+		SELECT `+backtestRunColumns+`
+		FROM backtest_runs WHERE id = $1`, id))
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT entry_time, exit_time, entry_price, exit_price, quantity, fees, pnl
+		FROM backtest_trades WHERE run_id = $1
+		ORDER BY entry_time`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backtest trades: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t model.BacktestTrade
+		if err := rows.Scan(&t.EntryTime, &t.ExitTime, &t.EntryPrice, &t.ExitPrice, &t.Quantity, &t.Fees, &t.PnL); err != nil {
+			return nil, fmt.Errorf("failed to scan backtest trade: %w", err)
+		}
+		run.Trades = append(run.Trades, t)
+	}
+	return run, rows.Err()
+}
+
+// ListByUser returns a user's runs newest first, without trades
+func (r *BacktestRepository) ListByUser(ctx context.Context, userID uuid.UUID, filter repository.BacktestFilter) ([]*model.BacktestRun, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultBacktestListLimit
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT `+backtestRunColumns+`
+		FROM backtest_runs
+		WHERE user_id = $1 AND ($2 = '' OR market = $2) AND ($3 = '' OR strategy = $3)
+		ORDER BY created_at DESC
+		LIMIT $4`, userID, filter.Market, filter.Strategy, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backtest runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []*model.BacktestRun
+	for rows.Next() {
+		run, err := scanBacktestRun(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan backtest run: %w", err)
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+const backtestRunColumns = `id, user_id, market, candle_interval, strategy, params, from_time, to_time, config,
+	initial_capital, final_equity, total_fees, slippage, metrics, created_at`
+
+func scanBacktestRun(row interface{ Scan(dest ...any) error }) (*model.BacktestRun, error) {
+	var run model.BacktestRun
+	var params, config, metrics []byte
+	err := row.Scan(&run.ID, &run.UserID, &run.Market, &run.Interval, &run.Strategy, &params, &run.From, &run.To,
+		&config, &run.InitialCapital, &run.FinalEquity, &run.TotalFees, &run.Slippage, &metrics, &run.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(params, &run.Params); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal params: %w", err)
+	}
+	if err := json.Unmarshal(metrics, &run.Metrics); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal metrics: %w", err)
+	}
+	run.Config = config
+	return &run, nil
+}
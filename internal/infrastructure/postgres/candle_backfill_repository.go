@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// CandleBackfillRepository is a PostgreSQL implementation of repository.CandleBackfillRepository
+type CandleBackfillRepository struct {
+	db DBTX
+}
+
+// NewCandleBackfillRepository creates a new candle backfill repository
+func NewCandleBackfillRepository(db DBTX) *CandleBackfillRepository {
+	return &CandleBackfillRepository{db: db}
+}
+
+var _ repository.CandleBackfillRepository = (*CandleBackfillRepository)(nil)
+
+// Get returns a market's backfill progress
+func (r *CandleBackfillRepository) Get(ctx context.Context, market string, interval model.CandleInterval) (*model.CandleBackfill, error) {
+	var b model.CandleBackfill
+	err := r.db.QueryRow(ctx, `
+		SELECT market, candle_interval, from_time, cursor, updated_at
+		FROM candle_backfills
+		WHERE market = $1 AND candle_interval = $2`,
+		market, interval,
+	).Scan(&b.Market, &b.Interval, &b.From, &b.Cursor, &b.UpdatedAt)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &b, nil
+}
+
+// Save creates or replaces a market's backfill progress
+func (r *CandleBackfillRepository) Save(ctx context.Context, backfill *model.CandleBackfill) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO candle_backfills (market, candle_interval, from_time, cursor, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (market, candle_interval) DO UPDATE
+		SET from_time = EXCLUDED.from_time, cursor = EXCLUDED.cursor, updated_at = EXCLUDED.updated_at`,
+		backfill.Market, backfill.Interval, backfill.From, backfill.Cursor, backfill.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save candle backfill: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+const collectorShardColumns = `candle_interval, shard, shards, owner, lease_until, markets, candles, failures,
+	last_error, last_collected_at, updated_at`
+
+// CollectorShardRepository is a PostgreSQL implementation of repository.CollectorShardRepository
+type CollectorShardRepository struct {
+	db DBTX
+}
+
+// NewCollectorShardRepository creates a new collector shard repository
+func NewCollectorShardRepository(db DBTX) *CollectorShardRepository {
+	return &CollectorShardRepository{db: db}
+}
+
+var _ repository.CollectorShardRepository = (*CollectorShardRepository)(nil)
+
+// Claim leases a shard to owner, creating the interval's shards on first use.
+// SKIP LOCKED lets workers claim concurrently without taking the same shard.
+func (r *CollectorShardRepository) Claim(ctx context.Context, interval model.CandleInterval, shards int, owner string, now, leaseUntil time.Time) (*model.CollectorShard, error) {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO candle_collector_shards (candle_interval, shard, shards)
+		SELECT $1, s, $2 FROM generate_series(0, $2 - 1) AS s
+		ON CONFLICT (candle_interval, shard) DO NOTHING`,
+		interval, shards,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create collector shards: %w", err)
+	}
+
+	row := r.db.QueryRow(ctx, `
+		UPDATE candle_collector_shards
+		SET owner = $3, lease_until = $5, shards = $2, updated_at = $4
+		WHERE (candle_interval, shard) = (
+			SELECT candle_interval, shard FROM candle_collector_shards
+			WHERE candle_interval = $1 AND shard < $2 AND (owner = $3 OR lease_until <= $4)
+			ORDER BY owner = $3 DESC, shard
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+collectorShardColumns,
+		interval, shards, owner, now, leaseUntil,
+	)
+	shard, err := scanCollectorShard(row)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return shard, nil
+}
+
+// SaveProgress records a collection pass over a shard and extends its lease
+func (r *CollectorShardRepository) SaveProgress(ctx context.Context, shard *model.CollectorShard) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE candle_collector_shards
+		SET lease_until = $4, markets = $5, candles = $6, failures = $7, last_error = $8, last_collected_at = $9,
+			updated_at = $10
+		WHERE candle_interval = $1 AND shard = $2 AND owner = $3`,
+		shard.Interval, shard.Shard, shard.Owner, shard.LeaseUntil, shard.Markets, shard.Candles, shard.Failures,
+		shard.LastError, shard.LastCollectedAt, shard.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save collector shard progress: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// Release gives up owner's lease on a shard
+func (r *CollectorShardRepository) Release(ctx context.Context, interval model.CandleInterval, shard int, owner string) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE candle_collector_shards
+		SET owner = '', lease_until = 'epoch', updated_at = CURRENT_TIMESTAMP
+		WHERE candle_interval = $1 AND shard = $2 AND owner = $3`,
+		interval, shard, owner,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to release collector shard: %w", err)
+	}
+	return nil
+}
+
+// List returns an interval's shards in order
+func (r *CollectorShardRepository) List(ctx context.Context, interval model.CandleInterval) ([]*model.CollectorShard, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+collectorShardColumns+`
+		FROM candle_collector_shards
+		WHERE candle_interval = $1
+		ORDER BY shard`, interval)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collector shards: %w", err)
+	}
+	defer rows.Close()
+
+	var shards []*model.CollectorShard
+	for rows.Next() {
+		shard, err := scanCollectorShard(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan collector shard: %w", err)
+		}
+		shards = append(shards, shard)
+	}
+	return shards, rows.Err()
+}
+
+func scanCollectorShard(row pgx.Row) (*model.CollectorShard, error) {
+	var s model.CollectorShard
+	err := row.Scan(
+		&s.Interval, &s.Shard, &s.Shards, &s.Owner, &s.LeaseUntil, &s.Markets, &s.Candles, &s.Failures,
+		&s.LastError, &s.LastCollectedAt, &s.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
@@ -0,0 +1,26 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// DBTX is the subset of pgx functionality shared by *pgxpool.Pool and pgx.Tx,
+// so repositories work the same inside and outside a transaction
+type DBTX interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// translateError maps driver errors to repository errors
+func translateError(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return repository.ErrNotFound
+	}
+	return err
+}
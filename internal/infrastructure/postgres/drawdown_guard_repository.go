@@ -0,0 +1,177 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+const drawdownGuardColumns = `position_id, user_id, market, max_drawdown, peak_price, active,
+	triggered_at, trigger_price, exit_order_id, created_at, updated_at, max_price_age, confirm_interval, dry_run, template_id`
+
+// DrawdownGuardRepository is a PostgreSQL implementation of repository.DrawdownGuardRepository
+type DrawdownGuardRepository struct {
+	db DBTX
+}
+
+// NewDrawdownGuardRepository creates a new drawdown guard repository
+func NewDrawdownGuardRepository(db DBTX) *DrawdownGuardRepository {
+	return &DrawdownGuardRepository{db: db}
+}
+
+var _ repository.DrawdownGuardRepository = (*DrawdownGuardRepository)(nil)
+
+// Save creates or replaces the guard of a position
+func (r *DrawdownGuardRepository) Save(ctx context.Context, g *model.DrawdownGuard) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO drawdown_guards (`+drawdownGuardColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		ON CONFLICT (position_id) DO UPDATE
+		SET max_drawdown = EXCLUDED.max_drawdown, peak_price = EXCLUDED.peak_price, active = EXCLUDED.active,
+			triggered_at = EXCLUDED.triggered_at, trigger_price = EXCLUDED.trigger_price,
+			exit_order_id = EXCLUDED.exit_order_id, created_at = EXCLUDED.created_at,
+			updated_at = EXCLUDED.updated_at, max_price_age = EXCLUDED.max_price_age,
+			confirm_interval = EXCLUDED.confirm_interval, dry_run = EXCLUDED.dry_run,
+			template_id = EXCLUDED.template_id`,
+		g.PositionID, g.UserID, g.Market, g.MaxDrawdown, g.PeakPrice, g.Active,
+		g.TriggeredAt, g.TriggerPrice, g.ExitOrderID, g.CreatedAt, g.UpdatedAt, g.MaxPriceAge, g.ConfirmInterval, g.DryRun, g.TemplateID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save drawdown guard: %w", err)
+	}
+	return nil
+}
+
+// SavePeak stores the peak of a position's guard while it is active
+func (r *DrawdownGuardRepository) SavePeak(ctx context.Context, g *model.DrawdownGuard) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE drawdown_guards SET peak_price = $2, updated_at = $3
+		WHERE position_id = $1 AND active`,
+		g.PositionID, g.PeakPrice, g.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save drawdown guard peak: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// GetByPosition returns the guard of a position
+func (r *DrawdownGuardRepository) GetByPosition(ctx context.Context, positionID uuid.UUID) (*model.DrawdownGuard, error) {
+	row := r.db.QueryRow(ctx, `SELECT `+drawdownGuardColumns+` FROM drawdown_guards WHERE position_id = $1`, positionID)
+	guard, err := scanDrawdownGuard(row)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return guard, nil
+}
+
+// Delete removes the guard of a position
+func (r *DrawdownGuardRepository) Delete(ctx context.Context, positionID uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM drawdown_guards WHERE position_id = $1`, positionID)
+	if err != nil {
+		return fmt.Errorf("failed to delete drawdown guard: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// ListByUser returns a user's guards, newest first
+func (r *DrawdownGuardRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.DrawdownGuard, error) {
+	return r.list(ctx, `SELECT `+drawdownGuardColumns+` FROM drawdown_guards WHERE user_id = $1 ORDER BY created_at DESC`, userID)
+}
+
+// ListActive returns every active guard
+func (r *DrawdownGuardRepository) ListActive(ctx context.Context) ([]*model.DrawdownGuard, error) {
+	return r.list(ctx, `SELECT `+drawdownGuardColumns+` FROM drawdown_guards WHERE active`)
+}
+
+func (r *DrawdownGuardRepository) list(ctx context.Context, query string, args ...any) ([]*model.DrawdownGuard, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list drawdown guards: %w", err)
+	}
+	defer rows.Close()
+
+	var guards []*model.DrawdownGuard
+	for rows.Next() {
+		guard, err := scanDrawdownGuard(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan drawdown guard: %w", err)
+		}
+		guards = append(guards, guard)
+	}
+	return guards, rows.Err()
+}
+
+func scanDrawdownGuard(row pgx.Row) (*model.DrawdownGuard, error) {
+	var g model.DrawdownGuard
+	err := row.Scan(
+		&g.PositionID, &g.UserID, &g.Market, &g.MaxDrawdown, &g.PeakPrice, &g.Active,
+		&g.TriggeredAt, &g.TriggerPrice, &g.ExitOrderID, &g.CreatedAt, &g.UpdatedAt, &g.MaxPriceAge, &g.ConfirmInterval, &g.DryRun, &g.TemplateID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+// DrawdownGuardUpdateRepository is a PostgreSQL implementation of repository.DrawdownGuardUpdateRepository
+type DrawdownGuardUpdateRepository struct {
+	db DBTX
+}
+
+// NewDrawdownGuardUpdateRepository creates a new drawdown guard update repository
+func NewDrawdownGuardUpdateRepository(db DBTX) *DrawdownGuardUpdateRepository {
+	return &DrawdownGuardUpdateRepository{db: db}
+}
+
+var _ repository.DrawdownGuardUpdateRepository = (*DrawdownGuardUpdateRepository)(nil)
+
+// Create inserts a new update
+func (r *DrawdownGuardUpdateRepository) Create(ctx context.Context, u *model.DrawdownGuardUpdate) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO drawdown_guard_updates (id, position_id, kind, price, peak_price, max_drawdown, trigger_level, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		u.ID, u.PositionID, u.Kind, u.Price, u.PeakPrice, u.MaxDrawdown, u.TriggerLevel, u.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create drawdown guard update: %w", err)
+	}
+	return nil
+}
+
+// ListSince returns a position's guard updates from since on in
+// chronological order, preceded by the last update before since
+func (r *DrawdownGuardUpdateRepository) ListSince(ctx context.Context, positionID uuid.UUID, since time.Time) ([]*model.DrawdownGuardUpdate, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, position_id, kind, price, peak_price, max_drawdown, trigger_level, created_at FROM (
+			(SELECT * FROM drawdown_guard_updates WHERE position_id = $1 AND created_at < $2
+				ORDER BY created_at DESC, id DESC LIMIT 1)
+			UNION ALL
+			SELECT * FROM drawdown_guard_updates WHERE position_id = $1 AND created_at >= $2
+		) updates ORDER BY created_at, id`, positionID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list drawdown guard updates: %w", err)
+	}
+	defer rows.Close()
+
+	var updates []*model.DrawdownGuardUpdate
+	for rows.Next() {
+		var u model.DrawdownGuardUpdate
+		if err := rows.Scan(&u.ID, &u.PositionID, &u.Kind, &u.Price, &u.PeakPrice, &u.MaxDrawdown, &u.TriggerLevel, &u.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan drawdown guard update: %w", err)
+		}
+		updates = append(updates, &u)
+	}
+	return updates, rows.Err()
+}
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// OrderExecutionRepository is a PostgreSQL implementation of repository.OrderExecutionRepository
+type OrderExecutionRepository struct {
+	db     DBTX
+	reader DBTX // Serves read-only queries; the primary unless a replica is set
+}
+
+// NewOrderExecutionRepository creates a new order execution repository
+func NewOrderExecutionRepository(db DBTX) *OrderExecutionRepository {
+	return &OrderExecutionRepository{db: db, reader: db}
+}
+
+// WithReplica returns a copy of the repository that lists executions from a replica
+func (r *OrderExecutionRepository) WithReplica(replica DBTX) *OrderExecutionRepository {
+	return &OrderExecutionRepository{db: r.db, reader: replica}
+}
+
+var _ repository.OrderExecutionRepository = (*OrderExecutionRepository)(nil)
+
+// Create inserts a new execution
+func (r *OrderExecutionRepository) Create(ctx context.Context, execution *model.OrderExecution) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO order_executions (id, order_id, price, quantity, fee, total, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		execution.ID, execution.OrderID, execution.Price, execution.Quantity, execution.Fee, execution.Total, execution.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create order execution: %w", err)
+	}
+	return nil
+}
+
+// ListByOrder returns the executions of an order in chronological order
+func (r *OrderExecutionRepository) ListByOrder(ctx context.Context, orderID uuid.UUID) ([]*model.OrderExecution, error) {
+	rows, err := r.reader.Query(ctx, `
+		SELECT id, order_id, price, quantity, fee, total, created_at
+		FROM order_executions WHERE order_id = $1 ORDER BY created_at`, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list order executions: %w", err)
+	}
+	defer rows.Close()
+
+	var executions []*model.OrderExecution
+	for rows.Next() {
+		var e model.OrderExecution
+		if err := rows.Scan(&e.ID, &e.OrderID, &e.Price, &e.Quantity, &e.Fee, &e.Total, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan order execution: %w", err)
+		}
+		executions = append(executions, &e)
+	}
+	return executions, rows.Err()
+}
@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// TradingHaltRepository is a PostgreSQL implementation of repository.TradingHaltRepository
+type TradingHaltRepository struct {
+	db DBTX
+}
+
+// NewTradingHaltRepository creates a new trading halt repository
+func NewTradingHaltRepository(db DBTX) *TradingHaltRepository {
+	return &TradingHaltRepository{db: db}
+}
+
+var _ repository.TradingHaltRepository = (*TradingHaltRepository)(nil)
+
+// Get returns the halt of a user, or the global halt for uuid.Nil
+func (r *TradingHaltRepository) Get(ctx context.Context, userID uuid.UUID) (*model.TradingHalt, error) {
+	var halt model.TradingHalt
+	err := r.db.QueryRow(ctx, `SELECT user_id, reason, halted_by, created_at FROM trading_halts WHERE user_id = $1`, userID).
+		Scan(&halt.UserID, &halt.Reason, &halt.HaltedBy, &halt.CreatedAt)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &halt, nil
+}
+
+// Save creates or replaces a halt
+func (r *TradingHaltRepository) Save(ctx context.Context, halt *model.TradingHalt) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO trading_halts (user_id, reason, halted_by, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO UPDATE
+		SET reason = EXCLUDED.reason, halted_by = EXCLUDED.halted_by, created_at = EXCLUDED.created_at`,
+		halt.UserID, halt.Reason, halt.HaltedBy, halt.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save trading halt: %w", err)
+	}
+	return nil
+}
+
+// Delete lifts a halt
+func (r *TradingHaltRepository) Delete(ctx context.Context, userID uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM trading_halts WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete trading halt: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
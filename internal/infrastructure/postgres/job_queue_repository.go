@@ -0,0 +1,173 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+const (
+	queuedJobColumns = `id, kind, user_id, payload, status, attempts, max_attempts, interrupted, last_error, run_at,
+	finished_at, created_at, updated_at`
+	defaultQueuedJobListLimit = 100
+)
+
+// JobQueueRepository is a PostgreSQL implementation of repository.JobQueueRepository
+type JobQueueRepository struct {
+	db DBTX
+}
+
+// NewJobQueueRepository creates a new job queue repository
+func NewJobQueueRepository(db DBTX) *JobQueueRepository {
+	return &JobQueueRepository{db: db}
+}
+
+var _ repository.JobQueueRepository = (*JobQueueRepository)(nil)
+
+// Enqueue inserts a new job
+func (r *JobQueueRepository) Enqueue(ctx context.Context, job *model.QueuedJob) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO queued_jobs (`+queuedJobColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`,
+		job.ID, job.Kind, job.UserID, []byte(job.Payload), job.Status, job.Attempts, job.MaxAttempts, job.Interrupted,
+		job.LastError, job.RunAt, job.FinishedAt, job.CreatedAt, job.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue job: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a job by ID
+func (r *JobQueueRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.QueuedJob, error) {
+	job, err := scanQueuedJob(r.db.QueryRow(ctx, `SELECT `+queuedJobColumns+` FROM queued_jobs WHERE id = $1`, id))
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return job, nil
+}
+
+// Update stores the outcome of an attempt, or a retry requested by an operator
+func (r *JobQueueRepository) Update(ctx context.Context, job *model.QueuedJob) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE queued_jobs
+		SET status = $2, attempts = $3, max_attempts = $4, interrupted = $5, last_error = $6, run_at = $7,
+			finished_at = $8, updated_at = $9
+		WHERE id = $1`,
+		job.ID, job.Status, job.Attempts, job.MaxAttempts, job.Interrupted, job.LastError, job.RunAt,
+		job.FinishedAt, job.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update job: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// Claim leases due jobs of the given kinds. SKIP LOCKED lets several
+// instances claim concurrently without taking the same job.
+func (r *JobQueueRepository) Claim(ctx context.Context, kinds []string, now, leaseUntil time.Time, limit int) ([]*model.QueuedJob, error) {
+	return r.list(ctx, `
+		UPDATE queued_jobs
+		SET status = 'running', attempts = attempts + 1, interrupted = (status = 'running'), run_at = $3,
+			updated_at = $2
+		WHERE id IN (
+			SELECT id FROM queued_jobs
+			WHERE kind = ANY($1) AND status IN ('pending', 'running') AND run_at <= $2
+			ORDER BY run_at
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING `+queuedJobColumns,
+		kinds, now, leaseUntil, limit,
+	)
+}
+
+// List returns jobs, newest first
+func (r *JobQueueRepository) List(ctx context.Context, filter repository.JobQueueFilter) ([]*model.QueuedJob, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultQueuedJobListLimit
+	}
+
+	var userID *uuid.UUID
+	if filter.UserID != uuid.Nil {
+		userID = &filter.UserID
+	}
+
+	return r.list(ctx, `
+		SELECT `+queuedJobColumns+`
+		FROM queued_jobs
+		WHERE ($1 = '' OR kind = $1) AND ($2 = '' OR status = $2) AND ($3::uuid IS NULL OR user_id = $3)
+		ORDER BY created_at DESC
+		LIMIT $4`,
+		filter.Kind, filter.Status, userID, limit,
+	)
+}
+
+// SaveResult stores a job's result, replacing one an earlier attempt saved
+func (r *JobQueueRepository) SaveResult(ctx context.Context, result *model.QueuedJobResult) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO queued_job_results (job_id, content_type, data, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (job_id) DO UPDATE
+		SET content_type = EXCLUDED.content_type, data = EXCLUDED.data, created_at = EXCLUDED.created_at`,
+		result.JobID, result.ContentType, result.Data, result.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save job result: %w", err)
+	}
+	return nil
+}
+
+// GetResult retrieves a job's result
+func (r *JobQueueRepository) GetResult(ctx context.Context, jobID uuid.UUID) (*model.QueuedJobResult, error) {
+	var result model.QueuedJobResult
+	err := r.db.QueryRow(ctx, `
+		SELECT job_id, content_type, data, created_at FROM queued_job_results WHERE job_id = $1`,
+		jobID,
+	).Scan(&result.JobID, &result.ContentType, &result.Data, &result.CreatedAt)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &result, nil
+}
+
+func (r *JobQueueRepository) list(ctx context.Context, query string, args ...any) ([]*model.QueuedJob, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []*model.QueuedJob
+	for rows.Next() {
+		job, err := scanQueuedJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+func scanQueuedJob(row pgx.Row) (*model.QueuedJob, error) {
+	var job model.QueuedJob
+	var payload []byte
+	err := row.Scan(
+		&job.ID, &job.Kind, &job.UserID, &payload, &job.Status, &job.Attempts, &job.MaxAttempts, &job.Interrupted,
+		&job.LastError, &job.RunAt, &job.FinishedAt, &job.CreatedAt, &job.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	job.Payload = payload
+	return &job, nil
+}
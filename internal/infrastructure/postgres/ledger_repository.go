@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// CashLedgerRepository is a PostgreSQL implementation of repository.CashLedgerRepository
+type CashLedgerRepository struct {
+	db DBTX
+}
+
+// NewCashLedgerRepository creates a new cash ledger repository
+func NewCashLedgerRepository(db DBTX) *CashLedgerRepository {
+	return &CashLedgerRepository{db: db}
+}
+
+var _ repository.CashLedgerRepository = (*CashLedgerRepository)(nil)
+
+// Record inserts an entry unless one already exists for the user, type and reference
+func (r *CashLedgerRepository) Record(ctx context.Context, entry *model.CashLedgerEntry) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO cash_ledger_entries (id, user_id, entry_type, amount, reference, order_id, occurred_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (user_id, entry_type, reference) DO NOTHING`,
+		entry.ID, entry.UserID, entry.Type, entry.Amount, entry.Reference, entry.OrderID, entry.OccurredAt, entry.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record cash ledger entry: %w", err)
+	}
+	return nil
+}
+
+// GetOpening returns a user's opening balance entry
+func (r *CashLedgerRepository) GetOpening(ctx context.Context, userID uuid.UUID) (*model.CashLedgerEntry, error) {
+	var e model.CashLedgerEntry
+	err := r.db.QueryRow(ctx, `
+		SELECT id, user_id, entry_type, amount, reference, order_id, occurred_at, created_at
+		FROM cash_ledger_entries
+		WHERE user_id = $1 AND entry_type = $2`,
+		userID, model.CashEntryOpeningBalance,
+	).Scan(&e.ID, &e.UserID, &e.Type, &e.Amount, &e.Reference, &e.OrderID, &e.OccurredAt, &e.CreatedAt)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &e, nil
+}
+
+// ListByUser returns a user's entries that occurred in [from, to), newest first
+func (r *CashLedgerRepository) ListByUser(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*model.CashLedgerEntry, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, user_id, entry_type, amount, reference, order_id, occurred_at, created_at
+		FROM cash_ledger_entries
+		WHERE user_id = $1 AND occurred_at >= $2 AND occurred_at < $3
+		ORDER BY occurred_at DESC, created_at DESC`, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cash ledger entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*model.CashLedgerEntry
+	for rows.Next() {
+		var e model.CashLedgerEntry
+		if err := rows.Scan(&e.ID, &e.UserID, &e.Type, &e.Amount, &e.Reference, &e.OrderID, &e.OccurredAt, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan cash ledger entry: %w", err)
+		}
+		entries = append(entries, &e)
+	}
+	return entries, rows.Err()
+}
+
+// Balance returns the sum of a user's entries
+func (r *CashLedgerRepository) Balance(ctx context.Context, userID uuid.UUID) (float64, error) {
+	var balance float64
+	err := r.db.QueryRow(ctx, `
+		SELECT COALESCE(SUM(amount), 0)::DOUBLE PRECISION
+		FROM cash_ledger_entries
+		WHERE user_id = $1`, userID,
+	).Scan(&balance)
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum cash ledger entries: %w", err)
+	}
+	return balance, nil
+}
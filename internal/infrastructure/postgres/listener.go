@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/pkg/recovery"
+)
+
+const (
+	// InvalidationChannel is the NOTIFY channel for model.Invalidation payloads
+	InvalidationChannel = "invalidation"
+
+	listenRetryDelay = 5 * time.Second
+)
+
+// InvalidationHandler reacts to an invalidation
+type InvalidationHandler func(ctx context.Context, invalidation model.Invalidation)
+
+// Listener subscribes to the invalidation channel on a dedicated connection
+// and dispatches notifications to handlers by kind
+type Listener struct {
+	pool      *pgxpool.Pool
+	handlers  map[string][]InvalidationHandler
+	mu        sync.RWMutex
+	isRunning bool
+	cancel    context.CancelFunc
+}
+
+// NewListener creates a new invalidation listener
+func NewListener(pool *pgxpool.Pool) *Listener {
+	return &Listener{
+		pool:     pool,
+		handlers: make(map[string][]InvalidationHandler),
+	}
+}
+
+// Subscribe registers a handler for an invalidation kind
+func (l *Listener) Subscribe(kind string, handler InvalidationHandler) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.handlers[kind] = append(l.handlers[kind], handler)
+}
+
+// Start starts listening, reconnecting whenever the connection is lost
+func (l *Listener) Start(ctx context.Context) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.isRunning {
+		return
+	}
+	l.isRunning = true
+
+	ctx, l.cancel = context.WithCancel(ctx)
+	go l.run(ctx)
+}
+
+// Stop stops listening
+func (l *Listener) Stop() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.isRunning {
+		return
+	}
+
+	l.cancel()
+	l.isRunning = false
+}
+
+func (l *Listener) run(ctx context.Context) {
+	for {
+		// A panic drops the connection and reconnects like an error
+		err := recovery.Call("invalidation.listen", func() error { return l.listen(ctx) })
+		if err != nil && ctx.Err() == nil {
+			log.Printf("Invalidation listener error, reconnecting in %s: %v", listenRetryDelay, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetryDelay):
+		}
+	}
+}
+
+func (l *Listener) listen(ctx context.Context) error {
+	conn, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+InvalidationChannel); err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var invalidation model.Invalidation
+		if err := json.Unmarshal([]byte(notification.Payload), &invalidation); err != nil {
+			log.Printf("Ignoring malformed invalidation %q: %v", notification.Payload, err)
+			continue
+		}
+
+		l.dispatch(ctx, invalidation)
+	}
+}
+
+func (l *Listener) dispatch(ctx context.Context, invalidation model.Invalidation) {
+	l.mu.RLock()
+	handlers := l.handlers[invalidation.Kind]
+	l.mu.RUnlock()
+
+	for _, handler := range handlers {
+		recovery.Do("invalidation."+invalidation.Kind, func() { handler(ctx, invalidation) })
+	}
+}
+
+// Notify announces an invalidation to all instances. Inside a transaction the
+// notification is delivered on commit.
+func Notify(ctx context.Context, db DBTX, invalidation model.Invalidation) error {
+	payload, err := json.Marshal(invalidation)
+	if err != nil {
+		return fmt.Errorf("failed to marshal invalidation: %w", err)
+	}
+
+	if _, err := db.Exec(ctx, "SELECT pg_notify($1, $2)", InvalidationChannel, string(payload)); err != nil {
+		return fmt.Errorf("failed to notify: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// MaintenanceWindowRepository is a PostgreSQL implementation of repository.MaintenanceWindowRepository
+type MaintenanceWindowRepository struct {
+	db DBTX
+}
+
+// NewMaintenanceWindowRepository creates a new maintenance window repository
+func NewMaintenanceWindowRepository(db DBTX) *MaintenanceWindowRepository {
+	return &MaintenanceWindowRepository{db: db}
+}
+
+var _ repository.MaintenanceWindowRepository = (*MaintenanceWindowRepository)(nil)
+
+// Create inserts a new maintenance window
+func (r *MaintenanceWindowRepository) Create(ctx context.Context, w *model.MaintenanceWindow) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO maintenance_windows (id, market, reason, starts_at, ends_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		w.ID, w.Market, w.Reason, w.StartsAt, w.EndsAt, w.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create maintenance window: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a maintenance window
+func (r *MaintenanceWindowRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM maintenance_windows WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete maintenance window: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// ListEndingAfter returns the windows that end after the given time,
+// earliest start first
+func (r *MaintenanceWindowRepository) ListEndingAfter(ctx context.Context, after time.Time) ([]*model.MaintenanceWindow, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, market, reason, starts_at, ends_at, created_at FROM maintenance_windows
+		WHERE ends_at > $1
+		ORDER BY starts_at`, after)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list maintenance windows: %w", err)
+	}
+	defer rows.Close()
+
+	var windows []*model.MaintenanceWindow
+	for rows.Next() {
+		var w model.MaintenanceWindow
+		if err := rows.Scan(&w.ID, &w.Market, &w.Reason, &w.StartsAt, &w.EndsAt, &w.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan maintenance window: %w", err)
+		}
+		windows = append(windows, &w)
+	}
+	return windows, rows.Err()
+}
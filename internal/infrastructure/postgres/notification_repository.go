@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// NotificationSettingsRepository is a PostgreSQL implementation of repository.NotificationSettingsRepository
+type NotificationSettingsRepository struct {
+	db DBTX
+}
+
+// NewNotificationSettingsRepository creates a new notification settings repository
+func NewNotificationSettingsRepository(db DBTX) *NotificationSettingsRepository {
+	return &NotificationSettingsRepository{db: db}
+}
+
+var _ repository.NotificationSettingsRepository = (*NotificationSettingsRepository)(nil)
+
+const notificationSettingsColumns = `user_id, daily_summary, summary_channel, summary_time, timezone, updated_at`
+
+// Get returns the user's settings
+func (r *NotificationSettingsRepository) Get(ctx context.Context, userID uuid.UUID) (*model.NotificationSettings, error) {
+	settings, err := scanNotificationSettings(r.db.QueryRow(ctx, `SELECT `+notificationSettingsColumns+` FROM notification_settings WHERE user_id = $1`, userID))
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return settings, nil
+}
+
+// Save creates or replaces the user's settings
+func (r *NotificationSettingsRepository) Save(ctx context.Context, s *model.NotificationSettings) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO notification_settings (`+notificationSettingsColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id) DO UPDATE
+		SET daily_summary = EXCLUDED.daily_summary, summary_channel = EXCLUDED.summary_channel,
+			summary_time = EXCLUDED.summary_time, timezone = EXCLUDED.timezone, updated_at = EXCLUDED.updated_at`,
+		s.UserID, s.DailySummary, s.SummaryChannel, s.SummaryTime, s.Timezone, s.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save notification settings: %w", err)
+	}
+	return nil
+}
+
+// ListDailySummaries returns the settings of users with daily summaries enabled
+func (r *NotificationSettingsRepository) ListDailySummaries(ctx context.Context) ([]*model.NotificationSettings, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+notificationSettingsColumns+` FROM notification_settings WHERE daily_summary`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notification settings: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*model.NotificationSettings
+	for rows.Next() {
+		settings, err := scanNotificationSettings(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification settings: %w", err)
+		}
+		result = append(result, settings)
+	}
+	return result, rows.Err()
+}
+
+func scanNotificationSettings(row pgx.Row) (*model.NotificationSettings, error) {
+	var s model.NotificationSettings
+	err := row.Scan(&s.UserID, &s.DailySummary, &s.SummaryChannel, &s.SummaryTime, &s.Timezone, &s.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
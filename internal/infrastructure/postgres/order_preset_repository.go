@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+const orderPresetColumns = `id, user_id, name, market, side, type, size_rule, size, algorithm, slices, slice_interval, created_at, updated_at`
+
+// OrderPresetRepository is a PostgreSQL implementation of repository.OrderPresetRepository
+type OrderPresetRepository struct {
+	db DBTX
+}
+
+// NewOrderPresetRepository creates a new order preset repository
+func NewOrderPresetRepository(db DBTX) *OrderPresetRepository {
+	return &OrderPresetRepository{db: db}
+}
+
+var _ repository.OrderPresetRepository = (*OrderPresetRepository)(nil)
+
+// Save creates or replaces the user's preset of the same name
+func (r *OrderPresetRepository) Save(ctx context.Context, p *model.OrderPreset) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO order_presets (`+orderPresetColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (user_id, name) DO UPDATE
+		SET market = EXCLUDED.market, side = EXCLUDED.side, type = EXCLUDED.type,
+			size_rule = EXCLUDED.size_rule, size = EXCLUDED.size, algorithm = EXCLUDED.algorithm,
+			slices = EXCLUDED.slices, slice_interval = EXCLUDED.slice_interval, updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at`,
+		p.ID, p.UserID, p.Name, p.Market, p.Side, p.Type, p.SizeRule, p.Size, p.Algorithm, p.Slices, p.SliceInterval,
+		p.CreatedAt, p.UpdatedAt,
+	).Scan(&p.ID, &p.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save order preset: %w", err)
+	}
+	return nil
+}
+
+// GetByName returns the user's preset of a name
+func (r *OrderPresetRepository) GetByName(ctx context.Context, userID uuid.UUID, name string) (*model.OrderPreset, error) {
+	row := r.db.QueryRow(ctx, `SELECT `+orderPresetColumns+` FROM order_presets WHERE user_id = $1 AND name = $2`, userID, name)
+	p, err := scanOrderPreset(row)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return p, nil
+}
+
+// ListByUser returns a user's presets by name
+func (r *OrderPresetRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.OrderPreset, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+orderPresetColumns+` FROM order_presets
+		WHERE user_id = $1
+		ORDER BY name`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list order presets: %w", err)
+	}
+	defer rows.Close()
+
+	var presets []*model.OrderPreset
+	for rows.Next() {
+		p, err := scanOrderPreset(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan order preset: %w", err)
+		}
+		presets = append(presets, p)
+	}
+	return presets, rows.Err()
+}
+
+// Delete removes the user's preset of a name
+func (r *OrderPresetRepository) Delete(ctx context.Context, userID uuid.UUID, name string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM order_presets WHERE user_id = $1 AND name = $2`, userID, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete order preset: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func scanOrderPreset(row pgx.Row) (*model.OrderPreset, error) {
+	var p model.OrderPreset
+	err := row.Scan(&p.ID, &p.UserID, &p.Name, &p.Market, &p.Side, &p.Type, &p.SizeRule, &p.Size,
+		&p.Algorithm, &p.Slices, &p.SliceInterval, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
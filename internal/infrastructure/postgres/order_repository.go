@@ -0,0 +1,227 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+const orderColumns = `id, user_id, position_id, market, side, order_type, price, quantity,
+	executed_quantity, status, exchange_order_id, created_at, updated_at, submitted_at, filled_at, source, source_id, tags, notes, activate_at, strategy_id`
+
+// OrderRepository is a PostgreSQL implementation of repository.OrderRepository
+type OrderRepository struct {
+	db     DBTX
+	reader DBTX // Serves read-only queries; the primary unless a replica is set
+}
+
+// NewOrderRepository creates a new order repository
+func NewOrderRepository(db DBTX) *OrderRepository {
+	return &OrderRepository{db: db, reader: db}
+}
+
+// WithReplica returns a copy of the repository that reads orders from a
+// replica. ListOpen stays on the primary because the engine acts on its
+// results, and a lagging replica could return orders that are already filled.
+func (r *OrderRepository) WithReplica(replica DBTX) *OrderRepository {
+	return &OrderRepository{db: r.db, reader: replica}
+}
+
+var _ repository.OrderRepository = (*OrderRepository)(nil)
+
+// Create inserts a new order
+func (r *OrderRepository) Create(ctx context.Context, order *model.Order) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO orders (`+orderColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)`,
+		order.ID, order.UserID, order.PositionID, order.Market, order.Side, order.Type, order.Price, order.Quantity,
+		order.ExecutedQuantity, order.Status, order.ExchangeOrderID, order.CreatedAt, order.UpdatedAt, order.SubmittedAt, order.FilledAt,
+		orderSource(order.Source), order.SourceID, tagList(order.Tags), order.Notes, order.ActivateAt, order.StrategyID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create order: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves an order by ID
+func (r *OrderRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Order, error) {
+	row := r.reader.QueryRow(ctx, `SELECT `+orderColumns+` FROM orders WHERE id = $1`, id)
+	order, err := scanOrder(row)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return order, nil
+}
+
+// GetCurrent retrieves an order by ID from the primary
+func (r *OrderRepository) GetCurrent(ctx context.Context, id uuid.UUID) (*model.Order, error) {
+	row := r.db.QueryRow(ctx, `SELECT `+orderColumns+` FROM orders WHERE id = $1`, id)
+	order, err := scanOrder(row)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return order, nil
+}
+
+// Update updates the mutable fields of an order
+func (r *OrderRepository) Update(ctx context.Context, order *model.Order) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE orders
+		SET position_id = $2, price = $3, quantity = $4, executed_quantity = $5, status = $6,
+			exchange_order_id = $7, updated_at = $8, submitted_at = $9, filled_at = $10
+		WHERE id = $1`,
+		order.ID, order.PositionID, order.Price, order.Quantity, order.ExecutedQuantity, order.Status,
+		order.ExchangeOrderID, order.UpdatedAt, order.SubmittedAt, order.FilledAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update order: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// Annotate replaces the tags and notes of an order
+func (r *OrderRepository) Annotate(ctx context.Context, id uuid.UUID, tags []string, notes string) error {
+	tag, err := r.db.Exec(ctx, `UPDATE orders SET tags = $2, notes = $3 WHERE id = $1`, id, tagList(tags), notes)
+	if err != nil {
+		return fmt.Errorf("failed to annotate order: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// ListByUser returns a user's orders, newest first
+func (r *OrderRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.Order, error) {
+	rows, err := r.reader.Query(ctx, `SELECT `+orderColumns+` FROM orders WHERE user_id = $1 ORDER BY created_at DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders: %w", err)
+	}
+	return collectOrders(rows)
+}
+
+// ListOpenByUser returns a user's pending, submitted or partially filled
+// orders, newest first. It reads from the primary.
+func (r *OrderRepository) ListOpenByUser(ctx context.Context, userID uuid.UUID) ([]*model.Order, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+orderColumns+` FROM orders
+		WHERE user_id = $1 AND status IN ('pending', 'submitted', 'partial')
+		ORDER BY created_at DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list open orders: %w", err)
+	}
+	return collectOrders(rows)
+}
+
+// ListOpenSellsByPosition returns the pending, submitted or partially filled
+// sells of a position, oldest first. It reads from the primary.
+func (r *OrderRepository) ListOpenSellsByPosition(ctx context.Context, positionID uuid.UUID) ([]*model.Order, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+orderColumns+` FROM orders
+		WHERE position_id = $1 AND side = 'ask' AND status IN ('pending', 'submitted', 'partial')
+		ORDER BY created_at`, positionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list open sells: %w", err)
+	}
+	return collectOrders(rows)
+}
+
+// CountCreatedSince counts a user's orders created after since and returns
+// when the earliest was created. It reads from the primary.
+func (r *OrderRepository) CountCreatedSince(ctx context.Context, userID uuid.UUID, since time.Time) (int, time.Time, error) {
+	var count int
+	var earliest *time.Time
+	err := r.db.QueryRow(ctx, `
+		SELECT COUNT(*), MIN(created_at) FROM orders
+		WHERE user_id = $1 AND created_at > $2`, userID, since).Scan(&count, &earliest)
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to count orders: %w", err)
+	}
+	if earliest == nil {
+		return 0, time.Time{}, nil
+	}
+	return count, *earliest, nil
+}
+
+// ListOpen returns submitted or partially filled orders
+func (r *OrderRepository) ListOpen(ctx context.Context) ([]*model.Order, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+orderColumns+` FROM orders
+		WHERE status IN ('submitted', 'partial') AND exchange_order_id IS NOT NULL
+		ORDER BY created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list open orders: %w", err)
+	}
+	return collectOrders(rows)
+}
+
+// ListDue returns scheduled orders whose activation time is at or before
+// now, soonest first
+func (r *OrderRepository) ListDue(ctx context.Context, now time.Time) ([]*model.Order, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+orderColumns+` FROM orders
+		WHERE status = 'scheduled' AND activate_at <= $1
+		ORDER BY activate_at`, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due orders: %w", err)
+	}
+	return collectOrders(rows)
+}
+
+// ListUnsubmitted returns pending orders without an exchange order ID last
+// updated before the given time, oldest first
+func (r *OrderRepository) ListUnsubmitted(ctx context.Context, before time.Time) ([]*model.Order, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+orderColumns+` FROM orders
+		WHERE status = 'pending' AND exchange_order_id IS NULL AND updated_at < $1
+		ORDER BY updated_at`, before)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list unsubmitted orders: %w", err)
+	}
+	return collectOrders(rows)
+}
+
+func collectOrders(rows pgx.Rows) ([]*model.Order, error) {
+	defer rows.Close()
+
+	var orders []*model.Order
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan order: %w", err)
+		}
+		orders = append(orders, order)
+	}
+	return orders, rows.Err()
+}
+
+func scanOrder(row pgx.Row) (*model.Order, error) {
+	var o model.Order
+	err := row.Scan(
+		&o.ID, &o.UserID, &o.PositionID, &o.Market, &o.Side, &o.Type, &o.Price, &o.Quantity,
+		&o.ExecutedQuantity, &o.Status, &o.ExchangeOrderID, &o.CreatedAt, &o.UpdatedAt, &o.SubmittedAt, &o.FilledAt,
+		&o.Source, &o.SourceID, &o.Tags, &o.Notes, &o.ActivateAt, &o.StrategyID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+// orderSource defaults orders built without a source, such as those imported
+// from the exchange, to the user
+func orderSource(source model.OrderSource) model.OrderSource {
+	if source == "" {
+		return model.OrderSourceUser
+	}
+	return source
+}
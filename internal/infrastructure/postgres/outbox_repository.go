@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// OutboxRepository is a PostgreSQL implementation of repository.OutboxRepository
+type OutboxRepository struct {
+	db DBTX
+}
+
+// NewOutboxRepository creates a new outbox repository
+func NewOutboxRepository(db DBTX) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+var _ repository.OutboxRepository = (*OutboxRepository)(nil)
+
+// Create inserts a new event
+func (r *OutboxRepository) Create(ctx context.Context, event *model.OutboxEvent) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO outbox_events (id, aggregate_type, aggregate_id, event_type, payload, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		event.ID, event.AggregateType, event.AggregateID, event.EventType, []byte(event.Payload), event.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create outbox event: %w", err)
+	}
+	return nil
+}
+
+// ListPending returns undispatched events and locks them until the transaction ends.
+// SKIP LOCKED lets several instances dispatch concurrently without blocking each other.
+func (r *OutboxRepository) ListPending(ctx context.Context, limit int) ([]*model.OutboxEvent, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, aggregate_type, aggregate_id, event_type, payload, attempts, last_error, created_at, dispatched_at
+		FROM outbox_events
+		WHERE dispatched_at IS NULL
+		ORDER BY created_at
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending outbox events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*model.OutboxEvent
+	for rows.Next() {
+		var e model.OutboxEvent
+		var payload []byte
+		if err := rows.Scan(&e.ID, &e.AggregateType, &e.AggregateID, &e.EventType, &payload, &e.Attempts, &e.LastError, &e.CreatedAt, &e.DispatchedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox event: %w", err)
+		}
+		e.Payload = payload
+		events = append(events, &e)
+	}
+	return events, rows.Err()
+}
+
+// MarkDispatched marks an event as delivered
+func (r *OutboxRepository) MarkDispatched(ctx context.Context, id uuid.UUID) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE outbox_events SET dispatched_at = CURRENT_TIMESTAMP, attempts = attempts + 1 WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox event dispatched: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed records a failed delivery attempt
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, reason string) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE outbox_events SET attempts = attempts + 1, last_error = $2 WHERE id = $1`, id, reason)
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox event failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+const paperAccountColumns = `id, user_id, access_key, starting_balance, state, reset_at, created_at, updated_at`
+
+// PaperAccountRepository is a PostgreSQL implementation of repository.PaperAccountRepository
+type PaperAccountRepository struct {
+	db DBTX
+}
+
+// NewPaperAccountRepository creates a new paper account repository
+func NewPaperAccountRepository(db DBTX) *PaperAccountRepository {
+	return &PaperAccountRepository{db: db}
+}
+
+var _ repository.PaperAccountRepository = (*PaperAccountRepository)(nil)
+
+// Create inserts a new paper account
+func (r *PaperAccountRepository) Create(ctx context.Context, a *model.PaperAccount) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO paper_accounts (`+paperAccountColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		a.ID, a.UserID, a.AccessKey, a.StartingBalance, a.State, a.ResetAt, a.CreatedAt, a.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create paper account: %w", err)
+	}
+	return nil
+}
+
+// GetByUser retrieves a user's paper account
+func (r *PaperAccountRepository) GetByUser(ctx context.Context, userID uuid.UUID) (*model.PaperAccount, error) {
+	row := r.db.QueryRow(ctx, `SELECT `+paperAccountColumns+` FROM paper_accounts WHERE user_id = $1`, userID)
+	account, err := scanPaperAccount(row)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return account, nil
+}
+
+// GetByAccessKey retrieves the paper account of a paper API key
+func (r *PaperAccountRepository) GetByAccessKey(ctx context.Context, accessKey string) (*model.PaperAccount, error) {
+	row := r.db.QueryRow(ctx, `SELECT `+paperAccountColumns+` FROM paper_accounts WHERE access_key = $1`, accessKey)
+	account, err := scanPaperAccount(row)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return account, nil
+}
+
+// Update replaces a paper account
+func (r *PaperAccountRepository) Update(ctx context.Context, a *model.PaperAccount) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE paper_accounts
+		SET starting_balance = $2, state = $3, reset_at = $4, updated_at = $5
+		WHERE id = $1`,
+		a.ID, a.StartingBalance, a.State, a.ResetAt, a.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update paper account: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func scanPaperAccount(row pgx.Row) (*model.PaperAccount, error) {
+	var a model.PaperAccount
+	err := row.Scan(&a.ID, &a.UserID, &a.AccessKey, &a.StartingBalance, &a.State, &a.ResetAt, &a.CreatedAt, &a.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// PositionEventRepository is a PostgreSQL implementation of repository.PositionEventRepository
+type PositionEventRepository struct {
+	db DBTX
+}
+
+// NewPositionEventRepository creates a new position event repository
+func NewPositionEventRepository(db DBTX) *PositionEventRepository {
+	return &PositionEventRepository{db: db}
+}
+
+var _ repository.PositionEventRepository = (*PositionEventRepository)(nil)
+
+// Create inserts a new event
+func (r *PositionEventRepository) Create(ctx context.Context, event *model.PositionEvent) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO position_events (id, position_id, type, order_id, source, source_id, price, quantity,
+			position_quantity, entry_price, realized_pnl, created_at, strategy_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`,
+		event.ID, event.PositionID, event.Type, event.OrderID, event.Source, event.SourceID, event.Price, event.Quantity,
+		event.PositionQuantity, event.EntryPrice, event.RealizedPnL, event.CreatedAt, event.StrategyID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create position event: %w", err)
+	}
+	return nil
+}
+
+// ListByPosition returns a position's events in chronological order
+func (r *PositionEventRepository) ListByPosition(ctx context.Context, positionID uuid.UUID) ([]*model.PositionEvent, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, position_id, type, order_id, source, source_id, price, quantity,
+			position_quantity, entry_price, realized_pnl, created_at, strategy_id
+		FROM position_events WHERE position_id = $1 ORDER BY created_at, id`, positionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list position events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*model.PositionEvent
+	for rows.Next() {
+		var e model.PositionEvent
+		if err := rows.Scan(&e.ID, &e.PositionID, &e.Type, &e.OrderID, &e.Source, &e.SourceID, &e.Price, &e.Quantity,
+			&e.PositionQuantity, &e.EntryPrice, &e.RealizedPnL, &e.CreatedAt, &e.StrategyID); err != nil {
+			return nil, fmt.Errorf("failed to scan position event: %w", err)
+		}
+		events = append(events, &e)
+	}
+	return events, rows.Err()
+}
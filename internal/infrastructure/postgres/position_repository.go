@@ -0,0 +1,172 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+const positionColumns = `id, user_id, market, side, status, entry_price, quantity, initial_quantity,
+	realized_pnl, created_at, updated_at, closed_at, accounting_method, lots, tags, notes`
+
+// PositionRepository is a PostgreSQL implementation of repository.PositionRepository
+type PositionRepository struct {
+	db     DBTX
+	reader DBTX // Serves read-only queries; the primary unless a replica is set
+}
+
+// NewPositionRepository creates a new position repository
+func NewPositionRepository(db DBTX) *PositionRepository {
+	return &PositionRepository{db: db, reader: db}
+}
+
+// WithReplica returns a copy of the repository that reads positions from a
+// replica, for read-heavy callers that tolerate replication lag
+func (r *PositionRepository) WithReplica(replica DBTX) *PositionRepository {
+	return &PositionRepository{db: r.db, reader: replica}
+}
+
+var _ repository.PositionRepository = (*PositionRepository)(nil)
+
+// Create inserts a new position
+func (r *PositionRepository) Create(ctx context.Context, position *model.Position) error {
+	lots, err := marshalLots(position.Lots)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, `
+		INSERT INTO positions (`+positionColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`,
+		position.ID, position.UserID, position.Market, position.Side, position.Status, position.EntryPrice, position.Quantity,
+		position.InitialQuantity, position.RealizedPnL, position.CreatedAt, position.UpdatedAt, position.ClosedAt,
+		accountingMethod(position), lots, tagList(position.Tags), position.Notes,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create position: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a position by ID
+func (r *PositionRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Position, error) {
+	row := r.reader.QueryRow(ctx, `SELECT `+positionColumns+` FROM positions WHERE id = $1`, id)
+	position, err := scanPosition(row)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return position, nil
+}
+
+// GetCurrent retrieves a position by ID from the primary
+func (r *PositionRepository) GetCurrent(ctx context.Context, id uuid.UUID) (*model.Position, error) {
+	row := r.db.QueryRow(ctx, `SELECT `+positionColumns+` FROM positions WHERE id = $1`, id)
+	position, err := scanPosition(row)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return position, nil
+}
+
+// Update updates the mutable fields of a position
+func (r *PositionRepository) Update(ctx context.Context, position *model.Position) error {
+	lots, err := marshalLots(position.Lots)
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.db.Exec(ctx, `
+		UPDATE positions
+		SET status = $2, entry_price = $3, quantity = $4, realized_pnl = $5, updated_at = $6, closed_at = $7, lots = $8
+		WHERE id = $1`,
+		position.ID, position.Status, position.EntryPrice, position.Quantity, position.RealizedPnL, position.UpdatedAt, position.ClosedAt,
+		lots,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update position: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// Annotate replaces the tags and notes of a position, leaving the fields the
+// trading engine maintains alone
+func (r *PositionRepository) Annotate(ctx context.Context, id uuid.UUID, tags []string, notes string) error {
+	tag, err := r.db.Exec(ctx, `UPDATE positions SET tags = $2, notes = $3 WHERE id = $1`, id, tagList(tags), notes)
+	if err != nil {
+		return fmt.Errorf("failed to annotate position: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// ListByUser returns a user's positions, newest first
+func (r *PositionRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.Position, error) {
+	rows, err := r.reader.Query(ctx, `SELECT `+positionColumns+` FROM positions WHERE user_id = $1 ORDER BY created_at DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list positions: %w", err)
+	}
+	defer rows.Close()
+
+	var positions []*model.Position
+	for rows.Next() {
+		position, err := scanPosition(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan position: %w", err)
+		}
+		positions = append(positions, position)
+	}
+	return positions, rows.Err()
+}
+
+func scanPosition(row pgx.Row) (*model.Position, error) {
+	var p model.Position
+	var lots []byte
+	err := row.Scan(
+		&p.ID, &p.UserID, &p.Market, &p.Side, &p.Status, &p.EntryPrice, &p.Quantity, &p.InitialQuantity,
+		&p.RealizedPnL, &p.CreatedAt, &p.UpdatedAt, &p.ClosedAt, &p.AccountingMethod, &lots, &p.Tags, &p.Notes,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(lots, &p.Lots); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal lots: %w", err)
+	}
+	return &p, nil
+}
+
+func marshalLots(lots model.Lots) ([]byte, error) {
+	if lots == nil {
+		lots = model.Lots{}
+	}
+	data, err := json.Marshal(lots)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal lots: %w", err)
+	}
+	return data, nil
+}
+
+// tagList stores missing tags as an empty array rather than NULL
+func tagList(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
+// accountingMethod returns the position's method, defaulting to average cost
+func accountingMethod(position *model.Position) model.AccountingMethod {
+	if position.AccountingMethod == "" {
+		return model.AccountingAverage
+	}
+	return position.AccountingMethod
+}
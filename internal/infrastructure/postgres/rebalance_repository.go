@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+const targetPortfolioColumns = `user_id, weights, drift_threshold, interval_hours, enabled, pending_buys,
+	last_rebalanced_at, created_at, updated_at`
+
+// TargetPortfolioRepository is a PostgreSQL implementation of repository.TargetPortfolioRepository
+type TargetPortfolioRepository struct {
+	db DBTX
+}
+
+// NewTargetPortfolioRepository creates a new target portfolio repository
+func NewTargetPortfolioRepository(db DBTX) *TargetPortfolioRepository {
+	return &TargetPortfolioRepository{db: db}
+}
+
+var _ repository.TargetPortfolioRepository = (*TargetPortfolioRepository)(nil)
+
+// Get returns the target portfolio of a user
+func (r *TargetPortfolioRepository) Get(ctx context.Context, userID uuid.UUID) (*model.TargetPortfolio, error) {
+	row := r.db.QueryRow(ctx, `SELECT `+targetPortfolioColumns+` FROM target_portfolios WHERE user_id = $1`, userID)
+	target, err := scanTargetPortfolio(row)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return target, nil
+}
+
+// Save creates or replaces a target portfolio
+func (r *TargetPortfolioRepository) Save(ctx context.Context, target *model.TargetPortfolio) error {
+	weights, err := json.Marshal(target.Weights)
+	if err != nil {
+		return fmt.Errorf("failed to marshal weights: %w", err)
+	}
+
+	_, err = r.db.Exec(ctx, `
+		INSERT INTO target_portfolios (`+targetPortfolioColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (user_id) DO UPDATE
+		SET weights = EXCLUDED.weights, drift_threshold = EXCLUDED.drift_threshold,
+			interval_hours = EXCLUDED.interval_hours, enabled = EXCLUDED.enabled, pending_buys = EXCLUDED.pending_buys,
+			last_rebalanced_at = EXCLUDED.last_rebalanced_at, updated_at = EXCLUDED.updated_at`,
+		target.UserID, weights, target.DriftThreshold, target.IntervalHours, target.Enabled, target.PendingBuys,
+		target.LastRebalancedAt, target.CreatedAt, target.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save target portfolio: %w", err)
+	}
+	return nil
+}
+
+// Delete removes a target portfolio
+func (r *TargetPortfolioRepository) Delete(ctx context.Context, userID uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM target_portfolios WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete target portfolio: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// ListEnabled returns the target portfolios that rebalance automatically
+func (r *TargetPortfolioRepository) ListEnabled(ctx context.Context) ([]*model.TargetPortfolio, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+targetPortfolioColumns+` FROM target_portfolios WHERE enabled`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list target portfolios: %w", err)
+	}
+	defer rows.Close()
+
+	var targets []*model.TargetPortfolio
+	for rows.Next() {
+		target, err := scanTargetPortfolio(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan target portfolio: %w", err)
+		}
+		targets = append(targets, target)
+	}
+	return targets, rows.Err()
+}
+
+func scanTargetPortfolio(row pgx.Row) (*model.TargetPortfolio, error) {
+	var t model.TargetPortfolio
+	var weights []byte
+	err := row.Scan(&t.UserID, &weights, &t.DriftThreshold, &t.IntervalHours, &t.Enabled, &t.PendingBuys,
+		&t.LastRebalancedAt, &t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(weights, &t.Weights); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal weights: %w", err)
+	}
+	return &t, nil
+}
@@ -0,0 +1,181 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+const recurringOrderColumns = `id, user_id, market, side, amount, schedule, time_zone, active,
+	next_run_at, last_run_at, created_at, updated_at`
+
+// RecurringOrderRepository is a PostgreSQL implementation of repository.RecurringOrderRepository
+type RecurringOrderRepository struct {
+	db DBTX
+}
+
+// NewRecurringOrderRepository creates a new recurring order repository
+func NewRecurringOrderRepository(db DBTX) *RecurringOrderRepository {
+	return &RecurringOrderRepository{db: db}
+}
+
+var _ repository.RecurringOrderRepository = (*RecurringOrderRepository)(nil)
+
+// Create inserts a new recurring order
+func (r *RecurringOrderRepository) Create(ctx context.Context, o *model.RecurringOrder) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO recurring_orders (`+recurringOrderColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		o.ID, o.UserID, o.Market, o.Side, o.Amount, o.Schedule, o.TimeZone, o.Active,
+		o.NextRunAt, o.LastRunAt, o.CreatedAt, o.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create recurring order: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a recurring order by ID
+func (r *RecurringOrderRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.RecurringOrder, error) {
+	row := r.db.QueryRow(ctx, `SELECT `+recurringOrderColumns+` FROM recurring_orders WHERE id = $1`, id)
+	o, err := scanRecurringOrder(row)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return o, nil
+}
+
+// Update updates the mutable fields of a recurring order
+func (r *RecurringOrderRepository) Update(ctx context.Context, o *model.RecurringOrder) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE recurring_orders
+		SET amount = $2, schedule = $3, time_zone = $4, active = $5, next_run_at = $6, last_run_at = $7, updated_at = $8
+		WHERE id = $1`,
+		o.ID, o.Amount, o.Schedule, o.TimeZone, o.Active, o.NextRunAt, o.LastRunAt, o.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update recurring order: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes a recurring order; its runs are removed by cascade
+func (r *RecurringOrderRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM recurring_orders WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete recurring order: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// ListByUser returns a user's recurring orders, oldest first
+func (r *RecurringOrderRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.RecurringOrder, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+recurringOrderColumns+` FROM recurring_orders
+		WHERE user_id = $1
+		ORDER BY created_at`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recurring orders: %w", err)
+	}
+	return collectRecurringOrders(rows)
+}
+
+// ListDue returns the active recurring orders whose next run is at or before now
+func (r *RecurringOrderRepository) ListDue(ctx context.Context, now time.Time) ([]*model.RecurringOrder, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+recurringOrderColumns+` FROM recurring_orders
+		WHERE active AND next_run_at <= $1
+		ORDER BY next_run_at`, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due recurring orders: %w", err)
+	}
+	return collectRecurringOrders(rows)
+}
+
+// CreateRun inserts a run unless one exists for the recurring order and time
+func (r *RecurringOrderRepository) CreateRun(ctx context.Context, run *model.RecurringOrderRun) (bool, error) {
+	tag, err := r.db.Exec(ctx, `
+		INSERT INTO recurring_order_runs (id, recurring_order_id, scheduled_at, status, order_id, error, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (recurring_order_id, scheduled_at) DO NOTHING`,
+		run.ID, run.RecurringOrderID, run.ScheduledAt, run.Status, run.OrderID, run.Error, run.CreatedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to create recurring order run: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// UpdateRun records the outcome of a run
+func (r *RecurringOrderRepository) UpdateRun(ctx context.Context, run *model.RecurringOrderRun) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE recurring_order_runs SET status = $2, order_id = $3, error = $4 WHERE id = $1`,
+		run.ID, run.Status, run.OrderID, run.Error,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update recurring order run: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// ListRuns returns a recurring order's runs, newest first
+func (r *RecurringOrderRepository) ListRuns(ctx context.Context, recurringOrderID uuid.UUID, limit int) ([]*model.RecurringOrderRun, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, recurring_order_id, scheduled_at, status, order_id, error, created_at
+		FROM recurring_order_runs
+		WHERE recurring_order_id = $1
+		ORDER BY scheduled_at DESC
+		LIMIT $2`, recurringOrderID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recurring order runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []*model.RecurringOrderRun
+	for rows.Next() {
+		var run model.RecurringOrderRun
+		if err := rows.Scan(&run.ID, &run.RecurringOrderID, &run.ScheduledAt, &run.Status, &run.OrderID, &run.Error, &run.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan recurring order run: %w", err)
+		}
+		runs = append(runs, &run)
+	}
+	return runs, rows.Err()
+}
+
+func collectRecurringOrders(rows pgx.Rows) ([]*model.RecurringOrder, error) {
+	defer rows.Close()
+
+	var orders []*model.RecurringOrder
+	for rows.Next() {
+		o, err := scanRecurringOrder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan recurring order: %w", err)
+		}
+		orders = append(orders, o)
+	}
+	return orders, rows.Err()
+}
+
+func scanRecurringOrder(row pgx.Row) (*model.RecurringOrder, error) {
+	var o model.RecurringOrder
+	err := row.Scan(&o.ID, &o.UserID, &o.Market, &o.Side, &o.Amount, &o.Schedule, &o.TimeZone, &o.Active,
+		&o.NextRunAt, &o.LastRunAt, &o.CreatedAt, &o.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+const (
+	// expiredOrders selects up to $2 cancelled and failed orders without
+	// fills last updated before $1, oldest first
+	expiredOrders = `SELECT id FROM orders
+		WHERE status IN ('cancelled', 'failed') AND executed_quantity = 0 AND updated_at < $1
+		ORDER BY updated_at
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED`
+	// expiredGuards selects up to $2 drawdown guards that triggered before
+	// $1, oldest first
+	expiredGuards = `SELECT position_id FROM drawdown_guards
+		WHERE triggered_at < $1
+		ORDER BY triggered_at
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED`
+)
+
+// TradingRetentionRepository is a PostgreSQL implementation of repository.TradingRetentionRepository
+type TradingRetentionRepository struct {
+	db DBTX
+}
+
+// NewTradingRetentionRepository creates a new trading retention repository
+func NewTradingRetentionRepository(db DBTX) *TradingRetentionRepository {
+	return &TradingRetentionRepository{db: db}
+}
+
+var _ repository.TradingRetentionRepository = (*TradingRetentionRepository)(nil)
+
+// ExpireOrders archives or deletes up to limit expired orders. Archiving
+// moves the rows in one statement, so an order is never both kept and
+// archived.
+func (r *TradingRetentionRepository) ExpireOrders(ctx context.Context, mode model.RetentionMode, cutoff time.Time, limit int) (int, error) {
+	query := `DELETE FROM orders WHERE id IN (` + expiredOrders + `)`
+	if mode == model.RetentionArchive {
+		query = `
+			WITH expired AS (
+				DELETE FROM orders WHERE id IN (` + expiredOrders + `)
+				RETURNING *
+			)
+			INSERT INTO archived_records (kind, id, user_id, data)
+			SELECT '` + model.ArchivedOrder + `', id, user_id, to_jsonb(expired) FROM expired
+			ON CONFLICT (kind, id) DO UPDATE SET data = EXCLUDED.data, archived_at = EXCLUDED.archived_at`
+	}
+
+	tag, err := r.db.Exec(ctx, query, cutoff, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire orders: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// ExpireStrategies archives or deletes up to limit expired drawdown guards
+func (r *TradingRetentionRepository) ExpireStrategies(ctx context.Context, mode model.RetentionMode, cutoff time.Time, limit int) (int, error) {
+	query := `DELETE FROM drawdown_guards WHERE position_id IN (` + expiredGuards + `)`
+	if mode == model.RetentionArchive {
+		query = `
+			WITH expired AS (
+				DELETE FROM drawdown_guards WHERE position_id IN (` + expiredGuards + `)
+				RETURNING *
+			)
+			INSERT INTO archived_records (kind, id, user_id, data)
+			SELECT '` + model.ArchivedDrawdownGuard + `', position_id, user_id, to_jsonb(expired) FROM expired
+			ON CONFLICT (kind, id) DO UPDATE SET data = EXCLUDED.data, archived_at = EXCLUDED.archived_at`
+	}
+
+	tag, err := r.db.Exec(ctx, query, cutoff, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire drawdown guards: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// CountExpired returns how many orders and drawdown guards have expired
+func (r *TradingRetentionRepository) CountExpired(ctx context.Context, ordersBefore, strategiesBefore time.Time) (int, int, error) {
+	var orders, strategies int
+	err := r.db.QueryRow(ctx, `
+		SELECT
+			(SELECT COUNT(*) FROM orders
+				WHERE $1::timestamptz IS NOT NULL AND status IN ('cancelled', 'failed')
+					AND executed_quantity = 0 AND updated_at < $1),
+			(SELECT COUNT(*) FROM drawdown_guards
+				WHERE $2::timestamptz IS NOT NULL AND triggered_at < $2)`,
+		nullTime(ordersBefore), nullTime(strategiesBefore),
+	).Scan(&orders, &strategies)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count expired records: %w", err)
+	}
+	return orders, strategies, nil
+}
+
+// nullTime passes a zero time as NULL
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
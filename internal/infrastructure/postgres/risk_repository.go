@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// RiskLimitsRepository is a PostgreSQL implementation of repository.RiskLimitsRepository
+type RiskLimitsRepository struct {
+	db DBTX
+}
+
+// NewRiskLimitsRepository creates a new risk limits repository
+func NewRiskLimitsRepository(db DBTX) *RiskLimitsRepository {
+	return &RiskLimitsRepository{db: db}
+}
+
+var _ repository.RiskLimitsRepository = (*RiskLimitsRepository)(nil)
+
+const riskLimitsColumns = `user_id, max_order_notional, max_open_positions, max_market_exposure, max_total_exposure,
+	downsize, daily_loss_limit, flatten_on_loss, day_start, timezone, updated_at, max_concentration`
+
+// Get returns the user's limits
+func (r *RiskLimitsRepository) Get(ctx context.Context, userID uuid.UUID) (*model.RiskLimits, error) {
+	limits, err := scanRiskLimits(r.db.QueryRow(ctx, `SELECT `+riskLimitsColumns+` FROM risk_limits WHERE user_id = $1`, userID))
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return limits, nil
+}
+
+// Save creates or replaces the user's limits
+func (r *RiskLimitsRepository) Save(ctx context.Context, l *model.RiskLimits) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO risk_limits (`+riskLimitsColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (user_id) DO UPDATE
+		SET max_order_notional = EXCLUDED.max_order_notional, max_open_positions = EXCLUDED.max_open_positions,
+			max_market_exposure = EXCLUDED.max_market_exposure, max_total_exposure = EXCLUDED.max_total_exposure,
+			downsize = EXCLUDED.downsize, daily_loss_limit = EXCLUDED.daily_loss_limit,
+			flatten_on_loss = EXCLUDED.flatten_on_loss, day_start = EXCLUDED.day_start,
+			timezone = EXCLUDED.timezone, updated_at = EXCLUDED.updated_at,
+			max_concentration = EXCLUDED.max_concentration`,
+		l.UserID, l.MaxOrderNotional, l.MaxOpenPositions, l.MaxMarketExposure, l.MaxTotalExposure,
+		l.Downsize, l.DailyLossLimit, l.FlattenOnLoss, l.DayStart, l.Timezone, l.UpdatedAt, l.MaxConcentration,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save risk limits: %w", err)
+	}
+	return nil
+}
+
+// ListDailyLossLimits returns the limits of users with a daily loss limit
+func (r *RiskLimitsRepository) ListDailyLossLimits(ctx context.Context) ([]*model.RiskLimits, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+riskLimitsColumns+` FROM risk_limits WHERE daily_loss_limit > 0`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list risk limits: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*model.RiskLimits
+	for rows.Next() {
+		limits, err := scanRiskLimits(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan risk limits: %w", err)
+		}
+		result = append(result, limits)
+	}
+	return result, rows.Err()
+}
+
+func scanRiskLimits(row pgx.Row) (*model.RiskLimits, error) {
+	var l model.RiskLimits
+	err := row.Scan(&l.UserID, &l.MaxOrderNotional, &l.MaxOpenPositions, &l.MaxMarketExposure, &l.MaxTotalExposure,
+		&l.Downsize, &l.DailyLossLimit, &l.FlattenOnLoss, &l.DayStart, &l.Timezone, &l.UpdatedAt, &l.MaxConcentration)
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// RiskStateRepository is a PostgreSQL implementation of repository.RiskStateRepository
+type RiskStateRepository struct {
+	db DBTX
+}
+
+// NewRiskStateRepository creates a new risk state repository
+func NewRiskStateRepository(db DBTX) *RiskStateRepository {
+	return &RiskStateRepository{db: db}
+}
+
+var _ repository.RiskStateRepository = (*RiskStateRepository)(nil)
+
+// Get returns the user's state
+func (r *RiskStateRepository) Get(ctx context.Context, userID uuid.UUID) (*model.RiskState, error) {
+	var s model.RiskState
+	err := r.db.QueryRow(ctx, `
+		SELECT user_id, day, start_pnl, day_pnl, halted_at, updated_at
+		FROM risk_state WHERE user_id = $1`, userID,
+	).Scan(&s.UserID, &s.Day, &s.StartPnL, &s.DayPnL, &s.HaltedAt, &s.UpdatedAt)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &s, nil
+}
+
+// Save creates or replaces the user's state
+func (r *RiskStateRepository) Save(ctx context.Context, s *model.RiskState) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO risk_state (user_id, day, start_pnl, day_pnl, halted_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id) DO UPDATE
+		SET day = EXCLUDED.day, start_pnl = EXCLUDED.start_pnl, day_pnl = EXCLUDED.day_pnl,
+			halted_at = EXCLUDED.halted_at, updated_at = EXCLUDED.updated_at`,
+		s.UserID, s.Day, s.StartPnL, s.DayPnL, s.HaltedAt, s.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save risk state: %w", err)
+	}
+	return nil
+}
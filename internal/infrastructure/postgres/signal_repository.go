@@ -0,0 +1,381 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+const signalSourceColumns = `id, user_id, type, name, token_hash, telegram_chat_id, indicator, markets, strategies,
+	public, active, last_signal_at, created_at, updated_at`
+
+// SignalSourceRepository is a PostgreSQL implementation of repository.SignalSourceRepository
+type SignalSourceRepository struct {
+	db DBTX
+}
+
+// NewSignalSourceRepository creates a new signal source repository
+func NewSignalSourceRepository(db DBTX) *SignalSourceRepository {
+	return &SignalSourceRepository{db: db}
+}
+
+var _ repository.SignalSourceRepository = (*SignalSourceRepository)(nil)
+
+// Create inserts a new signal source
+func (r *SignalSourceRepository) Create(ctx context.Context, s *model.SignalSource) error {
+	indicator, strategies, err := marshalSignalSource(s)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, `
+		INSERT INTO signal_sources (`+signalSourceColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`,
+		s.ID, s.UserID, s.Type, s.Name, nullableTokenHash(s.TokenHash), s.TelegramChatID, indicator,
+		signalMarkets(s.Markets), strategies, s.Public, s.Active, s.LastSignalAt, s.CreatedAt, s.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create signal source: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a signal source by ID
+func (r *SignalSourceRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.SignalSource, error) {
+	row := r.db.QueryRow(ctx, `SELECT `+signalSourceColumns+` FROM signal_sources WHERE id = $1`, id)
+	s, err := scanSignalSource(row)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return s, nil
+}
+
+// GetByTokenHash retrieves the webhook source with a token hash
+func (r *SignalSourceRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*model.SignalSource, error) {
+	row := r.db.QueryRow(ctx, `SELECT `+signalSourceColumns+` FROM signal_sources WHERE token_hash = $1`, tokenHash)
+	s, err := scanSignalSource(row)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return s, nil
+}
+
+// Update updates the mutable fields of a signal source
+func (r *SignalSourceRepository) Update(ctx context.Context, s *model.SignalSource) error {
+	indicator, strategies, err := marshalSignalSource(s)
+	if err != nil {
+		return err
+	}
+
+	tag, err := r.db.Exec(ctx, `
+		UPDATE signal_sources
+		SET name = $2, token_hash = $3, telegram_chat_id = $4, indicator = $5, markets = $6, strategies = $7,
+			public = $8, active = $9, last_signal_at = $10, updated_at = $11
+		WHERE id = $1`,
+		s.ID, s.Name, nullableTokenHash(s.TokenHash), s.TelegramChatID, indicator, signalMarkets(s.Markets), strategies,
+		s.Public, s.Active, s.LastSignalAt, s.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update signal source: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes a signal source; its subscriptions and logs are removed by
+// cascade
+func (r *SignalSourceRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM signal_sources WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete signal source: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// ListByUser returns the sources a user owns, oldest first
+func (r *SignalSourceRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.SignalSource, error) {
+	return r.list(ctx, `WHERE user_id = $1 ORDER BY created_at`, userID)
+}
+
+// ListPublic returns the active public sources, oldest first
+func (r *SignalSourceRepository) ListPublic(ctx context.Context) ([]*model.SignalSource, error) {
+	return r.list(ctx, `WHERE active AND public ORDER BY created_at`)
+}
+
+// ListActive returns the active sources of a type
+func (r *SignalSourceRepository) ListActive(ctx context.Context, sourceType model.SignalSourceType) ([]*model.SignalSource, error) {
+	return r.list(ctx, `WHERE active AND type = $1 ORDER BY created_at`, sourceType)
+}
+
+// ListByTelegramChat returns the active sources reading a Telegram chat
+func (r *SignalSourceRepository) ListByTelegramChat(ctx context.Context, chatID int64) ([]*model.SignalSource, error) {
+	return r.list(ctx, `WHERE active AND telegram_chat_id = $1 ORDER BY created_at`, chatID)
+}
+
+func (r *SignalSourceRepository) list(ctx context.Context, where string, args ...any) ([]*model.SignalSource, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+signalSourceColumns+` FROM signal_sources `+where, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list signal sources: %w", err)
+	}
+	defer rows.Close()
+
+	var sources []*model.SignalSource
+	for rows.Next() {
+		s, err := scanSignalSource(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan signal source: %w", err)
+		}
+		sources = append(sources, s)
+	}
+	return sources, rows.Err()
+}
+
+// signalMarkets stores "any market" as an empty array rather than NULL
+func signalMarkets(markets []string) []string {
+	if markets == nil {
+		return []string{}
+	}
+	return markets
+}
+
+// nullableTokenHash stores the missing token of non-webhook sources as NULL,
+// which the unique index allows any number of
+func nullableTokenHash(tokenHash string) *string {
+	if tokenHash == "" {
+		return nil
+	}
+	return &tokenHash
+}
+
+func marshalSignalSource(s *model.SignalSource) (indicator, strategies []byte, err error) {
+	if s.Indicator != nil {
+		if indicator, err = json.Marshal(s.Indicator); err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal signal indicator: %w", err)
+		}
+	}
+	list := s.Strategies
+	if list == nil {
+		list = []model.SignalStrategy{}
+	}
+	if strategies, err = json.Marshal(list); err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal signal strategies: %w", err)
+	}
+	return indicator, strategies, nil
+}
+
+func scanSignalSource(row pgx.Row) (*model.SignalSource, error) {
+	var s model.SignalSource
+	var tokenHash *string
+	var indicator, strategies []byte
+	err := row.Scan(&s.ID, &s.UserID, &s.Type, &s.Name, &tokenHash, &s.TelegramChatID, &indicator, &s.Markets, &strategies,
+		&s.Public, &s.Active, &s.LastSignalAt, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if tokenHash != nil {
+		s.TokenHash = *tokenHash
+	}
+	if indicator != nil {
+		if err := json.Unmarshal(indicator, &s.Indicator); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal signal indicator: %w", err)
+		}
+	}
+	if err := json.Unmarshal(strategies, &s.Strategies); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal signal strategies: %w", err)
+	}
+	return &s, nil
+}
+
+const signalSubscriptionColumns = `id, user_id, source_id, market, strategy, open_amount, max_order_amount,
+	max_position_amount, max_daily_orders, active, created_at, updated_at`
+
+// SignalSubscriptionRepository is a PostgreSQL implementation of repository.SignalSubscriptionRepository
+type SignalSubscriptionRepository struct {
+	db DBTX
+}
+
+// NewSignalSubscriptionRepository creates a new signal subscription repository
+func NewSignalSubscriptionRepository(db DBTX) *SignalSubscriptionRepository {
+	return &SignalSubscriptionRepository{db: db}
+}
+
+var _ repository.SignalSubscriptionRepository = (*SignalSubscriptionRepository)(nil)
+
+// Create inserts a new subscription
+func (r *SignalSubscriptionRepository) Create(ctx context.Context, s *model.SignalSubscription) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO signal_subscriptions (`+signalSubscriptionColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		s.ID, s.UserID, s.SourceID, s.Market, s.Strategy, s.OpenAmount, s.MaxOrderAmount,
+		s.MaxPositionAmount, s.MaxDailyOrders, s.Active, s.CreatedAt, s.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create signal subscription: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a subscription by ID
+func (r *SignalSubscriptionRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.SignalSubscription, error) {
+	row := r.db.QueryRow(ctx, `SELECT `+signalSubscriptionColumns+` FROM signal_subscriptions WHERE id = $1`, id)
+	s, err := scanSignalSubscription(row)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return s, nil
+}
+
+// Update updates the mutable fields of a subscription
+func (r *SignalSubscriptionRepository) Update(ctx context.Context, s *model.SignalSubscription) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE signal_subscriptions
+		SET market = $2, strategy = $3, open_amount = $4, max_order_amount = $5, max_position_amount = $6,
+			max_daily_orders = $7, active = $8, updated_at = $9
+		WHERE id = $1`,
+		s.ID, s.Market, s.Strategy, s.OpenAmount, s.MaxOrderAmount, s.MaxPositionAmount,
+		s.MaxDailyOrders, s.Active, s.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update signal subscription: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes a subscription; its logs are kept
+func (r *SignalSubscriptionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM signal_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete signal subscription: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// ListByUser returns a user's subscriptions, oldest first
+func (r *SignalSubscriptionRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.SignalSubscription, error) {
+	return r.list(ctx, `WHERE user_id = $1 ORDER BY created_at`, userID)
+}
+
+// ListBySource returns the active subscriptions to a source
+func (r *SignalSubscriptionRepository) ListBySource(ctx context.Context, sourceID uuid.UUID) ([]*model.SignalSubscription, error) {
+	return r.list(ctx, `WHERE source_id = $1 AND active ORDER BY created_at`, sourceID)
+}
+
+func (r *SignalSubscriptionRepository) list(ctx context.Context, where string, args ...any) ([]*model.SignalSubscription, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+signalSubscriptionColumns+` FROM signal_subscriptions `+where, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list signal subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var subscriptions []*model.SignalSubscription
+	for rows.Next() {
+		s, err := scanSignalSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan signal subscription: %w", err)
+		}
+		subscriptions = append(subscriptions, s)
+	}
+	return subscriptions, rows.Err()
+}
+
+func scanSignalSubscription(row pgx.Row) (*model.SignalSubscription, error) {
+	var s model.SignalSubscription
+	err := row.Scan(&s.ID, &s.UserID, &s.SourceID, &s.Market, &s.Strategy, &s.OpenAmount, &s.MaxOrderAmount,
+		&s.MaxPositionAmount, &s.MaxDailyOrders, &s.Active, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+const signalLogColumns = `id, source_id, subscription_id, user_id, payload, action, market, strategy, status,
+	order_ids, error, received_at`
+
+// SignalLogRepository is a PostgreSQL implementation of repository.SignalLogRepository
+type SignalLogRepository struct {
+	db DBTX
+}
+
+// NewSignalLogRepository creates a new signal log repository
+func NewSignalLogRepository(db DBTX) *SignalLogRepository {
+	return &SignalLogRepository{db: db}
+}
+
+var _ repository.SignalLogRepository = (*SignalLogRepository)(nil)
+
+// Create inserts a signal log
+func (r *SignalLogRepository) Create(ctx context.Context, l *model.SignalLog) error {
+	orderIDs := l.OrderIDs
+	if orderIDs == nil {
+		orderIDs = []uuid.UUID{}
+	}
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO signal_logs (`+signalLogColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		l.ID, l.SourceID, l.SubscriptionID, l.UserID, l.Payload, l.Action, l.Market, l.Strategy, l.Status,
+		orderIDs, l.Error, l.ReceivedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create signal log: %w", err)
+	}
+	return nil
+}
+
+// ListBySource returns a source's logs, newest first
+func (r *SignalLogRepository) ListBySource(ctx context.Context, sourceID uuid.UUID, limit int) ([]*model.SignalLog, error) {
+	return r.list(ctx, `WHERE source_id = $1 ORDER BY received_at DESC LIMIT $2`, sourceID, limit)
+}
+
+// ListBySubscription returns a subscription's logs, newest first
+func (r *SignalLogRepository) ListBySubscription(ctx context.Context, subscriptionID uuid.UUID, limit int) ([]*model.SignalLog, error) {
+	return r.list(ctx, `WHERE subscription_id = $1 ORDER BY received_at DESC LIMIT $2`, subscriptionID, limit)
+}
+
+// CountOrders returns how many orders a subscription placed on signals
+// received at or after since
+func (r *SignalLogRepository) CountOrders(ctx context.Context, subscriptionID uuid.UUID, since time.Time) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx, `
+		SELECT COALESCE(SUM(cardinality(order_ids)), 0) FROM signal_logs
+		WHERE subscription_id = $1 AND received_at >= $2`, subscriptionID, since).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count signal orders: %w", err)
+	}
+	return count, nil
+}
+
+func (r *SignalLogRepository) list(ctx context.Context, where string, args ...any) ([]*model.SignalLog, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+signalLogColumns+` FROM signal_logs `+where, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list signal logs: %w", err)
+	}
+	defer rows.Close()
+
+	var logs []*model.SignalLog
+	for rows.Next() {
+		var l model.SignalLog
+		if err := rows.Scan(&l.ID, &l.SourceID, &l.SubscriptionID, &l.UserID, &l.Payload, &l.Action, &l.Market,
+			&l.Strategy, &l.Status, &l.OrderIDs, &l.Error, &l.ReceivedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan signal log: %w", err)
+		}
+		logs = append(logs, &l)
+	}
+	return logs, rows.Err()
+}
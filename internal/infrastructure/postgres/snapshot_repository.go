@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// SnapshotRepository is a PostgreSQL implementation of repository.SnapshotRepository
+type SnapshotRepository struct {
+	db DBTX
+}
+
+// NewSnapshotRepository creates a new snapshot repository
+func NewSnapshotRepository(db DBTX) *SnapshotRepository {
+	return &SnapshotRepository{db: db}
+}
+
+var _ repository.SnapshotRepository = (*SnapshotRepository)(nil)
+
+// Create inserts a snapshot unless one already exists for the user, period and time
+func (r *SnapshotRepository) Create(ctx context.Context, snapshot *model.AccountSnapshot) error {
+	balances, err := json.Marshal(snapshot.Balances)
+	if err != nil {
+		return fmt.Errorf("failed to marshal balances: %w", err)
+	}
+	positions, err := json.Marshal(snapshot.Positions)
+	if err != nil {
+		return fmt.Errorf("failed to marshal positions: %w", err)
+	}
+
+	_, err = r.db.Exec(ctx, `
+		INSERT INTO account_snapshots (id, user_id, period, taken_at, cash_balance, holdings_value, equity,
+			unrealized_pnl, balances, positions, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (user_id, period, taken_at) DO NOTHING`,
+		snapshot.ID, snapshot.UserID, snapshot.Period, snapshot.TakenAt, snapshot.CashBalance, snapshot.HoldingsValue,
+		snapshot.Equity, snapshot.UnrealizedPnL, balances, positions, snapshot.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create account snapshot: %w", err)
+	}
+	return nil
+}
+
+// ListByUser returns a user's snapshots taken in [from, to), oldest first
+func (r *SnapshotRepository) ListByUser(ctx context.Context, userID uuid.UUID, period model.SnapshotPeriod, from, to time.Time) ([]*model.AccountSnapshot, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, user_id, period, taken_at, cash_balance, holdings_value, equity, unrealized_pnl,
+			balances, positions, created_at
+		FROM account_snapshots
+		WHERE user_id = $1 AND period = $2 AND taken_at >= $3 AND taken_at < $4
+		ORDER BY taken_at`, userID, period, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list account snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots []*model.AccountSnapshot
+	for rows.Next() {
+		var s model.AccountSnapshot
+		var balances, positions []byte
+		err := rows.Scan(&s.ID, &s.UserID, &s.Period, &s.TakenAt, &s.CashBalance, &s.HoldingsValue, &s.Equity,
+			&s.UnrealizedPnL, &balances, &positions, &s.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan account snapshot: %w", err)
+		}
+		if err := json.Unmarshal(balances, &s.Balances); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal balances: %w", err)
+		}
+		if err := json.Unmarshal(positions, &s.Positions); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal positions: %w", err)
+		}
+		snapshots = append(snapshots, &s)
+	}
+	return snapshots, rows.Err()
+}
@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// defaultTemplateListLimit bounds catalog listings that don't set a limit
+const defaultTemplateListLimit = 100
+
+const strategyTemplateColumns = `id, publisher_id, name, description, type, params, backtest, usage_count, created_at, updated_at`
+
+// StrategyTemplateRepository is a PostgreSQL implementation of repository.StrategyTemplateRepository
+type StrategyTemplateRepository struct {
+	db DBTX
+}
+
+// NewStrategyTemplateRepository creates a new strategy template repository
+func NewStrategyTemplateRepository(db DBTX) *StrategyTemplateRepository {
+	return &StrategyTemplateRepository{db: db}
+}
+
+var _ repository.StrategyTemplateRepository = (*StrategyTemplateRepository)(nil)
+
+// Create inserts a new strategy template
+func (r *StrategyTemplateRepository) Create(ctx context.Context, t *model.StrategyTemplate) error {
+	params, err := json.Marshal(t.Params)
+	if err != nil {
+		return fmt.Errorf("failed to marshal params: %w", err)
+	}
+	var backtest []byte
+	if t.Backtest != nil {
+		if backtest, err = json.Marshal(t.Backtest); err != nil {
+			return fmt.Errorf("failed to marshal backtest: %w", err)
+		}
+	}
+
+	_, err = r.db.Exec(ctx, `
+		INSERT INTO strategy_templates (`+strategyTemplateColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		t.ID, t.PublisherID, t.Name, t.Description, t.Type, params, backtest, t.UsageCount, t.CreatedAt, t.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create strategy template: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a strategy template
+func (r *StrategyTemplateRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.StrategyTemplate, error) {
+	row := r.db.QueryRow(ctx, `SELECT `+strategyTemplateColumns+` FROM strategy_templates WHERE id = $1`, id)
+	template, err := scanStrategyTemplate(row)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return template, nil
+}
+
+// List returns templates most used first, then newest first
+func (r *StrategyTemplateRepository) List(ctx context.Context, filter repository.StrategyTemplateFilter) ([]*model.StrategyTemplate, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultTemplateListLimit
+	}
+
+	var publisherID *uuid.UUID
+	if filter.PublisherID != uuid.Nil {
+		publisherID = &filter.PublisherID
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT `+strategyTemplateColumns+`
+		FROM strategy_templates
+		WHERE ($1 = '' OR type = $1) AND ($2::uuid IS NULL OR publisher_id = $2)
+		ORDER BY usage_count DESC, created_at DESC
+		LIMIT $3`, string(filter.Type), publisherID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list strategy templates: %w", err)
+	}
+	defer rows.Close()
+
+	var templates []*model.StrategyTemplate
+	for rows.Next() {
+		template, err := scanStrategyTemplate(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan strategy template: %w", err)
+		}
+		templates = append(templates, template)
+	}
+	return templates, rows.Err()
+}
+
+// Delete removes a strategy template
+func (r *StrategyTemplateRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM strategy_templates WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete strategy template: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// AddUsage adds n to a template's usage count
+func (r *StrategyTemplateRepository) AddUsage(ctx context.Context, id uuid.UUID, n int) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE strategy_templates
+		SET usage_count = usage_count + $2, updated_at = NOW()
+		WHERE id = $1`, id, n)
+	if err != nil {
+		return fmt.Errorf("failed to count strategy template usage: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func scanStrategyTemplate(row pgx.Row) (*model.StrategyTemplate, error) {
+	var t model.StrategyTemplate
+	var params, backtest []byte
+	err := row.Scan(&t.ID, &t.PublisherID, &t.Name, &t.Description, &t.Type, &params, &backtest, &t.UsageCount, &t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(params, &t.Params); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal params: %w", err)
+	}
+	if backtest != nil {
+		t.Backtest = &model.TemplateBacktest{}
+		if err := json.Unmarshal(backtest, t.Backtest); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal backtest: %w", err)
+		}
+	}
+	return &t, nil
+}
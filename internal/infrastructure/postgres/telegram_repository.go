@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// TelegramLinkRepository is a PostgreSQL implementation of repository.TelegramLinkRepository
+type TelegramLinkRepository struct {
+	db DBTX
+}
+
+// NewTelegramLinkRepository creates a new Telegram link repository
+func NewTelegramLinkRepository(db DBTX) *TelegramLinkRepository {
+	return &TelegramLinkRepository{db: db}
+}
+
+var _ repository.TelegramLinkRepository = (*TelegramLinkRepository)(nil)
+
+// Save stores a link, replacing any existing link of the user or the chat
+func (r *TelegramLinkRepository) Save(ctx context.Context, link *model.TelegramLink) error {
+	_, err := r.db.Exec(ctx, `
+		WITH unlinked AS (
+			DELETE FROM telegram_links WHERE chat_id = $2 AND user_id <> $1
+		)
+		INSERT INTO telegram_links (user_id, chat_id, username, linked_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO UPDATE
+		SET chat_id = EXCLUDED.chat_id, username = EXCLUDED.username, linked_at = EXCLUDED.linked_at`,
+		link.UserID, link.ChatID, link.Username, link.LinkedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save telegram link: %w", err)
+	}
+	return nil
+}
+
+// GetByUserID returns the link of a user
+func (r *TelegramLinkRepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*model.TelegramLink, error) {
+	return r.get(ctx, `SELECT user_id, chat_id, username, linked_at FROM telegram_links WHERE user_id = $1`, userID)
+}
+
+// GetByChatID returns the link of a chat
+func (r *TelegramLinkRepository) GetByChatID(ctx context.Context, chatID int64) (*model.TelegramLink, error) {
+	return r.get(ctx, `SELECT user_id, chat_id, username, linked_at FROM telegram_links WHERE chat_id = $1`, chatID)
+}
+
+// DeleteByUserID removes the link of a user
+func (r *TelegramLinkRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM telegram_links WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete telegram link: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+func (r *TelegramLinkRepository) get(ctx context.Context, query string, arg any) (*model.TelegramLink, error) {
+	var link model.TelegramLink
+	err := r.db.QueryRow(ctx, query, arg).Scan(&link.UserID, &link.ChatID, &link.Username, &link.LinkedAt)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &link, nil
+}
@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// UnitOfWork is a pgx.Tx based implementation of repository.UnitOfWork
+type UnitOfWork struct {
+	pool *pgxpool.Pool
+}
+
+// NewUnitOfWork creates a new unit of work
+func NewUnitOfWork(pool *pgxpool.Pool) *UnitOfWork {
+	return &UnitOfWork{pool: pool}
+}
+
+var _ repository.UnitOfWork = (*UnitOfWork)(nil)
+
+// Do runs fn inside a transaction, committing on success and rolling back on error
+func (u *UnitOfWork) Do(ctx context.Context, fn func(tx repository.Tx) error) error {
+	return pgx.BeginFunc(ctx, u.pool, func(tx pgx.Tx) error {
+		return fn(&txRepositories{tx: tx})
+	})
+}
+
+// txRepositories binds repositories to a single pgx.Tx
+type txRepositories struct {
+	tx pgx.Tx
+}
+
+func (t *txRepositories) Orders() repository.OrderRepository {
+	return NewOrderRepository(t.tx)
+}
+
+func (t *txRepositories) Executions() repository.OrderExecutionRepository {
+	return NewOrderExecutionRepository(t.tx)
+}
+
+func (t *txRepositories) Positions() repository.PositionRepository {
+	return NewPositionRepository(t.tx)
+}
+
+func (t *txRepositories) PositionEvents() repository.PositionEventRepository {
+	return NewPositionEventRepository(t.tx)
+}
+
+func (t *txRepositories) Outbox() repository.OutboxRepository {
+	return NewOutboxRepository(t.tx)
+}
+
+func (t *txRepositories) Jobs() repository.JobQueueRepository {
+	return NewJobQueueRepository(t.tx)
+}
+
+func (t *txRepositories) WebhookDeliveries() repository.WebhookDeliveryRepository {
+	return NewWebhookDeliveryRepository(t.tx)
+}
+
+func (t *txRepositories) DrawdownGuards() repository.DrawdownGuardRepository {
+	return NewDrawdownGuardRepository(t.tx)
+}
+
+func (t *txRepositories) DrawdownGuardUpdates() repository.DrawdownGuardUpdateRepository {
+	return NewDrawdownGuardUpdateRepository(t.tx)
+}
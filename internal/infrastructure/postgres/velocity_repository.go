@@ -0,0 +1,61 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// VelocityLimitRepository is a PostgreSQL implementation of repository.VelocityLimitRepository
+type VelocityLimitRepository struct {
+	db DBTX
+}
+
+// NewVelocityLimitRepository creates a new velocity limit repository
+func NewVelocityLimitRepository(db DBTX) *VelocityLimitRepository {
+	return &VelocityLimitRepository{db: db}
+}
+
+var _ repository.VelocityLimitRepository = (*VelocityLimitRepository)(nil)
+
+// Get returns the override of a user
+func (r *VelocityLimitRepository) Get(ctx context.Context, userID uuid.UUID) (*model.VelocityLimits, error) {
+	var limits model.VelocityLimits
+	err := r.db.QueryRow(ctx, `SELECT user_id, per_minute, per_hour, reason, updated_at FROM order_velocity_limits WHERE user_id = $1`, userID).
+		Scan(&limits.UserID, &limits.PerMinute, &limits.PerHour, &limits.Reason, &limits.UpdatedAt)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &limits, nil
+}
+
+// Save creates or replaces an override
+func (r *VelocityLimitRepository) Save(ctx context.Context, limits *model.VelocityLimits) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO order_velocity_limits (user_id, per_minute, per_hour, reason, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE
+		SET per_minute = EXCLUDED.per_minute, per_hour = EXCLUDED.per_hour, reason = EXCLUDED.reason,
+			updated_at = EXCLUDED.updated_at`,
+		limits.UserID, limits.PerMinute, limits.PerHour, limits.Reason, limits.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save velocity limits: %w", err)
+	}
+	return nil
+}
+
+// Delete removes an override
+func (r *VelocityLimitRepository) Delete(ctx context.Context, userID uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM order_velocity_limits WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete velocity limits: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
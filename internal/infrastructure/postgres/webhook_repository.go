@@ -0,0 +1,223 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+const webhookColumns = `id, user_id, url, secret, events, max_attempts, initial_backoff, active, created_at, updated_at`
+
+// defaultWebhookDeliveryListLimit bounds listings that don't set a limit
+const defaultWebhookDeliveryListLimit = 50
+
+const webhookDeliveryColumns = `id, webhook_id, event_type, payload, status, attempts, last_status_code, last_error,
+	next_attempt_at, delivered_at, created_at, updated_at`
+
+// WebhookRepository is a PostgreSQL implementation of repository.WebhookRepository
+type WebhookRepository struct {
+	db DBTX
+}
+
+// NewWebhookRepository creates a new webhook repository
+func NewWebhookRepository(db DBTX) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+var _ repository.WebhookRepository = (*WebhookRepository)(nil)
+
+// Create inserts a new webhook
+func (r *WebhookRepository) Create(ctx context.Context, webhook *model.Webhook) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO webhooks (`+webhookColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		webhook.ID, webhook.UserID, webhook.URL, webhook.Secret, webhookEvents(webhook.Events), webhook.MaxAttempts,
+		webhook.InitialBackoff, webhook.Active, webhook.CreatedAt, webhook.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a webhook by ID
+func (r *WebhookRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Webhook, error) {
+	webhook, err := scanWebhook(r.db.QueryRow(ctx, `SELECT `+webhookColumns+` FROM webhooks WHERE id = $1`, id))
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return webhook, nil
+}
+
+// Update stores a webhook's settings and secret
+func (r *WebhookRepository) Update(ctx context.Context, webhook *model.Webhook) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE webhooks
+		SET url = $2, secret = $3, events = $4, max_attempts = $5, initial_backoff = $6, active = $7, updated_at = $8
+		WHERE id = $1`,
+		webhook.ID, webhook.URL, webhook.Secret, webhookEvents(webhook.Events), webhook.MaxAttempts,
+		webhook.InitialBackoff, webhook.Active, webhook.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes a webhook and its deliveries
+func (r *WebhookRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// ListByUser returns the user's webhooks, newest first
+func (r *WebhookRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.Webhook, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+webhookColumns+` FROM webhooks WHERE user_id = $1 ORDER BY created_at DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	var webhooks []*model.Webhook
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return webhooks, rows.Err()
+}
+
+// webhookEvents stores "every event" as an empty array rather than NULL
+func webhookEvents(events []string) []string {
+	if events == nil {
+		return []string{}
+	}
+	return events
+}
+
+func scanWebhook(row pgx.Row) (*model.Webhook, error) {
+	var w model.Webhook
+	err := row.Scan(
+		&w.ID, &w.UserID, &w.URL, &w.Secret, &w.Events, &w.MaxAttempts, &w.InitialBackoff, &w.Active,
+		&w.CreatedAt, &w.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// WebhookDeliveryRepository is a PostgreSQL implementation of repository.WebhookDeliveryRepository
+type WebhookDeliveryRepository struct {
+	db DBTX
+}
+
+// NewWebhookDeliveryRepository creates a new webhook delivery repository
+func NewWebhookDeliveryRepository(db DBTX) *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{db: db}
+}
+
+var _ repository.WebhookDeliveryRepository = (*WebhookDeliveryRepository)(nil)
+
+// Create inserts a new delivery
+func (r *WebhookDeliveryRepository) Create(ctx context.Context, d *model.WebhookDelivery) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO webhook_deliveries (`+webhookDeliveryColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		d.ID, d.WebhookID, d.EventType, d.Payload, d.Status, d.Attempts, d.LastStatusCode, d.LastError,
+		d.NextAttemptAt, d.DeliveredAt, d.CreatedAt, d.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a delivery by ID
+func (r *WebhookDeliveryRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.WebhookDelivery, error) {
+	d, err := scanWebhookDelivery(r.db.QueryRow(ctx, `SELECT `+webhookDeliveryColumns+` FROM webhook_deliveries WHERE id = $1`, id))
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return d, nil
+}
+
+// Update stores the outcome of a delivery attempt
+func (r *WebhookDeliveryRepository) Update(ctx context.Context, d *model.WebhookDelivery) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, last_status_code = $4, last_error = $5, next_attempt_at = $6,
+			delivered_at = $7, updated_at = $8
+		WHERE id = $1`,
+		d.ID, d.Status, d.Attempts, d.LastStatusCode, d.LastError, d.NextAttemptAt, d.DeliveredAt, d.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// ListByWebhook returns a webhook's deliveries, newest first
+func (r *WebhookDeliveryRepository) ListByWebhook(ctx context.Context, webhookID uuid.UUID, filter repository.WebhookDeliveryFilter) ([]*model.WebhookDelivery, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultWebhookDeliveryListLimit
+	}
+
+	return r.list(ctx, `
+		SELECT `+webhookDeliveryColumns+`
+		FROM webhook_deliveries
+		WHERE webhook_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC
+		LIMIT $3`,
+		webhookID, filter.Status, limit,
+	)
+}
+
+func (r *WebhookDeliveryRepository) list(ctx context.Context, query string, args ...any) ([]*model.WebhookDelivery, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []*model.WebhookDelivery
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+func scanWebhookDelivery(row pgx.Row) (*model.WebhookDelivery, error) {
+	var d model.WebhookDelivery
+	err := row.Scan(
+		&d.ID, &d.WebhookID, &d.EventType, &d.Payload, &d.Status, &d.Attempts, &d.LastStatusCode, &d.LastError,
+		&d.NextAttemptAt, &d.DeliveredAt, &d.CreatedAt, &d.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+const withdrawAddressColumns = `id, user_id, currency, net_type, address, secondary_address, label, status,
+	code_hash, code_expires_at, code_attempts, confirmed_at, usable_at, created_at, updated_at`
+
+// WithdrawAddressRepository is a PostgreSQL implementation of repository.WithdrawAddressRepository
+type WithdrawAddressRepository struct {
+	db DBTX
+}
+
+// NewWithdrawAddressRepository creates a new withdrawal address book repository
+func NewWithdrawAddressRepository(db DBTX) *WithdrawAddressRepository {
+	return &WithdrawAddressRepository{db: db}
+}
+
+var _ repository.WithdrawAddressRepository = (*WithdrawAddressRepository)(nil)
+
+// Create inserts a new address book entry
+func (r *WithdrawAddressRepository) Create(ctx context.Context, a *model.WithdrawAddress) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO withdraw_addresses (`+withdrawAddressColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`,
+		a.ID, a.UserID, a.Currency, a.NetType, a.Address, a.SecondaryAddress, a.Label, a.Status,
+		a.CodeHash, a.CodeExpiresAt, a.CodeAttempts, a.ConfirmedAt, a.UsableAt, a.CreatedAt, a.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create withdraw address: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves an address book entry
+func (r *WithdrawAddressRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.WithdrawAddress, error) {
+	row := r.db.QueryRow(ctx, `SELECT `+withdrawAddressColumns+` FROM withdraw_addresses WHERE id = $1`, id)
+	address, err := scanWithdrawAddress(row)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return address, nil
+}
+
+// Update replaces an address book entry
+func (r *WithdrawAddressRepository) Update(ctx context.Context, a *model.WithdrawAddress) error {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE withdraw_addresses
+		SET label = $2, status = $3, code_hash = $4, code_expires_at = $5, code_attempts = $6,
+			confirmed_at = $7, usable_at = $8, updated_at = $9
+		WHERE id = $1`,
+		a.ID, a.Label, a.Status, a.CodeHash, a.CodeExpiresAt, a.CodeAttempts, a.ConfirmedAt, a.UsableAt, a.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update withdraw address: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// Delete removes an address book entry
+func (r *WithdrawAddressRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM withdraw_addresses WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete withdraw address: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+// ListByUser returns the user's addresses, oldest first
+func (r *WithdrawAddressRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*model.WithdrawAddress, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+withdrawAddressColumns+` FROM withdraw_addresses
+		WHERE user_id = $1
+		ORDER BY created_at`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list withdraw addresses: %w", err)
+	}
+	defer rows.Close()
+
+	var addresses []*model.WithdrawAddress
+	for rows.Next() {
+		address, err := scanWithdrawAddress(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan withdraw address: %w", err)
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses, rows.Err()
+}
+
+func scanWithdrawAddress(row pgx.Row) (*model.WithdrawAddress, error) {
+	var a model.WithdrawAddress
+	err := row.Scan(&a.ID, &a.UserID, &a.Currency, &a.NetType, &a.Address, &a.SecondaryAddress, &a.Label, &a.Status,
+		&a.CodeHash, &a.CodeExpiresAt, &a.CodeAttempts, &a.ConfirmedAt, &a.UsableAt, &a.CreatedAt, &a.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
@@ -0,0 +1,26 @@
+package addressbook
+
+var (
+	ErrInvalidCurrency  = &AddressError{message: "currency must be a coin symbol, e.g. BTC"}
+	ErrInvalidNetType   = &AddressError{message: "net_type must be a network symbol, e.g. BTC or TRX"}
+	ErrInvalidAddress   = &AddressError{message: "address is required and must be at most 256 characters without spaces"}
+	ErrInvalidLabel     = &AddressError{message: "label must be at most 50 characters"}
+	ErrDuplicateAddress = &AddressError{message: "the address is already in your address book"}
+	ErrTooManyAddresses = &AddressError{message: "an address book can have at most 50 addresses"}
+	ErrNoSecondFactor   = &AddressError{message: "link a Telegram chat to receive confirmation codes before adding withdrawal addresses"}
+	ErrNotPending       = &AddressError{message: "the address is already confirmed"}
+	ErrInvalidCode      = &AddressError{message: "confirmation code is wrong or expired"}
+	ErrTooManyAttempts  = &AddressError{message: "too many wrong codes; request a new code"}
+	ErrNotWhitelisted   = &AddressError{message: "the address is not a confirmed address in your address book"}
+	ErrAddressOnHold    = &AddressError{message: "the address was confirmed recently and can't be withdrawn to yet"}
+)
+
+// AddressError represents an invalid address book entry or a withdrawal the
+// whitelist refused
+type AddressError struct {
+	message string
+}
+
+func (e *AddressError) Error() string {
+	return e.message
+}
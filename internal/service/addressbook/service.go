@@ -0,0 +1,341 @@
+// Package addressbook keeps users' withdrawal address books. Addresses are
+// confirmed with a code sent to the user's linked Telegram chat and then
+// held before they are whitelisted, so a stolen session can't withdraw to an
+// address of its own.
+package addressbook
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+const (
+	// CodeTTL is how long a confirmation code stays valid
+	CodeTTL = 15 * time.Minute
+	// DefaultHold is how long a confirmed address waits to be whitelisted
+	DefaultHold = 24 * time.Hour
+	// MaxAddresses caps the entries of an address book
+	MaxAddresses = 50
+	// maxCodeAttempts is how many wrong codes are accepted per code
+	maxCodeAttempts = 5
+	codeDigits      = 6
+	// CodeChannel is the notification channel codes are sent over
+	CodeChannel = "telegram"
+)
+
+var symbolPattern = regexp.MustCompile(`^[A-Z0-9]{1,20}$`)
+
+// CodeSender sends notifications over a single channel;
+// notification.Service satisfies it
+type CodeSender interface {
+	NotifyVia(ctx context.Context, name string, notification *model.Notification) error
+}
+
+// Notifier announces newly whitelisted addresses; notification.Service
+// satisfies it
+type Notifier interface {
+	Notify(ctx context.Context, notification *model.Notification) error
+}
+
+// Service manages withdrawal address books and enforces the whitelist.
+// Withdrawals through the platform must pass Authorize first.
+type Service struct {
+	addresses repository.WithdrawAddressRepository
+	links     repository.TelegramLinkRepository
+	codes     CodeSender
+	notifier  Notifier // Optional
+	hold      time.Duration
+}
+
+// NewService creates a new address book service
+func NewService(addresses repository.WithdrawAddressRepository, links repository.TelegramLinkRepository, codes CodeSender) *Service {
+	return &Service{
+		addresses: addresses,
+		links:     links,
+		codes:     codes,
+		hold:      DefaultHold,
+	}
+}
+
+// WithNotifier notifies users on every channel when an address of theirs
+// is confirmed
+func (s *Service) WithNotifier(notifier Notifier) *Service {
+	s.notifier = notifier
+	return s
+}
+
+// WithHold sets how long confirmed addresses wait to be whitelisted
+func (s *Service) WithHold(hold time.Duration) *Service {
+	s.hold = hold
+	return s
+}
+
+// Add adds a pending address to the user's address book and sends its
+// confirmation code to the user's Telegram chat
+func (s *Service) Add(ctx context.Context, address *model.WithdrawAddress) (*model.WithdrawAddress, error) {
+	if err := normalize(address); err != nil {
+		return nil, err
+	}
+	if err := s.checkSecondFactor(ctx, address.UserID); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.addresses.ListByUser(ctx, address.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= MaxAddresses {
+		return nil, ErrTooManyAddresses
+	}
+	for _, a := range existing {
+		if a.Matches(address.Currency, address.NetType, address.Address, address.SecondaryAddress) {
+			return nil, ErrDuplicateAddress
+		}
+	}
+
+	now := time.Now()
+	address.ID = uuid.New()
+	address.Status = model.WithdrawAddressPending
+	address.ConfirmedAt, address.UsableAt = nil, nil
+	address.CreatedAt, address.UpdatedAt = now, now
+	code, err := newCode(address, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.addresses.Create(ctx, address); err != nil {
+		return nil, err
+	}
+	if err := s.sendCode(ctx, address, code); err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+// ResendCode replaces a pending address's confirmation code and sends the
+// new one
+func (s *Service) ResendCode(ctx context.Context, userID, id uuid.UUID) error {
+	address, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if address.Status != model.WithdrawAddressPending {
+		return ErrNotPending
+	}
+	if err := s.checkSecondFactor(ctx, userID); err != nil {
+		return err
+	}
+
+	code, err := newCode(address, time.Now())
+	if err != nil {
+		return err
+	}
+	if err := s.addresses.Update(ctx, address); err != nil {
+		return err
+	}
+	return s.sendCode(ctx, address, code)
+}
+
+// Confirm confirms a pending address with the code sent for it. The address
+// is whitelisted once the hold has passed.
+func (s *Service) Confirm(ctx context.Context, userID, id uuid.UUID, code string) (*model.WithdrawAddress, error) {
+	address, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if address.Status != model.WithdrawAddressPending {
+		return nil, ErrNotPending
+	}
+	if address.CodeAttempts >= maxCodeAttempts {
+		return nil, ErrTooManyAttempts
+	}
+
+	now := time.Now()
+	if address.CodeExpiresAt == nil || now.After(*address.CodeExpiresAt) ||
+		subtle.ConstantTimeCompare([]byte(hashCode(strings.TrimSpace(code))), []byte(address.CodeHash)) != 1 {
+		address.CodeAttempts++
+		address.UpdatedAt = now
+		if err := s.addresses.Update(ctx, address); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidCode
+	}
+
+	usableAt := now.Add(s.hold)
+	address.Status = model.WithdrawAddressConfirmed
+	address.ConfirmedAt, address.UsableAt = &now, &usableAt
+	address.CodeHash, address.CodeExpiresAt, address.CodeAttempts = "", nil, 0
+	address.UpdatedAt = now
+	if err := s.addresses.Update(ctx, address); err != nil {
+		return nil, err
+	}
+
+	s.notifyConfirmed(ctx, address)
+	return address, nil
+}
+
+// Get returns one of the user's addresses
+func (s *Service) Get(ctx context.Context, userID, id uuid.UUID) (*model.WithdrawAddress, error) {
+	address, err := s.addresses.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if address.UserID != userID {
+		return nil, repository.ErrNotFound
+	}
+	return address, nil
+}
+
+// List returns the user's address book
+func (s *Service) List(ctx context.Context, userID uuid.UUID) ([]*model.WithdrawAddress, error) {
+	return s.addresses.ListByUser(ctx, userID)
+}
+
+// Delete removes an address from the user's address book. Adding it again
+// needs a new confirmation and hold.
+func (s *Service) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	if _, err := s.Get(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.addresses.Delete(ctx, id)
+}
+
+// Authorize returns the address book entry of a withdrawal's destination
+// if the whitelist allows withdrawing to it. Every withdrawal placed through
+// the platform must pass it.
+func (s *Service) Authorize(ctx context.Context, userID uuid.UUID, currency, netType, address, secondaryAddress string) (*model.WithdrawAddress, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	netType = strings.ToUpper(strings.TrimSpace(netType))
+	if netType == "" {
+		netType = currency
+	}
+
+	addresses, err := s.addresses.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range addresses {
+		if !a.Matches(currency, netType, strings.TrimSpace(address), strings.TrimSpace(secondaryAddress)) {
+			continue
+		}
+		if a.Status != model.WithdrawAddressConfirmed {
+			return nil, ErrNotWhitelisted
+		}
+		if !a.Usable(time.Now()) {
+			return nil, ErrAddressOnHold
+		}
+		return a, nil
+	}
+	return nil, ErrNotWhitelisted
+}
+
+// checkSecondFactor requires the user to have a chat codes can reach
+func (s *Service) checkSecondFactor(ctx context.Context, userID uuid.UUID) error {
+	_, err := s.links.GetByUserID(ctx, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrNoSecondFactor
+	}
+	return err
+}
+
+func (s *Service) sendCode(ctx context.Context, address *model.WithdrawAddress, code string) error {
+	n := model.NewNotification(address.UserID, model.NotificationWithdrawAddress, "Confirm withdrawal address",
+		fmt.Sprintf("Your code to confirm %s address %s%s is %s. It expires in %d minutes. If you didn't add this address, change your password now.",
+			address.Currency, address.Address, labelSuffix(address), code, int(CodeTTL.Minutes())),
+		map[string]any{"address_id": address.ID}, address.UpdatedAt)
+	if err := s.codes.NotifyVia(ctx, CodeChannel, n); err != nil {
+		return fmt.Errorf("failed to send confirmation code: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) notifyConfirmed(ctx context.Context, address *model.WithdrawAddress) {
+	if s.notifier == nil {
+		return
+	}
+	n := model.NewNotification(address.UserID, model.NotificationWithdrawAddress, "Withdrawal address confirmed",
+		fmt.Sprintf("%s address %s%s was confirmed and can be withdrawn to from %s. If you didn't add it, delete it and change your password now.",
+			address.Currency, address.Address, labelSuffix(address), address.UsableAt.Format(time.RFC3339)),
+		map[string]any{
+			"address_id": address.ID,
+			"currency":   address.Currency,
+			"usable_at":  address.UsableAt,
+		}, address.UpdatedAt)
+	if err := s.notifier.Notify(ctx, n); err != nil {
+		log.Printf("Error notifying user %s of confirmed withdraw address %s: %v", address.UserID, address.ID, err)
+	}
+}
+
+// normalize validates an address and puts it in canonical form
+func normalize(address *model.WithdrawAddress) error {
+	address.Currency = strings.ToUpper(strings.TrimSpace(address.Currency))
+	address.NetType = strings.ToUpper(strings.TrimSpace(address.NetType))
+	address.Address = strings.TrimSpace(address.Address)
+	address.SecondaryAddress = strings.TrimSpace(address.SecondaryAddress)
+	address.Label = strings.TrimSpace(address.Label)
+	if address.NetType == "" {
+		address.NetType = address.Currency
+	}
+
+	switch {
+	case !symbolPattern.MatchString(address.Currency) || address.Currency == "KRW":
+		return ErrInvalidCurrency
+	case !symbolPattern.MatchString(address.NetType):
+		return ErrInvalidNetType
+	case !validAddress(address.Address, true) || !validAddress(address.SecondaryAddress, false):
+		return ErrInvalidAddress
+	case len([]rune(address.Label)) > 50:
+		return ErrInvalidLabel
+	}
+	return nil
+}
+
+func validAddress(address string, required bool) bool {
+	if address == "" {
+		return !required
+	}
+	return len(address) <= 256 && !strings.ContainsFunc(address, func(r rune) bool {
+		return r <= ' ' || r == 0x7f
+	})
+}
+
+// newCode gives an address a new confirmation code, returning the code
+func newCode(address *model.WithdrawAddress, now time.Time) (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", fmt.Errorf("failed to generate confirmation code: %w", err)
+	}
+	code := fmt.Sprintf("%0*d", codeDigits, n.Int64())
+
+	expiresAt := now.Add(CodeTTL)
+	address.CodeHash = hashCode(code)
+	address.CodeExpiresAt = &expiresAt
+	address.CodeAttempts = 0
+	address.UpdatedAt = now
+	return code, nil
+}
+
+func hashCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+func labelSuffix(address *model.WithdrawAddress) string {
+	if address.Label == "" {
+		return ""
+	}
+	return " (" + address.Label + ")"
+}
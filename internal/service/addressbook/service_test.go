@@ -0,0 +1,168 @@
+package addressbook
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+)
+
+// recordingSender keeps the notifications sent through it
+type recordingSender struct {
+	sent []*model.Notification
+}
+
+func (r *recordingSender) NotifyVia(ctx context.Context, name string, n *model.Notification) error {
+	if name != CodeChannel {
+		panic("codes must go to " + CodeChannel)
+	}
+	r.sent = append(r.sent, n)
+	return nil
+}
+
+func (r *recordingSender) Notify(ctx context.Context, n *model.Notification) error {
+	r.sent = append(r.sent, n)
+	return nil
+}
+
+var codePattern = regexp.MustCompile(`code to confirm .* is (\d{6})\.`)
+
+// lastCode returns the code of the latest confirmation message
+func (r *recordingSender) lastCode(t *testing.T) string {
+	t.Helper()
+	require.NotEmpty(t, r.sent)
+	match := codePattern.FindStringSubmatch(r.sent[len(r.sent)-1].Message)
+	require.Len(t, match, 2)
+	return match[1]
+}
+
+func newTestService(t *testing.T) (*Service, *recordingSender, uuid.UUID) {
+	store := memory.NewStore()
+	sender := &recordingSender{}
+	userID := uuid.New()
+	require.NoError(t, store.TelegramLinks().Save(context.Background(), &model.TelegramLink{UserID: userID, ChatID: 42, LinkedAt: time.Now()}))
+	service := NewService(store.WithdrawAddresses(), store.TelegramLinks(), sender).WithNotifier(sender)
+	return service, sender, userID
+}
+
+func TestService_AddValidates(t *testing.T) {
+	ctx := context.Background()
+	service, _, userID := newTestService(t)
+
+	tests := []struct {
+		address model.WithdrawAddress
+		err     error
+	}{
+		{model.WithdrawAddress{Currency: "krw", Address: "x"}, ErrInvalidCurrency},
+		{model.WithdrawAddress{Currency: "BTC-X", Address: "x"}, ErrInvalidCurrency},
+		{model.WithdrawAddress{Currency: "BTC", NetType: "b t", Address: "x"}, ErrInvalidNetType},
+		{model.WithdrawAddress{Currency: "BTC"}, ErrInvalidAddress},
+		{model.WithdrawAddress{Currency: "BTC", Address: "bc1q wrong"}, ErrInvalidAddress},
+	}
+	for _, tt := range tests {
+		address := tt.address
+		address.UserID = userID
+		_, err := service.Add(ctx, &address)
+		assert.ErrorIs(t, err, tt.err)
+	}
+
+	// Users without a linked chat can't receive codes
+	_, err := service.Add(ctx, &model.WithdrawAddress{UserID: uuid.New(), Currency: "BTC", Address: "bc1qxyz"})
+	assert.ErrorIs(t, err, ErrNoSecondFactor)
+
+	address, err := service.Add(ctx, &model.WithdrawAddress{UserID: userID, Currency: " xrp ", Address: "rXYZ", SecondaryAddress: "1234"})
+	require.NoError(t, err)
+	assert.Equal(t, "XRP", address.Currency)
+	assert.Equal(t, "XRP", address.NetType)
+	assert.Equal(t, model.WithdrawAddressPending, address.Status)
+
+	_, err = service.Add(ctx, &model.WithdrawAddress{UserID: userID, Currency: "XRP", Address: "rXYZ", SecondaryAddress: "1234"})
+	assert.ErrorIs(t, err, ErrDuplicateAddress)
+	_, err = service.Add(ctx, &model.WithdrawAddress{UserID: userID, Currency: "XRP", Address: "rXYZ", SecondaryAddress: "5678"})
+	assert.NoError(t, err)
+}
+
+func TestService_ConfirmAndAuthorize(t *testing.T) {
+	ctx := context.Background()
+	service, sender, userID := newTestService(t)
+	service.WithHold(time.Hour)
+
+	address, err := service.Add(ctx, &model.WithdrawAddress{UserID: userID, Currency: "BTC", Address: "bc1qxyz", Label: "cold wallet"})
+	require.NoError(t, err)
+	code := sender.lastCode(t)
+
+	// Pending addresses aren't whitelisted
+	_, err = service.Authorize(ctx, userID, "BTC", "", "bc1qxyz", "")
+	assert.ErrorIs(t, err, ErrNotWhitelisted)
+
+	// Other users can't confirm it, and wrong codes count
+	_, err = service.Confirm(ctx, uuid.New(), address.ID, code)
+	assert.Error(t, err)
+	_, err = service.Confirm(ctx, userID, address.ID, "000000x")
+	assert.ErrorIs(t, err, ErrInvalidCode)
+
+	confirmed, err := service.Confirm(ctx, userID, address.ID, " "+code+" ")
+	require.NoError(t, err)
+	assert.Equal(t, model.WithdrawAddressConfirmed, confirmed.Status)
+	require.NotNil(t, confirmed.UsableAt)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), *confirmed.UsableAt, time.Minute)
+	assert.Equal(t, "Withdrawal address confirmed", sender.sent[len(sender.sent)-1].Title)
+
+	_, err = service.Confirm(ctx, userID, address.ID, code)
+	assert.ErrorIs(t, err, ErrNotPending)
+
+	// Confirmed addresses are held before they are whitelisted
+	_, err = service.Authorize(ctx, userID, "btc", "BTC", "bc1qxyz", "")
+	assert.ErrorIs(t, err, ErrAddressOnHold)
+
+	service.WithHold(0)
+	other, err := service.Add(ctx, &model.WithdrawAddress{UserID: userID, Currency: "ETH", Address: "0xabc"})
+	require.NoError(t, err)
+	_, err = service.Confirm(ctx, userID, other.ID, sender.lastCode(t))
+	require.NoError(t, err)
+
+	authorized, err := service.Authorize(ctx, userID, "eth", "", "0xabc", "")
+	require.NoError(t, err)
+	assert.Equal(t, other.ID, authorized.ID)
+	_, err = service.Authorize(ctx, uuid.New(), "ETH", "", "0xabc", "")
+	assert.ErrorIs(t, err, ErrNotWhitelisted)
+	_, err = service.Authorize(ctx, userID, "ETH", "ARB", "0xabc", "")
+	assert.ErrorIs(t, err, ErrNotWhitelisted)
+
+	// Deleted addresses are no longer whitelisted
+	require.NoError(t, service.Delete(ctx, userID, other.ID))
+	_, err = service.Authorize(ctx, userID, "ETH", "", "0xabc", "")
+	assert.ErrorIs(t, err, ErrNotWhitelisted)
+}
+
+func TestService_ConfirmLimitsAttempts(t *testing.T) {
+	ctx := context.Background()
+	service, sender, userID := newTestService(t)
+
+	address, err := service.Add(ctx, &model.WithdrawAddress{UserID: userID, Currency: "BTC", Address: "bc1qxyz"})
+	require.NoError(t, err)
+	code := sender.lastCode(t)
+
+	for range maxCodeAttempts {
+		_, err = service.Confirm(ctx, userID, address.ID, "wrong")
+		assert.ErrorIs(t, err, ErrInvalidCode)
+	}
+	_, err = service.Confirm(ctx, userID, address.ID, code)
+	assert.ErrorIs(t, err, ErrTooManyAttempts)
+
+	// A new code resets the attempts; the old code no longer works
+	require.NoError(t, service.ResendCode(ctx, userID, address.ID))
+	newCode := sender.lastCode(t)
+	if newCode != code {
+		_, err = service.Confirm(ctx, userID, address.ID, code)
+		assert.ErrorIs(t, err, ErrInvalidCode)
+	}
+	_, err = service.Confirm(ctx, userID, address.ID, newCode)
+	assert.NoError(t, err)
+}
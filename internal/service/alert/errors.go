@@ -0,0 +1,15 @@
+package alert
+
+var (
+	ErrInvalidAlert  = &AlertError{message: "alert needs a market, a positive price, condition above or below and mode once or recurring"}
+	ErrTooManyAlerts = &AlertError{message: "too many active alerts"}
+)
+
+// AlertError represents a price alert validation error
+type AlertError struct {
+	message string
+}
+
+func (e *AlertError) Error() string {
+	return e.message
+}
@@ -0,0 +1,269 @@
+// Package alert evaluates users' price alerts against the shared price feed
+package alert
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/notification"
+	"github.com/sungminna/upbit-trading-platform/internal/service/pricefeed"
+	"github.com/sungminna/upbit-trading-platform/pkg/recovery"
+)
+
+// maxActiveAlertsPerUser bounds the alerts a single user can keep active
+const maxActiveAlertsPerUser = 100
+
+// deliveryTimeout bounds storing and notifying a triggered alert
+const deliveryTimeout = 10 * time.Second
+
+// deliveryQueueSize is how many triggered alerts can wait for delivery
+const deliveryQueueSize = 1024
+
+// delivery is a triggered alert and the price that triggered it
+type delivery struct {
+	alert *model.PriceAlert
+	price float64
+}
+
+// Service keeps active alerts in memory, evaluates them on every price update
+// and notifies their owners when the price crosses the alert level
+type Service struct {
+	alerts      repository.PriceAlertRepository
+	feed        *pricefeed.Feed
+	poller      *pricefeed.Poller // Optional; keeps alerted markets polled
+	notifier    notification.Notifier
+	active      map[string]map[uuid.UUID]*model.PriceAlert // By market
+	untrack     map[uuid.UUID]func()
+	lastPrice   map[string]float64
+	unsubscribe func()
+	mu          sync.Mutex
+	isRunning   bool
+	deliveries  chan delivery // Delivered in order by a single worker
+	done        chan struct{}
+}
+
+// NewService creates a new alert service. poller may be nil when another
+// component keeps the feed updated.
+func NewService(alerts repository.PriceAlertRepository, feed *pricefeed.Feed, poller *pricefeed.Poller, notifier notification.Notifier) *Service {
+	return &Service{
+		alerts:    alerts,
+		feed:      feed,
+		poller:    poller,
+		notifier:  notifier,
+		active:    make(map[string]map[uuid.UUID]*model.PriceAlert),
+		untrack:   make(map[uuid.UUID]func()),
+		lastPrice: make(map[string]float64),
+	}
+}
+
+// Start loads active alerts and starts evaluating them
+func (s *Service) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.isRunning {
+		return nil
+	}
+
+	alerts, err := s.alerts.ListActive(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load active alerts: %w", err)
+	}
+	for _, alert := range alerts {
+		s.add(alert)
+	}
+
+	s.deliveries = make(chan delivery, deliveryQueueSize)
+	s.done = make(chan struct{})
+	go s.deliverAll(s.deliveries, s.done)
+
+	s.unsubscribe = s.feed.Subscribe(pricefeed.AllMarkets, s.onPrice)
+	s.isRunning = true
+	return nil
+}
+
+// Stop stops evaluating alerts and waits for queued notifications
+func (s *Service) Stop() {
+	s.mu.Lock()
+	if !s.isRunning {
+		s.mu.Unlock()
+		return
+	}
+	s.unsubscribe()
+	for id, untrack := range s.untrack {
+		untrack()
+		delete(s.untrack, id)
+	}
+	s.active = make(map[string]map[uuid.UUID]*model.PriceAlert)
+	s.isRunning = false
+	close(s.deliveries)
+	done := s.done
+	s.mu.Unlock()
+
+	<-done
+}
+
+// Create validates and stores a new alert and starts evaluating it
+func (s *Service) Create(ctx context.Context, alert *model.PriceAlert) error {
+	if alert.Market == "" || alert.Price <= 0 {
+		return ErrInvalidAlert
+	}
+	if alert.Condition != model.AlertConditionAbove && alert.Condition != model.AlertConditionBelow {
+		return ErrInvalidAlert
+	}
+	if alert.Mode != model.AlertModeOnce && alert.Mode != model.AlertModeRecurring {
+		return ErrInvalidAlert
+	}
+
+	existing, err := s.alerts.ListByUser(ctx, alert.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to list alerts: %w", err)
+	}
+	activeCount := 0
+	for _, a := range existing {
+		if a.Active {
+			activeCount++
+		}
+	}
+	if activeCount >= maxActiveAlertsPerUser {
+		return ErrTooManyAlerts
+	}
+
+	if err := s.alerts.Create(ctx, alert); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.isRunning {
+		s.add(alert)
+	}
+	return nil
+}
+
+// List returns a user's alerts, newest first
+func (s *Service) List(ctx context.Context, userID uuid.UUID) ([]*model.PriceAlert, error) {
+	return s.alerts.ListByUser(ctx, userID)
+}
+
+// Delete removes one of the user's alerts. Alerts of other users are reported
+// as not found.
+func (s *Service) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	alert, err := s.alerts.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if alert.UserID != userID {
+		return repository.ErrNotFound
+	}
+
+	if err := s.alerts.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.remove(alert)
+	return nil
+}
+
+// add starts evaluating an alert; s.mu must be held
+func (s *Service) add(alert *model.PriceAlert) {
+	a := *alert
+	if s.active[a.Market] == nil {
+		s.active[a.Market] = make(map[uuid.UUID]*model.PriceAlert)
+	}
+	s.active[a.Market][a.ID] = &a
+
+	if s.poller != nil {
+		s.untrack[a.ID] = s.poller.Track(a.Market)
+	}
+}
+
+// remove stops evaluating an alert; s.mu must be held
+func (s *Service) remove(alert *model.PriceAlert) {
+	delete(s.active[alert.Market], alert.ID)
+	if len(s.active[alert.Market]) == 0 {
+		delete(s.active, alert.Market)
+	}
+
+	if untrack, ok := s.untrack[alert.ID]; ok {
+		untrack()
+		delete(s.untrack, alert.ID)
+	}
+}
+
+// onPrice triggers the market's alerts whose level the price crossed since
+// the previous update. Delivery happens off the feed's goroutine.
+func (s *Service) onPrice(update pricefeed.PriceUpdate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, seen := s.lastPrice[update.Market]
+	s.lastPrice[update.Market] = update.Price
+	if !seen || !s.isRunning {
+		return
+	}
+
+	for _, alert := range s.active[update.Market] {
+		if !alert.Crossed(previous, update.Price) {
+			continue
+		}
+
+		alert.Trigger(time.Now())
+		if !alert.Active {
+			s.remove(alert)
+		}
+
+		a := *alert
+		select {
+		case s.deliveries <- delivery{alert: &a, price: update.Price}:
+		default:
+			log.Printf("Dropping price alert %s: delivery queue is full", a.ID)
+		}
+	}
+}
+
+// deliverAll delivers queued alerts in trigger order until deliveries is closed
+func (s *Service) deliverAll(deliveries <-chan delivery, done chan<- struct{}) {
+	defer close(done)
+	for d := range deliveries {
+		recovery.Do("alert.deliver", func() { s.deliver(d.alert, d.price) })
+	}
+}
+
+// deliver stores the alert's trigger state and notifies its owner
+func (s *Service) deliver(alert *model.PriceAlert, price float64) {
+	ctx, cancel := context.WithTimeout(context.Background(), deliveryTimeout)
+	defer cancel()
+
+	if err := s.alerts.Update(ctx, alert); err != nil {
+		log.Printf("Error updating price alert %s: %v", alert.ID, err)
+	}
+
+	notification := model.NewNotification(
+		alert.UserID,
+		model.NotificationPriceAlert,
+		fmt.Sprintf("%s crossed %s %g", alert.Market, alert.Condition, alert.Price),
+		fmt.Sprintf("%s is now %g", alert.Market, price),
+		map[string]any{
+			"alert_id":  alert.ID,
+			"market":    alert.Market,
+			"condition": alert.Condition,
+			"level":     alert.Price,
+			"price":     price,
+		},
+		*alert.LastTriggeredAt,
+	)
+	// A recurring alert on a flapping price is throttled per alert
+	notification.DedupKey = alert.ID.String()
+	if err := s.notifier.Notify(ctx, notification); err != nil {
+		log.Printf("Error delivering price alert %s: %v", alert.ID, err)
+	}
+}
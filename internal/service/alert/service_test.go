@@ -0,0 +1,101 @@
+package alert
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+	"github.com/sungminna/upbit-trading-platform/internal/service/pricefeed"
+)
+
+type recordingNotifier struct {
+	mu   sync.Mutex
+	sent []*model.Notification
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, notification *model.Notification) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sent = append(n.sent, notification)
+	return nil
+}
+
+func newTestService(t *testing.T) (*Service, *pricefeed.Feed, *recordingNotifier, *memory.PriceAlertRepository) {
+	t.Helper()
+	repo := memory.NewStore().Alerts()
+	feed := pricefeed.NewFeed()
+	notifier := &recordingNotifier{}
+	service := NewService(repo, feed, nil, notifier)
+	require.NoError(t, service.Start(context.Background()))
+	t.Cleanup(service.Stop)
+	return service, feed, notifier, repo
+}
+
+// publish publishes prices and stops the service, which waits for deliveries
+func publish(service *Service, feed *pricefeed.Feed, prices ...float64) {
+	for _, price := range prices {
+		feed.Publish(pricefeed.PriceUpdate{Market: "KRW-BTC", Price: price})
+	}
+	service.Stop()
+}
+
+func TestService_OneShotAlert(t *testing.T) {
+	service, feed, notifier, repo := newTestService(t)
+	ctx := context.Background()
+
+	alert := model.NewPriceAlert(uuid.New(), "KRW-BTC", model.AlertConditionAbove, 100, model.AlertModeOnce, time.Now())
+	require.NoError(t, service.Create(ctx, alert))
+
+	// Starting above the level doesn't count as crossing it
+	publish(service, feed, 105, 95, 101, 90, 110)
+
+	require.Len(t, notifier.sent, 1)
+	assert.Equal(t, alert.UserID, notifier.sent[0].UserID)
+	assert.Equal(t, 101.0, notifier.sent[0].Data["price"])
+
+	stored, err := repo.GetByID(ctx, alert.ID)
+	require.NoError(t, err)
+	assert.False(t, stored.Active)
+	assert.Equal(t, 1, stored.TriggerCount)
+}
+
+func TestService_RecurringAlert(t *testing.T) {
+	service, feed, notifier, repo := newTestService(t)
+	ctx := context.Background()
+
+	alert := model.NewPriceAlert(uuid.New(), "KRW-BTC", model.AlertConditionBelow, 100, model.AlertModeRecurring, time.Now())
+	require.NoError(t, service.Create(ctx, alert))
+
+	publish(service, feed, 105, 100, 99, 101, 90)
+
+	assert.Len(t, notifier.sent, 2)
+	stored, err := repo.GetByID(ctx, alert.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.Active)
+	assert.Equal(t, 2, stored.TriggerCount)
+}
+
+func TestService_CreateAndDelete(t *testing.T) {
+	service, feed, notifier, _ := newTestService(t)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	err := service.Create(ctx, model.NewPriceAlert(userID, "KRW-BTC", "sideways", 100, model.AlertModeOnce, time.Now()))
+	assert.ErrorIs(t, err, ErrInvalidAlert)
+
+	alert := model.NewPriceAlert(userID, "KRW-BTC", model.AlertConditionAbove, 100, model.AlertModeOnce, time.Now())
+	require.NoError(t, service.Create(ctx, alert))
+
+	assert.ErrorIs(t, service.Delete(ctx, uuid.New(), alert.ID), repository.ErrNotFound, "other users can't delete it")
+	require.NoError(t, service.Delete(ctx, userID, alert.ID))
+
+	publish(service, feed, 90, 110)
+	assert.Empty(t, notifier.sent)
+}
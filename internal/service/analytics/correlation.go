@@ -0,0 +1,125 @@
+// Package analytics computes statistics across markets from their candles
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/pkg/perf"
+)
+
+const (
+	// DefaultWindow is how many returns correlations cover by default
+	DefaultWindow = 90
+	// maxWindow keeps each market to one candle request of at most 200
+	maxWindow  = 199
+	maxMarkets = 20
+)
+
+// CandleSource provides recent candles; gateway.QuotationAPI satisfies it
+type CandleSource interface {
+	GetCandles(ctx context.Context, market string, interval model.CandleInterval, count int) ([]model.Candle, error)
+}
+
+// Service computes market analytics
+type Service struct {
+	candles CandleSource
+}
+
+// NewService creates a new analytics service
+func NewService(candles CandleSource) *Service {
+	return &Service{candles: candles}
+}
+
+// CorrelationMatrix holds the pairwise correlations of markets' returns
+type CorrelationMatrix struct {
+	Markets  []string             `json:"markets"`
+	Interval model.CandleInterval `json:"interval"`
+	Window   int                  `json:"window"` // Returns the correlations were computed over
+	From     time.Time            `json:"from"`   // Start of the first candle used
+	To       time.Time            `json:"to"`     // Start of the last candle used
+	Matrix   [][]float64          `json:"matrix"` // Matrix[i][j] correlates Markets[i] with Markets[j]
+}
+
+// Correlation computes the correlations of the markets' close-to-close
+// returns over their latest window candles of an interval. Only candles every
+// market has are used, so a market that didn't trade in a period doesn't skew
+// the others; the window shrinks when fewer are shared. Zero uses
+// DefaultWindow.
+func (s *Service) Correlation(ctx context.Context, markets []string, interval model.CandleInterval, window int) (*CorrelationMatrix, error) {
+	if window == 0 {
+		window = DefaultWindow
+	}
+	if window < 2 || window > maxWindow {
+		return nil, ErrInvalidWindow
+	}
+	if interval.Duration() == 0 {
+		return nil, ErrInvalidInterval
+	}
+	if len(markets) < 2 || len(markets) > maxMarkets {
+		return nil, ErrInvalidMarkets
+	}
+	seen := make(map[string]bool, len(markets))
+	for _, market := range markets {
+		if market == "" || seen[market] {
+			return nil, ErrInvalidMarkets
+		}
+		seen[market] = true
+	}
+
+	// Closes by candle start, keeping only the starts every market has
+	closes := make([]map[time.Time]float64, len(markets))
+	shared := make(map[time.Time]int)
+	for i, market := range markets {
+		candles, err := s.candles.GetCandles(ctx, market, interval, window+1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s candles: %w", market, err)
+		}
+		closes[i] = make(map[time.Time]float64, len(candles))
+		for _, c := range candles {
+			closes[i][c.Timestamp] = c.ClosePrice
+			shared[c.Timestamp]++
+		}
+	}
+	var times []time.Time
+	for t, count := range shared {
+		if count == len(markets) {
+			times = append(times, t)
+		}
+	}
+	if len(times) < 3 {
+		return nil, ErrNotEnoughCandles
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+
+	returns := make([][]float64, len(markets))
+	for i := range markets {
+		curve := make([]perf.EquityPoint, len(times))
+		for j, t := range times {
+			curve[j] = perf.EquityPoint{Time: t, Equity: closes[i][t]}
+		}
+		returns[i] = perf.Returns(curve)
+	}
+
+	matrix := make([][]float64, len(markets))
+	for i := range markets {
+		matrix[i] = make([]float64, len(markets))
+		matrix[i][i] = 1
+		for j := 0; j < i; j++ {
+			matrix[i][j] = perf.Correlation(returns[i], returns[j])
+			matrix[j][i] = matrix[i][j]
+		}
+	}
+
+	return &CorrelationMatrix{
+		Markets:  markets,
+		Interval: interval,
+		Window:   len(times) - 1,
+		From:     times[0],
+		To:       times[len(times)-1],
+		Matrix:   matrix,
+	}, nil
+}
@@ -0,0 +1,59 @@
+package analytics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// stubCandles serves daily candles of fixed closes, newest first like Upbit
+type stubCandles struct {
+	closes map[string][]float64 // Oldest first
+	counts []int
+}
+
+func (s *stubCandles) GetCandles(ctx context.Context, market string, interval model.CandleInterval, count int) ([]model.Candle, error) {
+	s.counts = append(s.counts, count)
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	closes := s.closes[market]
+	var candles []model.Candle
+	for i := len(closes) - 1; i >= 0 && len(candles) < count; i-- {
+		candles = append(candles, model.Candle{Market: market, Timestamp: start.AddDate(0, 0, i), ClosePrice: closes[i]})
+	}
+	return candles, nil
+}
+
+func TestService_Correlation(t *testing.T) {
+	candles := &stubCandles{closes: map[string][]float64{
+		"KRW-BTC": {100, 110, 99, 108.9, 98.01},
+		"KRW-ETH": {50, 60, 48, 57.6, 46.08}, // Twice BTC's moves
+		"KRW-XRP": {10, 9, 9.9, 8.91, 9.801}, // Opposite to BTC's
+	}}
+	service := NewService(candles)
+
+	result, err := service.Correlation(context.Background(), []string{"KRW-BTC", "KRW-ETH", "KRW-XRP"}, model.CandleInterval1d, 3)
+	require.NoError(t, err)
+	assert.Equal(t, []int{4, 4, 4}, candles.counts)
+	assert.Equal(t, 3, result.Window)
+	assert.Equal(t, time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), result.From)
+	assert.InDelta(t, 1, result.Matrix[0][0], 1e-9)
+	assert.InDelta(t, 1, result.Matrix[0][1], 1e-9)
+	assert.InDelta(t, -1, result.Matrix[0][2], 1e-9)
+	assert.Equal(t, result.Matrix[0][2], result.Matrix[2][0])
+
+	// Only candles every market has count
+	candles.closes["KRW-SOL"] = []float64{20, 21}
+	_, err = service.Correlation(context.Background(), []string{"KRW-BTC", "KRW-SOL"}, model.CandleInterval1d, 0)
+	assert.ErrorIs(t, err, ErrNotEnoughCandles)
+
+	_, err = service.Correlation(context.Background(), []string{"KRW-BTC", "KRW-BTC"}, model.CandleInterval1d, 0)
+	assert.ErrorIs(t, err, ErrInvalidMarkets)
+	_, err = service.Correlation(context.Background(), []string{"KRW-BTC", "KRW-ETH"}, model.CandleInterval1d, 200)
+	assert.ErrorIs(t, err, ErrInvalidWindow)
+	_, err = service.Correlation(context.Background(), []string{"KRW-BTC", "KRW-ETH"}, "2d", 0)
+	assert.ErrorIs(t, err, ErrInvalidInterval)
+}
@@ -0,0 +1,17 @@
+package analytics
+
+var (
+	ErrInvalidMarkets   = &AnalyticsError{message: "markets must list 2 to 20 distinct markets"}
+	ErrInvalidInterval  = &AnalyticsError{message: "interval must be a candle interval"}
+	ErrInvalidWindow    = &AnalyticsError{message: "window must be between 2 and 199 candles"}
+	ErrNotEnoughCandles = &AnalyticsError{message: "markets have too few candles in common"}
+)
+
+// AnalyticsError represents an analytics request validation error
+type AnalyticsError struct {
+	message string
+}
+
+func (e *AnalyticsError) Error() string {
+	return e.message
+}
@@ -0,0 +1,14 @@
+package apikey
+
+var (
+	ErrKeyRejected = &APIKeyError{message: "Upbit rejected the API key; it may have expired or been revoked"}
+)
+
+// APIKeyError represents an API key that can't be inspected
+type APIKeyError struct {
+	message string
+}
+
+func (e *APIKeyError) Error() string {
+	return e.message
+}
@@ -0,0 +1,202 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/gateway"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/service/notification"
+	"github.com/sungminna/upbit-trading-platform/internal/service/scheduler"
+	"github.com/sungminna/upbit-trading-platform/internal/service/trading"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/exchange"
+	"github.com/sungminna/upbit-trading-platform/pkg/cache"
+)
+
+const (
+	// expiryCheckInterval and expiryJitter schedule the expiry check,
+	// spread across instances sharing the schedule
+	expiryCheckInterval = 12 * time.Hour
+	expiryJitter        = 10 * time.Minute
+	// claimKey prefixes the claims that make instances send each reminder
+	// once
+	claimKey = "apikey-expiry:"
+	// lapseClaimTTL is how often a user is told again that trading stays
+	// halted on their lapsed key, if they resume without replacing it
+	lapseClaimTTL = 24 * time.Hour
+)
+
+// ReminderDays are how many days before a key expires its user is reminded
+var ReminderDays = []int{30, 7, 1}
+
+// Halter halts a user's trading; trading.Engine satisfies it
+type Halter interface {
+	Halt(ctx context.Context, userID uuid.UUID, reason, haltedBy string, cancelOrders bool) (*trading.HaltResult, error)
+	ActiveHalt(ctx context.Context, userID uuid.UUID) (*model.TradingHalt, error)
+}
+
+// WithExpiryMonitor enables the expiry check: users are reminded before
+// their active Upbit key expires, and their trading is halted once it has,
+// so drawdown guards, recurring orders, signals and rebalancing stop placing
+// orders that would be rejected. Nothing is deleted; they carry on once the
+// user registers a new key and resumes trading. Reminders are claimed in
+// claims, so instances sharing a cache send each once.
+func (s *Service) WithExpiryMonitor(halter Halter, notifier notification.Notifier, claims cache.Cache) *Service {
+	s.halter = halter
+	s.notifier = notifier
+	s.claims = claims
+	return s
+}
+
+// Job returns the job checking API key expiry
+func (s *Service) Job() scheduler.Job {
+	return scheduler.Job{
+		Name:     "api-key-expiry",
+		Schedule: scheduler.Every(expiryCheckInterval),
+		Jitter:   expiryJitter,
+		Run: func(ctx context.Context, at time.Time) error {
+			return s.CheckExpiry(ctx, s.clock.Now())
+		},
+	}
+}
+
+// CheckExpiry refreshes the expiry of every active Upbit key from Upbit and
+// reminds or halts their users. A failure for one user doesn't stop the
+// others.
+func (s *Service) CheckExpiry(ctx context.Context, now time.Time) error {
+	userIDs, err := s.keys.ListActiveUserIDs(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, userID := range userIDs {
+		if err := s.checkUser(ctx, userID, now); err != nil {
+			errs = append(errs, fmt.Errorf("user %s: %w", userID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkUser checks the user's active key. Paper keys don't expire.
+func (s *Service) checkUser(ctx context.Context, userID uuid.UUID, now time.Time) error {
+	key, err := s.keys.GetActiveByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if key.Paper {
+		return nil
+	}
+
+	expiresAt, lapsed, err := s.refreshExpiry(ctx, key)
+	if err != nil {
+		return err
+	}
+	if expiresAt == nil && !lapsed {
+		return nil
+	}
+	if lapsed || !expiresAt.After(now) {
+		return s.lapse(ctx, key, expiresAt)
+	}
+
+	daysLeft := int(math.Ceil(expiresAt.Sub(now).Hours() / 24))
+	for i := len(ReminderDays) - 1; i >= 0; i-- {
+		if daysLeft <= ReminderDays[i] {
+			return s.remind(ctx, key, *expiresAt, ReminderDays[i], daysLeft)
+		}
+	}
+	return nil
+}
+
+// refreshExpiry looks up when the key expires on Upbit and stores it,
+// falling back to the stored expiry when Upbit can't tell. lapsed reports
+// that Upbit rejected the key as expired.
+func (s *Service) refreshExpiry(ctx context.Context, key *model.UserAPIKey) (expiresAt *time.Time, lapsed bool, err error) {
+	info, ok := s.newClient(key.AccessKey, key.SecretKey).(gateway.APIKeyInfoAPI)
+	if !ok {
+		return key.ExpiresAt, false, nil
+	}
+
+	found, err := expiry(ctx, info, key.AccessKey)
+	var apiErr *exchange.APIError
+	if errors.As(err, &apiErr) && apiErr.Name == "expired_access_key" {
+		return key.ExpiresAt, true, nil
+	}
+	if err != nil {
+		log.Printf("Error looking up the expiry of API key %s: %v", key.ID, err)
+		return key.ExpiresAt, false, nil
+	}
+	if found == nil {
+		return key.ExpiresAt, false, nil
+	}
+	if err := s.keys.SetExpiry(ctx, key.ID, *found); err != nil {
+		return nil, false, err
+	}
+	return found, false, nil
+}
+
+// remind tells the user their key expires in daysLeft days, once per
+// reminder
+func (s *Service) remind(ctx context.Context, key *model.UserAPIKey, expiresAt time.Time, reminder, daysLeft int) error {
+	claimed, err := s.claim(ctx, fmt.Sprintf("%s:%d", key.ID, reminder), time.Duration(reminder+1)*24*time.Hour)
+	if err != nil || !claimed {
+		return err
+	}
+
+	message := fmt.Sprintf("Your Upbit API key %s expires on %s, in %d days. Issue a new key on Upbit and register it before then, or trading will be halted.",
+		key.AccessKey, expiresAt.Format("2006-01-02"), daysLeft)
+	s.notify(ctx, key, "Upbit API key expiring", message, map[string]any{
+		"key_id": key.ID, "expires_at": expiresAt, "days_left": daysLeft,
+	})
+	return nil
+}
+
+// lapse halts the trading of a user whose key expired, unless it already is
+func (s *Service) lapse(ctx context.Context, key *model.UserAPIKey, expiresAt *time.Time) error {
+	active, err := s.halter.ActiveHalt(ctx, key.UserID)
+	if err != nil {
+		return err
+	}
+	if active != nil {
+		return nil
+	}
+	claimed, err := s.claim(ctx, key.ID.String()+":lapsed", lapseClaimTTL)
+	if err != nil || !claimed {
+		return err
+	}
+
+	log.Printf("API key %s of user %s expired, halting their trading", key.ID, key.UserID)
+	if _, err := s.halter.Halt(ctx, key.UserID, "Upbit API key expired", model.HaltedByKeyExpiry, false); err != nil {
+		return fmt.Errorf("failed to halt trading: %w", err)
+	}
+
+	data := map[string]any{"key_id": key.ID, "halted": true}
+	if expiresAt != nil {
+		data["expires_at"] = *expiresAt
+	}
+	s.notify(ctx, key, "Upbit API key expired",
+		fmt.Sprintf("Your Upbit API key %s has expired. Trading is halted, so drawdown guards, recurring orders, signals and rebalancing won't place orders until you register a new key and resume trading.", key.AccessKey),
+		data)
+	return nil
+}
+
+// claim reports whether this instance is the first to claim name
+func (s *Service) claim(ctx context.Context, name string, ttl time.Duration) (bool, error) {
+	return s.claims.SetNX(ctx, claimKey+name, []byte{1}, ttl)
+}
+
+func (s *Service) notify(ctx context.Context, key *model.UserAPIKey, title, message string, data map[string]any) {
+	if s.notifier == nil {
+		return
+	}
+	n := model.NewNotification(key.UserID, model.NotificationAPIKeyExpiry, title, message, data, s.clock.Now())
+	n.DedupKey = key.ID.String()
+	if err := s.notifier.Notify(ctx, n); err != nil {
+		log.Printf("Error notifying user %s of API key expiry: %v", key.UserID, err)
+	}
+}
@@ -0,0 +1,210 @@
+// Package apikey inspects users' Upbit API keys: what they may do and when
+// they expire
+package apikey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/gateway"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/notification"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/exchange"
+	"github.com/sungminna/upbit-trading-platform/pkg/cache"
+	"github.com/sungminna/upbit-trading-platform/pkg/clock"
+)
+
+// ExpiryWarning is how long before a key expires users are warned. Upbit
+// keys expire a year after they are issued.
+const ExpiryWarning = 30 * 24 * time.Hour
+
+// probeMarket is the market order permissions are probed on
+const probeMarket = "KRW-BTC"
+
+// Scopes of an Upbit API key
+const (
+	ScopeViewAccounts    = "view_accounts"
+	ScopeViewOrders      = "view_orders"
+	ScopeMakeOrders      = "make_orders"
+	ScopeViewDeposits    = "view_deposits"
+	ScopeViewWithdrawals = "view_withdrawals"
+)
+
+// ScopeStatus is whether a key has a scope
+type ScopeStatus string
+
+const (
+	ScopeGranted ScopeStatus = "granted"
+	ScopeDenied  ScopeStatus = "denied"
+	// ScopeUnknown is a scope that can't be checked without acting on the
+	// account, e.g. placing an order
+	ScopeUnknown ScopeStatus = "unknown"
+)
+
+// Permissions reports what an API key may do and when it expires
+type Permissions struct {
+	KeyID     uuid.UUID              `json:"key_id"`
+	AccessKey string                 `json:"access_key"`
+	Paper     bool                   `json:"paper"`
+	Scopes    map[string]ScopeStatus `json:"scopes"`
+	ExpiresAt *time.Time             `json:"expires_at,omitempty"` // Unknown for paper keys
+	DaysLeft  *int                   `json:"days_left,omitempty"`
+	Warning   string                 `json:"warning,omitempty"`
+}
+
+// Service inspects users' API keys on the exchange and, with
+// WithExpiryMonitor, watches them expire
+type Service struct {
+	keys      repository.UserAPIKeyRepository
+	newClient gateway.ExchangeClientFactory
+	halter    Halter                // Set by WithExpiryMonitor
+	notifier  notification.Notifier // Set by WithExpiryMonitor
+	claims    cache.Cache           // Set by WithExpiryMonitor
+	clock     clock.Clock
+}
+
+// NewService creates a new API key service
+func NewService(keys repository.UserAPIKeyRepository, newClient gateway.ExchangeClientFactory) *Service {
+	return &Service{
+		keys:      keys,
+		newClient: newClient,
+		clock:     clock.Real,
+	}
+}
+
+// WithClock sets the clock expiry is measured by
+func (s *Service) WithClock(c clock.Clock) *Service {
+	s.clock = c
+	return s
+}
+
+// Permissions probes which scopes one of the user's keys has with read-only
+// calls and looks up when it expires. Placing orders can't be probed safely,
+// so it is reported as granted only for paper keys.
+func (s *Service) Permissions(ctx context.Context, userID, keyID uuid.UUID) (*Permissions, error) {
+	keys, err := s.keys.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	var key *model.UserAPIKey
+	for _, k := range keys {
+		if k.ID == keyID {
+			key = k
+			break
+		}
+	}
+	if key == nil {
+		return nil, repository.ErrNotFound
+	}
+
+	client := s.newClient(key.AccessKey, key.SecretKey)
+	perms := &Permissions{
+		KeyID:     key.ID,
+		AccessKey: key.AccessKey,
+		Paper:     key.Paper,
+		Scopes:    map[string]ScopeStatus{ScopeMakeOrders: ScopeUnknown},
+	}
+	if key.Paper {
+		perms.Scopes[ScopeMakeOrders] = ScopeGranted
+	}
+
+	probes := map[string]func() error{
+		ScopeViewAccounts: func() error {
+			_, err := client.GetAccounts(ctx)
+			return err
+		},
+		ScopeViewOrders: func() error {
+			_, err := client.GetOrders(ctx, probeMarket, "wait")
+			return err
+		},
+	}
+	if transfers, ok := client.(gateway.TransferAPI); ok {
+		probes[ScopeViewDeposits] = func() error {
+			_, err := transfers.GetDeposits(ctx, "KRW")
+			return err
+		}
+		probes[ScopeViewWithdrawals] = func() error {
+			_, err := transfers.GetWithdraws(ctx, "KRW")
+			return err
+		}
+	}
+	for scope, probe := range probes {
+		status, err := scopeStatus(probe())
+		if err != nil {
+			return nil, fmt.Errorf("failed to check the %s scope: %w", scope, err)
+		}
+		perms.Scopes[scope] = status
+	}
+
+	if info, ok := client.(gateway.APIKeyInfoAPI); ok {
+		expiresAt, err := expiry(ctx, info, key.AccessKey)
+		if err != nil {
+			return nil, err
+		}
+		if expiresAt != nil {
+			if err := s.keys.SetExpiry(ctx, key.ID, *expiresAt); err != nil {
+				return nil, err
+			}
+			perms.setExpiry(*expiresAt, s.clock.Now())
+		}
+	}
+	return perms, nil
+}
+
+// scopeStatus tells a missing scope from a rejected key. Any other failure
+// is returned.
+func scopeStatus(err error) (ScopeStatus, error) {
+	if err == nil {
+		return ScopeGranted, nil
+	}
+	var apiErr *exchange.APIError
+	if errors.As(err, &apiErr) && apiErr.IsAuth() {
+		if apiErr.Name == "out_of_scope" {
+			return ScopeDenied, nil
+		}
+		return "", ErrKeyRejected
+	}
+	return "", err
+}
+
+// expiry returns when the key with accessKey expires; nil if the exchange
+// doesn't list it
+func expiry(ctx context.Context, info gateway.APIKeyInfoAPI, accessKey string) (*time.Time, error) {
+	keys, err := info.GetAPIKeys(ctx)
+	var apiErr *exchange.APIError
+	if errors.As(err, &apiErr) && apiErr.Name == "out_of_scope" {
+		return nil, nil // Listing keys needs a scope the key lacks
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up the key's expiry: %w", err)
+	}
+	for _, k := range keys {
+		if k.AccessKey == accessKey {
+			expiresAt := k.ExpireAt
+			return &expiresAt, nil
+		}
+	}
+	return nil, nil
+}
+
+// setExpiry records when the key expires and warns within ExpiryWarning of it
+func (p *Permissions) setExpiry(expiresAt, now time.Time) {
+	p.ExpiresAt = &expiresAt
+	daysLeft := int(math.Ceil(expiresAt.Sub(now).Hours() / 24))
+	if daysLeft < 0 {
+		daysLeft = 0
+	}
+	p.DaysLeft = &daysLeft
+
+	switch left := expiresAt.Sub(now); {
+	case left <= 0:
+		p.Warning = "this key has expired; issue a new key on Upbit and register it"
+	case left <= ExpiryWarning:
+		p.Warning = fmt.Sprintf("this key expires in %d days; issue a new key on Upbit and register it before then", daysLeft)
+	}
+}
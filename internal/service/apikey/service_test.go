@@ -0,0 +1,215 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/gateway"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+	"github.com/sungminna/upbit-trading-platform/internal/service/trading"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/exchange"
+	"github.com/sungminna/upbit-trading-platform/pkg/cache"
+)
+
+// stubUpbit answers like Upbit for a key with the given scopes
+type stubUpbit struct {
+	accessKey string
+	scopes    map[string]bool
+	expireAt  time.Time
+	rejected  bool
+}
+
+func (s *stubUpbit) call(scope string) error {
+	if s.rejected {
+		return &exchange.APIError{StatusCode: http.StatusUnauthorized, Name: "expired_access_key"}
+	}
+	if !s.scopes[scope] {
+		return &exchange.APIError{StatusCode: http.StatusUnauthorized, Name: "out_of_scope"}
+	}
+	return nil
+}
+
+func (s *stubUpbit) GetAccounts(ctx context.Context) ([]exchange.Account, error) {
+	return nil, s.call(ScopeViewAccounts)
+}
+
+func (s *stubUpbit) PlaceOrder(ctx context.Context, req exchange.OrderRequest) (*exchange.OrderResponse, error) {
+	return nil, errors.New("orders are never placed to check permissions")
+}
+
+func (s *stubUpbit) GetOrder(ctx context.Context, orderUUID string) (*exchange.OrderResponse, error) {
+	return nil, s.call(ScopeViewOrders)
+}
+
+func (s *stubUpbit) CancelOrder(ctx context.Context, orderUUID string) (*exchange.OrderResponse, error) {
+	return nil, errors.New("orders are never cancelled to check permissions")
+}
+
+func (s *stubUpbit) GetOrders(ctx context.Context, market string, state string) ([]exchange.OrderResponse, error) {
+	return nil, s.call(ScopeViewOrders)
+}
+
+func (s *stubUpbit) GetDeposits(ctx context.Context, currency string) ([]exchange.Transfer, error) {
+	return nil, s.call(ScopeViewDeposits)
+}
+
+func (s *stubUpbit) GetWithdraws(ctx context.Context, currency string) ([]exchange.Transfer, error) {
+	return nil, s.call(ScopeViewWithdrawals)
+}
+
+func (s *stubUpbit) GetAPIKeys(ctx context.Context) ([]exchange.APIKey, error) {
+	if s.rejected {
+		return nil, s.call("")
+	}
+	return []exchange.APIKey{
+		{AccessKey: "other-key", ExpireAt: time.Now().Add(time.Hour)},
+		{AccessKey: s.accessKey, ExpireAt: s.expireAt},
+	}, nil
+}
+
+type recordingNotifier struct {
+	sent []*model.Notification
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, notification *model.Notification) error {
+	n.sent = append(n.sent, notification)
+	return nil
+}
+
+type recordingHalter struct {
+	halts []*model.TradingHalt
+}
+
+func (h *recordingHalter) Halt(ctx context.Context, userID uuid.UUID, reason, haltedBy string, cancelOrders bool) (*trading.HaltResult, error) {
+	halt := &model.TradingHalt{UserID: userID, Reason: reason, HaltedBy: haltedBy, CreatedAt: time.Now()}
+	h.halts = append(h.halts, halt)
+	return &trading.HaltResult{Halt: halt}, nil
+}
+
+func (h *recordingHalter) ActiveHalt(ctx context.Context, userID uuid.UUID) (*model.TradingHalt, error) {
+	for _, halt := range h.halts {
+		if halt.UserID == userID {
+			return halt, nil
+		}
+	}
+	return nil, nil
+}
+
+func newTestService(t *testing.T, upbit *stubUpbit) (*Service, *model.UserAPIKey) {
+	store := memory.NewStore()
+	key := model.NewUserAPIKey(uuid.New(), upbit.accessKey, "secret", "main", time.Now())
+	require.NoError(t, store.APIKeys().Create(context.Background(), key))
+	return NewService(store.APIKeys(), func(accessKey, secretKey string) gateway.ExchangeAPI { return upbit }), key
+}
+
+func TestService_Permissions(t *testing.T) {
+	upbit := &stubUpbit{
+		accessKey: "upbit-key",
+		scopes:    map[string]bool{ScopeViewAccounts: true, ScopeViewOrders: true},
+		expireAt:  time.Now().Add(10*24*time.Hour - time.Minute),
+	}
+	service, key := newTestService(t, upbit)
+
+	perms, err := service.Permissions(context.Background(), key.UserID, key.ID)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]ScopeStatus{
+		ScopeViewAccounts:    ScopeGranted,
+		ScopeViewOrders:      ScopeGranted,
+		ScopeMakeOrders:      ScopeUnknown,
+		ScopeViewDeposits:    ScopeDenied,
+		ScopeViewWithdrawals: ScopeDenied,
+	}, perms.Scopes)
+	require.NotNil(t, perms.ExpiresAt)
+	assert.True(t, perms.ExpiresAt.Equal(upbit.expireAt))
+	require.NotNil(t, perms.DaysLeft)
+	assert.Equal(t, 10, *perms.DaysLeft)
+	assert.Contains(t, perms.Warning, "expires in 10 days")
+
+	upbit.expireAt = time.Now().Add(200 * 24 * time.Hour)
+	perms, err = service.Permissions(context.Background(), key.UserID, key.ID)
+	require.NoError(t, err)
+	assert.Empty(t, perms.Warning, "no warning long before expiry")
+}
+
+func TestService_PermissionsErrors(t *testing.T) {
+	upbit := &stubUpbit{accessKey: "upbit-key", rejected: true}
+	service, key := newTestService(t, upbit)
+
+	_, err := service.Permissions(context.Background(), key.UserID, key.ID)
+	assert.ErrorIs(t, err, ErrKeyRejected)
+
+	_, err = service.Permissions(context.Background(), uuid.New(), key.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound, "other users' keys are not found")
+}
+
+func TestPermissions_SetExpiry(t *testing.T) {
+	now := time.Now()
+	var perms Permissions
+	perms.setExpiry(now.Add(-time.Hour), now)
+	assert.Equal(t, 0, *perms.DaysLeft)
+	assert.Contains(t, perms.Warning, "has expired")
+}
+
+func TestService_CheckExpiryReminds(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	upbit := &stubUpbit{accessKey: "upbit-key", expireAt: now.Add(20 * 24 * time.Hour)}
+	service, key := newTestService(t, upbit)
+	halter, notifier := &recordingHalter{}, &recordingNotifier{}
+	service.WithExpiryMonitor(halter, notifier, cache.NewMemoryCache())
+
+	require.NoError(t, service.CheckExpiry(ctx, now))
+	require.NoError(t, service.CheckExpiry(ctx, now))
+	require.Len(t, notifier.sent, 1, "each reminder is sent once")
+	assert.Equal(t, model.NotificationAPIKeyExpiry, notifier.sent[0].Type)
+	assert.Equal(t, 20, notifier.sent[0].Data["days_left"])
+
+	stored, err := service.keys.GetActiveByUserID(ctx, key.UserID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.ExpiresAt, "the expiry is stored")
+	assert.True(t, stored.ExpiresAt.Equal(upbit.expireAt))
+
+	upbit.expireAt = now.Add(6 * 24 * time.Hour)
+	require.NoError(t, service.CheckExpiry(ctx, now))
+	require.Len(t, notifier.sent, 2, "the 7 day reminder follows the 30 day one")
+	assert.Equal(t, 6, notifier.sent[1].Data["days_left"])
+
+	upbit.expireAt = now.Add(100 * 24 * time.Hour)
+	require.NoError(t, service.CheckExpiry(ctx, now))
+	assert.Len(t, notifier.sent, 2, "no reminder long before expiry")
+	assert.Empty(t, halter.halts)
+}
+
+func TestService_CheckExpiryHaltsLapsedKeys(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	upbit := &stubUpbit{accessKey: "upbit-key", expireAt: now.Add(-time.Hour)}
+	service, key := newTestService(t, upbit)
+	halter, notifier := &recordingHalter{}, &recordingNotifier{}
+	service.WithExpiryMonitor(halter, notifier, cache.NewMemoryCache())
+
+	require.NoError(t, service.CheckExpiry(ctx, now))
+	require.Len(t, halter.halts, 1)
+	assert.Equal(t, key.UserID, halter.halts[0].UserID)
+	assert.Equal(t, model.HaltedByKeyExpiry, halter.halts[0].HaltedBy)
+	require.Len(t, notifier.sent, 1)
+	assert.Equal(t, true, notifier.sent[0].Data["halted"])
+
+	// Upbit rejects keys once they have lapsed
+	upbit.rejected = true
+	require.NoError(t, service.CheckExpiry(ctx, now))
+	assert.Len(t, halter.halts, 1, "halted trading isn't halted again")
+	assert.Len(t, notifier.sent, 1)
+
+	halter.halts = nil
+	require.NoError(t, service.CheckExpiry(ctx, now))
+	assert.Empty(t, halter.halts, "resuming on the lapsed key is halted again at most daily")
+}
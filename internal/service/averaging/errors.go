@@ -0,0 +1,20 @@
+package averaging
+
+var (
+	ErrInvalidRequest    = &AveragingError{message: "amount and target_entry must be positive"}
+	ErrTargetNotBelow    = &AveragingError{message: "target_entry must be below the position's average entry price"}
+	ErrPositionNotOpen   = &AveragingError{message: "position is not open"}
+	ErrNotLong           = &AveragingError{message: "only long positions can be averaged down"}
+	ErrAmountTooSmall    = &AveragingError{message: "amount is below the exchange's minimum order of 5000 KRW"}
+	ErrTargetUnreachable = &AveragingError{message: "target_entry can't be reached with this amount"}
+	ErrExecutionDisabled = &AveragingError{message: "order placement is not available"}
+)
+
+// AveragingError represents an average-down request that can't be planned
+type AveragingError struct {
+	message string
+}
+
+func (e *AveragingError) Error() string {
+	return e.message
+}
@@ -0,0 +1,150 @@
+// Package averaging plans the buys that lower a position's average entry
+// price to a target
+package averaging
+
+import (
+	"context"
+	"math"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/trading"
+)
+
+const (
+	// volumePrecision is how many decimals of volume Upbit accepts
+	volumePrecision = 1e8
+)
+
+// OrderPlacer places orders; trading.Engine satisfies it
+type OrderPlacer interface {
+	PlaceOrder(ctx context.Context, userID uuid.UUID, req trading.PlaceOrderRequest) (*model.Order, error)
+}
+
+// Request asks how to bring a position's average entry down to TargetEntry
+// by spending Amount more
+type Request struct {
+	Amount      float64 `json:"amount"`       // In the quote currency, e.g. KRW
+	TargetEntry float64 `json:"target_entry"` // Average entry price to reach
+	Execute     bool    `json:"execute"`      // Place the buy as a limit order
+}
+
+// Plan is the limit buy that reaches the target entry
+type Plan struct {
+	PositionID    uuid.UUID    `json:"position_id"`
+	Market        string       `json:"market"`
+	Quantity      float64      `json:"quantity"`    // Held now
+	EntryPrice    float64      `json:"entry_price"` // Average entry now
+	Amount        float64      `json:"amount"`
+	TargetEntry   float64      `json:"target_entry"`
+	BuyPrice      float64      `json:"buy_price"`
+	BuyQuantity   float64      `json:"buy_quantity"`
+	NewQuantity   float64      `json:"new_quantity"`
+	NewEntryPrice float64      `json:"new_entry_price"` // At or just below the target, after rounding to the price unit
+	Order         *model.Order `json:"order,omitempty"` // Set when the plan was executed
+}
+
+// Planner plans and optionally places average-down buys
+type Planner struct {
+	positions repository.PositionRepository
+	placer    OrderPlacer // Optional; enables Execute
+}
+
+// NewPlanner creates a new average-down planner
+func NewPlanner(positions repository.PositionRepository) *Planner {
+	return &Planner{positions: positions}
+}
+
+// WithPlacer lets the planner place the buys it plans
+func (p *Planner) WithPlacer(placer OrderPlacer) *Planner {
+	p.placer = placer
+	return p
+}
+
+// Plan computes the price and quantity a buy of req.Amount must fill at for
+// the user's position to average req.TargetEntry, and places it as a limit
+// buy when req.Execute is set
+func (p *Planner) Plan(ctx context.Context, userID, positionID uuid.UUID, req Request) (*Plan, error) {
+	if req.Amount <= 0 || req.TargetEntry <= 0 {
+		return nil, ErrInvalidRequest
+	}
+	if req.Execute && p.placer == nil {
+		return nil, ErrExecutionDisabled
+	}
+
+	position, err := p.positions.GetByID(ctx, positionID)
+	if err != nil {
+		return nil, err
+	}
+	if position.UserID != userID {
+		return nil, repository.ErrNotFound
+	}
+	if position.Status != model.PositionStatusOpen || position.Quantity <= 0 {
+		return nil, ErrPositionNotOpen
+	}
+	if position.Side != model.PositionSideLong {
+		return nil, ErrNotLong
+	}
+
+	plan, err := planBuy(position, req)
+	if err != nil {
+		return nil, err
+	}
+	if !req.Execute {
+		return plan, nil
+	}
+
+	plan.Order, err = p.placer.PlaceOrder(ctx, userID, trading.PlaceOrderRequest{
+		Market:     position.Market,
+		Side:       model.OrderSideBid,
+		Type:       model.OrderTypeLimit,
+		Quantity:   plan.BuyQuantity,
+		Price:      &plan.BuyPrice,
+		PositionID: &position.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// planBuy solves (Q*E + A) / (Q + A/P) = T for the buy price P, where Q and
+// E are the position's quantity and average entry, A the amount and T the
+// target. KRW prices are rounded down to the market's price unit, which
+// lands the new average at or just below the target.
+func planBuy(position *model.Position, req Request) (*Plan, error) {
+	q, e, a, t := position.Quantity, position.EntryPrice, req.Amount, req.TargetEntry
+	if t >= e {
+		return nil, ErrTargetNotBelow
+	}
+
+	krw := strings.HasPrefix(position.Market, "KRW-")
+	if krw && a < model.MinOrderAmount {
+		return nil, ErrAmountTooSmall
+	}
+
+	price := t * a / (a + q*(e-t))
+	if krw {
+		price = trading.FloorToTick(price)
+	}
+	quantity := math.Floor(a/price*volumePrecision) / volumePrecision
+	if price <= 0 || quantity <= 0 {
+		return nil, ErrTargetUnreachable
+	}
+
+	newQuantity := q + quantity
+	return &Plan{
+		PositionID:    position.ID,
+		Market:        position.Market,
+		Quantity:      q,
+		EntryPrice:    e,
+		Amount:        a,
+		TargetEntry:   t,
+		BuyPrice:      price,
+		BuyQuantity:   quantity,
+		NewQuantity:   newQuantity,
+		NewEntryPrice: (q*e + quantity*price) / newQuantity,
+	}, nil
+}
@@ -0,0 +1,88 @@
+package averaging
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+	"github.com/sungminna/upbit-trading-platform/internal/service/trading"
+)
+
+type fakePlacer struct {
+	requests []trading.PlaceOrderRequest
+}
+
+func (f *fakePlacer) PlaceOrder(ctx context.Context, userID uuid.UUID, req trading.PlaceOrderRequest) (*model.Order, error) {
+	f.requests = append(f.requests, req)
+	return model.NewOrder(userID, req.Market, req.Side, req.Type, req.Quantity, req.Price, time.Now()), nil
+}
+
+func TestPlanner_Plan(t *testing.T) {
+	store := memory.NewStore()
+	placer := &fakePlacer{}
+	planner := NewPlanner(store.Positions()).WithPlacer(placer)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	position := model.NewPosition(userID, "KRW-BTC", model.PositionSideLong, 100000000, 0.01, time.Now())
+	require.NoError(t, store.Positions().Create(ctx, position))
+
+	plan, err := planner.Plan(ctx, userID, position.ID, Request{Amount: 1000000, TargetEntry: 90000000})
+	require.NoError(t, err)
+	// 90M * 1M / (1M + 0.01 * 10M) = 81,818,181.8, floored to the 1,000 KRW unit
+	assert.Equal(t, 81818000.0, plan.BuyPrice)
+	assert.InDelta(t, 0.01222224, plan.BuyQuantity, 1e-12)
+	assert.LessOrEqual(t, plan.NewEntryPrice, 90000000.0)
+	assert.InDelta(t, 90000000, plan.NewEntryPrice, 1000)
+	assert.Nil(t, plan.Order)
+	assert.Empty(t, placer.requests, "previews place nothing")
+
+	plan, err = planner.Plan(ctx, userID, position.ID, Request{Amount: 1000000, TargetEntry: 90000000, Execute: true})
+	require.NoError(t, err)
+	require.NotNil(t, plan.Order)
+	require.Len(t, placer.requests, 1)
+	assert.Equal(t, model.OrderTypeLimit, placer.requests[0].Type)
+	assert.Equal(t, model.OrderSideBid, placer.requests[0].Side)
+	assert.Equal(t, 81818000.0, *placer.requests[0].Price)
+	assert.Equal(t, position.ID, *placer.requests[0].PositionID)
+}
+
+func TestPlanner_Plan_Rejects(t *testing.T) {
+	store := memory.NewStore()
+	ctx := context.Background()
+	userID := uuid.New()
+
+	position := model.NewPosition(userID, "KRW-BTC", model.PositionSideLong, 100000000, 0.01, time.Now())
+	require.NoError(t, store.Positions().Create(ctx, position))
+	closed := model.NewPosition(userID, "KRW-ETH", model.PositionSideLong, 5000000, 1, time.Now())
+	closed.ReduceQuantity(1, 5000000, time.Now())
+	require.NoError(t, store.Positions().Create(ctx, closed))
+
+	planner := NewPlanner(store.Positions())
+	tests := []struct {
+		name       string
+		userID     uuid.UUID
+		positionID uuid.UUID
+		req        Request
+		want       error
+	}{
+		{"no amount", userID, position.ID, Request{TargetEntry: 90000000}, ErrInvalidRequest},
+		{"target above entry", userID, position.ID, Request{Amount: 1000000, TargetEntry: 110000000}, ErrTargetNotBelow},
+		{"below minimum order", userID, position.ID, Request{Amount: 1000, TargetEntry: 90000000}, ErrAmountTooSmall},
+		{"closed position", userID, closed.ID, Request{Amount: 1000000, TargetEntry: 4000000}, ErrPositionNotOpen},
+		{"someone else's position", uuid.New(), position.ID, Request{Amount: 1000000, TargetEntry: 90000000}, repository.ErrNotFound},
+		{"execute without engine", userID, position.ID, Request{Amount: 1000000, TargetEntry: 90000000, Execute: true}, ErrExecutionDisabled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := planner.Plan(ctx, tt.userID, tt.positionID, tt.req)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
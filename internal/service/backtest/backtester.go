@@ -0,0 +1,310 @@
+// Package backtest simulates strategies on historical candles
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/service/sizing"
+	"github.com/sungminna/upbit-trading-platform/pkg/perf"
+)
+
+// defaultInitialCapital is the KRW a backtest starts with when unset
+const defaultInitialCapital = 1000000
+
+// CandleSource provides historical candles; gateway.QuotationAPI satisfies it
+type CandleSource interface {
+	GetCandleRange(ctx context.Context, market string, interval model.CandleInterval, from, to time.Time) ([]model.Candle, error)
+	StreamCandleRange(ctx context.Context, market string, interval model.CandleInterval, from, to time.Time, fn func([]model.Candle) error) error
+}
+
+// Config describes a single backtest run
+type Config struct {
+	Market         string               `json:"market"`
+	Interval       model.CandleInterval `json:"interval"`
+	From           time.Time            `json:"from"`
+	To             time.Time            `json:"to"`
+	Strategy       string               `json:"strategy"`
+	Params         Params               `json:"params"`
+	InitialCapital float64              `json:"initial_capital"`
+	Fees           *FeeModel            `json:"fees,omitempty"` // Upbit's fees when nil
+	Slippage       SlippageModel        `json:"slippage"`
+	Sizing         *sizing.Config       `json:"sizing,omitempty"` // Entries spend all cash when nil
+}
+
+// Trade is a simulated round trip
+type Trade struct {
+	EntryTime  time.Time `json:"entry_time"`
+	ExitTime   time.Time `json:"exit_time"`
+	EntryPrice float64   `json:"entry_price"`
+	ExitPrice  float64   `json:"exit_price"`
+	Quantity   float64   `json:"quantity"`
+	Fees       float64   `json:"fees"`
+	PnL        float64   `json:"pnl"` // Net of fees and slippage
+}
+
+// Result is the outcome of a backtest run
+type Result struct {
+	ID          uuid.UUID          `json:"id,omitzero"` // Set once the run is stored
+	Config      Config             `json:"config"`
+	Metrics     perf.Metrics       `json:"metrics"`
+	FinalEquity float64            `json:"final_equity"`
+	TotalFees   float64            `json:"total_fees"`
+	Slippage    float64            `json:"slippage"` // KRW lost to slippage
+	Trades      []Trade            `json:"trades"`
+	OpenTrade   *Trade             `json:"open_trade,omitempty"` // Position still open at the end
+	Equity      []perf.EquityPoint `json:"equity"`
+}
+
+// Backtester runs strategies against historical candles
+type Backtester struct {
+	candles CandleSource
+}
+
+// NewBacktester creates a new backtester
+func NewBacktester(candles CandleSource) *Backtester {
+	return &Backtester{candles: candles}
+}
+
+// Run simulates cfg's strategy on its candles as they are streamed, so
+// long ranges of short candles never have to be held in memory
+func (b *Backtester) Run(ctx context.Context, cfg Config) (*Result, error) {
+	if err := validateRange(cfg); err != nil {
+		return nil, err
+	}
+	sim, err := newSimulation(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	err = b.candles.StreamCandleRange(ctx, cfg.Market, cfg.Interval, cfg.From, cfg.To, func(candles []model.Candle) error {
+		for _, candle := range candles {
+			sim.step(candle)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load candles: %w", err)
+	}
+
+	return sim.finish()
+}
+
+// loadCandles fetches the candles for a run, oldest first
+func (b *Backtester) loadCandles(ctx context.Context, cfg Config) ([]model.Candle, error) {
+	if err := validateRange(cfg); err != nil {
+		return nil, err
+	}
+
+	candles, err := b.candles.GetCandleRange(ctx, cfg.Market, cfg.Interval, cfg.From, cfg.To)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load candles: %w", err)
+	}
+	if len(candles) == 0 {
+		return nil, ErrNoCandles
+	}
+
+	sort.Slice(candles, func(i, j int) bool {
+		return candles[i].Timestamp.Before(candles[j].Timestamp)
+	})
+	return candles, nil
+}
+
+func validateRange(cfg Config) error {
+	if !cfg.From.Before(cfg.To) {
+		return ErrInvalidRange
+	}
+	if cfg.Interval.Duration() == 0 {
+		return ErrInvalidInterval
+	}
+	return nil
+}
+
+// Simulate runs cfg's strategy over candles, which must be oldest first.
+// Orders fill at the close of the candle that produced the signal, adjusted
+// for slippage. Entries invest the whole account unless cfg.Sizing is set, in
+// which case the equity is the cash on hand and ATR and volatility are measured
+// on the candles up to the signal; signals without enough history to size
+// are skipped. An open
+// position at the end is marked to market and reported as OpenTrade, but not
+// counted as a trade.
+func Simulate(cfg Config, candles []model.Candle) (*Result, error) {
+	sim, err := newSimulation(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(candles) == 0 {
+		return nil, ErrNoCandles
+	}
+
+	for _, candle := range candles {
+		sim.step(candle)
+	}
+	return sim.finish()
+}
+
+// simulation is the state of a backtest run fed one candle at a time
+type simulation struct {
+	cfg       Config
+	strategy  Strategy
+	feeRate   float64
+	cash      float64
+	quantity  float64
+	entryCost float64
+	open      *Trade
+	history   []model.Candle // The most recent candles, for sizing entries
+	result    *Result
+}
+
+// newSimulation validates cfg, fills in its defaults and starts a run
+func newSimulation(cfg Config) (*simulation, error) {
+	strategy, err := NewStrategy(cfg.Strategy, cfg.Params)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.InitialCapital <= 0 {
+		cfg.InitialCapital = defaultInitialCapital
+	}
+	if cfg.Fees == nil {
+		fees := DefaultFees
+		cfg.Fees = &fees
+	}
+	if err := cfg.Fees.validate(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Slippage.validate(); err != nil {
+		return nil, err
+	}
+	if cfg.Sizing != nil {
+		if err := cfg.Sizing.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSizing, err)
+		}
+	}
+
+	return &simulation{
+		cfg:      cfg,
+		strategy: strategy,
+		feeRate:  cfg.Fees.rate(),
+		cash:     cfg.InitialCapital,
+		result:   &Result{Config: cfg},
+	}, nil
+}
+
+// step feeds the next candle to the strategy and fills its signal
+func (s *simulation) step(candle model.Candle) {
+	cfg, result := s.cfg, s.result
+	price := candle.ClosePrice
+
+	if cfg.Sizing != nil {
+		// ATR and volatility only look at the last period+1 candles
+		keep := cfg.Sizing.PeriodOrDefault() + 1
+		s.history = append(s.history, candle)
+		if len(s.history) > 2*keep {
+			s.history = append(s.history[:0], s.history[len(s.history)-keep:]...)
+		}
+	}
+
+	switch s.strategy.OnCandle(candle, s.open != nil) {
+	case SignalBuy:
+		if s.open == nil && price > 0 {
+			target := s.cash / price
+			if cfg.Sizing != nil {
+				sized, ok := sizeEntry(*cfg.Sizing, s.cash, s.history, periodsPerYear(cfg.Interval))
+				if !ok {
+					break
+				}
+				target = sized
+			}
+
+			fillPrice := price * (1 + cfg.Slippage.rate(target, candle.Volume))
+			// Never spend more than the cash on hand, leaving room for the fee
+			s.quantity = math.Min(target, s.cash/(fillPrice*(1+s.feeRate)))
+			fee := s.quantity * fillPrice * s.feeRate
+			s.entryCost = s.quantity*fillPrice + fee
+			s.cash -= s.entryCost
+
+			result.TotalFees += fee
+			result.Slippage += s.quantity * (fillPrice - price)
+			s.open = &Trade{EntryTime: candle.Timestamp, EntryPrice: fillPrice, Quantity: s.quantity, Fees: fee}
+		}
+	case SignalSell:
+		if s.open != nil {
+			fillPrice := price * (1 - cfg.Slippage.rate(s.quantity, candle.Volume))
+			proceeds := s.quantity * fillPrice
+			fee := proceeds * s.feeRate
+			s.cash += proceeds - fee
+
+			result.TotalFees += fee
+			result.Slippage += s.quantity * (price - fillPrice)
+			s.open.ExitTime = candle.Timestamp
+			s.open.ExitPrice = fillPrice
+			s.open.Fees += fee
+			s.open.PnL = proceeds - fee - s.entryCost
+			result.Trades = append(result.Trades, *s.open)
+			s.quantity, s.open = 0, nil
+		}
+	}
+
+	result.Equity = append(result.Equity, perf.EquityPoint{Time: candle.Timestamp, Equity: s.cash + s.quantity*price})
+}
+
+// finish marks an open position to market and computes the run's metrics
+func (s *simulation) finish() (*Result, error) {
+	result := s.result
+	if len(result.Equity) == 0 {
+		return nil, ErrNoCandles
+	}
+
+	result.OpenTrade = s.open
+	result.FinalEquity = result.Equity[len(result.Equity)-1].Equity
+	result.Metrics = perf.Compute(result.Equity, perfTrades(result.Trades), perf.Options{
+		PeriodsPerYear: periodsPerYear(s.cfg.Interval),
+	})
+	return result, nil
+}
+
+// sizeEntry returns the quantity cfg buys with the given cash at the last
+// candle's close, or false when the entry can't be sized
+func sizeEntry(cfg sizing.Config, cash float64, history []model.Candle, periodsPerYear float64) (float64, bool) {
+	in := sizing.Inputs{Equity: cash, EntryPrice: history[len(history)-1].ClosePrice}
+
+	var err error
+	period := cfg.PeriodOrDefault()
+	if cfg.NeedsATR() {
+		if in.ATR, err = sizing.ATR(history, period); err != nil {
+			return 0, false
+		}
+	}
+	if cfg.NeedsVolatility() {
+		if in.Volatility, err = sizing.Volatility(history, period, periodsPerYear); err != nil {
+			return 0, false
+		}
+	}
+
+	result, err := sizing.Size(cfg, in)
+	if err != nil || result.Quantity <= 0 {
+		return 0, false
+	}
+	return result.Quantity, true
+}
+
+func perfTrades(trades []Trade) []perf.Trade {
+	result := make([]perf.Trade, len(trades))
+	for i, t := range trades {
+		result[i] = perf.Trade{EntryTime: t.EntryTime, ExitTime: t.ExitTime, PnL: t.PnL}
+	}
+	return result
+}
+
+// periodsPerYear returns how many candles of an interval fit in a year
+func periodsPerYear(interval model.CandleInterval) float64 {
+	if interval.Duration() == 0 {
+		return 0
+	}
+	return float64(365*24*time.Hour) / float64(interval.Duration())
+}
@@ -0,0 +1,303 @@
+package backtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/service/sizing"
+)
+
+var testStart = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func hourlyCandles(closes ...float64) []model.Candle {
+	candles := make([]model.Candle, len(closes))
+	for i, c := range closes {
+		candles[i] = model.Candle{
+			Market:     "KRW-BTC",
+			Interval:   model.CandleInterval1h,
+			Timestamp:  testStart.Add(time.Duration(i) * time.Hour),
+			ClosePrice: c,
+		}
+	}
+	return candles
+}
+
+// stubCandles returns fixed candles newest first, like the Upbit API
+type stubCandles struct {
+	candles []model.Candle
+}
+
+func (s *stubCandles) GetCandleRange(ctx context.Context, market string, interval model.CandleInterval, from, to time.Time) ([]model.Candle, error) {
+	result := make([]model.Candle, len(s.candles))
+	for i, c := range s.candles {
+		result[len(s.candles)-1-i] = c
+	}
+	return result, nil
+}
+
+// StreamCandleRange passes the candles oldest first, two at a time
+func (s *stubCandles) StreamCandleRange(ctx context.Context, market string, interval model.CandleInterval, from, to time.Time, fn func([]model.Candle) error) error {
+	for i := 0; i < len(s.candles); i += 2 {
+		if err := fn(s.candles[i:min(i+2, len(s.candles))]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestSimulate_TrailingStop(t *testing.T) {
+	cfg := Config{
+		Interval:       model.CandleInterval1h,
+		Strategy:       "trailing_stop",
+		Params:         Params{"trail_percent": 10},
+		InitialCapital: 1000,
+		Fees:           &FeeModel{},
+	}
+	// Buy at 100, ride to 200, exit at 180 (10% off the high), re-enter at 199
+	candles := hourlyCandles(100, 150, 200, 180, 185, 199, 210)
+
+	result, err := Simulate(cfg, candles)
+	require.NoError(t, err)
+
+	require.Len(t, result.Trades, 1)
+	trade := result.Trades[0]
+	assert.Equal(t, 100.0, trade.EntryPrice)
+	assert.Equal(t, 180.0, trade.ExitPrice)
+	assert.InDelta(t, 800, trade.PnL, 1e-9)
+
+	// 1800 cash re-invested at 199 and marked at 210
+	assert.InDelta(t, 1800.0/199*210, result.FinalEquity, 1e-6)
+	assert.Len(t, result.Equity, len(candles))
+	assert.InDelta(t, 1, result.Metrics.WinRate, 1e-9)
+}
+
+func TestSimulate_Sizing(t *testing.T) {
+	cfg := Config{
+		Interval:       model.CandleInterval1h,
+		Strategy:       "trailing_stop",
+		Params:         Params{"trail_percent": 10},
+		InitialCapital: 1000,
+		Fees:           &FeeModel{},
+		// Half Kelly for a 60% win rate at 1:1 puts 10% of cash in each trade
+		Sizing: &sizing.Config{Method: sizing.MethodKelly, WinRate: 0.6, PayoffRatio: 1},
+	}
+	candles := hourlyCandles(100, 150, 200, 180, 185, 199, 210)
+
+	result, err := Simulate(cfg, candles)
+	require.NoError(t, err)
+
+	require.Len(t, result.Trades, 1)
+	assert.InDelta(t, 1, result.Trades[0].Quantity, 1e-9)
+	assert.InDelta(t, 80, result.Trades[0].PnL, 1e-9)
+
+	// The rest of the cash stays uninvested
+	require.NotNil(t, result.OpenTrade)
+	quantity := result.OpenTrade.Quantity
+	assert.InDelta(t, 108.0/199, quantity, 1e-8)
+	assert.InDelta(t, 1080+quantity*11, result.FinalEquity, 1e-9)
+
+	cfg.Sizing = &sizing.Config{Method: sizing.MethodFixedFractional}
+	_, err = Simulate(cfg, candles)
+	assert.ErrorIs(t, err, ErrInvalidSizing)
+}
+
+func TestSimulate_FeesAndSlippage(t *testing.T) {
+	cfg := Config{
+		Interval:       model.CandleInterval1h,
+		Strategy:       "trailing_stop",
+		Params:         Params{"trail_percent": 10},
+		InitialCapital: 1000,
+		Slippage:       SlippageModel{Model: SlippageFixed, BPS: 10},
+	}
+	candles := hourlyCandles(100, 150, 200, 180)
+
+	result, err := Simulate(cfg, candles)
+	require.NoError(t, err)
+	require.Len(t, result.Trades, 1)
+	trade := result.Trades[0]
+
+	// Upbit's 0.05% fee applies by default and slippage moves both fills against us
+	assert.InDelta(t, 100.1, trade.EntryPrice, 1e-9)
+	assert.InDelta(t, 179.82, trade.ExitPrice, 1e-9)
+
+	quantity := 1000 / (100.1 * 1.0005)
+	exit := quantity * 179.82 * (1 - 0.0005)
+	assert.InDelta(t, quantity, trade.Quantity, 1e-9)
+	assert.InDelta(t, exit-1000, trade.PnL, 1e-9)
+	assert.InDelta(t, quantity*100.1*0.0005+quantity*179.82*0.0005, result.TotalFees, 1e-9)
+	assert.InDelta(t, quantity*0.1+quantity*0.18, result.Slippage, 1e-9)
+	assert.InDelta(t, exit, result.FinalEquity, 1e-9)
+}
+
+func TestSlippageModel_Rate(t *testing.T) {
+	assert.Zero(t, SlippageModel{}.rate(10, 100))
+	assert.InDelta(t, 0.001, SlippageModel{Model: SlippageFixed, BPS: 10}.rate(10, 100), 1e-12)
+
+	volume := SlippageModel{Model: SlippageVolume, BPS: 5, ImpactBPS: 100}
+	assert.InDelta(t, 0.0015, volume.rate(10, 100), 1e-12, "10% of the candle's volume adds 10 bps")
+	assert.InDelta(t, 0.0105, volume.rate(10, 0), 1e-12, "unknown volume assumes full impact")
+
+	volume.MaxBPS = 8
+	assert.InDelta(t, 0.0008, volume.rate(10, 100), 1e-12)
+}
+
+func TestSimulate_InvalidConfig(t *testing.T) {
+	candles := hourlyCandles(100, 101)
+
+	_, err := Simulate(Config{Interval: model.CandleInterval1h, Strategy: "unknown"}, candles)
+	assert.ErrorIs(t, err, ErrUnknownStrategy)
+
+	_, err = Simulate(Config{Interval: model.CandleInterval1h, Strategy: "trailing_stop", Params: Params{"trail_percent": 0}}, candles)
+	assert.ErrorIs(t, err, ErrInvalidParams)
+
+	_, err = Simulate(Config{Interval: model.CandleInterval1h, Strategy: "trailing_stop", Params: Params{"trail_percent": 5}}, nil)
+	assert.ErrorIs(t, err, ErrNoCandles)
+
+	valid := Config{Interval: model.CandleInterval1h, Strategy: "trailing_stop", Params: Params{"trail_percent": 5}}
+	withFees := valid
+	withFees.Fees = &FeeModel{TakerRate: -0.1}
+	_, err = Simulate(withFees, candles)
+	assert.ErrorIs(t, err, ErrInvalidCosts)
+
+	withSlippage := valid
+	withSlippage.Slippage = SlippageModel{Model: "random"}
+	_, err = Simulate(withSlippage, candles)
+	assert.ErrorIs(t, err, ErrInvalidCosts)
+}
+
+func TestParamGrid(t *testing.T) {
+	grid, err := paramGrid(Params{"fast": 5}, []ParamRange{{Name: "slow", Min: 10, Max: 30, Step: 10}})
+	require.NoError(t, err)
+	assert.Equal(t, []Params{{"fast": 5, "slow": 10}, {"fast": 5, "slow": 20}, {"fast": 5, "slow": 30}}, grid)
+
+	grid, err = paramGrid(nil, []ParamRange{{Name: "trail_percent", Min: 0.1, Max: 0.3, Step: 0.1}})
+	require.NoError(t, err)
+	assert.Len(t, grid, 3, "floating point drift must not drop the last value")
+	assert.Equal(t, 0.3, grid[2]["trail_percent"])
+
+	_, err = paramGrid(nil, []ParamRange{{Name: "a", Min: 1, Max: 100, Step: 1}, {Name: "b", Min: 1, Max: 100, Step: 1}})
+	assert.ErrorIs(t, err, ErrTooManyRuns)
+
+	_, err = paramGrid(nil, []ParamRange{{Name: "a", Min: 1, Max: 2, Step: 0}})
+	assert.ErrorIs(t, err, ErrInvalidParams)
+}
+
+func TestBacktester_Optimize(t *testing.T) {
+	backtester := NewBacktester(&stubCandles{candles: hourlyCandles(100, 150, 200, 180, 185, 198, 210, 150, 120, 160)})
+
+	results, err := backtester.Optimize(context.Background(), OptimizeConfig{
+		Base: Config{
+			Market:   "KRW-BTC",
+			Interval: model.CandleInterval1h,
+			From:     testStart,
+			To:       testStart.Add(24 * time.Hour),
+			Strategy: "trailing_stop",
+		},
+		Ranges:    []ParamRange{{Name: "trail_percent", Min: 5, Max: 50, Step: 5}},
+		Objective: ObjectiveTotalReturn,
+		Workers:   3,
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 10)
+
+	for i, r := range results {
+		assert.Equal(t, i+1, r.Rank)
+		if i > 0 {
+			assert.GreaterOrEqual(t, results[i-1].Metrics.TotalReturn, r.Metrics.TotalReturn)
+		}
+	}
+
+	// Each result must match a standalone run with the same params
+	single, err := backtester.Run(context.Background(), Config{
+		Market:   "KRW-BTC",
+		Interval: model.CandleInterval1h,
+		From:     testStart,
+		To:       testStart.Add(24 * time.Hour),
+		Strategy: "trailing_stop",
+		Params:   results[0].Params,
+	})
+	require.NoError(t, err)
+	assert.InDelta(t, single.FinalEquity, results[0].FinalEquity, 1e-9)
+}
+
+func TestBacktester_WalkForward(t *testing.T) {
+	closes := make([]float64, 60)
+	for i := range closes {
+		// Saw-tooth: rises 10 candles, falls 5
+		if i%15 < 10 {
+			closes[i] = 100 + float64(i%15)*5
+		} else {
+			closes[i] = 150 - float64(i%15-10)*10
+		}
+	}
+	backtester := NewBacktester(&stubCandles{candles: hourlyCandles(closes...)})
+
+	result, err := backtester.WalkForward(context.Background(), WalkForwardConfig{
+		Optimize: OptimizeConfig{
+			Base: Config{
+				Market:   "KRW-BTC",
+				Interval: model.CandleInterval1h,
+				From:     testStart,
+				To:       testStart.Add(60 * time.Hour),
+				Strategy: "trailing_stop",
+			},
+			Ranges:    []ParamRange{{Name: "trail_percent", Min: 2, Max: 20, Step: 2}},
+			Objective: ObjectiveTotalReturn,
+		},
+		TrainCandles: 30,
+		TestCandles:  10,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, result.Windows, 3)
+	for i, w := range result.Windows {
+		assert.Equal(t, testStart.Add(time.Duration(i*10)*time.Hour), w.TrainFrom)
+		assert.Equal(t, testStart.Add(time.Duration(i*10+30)*time.Hour), w.TestFrom)
+		assert.True(t, w.TrainTo.Before(w.TestFrom))
+		assert.Contains(t, w.Params, "trail_percent")
+	}
+
+	_, err = backtester.WalkForward(context.Background(), WalkForwardConfig{
+		Optimize:     OptimizeConfig{Base: Config{Interval: model.CandleInterval1h, From: testStart, To: testStart.Add(time.Hour), Strategy: "trailing_stop"}},
+		TrainCandles: 50,
+		TestCandles:  20,
+	})
+	assert.ErrorIs(t, err, ErrInvalidRange)
+}
+
+func TestBacktester_Run(t *testing.T) {
+	closes := make([]float64, 100)
+	for i := range closes {
+		if i%15 < 10 {
+			closes[i] = 100 + float64(i%15)*5
+		} else {
+			closes[i] = 150 - float64(i%15-10)*10
+		}
+	}
+	candles := hourlyCandles(closes...)
+	cfg := Config{
+		Market:         "KRW-BTC",
+		Interval:       model.CandleInterval1h,
+		From:           testStart,
+		To:             testStart.Add(100 * time.Hour),
+		Strategy:       "trailing_stop",
+		Params:         Params{"trail_percent": 5},
+		InitialCapital: 1000,
+		Sizing:         &sizing.Config{Method: sizing.MethodVolatility, TargetVolatility: 0.2, Period: 5},
+	}
+
+	// Streaming keeps only a short sizing window but must match a run over all candles
+	expected, err := Simulate(cfg, candles)
+	require.NoError(t, err)
+	result, err := NewBacktester(&stubCandles{candles: candles}).Run(context.Background(), cfg)
+	require.NoError(t, err)
+
+	require.NotEmpty(t, result.Trades)
+	assert.Equal(t, expected.Trades, result.Trades)
+	assert.Equal(t, expected.FinalEquity, result.FinalEquity)
+	assert.Len(t, result.Equity, len(candles))
+}
@@ -0,0 +1,149 @@
+package backtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// NewRunRecord converts a result into a run record owned by userID
+func NewRunRecord(userID uuid.UUID, result *Result) (*model.BacktestRun, error) {
+	config, err := json.Marshal(result.Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal backtest config: %w", err)
+	}
+
+	trades := make([]model.BacktestTrade, len(result.Trades))
+	for i, t := range result.Trades {
+		trades[i] = model.BacktestTrade(t)
+	}
+
+	return &model.BacktestRun{
+		ID:             uuid.New(),
+		UserID:         userID,
+		Market:         result.Config.Market,
+		Interval:       result.Config.Interval,
+		Strategy:       result.Config.Strategy,
+		Params:         copyParams(result.Config.Params),
+		From:           result.Config.From,
+		To:             result.Config.To,
+		Config:         config,
+		InitialCapital: result.Config.InitialCapital,
+		FinalEquity:    result.FinalEquity,
+		TotalFees:      result.TotalFees,
+		Slippage:       result.Slippage,
+		Metrics:        result.Metrics,
+		Trades:         trades,
+		CreatedAt:      time.Now(),
+	}, nil
+}
+
+// comparedMetric is a metric included in run comparisons
+type comparedMetric struct {
+	name          string
+	value         func(run *model.BacktestRun) float64
+	lowerIsBetter bool
+}
+
+var comparedMetrics = []comparedMetric{
+	{name: "total_return", value: func(r *model.BacktestRun) float64 { return r.Metrics.TotalReturn }},
+	{name: "cagr", value: func(r *model.BacktestRun) float64 { return r.Metrics.CAGR }},
+	{name: "max_drawdown", value: func(r *model.BacktestRun) float64 { return r.Metrics.MaxDrawdown }, lowerIsBetter: true},
+	{name: "sharpe", value: func(r *model.BacktestRun) float64 { return r.Metrics.Sharpe }},
+	{name: "sortino", value: func(r *model.BacktestRun) float64 { return r.Metrics.Sortino }},
+	{name: "trades", value: func(r *model.BacktestRun) float64 { return float64(r.Metrics.Trades) }},
+	{name: "win_rate", value: func(r *model.BacktestRun) float64 { return r.Metrics.WinRate }},
+	{name: "profit_factor", value: func(r *model.BacktestRun) float64 { return r.Metrics.ProfitFactor }},
+	{name: "final_equity", value: func(r *model.BacktestRun) float64 { return r.FinalEquity }},
+	{name: "total_fees", value: func(r *model.BacktestRun) float64 { return r.TotalFees }, lowerIsBetter: true},
+}
+
+// ComparedRun is one run of a comparison
+type ComparedRun struct {
+	ID       uuid.UUID          `json:"id"`
+	Market   string             `json:"market"`
+	Strategy string             `json:"strategy"`
+	Params   Params             `json:"params"`
+	Metrics  map[string]float64 `json:"metrics"`
+	Diff     map[string]float64 `json:"diff"` // Metrics minus the baseline's
+}
+
+// Comparison diffs the metrics of several runs against the first, the baseline
+type Comparison struct {
+	Baseline      uuid.UUID            `json:"baseline"`
+	ChangedParams []string             `json:"changed_params"` // Params whose values differ between runs
+	Best          map[string]uuid.UUID `json:"best"`           // Run with the best value of each metric
+	Runs          []ComparedRun        `json:"runs"`
+}
+
+// Compare diffs runs against the first one
+func Compare(runs []*model.BacktestRun) *Comparison {
+	comparison := &Comparison{
+		ChangedParams: changedParams(runs),
+		Best:          make(map[string]uuid.UUID),
+		Runs:          make([]ComparedRun, 0, len(runs)),
+	}
+	if len(runs) == 0 {
+		return comparison
+	}
+	baseline := runs[0]
+	comparison.Baseline = baseline.ID
+
+	for _, run := range runs {
+		compared := ComparedRun{
+			ID:       run.ID,
+			Market:   run.Market,
+			Strategy: run.Strategy,
+			Params:   run.Params,
+			Metrics:  make(map[string]float64, len(comparedMetrics)),
+			Diff:     make(map[string]float64, len(comparedMetrics)),
+		}
+		for _, m := range comparedMetrics {
+			compared.Metrics[m.name] = m.value(run)
+			compared.Diff[m.name] = m.value(run) - m.value(baseline)
+		}
+		comparison.Runs = append(comparison.Runs, compared)
+	}
+
+	// Ties go to the earliest run
+	for _, m := range comparedMetrics {
+		best := runs[0]
+		for _, run := range runs[1:] {
+			if m.lowerIsBetter && m.value(run) < m.value(best) || !m.lowerIsBetter && m.value(run) > m.value(best) {
+				best = run
+			}
+		}
+		comparison.Best[m.name] = best.ID
+	}
+
+	return comparison
+}
+
+// changedParams returns the sorted names of params that aren't identical
+// across all runs, including params only some runs set
+func changedParams(runs []*model.BacktestRun) []string {
+	changed := []string{}
+	seen := make(map[string]bool)
+	for _, run := range runs {
+		for name := range run.Params {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+
+			for _, other := range runs {
+				value, ok := other.Params[name]
+				if !ok || value != run.Params[name] {
+					changed = append(changed, name)
+					break
+				}
+			}
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
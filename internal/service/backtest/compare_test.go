@@ -0,0 +1,60 @@
+package backtest
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/pkg/perf"
+)
+
+func TestNewRunRecord(t *testing.T) {
+	cfg := Config{
+		Market:   "KRW-BTC",
+		Interval: model.CandleInterval1h,
+		Strategy: "trailing_stop",
+		Params:   Params{"trail_percent": 10},
+	}
+	result, err := Simulate(cfg, hourlyCandles(100, 150, 200, 180))
+	require.NoError(t, err)
+
+	userID := uuid.New()
+	run, err := NewRunRecord(userID, result)
+	require.NoError(t, err)
+
+	assert.Equal(t, userID, run.UserID)
+	assert.Equal(t, "KRW-BTC", run.Market)
+	assert.Equal(t, map[string]float64{"trail_percent": 10}, run.Params)
+	assert.Equal(t, result.FinalEquity, run.FinalEquity)
+	require.Len(t, run.Trades, 1)
+	assert.Equal(t, result.Trades[0].PnL, run.Trades[0].PnL)
+	assert.Contains(t, string(run.Config), `"fees"`, "the config records the costs the run used")
+}
+
+func TestCompare(t *testing.T) {
+	baseline := &model.BacktestRun{
+		ID:      uuid.New(),
+		Params:  map[string]float64{"fast": 5, "slow": 20},
+		Metrics: perf.Metrics{TotalReturn: 0.1, MaxDrawdown: 0.2, Sharpe: 1},
+	}
+	other := &model.BacktestRun{
+		ID:      uuid.New(),
+		Params:  map[string]float64{"fast": 5, "slow": 30},
+		Metrics: perf.Metrics{TotalReturn: 0.15, MaxDrawdown: 0.25, Sharpe: 1},
+	}
+
+	comparison := Compare([]*model.BacktestRun{baseline, other})
+
+	assert.Equal(t, baseline.ID, comparison.Baseline)
+	assert.Equal(t, []string{"slow"}, comparison.ChangedParams)
+	require.Len(t, comparison.Runs, 2)
+	assert.Zero(t, comparison.Runs[0].Diff["total_return"])
+	assert.InDelta(t, 0.05, comparison.Runs[1].Diff["total_return"], 1e-9)
+	assert.InDelta(t, 0.05, comparison.Runs[1].Diff["max_drawdown"], 1e-9)
+
+	assert.Equal(t, other.ID, comparison.Best["total_return"])
+	assert.Equal(t, baseline.ID, comparison.Best["max_drawdown"], "lower drawdown is better")
+	assert.Equal(t, baseline.ID, comparison.Best["sharpe"], "ties go to the earliest run")
+}
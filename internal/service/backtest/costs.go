@@ -0,0 +1,85 @@
+package backtest
+
+import (
+	"fmt"
+	"math"
+)
+
+// upbitFeeRate is Upbit's KRW market trading fee for both makers and takers
+const upbitFeeRate = 0.0005
+
+// DefaultFees are Upbit's KRW market fees
+var DefaultFees = FeeModel{MakerRate: upbitFeeRate, TakerRate: upbitFeeRate}
+
+// FeeModel configures trading fees as fractions of the traded amount
+type FeeModel struct {
+	MakerRate float64 `json:"maker_rate"`
+	TakerRate float64 `json:"taker_rate"`
+	// Maker charges MakerRate on every fill, for strategies that rest limit
+	// orders at the signal price. By default fills are market orders (taker).
+	Maker bool `json:"maker"`
+}
+
+func (f FeeModel) rate() float64 {
+	if f.Maker {
+		return f.MakerRate
+	}
+	return f.TakerRate
+}
+
+func (f FeeModel) validate() error {
+	if f.MakerRate < 0 || f.TakerRate < 0 || f.MakerRate >= 1 || f.TakerRate >= 1 {
+		return fmt.Errorf("%w: fee rates must be in [0, 1)", ErrInvalidCosts)
+	}
+	return nil
+}
+
+// Slippage models
+const (
+	SlippageNone   = ""
+	SlippageFixed  = "fixed"  // BPS on every fill
+	SlippageVolume = "volume" // BPS plus ImpactBPS per 100% of the candle's volume traded
+)
+
+// SlippageModel moves fills against the order: buys fill higher and sells
+// lower than the candle close
+type SlippageModel struct {
+	Model     string  `json:"model"`
+	BPS       float64 `json:"bps"`        // Basis points of the price
+	ImpactBPS float64 `json:"impact_bps"` // Volume model only
+	MaxBPS    float64 `json:"max_bps"`    // Caps volume slippage; uncapped when zero
+}
+
+// rate returns the slippage as a fraction of price for an order of quantity
+// in a candle that traded candleVolume
+func (s SlippageModel) rate(quantity, candleVolume float64) float64 {
+	bps := 0.0
+	switch s.Model {
+	case SlippageFixed:
+		bps = s.BPS
+	case SlippageVolume:
+		bps = s.BPS
+		if candleVolume > 0 {
+			bps += s.ImpactBPS * quantity / candleVolume
+		} else {
+			// No volume information: assume the order is the whole market
+			bps += s.ImpactBPS
+		}
+		if s.MaxBPS > 0 {
+			bps = math.Min(bps, s.MaxBPS)
+		}
+	}
+	return bps / 10000
+}
+
+func (s SlippageModel) validate() error {
+	switch s.Model {
+	case SlippageNone, SlippageFixed, SlippageVolume:
+	default:
+		return fmt.Errorf("%w: unknown slippage model %q", ErrInvalidCosts, s.Model)
+	}
+	if s.BPS < 0 || s.ImpactBPS < 0 || s.MaxBPS < 0 {
+		return fmt.Errorf("%w: slippage must not be negative", ErrInvalidCosts)
+	}
+	return nil
+}
@@ -0,0 +1,225 @@
+package backtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// Trigger is an order the strategy is expected to place. Price is the trigger
+// level, the candle close, before slippage.
+type Trigger struct {
+	Time  time.Time       `json:"time"` // Close of the candle that produced the signal
+	Side  model.OrderSide `json:"side"`
+	Price float64         `json:"price"`
+}
+
+// LiveFill is a filled live order, priced at its average execution price
+type LiveFill struct {
+	OrderID uuid.UUID       `json:"order_id"`
+	Time    time.Time       `json:"time"`
+	Side    model.OrderSide `json:"side"`
+	Price   float64         `json:"price"`
+}
+
+// MatchedTrigger pairs an expected trigger with the live fill that executed it
+type MatchedTrigger struct {
+	Expected    Trigger       `json:"expected"`
+	Live        LiveFill      `json:"live"`
+	SlippageBPS float64       `json:"slippage_bps"` // Positive when the live price was worse
+	Latency     time.Duration `json:"latency"`      // Negative when live traded before the candle closed
+}
+
+// DivergenceReport compares a backtested strategy with its live executions
+type DivergenceReport struct {
+	RunID          uuid.UUID        `json:"run_id"`
+	From           time.Time        `json:"from"`
+	To             time.Time        `json:"to"`
+	Matched        []MatchedTrigger `json:"matched"`
+	Missed         []Trigger        `json:"missed"`     // Expected but never executed
+	Unexpected     []LiveFill       `json:"unexpected"` // Executed without an expected trigger
+	MatchRate      float64          `json:"match_rate"` // Matched / expected
+	AvgSlippageBPS float64          `json:"avg_slippage_bps"`
+	AvgLatency     time.Duration    `json:"avg_latency"`
+}
+
+// DivergenceTracker measures how live trading of a market diverges from a
+// stored backtest run by replaying the run's configuration over the live window
+type DivergenceTracker struct {
+	backtester *Backtester
+	orders     repository.OrderRepository
+	executions repository.OrderExecutionRepository
+}
+
+// NewDivergenceTracker creates a new divergence tracker
+func NewDivergenceTracker(backtester *Backtester, orders repository.OrderRepository, executions repository.OrderExecutionRepository) *DivergenceTracker {
+	return &DivergenceTracker{
+		backtester: backtester,
+		orders:     orders,
+		executions: executions,
+	}
+}
+
+// Report compares the triggers run's strategy produces in [from, to) with the
+// user's fills in the run's market. Fills count as a trigger's execution from
+// the candle's open until tolerance after its close.
+func (t *DivergenceTracker) Report(ctx context.Context, run *model.BacktestRun, from, to time.Time, tolerance time.Duration) (*DivergenceReport, error) {
+	var cfg Config
+	if err := json.Unmarshal(run.Config, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal backtest config: %w", err)
+	}
+	cfg.From, cfg.To = from, to
+
+	triggers, err := t.backtester.ExpectedTriggers(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	fills, err := t.liveFills(ctx, run.UserID, cfg.Market, from, to.Add(tolerance))
+	if err != nil {
+		return nil, err
+	}
+
+	report := CompareLive(triggers, fills, cfg.Interval.Duration(), tolerance)
+	report.RunID, report.From, report.To = run.ID, from, to
+	return report, nil
+}
+
+// liveFills returns the user's filled orders in market between from and to,
+// oldest first
+func (t *DivergenceTracker) liveFills(ctx context.Context, userID uuid.UUID, market string, from, to time.Time) ([]LiveFill, error) {
+	orders, err := t.orders.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders: %w", err)
+	}
+
+	var fills []LiveFill
+	for _, order := range orders {
+		if order.Market != market || order.ExecutedQuantity <= 0 {
+			continue
+		}
+
+		executions, err := t.executions.ListByOrder(ctx, order.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list executions: %w", err)
+		}
+		if len(executions) == 0 {
+			continue
+		}
+
+		// Orders are timed by their first fill and priced at the average fill
+		first := executions[0].CreatedAt
+		var total, quantity float64
+		for _, e := range executions {
+			total += e.Price * e.Quantity
+			quantity += e.Quantity
+			if e.CreatedAt.Before(first) {
+				first = e.CreatedAt
+			}
+		}
+		if quantity <= 0 || first.Before(from) || !first.Before(to) {
+			continue
+		}
+
+		fills = append(fills, LiveFill{OrderID: order.ID, Time: first, Side: order.Side, Price: total / quantity})
+	}
+
+	sort.Slice(fills, func(i, j int) bool {
+		return fills[i].Time.Before(fills[j].Time)
+	})
+	return fills, nil
+}
+
+// ExpectedTriggers simulates cfg and returns the orders its strategy places,
+// including the entry of a position still open at the end
+func (b *Backtester) ExpectedTriggers(ctx context.Context, cfg Config) ([]Trigger, error) {
+	candles, err := b.loadCandles(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Costs don't move signals; simulate frictionless to report raw trigger levels
+	cfg.Fees = &FeeModel{}
+	cfg.Slippage = SlippageModel{}
+	result, err := Simulate(cfg, candles)
+	if err != nil {
+		return nil, err
+	}
+
+	interval := cfg.Interval.Duration()
+	trades := result.Trades
+	if result.OpenTrade != nil {
+		trades = append(trades, *result.OpenTrade)
+	}
+
+	var triggers []Trigger
+	for _, trade := range trades {
+		triggers = append(triggers, Trigger{Time: trade.EntryTime.Add(interval), Side: model.OrderSideBid, Price: trade.EntryPrice})
+		if !trade.ExitTime.IsZero() {
+			triggers = append(triggers, Trigger{Time: trade.ExitTime.Add(interval), Side: model.OrderSideAsk, Price: trade.ExitPrice})
+		}
+	}
+	return triggers, nil
+}
+
+// CompareLive pairs each trigger, in order, with the earliest unmatched fill of
+// the same side between the trigger candle's open and tolerance after its close
+func CompareLive(triggers []Trigger, fills []LiveFill, interval, tolerance time.Duration) *DivergenceReport {
+	report := &DivergenceReport{
+		Matched:    []MatchedTrigger{},
+		Missed:     []Trigger{},
+		Unexpected: []LiveFill{},
+	}
+	used := make([]bool, len(fills))
+
+	var totalLatency time.Duration
+	for _, trigger := range triggers {
+		earliest, latest := trigger.Time.Add(-interval), trigger.Time.Add(tolerance)
+
+		match := -1
+		for i, fill := range fills {
+			if used[i] || fill.Side != trigger.Side || fill.Time.Before(earliest) || fill.Time.After(latest) {
+				continue
+			}
+			match = i
+			break
+		}
+		if match < 0 {
+			report.Missed = append(report.Missed, trigger)
+			continue
+		}
+		used[match] = true
+
+		fill := fills[match]
+		slippage := (fill.Price - trigger.Price) / trigger.Price * 10000
+		if trigger.Side == model.OrderSideAsk {
+			slippage = -slippage
+		}
+		latency := fill.Time.Sub(trigger.Time)
+
+		report.Matched = append(report.Matched, MatchedTrigger{Expected: trigger, Live: fill, SlippageBPS: slippage, Latency: latency})
+		report.AvgSlippageBPS += slippage
+		totalLatency += latency
+	}
+
+	for i, fill := range fills {
+		if !used[i] {
+			report.Unexpected = append(report.Unexpected, fill)
+		}
+	}
+
+	if n := len(report.Matched); n > 0 {
+		report.AvgSlippageBPS /= float64(n)
+		report.AvgLatency = totalLatency / time.Duration(n)
+	}
+	if len(triggers) > 0 {
+		report.MatchRate = float64(len(report.Matched)) / float64(len(triggers))
+	}
+	return report
+}
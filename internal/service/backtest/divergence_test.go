@@ -0,0 +1,61 @@
+package backtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+func TestBacktester_ExpectedTriggers(t *testing.T) {
+	backtester := NewBacktester(&stubCandles{candles: hourlyCandles(100, 150, 200, 180, 185, 199)})
+
+	triggers, err := backtester.ExpectedTriggers(context.Background(), Config{
+		Market:   "KRW-BTC",
+		Interval: model.CandleInterval1h,
+		From:     testStart,
+		To:       testStart.Add(6 * time.Hour),
+		Strategy: "trailing_stop",
+		Params:   Params{"trail_percent": 10},
+		Slippage: SlippageModel{Model: SlippageFixed, BPS: 50},
+	})
+	require.NoError(t, err)
+
+	// Entry, exit and the re-entry still open at the end, at raw closes
+	assert.Equal(t, []Trigger{
+		{Time: testStart.Add(time.Hour), Side: model.OrderSideBid, Price: 100},
+		{Time: testStart.Add(4 * time.Hour), Side: model.OrderSideAsk, Price: 180},
+		{Time: testStart.Add(6 * time.Hour), Side: model.OrderSideBid, Price: 199},
+	}, triggers)
+}
+
+func TestCompareLive(t *testing.T) {
+	triggers := []Trigger{
+		{Time: testStart.Add(time.Hour), Side: model.OrderSideBid, Price: 100},
+		{Time: testStart.Add(4 * time.Hour), Side: model.OrderSideAsk, Price: 200},
+		{Time: testStart.Add(6 * time.Hour), Side: model.OrderSideBid, Price: 150},
+	}
+	fills := []LiveFill{
+		{OrderID: uuid.New(), Time: testStart.Add(time.Hour + 2*time.Minute), Side: model.OrderSideBid, Price: 100.5},
+		{OrderID: uuid.New(), Time: testStart.Add(3*time.Hour + 30*time.Minute), Side: model.OrderSideAsk, Price: 199},
+		{OrderID: uuid.New(), Time: testStart.Add(5 * time.Hour), Side: model.OrderSideAsk, Price: 170},
+	}
+
+	report := CompareLive(triggers, fills, time.Hour, 5*time.Minute)
+
+	require.Len(t, report.Matched, 2)
+	assert.InDelta(t, 50, report.Matched[0].SlippageBPS, 1e-9)
+	assert.Equal(t, 2*time.Minute, report.Matched[0].Latency)
+	assert.InDelta(t, 50, report.Matched[1].SlippageBPS, 1e-9, "selling lower is adverse")
+	assert.Equal(t, -30*time.Minute, report.Matched[1].Latency, "intrabar exits trade before the close")
+
+	assert.Equal(t, []Trigger{triggers[2]}, report.Missed)
+	assert.Equal(t, []LiveFill{fills[2]}, report.Unexpected)
+	assert.InDelta(t, 2.0/3, report.MatchRate, 1e-9)
+	assert.InDelta(t, 50, report.AvgSlippageBPS, 1e-9)
+	assert.Equal(t, -14*time.Minute, report.AvgLatency)
+}
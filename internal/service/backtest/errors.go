@@ -0,0 +1,21 @@
+package backtest
+
+var (
+	ErrUnknownStrategy = &BacktestError{message: "unknown strategy"}
+	ErrInvalidParams   = &BacktestError{message: "invalid strategy parameters"}
+	ErrInvalidRange    = &BacktestError{message: "invalid date range"}
+	ErrInvalidInterval = &BacktestError{message: "unsupported candle interval"}
+	ErrInvalidCosts    = &BacktestError{message: "invalid fee or slippage model"}
+	ErrInvalidSizing   = &BacktestError{message: "invalid position sizing"}
+	ErrNoCandles       = &BacktestError{message: "no candles in date range"}
+	ErrTooManyRuns     = &BacktestError{message: "parameter grid is too large"}
+)
+
+// BacktestError represents a backtest configuration error
+type BacktestError struct {
+	message string
+}
+
+func (e *BacktestError) Error() string {
+	return e.message
+}
@@ -0,0 +1,144 @@
+package backtest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/queue"
+)
+
+// Kinds of queued backtest jobs
+const (
+	RunJob         = "backtest.run"
+	OptimizeJob    = "backtest.optimize"
+	WalkForwardJob = "backtest.walk_forward"
+	// jobAttempts bounds retries of a backtest whose candles failed to load
+	jobAttempts = 3
+)
+
+// Jobs runs backtests on the durable job queue, so long optimizations and
+// walk-forward analyses outlive the request that started them. Each job's
+// report is saved as its JSON result.
+type Jobs struct {
+	backtester *Backtester
+	runs       repository.BacktestRepository // Optional; single runs aren't stored when nil
+	queue      *queue.Queue
+}
+
+// NewJobs registers the backtest job handlers with q
+func NewJobs(backtester *Backtester, runs repository.BacktestRepository, q *queue.Queue) *Jobs {
+	j := &Jobs{backtester: backtester, runs: runs, queue: q}
+	q.Handle(RunJob, j.runRun)
+	q.Handle(OptimizeJob, j.runOptimize)
+	q.Handle(WalkForwardJob, j.runWalkForward)
+	return j
+}
+
+// EnqueueRun queues a single run for the user
+func (j *Jobs) EnqueueRun(ctx context.Context, userID uuid.UUID, cfg Config) (*model.QueuedJob, error) {
+	return j.queue.EnqueueFor(ctx, userID, RunJob, cfg, jobAttempts)
+}
+
+// EnqueueOptimize queues a grid search for the user
+func (j *Jobs) EnqueueOptimize(ctx context.Context, userID uuid.UUID, cfg OptimizeConfig) (*model.QueuedJob, error) {
+	return j.queue.EnqueueFor(ctx, userID, OptimizeJob, cfg, jobAttempts)
+}
+
+// EnqueueWalkForward queues a walk-forward analysis for the user
+func (j *Jobs) EnqueueWalkForward(ctx context.Context, userID uuid.UUID, cfg WalkForwardConfig) (*model.QueuedJob, error) {
+	return j.queue.EnqueueFor(ctx, userID, WalkForwardJob, cfg, jobAttempts)
+}
+
+// runRun runs a single backtest and stores it as the user's run
+func (j *Jobs) runRun(ctx context.Context, job *model.QueuedJob) error {
+	var cfg Config
+	if err := decodeJob(job, &cfg); err != nil {
+		return err
+	}
+
+	result, err := j.backtester.Run(ctx, cfg)
+	if err != nil {
+		return jobError(err)
+	}
+	if j.runs != nil {
+		if err := SaveRun(ctx, j.runs, *job.UserID, result); err != nil {
+			return err
+		}
+	}
+	return j.saveResult(ctx, job, result)
+}
+
+func (j *Jobs) runOptimize(ctx context.Context, job *model.QueuedJob) error {
+	var cfg OptimizeConfig
+	if err := decodeJob(job, &cfg); err != nil {
+		return err
+	}
+
+	results, err := j.backtester.Optimize(ctx, cfg)
+	if err != nil {
+		return jobError(err)
+	}
+	return j.saveResult(ctx, job, results)
+}
+
+func (j *Jobs) runWalkForward(ctx context.Context, job *model.QueuedJob) error {
+	var cfg WalkForwardConfig
+	if err := decodeJob(job, &cfg); err != nil {
+		return err
+	}
+
+	result, err := j.backtester.WalkForward(ctx, cfg)
+	if err != nil {
+		return jobError(err)
+	}
+	return j.saveResult(ctx, job, result)
+}
+
+func (j *Jobs) saveResult(ctx context.Context, job *model.QueuedJob, report any) error {
+	data, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s result: %w", job.Kind, err)
+	}
+	return j.queue.SaveResult(ctx, job.ID, "application/json", data)
+}
+
+// SaveRun stores a single run's result as a run record of the user and sets
+// the result's ID to the record's
+func SaveRun(ctx context.Context, runs repository.BacktestRepository, userID uuid.UUID, result *Result) error {
+	run, err := NewRunRecord(userID, result)
+	if err != nil {
+		return err
+	}
+	if err := runs.Create(ctx, run); err != nil {
+		return err
+	}
+	result.ID = run.ID
+	return nil
+}
+
+// decodeJob reads a backtest job's configuration. Jobs without a user or with
+// a payload that doesn't decode can never run.
+func decodeJob(job *model.QueuedJob, cfg any) error {
+	if job.UserID == nil {
+		return queue.Permanent(fmt.Errorf("%s job has no user", job.Kind))
+	}
+	if err := json.Unmarshal(job.Payload, cfg); err != nil {
+		return queue.Permanent(fmt.Errorf("invalid %s payload: %w", job.Kind, err))
+	}
+	return nil
+}
+
+// jobError fails the job at once on configuration errors; anything else,
+// such as failing to load candles, is retried
+func jobError(err error) error {
+	var backtestErr *BacktestError
+	if errors.As(err, &backtestErr) {
+		return queue.Permanent(err)
+	}
+	return err
+}
@@ -0,0 +1,65 @@
+package backtest
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+	"github.com/sungminna/upbit-trading-platform/internal/service/queue"
+)
+
+func TestJobs_RunStoresTheRunAndItsReport(t *testing.T) {
+	store := memory.NewStore()
+	q := queue.NewQueue(store.Jobs())
+	jobs := NewJobs(NewBacktester(&stubCandles{candles: hourlyCandles(100, 150, 200, 180, 185, 198, 210, 150, 120, 160)}), store.Backtests(), q)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	job, err := jobs.EnqueueRun(ctx, userID, Config{
+		Market:   "KRW-BTC",
+		Interval: model.CandleInterval1h,
+		From:     testStart,
+		To:       testStart.Add(10 * time.Hour),
+		Strategy: "trailing_stop",
+		Params:   Params{"trail_percent": 10},
+	})
+	require.NoError(t, err)
+	require.NoError(t, q.RunDue(ctx))
+
+	result, err := q.Result(ctx, userID, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", result.ContentType)
+	var report Result
+	require.NoError(t, json.Unmarshal(result.Data, &report))
+
+	runs, err := store.Backtests().ListByUser(ctx, userID, repository.BacktestFilter{})
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, runs[0].ID, report.ID)
+}
+
+func TestJobs_InvalidConfigFailsAtOnce(t *testing.T) {
+	store := memory.NewStore()
+	q := queue.NewQueue(store.Jobs())
+	jobs := NewJobs(NewBacktester(&stubCandles{}), nil, q)
+	ctx := context.Background()
+
+	job, err := jobs.EnqueueOptimize(ctx, uuid.New(), OptimizeConfig{
+		Base: Config{Market: "KRW-BTC", Interval: model.CandleInterval1h, From: testStart, To: testStart.Add(time.Hour), Strategy: "unknown"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, q.RunDue(ctx))
+
+	stored, err := store.Jobs().GetByID(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.QueuedJobFailed, stored.Status)
+	assert.Equal(t, 1, stored.Attempts)
+	assert.Equal(t, ErrUnknownStrategy.Error(), stored.LastError)
+}
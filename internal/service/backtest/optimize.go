@@ -0,0 +1,199 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/pkg/perf"
+	"github.com/sungminna/upbit-trading-platform/pkg/recovery"
+)
+
+// maxOptimizationRuns bounds the size of a parameter grid
+const maxOptimizationRuns = 1000
+
+// ParamRange is an inclusive range of values for one strategy parameter
+type ParamRange struct {
+	Name string  `json:"name"`
+	Min  float64 `json:"min"`
+	Max  float64 `json:"max"`
+	Step float64 `json:"step"`
+}
+
+// Objective is the metric optimization results are ranked by
+type Objective string
+
+const (
+	ObjectiveSharpe      Objective = "sharpe"
+	ObjectiveSortino     Objective = "sortino"
+	ObjectiveTotalReturn Objective = "total_return"
+	ObjectiveCAGR        Objective = "cagr"
+	ObjectiveMaxDrawdown Objective = "max_drawdown" // Lower is better
+)
+
+// OptimizeConfig describes a parameter grid search. Params in Base that
+// aren't swept are passed to every run unchanged.
+type OptimizeConfig struct {
+	Base      Config       `json:"base"`
+	Ranges    []ParamRange `json:"ranges"`
+	Objective Objective    `json:"objective"`
+	Workers   int          `json:"workers"` // Concurrent simulations; GOMAXPROCS when zero
+}
+
+// OptimizeResult is one point of the grid; Trades and the equity curve are
+// omitted to keep reports small
+type OptimizeResult struct {
+	Rank        int          `json:"rank"`
+	Params      Params       `json:"params"`
+	Metrics     perf.Metrics `json:"metrics"`
+	FinalEquity float64      `json:"final_equity"`
+}
+
+// Optimize backtests every parameter combination in the grid concurrently and
+// returns the results ranked best first. Candles are loaded once and shared.
+func (b *Backtester) Optimize(ctx context.Context, cfg OptimizeConfig) ([]OptimizeResult, error) {
+	grid, err := paramGrid(cfg.Base.Params, cfg.Ranges)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := strategies[cfg.Base.Strategy]; !ok {
+		return nil, ErrUnknownStrategy
+	}
+
+	candles, err := b.loadCandles(ctx, cfg.Base)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := optimize(ctx, cfg, candles, grid)
+	if err != nil {
+		return nil, err
+	}
+
+	rank(results, cfg.Objective)
+	return results, nil
+}
+
+// optimize simulates every grid point with a bounded worker pool.
+// Combinations whose params the strategy rejects are skipped.
+func optimize(ctx context.Context, cfg OptimizeConfig, candles []model.Candle, grid []Params) ([]OptimizeResult, error) {
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	// Results are stored by grid index so ties rank in grid order
+	jobs := make(chan int)
+	slots := make([]*OptimizeResult, len(grid))
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				run := cfg.Base
+				run.Params = grid[index]
+
+				// A combination that panics is skipped like one that fails
+				var result *Result
+				err := recovery.Call("backtest.optimize", func() (err error) {
+					result, err = Simulate(run, candles)
+					return err
+				})
+				if err != nil {
+					continue
+				}
+				slots[index] = &OptimizeResult{Params: run.Params, Metrics: result.Metrics, FinalEquity: result.FinalEquity}
+			}
+		}()
+	}
+
+feed:
+	for index := range grid {
+		select {
+		case jobs <- index:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	results := make([]OptimizeResult, 0, len(grid))
+	for _, result := range slots {
+		if result != nil {
+			results = append(results, *result)
+		}
+	}
+	return results, nil
+}
+
+// rank sorts results best first by the objective and numbers them
+func rank(results []OptimizeResult, objective Objective) {
+	score := func(m perf.Metrics) float64 {
+		switch objective {
+		case ObjectiveSortino:
+			return m.Sortino
+		case ObjectiveTotalReturn:
+			return m.TotalReturn
+		case ObjectiveCAGR:
+			return m.CAGR
+		case ObjectiveMaxDrawdown:
+			return -m.MaxDrawdown
+		}
+		return m.Sharpe
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return score(results[i].Metrics) > score(results[j].Metrics)
+	})
+	for i := range results {
+		results[i].Rank = i + 1
+	}
+}
+
+// paramGrid expands the ranges into every combination, on top of base
+func paramGrid(base Params, ranges []ParamRange) ([]Params, error) {
+	grid := []Params{copyParams(base)}
+
+	for _, r := range ranges {
+		if r.Step <= 0 || r.Max < r.Min {
+			return nil, fmt.Errorf("%w: bad range for %s", ErrInvalidParams, r.Name)
+		}
+
+		// Round to avoid losing the last value to floating point drift
+		steps := int(math.Floor((r.Max-r.Min)/r.Step+1e-9)) + 1
+		if len(grid)*steps > maxOptimizationRuns {
+			return nil, ErrTooManyRuns
+		}
+
+		expanded := make([]Params, 0, len(grid)*steps)
+		for _, params := range grid {
+			for i := 0; i < steps; i++ {
+				p := copyParams(params)
+				p[r.Name] = math.Round((r.Min+float64(i)*r.Step)*1e9) / 1e9
+				expanded = append(expanded, p)
+			}
+		}
+		grid = expanded
+	}
+
+	return grid, nil
+}
+
+func copyParams(params Params) Params {
+	p := make(Params, len(params))
+	for k, v := range params {
+		p[k] = v
+	}
+	return p
+}
@@ -0,0 +1,143 @@
+package backtest
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// Signal is what a strategy wants to do at a candle close
+type Signal int
+
+const (
+	SignalHold Signal = iota
+	SignalBuy
+	SignalSell
+)
+
+// Params are numeric strategy parameters, e.g. {"trail_percent": 5}
+type Params map[string]float64
+
+// Strategy decides entries and exits from closed candles. Strategies are
+// stateful and used by a single simulation, so they need no locking.
+type Strategy interface {
+	OnCandle(candle model.Candle, inPosition bool) Signal
+}
+
+// Factory creates a strategy from its parameters
+type Factory func(params Params) (Strategy, error)
+
+// strategies are the strategies available to backtests, by name
+var strategies = map[string]Factory{
+	"trailing_stop": newTrailingStop,
+	"sma_cross":     newSMACross,
+}
+
+// NewStrategy creates a registered strategy
+func NewStrategy(name string, params Params) (Strategy, error) {
+	factory, ok := strategies[name]
+	if !ok {
+		return nil, ErrUnknownStrategy
+	}
+	return factory(params)
+}
+
+// StrategyNames lists the registered strategies
+func StrategyNames() []string {
+	names := make([]string, 0, len(strategies))
+	for name := range strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// trailingStop is long-only: it exits when the close falls trail_percent
+// below the highest close since entry, and re-enters when the close rises
+// trail_percent above the lowest close since exit
+type trailingStop struct {
+	trail   float64
+	extreme float64 // Highest close while in position, lowest while flat
+}
+
+func newTrailingStop(params Params) (Strategy, error) {
+	trail := params["trail_percent"]
+	if trail <= 0 || trail >= 100 {
+		return nil, fmt.Errorf("%w: trail_percent must be between 0 and 100", ErrInvalidParams)
+	}
+	return &trailingStop{trail: trail / 100}, nil
+}
+
+func (s *trailingStop) OnCandle(candle model.Candle, inPosition bool) Signal {
+	price := candle.ClosePrice
+
+	if s.extreme == 0 {
+		s.extreme = price
+		if !inPosition {
+			return SignalBuy
+		}
+	}
+
+	if inPosition {
+		if price > s.extreme {
+			s.extreme = price
+		}
+		if price <= s.extreme*(1-s.trail) {
+			s.extreme = price
+			return SignalSell
+		}
+		return SignalHold
+	}
+
+	if price < s.extreme {
+		s.extreme = price
+	}
+	if price >= s.extreme*(1+s.trail) {
+		s.extreme = price
+		return SignalBuy
+	}
+	return SignalHold
+}
+
+// smaCross buys when the fast moving average of closes crosses above the slow
+// one and sells when it crosses below
+type smaCross struct {
+	fast, slow int
+	closes     []float64
+}
+
+func newSMACross(params Params) (Strategy, error) {
+	fast, slow := int(params["fast"]), int(params["slow"])
+	if fast < 1 || slow <= fast {
+		return nil, fmt.Errorf("%w: need 1 <= fast < slow", ErrInvalidParams)
+	}
+	return &smaCross{fast: fast, slow: slow}, nil
+}
+
+func (s *smaCross) OnCandle(candle model.Candle, inPosition bool) Signal {
+	s.closes = append(s.closes, candle.ClosePrice)
+	if len(s.closes) > s.slow {
+		s.closes = s.closes[1:]
+	}
+	if len(s.closes) < s.slow {
+		return SignalHold
+	}
+
+	fast, slow := average(s.closes[s.slow-s.fast:]), average(s.closes)
+	switch {
+	case fast > slow && !inPosition:
+		return SignalBuy
+	case fast < slow && inPosition:
+		return SignalSell
+	}
+	return SignalHold
+}
+
+func average(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
@@ -0,0 +1,127 @@
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/pkg/perf"
+)
+
+// Bounds on the work of a single walk-forward analysis
+const (
+	maxWalkForwardWindows = 100
+	maxWalkForwardRuns    = 10000 // Windows × grid size
+)
+
+// WalkForwardConfig describes a walk-forward analysis: parameters are
+// optimized on each training window and then tested, unchanged, on the
+// window that follows it. Windows roll forward by the test length.
+type WalkForwardConfig struct {
+	Optimize     OptimizeConfig `json:"optimize"`      // Base covers the whole date range
+	TrainCandles int            `json:"train_candles"` // Candles per in-sample window
+	TestCandles  int            `json:"test_candles"`  // Candles per out-of-sample window
+}
+
+// WalkForwardWindow compares in-sample and out-of-sample results of one split
+type WalkForwardWindow struct {
+	TrainFrom   time.Time    `json:"train_from"`
+	TrainTo     time.Time    `json:"train_to"`
+	TestFrom    time.Time    `json:"test_from"`
+	TestTo      time.Time    `json:"test_to"`
+	Params      Params       `json:"params"` // Best parameters on the training window
+	InSample    perf.Metrics `json:"in_sample"`
+	OutOfSample perf.Metrics `json:"out_of_sample"`
+}
+
+// WalkForwardResult summarizes every window. Efficiency is the average
+// out-of-sample return divided by the average in-sample return; values well
+// below 1 suggest the optimized parameters are overfitted.
+type WalkForwardResult struct {
+	Windows                      []WalkForwardWindow `json:"windows"`
+	AvgInSampleReturn            float64             `json:"avg_in_sample_return"`
+	AvgOutOfSampleReturn         float64             `json:"avg_out_of_sample_return"`
+	Efficiency                   float64             `json:"efficiency"`
+	OutOfSampleProfitableWindows int                 `json:"out_of_sample_profitable_windows"`
+}
+
+// WalkForward runs a walk-forward analysis over the base date range
+func (b *Backtester) WalkForward(ctx context.Context, cfg WalkForwardConfig) (*WalkForwardResult, error) {
+	if cfg.TrainCandles < 2 || cfg.TestCandles < 2 {
+		return nil, fmt.Errorf("%w: train and test windows need at least 2 candles", ErrInvalidRange)
+	}
+
+	grid, err := paramGrid(cfg.Optimize.Base.Params, cfg.Optimize.Ranges)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := strategies[cfg.Optimize.Base.Strategy]; !ok {
+		return nil, ErrUnknownStrategy
+	}
+
+	candles, err := b.loadCandles(ctx, cfg.Optimize.Base)
+	if err != nil {
+		return nil, err
+	}
+
+	return walkForward(ctx, cfg, candles, grid)
+}
+
+func walkForward(ctx context.Context, cfg WalkForwardConfig, candles []model.Candle, grid []Params) (*WalkForwardResult, error) {
+	windows := (len(candles) - cfg.TrainCandles) / cfg.TestCandles
+	if windows < 1 {
+		return nil, fmt.Errorf("%w: range is shorter than one train and test window", ErrInvalidRange)
+	}
+	if windows > maxWalkForwardWindows || windows*len(grid) > maxWalkForwardRuns {
+		return nil, ErrTooManyRuns
+	}
+
+	result := &WalkForwardResult{}
+	for w := 0; w < windows; w++ {
+		start := w * cfg.TestCandles
+		train := candles[start : start+cfg.TrainCandles]
+		test := candles[start+cfg.TrainCandles : start+cfg.TrainCandles+cfg.TestCandles]
+
+		ranked, err := optimize(ctx, cfg.Optimize, train, grid)
+		if err != nil {
+			return nil, err
+		}
+		if len(ranked) == 0 {
+			return nil, fmt.Errorf("%w: no valid parameter combination", ErrInvalidParams)
+		}
+		rank(ranked, cfg.Optimize.Objective)
+		best := ranked[0]
+
+		run := cfg.Optimize.Base
+		run.Params = best.Params
+		outOfSample, err := Simulate(run, test)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Windows = append(result.Windows, WalkForwardWindow{
+			TrainFrom:   train[0].Timestamp,
+			TrainTo:     train[len(train)-1].Timestamp,
+			TestFrom:    test[0].Timestamp,
+			TestTo:      test[len(test)-1].Timestamp,
+			Params:      best.Params,
+			InSample:    best.Metrics,
+			OutOfSample: outOfSample.Metrics,
+		})
+
+		result.AvgInSampleReturn += best.Metrics.TotalReturn
+		result.AvgOutOfSampleReturn += outOfSample.Metrics.TotalReturn
+		if outOfSample.Metrics.TotalReturn > 0 {
+			result.OutOfSampleProfitableWindows++
+		}
+	}
+
+	result.AvgInSampleReturn /= float64(windows)
+	result.AvgOutOfSampleReturn /= float64(windows)
+	if result.AvgInSampleReturn != 0 {
+		result.Efficiency = result.AvgOutOfSampleReturn / result.AvgInSampleReturn
+	}
+
+	return result, nil
+}
@@ -0,0 +1,168 @@
+// Package balance keeps a cached copy of every user's exchange balances
+package balance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/gateway"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/scheduler"
+	"github.com/sungminna/upbit-trading-platform/pkg/cache"
+)
+
+const (
+	defaultSyncInterval = time.Minute
+	// cacheTTL outlives a few sync intervals so a slow sync doesn't empty the cache
+	cacheTTL = 5 * time.Minute
+)
+
+// Service syncs users' Upbit balances into the cache. Balances are refreshed
+// periodically for every user with an active API key, and on demand when a
+// user's cached balances are missing or invalidated.
+type Service struct {
+	apiKeys   repository.UserAPIKeyRepository
+	orders    repository.OrderRepository // Optional; enables Available
+	newClient gateway.ExchangeClientFactory
+	cache     cache.Cache
+	interval  time.Duration
+}
+
+// NewService creates a new balance sync service
+func NewService(apiKeys repository.UserAPIKeyRepository, newClient gateway.ExchangeClientFactory, store cache.Cache) *Service {
+	return &Service{
+		apiKeys:   apiKeys,
+		newClient: newClient,
+		cache:     store,
+		interval:  defaultSyncInterval,
+	}
+}
+
+// WithOrders enables computing available balances, which take the user's
+// open orders into account
+func (s *Service) WithOrders(orders repository.OrderRepository) *Service {
+	s.orders = orders
+	return s
+}
+
+// Job returns the job refreshing every user's balances
+func (s *Service) Job() scheduler.Job {
+	return scheduler.Job{
+		Name:     "balance-sync",
+		Schedule: scheduler.Every(s.interval),
+		Run: func(ctx context.Context, at time.Time) error {
+			return s.SyncAll(ctx)
+		},
+	}
+}
+
+// SyncAll refreshes the balances of every user with an active API key. A
+// failure for one user doesn't stop the others; all failures are returned together.
+func (s *Service) SyncAll(ctx context.Context) error {
+	userIDs, err := s.apiKeys.ListActiveUserIDs(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, userID := range userIDs {
+		if _, err := s.Sync(ctx, userID); err != nil {
+			errs = append(errs, fmt.Errorf("user %s: %w", userID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Sync fetches a user's balances from the exchange and caches them
+func (s *Service) Sync(ctx context.Context, userID uuid.UUID) (*model.AccountBalances, error) {
+	key, err := s.apiKeys.GetActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load API key: %w", err)
+	}
+
+	accounts, err := s.newClient(key.AccessKey, key.SecretKey).GetAccounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get accounts: %w", err)
+	}
+
+	balances := &model.AccountBalances{
+		UserID:   userID,
+		Balances: make([]model.Balance, 0, len(accounts)),
+		SyncedAt: time.Now(),
+	}
+	for _, account := range accounts {
+		balances.Balances = append(balances.Balances, model.Balance{
+			Currency:    account.Currency,
+			Balance:     parseFloat(account.Balance),
+			Locked:      parseFloat(account.Locked),
+			AvgBuyPrice: parseFloat(account.AvgBuyPrice),
+		})
+	}
+
+	data, err := json.Marshal(balances)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cache.Set(ctx, cacheKey(userID), data, cacheTTL); err != nil {
+		log.Printf("Error caching balances of user %s: %v", userID, err)
+	}
+	return balances, nil
+}
+
+// Balances returns a user's cached balances, syncing them on a cache miss
+func (s *Service) Balances(ctx context.Context, userID uuid.UUID) (*model.AccountBalances, error) {
+	data, err := s.cache.Get(ctx, cacheKey(userID))
+	if err == nil {
+		var balances model.AccountBalances
+		if err := json.Unmarshal(data, &balances); err == nil {
+			return &balances, nil
+		}
+	} else if err != cache.ErrCacheMiss {
+		log.Printf("Error reading cached balances of user %s: %v", userID, err)
+	}
+
+	return s.Sync(ctx, userID)
+}
+
+// Available returns what the user can still commit of each currency: the
+// cached exchange balances less the funds of orders accepted but not yet
+// submitted, with the locked funds attributed to the platform's open orders
+func (s *Service) Available(ctx context.Context, userID uuid.UUID) (*model.AvailableBalances, error) {
+	if s.orders == nil {
+		return nil, errors.New("available balances need the order repository")
+	}
+
+	balances, err := s.Balances(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	orders, err := s.orders.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders: %w", err)
+	}
+
+	return model.NewAvailableBalances(balances, orders), nil
+}
+
+// Invalidate drops a user's cached balances, e.g. after an order changed them
+func (s *Service) Invalidate(ctx context.Context, userID uuid.UUID) {
+	if err := s.cache.Delete(ctx, cacheKey(userID)); err != nil {
+		log.Printf("Error invalidating balances of user %s: %v", userID, err)
+	}
+}
+
+func cacheKey(userID uuid.UUID) string {
+	return "balances:" + userID.String()
+}
+
+func parseFloat(s string) float64 {
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
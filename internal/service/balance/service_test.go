@@ -0,0 +1,99 @@
+package balance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/gateway"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/exchange"
+	"github.com/sungminna/upbit-trading-platform/pkg/cache"
+)
+
+// stubExchange serves account balances and counts requests
+type stubExchange struct {
+	gateway.ExchangeAPI
+	accounts []exchange.Account
+	calls    int
+}
+
+func (e *stubExchange) GetAccounts(ctx context.Context) ([]exchange.Account, error) {
+	e.calls++
+	return e.accounts, nil
+}
+
+func TestService_BalancesAreCachedUntilInvalidated(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	userID := uuid.New()
+	require.NoError(t, store.APIKeys().Create(ctx, model.NewUserAPIKey(userID, "access", "secret", "", time.Now())))
+
+	upbit := &stubExchange{accounts: []exchange.Account{
+		{Currency: "KRW", Balance: "500000", Locked: "100000", AvgBuyPrice: "0"},
+		{Currency: "BTC", Balance: "0.01", Locked: "0", AvgBuyPrice: "90000000"},
+	}}
+	service := NewService(store.APIKeys(), func(accessKey, secretKey string) gateway.ExchangeAPI {
+		return upbit
+	}, cache.NewMemoryCache())
+
+	balances, err := service.Balances(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, 500000.0, balances.Free("KRW"))
+	assert.Equal(t, 0.01, balances.Free("BTC"))
+	assert.Zero(t, balances.Free("ETH"))
+
+	_, err = service.Balances(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, upbit.calls, "second read is served from the cache")
+
+	upbit.accounts[0].Balance = "400000"
+	service.Invalidate(ctx, userID)
+	balances, err = service.Balances(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, 400000.0, balances.Free("KRW"))
+	assert.Equal(t, 2, upbit.calls)
+}
+
+func TestService_Available(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	userID := uuid.New()
+	require.NoError(t, store.APIKeys().Create(ctx, model.NewUserAPIKey(userID, "access", "secret", "", time.Now())))
+
+	upbit := &stubExchange{accounts: []exchange.Account{
+		{Currency: "KRW", Balance: "500000", Locked: "300000", AvgBuyPrice: "0"},
+		{Currency: "BTC", Balance: "0.01", Locked: "0", AvgBuyPrice: "90000000"},
+	}}
+	service := NewService(store.APIKeys(), func(accessKey, secretKey string) gateway.ExchangeAPI {
+		return upbit
+	}, cache.NewMemoryCache()).WithOrders(store.Orders())
+
+	price := 100000.0
+	resting := model.NewOrder(userID, "KRW-BTC", model.OrderSideBid, model.OrderTypeLimit, 2, &price, time.Now())
+	resting.Status = model.OrderStatusSubmitted
+	require.NoError(t, store.Orders().Create(ctx, resting))
+	require.NoError(t, store.Orders().Create(ctx, model.NewOrder(userID, "KRW-BTC", model.OrderSideBid, model.OrderTypeLimit, 1, &price, time.Now())))
+	require.NoError(t, store.Orders().Create(ctx, model.NewOrder(userID, "KRW-ETH", model.OrderSideAsk, model.OrderTypeLimit, 3, &price, time.Now())))
+
+	available, err := service.Available(ctx, userID)
+	require.NoError(t, err)
+
+	krw := available.Balances[0]
+	assert.Equal(t, "KRW", krw.Currency)
+	assert.Equal(t, 300000.0, krw.Locked)
+	assert.InDelta(t, 200100, krw.LockedByPlatform, 1e-6, "the rest is locked by orders placed elsewhere")
+	assert.InDelta(t, 100050, krw.Reserved, 1e-6)
+	assert.InDelta(t, 399950, krw.Available, 1e-6)
+	assert.Equal(t, 2, krw.OpenOrders)
+	assert.Equal(t, 0.01, available.Available("BTC"))
+
+	eth := available.Balances[2]
+	assert.Equal(t, "ETH", eth.Currency)
+	assert.Equal(t, 3.0, eth.Reserved)
+	assert.Zero(t, eth.Available)
+}
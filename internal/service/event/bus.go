@@ -0,0 +1,54 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// All subscribes a handler to every event type
+const All = "*"
+
+// Handler handles a published event
+type Handler func(ctx context.Context, event *model.OutboxEvent) error
+
+// Bus is an in-process publish/subscribe event bus
+type Bus struct {
+	handlers map[string][]Handler
+	mu       sync.RWMutex
+}
+
+// NewBus creates a new event bus
+func NewBus() *Bus {
+	return &Bus{
+		handlers: make(map[string][]Handler),
+	}
+}
+
+// Subscribe registers a handler for an event type, or for all events with All
+func (b *Bus) Subscribe(eventType string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+}
+
+// Publish delivers an event to all matching handlers. Every handler is invoked
+// even if an earlier one fails; the returned error joins all handler errors.
+func (b *Bus) Publish(ctx context.Context, event *model.OutboxEvent) error {
+	b.mu.RLock()
+	handlers := make([]Handler, 0, len(b.handlers[event.EventType])+len(b.handlers[All]))
+	handlers = append(handlers, b.handlers[event.EventType]...)
+	handlers = append(handlers, b.handlers[All]...)
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, handler := range handlers {
+		if err := handler(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
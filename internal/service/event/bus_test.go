@@ -0,0 +1,49 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+func TestBus_Publish(t *testing.T) {
+	bus := NewBus()
+
+	var filled, all int
+	bus.Subscribe(model.EventOrderFilled, func(ctx context.Context, event *model.OutboxEvent) error {
+		filled++
+		return nil
+	})
+	bus.Subscribe(All, func(ctx context.Context, event *model.OutboxEvent) error {
+		all++
+		return nil
+	})
+
+	ctx := context.Background()
+	assert.NoError(t, bus.Publish(ctx, &model.OutboxEvent{ID: uuid.New(), EventType: model.EventOrderFilled}))
+	assert.NoError(t, bus.Publish(ctx, &model.OutboxEvent{ID: uuid.New(), EventType: model.EventOrderCancelled}))
+
+	assert.Equal(t, 1, filled)
+	assert.Equal(t, 2, all)
+}
+
+func TestBus_PublishInvokesAllHandlersOnError(t *testing.T) {
+	bus := NewBus()
+
+	called := false
+	bus.Subscribe(model.EventOrderFailed, func(ctx context.Context, event *model.OutboxEvent) error {
+		return errors.New("delivery failed")
+	})
+	bus.Subscribe(model.EventOrderFailed, func(ctx context.Context, event *model.OutboxEvent) error {
+		called = true
+		return nil
+	})
+
+	err := bus.Publish(context.Background(), &model.OutboxEvent{ID: uuid.New(), EventType: model.EventOrderFailed})
+	assert.Error(t, err)
+	assert.True(t, called)
+}
@@ -0,0 +1,236 @@
+// Package export writes a user's trading history as CSV
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/report"
+)
+
+// flushEvery is how many rows are buffered before they are written out
+const flushEvery = 100
+
+// Service exports trading history
+type Service struct {
+	orders     repository.OrderRepository
+	executions repository.OrderExecutionRepository
+	reports    *report.Service
+	candles    CandleSource
+}
+
+// CandleSource streams market candles, oldest first
+type CandleSource interface {
+	StreamCandleRange(ctx context.Context, market string, interval model.CandleInterval, from, to time.Time, fn func([]model.Candle) error) error
+}
+
+// NewService creates a new export service
+func NewService(orders repository.OrderRepository, executions repository.OrderExecutionRepository, reports *report.Service) *Service {
+	return &Service{
+		orders:     orders,
+		executions: executions,
+		reports:    reports,
+	}
+}
+
+// WithCandles enables candle exports
+func (s *Service) WithCandles(candles CandleSource) *Service {
+	s.candles = candles
+	return s
+}
+
+// Candles writes the market's candles starting in [from, to), oldest first.
+// Candles are written page by page as they are fetched, so a long range is
+// never held in memory.
+func (s *Service) Candles(ctx context.Context, w io.Writer, market string, interval model.CandleInterval, from, to time.Time) error {
+	out := newWriter(w)
+	out.write("timestamp", "market", "interval", "open", "high", "low", "close", "volume", "acc_trade_price")
+	err := s.candles.StreamCandleRange(ctx, market, interval, from, to, func(candles []model.Candle) error {
+		for _, c := range candles {
+			out.write(formatTime(c.Timestamp), c.Market, string(interval), formatFloat(c.OpenPrice), formatFloat(c.HighPrice),
+				formatFloat(c.LowPrice), formatFloat(c.ClosePrice), formatFloat(c.Volume), formatFloat(c.AccTradePrice))
+		}
+		return out.err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to stream candles: %w", err)
+	}
+	return out.close()
+}
+
+// Orders writes the user's orders created in [from, to), oldest first
+func (s *Service) Orders(ctx context.Context, w io.Writer, userID uuid.UUID, from, to time.Time) error {
+	orders, err := s.listOrders(ctx, userID, to)
+	if err != nil {
+		return err
+	}
+
+	out := newWriter(w)
+	out.write("id", "created_at", "market", "side", "type", "price", "quantity", "executed_quantity", "status", "position_id", "exchange_order_id", "filled_at", "source", "source_id", "strategy_id", "tags", "notes")
+	for _, o := range orders {
+		if o.CreatedAt.Before(from) {
+			continue
+		}
+		var price, positionID, exchangeOrderID, filledAt, sourceID, strategyID string
+		if o.Price != nil {
+			price = formatFloat(*o.Price)
+		}
+		if o.PositionID != nil {
+			positionID = o.PositionID.String()
+		}
+		if o.ExchangeOrderID != nil {
+			exchangeOrderID = *o.ExchangeOrderID
+		}
+		if o.FilledAt != nil {
+			filledAt = formatTime(*o.FilledAt)
+		}
+		if o.SourceID != nil {
+			sourceID = o.SourceID.String()
+		}
+		if o.StrategyID != nil {
+			strategyID = o.StrategyID.String()
+		}
+		out.write(o.ID.String(), formatTime(o.CreatedAt), o.Market, string(o.Side), string(o.Type), price,
+			formatFloat(o.Quantity), formatFloat(o.ExecutedQuantity), string(o.Status), positionID, exchangeOrderID, filledAt, string(o.Source), sourceID, strategyID,
+			strings.Join(o.Tags, " "), o.Notes)
+	}
+	return out.close()
+}
+
+// Executions writes the fills of the user's orders made in [from, to),
+// oldest first, with the order's market and side
+func (s *Service) Executions(ctx context.Context, w io.Writer, userID uuid.UUID, from, to time.Time) error {
+	orders, err := s.listOrders(ctx, userID, to)
+	if err != nil {
+		return err
+	}
+
+	type row struct {
+		order     *model.Order
+		execution *model.OrderExecution
+	}
+	var rows []row
+	for _, o := range orders {
+		if o.ExecutedQuantity == 0 {
+			continue
+		}
+		executions, err := s.executions.ListByOrder(ctx, o.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list executions: %w", err)
+		}
+		for _, e := range executions {
+			if !e.CreatedAt.Before(from) && e.CreatedAt.Before(to) {
+				rows = append(rows, row{order: o, execution: e})
+			}
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].execution.CreatedAt.Before(rows[j].execution.CreatedAt)
+	})
+
+	out := newWriter(w)
+	out.write("id", "executed_at", "order_id", "market", "side", "price", "quantity", "total", "fee", "source")
+	for _, r := range rows {
+		e := r.execution
+		out.write(e.ID.String(), formatTime(e.CreatedAt), r.order.ID.String(), r.order.Market, string(r.order.Side),
+			formatFloat(e.Price), formatFloat(e.Quantity), formatFloat(e.Total), formatFloat(e.Fee), string(r.order.Source))
+	}
+	return out.close()
+}
+
+// Trades writes the user's round trips closed in [from, to), oldest first
+func (s *Service) Trades(ctx context.Context, w io.Writer, userID uuid.UUID, from, to time.Time) error {
+	trades, err := s.reports.Trades(ctx, userID, report.TradeFilter{From: from, To: to})
+	if err != nil {
+		return err
+	}
+
+	out := newWriter(w)
+	out.write("position_id", "market", "entry_time", "exit_time", "holding_seconds", "quantity", "entry_price", "exit_price", "gross_pnl", "fees", "net_pnl", "return_pct", "closed_by")
+	for i := len(trades) - 1; i >= 0; i-- {
+		t := trades[i]
+		out.write(t.PositionID.String(), t.Market, formatTime(t.EntryTime), formatTime(t.ExitTime),
+			strconv.FormatInt(int64(t.HoldingTime.Seconds()), 10), formatFloat(t.Quantity), formatFloat(t.EntryPrice),
+			formatFloat(t.ExitPrice), formatFloat(t.GrossPnL), formatFloat(t.Fees), formatFloat(t.NetPnL),
+			formatFloat(t.Return), t.ClosedBy)
+	}
+	return out.close()
+}
+
+// listOrders returns the user's orders created before to, oldest first
+func (s *Service) listOrders(ctx context.Context, userID uuid.UUID, to time.Time) ([]*model.Order, error) {
+	orders, err := s.orders.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders: %w", err)
+	}
+
+	var listed []*model.Order
+	for _, o := range orders {
+		if o.CreatedAt.Before(to) {
+			listed = append(listed, o)
+		}
+	}
+	sort.SliceStable(listed, func(i, j int) bool {
+		return listed[i].CreatedAt.Before(listed[j].CreatedAt)
+	})
+	return listed, nil
+}
+
+// writer writes CSV rows, flushing them to the client every flushEvery rows
+// so large exports stream instead of buffering
+type writer struct {
+	csv     *csv.Writer
+	flusher http.Flusher
+	rows    int
+	err     error
+}
+
+func newWriter(w io.Writer) *writer {
+	flusher, _ := w.(http.Flusher)
+	return &writer{csv: csv.NewWriter(w), flusher: flusher}
+}
+
+func (w *writer) write(record ...string) {
+	if w.err != nil {
+		return
+	}
+	if w.err = w.csv.Write(record); w.err != nil {
+		return
+	}
+	if w.rows++; w.rows%flushEvery == 0 {
+		w.flush()
+	}
+}
+
+func (w *writer) flush() {
+	w.csv.Flush()
+	if w.err == nil {
+		w.err = w.csv.Error()
+	}
+	if w.flusher != nil {
+		w.flusher.Flush()
+	}
+}
+
+func (w *writer) close() error {
+	w.flush()
+	return w.err
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
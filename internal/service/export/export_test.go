@@ -0,0 +1,101 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+	"github.com/sungminna/upbit-trading-platform/internal/service/report"
+)
+
+func TestService_Export(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	userID := uuid.New()
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	position := model.NewPosition(userID, "KRW-BTC", model.PositionSideLong, 100, 1, time.Now())
+
+	fill := func(side model.OrderSide, price float64, at time.Time) *model.Order {
+		order := model.NewOrder(userID, "KRW-BTC", side, model.OrderTypeLimit, 1, &price, time.Now())
+		order.PositionID = &position.ID
+		order.Status = model.OrderStatusFilled
+		order.ExecutedQuantity = 1
+		order.CreatedAt = at
+		require.NoError(t, store.Orders().Create(ctx, order))
+		execution := model.NewOrderExecution(order.ID, price, 1, price*0.0005, time.Now())
+		execution.CreatedAt = at
+		require.NoError(t, store.Executions().Create(ctx, execution))
+		return order
+	}
+	buy := fill(model.OrderSideBid, 100, start)
+	sell := fill(model.OrderSideAsk, 120, start.AddDate(0, 0, 1))
+	fill(model.OrderSideBid, 110, start.AddDate(0, 1, 0)) // Outside the range
+
+	service := NewService(store.Orders(), store.Executions(), report.NewService(store.Orders(), store.Executions()))
+	from, to := start, start.AddDate(0, 0, 7)
+
+	read := func(write func(buf *bytes.Buffer) error) [][]string {
+		var buf bytes.Buffer
+		require.NoError(t, write(&buf))
+		records, err := csv.NewReader(&buf).ReadAll()
+		require.NoError(t, err)
+		return records
+	}
+
+	orders := read(func(buf *bytes.Buffer) error { return service.Orders(ctx, buf, userID, from, to) })
+	require.Len(t, orders, 3)
+	assert.Equal(t, "id", orders[0][0])
+	assert.Equal(t, buy.ID.String(), orders[1][0], "oldest first")
+	assert.Equal(t, []string{"ask", "limit", "120"}, orders[2][3:6])
+
+	executions := read(func(buf *bytes.Buffer) error { return service.Executions(ctx, buf, userID, from, to) })
+	require.Len(t, executions, 3)
+	assert.Equal(t, sell.ID.String(), executions[2][2])
+	assert.Equal(t, "2025-01-02T00:00:00Z", executions[2][1])
+	assert.Equal(t, "0.06", executions[2][8])
+
+	trades := read(func(buf *bytes.Buffer) error { return service.Trades(ctx, buf, userID, from, to) })
+	require.Len(t, trades, 2)
+	assert.Equal(t, "86400", trades[1][4])
+	assert.Equal(t, "20", trades[1][8])
+	assert.Equal(t, "19.89", trades[1][10])
+}
+
+// stubCandles passes its candles one page at a time
+type stubCandles struct {
+	pages [][]model.Candle
+}
+
+func (s *stubCandles) StreamCandleRange(ctx context.Context, market string, interval model.CandleInterval, from, to time.Time, fn func([]model.Candle) error) error {
+	for _, page := range s.pages {
+		if err := fn(page); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestService_Candles(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	candle := func(hour int, close float64) model.Candle {
+		return model.Candle{Market: "KRW-BTC", Timestamp: start.Add(time.Duration(hour) * time.Hour), OpenPrice: 100, HighPrice: 130, LowPrice: 90, ClosePrice: close, Volume: 1.5}
+	}
+	source := &stubCandles{pages: [][]model.Candle{{candle(0, 110), candle(1, 120)}, {candle(2, 115)}}}
+	service := NewService(nil, nil, nil).WithCandles(source)
+
+	var buf bytes.Buffer
+	require.NoError(t, service.Candles(context.Background(), &buf, "KRW-BTC", model.CandleInterval1h, start, start.Add(3*time.Hour)))
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+
+	require.Len(t, records, 4)
+	assert.Equal(t, "timestamp", records[0][0])
+	assert.Equal(t, []string{"2025-01-01T02:00:00Z", "KRW-BTC", "1h", "100", "130", "90", "115", "1.5", "0"}, records[3])
+}
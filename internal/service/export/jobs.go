@@ -0,0 +1,103 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/service/queue"
+)
+
+// Kinds of queued export jobs
+const (
+	OrdersJob     = "export.orders"
+	ExecutionsJob = "export.executions"
+	TradesJob     = "export.trades"
+	CandlesJob    = "export.candles"
+	// jobAttempts bounds retries of an export whose records failed to load
+	jobAttempts = 3
+	contentType = "text/csv; charset=utf-8"
+)
+
+// historyPayload is the payload of an export of the user's history
+type historyPayload struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// candlesPayload is the payload of a CandlesJob
+type candlesPayload struct {
+	Market   string               `json:"market"`
+	Interval model.CandleInterval `json:"interval"`
+	From     time.Time            `json:"from"`
+	To       time.Time            `json:"to"`
+}
+
+// historyFunc writes one kind of history export
+type historyFunc func(ctx context.Context, w io.Writer, userID uuid.UUID, from, to time.Time) error
+
+// Jobs runs exports on the durable job queue, so exports too large to wait
+// for are saved as the job's CSV result instead of streamed
+type Jobs struct {
+	export *Service
+	queue  *queue.Queue
+}
+
+// NewJobs registers the export job handlers with q
+func NewJobs(export *Service, q *queue.Queue) *Jobs {
+	j := &Jobs{export: export, queue: q}
+	q.Handle(OrdersJob, j.history(export.Orders))
+	q.Handle(ExecutionsJob, j.history(export.Executions))
+	q.Handle(TradesJob, j.history(export.Trades))
+	q.Handle(CandlesJob, j.runCandles)
+	return j
+}
+
+// EnqueueHistory queues an export of the user's history covering [from, to).
+// kind is OrdersJob, ExecutionsJob or TradesJob.
+func (j *Jobs) EnqueueHistory(ctx context.Context, userID uuid.UUID, kind string, from, to time.Time) (*model.QueuedJob, error) {
+	return j.queue.EnqueueFor(ctx, userID, kind, historyPayload{From: from, To: to}, jobAttempts)
+}
+
+// EnqueueCandles queues an export of a market's candles for the user
+func (j *Jobs) EnqueueCandles(ctx context.Context, userID uuid.UUID, market string, interval model.CandleInterval, from, to time.Time) (*model.QueuedJob, error) {
+	payload := candlesPayload{Market: market, Interval: interval, From: from, To: to}
+	return j.queue.EnqueueFor(ctx, userID, CandlesJob, payload, jobAttempts)
+}
+
+// history returns the handler of a history export job
+func (j *Jobs) history(write historyFunc) queue.Handler {
+	return func(ctx context.Context, job *model.QueuedJob) error {
+		if job.UserID == nil {
+			return queue.Permanent(fmt.Errorf("%s job has no user", job.Kind))
+		}
+		var payload historyPayload
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			return queue.Permanent(fmt.Errorf("invalid %s payload: %w", job.Kind, err))
+		}
+
+		var buf bytes.Buffer
+		if err := write(ctx, &buf, *job.UserID, payload.From, payload.To); err != nil {
+			return err
+		}
+		return j.queue.SaveResult(ctx, job.ID, contentType, buf.Bytes())
+	}
+}
+
+func (j *Jobs) runCandles(ctx context.Context, job *model.QueuedJob) error {
+	var payload candlesPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return queue.Permanent(fmt.Errorf("invalid %s payload: %w", job.Kind, err))
+	}
+
+	var buf bytes.Buffer
+	if err := j.export.Candles(ctx, &buf, payload.Market, payload.Interval, payload.From, payload.To); err != nil {
+		return err
+	}
+	return j.queue.SaveResult(ctx, job.ID, contentType, buf.Bytes())
+}
@@ -0,0 +1,36 @@
+package export
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+	"github.com/sungminna/upbit-trading-platform/internal/service/queue"
+)
+
+func TestJobs_SaveTheExportAsTheResult(t *testing.T) {
+	store := memory.NewStore()
+	q := queue.NewQueue(store.Jobs())
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	source := &stubCandles{pages: [][]model.Candle{{{Market: "KRW-BTC", Timestamp: start, ClosePrice: 110}}}}
+	jobs := NewJobs(NewService(store.Orders(), store.Executions(), nil).WithCandles(source), q)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	job, err := jobs.EnqueueCandles(ctx, userID, "KRW-BTC", model.CandleInterval1h, start, start.Add(time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, q.RunDue(ctx))
+
+	result, err := q.Result(ctx, userID, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "text/csv; charset=utf-8", result.ContentType)
+	lines := strings.Split(strings.TrimSpace(string(result.Data)), "\n")
+	require.Len(t, lines, 2)
+	assert.True(t, strings.HasPrefix(lines[1], "2025-01-01T00:00:00Z,KRW-BTC,1h,"))
+}
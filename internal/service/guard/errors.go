@@ -0,0 +1,20 @@
+package guard
+
+var (
+	ErrInvalidGuard    = &GuardError{message: "max_drawdown must be a percent between 0 and 100"}
+	ErrInvalidPriceAge = &GuardError{message: "max_price_age must be a positive number of seconds"}
+	ErrPositionNotOpen = &GuardError{message: "position is not open"}
+	// ErrInvalidConfirmInterval is returned for confirm intervals that can't
+	// be aggregated from prices, or when candle closes aren't configured
+	ErrInvalidConfirmInterval = &GuardError{message: "confirm_interval must be a candle interval from 1s to 1d"}
+	ErrInvalidHistoryWindow   = &GuardError{message: "hours must be between 1 and 720"}
+)
+
+// GuardError represents a drawdown guard validation error
+type GuardError struct {
+	message string
+}
+
+func (e *GuardError) Error() string {
+	return e.message
+}
@@ -0,0 +1,630 @@
+// Package guard force-exits positions whose price falls too far below its peak
+package guard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/notification"
+	"github.com/sungminna/upbit-trading-platform/internal/service/pricefeed"
+	"github.com/sungminna/upbit-trading-platform/internal/service/trading"
+	"github.com/sungminna/upbit-trading-platform/pkg/cache"
+	"github.com/sungminna/upbit-trading-platform/pkg/clock"
+	"github.com/sungminna/upbit-trading-platform/pkg/latency"
+	"github.com/sungminna/upbit-trading-platform/pkg/recovery"
+)
+
+const (
+	// DefaultMaxPriceAge is how many seconds old a price may be, by default,
+	// for a guard to act on it
+	DefaultMaxPriceAge = 30
+	// jobTimeout bounds storing a guard and placing its exit order
+	jobTimeout = 10 * time.Second
+	// jobQueueSize is how many peak updates and exits can wait for the worker
+	jobQueueSize = 1024
+	// MaxHistoryWindow is how far back a guard's history may be asked for
+	MaxHistoryWindow = 30 * 24 * time.Hour
+	// claimTTL keeps other instances from exiting the same guard twice
+	claimTTL = time.Hour
+	claimKey = "drawdown-guard:"
+)
+
+// Exiter places the market sells that exit positions; trading.Engine
+// satisfies it
+type Exiter interface {
+	PlaceOrder(ctx context.Context, userID uuid.UUID, req trading.PlaceOrderRequest) (*model.Order, error)
+}
+
+// AttachOptions configure a guard being attached
+type AttachOptions struct {
+	MaxDrawdown float64 // Percent below the peak
+	// MaxPriceAge is in seconds; zero uses DefaultMaxPriceAge
+	MaxPriceAge int
+	// ConfirmInterval makes the guard trigger only on closes of candles of
+	// that interval; empty acts on every price
+	ConfirmInterval model.CandleInterval
+	// DryRun guards notify the user when they trigger but don't sell
+	DryRun bool
+	// TemplateID is the strategy template the guard is cloned from. Its exit
+	// order is attributed to that strategy.
+	TemplateID *uuid.UUID
+}
+
+// job stores a guard's new peak or, when exit is set, exits its position
+type job struct {
+	guard     *model.DrawdownGuard
+	price     float64
+	priceTime time.Time // Exchange time of price, when known
+	exit      bool
+}
+
+// Service keeps active guards in memory and evaluates them on every price
+// update. A guard whose position falls MaxDrawdown percent below its peak is
+// deactivated and the position is sold at market, whatever other exit rules
+// it has. Prices older than the guard's MaxPriceAge are skipped rather than
+// acted on. Guards with a ConfirmInterval only trigger on candle closes, so a
+// brief dip inside a candle doesn't exit the position.
+type Service struct {
+	guards      repository.DrawdownGuardRepository
+	positions   repository.PositionRepository
+	exiter      Exiter
+	feed        *pricefeed.Feed
+	poller      *pricefeed.Poller                        // Optional; keeps guarded markets polled
+	candles     *pricefeed.Aggregator                    // Optional; enables confirm intervals
+	notifier    notification.Notifier                    // Optional
+	latency     *latency.Recorder                        // Optional
+	updates     repository.DrawdownGuardUpdateRepository // Optional; records trigger level history
+	claims      cache.Cache
+	clock       clock.Clock
+	active      map[string]map[uuid.UUID]*model.DrawdownGuard // By market, then position
+	untrack     map[uuid.UUID]func()
+	unsubscribe func()
+	closes      map[model.CandleInterval]func() // Unsubscribes from candle closes
+	mu          sync.Mutex
+	isRunning   bool
+	jobs        chan job // Processed in order by a single worker
+	done        chan struct{}
+}
+
+// NewService creates a new drawdown guard service. poller may be nil when
+// another component keeps the feed updated.
+func NewService(
+	guards repository.DrawdownGuardRepository,
+	positions repository.PositionRepository,
+	exiter Exiter,
+	feed *pricefeed.Feed,
+	poller *pricefeed.Poller,
+	notifier notification.Notifier,
+	claims cache.Cache,
+) *Service {
+	return &Service{
+		guards:    guards,
+		positions: positions,
+		exiter:    exiter,
+		feed:      feed,
+		poller:    poller,
+		notifier:  notifier,
+		claims:    claims,
+		clock:     clock.Real,
+		active:    make(map[string]map[uuid.UUID]*model.DrawdownGuard),
+		untrack:   make(map[uuid.UUID]func()),
+		closes:    make(map[model.CandleInterval]func()),
+	}
+}
+
+// WithCandleCloses lets guards confirm breaches on the closes of candles
+// aggregated from the feed
+func (s *Service) WithCandleCloses(candles *pricefeed.Aggregator) *Service {
+	s.candles = candles
+	return s
+}
+
+// WithLatency records how long after the price's trade on the exchange guards
+// trigger ("guard.trigger") and their exit orders are placed ("guard.exit")
+func (s *Service) WithLatency(recorder *latency.Recorder) *Service {
+	s.latency = recorder
+	return s
+}
+
+// WithHistory records every attachment, new peak and trigger of guards, so
+// users can see how their trigger levels moved
+func (s *Service) WithHistory(updates repository.DrawdownGuardUpdateRepository) *Service {
+	s.updates = updates
+	return s
+}
+
+// WithClock sets the clock prices are aged and guards triggered by
+func (s *Service) WithClock(c clock.Clock) *Service {
+	s.clock = c
+	return s
+}
+
+// Start loads active guards and starts evaluating them
+func (s *Service) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.isRunning {
+		return nil
+	}
+
+	guards, err := s.guards.ListActive(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load drawdown guards: %w", err)
+	}
+	for _, guard := range guards {
+		s.add(guard)
+	}
+
+	s.jobs = make(chan job, jobQueueSize)
+	s.done = make(chan struct{})
+	go s.work(s.jobs, s.done)
+
+	s.unsubscribe = s.feed.Subscribe(pricefeed.AllMarkets, s.onPrice)
+	s.isRunning = true
+	return nil
+}
+
+// Stop stops evaluating guards and waits for queued exits
+func (s *Service) Stop() {
+	s.mu.Lock()
+	if !s.isRunning {
+		s.mu.Unlock()
+		return
+	}
+	s.unsubscribe()
+	for id, untrack := range s.untrack {
+		untrack()
+		delete(s.untrack, id)
+	}
+	for interval, unsubscribe := range s.closes {
+		unsubscribe()
+		delete(s.closes, interval)
+	}
+	s.active = make(map[string]map[uuid.UUID]*model.DrawdownGuard)
+	s.isRunning = false
+	close(s.jobs)
+	done := s.done
+	s.mu.Unlock()
+
+	<-done
+}
+
+// Attach guards one of the user's open positions, replacing any guard it
+// already has. The peak starts at the higher of the entry price, the latest
+// fresh price and the peak of the replaced guard.
+func (s *Service) Attach(ctx context.Context, userID, positionID uuid.UUID, opts AttachOptions) (*model.DrawdownGuard, error) {
+	if opts.MaxDrawdown <= 0 || opts.MaxDrawdown >= 100 {
+		return nil, ErrInvalidGuard
+	}
+	if opts.MaxPriceAge < 0 {
+		return nil, ErrInvalidPriceAge
+	}
+	if opts.ConfirmInterval != "" && (s.candles == nil || !pricefeed.SupportsInterval(opts.ConfirmInterval)) {
+		return nil, ErrInvalidConfirmInterval
+	}
+	if opts.MaxPriceAge == 0 {
+		opts.MaxPriceAge = DefaultMaxPriceAge
+	}
+
+	position, err := s.positions.GetByID(ctx, positionID)
+	if err != nil {
+		return nil, err
+	}
+	if position.UserID != userID {
+		return nil, repository.ErrNotFound
+	}
+	if position.Status != model.PositionStatusOpen {
+		return nil, ErrPositionNotOpen
+	}
+
+	guard := model.NewDrawdownGuard(position, opts.MaxDrawdown, opts.MaxPriceAge, s.clock.Now())
+	guard.ConfirmInterval = opts.ConfirmInterval
+	guard.DryRun = opts.DryRun
+	guard.TemplateID = opts.TemplateID
+	if latest, ok := s.feed.Fresh(position.Market, guard.PriceAge()); ok {
+		guard.PeakPrice = max(guard.PeakPrice, latest.Price)
+	}
+	if existing, err := s.guards.GetByPosition(ctx, positionID); err == nil && existing.Active {
+		guard.PeakPrice = max(guard.PeakPrice, existing.PeakPrice)
+	}
+
+	if err := s.guards.Save(ctx, guard); err != nil {
+		return nil, err
+	}
+	s.record(ctx, guard, model.DrawdownGuardAttached, guard.PeakPrice, s.clock.Now())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.isRunning {
+		s.remove(guard.Market, guard.PositionID)
+		s.add(guard)
+	}
+	return guard, nil
+}
+
+// Get returns the guard of one of the user's positions
+func (s *Service) Get(ctx context.Context, userID, positionID uuid.UUID) (*model.DrawdownGuard, error) {
+	guard, err := s.guards.GetByPosition(ctx, positionID)
+	if err != nil {
+		return nil, err
+	}
+	if guard.UserID != userID {
+		return nil, repository.ErrNotFound
+	}
+	return guard, nil
+}
+
+// History is how a guard's trigger level moved over a window, and how far
+// the market is from it now
+type History struct {
+	Guard        *model.DrawdownGuard `json:"guard"`
+	Since        time.Time            `json:"since"`
+	TriggerLevel float64              `json:"trigger_level"`
+	Price        *float64             `json:"price,omitempty"`               // Latest fresh price, while the guard is active
+	Distance     *float64             `json:"distance_to_trigger,omitempty"` // Percent the price may fall before the guard triggers
+	// Updates within the window, preceded by the last one before it
+	Updates []*model.DrawdownGuardUpdate `json:"updates"`
+}
+
+// History returns how the trigger level of the guard of one of the user's
+// positions moved over the last window, from its recorded updates
+func (s *Service) History(ctx context.Context, userID, positionID uuid.UUID, window time.Duration) (*History, error) {
+	if window <= 0 || window > MaxHistoryWindow {
+		return nil, ErrInvalidHistoryWindow
+	}
+
+	guard, err := s.Get(ctx, userID, positionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Active guards' latest peaks may not be stored yet
+	s.mu.Lock()
+	if current, ok := s.active[guard.Market][guard.PositionID]; ok && current.CreatedAt.Equal(guard.CreatedAt) {
+		g := *current
+		guard = &g
+	}
+	s.mu.Unlock()
+
+	history := &History{
+		Guard:        guard,
+		Since:        s.clock.Now().Add(-window),
+		TriggerLevel: guard.TriggerLevel(),
+		Updates:      []*model.DrawdownGuardUpdate{},
+	}
+	if guard.Active {
+		if latest, ok := s.feed.Fresh(guard.Market, guard.PriceAge()); ok && latest.Price > 0 {
+			distance := (latest.Price - history.TriggerLevel) / latest.Price * 100
+			history.Price, history.Distance = &latest.Price, &distance
+		}
+	}
+
+	if s.updates != nil {
+		updates, err := s.updates.ListSince(ctx, positionID, history.Since)
+		if err != nil {
+			return nil, err
+		}
+		if updates != nil {
+			history.Updates = updates
+		}
+	}
+	return history, nil
+}
+
+// List returns the user's guards, newest first
+func (s *Service) List(ctx context.Context, userID uuid.UUID) ([]*model.DrawdownGuard, error) {
+	return s.guards.ListByUser(ctx, userID)
+}
+
+// Detach removes the guard of one of the user's positions
+func (s *Service) Detach(ctx context.Context, userID, positionID uuid.UUID) error {
+	guard, err := s.Get(ctx, userID, positionID)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.remove(guard.Market, guard.PositionID)
+	s.mu.Unlock()
+
+	return s.guards.Delete(ctx, positionID)
+}
+
+// add starts evaluating a guard; s.mu must be held
+func (s *Service) add(guard *model.DrawdownGuard) {
+	g := *guard
+	if s.active[g.Market] == nil {
+		s.active[g.Market] = make(map[uuid.UUID]*model.DrawdownGuard)
+	}
+	s.active[g.Market][g.PositionID] = &g
+
+	if s.poller != nil {
+		s.untrack[g.PositionID] = s.poller.Track(g.Market)
+	}
+	if g.ConfirmInterval != "" && s.candles != nil && s.closes[g.ConfirmInterval] == nil {
+		s.closes[g.ConfirmInterval] = s.candles.Subscribe(g.ConfirmInterval, s.onClose)
+	}
+}
+
+// remove stops evaluating a position's guard; s.mu must be held
+func (s *Service) remove(market string, positionID uuid.UUID) {
+	delete(s.active[market], positionID)
+	if len(s.active[market]) == 0 {
+		delete(s.active, market)
+	}
+
+	if untrack, ok := s.untrack[positionID]; ok {
+		untrack()
+		delete(s.untrack, positionID)
+	}
+}
+
+// onPrice raises the peaks of the market's guards and triggers those whose
+// drawdown breached their limit, skipping guards the price is too old for.
+// Storing and exiting happen off the feed's goroutine.
+func (s *Service) onPrice(update pricefeed.PriceUpdate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.isRunning {
+		return
+	}
+
+	now := s.clock.Now()
+	age := update.Age(now)
+	for _, guard := range s.active[update.Market] {
+		if age > guard.PriceAge() {
+			continue
+		}
+
+		peaked, breached := guard.Observe(update.Price, now)
+		if guard.ConfirmInterval != "" {
+			breached = false // Left to the candle close
+		}
+		if breached && s.latency != nil {
+			s.latency.Since("guard.trigger", update.Timestamp, now)
+		}
+		if peaked || breached {
+			s.enqueue(guard, update.Price, update.Timestamp, breached)
+		}
+	}
+}
+
+// onClose triggers the guards confirming on the closed candle's interval
+// whose drawdown at the close breached their limit. Candles that closed
+// longer ago than a guard's MaxPriceAge are skipped.
+func (s *Service) onClose(candle model.Candle) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.isRunning {
+		return
+	}
+
+	age := s.clock.Now().Sub(candle.Timestamp.Add(candle.Interval.Duration()))
+	for _, guard := range s.active[candle.Market] {
+		if guard.ConfirmInterval != candle.Interval || age > guard.PriceAge() {
+			continue
+		}
+		if guard.Breached(candle.ClosePrice) {
+			s.enqueue(guard, candle.ClosePrice, time.Time{}, true)
+		}
+	}
+}
+
+// enqueue queues storing a guard's new peak or, when exit is set, triggers
+// the guard and queues its exit; s.mu must be held
+func (s *Service) enqueue(guard *model.DrawdownGuard, price float64, priceTime time.Time, exit bool) {
+	if exit {
+		guard.Trigger(price, s.clock.Now())
+		s.remove(guard.Market, guard.PositionID)
+	}
+
+	g := *guard
+	select {
+	case s.jobs <- job{guard: &g, price: price, priceTime: priceTime, exit: exit}:
+	default:
+		if exit {
+			log.Printf("Dropping drawdown exit of position %s: queue is full", g.PositionID)
+		}
+	}
+}
+
+// work processes queued jobs in order until jobs is closed
+func (s *Service) work(jobs <-chan job, done chan<- struct{}) {
+	defer close(done)
+	for j := range jobs {
+		recovery.Do("guard.job", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), jobTimeout)
+			defer cancel()
+			if j.exit {
+				s.exit(ctx, j.guard, j.price, j.priceTime)
+			} else {
+				s.savePeak(ctx, j.guard, j.price)
+			}
+		})
+	}
+}
+
+// savePeak stores a guard's new peak unless the guard was replaced, removed
+// or triggered since. A guard no longer active in storage, e.g. cancelled by
+// another instance closing its position, stops being evaluated.
+func (s *Service) savePeak(ctx context.Context, guard *model.DrawdownGuard, price float64) {
+	s.mu.Lock()
+	current, ok := s.active[guard.Market][guard.PositionID]
+	stale := !ok || current.CreatedAt != guard.CreatedAt
+	s.mu.Unlock()
+	if stale {
+		return
+	}
+
+	err := s.guards.SavePeak(ctx, guard)
+	if errors.Is(err, repository.ErrNotFound) {
+		s.drop(guard)
+		return
+	}
+	if err != nil {
+		log.Printf("Error saving peak of drawdown guard %s: %v", guard.PositionID, err)
+		return
+	}
+	s.record(ctx, guard, model.DrawdownGuardPeak, price, s.clock.Now())
+}
+
+// exit sells the guarded position at market and notifies its owner. Only one
+// instance exits a guard; the others just store the triggered guard. The
+// claim is released when nothing was sold, so it never holds back a guard
+// attached to the position later.
+func (s *Service) exit(ctx context.Context, guard *model.DrawdownGuard, price float64, priceTime time.Time) {
+	key := fmt.Sprintf("%s%s:%d", claimKey, guard.PositionID, guard.CreatedAt.UnixNano())
+	claimed, err := s.claims.SetNX(ctx, key, []byte("1"), claimTTL)
+	if err != nil {
+		log.Printf("Error claiming drawdown exit of position %s: %v", guard.PositionID, err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	sold := false
+	defer func() {
+		if sold {
+			return
+		}
+		if err := s.claims.Delete(ctx, key); err != nil {
+			log.Printf("Error releasing drawdown exit claim of position %s: %v", guard.PositionID, err)
+		}
+	}()
+
+	// The guard may have been replaced, detached or triggered elsewhere since
+	// it was queued; only the guard still active in storage is exited
+	stored, err := s.guards.GetByPosition(ctx, guard.PositionID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return
+	}
+	if err != nil {
+		log.Printf("Error loading drawdown guard %s for exit: %v", guard.PositionID, err)
+		return
+	}
+	if !stored.Active || !stored.CreatedAt.Equal(guard.CreatedAt) {
+		return
+	}
+
+	position, err := s.positions.GetByID(ctx, guard.PositionID)
+	if err != nil {
+		log.Printf("Error loading position %s for drawdown exit: %v", guard.PositionID, err)
+		return
+	}
+
+	var exitErr error
+	if guard.DryRun {
+		log.Printf("Dry run drawdown guard %s triggered at %g; not exiting", guard.PositionID, price)
+	} else if position.Status == model.PositionStatusOpen && position.Quantity > 0 {
+		order, err := s.exiter.PlaceOrder(ctx, guard.UserID, trading.PlaceOrderRequest{
+			Market:     position.Market,
+			Side:       model.OrderSideAsk,
+			Type:       model.OrderTypeMarket,
+			Quantity:   position.Quantity,
+			PositionID: &position.ID,
+			Source:     model.OrderSourceDrawdownGuard,
+			SourceID:   &position.ID,
+			StrategyID: guard.TemplateID,
+		})
+		if err != nil {
+			exitErr = err
+			log.Printf("Error exiting position %s on drawdown: %v", position.ID, err)
+		} else {
+			sold = true
+			guard.ExitOrderID = &order.ID
+			if s.latency != nil {
+				s.latency.Since("guard.exit", priceTime, s.clock.Now())
+			}
+		}
+	}
+
+	if err := s.guards.Save(ctx, guard); err != nil {
+		log.Printf("Error saving triggered drawdown guard %s: %v", guard.PositionID, err)
+	} else {
+		s.record(ctx, guard, model.DrawdownGuardTriggered, price, *guard.TriggeredAt)
+	}
+	if position.Status != model.PositionStatusOpen {
+		return // Closed by other means before the guard fired
+	}
+
+	s.notify(ctx, guard, price, exitErr)
+}
+
+// HandleEvent stops evaluating the guards cancelled because their position
+// closed. It is subscribed to EventDrawdownGuardCancelled on the event bus.
+func (s *Service) HandleEvent(ctx context.Context, event *model.OutboxEvent) error {
+	if event.EventType != model.EventDrawdownGuardCancelled {
+		return nil
+	}
+
+	var guard model.DrawdownGuard
+	if err := json.Unmarshal(event.Payload, &guard); err != nil {
+		return fmt.Errorf("failed to decode drawdown guard event: %w", err)
+	}
+	s.drop(&guard)
+	return nil
+}
+
+// drop stops evaluating a guard unless it was replaced since
+func (s *Service) drop(guard *model.DrawdownGuard) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.active[guard.Market][guard.PositionID]
+	if ok && current.CreatedAt.Equal(guard.CreatedAt) {
+		s.remove(guard.Market, guard.PositionID)
+	}
+}
+
+// record adds a change of a guard to its history
+func (s *Service) record(ctx context.Context, guard *model.DrawdownGuard, kind model.DrawdownGuardUpdateKind, price float64, at time.Time) {
+	if s.updates == nil {
+		return
+	}
+	if err := s.updates.Create(ctx, model.NewDrawdownGuardUpdate(guard, kind, price, at)); err != nil {
+		log.Printf("Error recording %s of drawdown guard %s: %v", kind, guard.PositionID, err)
+	}
+}
+
+func (s *Service) notify(ctx context.Context, guard *model.DrawdownGuard, price float64, exitErr error) {
+	if s.notifier == nil {
+		return
+	}
+
+	message := fmt.Sprintf("%s fell to %g, %.1f%% below its peak of %g. The position is being sold at market.",
+		guard.Market, price, guard.Drawdown(price), guard.PeakPrice)
+	if guard.DryRun {
+		message = fmt.Sprintf("%s fell to %g, %.1f%% below its peak of %g. Dry run: the position would have been sold at market.",
+			guard.Market, price, guard.Drawdown(price), guard.PeakPrice)
+	} else if exitErr != nil {
+		message = fmt.Sprintf("%s fell to %g, %.1f%% below its peak of %g, but selling the position failed: %v",
+			guard.Market, price, guard.Drawdown(price), guard.PeakPrice, exitErr)
+	}
+
+	n := model.NewNotification(guard.UserID, model.NotificationDrawdownGuard, "Drawdown guard triggered", message,
+		map[string]any{
+			"position_id":  guard.PositionID,
+			"market":       guard.Market,
+			"peak_price":   guard.PeakPrice,
+			"price":        price,
+			"max_drawdown": guard.MaxDrawdown,
+			"exited":       exitErr == nil && !guard.DryRun,
+			"dry_run":      guard.DryRun,
+		}, *guard.TriggeredAt)
+	n.DedupKey = guard.PositionID.String()
+	if err := s.notifier.Notify(ctx, n); err != nil {
+		log.Printf("Error notifying user %s of drawdown guard %s: %v", guard.UserID, guard.PositionID, err)
+	}
+}
@@ -0,0 +1,417 @@
+package guard
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+	"github.com/sungminna/upbit-trading-platform/internal/service/pricefeed"
+	"github.com/sungminna/upbit-trading-platform/internal/service/trading"
+	"github.com/sungminna/upbit-trading-platform/pkg/cache"
+	"github.com/sungminna/upbit-trading-platform/pkg/clock"
+)
+
+type recordingNotifier struct {
+	mu   sync.Mutex
+	sent []*model.Notification
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, notification *model.Notification) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sent = append(n.sent, notification)
+	return nil
+}
+
+type recordingExiter struct {
+	mu     sync.Mutex
+	orders []trading.PlaceOrderRequest
+}
+
+func (e *recordingExiter) PlaceOrder(ctx context.Context, userID uuid.UUID, req trading.PlaceOrderRequest) (*model.Order, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.orders = append(e.orders, req)
+	return model.NewOrder(userID, req.Market, req.Side, req.Type, req.Quantity, req.Price, time.Now()), nil
+}
+
+type testEnv struct {
+	service  *Service
+	feed     *pricefeed.Feed
+	store    *memory.Store
+	exiter   *recordingExiter
+	notifier *recordingNotifier
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	env := &testEnv{
+		feed:     pricefeed.NewFeed(),
+		store:    memory.NewStore(),
+		exiter:   &recordingExiter{},
+		notifier: &recordingNotifier{},
+	}
+	env.service = NewService(env.store.DrawdownGuards(), env.store.Positions(), env.exiter, env.feed, nil, env.notifier, cache.NewMemoryCache()).
+		WithCandleCloses(pricefeed.NewAggregator(env.feed)).WithHistory(env.store.DrawdownGuardUpdates())
+	require.NoError(t, env.service.Start(context.Background()))
+	t.Cleanup(env.service.Stop)
+	return env
+}
+
+// publish publishes prices and stops the service, which waits for queued jobs
+func (e *testEnv) publish(prices ...float64) {
+	for _, price := range prices {
+		e.feed.Publish(pricefeed.PriceUpdate{Market: "KRW-BTC", Price: price})
+	}
+	e.service.Stop()
+}
+
+func (e *testEnv) openPosition(t *testing.T, entry, qty float64) *model.Position {
+	position := model.NewPosition(uuid.New(), "KRW-BTC", model.PositionSideLong, entry, qty, time.Now())
+	require.NoError(t, e.store.Positions().Create(context.Background(), position))
+	return position
+}
+
+func TestService_ExitsOnDrawdownFromPeak(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	position := env.openPosition(t, 100, 2)
+
+	_, err := env.service.Attach(ctx, position.UserID, position.ID, AttachOptions{MaxDrawdown: 10})
+	require.NoError(t, err)
+
+	// 95 is 5% below entry; the peak then rises to 120 and 107 is 10.8% off it
+	env.publish(95, 120, 110, 107, 90)
+
+	require.Len(t, env.exiter.orders, 1)
+	exit := env.exiter.orders[0]
+	assert.Equal(t, model.OrderSideAsk, exit.Side)
+	assert.Equal(t, model.OrderTypeMarket, exit.Type)
+	assert.Equal(t, 2.0, exit.Quantity)
+	assert.Equal(t, position.ID, *exit.PositionID)
+
+	guard, err := env.service.Get(ctx, position.UserID, position.ID)
+	require.NoError(t, err)
+	assert.False(t, guard.Active)
+	assert.Equal(t, 120.0, guard.PeakPrice)
+	assert.Equal(t, 107.0, *guard.TriggerPrice)
+	assert.NotNil(t, guard.ExitOrderID)
+
+	require.Len(t, env.notifier.sent, 1)
+	assert.Equal(t, model.NotificationDrawdownGuard, env.notifier.sent[0].Type)
+}
+
+func TestService_ExitsAsTheClonedStrategy(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	position := env.openPosition(t, 100, 1)
+	templateID := uuid.New()
+
+	guard, err := env.service.Attach(ctx, position.UserID, position.ID, AttachOptions{MaxDrawdown: 10, TemplateID: &templateID})
+	require.NoError(t, err)
+	assert.Equal(t, templateID, *guard.TemplateID)
+
+	env.publish(80)
+
+	require.Len(t, env.exiter.orders, 1)
+	require.NotNil(t, env.exiter.orders[0].StrategyID)
+	assert.Equal(t, templateID, *env.exiter.orders[0].StrategyID)
+}
+
+func TestService_History(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	fake := clock.NewFake(time.Now())
+	env.service.WithClock(fake)
+	position := env.openPosition(t, 100, 1)
+
+	_, err := env.service.Attach(ctx, position.UserID, position.ID, AttachOptions{MaxDrawdown: 10})
+	require.NoError(t, err)
+
+	// The new peak is applied as the price arrives, before it is stored
+	fake.Advance(time.Second)
+	env.feed.Publish(pricefeed.PriceUpdate{Market: "KRW-BTC", Price: 120})
+	history, err := env.service.History(ctx, position.UserID, position.ID, time.Hour)
+	require.NoError(t, err)
+	assert.InDelta(t, 108.0, history.TriggerLevel, 1e-9)
+	assert.Equal(t, 120.0, *history.Price)
+	assert.InDelta(t, 10.0, *history.Distance, 1e-9)
+
+	// Peaks still queued when the guard triggers aren't stored, so wait for
+	// each before moving on
+	stored := func(peak float64) {
+		require.Eventually(t, func() bool {
+			guard, err := env.store.DrawdownGuards().GetByPosition(ctx, position.ID)
+			return err == nil && guard.PeakPrice == peak
+		}, time.Second, time.Millisecond)
+		fake.Advance(time.Second)
+	}
+	stored(120)
+	env.feed.Publish(pricefeed.PriceUpdate{Market: "KRW-BTC", Price: 130})
+	stored(130)
+	env.publish(110)
+
+	history, err = env.service.History(ctx, position.UserID, position.ID, time.Hour)
+	require.NoError(t, err)
+	assert.Nil(t, history.Distance, "triggered guards have no distance")
+	var kinds []model.DrawdownGuardUpdateKind
+	var levels []float64
+	for _, u := range history.Updates {
+		kinds = append(kinds, u.Kind)
+		levels = append(levels, u.TriggerLevel)
+	}
+	assert.Equal(t, []model.DrawdownGuardUpdateKind{
+		model.DrawdownGuardAttached, model.DrawdownGuardPeak, model.DrawdownGuardPeak, model.DrawdownGuardTriggered,
+	}, kinds)
+	assert.InDeltaSlice(t, []float64{90, 108, 117, 117}, levels, 1e-9)
+
+	// Older updates are left out, except the last one before the window
+	fake.Advance(2 * time.Hour)
+	history, err = env.service.History(ctx, position.UserID, position.ID, time.Hour)
+	require.NoError(t, err)
+	require.Len(t, history.Updates, 1)
+	assert.Equal(t, model.DrawdownGuardTriggered, history.Updates[0].Kind)
+
+	_, err = env.service.History(ctx, position.UserID, position.ID, 0)
+	assert.ErrorIs(t, err, ErrInvalidHistoryWindow)
+	_, err = env.service.History(ctx, uuid.New(), position.ID, time.Hour)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+
+	require.NoError(t, env.service.Detach(ctx, position.UserID, position.ID))
+	updates, err := env.store.DrawdownGuardUpdates().ListSince(ctx, position.ID, time.Time{})
+	require.NoError(t, err)
+	assert.Empty(t, updates, "updates go with their guard")
+}
+
+func TestService_DryRunOnlyNotifies(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	position := env.openPosition(t, 100, 2)
+
+	_, err := env.service.Attach(ctx, position.UserID, position.ID, AttachOptions{MaxDrawdown: 10, DryRun: true})
+	require.NoError(t, err)
+
+	env.publish(120, 100)
+	assert.Empty(t, env.exiter.orders)
+
+	guard, err := env.service.Get(ctx, position.UserID, position.ID)
+	require.NoError(t, err)
+	assert.False(t, guard.Active)
+	assert.Equal(t, 100.0, *guard.TriggerPrice)
+	assert.Nil(t, guard.ExitOrderID)
+
+	require.Len(t, env.notifier.sent, 1)
+	assert.Equal(t, true, env.notifier.sent[0].Data["dry_run"])
+	assert.Equal(t, false, env.notifier.sent[0].Data["exited"])
+}
+
+func TestService_ExitsAGuardAttachedAfterADryRun(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	position := env.openPosition(t, 100, 2)
+
+	_, err := env.service.Attach(ctx, position.UserID, position.ID, AttachOptions{MaxDrawdown: 10, DryRun: true})
+	require.NoError(t, err)
+	env.publish(80)
+	require.Empty(t, env.exiter.orders)
+
+	guard, err := env.service.Attach(ctx, position.UserID, position.ID, AttachOptions{MaxDrawdown: 10})
+	require.NoError(t, err)
+	guard.Trigger(80, time.Now())
+	env.service.exit(ctx, guard, 80, time.Now())
+
+	require.Len(t, env.exiter.orders, 1)
+	assert.Equal(t, 2.0, env.exiter.orders[0].Quantity)
+}
+
+func TestService_SkipsExitsOfReplacedGuards(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	position := env.openPosition(t, 100, 2)
+
+	replaced, err := env.service.Attach(ctx, position.UserID, position.ID, AttachOptions{MaxDrawdown: 10})
+	require.NoError(t, err)
+	env.service.Stop()
+	_, err = env.service.Attach(ctx, position.UserID, position.ID, AttachOptions{MaxDrawdown: 20})
+	require.NoError(t, err)
+
+	replaced.Trigger(80, time.Now())
+	env.service.exit(ctx, replaced, 80, time.Now())
+	assert.Empty(t, env.exiter.orders)
+
+	guard, err := env.service.Get(ctx, position.UserID, position.ID)
+	require.NoError(t, err)
+	assert.True(t, guard.Active)
+	assert.Equal(t, 20.0, guard.MaxDrawdown)
+}
+
+func TestService_SkipsPositionsClosedBeforeTrigger(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	position := env.openPosition(t, 100, 1)
+
+	_, err := env.service.Attach(ctx, position.UserID, position.ID, AttachOptions{MaxDrawdown: 5})
+	require.NoError(t, err)
+
+	position.ReduceQuantity(1, 100, time.Now())
+	require.NoError(t, env.store.Positions().Update(ctx, position))
+
+	env.publish(90)
+	assert.Empty(t, env.exiter.orders)
+	assert.Empty(t, env.notifier.sent)
+}
+
+// closeWithFill sells a position in full outside its guard, cancelling the
+// guard in the same transaction like the trading engine does
+func (e *testEnv) closeWithFill(t *testing.T, position *model.Position, price float64) {
+	ctx := context.Background()
+	require.NoError(t, e.store.Do(ctx, func(tx repository.Tx) error {
+		position.ReduceQuantity(position.Quantity, price, time.Now())
+		if err := tx.Positions().Update(ctx, position); err != nil {
+			return err
+		}
+		return trading.CancelAutomations(ctx, tx, position, price)
+	}))
+}
+
+func TestService_StopsGuardsCancelledWithTheirPosition(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	position := env.openPosition(t, 100, 1)
+
+	_, err := env.service.Attach(ctx, position.UserID, position.ID, AttachOptions{MaxDrawdown: 5})
+	require.NoError(t, err)
+	env.closeWithFill(t, position, 110)
+
+	events, err := env.store.Outbox().ListPending(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, model.EventDrawdownGuardCancelled, events[0].EventType)
+	require.NoError(t, env.service.HandleEvent(ctx, events[0]))
+
+	env.publish(130, 90)
+	assert.Empty(t, env.exiter.orders)
+	assert.Empty(t, env.notifier.sent)
+
+	history, err := env.service.History(ctx, position.UserID, position.ID, time.Hour)
+	require.NoError(t, err)
+	assert.False(t, history.Guard.Active)
+	assert.Nil(t, history.Guard.TriggeredAt)
+	assert.Equal(t, 100.0, history.Guard.PeakPrice)
+	require.Len(t, history.Updates, 2)
+	assert.Equal(t, model.DrawdownGuardCancelled, history.Updates[1].Kind)
+	assert.Equal(t, 110.0, history.Updates[1].Price)
+}
+
+func TestService_DropsGuardsCancelledElsewhere(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	position := env.openPosition(t, 100, 1)
+
+	_, err := env.service.Attach(ctx, position.UserID, position.ID, AttachOptions{MaxDrawdown: 5})
+	require.NoError(t, err)
+
+	// Closed on another instance, whose event this one never sees; the next
+	// peak finds the guard inactive instead of reactivating it
+	env.closeWithFill(t, position, 110)
+	env.feed.Publish(pricefeed.PriceUpdate{Market: "KRW-BTC", Price: 130})
+	require.Eventually(t, func() bool {
+		env.service.mu.Lock()
+		defer env.service.mu.Unlock()
+		return len(env.service.active) == 0
+	}, time.Second, 10*time.Millisecond)
+
+	env.publish(90)
+	assert.Empty(t, env.exiter.orders)
+
+	guard, err := env.service.Get(ctx, position.UserID, position.ID)
+	require.NoError(t, err)
+	assert.False(t, guard.Active)
+	assert.Nil(t, guard.TriggeredAt)
+	assert.Equal(t, 100.0, guard.PeakPrice)
+}
+
+func TestService_IgnoresStalePrices(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	position := env.openPosition(t, 100, 1)
+
+	_, err := env.service.Attach(ctx, position.UserID, position.ID, AttachOptions{MaxDrawdown: 10, MaxPriceAge: 5})
+	require.NoError(t, err)
+
+	// A crash traded a minute ago must not trigger the guard
+	env.feed.Publish(pricefeed.PriceUpdate{Market: "KRW-BTC", Price: 50, Timestamp: time.Now().Add(-time.Minute)})
+	env.publish(95)
+	assert.Empty(t, env.exiter.orders)
+
+	guard, err := env.service.Get(ctx, position.UserID, position.ID)
+	require.NoError(t, err)
+	assert.True(t, guard.Active)
+	assert.Equal(t, 5, guard.MaxPriceAge)
+}
+
+func TestService_AttachValidation(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	position := env.openPosition(t, 100, 1)
+
+	_, err := env.service.Attach(ctx, position.UserID, position.ID, AttachOptions{MaxDrawdown: 0})
+	assert.ErrorIs(t, err, ErrInvalidGuard)
+	_, err = env.service.Attach(ctx, position.UserID, position.ID, AttachOptions{MaxDrawdown: 10, MaxPriceAge: -1})
+	assert.ErrorIs(t, err, ErrInvalidPriceAge)
+	_, err = env.service.Attach(ctx, uuid.New(), position.ID, AttachOptions{MaxDrawdown: 10})
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	_, err = env.service.Attach(ctx, position.UserID, position.ID, AttachOptions{MaxDrawdown: 10, ConfirmInterval: model.CandleInterval1w})
+	assert.ErrorIs(t, err, ErrInvalidConfirmInterval)
+
+	// The peak starts at the latest price when it is above entry
+	env.feed.Publish(pricefeed.PriceUpdate{Market: "KRW-BTC", Price: 130})
+	guard, err := env.service.Attach(ctx, position.UserID, position.ID, AttachOptions{MaxDrawdown: 10})
+	require.NoError(t, err)
+	assert.Equal(t, 130.0, guard.PeakPrice)
+
+	require.NoError(t, env.service.Detach(ctx, position.UserID, position.ID))
+	env.publish(100)
+	assert.Empty(t, env.exiter.orders)
+	_, err = env.service.Get(ctx, position.UserID, position.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func TestService_ConfirmsOnCandleClose(t *testing.T) {
+	env := newTestEnv(t)
+	ctx := context.Background()
+	position := env.openPosition(t, 100, 1)
+
+	guard, err := env.service.Attach(ctx, position.UserID, position.ID, AttachOptions{MaxDrawdown: 10, MaxPriceAge: 180, ConfirmInterval: model.CandleInterval1m})
+	require.NoError(t, err)
+	assert.Equal(t, model.CandleInterval1m, guard.ConfirmInterval)
+
+	start := time.Now().Truncate(time.Minute).Add(-time.Minute)
+	publish := func(price float64, offset time.Duration) {
+		env.feed.Publish(pricefeed.PriceUpdate{Market: "KRW-BTC", Price: price, Timestamp: start.Add(offset)})
+	}
+
+	// A wick to 85 recovers to close the first minute at 95
+	publish(85, 10*time.Second)
+	publish(95, 20*time.Second)
+	publish(88, 70*time.Second)
+	assert.Empty(t, env.exiter.orders)
+
+	// The second minute closes at 89, 11% below the entry
+	publish(89, 80*time.Second)
+	publish(96, 130*time.Second)
+	env.service.Stop()
+
+	require.Len(t, env.exiter.orders, 1)
+	guard, err = env.service.Get(ctx, position.UserID, position.ID)
+	require.NoError(t, err)
+	assert.False(t, guard.Active)
+	assert.Equal(t, 89.0, *guard.TriggerPrice)
+}
@@ -0,0 +1,16 @@
+package journal
+
+var (
+	ErrTooManyTags  = &JournalError{message: "at most 10 tags are allowed"}
+	ErrInvalidTag   = &JournalError{message: "tags must be 1-32 letters, digits, '-' or '_'"}
+	ErrNotesTooLong = &JournalError{message: "notes must be at most 2000 characters"}
+)
+
+// JournalError represents a journal validation error
+type JournalError struct {
+	message string
+}
+
+func (e *JournalError) Error() string {
+	return e.message
+}
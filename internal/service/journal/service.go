@@ -0,0 +1,199 @@
+// Package journal lets users tag and annotate their positions and orders,
+// e.g. "swing" or "bot-x", and find them by tag
+package journal
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+const (
+	maxTags      = 10
+	maxTagLength = 32
+	maxNotes     = 2000 // Characters
+)
+
+// Entry is the user's journal of one position or order
+type Entry struct {
+	Tags  []string `json:"tags"`
+	Notes string   `json:"notes"`
+}
+
+// PositionFilter selects positions; empty fields match all
+type PositionFilter struct {
+	Tag    string
+	Status model.PositionStatus
+}
+
+// OrderFilter selects orders; empty fields match all
+type OrderFilter struct {
+	Tag    string
+	Market string
+	Status model.OrderStatus
+}
+
+// Service manages the journal of users' positions and orders
+type Service struct {
+	positions repository.PositionRepository
+	orders    repository.OrderRepository
+	events    repository.PositionEventRepository // Optional; enables History
+}
+
+// NewService creates a new journal service
+func NewService(positions repository.PositionRepository, orders repository.OrderRepository) *Service {
+	return &Service{
+		positions: positions,
+		orders:    orders,
+	}
+}
+
+// WithEvents enables position history
+func (s *Service) WithEvents(events repository.PositionEventRepository) *Service {
+	s.events = events
+	return s
+}
+
+// History returns the lifecycle events of one of the user's positions in
+// chronological order
+func (s *Service) History(ctx context.Context, userID, positionID uuid.UUID) ([]*model.PositionEvent, error) {
+	position, err := s.positions.GetByID(ctx, positionID)
+	if err != nil {
+		return nil, err
+	}
+	if position.UserID != userID {
+		return nil, repository.ErrNotFound
+	}
+
+	events, err := s.events.ListByPosition(ctx, positionID)
+	if err != nil {
+		return nil, err
+	}
+	if events == nil {
+		events = []*model.PositionEvent{}
+	}
+	return events, nil
+}
+
+// Positions lists the user's positions matching the filter, newest first
+func (s *Service) Positions(ctx context.Context, userID uuid.UUID, filter PositionFilter) ([]*model.Position, error) {
+	positions, err := s.positions.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tag := normalizeTag(filter.Tag)
+	matched := []*model.Position{}
+	for _, p := range positions {
+		if (filter.Status == "" || p.Status == filter.Status) && (tag == "" || slices.Contains(p.Tags, tag)) {
+			matched = append(matched, p)
+		}
+	}
+	return matched, nil
+}
+
+// Orders lists the user's orders matching the filter, newest first
+func (s *Service) Orders(ctx context.Context, userID uuid.UUID, filter OrderFilter) ([]*model.Order, error) {
+	orders, err := s.orders.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tag := normalizeTag(filter.Tag)
+	matched := []*model.Order{}
+	for _, o := range orders {
+		if (filter.Status == "" || o.Status == filter.Status) && (filter.Market == "" || o.Market == filter.Market) &&
+			(tag == "" || slices.Contains(o.Tags, tag)) {
+			matched = append(matched, o)
+		}
+	}
+	return matched, nil
+}
+
+// AnnotatePosition replaces the tags and notes of one of the user's positions
+func (s *Service) AnnotatePosition(ctx context.Context, userID, positionID uuid.UUID, entry Entry) (*model.Position, error) {
+	tags, err := validate(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	position, err := s.positions.GetByID(ctx, positionID)
+	if err != nil {
+		return nil, err
+	}
+	if position.UserID != userID {
+		return nil, repository.ErrNotFound
+	}
+
+	if err := s.positions.Annotate(ctx, positionID, tags, entry.Notes); err != nil {
+		return nil, err
+	}
+	position.Tags, position.Notes = tags, entry.Notes
+	return position, nil
+}
+
+// AnnotateOrder replaces the tags and notes of one of the user's orders
+func (s *Service) AnnotateOrder(ctx context.Context, userID, orderID uuid.UUID, entry Entry) (*model.Order, error) {
+	tags, err := validate(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := s.orders.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != userID {
+		return nil, repository.ErrNotFound
+	}
+
+	if err := s.orders.Annotate(ctx, orderID, tags, entry.Notes); err != nil {
+		return nil, err
+	}
+	order.Tags, order.Notes = tags, entry.Notes
+	return order, nil
+}
+
+// validate checks an entry and returns its tags normalized: lower case,
+// sorted and without duplicates
+func validate(entry Entry) ([]string, error) {
+	if utf8.RuneCountInString(entry.Notes) > maxNotes {
+		return nil, ErrNotesTooLong
+	}
+
+	tags := make([]string, 0, len(entry.Tags))
+	for _, tag := range entry.Tags {
+		tag = normalizeTag(tag)
+		if !validTag(tag) {
+			return nil, ErrInvalidTag
+		}
+		tags = append(tags, tag)
+	}
+	slices.Sort(tags)
+	tags = slices.Compact(tags)
+	if len(tags) > maxTags {
+		return nil, ErrTooManyTags
+	}
+	return tags, nil
+}
+
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+func validTag(tag string) bool {
+	if tag == "" || len(tag) > maxTagLength {
+		return false
+	}
+	for _, r := range tag {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,108 @@
+package journal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+)
+
+func TestService_AnnotatePosition(t *testing.T) {
+	store := memory.NewStore()
+	s := NewService(store.Positions(), store.Orders())
+	ctx := context.Background()
+	userID := uuid.New()
+
+	position := model.NewPosition(userID, "KRW-BTC", model.PositionSideLong, 100000000, 0.01, time.Now())
+	require.NoError(t, store.Positions().Create(ctx, position))
+	other := model.NewPosition(userID, "KRW-ETH", model.PositionSideLong, 5000000, 1, time.Now())
+	require.NoError(t, store.Positions().Create(ctx, other))
+
+	annotated, err := s.AnnotatePosition(ctx, userID, position.ID, Entry{Tags: []string{" Swing", "btc", "swing"}, Notes: "Breakout entry"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"btc", "swing"}, annotated.Tags)
+
+	// Fills don't overwrite the journal
+	position.UpdateQuantity(0.01, 110000000, time.Now())
+	require.NoError(t, store.Positions().Update(ctx, position))
+
+	tagged, err := s.Positions(ctx, userID, PositionFilter{Tag: "SWING"})
+	require.NoError(t, err)
+	require.Len(t, tagged, 1)
+	assert.Equal(t, position.ID, tagged[0].ID)
+	assert.Equal(t, "Breakout entry", tagged[0].Notes)
+	assert.InDelta(t, 0.02, tagged[0].Quantity, 1e-12)
+
+	_, err = s.AnnotatePosition(ctx, uuid.New(), position.ID, Entry{})
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func TestService_AnnotateOrder(t *testing.T) {
+	store := memory.NewStore()
+	s := NewService(store.Positions(), store.Orders())
+	ctx := context.Background()
+	userID := uuid.New()
+
+	price := 100000000.0
+	order := model.NewOrder(userID, "KRW-BTC", model.OrderSideBid, model.OrderTypeLimit, 0.01, &price, time.Now())
+	require.NoError(t, store.Orders().Create(ctx, order))
+
+	_, err := s.AnnotateOrder(ctx, userID, order.ID, Entry{Tags: []string{"bot-x"}})
+	require.NoError(t, err)
+
+	orders, err := s.Orders(ctx, userID, OrderFilter{Tag: "bot-x", Market: "KRW-BTC"})
+	require.NoError(t, err)
+	assert.Len(t, orders, 1)
+	orders, err = s.Orders(ctx, userID, OrderFilter{Tag: "swing"})
+	require.NoError(t, err)
+	assert.Empty(t, orders)
+}
+
+func TestService_History(t *testing.T) {
+	store := memory.NewStore()
+	s := NewService(store.Positions(), store.Orders()).WithEvents(store.PositionEvents())
+	ctx := context.Background()
+	userID := uuid.New()
+
+	position := model.NewPosition(userID, "KRW-BTC", model.PositionSideLong, 100000000, 0.01, time.Now())
+	require.NoError(t, store.Positions().Create(ctx, position))
+
+	events, err := s.History(ctx, userID, position.ID)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+
+	order := model.NewOrder(userID, "KRW-BTC", model.OrderSideBid, model.OrderTypeMarket, 0.01, nil, time.Now())
+	require.NoError(t, store.PositionEvents().Create(ctx, model.NewPositionEvent(position, model.PositionEventOpened, order, 100000000, 0.01, time.Now())))
+
+	events, err = s.History(ctx, userID, position.ID)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, model.PositionEventOpened, events[0].Type)
+
+	_, err = s.History(ctx, uuid.New(), position.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func TestValidate(t *testing.T) {
+	_, err := validate(Entry{Tags: []string{"bot x"}})
+	assert.ErrorIs(t, err, ErrInvalidTag)
+	_, err = validate(Entry{Tags: []string{""}})
+	assert.ErrorIs(t, err, ErrInvalidTag)
+
+	many := make([]string, maxTags+1)
+	for i := range many {
+		many[i] = string(rune('a' + i))
+	}
+	_, err = validate(Entry{Tags: many})
+	assert.ErrorIs(t, err, ErrTooManyTags)
+
+	tags, err := validate(Entry{})
+	require.NoError(t, err)
+	assert.Empty(t, tags)
+}
@@ -0,0 +1,252 @@
+// Package ledger keeps a ledger of users' KRW cash movements and reconciles
+// it with their exchange balances
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/gateway"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/scheduler"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/exchange"
+)
+
+const (
+	syncInterval = 5 * time.Minute
+	cashCurrency = "KRW"
+	// balanceTolerance absorbs the exchange rounding fees to whole won
+	balanceTolerance = 1.0
+)
+
+// Service derives cash ledger entries from users' platform executions and
+// exchange transfers. A user's ledger starts with their exchange balance the
+// first time it is synced; earlier movements are covered by that opening
+// balance. Anything the ledger misses, such as trades placed directly on
+// Upbit, shows up as a difference when it is reconciled.
+type Service struct {
+	entries    repository.CashLedgerRepository
+	apiKeys    repository.UserAPIKeyRepository
+	orders     repository.OrderRepository
+	executions repository.OrderExecutionRepository
+	newClient  gateway.ExchangeClientFactory
+}
+
+// NewService creates a new cash ledger service
+func NewService(
+	entries repository.CashLedgerRepository,
+	apiKeys repository.UserAPIKeyRepository,
+	orders repository.OrderRepository,
+	executions repository.OrderExecutionRepository,
+	newClient gateway.ExchangeClientFactory,
+) *Service {
+	return &Service{
+		entries:    entries,
+		apiKeys:    apiKeys,
+		orders:     orders,
+		executions: executions,
+		newClient:  newClient,
+	}
+}
+
+// Job returns the job syncing every user's ledger
+func (s *Service) Job() scheduler.Job {
+	return scheduler.Job{
+		Name:     "cash-ledger",
+		Schedule: scheduler.Every(syncInterval),
+		Run: func(ctx context.Context, at time.Time) error {
+			return s.SyncAll(ctx)
+		},
+	}
+}
+
+// SyncAll syncs the ledger of every user with an active API key. A failure
+// for one user doesn't stop the others; all failures are returned together.
+func (s *Service) SyncAll(ctx context.Context) error {
+	userIDs, err := s.apiKeys.ListActiveUserIDs(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, userID := range userIDs {
+		reconciliation, err := s.Sync(ctx, userID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("user %s: %w", userID, err))
+			continue
+		}
+		if !reconciliation.Balanced {
+			log.Printf("Cash ledger of user %s is off by %.2f KRW", userID, reconciliation.Difference)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Sync records the user's new cash movements and reconciles the ledger with
+// their exchange balance
+func (s *Service) Sync(ctx context.Context, userID uuid.UUID) (*model.CashReconciliation, error) {
+	key, err := s.apiKeys.GetActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load API key: %w", err)
+	}
+	client := s.newClient(key.AccessKey, key.SecretKey)
+	now := time.Now()
+
+	accounts, err := client.GetAccounts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get accounts: %w", err)
+	}
+	var held float64
+	for _, account := range accounts {
+		if account.Currency == cashCurrency {
+			held = parseFloat(account.Balance) + parseFloat(account.Locked)
+		}
+	}
+
+	opening, err := s.entries.GetOpening(ctx, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		opening = model.NewCashLedgerEntry(userID, model.CashEntryOpeningBalance, held, "opening", now, now)
+		if err := s.entries.Record(ctx, opening); err != nil {
+			return nil, err
+		}
+		// Another instance may have opened the ledger first
+		if opening, err = s.entries.GetOpening(ctx, userID); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to load opening balance: %w", err)
+	}
+
+	if err := s.recordExecutions(ctx, userID, opening.OccurredAt, now); err != nil {
+		return nil, err
+	}
+	if transfers, ok := client.(gateway.TransferAPI); ok {
+		if err := s.recordTransfers(ctx, userID, transfers, opening.OccurredAt, now); err != nil {
+			return nil, err
+		}
+	}
+
+	balance, err := s.entries.Balance(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	difference := held - balance
+	return &model.CashReconciliation{
+		UserID:          userID,
+		LedgerBalance:   balance,
+		ExchangeBalance: held,
+		Difference:      difference,
+		Balanced:        math.Abs(difference) <= balanceTolerance,
+		CheckedAt:       now,
+	}, nil
+}
+
+// Entries returns a user's ledger entries that occurred in [from, to), newest first
+func (s *Service) Entries(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]*model.CashLedgerEntry, error) {
+	return s.entries.ListByUser(ctx, userID, from, to)
+}
+
+// recordExecutions records the settlement and fee of each execution of the
+// user's KRW market orders since the ledger opened, recording them at now
+func (s *Service) recordExecutions(ctx context.Context, userID uuid.UUID, since, now time.Time) error {
+	orders, err := s.orders.ListByUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to list orders: %w", err)
+	}
+
+	for _, order := range orders {
+		if order.ExecutedQuantity <= 0 || order.UpdatedAt.Before(since) || !strings.HasPrefix(order.Market, cashCurrency+"-") {
+			continue
+		}
+		executions, err := s.executions.ListByOrder(ctx, order.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list executions of order %s: %w", order.ID, err)
+		}
+
+		for _, execution := range executions {
+			if execution.CreatedAt.Before(since) {
+				continue
+			}
+			settlement := execution.Total
+			if order.Side == model.OrderSideBid {
+				settlement = -settlement
+			}
+			entries := []*model.CashLedgerEntry{
+				model.NewCashLedgerEntry(userID, model.CashEntryTradeSettlement, settlement, execution.ID.String(), execution.CreatedAt, now),
+			}
+			if execution.Fee > 0 {
+				entries = append(entries, model.NewCashLedgerEntry(userID, model.CashEntryFee, -execution.Fee, execution.ID.String(), execution.CreatedAt, now))
+			}
+			for _, entry := range entries {
+				entry.OrderID = &order.ID
+				if err := s.entries.Record(ctx, entry); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// recordTransfers records the user's completed KRW deposits and withdrawals
+// since the ledger opened, recording them at now
+func (s *Service) recordTransfers(ctx context.Context, userID uuid.UUID, client gateway.TransferAPI, since, now time.Time) error {
+	deposits, err := client.GetDeposits(ctx, cashCurrency)
+	if err != nil {
+		return fmt.Errorf("failed to get deposits: %w", err)
+	}
+	withdrawals, err := client.GetWithdraws(ctx, cashCurrency)
+	if err != nil {
+		return fmt.Errorf("failed to get withdrawals: %w", err)
+	}
+
+	var entries []*model.CashLedgerEntry
+	for _, deposit := range deposits {
+		if at, ok := completedAt(deposit, since); ok {
+			entries = append(entries, model.NewCashLedgerEntry(userID, model.CashEntryDeposit, parseFloat(deposit.Amount), deposit.UUID, at, now))
+		}
+	}
+	for _, withdrawal := range withdrawals {
+		if at, ok := completedAt(withdrawal, since); ok {
+			entries = append(entries, model.NewCashLedgerEntry(userID, model.CashEntryWithdrawal, -parseFloat(withdrawal.Amount), withdrawal.UUID, at, now))
+			if fee := parseFloat(withdrawal.Fee); fee > 0 {
+				entries = append(entries, model.NewCashLedgerEntry(userID, model.CashEntryFee, -fee, withdrawal.UUID, at, now))
+			}
+		}
+	}
+
+	for _, entry := range entries {
+		if err := s.entries.Record(ctx, entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// completedAt returns when a transfer completed, and whether it completed
+// since the given time. Deposits complete as accepted, withdrawals as done.
+func completedAt(transfer exchange.Transfer, since time.Time) (time.Time, bool) {
+	switch strings.ToLower(transfer.State) {
+	case "accepted", "done":
+	default:
+		return time.Time{}, false
+	}
+	at := transfer.CreatedAt
+	if transfer.DoneAt != nil {
+		at = *transfer.DoneAt
+	}
+	return at, !at.Before(since)
+}
+
+func parseFloat(s string) float64 {
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
@@ -0,0 +1,86 @@
+package ledger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/gateway"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/exchange"
+)
+
+// stubExchange serves a KRW balance and transfers
+type stubExchange struct {
+	gateway.ExchangeAPI
+	krw         string
+	deposits    []exchange.Transfer
+	withdrawals []exchange.Transfer
+}
+
+func (e *stubExchange) GetAccounts(ctx context.Context) ([]exchange.Account, error) {
+	return []exchange.Account{{Currency: "KRW", Balance: e.krw, Locked: "0"}}, nil
+}
+
+func (e *stubExchange) GetDeposits(ctx context.Context, currency string) ([]exchange.Transfer, error) {
+	return e.deposits, nil
+}
+
+func (e *stubExchange) GetWithdraws(ctx context.Context, currency string) ([]exchange.Transfer, error) {
+	return e.withdrawals, nil
+}
+
+func TestService_Sync(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	userID := uuid.New()
+	require.NoError(t, store.APIKeys().Create(ctx, model.NewUserAPIKey(userID, "access", "secret", "", time.Now())))
+
+	upbit := &stubExchange{krw: "1000000"}
+	service := NewService(store.CashLedger(), store.APIKeys(), store.Orders(), store.Executions(), func(accessKey, secretKey string) gateway.ExchangeAPI {
+		return upbit
+	})
+
+	reconciliation, err := service.Sync(ctx, userID)
+	require.NoError(t, err)
+	assert.True(t, reconciliation.Balanced)
+	assert.Equal(t, 1000000.0, reconciliation.LedgerBalance)
+
+	// A buy settled on the platform, a deposit and a withdrawal with its fee
+	price := 50000000.0
+	order := model.NewOrder(userID, "KRW-BTC", model.OrderSideBid, model.OrderTypeLimit, 0.01, &price, time.Now())
+	order.UpdateExecution(0.01, time.Now())
+	require.NoError(t, store.Orders().Create(ctx, order))
+	require.NoError(t, store.Executions().Create(ctx, model.NewOrderExecution(order.ID, price, 0.01, 250, time.Now())))
+	done := time.Now()
+	upbit.deposits = []exchange.Transfer{
+		{UUID: "d1", State: "ACCEPTED", Amount: "300000", DoneAt: &done},
+		{UUID: "d2", State: "PROCESSING", Amount: "700000"},
+	}
+	upbit.withdrawals = []exchange.Transfer{{UUID: "w1", State: "DONE", Amount: "100000", Fee: "1000", DoneAt: &done}}
+	upbit.krw = "698750" // 1,000,000 - 500,250 + 300,000 - 101,000
+
+	reconciliation, err = service.Sync(ctx, userID)
+	require.NoError(t, err)
+	assert.True(t, reconciliation.Balanced)
+	assert.InDelta(t, 698750, reconciliation.LedgerBalance, 1e-6)
+
+	entries, err := service.Entries(ctx, userID, time.Time{}, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Len(t, entries, 6)
+
+	// Syncing again records nothing twice, and a trade made directly on
+	// the exchange shows up as a difference
+	upbit.krw = "598750"
+	reconciliation, err = service.Sync(ctx, userID)
+	require.NoError(t, err)
+	assert.False(t, reconciliation.Balanced)
+	assert.InDelta(t, -100000, reconciliation.Difference, 1e-6)
+	entries, err = service.Entries(ctx, userID, time.Time{}, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.Len(t, entries, 6)
+}
@@ -0,0 +1,339 @@
+// Package maintenance tracks Upbit maintenance, announced for a market or
+// detected from Upbit's responses, so orders wait it out instead of failing
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/notification"
+	"github.com/sungminna/upbit-trading-platform/internal/service/scheduler"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/exchange"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/quotation"
+	"github.com/sungminna/upbit-trading-platform/pkg/recovery"
+)
+
+const (
+	checkInterval = time.Minute
+	// probeMarket is the market whose ticker shows whether Upbit is up
+	probeMarket = "KRW-BTC"
+	// notifyTimeout bounds notifying users of a change
+	notifyTimeout = 30 * time.Second
+	// outageReason describes detected outages
+	outageReason = "Upbit is under maintenance"
+)
+
+// TickerSource provides current prices; gateway.QuotationAPI satisfies it
+type TickerSource interface {
+	GetTicker(ctx context.Context, markets []string) ([]quotation.Ticker, error)
+}
+
+// Status is the maintenance in effect and announced
+type Status struct {
+	Outage  *model.Maintenance         `json:"outage,omitempty"` // Detected from Upbit's responses
+	Windows []*model.MaintenanceWindow `json:"windows"`          // In effect or upcoming, earliest first
+}
+
+// Detector tracks Upbit maintenance. Outages are detected from maintenance
+// responses to exchange requests and to a ticker probe, which also shows when
+// they end; windows are announced by admins. Users are notified when either
+// begins and ends.
+type Detector struct {
+	windows  repository.MaintenanceWindowRepository
+	tickers  TickerSource
+	apiKeys  repository.UserAPIKeyRepository
+	orders   repository.OrderRepository
+	notifier notification.Notifier // Optional
+
+	mu       sync.RWMutex
+	outage   *model.Maintenance         // nil while Upbit is up
+	upcoming []*model.MaintenanceWindow // Refreshed every check
+	started  map[uuid.UUID]*model.MaintenanceWindow
+}
+
+// NewDetector creates a new maintenance detector
+func NewDetector(
+	windows repository.MaintenanceWindowRepository,
+	tickers TickerSource,
+	apiKeys repository.UserAPIKeyRepository,
+	orders repository.OrderRepository,
+) *Detector {
+	return &Detector{
+		windows: windows,
+		tickers: tickers,
+		apiKeys: apiKeys,
+		orders:  orders,
+		started: make(map[uuid.UUID]*model.MaintenanceWindow),
+	}
+}
+
+// WithNotifier makes the detector notify users when maintenance begins and ends
+func (d *Detector) WithNotifier(notifier notification.Notifier) *Detector {
+	d.notifier = notifier
+	return d
+}
+
+// Job returns the job refreshing maintenance windows and probing Upbit
+func (d *Detector) Job() scheduler.Job {
+	return scheduler.Job{
+		Name:     "maintenance",
+		Schedule: scheduler.Every(checkInterval),
+		Run: func(ctx context.Context, at time.Time) error {
+			return d.Check(ctx, time.Now())
+		},
+	}
+}
+
+// Maintenance returns the maintenance blocking orders for a market, or nil.
+// An empty market only matches maintenance of every market.
+func (d *Detector) Maintenance(market string) *model.Maintenance {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.outage != nil {
+		m := *d.outage
+		return &m
+	}
+	now := time.Now()
+	for _, w := range d.upcoming {
+		if w.Covers(market, now) {
+			return windowMaintenance(w)
+		}
+	}
+	return nil
+}
+
+// Observe records the error of an exchange request, starting an outage if
+// Upbit answered that it is under maintenance
+func (d *Detector) Observe(err error) {
+	if IsMaintenanceError(err) {
+		d.startOutage(err)
+	}
+}
+
+// Status returns the detected outage and the windows in effect or upcoming
+func (d *Detector) Status() *Status {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	status := &Status{Windows: make([]*model.MaintenanceWindow, 0, len(d.upcoming))}
+	if d.outage != nil {
+		m := *d.outage
+		status.Outage = &m
+	}
+	for _, w := range d.upcoming {
+		window := *w
+		status.Windows = append(status.Windows, &window)
+	}
+	return status
+}
+
+// CreateWindow validates and stores an announced maintenance window
+func (d *Detector) CreateWindow(ctx context.Context, window *model.MaintenanceWindow) (*model.MaintenanceWindow, error) {
+	window.Market = strings.ToUpper(strings.TrimSpace(window.Market))
+	if window.Market != "" && !strings.Contains(window.Market, "-") {
+		return nil, ErrInvalidMarket
+	}
+	now := time.Now()
+	if !window.EndsAt.After(window.StartsAt) || !window.EndsAt.After(now) {
+		return nil, ErrInvalidWindow
+	}
+
+	if window.Reason == "" {
+		window.Reason = "scheduled maintenance"
+	}
+
+	window.ID = uuid.New()
+	window.CreatedAt = now
+	if err := d.windows.Create(ctx, window); err != nil {
+		return nil, err
+	}
+	return window, d.Refresh(ctx, now)
+}
+
+// DeleteWindow removes a maintenance window, ending it if it is in effect
+func (d *Detector) DeleteWindow(ctx context.Context, id uuid.UUID) error {
+	if err := d.windows.Delete(ctx, id); err != nil {
+		return err
+	}
+	return d.Refresh(ctx, time.Now())
+}
+
+// Check refreshes the maintenance windows and probes whether Upbit is under
+// maintenance
+func (d *Detector) Check(ctx context.Context, now time.Time) error {
+	if err := d.Refresh(ctx, now); err != nil {
+		return err
+	}
+
+	_, err := d.tickers.GetTicker(ctx, []string{probeMarket})
+	switch {
+	case err == nil:
+		d.endOutage()
+	case IsMaintenanceError(err):
+		d.startOutage(err)
+	}
+	// Other errors, e.g. a network failure, say nothing about maintenance
+	return nil
+}
+
+// Refresh reloads the windows and notifies the users affected by windows that
+// began or ended since the last refresh
+func (d *Detector) Refresh(ctx context.Context, now time.Time) error {
+	windows, err := d.windows.ListEndingAfter(ctx, now)
+	if err != nil {
+		return fmt.Errorf("failed to list maintenance windows: %w", err)
+	}
+
+	var began, ended []*model.MaintenanceWindow
+	d.mu.Lock()
+	d.upcoming = windows
+	inEffect := make(map[uuid.UUID]bool)
+	for _, w := range windows {
+		if !w.Covers(w.Market, now) {
+			continue
+		}
+		inEffect[w.ID] = true
+		if d.started[w.ID] == nil {
+			d.started[w.ID] = w
+			began = append(began, w)
+		}
+	}
+	for id, w := range d.started {
+		if !inEffect[id] {
+			delete(d.started, id)
+			ended = append(ended, w)
+		}
+	}
+	d.mu.Unlock()
+
+	for _, w := range began {
+		log.Printf("Maintenance window %s of %s began: %s", w.ID, marketName(w.Market), w.Reason)
+		d.notifyStart(windowMaintenance(w), w.ID.String())
+	}
+	for _, w := range ended {
+		log.Printf("Maintenance window %s of %s ended", w.ID, marketName(w.Market))
+		d.notifyEnd(w.Market, w.ID.String())
+	}
+	return nil
+}
+
+func (d *Detector) startOutage(cause error) {
+	d.mu.Lock()
+	if d.outage != nil {
+		d.mu.Unlock()
+		return
+	}
+	d.outage = &model.Maintenance{Reason: outageReason, Detected: true, Since: time.Now()}
+	outage := *d.outage
+	d.mu.Unlock()
+
+	log.Printf("Upbit maintenance detected, pausing orders: %v", cause)
+	d.notifyStart(&outage, "outage")
+}
+
+func (d *Detector) endOutage() {
+	d.mu.Lock()
+	outage := d.outage
+	d.outage = nil
+	d.mu.Unlock()
+
+	if outage == nil {
+		return
+	}
+	log.Printf("Upbit maintenance over after %s, resuming orders", time.Since(outage.Since).Round(time.Second))
+	d.notifyEnd("", "outage")
+}
+
+func (d *Detector) notifyStart(m *model.Maintenance, dedupKey string) {
+	message := fmt.Sprintf("Upbit maintenance of %s: %s. Orders wait until it is over, then resume automatically.", marketName(m.Market), m.Reason)
+	if m.Until != nil {
+		message = fmt.Sprintf("Upbit maintenance of %s until %s: %s. Orders wait until it is over, then resume automatically.",
+			marketName(m.Market), m.Until.Format(time.RFC3339), m.Reason)
+	}
+	d.notify(m.Market, model.NotificationMaintenanceStart, "Upbit maintenance", message, dedupKey)
+}
+
+func (d *Detector) notifyEnd(market, dedupKey string) {
+	message := fmt.Sprintf("Upbit maintenance of %s is over and orders resumed.", marketName(market))
+	d.notify(market, model.NotificationMaintenanceEnd, "Upbit maintenance over", message, dedupKey)
+}
+
+// notify tells the affected users in the background: every user with an
+// active API key for maintenance of every market, and the users with open
+// orders in the market otherwise
+func (d *Detector) notify(market, notificationType, title, message, dedupKey string) {
+	if d.notifier == nil {
+		return
+	}
+
+	recovery.Go("maintenance.notify", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+		defer cancel()
+
+		users, err := d.affectedUsers(ctx, market)
+		if err != nil {
+			log.Printf("Error listing users affected by maintenance: %v", err)
+			return
+		}
+		now := time.Now()
+		for _, userID := range users {
+			n := model.NewNotification(userID, notificationType, title, message, map[string]any{"market": market}, now)
+			n.DedupKey = dedupKey
+			if err := d.notifier.Notify(ctx, n); err != nil {
+				log.Printf("Error notifying user %s of maintenance: %v", userID, err)
+			}
+		}
+	})
+}
+
+func (d *Detector) affectedUsers(ctx context.Context, market string) ([]uuid.UUID, error) {
+	if market == "" {
+		return d.apiKeys.ListActiveUserIDs(ctx)
+	}
+
+	orders, err := d.orders.ListOpen(ctx)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[uuid.UUID]bool)
+	var users []uuid.UUID
+	for _, order := range orders {
+		if order.Market == market && !seen[order.UserID] {
+			seen[order.UserID] = true
+			users = append(users, order.UserID)
+		}
+	}
+	return users, nil
+}
+
+// IsMaintenanceError reports whether an Upbit error says it is under
+// maintenance
+func IsMaintenanceError(err error) bool {
+	var exchangeErr *exchange.APIError
+	if errors.As(err, &exchangeErr) {
+		return exchangeErr.IsMaintenance()
+	}
+	var quotationErr *quotation.APIError
+	return errors.As(err, &quotationErr) && quotationErr.IsMaintenance()
+}
+
+func windowMaintenance(w *model.MaintenanceWindow) *model.Maintenance {
+	until := w.EndsAt
+	return &model.Maintenance{Market: w.Market, Reason: w.Reason, Since: w.StartsAt, Until: &until}
+}
+
+func marketName(market string) string {
+	if market == "" {
+		return "all markets"
+	}
+	return market
+}
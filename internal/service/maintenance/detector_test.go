@@ -0,0 +1,127 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/exchange"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/quotation"
+)
+
+type stubTickers struct {
+	mu  sync.Mutex
+	err error
+}
+
+func (s *stubTickers) GetTicker(ctx context.Context, markets []string) ([]quotation.Ticker, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	return []quotation.Ticker{{Market: markets[0], TradePrice: 100000000}}, nil
+}
+
+func (s *stubTickers) fail(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.err = err
+}
+
+type recordingNotifier struct {
+	mu   sync.Mutex
+	sent []*model.Notification
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, notification *model.Notification) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sent = append(n.sent, notification)
+	return nil
+}
+
+func (n *recordingNotifier) types() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	var types []string
+	for _, notification := range n.sent {
+		types = append(types, notification.Type)
+	}
+	return types
+}
+
+func TestDetector_DetectsOutagesUntilUpbitAnswers(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	userID := uuid.New()
+	require.NoError(t, store.APIKeys().Create(ctx, model.NewUserAPIKey(userID, "access", "secret", "test", time.Now())))
+	tickers := &stubTickers{}
+	notifier := &recordingNotifier{}
+	detector := NewDetector(store.MaintenanceWindows(), tickers, store.APIKeys(), store.Orders()).WithNotifier(notifier)
+
+	// Errors that aren't maintenance don't start one
+	detector.Observe(&exchange.APIError{StatusCode: 400, Name: "insufficient_funds_bid"})
+	detector.Observe(errors.New("connection refused"))
+	assert.Nil(t, detector.Maintenance("KRW-BTC"))
+
+	detector.Observe(&exchange.APIError{StatusCode: 503, Body: "<html>maintenance</html>"})
+	m := detector.Maintenance("KRW-BTC")
+	require.NotNil(t, m)
+	assert.True(t, m.Detected)
+	assert.NotNil(t, detector.Maintenance(""), "detected outages cover every market")
+
+	// The probe keeps it going while Upbit is under maintenance, and ends it
+	// once Upbit answers
+	tickers.fail(&quotation.APIError{StatusCode: 503})
+	require.NoError(t, detector.Check(ctx, time.Now()))
+	assert.NotNil(t, detector.Maintenance("KRW-BTC"))
+	tickers.fail(errors.New("timeout"))
+	require.NoError(t, detector.Check(ctx, time.Now()))
+	assert.NotNil(t, detector.Maintenance("KRW-BTC"), "network errors say nothing about maintenance")
+	tickers.fail(nil)
+	require.NoError(t, detector.Check(ctx, time.Now()))
+	assert.Nil(t, detector.Maintenance("KRW-BTC"))
+
+	require.Eventually(t, func() bool {
+		return len(notifier.types()) == 2
+	}, time.Second, 10*time.Millisecond)
+	assert.ElementsMatch(t, []string{model.NotificationMaintenanceStart, model.NotificationMaintenanceEnd}, notifier.types())
+}
+
+func TestDetector_Windows(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	detector := NewDetector(store.MaintenanceWindows(), &stubTickers{}, store.APIKeys(), store.Orders())
+	now := time.Now()
+
+	_, err := detector.CreateWindow(ctx, &model.MaintenanceWindow{Market: "BTC", StartsAt: now, EndsAt: now.Add(time.Hour)})
+	assert.ErrorIs(t, err, ErrInvalidMarket)
+	_, err = detector.CreateWindow(ctx, &model.MaintenanceWindow{StartsAt: now, EndsAt: now.Add(-time.Minute)})
+	assert.ErrorIs(t, err, ErrInvalidWindow)
+	_, err = detector.CreateWindow(ctx, &model.MaintenanceWindow{StartsAt: now.Add(-2 * time.Hour), EndsAt: now.Add(-time.Hour)})
+	assert.ErrorIs(t, err, ErrInvalidWindow, "already over")
+
+	window, err := detector.CreateWindow(ctx, &model.MaintenanceWindow{Market: "krw-eth", StartsAt: now.Add(-time.Minute), EndsAt: now.Add(time.Hour)})
+	require.NoError(t, err)
+	assert.Equal(t, "KRW-ETH", window.Market)
+	_, err = detector.CreateWindow(ctx, &model.MaintenanceWindow{StartsAt: now.Add(time.Hour), EndsAt: now.Add(2 * time.Hour), Reason: "system upgrade"})
+	require.NoError(t, err)
+
+	m := detector.Maintenance("KRW-ETH")
+	require.NotNil(t, m)
+	assert.False(t, m.Detected)
+	assert.WithinDuration(t, window.EndsAt, *m.Until, 0)
+	assert.Nil(t, detector.Maintenance("KRW-BTC"), "other markets trade, and the upcoming window hasn't begun")
+	assert.Len(t, detector.Status().Windows, 2)
+
+	require.NoError(t, detector.DeleteWindow(ctx, window.ID))
+	assert.Nil(t, detector.Maintenance("KRW-ETH"))
+}
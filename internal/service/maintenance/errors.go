@@ -0,0 +1,15 @@
+package maintenance
+
+var (
+	ErrInvalidMarket = &MaintenanceError{message: "market must be a market code, e.g. KRW-BTC, or empty for every market"}
+	ErrInvalidWindow = &MaintenanceError{message: "ends_at must be after starts_at and in the future"}
+)
+
+// MaintenanceError represents a maintenance window validation error
+type MaintenanceError struct {
+	message string
+}
+
+func (e *MaintenanceError) Error() string {
+	return e.message
+}
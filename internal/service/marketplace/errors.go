@@ -0,0 +1,21 @@
+package marketplace
+
+var (
+	ErrInvalidName        = &MarketplaceError{message: "name is required and must be at most 100 characters"}
+	ErrInvalidDescription = &MarketplaceError{message: "description must be at most 1000 characters"}
+	ErrUnknownType        = &MarketplaceError{message: "unknown strategy template type"}
+	ErrInvalidParams      = &MarketplaceError{message: "invalid strategy template params"}
+	ErrBacktestNotFound   = &MarketplaceError{message: "backtest not found"}
+	ErrBacktestMismatch   = &MarketplaceError{message: "the backtest must be of the template's strategy with the same parameters"}
+	ErrInvalidPositions   = &MarketplaceError{message: "position_ids must list 1 to 50 positions"}
+	ErrNotPublisher       = &MarketplaceError{message: "only the publisher can remove a strategy template"}
+)
+
+// MarketplaceError represents an invalid strategy template or use of one
+type MarketplaceError struct {
+	message string
+}
+
+func (e *MarketplaceError) Error() string {
+	return e.message
+}
@@ -0,0 +1,245 @@
+// Package marketplace runs the shared catalog of strategy templates. Users
+// publish a strategy's type and parameters, optionally with the stats of one
+// of their backtests of it, and others clone it onto their own positions.
+// Templates never reveal their publisher, keys or positions.
+package marketplace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/guard"
+)
+
+const (
+	maxNameLength        = 100
+	maxDescriptionLength = 1000
+	// MaxClonePositions caps the positions a template is cloned onto at once
+	MaxClonePositions = 50
+)
+
+// templateType describes the parameters of a template type and the
+// backtest strategy that simulates it
+type templateType struct {
+	params   []string // Allowed; any other param is rejected
+	validate func(params map[string]float64) bool
+	strategy string
+	// strategyParams maps template params to the backtest strategy's
+	strategyParams func(params map[string]float64) map[string]float64
+}
+
+var templateTypes = map[model.StrategyTemplateType]templateType{
+	model.StrategyTemplateDrawdownGuard: {
+		params: []string{"max_drawdown", "max_price_age"},
+		validate: func(params map[string]float64) bool {
+			drawdown, age := params["max_drawdown"], params["max_price_age"]
+			return drawdown > 0 && drawdown < 100 && age >= 0 && age == math.Trunc(age)
+		},
+		// A drawdown guard exits like the trailing stop strategy does
+		strategy: "trailing_stop",
+		strategyParams: func(params map[string]float64) map[string]float64 {
+			return map[string]float64{"trail_percent": params["max_drawdown"]}
+		},
+	},
+}
+
+// GuardAttacher attaches drawdown guards; guard.Service satisfies it
+type GuardAttacher interface {
+	Attach(ctx context.Context, userID, positionID uuid.UUID, opts guard.AttachOptions) (*model.DrawdownGuard, error)
+}
+
+// PublishRequest is a strategy template to publish
+type PublishRequest struct {
+	Name        string
+	Description string
+	Type        model.StrategyTemplateType
+	Params      map[string]float64
+	BacktestID  *uuid.UUID // One of the publisher's backtests of the template, whose stats are shared
+}
+
+// CloneResult is the outcome of cloning a template onto one position
+type CloneResult struct {
+	PositionID uuid.UUID            `json:"position_id"`
+	Guard      *model.DrawdownGuard `json:"drawdown_guard,omitempty"`
+	Error      string               `json:"error,omitempty"`
+}
+
+// Service manages the strategy template catalog
+type Service struct {
+	templates repository.StrategyTemplateRepository
+	backtests repository.BacktestRepository
+	guards    GuardAttacher
+}
+
+// NewService creates a new marketplace service
+func NewService(templates repository.StrategyTemplateRepository, backtests repository.BacktestRepository, guards GuardAttacher) *Service {
+	return &Service{
+		templates: templates,
+		backtests: backtests,
+		guards:    guards,
+	}
+}
+
+// Publish adds a strategy template to the catalog
+func (s *Service) Publish(ctx context.Context, userID uuid.UUID, req PublishRequest) (*model.StrategyTemplate, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxNameLength {
+		return nil, ErrInvalidName
+	}
+	if utf8.RuneCountInString(req.Description) > maxDescriptionLength {
+		return nil, ErrInvalidDescription
+	}
+	spec, ok := templateTypes[req.Type]
+	if !ok {
+		return nil, ErrUnknownType
+	}
+	params := make(map[string]float64, len(req.Params))
+	for name, value := range req.Params {
+		if !slices.Contains(spec.params, name) {
+			return nil, fmt.Errorf("%w: unknown param %s", ErrInvalidParams, name)
+		}
+		params[name] = value
+	}
+	if !spec.validate(params) {
+		return nil, ErrInvalidParams
+	}
+
+	now := time.Now()
+	template := &model.StrategyTemplate{
+		ID:          uuid.New(),
+		PublisherID: userID,
+		Name:        name,
+		Description: strings.TrimSpace(req.Description),
+		Type:        req.Type,
+		Params:      params,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if req.BacktestID != nil {
+		run, err := s.backtests.GetByID(ctx, *req.BacktestID)
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrBacktestNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		// Only the publisher's own runs can be shared
+		if run.UserID != userID {
+			return nil, ErrBacktestNotFound
+		}
+		if run.Strategy != spec.strategy || !sameParams(run.Params, spec.strategyParams(params)) {
+			return nil, ErrBacktestMismatch
+		}
+		template.Backtest = &model.TemplateBacktest{
+			Market:   run.Market,
+			Interval: run.Interval,
+			From:     run.From,
+			To:       run.To,
+			Metrics:  run.Metrics,
+		}
+	}
+
+	if err := s.templates.Create(ctx, template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// List returns the catalog, most used templates first
+func (s *Service) List(ctx context.Context, filter repository.StrategyTemplateFilter) ([]*model.StrategyTemplate, error) {
+	if filter.Type != "" {
+		if _, ok := templateTypes[filter.Type]; !ok {
+			return nil, ErrUnknownType
+		}
+	}
+	return s.templates.List(ctx, filter)
+}
+
+// Get returns a template of the catalog
+func (s *Service) Get(ctx context.Context, id uuid.UUID) (*model.StrategyTemplate, error) {
+	return s.templates.GetByID(ctx, id)
+}
+
+// Unpublish removes one of the user's templates from the catalog. Positions
+// it was cloned onto keep their automations.
+func (s *Service) Unpublish(ctx context.Context, userID, id uuid.UUID) error {
+	template, err := s.templates.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if template.PublisherID != userID {
+		return ErrNotPublisher
+	}
+	return s.templates.Delete(ctx, id)
+}
+
+// Clone configures a template on each of the user's positions. Positions
+// the template can't be applied to, e.g. closed ones or those of other
+// users, are reported in their result without failing the others. Clones by
+// the publisher don't count towards the template's usage.
+func (s *Service) Clone(ctx context.Context, userID, id uuid.UUID, positionIDs []uuid.UUID, dryRun bool) ([]CloneResult, error) {
+	if len(positionIDs) == 0 || len(positionIDs) > MaxClonePositions {
+		return nil, ErrInvalidPositions
+	}
+	template, err := s.templates.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]CloneResult, 0, len(positionIDs))
+	cloned := 0
+	for _, positionID := range positionIDs {
+		result := CloneResult{PositionID: positionID}
+		switch template.Type {
+		case model.StrategyTemplateDrawdownGuard:
+			result.Guard, err = s.guards.Attach(ctx, userID, positionID, guard.AttachOptions{
+				MaxDrawdown: template.Params["max_drawdown"],
+				MaxPriceAge: int(template.Params["max_price_age"]),
+				DryRun:      dryRun,
+				TemplateID:  &template.ID,
+			})
+		default:
+			err = ErrUnknownType
+		}
+		if errors.Is(err, repository.ErrNotFound) {
+			err = errors.New("position not found")
+		}
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			cloned++
+		}
+		results = append(results, result)
+	}
+
+	if cloned > 0 && userID != template.PublisherID {
+		if err := s.templates.AddUsage(ctx, template.ID, cloned); err != nil {
+			log.Printf("Error counting usage of strategy template %s: %v", template.ID, err)
+		}
+	}
+	return results, nil
+}
+
+// sameParams reports whether a backtest ran with exactly the given params
+func sameParams(run, want map[string]float64) bool {
+	if len(run) != len(want) {
+		return false
+	}
+	for name, value := range want {
+		if got, ok := run[name]; !ok || math.Abs(got-value) > 1e-9 {
+			return false
+		}
+	}
+	return true
+}
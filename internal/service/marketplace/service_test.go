@@ -0,0 +1,136 @@
+package marketplace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+	"github.com/sungminna/upbit-trading-platform/internal/service/guard"
+	"github.com/sungminna/upbit-trading-platform/pkg/perf"
+)
+
+// stubGuards attaches guards to the positions of their owner
+type stubGuards struct {
+	owners map[uuid.UUID]uuid.UUID // Position owners
+}
+
+func (s *stubGuards) Attach(ctx context.Context, userID, positionID uuid.UUID, opts guard.AttachOptions) (*model.DrawdownGuard, error) {
+	if s.owners[positionID] != userID {
+		return nil, repository.ErrNotFound
+	}
+	return &model.DrawdownGuard{PositionID: positionID, UserID: userID, MaxDrawdown: opts.MaxDrawdown, MaxPriceAge: opts.MaxPriceAge, DryRun: opts.DryRun, Active: true}, nil
+}
+
+func TestService_PublishWithBacktest(t *testing.T) {
+	store := memory.NewStore()
+	service := NewService(store.StrategyTemplates(), store.Backtests(), &stubGuards{})
+	ctx := context.Background()
+	publisher := uuid.New()
+
+	run := &model.BacktestRun{
+		ID:       uuid.New(),
+		UserID:   publisher,
+		Market:   "KRW-BTC",
+		Interval: model.CandleInterval1h,
+		Strategy: "trailing_stop",
+		Params:   map[string]float64{"trail_percent": 8},
+		From:     time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:       time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		Metrics:  perf.Metrics{TotalReturn: 0.12, Trades: 7},
+	}
+	require.NoError(t, store.Backtests().Create(ctx, run))
+
+	template, err := service.Publish(ctx, publisher, PublishRequest{
+		Name:       " BTC 8% trail ",
+		Type:       model.StrategyTemplateDrawdownGuard,
+		Params:     map[string]float64{"max_drawdown": 8},
+		BacktestID: &run.ID,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "BTC 8% trail", template.Name)
+	require.NotNil(t, template.Backtest)
+	assert.Equal(t, 0.12, template.Backtest.Metrics.TotalReturn)
+	assert.Equal(t, "KRW-BTC", template.Backtest.Market)
+
+	_, err = service.Publish(ctx, publisher, PublishRequest{
+		Name: "other", Type: model.StrategyTemplateDrawdownGuard,
+		Params: map[string]float64{"max_drawdown": 5}, BacktestID: &run.ID,
+	})
+	assert.ErrorIs(t, err, ErrBacktestMismatch)
+
+	_, err = service.Publish(ctx, uuid.New(), PublishRequest{
+		Name: "stolen", Type: model.StrategyTemplateDrawdownGuard,
+		Params: map[string]float64{"max_drawdown": 8}, BacktestID: &run.ID,
+	})
+	assert.ErrorIs(t, err, ErrBacktestNotFound, "only the publisher's own runs can be shared")
+}
+
+func TestService_PublishValidates(t *testing.T) {
+	store := memory.NewStore()
+	service := NewService(store.StrategyTemplates(), store.Backtests(), &stubGuards{})
+	ctx := context.Background()
+
+	tests := []struct {
+		req  PublishRequest
+		want error
+	}{
+		{PublishRequest{Name: " ", Type: model.StrategyTemplateDrawdownGuard, Params: map[string]float64{"max_drawdown": 5}}, ErrInvalidName},
+		{PublishRequest{Name: "x", Type: "martingale"}, ErrUnknownType},
+		{PublishRequest{Name: "x", Type: model.StrategyTemplateDrawdownGuard, Params: map[string]float64{"max_drawdown": 100}}, ErrInvalidParams},
+		{PublishRequest{Name: "x", Type: model.StrategyTemplateDrawdownGuard, Params: map[string]float64{"max_drawdown": 5, "max_price_age": 1.5}}, ErrInvalidParams},
+		{PublishRequest{Name: "x", Type: model.StrategyTemplateDrawdownGuard, Params: map[string]float64{"max_drawdown": 5, "api_key": 1}}, ErrInvalidParams},
+	}
+	for _, tt := range tests {
+		_, err := service.Publish(ctx, uuid.New(), tt.req)
+		assert.ErrorIs(t, err, tt.want, tt.req)
+	}
+}
+
+func TestService_Clone(t *testing.T) {
+	store := memory.NewStore()
+	user := uuid.New()
+	mine, theirs := uuid.New(), uuid.New()
+	guards := &stubGuards{owners: map[uuid.UUID]uuid.UUID{mine: user, theirs: uuid.New()}}
+	service := NewService(store.StrategyTemplates(), store.Backtests(), guards)
+	ctx := context.Background()
+
+	publisher := uuid.New()
+	template, err := service.Publish(ctx, publisher, PublishRequest{
+		Name: "tight", Type: model.StrategyTemplateDrawdownGuard,
+		Params: map[string]float64{"max_drawdown": 3, "max_price_age": 30},
+	})
+	require.NoError(t, err)
+
+	results, err := service.Clone(ctx, user, template.ID, []uuid.UUID{mine, theirs}, true)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	require.NotNil(t, results[0].Guard)
+	assert.Equal(t, 3.0, results[0].Guard.MaxDrawdown)
+	assert.Equal(t, 30, results[0].Guard.MaxPriceAge)
+	assert.True(t, results[0].Guard.DryRun)
+	assert.Equal(t, "position not found", results[1].Error)
+
+	// The publisher's own clones aren't counted
+	guards.owners[mine] = publisher
+	_, err = service.Clone(ctx, publisher, template.ID, []uuid.UUID{mine}, false)
+	require.NoError(t, err)
+
+	template, err = service.Get(ctx, template.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, template.UsageCount)
+
+	_, err = service.Clone(ctx, user, template.ID, nil, false)
+	assert.ErrorIs(t, err, ErrInvalidPositions)
+
+	assert.ErrorIs(t, service.Unpublish(ctx, user, template.ID), ErrNotPublisher)
+	require.NoError(t, service.Unpublish(ctx, publisher, template.ID))
+	templates, err := service.List(ctx, repository.StrategyTemplateFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, templates)
+}
@@ -0,0 +1,14 @@
+package notification
+
+var (
+	ErrUnknownChannel = &NotificationError{message: "unknown notification channel"}
+)
+
+// NotificationError represents a notification delivery error
+type NotificationError struct {
+	message string
+}
+
+func (e *NotificationError) Error() string {
+	return e.message
+}
@@ -0,0 +1,118 @@
+// Package notification delivers notifications to users through pluggable channels
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/pkg/cache"
+)
+
+// Notifier delivers notifications
+type Notifier interface {
+	Notify(ctx context.Context, notification *model.Notification) error
+}
+
+// Channel delivers notifications over one medium, e.g. a log or a chat bot
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, notification *model.Notification) error
+}
+
+// Service fans notifications out to every registered channel
+type Service struct {
+	channels      []Channel
+	throttleStore cache.Cache
+	throttle      map[string]time.Duration
+	mu            sync.RWMutex
+}
+
+var _ Notifier = (*Service)(nil)
+
+// NewService creates a new notification service
+func NewService(channels ...Channel) *Service {
+	return &Service{channels: channels}
+}
+
+// AddChannel registers a channel
+func (s *Service) AddChannel(channel Channel) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.channels = append(s.channels, channel)
+}
+
+// Notify sends a notification to every channel, unless it is throttled. All
+// channels are tried even if an earlier one fails; the returned error joins
+// all channel errors.
+func (s *Service) Notify(ctx context.Context, notification *model.Notification) error {
+	if !s.allow(ctx, notification) {
+		return nil
+	}
+
+	s.mu.RLock()
+	channels := append([]Channel(nil), s.channels...)
+	s.mu.RUnlock()
+
+	var errs []error
+	for _, channel := range channels {
+		if err := channel.Send(ctx, notification); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", channel.Name(), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// NotifyVia sends a notification through the named channel only, unless it is
+// throttled
+func (s *Service) NotifyVia(ctx context.Context, name string, notification *model.Notification) error {
+	s.mu.RLock()
+	var target Channel
+	for _, channel := range s.channels {
+		if channel.Name() == name {
+			target = channel
+			break
+		}
+	}
+	s.mu.RUnlock()
+
+	if target == nil {
+		return ErrUnknownChannel
+	}
+	if !s.allow(ctx, notification) {
+		return nil
+	}
+	return target.Send(ctx, notification)
+}
+
+// HasChannel reports whether a channel with the name is registered
+func (s *Service) HasChannel(name string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, channel := range s.channels {
+		if channel.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// LogChannel writes notifications to the application log
+type LogChannel struct{}
+
+// Name returns the channel name
+func (LogChannel) Name() string {
+	return "log"
+}
+
+// Send logs the notification
+func (LogChannel) Send(ctx context.Context, notification *model.Notification) error {
+	log.Printf("Notification for user %s: [%s] %s: %s", notification.UserID, notification.Type, notification.Title, notification.Message)
+	return nil
+}
@@ -0,0 +1,89 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/pkg/cache"
+)
+
+type recordingChannel struct {
+	name string
+	err  error
+	sent []*model.Notification
+}
+
+func (c *recordingChannel) Name() string {
+	return c.name
+}
+
+func (c *recordingChannel) Send(ctx context.Context, notification *model.Notification) error {
+	c.sent = append(c.sent, notification)
+	return c.err
+}
+
+func TestService_NotifySendsToAllChannels(t *testing.T) {
+	failing := &recordingChannel{name: "failing", err: errors.New("unreachable")}
+	working := &recordingChannel{name: "working"}
+	service := NewService(failing)
+	service.AddChannel(working)
+
+	n := model.NewNotification(uuid.New(), model.NotificationPriceAlert, "KRW-BTC", "crossed above 100", nil, time.Now())
+	err := service.Notify(context.Background(), n)
+
+	assert.ErrorContains(t, err, "failing: unreachable")
+	assert.Equal(t, []*model.Notification{n}, failing.sent)
+	assert.Equal(t, []*model.Notification{n}, working.sent)
+}
+
+func TestService_NotifyViaSendsToOneChannel(t *testing.T) {
+	log := &recordingChannel{name: "log"}
+	telegram := &recordingChannel{name: "telegram"}
+	service := NewService(log, telegram)
+
+	n := model.NewNotification(uuid.New(), model.NotificationDailySummary, "Daily summary", "", nil, time.Now())
+	assert.NoError(t, service.NotifyVia(context.Background(), "telegram", n))
+	assert.Empty(t, log.sent)
+	assert.Equal(t, []*model.Notification{n}, telegram.sent)
+
+	assert.ErrorIs(t, service.NotifyVia(context.Background(), "email", n), ErrUnknownChannel)
+	assert.True(t, service.HasChannel("log"))
+	assert.False(t, service.HasChannel("email"))
+}
+
+func TestService_NotifyThrottlesRepeats(t *testing.T) {
+	ctx := context.Background()
+	channel := &recordingChannel{name: "log"}
+	service := NewService(channel).WithThrottle(cache.NewMemoryCache(), map[string]time.Duration{
+		model.NotificationPriceAlert: time.Minute,
+	})
+	userID := uuid.New()
+
+	alert := func(dedupKey string) *model.Notification {
+		n := model.NewNotification(userID, model.NotificationPriceAlert, "KRW-BTC", "crossed above 100", nil, time.Now())
+		n.DedupKey = dedupKey
+		return n
+	}
+
+	first := alert("alert-1")
+	assert.NoError(t, service.Notify(ctx, first))
+	assert.NoError(t, service.Notify(ctx, alert("alert-1")))
+	assert.NoError(t, service.NotifyVia(ctx, "log", alert("alert-1")))
+	assert.Equal(t, []*model.Notification{first}, channel.sent)
+
+	// Other subjects, users and types aren't affected
+	assert.NoError(t, service.Notify(ctx, alert("alert-2")))
+	other := model.NewNotification(uuid.New(), model.NotificationPriceAlert, "KRW-BTC", "crossed above 100", nil, time.Now())
+	other.DedupKey = "alert-1"
+	assert.NoError(t, service.Notify(ctx, other))
+	summary := model.NewNotification(userID, model.NotificationDailySummary, "Daily summary", "", nil, time.Now())
+	assert.NoError(t, service.Notify(ctx, summary))
+	summary2 := model.NewNotification(userID, model.NotificationDailySummary, "Daily summary", "", nil, time.Now())
+	assert.NoError(t, service.Notify(ctx, summary2))
+	assert.Len(t, channel.sent, 5)
+}
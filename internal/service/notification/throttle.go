@@ -0,0 +1,55 @@
+package notification
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/pkg/cache"
+)
+
+// throttleKey prefixes the cache keys that hold throttle windows
+const throttleKey = "notification-throttle:"
+
+// DefaultThrottle is the minimum time between two notifications of a type for
+// the same user and dedup key. Types without a window are never throttled.
+var DefaultThrottle = map[string]time.Duration{
+	model.NotificationPriceAlert:       10 * time.Minute,
+	model.NotificationOrderFailed:      time.Hour,
+	model.NotificationAPIKeyRejected:   time.Hour,
+	model.NotificationExchangeDegraded: 10 * time.Minute,
+	model.NotificationExchangeRestored: 10 * time.Minute,
+	model.NotificationMaintenanceStart: 10 * time.Minute,
+	model.NotificationMaintenanceEnd:   10 * time.Minute,
+}
+
+// WithThrottle drops notifications that repeat within their type's window.
+// Windows are claimed in store, so instances sharing a cache throttle together.
+func (s *Service) WithThrottle(store cache.Cache, windows map[string]time.Duration) *Service {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.throttleStore = store
+	s.throttle = windows
+	return s
+}
+
+// allow reports whether a notification may be sent, claiming its throttle
+// window. Notifications are sent if the cache is unavailable.
+func (s *Service) allow(ctx context.Context, notification *model.Notification) bool {
+	s.mu.RLock()
+	store, window := s.throttleStore, s.throttle[notification.Type]
+	s.mu.RUnlock()
+
+	if store == nil || window <= 0 {
+		return true
+	}
+
+	key := throttleKey + notification.UserID.String() + ":" + notification.Type + ":" + notification.DedupKey
+	claimed, err := store.SetNX(ctx, key, []byte{1}, window)
+	if err != nil {
+		log.Printf("Error throttling notification for user %s: %v", notification.UserID, err)
+		return true
+	}
+	return claimed
+}
@@ -0,0 +1,20 @@
+package onboarding
+
+var (
+	ErrInvalidMode        = &OnboardingError{message: "mode must be paper or live"}
+	ErrInvalidBalance     = &OnboardingError{message: "starting balance must be positive"}
+	ErrAlreadyProvisioned = &OnboardingError{message: "you already have a paper account; reset it to start over"}
+	ErrNoPaperAccount     = &OnboardingError{message: "create a paper account first"}
+	ErrNoLiveKey          = &OnboardingError{message: "add an Upbit API key before trading live"}
+	ErrOpenPositions      = &OnboardingError{message: "close your open positions first"}
+	ErrOpenOrders         = &OnboardingError{message: "wait for your open orders to complete or cancel them first"}
+)
+
+// OnboardingError represents an onboarding step the user can't take yet
+type OnboardingError struct {
+	message string
+}
+
+func (e *OnboardingError) Error() string {
+	return e.message
+}
@@ -0,0 +1,309 @@
+// Package onboarding walks new users from a paper account to live trading.
+// A paper account is traded through a paper API key, which coexists with the
+// user's Upbit keys; the active key decides whether the user's orders and
+// automations run on paper or live.
+package onboarding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/paper"
+)
+
+const (
+	// ModePaper trades the user's paper account
+	ModePaper = "paper"
+	// ModeLive trades the user's Upbit account
+	ModeLive = "live"
+)
+
+// Onboarding steps, in the order users take them
+const (
+	StepPaperAccount = "paper_account"
+	StepFirstOrder   = "first_order"
+	StepLiveKey      = "live_api_key"
+	StepGoLive       = "go_live"
+)
+
+// Step is one step of the onboarding wizard
+type Step struct {
+	Name string `json:"name"`
+	Done bool   `json:"done"`
+}
+
+// Status is where a user is in onboarding
+type Status struct {
+	Mode         string              `json:"mode,omitempty"` // Empty until the user has an API key
+	PaperAccount *model.PaperAccount `json:"paper_account,omitempty"`
+	PaperKRW     float64             `json:"paper_krw"` // Held by the paper account, available or locked
+	Steps        []Step              `json:"steps"`
+}
+
+// ClientInvalidator drops cached exchange clients; trading.Engine
+// satisfies it
+type ClientInvalidator interface {
+	InvalidateClient(userID uuid.UUID)
+}
+
+// BalanceInvalidator drops cached balances; balance.Service satisfies it
+type BalanceInvalidator interface {
+	Invalidate(ctx context.Context, userID uuid.UUID)
+}
+
+// Service provisions paper accounts and switches users between paper and
+// live trading
+type Service struct {
+	apiKeys   repository.UserAPIKeyRepository
+	accounts  repository.PaperAccountRepository
+	positions repository.PositionRepository
+	orders    repository.OrderRepository
+	exchange  *paper.Exchange
+	balance   float64
+	clients   ClientInvalidator  // Optional
+	balances  BalanceInvalidator // Optional
+}
+
+// NewService creates a new onboarding service
+func NewService(
+	apiKeys repository.UserAPIKeyRepository,
+	accounts repository.PaperAccountRepository,
+	positions repository.PositionRepository,
+	orders repository.OrderRepository,
+	exchange *paper.Exchange,
+) *Service {
+	return &Service{
+		apiKeys:   apiKeys,
+		accounts:  accounts,
+		positions: positions,
+		orders:    orders,
+		exchange:  exchange,
+		balance:   paper.DefaultBalance,
+	}
+}
+
+// WithStartingBalance sets the virtual KRW new paper accounts open with
+func (s *Service) WithStartingBalance(balance float64) (*Service, error) {
+	if balance <= 0 {
+		return nil, ErrInvalidBalance
+	}
+	s.balance = balance
+	return s, nil
+}
+
+// WithInvalidation drops the user's cached exchange client and balances
+// when they switch modes, so the next request trades the new account
+// without waiting for the caches to expire
+func (s *Service) WithInvalidation(clients ClientInvalidator, balances BalanceInvalidator) *Service {
+	s.clients = clients
+	s.balances = balances
+	return s
+}
+
+// Status returns where a user is in onboarding
+func (s *Service) Status(ctx context.Context, userID uuid.UUID) (*Status, error) {
+	keys, err := s.apiKeys.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	status := &Status{}
+	hasLiveKey := false
+	for _, key := range keys {
+		if key.IsActive && status.Mode == "" {
+			status.Mode = modeOf(key)
+		}
+		hasLiveKey = hasLiveKey || !key.Paper
+	}
+
+	account, err := s.accounts.GetByUser(ctx, userID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("failed to load paper account: %w", err)
+	}
+	if account != nil {
+		status.PaperAccount = account
+		if status.PaperKRW, err = paper.Balance(account); err != nil {
+			return nil, err
+		}
+	}
+
+	orders, err := s.orders.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders: %w", err)
+	}
+
+	status.Steps = []Step{
+		{Name: StepPaperAccount, Done: account != nil},
+		{Name: StepFirstOrder, Done: len(orders) > 0},
+		{Name: StepLiveKey, Done: hasLiveKey},
+		{Name: StepGoLive, Done: status.Mode == ModeLive},
+	}
+	return status, nil
+}
+
+// ProvisionPaperAccount opens a paper account for the user, holding the
+// starting balance in virtual KRW. Users without an active API key start
+// trading it right away; others keep trading live until they switch.
+func (s *Service) ProvisionPaperAccount(ctx context.Context, userID uuid.UUID) (*Status, error) {
+	if _, err := s.accounts.GetByUser(ctx, userID); err == nil {
+		return nil, ErrAlreadyProvisioned
+	} else if !errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("failed to load paper account: %w", err)
+	}
+
+	state, err := paper.NewState(s.balance)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open paper account: %w", err)
+	}
+	now := time.Now()
+	account := &model.PaperAccount{
+		ID:              uuid.New(),
+		UserID:          userID,
+		AccessKey:       paper.NewAccessKey(),
+		StartingBalance: s.balance,
+		State:           state,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if err := s.accounts.Create(ctx, account); err != nil {
+		return nil, err
+	}
+
+	_, err = s.apiKeys.GetActiveByUserID(ctx, userID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("failed to load API key: %w", err)
+	}
+	key := &model.UserAPIKey{
+		ID:          uuid.New(),
+		UserID:      userID,
+		AccessKey:   account.AccessKey,
+		Description: "Paper trading",
+		Paper:       true,
+		IsActive:    errors.Is(err, repository.ErrNotFound),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.apiKeys.Create(ctx, key); err != nil {
+		return nil, err
+	}
+	if key.IsActive {
+		s.invalidate(ctx, userID)
+	}
+
+	log.Printf("Opened paper account for user %s with %.0f KRW", userID, s.balance)
+	return s.Status(ctx, userID)
+}
+
+// ResetPaperAccount empties the user's paper account back to its starting
+// balance. While the user trades on paper, their positions and orders must
+// be closed first, as they would no longer match the account.
+func (s *Service) ResetPaperAccount(ctx context.Context, userID uuid.UUID) (*Status, error) {
+	account, err := s.accounts.GetByUser(ctx, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, ErrNoPaperAccount
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load paper account: %w", err)
+	}
+
+	active, err := s.apiKeys.GetActiveByUserID(ctx, userID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("failed to load API key: %w", err)
+	}
+	if active != nil && active.Paper {
+		if err := s.checkIdle(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.exchange.Reset(ctx, account); err != nil {
+		return nil, err
+	}
+	s.invalidate(ctx, userID)
+	return s.Status(ctx, userID)
+}
+
+// SetMode switches the user between paper and live trading by activating
+// their paper key or their newest Upbit key. Positions and orders belong to
+// the account they were opened on, so they must be closed first.
+func (s *Service) SetMode(ctx context.Context, userID uuid.UUID, mode string) (*Status, error) {
+	if mode != ModePaper && mode != ModeLive {
+		return nil, ErrInvalidMode
+	}
+
+	keys, err := s.apiKeys.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+	var target *model.UserAPIKey
+	for _, key := range keys {
+		if modeOf(key) == mode {
+			target = key
+			break
+		}
+	}
+	if target == nil {
+		if mode == ModePaper {
+			return nil, ErrNoPaperAccount
+		}
+		return nil, ErrNoLiveKey
+	}
+
+	if !target.IsActive {
+		if err := s.checkIdle(ctx, userID); err != nil {
+			return nil, err
+		}
+		if err := s.apiKeys.SetActive(ctx, userID, target.ID); err != nil {
+			return nil, fmt.Errorf("failed to activate API key: %w", err)
+		}
+		s.invalidate(ctx, userID)
+		log.Printf("User %s switched to %s trading", userID, mode)
+	}
+	return s.Status(ctx, userID)
+}
+
+// checkIdle rejects switching accounts while the user has open positions or
+// orders
+func (s *Service) checkIdle(ctx context.Context, userID uuid.UUID) error {
+	positions, err := s.positions.ListByUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to list positions: %w", err)
+	}
+	for _, p := range positions {
+		if p.Status == model.PositionStatusOpen {
+			return ErrOpenPositions
+		}
+	}
+
+	orders, err := s.orders.ListByUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to list orders: %w", err)
+	}
+	for _, o := range orders {
+		if !o.Status.IsFinal() {
+			return ErrOpenOrders
+		}
+	}
+	return nil
+}
+
+func (s *Service) invalidate(ctx context.Context, userID uuid.UUID) {
+	if s.clients != nil {
+		s.clients.InvalidateClient(userID)
+	}
+	if s.balances != nil {
+		s.balances.Invalidate(ctx, userID)
+	}
+}
+
+func modeOf(key *model.UserAPIKey) string {
+	if key.Paper {
+		return ModePaper
+	}
+	return ModeLive
+}
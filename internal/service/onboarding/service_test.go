@@ -0,0 +1,140 @@
+package onboarding
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/exchange"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/paper"
+	"github.com/sungminna/upbit-trading-platform/pkg/cache"
+)
+
+type stubBooks struct{}
+
+func (stubBooks) GetOrderbook(ctx context.Context, market string) (*model.Orderbook, error) {
+	return &model.Orderbook{
+		Market:         market,
+		Timestamp:      time.Now().UnixMilli(),
+		OrderbookUnits: []model.OrderbookUnit{{AskPrice: 1000, BidPrice: 990, AskSize: 1000, BidSize: 1000}},
+	}, nil
+}
+
+// recordingInvalidator records whose caches were dropped
+type recordingInvalidator struct {
+	users []uuid.UUID
+}
+
+func (r *recordingInvalidator) InvalidateClient(userID uuid.UUID) {
+	r.users = append(r.users, userID)
+}
+
+func (r *recordingInvalidator) Invalidate(ctx context.Context, userID uuid.UUID) {}
+
+func newTestService(t *testing.T) (*Service, *memory.Store, *paper.Exchange, *recordingInvalidator) {
+	t.Helper()
+	store := memory.NewStore()
+	paperExchange := paper.NewExchange(store.PaperAccounts(), stubBooks{}, cache.NewMemoryCache())
+	invalidator := &recordingInvalidator{}
+	service, err := NewService(store.APIKeys(), store.PaperAccounts(), store.Positions(), store.Orders(), paperExchange).
+		WithStartingBalance(1_000_000)
+	require.NoError(t, err)
+	return service.WithInvalidation(invalidator, invalidator), store, paperExchange, invalidator
+}
+
+func ptr(s string) *string { return &s }
+
+func TestService_ProvisionPaperAccount(t *testing.T) {
+	service, store, paperExchange, invalidator := newTestService(t)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	status, err := service.Status(ctx, userID)
+	require.NoError(t, err)
+	assert.Empty(t, status.Mode)
+	assert.False(t, status.Steps[0].Done)
+
+	status, err = service.ProvisionPaperAccount(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, ModePaper, status.Mode, "users without a key start on paper")
+	assert.Equal(t, 1_000_000.0, status.PaperKRW)
+	assert.True(t, status.Steps[0].Done)
+	assert.Equal(t, []uuid.UUID{userID}, invalidator.users)
+
+	_, err = service.ProvisionPaperAccount(ctx, userID)
+	assert.ErrorIs(t, err, ErrAlreadyProvisioned)
+
+	// The active paper key trades the paper account
+	key, err := store.APIKeys().GetActiveByUserID(ctx, userID)
+	require.NoError(t, err)
+	assert.True(t, key.Paper)
+	_, err = paperExchange.NewClient(key.AccessKey, key.SecretKey).PlaceOrder(ctx, exchange.OrderRequest{
+		Market: "KRW-BTC", Side: "bid", OrdType: "price", Price: ptr("100000"),
+	})
+	require.NoError(t, err)
+
+	status, err = service.ResetPaperAccount(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, 1_000_000.0, status.PaperKRW)
+}
+
+func TestService_PaperAndLiveCoexist(t *testing.T) {
+	service, store, _, _ := newTestService(t)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	live := &model.UserAPIKey{ID: uuid.New(), UserID: userID, AccessKey: "upbit", SecretKey: "secret", IsActive: true, CreatedAt: time.Now()}
+	require.NoError(t, store.APIKeys().Create(ctx, live))
+
+	status, err := service.ProvisionPaperAccount(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, ModeLive, status.Mode, "users trading live stay live")
+
+	status, err = service.SetMode(ctx, userID, ModePaper)
+	require.NoError(t, err)
+	assert.Equal(t, ModePaper, status.Mode)
+	assert.False(t, status.Steps[3].Done)
+
+	// An open paper position keeps the user on paper
+	position := model.NewPosition(userID, "KRW-BTC", model.PositionSideLong, 1000, 1, time.Now())
+	require.NoError(t, store.Positions().Create(ctx, position))
+	_, err = service.SetMode(ctx, userID, ModeLive)
+	assert.ErrorIs(t, err, ErrOpenPositions)
+	_, err = service.ResetPaperAccount(ctx, userID)
+	assert.ErrorIs(t, err, ErrOpenPositions)
+
+	position.Status = model.PositionStatusClosed
+	require.NoError(t, store.Positions().Update(ctx, position))
+	status, err = service.SetMode(ctx, userID, ModeLive)
+	require.NoError(t, err)
+	assert.Equal(t, ModeLive, status.Mode)
+	assert.True(t, status.Steps[2].Done)
+	assert.True(t, status.Steps[3].Done)
+
+	_, err = service.SetMode(ctx, userID, "demo")
+	assert.ErrorIs(t, err, ErrInvalidMode)
+}
+
+func TestService_SetModeRequiresTheAccount(t *testing.T) {
+	service, _, _, _ := newTestService(t)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	_, err := service.SetMode(ctx, userID, ModePaper)
+	assert.ErrorIs(t, err, ErrNoPaperAccount)
+	_, err = service.ResetPaperAccount(ctx, userID)
+	assert.ErrorIs(t, err, ErrNoPaperAccount)
+
+	_, err = service.ProvisionPaperAccount(ctx, userID)
+	require.NoError(t, err)
+	_, err = service.SetMode(ctx, userID, ModeLive)
+	assert.ErrorIs(t, err, ErrNoLiveKey)
+
+	_, err = NewService(nil, nil, nil, nil, nil).WithStartingBalance(0)
+	assert.ErrorIs(t, err, ErrInvalidBalance)
+}
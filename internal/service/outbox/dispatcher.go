@@ -0,0 +1,120 @@
+package outbox
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/pkg/recovery"
+)
+
+const (
+	defaultPollInterval = 1 * time.Second
+	defaultBatchSize    = 100
+)
+
+// Publisher delivers an outbox event to its destination (event bus, webhooks, ...)
+type Publisher interface {
+	Publish(ctx context.Context, event *model.OutboxEvent) error
+}
+
+// Dispatcher relays outbox events to a publisher with at-least-once delivery:
+// an event is only marked dispatched after the publisher succeeded, so it is
+// retried on the next poll if publishing or the commit fails
+type Dispatcher struct {
+	uow          repository.UnitOfWork
+	publisher    Publisher
+	pollInterval time.Duration
+	batchSize    int
+	mu           sync.Mutex
+	isRunning    bool
+	stopChan     chan struct{}
+}
+
+// NewDispatcher creates a new outbox dispatcher
+func NewDispatcher(uow repository.UnitOfWork, publisher Publisher) *Dispatcher {
+	return &Dispatcher{
+		uow:          uow,
+		publisher:    publisher,
+		pollInterval: defaultPollInterval,
+		batchSize:    defaultBatchSize,
+		stopChan:     make(chan struct{}),
+	}
+}
+
+// Start starts relaying events
+func (d *Dispatcher) Start(ctx context.Context) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.isRunning {
+		return
+	}
+	d.isRunning = true
+
+	go d.run(ctx)
+}
+
+// Stop stops the dispatcher
+func (d *Dispatcher) Stop() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.isRunning {
+		return
+	}
+
+	close(d.stopChan)
+	d.isRunning = false
+}
+
+func (d *Dispatcher) run(ctx context.Context) {
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.stopChan:
+			return
+		case <-ticker.C:
+			recovery.Do("outbox.poll", func() {
+				if err := d.DispatchPending(ctx); err != nil {
+					log.Printf("Error dispatching outbox events: %v", err)
+				}
+			})
+		}
+	}
+}
+
+// DispatchPending relays one batch of pending events
+func (d *Dispatcher) DispatchPending(ctx context.Context) error {
+	return d.uow.Do(ctx, func(tx repository.Tx) error {
+		events, err := tx.Outbox().ListPending(ctx, d.batchSize)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			// A panicking subscriber marks the event failed like an erroring one
+			err := recovery.Call("outbox.publish", func() error { return d.publisher.Publish(ctx, event) })
+			if err != nil {
+				log.Printf("Error publishing outbox event %s (%s): %v", event.ID, event.EventType, err)
+				if err := tx.Outbox().MarkFailed(ctx, event.ID, err.Error()); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if err := tx.Outbox().MarkDispatched(ctx, event.ID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,200 @@
+package portfolio
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// Equity curve intervals
+const (
+	IntervalHour = "1h" // Hourly snapshots
+	IntervalDay  = "1d" // Daily snapshots
+	IntervalWeek = "1w" // Every seventh daily snapshot
+)
+
+// EquityCurve is a user's equity over time with its time-weighted return.
+// Values are in Currency at the rate of their point's time.
+type EquityCurve struct {
+	Currency string        `json:"currency"`
+	Interval string        `json:"interval"`
+	Points   []EquityPoint `json:"points"`
+	// TimeWeightedReturn compounds the period returns, so deposits and
+	// withdrawals don't count as gains or losses. Ratios are fractions.
+	TimeWeightedReturn float64 `json:"time_weighted_return"`
+	NetFlows           float64 `json:"net_flows"` // Deposits less withdrawals
+}
+
+// EquityPoint is the equity at a snapshot
+type EquityPoint struct {
+	Time             time.Time `json:"time"`
+	Equity           float64   `json:"equity"`
+	NetFlow          float64   `json:"net_flow"` // Deposits less withdrawals since the previous point
+	Return           float64   `json:"return"`   // Since the previous point, excluding NetFlow
+	CumulativeReturn float64   `json:"cumulative_return"`
+}
+
+// EquityCurve returns the user's equity at each snapshot in [from, to).
+// Deposits and withdrawals are the part of each balance change not explained
+// by the executions of the user's orders; coins are valued at the later
+// snapshot's price. They are assumed to happen halfway through the period
+// (modified Dietz), and period returns are chained into the time-weighted
+// return. Without executions every balance change counts as performance.
+// In a display currency other than KRW, each point and its flows are valued
+// at the rate of its time, so returns include the currency's moves.
+func (s *Service) EquityCurve(ctx context.Context, userID uuid.UUID, from, to time.Time, interval, currency string) (*EquityCurve, error) {
+	period := model.SnapshotPeriodDaily
+	switch interval {
+	case IntervalHour:
+		period = model.SnapshotPeriodHourly
+	case IntervalDay, IntervalWeek:
+	default:
+		return nil, ErrInvalidInterval
+	}
+
+	snapshots, err := s.snapshots.ListByUser(ctx, userID, period, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots: %w", err)
+	}
+	if interval == IntervalWeek {
+		snapshots = sampleWeekly(snapshots)
+	}
+
+	fills, err := s.fills(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	quote, err := s.quote(ctx, currency, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	curve := &EquityCurve{Currency: quote.Code(), Interval: interval, Points: make([]EquityPoint, 0, len(snapshots))}
+	growth := 1.0
+	for i, snapshot := range snapshots {
+		point := EquityPoint{Time: snapshot.TakenAt, Equity: quote.Convert(snapshot.Equity, snapshot.TakenAt)}
+		if i > 0 {
+			prev := curve.Points[i-1]
+			point.NetFlow = quote.Convert(netFlow(snapshots[i-1], snapshot, fills), snapshot.TakenAt)
+			if base := prev.Equity + point.NetFlow/2; base > 0 {
+				point.Return = (point.Equity - prev.Equity - point.NetFlow) / base
+			}
+			growth *= 1 + point.Return
+			curve.NetFlows += point.NetFlow
+		}
+		point.CumulativeReturn = growth - 1
+		curve.Points = append(curve.Points, point)
+	}
+	curve.TimeWeightedReturn = growth - 1
+
+	return curve, nil
+}
+
+// fill is an execution of the user's order in a market
+type fill struct {
+	market    string
+	side      model.OrderSide
+	execution *model.OrderExecution
+}
+
+// fills returns the executions of the user's orders in [from, to)
+func (s *Service) fills(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]fill, error) {
+	if s.orders == nil || s.executions == nil {
+		return nil, nil
+	}
+
+	orders, err := s.orders.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders: %w", err)
+	}
+
+	var fills []fill
+	for _, order := range orders {
+		if order.ExecutedQuantity == 0 || !order.CreatedAt.Before(to) {
+			continue
+		}
+		executions, err := s.executions.ListByOrder(ctx, order.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list executions: %w", err)
+		}
+		for _, e := range executions {
+			if !e.CreatedAt.Before(from) && e.CreatedAt.Before(to) {
+				fills = append(fills, fill{market: order.Market, side: order.Side, execution: e})
+			}
+		}
+	}
+	return fills, nil
+}
+
+// netFlow returns the KRW value moved into the account between two
+// snapshots other than by the fills in between
+func netFlow(prev, next *model.AccountSnapshot, fills []fill) float64 {
+	// Expected change of each currency's quantity from trading
+	traded := make(map[string]float64)
+	for _, f := range fills {
+		e := f.execution
+		if !e.CreatedAt.After(prev.TakenAt) || e.CreatedAt.After(next.TakenAt) {
+			continue
+		}
+		quote, base, ok := strings.Cut(f.market, "-")
+		if !ok {
+			continue
+		}
+		if f.side == model.OrderSideBid {
+			traded[base] += e.Quantity
+			traded[quote] -= e.Total + e.Fee
+		} else {
+			traded[base] -= e.Quantity
+			traded[quote] += e.Total - e.Fee
+		}
+	}
+
+	quantities := func(snapshot *model.AccountSnapshot) map[string]model.BalanceSnapshot {
+		balances := make(map[string]model.BalanceSnapshot, len(snapshot.Balances))
+		for _, b := range snapshot.Balances {
+			balances[b.Currency] = b
+		}
+		return balances
+	}
+	before, after := quantities(prev), quantities(next)
+
+	currencies := make(map[string]bool)
+	for currency := range before {
+		currencies[currency] = true
+	}
+	for currency := range after {
+		currencies[currency] = true
+	}
+
+	var flow float64
+	for currency := range currencies {
+		b, a := before[currency], after[currency]
+		moved := (a.Balance + a.Locked) - (b.Balance + b.Locked) - traded[currency]
+		// Coins gone by the later snapshot are valued at the earlier price
+		price := a.Price
+		if price == 0 {
+			price = b.Price
+		}
+		if currency == "KRW" {
+			price = 1
+		}
+		flow += moved * price
+	}
+	return flow
+}
+
+// sampleWeekly keeps the first snapshot and each one at least a week after
+// the last kept
+func sampleWeekly(snapshots []*model.AccountSnapshot) []*model.AccountSnapshot {
+	var sampled []*model.AccountSnapshot
+	for _, snapshot := range snapshots {
+		if len(sampled) == 0 || !snapshot.TakenAt.Before(sampled[len(sampled)-1].TakenAt.AddDate(0, 0, 7)) {
+			sampled = append(sampled, snapshot)
+		}
+	}
+	return sampled
+}
@@ -0,0 +1,14 @@
+package portfolio
+
+var (
+	ErrInvalidInterval = &PortfolioError{message: "interval must be 1h, 1d or 1w"}
+)
+
+// PortfolioError represents an invalid portfolio request
+type PortfolioError struct {
+	message string
+}
+
+func (e *PortfolioError) Error() string {
+	return e.message
+}
@@ -0,0 +1,271 @@
+// Package portfolio aggregates a user's balances, positions and snapshots
+// into a portfolio view
+package portfolio
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/valuation"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/quotation"
+)
+
+// baselineWindow is how far back the daily change looks for a daily snapshot
+const baselineWindow = 48 * time.Hour
+
+// BalanceSource provides users' cached exchange balances; balance.Service
+// satisfies it
+type BalanceSource interface {
+	Balances(ctx context.Context, userID uuid.UUID) (*model.AccountBalances, error)
+}
+
+// TickerSource provides current prices; gateway.QuotationAPI satisfies it
+type TickerSource interface {
+	GetTicker(ctx context.Context, markets []string) ([]quotation.Ticker, error)
+}
+
+// Valuer quotes display currencies; valuation.Service satisfies it
+type Valuer interface {
+	Quote(ctx context.Context, currency string, from, to time.Time) (*valuation.Quote, error)
+}
+
+// Portfolio is a user's exchange account valued at current prices. Values
+// and prices are in Currency.
+type Portfolio struct {
+	Currency      string          `json:"currency"`
+	Equity        float64         `json:"equity"` // Cash plus holdings
+	Cash          float64         `json:"cash"`   // KRW, including funds locked by open orders
+	HoldingsValue float64         `json:"holdings_value"`
+	UnrealizedPnL float64         `json:"unrealized_pnl"` // Of holdings, against their average buy price
+	Allocation    []Allocation    `json:"allocation"`     // Largest first, cash included
+	Positions     []PositionValue `json:"positions"`      // Open positions tracked by the platform
+	DailyChange   *DailyChange    `json:"daily_change,omitempty"`
+	SyncedAt      time.Time       `json:"synced_at"` // When balances were read from the exchange
+	PricedAt      time.Time       `json:"priced_at"`
+}
+
+// Allocation is the share of equity held in one currency
+type Allocation struct {
+	Currency      string  `json:"currency"`
+	Quantity      float64 `json:"quantity"` // Including locked
+	Price         float64 `json:"price"`    // The average buy price when the coin has no KRW market
+	Value         float64 `json:"value"`
+	Percent       float64 `json:"percent"` // Of equity
+	AvgBuyPrice   float64 `json:"avg_buy_price,omitempty"`
+	UnrealizedPnL float64 `json:"unrealized_pnl,omitempty"`
+}
+
+// PositionValue is an open position marked to market
+type PositionValue struct {
+	PositionID    uuid.UUID `json:"position_id"`
+	Market        string    `json:"market"`
+	Quantity      float64   `json:"quantity"`
+	EntryPrice    float64   `json:"entry_price"`
+	Price         float64   `json:"price"`
+	Value         float64   `json:"value"`
+	UnrealizedPnL float64   `json:"unrealized_pnl"`
+}
+
+// DailyChange compares equity with the latest daily snapshot
+type DailyChange struct {
+	Since   time.Time `json:"since"`  // When the baseline snapshot was taken
+	Equity  float64   `json:"equity"` // Baseline equity
+	Change  float64   `json:"change"`
+	Percent float64   `json:"percent"`
+}
+
+// Service builds portfolio views
+type Service struct {
+	balances  BalanceSource
+	positions repository.PositionRepository
+	snapshots repository.SnapshotRepository
+	tickers   TickerSource
+	// Optional; separate deposits and withdrawals from trading in the equity curve
+	orders     repository.OrderRepository
+	executions repository.OrderExecutionRepository
+	valuations Valuer // Optional; values in display currencies other than KRW
+}
+
+// NewService creates a new portfolio service
+func NewService(balances BalanceSource, positions repository.PositionRepository, snapshots repository.SnapshotRepository, tickers TickerSource) *Service {
+	return &Service{
+		balances:  balances,
+		positions: positions,
+		snapshots: snapshots,
+		tickers:   tickers,
+	}
+}
+
+// WithExecutions lets the equity curve tell deposits and withdrawals apart
+// from trading
+func (s *Service) WithExecutions(orders repository.OrderRepository, executions repository.OrderExecutionRepository) *Service {
+	s.orders = orders
+	s.executions = executions
+	return s
+}
+
+// WithValuation lets views be valued in display currencies other than KRW
+func (s *Service) WithValuation(valuations Valuer) *Service {
+	s.valuations = valuations
+	return s
+}
+
+// Portfolio values the user's balances and open positions at current prices
+// in a display currency and compares equity with the latest daily snapshot
+func (s *Service) Portfolio(ctx context.Context, userID uuid.UUID, now time.Time, currency string) (*Portfolio, error) {
+	quote, err := s.quote(ctx, currency, now.Add(-baselineWindow), now)
+	if err != nil {
+		return nil, err
+	}
+	balances, err := s.balances.Balances(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load balances: %w", err)
+	}
+	positions, err := s.positions.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list positions: %w", err)
+	}
+
+	var open []*model.Position
+	var markets []string
+	for _, b := range balances.Balances {
+		if b.Currency != "KRW" {
+			markets = append(markets, "KRW-"+b.Currency)
+		}
+	}
+	for _, p := range positions {
+		if p.Status == model.PositionStatusOpen {
+			open = append(open, p)
+			markets = append(markets, p.Market)
+		}
+	}
+	prices, err := s.fetchPrices(ctx, markets)
+	if err != nil {
+		return nil, err
+	}
+
+	portfolio := &Portfolio{
+		Currency:   quote.Code(),
+		Allocation: make([]Allocation, 0, len(balances.Balances)),
+		Positions:  make([]PositionValue, 0, len(open)),
+		SyncedAt:   balances.SyncedAt,
+		PricedAt:   now,
+	}
+	for _, b := range balances.Balances {
+		a := Allocation{Currency: b.Currency, Quantity: b.Balance + b.Locked, Price: quote.Convert(1, now)}
+		if b.Currency != "KRW" {
+			// Coins without a KRW market (e.g. delisted) are valued at cost
+			price, ok := prices["KRW-"+b.Currency]
+			if !ok {
+				price = b.AvgBuyPrice
+			}
+			a.Price = quote.Convert(price, now)
+			a.AvgBuyPrice = quote.Convert(b.AvgBuyPrice, now)
+			a.UnrealizedPnL = (a.Price - a.AvgBuyPrice) * a.Quantity
+		}
+		a.Value = a.Quantity * a.Price
+
+		if b.Currency == "KRW" {
+			portfolio.Cash += a.Value
+		} else {
+			portfolio.HoldingsValue += a.Value
+			portfolio.UnrealizedPnL += a.UnrealizedPnL
+		}
+		portfolio.Allocation = append(portfolio.Allocation, a)
+	}
+	portfolio.Equity = portfolio.Cash + portfolio.HoldingsValue
+
+	for i := range portfolio.Allocation {
+		portfolio.Allocation[i].Percent = percentOf(portfolio.Allocation[i].Value, portfolio.Equity)
+	}
+	sort.SliceStable(portfolio.Allocation, func(i, j int) bool {
+		return portfolio.Allocation[i].Value > portfolio.Allocation[j].Value
+	})
+
+	for _, p := range open {
+		price, ok := prices[p.Market]
+		if !ok {
+			price = p.EntryPrice
+		}
+		portfolio.Positions = append(portfolio.Positions, PositionValue{
+			PositionID:    p.ID,
+			Market:        p.Market,
+			Quantity:      p.Quantity,
+			EntryPrice:    quote.Convert(p.EntryPrice, now),
+			Price:         quote.Convert(price, now),
+			Value:         quote.Convert(p.Quantity*price, now),
+			UnrealizedPnL: quote.Convert(p.CalculateUnrealizedPnL(price), now),
+		})
+	}
+
+	if s.snapshots != nil {
+		snapshots, err := s.snapshots.ListByUser(ctx, userID, model.SnapshotPeriodDaily, now.Add(-baselineWindow), now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list snapshots: %w", err)
+		}
+		if len(snapshots) > 0 {
+			// The baseline is valued at its own time's rate, so the change
+			// includes moves of the display currency against KRW
+			baseline := snapshots[len(snapshots)-1]
+			equity := quote.Convert(baseline.Equity, baseline.TakenAt)
+			portfolio.DailyChange = &DailyChange{
+				Since:   baseline.TakenAt,
+				Equity:  equity,
+				Change:  portfolio.Equity - equity,
+				Percent: percentOf(portfolio.Equity-equity, equity),
+			}
+		}
+	}
+
+	return portfolio, nil
+}
+
+// quote returns the quote of a display currency over [from, to); nil for KRW
+func (s *Service) quote(ctx context.Context, currency string, from, to time.Time) (*valuation.Quote, error) {
+	if valuation.IsBase(currency) {
+		return nil, nil
+	}
+	if s.valuations == nil {
+		return nil, valuation.ErrUnsupportedCurrency
+	}
+	return s.valuations.Quote(ctx, currency, from, to)
+}
+
+// fetchPrices returns the current price of every market with a single
+// ticker request. Markets missing from the response are left out.
+func (s *Service) fetchPrices(ctx context.Context, markets []string) (map[string]float64, error) {
+	var unique []string
+	seen := make(map[string]bool)
+	for _, market := range markets {
+		if !seen[market] {
+			seen[market] = true
+			unique = append(unique, market)
+		}
+	}
+
+	prices := make(map[string]float64, len(unique))
+	if len(unique) == 0 {
+		return prices, nil
+	}
+
+	tickers, err := s.tickers.GetTicker(ctx, unique)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prices: %w", err)
+	}
+	for _, ticker := range tickers {
+		prices[ticker.Market] = ticker.TradePrice
+	}
+	return prices, nil
+}
+
+func percentOf(value, total float64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return value / total * 100
+}
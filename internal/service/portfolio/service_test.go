@@ -0,0 +1,130 @@
+package portfolio
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/quotation"
+)
+
+type staticBalances struct {
+	balances *model.AccountBalances
+}
+
+func (s *staticBalances) Balances(ctx context.Context, userID uuid.UUID) (*model.AccountBalances, error) {
+	return s.balances, nil
+}
+
+type staticTickers map[string]float64
+
+func (s staticTickers) GetTicker(ctx context.Context, markets []string) ([]quotation.Ticker, error) {
+	var tickers []quotation.Ticker
+	for _, market := range markets {
+		if price, ok := s[market]; ok {
+			tickers = append(tickers, quotation.Ticker{Market: market, TradePrice: price})
+		}
+	}
+	return tickers, nil
+}
+
+func TestService_Portfolio(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	userID := uuid.New()
+	now := time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC)
+
+	balances := &staticBalances{balances: &model.AccountBalances{
+		UserID: userID,
+		Balances: []model.Balance{
+			{Currency: "KRW", Balance: 400000, Locked: 100000},
+			{Currency: "BTC", Balance: 0.004, Locked: 0.001, AvgBuyPrice: 80000000},
+			{Currency: "OLD", Balance: 10, AvgBuyPrice: 1000}, // Delisted, valued at cost
+		},
+	}}
+	tickers := staticTickers{"KRW-BTC": 100000000}
+
+	position := model.NewPosition(userID, "KRW-BTC", model.PositionSideLong, 90000000, 0.005, time.Now())
+	require.NoError(t, store.Positions().Create(ctx, position))
+
+	yesterday := model.NewAccountSnapshot(userID, model.SnapshotPeriodDaily, now.Truncate(24*time.Hour), []model.BalanceSnapshot{
+		{Currency: "KRW", Balance: 1000000, Price: 1, Value: 1000000},
+	}, nil, time.Now())
+	require.NoError(t, store.Snapshots().Create(ctx, yesterday))
+
+	service := NewService(balances, store.Positions(), store.Snapshots(), tickers)
+	portfolio, err := service.Portfolio(ctx, userID, now, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, 500000.0, portfolio.Cash)
+	assert.InDelta(t, 510000, portfolio.HoldingsValue, 1e-6)
+	assert.InDelta(t, 1010000, portfolio.Equity, 1e-6)
+	assert.InDelta(t, 100000, portfolio.UnrealizedPnL, 1e-6)
+
+	require.Len(t, portfolio.Allocation, 3)
+	assert.Equal(t, "KRW", portfolio.Allocation[0].Currency, "largest first")
+	assert.Equal(t, "BTC", portfolio.Allocation[1].Currency)
+	assert.InDelta(t, 500000.0/1010000*100, portfolio.Allocation[1].Percent, 1e-9)
+	assert.Equal(t, 1000.0, portfolio.Allocation[2].Price)
+
+	require.Len(t, portfolio.Positions, 1)
+	assert.InDelta(t, 50000, portfolio.Positions[0].UnrealizedPnL, 1e-6)
+
+	require.NotNil(t, portfolio.DailyChange)
+	assert.InDelta(t, 10000, portfolio.DailyChange.Change, 1e-6)
+	assert.InDelta(t, 1, portfolio.DailyChange.Percent, 1e-9)
+}
+
+func TestService_EquityCurve(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	userID := uuid.New()
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	snapshot := func(at time.Time, krw, btc, btcPrice float64) {
+		s := model.NewAccountSnapshot(userID, model.SnapshotPeriodDaily, at, []model.BalanceSnapshot{
+			{Currency: "KRW", Balance: krw, Price: 1, Value: krw},
+			{Currency: "BTC", Balance: btc, Price: btcPrice, Value: btc * btcPrice},
+		}, nil, time.Now())
+		require.NoError(t, store.Snapshots().Create(ctx, s))
+	}
+
+	// Buy 0.01 BTC on the first day, then deposit 1,000,000 KRW on the second
+	price := 50000000.0
+	order := model.NewOrder(userID, "KRW-BTC", model.OrderSideBid, model.OrderTypeLimit, 0.01, &price, time.Now())
+	order.ExecutedQuantity = 0.01
+	order.CreatedAt = start.Add(time.Hour)
+	require.NoError(t, store.Orders().Create(ctx, order))
+	execution := model.NewOrderExecution(order.ID, price, 0.01, 250, time.Now())
+	execution.CreatedAt = start.Add(2 * time.Hour)
+	require.NoError(t, store.Executions().Create(ctx, execution))
+
+	snapshot(start, 1000000, 0, 50000000)
+	snapshot(start.AddDate(0, 0, 1), 499750, 0.01, 50000000)
+	snapshot(start.AddDate(0, 0, 2), 1499750, 0.01, 55000000)
+
+	service := NewService(nil, store.Positions(), store.Snapshots(), nil).WithExecutions(store.Orders(), store.Executions())
+	curve, err := service.EquityCurve(ctx, userID, start, start.AddDate(0, 0, 3), IntervalDay, "")
+	require.NoError(t, err)
+
+	require.Len(t, curve.Points, 3)
+	assert.InDelta(t, 0, curve.Points[1].NetFlow, 1e-6, "the buy is not a flow")
+	assert.InDelta(t, -250.0/1000000, curve.Points[1].Return, 1e-12)
+	assert.InDelta(t, 1000000, curve.Points[2].NetFlow, 1e-6)
+	assert.InDelta(t, 50000/(999750+500000.0), curve.Points[2].Return, 1e-12)
+	assert.InDelta(t, 1000000, curve.NetFlows, 1e-6)
+	assert.InDelta(t, (1-250.0/1000000)*(1+50000/1499750.0)-1, curve.TimeWeightedReturn, 1e-12)
+	assert.Equal(t, curve.TimeWeightedReturn, curve.Points[2].CumulativeReturn)
+
+	weekly, err := service.EquityCurve(ctx, userID, start, start.AddDate(0, 0, 3), IntervalWeek, "")
+	require.NoError(t, err)
+	assert.Len(t, weekly.Points, 1)
+
+	_, err = service.EquityCurve(ctx, userID, start, start.AddDate(0, 0, 3), "5m", "")
+	assert.ErrorIs(t, err, ErrInvalidInterval)
+}
@@ -0,0 +1,25 @@
+package preset
+
+var (
+	ErrInvalidName     = &PresetError{message: "name must be 1 to 50 letters, digits, '-' or '_'"}
+	ErrInvalidMarket   = &PresetError{message: "market must be a KRW market, e.g. KRW-BTC"}
+	ErrInvalidSide     = &PresetError{message: "side must be bid or ask"}
+	ErrInvalidType     = &PresetError{message: "type must be limit or market"}
+	ErrInvalidSizeRule = &PresetError{message: "size_rule must be quantity or amount"}
+	ErrInvalidSize     = &PresetError{message: "size must be positive, and at least 5,000 KRW for amounts"}
+	ErrInvalidPrice    = &PresetError{message: "price must be positive"}
+
+	ErrInvalidAlgorithm     = &PresetError{message: "algorithm must be whole, split or twap"}
+	ErrInvalidSlices        = &PresetError{message: "slices must be 2 to 20 for split and twap, and 1 for whole"}
+	ErrInvalidSliceInterval = &PresetError{message: "twap needs a slice_interval of at least 10 seconds, with the last slice within a day; other algorithms take none"}
+	ErrSliceTooSmall        = &PresetError{message: "each slice must be at least 5,000 KRW"}
+)
+
+// PresetError represents an order preset validation error
+type PresetError struct {
+	message string
+}
+
+func (e *PresetError) Error() string {
+	return e.message
+}
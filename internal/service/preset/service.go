@@ -0,0 +1,255 @@
+// Package preset saves the orders users place often under names, so placing
+// one takes only its name, e.g. a hotkey buying 50,000 KRW of BTC at market
+package preset
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/trading"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/quotation"
+	"github.com/sungminna/upbit-trading-platform/pkg/clock"
+)
+
+// validName is what preset names may be; they appear in URLs
+var validName = regexp.MustCompile(`^[A-Za-z0-9_-]{1,50}$`)
+
+const (
+	// maxSlices is the most orders a split or twap preset places
+	maxSlices = 20
+	// minSliceInterval is the shortest time between twap slices, in seconds
+	minSliceInterval = 10
+	// maxTWAPDuration is how long after the first slice the last may be placed
+	maxTWAPDuration = 24 * time.Hour
+	// volumePrecision is how many decimals of volume Upbit accepts
+	volumePrecision = 1e8
+)
+
+// TickerSource provides current prices; gateway.QuotationAPI satisfies it
+type TickerSource interface {
+	GetTicker(ctx context.Context, markets []string) ([]quotation.Ticker, error)
+}
+
+// OrderPlacer places orders; trading.Engine satisfies it
+type OrderPlacer interface {
+	PlaceOrder(ctx context.Context, userID uuid.UUID, req trading.PlaceOrderRequest) (*model.Order, error)
+}
+
+// PlaceOptions adjust a preset's order when placing it
+type PlaceOptions struct {
+	// Price of the order. Limit orders and amounts default to the current
+	// price.
+	Price *float64
+	// Size replaces the preset's, in the unit of its size rule
+	Size *float64
+	// PositionID attributes the order to one of the user's positions
+	PositionID *uuid.UUID
+}
+
+// Service manages order presets and places their orders. Orders go through
+// the engine as the user's own, so halts, risk limits and funds checks apply.
+type Service struct {
+	presets repository.OrderPresetRepository
+	placer  OrderPlacer
+	tickers TickerSource
+	clock   clock.Clock
+}
+
+// NewService creates a new order preset service
+func NewService(presets repository.OrderPresetRepository, placer OrderPlacer, tickers TickerSource) *Service {
+	return &Service{
+		presets: presets,
+		placer:  placer,
+		tickers: tickers,
+		clock:   clock.Real,
+	}
+}
+
+// WithClock sets the clock presets are timestamped by
+func (s *Service) WithClock(c clock.Clock) *Service {
+	s.clock = c
+	return s
+}
+
+// Save validates and stores a preset, replacing the user's preset of the
+// same name
+func (s *Service) Save(ctx context.Context, preset *model.OrderPreset) (*model.OrderPreset, error) {
+	if err := validate(preset); err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	preset.ID = uuid.New()
+	preset.CreatedAt = now
+	preset.UpdatedAt = now
+	if err := s.presets.Save(ctx, preset); err != nil {
+		return nil, err
+	}
+	return preset, nil
+}
+
+// Get returns the user's preset of a name
+func (s *Service) Get(ctx context.Context, userID uuid.UUID, name string) (*model.OrderPreset, error) {
+	return s.presets.GetByName(ctx, userID, name)
+}
+
+// List returns the user's presets by name
+func (s *Service) List(ctx context.Context, userID uuid.UUID) ([]*model.OrderPreset, error) {
+	return s.presets.ListByUser(ctx, userID)
+}
+
+// Delete removes the user's preset of a name
+func (s *Service) Delete(ctx context.Context, userID uuid.UUID, name string) error {
+	return s.presets.Delete(ctx, userID, name)
+}
+
+// Place places the order of the user's preset of a name, in slices if its
+// algorithm splits it. Twap slices after the first are scheduled orders. On
+// error the orders placed before the failing slice are returned with it.
+func (s *Service) Place(ctx context.Context, userID uuid.UUID, name string, opts PlaceOptions) ([]*model.Order, error) {
+	preset, err := s.presets.GetByName(ctx, userID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	size := preset.Size
+	if opts.Size != nil {
+		size = *opts.Size
+		if size <= 0 || (preset.SizeRule == model.PresetSizeAmount && size < model.MinOrderAmount) {
+			return nil, ErrInvalidSize
+		}
+	}
+	if opts.Price != nil && *opts.Price <= 0 {
+		return nil, ErrInvalidPrice
+	}
+
+	req := trading.PlaceOrderRequest{
+		Market:     preset.Market,
+		Side:       preset.Side,
+		Type:       preset.Type,
+		Quantity:   size,
+		PositionID: opts.PositionID,
+	}
+
+	// Market sells of a quantity are the only orders placed without a price
+	price := opts.Price
+	if price == nil && (preset.Type == model.OrderTypeLimit || preset.Side == model.OrderSideBid ||
+		preset.SizeRule == model.PresetSizeAmount) {
+		if price, err = s.currentPrice(ctx, preset.Market); err != nil {
+			return nil, err
+		}
+	}
+	if preset.SizeRule == model.PresetSizeAmount {
+		req.Quantity = size / *price
+	}
+	if preset.Type == model.OrderTypeLimit || preset.Side == model.OrderSideBid {
+		req.Price = price
+	}
+
+	slices := max(preset.Slices, 1)
+	sliceQty := req.Quantity
+	if slices > 1 {
+		sliceQty = math.Floor(req.Quantity/float64(slices)*volumePrecision) / volumePrecision
+		if sliceQty <= 0 || (price != nil && sliceQty**price < model.MinOrderAmount) {
+			return nil, ErrSliceTooSmall
+		}
+	}
+
+	start := s.clock.Now()
+	orders := make([]*model.Order, 0, slices)
+	for i := range slices {
+		slice := req
+		slice.Quantity = sliceQty
+		if i == slices-1 {
+			slice.Quantity = req.Quantity - sliceQty*float64(slices-1) // The rounding remainder
+		}
+		if preset.Algorithm == model.PresetAlgorithmTWAP && i > 0 {
+			at := start.Add(time.Duration(i*preset.SliceInterval) * time.Second)
+			slice.ActivateAt = &at
+		}
+
+		order, err := s.placer.PlaceOrder(ctx, userID, slice)
+		if err != nil {
+			return orders, err
+		}
+		orders = append(orders, order)
+	}
+	return orders, nil
+}
+
+func (s *Service) currentPrice(ctx context.Context, market string) (*float64, error) {
+	tickers, err := s.tickers.GetTicker(ctx, []string{market})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price: %w", err)
+	}
+	if len(tickers) == 0 || tickers[0].TradePrice <= 0 {
+		return nil, fmt.Errorf("no price for %s", market)
+	}
+	price := tickers[0].TradePrice
+	return &price, nil
+}
+
+// validate checks a preset before it is saved
+func validate(preset *model.OrderPreset) error {
+	if !validName.MatchString(preset.Name) {
+		return ErrInvalidName
+	}
+	if !strings.HasPrefix(preset.Market, "KRW-") {
+		return ErrInvalidMarket
+	}
+	if preset.Side != model.OrderSideBid && preset.Side != model.OrderSideAsk {
+		return ErrInvalidSide
+	}
+	if preset.Type != model.OrderTypeLimit && preset.Type != model.OrderTypeMarket {
+		return ErrInvalidType
+	}
+	if preset.SizeRule != model.PresetSizeQuantity && preset.SizeRule != model.PresetSizeAmount {
+		return ErrInvalidSizeRule
+	}
+	if preset.Size <= 0 || (preset.SizeRule == model.PresetSizeAmount && preset.Size < model.MinOrderAmount) {
+		return ErrInvalidSize
+	}
+	return validateAlgorithm(preset)
+}
+
+// validateAlgorithm checks a preset's execution, defaulting it to whole
+func validateAlgorithm(preset *model.OrderPreset) error {
+	switch preset.Algorithm {
+	case "", model.PresetAlgorithmWhole:
+		preset.Algorithm = model.PresetAlgorithmWhole
+		if preset.Slices == 0 {
+			preset.Slices = 1
+		}
+		if preset.Slices != 1 {
+			return ErrInvalidSlices
+		}
+	case model.PresetAlgorithmSplit, model.PresetAlgorithmTWAP:
+		if preset.Slices < 2 || preset.Slices > maxSlices {
+			return ErrInvalidSlices
+		}
+	default:
+		return ErrInvalidAlgorithm
+	}
+	if preset.SizeRule == model.PresetSizeAmount && preset.Size/float64(preset.Slices) < model.MinOrderAmount {
+		return ErrSliceTooSmall
+	}
+
+	if preset.Algorithm != model.PresetAlgorithmTWAP {
+		if preset.SliceInterval != 0 {
+			return ErrInvalidSliceInterval
+		}
+		return nil
+	}
+	last := time.Duration((preset.Slices-1)*preset.SliceInterval) * time.Second
+	if preset.SliceInterval < minSliceInterval || last > maxTWAPDuration {
+		return ErrInvalidSliceInterval
+	}
+	return nil
+}
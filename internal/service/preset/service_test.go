@@ -0,0 +1,187 @@
+package preset
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+	"github.com/sungminna/upbit-trading-platform/internal/service/trading"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/quotation"
+	"github.com/sungminna/upbit-trading-platform/pkg/clock"
+)
+
+type staticTickers map[string]float64
+
+func (s staticTickers) GetTicker(ctx context.Context, markets []string) ([]quotation.Ticker, error) {
+	var tickers []quotation.Ticker
+	for _, market := range markets {
+		if price, ok := s[market]; ok {
+			tickers = append(tickers, quotation.Ticker{Market: market, TradePrice: price})
+		}
+	}
+	return tickers, nil
+}
+
+type recordingPlacer struct {
+	placed []trading.PlaceOrderRequest
+}
+
+func (p *recordingPlacer) PlaceOrder(ctx context.Context, userID uuid.UUID, req trading.PlaceOrderRequest) (*model.Order, error) {
+	p.placed = append(p.placed, req)
+	return model.NewOrder(userID, req.Market, req.Side, req.Type, req.Quantity, req.Price, time.Now()), nil
+}
+
+var prices = staticTickers{"KRW-BTC": 100000000}
+
+func TestService_SaveValidatesAndReplaces(t *testing.T) {
+	ctx := context.Background()
+	service := NewService(memory.NewStore().OrderPresets(), &recordingPlacer{}, prices)
+	userID := uuid.New()
+
+	valid := func() *model.OrderPreset {
+		return &model.OrderPreset{UserID: userID, Name: "btc-dip", Market: "KRW-BTC", Side: model.OrderSideBid,
+			Type: model.OrderTypeMarket, SizeRule: model.PresetSizeAmount, Size: 50000}
+	}
+	tests := []struct {
+		change func(*model.OrderPreset)
+		err    error
+	}{
+		{func(p *model.OrderPreset) { p.Name = "btc dip" }, ErrInvalidName},
+		{func(p *model.OrderPreset) { p.Market = "BTC-ETH" }, ErrInvalidMarket},
+		{func(p *model.OrderPreset) { p.Side = "hold" }, ErrInvalidSide},
+		{func(p *model.OrderPreset) { p.Type = "stop" }, ErrInvalidType},
+		{func(p *model.OrderPreset) { p.SizeRule = "percent" }, ErrInvalidSizeRule},
+		{func(p *model.OrderPreset) { p.Size = 1000 }, ErrInvalidSize},
+		{func(p *model.OrderPreset) { p.Algorithm = "vwap" }, ErrInvalidAlgorithm},
+		{func(p *model.OrderPreset) { p.Slices = 3 }, ErrInvalidSlices},
+		{func(p *model.OrderPreset) { p.Algorithm, p.Slices = model.PresetAlgorithmSplit, 21 }, ErrInvalidSlices},
+		{func(p *model.OrderPreset) { p.Algorithm, p.Slices = model.PresetAlgorithmSplit, 20 }, ErrSliceTooSmall},
+		{func(p *model.OrderPreset) { p.Algorithm, p.Slices, p.SliceInterval = model.PresetAlgorithmSplit, 2, 60 }, ErrInvalidSliceInterval},
+		{func(p *model.OrderPreset) { p.Algorithm, p.Slices, p.SliceInterval = model.PresetAlgorithmTWAP, 2, 5 }, ErrInvalidSliceInterval},
+		{func(p *model.OrderPreset) {
+			p.Algorithm, p.Slices, p.SliceInterval = model.PresetAlgorithmTWAP, 5, 86400
+		}, ErrInvalidSliceInterval},
+	}
+	for _, tt := range tests {
+		preset := valid()
+		tt.change(preset)
+		_, err := service.Save(ctx, preset)
+		assert.ErrorIs(t, err, tt.err)
+	}
+
+	first, err := service.Save(ctx, valid())
+	require.NoError(t, err)
+	assert.Equal(t, model.PresetAlgorithmWhole, first.Algorithm, "orders are placed whole by default")
+	assert.Equal(t, 1, first.Slices)
+	replacement := valid()
+	replacement.Size = 100000
+	second, err := service.Save(ctx, replacement)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, second.ID, "a preset saved under the same name replaces the old one")
+
+	presets, err := service.List(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, presets, 1)
+	assert.Equal(t, 100000.0, presets[0].Size)
+
+	require.NoError(t, service.Delete(ctx, userID, "btc-dip"))
+	_, err = service.Get(ctx, userID, "btc-dip")
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func TestService_PlaceSizesOrders(t *testing.T) {
+	ctx := context.Background()
+	placer := &recordingPlacer{}
+	service := NewService(memory.NewStore().OrderPresets(), placer, prices)
+	userID := uuid.New()
+
+	save := func(name string, side model.OrderSide, typ model.OrderType, rule model.OrderPresetSizeRule, size float64) {
+		_, err := service.Save(ctx, &model.OrderPreset{UserID: userID, Name: name, Market: "KRW-BTC",
+			Side: side, Type: typ, SizeRule: rule, Size: size})
+		require.NoError(t, err)
+	}
+	save("buy", model.OrderSideBid, model.OrderTypeMarket, model.PresetSizeAmount, 50000)
+	save("sell", model.OrderSideAsk, model.OrderTypeMarket, model.PresetSizeQuantity, 0.01)
+	save("bid", model.OrderSideBid, model.OrderTypeLimit, model.PresetSizeQuantity, 0.01)
+
+	// Amounts are converted at the current price
+	_, err := service.Place(ctx, userID, "buy", PlaceOptions{})
+	require.NoError(t, err)
+	// Market sells of a quantity need no price
+	_, err = service.Place(ctx, userID, "sell", PlaceOptions{})
+	require.NoError(t, err)
+	// Limit orders take the given price and size
+	price, size := 90000000.0, 0.02
+	_, err = service.Place(ctx, userID, "bid", PlaceOptions{Price: &price, Size: &size})
+	require.NoError(t, err)
+
+	require.Len(t, placer.placed, 3)
+	assert.InDelta(t, 0.0005, placer.placed[0].Quantity, 1e-12)
+	require.NotNil(t, placer.placed[0].Price)
+	assert.Equal(t, 100000000.0, *placer.placed[0].Price)
+	assert.Equal(t, model.OrderTypeMarket, placer.placed[0].Type)
+
+	assert.Equal(t, 0.01, placer.placed[1].Quantity)
+	assert.Nil(t, placer.placed[1].Price)
+
+	assert.Equal(t, model.OrderTypeLimit, placer.placed[2].Type)
+	assert.Equal(t, 0.02, placer.placed[2].Quantity)
+	assert.Equal(t, price, *placer.placed[2].Price)
+	for _, req := range placer.placed {
+		assert.Empty(t, req.Source, "orders placed from presets are the user's own")
+	}
+
+	_, err = service.Place(ctx, userID, "missing", PlaceOptions{})
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	_, err = service.Place(ctx, uuid.New(), "buy", PlaceOptions{})
+	assert.ErrorIs(t, err, repository.ErrNotFound, "presets are per user")
+}
+
+func TestService_PlaceSlicesOrders(t *testing.T) {
+	ctx := context.Background()
+	placer := &recordingPlacer{}
+	now := time.Date(2025, 3, 4, 9, 0, 0, 0, time.UTC)
+	service := NewService(memory.NewStore().OrderPresets(), placer, prices).WithClock(clock.NewFake(now))
+	userID := uuid.New()
+
+	_, err := service.Save(ctx, &model.OrderPreset{UserID: userID, Name: "split", Market: "KRW-BTC", Side: model.OrderSideAsk,
+		Type: model.OrderTypeMarket, SizeRule: model.PresetSizeQuantity, Size: 0.01,
+		Algorithm: model.PresetAlgorithmSplit, Slices: 3})
+	require.NoError(t, err)
+	_, err = service.Save(ctx, &model.OrderPreset{UserID: userID, Name: "twap", Market: "KRW-BTC", Side: model.OrderSideBid,
+		Type: model.OrderTypeMarket, SizeRule: model.PresetSizeAmount, Size: 60000,
+		Algorithm: model.PresetAlgorithmTWAP, Slices: 3, SliceInterval: 600})
+	require.NoError(t, err)
+
+	orders, err := service.Place(ctx, userID, "split", PlaceOptions{})
+	require.NoError(t, err)
+	require.Len(t, orders, 3)
+	var total float64
+	for _, req := range placer.placed {
+		assert.Nil(t, req.ActivateAt, "split slices are placed at once")
+		total += req.Quantity
+	}
+	assert.InDelta(t, 0.00333333, placer.placed[0].Quantity, 1e-12)
+	assert.InDelta(t, 0.01, total, 1e-12, "the last slice takes the rounding remainder")
+
+	placer.placed = nil
+	orders, err = service.Place(ctx, userID, "twap", PlaceOptions{})
+	require.NoError(t, err)
+	require.Len(t, orders, 3)
+	assert.Nil(t, placer.placed[0].ActivateAt, "the first slice is placed at once")
+	for i, req := range placer.placed[1:] {
+		require.NotNil(t, req.ActivateAt)
+		assert.Equal(t, now.Add(time.Duration(i+1)*10*time.Minute), *req.ActivateAt)
+	}
+	assert.InDelta(t, 0.0002, placer.placed[0].Quantity, 1e-12, "20,000 KRW at the current price")
+
+	small := 12000.0
+	_, err = service.Place(ctx, userID, "twap", PlaceOptions{Size: &small})
+	assert.ErrorIs(t, err, ErrSliceTooSmall)
+}
@@ -0,0 +1,151 @@
+package pricefeed
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/pkg/recovery"
+)
+
+// CandleHandler receives candles as they close. Handlers run on the feed's
+// goroutine and must not block.
+type CandleHandler func(candle model.Candle)
+
+// aggregateKey identifies the candle being built for a market and interval
+type aggregateKey struct {
+	market   string
+	interval model.CandleInterval
+}
+
+// openCandle is a candle being built and the time of its latest price
+type openCandle struct {
+	candle model.Candle
+	last   time.Time
+}
+
+// Aggregator builds candles of the intervals subscribed to from a feed's
+// prices. A candle closes when the first price of the next candle arrives, so
+// a market that stops trading leaves its last candle open.
+type Aggregator struct {
+	feed        *Feed
+	handlers    map[model.CandleInterval]map[int]CandleHandler
+	open        map[aggregateKey]*openCandle
+	nextID      int
+	unsubscribe func() // From the feed, while anything is subscribed
+	mu          sync.Mutex
+}
+
+// NewAggregator creates a candle aggregator over a feed
+func NewAggregator(feed *Feed) *Aggregator {
+	return &Aggregator{
+		feed:     feed,
+		handlers: make(map[model.CandleInterval]map[int]CandleHandler),
+		open:     make(map[aggregateKey]*openCandle),
+	}
+}
+
+// SupportsInterval reports whether candles of an interval can be aggregated.
+// Weekly and monthly candles don't start on a fixed grid, so they can't.
+func SupportsInterval(interval model.CandleInterval) bool {
+	d := interval.Duration()
+	return d > 0 && d <= 24*time.Hour
+}
+
+// Subscribe registers a handler for every market's candles of an interval,
+// which must be supported, and returns a function that removes it
+func (a *Aggregator) Subscribe(interval model.CandleInterval, handler CandleHandler) (unsubscribe func()) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	id := a.nextID
+	a.nextID++
+	if a.handlers[interval] == nil {
+		a.handlers[interval] = make(map[int]CandleHandler)
+	}
+	a.handlers[interval][id] = handler
+	if a.unsubscribe == nil {
+		a.unsubscribe = a.feed.Subscribe(AllMarkets, a.onPrice)
+	}
+
+	return func() {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		delete(a.handlers[interval], id)
+		if len(a.handlers[interval]) > 0 {
+			return
+		}
+		delete(a.handlers, interval)
+		for key := range a.open {
+			if key.interval == interval {
+				delete(a.open, key)
+			}
+		}
+		if len(a.handlers) == 0 && a.unsubscribe != nil {
+			a.unsubscribe()
+			a.unsubscribe = nil
+		}
+	}
+}
+
+// onPrice adds a price to the market's open candles, closing those the price
+// falls after. Prices older than an open candle are ignored, and prices
+// arriving out of order count toward its range but not its close.
+func (a *Aggregator) onPrice(update PriceUpdate) {
+	at := update.Timestamp
+	if at.IsZero() {
+		at = update.ReceivedAt
+	}
+
+	type closed struct {
+		candle   model.Candle
+		handlers []CandleHandler
+	}
+	var closes []closed
+
+	a.mu.Lock()
+	for interval, handlers := range a.handlers {
+		start := at.Truncate(interval.Duration())
+		key := aggregateKey{market: update.Market, interval: interval}
+		open, ok := a.open[key]
+		switch {
+		case ok && start.Before(open.candle.Timestamp):
+			continue
+		case ok && start.Equal(open.candle.Timestamp):
+			open.candle.HighPrice = max(open.candle.HighPrice, update.Price)
+			open.candle.LowPrice = min(open.candle.LowPrice, update.Price)
+			open.candle.Volume += update.Volume
+			if !at.Before(open.last) {
+				open.candle.ClosePrice = update.Price
+				open.last = at
+			}
+			continue
+		case ok:
+			c := closed{candle: open.candle}
+			for _, h := range handlers {
+				c.handlers = append(c.handlers, h)
+			}
+			closes = append(closes, c)
+		}
+		a.open[key] = &openCandle{
+			candle: model.Candle{
+				Market:     update.Market,
+				Interval:   interval,
+				Timestamp:  start,
+				OpenPrice:  update.Price,
+				HighPrice:  update.Price,
+				LowPrice:   update.Price,
+				ClosePrice: update.Price,
+				Volume:     update.Volume,
+			},
+			last: at,
+		}
+	}
+	a.mu.Unlock()
+
+	for _, c := range closes {
+		for _, handler := range c.handlers {
+			recovery.Do("pricefeed.candle-close", func() { handler(c.candle) })
+		}
+	}
+}
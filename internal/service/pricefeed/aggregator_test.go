@@ -0,0 +1,45 @@
+package pricefeed
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+func TestAggregator_ClosesCandles(t *testing.T) {
+	feed := NewFeed()
+	aggregator := NewAggregator(feed)
+
+	var closes []model.Candle
+	unsubscribe := aggregator.Subscribe(model.CandleInterval5m, func(c model.Candle) { closes = append(closes, c) })
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	publish := func(market string, price float64, offset time.Duration) {
+		feed.Publish(PriceUpdate{Market: market, Price: price, Volume: 1, Timestamp: start.Add(offset)})
+	}
+	publish("KRW-BTC", 100, time.Minute)
+	publish("KRW-BTC", 90, 2*time.Minute)
+	publish("KRW-ETH", 10, 3*time.Minute)
+	publish("KRW-BTC", 95, 4*time.Minute)
+	publish("KRW-BTC", 80, 30*time.Second) // Out of order; lowers the low but isn't the close
+	publish("KRW-BTC", 70, -time.Minute)   // Before the open candle; ignored
+	assert.Empty(t, closes)
+
+	// The first price of the next candle closes the previous one
+	publish("KRW-BTC", 97, 6*time.Minute)
+	require.Len(t, closes, 1)
+	assert.Equal(t, model.Candle{
+		Market: "KRW-BTC", Interval: model.CandleInterval5m, Timestamp: start,
+		OpenPrice: 100, HighPrice: 100, LowPrice: 80, ClosePrice: 95, Volume: 4,
+	}, closes[0])
+
+	unsubscribe()
+	publish("KRW-BTC", 99, 11*time.Minute)
+	assert.Len(t, closes, 1)
+
+	assert.True(t, SupportsInterval(model.CandleInterval1d))
+	assert.False(t, SupportsInterval(model.CandleInterval1w))
+}
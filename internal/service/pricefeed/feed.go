@@ -0,0 +1,123 @@
+// Package pricefeed distributes market prices to in-process consumers
+package pricefeed
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sungminna/upbit-trading-platform/pkg/latency"
+	"github.com/sungminna/upbit-trading-platform/pkg/recovery"
+)
+
+// AllMarkets subscribes a handler to every market
+const AllMarkets = "*"
+
+// PriceUpdate is the latest trade price of a market
+type PriceUpdate struct {
+	Market     string    `json:"market"`
+	Price      float64   `json:"price"`
+	Volume     float64   `json:"volume"`
+	Timestamp  time.Time `json:"timestamp"`   // Exchange time of the price
+	Source     string    `json:"source"`      // e.g. "websocket", "replay"
+	ReceivedAt time.Time `json:"received_at"` // Set by Publish
+}
+
+// Age returns how old the price is at now, by exchange time when known and
+// by the time it was published otherwise
+func (u PriceUpdate) Age(now time.Time) time.Duration {
+	if !u.Timestamp.IsZero() {
+		return now.Sub(u.Timestamp)
+	}
+	return now.Sub(u.ReceivedAt)
+}
+
+// Handler receives price updates. Handlers run on the publisher's goroutine
+// and must not block.
+type Handler func(update PriceUpdate)
+
+// Feed is an in-process price feed that remembers the latest price per market
+type Feed struct {
+	handlers map[string]map[int]Handler
+	latest   map[string]PriceUpdate
+	nextID   int
+	latency  *latency.Recorder // Optional
+	mu       sync.RWMutex
+}
+
+// NewFeed creates a new price feed
+func NewFeed() *Feed {
+	return &Feed{
+		handlers: make(map[string]map[int]Handler),
+		latest:   make(map[string]PriceUpdate),
+	}
+}
+
+// WithLatency records, per source, how long prices take from their trade on
+// the exchange to being published, as stage "feed.<source>"
+func (f *Feed) WithLatency(recorder *latency.Recorder) *Feed {
+	f.latency = recorder
+	return f
+}
+
+// Subscribe registers a handler for a market, or for every market with
+// AllMarkets, and returns a function that removes it
+func (f *Feed) Subscribe(market string, handler Handler) (unsubscribe func()) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	id := f.nextID
+	f.nextID++
+	if f.handlers[market] == nil {
+		f.handlers[market] = make(map[int]Handler)
+	}
+	f.handlers[market][id] = handler
+
+	return func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		delete(f.handlers[market], id)
+	}
+}
+
+// Publish records an update as the market's latest price and delivers it
+func (f *Feed) Publish(update PriceUpdate) {
+	update.ReceivedAt = time.Now()
+	if f.latency != nil {
+		f.latency.Since("feed."+update.Source, update.Timestamp, update.ReceivedAt)
+	}
+
+	f.mu.Lock()
+	f.latest[update.Market] = update
+	handlers := make([]Handler, 0, len(f.handlers[update.Market])+len(f.handlers[AllMarkets]))
+	for _, h := range f.handlers[update.Market] {
+		handlers = append(handlers, h)
+	}
+	for _, h := range f.handlers[AllMarkets] {
+		handlers = append(handlers, h)
+	}
+	f.mu.Unlock()
+
+	// A panicking subscriber misses this update but doesn't stop the others
+	// or the publisher
+	for _, handler := range handlers {
+		recovery.Do("pricefeed.subscriber", func() { handler(update) })
+	}
+}
+
+// Latest returns the most recent price of a market
+func (f *Feed) Latest(market string) (PriceUpdate, bool) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	update, ok := f.latest[market]
+	return update, ok
+}
+
+// Fresh returns the most recent price of a market unless it is older than
+// maxAge
+func (f *Feed) Fresh(market string, maxAge time.Duration) (PriceUpdate, bool) {
+	update, ok := f.Latest(market)
+	if !ok || update.Age(time.Now()) > maxAge {
+		return PriceUpdate{}, false
+	}
+	return update, true
+}
@@ -0,0 +1,119 @@
+package pricefeed
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/quotation"
+	"github.com/sungminna/upbit-trading-platform/pkg/latency"
+)
+
+func TestFeed_PublishAndSubscribe(t *testing.T) {
+	feed := NewFeed()
+
+	var btc, all []PriceUpdate
+	unsubscribe := feed.Subscribe("KRW-BTC", func(u PriceUpdate) { btc = append(btc, u) })
+	feed.Subscribe(AllMarkets, func(u PriceUpdate) { all = append(all, u) })
+
+	now := time.Now()
+	feed.Publish(PriceUpdate{Market: "KRW-BTC", Price: 100, Timestamp: now})
+	feed.Publish(PriceUpdate{Market: "KRW-ETH", Price: 10, Timestamp: now})
+
+	assert.Len(t, btc, 1)
+	assert.Len(t, all, 2)
+
+	latest, ok := feed.Latest("KRW-ETH")
+	assert.True(t, ok)
+	assert.Equal(t, 10.0, latest.Price)
+
+	_, ok = feed.Latest("KRW-XRP")
+	assert.False(t, ok)
+
+	unsubscribe()
+	feed.Publish(PriceUpdate{Market: "KRW-BTC", Price: 101, Timestamp: now})
+	assert.Len(t, btc, 1)
+	assert.Len(t, all, 3)
+}
+
+func TestFeed_Fresh(t *testing.T) {
+	feed := NewFeed()
+
+	feed.Publish(PriceUpdate{Market: "KRW-BTC", Price: 100, Timestamp: time.Now().Add(-time.Minute)})
+	feed.Publish(PriceUpdate{Market: "KRW-ETH", Price: 10})
+
+	_, ok := feed.Fresh("KRW-BTC", 30*time.Second)
+	assert.False(t, ok, "a minute-old trade is stale")
+	_, ok = feed.Fresh("KRW-BTC", 2*time.Minute)
+	assert.True(t, ok)
+
+	// Without an exchange time the publish time counts
+	eth, ok := feed.Fresh("KRW-ETH", time.Second)
+	require.True(t, ok)
+	assert.False(t, eth.ReceivedAt.IsZero())
+
+	_, ok = feed.Fresh("KRW-XRP", time.Hour)
+	assert.False(t, ok)
+}
+
+type stubTickers struct {
+	requested []string
+}
+
+func (s *stubTickers) GetTicker(ctx context.Context, markets []string) ([]quotation.Ticker, error) {
+	s.requested = markets
+	tickers := make([]quotation.Ticker, len(markets))
+	for i, market := range markets {
+		tickers[i] = quotation.Ticker{Market: market, TradePrice: 100, TradeTimestamp: 1735689600000}
+	}
+	return tickers, nil
+}
+
+func TestFeed_Latency(t *testing.T) {
+	recorder := latency.NewRecorder()
+	feed := NewFeed().WithLatency(recorder)
+
+	feed.Publish(PriceUpdate{Market: "KRW-BTC", Price: 100, Timestamp: time.Now().Add(-200 * time.Millisecond), Source: "ticker"})
+	feed.Publish(PriceUpdate{Market: "KRW-BTC", Price: 101, Source: "ticker"}) // No exchange time
+
+	stages := recorder.Snapshot()
+	require.Len(t, stages, 1)
+	assert.Equal(t, "feed.ticker", stages[0].Stage)
+	assert.Equal(t, int64(1), stages[0].Count)
+	assert.GreaterOrEqual(t, stages[0].MaxMs, int64(200))
+}
+
+func TestFeed_PanickingSubscriber(t *testing.T) {
+	feed := NewFeed()
+	feed.Subscribe("KRW-BTC", func(update PriceUpdate) { panic("bad strategy") })
+	var received []float64
+	feed.Subscribe("KRW-BTC", func(update PriceUpdate) { received = append(received, update.Price) })
+
+	feed.Publish(PriceUpdate{Market: "KRW-BTC", Price: 100})
+	feed.Publish(PriceUpdate{Market: "KRW-BTC", Price: 101})
+	assert.Equal(t, []float64{100, 101}, received, "other subscribers keep receiving")
+}
+
+func TestPoller_Poll(t *testing.T) {
+	tickers := &stubTickers{}
+	feed := NewFeed()
+	poller := NewPoller(tickers, feed, time.Second)
+
+	untrackBTC := poller.Track("KRW-BTC")
+	poller.Track("KRW-BTC")
+	poller.Track("KRW-ETH")
+	require.NoError(t, poller.Poll(context.Background()))
+	assert.Equal(t, []string{"KRW-BTC", "KRW-ETH"}, tickers.requested)
+
+	latest, ok := feed.Latest("KRW-ETH")
+	require.True(t, ok)
+	assert.Equal(t, 100.0, latest.Price)
+	assert.Equal(t, time.UnixMilli(1735689600000), latest.Timestamp)
+
+	// BTC stays tracked until every consumer untracks it
+	untrackBTC()
+	untrackBTC()
+	assert.Equal(t, []string{"KRW-BTC", "KRW-ETH"}, poller.Markets())
+}
@@ -0,0 +1,148 @@
+package pricefeed
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/quotation"
+	"github.com/sungminna/upbit-trading-platform/pkg/recovery"
+)
+
+// tickerSource identifies polled prices in the feed
+const tickerSource = "ticker"
+
+// DefaultPollInterval is how often the poller fetches tickers by default
+const DefaultPollInterval = 2 * time.Second
+
+// TickerSource provides current tickers; gateway.QuotationAPI satisfies it
+type TickerSource interface {
+	GetTicker(ctx context.Context, markets []string) ([]quotation.Ticker, error)
+}
+
+// Poller publishes the trade price of tracked markets into a feed at a fixed
+// interval. Markets are tracked by reference count so several consumers can
+// share the same market.
+type Poller struct {
+	tickers   TickerSource
+	feed      *Feed
+	interval  time.Duration
+	markets   map[string]int
+	mu        sync.Mutex
+	isRunning bool
+	stopChan  chan struct{}
+}
+
+// NewPoller creates a new poller publishing into feed every interval
+func NewPoller(tickers TickerSource, feed *Feed, interval time.Duration) *Poller {
+	return &Poller{
+		tickers:  tickers,
+		feed:     feed,
+		interval: interval,
+		markets:  make(map[string]int),
+		stopChan: make(chan struct{}),
+	}
+}
+
+// Track starts polling a market and returns a function that stops tracking it
+func (p *Poller) Track(market string) (untrack func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.markets[market]++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			if p.markets[market]--; p.markets[market] <= 0 {
+				delete(p.markets, market)
+			}
+		})
+	}
+}
+
+// Markets returns the tracked markets, sorted
+func (p *Poller) Markets() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	markets := make([]string, 0, len(p.markets))
+	for market := range p.markets {
+		markets = append(markets, market)
+	}
+	sort.Strings(markets)
+	return markets
+}
+
+// Start starts polling
+func (p *Poller) Start(ctx context.Context) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.isRunning {
+		return
+	}
+	p.isRunning = true
+
+	go p.run(ctx)
+}
+
+// Stop stops polling
+func (p *Poller) Stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.isRunning {
+		return
+	}
+
+	close(p.stopChan)
+	p.isRunning = false
+}
+
+func (p *Poller) run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.stopChan:
+			return
+		case <-ticker.C:
+			recovery.Do("pricefeed.poll", func() {
+				if err := p.Poll(ctx); err != nil {
+					log.Printf("Error polling tickers: %v", err)
+				}
+			})
+		}
+	}
+}
+
+// Poll fetches the tickers of all tracked markets once and publishes them
+func (p *Poller) Poll(ctx context.Context) error {
+	markets := p.Markets()
+	if len(markets) == 0 {
+		return nil
+	}
+
+	tickers, err := p.tickers.GetTicker(ctx, markets)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range tickers {
+		p.feed.Publish(PriceUpdate{
+			Market:    t.Market,
+			Price:     t.TradePrice,
+			Volume:    t.TradeVolume,
+			Timestamp: time.UnixMilli(t.TradeTimestamp),
+			Source:    tickerSource,
+		})
+	}
+	return nil
+}
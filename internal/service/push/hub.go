@@ -0,0 +1,140 @@
+// Package push delivers users' notifications and order events to their open
+// websocket connections, so clients reflect fills, guard triggers and alerts
+// as they happen instead of polling the REST API
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// ChannelName is the notification channel name of the hub
+const ChannelName = "websocket"
+
+// sendBuffer is how many messages a connection may fall behind before the hub
+// drops it; the client reconnects and catches up over REST
+const sendBuffer = 64
+
+// Message kinds
+const (
+	KindNotification = "notification"
+	KindOrder        = "order"
+)
+
+// Message is what the hub pushes to a connection
+type Message struct {
+	Kind         string              `json:"kind"`
+	Event        string              `json:"event,omitempty"` // Of order messages, e.g. "order.filled"
+	Notification *model.Notification `json:"notification,omitempty"`
+	Order        json.RawMessage     `json:"order,omitempty"`
+}
+
+// Hub tracks the open connections of each user on this instance. It is a
+// notification channel and an event bus handler; both deliver only to the
+// connections of the user they concern. It is safe for concurrent use.
+type Hub struct {
+	mu    sync.RWMutex
+	conns map[uuid.UUID]map[*Conn]struct{}
+}
+
+// Conn is one of a user's connections to the hub
+type Conn struct {
+	userID  uuid.UUID
+	send    chan Message
+	dropped chan struct{}
+	once    sync.Once
+}
+
+// NewHub creates a hub without connections
+func NewHub() *Hub {
+	return &Hub{conns: make(map[uuid.UUID]map[*Conn]struct{})}
+}
+
+// Register adds a connection for the user. Unregister it when it closes.
+func (h *Hub) Register(userID uuid.UUID) *Conn {
+	c := &Conn{userID: userID, send: make(chan Message, sendBuffer), dropped: make(chan struct{})}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.conns[userID] == nil {
+		h.conns[userID] = make(map[*Conn]struct{})
+	}
+	h.conns[userID][c] = struct{}{}
+	return c
+}
+
+// Unregister removes a connection
+func (h *Hub) Unregister(c *Conn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.conns[c.userID], c)
+	if len(h.conns[c.userID]) == 0 {
+		delete(h.conns, c.userID)
+	}
+}
+
+// Connections returns how many connections the user has open
+func (h *Hub) Connections(userID uuid.UUID) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.conns[userID])
+}
+
+// Messages returns the messages to write to the connection
+func (c *Conn) Messages() <-chan Message {
+	return c.send
+}
+
+// Dropped is closed when the connection fell too far behind and should be
+// closed
+func (c *Conn) Dropped() <-chan struct{} {
+	return c.dropped
+}
+
+// Name returns the channel name
+func (h *Hub) Name() string {
+	return ChannelName
+}
+
+// Send pushes a notification to the user's connections. Users without a
+// connection simply miss it here.
+func (h *Hub) Send(ctx context.Context, notification *model.Notification) error {
+	h.push(notification.UserID, Message{Kind: KindNotification, Notification: notification})
+	return nil
+}
+
+// HandleEvent pushes an order event to the connections of the order's owner
+func (h *Hub) HandleEvent(ctx context.Context, event *model.OutboxEvent) error {
+	if event.AggregateType != "order" {
+		return nil
+	}
+
+	var order struct {
+		UserID uuid.UUID `json:"user_id"`
+	}
+	if err := json.Unmarshal(event.Payload, &order); err != nil {
+		return fmt.Errorf("failed to decode order event: %w", err)
+	}
+	h.push(order.UserID, Message{Kind: KindOrder, Event: event.EventType, Order: event.Payload})
+	return nil
+}
+
+// push queues a message on each of the user's connections without waiting;
+// connections whose queue is full are dropped
+func (h *Hub) push(userID uuid.UUID, msg Message) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for c := range h.conns[userID] {
+		select {
+		case c.send <- msg:
+		default:
+			c.once.Do(func() { close(c.dropped) })
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package push
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+func TestHub_PushesToTheUsersConnections(t *testing.T) {
+	hub := NewHub()
+	ctx := context.Background()
+	userID := uuid.New()
+	first, second := hub.Register(userID), hub.Register(userID)
+	other := hub.Register(uuid.New())
+	assert.Equal(t, 2, hub.Connections(userID))
+
+	n := model.NewNotification(userID, model.NotificationDrawdownGuard, "Drawdown guard triggered", "KRW-BTC fell 5%", nil, time.Now())
+	require.NoError(t, hub.Send(ctx, n))
+
+	order := model.NewOrder(userID, "KRW-BTC", model.OrderSideAsk, model.OrderTypeMarket, 0.01, nil, time.Now())
+	event, err := model.NewOrderEvent(model.EventOrderFilled, order, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, hub.HandleEvent(ctx, event))
+
+	for _, c := range []*Conn{first, second} {
+		msg := <-c.Messages()
+		assert.Equal(t, KindNotification, msg.Kind)
+		assert.Equal(t, n.ID, msg.Notification.ID)
+
+		msg = <-c.Messages()
+		assert.Equal(t, KindOrder, msg.Kind)
+		assert.Equal(t, model.EventOrderFilled, msg.Event)
+		assert.JSONEq(t, string(event.Payload), string(msg.Order))
+	}
+	assert.Empty(t, other.Messages(), "other users' connections get nothing")
+
+	hub.Unregister(first)
+	hub.Unregister(second)
+	assert.Zero(t, hub.Connections(userID))
+	require.NoError(t, hub.Send(ctx, n), "users without connections are skipped")
+}
+
+func TestHub_DropsSlowConnections(t *testing.T) {
+	hub := NewHub()
+	userID := uuid.New()
+	c := hub.Register(userID)
+
+	n := model.NewNotification(userID, model.NotificationPriceAlert, "KRW-BTC", "crossed above 100", nil, time.Now())
+	for i := 0; i <= sendBuffer; i++ {
+		require.NoError(t, hub.Send(context.Background(), n))
+	}
+
+	select {
+	case <-c.Dropped():
+	default:
+		t.Fatal("a connection that fell behind is dropped")
+	}
+	assert.Len(t, c.Messages(), sendBuffer)
+}
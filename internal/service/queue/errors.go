@@ -0,0 +1,15 @@
+package queue
+
+var (
+	ErrNotFailed = &QueueError{message: "only failed jobs can be retried"}
+	ErrNoResult  = &QueueError{message: "job has not succeeded"}
+)
+
+// QueueError represents a job queue error
+type QueueError struct {
+	message string
+}
+
+func (e *QueueError) Error() string {
+	return e.message
+}
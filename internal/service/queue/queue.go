@@ -0,0 +1,320 @@
+// Package queue runs asynchronous work from a durable job queue. Jobs are
+// stored before they run, so work survives restarts, and every attempt's
+// outcome is recorded on the job.
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/pkg/clock"
+	"github.com/sungminna/upbit-trading-platform/pkg/recovery"
+)
+
+const (
+	// DefaultMaxAttempts is how many times a job runs before it fails
+	DefaultMaxAttempts = 5
+	pollInterval       = time.Second
+	claimBatchSize     = 10
+	// lease is how long an attempt may run. A job still running after it is
+	// claimed again as interrupted, e.g. after its worker crashed.
+	lease          = 5 * time.Minute
+	initialBackoff = 2 * time.Second
+	maxBackoff     = 5 * time.Minute
+)
+
+// Handler runs a job. Returning an error retries the job with exponential
+// backoff until it runs out of attempts, unless the error is Permanent.
+type Handler func(ctx context.Context, job *model.QueuedJob) error
+
+// permanentError is a handler error retrying can't fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent marks a handler error retrying can't fix, such as invalid input,
+// so the job fails at once
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// Queue runs queued jobs with the handlers registered for their kinds.
+// Instances sharing the queue each run the kinds they have handlers for.
+type Queue struct {
+	jobs       repository.JobQueueRepository
+	handlers   map[string]Handler
+	handlersMu sync.RWMutex
+	clock      clock.Clock
+	mu         sync.Mutex
+	isRunning  bool
+	stopChan   chan struct{}
+}
+
+// NewQueue creates a new job queue
+func NewQueue(jobs repository.JobQueueRepository) *Queue {
+	return &Queue{
+		jobs:     jobs,
+		handlers: make(map[string]Handler),
+		clock:    clock.Real,
+		stopChan: make(chan struct{}),
+	}
+}
+
+// WithClock sets the clock jobs are claimed, retried and finished by
+func (q *Queue) WithClock(c clock.Clock) *Queue {
+	q.clock = c
+	return q
+}
+
+// NewJob creates a job of kind with payload marshalled to JSON, due at now,
+// for callers enqueueing it in their own transaction
+func NewJob(kind string, payload any, maxAttempts int, now time.Time) (*model.QueuedJob, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s job: %w", kind, err)
+	}
+	return model.NewQueuedJob(kind, data, max(maxAttempts, 1), now), nil
+}
+
+// Handle registers the handler of a kind of job
+func (q *Queue) Handle(kind string, handler Handler) {
+	q.handlersMu.Lock()
+	defer q.handlersMu.Unlock()
+
+	q.handlers[kind] = handler
+}
+
+// Enqueue stores a job to run as soon as a worker is free
+func (q *Queue) Enqueue(ctx context.Context, kind string, payload any, maxAttempts int) (*model.QueuedJob, error) {
+	job, err := NewJob(kind, payload, maxAttempts, q.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	return q.enqueue(ctx, job)
+}
+
+// EnqueueFor stores a job the user started, which they can follow and fetch
+// the result of
+func (q *Queue) EnqueueFor(ctx context.Context, userID uuid.UUID, kind string, payload any, maxAttempts int) (*model.QueuedJob, error) {
+	job, err := NewJob(kind, payload, maxAttempts, q.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	job.UserID = &userID
+	return q.enqueue(ctx, job)
+}
+
+func (q *Queue) enqueue(ctx context.Context, job *model.QueuedJob) (*model.QueuedJob, error) {
+	if err := q.jobs.Enqueue(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// List returns queued jobs, newest first
+func (q *Queue) List(ctx context.Context, filter repository.JobQueueFilter) ([]*model.QueuedJob, error) {
+	return q.jobs.List(ctx, filter)
+}
+
+// ListForUser returns the jobs a user started, newest first
+func (q *Queue) ListForUser(ctx context.Context, userID uuid.UUID, filter repository.JobQueueFilter) ([]*model.QueuedJob, error) {
+	filter.UserID = userID
+	return q.jobs.List(ctx, filter)
+}
+
+// GetForUser returns a job the user started
+func (q *Queue) GetForUser(ctx context.Context, userID, id uuid.UUID) (*model.QueuedJob, error) {
+	job, err := q.jobs.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.UserID == nil || *job.UserID != userID {
+		return nil, repository.ErrNotFound
+	}
+	return job, nil
+}
+
+// Result returns the result of a job the user started once it has succeeded
+func (q *Queue) Result(ctx context.Context, userID, id uuid.UUID) (*model.QueuedJobResult, error) {
+	job, err := q.GetForUser(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != model.QueuedJobSucceeded {
+		return nil, ErrNoResult
+	}
+	return q.jobs.GetResult(ctx, id)
+}
+
+// SaveResult stores the output of a running job for its user to fetch
+func (q *Queue) SaveResult(ctx context.Context, jobID uuid.UUID, contentType string, data []byte) error {
+	return q.jobs.SaveResult(ctx, &model.QueuedJobResult{
+		JobID:       jobID,
+		ContentType: contentType,
+		Data:        data,
+		CreatedAt:   q.clock.Now(),
+	})
+}
+
+// Retry gives a failed job a fresh set of attempts
+func (q *Queue) Retry(ctx context.Context, id uuid.UUID) (*model.QueuedJob, error) {
+	job, err := q.jobs.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != model.QueuedJobFailed {
+		return nil, ErrNotFailed
+	}
+
+	now := q.clock.Now()
+	job.Status = model.QueuedJobPending
+	job.Attempts = 0
+	job.Interrupted = false
+	job.RunAt = now
+	job.FinishedAt = nil
+	job.UpdatedAt = now
+	if err := q.jobs.Update(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Start starts running due jobs
+func (q *Queue) Start(ctx context.Context) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.isRunning {
+		return
+	}
+	q.isRunning = true
+
+	go q.run(ctx)
+}
+
+// Stop stops running jobs. Attempts in progress finish on their own.
+func (q *Queue) Stop() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if !q.isRunning {
+		return
+	}
+
+	close(q.stopChan)
+	q.isRunning = false
+}
+
+func (q *Queue) run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.stopChan:
+			return
+		case <-ticker.C:
+			recovery.Do("queue.poll", func() {
+				if err := q.RunDue(ctx); err != nil {
+					log.Printf("Error running queued jobs: %v", err)
+				}
+			})
+		}
+	}
+}
+
+// RunDue claims one batch of due jobs this queue has handlers for and runs
+// them concurrently
+func (q *Queue) RunDue(ctx context.Context) error {
+	q.handlersMu.RLock()
+	kinds := make([]string, 0, len(q.handlers))
+	for kind := range q.handlers {
+		kinds = append(kinds, kind)
+	}
+	q.handlersMu.RUnlock()
+	if len(kinds) == 0 {
+		return nil
+	}
+
+	now := q.clock.Now()
+	jobs, err := q.jobs.Claim(ctx, kinds, now, now.Add(lease), claimBatchSize)
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(jobs))
+	for i, job := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = q.attempt(ctx, job)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// attempt runs a claimed job once and records the outcome, scheduling a
+// retry or failing the job when it returns an error
+func (q *Queue) attempt(ctx context.Context, job *model.QueuedJob) error {
+	q.handlersMu.RLock()
+	handler := q.handlers[job.Kind]
+	q.handlersMu.RUnlock()
+
+	runCtx, cancel := context.WithTimeout(ctx, lease)
+	// A panic fails the attempt rather than taking the worker down
+	err := recovery.Call("queue."+job.Kind, func() error { return handler(runCtx, job) })
+	cancel()
+
+	now := q.clock.Now()
+	job.UpdatedAt = now
+	var permanent *permanentError
+	switch {
+	case err == nil:
+		job.Status = model.QueuedJobSucceeded
+		job.LastError = ""
+		job.FinishedAt = &now
+	case job.Attempts >= job.MaxAttempts || errors.As(err, &permanent):
+		log.Printf("Job %s (%s) failed after %d attempts: %v", job.ID, job.Kind, job.Attempts, err)
+		job.Status = model.QueuedJobFailed
+		job.LastError = err.Error()
+		job.FinishedAt = &now
+	default:
+		job.Status = model.QueuedJobPending
+		job.LastError = err.Error()
+		job.RunAt = now.Add(backoff(job.Attempts))
+	}
+
+	if err := q.jobs.Update(ctx, job); err != nil {
+		return fmt.Errorf("failed to record job %s: %w", job.ID, err)
+	}
+	return nil
+}
+
+// backoff returns the delay before the retry following attempt
+func backoff(attempt int) time.Duration {
+	delay := initialBackoff
+	for i := 1; i < attempt && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, maxBackoff)
+}
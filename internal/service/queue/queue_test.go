@@ -0,0 +1,154 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+	"github.com/sungminna/upbit-trading-platform/pkg/clock"
+)
+
+func TestQueue_RetriesWithBackoffThenFails(t *testing.T) {
+	store := memory.NewStore()
+	now := clock.NewFake(time.Now())
+	q := NewQueue(store.Jobs()).WithClock(now)
+	ctx := context.Background()
+
+	calls := 0
+	q.Handle("test", func(ctx context.Context, job *model.QueuedJob) error {
+		calls++
+		return errors.New("boom")
+	})
+	job, err := q.Enqueue(ctx, "test", map[string]int{"n": 1}, 2)
+	require.NoError(t, err)
+
+	require.NoError(t, q.RunDue(ctx))
+	stored, err := store.Jobs().GetByID(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.QueuedJobPending, stored.Status)
+	assert.Equal(t, "boom", stored.LastError)
+	assert.True(t, stored.RunAt.After(now.Now().Add(initialBackoff/2)))
+
+	// Not due until the backoff passes
+	require.NoError(t, q.RunDue(ctx))
+	assert.Equal(t, 1, calls)
+
+	now.Advance(maxBackoff)
+	require.NoError(t, q.RunDue(ctx))
+	stored, err = store.Jobs().GetByID(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.QueuedJobFailed, stored.Status)
+	assert.Equal(t, 2, stored.Attempts)
+	assert.NotNil(t, stored.FinishedAt)
+
+	_, err = q.Retry(ctx, stored.ID)
+	require.NoError(t, err)
+	require.NoError(t, q.RunDue(ctx))
+	assert.Equal(t, 3, calls)
+
+	succeeded, err := q.Enqueue(ctx, "test", nil, 1)
+	require.NoError(t, err)
+	_, err = q.Retry(ctx, succeeded.ID)
+	assert.ErrorIs(t, err, ErrNotFailed)
+}
+
+func TestQueue_RunsOnlyHandledKinds(t *testing.T) {
+	store := memory.NewStore()
+	q := NewQueue(store.Jobs())
+	ctx := context.Background()
+
+	q.Handle("handled", func(ctx context.Context, job *model.QueuedJob) error { return nil })
+	handled, err := q.Enqueue(ctx, "handled", nil, DefaultMaxAttempts)
+	require.NoError(t, err)
+	other, err := q.Enqueue(ctx, "other", nil, DefaultMaxAttempts)
+	require.NoError(t, err)
+
+	require.NoError(t, q.RunDue(ctx))
+
+	stored, err := store.Jobs().GetByID(ctx, handled.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.QueuedJobSucceeded, stored.Status)
+	stored, err = store.Jobs().GetByID(ctx, other.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.QueuedJobPending, stored.Status)
+}
+
+func TestQueue_RecoversFromPanics(t *testing.T) {
+	store := memory.NewStore()
+	q := NewQueue(store.Jobs())
+	ctx := context.Background()
+
+	q.Handle("test", func(ctx context.Context, job *model.QueuedJob) error { panic("bad payload") })
+	job, err := q.Enqueue(ctx, "test", nil, 1)
+	require.NoError(t, err)
+
+	require.NoError(t, q.RunDue(ctx))
+	stored, err := store.Jobs().GetByID(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.QueuedJobFailed, stored.Status)
+	assert.Equal(t, "panic: bad payload", stored.LastError)
+}
+
+func TestQueue_PermanentErrorFailsAtOnce(t *testing.T) {
+	store := memory.NewStore()
+	q := NewQueue(store.Jobs())
+	ctx := context.Background()
+
+	q.Handle("test", func(ctx context.Context, job *model.QueuedJob) error {
+		return Permanent(errors.New("invalid config"))
+	})
+	job, err := q.Enqueue(ctx, "test", nil, DefaultMaxAttempts)
+	require.NoError(t, err)
+
+	require.NoError(t, q.RunDue(ctx))
+	stored, err := store.Jobs().GetByID(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.QueuedJobFailed, stored.Status)
+	assert.Equal(t, 1, stored.Attempts)
+	assert.Equal(t, "invalid config", stored.LastError)
+}
+
+func TestQueue_UserJobResults(t *testing.T) {
+	store := memory.NewStore()
+	q := NewQueue(store.Jobs())
+	ctx := context.Background()
+	userID := uuid.New()
+
+	q.Handle("test", func(ctx context.Context, job *model.QueuedJob) error {
+		return q.SaveResult(ctx, job.ID, "text/csv", []byte("a,b\n"))
+	})
+	job, err := q.EnqueueFor(ctx, userID, "test", nil, DefaultMaxAttempts)
+	require.NoError(t, err)
+	_, err = q.Enqueue(ctx, "test", nil, DefaultMaxAttempts)
+	require.NoError(t, err)
+
+	_, err = q.Result(ctx, userID, job.ID)
+	assert.ErrorIs(t, err, ErrNoResult)
+
+	require.NoError(t, q.RunDue(ctx))
+	result, err := q.Result(ctx, userID, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "text/csv", result.ContentType)
+	assert.Equal(t, "a,b\n", string(result.Data))
+
+	_, err = q.Result(ctx, uuid.New(), job.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound, "other users can't see the job")
+
+	jobs, err := q.ListForUser(ctx, userID, repository.JobQueueFilter{})
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, job.ID, jobs[0].ID)
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, initialBackoff, backoff(1))
+	assert.Equal(t, 4*initialBackoff, backoff(3))
+	assert.Equal(t, maxBackoff, backoff(30))
+}
@@ -0,0 +1,24 @@
+package rebalance
+
+import "errors"
+
+var (
+	ErrNoWeights        = &RebalanceError{message: "weights must not be empty"}
+	ErrInvalidWeight    = &RebalanceError{message: "each weight needs a currency and a percent between 0 and 100"}
+	ErrDuplicateWeight  = &RebalanceError{message: "each currency may only be weighted once"}
+	ErrWeightsSum       = &RebalanceError{message: "weights must sum to 100"}
+	ErrInvalidThreshold = &RebalanceError{message: "drift_threshold must be between 0 and 100"}
+	ErrInvalidInterval  = &RebalanceError{message: "interval_hours must not be negative"}
+)
+
+// ErrInProgress is returned when the user's portfolio is already being rebalanced
+var ErrInProgress = errors.New("a rebalance is already in progress")
+
+// RebalanceError represents a target portfolio validation error
+type RebalanceError struct {
+	message string
+}
+
+func (e *RebalanceError) Error() string {
+	return e.message
+}
@@ -0,0 +1,609 @@
+// Package rebalance keeps users' accounts at a target allocation
+package rebalance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/notification"
+	"github.com/sungminna/upbit-trading-platform/internal/service/scheduler"
+	"github.com/sungminna/upbit-trading-platform/internal/service/trading"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/quotation"
+	"github.com/sungminna/upbit-trading-platform/pkg/cache"
+	"github.com/sungminna/upbit-trading-platform/pkg/recovery"
+)
+
+const (
+	checkInterval = time.Minute
+	claimKey      = "rebalance:"
+	// feeReserve is the share of a buy set aside for the trading fee
+	feeReserve = 0.0005
+	// driftCooldown spaces out rebalances triggered by drift, so orders that
+	// keep failing aren't retried every minute
+	driftCooldown = time.Hour
+	// DefaultWorkers is how many target portfolios are evaluated at once
+	DefaultWorkers = 8
+	// tickerBatchSize is how many markets are priced per ticker request
+	tickerBatchSize = 100
+)
+
+// BalanceSource provides users' cached exchange balances; balance.Service
+// satisfies it
+type BalanceSource interface {
+	Balances(ctx context.Context, userID uuid.UUID) (*model.AccountBalances, error)
+}
+
+// TickerSource provides current prices; gateway.QuotationAPI satisfies it
+type TickerSource interface {
+	GetTicker(ctx context.Context, markets []string) ([]quotation.Ticker, error)
+}
+
+// OrderPlacer places orders; trading.Engine satisfies it
+type OrderPlacer interface {
+	PlaceOrder(ctx context.Context, userID uuid.UUID, req trading.PlaceOrderRequest) (*model.Order, error)
+}
+
+// MaintenanceChecker reports Upbit maintenance; maintenance.Detector satisfies it
+type MaintenanceChecker interface {
+	Maintenance(market string) *model.Maintenance
+}
+
+// Plan is what it takes to bring an account back to its target allocation
+type Plan struct {
+	Equity    float64        `json:"equity"`
+	MaxDrift  float64        `json:"max_drift"` // Percentage points
+	Drift     []Drift        `json:"drift"`     // Largest target first
+	Orders    []PlannedOrder `json:"orders"`    // Sells first
+	PlannedAt time.Time      `json:"planned_at"`
+}
+
+// Drift compares a currency's share of equity with its target
+type Drift struct {
+	Currency      string  `json:"currency"`
+	Value         float64 `json:"value"`
+	Percent       float64 `json:"percent"`
+	TargetValue   float64 `json:"target_value"`
+	TargetPercent float64 `json:"target_percent"`
+	Drift         float64 `json:"drift"`                // Percent less the target, in percentage points
+	Untradable    bool    `json:"untradable,omitempty"` // No KRW market; valued at cost and left alone
+}
+
+// PlannedOrder is a market order of the plan
+type PlannedOrder struct {
+	Market   string          `json:"market"`
+	Side     model.OrderSide `json:"side"`
+	Quantity float64         `json:"quantity"`
+	Price    float64         `json:"price"`  // Current price
+	Amount   float64         `json:"amount"` // KRW
+	// Deferred buys can't be funded until sale proceeds settle; they are
+	// placed by a follow-up pass a minute later
+	Deferred bool `json:"deferred,omitempty"`
+}
+
+// resized returns the order for a different KRW amount
+func (o PlannedOrder) resized(amount float64) PlannedOrder {
+	o.Amount = amount
+	o.Quantity = amount / o.Price
+	return o
+}
+
+// Result is the outcome of a rebalance
+type Result struct {
+	Plan   *Plan          `json:"plan"`
+	Orders []*model.Order `json:"orders"`
+	Failed []FailedOrder  `json:"failed,omitempty"`
+}
+
+// FailedOrder is a planned order the engine rejected
+type FailedOrder struct {
+	PlannedOrder
+	Error string `json:"error"`
+}
+
+// Service manages target portfolios and rebalances them, on demand or
+// automatically on their schedule or drift threshold
+type Service struct {
+	targets     repository.TargetPortfolioRepository
+	balances    BalanceSource
+	tickers     TickerSource
+	placer      OrderPlacer
+	claims      cache.Cache           // Claims users so instances don't rebalance one twice
+	notifier    notification.Notifier // Optional
+	maintenance MaintenanceChecker    // Optional
+	workers     int
+	inFlight    map[uuid.UUID]bool // Users being rebalanced by this instance
+	flightMu    sync.Mutex
+}
+
+// NewService creates a new rebalancing service
+func NewService(targets repository.TargetPortfolioRepository, balances BalanceSource, tickers TickerSource, placer OrderPlacer, claims cache.Cache) *Service {
+	return &Service{
+		targets:  targets,
+		balances: balances,
+		tickers:  tickers,
+		placer:   placer,
+		claims:   claims,
+		workers:  DefaultWorkers,
+		inFlight: make(map[uuid.UUID]bool),
+	}
+}
+
+// WithWorkers sets how many target portfolios are evaluated at once
+func (s *Service) WithWorkers(workers int) *Service {
+	s.workers = max(workers, 1)
+	return s
+}
+
+// WithNotifier makes the service tell users about automatic rebalances
+func (s *Service) WithNotifier(notifier notification.Notifier) *Service {
+	s.notifier = notifier
+	return s
+}
+
+// WithMaintenance makes automatic rebalances wait while any market of the
+// target portfolio is under Upbit maintenance, rather than rebalance partly
+func (s *Service) WithMaintenance(maintenance MaintenanceChecker) *Service {
+	s.maintenance = maintenance
+	return s
+}
+
+// Job returns the job rebalancing portfolios that drifted or are due
+func (s *Service) Job() scheduler.Job {
+	return scheduler.Job{
+		Name:     "rebalance",
+		Schedule: scheduler.Every(checkInterval),
+		Run:      s.Evaluate,
+	}
+}
+
+// Get returns the user's target portfolio
+func (s *Service) Get(ctx context.Context, userID uuid.UUID) (*model.TargetPortfolio, error) {
+	return s.targets.Get(ctx, userID)
+}
+
+// Save validates and stores the user's target portfolio, keeping when it
+// was last rebalanced
+func (s *Service) Save(ctx context.Context, target *model.TargetPortfolio) (*model.TargetPortfolio, error) {
+	if err := validate(target); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	target.CreatedAt, target.UpdatedAt = now, now
+	target.PendingBuys = false
+	existing, err := s.targets.Get(ctx, target.UserID)
+	switch {
+	case err == nil:
+		target.CreatedAt = existing.CreatedAt
+		target.LastRebalancedAt = existing.LastRebalancedAt
+	case !errors.Is(err, repository.ErrNotFound):
+		return nil, err
+	}
+
+	if err := s.targets.Save(ctx, target); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+// Delete removes the user's target portfolio
+func (s *Service) Delete(ctx context.Context, userID uuid.UUID) error {
+	return s.targets.Delete(ctx, userID)
+}
+
+// Preview plans a rebalance of the user's account without placing orders
+func (s *Service) Preview(ctx context.Context, userID uuid.UUID) (*Plan, error) {
+	target, err := s.targets.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.plan(ctx, target, time.Now(), nil)
+}
+
+// Rebalance rebalances the user's account now
+func (s *Service) Rebalance(ctx context.Context, userID uuid.UUID) (*Result, error) {
+	target, err := s.targets.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.begin(userID) {
+		return nil, ErrInProgress
+	}
+	defer s.end(userID)
+	claimed, err := s.claims.SetNX(ctx, claimKey+userID.String(), []byte{1}, checkInterval)
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, ErrInProgress
+	}
+	return s.execute(ctx, target, time.Now(), false, nil)
+}
+
+// Evaluate rebalances the enabled target portfolios that are due, have
+// drifted past their threshold or have buys waiting. The targeted markets
+// are priced once for the whole pass and portfolios are evaluated by a
+// bounded pool of workers; users still being rebalanced are skipped. A
+// failure for one user doesn't stop the others.
+func (s *Service) Evaluate(ctx context.Context, now time.Time) error {
+	targets, err := s.targets.ListEnabled(ctx)
+	if err != nil {
+		return err
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+
+	var markets []string
+	seen := make(map[string]bool)
+	for _, target := range targets {
+		for _, w := range target.Weights {
+			if w.Currency != "KRW" && !seen[w.Currency] {
+				seen[w.Currency] = true
+				markets = append(markets, "KRW-"+w.Currency)
+			}
+		}
+	}
+	sort.Strings(markets)
+	prices, err := s.prices(ctx, markets)
+	if err != nil {
+		return err
+	}
+
+	var (
+		wg    sync.WaitGroup
+		errMu sync.Mutex
+		errs  []error
+	)
+	queue := make(chan *model.TargetPortfolio)
+	for range min(s.workers, len(targets)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for target := range queue {
+				err := recovery.Call("rebalance.evaluate", func() error { return s.evaluate(ctx, target, now, prices) })
+				if err != nil {
+					errMu.Lock()
+					errs = append(errs, fmt.Errorf("user %s: %w", target.UserID, err))
+					errMu.Unlock()
+				}
+			}
+		}()
+	}
+	for _, target := range targets {
+		if s.underMaintenance(target) {
+			continue
+		}
+		queue <- target
+	}
+	close(queue)
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// underMaintenance reports whether any market of the target portfolio is
+// under maintenance
+func (s *Service) underMaintenance(target *model.TargetPortfolio) bool {
+	if s.maintenance == nil {
+		return false
+	}
+	for _, w := range target.Weights {
+		if w.Currency != "KRW" && s.maintenance.Maintenance("KRW-"+w.Currency) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// begin marks the user as being rebalanced, reporting false if they already are
+func (s *Service) begin(userID uuid.UUID) bool {
+	s.flightMu.Lock()
+	defer s.flightMu.Unlock()
+
+	if s.inFlight[userID] {
+		return false
+	}
+	s.inFlight[userID] = true
+	return true
+}
+
+// end marks the user's rebalance as finished
+func (s *Service) end(userID uuid.UUID) {
+	s.flightMu.Lock()
+	defer s.flightMu.Unlock()
+	delete(s.inFlight, userID)
+}
+
+// evaluate rebalances the target if it is due. prices holds the current
+// prices of the targeted currencies; any others are fetched.
+func (s *Service) evaluate(ctx context.Context, target *model.TargetPortfolio, now time.Time, prices map[string]float64) error {
+	if !s.begin(target.UserID) {
+		return nil
+	}
+	defer s.end(target.UserID)
+
+	buysOnly := target.PendingBuys
+	if !buysOnly && !target.Due(now) {
+		if target.DriftThreshold <= 0 || (target.LastRebalancedAt != nil && now.Sub(*target.LastRebalancedAt) < driftCooldown) {
+			return nil
+		}
+		plan, err := s.plan(ctx, target, now, prices)
+		if err != nil {
+			return err
+		}
+		if plan.MaxDrift < target.DriftThreshold {
+			return nil
+		}
+	}
+
+	claimed, err := s.claims.SetNX(ctx, claimKey+target.UserID.String(), []byte{1}, checkInterval)
+	if err != nil || !claimed {
+		return err
+	}
+
+	result, err := s.execute(ctx, target, now, buysOnly, prices)
+	if err != nil {
+		return err
+	}
+	s.notify(ctx, target, result, now)
+	return nil
+}
+
+// execute plans and places the rebalance orders, sells first. Buys the free
+// KRW can't fund yet are left to a buys-only pass once the sales settle.
+func (s *Service) execute(ctx context.Context, target *model.TargetPortfolio, now time.Time, buysOnly bool, prices map[string]float64) (*Result, error) {
+	plan, err := s.plan(ctx, target, now, prices)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Plan: plan, Orders: []*model.Order{}}
+	sold, deferred := false, false
+	for _, planned := range plan.Orders {
+		switch {
+		case planned.Side == model.OrderSideAsk && buysOnly:
+			continue
+		case planned.Deferred:
+			deferred = true
+			continue
+		}
+
+		price := planned.Price
+		order, err := s.placer.PlaceOrder(ctx, target.UserID, trading.PlaceOrderRequest{
+			Market:   planned.Market,
+			Side:     planned.Side,
+			Type:     model.OrderTypeMarket,
+			Quantity: planned.Quantity,
+			Price:    &price,
+			Source:   model.OrderSourceRebalance,
+		})
+		if err != nil {
+			result.Failed = append(result.Failed, FailedOrder{PlannedOrder: planned, Error: err.Error()})
+			continue
+		}
+		result.Orders = append(result.Orders, order)
+		if planned.Side == model.OrderSideAsk {
+			sold = true
+		}
+	}
+
+	target.PendingBuys = sold && deferred
+	if !buysOnly {
+		target.LastRebalancedAt = &now
+	}
+	target.UpdatedAt = now
+	if err := s.targets.Save(ctx, target); err != nil {
+		return nil, fmt.Errorf("failed to save target portfolio: %w", err)
+	}
+	return result, nil
+}
+
+// plan values the account at current prices and works out the market orders
+// that bring each currency back to its target. Prices of currencies missing
+// from known, which may be nil, are fetched.
+func (s *Service) plan(ctx context.Context, target *model.TargetPortfolio, now time.Time, known map[string]float64) (*Plan, error) {
+	balances, err := s.balances.Balances(ctx, target.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load balances: %w", err)
+	}
+
+	// Every currency held or targeted, with what is held of it
+	held := make(map[string]model.Balance)
+	for _, b := range balances.Balances {
+		held[b.Currency] = b
+	}
+	for _, w := range target.Weights {
+		if _, ok := held[w.Currency]; !ok {
+			held[w.Currency] = model.Balance{Currency: w.Currency}
+		}
+	}
+
+	var markets []string
+	for currency := range held {
+		if _, ok := known[currency]; !ok && currency != "KRW" {
+			markets = append(markets, "KRW-"+currency)
+		}
+	}
+	sort.Strings(markets)
+	prices, err := s.prices(ctx, markets)
+	if err != nil {
+		return nil, err
+	}
+	for currency, price := range known {
+		prices[currency] = price
+	}
+
+	plan := &Plan{Drift: make([]Drift, 0, len(held)), Orders: []PlannedOrder{}, PlannedAt: now}
+	for currency, b := range held {
+		d := Drift{Currency: currency, TargetPercent: target.Weight(currency)}
+		price, ok := prices[currency]
+		switch {
+		case currency == "KRW":
+			price = 1
+		case !ok:
+			price, d.Untradable = b.AvgBuyPrice, true
+		}
+		d.Value = (b.Balance + b.Locked) * price
+		plan.Equity += d.Value
+		plan.Drift = append(plan.Drift, d)
+	}
+
+	var sells, buys []PlannedOrder
+	for i := range plan.Drift {
+		d := &plan.Drift[i]
+		d.TargetValue = plan.Equity * d.TargetPercent / 100
+		if plan.Equity > 0 {
+			d.Percent = d.Value / plan.Equity * 100
+		}
+		d.Drift = d.Percent - d.TargetPercent
+		plan.MaxDrift = max(plan.MaxDrift, math.Abs(d.Drift))
+		if d.Currency == "KRW" || d.Untradable {
+			continue
+		}
+
+		price := prices[d.Currency]
+		order := PlannedOrder{Market: "KRW-" + d.Currency, Price: price}
+		diff := d.TargetValue - d.Value
+		switch {
+		case diff <= -model.MinOrderAmount:
+			// Only free coins can be sold; a zero target sells them all
+			free := held[d.Currency].Balance
+			order.Side = model.OrderSideAsk
+			order.Quantity = free
+			if d.TargetPercent > 0 {
+				order.Quantity = min(-diff/price, free)
+			}
+		case diff >= model.MinOrderAmount:
+			order.Side = model.OrderSideBid
+			order.Quantity = diff / price
+		default:
+			continue
+		}
+		order.Amount = order.Quantity * price
+		if order.Amount < model.MinOrderAmount {
+			continue
+		}
+		if order.Side == model.OrderSideAsk {
+			sells = append(sells, order)
+		} else {
+			buys = append(buys, order)
+		}
+	}
+
+	// Largest orders first. Buys are funded from free cash in that order;
+	// the rest wait for the sales.
+	byAmount := func(orders []PlannedOrder) func(i, j int) bool {
+		return func(i, j int) bool { return orders[i].Amount > orders[j].Amount }
+	}
+	sort.Slice(sells, byAmount(sells))
+	sort.Slice(buys, byAmount(buys))
+	plan.Orders = append(plan.Orders, sells...)
+	cash := balances.Free("KRW")
+	for _, buy := range buys {
+		fundable := cash / (1 + feeReserve)
+		if buy.Amount <= fundable {
+			cash -= buy.Amount * (1 + feeReserve)
+			plan.Orders = append(plan.Orders, buy)
+			continue
+		}
+		// Buy what cash allows now and defer the rest
+		if fundable >= model.MinOrderAmount {
+			funded := buy.resized(fundable)
+			plan.Orders = append(plan.Orders, funded)
+			cash = 0
+			buy = buy.resized(buy.Amount - fundable)
+		}
+		if buy.Amount >= model.MinOrderAmount {
+			buy.Deferred = true
+			plan.Orders = append(plan.Orders, buy)
+		}
+	}
+	sort.Slice(plan.Drift, func(i, j int) bool {
+		if plan.Drift[i].TargetPercent != plan.Drift[j].TargetPercent {
+			return plan.Drift[i].TargetPercent > plan.Drift[j].TargetPercent
+		}
+		return plan.Drift[i].Currency < plan.Drift[j].Currency
+	})
+	return plan, nil
+}
+
+// prices returns the current prices of KRW markets by currency, fetched in
+// batches
+func (s *Service) prices(ctx context.Context, markets []string) (map[string]float64, error) {
+	prices := make(map[string]float64)
+	for start := 0; start < len(markets); start += tickerBatchSize {
+		tickers, err := s.tickers.GetTicker(ctx, markets[start:min(start+tickerBatchSize, len(markets))])
+		if err != nil {
+			return nil, fmt.Errorf("failed to get prices: %w", err)
+		}
+		for _, t := range tickers {
+			prices[strings.TrimPrefix(t.Market, "KRW-")] = t.TradePrice
+		}
+	}
+	return prices, nil
+}
+
+func (s *Service) notify(ctx context.Context, target *model.TargetPortfolio, result *Result, now time.Time) {
+	if s.notifier == nil || (len(result.Orders) == 0 && len(result.Failed) == 0) {
+		return
+	}
+
+	message := fmt.Sprintf("Placed %d rebalancing orders (max drift %.1f%%p).", len(result.Orders), result.Plan.MaxDrift)
+	if len(result.Failed) > 0 {
+		message += fmt.Sprintf(" %d orders failed: %s.", len(result.Failed), result.Failed[0].Error)
+	}
+	if target.PendingBuys {
+		message += " Buys follow once the sales settle."
+	}
+
+	n := model.NewNotification(target.UserID, model.NotificationRebalance, "Portfolio rebalanced", message, map[string]any{
+		"orders":    len(result.Orders),
+		"failed":    len(result.Failed),
+		"max_drift": result.Plan.MaxDrift,
+	}, now)
+	if err := s.notifier.Notify(ctx, n); err != nil {
+		log.Printf("Error notifying user %s of a rebalance: %v", target.UserID, err)
+	}
+}
+
+// validate checks and normalizes a target portfolio
+func validate(target *model.TargetPortfolio) error {
+	if len(target.Weights) == 0 {
+		return ErrNoWeights
+	}
+	seen := make(map[string]bool)
+	var sum float64
+	for i := range target.Weights {
+		w := &target.Weights[i]
+		w.Currency = strings.ToUpper(strings.TrimSpace(w.Currency))
+		if w.Currency == "" || w.Percent <= 0 || w.Percent > 100 {
+			return ErrInvalidWeight
+		}
+		if seen[w.Currency] {
+			return ErrDuplicateWeight
+		}
+		seen[w.Currency] = true
+		sum += w.Percent
+	}
+	if math.Abs(sum-100) > 0.01 {
+		return ErrWeightsSum
+	}
+	if target.DriftThreshold < 0 || target.DriftThreshold > 100 {
+		return ErrInvalidThreshold
+	}
+	if target.IntervalHours < 0 {
+		return ErrInvalidInterval
+	}
+	return nil
+}
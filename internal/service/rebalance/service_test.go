@@ -0,0 +1,192 @@
+package rebalance
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+	"github.com/sungminna/upbit-trading-platform/internal/service/trading"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/quotation"
+	"github.com/sungminna/upbit-trading-platform/pkg/cache"
+)
+
+type staticBalances struct {
+	balances []model.Balance
+}
+
+func (s *staticBalances) Balances(ctx context.Context, userID uuid.UUID) (*model.AccountBalances, error) {
+	return &model.AccountBalances{UserID: userID, Balances: s.balances}, nil
+}
+
+type staticTickers map[string]float64
+
+func (s staticTickers) GetTicker(ctx context.Context, markets []string) ([]quotation.Ticker, error) {
+	var tickers []quotation.Ticker
+	for _, market := range markets {
+		if price, ok := s[market]; ok {
+			tickers = append(tickers, quotation.Ticker{Market: market, TradePrice: price})
+		}
+	}
+	return tickers, nil
+}
+
+type countingTickers struct {
+	staticTickers
+	mu    sync.Mutex
+	calls int
+}
+
+func (c *countingTickers) GetTicker(ctx context.Context, markets []string) ([]quotation.Ticker, error) {
+	c.mu.Lock()
+	c.calls++
+	c.mu.Unlock()
+	return c.staticTickers.GetTicker(ctx, markets)
+}
+
+type recordingPlacer struct {
+	mu     sync.Mutex
+	placed []trading.PlaceOrderRequest
+}
+
+func (p *recordingPlacer) PlaceOrder(ctx context.Context, userID uuid.UUID, req trading.PlaceOrderRequest) (*model.Order, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.placed = append(p.placed, req)
+	return model.NewOrder(userID, req.Market, req.Side, req.Type, req.Quantity, req.Price, time.Now()), nil
+}
+
+var prices = staticTickers{"KRW-BTC": 100000000, "KRW-ETH": 5000000}
+
+func TestService_Preview(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	userID := uuid.New()
+	balances := &staticBalances{balances: []model.Balance{
+		{Currency: "KRW", Balance: 1000000},
+		{Currency: "BTC", Balance: 0.01},
+	}}
+	placer := &recordingPlacer{}
+	service := NewService(store.TargetPortfolios(), balances, prices, placer, cache.NewMemoryCache())
+
+	_, err := service.Save(ctx, &model.TargetPortfolio{UserID: userID, Weights: []model.TargetWeight{
+		{Currency: "btc", Percent: 30}, {Currency: "ETH", Percent: 30}, {Currency: "KRW", Percent: 30},
+	}})
+	assert.ErrorIs(t, err, ErrWeightsSum)
+
+	_, err = service.Save(ctx, &model.TargetPortfolio{UserID: userID, Weights: []model.TargetWeight{
+		{Currency: "btc", Percent: 30}, {Currency: "ETH", Percent: 30}, {Currency: "KRW", Percent: 40},
+	}})
+	require.NoError(t, err)
+
+	plan, err := service.Preview(ctx, userID)
+	require.NoError(t, err)
+	assert.InDelta(t, 2000000, plan.Equity, 1e-6)
+	assert.InDelta(t, 30, plan.MaxDrift, 1e-9)
+	require.Len(t, plan.Drift, 3)
+	assert.Equal(t, "KRW", plan.Drift[0].Currency, "largest target first")
+	assert.InDelta(t, 10, plan.Drift[0].Drift, 1e-9)
+
+	require.Len(t, plan.Orders, 2)
+	assert.Equal(t, PlannedOrder{Market: "KRW-BTC", Side: model.OrderSideAsk, Quantity: 0.004, Price: 100000000, Amount: 400000}, plan.Orders[0])
+	assert.Equal(t, model.OrderSideBid, plan.Orders[1].Side)
+	assert.InDelta(t, 0.12, plan.Orders[1].Quantity, 1e-12)
+	assert.False(t, plan.Orders[1].Deferred)
+	assert.Empty(t, placer.placed, "previews place nothing")
+}
+
+func TestService_EvaluateDefersBuysUntilSalesSettle(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	userID := uuid.New()
+	balances := &staticBalances{balances: []model.Balance{
+		{Currency: "KRW", Balance: 100000},
+		{Currency: "BTC", Balance: 0.02},
+	}}
+	placer := &recordingPlacer{}
+	claims := cache.NewMemoryCache()
+	service := NewService(store.TargetPortfolios(), balances, prices, placer, claims)
+
+	_, err := service.Save(ctx, &model.TargetPortfolio{
+		UserID:        userID,
+		Weights:       []model.TargetWeight{{Currency: "BTC", Percent: 50}, {Currency: "ETH", Percent: 50}},
+		IntervalHours: 24,
+		Enabled:       true,
+	})
+	require.NoError(t, err)
+
+	now := time.Now()
+	require.NoError(t, service.Evaluate(ctx, now))
+	require.Len(t, placer.placed, 2, "the sale and the buy free cash covers")
+	assert.Equal(t, model.OrderSideAsk, placer.placed[0].Side)
+	assert.InDelta(t, 0.0095, placer.placed[0].Quantity, 1e-12)
+	assert.InDelta(t, 100000/(1+feeReserve), placer.placed[1].Quantity*(*placer.placed[1].Price), 1e-6)
+
+	target, err := service.Get(ctx, userID)
+	require.NoError(t, err)
+	assert.True(t, target.PendingBuys)
+	require.NotNil(t, target.LastRebalancedAt)
+
+	// The sale settled; the follow-up pass only buys
+	balances.balances = []model.Balance{
+		{Currency: "KRW", Balance: 949525},
+		{Currency: "BTC", Balance: 0.0105},
+		{Currency: "ETH", Balance: 0.02},
+	}
+	require.NoError(t, claims.Delete(ctx, claimKey+userID.String()))
+	require.NoError(t, service.Evaluate(ctx, now.Add(time.Minute)))
+	require.Len(t, placer.placed, 3)
+	assert.Equal(t, model.OrderSideBid, placer.placed[2].Side)
+
+	target, err = service.Get(ctx, userID)
+	require.NoError(t, err)
+	assert.False(t, target.PendingBuys)
+
+	// Nothing is due until the interval passes
+	require.NoError(t, claims.Delete(ctx, claimKey+userID.String()))
+	require.NoError(t, service.Evaluate(ctx, now.Add(time.Hour)))
+	assert.Len(t, placer.placed, 3)
+}
+
+func TestService_EvaluatePricesMarketsOncePerPass(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	balances := &staticBalances{balances: []model.Balance{{Currency: "KRW", Balance: 1000000}}}
+	tickers := &countingTickers{staticTickers: prices}
+	placer := &recordingPlacer{}
+	service := NewService(store.TargetPortfolios(), balances, tickers, placer, cache.NewMemoryCache()).WithWorkers(2)
+
+	for range 5 {
+		_, err := service.Save(ctx, &model.TargetPortfolio{
+			UserID:        uuid.New(),
+			Weights:       []model.TargetWeight{{Currency: "BTC", Percent: 50}, {Currency: "KRW", Percent: 50}},
+			IntervalHours: 24,
+			Enabled:       true,
+		})
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, service.Evaluate(ctx, time.Now()))
+	assert.Len(t, placer.placed, 5)
+	assert.Equal(t, 1, tickers.calls)
+
+	// A user still being rebalanced is skipped
+	busy := uuid.New()
+	_, err := service.Save(ctx, &model.TargetPortfolio{
+		UserID:        busy,
+		Weights:       []model.TargetWeight{{Currency: "ETH", Percent: 100}},
+		IntervalHours: 24,
+		Enabled:       true,
+	})
+	require.NoError(t, err)
+	require.True(t, service.begin(busy))
+	require.NoError(t, service.Evaluate(ctx, time.Now()))
+	assert.Len(t, placer.placed, 5)
+	_, err = service.Rebalance(ctx, busy)
+	assert.ErrorIs(t, err, ErrInProgress)
+}
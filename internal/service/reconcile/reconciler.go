@@ -0,0 +1,365 @@
+// Package reconcile finds positions sold outside the platform, e.g. directly
+// on Upbit, by comparing them with the user's exchange balances
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/notification"
+	"github.com/sungminna/upbit-trading-platform/internal/service/scheduler"
+	"github.com/sungminna/upbit-trading-platform/internal/service/trading"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/quotation"
+	"github.com/sungminna/upbit-trading-platform/pkg/cache"
+	"github.com/sungminna/upbit-trading-platform/pkg/clock"
+)
+
+const (
+	checkInterval = time.Minute
+	claimKey      = "reconcile:"
+	// claimTTL is how often the user is reminded of a shortfall in ModeFlag
+	claimTTL = 24 * time.Hour
+	// quantityTolerance absorbs float rounding when comparing quantities
+	quantityTolerance = 1e-8
+)
+
+// Mode is what the reconciler does about a shortfall
+type Mode string
+
+const (
+	ModeOff    Mode = "off"
+	ModeFlag   Mode = "flag"   // Notify the user
+	ModeReduce Mode = "reduce" // Write the missing quantity off the positions and notify the user
+)
+
+// Valid reports whether the mode is supported
+func (m Mode) Valid() bool {
+	switch m {
+	case ModeOff, ModeFlag, ModeReduce:
+		return true
+	}
+	return false
+}
+
+// BalanceSource provides users' cached exchange balances; balance.Service
+// satisfies it
+type BalanceSource interface {
+	Balances(ctx context.Context, userID uuid.UUID) (*model.AccountBalances, error)
+}
+
+// TickerSource provides current prices; quotation.Client satisfies it
+type TickerSource interface {
+	GetTicker(ctx context.Context, markets []string) ([]quotation.Ticker, error)
+}
+
+// Shortfall is a currency the user's open positions hold more of than their
+// exchange account does
+type Shortfall struct {
+	UserID    uuid.UUID   `json:"user_id"`
+	Currency  string      `json:"currency"`
+	Positions []uuid.UUID `json:"positions"` // Oldest first
+	Tracked   float64     `json:"tracked"`   // Held by the positions
+	Held      float64     `json:"held"`      // Held on the exchange
+
+	markets []string // Of the positions, priced before a write-off
+}
+
+// Missing returns the quantity the positions hold beyond the exchange balance
+func (s Shortfall) Missing() float64 {
+	return s.Tracked - s.Held
+}
+
+// Reconciler periodically compares the open positions of users with an
+// active API key with their synced exchange balances. Only currencies without
+// open platform sells whose positions haven't changed since the balances were
+// synced are compared, so the platform's own fills are never mistaken for
+// outside sales.
+type Reconciler struct {
+	mode      Mode
+	apiKeys   repository.UserAPIKeyRepository
+	orders    repository.OrderRepository
+	positions repository.PositionRepository
+	balances  BalanceSource
+	uow       repository.UnitOfWork
+	notifier  notification.Notifier // Optional
+	claims    cache.Cache           // Claims flagged shortfalls so instances report each only once
+	tickers   TickerSource          // Optional; prices the guards of positions written off entirely
+	clock     clock.Clock
+}
+
+// NewReconciler creates a new position reconciler
+func NewReconciler(
+	mode Mode,
+	apiKeys repository.UserAPIKeyRepository,
+	orders repository.OrderRepository,
+	positions repository.PositionRepository,
+	balances BalanceSource,
+	uow repository.UnitOfWork,
+	notifier notification.Notifier,
+	claims cache.Cache,
+) *Reconciler {
+	return &Reconciler{
+		mode:      mode,
+		apiKeys:   apiKeys,
+		orders:    orders,
+		positions: positions,
+		balances:  balances,
+		uow:       uow,
+		notifier:  notifier,
+		claims:    claims,
+		clock:     clock.Real,
+	}
+}
+
+// WithTickers makes the reconciler record the market price, rather than the
+// entry price, as the price a guard was cancelled at when its position is
+// written off entirely
+func (r *Reconciler) WithTickers(tickers TickerSource) *Reconciler {
+	r.tickers = tickers
+	return r
+}
+
+// WithClock sets the clock write-offs and notifications are dated by
+func (r *Reconciler) WithClock(c clock.Clock) *Reconciler {
+	r.clock = c
+	return r
+}
+
+// Job returns the job reconciling positions
+func (r *Reconciler) Job() scheduler.Job {
+	return scheduler.Job{
+		Name:     "position-reconciliation",
+		Schedule: scheduler.Every(checkInterval),
+		Run: func(ctx context.Context, at time.Time) error {
+			return r.Reconcile(ctx)
+		},
+	}
+}
+
+// Reconcile inspects every user with an active API key and acts on their
+// shortfalls. A failure for one user doesn't stop the others.
+func (r *Reconciler) Reconcile(ctx context.Context) error {
+	if r.mode == ModeOff {
+		return nil
+	}
+
+	userIDs, err := r.apiKeys.ListActiveUserIDs(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, userID := range userIDs {
+		shortfalls, err := r.Inspect(ctx, userID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("user %s: %w", userID, err))
+			continue
+		}
+		for _, shortfall := range shortfalls {
+			if err := r.act(ctx, shortfall); err != nil {
+				errs = append(errs, fmt.Errorf("user %s: %w", userID, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Inspect returns the currencies the user's positions hold more of than
+// their exchange account
+func (r *Reconciler) Inspect(ctx context.Context, userID uuid.UUID) ([]Shortfall, error) {
+	balances, err := r.balances.Balances(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load balances: %w", err)
+	}
+	positions, err := r.positions.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list positions: %w", err)
+	}
+	orders, err := r.orders.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders: %w", err)
+	}
+
+	// Sells still open may have filled on the exchange before the platform
+	// applied them
+	selling := make(map[string]bool)
+	for _, o := range orders {
+		if o.Side == model.OrderSideAsk && isOpen(o) {
+			selling[currencyOf(o.Market)] = true
+		}
+	}
+
+	byCurrency := make(map[string][]*model.Position)
+	changed := make(map[string]bool)
+	for _, p := range positions {
+		if p.Status != model.PositionStatusOpen || p.Side != model.PositionSideLong {
+			continue
+		}
+		currency := currencyOf(p.Market)
+		byCurrency[currency] = append(byCurrency[currency], p)
+		if p.UpdatedAt.After(balances.SyncedAt) {
+			changed[currency] = true
+		}
+	}
+
+	var shortfalls []Shortfall
+	for currency, open := range byCurrency {
+		if selling[currency] || changed[currency] {
+			continue
+		}
+
+		sort.Slice(open, func(i, j int) bool { return open[i].CreatedAt.Before(open[j].CreatedAt) })
+		shortfall := Shortfall{UserID: userID, Currency: currency, Held: balances.Held(currency)}
+		for _, p := range open {
+			shortfall.Positions = append(shortfall.Positions, p.ID)
+			shortfall.Tracked += p.Quantity
+			if !slices.Contains(shortfall.markets, p.Market) {
+				shortfall.markets = append(shortfall.markets, p.Market)
+			}
+		}
+		if shortfall.Missing() > quantityTolerance {
+			shortfalls = append(shortfalls, shortfall)
+		}
+	}
+	sort.Slice(shortfalls, func(i, j int) bool { return shortfalls[i].Currency < shortfalls[j].Currency })
+	return shortfalls, nil
+}
+
+// act flags a shortfall to the user once per claimTTL or, in ModeReduce,
+// writes it off the positions
+func (r *Reconciler) act(ctx context.Context, shortfall Shortfall) error {
+	if r.mode == ModeReduce {
+		if err := r.reduce(ctx, shortfall); err != nil {
+			return fmt.Errorf("failed to reduce %s positions: %w", shortfall.Currency, err)
+		}
+		log.Printf("Wrote %g %s sold outside the platform off the positions of user %s",
+			shortfall.Missing(), shortfall.Currency, shortfall.UserID)
+		r.notify(ctx, shortfall, true)
+		return nil
+	}
+
+	key := claimKey + shortfall.UserID.String() + ":" + shortfall.Currency
+	claimed, err := r.claims.SetNX(ctx, key, []byte{1}, claimTTL)
+	if err != nil || !claimed {
+		return err
+	}
+	log.Printf("Positions of user %s hold %g %s more than the exchange", shortfall.UserID, shortfall.Missing(), shortfall.Currency)
+	r.notify(ctx, shortfall, false)
+	return nil
+}
+
+// reduce writes the missing quantity off the positions, newest first, and
+// records it in their history. Prices are fetched before the transaction is
+// opened, so a slow quotation API never holds it open.
+func (r *Reconciler) reduce(ctx context.Context, shortfall Shortfall) error {
+	prices := r.exitPrices(ctx, shortfall)
+	return r.uow.Do(ctx, func(tx repository.Tx) error {
+		missing := shortfall.Missing()
+		for i := len(shortfall.Positions) - 1; i >= 0 && missing > quantityTolerance; i-- {
+			position, err := tx.Positions().GetByID(ctx, shortfall.Positions[i])
+			if err != nil {
+				return err
+			}
+			if position.Status != model.PositionStatusOpen {
+				continue
+			}
+
+			qty := min(missing, position.Quantity)
+			now := r.clock.Now()
+			position.WriteOff(qty, now)
+			if err := tx.Positions().Update(ctx, position); err != nil {
+				return err
+			}
+			if err := trading.CancelAutomations(ctx, tx, position, exitPrice(position, prices)); err != nil {
+				return err
+			}
+			event := model.NewPositionEvent(position, model.PositionEventExternalReduction, nil, 0, qty, now)
+			if err := tx.PositionEvents().Create(ctx, event); err != nil {
+				return err
+			}
+			missing -= qty
+		}
+		return nil
+	})
+}
+
+// exitPrices returns the market prices of a shortfall's positions, or none
+// if they are unknown
+func (r *Reconciler) exitPrices(ctx context.Context, shortfall Shortfall) map[string]float64 {
+	prices := make(map[string]float64)
+	if r.tickers == nil || len(shortfall.markets) == 0 {
+		return prices
+	}
+	tickers, err := r.tickers.GetTicker(ctx, shortfall.markets)
+	if err != nil {
+		log.Printf("No prices for %s, cancelling the guards of positions written off at their entry price: %v", shortfall.Currency, err)
+		return prices
+	}
+	for _, t := range tickers {
+		prices[t.Market] = t.TradePrice
+	}
+	return prices
+}
+
+// exitPrice returns the price a position written off entirely is taken to
+// have been sold at: the market price, or its entry price if that is unknown
+func exitPrice(position *model.Position, prices map[string]float64) float64 {
+	if position.Status != model.PositionStatusClosed {
+		return position.EntryPrice
+	}
+	if price, ok := prices[position.Market]; ok && price > 0 {
+		return price
+	}
+	return position.EntryPrice
+}
+
+func (r *Reconciler) notify(ctx context.Context, shortfall Shortfall, reduced bool) {
+	if r.notifier == nil {
+		return
+	}
+
+	message := fmt.Sprintf("Your open %s positions hold %g but your Upbit account only %g. Was it sold outside the platform?",
+		shortfall.Currency, shortfall.Tracked, shortfall.Held)
+	if reduced {
+		message = fmt.Sprintf("Your open %s positions held %g but your Upbit account only %g, so %g was written off them at cost.",
+			shortfall.Currency, shortfall.Tracked, shortfall.Held, shortfall.Missing())
+	}
+
+	n := model.NewNotification(shortfall.UserID, model.NotificationPositionMismatch, "Positions don't match your balance", message,
+		map[string]any{
+			"currency":  shortfall.Currency,
+			"positions": shortfall.Positions,
+			"tracked":   shortfall.Tracked,
+			"held":      shortfall.Held,
+			"reduced":   reduced,
+		}, r.clock.Now())
+	n.DedupKey = shortfall.Currency
+	if err := r.notifier.Notify(ctx, n); err != nil {
+		log.Printf("Error notifying user %s of a %s position mismatch: %v", shortfall.UserID, shortfall.Currency, err)
+	}
+}
+
+// currencyOf returns the currency a market trades, e.g. BTC for KRW-BTC
+func currencyOf(market string) string {
+	if _, currency, ok := strings.Cut(market, "-"); ok {
+		return currency
+	}
+	return market
+}
+
+func isOpen(order *model.Order) bool {
+	switch order.Status {
+	case model.OrderStatusPending, model.OrderStatusSubmitted, model.OrderStatusPartial:
+		return true
+	}
+	return false
+}
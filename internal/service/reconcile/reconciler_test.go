@@ -0,0 +1,159 @@
+package reconcile
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/quotation"
+	"github.com/sungminna/upbit-trading-platform/pkg/cache"
+)
+
+type recordingNotifier struct {
+	sent []*model.Notification
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, notification *model.Notification) error {
+	n.sent = append(n.sent, notification)
+	return nil
+}
+
+// stubBalances serves fixed balances synced now
+type stubBalances map[string]float64
+
+func (b stubBalances) Balances(ctx context.Context, userID uuid.UUID) (*model.AccountBalances, error) {
+	balances := &model.AccountBalances{UserID: userID, SyncedAt: time.Now()}
+	for currency, qty := range b {
+		balances.Balances = append(balances.Balances, model.Balance{Currency: currency, Balance: qty})
+	}
+	return balances, nil
+}
+
+// stubTickers serves fixed prices
+type stubTickers map[string]float64
+
+func (s stubTickers) GetTicker(ctx context.Context, markets []string) ([]quotation.Ticker, error) {
+	var tickers []quotation.Ticker
+	for _, market := range markets {
+		tickers = append(tickers, quotation.Ticker{Market: market, TradePrice: s[market]})
+	}
+	return tickers, nil
+}
+
+func setup(t *testing.T, mode Mode, held stubBalances) (*Reconciler, *memory.Store, *recordingNotifier, uuid.UUID) {
+	t.Helper()
+	store := memory.NewStore()
+	userID := uuid.New()
+	require.NoError(t, store.APIKeys().Create(context.Background(), model.NewUserAPIKey(userID, "access", "secret", "", time.Now())))
+
+	notifier := &recordingNotifier{}
+	r := NewReconciler(mode, store.APIKeys(), store.Orders(), store.Positions(), held, store, notifier, cache.NewMemoryCache())
+	return r, store, notifier, userID
+}
+
+func openPosition(t *testing.T, store *memory.Store, userID uuid.UUID, market string, qty float64, openedAt time.Time) *model.Position {
+	t.Helper()
+	position := model.NewPosition(userID, market, model.PositionSideLong, 100000000, qty, time.Now())
+	position.CreatedAt, position.UpdatedAt = openedAt, openedAt
+	require.NoError(t, store.Positions().Create(context.Background(), position))
+	return position
+}
+
+func TestReconciler_FlagsShortfallOnce(t *testing.T) {
+	ctx := context.Background()
+	r, store, notifier, userID := setup(t, ModeFlag, stubBalances{"BTC": 0.01, "ETH": 2})
+	past := time.Now().Add(-time.Hour)
+	openPosition(t, store, userID, "KRW-BTC", 0.01, past)
+	openPosition(t, store, userID, "KRW-BTC", 0.02, past.Add(time.Minute))
+	openPosition(t, store, userID, "KRW-ETH", 1, past)
+
+	shortfalls, err := r.Inspect(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, shortfalls, 1)
+	assert.Equal(t, "BTC", shortfalls[0].Currency)
+	assert.InDelta(t, 0.02, shortfalls[0].Missing(), 1e-12)
+
+	require.NoError(t, r.Reconcile(ctx))
+	require.NoError(t, r.Reconcile(ctx))
+	require.Len(t, notifier.sent, 1, "flagged once per day")
+	assert.Equal(t, model.NotificationPositionMismatch, notifier.sent[0].Type)
+
+	positions, err := store.Positions().ListByUser(ctx, userID)
+	require.NoError(t, err)
+	for _, p := range positions {
+		assert.Equal(t, model.PositionStatusOpen, p.Status, "flagging changes nothing")
+	}
+}
+
+func TestReconciler_ReducesNewestPositionsFirst(t *testing.T) {
+	ctx := context.Background()
+	r, store, notifier, userID := setup(t, ModeReduce, stubBalances{"BTC": 0.015})
+	past := time.Now().Add(-time.Hour)
+	older := openPosition(t, store, userID, "KRW-BTC", 0.01, past)
+	newer := openPosition(t, store, userID, "KRW-BTC", 0.01, past.Add(time.Minute))
+
+	require.NoError(t, r.Reconcile(ctx))
+	require.Len(t, notifier.sent, 1)
+
+	stored, err := store.Positions().GetByID(ctx, newer.ID)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.005, stored.Quantity, 1e-12)
+	assert.Zero(t, stored.RealizedPnL, "written off at cost")
+
+	stored, err = store.Positions().GetByID(ctx, older.ID)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.01, stored.Quantity, 1e-12)
+
+	events, err := store.PositionEvents().ListByPosition(ctx, newer.ID)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, model.PositionEventExternalReduction, events[0].Type)
+	assert.Nil(t, events[0].OrderID)
+	assert.InDelta(t, 0.005, events[0].Quantity, 1e-12)
+}
+
+func TestReconciler_SkipsPlatformActivity(t *testing.T) {
+	ctx := context.Background()
+	r, store, _, userID := setup(t, ModeReduce, stubBalances{})
+
+	// Changed after the balances were synced, e.g. by a platform buy
+	openPosition(t, store, userID, "KRW-BTC", 0.01, time.Now().Add(time.Minute))
+
+	// A platform sell that may have filled on the exchange already
+	position := openPosition(t, store, userID, "KRW-ETH", 1, time.Now().Add(-time.Hour))
+	sell := model.NewOrder(userID, "KRW-ETH", model.OrderSideAsk, model.OrderTypeMarket, 1, nil, time.Now())
+	sell.PositionID = &position.ID
+	sell.Status = model.OrderStatusSubmitted
+	require.NoError(t, store.Orders().Create(ctx, sell))
+
+	shortfalls, err := r.Inspect(ctx, userID)
+	require.NoError(t, err)
+	assert.Empty(t, shortfalls)
+}
+
+func TestReconciler_CancelsGuardsAtTheMarketPrice(t *testing.T) {
+	ctx := context.Background()
+	r, store, _, userID := setup(t, ModeReduce, stubBalances{})
+	r.WithTickers(stubTickers{"KRW-BTC": 90000000})
+	past := time.Now().Add(-time.Hour)
+	position := openPosition(t, store, userID, "KRW-BTC", 0.01, past)
+	guard := model.NewDrawdownGuard(position, 10, 0, past)
+	require.NoError(t, store.DrawdownGuards().Save(ctx, guard))
+
+	require.NoError(t, r.Reconcile(ctx))
+
+	stored, err := store.DrawdownGuards().GetByPosition(ctx, position.ID)
+	require.NoError(t, err)
+	assert.False(t, stored.Active)
+	updates, err := store.DrawdownGuardUpdates().ListSince(ctx, position.ID, past)
+	require.NoError(t, err)
+	require.NotEmpty(t, updates)
+	cancelled := updates[len(updates)-1]
+	assert.Equal(t, model.DrawdownGuardCancelled, cancelled.Kind)
+	assert.Equal(t, 90000000.0, cancelled.Price, "the market price, not the entry price")
+}
@@ -0,0 +1,18 @@
+package recurring
+
+var (
+	ErrInvalidMarket   = &RecurringError{message: "market must be a KRW market, e.g. KRW-BTC"}
+	ErrInvalidSide     = &RecurringError{message: "side must be bid or ask"}
+	ErrInvalidAmount   = &RecurringError{message: "amount must be positive, and at least 5,000 KRW for buys"}
+	ErrInvalidTimeZone = &RecurringError{message: "time_zone must be an IANA time zone, e.g. Asia/Seoul"}
+	ErrInvalidSchedule = &RecurringError{message: "schedule must be a cron expression running at most once an hour"}
+)
+
+// RecurringError represents a recurring order validation error
+type RecurringError struct {
+	message string
+}
+
+func (e *RecurringError) Error() string {
+	return e.message
+}
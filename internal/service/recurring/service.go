@@ -0,0 +1,344 @@
+// Package recurring places market orders on users' schedules, e.g. buying
+// 100,000 KRW of BTC every Monday morning
+package recurring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/notification"
+	"github.com/sungminna/upbit-trading-platform/internal/service/scheduler"
+	"github.com/sungminna/upbit-trading-platform/internal/service/trading"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/quotation"
+	"github.com/sungminna/upbit-trading-platform/pkg/clock"
+)
+
+const (
+	checkInterval = time.Minute
+	// DefaultTimeZone is the time zone of schedules that don't name one
+	DefaultTimeZone = "Asia/Seoul"
+	// minRunSpacing is the shortest time allowed between runs
+	minRunSpacing = time.Hour
+	// maxLateness is how late a run may still place its order. Runs missed
+	// by more, e.g. during downtime, are recorded as skipped.
+	maxLateness = time.Hour
+	// DefaultRunLimit is how many runs are listed by default
+	DefaultRunLimit = 50
+)
+
+// TickerSource provides current prices; gateway.QuotationAPI satisfies it
+type TickerSource interface {
+	GetTicker(ctx context.Context, markets []string) ([]quotation.Ticker, error)
+}
+
+// OrderPlacer places orders; trading.Engine satisfies it
+type OrderPlacer interface {
+	PlaceOrder(ctx context.Context, userID uuid.UUID, req trading.PlaceOrderRequest) (*model.Order, error)
+}
+
+// MaintenanceChecker reports Upbit maintenance; maintenance.Detector satisfies it
+type MaintenanceChecker interface {
+	Maintenance(market string) *model.Maintenance
+}
+
+// Service manages recurring orders and places their orders when due. Orders
+// go through the engine, so halts, risk limits and funds checks apply.
+type Service struct {
+	orders      repository.RecurringOrderRepository
+	placer      OrderPlacer
+	tickers     TickerSource
+	notifier    notification.Notifier // Optional
+	maintenance MaintenanceChecker    // Optional
+	clock       clock.Clock
+}
+
+// NewService creates a new recurring order service
+func NewService(orders repository.RecurringOrderRepository, placer OrderPlacer, tickers TickerSource) *Service {
+	return &Service{
+		orders:  orders,
+		placer:  placer,
+		tickers: tickers,
+		clock:   clock.Real,
+	}
+}
+
+// WithNotifier makes the service notify users of runs that fail
+func (s *Service) WithNotifier(notifier notification.Notifier) *Service {
+	s.notifier = notifier
+	return s
+}
+
+// WithMaintenance makes due orders wait while their market is under Upbit
+// maintenance. They run once it is over, unless that is more than maxLateness
+// after their scheduled time.
+func (s *Service) WithMaintenance(maintenance MaintenanceChecker) *Service {
+	s.maintenance = maintenance
+	return s
+}
+
+// WithClock sets the clock recurring orders are scheduled and run by
+func (s *Service) WithClock(c clock.Clock) *Service {
+	s.clock = c
+	return s
+}
+
+// Job returns the job placing due recurring orders
+func (s *Service) Job() scheduler.Job {
+	return scheduler.Job{
+		Name:     "recurring-orders",
+		Schedule: scheduler.Every(checkInterval),
+		Run: func(ctx context.Context, at time.Time) error {
+			return s.RunDue(ctx, s.clock.Now())
+		},
+	}
+}
+
+// Create validates and stores a new recurring order, active from its next
+// scheduled time
+func (s *Service) Create(ctx context.Context, order *model.RecurringOrder) (*model.RecurringOrder, error) {
+	now := s.clock.Now()
+	schedule, err := validate(order, now)
+	if err != nil {
+		return nil, err
+	}
+
+	order.ID = uuid.New()
+	order.Active = true
+	order.NextRunAt = schedule.Next(now)
+	order.LastRunAt = nil
+	order.CreatedAt = now
+	order.UpdatedAt = now
+	if err := s.orders.Create(ctx, order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// Get returns one of the user's recurring orders
+func (s *Service) Get(ctx context.Context, userID, id uuid.UUID) (*model.RecurringOrder, error) {
+	order, err := s.orders.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != userID {
+		return nil, repository.ErrNotFound
+	}
+	return order, nil
+}
+
+// List returns the user's recurring orders, oldest first
+func (s *Service) List(ctx context.Context, userID uuid.UUID) ([]*model.RecurringOrder, error) {
+	return s.orders.ListByUser(ctx, userID)
+}
+
+// Delete removes one of the user's recurring orders and its history
+func (s *Service) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	if _, err := s.Get(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.orders.Delete(ctx, id)
+}
+
+// Pause stops one of the user's recurring orders from running
+func (s *Service) Pause(ctx context.Context, userID, id uuid.UUID) (*model.RecurringOrder, error) {
+	order, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	order.Active = false
+	order.UpdatedAt = s.clock.Now()
+	if err := s.orders.Update(ctx, order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// Resume restarts a paused recurring order from its next scheduled time;
+// runs missed while it was paused are not made up
+func (s *Service) Resume(ctx context.Context, userID, id uuid.UUID) (*model.RecurringOrder, error) {
+	order, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	now := s.clock.Now()
+	schedule, err := parseSchedule(order.Schedule, order.TimeZone, now)
+	if err != nil {
+		return nil, err
+	}
+
+	order.Active = true
+	order.NextRunAt = schedule.Next(now)
+	order.UpdatedAt = now
+	if err := s.orders.Update(ctx, order); err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// Runs returns the latest runs of one of the user's recurring orders, newest
+// first
+func (s *Service) Runs(ctx context.Context, userID, id uuid.UUID, limit int) ([]*model.RecurringOrderRun, error) {
+	if _, err := s.Get(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = DefaultRunLimit
+	}
+	return s.orders.ListRuns(ctx, id, limit)
+}
+
+// RunDue runs every recurring order due at now. A failure of one doesn't stop
+// the others; all failures are returned together.
+func (s *Service) RunDue(ctx context.Context, now time.Time) error {
+	due, err := s.orders.ListDue(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, order := range due {
+		if s.maintenance != nil && s.maintenance.Maintenance(order.Market) != nil {
+			continue
+		}
+		if err := s.run(ctx, order, now); err != nil {
+			errs = append(errs, fmt.Errorf("recurring order %s: %w", order.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// run claims the order's due run and places its order. The next run is
+// scheduled first, so an order the engine rejects isn't retried every check.
+func (s *Service) run(ctx context.Context, order *model.RecurringOrder, now time.Time) error {
+	schedule, err := parseSchedule(order.Schedule, order.TimeZone, now)
+	if err != nil {
+		return err
+	}
+
+	run := model.NewRecurringOrderRun(order.ID, order.NextRunAt, now)
+	claimed, err := s.orders.CreateRun(ctx, run)
+	if err != nil {
+		return err
+	}
+
+	order.NextRunAt = schedule.Next(now)
+	order.UpdatedAt = now
+	if claimed {
+		order.LastRunAt = &now
+	}
+	if err := s.orders.Update(ctx, order); err != nil {
+		return err
+	}
+	if !claimed {
+		return nil // Another instance placed it
+	}
+
+	if late := now.Sub(run.ScheduledAt); late > maxLateness {
+		run.Status = model.RecurringRunSkipped
+		run.Error = fmt.Sprintf("missed by %s", late.Round(time.Minute))
+		return s.orders.UpdateRun(ctx, run)
+	}
+
+	placed, err := s.place(ctx, order)
+	if err != nil {
+		run.Status = model.RecurringRunFailed
+		run.Error = err.Error()
+		s.notifyFailed(ctx, order, err, now)
+	} else {
+		run.OrderID = &placed.ID
+	}
+	return s.orders.UpdateRun(ctx, run)
+}
+
+// place places a recurring order's market order: buys spend Amount KRW at
+// the current price, sells sell Amount
+func (s *Service) place(ctx context.Context, order *model.RecurringOrder) (*model.Order, error) {
+	req := trading.PlaceOrderRequest{
+		Market:   order.Market,
+		Side:     order.Side,
+		Type:     model.OrderTypeMarket,
+		Quantity: order.Amount,
+		Source:   model.OrderSourceRecurring,
+		SourceID: &order.ID,
+	}
+
+	if order.Side == model.OrderSideBid {
+		tickers, err := s.tickers.GetTicker(ctx, []string{order.Market})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get price: %w", err)
+		}
+		if len(tickers) == 0 || tickers[0].TradePrice <= 0 {
+			return nil, fmt.Errorf("no price for %s", order.Market)
+		}
+		price := tickers[0].TradePrice
+		req.Quantity = order.Amount / price
+		req.Price = &price
+	}
+
+	return s.placer.PlaceOrder(ctx, order.UserID, req)
+}
+
+func (s *Service) notifyFailed(ctx context.Context, order *model.RecurringOrder, cause error, now time.Time) {
+	if s.notifier == nil {
+		return
+	}
+
+	message := fmt.Sprintf("Your recurring %s %s order of %g couldn't be placed: %v", order.Market, order.Side, order.Amount, cause)
+	n := model.NewNotification(order.UserID, model.NotificationRecurringOrder, "Recurring order failed", message, map[string]any{
+		"recurring_order_id": order.ID,
+		"market":             order.Market,
+		"side":               order.Side,
+		"amount":             order.Amount,
+	}, now)
+	n.DedupKey = order.ID.String()
+	if err := s.notifier.Notify(ctx, n); err != nil {
+		log.Printf("Error notifying user %s of recurring order %s: %v", order.UserID, order.ID, err)
+	}
+}
+
+// validate checks and normalizes a new recurring order created at now and
+// returns its schedule
+func validate(order *model.RecurringOrder, now time.Time) (scheduler.Schedule, error) {
+	if !strings.HasPrefix(order.Market, "KRW-") {
+		return nil, ErrInvalidMarket
+	}
+	if order.Side != model.OrderSideBid && order.Side != model.OrderSideAsk {
+		return nil, ErrInvalidSide
+	}
+	if order.Amount <= 0 || (order.Side == model.OrderSideBid && order.Amount < model.MinOrderAmount) {
+		return nil, ErrInvalidAmount
+	}
+	order.Schedule = strings.TrimSpace(order.Schedule)
+	if order.TimeZone == "" {
+		order.TimeZone = DefaultTimeZone
+	}
+	return parseSchedule(order.Schedule, order.TimeZone, now)
+}
+
+// parseSchedule parses a cron expression in a time zone, rejecting schedules
+// whose runs after now are closer than minRunSpacing
+func parseSchedule(expr, timeZone string, now time.Time) (scheduler.Schedule, error) {
+	if _, err := time.LoadLocation(timeZone); err != nil {
+		return nil, ErrInvalidTimeZone
+	}
+	if expr == "" || strings.HasPrefix(expr, "CRON_TZ=") {
+		return nil, ErrInvalidSchedule
+	}
+
+	schedule, err := scheduler.ParseCron("CRON_TZ=" + timeZone + " " + expr)
+	if err != nil {
+		return nil, ErrInvalidSchedule
+	}
+	first := schedule.Next(now)
+	if first.IsZero() || schedule.Next(first).Sub(first) < minRunSpacing {
+		return nil, ErrInvalidSchedule
+	}
+	return schedule, nil
+}
@@ -0,0 +1,179 @@
+package recurring
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+	"github.com/sungminna/upbit-trading-platform/internal/service/trading"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/quotation"
+)
+
+type staticTickers map[string]float64
+
+func (s staticTickers) GetTicker(ctx context.Context, markets []string) ([]quotation.Ticker, error) {
+	var tickers []quotation.Ticker
+	for _, market := range markets {
+		if price, ok := s[market]; ok {
+			tickers = append(tickers, quotation.Ticker{Market: market, TradePrice: price})
+		}
+	}
+	return tickers, nil
+}
+
+type recordingPlacer struct {
+	mu     sync.Mutex
+	placed []trading.PlaceOrderRequest
+	err    error
+}
+
+func (p *recordingPlacer) PlaceOrder(ctx context.Context, userID uuid.UUID, req trading.PlaceOrderRequest) (*model.Order, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err != nil {
+		return nil, p.err
+	}
+	p.placed = append(p.placed, req)
+	return model.NewOrder(userID, req.Market, req.Side, req.Type, req.Quantity, req.Price, time.Now()), nil
+}
+
+var prices = staticTickers{"KRW-BTC": 100000000}
+
+func TestService_CreateValidates(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	service := NewService(store.RecurringOrders(), &recordingPlacer{}, prices)
+	userID := uuid.New()
+
+	tests := []struct {
+		order *model.RecurringOrder
+		err   error
+	}{
+		{&model.RecurringOrder{Market: "BTC-ETH", Side: model.OrderSideBid, Amount: 10000, Schedule: "0 9 * * 1"}, ErrInvalidMarket},
+		{&model.RecurringOrder{Market: "KRW-BTC", Side: "hold", Amount: 10000, Schedule: "0 9 * * 1"}, ErrInvalidSide},
+		{&model.RecurringOrder{Market: "KRW-BTC", Side: model.OrderSideBid, Amount: 1000, Schedule: "0 9 * * 1"}, ErrInvalidAmount},
+		{&model.RecurringOrder{Market: "KRW-BTC", Side: model.OrderSideBid, Amount: 10000, Schedule: "0 9 * * 1", TimeZone: "Mars/Base"}, ErrInvalidTimeZone},
+		{&model.RecurringOrder{Market: "KRW-BTC", Side: model.OrderSideBid, Amount: 10000, Schedule: "* * * * *"}, ErrInvalidSchedule},
+		{&model.RecurringOrder{Market: "KRW-BTC", Side: model.OrderSideBid, Amount: 10000, Schedule: "not cron"}, ErrInvalidSchedule},
+	}
+	for _, tt := range tests {
+		tt.order.UserID = userID
+		_, err := service.Create(ctx, tt.order)
+		assert.ErrorIs(t, err, tt.err)
+	}
+
+	order, err := service.Create(ctx, &model.RecurringOrder{
+		UserID: userID, Market: "KRW-BTC", Side: model.OrderSideBid, Amount: 100000, Schedule: "0 9 * * 1",
+	})
+	require.NoError(t, err)
+	assert.True(t, order.Active)
+	assert.Equal(t, DefaultTimeZone, order.TimeZone)
+
+	// Mondays at 09:00 KST are 00:00 UTC
+	next := order.NextRunAt.UTC()
+	assert.Equal(t, time.Monday, next.Weekday())
+	assert.Equal(t, 0, next.Hour())
+	assert.True(t, next.After(time.Now()))
+}
+
+func TestService_RunDue(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	placer := &recordingPlacer{}
+	service := NewService(store.RecurringOrders(), placer, prices)
+	userID := uuid.New()
+
+	order, err := service.Create(ctx, &model.RecurringOrder{
+		UserID: userID, Market: "KRW-BTC", Side: model.OrderSideBid, Amount: 100000, Schedule: "0 9 * * 1",
+	})
+	require.NoError(t, err)
+
+	now := order.NextRunAt.Add(time.Minute)
+	require.NoError(t, service.RunDue(ctx, now))
+	require.Len(t, placer.placed, 1)
+	req := placer.placed[0]
+	assert.Equal(t, model.OrderTypeMarket, req.Type)
+	assert.InDelta(t, 0.001, req.Quantity, 1e-12)
+	assert.Equal(t, model.OrderSourceRecurring, req.Source)
+	assert.Equal(t, order.ID, *req.SourceID)
+
+	// The next run is a week later, so running again places nothing
+	got, err := service.Get(ctx, userID, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, order.NextRunAt.Add(7*24*time.Hour), got.NextRunAt)
+	require.NoError(t, service.RunDue(ctx, now))
+	assert.Len(t, placer.placed, 1)
+
+	runs, err := service.Runs(ctx, userID, order.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, model.RecurringRunPlaced, runs[0].Status)
+	assert.NotNil(t, runs[0].OrderID)
+
+	// Other users can't see it
+	_, err = service.Runs(ctx, uuid.New(), order.ID, 0)
+	assert.Error(t, err)
+}
+
+func TestService_RunDueSkipsLateAndRecordsFailures(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	placer := &recordingPlacer{}
+	service := NewService(store.RecurringOrders(), placer, prices)
+	userID := uuid.New()
+
+	order, err := service.Create(ctx, &model.RecurringOrder{
+		UserID: userID, Market: "KRW-BTC", Side: model.OrderSideAsk, Amount: 0.5, Schedule: "0 9 * * *",
+	})
+	require.NoError(t, err)
+
+	// Missed by two hours, e.g. during downtime
+	require.NoError(t, service.RunDue(ctx, order.NextRunAt.Add(2*time.Hour)))
+	assert.Empty(t, placer.placed)
+
+	got, err := service.Get(ctx, userID, order.ID)
+	require.NoError(t, err)
+	placer.err = errors.New("insufficient funds")
+	require.NoError(t, service.RunDue(ctx, got.NextRunAt))
+
+	runs, err := service.Runs(ctx, userID, order.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Equal(t, model.RecurringRunFailed, runs[0].Status)
+	assert.Equal(t, "insufficient funds", runs[0].Error)
+	assert.Equal(t, model.RecurringRunSkipped, runs[1].Status)
+}
+
+func TestService_PauseResume(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	placer := &recordingPlacer{}
+	service := NewService(store.RecurringOrders(), placer, prices)
+	userID := uuid.New()
+
+	order, err := service.Create(ctx, &model.RecurringOrder{
+		UserID: userID, Market: "KRW-BTC", Side: model.OrderSideBid, Amount: 10000, Schedule: "0 9 * * *",
+	})
+	require.NoError(t, err)
+
+	paused, err := service.Pause(ctx, userID, order.ID)
+	require.NoError(t, err)
+	assert.False(t, paused.Active)
+	require.NoError(t, service.RunDue(ctx, order.NextRunAt.Add(time.Minute)))
+	assert.Empty(t, placer.placed)
+
+	resumed, err := service.Resume(ctx, userID, order.ID)
+	require.NoError(t, err)
+	assert.True(t, resumed.Active)
+	assert.True(t, resumed.NextRunAt.After(time.Now()))
+
+	_, err = service.Pause(ctx, uuid.New(), order.ID)
+	assert.Error(t, err)
+}
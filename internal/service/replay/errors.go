@@ -0,0 +1,17 @@
+package replay
+
+var (
+	ErrInvalidConfig  = &ReplayError{message: "replay needs a known source, markets, a valid interval for candles, from before to and a non-negative speed"}
+	ErrAlreadyRunning = &ReplayError{message: "a replay is already running"}
+	ErrNoMessages     = &ReplayError{message: "websocket messages are not recorded"}
+	ErrLiveDisabled   = &ReplayError{message: "replaying into the live price feed is disabled in this profile"}
+)
+
+// ReplayError represents a replay error
+type ReplayError struct {
+	message string
+}
+
+func (e *ReplayError) Error() string {
+	return e.message
+}
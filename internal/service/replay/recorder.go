@@ -0,0 +1,142 @@
+package replay
+
+import (
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/pkg/recovery"
+)
+
+const (
+	// DefaultRecorderBuffer is how many messages wait to be written before
+	// new ones are dropped
+	DefaultRecorderBuffer = 10000
+	// recorderBatch is the most messages written at once
+	recorderBatch = 1000
+	// recorderFlushInterval is how long a partial batch waits to be written
+	recorderFlushInterval = time.Second
+)
+
+// RecorderStats counts the messages passed to a recorder
+type RecorderStats struct {
+	Recorded int64 `json:"recorded"`
+	Dropped  int64 `json:"dropped"` // Buffer full or the write failed
+}
+
+// Recorder writes raw websocket messages to storage in batches. It satisfies
+// websocket.Recorder; messages are dropped rather than stalling the socket
+// when storage falls behind.
+type Recorder struct {
+	messages repository.RawMessageRepository
+	queue    chan model.RawMessage
+	recorded atomic.Int64
+	dropped  atomic.Int64
+	mu       sync.Mutex
+	stopChan chan struct{}
+	done     chan struct{}
+}
+
+// NewRecorder creates a new recorder buffering up to buffer messages
+func NewRecorder(messages repository.RawMessageRepository, buffer int) *Recorder {
+	return &Recorder{
+		messages: messages,
+		queue:    make(chan model.RawMessage, max(buffer, 1)),
+	}
+}
+
+// Record queues a message without blocking
+func (r *Recorder) Record(msg model.RawMessage) {
+	select {
+	case r.queue <- msg:
+	default:
+		r.dropped.Add(1)
+	}
+}
+
+// Stats returns how many messages were written and dropped
+func (r *Recorder) Stats() RecorderStats {
+	return RecorderStats{Recorded: r.recorded.Load(), Dropped: r.dropped.Load()}
+}
+
+// Start starts writing queued messages
+func (r *Recorder) Start(ctx context.Context) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stopChan != nil {
+		return
+	}
+	r.stopChan = make(chan struct{})
+	r.done = make(chan struct{})
+
+	stopChan, done := r.stopChan, r.done
+	recovery.Go("replay.recorder", func() { r.run(ctx, stopChan, done) })
+}
+
+// Stop writes the messages still queued and stops
+func (r *Recorder) Stop() {
+	r.mu.Lock()
+	if r.stopChan == nil {
+		r.mu.Unlock()
+		return
+	}
+	close(r.stopChan)
+	r.stopChan = nil
+	done := r.done
+	r.mu.Unlock()
+
+	<-done
+}
+
+func (r *Recorder) run(ctx context.Context, stopChan <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(recorderFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]model.RawMessage, 0, recorderBatch)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-stopChan:
+			for {
+				select {
+				case msg := <-r.queue:
+					if batch = append(batch, msg); len(batch) == recorderBatch {
+						batch = r.flush(ctx, batch)
+					}
+				default:
+					r.flush(ctx, batch)
+					return
+				}
+			}
+		case msg := <-r.queue:
+			if batch = append(batch, msg); len(batch) == recorderBatch {
+				batch = r.flush(ctx, batch)
+			}
+		case <-ticker.C:
+			batch = r.flush(ctx, batch)
+		}
+	}
+}
+
+// flush writes a batch and returns it emptied
+func (r *Recorder) flush(ctx context.Context, batch []model.RawMessage) []model.RawMessage {
+	if len(batch) == 0 {
+		return batch
+	}
+
+	if err := r.messages.SaveRawMessages(ctx, batch); err != nil {
+		log.Printf("Error recording %d websocket messages: %v", len(batch), err)
+		r.dropped.Add(int64(len(batch)))
+	} else {
+		r.recorded.Add(int64(len(batch)))
+	}
+	return batch[:0]
+}
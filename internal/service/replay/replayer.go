@@ -0,0 +1,308 @@
+// Package replay streams historical market data into a price feed so the live
+// pipeline can be exercised against history
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/pricefeed"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/websocket"
+	"github.com/sungminna/upbit-trading-platform/pkg/recovery"
+)
+
+// source identifies replayed prices in the feed
+const source = "replay"
+
+// CandleSource provides historical candles; gateway.QuotationAPI satisfies it
+type CandleSource interface {
+	GetCandleRange(ctx context.Context, market string, interval model.CandleInterval, from, to time.Time) ([]model.Candle, error)
+}
+
+// Source is what a session replays
+type Source string
+
+const (
+	// SourceCandles replays candle closes
+	SourceCandles Source = "candles"
+	// SourceMessages replays the trade prices of recorded websocket messages
+	SourceMessages Source = "messages"
+)
+
+// Config describes a replay session
+type Config struct {
+	Source   Source               `json:"source,omitempty"` // Defaults to SourceCandles
+	Markets  []string             `json:"markets"`
+	Interval model.CandleInterval `json:"interval"` // Of candles`
+	From     time.Time            `json:"from"`
+	To       time.Time            `json:"to"`
+	// Speed is how many seconds of history play per second, e.g. 60 plays a
+	// minute of candles every second. Zero replays as fast as possible.
+	Speed float64 `json:"speed"`
+	// Live publishes into the feed live trading acts on, stamped with the
+	// time they are published, so guards and alerts react to the replayed
+	// moves. Requires WithLiveFeed.
+	Live bool `json:"live,omitempty"`
+}
+
+// Status reports the progress of the current or last replay session
+type Status struct {
+	Running   bool      `json:"running"`
+	Config    *Config   `json:"config,omitempty"`
+	Published int       `json:"published"`
+	Total     int       `json:"total"`
+	Position  time.Time `json:"position"` // Market time of the last published price
+	Error     string    `json:"error,omitempty"`
+}
+
+// Replayer replays candle closes into a price feed, one session at a time.
+// The feed should be dedicated to replay so history never reaches live trading.
+type Replayer struct {
+	candles  CandleSource
+	messages repository.RawMessageRepository // Optional; enables SourceMessages
+	feed     *pricefeed.Feed
+	live     *pricefeed.Feed // Optional; enables Config.Live
+	mu       sync.Mutex
+	status   Status
+	stopChan chan struct{}
+	done     chan struct{}
+}
+
+// NewReplayer creates a new replayer publishing into feed
+func NewReplayer(candles CandleSource, feed *pricefeed.Feed) *Replayer {
+	return &Replayer{
+		candles: candles,
+		feed:    feed,
+	}
+}
+
+// WithMessages enables replaying recorded websocket messages
+func (r *Replayer) WithMessages(messages repository.RawMessageRepository) *Replayer {
+	r.messages = messages
+	return r
+}
+
+// WithLiveFeed lets sessions publish into the feed live trading acts on, e.g.
+// to check end to end that a drawdown guard exits its position. Only enable
+// it where no real money trades.
+func (r *Replayer) WithLiveFeed(feed *pricefeed.Feed) *Replayer {
+	r.live = feed
+	return r
+}
+
+// Feed returns the feed replayed prices are published to
+func (r *Replayer) Feed() *pricefeed.Feed {
+	return r.feed
+}
+
+// Start loads the candles and starts replaying them in the background
+func (r *Replayer) Start(ctx context.Context, cfg Config) error {
+	if cfg.Source == "" {
+		cfg.Source = SourceCandles
+	}
+	if len(cfg.Markets) == 0 || !cfg.From.Before(cfg.To) || cfg.Speed < 0 {
+		return ErrInvalidConfig
+	}
+	switch cfg.Source {
+	case SourceCandles:
+		if cfg.Interval.Duration() == 0 {
+			return ErrInvalidConfig
+		}
+	case SourceMessages:
+		if r.messages == nil {
+			return ErrNoMessages
+		}
+	default:
+		return ErrInvalidConfig
+	}
+	if cfg.Live && r.live == nil {
+		return ErrLiveDisabled
+	}
+
+	r.mu.Lock()
+	if r.status.Running {
+		r.mu.Unlock()
+		return ErrAlreadyRunning
+	}
+	r.status = Status{Running: true, Config: &cfg}
+	r.mu.Unlock()
+
+	updates, err := r.load(ctx, cfg)
+	if err != nil {
+		r.finish(err)
+		return err
+	}
+
+	r.mu.Lock()
+	r.status.Total = len(updates)
+	r.stopChan = make(chan struct{})
+	r.done = make(chan struct{})
+	stopChan, done := r.stopChan, r.done
+	r.mu.Unlock()
+
+	go func() {
+		defer close(done)
+		if recovery.Do("replay.play", func() { r.play(updates, cfg, stopChan) }) {
+			r.finish(errors.New("replay panicked"))
+		}
+	}()
+
+	return nil
+}
+
+// Stop stops the running session and waits for it to finish
+func (r *Replayer) Stop() {
+	r.mu.Lock()
+	if !r.status.Running || r.stopChan == nil {
+		r.mu.Unlock()
+		return
+	}
+	close(r.stopChan)
+	r.stopChan = nil
+	done := r.done
+	r.mu.Unlock()
+
+	<-done
+}
+
+// Status returns the progress of the current or last session
+func (r *Replayer) Status() Status {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.status
+}
+
+// load builds the session's timeline of prices
+func (r *Replayer) load(ctx context.Context, cfg Config) ([]pricefeed.PriceUpdate, error) {
+	if cfg.Source == SourceMessages {
+		return r.loadMessages(ctx, cfg)
+	}
+	return r.loadCandles(ctx, cfg)
+}
+
+// loadCandles fetches every market's candles and merges them into one
+// timeline of close prices. Each price is stamped with its candle's close time.
+func (r *Replayer) loadCandles(ctx context.Context, cfg Config) ([]pricefeed.PriceUpdate, error) {
+	var updates []pricefeed.PriceUpdate
+	for _, market := range cfg.Markets {
+		candles, err := r.candles.GetCandleRange(ctx, market, cfg.Interval, cfg.From, cfg.To)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load candles for %s: %w", market, err)
+		}
+
+		for _, c := range candles {
+			updates = append(updates, pricefeed.PriceUpdate{
+				Market:    market,
+				Price:     c.ClosePrice,
+				Volume:    c.Volume,
+				Timestamp: c.Timestamp.Add(cfg.Interval.Duration()),
+				Source:    source,
+			})
+		}
+	}
+
+	sort.SliceStable(updates, func(i, j int) bool {
+		return updates[i].Timestamp.Before(updates[j].Timestamp)
+	})
+	return updates, nil
+}
+
+// loadMessages turns the recorded tickers and trades of the markets into a
+// timeline of trade prices, stamped with their exchange time
+func (r *Replayer) loadMessages(ctx context.Context, cfg Config) ([]pricefeed.PriceUpdate, error) {
+	messages, err := r.messages.ListRawMessages(ctx, cfg.Markets, cfg.From, cfg.To)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load recorded messages: %w", err)
+	}
+
+	var updates []pricefeed.PriceUpdate
+	for _, raw := range messages {
+		_, msg, err := websocket.Decode(raw.Payload)
+		if err != nil {
+			continue
+		}
+
+		switch m := msg.(type) {
+		case websocket.TickerMessage:
+			updates = append(updates, pricefeed.PriceUpdate{
+				Market:    m.Code,
+				Price:     m.TradePrice,
+				Volume:    m.TradeVolume,
+				Timestamp: time.UnixMilli(m.TradeTimestamp),
+				Source:    source,
+			})
+		case websocket.TradeMessage:
+			updates = append(updates, pricefeed.PriceUpdate{
+				Market:    m.Code,
+				Price:     m.TradePrice,
+				Volume:    m.TradeVolume,
+				Timestamp: time.UnixMilli(m.TradeTimestamp),
+				Source:    source,
+			})
+		}
+	}
+
+	sort.SliceStable(updates, func(i, j int) bool {
+		return updates[i].Timestamp.Before(updates[j].Timestamp)
+	})
+	return updates, nil
+}
+
+// play publishes updates, sleeping between them to keep the requested speed
+func (r *Replayer) play(updates []pricefeed.PriceUpdate, cfg Config, stopChan <-chan struct{}) {
+	defer r.finish(nil)
+
+	feed, speed := r.feed, cfg.Speed
+	if cfg.Live {
+		feed = r.live
+	}
+	for i, update := range updates {
+		if speed > 0 && i > 0 {
+			wait := time.Duration(float64(update.Timestamp.Sub(updates[i-1].Timestamp)) / speed)
+			if wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-stopChan:
+					timer.Stop()
+					return
+				case <-timer.C:
+				}
+			}
+		}
+
+		select {
+		case <-stopChan:
+			return
+		default:
+		}
+
+		position := update.Timestamp
+		if cfg.Live {
+			update.Timestamp = time.Now()
+		}
+		feed.Publish(update)
+
+		r.mu.Lock()
+		r.status.Published++
+		r.status.Position = position
+		r.mu.Unlock()
+	}
+}
+
+func (r *Replayer) finish(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.status.Running = false
+	if err != nil {
+		r.status.Error = err.Error()
+		log.Printf("Replay failed: %v", err)
+	}
+}
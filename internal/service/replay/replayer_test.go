@@ -0,0 +1,189 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/service/pricefeed"
+)
+
+var testStart = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// stubCandles returns three 1m candles per market, newest first
+type stubCandles struct{}
+
+func (stubCandles) GetCandleRange(ctx context.Context, market string, interval model.CandleInterval, from, to time.Time) ([]model.Candle, error) {
+	base := 100.0
+	if market == "KRW-ETH" {
+		base = 10
+	}
+	var candles []model.Candle
+	for i := 2; i >= 0; i-- {
+		candles = append(candles, model.Candle{
+			Market:     market,
+			Timestamp:  testStart.Add(time.Duration(i) * time.Minute),
+			ClosePrice: base + float64(i),
+		})
+	}
+	return candles, nil
+}
+
+func waitStopped(t *testing.T, r *Replayer) Status {
+	require.Eventually(t, func() bool { return !r.Status().Running }, time.Second, time.Millisecond)
+	return r.Status()
+}
+
+func TestReplayer_PublishesInMarketTimeOrder(t *testing.T) {
+	feed := pricefeed.NewFeed()
+	var mu sync.Mutex
+	var received []pricefeed.PriceUpdate
+	feed.Subscribe(pricefeed.AllMarkets, func(u pricefeed.PriceUpdate) {
+		mu.Lock()
+		received = append(received, u)
+		mu.Unlock()
+	})
+
+	replayer := NewReplayer(stubCandles{}, feed)
+	err := replayer.Start(context.Background(), Config{
+		Markets:  []string{"KRW-BTC", "KRW-ETH"},
+		Interval: model.CandleInterval1m,
+		From:     testStart,
+		To:       testStart.Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	status := waitStopped(t, replayer)
+	assert.Equal(t, 6, status.Published)
+	assert.Equal(t, 6, status.Total)
+	assert.Equal(t, testStart.Add(3*time.Minute), status.Position)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, received, 6)
+	for i := 1; i < len(received); i++ {
+		assert.False(t, received[i].Timestamp.Before(received[i-1].Timestamp))
+	}
+	assert.Equal(t, "replay", received[0].Source)
+	// Prices are stamped with the candle close time
+	assert.Equal(t, testStart.Add(time.Minute), received[0].Timestamp)
+
+	latest, ok := feed.Latest("KRW-BTC")
+	require.True(t, ok)
+	assert.Equal(t, 102.0, latest.Price)
+}
+
+func TestReplayer_StopAndSingleSession(t *testing.T) {
+	replayer := NewReplayer(stubCandles{}, pricefeed.NewFeed())
+	cfg := Config{
+		Markets:  []string{"KRW-BTC"},
+		Interval: model.CandleInterval1m,
+		From:     testStart,
+		To:       testStart.Add(time.Hour),
+		Speed:    1, // Real time: a minute between candles
+	}
+
+	require.NoError(t, replayer.Start(context.Background(), cfg))
+	assert.ErrorIs(t, replayer.Start(context.Background(), cfg), ErrAlreadyRunning)
+
+	// The first price publishes right away; the next is a minute later
+	require.Eventually(t, func() bool { return replayer.Status().Published == 1 }, time.Second, time.Millisecond)
+	replayer.Stop()
+	status := replayer.Status()
+	assert.False(t, status.Running)
+	assert.Equal(t, 1, status.Published)
+
+	cfg.Markets = nil
+	assert.ErrorIs(t, replayer.Start(context.Background(), cfg), ErrInvalidConfig)
+}
+
+func TestReplayer_LiveFeed(t *testing.T) {
+	replay, live := pricefeed.NewFeed(), pricefeed.NewFeed()
+	var mu sync.Mutex
+	var received []pricefeed.PriceUpdate
+	live.Subscribe(pricefeed.AllMarkets, func(u pricefeed.PriceUpdate) {
+		mu.Lock()
+		received = append(received, u)
+		mu.Unlock()
+	})
+	cfg := Config{
+		Markets:  []string{"KRW-BTC"},
+		Interval: model.CandleInterval1m,
+		From:     testStart,
+		To:       testStart.Add(time.Hour),
+		Live:     true,
+	}
+
+	replayer := NewReplayer(stubCandles{}, replay)
+	assert.ErrorIs(t, replayer.Start(context.Background(), cfg), ErrLiveDisabled)
+
+	replayer.WithLiveFeed(live)
+	started := time.Now()
+	require.NoError(t, replayer.Start(context.Background(), cfg))
+	status := waitStopped(t, replayer)
+	assert.Equal(t, 3, status.Published)
+	assert.Equal(t, testStart.Add(3*time.Minute), status.Position, "progress is still in market time")
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, received, 3)
+	for _, u := range received {
+		assert.False(t, u.Timestamp.Before(started), "live prices are stamped when published")
+	}
+	_, ok := replay.Latest("KRW-BTC")
+	assert.False(t, ok, "nothing reaches the replay feed")
+}
+
+// stubMessages stores raw messages in memory
+type stubMessages struct {
+	mu       sync.Mutex
+	messages []model.RawMessage
+}
+
+func (s *stubMessages) SaveRawMessages(ctx context.Context, messages []model.RawMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = append(s.messages, messages...)
+	return nil
+}
+
+func (s *stubMessages) ListRawMessages(ctx context.Context, markets []string, from, to time.Time) ([]model.RawMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]model.RawMessage(nil), s.messages...), nil
+}
+
+func TestReplayer_RecordedMessages(t *testing.T) {
+	messages := &stubMessages{}
+	recorder := NewRecorder(messages, DefaultRecorderBuffer)
+	recorder.Start(context.Background())
+
+	ms := testStart.UnixMilli()
+	recorder.Record(model.RawMessage{Type: "trade", Market: "KRW-BTC", Payload: []byte(
+		fmt.Sprintf(`{"type":"trade","code":"KRW-BTC","trade_price":101,"trade_volume":0.5,"trade_timestamp":%d}`, ms+2000))})
+	recorder.Record(model.RawMessage{Type: "ticker", Market: "KRW-BTC", Payload: []byte(
+		fmt.Sprintf(`{"ty":"ticker","cd":"KRW-BTC","tp":100,"ttms":%d}`, ms+1000))})
+	recorder.Record(model.RawMessage{Type: "orderbook", Market: "KRW-BTC", Payload: []byte(
+		`{"type":"orderbook","code":"KRW-BTC","total_ask_size":1}`)})
+	recorder.Stop()
+	assert.Equal(t, RecorderStats{Recorded: 3}, recorder.Stats())
+
+	replayer := NewReplayer(stubCandles{}, pricefeed.NewFeed())
+	cfg := Config{Source: SourceMessages, Markets: []string{"KRW-BTC"}, From: testStart, To: testStart.Add(time.Hour)}
+	assert.ErrorIs(t, replayer.Start(context.Background(), cfg), ErrNoMessages)
+
+	replayer.WithMessages(messages)
+	require.NoError(t, replayer.Start(context.Background(), cfg))
+	status := waitStopped(t, replayer)
+	assert.Equal(t, 2, status.Published, "orderbooks carry no trade price")
+	assert.Equal(t, time.UnixMilli(ms+2000), status.Position)
+
+	latest, ok := replayer.Feed().Latest("KRW-BTC")
+	require.True(t, ok)
+	assert.Equal(t, 101.0, latest.Price)
+}
@@ -0,0 +1,17 @@
+package report
+
+var (
+	ErrInvalidGroupBy = &ReportError{message: "group_by must be market, day, source, instance or tag"}
+	ErrInvalidRange   = &ReportError{message: "from must be before to"}
+	ErrInvalidOutcome = &ReportError{message: "outcome must be win or loss"}
+	ErrInvalidMethod  = &ReportError{message: "method must be average, fifo or lifo"}
+)
+
+// ReportError represents an invalid report request
+type ReportError struct {
+	message string
+}
+
+func (e *ReportError) Error() string {
+	return e.message
+}
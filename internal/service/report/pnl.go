@@ -0,0 +1,299 @@
+// Package report builds performance reports from order executions
+package report
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/valuation"
+)
+
+// Report groupings
+const (
+	GroupByMarket   = "market"
+	GroupByDay      = "day"      // UTC calendar days
+	GroupBySource   = "source"   // The user or the automation that placed the orders
+	GroupByInstance = "instance" // Source and, for automations with several instances, its ID
+	GroupByTag      = "tag"      // Tags of the order and its position; a fill counts towards each
+)
+
+// untagged is the tag group of fills without tags
+const untagged = "untagged"
+
+// PnLReport is the PnL realized in [From, To), net of the fees paid in it.
+// Values are in Currency, each fill's at the rate of its time.
+type PnLReport struct {
+	From     time.Time              `json:"from"`
+	To       time.Time              `json:"to"`
+	GroupBy  string                 `json:"group_by"`
+	Method   model.AccountingMethod `json:"method"`
+	Currency string                 `json:"currency"`
+	PnLGroup                        // Totals
+	Groups   []PnLGroup             `json:"groups"` // By day oldest first, otherwise most profitable first
+}
+
+// PnLGroup is the realized PnL of one market, day, source, instance or tag
+type PnLGroup struct {
+	Key      string  `json:"key,omitempty"` // Market, day as 2006-01-02, source, source:id or tag
+	GrossPnL float64 `json:"gross_pnl"`     // Realized against the average entry price, before fees
+	Fees     float64 `json:"fees"`          // Of every fill, buys included
+	NetPnL   float64 `json:"net_pnl"`
+	Sells    int     `json:"sells"`  // Fills that closed part of a position
+	Trades   int     `json:"trades"` // All fills
+	Volume   float64 `json:"volume"` // Value of all fills
+}
+
+// Valuer quotes display currencies; valuation.Service satisfies it
+type Valuer interface {
+	Quote(ctx context.Context, currency string, from, to time.Time) (*valuation.Quote, error)
+}
+
+// GuardSource lists users' drawdown guards; guard.Service satisfies it
+type GuardSource interface {
+	List(ctx context.Context, userID uuid.UUID) ([]*model.DrawdownGuard, error)
+}
+
+// Service builds reports
+type Service struct {
+	orders     repository.OrderRepository
+	executions repository.OrderExecutionRepository
+	guards     GuardSource                   // Optional; attributes trades closed by drawdown guards
+	positions  repository.PositionRepository // Optional; adds position tags to tag groups
+	valuations Valuer                        // Optional; values in display currencies other than KRW
+}
+
+// NewService creates a new report service
+func NewService(orders repository.OrderRepository, executions repository.OrderExecutionRepository) *Service {
+	return &Service{
+		orders:     orders,
+		executions: executions,
+	}
+}
+
+// WithGuards attributes trades closed by drawdown guards to them
+func (s *Service) WithGuards(guards GuardSource) *Service {
+	s.guards = guards
+	return s
+}
+
+// WithPositions makes tag groups include the tags of the positions orders
+// belong to, not just those of the orders
+func (s *Service) WithPositions(positions repository.PositionRepository) *Service {
+	s.positions = positions
+	return s
+}
+
+// WithValuation lets reports be valued in display currencies other than KRW
+func (s *Service) WithValuation(valuations Valuer) *Service {
+	s.valuations = valuations
+	return s
+}
+
+// PnL reports the user's realized PnL in [from, to) in a display currency. Each position's fills
+// are replayed from its first buy into lots, so sells are measured against
+// the cost of the lots the accounting method sells, whatever method the
+// position itself uses. Sells not attached to a position count towards fees
+// and volume only. Grouped by source, the PnL of a sale is credited to
+// whatever placed the sell order. Grouped by tag, groups overlap: a fill
+// counts towards every tag of its order and position. Outside KRW, each fill
+// and the PnL it realized is converted at the rate of its time.
+func (s *Service) PnL(ctx context.Context, userID uuid.UUID, from, to time.Time, groupBy string, method model.AccountingMethod, currency string) (*PnLReport, error) {
+	switch groupBy {
+	case GroupByMarket, GroupByDay, GroupBySource, GroupByInstance, GroupByTag:
+	default:
+		return nil, ErrInvalidGroupBy
+	}
+	if !method.Valid() {
+		return nil, ErrInvalidMethod
+	}
+	if !from.Before(to) {
+		return nil, ErrInvalidRange
+	}
+
+	quote, err := s.quote(ctx, currency, from, to)
+	if err != nil {
+		return nil, err
+	}
+	fills, err := s.fills(ctx, userID, to)
+	if err != nil {
+		return nil, err
+	}
+	var positionTags map[uuid.UUID][]string
+	if groupBy == GroupByTag {
+		if positionTags, err = s.positionTags(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &PnLReport{From: from, To: to, GroupBy: groupBy, Method: method, Currency: quote.Code(), Groups: []PnLGroup{}}
+	groups := make(map[string]*PnLGroup)
+	group := func(key string) *PnLGroup {
+		g, ok := groups[key]
+		if !ok {
+			g = &PnLGroup{Key: key}
+			groups[key] = g
+		}
+		return g
+	}
+	groupsFor := func(f fill) []*PnLGroup {
+		key := f.order.Market
+		switch groupBy {
+		case GroupByDay:
+			key = f.execution.CreatedAt.UTC().Format(time.DateOnly)
+		case GroupBySource:
+			key = string(source(f.order))
+		case GroupByInstance:
+			key = string(source(f.order))
+			if f.order.SourceID != nil {
+				key += ":" + f.order.SourceID.String()
+			}
+		case GroupByTag:
+			tags := f.order.Tags
+			if f.order.PositionID != nil {
+				tags = append(slices.Clone(tags), positionTags[*f.order.PositionID]...)
+			}
+			if len(tags) == 0 {
+				return []*PnLGroup{group(untagged)}
+			}
+			slices.Sort(tags)
+			tagged := make([]*PnLGroup, 0, len(tags))
+			for _, tag := range slices.Compact(tags) {
+				tagged = append(tagged, group(tag))
+			}
+			return tagged
+		}
+		return []*PnLGroup{group(key)}
+	}
+
+	// The lots of each position as fills are replayed
+	holdings := make(map[uuid.UUID]model.Lots)
+
+	for _, f := range fills {
+		e := f.execution
+		var pnl float64
+		realized := false
+		if id := f.order.PositionID; id != nil {
+			lots := holdings[*id]
+			if f.order.Side == model.OrderSideBid {
+				holdings[*id] = lots.Add(e.Quantity, e.Price, e.CreatedAt)
+			} else if held := lots.Quantity(); held > 0 {
+				qty := min(e.Quantity, held)
+				remaining, cost := lots.Reduce(qty, method)
+				holdings[*id] = remaining
+				pnl = e.Price*qty - cost
+				realized = true
+			}
+		}
+
+		if e.CreatedAt.Before(from) {
+			continue
+		}
+		volume, fee := quote.Convert(e.Total, e.CreatedAt), quote.Convert(e.Fee, e.CreatedAt)
+		pnl = quote.Convert(pnl, e.CreatedAt)
+		report.add(volume, fee, pnl, realized)
+		for _, g := range groupsFor(f) {
+			g.add(volume, fee, pnl, realized)
+		}
+	}
+
+	for _, g := range groups {
+		report.Groups = append(report.Groups, *g)
+	}
+	sort.Slice(report.Groups, func(i, j int) bool {
+		if groupBy != GroupByDay && report.Groups[i].NetPnL != report.Groups[j].NetPnL {
+			return report.Groups[i].NetPnL > report.Groups[j].NetPnL
+		}
+		return report.Groups[i].Key < report.Groups[j].Key
+	})
+
+	return report, nil
+}
+
+// add records a fill's value and fee and the PnL it realized
+func (g *PnLGroup) add(volume, fee, pnl float64, realized bool) {
+	g.Trades++
+	g.Volume += volume
+	g.Fees += fee
+	if realized {
+		g.Sells++
+		g.GrossPnL += pnl
+	}
+	g.NetPnL = g.GrossPnL - g.Fees
+}
+
+// quote returns the quote of a display currency over [from, to); nil for KRW
+func (s *Service) quote(ctx context.Context, currency string, from, to time.Time) (*valuation.Quote, error) {
+	if valuation.IsBase(currency) {
+		return nil, nil
+	}
+	if s.valuations == nil {
+		return nil, valuation.ErrUnsupportedCurrency
+	}
+	return s.valuations.Quote(ctx, currency, from, to)
+}
+
+// positionTags returns the tags of the user's positions, by position
+func (s *Service) positionTags(ctx context.Context, userID uuid.UUID) (map[uuid.UUID][]string, error) {
+	tags := make(map[uuid.UUID][]string)
+	if s.positions == nil {
+		return tags, nil
+	}
+
+	positions, err := s.positions.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list positions: %w", err)
+	}
+	for _, p := range positions {
+		tags[p.ID] = p.Tags
+	}
+	return tags, nil
+}
+
+// source returns what placed the order; orders imported from the exchange
+// have none and are the user's
+func source(order *model.Order) model.OrderSource {
+	if order.Source == "" {
+		return model.OrderSourceUser
+	}
+	return order.Source
+}
+
+// fill is one execution with the order it belongs to
+type fill struct {
+	order     *model.Order
+	execution *model.OrderExecution
+}
+
+// fills returns the executions of the user's orders before to, oldest first
+func (s *Service) fills(ctx context.Context, userID uuid.UUID, to time.Time) ([]fill, error) {
+	orders, err := s.orders.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders: %w", err)
+	}
+
+	var fills []fill
+	for _, order := range orders {
+		if order.ExecutedQuantity == 0 || !order.CreatedAt.Before(to) {
+			continue
+		}
+		executions, err := s.executions.ListByOrder(ctx, order.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list executions: %w", err)
+		}
+		for _, e := range executions {
+			if e.CreatedAt.Before(to) {
+				fills = append(fills, fill{order: order, execution: e})
+			}
+		}
+	}
+	sort.SliceStable(fills, func(i, j int) bool {
+		return fills[i].execution.CreatedAt.Before(fills[j].execution.CreatedAt)
+	})
+	return fills, nil
+}
@@ -0,0 +1,225 @@
+package report
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+	"github.com/sungminna/upbit-trading-platform/internal/service/valuation"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/quotation"
+)
+
+// flatRates prices every reference market at a fixed KRW rate
+type flatRates float64
+
+func (r flatRates) GetTicker(ctx context.Context, markets []string) ([]quotation.Ticker, error) {
+	tickers := make([]quotation.Ticker, len(markets))
+	for i, market := range markets {
+		tickers[i] = quotation.Ticker{Market: market, TradePrice: float64(r)}
+	}
+	return tickers, nil
+}
+
+func (r flatRates) GetCandleRange(ctx context.Context, market string, interval model.CandleInterval, from, to time.Time) ([]model.Candle, error) {
+	return nil, nil
+}
+
+var day = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func fillOrder(t *testing.T, store *memory.Store, position *model.Position, side model.OrderSide, price, qty, fee float64, at time.Time) *model.Order {
+	t.Helper()
+	ctx := context.Background()
+	order := model.NewOrder(position.UserID, position.Market, side, model.OrderTypeLimit, qty, &price, time.Now())
+	order.PositionID = &position.ID
+	order.Status = model.OrderStatusFilled
+	order.ExecutedQuantity = qty
+	order.CreatedAt = at
+	require.NoError(t, store.Orders().Create(ctx, order))
+
+	execution := model.NewOrderExecution(order.ID, price, qty, fee, time.Now())
+	execution.CreatedAt = at
+	require.NoError(t, store.Executions().Create(ctx, execution))
+	return order
+}
+
+func TestService_PnL(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	userID := uuid.New()
+
+	btc := model.NewPosition(userID, "KRW-BTC", model.PositionSideLong, 100, 1, time.Now())
+	eth := model.NewPosition(userID, "KRW-ETH", model.PositionSideLong, 10, 2, time.Now())
+	// The first buy is before the range but still sets the entry price
+	fillOrder(t, store, btc, model.OrderSideBid, 100, 1, 0.05, day)
+	fillOrder(t, store, btc, model.OrderSideBid, 200, 1, 0.1, day.AddDate(0, 0, 1))
+	fillOrder(t, store, btc, model.OrderSideAsk, 180, 1, 0.09, day.AddDate(0, 0, 2))
+	fillOrder(t, store, eth, model.OrderSideBid, 10, 2, 0.01, day.AddDate(0, 0, 2))
+	fillOrder(t, store, eth, model.OrderSideAsk, 8, 2, 0.008, day.AddDate(0, 0, 3))
+	// After the range
+	fillOrder(t, store, btc, model.OrderSideAsk, 300, 1, 0.15, day.AddDate(0, 0, 5))
+
+	service := NewService(store.Orders(), store.Executions())
+	from, to := day.AddDate(0, 0, 1), day.AddDate(0, 0, 4)
+
+	byMarket, err := service.PnL(ctx, userID, from, to, GroupByMarket, model.AccountingAverage, "")
+	require.NoError(t, err)
+	assert.InDelta(t, 26, byMarket.GrossPnL, 1e-9) // 30 on BTC, -4 on ETH
+	assert.InDelta(t, 0.208, byMarket.Fees, 1e-9)
+	assert.InDelta(t, 25.792, byMarket.NetPnL, 1e-9)
+	assert.Equal(t, 4, byMarket.Trades)
+	assert.Equal(t, 2, byMarket.Sells)
+	assert.InDelta(t, 416, byMarket.Volume, 1e-9)
+	require.Len(t, byMarket.Groups, 2)
+	assert.Equal(t, "KRW-BTC", byMarket.Groups[0].Key)
+	assert.InDelta(t, 30, byMarket.Groups[0].GrossPnL, 1e-9)
+	assert.InDelta(t, 0.19, byMarket.Groups[0].Fees, 1e-9)
+	assert.Equal(t, "KRW-ETH", byMarket.Groups[1].Key)
+	assert.InDelta(t, -4, byMarket.Groups[1].GrossPnL, 1e-9)
+
+	service.WithValuation(valuation.NewService(flatRates(2)))
+	inUSD, err := service.PnL(ctx, userID, from, to, GroupByMarket, model.AccountingAverage, "usd")
+	require.NoError(t, err)
+	assert.Equal(t, "USD", inUSD.Currency)
+	assert.Equal(t, "KRW", byMarket.Currency)
+	assert.InDelta(t, 12.896, inUSD.NetPnL, 1e-9)
+	assert.InDelta(t, 208, inUSD.Volume, 1e-9)
+
+	byDay, err := service.PnL(ctx, userID, from, to, GroupByDay, model.AccountingAverage, "")
+	require.NoError(t, err)
+	require.Len(t, byDay.Groups, 3)
+	assert.Equal(t, []string{"2025-03-02", "2025-03-03", "2025-03-04"},
+		[]string{byDay.Groups[0].Key, byDay.Groups[1].Key, byDay.Groups[2].Key})
+	assert.InDelta(t, -0.1, byDay.Groups[0].NetPnL, 1e-9)
+	assert.InDelta(t, 30, byDay.Groups[1].GrossPnL, 1e-9)
+	assert.InDelta(t, byMarket.NetPnL, byDay.NetPnL, 1e-9)
+}
+
+func TestService_PnLBySource(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	userID := uuid.New()
+
+	btc := model.NewPosition(userID, "KRW-BTC", model.PositionSideLong, 100, 1, time.Now())
+	eth := model.NewPosition(userID, "KRW-ETH", model.PositionSideLong, 10, 1, time.Now())
+	fillOrder(t, store, btc, model.OrderSideBid, 100, 1, 0, day)
+	fillOrder(t, store, eth, model.OrderSideBid, 10, 1, 0, day)
+	fillOrder(t, store, eth, model.OrderSideAsk, 15, 1, 0, day.Add(time.Hour))
+
+	// Sold by the position's drawdown guard
+	price := 90.0
+	exit := model.NewOrder(userID, "KRW-BTC", model.OrderSideAsk, model.OrderTypeMarket, 1, &price, time.Now())
+	exit.PositionID = &btc.ID
+	exit.Source = model.OrderSourceDrawdownGuard
+	exit.SourceID = &btc.ID
+	exit.Status = model.OrderStatusFilled
+	exit.ExecutedQuantity = 1
+	exit.CreatedAt = day.Add(2 * time.Hour)
+	require.NoError(t, store.Orders().Create(ctx, exit))
+	execution := model.NewOrderExecution(exit.ID, price, 1, 0, time.Now())
+	execution.CreatedAt = exit.CreatedAt
+	require.NoError(t, store.Executions().Create(ctx, execution))
+
+	service := NewService(store.Orders(), store.Executions())
+	from, to := day, day.AddDate(0, 0, 1)
+
+	bySource, err := service.PnL(ctx, userID, from, to, GroupBySource, model.AccountingAverage, "")
+	require.NoError(t, err)
+	require.Len(t, bySource.Groups, 2)
+	assert.Equal(t, "user", bySource.Groups[0].Key)
+	assert.InDelta(t, 5, bySource.Groups[0].GrossPnL, 1e-9)
+	assert.Equal(t, 3, bySource.Groups[0].Trades)
+	assert.Equal(t, "drawdown_guard", bySource.Groups[1].Key)
+	assert.InDelta(t, -10, bySource.Groups[1].GrossPnL, 1e-9)
+	assert.Equal(t, 1, bySource.Groups[1].Sells)
+
+	byInstance, err := service.PnL(ctx, userID, from, to, GroupByInstance, model.AccountingAverage, "")
+	require.NoError(t, err)
+	require.Len(t, byInstance.Groups, 2)
+	assert.Equal(t, "drawdown_guard:"+btc.ID.String(), byInstance.Groups[1].Key)
+}
+
+func TestService_PnLByTag(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	userID := uuid.New()
+
+	btc := model.NewPosition(userID, "KRW-BTC", model.PositionSideLong, 100, 1, time.Now())
+	require.NoError(t, store.Positions().Create(ctx, btc))
+	require.NoError(t, store.Positions().Annotate(ctx, btc.ID, []string{"swing"}, ""))
+	eth := model.NewPosition(userID, "KRW-ETH", model.PositionSideLong, 10, 1, time.Now())
+	fillOrder(t, store, btc, model.OrderSideBid, 100, 1, 0, day)
+	sell := fillOrder(t, store, btc, model.OrderSideAsk, 120, 1, 0, day.Add(time.Hour))
+	require.NoError(t, store.Orders().Annotate(ctx, sell.ID, []string{"bot-x", "swing"}, ""))
+	fillOrder(t, store, eth, model.OrderSideBid, 10, 1, 0, day)
+	fillOrder(t, store, eth, model.OrderSideAsk, 5, 1, 0, day.Add(time.Hour))
+
+	service := NewService(store.Orders(), store.Executions()).WithPositions(store.Positions())
+	byTag, err := service.PnL(ctx, userID, day, day.AddDate(0, 0, 1), GroupByTag, model.AccountingAverage, "")
+	require.NoError(t, err)
+
+	assert.InDelta(t, 15, byTag.GrossPnL, 1e-9)
+	require.Len(t, byTag.Groups, 3)
+	// Both made 20; the buy was tagged through its position only
+	assert.Equal(t, "bot-x", byTag.Groups[0].Key)
+	assert.InDelta(t, 20, byTag.Groups[0].GrossPnL, 1e-9)
+	assert.Equal(t, 1, byTag.Groups[0].Trades)
+	assert.Equal(t, "swing", byTag.Groups[1].Key)
+	assert.InDelta(t, 20, byTag.Groups[1].GrossPnL, 1e-9)
+	assert.Equal(t, 2, byTag.Groups[1].Trades)
+	assert.Equal(t, "untagged", byTag.Groups[2].Key)
+	assert.InDelta(t, -5, byTag.Groups[2].GrossPnL, 1e-9)
+}
+
+func TestService_PnLValidation(t *testing.T) {
+	store := memory.NewStore()
+	service := NewService(store.Orders(), store.Executions())
+
+	_, err := service.PnL(context.Background(), uuid.New(), day, day.Add(time.Hour), "week", model.AccountingAverage, "")
+	assert.ErrorIs(t, err, ErrInvalidGroupBy)
+
+	_, err = service.PnL(context.Background(), uuid.New(), day, day, GroupByDay, model.AccountingAverage, "")
+	assert.ErrorIs(t, err, ErrInvalidRange)
+
+	_, err = service.PnL(context.Background(), uuid.New(), day, day.Add(time.Hour), GroupByDay, model.AccountingAverage, "USD")
+	assert.ErrorIs(t, err, valuation.ErrUnsupportedCurrency, "without a valuation service")
+}
+
+func TestService_PnLAccountingMethods(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	userID := uuid.New()
+
+	btc := model.NewPosition(userID, "KRW-BTC", model.PositionSideLong, 100, 1, time.Now())
+	fillOrder(t, store, btc, model.OrderSideBid, 100, 1, 0, day)
+	fillOrder(t, store, btc, model.OrderSideBid, 200, 1, 0, day.Add(time.Hour))
+	fillOrder(t, store, btc, model.OrderSideAsk, 180, 1, 0, day.Add(2*time.Hour))
+	fillOrder(t, store, btc, model.OrderSideAsk, 180, 1, 0, day.Add(3*time.Hour))
+
+	service := NewService(store.Orders(), store.Executions())
+	from, to := day, day.Add(150*time.Minute) // Only the first sell
+
+	for method, want := range map[model.AccountingMethod]float64{
+		model.AccountingAverage: 30,
+		model.AccountingFIFO:    80,
+		model.AccountingLIFO:    -20,
+	} {
+		report, err := service.PnL(ctx, userID, from, to, GroupByMarket, method, "")
+		require.NoError(t, err)
+		assert.InDelta(t, want, report.GrossPnL, 1e-9, method)
+	}
+
+	// Every method realizes the same total once the position is flat
+	for _, method := range []model.AccountingMethod{model.AccountingAverage, model.AccountingFIFO, model.AccountingLIFO} {
+		report, err := service.PnL(ctx, userID, from, day.Add(4*time.Hour), GroupByMarket, method, "")
+		require.NoError(t, err)
+		assert.InDelta(t, 60, report.GrossPnL, 1e-9, method)
+	}
+
+	_, err := service.PnL(ctx, userID, from, to, GroupByMarket, "hifo", "")
+	assert.ErrorIs(t, err, ErrInvalidMethod)
+}
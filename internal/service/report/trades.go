@@ -0,0 +1,198 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// quantityTolerance absorbs float rounding when deciding a position is flat
+const quantityTolerance = 1e-8
+
+// What closed a round trip
+const (
+	ClosedByUser          = "user"
+	ClosedByDrawdownGuard = "drawdown_guard"
+)
+
+// Trade outcomes to filter by
+const (
+	OutcomeWin  = "win"  // Positive net PnL
+	OutcomeLoss = "loss" // Zero or negative net PnL
+)
+
+// RoundTrip is a position opened from flat and closed back to flat
+type RoundTrip struct {
+	PositionID  uuid.UUID     `json:"position_id"`
+	Market      string        `json:"market"`
+	Quantity    float64       `json:"quantity"`    // Bought over the trip
+	EntryPrice  float64       `json:"entry_price"` // Volume-weighted
+	ExitPrice   float64       `json:"exit_price"`  // Volume-weighted
+	EntryTime   time.Time     `json:"entry_time"`  // First buy
+	ExitTime    time.Time     `json:"exit_time"`   // Last sell
+	HoldingTime time.Duration `json:"holding_time"`
+	GrossPnL    float64       `json:"gross_pnl"`
+	Fees        float64       `json:"fees"` // Of entries and exits
+	NetPnL      float64       `json:"net_pnl"`
+	Return      float64       `json:"return"` // Net PnL as a percent of the cost of entries
+	ExitOrderID uuid.UUID     `json:"exit_order_id"`
+	ClosedBy    string        `json:"closed_by"`
+}
+
+// TradeFilter narrows the trade journal. Zero values match everything.
+type TradeFilter struct {
+	Market   string
+	From     time.Time // Exit time, inclusive
+	To       time.Time // Exit time, exclusive
+	Outcome  string
+	ClosedBy string
+	Limit    int
+}
+
+// Trades pairs the entry and exit fills of the user's positions into closed
+// round trips, newest exit first. Open trips are left out until they close.
+func (s *Service) Trades(ctx context.Context, userID uuid.UUID, filter TradeFilter) ([]RoundTrip, error) {
+	if filter.Outcome != "" && filter.Outcome != OutcomeWin && filter.Outcome != OutcomeLoss {
+		return nil, ErrInvalidOutcome
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, ErrInvalidRange
+	}
+
+	to := filter.To
+	if to.IsZero() {
+		to = time.Now()
+	}
+	fills, err := s.fills(ctx, userID, to)
+	if err != nil {
+		return nil, err
+	}
+	guardExits, err := s.guardExits(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The trip each position is in; a position can be re-entered after it closes
+	open := make(map[uuid.UUID]*trip)
+	var trips []RoundTrip
+	for _, f := range fills {
+		if f.order.PositionID == nil {
+			continue
+		}
+		t, ok := open[*f.order.PositionID]
+		if !ok {
+			if f.order.Side != model.OrderSideBid {
+				continue // An exit of a trip that started before the window
+			}
+			t = &trip{RoundTrip: RoundTrip{PositionID: *f.order.PositionID, Market: f.order.Market, EntryTime: f.execution.CreatedAt}}
+			open[*f.order.PositionID] = t
+		}
+
+		if t.add(f) {
+			delete(open, t.PositionID)
+			trip := t.close(guardExits)
+			if filter.matches(trip) {
+				trips = append(trips, trip)
+			}
+		}
+	}
+
+	sort.SliceStable(trips, func(i, j int) bool {
+		return trips[i].ExitTime.After(trips[j].ExitTime)
+	})
+	if filter.Limit > 0 && len(trips) > filter.Limit {
+		trips = trips[:filter.Limit]
+	}
+	return trips, nil
+}
+
+// guardExits returns the IDs of the exit orders placed by the user's drawdown guards
+func (s *Service) guardExits(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]bool, error) {
+	exits := make(map[uuid.UUID]bool)
+	if s.guards == nil {
+		return exits, nil
+	}
+
+	guards, err := s.guards.List(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list drawdown guards: %w", err)
+	}
+	for _, g := range guards {
+		if g.ExitOrderID != nil {
+			exits[*g.ExitOrderID] = true
+		}
+	}
+	return exits, nil
+}
+
+// trip accumulates the fills of a round trip
+type trip struct {
+	RoundTrip
+	held, entryCost float64 // Open quantity and its cost at the average entry price
+	entryValue      float64 // Of all buys
+	sold, exitValue float64
+}
+
+// add records a fill and reports whether it closed the trip
+func (t *trip) add(f fill) bool {
+	e := f.execution
+	t.Fees += e.Fee
+	if f.order.Side == model.OrderSideBid {
+		t.Quantity += e.Quantity
+		t.entryValue += e.Total
+		t.held += e.Quantity
+		t.entryCost += e.Total
+		return false
+	}
+
+	if t.held <= 0 {
+		return false
+	}
+	avg := t.entryCost / t.held
+	qty := min(e.Quantity, t.held)
+	t.GrossPnL += (e.Price - avg) * qty
+	t.held -= qty
+	t.entryCost -= avg * qty
+	t.sold += qty
+	t.exitValue += e.Price * qty
+	t.ExitTime = e.CreatedAt
+	t.ExitOrderID = f.order.ID
+	return t.held <= quantityTolerance
+}
+
+// close completes the round trip's totals
+func (t *trip) close(guardExits map[uuid.UUID]bool) RoundTrip {
+	rt := t.RoundTrip
+	rt.EntryPrice = t.entryValue / t.Quantity
+	rt.ExitPrice = t.exitValue / t.sold
+	rt.HoldingTime = rt.ExitTime.Sub(rt.EntryTime)
+	rt.NetPnL = rt.GrossPnL - rt.Fees
+	if t.entryValue > 0 {
+		rt.Return = rt.NetPnL / t.entryValue * 100
+	}
+	rt.ClosedBy = ClosedByUser
+	if guardExits[rt.ExitOrderID] {
+		rt.ClosedBy = ClosedByDrawdownGuard
+	}
+	return rt
+}
+
+func (f TradeFilter) matches(trip RoundTrip) bool {
+	switch {
+	case f.Market != "" && trip.Market != f.Market:
+		return false
+	case !f.From.IsZero() && trip.ExitTime.Before(f.From):
+		return false
+	case f.Outcome == OutcomeWin && trip.NetPnL <= 0:
+		return false
+	case f.Outcome == OutcomeLoss && trip.NetPnL > 0:
+		return false
+	case f.ClosedBy != "" && trip.ClosedBy != f.ClosedBy:
+		return false
+	}
+	return true
+}
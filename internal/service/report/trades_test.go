@@ -0,0 +1,73 @@
+package report
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+)
+
+type staticGuards []*model.DrawdownGuard
+
+func (g staticGuards) List(ctx context.Context, userID uuid.UUID) ([]*model.DrawdownGuard, error) {
+	return g, nil
+}
+
+func TestService_Trades(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	userID := uuid.New()
+
+	btc := model.NewPosition(userID, "KRW-BTC", model.PositionSideLong, 100, 1, time.Now())
+	eth := model.NewPosition(userID, "KRW-ETH", model.PositionSideLong, 10, 1, time.Now())
+	fillOrder(t, store, btc, model.OrderSideBid, 100, 1, 0.05, day)
+	fillOrder(t, store, btc, model.OrderSideBid, 200, 1, 0.1, day.AddDate(0, 0, 1))
+	fillOrder(t, store, btc, model.OrderSideAsk, 180, 2, 0.18, day.AddDate(0, 0, 2))
+	// Re-entered, then closed by a drawdown guard
+	fillOrder(t, store, btc, model.OrderSideBid, 180, 1, 0.09, day.AddDate(0, 0, 3))
+	exit := fillOrder(t, store, btc, model.OrderSideAsk, 170, 1, 0.085, day.AddDate(0, 0, 4))
+	// Still open
+	fillOrder(t, store, eth, model.OrderSideBid, 10, 1, 0.005, day.AddDate(0, 0, 4))
+
+	guard := model.NewDrawdownGuard(btc, 5, 30, time.Now())
+	guard.ExitOrderID = &exit.ID
+	service := NewService(store.Orders(), store.Executions()).WithGuards(staticGuards{guard})
+
+	trades, err := service.Trades(ctx, userID, TradeFilter{To: day.AddDate(0, 0, 10)})
+	require.NoError(t, err)
+	require.Len(t, trades, 2)
+
+	assert.Equal(t, ClosedByDrawdownGuard, trades[0].ClosedBy, "newest exit first")
+	assert.Equal(t, exit.ID, trades[0].ExitOrderID)
+	assert.InDelta(t, -10.175, trades[0].NetPnL, 1e-9)
+
+	first := trades[1]
+	assert.Equal(t, btc.ID, first.PositionID)
+	assert.Equal(t, ClosedByUser, first.ClosedBy)
+	assert.InDelta(t, 2, first.Quantity, 1e-9)
+	assert.InDelta(t, 150, first.EntryPrice, 1e-9)
+	assert.InDelta(t, 180, first.ExitPrice, 1e-9)
+	assert.Equal(t, 48*time.Hour, first.HoldingTime)
+	assert.InDelta(t, 60, first.GrossPnL, 1e-9)
+	assert.InDelta(t, 0.33, first.Fees, 1e-9)
+	assert.InDelta(t, 59.67, first.NetPnL, 1e-9)
+	assert.InDelta(t, 59.67/300*100, first.Return, 1e-9)
+
+	losses, err := service.Trades(ctx, userID, TradeFilter{Outcome: OutcomeLoss, To: day.AddDate(0, 0, 10)})
+	require.NoError(t, err)
+	require.Len(t, losses, 1)
+	assert.Equal(t, ClosedByDrawdownGuard, losses[0].ClosedBy)
+
+	windowed, err := service.Trades(ctx, userID, TradeFilter{From: day, To: day.AddDate(0, 0, 3)})
+	require.NoError(t, err)
+	require.Len(t, windowed, 1)
+	assert.Equal(t, day.AddDate(0, 0, 2), windowed[0].ExitTime)
+
+	_, err = service.Trades(ctx, userID, TradeFilter{Outcome: "draw"})
+	assert.ErrorIs(t, err, ErrInvalidOutcome)
+}
@@ -0,0 +1,16 @@
+package retention
+
+var (
+	ErrRunning       = &RetentionError{message: "a retention cleanup is already running"}
+	ErrInvalidMode   = &RetentionError{message: "retention mode must be archive or purge"}
+	ErrInvalidMonths = &RetentionError{message: "retention must be a whole number of months, 0 to keep forever"}
+)
+
+// RetentionError represents a retention policy or cleanup that can't be used
+type RetentionError struct {
+	message string
+}
+
+func (e *RetentionError) Error() string {
+	return e.message
+}
@@ -0,0 +1,229 @@
+// Package retention archives or purges trading records nobody needs live any
+// more: cancelled and failed orders without fills, and drawdown guards that
+// have triggered
+package retention
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/scheduler"
+	"github.com/sungminna/upbit-trading-platform/pkg/recovery"
+)
+
+const (
+	// batchSize is how many records one statement removes, so a large
+	// backlog doesn't hold locks for long
+	batchSize = 1000
+	// runTimeout bounds a cleanup started by an admin
+	runTimeout = time.Hour
+	// jobInterval and jobJitter schedule the daily cleanup, spread across
+	// instances sharing the schedule
+	jobInterval = 24 * time.Hour
+	jobJitter   = 30 * time.Minute
+)
+
+// Triggers of a cleanup
+const (
+	TriggerSchedule = "schedule"
+	TriggerAdmin    = "admin"
+)
+
+// Policy is how long finished trading records are kept, in months. A
+// retention of 0 keeps that kind of record forever.
+type Policy struct {
+	Mode       model.RetentionMode `json:"mode"`
+	Orders     int                 `json:"order_months"`
+	Strategies int                 `json:"strategy_months"`
+}
+
+// Validate checks the mode and the retentions
+func (p Policy) Validate() error {
+	if !p.Mode.Valid() {
+		return ErrInvalidMode
+	}
+	if p.Orders < 0 || p.Strategies < 0 {
+		return ErrInvalidMonths
+	}
+	return nil
+}
+
+// cutoffs returns the times before which records have expired; zero for a
+// kind kept forever
+func (p Policy) cutoffs(now time.Time) (orders, strategies time.Time) {
+	if p.Orders > 0 {
+		orders = now.AddDate(0, -p.Orders, 0)
+	}
+	if p.Strategies > 0 {
+		strategies = now.AddDate(0, -p.Strategies, 0)
+	}
+	return orders, strategies
+}
+
+// Status reports the policy, the latest cleanup and what the next one would
+// remove
+type Status struct {
+	Policy            Policy              `json:"policy"`
+	Running           bool                `json:"running"`
+	LastRun           *model.RetentionRun `json:"last_run,omitempty"`
+	PendingOrders     int                 `json:"pending_orders"`
+	PendingStrategies int                 `json:"pending_strategies"`
+}
+
+// Service applies the retention policy on a schedule or when an admin asks.
+// One cleanup runs at a time per instance.
+type Service struct {
+	records repository.TradingRetentionRepository
+	policy  Policy
+
+	mu      sync.Mutex
+	running bool
+	lastRun *model.RetentionRun
+}
+
+// NewService creates a new retention service
+func NewService(records repository.TradingRetentionRepository, policy Policy) (*Service, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return &Service{
+		records: records,
+		policy:  policy,
+	}, nil
+}
+
+// Job returns the daily cleanup job
+func (s *Service) Job() scheduler.Job {
+	return scheduler.Job{
+		Name:     "trading-retention",
+		Schedule: scheduler.Every(jobInterval),
+		Jitter:   jobJitter,
+		Run: func(ctx context.Context, at time.Time) error {
+			_, err := s.Run(ctx, TriggerSchedule)
+			if err == ErrRunning {
+				return nil
+			}
+			return err
+		},
+	}
+}
+
+// Trigger starts a cleanup in the background and returns once it has
+// started, or ErrRunning if one is in progress
+func (s *Service) Trigger(ctx context.Context) error {
+	if !s.begin() {
+		return ErrRunning
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), runTimeout)
+	go func() {
+		defer cancel()
+		if _, err := s.run(ctx, TriggerAdmin); err != nil {
+			log.Printf("Retention cleanup failed: %v", err)
+		}
+	}()
+	return nil
+}
+
+// Run removes every expired record, in batches, and reports what was removed
+func (s *Service) Run(ctx context.Context, trigger string) (*model.RetentionRun, error) {
+	if !s.begin() {
+		return nil, ErrRunning
+	}
+	return s.run(ctx, trigger)
+}
+
+// begin claims the cleanup, reporting false if one is running
+func (s *Service) begin() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running {
+		return false
+	}
+	s.running = true
+	return true
+}
+
+// run removes the expired records; the caller must have claimed the cleanup
+func (s *Service) run(ctx context.Context, trigger string) (*model.RetentionRun, error) {
+	run := &model.RetentionRun{
+		Mode:      s.policy.Mode,
+		Trigger:   trigger,
+		StartedAt: time.Now(),
+	}
+	ordersBefore, strategiesBefore := s.policy.cutoffs(run.StartedAt)
+
+	// A panic fails the run rather than leaving the cleanup claimed forever
+	err := recovery.Call("retention.cleanup", func() (err error) {
+		if !ordersBefore.IsZero() {
+			run.OrdersBefore = &ordersBefore
+			run.Orders, err = expireAll(ctx, func(ctx context.Context) (int, error) {
+				return s.records.ExpireOrders(ctx, s.policy.Mode, ordersBefore, batchSize)
+			})
+		}
+		if err == nil && !strategiesBefore.IsZero() {
+			run.StrategiesBefore = &strategiesBefore
+			run.Strategies, err = expireAll(ctx, func(ctx context.Context) (int, error) {
+				return s.records.ExpireStrategies(ctx, s.policy.Mode, strategiesBefore, batchSize)
+			})
+		}
+		return err
+	})
+	if err != nil {
+		run.Error = err.Error()
+	}
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+
+	if run.Orders > 0 || run.Strategies > 0 {
+		log.Printf("Retention cleanup (%s) removed %d orders and %d strategies", s.policy.Mode, run.Orders, run.Strategies)
+	}
+
+	s.mu.Lock()
+	s.running = false
+	s.lastRun = run
+	s.mu.Unlock()
+	return run, err
+}
+
+// expireAll repeats a batch until it removes fewer than a full batch
+func expireAll(ctx context.Context, batch func(ctx context.Context) (int, error)) (int, error) {
+	total := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+		n, err := batch(ctx)
+		total += n
+		if err != nil || n < batchSize {
+			return total, err
+		}
+	}
+}
+
+// Status reports the policy, the latest cleanup and how many records have
+// expired since
+func (s *Service) Status(ctx context.Context) (*Status, error) {
+	s.mu.Lock()
+	status := &Status{
+		Policy:  s.policy,
+		Running: s.running,
+	}
+	if s.lastRun != nil {
+		lastRun := *s.lastRun
+		status.LastRun = &lastRun
+	}
+	s.mu.Unlock()
+
+	ordersBefore, strategiesBefore := s.policy.cutoffs(time.Now())
+	orders, strategies, err := s.records.CountExpired(ctx, ordersBefore, strategiesBefore)
+	if err != nil {
+		return nil, err
+	}
+	status.PendingOrders, status.PendingStrategies = orders, strategies
+	return status, nil
+}
@@ -0,0 +1,161 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+)
+
+// createOrder stores an order last updated age ago
+func createOrder(t *testing.T, store *memory.Store, status model.OrderStatus, executed float64, age time.Duration) *model.Order {
+	order := model.NewOrder(uuid.New(), "KRW-BTC", model.OrderSideBid, model.OrderTypeMarket, 0.01, nil, time.Now())
+	order.Status = status
+	order.ExecutedQuantity = executed
+	order.UpdatedAt = time.Now().Add(-age)
+	require.NoError(t, store.Orders().Create(context.Background(), order))
+	return order
+}
+
+// createGuard stores a drawdown guard that triggered age ago, or an active
+// one for a zero age
+func createGuard(t *testing.T, store *memory.Store, age time.Duration) *model.DrawdownGuard {
+	guard := model.NewDrawdownGuard(&model.Position{ID: uuid.New(), UserID: uuid.New(), Market: "KRW-BTC"}, 10, 60, time.Now())
+	if age > 0 {
+		triggeredAt := time.Now().Add(-age)
+		guard.Active = false
+		guard.TriggeredAt = &triggeredAt
+	}
+	require.NoError(t, store.DrawdownGuards().Save(context.Background(), guard))
+	return guard
+}
+
+const month = 31 * 24 * time.Hour
+
+func TestService_Run(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+
+	expiredCancelled := createOrder(t, store, model.OrderStatusCancelled, 0, 7*month)
+	expiredFailed := createOrder(t, store, model.OrderStatusFailed, 0, 7*month)
+	partiallyFilled := createOrder(t, store, model.OrderStatusCancelled, 0.005, 7*month)
+	filled := createOrder(t, store, model.OrderStatusFilled, 0.01, 7*month)
+	recent := createOrder(t, store, model.OrderStatusCancelled, 0, month)
+	expiredGuard := createGuard(t, store, 13*month)
+	recentGuard := createGuard(t, store, 6*month)
+	activeGuard := createGuard(t, store, 0)
+
+	service, err := NewService(store.Retention(), Policy{Mode: model.RetentionArchive, Orders: 6, Strategies: 12})
+	require.NoError(t, err)
+
+	status, err := service.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, status.PendingOrders)
+	assert.Equal(t, 1, status.PendingStrategies)
+	assert.Nil(t, status.LastRun)
+
+	run, err := service.Run(ctx, TriggerSchedule)
+	require.NoError(t, err)
+	assert.Equal(t, 2, run.Orders)
+	assert.Equal(t, 1, run.Strategies)
+	assert.NotNil(t, run.FinishedAt)
+
+	for _, id := range []uuid.UUID{expiredCancelled.ID, expiredFailed.ID} {
+		_, err := store.Orders().GetByID(ctx, id)
+		assert.ErrorIs(t, err, repository.ErrNotFound, "expired orders are removed")
+	}
+	for _, id := range []uuid.UUID{partiallyFilled.ID, filled.ID, recent.ID} {
+		_, err := store.Orders().GetByID(ctx, id)
+		assert.NoError(t, err, "orders with fills or within the retention are kept")
+	}
+	_, err = store.DrawdownGuards().GetByPosition(ctx, expiredGuard.PositionID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+	for _, guard := range []*model.DrawdownGuard{recentGuard, activeGuard} {
+		_, err := store.DrawdownGuards().GetByPosition(ctx, guard.PositionID)
+		assert.NoError(t, err)
+	}
+
+	status, err = service.Status(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, status.PendingOrders)
+	assert.Zero(t, status.PendingStrategies)
+	require.NotNil(t, status.LastRun)
+	assert.Equal(t, TriggerSchedule, status.LastRun.Trigger)
+}
+
+func TestService_KeepsForever(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	order := createOrder(t, store, model.OrderStatusCancelled, 0, 60*month)
+	createGuard(t, store, 13*month)
+
+	service, err := NewService(store.Retention(), Policy{Mode: model.RetentionPurge, Strategies: 12})
+	require.NoError(t, err)
+
+	run, err := service.Run(ctx, TriggerSchedule)
+	require.NoError(t, err)
+	assert.Zero(t, run.Orders, "orders have no retention")
+	assert.Nil(t, run.OrdersBefore)
+	assert.Equal(t, 1, run.Strategies)
+
+	_, err = store.Orders().GetByID(ctx, order.ID)
+	assert.NoError(t, err)
+}
+
+func TestService_Batches(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	for i := 0; i < batchSize+5; i++ {
+		createOrder(t, store, model.OrderStatusCancelled, 0, 7*month)
+	}
+
+	service, err := NewService(store.Retention(), Policy{Mode: model.RetentionPurge, Orders: 6})
+	require.NoError(t, err)
+
+	run, err := service.Run(ctx, TriggerSchedule)
+	require.NoError(t, err)
+	assert.Equal(t, batchSize+5, run.Orders)
+}
+
+func TestService_Trigger(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	createOrder(t, store, model.OrderStatusFailed, 0, 7*month)
+
+	service, err := NewService(store.Retention(), Policy{Mode: model.RetentionArchive, Orders: 6})
+	require.NoError(t, err)
+
+	require.True(t, service.begin())
+	assert.ErrorIs(t, service.Trigger(ctx), ErrRunning, "one cleanup at a time")
+	_, err = service.Run(ctx, TriggerSchedule)
+	assert.ErrorIs(t, err, ErrRunning)
+	_, err = service.run(ctx, TriggerSchedule)
+	require.NoError(t, err)
+
+	createOrder(t, store, model.OrderStatusFailed, 0, 7*month)
+	require.NoError(t, service.Trigger(ctx))
+	require.Eventually(t, func() bool {
+		status, err := service.Status(ctx)
+		return err == nil && !status.Running && status.LastRun.Trigger == TriggerAdmin
+	}, time.Second, 10*time.Millisecond)
+
+	status, err := service.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, status.LastRun.Orders)
+}
+
+func TestNewService_ValidatesPolicy(t *testing.T) {
+	store := memory.NewStore()
+
+	_, err := NewService(store.Retention(), Policy{Mode: "shred", Orders: 6})
+	assert.True(t, errors.Is(err, ErrInvalidMode))
+	_, err = NewService(store.Retention(), Policy{Mode: model.RetentionPurge, Orders: -1})
+	assert.True(t, errors.Is(err, ErrInvalidMonths))
+}
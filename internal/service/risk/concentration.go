@@ -0,0 +1,212 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// BalanceSource provides users' cached exchange balances; balance.Service
+// satisfies it
+type BalanceSource interface {
+	Balances(ctx context.Context, userID uuid.UUID) (*model.AccountBalances, error)
+}
+
+// ExposureReport is a user's open positions marked to market. Equity is the
+// KRW on the exchange, including funds locked by open orders, plus the value
+// of open positions.
+type ExposureReport struct {
+	Equity          float64            `json:"equity"`
+	Cash            float64            `json:"cash"`
+	Invested        float64            `json:"invested"`         // Market value of open positions
+	InvestedPercent float64            `json:"invested_percent"` // Of equity
+	Markets         []MarketExposure   `json:"markets"`          // Largest first
+	Positions       []PositionExposure `json:"positions"`        // Largest first
+	Largest         *MarketExposure    `json:"largest,omitempty"`
+	PricedAt        time.Time          `json:"priced_at"`
+}
+
+// MarketExposure is the value held in one market
+type MarketExposure struct {
+	Market          string  `json:"market"`
+	Value           float64 `json:"value"`
+	PercentOfEquity float64 `json:"percent_of_equity"`
+	OpenBids        float64 `json:"open_bids"` // KRW of unfilled buy orders
+	Positions       int     `json:"positions"`
+}
+
+// PositionExposure is one open position marked to market
+type PositionExposure struct {
+	PositionID      uuid.UUID `json:"position_id"`
+	Market          string    `json:"market"`
+	Quantity        float64   `json:"quantity"`
+	EntryPrice      float64   `json:"entry_price"`
+	Price           float64   `json:"price"`
+	Value           float64   `json:"value"`
+	UnrealizedPnL   float64   `json:"unrealized_pnl"`
+	PercentOfEquity float64   `json:"percent_of_equity"`
+}
+
+// WithMarketData enables exposure reports and the concentration limit, which
+// need current prices and the user's cash balance
+func (s *Service) WithMarketData(tickers TickerSource, balances BalanceSource) *Service {
+	s.tickers = tickers
+	s.balances = balances
+	return s
+}
+
+// ExposureReport marks the user's open positions to market and reports how
+// concentrated their equity is
+func (s *Service) ExposureReport(ctx context.Context, userID uuid.UUID) (*ExposureReport, error) {
+	if s.tickers == nil || s.balances == nil {
+		return nil, ErrNoMarketData
+	}
+
+	balances, err := s.balances.Balances(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load balances: %w", err)
+	}
+	positions, err := s.positions.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list positions: %w", err)
+	}
+	orders, err := s.orders.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders: %w", err)
+	}
+
+	var open []*model.Position
+	var markets []string
+	for _, p := range positions {
+		if p.Status == model.PositionStatusOpen {
+			open = append(open, p)
+			markets = append(markets, p.Market)
+		}
+	}
+	prices, err := fetchPrices(ctx, s.tickers, markets)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ExposureReport{
+		Positions: make([]PositionExposure, 0, len(open)),
+		PricedAt:  s.clock.Now(),
+	}
+	for _, b := range balances.Balances {
+		if b.Currency == "KRW" {
+			report.Cash = b.Balance + b.Locked
+		}
+	}
+
+	byMarket := make(map[string]*MarketExposure)
+	marketFor := func(market string) *MarketExposure {
+		m, ok := byMarket[market]
+		if !ok {
+			m = &MarketExposure{Market: market}
+			byMarket[market] = m
+		}
+		return m
+	}
+	for _, p := range open {
+		price := prices[p.Market]
+		value := p.Quantity * price
+		report.Positions = append(report.Positions, PositionExposure{
+			PositionID:    p.ID,
+			Market:        p.Market,
+			Quantity:      p.Quantity,
+			EntryPrice:    p.EntryPrice,
+			Price:         price,
+			Value:         value,
+			UnrealizedPnL: p.CalculateUnrealizedPnL(price),
+		})
+		m := marketFor(p.Market)
+		m.Value += value
+		m.Positions++
+		report.Invested += value
+	}
+	for _, o := range orders {
+		if isOpen(o) && o.Side == model.OrderSideBid && o.Price != nil {
+			marketFor(o.Market).OpenBids += *o.Price * (o.Quantity - o.ExecutedQuantity)
+		}
+	}
+
+	report.Equity = report.Cash + report.Invested
+	report.InvestedPercent = percentOf(report.Invested, report.Equity)
+	for i := range report.Positions {
+		report.Positions[i].PercentOfEquity = percentOf(report.Positions[i].Value, report.Equity)
+	}
+	sort.SliceStable(report.Positions, func(i, j int) bool {
+		return report.Positions[i].Value > report.Positions[j].Value
+	})
+
+	report.Markets = make([]MarketExposure, 0, len(byMarket))
+	for _, m := range byMarket {
+		m.PercentOfEquity = percentOf(m.Value, report.Equity)
+		report.Markets = append(report.Markets, *m)
+	}
+	sort.Slice(report.Markets, func(i, j int) bool {
+		if report.Markets[i].Value != report.Markets[j].Value {
+			return report.Markets[i].Value > report.Markets[j].Value
+		}
+		return report.Markets[i].Market < report.Markets[j].Market
+	})
+	if len(report.Markets) > 0 && report.Markets[0].Value > 0 {
+		largest := report.Markets[0]
+		report.Largest = &largest
+	}
+
+	return report, nil
+}
+
+// Market returns the exposure to a market, which is empty if nothing is held
+func (r *ExposureReport) Market(market string) MarketExposure {
+	for _, m := range r.Markets {
+		if m.Market == market {
+			return m
+		}
+	}
+	return MarketExposure{Market: market}
+}
+
+// fetchPrices returns the current price of every market with a single
+// ticker request
+func fetchPrices(ctx context.Context, tickers TickerSource, markets []string) (map[string]float64, error) {
+	var unique []string
+	seen := make(map[string]bool)
+	for _, market := range markets {
+		if !seen[market] {
+			seen[market] = true
+			unique = append(unique, market)
+		}
+	}
+
+	prices := make(map[string]float64, len(unique))
+	if len(unique) == 0 {
+		return prices, nil
+	}
+
+	result, err := tickers.GetTicker(ctx, unique)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prices: %w", err)
+	}
+	for _, ticker := range result {
+		prices[ticker.Market] = ticker.TradePrice
+	}
+	for _, market := range unique {
+		if _, ok := prices[market]; !ok {
+			return nil, fmt.Errorf("no price for %s", market)
+		}
+	}
+	return prices, nil
+}
+
+func percentOf(value, total float64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return value / total * 100
+}
@@ -0,0 +1,93 @@
+package risk
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+)
+
+// fixedBalances serves the same KRW balance to every user
+type fixedBalances struct {
+	krw, locked float64
+}
+
+func (b fixedBalances) Balances(ctx context.Context, userID uuid.UUID) (*model.AccountBalances, error) {
+	return &model.AccountBalances{
+		UserID:   userID,
+		Balances: []model.Balance{{Currency: "KRW", Balance: b.krw, Locked: b.locked}},
+	}, nil
+}
+
+func TestService_ExposureReport(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	store := memory.NewStore()
+	service := NewService(store.RiskLimits(), store.RiskStates(), store.Positions(), store.Orders())
+
+	_, err := service.ExposureReport(ctx, userID)
+	assert.ErrorIs(t, err, ErrNoMarketData)
+
+	service.WithMarketData(fakeTickers{"KRW-BTC": 110000000, "KRW-ETH": 4000000}, fixedBalances{krw: 3500000, locked: 500000})
+	require.NoError(t, store.Positions().Create(ctx, model.NewPosition(userID, "KRW-BTC", model.PositionSideLong, 100000000, 0.04, time.Now())))
+	require.NoError(t, store.Positions().Create(ctx, model.NewPosition(userID, "KRW-BTC", model.PositionSideLong, 100000000, 0.01, time.Now())))
+	require.NoError(t, store.Positions().Create(ctx, model.NewPosition(userID, "KRW-ETH", model.PositionSideLong, 5000000, 0.125, time.Now())))
+	resting := buyOrder(userID, "KRW-ETH", 0.1, 4000000)
+	resting.Status = model.OrderStatusSubmitted
+	require.NoError(t, store.Orders().Create(ctx, resting))
+
+	report, err := service.ExposureReport(ctx, userID)
+	require.NoError(t, err)
+
+	// 4,000,000 cash + 5,500,000 BTC + 500,000 ETH
+	assert.InDelta(t, 4000000, report.Cash, 1e-6)
+	assert.InDelta(t, 6000000, report.Invested, 1e-6)
+	assert.InDelta(t, 10000000, report.Equity, 1e-6)
+	assert.InDelta(t, 60, report.InvestedPercent, 1e-9)
+
+	require.Len(t, report.Markets, 2)
+	require.NotNil(t, report.Largest)
+	assert.Equal(t, "KRW-BTC", report.Largest.Market)
+	assert.InDelta(t, 55, report.Largest.PercentOfEquity, 1e-9)
+	assert.Equal(t, 2, report.Largest.Positions)
+	eth := report.Market("KRW-ETH")
+	assert.InDelta(t, 5, eth.PercentOfEquity, 1e-9)
+	assert.InDelta(t, 400000, eth.OpenBids, 1e-6)
+
+	require.Len(t, report.Positions, 3)
+	assert.InDelta(t, 44, report.Positions[0].PercentOfEquity, 1e-9)
+	assert.InDelta(t, -125000, report.Positions[2].UnrealizedPnL, 1e-6)
+}
+
+func TestService_CheckConcentration(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	service, store := newTestService(t, model.RiskLimits{UserID: userID, MaxConcentration: 30, Downsize: true})
+	service.WithMarketData(fakeTickers{"KRW-BTC": 100000000}, fixedBalances{krw: 8000000})
+
+	// 2,000,000 of 10,000,000 equity is in BTC, so 1,000,000 more fits
+	require.NoError(t, store.Positions().Create(ctx, model.NewPosition(userID, "KRW-BTC", model.PositionSideLong, 100000000, 0.02, time.Now())))
+
+	order := buyOrder(userID, "KRW-BTC", 0.02, 100000000)
+	require.NoError(t, service.Check(ctx, order))
+	assert.InDelta(t, 0.01, order.Quantity, 1e-12)
+
+	// Other markets have their own headroom
+	order = buyOrder(userID, "KRW-ETH", 0.5, 4000000)
+	require.NoError(t, service.Check(ctx, order))
+	assert.InDelta(t, 0.5, order.Quantity, 1e-12)
+
+	limits, err := service.Limits(ctx, userID)
+	require.NoError(t, err)
+	limits.Downsize = false
+	require.NoError(t, service.SetLimits(ctx, limits))
+	assert.ErrorIs(t, service.Check(ctx, buyOrder(userID, "KRW-BTC", 0.02, 100000000)), ErrConcentration)
+
+	limits.MaxConcentration = 150
+	assert.ErrorIs(t, service.SetLimits(ctx, limits), ErrInvalidConcentration)
+}
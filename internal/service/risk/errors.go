@@ -0,0 +1,24 @@
+package risk
+
+var (
+	ErrInvalidLimits        = &RiskError{message: "risk limits must not be negative"}
+	ErrOrderTooLarge        = &RiskError{message: "order exceeds the maximum order size"}
+	ErrTooManyPositions     = &RiskError{message: "order would exceed the maximum number of open positions"}
+	ErrMarketExposure       = &RiskError{message: "order would exceed the maximum exposure to the market"}
+	ErrTotalExposure        = &RiskError{message: "order would exceed the maximum total exposure"}
+	ErrBelowMinimumOrder    = &RiskError{message: "order is below the exchange minimum after downsizing"}
+	ErrInvalidTradingDay    = &RiskError{message: "day_start must be HH:MM and timezone an IANA name"}
+	ErrDailyLossLimit       = &RiskError{message: "daily loss limit reached; buying is halted until the next trading day"}
+	ErrConcentration        = &RiskError{message: "order would exceed the maximum concentration in the market"}
+	ErrInvalidConcentration = &RiskError{message: "max_concentration must be a percent between 0 and 100"}
+	ErrNoMarketData         = &RiskError{message: "exposure reports need prices and balances, which are not configured"}
+)
+
+// RiskError represents a violated risk limit or invalid limits
+type RiskError struct {
+	message string
+}
+
+func (e *RiskError) Error() string {
+	return e.message
+}
@@ -0,0 +1,261 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/notification"
+	"github.com/sungminna/upbit-trading-platform/internal/service/scheduler"
+	"github.com/sungminna/upbit-trading-platform/internal/service/trading"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/quotation"
+	"github.com/sungminna/upbit-trading-platform/pkg/cache"
+)
+
+const (
+	lossCheckInterval = 30 * time.Second
+	haltKey           = "risk-halt:"
+)
+
+// TickerSource provides current prices; gateway.QuotationAPI satisfies it
+type TickerSource interface {
+	GetTicker(ctx context.Context, markets []string) ([]quotation.Ticker, error)
+}
+
+// Flattener cancels orders and places the sells that flatten positions;
+// trading.Engine satisfies it
+type Flattener interface {
+	PlaceOrder(ctx context.Context, userID uuid.UUID, req trading.PlaceOrderRequest) (*model.Order, error)
+	CancelOrder(ctx context.Context, userID, orderID uuid.UUID) (*model.Order, error)
+}
+
+// LossMonitor tracks the daily PnL of users with a daily loss limit and
+// halts their buying when it is hit
+type LossMonitor struct {
+	risk      *Service
+	tickers   TickerSource
+	flattener Flattener
+	notifier  notification.Notifier // Optional
+	claims    cache.Cache           // Claims halts so instances act on each only once
+}
+
+// NewLossMonitor creates a new daily loss monitor
+func NewLossMonitor(risk *Service, tickers TickerSource, flattener Flattener, notifier notification.Notifier, claims cache.Cache) *LossMonitor {
+	return &LossMonitor{
+		risk:      risk,
+		tickers:   tickers,
+		flattener: flattener,
+		notifier:  notifier,
+		claims:    claims,
+	}
+}
+
+// Job returns the job evaluating daily losses
+func (m *LossMonitor) Job() scheduler.Job {
+	return scheduler.Job{
+		Name:     "daily-loss-limits",
+		Schedule: scheduler.Every(lossCheckInterval),
+		Run:      m.Evaluate,
+	}
+}
+
+// Evaluate updates the daily PnL of every user with a daily loss limit and
+// halts those who hit it. Users holding the same market share its price: each
+// market is fetched once per pass. A failure for one user doesn't stop the
+// others.
+func (m *LossMonitor) Evaluate(ctx context.Context, now time.Time) error {
+	limits, err := m.risk.limits.ListDailyLossLimits(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	var markets []string
+	positions := make(map[uuid.UUID][]*model.Position, len(limits))
+	for _, l := range limits {
+		held, err := m.risk.positions.ListByUser(ctx, l.UserID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("user %s: failed to list positions: %w", l.UserID, err))
+			continue
+		}
+		positions[l.UserID] = held
+		for _, p := range held {
+			if p.Status == model.PositionStatusOpen {
+				markets = append(markets, p.Market)
+			}
+		}
+	}
+
+	prices, err := m.prices(ctx, markets)
+	if err != nil {
+		return errors.Join(append(errs, err)...)
+	}
+	for _, l := range limits {
+		held, ok := positions[l.UserID]
+		if !ok {
+			continue
+		}
+		if err := m.evaluate(ctx, l, held, prices, now); err != nil {
+			errs = append(errs, fmt.Errorf("user %s: %w", l.UserID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// prices returns the current price of every market with a single ticker
+// request. Markets the exchange has no price for are left out, so only the
+// users holding them fail.
+func (m *LossMonitor) prices(ctx context.Context, markets []string) (map[string]float64, error) {
+	var unique []string
+	seen := make(map[string]bool)
+	for _, market := range markets {
+		if !seen[market] {
+			seen[market] = true
+			unique = append(unique, market)
+		}
+	}
+
+	prices := make(map[string]float64, len(unique))
+	if len(unique) == 0 {
+		return prices, nil
+	}
+
+	result, err := m.tickers.GetTicker(ctx, unique)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get prices: %w", err)
+	}
+	for _, ticker := range result {
+		prices[ticker.Market] = ticker.TradePrice
+	}
+	return prices, nil
+}
+
+// evaluate updates one user's daily PnL. The first evaluation of a trading
+// day records the PnL it is measured from.
+func (m *LossMonitor) evaluate(ctx context.Context, limits *model.RiskLimits, positions []*model.Position, prices map[string]float64, now time.Time) error {
+	day, err := limits.TradingDay(now)
+	if err != nil {
+		return err
+	}
+	pnl, err := totalPnL(positions, prices)
+	if err != nil {
+		return err
+	}
+
+	state, err := m.risk.states.Get(ctx, limits.UserID)
+	switch {
+	case errors.Is(err, repository.ErrNotFound) || (err == nil && !state.Day.Equal(day)):
+		state = &model.RiskState{UserID: limits.UserID, Day: day, StartPnL: pnl}
+	case err != nil:
+		return err
+	}
+
+	state.DayPnL = pnl - state.StartPnL
+	state.UpdatedAt = now
+	breached := state.HaltedAt == nil && state.DayPnL <= -limits.DailyLossLimit
+	if breached {
+		state.HaltedAt = &now
+	}
+
+	if err := m.risk.states.Save(ctx, state); err != nil {
+		return err
+	}
+	if !breached {
+		return nil
+	}
+
+	key := haltKey + limits.UserID.String() + ":" + strconv.FormatInt(day.Unix(), 10)
+	claimed, err := m.claims.SetNX(ctx, key, []byte{1}, 25*time.Hour)
+	if err != nil || !claimed {
+		return err
+	}
+	return m.halt(ctx, limits, state)
+}
+
+// totalPnL returns the realized PnL of the positions plus the unrealized PnL
+// of the open ones at prices
+func totalPnL(positions []*model.Position, prices map[string]float64) (float64, error) {
+	var pnl float64
+	for _, p := range positions {
+		pnl += p.RealizedPnL
+		if p.Status != model.PositionStatusOpen {
+			continue
+		}
+		price, ok := prices[p.Market]
+		if !ok {
+			return 0, fmt.Errorf("no price for %s", p.Market)
+		}
+		pnl += p.CalculateUnrealizedPnL(price)
+	}
+	return pnl, nil
+}
+
+// halt notifies the user that buying is halted and flattens their positions
+// if they asked for it
+func (m *LossMonitor) halt(ctx context.Context, limits *model.RiskLimits, state *model.RiskState) error {
+	log.Printf("User %s hit the daily loss limit: %.0f KRW", limits.UserID, state.DayPnL)
+
+	var flattenErr error
+	message := fmt.Sprintf("Today's PnL is %s KRW, past your limit of -%s KRW. Buying is halted until the next trading day.",
+		formatKRW(state.DayPnL), formatKRW(limits.DailyLossLimit))
+	if limits.FlattenOnLoss {
+		flattenErr = m.flatten(ctx, limits.UserID)
+		message += " Open buy orders are being cancelled and positions sold at market."
+	}
+
+	if m.notifier != nil {
+		n := model.NewNotification(limits.UserID, model.NotificationDailyLossLimit, "Daily loss limit reached", message,
+			map[string]any{"day_pnl": state.DayPnL, "limit": limits.DailyLossLimit, "flatten": limits.FlattenOnLoss}, *state.HaltedAt)
+		if err := m.notifier.Notify(ctx, n); err != nil {
+			log.Printf("Error notifying user %s of the daily loss limit: %v", limits.UserID, err)
+		}
+	}
+	return flattenErr
+}
+
+// flatten cancels the user's open buy orders and sells every open position at
+// market
+func (m *LossMonitor) flatten(ctx context.Context, userID uuid.UUID) error {
+	var errs []error
+
+	orders, err := m.risk.orders.ListByUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to list orders: %w", err)
+	}
+	for _, o := range orders {
+		if o.Side != model.OrderSideBid || o.ExchangeOrderID == nil || !isOpen(o) {
+			continue
+		}
+		if _, err := m.flattener.CancelOrder(ctx, userID, o.ID); err != nil {
+			errs = append(errs, fmt.Errorf("cancel order %s: %w", o.ID, err))
+		}
+	}
+
+	positions, err := m.risk.positions.ListByUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to list positions: %w", err)
+	}
+	for _, p := range positions {
+		if p.Status != model.PositionStatusOpen || p.Quantity <= 0 {
+			continue
+		}
+		_, err := m.flattener.PlaceOrder(ctx, userID, trading.PlaceOrderRequest{
+			Market:     p.Market,
+			Side:       model.OrderSideAsk,
+			Type:       model.OrderTypeMarket,
+			Quantity:   p.Quantity,
+			PositionID: &p.ID,
+			Source:     model.OrderSourceLossLimit,
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("sell position %s: %w", p.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,179 @@
+package risk
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+	"github.com/sungminna/upbit-trading-platform/internal/service/trading"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/quotation"
+	"github.com/sungminna/upbit-trading-platform/pkg/cache"
+)
+
+type fakeTickers map[string]float64
+
+func (f fakeTickers) GetTicker(ctx context.Context, markets []string) ([]quotation.Ticker, error) {
+	var result []quotation.Ticker
+	for _, market := range markets {
+		result = append(result, quotation.Ticker{Market: market, TradePrice: f[market]})
+	}
+	return result, nil
+}
+
+// countingTickers records the markets of every ticker request
+type countingTickers struct {
+	fakeTickers
+	requests [][]string
+}
+
+func (c *countingTickers) GetTicker(ctx context.Context, markets []string) ([]quotation.Ticker, error) {
+	c.requests = append(c.requests, markets)
+	var result []quotation.Ticker
+	for _, market := range markets {
+		if price, ok := c.fakeTickers[market]; ok {
+			result = append(result, quotation.Ticker{Market: market, TradePrice: price})
+		}
+	}
+	return result, nil
+}
+
+type recordingFlattener struct {
+	placed    []trading.PlaceOrderRequest
+	cancelled []uuid.UUID
+}
+
+func (f *recordingFlattener) PlaceOrder(ctx context.Context, userID uuid.UUID, req trading.PlaceOrderRequest) (*model.Order, error) {
+	f.placed = append(f.placed, req)
+	return model.NewOrder(userID, req.Market, req.Side, req.Type, req.Quantity, req.Price, time.Now()), nil
+}
+
+func (f *recordingFlattener) CancelOrder(ctx context.Context, userID, orderID uuid.UUID) (*model.Order, error) {
+	f.cancelled = append(f.cancelled, orderID)
+	return nil, nil
+}
+
+type recordingNotifier struct {
+	sent []*model.Notification
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, notification *model.Notification) error {
+	n.sent = append(n.sent, notification)
+	return nil
+}
+
+func TestLossMonitor_HaltsAndFlattensUntilNextDay(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	service := NewService(store.RiskLimits(), store.RiskStates(), store.Positions(), store.Orders())
+	prices := fakeTickers{"KRW-BTC": 100000000}
+	flattener := &recordingFlattener{}
+	notifier := &recordingNotifier{}
+	monitor := NewLossMonitor(service, prices, flattener, notifier, cache.NewMemoryCache())
+
+	userID := uuid.New()
+	require.NoError(t, service.SetLimits(ctx, &model.RiskLimits{
+		UserID:         userID,
+		DailyLossLimit: 100000,
+		FlattenOnLoss:  true,
+		DayStart:       "09:00",
+		Timezone:       "Asia/Seoul",
+	}))
+	position := model.NewPosition(userID, "KRW-BTC", model.PositionSideLong, 100000000, 0.1, time.Now())
+	require.NoError(t, store.Positions().Create(ctx, position))
+	resting := buyOrder(userID, "KRW-BTC", 0.01, 95000000)
+	exchangeID := "exchange-order"
+	resting.ExchangeOrderID = &exchangeID
+	resting.Status = model.OrderStatusSubmitted
+	require.NoError(t, store.Orders().Create(ctx, resting))
+
+	kst := time.FixedZone("KST", 9*60*60)
+	morning := time.Date(2025, 3, 3, 9, 0, 30, 0, kst)
+
+	// The first evaluation of the day sets the baseline
+	require.NoError(t, monitor.Evaluate(ctx, morning))
+	prices["KRW-BTC"] = 99500000 // -50,000 KRW
+	require.NoError(t, monitor.Evaluate(ctx, morning.Add(time.Hour)))
+	assert.Empty(t, notifier.sent)
+
+	prices["KRW-BTC"] = 98900000 // -110,000 KRW
+	require.NoError(t, monitor.Evaluate(ctx, morning.Add(2*time.Hour)))
+	require.NoError(t, monitor.Evaluate(ctx, morning.Add(3*time.Hour)))
+
+	require.Len(t, notifier.sent, 1)
+	assert.Equal(t, model.NotificationDailyLossLimit, notifier.sent[0].Type)
+	assert.Equal(t, []uuid.UUID{resting.ID}, flattener.cancelled)
+	require.Len(t, flattener.placed, 1)
+	assert.Equal(t, model.OrderSideAsk, flattener.placed[0].Side)
+	assert.Equal(t, 0.1, flattener.placed[0].Quantity)
+	assert.Equal(t, &position.ID, flattener.placed[0].PositionID)
+
+	state, err := store.RiskStates().Get(ctx, userID)
+	require.NoError(t, err)
+	assert.InDelta(t, -110000, state.DayPnL, 1e-6)
+	require.NotNil(t, state.HaltedAt)
+
+	// The next trading day starts from a new baseline
+	require.NoError(t, monitor.Evaluate(ctx, morning.Add(24*time.Hour)))
+	state, err = store.RiskStates().Get(ctx, userID)
+	require.NoError(t, err)
+	assert.Nil(t, state.HaltedAt)
+	assert.Zero(t, state.DayPnL)
+}
+
+func TestLossMonitor_FetchesEachMarketOncePerPass(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	service := NewService(store.RiskLimits(), store.RiskStates(), store.Positions(), store.Orders())
+	tickers := &countingTickers{fakeTickers: fakeTickers{"KRW-BTC": 100000000, "KRW-ETH": 5000000}}
+	monitor := NewLossMonitor(service, tickers, &recordingFlattener{}, nil, cache.NewMemoryCache())
+
+	markets := [][]string{{"KRW-BTC"}, {"KRW-BTC", "KRW-ETH"}, {"KRW-BTC", "KRW-XRP"}}
+	users := make([]uuid.UUID, len(markets))
+	for i, held := range markets {
+		users[i] = uuid.New()
+		require.NoError(t, service.SetLimits(ctx, &model.RiskLimits{UserID: users[i], DailyLossLimit: 100000}))
+		for _, market := range held {
+			require.NoError(t, store.Positions().Create(ctx, model.NewPosition(users[i], market, model.PositionSideLong, 1000, 1, time.Now())))
+		}
+	}
+
+	err := monitor.Evaluate(ctx, time.Now())
+	require.Len(t, tickers.requests, 1)
+	assert.ElementsMatch(t, []string{"KRW-BTC", "KRW-ETH", "KRW-XRP"}, tickers.requests[0])
+
+	// Only the user holding the unpriced market fails
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), users[2].String())
+	for _, userID := range users[:2] {
+		_, err := store.RiskStates().Get(ctx, userID)
+		assert.NoError(t, err)
+	}
+}
+
+func TestService_CheckRejectsBuysWhileHalted(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	service := NewService(store.RiskLimits(), store.RiskStates(), store.Positions(), store.Orders())
+
+	userID := uuid.New()
+	limits := &model.RiskLimits{UserID: userID, DailyLossLimit: 100000}
+	require.NoError(t, service.SetLimits(ctx, limits))
+
+	day, err := limits.TradingDay(time.Now())
+	require.NoError(t, err)
+	halted := time.Now()
+	require.NoError(t, store.RiskStates().Save(ctx, &model.RiskState{UserID: userID, Day: day, DayPnL: -150000, HaltedAt: &halted}))
+
+	assert.ErrorIs(t, service.Check(ctx, buyOrder(userID, "KRW-BTC", 0.001, 100000000)), ErrDailyLossLimit)
+	sell := model.NewOrder(userID, "KRW-BTC", model.OrderSideAsk, model.OrderTypeMarket, 0.001, nil, time.Now())
+	assert.NoError(t, service.Check(ctx, sell))
+
+	// Yesterday's halt no longer applies
+	require.NoError(t, store.RiskStates().Save(ctx, &model.RiskState{UserID: userID, Day: day.AddDate(0, 0, -1), HaltedAt: &halted}))
+	assert.NoError(t, service.Check(ctx, buyOrder(userID, "KRW-BTC", 0.001, 100000000)))
+}
@@ -0,0 +1,272 @@
+// Package risk checks orders against users' pre-trade risk limits
+package risk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/pkg/clock"
+)
+
+// Service stores risk limits and checks new orders against them
+type Service struct {
+	limits    repository.RiskLimitsRepository
+	states    repository.RiskStateRepository
+	positions repository.PositionRepository
+	orders    repository.OrderRepository
+	tickers   TickerSource  // Optional
+	balances  BalanceSource // Optional
+	clock     clock.Clock
+}
+
+// NewService creates a new risk service
+func NewService(
+	limits repository.RiskLimitsRepository,
+	states repository.RiskStateRepository,
+	positions repository.PositionRepository,
+	orders repository.OrderRepository,
+) *Service {
+	return &Service{
+		limits:    limits,
+		states:    states,
+		positions: positions,
+		orders:    orders,
+		clock:     clock.Real,
+	}
+}
+
+// WithClock sets the clock trading days and limit updates are dated by
+func (s *Service) WithClock(c clock.Clock) *Service {
+	s.clock = c
+	return s
+}
+
+// Limits returns the user's limits; users who haven't saved any have none
+func (s *Service) Limits(ctx context.Context, userID uuid.UUID) (*model.RiskLimits, error) {
+	limits, err := s.limits.Get(ctx, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.DefaultRiskLimits(userID), nil
+	}
+	return limits, err
+}
+
+// SetLimits validates and saves the user's limits. An empty day start or
+// timezone keeps the default trading day.
+func (s *Service) SetLimits(ctx context.Context, limits *model.RiskLimits) error {
+	if limits.MaxOrderNotional < 0 || limits.MaxOpenPositions < 0 ||
+		limits.MaxMarketExposure < 0 || limits.MaxTotalExposure < 0 || limits.DailyLossLimit < 0 {
+		return ErrInvalidLimits
+	}
+	if limits.MaxConcentration < 0 || limits.MaxConcentration > 100 {
+		return ErrInvalidConcentration
+	}
+
+	defaults := model.DefaultRiskLimits(limits.UserID)
+	if limits.DayStart == "" {
+		limits.DayStart = defaults.DayStart
+	}
+	if limits.Timezone == "" {
+		limits.Timezone = defaults.Timezone
+	}
+	now := s.clock.Now()
+	if _, err := limits.TradingDay(now); err != nil {
+		return ErrInvalidTradingDay
+	}
+
+	limits.UpdatedAt = now
+	return s.limits.Save(ctx, limits)
+}
+
+// Today returns the user's PnL tracking for the current trading day, or nil
+// if it isn't tracked
+func (s *Service) Today(ctx context.Context, limits *model.RiskLimits, now time.Time) (*model.RiskState, error) {
+	if limits.DailyLossLimit == 0 {
+		return nil, nil
+	}
+
+	day, err := limits.TradingDay(now)
+	if err != nil {
+		return nil, err
+	}
+	state, err := s.states.Get(ctx, limits.UserID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !state.Day.Equal(day) {
+		return nil, nil
+	}
+	return state, nil
+}
+
+// Exposure is a user's current KRW exposure
+type Exposure struct {
+	Total         float64            `json:"total"`
+	Markets       map[string]float64 `json:"markets"`
+	OpenPositions int                `json:"open_positions"` // Including open buys that will open one
+}
+
+// Exposure returns the cost of the user's open positions plus the unfilled
+// part of their open buy orders
+func (s *Service) Exposure(ctx context.Context, userID uuid.UUID) (*Exposure, error) {
+	positions, err := s.positions.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list positions: %w", err)
+	}
+	orders, err := s.orders.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders: %w", err)
+	}
+
+	exposure := &Exposure{Markets: make(map[string]float64)}
+	for _, p := range positions {
+		if p.Status != model.PositionStatusOpen {
+			continue
+		}
+		exposure.add(p.Market, p.EntryPrice*p.Quantity)
+		exposure.OpenPositions++
+	}
+	for _, o := range orders {
+		if !isOpen(o) || o.Side != model.OrderSideBid || o.Price == nil {
+			continue
+		}
+		exposure.add(o.Market, *o.Price*(o.Quantity-o.ExecutedQuantity))
+		if o.PositionID == nil {
+			exposure.OpenPositions++
+		}
+	}
+	return exposure, nil
+}
+
+func (e *Exposure) add(market string, amount float64) {
+	e.Markets[market] += amount
+	e.Total += amount
+}
+
+// Check checks a new order against its user's limits. Buys are rejected for
+// the rest of the trading day once the daily loss limit was hit. Buys that
+// exceed a KRW limit are rejected, or shrunk to fit by lowering
+// order.Quantity when the user enabled downsizing. Sells only reduce
+// exposure and always pass.
+func (s *Service) Check(ctx context.Context, order *model.Order) error {
+	if order.Side != model.OrderSideBid || order.Price == nil {
+		return nil
+	}
+
+	limits, err := s.Limits(ctx, order.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to load risk limits: %w", err)
+	}
+
+	today, err := s.Today(ctx, limits, s.clock.Now())
+	if err != nil {
+		return fmt.Errorf("failed to load daily PnL: %w", err)
+	}
+	if today != nil && today.HaltedAt != nil {
+		return fmt.Errorf("%w: %s KRW today", ErrDailyLossLimit, formatKRW(today.DayPnL))
+	}
+
+	if limits.MaxOrderNotional == 0 && limits.MaxOpenPositions == 0 &&
+		limits.MaxMarketExposure == 0 && limits.MaxTotalExposure == 0 && limits.MaxConcentration == 0 {
+		return nil
+	}
+
+	exposure, err := s.Exposure(ctx, order.UserID)
+	if err != nil {
+		return err
+	}
+	concentration, measured, err := s.concentrationHeadroom(ctx, limits, order.Market)
+	if err != nil {
+		return err
+	}
+	maxConcentration := limits.MaxConcentration
+	if !measured {
+		maxConcentration = 0 // Left out rather than allowing nothing
+	}
+
+	if limits.MaxOpenPositions > 0 && order.PositionID == nil && exposure.OpenPositions >= limits.MaxOpenPositions {
+		return fmt.Errorf("%w (%d)", ErrTooManyPositions, limits.MaxOpenPositions)
+	}
+
+	// The order must fit within every KRW limit; the tightest one wins
+	notional := order.Quantity * *order.Price
+	allowed := notional
+	var violated *RiskError
+	for _, c := range []struct {
+		limit, headroom float64
+		err             *RiskError
+	}{
+		{limits.MaxOrderNotional, limits.MaxOrderNotional, ErrOrderTooLarge},
+		{limits.MaxMarketExposure, limits.MaxMarketExposure - exposure.Markets[order.Market], ErrMarketExposure},
+		{limits.MaxTotalExposure, limits.MaxTotalExposure - exposure.Total, ErrTotalExposure},
+		{maxConcentration, concentration, ErrConcentration},
+	} {
+		if c.limit > 0 && c.headroom < allowed {
+			allowed, violated = max(c.headroom, 0), c.err
+		}
+	}
+	if violated == nil {
+		return nil
+	}
+
+	if !limits.Downsize {
+		return fmt.Errorf("%w: %s KRW requested, %s KRW allowed", violated, formatKRW(notional), formatKRW(allowed))
+	}
+	if allowed < model.MinOrderAmount {
+		return fmt.Errorf("%w (%s): %s KRW allowed", ErrBelowMinimumOrder, violated, formatKRW(allowed))
+	}
+
+	order.Quantity = allowed / *order.Price
+	return nil
+}
+
+// concentrationHeadroom returns how much more KRW the market can take before
+// its value and open buys exceed MaxConcentration percent of equity. It
+// reports false when the limit isn't set or there is no market data to
+// measure it with, in which case the limit is skipped.
+func (s *Service) concentrationHeadroom(ctx context.Context, limits *model.RiskLimits, market string) (float64, bool, error) {
+	if limits.MaxConcentration == 0 || s.tickers == nil || s.balances == nil {
+		return 0, false, nil
+	}
+
+	report, err := s.ExposureReport(ctx, limits.UserID)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to measure concentration: %w", err)
+	}
+	held := report.Market(market)
+	return report.Equity*limits.MaxConcentration/100 - held.Value - held.OpenBids, true, nil
+}
+
+func isOpen(order *model.Order) bool {
+	switch order.Status {
+	case model.OrderStatusPending, model.OrderStatusSubmitted, model.OrderStatusPartial:
+		return true
+	}
+	return false
+}
+
+// formatKRW formats an amount with thousands separators, e.g. 1,234,567
+func formatKRW(v float64) string {
+	s := fmt.Sprintf("%.0f", v)
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+
+	var sb strings.Builder
+	for i, c := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteRune(c)
+	}
+	return sign + sb.String()
+}
@@ -0,0 +1,138 @@
+package risk
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+	"github.com/sungminna/upbit-trading-platform/pkg/clock"
+)
+
+func newTestService(t *testing.T, limits model.RiskLimits) (*Service, *memory.Store) {
+	store := memory.NewStore()
+	service := NewService(store.RiskLimits(), store.RiskStates(), store.Positions(), store.Orders())
+	require.NoError(t, service.SetLimits(context.Background(), &limits))
+	return service, store
+}
+
+func buyOrder(userID uuid.UUID, market string, qty, price float64) *model.Order {
+	return model.NewOrder(userID, market, model.OrderSideBid, model.OrderTypeLimit, qty, &price, time.Now())
+}
+
+func TestService_CheckWithoutLimits(t *testing.T) {
+	store := memory.NewStore()
+	service := NewService(store.RiskLimits(), store.RiskStates(), store.Positions(), store.Orders())
+
+	order := buyOrder(uuid.New(), "KRW-BTC", 10, 100000000)
+	assert.NoError(t, service.Check(context.Background(), order))
+	assert.Equal(t, 10.0, order.Quantity)
+}
+
+func TestService_CheckRejectsViolations(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	service, store := newTestService(t, model.RiskLimits{
+		UserID:            userID,
+		MaxOrderNotional:  2000000,
+		MaxOpenPositions:  2,
+		MaxMarketExposure: 3000000,
+		MaxTotalExposure:  5000000,
+	})
+
+	err := service.Check(ctx, buyOrder(userID, "KRW-XRP", 1, 2500000))
+	assert.ErrorIs(t, err, ErrOrderTooLarge)
+	assert.ErrorContains(t, err, "2,500,000 KRW requested, 2,000,000 KRW allowed")
+
+	// 2,000,000 KRW in KRW-BTC and a resting 1,000,000 KRW buy in KRW-ETH
+	require.NoError(t, store.Positions().Create(ctx, model.NewPosition(userID, "KRW-BTC", model.PositionSideLong, 100000000, 0.02, time.Now())))
+	resting := buyOrder(userID, "KRW-ETH", 0.5, 2000000)
+	resting.Status = model.OrderStatusSubmitted
+	require.NoError(t, store.Orders().Create(ctx, resting))
+
+	exposure, err := service.Exposure(ctx, userID)
+	require.NoError(t, err)
+	assert.InDelta(t, 3000000, exposure.Total, 1e-6)
+	assert.Equal(t, 2, exposure.OpenPositions)
+
+	assert.ErrorIs(t, service.Check(ctx, buyOrder(userID, "KRW-XRP", 1, 10000)), ErrTooManyPositions)
+
+	position, err := store.Positions().ListByUser(ctx, userID)
+	require.NoError(t, err)
+	addToBTC := buyOrder(userID, "KRW-BTC", 0.015, 100000000)
+	addToBTC.PositionID = &position[0].ID
+	assert.ErrorIs(t, service.Check(ctx, addToBTC), ErrMarketExposure)
+
+	addToBTC.Quantity = 0.01
+	assert.NoError(t, service.Check(ctx, addToBTC))
+
+	// Sells always pass
+	sell := model.NewOrder(userID, "KRW-BTC", model.OrderSideAsk, model.OrderTypeMarket, 100, nil, time.Now())
+	assert.NoError(t, service.Check(ctx, sell))
+}
+
+func TestService_CheckDownsizes(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	service, store := newTestService(t, model.RiskLimits{
+		UserID:           userID,
+		MaxOrderNotional: 2000000,
+		MaxTotalExposure: 3000000,
+		Downsize:         true,
+	})
+
+	order := buyOrder(userID, "KRW-BTC", 0.05, 100000000)
+	require.NoError(t, service.Check(ctx, order))
+	assert.InDelta(t, 0.02, order.Quantity, 1e-12)
+
+	require.NoError(t, store.Positions().Create(ctx, model.NewPosition(userID, "KRW-ETH", model.PositionSideLong, 2000000, 1.4, time.Now())))
+	order = buyOrder(userID, "KRW-BTC", 0.05, 100000000)
+	require.NoError(t, service.Check(ctx, order))
+	assert.InDelta(t, 0.002, order.Quantity, 1e-12)
+
+	// Less than the exchange minimum is left
+	require.NoError(t, store.Positions().Create(ctx, model.NewPosition(userID, "KRW-XRP", model.PositionSideLong, 1000, 198, time.Now())))
+	assert.ErrorIs(t, service.Check(ctx, buyOrder(userID, "KRW-BTC", 0.05, 100000000)), ErrBelowMinimumOrder)
+}
+
+func TestService_CheckSkipsConcentrationWithoutMarketData(t *testing.T) {
+	userID := uuid.New()
+	service, _ := newTestService(t, model.RiskLimits{UserID: userID, MaxConcentration: 20})
+
+	order := buyOrder(userID, "KRW-BTC", 0.01, 100000000)
+	assert.NoError(t, service.Check(context.Background(), order))
+	assert.Equal(t, 0.01, order.Quantity)
+}
+
+func TestService_SetLimitsRejectsNegative(t *testing.T) {
+	store := memory.NewStore()
+	service := NewService(store.RiskLimits(), store.RiskStates(), store.Positions(), store.Orders())
+
+	err := service.SetLimits(context.Background(), &model.RiskLimits{UserID: uuid.New(), MaxTotalExposure: -1})
+	assert.ErrorIs(t, err, ErrInvalidLimits)
+}
+
+func TestService_CheckUsesTheClocksTradingDay(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	now := time.Date(2025, 3, 4, 12, 0, 0, 0, time.UTC)
+	clk := clock.NewFake(now)
+	store := memory.NewStore()
+	service := NewService(store.RiskLimits(), store.RiskStates(), store.Positions(), store.Orders()).WithClock(clk)
+
+	limits := model.RiskLimits{UserID: userID, DailyLossLimit: 100000, DayStart: "00:00", Timezone: "UTC"}
+	require.NoError(t, service.SetLimits(ctx, &limits))
+	assert.Equal(t, now, limits.UpdatedAt)
+
+	day, err := limits.TradingDay(now)
+	require.NoError(t, err)
+	require.NoError(t, store.RiskStates().Save(ctx, &model.RiskState{UserID: userID, Day: day, DayPnL: -150000, HaltedAt: &now}))
+	assert.ErrorIs(t, service.Check(ctx, buyOrder(userID, "KRW-BTC", 0.001, 100000000)), ErrDailyLossLimit)
+
+	clk.Advance(24 * time.Hour)
+	assert.NoError(t, service.Check(ctx, buyOrder(userID, "KRW-BTC", 0.001, 100000000)), "the halt ends with the trading day")
+}
@@ -2,20 +2,48 @@ package scheduler
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
 	"log"
+	"os"
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/gateway"
 	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
-	"github.com/sungminna/upbit-trading-platform/internal/upbit/quotation"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/pkg/recovery"
+)
+
+const (
+	// shardLease is how long a worker holds a shard without renewing it
+	shardLease = 90 * time.Second
+	// shardRenewInterval is how often sharded workers renew their lease, or
+	// try to claim a shard while they have none
+	shardRenewInterval = 30 * time.Second
+	// historyWindow is how far back collection of a market starts
+	historyWindow = 30 * 24 * time.Hour
+	// secondsHistoryWindow replaces historyWindow for 1s candles, which Upbit
+	// only keeps for a few months and which add up to millions per market
+	secondsHistoryWindow = time.Hour
 )
 
 // CandleCollector collects candle data from Upbit API
 type CandleCollector struct {
-	quotationClient *quotation.Client
+	quotationClient gateway.QuotationAPI
 	markets         []string
 	interval        model.CandleInterval
 	storage         CandleStorage
+	shards          repository.CollectorShardRepository // Optional; coordinates sharded collection
+	backfills       repository.CandleBackfillRepository // Optional; makes backfills resumable
+	depth           time.Duration                       // Of backfills; zero uses the interval's default
+	marketDepths    map[string]time.Duration            // Per-market overrides of depth
+	shardCount      int
+	workers         int
+	instance        string // Identifies this instance's workers in shard leases
+	now             func() time.Time
 	mu              sync.RWMutex
 	isRunning       bool
 	stopChan        chan struct{}
@@ -29,7 +57,7 @@ type CandleStorage interface {
 
 // NewCandleCollector creates a new candle collector
 func NewCandleCollector(
-	quotationClient *quotation.Client,
+	quotationClient gateway.QuotationAPI,
 	storage CandleStorage,
 	markets []string,
 	interval model.CandleInterval,
@@ -39,11 +67,42 @@ func NewCandleCollector(
 		markets:         markets,
 		interval:        interval,
 		storage:         storage,
+		now:             time.Now,
 		stopChan:        make(chan struct{}),
 	}
 }
 
-// Start starts the candle collector
+// WithSharding spreads the markets over shardCount shards by hash and collects
+// them with workers goroutines, each holding the lease on one shard at a time.
+// Instances configured with the same markets and shard count share the work;
+// spare workers stand by and take over shards whose owner stops renewing.
+func (cc *CandleCollector) WithSharding(shards repository.CollectorShardRepository, shardCount, workers int) *CandleCollector {
+	cc.shards = shards
+	cc.shardCount = max(shardCount, 1)
+	cc.workers = max(workers, 1)
+	cc.instance = instanceName()
+	return cc
+}
+
+// WithBackfillDepth sets how far back a market's backfill starts, by default
+// and for specific markets. Without it 1s candles are backfilled for an hour
+// and other intervals for 30 days.
+func (cc *CandleCollector) WithBackfillDepth(depth time.Duration, markets map[string]time.Duration) *CandleCollector {
+	cc.depth = depth
+	cc.marketDepths = markets
+	return cc
+}
+
+// WithBackfillProgress persists each market's backfill progress, so
+// interrupted backfills resume where they stopped and ranges already stored
+// are not fetched again
+func (cc *CandleCollector) WithBackfillProgress(backfills repository.CandleBackfillRepository) *CandleCollector {
+	cc.backfills = backfills
+	return cc
+}
+
+// Start starts a sharded collector's workers. An unsharded collector
+// collects historical data here; register its Job for periodic collection.
 func (cc *CandleCollector) Start(ctx context.Context) error {
 	cc.mu.Lock()
 	if cc.isRunning {
@@ -53,18 +112,35 @@ func (cc *CandleCollector) Start(ctx context.Context) error {
 	cc.isRunning = true
 	cc.mu.Unlock()
 
-	// Collect historical data on startup
-	log.Println("Collecting historical candle data...")
-	if err := cc.collectHistoricalData(ctx); err != nil {
-		log.Printf("Error collecting historical data: %v", err)
+	if cc.shards != nil {
+		log.Printf("Collecting candles of %d markets over %d shards with %d workers", len(cc.markets), cc.shardCount, cc.workers)
+		for i := 0; i < cc.workers; i++ {
+			go cc.runShardWorker(ctx, fmt.Sprintf("%s/%d", cc.instance, i))
+		}
+		return nil
 	}
 
-	// Start periodic collection
-	go cc.runPeriodic(ctx)
+	// Collect historical data on startup
+	log.Println("Collecting historical candle data...")
+	to := cc.now()
+	cc.collectHistoricalData(ctx, cc.markets, time.Time{}, to, nil)
 
 	return nil
 }
 
+// Job returns the job collecting the latest candles of an unsharded collector
+// at each interval boundary
+func (cc *CandleCollector) Job() Job {
+	return Job{
+		Name:     "candles-" + string(cc.interval),
+		Schedule: Every(cc.getCollectionInterval()),
+		Run: func(ctx context.Context, at time.Time) error {
+			pass := cc.collectLatestCandles(ctx, cc.markets, nil)
+			return pass.lastErr
+		},
+	}
+}
+
 // Stop stops the candle collector
 func (cc *CandleCollector) Stop() {
 	cc.mu.Lock()
@@ -78,73 +154,326 @@ func (cc *CandleCollector) Stop() {
 	cc.isRunning = false
 }
 
-// collectHistoricalData collects historical candle data
-func (cc *CandleCollector) collectHistoricalData(ctx context.Context) error {
-	// Collect last 30 days of data
-	to := time.Now()
-	from := to.Add(-30 * 24 * time.Hour)
+// Shards returns the progress of every shard of a sharded collector
+func (cc *CandleCollector) Shards(ctx context.Context) ([]*model.CollectorShard, error) {
+	if cc.shards == nil {
+		return nil, nil
+	}
+	return cc.shards.List(ctx, cc.interval)
+}
 
-	for _, market := range cc.markets {
+// ShardOf returns which of shards shards collects a market. The hash is
+// stable, so every instance agrees on it.
+func ShardOf(market string, shards int) int {
+	h := fnv.New32a()
+	h.Write([]byte(market))
+	return int(h.Sum32() % uint32(max(shards, 1)))
+}
+
+// collectionPass is the outcome of collecting a set of markets
+type collectionPass struct {
+	markets  int
+	candles  int
+	failures int
+	lastErr  error
+	aborted  bool // Stopped partway because the worker lost its shard
+}
+
+func (p *collectionPass) fail(err error) {
+	p.failures++
+	p.lastErr = err
+}
+
+// collectHistoricalData backfills the markets' candles up to to, from each
+// market's backfill depth or from, whichever is later. keep, if set, is
+// checked between markets and stops the pass when false.
+func (cc *CandleCollector) collectHistoricalData(ctx context.Context, markets []string, from, to time.Time, keep func() bool) collectionPass {
+	pass := collectionPass{markets: len(markets)}
+	for _, market := range markets {
+		if keep != nil && !keep() {
+			pass.aborted = true
+			return pass
+		}
 		log.Printf("Collecting historical data for %s...", market)
 
-		candles, err := cc.quotationClient.GetCandleRange(ctx, market, cc.interval, from, to)
+		start := to.Add(-cc.getHistoryWindow(market))
+		if from.After(start) {
+			start = from
+		}
+		saved, err := cc.backfill(ctx, market, start, to)
+		pass.candles += saved
 		if err != nil {
 			log.Printf("Error collecting historical data for %s: %v", market, err)
+			pass.fail(fmt.Errorf("%s: %w", market, err))
+			continue
+		}
+		log.Printf("Saved %d candles for %s", saved, market)
+
+		// Rate limiting - small delay between markets
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	return pass
+}
+
+// backfill saves a market's candles between from and to a page at a time,
+// returning how many were saved. With backfill progress configured, the part
+// of the range already stored is skipped and the cursor advances with each
+// page, so an interrupted backfill resumes where it stopped.
+func (cc *CandleCollector) backfill(ctx context.Context, market string, from, to time.Time) (int, error) {
+	progress := &model.CandleBackfill{Market: market, Interval: cc.interval, From: from, Cursor: from}
+	if cc.backfills != nil {
+		stored, err := cc.backfills.Get(ctx, market, cc.interval)
+		switch {
+		case err == nil && stored.Cursor.After(from) && !stored.From.After(to):
+			progress = stored
+		case err != nil && !errors.Is(err, repository.ErrNotFound):
+			return 0, fmt.Errorf("failed to load backfill progress: %w", err)
+		}
+	}
+
+	saved := 0
+	save := func(candles []model.Candle) error {
+		if err := cc.storage.SaveCandles(ctx, candles); err != nil {
+			return err
+		}
+		saved += len(candles)
+		return nil
+	}
+
+	// A deeper backfill than the stored one first fills in before it
+	if from.Before(progress.From) {
+		if err := cc.quotationClient.StreamCandleRange(ctx, market, cc.interval, from, progress.From, save); err != nil {
+			return saved, err
+		}
+		progress.From = from
+		if err := cc.saveBackfill(ctx, progress); err != nil {
+			return saved, err
+		}
+	}
+
+	if progress.Cursor.Before(from) {
+		progress.Cursor = from
+	}
+	err := cc.quotationClient.StreamCandleRange(ctx, market, cc.interval, progress.Cursor, to, func(candles []model.Candle) error {
+		if err := save(candles); err != nil {
+			return err
+		}
+		progress.Cursor = candles[len(candles)-1].Timestamp.Add(cc.interval.Duration())
+		return cc.saveBackfill(ctx, progress)
+	})
+	if err != nil {
+		return saved, err
+	}
+	if progress.Cursor.Before(to) {
+		progress.Cursor = to
+		return saved, cc.saveBackfill(ctx, progress)
+	}
+	return saved, nil
+}
+
+// saveBackfill records a market's backfill progress when it is persisted
+func (cc *CandleCollector) saveBackfill(ctx context.Context, progress *model.CandleBackfill) error {
+	if cc.backfills == nil {
+		return nil
+	}
+	progress.UpdatedAt = cc.now()
+	if err := cc.backfills.Save(ctx, progress); err != nil {
+		return fmt.Errorf("failed to save backfill progress: %w", err)
+	}
+	return nil
+}
+
+// collectLatestCandles collects the latest candle of each market. keep, if
+// set, is checked between markets and stops the pass when false.
+func (cc *CandleCollector) collectLatestCandles(ctx context.Context, markets []string, keep func() bool) collectionPass {
+	pass := collectionPass{markets: len(markets)}
+	for _, market := range markets {
+		if keep != nil && !keep() {
+			pass.aborted = true
+			return pass
+		}
+
+		candles, err := cc.quotationClient.GetCandles(ctx, market, cc.interval, cc.getCollectionCount())
+		if err != nil {
+			log.Printf("Error collecting candle for %s: %v", market, err)
+			pass.fail(fmt.Errorf("%s: %w", market, err))
 			continue
 		}
 
 		if len(candles) > 0 {
 			if err := cc.storage.SaveCandles(ctx, candles); err != nil {
-				log.Printf("Error saving candles for %s: %v", market, err)
+				log.Printf("Error saving candle for %s: %v", market, err)
+				pass.fail(fmt.Errorf("%s: %w", market, err))
 			} else {
-				log.Printf("Saved %d candles for %s", len(candles), market)
+				pass.candles += len(candles)
 			}
 		}
-
-		// Rate limiting - small delay between markets
-		time.Sleep(100 * time.Millisecond)
 	}
+	return pass
+}
 
-	return nil
+// shardWorker is one worker of a sharded collector
+type shardWorker struct {
+	owner string
+	shard *model.CollectorShard // Held, or nil while standing by
+	due   time.Time             // Of the next collection pass
 }
 
-// runPeriodic runs periodic candle collection
-func (cc *CandleCollector) runPeriodic(ctx context.Context) {
-	ticker := time.NewTicker(cc.getCollectionInterval())
+// runShardWorker claims a shard and collects it until the collector stops,
+// then releases it
+func (cc *CandleCollector) runShardWorker(ctx context.Context, owner string) {
+	w := &shardWorker{owner: owner}
+	defer func() {
+		if w.shard != nil {
+			if err := cc.shards.Release(context.Background(), cc.interval, w.shard.Shard, w.owner); err != nil {
+				log.Printf("Error releasing collector shard %d: %v", w.shard.Shard, err)
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(shardRenewInterval)
 	defer ticker.Stop()
 
 	for {
+		// A panicking pass is retried at the next tick; the lease is kept
+		// until it runs out
+		recovery.Do("collector.shard", func() { cc.work(ctx, w) })
+
 		select {
 		case <-ctx.Done():
 			return
 		case <-cc.stopChan:
 			return
 		case <-ticker.C:
-			cc.collectLatestCandles(ctx)
 		}
 	}
 }
 
-// collectLatestCandles collects the latest candles for all markets
-func (cc *CandleCollector) collectLatestCandles(ctx context.Context) {
-	for _, market := range cc.markets {
-		candles, err := cc.quotationClient.GetCandles(ctx, market, cc.interval, 1)
-		if err != nil {
-			log.Printf("Error collecting candle for %s: %v", market, err)
-			continue
+// work renews the worker's lease, or claims a shard, and collects the shard
+// when a pass is due. A newly claimed shard is first caught up from where its
+// last owner stopped.
+func (cc *CandleCollector) work(ctx context.Context, w *shardWorker) {
+	now := cc.now()
+	shard, err := cc.shards.Claim(ctx, cc.interval, cc.shardCount, w.owner, now, now.Add(shardLease))
+	if errors.Is(err, repository.ErrNotFound) {
+		w.shard = nil
+		return
+	}
+	if err != nil {
+		log.Printf("Error claiming a collector shard: %v", err)
+		return
+	}
+
+	claimed := w.shard == nil || w.shard.Shard != shard.Shard
+	w.shard = shard
+	markets := cc.shardMarkets(shard.Shard)
+	keep := func() bool { return cc.renew(ctx, w) }
+
+	if claimed {
+		log.Printf("Collector worker %s claimed shard %d/%d with %d markets", w.owner, shard.Shard, cc.shardCount, len(markets))
+		var from time.Time
+		if shard.LastCollectedAt != nil {
+			from = shard.LastCollectedAt.Add(-cc.interval.Duration())
 		}
+		w.due = now.Add(cc.getCollectionInterval())
+		cc.record(ctx, w, cc.collectHistoricalData(ctx, markets, from, now, keep))
+		return
+	}
 
-		if len(candles) > 0 {
-			if err := cc.storage.SaveCandles(ctx, candles); err != nil {
-				log.Printf("Error saving candle for %s: %v", market, err)
-			}
+	if now.Before(w.due) {
+		return
+	}
+	w.due = now.Add(cc.getCollectionInterval())
+	cc.record(ctx, w, cc.collectLatestCandles(ctx, markets, keep))
+}
+
+// renew extends the worker's lease once half of it is spent, reporting
+// whether the worker still holds its shard
+func (cc *CandleCollector) renew(ctx context.Context, w *shardWorker) bool {
+	if w.shard == nil {
+		return false
+	}
+	now := cc.now()
+	if now.Before(w.shard.LeaseUntil.Add(-shardLease / 2)) {
+		return true
+	}
+
+	shard, err := cc.shards.Claim(ctx, cc.interval, cc.shardCount, w.owner, now, now.Add(shardLease))
+	switch {
+	case err == nil && shard.Shard == w.shard.Shard:
+		w.shard = shard
+		return true
+	case err != nil && !errors.Is(err, repository.ErrNotFound):
+		// Keep collecting while the lease lasts
+		log.Printf("Error renewing collector shard %d: %v", w.shard.Shard, err)
+		return now.Before(w.shard.LeaseUntil)
+	default:
+		log.Printf("Collector worker %s lost shard %d", w.owner, w.shard.Shard)
+		w.shard = nil
+		return false
+	}
+}
+
+// record saves the progress of a completed pass over the worker's shard
+func (cc *CandleCollector) record(ctx context.Context, w *shardWorker, pass collectionPass) {
+	if pass.aborted || w.shard == nil {
+		return
+	}
+
+	now := cc.now()
+	shard := *w.shard
+	shard.LeaseUntil = now.Add(shardLease)
+	shard.Markets = pass.markets
+	shard.Candles = pass.candles
+	shard.Failures = pass.failures
+	shard.LastError = nil
+	if pass.lastErr != nil {
+		message := pass.lastErr.Error()
+		shard.LastError = &message
+	}
+	shard.LastCollectedAt = &now
+	shard.UpdatedAt = now
+
+	err := cc.shards.SaveProgress(ctx, &shard)
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		log.Printf("Collector worker %s lost shard %d", w.owner, shard.Shard)
+		w.shard = nil
+	case err != nil:
+		log.Printf("Error saving progress of collector shard %d: %v", shard.Shard, err)
+	default:
+		w.shard = &shard
+	}
+}
+
+// shardMarkets returns the markets a shard collects
+func (cc *CandleCollector) shardMarkets(shard int) []string {
+	var markets []string
+	for _, market := range cc.markets {
+		if ShardOf(market, cc.shardCount) == shard {
+			markets = append(markets, market)
 		}
 	}
+	return markets
+}
+
+// instanceName identifies this process in shard leases
+func instanceName() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "collector"
+	}
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), uuid.NewString()[:8])
 }
 
-// getCollectionInterval returns the collection interval based on candle interval
+// getCollectionInterval returns the collection interval based on candle
+// interval. Polling every second for 1s candles would exhaust the rate limit,
+// so they are collected a minute at a time.
 func (cc *CandleCollector) getCollectionInterval() time.Duration {
 	switch cc.interval {
+	case model.CandleInterval1s:
+		return 1 * time.Minute
 	case model.CandleInterval1m:
 		return 1 * time.Minute
 	case model.CandleInterval3m:
@@ -165,3 +494,26 @@ func (cc *CandleCollector) getCollectionInterval() time.Duration {
 		return 1 * time.Minute
 	}
 }
+
+// getCollectionCount returns how many of the latest candles each pass
+// collects: every candle closed since the previous pass
+func (cc *CandleCollector) getCollectionCount() int {
+	if d := cc.interval.Duration(); d > 0 && d < cc.getCollectionInterval() {
+		return int(cc.getCollectionInterval() / d)
+	}
+	return 1
+}
+
+// getHistoryWindow returns how far back collection of a market starts
+func (cc *CandleCollector) getHistoryWindow(market string) time.Duration {
+	if depth, ok := cc.marketDepths[market]; ok {
+		return depth
+	}
+	if cc.depth > 0 {
+		return cc.depth
+	}
+	if cc.interval == model.CandleInterval1s {
+		return secondsHistoryWindow
+	}
+	return historyWindow
+}
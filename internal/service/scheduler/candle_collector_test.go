@@ -0,0 +1,197 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/gateway"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+)
+
+// recordingCandles serves a candle per request and records the markets and
+// range starts requested
+type recordingCandles struct {
+	gateway.QuotationAPI
+	mu     sync.Mutex
+	ranges map[string]time.Time
+	latest []string
+}
+
+func (q *recordingCandles) StreamCandleRange(ctx context.Context, market string, interval model.CandleInterval, from, to time.Time, fn func([]model.Candle) error) error {
+	q.mu.Lock()
+	q.ranges[market] = from
+	q.mu.Unlock()
+	return fn([]model.Candle{{Market: market, Interval: interval, Timestamp: from}})
+}
+
+func (q *recordingCandles) GetCandles(ctx context.Context, market string, interval model.CandleInterval, count int) ([]model.Candle, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.latest = append(q.latest, market)
+	return []model.Candle{{Market: market, Interval: interval}}, nil
+}
+
+type discardCandles struct{}
+
+func (discardCandles) SaveCandles(ctx context.Context, candles []model.Candle) error { return nil }
+
+func (discardCandles) GetLatestCandle(ctx context.Context, market string, interval model.CandleInterval) (*model.Candle, error) {
+	return nil, nil
+}
+
+func TestShardOf(t *testing.T) {
+	counts := make([]int, 4)
+	for i := 0; i < 200; i++ {
+		market := fmt.Sprintf("KRW-C%d", i)
+		shard := ShardOf(market, 4)
+		require.Equal(t, shard, ShardOf(market, 4))
+		counts[shard]++
+	}
+	for _, count := range counts {
+		assert.Greater(t, count, 20)
+	}
+	assert.Zero(t, ShardOf("KRW-BTC", 0))
+}
+
+func TestCandleCollector_ShardsMarketsAcrossWorkers(t *testing.T) {
+	ctx := context.Background()
+	shards := memory.NewStore().CollectorShards()
+	quotes := &recordingCandles{ranges: make(map[string]time.Time)}
+	markets := []string{"KRW-BTC", "KRW-ETH", "KRW-XRP", "KRW-SOL", "KRW-ADA", "KRW-DOGE"}
+
+	clock := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	newCollector := func() *CandleCollector {
+		cc := NewCandleCollector(quotes, discardCandles{}, markets, model.CandleInterval1m).WithSharding(shards, 2, 1)
+		cc.now = func() time.Time { return clock }
+		return cc
+	}
+	a, b, spare := newCollector(), newCollector(), newCollector()
+	wa, wb, ws := &shardWorker{owner: "a"}, &shardWorker{owner: "b"}, &shardWorker{owner: "spare"}
+
+	// Each instance claims a shard and backfills its markets
+	a.work(ctx, wa)
+	b.work(ctx, wb)
+	require.NotNil(t, wa.shard)
+	require.NotNil(t, wb.shard)
+	assert.NotEqual(t, wa.shard.Shard, wb.shard.Shard)
+	assert.Len(t, quotes.ranges, len(markets))
+	for _, market := range a.shardMarkets(wa.shard.Shard) {
+		assert.Equal(t, clock.Add(-historyWindow), quotes.ranges[market])
+	}
+
+	// With every shard held the spare stands by
+	spare.work(ctx, ws)
+	assert.Nil(t, ws.shard)
+
+	// Latest candles are collected once a pass is due
+	clock = clock.Add(time.Minute)
+	a.work(ctx, wa)
+	b.work(ctx, wb)
+	assert.ElementsMatch(t, markets, quotes.latest)
+
+	progress, err := a.Shards(ctx)
+	require.NoError(t, err)
+	require.Len(t, progress, 2)
+	for _, shard := range progress {
+		assert.Equal(t, len(a.shardMarkets(shard.Shard)), shard.Markets)
+		assert.Equal(t, shard.Markets, shard.Candles)
+		assert.Equal(t, clock, *shard.LastCollectedAt)
+	}
+
+	// When a stops renewing, the spare takes over its shard and catches up
+	// from its last pass
+	clock = clock.Add(30 * time.Second)
+	b.work(ctx, wb)
+	clock = clock.Add(shardLease)
+	b.work(ctx, wb)
+	spare.work(ctx, ws)
+	require.NotNil(t, ws.shard)
+	assert.Equal(t, wa.shard.Shard, ws.shard.Shard)
+	for _, market := range spare.shardMarkets(ws.shard.Shard) {
+		assert.Equal(t, clock.Add(-shardLease-30*time.Second-time.Minute), quotes.ranges[market])
+	}
+}
+
+func TestCandleCollector_SecondCandles(t *testing.T) {
+	cc := NewCandleCollector(&recordingCandles{}, discardCandles{}, []string{"KRW-BTC"}, model.CandleInterval1s)
+	assert.Equal(t, time.Minute, cc.getCollectionInterval())
+	assert.Equal(t, 60, cc.getCollectionCount())
+	assert.Equal(t, secondsHistoryWindow, cc.getHistoryWindow("KRW-BTC"))
+
+	cc = NewCandleCollector(&recordingCandles{}, discardCandles{}, []string{"KRW-BTC"}, model.CandleInterval1h)
+	assert.Equal(t, 1, cc.getCollectionCount())
+	assert.Equal(t, historyWindow, cc.getHistoryWindow("KRW-BTC"))
+}
+
+// pagedCandles streams an hourly candle per hour of the range, two per page,
+// failing once failAfter pages have been passed
+type pagedCandles struct {
+	gateway.QuotationAPI
+	streams   [][2]time.Time
+	failAfter int
+}
+
+func (q *pagedCandles) StreamCandleRange(ctx context.Context, market string, interval model.CandleInterval, from, to time.Time, fn func([]model.Candle) error) error {
+	q.streams = append(q.streams, [2]time.Time{from, to})
+	var page []model.Candle
+	for ts := from; ts.Before(to); ts = ts.Add(time.Hour) {
+		page = append(page, model.Candle{Market: market, Interval: interval, Timestamp: ts})
+		if len(page) < 2 && ts.Add(time.Hour).Before(to) {
+			continue
+		}
+		if q.failAfter == 0 {
+			return fmt.Errorf("connection reset")
+		}
+		q.failAfter--
+		if err := fn(page); err != nil {
+			return err
+		}
+		page = nil
+	}
+	return nil
+}
+
+func TestCandleCollector_ResumesBackfill(t *testing.T) {
+	ctx := context.Background()
+	backfills := memory.NewStore().CandleBackfills()
+	quotes := &pagedCandles{failAfter: 2}
+	to := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	cc := NewCandleCollector(quotes, discardCandles{}, []string{"KRW-BTC"}, model.CandleInterval1h).
+		WithBackfillDepth(10*time.Hour, map[string]time.Duration{"KRW-ETH": 4 * time.Hour}).
+		WithBackfillProgress(backfills)
+	assert.Equal(t, 4*time.Hour, cc.getHistoryWindow("KRW-ETH"))
+
+	// Interrupted after two pages
+	pass := cc.collectHistoricalData(ctx, cc.markets, time.Time{}, to, nil)
+	assert.Equal(t, 4, pass.candles)
+	assert.Equal(t, 1, pass.failures)
+	progress, err := backfills.Get(ctx, "KRW-BTC", model.CandleInterval1h)
+	require.NoError(t, err)
+	assert.Equal(t, to.Add(-10*time.Hour), progress.From)
+	assert.Equal(t, to.Add(-6*time.Hour), progress.Cursor)
+
+	// Resumes at the cursor
+	quotes.failAfter = 10
+	pass = cc.collectHistoricalData(ctx, cc.markets, time.Time{}, to, nil)
+	assert.Equal(t, 6, pass.candles)
+	assert.Equal(t, [2]time.Time{to.Add(-6 * time.Hour), to}, quotes.streams[1])
+	progress, err = backfills.Get(ctx, "KRW-BTC", model.CandleInterval1h)
+	require.NoError(t, err)
+	assert.Equal(t, to, progress.Cursor)
+
+	// A deeper backfill fetches only what is older than the stored range
+	quotes.streams = nil
+	cc.WithBackfillDepth(12*time.Hour, nil)
+	pass = cc.collectHistoricalData(ctx, cc.markets, time.Time{}, to, nil)
+	assert.Equal(t, 2, pass.candles)
+	assert.Equal(t, [2]time.Time{to.Add(-12 * time.Hour), to.Add(-10 * time.Hour)}, quotes.streams[0])
+	progress, err = backfills.Get(ctx, "KRW-BTC", model.CandleInterval1h)
+	require.NoError(t, err)
+	assert.Equal(t, to.Add(-12*time.Hour), progress.From)
+}
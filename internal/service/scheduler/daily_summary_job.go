@@ -0,0 +1,222 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/gateway"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/pkg/cache"
+)
+
+const (
+	// summaryCheckInterval is how often due summaries are looked for
+	summaryCheckInterval = time.Minute
+	// summaryCatchUp is how late a summary may still be sent, e.g. after a
+	// restart. Older summaries are skipped.
+	summaryCatchUp = time.Hour
+	summaryKey     = "daily-summary:"
+)
+
+// SummaryNotifier delivers summaries; notification.Service satisfies it
+type SummaryNotifier interface {
+	Notify(ctx context.Context, notification *model.Notification) error
+	NotifyVia(ctx context.Context, channel string, notification *model.Notification) error
+}
+
+// DailySummary is a user's trading activity over one day
+type DailySummary struct {
+	UserID        uuid.UUID
+	From          time.Time
+	To            time.Time
+	RealizedPnL   float64 // Of positions closed during the day
+	UnrealizedPnL float64 // Of open positions at the time of the summary
+	OpenPositions int
+	Trades        int     // Executions during the day
+	TradedValue   float64 // KRW value of the executions
+	Fees          float64
+}
+
+// DailySummaryJob sends each user who enabled it a digest of the last day's
+// trading at their configured local time
+type DailySummaryJob struct {
+	settings        repository.NotificationSettingsRepository
+	positions       repository.PositionRepository
+	orders          repository.OrderRepository
+	executions      repository.OrderExecutionRepository
+	quotationClient gateway.QuotationAPI
+	notifier        SummaryNotifier
+	sent            cache.Cache // Marks sent summaries so instances don't send twice
+}
+
+// NewDailySummaryJob creates a new daily summary job
+func NewDailySummaryJob(
+	settings repository.NotificationSettingsRepository,
+	positions repository.PositionRepository,
+	orders repository.OrderRepository,
+	executions repository.OrderExecutionRepository,
+	quotationClient gateway.QuotationAPI,
+	notifier SummaryNotifier,
+	sent cache.Cache,
+) *DailySummaryJob {
+	return &DailySummaryJob{
+		settings:        settings,
+		positions:       positions,
+		orders:          orders,
+		executions:      executions,
+		quotationClient: quotationClient,
+		notifier:        notifier,
+		sent:            sent,
+	}
+}
+
+// Job returns the job sending due summaries every minute
+func (j *DailySummaryJob) Job() Job {
+	return Job{
+		Name:     "daily-summaries",
+		Schedule: Every(summaryCheckInterval),
+		Run:      j.SendDue,
+	}
+}
+
+// SendDue sends the summaries whose scheduled time has passed and that
+// weren't sent yet. A failure for one user doesn't stop the others.
+func (j *DailySummaryJob) SendDue(ctx context.Context, now time.Time) error {
+	settings, err := j.settings.ListDailySummaries(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, s := range settings {
+		// The most recent scheduled time at or before now
+		due, err := s.NextSummary(now.Add(-24 * time.Hour))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("user %s: %w", s.UserID, err))
+			continue
+		}
+		if due.After(now) || now.Sub(due) > summaryCatchUp {
+			continue
+		}
+
+		key := summaryKey + s.UserID.String() + ":" + strconv.FormatInt(due.Unix(), 10)
+		claimed, err := j.sent.SetNX(ctx, key, []byte{1}, 25*time.Hour)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("user %s: %w", s.UserID, err))
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := j.send(ctx, s, due, now); err != nil {
+			errs = append(errs, fmt.Errorf("user %s: %w", s.UserID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (j *DailySummaryJob) send(ctx context.Context, settings *model.NotificationSettings, due, now time.Time) error {
+	summary, err := j.Compile(ctx, settings.UserID, due.Add(-24*time.Hour), due)
+	if err != nil {
+		return err
+	}
+
+	n := summary.Notification(now)
+	if settings.SummaryChannel == "" {
+		return j.notifier.Notify(ctx, n)
+	}
+	return j.notifier.NotifyVia(ctx, settings.SummaryChannel, n)
+}
+
+// Compile summarizes a user's trading between from and to
+func (j *DailySummaryJob) Compile(ctx context.Context, userID uuid.UUID, from, to time.Time) (*DailySummary, error) {
+	summary := &DailySummary{UserID: userID, From: from, To: to}
+
+	positions, err := j.positions.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var markets []string
+	seen := make(map[string]bool)
+	for _, p := range positions {
+		switch {
+		case p.Status == model.PositionStatusOpen:
+			summary.OpenPositions++
+			if !seen[p.Market] {
+				seen[p.Market] = true
+				markets = append(markets, p.Market)
+			}
+		case p.ClosedAt != nil && !p.ClosedAt.Before(from) && p.ClosedAt.Before(to):
+			summary.RealizedPnL += p.RealizedPnL
+		}
+	}
+
+	if len(markets) > 0 {
+		tickers, err := j.quotationClient.GetTicker(ctx, markets)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get prices: %w", err)
+		}
+		prices := make(map[string]float64, len(tickers))
+		for _, ticker := range tickers {
+			prices[ticker.Market] = ticker.TradePrice
+		}
+		for _, p := range positions {
+			if price, ok := prices[p.Market]; ok && p.Status == model.PositionStatusOpen {
+				summary.UnrealizedPnL += p.CalculateUnrealizedPnL(price)
+			}
+		}
+	}
+
+	orders, err := j.orders.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, order := range orders {
+		// Executions are recorded when an order is updated, so orders last
+		// updated before the day have none in it
+		if order.ExecutedQuantity == 0 || order.UpdatedAt.Before(from) {
+			continue
+		}
+
+		executions, err := j.executions.ListByOrder(ctx, order.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range executions {
+			if e.CreatedAt.Before(from) || !e.CreatedAt.Before(to) {
+				continue
+			}
+			summary.Trades++
+			summary.TradedValue += e.Total
+			summary.Fees += e.Fee
+		}
+	}
+
+	return summary, nil
+}
+
+// Notification formats the summary as a notification sent at now
+func (s *DailySummary) Notification(now time.Time) *model.Notification {
+	message := fmt.Sprintf(
+		"Realized PnL: %+.0f KRW\nUnrealized PnL: %+.0f KRW (%d open positions)\nTrades: %d, %.0f KRW traded, %.0f KRW fees",
+		s.RealizedPnL, s.UnrealizedPnL, s.OpenPositions, s.Trades, s.TradedValue, s.Fees,
+	)
+
+	return model.NewNotification(s.UserID, model.NotificationDailySummary, "Daily summary", message, map[string]any{
+		"from":           s.From,
+		"to":             s.To,
+		"realized_pnl":   s.RealizedPnL,
+		"unrealized_pnl": s.UnrealizedPnL,
+		"open_positions": s.OpenPositions,
+		"trades":         s.Trades,
+		"traded_value":   s.TradedValue,
+		"fees":           s.Fees,
+	}, now)
+}
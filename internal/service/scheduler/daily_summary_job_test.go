@@ -0,0 +1,111 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+	"github.com/sungminna/upbit-trading-platform/pkg/cache"
+)
+
+type recordingNotifier struct {
+	mu   sync.Mutex
+	sent []*model.Notification
+	via  []string
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, notification *model.Notification) error {
+	return n.NotifyVia(ctx, "", notification)
+}
+
+func (n *recordingNotifier) NotifyVia(ctx context.Context, channel string, notification *model.Notification) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sent = append(n.sent, notification)
+	n.via = append(n.via, channel)
+	return nil
+}
+
+func TestDailySummaryJob_SendDue(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	userID := uuid.New()
+	kst := time.FixedZone("KST", 9*60*60)
+	due := time.Date(2026, 3, 2, 9, 0, 0, 0, kst)
+
+	settings := model.DefaultNotificationSettings(userID)
+	settings.DailySummary = true
+	settings.SummaryChannel = "telegram"
+	require.NoError(t, store.NotificationSettings().Save(ctx, settings))
+
+	open := model.NewPosition(userID, "KRW-BTC", model.PositionSideLong, 90000000, 0.01, time.Now())
+	require.NoError(t, store.Positions().Create(ctx, open))
+
+	closedAt := due.Add(-2 * time.Hour)
+	closed := model.NewPosition(userID, "KRW-ETH", model.PositionSideLong, 4000000, 1, time.Now())
+	closed.Status, closed.RealizedPnL, closed.ClosedAt = model.PositionStatusClosed, 50000, &closedAt
+	require.NoError(t, store.Positions().Create(ctx, closed))
+
+	// Closed before the day; not part of the summary
+	closedEarlier := due.Add(-30 * time.Hour)
+	old := model.NewPosition(userID, "KRW-XRP", model.PositionSideLong, 1000, 10, time.Now())
+	old.Status, old.RealizedPnL, old.ClosedAt = model.PositionStatusClosed, 999, &closedEarlier
+	require.NoError(t, store.Positions().Create(ctx, old))
+
+	price := 4050000.0
+	order := model.NewOrder(userID, "KRW-ETH", model.OrderSideAsk, model.OrderTypeLimit, 1, &price, time.Now())
+	order.ExecutedQuantity, order.Status, order.UpdatedAt = 1, model.OrderStatusFilled, closedAt
+	require.NoError(t, store.Orders().Create(ctx, order))
+	execution := model.NewOrderExecution(order.ID, price, 1, 2025, time.Now())
+	execution.CreatedAt = closedAt
+	require.NoError(t, store.Executions().Create(ctx, execution))
+
+	notifier := &recordingNotifier{}
+	prices := &stubQuotation{prices: map[string]float64{"KRW-BTC": 100000000}}
+	job := NewDailySummaryJob(store.NotificationSettings(), store.Positions(), store.Orders(), store.Executions(), prices, notifier, cache.NewMemoryCache())
+
+	// Not due yet
+	require.NoError(t, job.SendDue(ctx, due.Add(-time.Minute)))
+	assert.Empty(t, notifier.sent)
+
+	require.NoError(t, job.SendDue(ctx, due.Add(time.Minute)))
+	// Sent once per day
+	require.NoError(t, job.SendDue(ctx, due.Add(2*time.Minute)))
+	require.Len(t, notifier.sent, 1)
+	assert.Equal(t, "telegram", notifier.via[0])
+
+	data := notifier.sent[0].Data
+	assert.Equal(t, model.NotificationDailySummary, notifier.sent[0].Type)
+	assert.InDelta(t, 50000, data["realized_pnl"], 1e-6)
+	assert.InDelta(t, 100000, data["unrealized_pnl"], 1e-6)
+	assert.Equal(t, 1, data["open_positions"])
+	assert.Equal(t, 1, data["trades"])
+	assert.InDelta(t, 2025, data["fees"], 1e-6)
+
+	// Summaries missed by more than the catch-up window are skipped
+	require.NoError(t, job.SendDue(ctx, due.Add(24*time.Hour+2*time.Hour)))
+	assert.Len(t, notifier.sent, 1)
+}
+
+func TestNotificationSettings_NextSummary(t *testing.T) {
+	settings := model.DefaultNotificationSettings(uuid.New())
+
+	// 09:00 KST is 00:00 UTC
+	next, err := settings.NextSummary(time.Date(2026, 3, 1, 23, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.True(t, next.Equal(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)))
+
+	next, err = settings.NextSummary(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.True(t, next.Equal(time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)))
+
+	settings.Timezone = "Mars/Olympus"
+	_, err = settings.NextSummary(time.Now())
+	assert.Error(t, err)
+}
@@ -0,0 +1,16 @@
+package scheduler
+
+var (
+	ErrInvalidCron  = &SchedulerError{message: "invalid cron expression"}
+	ErrInvalidJob   = &SchedulerError{message: "a job needs a name, a schedule and a run function"}
+	ErrDuplicateJob = &SchedulerError{message: "a job with this name is already registered"}
+)
+
+// SchedulerError represents a scheduler error
+type SchedulerError struct {
+	message string
+}
+
+func (e *SchedulerError) Error() string {
+	return e.message
+}
@@ -0,0 +1,38 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// retentionJitter spreads the cleanups of instances sharing the schedule
+const retentionJitter = 10 * time.Minute
+
+// RetentionJob periodically removes expired market data. ClickHouse also
+// drops expired rows during merges, but merges of old partitions can be rare.
+type RetentionJob struct {
+	maintenance repository.MarketDataMaintenance
+	interval    time.Duration
+}
+
+// NewRetentionJob creates a new retention job running every interval
+func NewRetentionJob(maintenance repository.MarketDataMaintenance, interval time.Duration) *RetentionJob {
+	return &RetentionJob{
+		maintenance: maintenance,
+		interval:    interval,
+	}
+}
+
+// Job returns the job cleaning up every table
+func (j *RetentionJob) Job() Job {
+	return Job{
+		Name:     "market-data-retention",
+		Schedule: Every(j.interval),
+		Jitter:   retentionJitter,
+		Run: func(ctx context.Context, at time.Time) error {
+			return j.maintenance.Cleanup(ctx, "")
+		},
+	}
+}
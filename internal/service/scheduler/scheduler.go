@@ -0,0 +1,199 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sungminna/upbit-trading-platform/pkg/recovery"
+)
+
+// Job is periodic work run by a Scheduler
+type Job struct {
+	Name     string
+	Schedule Schedule
+	// Jitter delays each run by a random duration up to Jitter so instances
+	// sharing a schedule don't all run at once
+	Jitter time.Duration
+	// Run does the work. at is the scheduled time of the run, before jitter.
+	Run func(ctx context.Context, at time.Time) error
+}
+
+// JobStatus reports a registered job's schedule and recent runs
+type JobStatus struct {
+	Name     string     `json:"name"`
+	Schedule string     `json:"schedule"`
+	Running  bool       `json:"running"`
+	NextRun  *time.Time `json:"next_run,omitempty"`
+	LastRun  *time.Time `json:"last_run,omitempty"` // When the last finished run started
+	// LastDurationMs is how long the last finished run took
+	LastDurationMs int64   `json:"last_duration_ms"`
+	LastError      *string `json:"last_error,omitempty"`
+	Runs           int     `json:"runs"`
+	Failures       int     `json:"failures"`
+	Skipped        int     `json:"skipped"` // Runs skipped because the previous one was still running
+}
+
+// Scheduler runs registered jobs on their schedules. A run that comes due
+// while the job's previous run is still going is skipped, so runs of a job
+// never overlap.
+type Scheduler struct {
+	jobs      map[string]*scheduledJob
+	now       func() time.Time
+	ctx       context.Context // Of Start, for jobs registered while running
+	mu        sync.Mutex
+	isRunning bool
+	stopChan  chan struct{}
+}
+
+// scheduledJob is a registered job and its status. Guarded by the
+// scheduler's mutex.
+type scheduledJob struct {
+	job    Job
+	status JobStatus
+}
+
+// NewScheduler creates a new scheduler
+func NewScheduler() *Scheduler {
+	return &Scheduler{
+		jobs:     make(map[string]*scheduledJob),
+		now:      time.Now,
+		stopChan: make(chan struct{}),
+	}
+}
+
+// Register adds a job. Jobs registered while the scheduler is running start
+// right away.
+func (s *Scheduler) Register(job Job) error {
+	if job.Name == "" || job.Schedule == nil || job.Run == nil || job.Jitter < 0 {
+		return ErrInvalidJob
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobs[job.Name]; exists {
+		return fmt.Errorf("%w: %s", ErrDuplicateJob, job.Name)
+	}
+	j := &scheduledJob{job: job, status: JobStatus{Name: job.Name, Schedule: job.Schedule.String()}}
+	s.jobs[job.Name] = j
+
+	if s.isRunning {
+		go s.run(s.ctx, j)
+	}
+	return nil
+}
+
+// Start starts running the registered jobs
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.isRunning {
+		return
+	}
+	s.isRunning = true
+	s.ctx = ctx
+
+	for _, j := range s.jobs {
+		go s.run(ctx, j)
+	}
+}
+
+// Stop stops scheduling runs. Runs in progress finish on their own.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.isRunning {
+		return
+	}
+
+	close(s.stopChan)
+	s.isRunning = false
+}
+
+// Statuses returns the status of every registered job, by name
+func (s *Scheduler) Statuses() []JobStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]JobStatus, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		statuses = append(statuses, j.status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// run waits for each of a job's scheduled times and starts a run
+func (s *Scheduler) run(ctx context.Context, j *scheduledJob) {
+	for {
+		now := s.now()
+		at := j.job.Schedule.Next(now)
+		if at.IsZero() {
+			log.Printf("Job %s has no future runs", j.job.Name)
+			return
+		}
+		s.mu.Lock()
+		j.status.NextRun = &at
+		s.mu.Unlock()
+
+		delay := at.Sub(now)
+		if j.job.Jitter > 0 {
+			delay += rand.N(j.job.Jitter)
+		}
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-s.stopChan:
+			timer.Stop()
+			return
+		case <-timer.C:
+			s.start(ctx, j, at)
+		}
+	}
+}
+
+// start runs a job in the background unless its previous run is still going
+func (s *Scheduler) start(ctx context.Context, j *scheduledJob, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if j.status.Running {
+		j.status.Skipped++
+		log.Printf("Skipping job %s: the previous run is still going", j.job.Name)
+		return
+	}
+	j.status.Running = true
+
+	go func() {
+		started := s.now()
+		// A panicking run fails like an erroring one, so the job runs again
+		err := recovery.Call("job."+j.job.Name, func() error { return j.job.Run(ctx, at) })
+		finished := s.now()
+		if err != nil {
+			log.Printf("Error running job %s: %v", j.job.Name, err)
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		j.status.Running = false
+		j.status.Runs++
+		j.status.LastRun = &started
+		j.status.LastDurationMs = finished.Sub(started).Milliseconds()
+		j.status.LastError = nil
+		if err != nil {
+			message := err.Error()
+			j.status.Failures++
+			j.status.LastError = &message
+		}
+	}()
+}
@@ -0,0 +1,54 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler_RegisterRejectsInvalidJobs(t *testing.T) {
+	s := NewScheduler()
+	run := func(ctx context.Context, at time.Time) error { return nil }
+
+	assert.ErrorIs(t, s.Register(Job{Schedule: Every(time.Minute), Run: run}), ErrInvalidJob)
+	assert.ErrorIs(t, s.Register(Job{Name: "job", Run: run}), ErrInvalidJob)
+	require.NoError(t, s.Register(Job{Name: "job", Schedule: Every(time.Minute), Run: run}))
+	assert.ErrorIs(t, s.Register(Job{Name: "job", Schedule: Every(time.Minute), Run: run}), ErrDuplicateJob)
+}
+
+func TestScheduler_SkipsOverlappingRuns(t *testing.T) {
+	s := NewScheduler()
+	release := make(chan struct{})
+	started := make(chan time.Time, 10)
+	require.NoError(t, s.Register(Job{
+		Name:     "slow",
+		Schedule: Every(10 * time.Millisecond),
+		Run: func(ctx context.Context, at time.Time) error {
+			started <- at
+			<-release
+			return errors.New("failed")
+		},
+	}))
+
+	s.Start(context.Background())
+	defer s.Stop()
+
+	at := <-started
+	assert.Zero(t, at.UnixNano()%int64(10*time.Millisecond))
+	assert.Eventually(t, func() bool { return s.Statuses()[0].Skipped >= 2 }, time.Second, 5*time.Millisecond)
+	assert.Empty(t, started)
+
+	close(release)
+	assert.Eventually(t, func() bool { return s.Statuses()[0].Runs >= 1 }, time.Second, 5*time.Millisecond)
+	status := s.Statuses()[0]
+	assert.Equal(t, "slow", status.Name)
+	assert.Equal(t, "@every 10ms", status.Schedule)
+	assert.Equal(t, status.Runs, status.Failures)
+	require.NotNil(t, status.LastError)
+	assert.Equal(t, "failed", *status.LastError)
+	assert.NotNil(t, status.NextRun)
+}
@@ -0,0 +1,174 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/gateway"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// SnapshotJob records every user's balances, open positions and equity once per period
+type SnapshotJob struct {
+	apiKeys         repository.UserAPIKeyRepository
+	positions       repository.PositionRepository
+	snapshots       repository.SnapshotRepository
+	quotationClient gateway.QuotationAPI
+	newClient       gateway.ExchangeClientFactory
+	period          model.SnapshotPeriod
+}
+
+// NewSnapshotJob creates a new snapshot job for the given period
+func NewSnapshotJob(
+	apiKeys repository.UserAPIKeyRepository,
+	positions repository.PositionRepository,
+	snapshots repository.SnapshotRepository,
+	quotationClient gateway.QuotationAPI,
+	newClient gateway.ExchangeClientFactory,
+	period model.SnapshotPeriod,
+) *SnapshotJob {
+	return &SnapshotJob{
+		apiKeys:         apiKeys,
+		positions:       positions,
+		snapshots:       snapshots,
+		quotationClient: quotationClient,
+		newClient:       newClient,
+		period:          period,
+	}
+}
+
+// Job returns the job taking snapshots at each period boundary (UTC midnight
+// for daily snapshots)
+func (j *SnapshotJob) Job() Job {
+	return Job{
+		Name:     string(j.period) + "-snapshots",
+		Schedule: Every(j.period.Duration()),
+		Run:      j.TakeSnapshots,
+	}
+}
+
+// TakeSnapshots snapshots every user with an active API key. A failure for
+// one user doesn't stop the others; all failures are returned together.
+func (j *SnapshotJob) TakeSnapshots(ctx context.Context, takenAt time.Time) error {
+	userIDs, err := j.apiKeys.ListActiveUserIDs(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, userID := range userIDs {
+		if err := j.takeSnapshot(ctx, userID, takenAt); err != nil {
+			errs = append(errs, fmt.Errorf("user %s: %w", userID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (j *SnapshotJob) takeSnapshot(ctx context.Context, userID uuid.UUID, takenAt time.Time) error {
+	key, err := j.apiKeys.GetActiveByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to load API key: %w", err)
+	}
+
+	accounts, err := j.newClient(key.AccessKey, key.SecretKey).GetAccounts(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get accounts: %w", err)
+	}
+
+	positions, err := j.positions.ListByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	// Price every held coin and open position with a single ticker request
+	var markets []string
+	seen := make(map[string]bool)
+	addMarket := func(market string) {
+		if !seen[market] {
+			seen[market] = true
+			markets = append(markets, market)
+		}
+	}
+	for _, account := range accounts {
+		if account.Currency != "KRW" {
+			addMarket("KRW-" + account.Currency)
+		}
+	}
+	for _, position := range positions {
+		if position.Status == model.PositionStatusOpen {
+			addMarket(position.Market)
+		}
+	}
+
+	prices := make(map[string]float64, len(markets))
+	if len(markets) > 0 {
+		tickers, err := j.quotationClient.GetTicker(ctx, markets)
+		if err != nil {
+			return fmt.Errorf("failed to get prices: %w", err)
+		}
+		for _, ticker := range tickers {
+			prices[ticker.Market] = ticker.TradePrice
+		}
+	}
+
+	balances := make([]model.BalanceSnapshot, 0, len(accounts))
+	for _, account := range accounts {
+		b := model.BalanceSnapshot{
+			Currency:    account.Currency,
+			Balance:     parseFloat(account.Balance),
+			Locked:      parseFloat(account.Locked),
+			AvgBuyPrice: parseFloat(account.AvgBuyPrice),
+			Price:       1,
+		}
+		if account.Currency != "KRW" {
+			// Coins without a KRW market (e.g. delisted) are valued at cost
+			price, ok := prices["KRW-"+account.Currency]
+			if !ok {
+				price = b.AvgBuyPrice
+			}
+			b.Price = price
+		}
+		b.Value = (b.Balance + b.Locked) * b.Price
+		balances = append(balances, b)
+	}
+
+	var positionSnapshots []model.PositionSnapshot
+	for _, position := range positions {
+		if position.Status != model.PositionStatusOpen {
+			continue
+		}
+
+		markPrice, ok := prices[position.Market]
+		if !ok {
+			markPrice = position.EntryPrice
+		}
+		positionSnapshots = append(positionSnapshots, model.PositionSnapshot{
+			PositionID:    position.ID,
+			Market:        position.Market,
+			Side:          position.Side,
+			Quantity:      position.Quantity,
+			EntryPrice:    position.EntryPrice,
+			MarkPrice:     markPrice,
+			UnrealizedPnL: position.CalculateUnrealizedPnL(markPrice),
+			RealizedPnL:   position.RealizedPnL,
+		})
+	}
+
+	snapshot := model.NewAccountSnapshot(userID, j.period, takenAt, balances, positionSnapshots, takenAt)
+	return j.snapshots.Create(ctx, snapshot)
+}
+
+// nextPeriodStart returns the first period boundary after now, aligned to UTC
+func nextPeriodStart(now time.Time, period time.Duration) time.Time {
+	return now.UTC().Truncate(period).Add(period)
+}
+
+func parseFloat(s string) float64 {
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
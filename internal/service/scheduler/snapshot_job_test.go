@@ -0,0 +1,88 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/gateway"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/exchange"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/quotation"
+)
+
+// stubQuotation serves fixed ticker prices
+type stubQuotation struct {
+	gateway.QuotationAPI
+	prices map[string]float64
+}
+
+func (q *stubQuotation) GetTicker(ctx context.Context, markets []string) ([]quotation.Ticker, error) {
+	var tickers []quotation.Ticker
+	for _, market := range markets {
+		if price, ok := q.prices[market]; ok {
+			tickers = append(tickers, quotation.Ticker{Market: market, TradePrice: price})
+		}
+	}
+	return tickers, nil
+}
+
+// stubExchange serves fixed account balances
+type stubExchange struct {
+	gateway.ExchangeAPI
+	accounts []exchange.Account
+}
+
+func (e *stubExchange) GetAccounts(ctx context.Context) ([]exchange.Account, error) {
+	return e.accounts, nil
+}
+
+func TestSnapshotJob_TakeSnapshots(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	userID := uuid.New()
+	require.NoError(t, store.APIKeys().Create(ctx, model.NewUserAPIKey(userID, "access", "secret", "", time.Now())))
+
+	position := model.NewPosition(userID, "KRW-BTC", model.PositionSideLong, 90000000, 0.01, time.Now())
+	require.NoError(t, store.Positions().Create(ctx, position))
+
+	accounts := []exchange.Account{
+		{Currency: "KRW", Balance: "500000", Locked: "100000", AvgBuyPrice: "0"},
+		{Currency: "BTC", Balance: "0.01", Locked: "0", AvgBuyPrice: "90000000"},
+		{Currency: "OLD", Balance: "10", Locked: "0", AvgBuyPrice: "1000"}, // No KRW market
+	}
+	newClient := func(accessKey, secretKey string) gateway.ExchangeAPI {
+		return &stubExchange{accounts: accounts}
+	}
+	prices := &stubQuotation{prices: map[string]float64{"KRW-BTC": 100000000}}
+
+	job := NewSnapshotJob(store.APIKeys(), store.Positions(), store.Snapshots(), prices, newClient, model.SnapshotPeriodDaily)
+	takenAt := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, job.TakeSnapshots(ctx, takenAt))
+	// Running again for the same period keeps the first snapshot
+	require.NoError(t, job.TakeSnapshots(ctx, takenAt))
+
+	snapshots, err := store.Snapshots().ListByUser(ctx, userID, model.SnapshotPeriodDaily, takenAt, takenAt.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+
+	s := snapshots[0]
+	assert.InDelta(t, 600000, s.CashBalance, 1e-6)
+	assert.InDelta(t, 1000000+10000, s.HoldingsValue, 1e-6)
+	assert.InDelta(t, 1610000, s.Equity, 1e-6)
+	assert.InDelta(t, 100000, s.UnrealizedPnL, 1e-6)
+	require.Len(t, s.Positions, 1)
+	assert.Equal(t, position.ID, s.Positions[0].PositionID)
+	assert.InDelta(t, 100000000, s.Positions[0].MarkPrice, 1e-6)
+}
+
+func TestNextPeriodStart(t *testing.T) {
+	now := time.Date(2026, 3, 4, 15, 30, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC), nextPeriodStart(now, model.SnapshotPeriodDaily.Duration()))
+	assert.Equal(t, time.Date(2026, 3, 4, 16, 0, 0, 0, time.UTC), nextPeriodStart(now, model.SnapshotPeriodHourly.Duration()))
+}
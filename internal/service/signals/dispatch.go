@@ -0,0 +1,367 @@
+package signals
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/trading"
+)
+
+// signal is a received signal resolved against its source
+type signal struct {
+	action   model.SignalAction // open or close
+	strategy string
+	market   string
+	amount   float64 // KRW for opens; zero for the subscription's default
+}
+
+// HandleWebhook authenticates a webhook signal by its source's token and
+// carries it out. Signals that aren't JSON alerts are logged and rejected.
+func (s *Service) HandleWebhook(ctx context.Context, token string, payload json.RawMessage) ([]*model.SignalLog, error) {
+	source, err := s.sources.GetByTokenHash(ctx, hashToken(token))
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && (!source.Active || source.Type != model.SignalSourceWebhook)) {
+		return nil, ErrInvalidToken
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var alert Alert
+	if err := json.Unmarshal(payload, &alert); err != nil {
+		entry := model.NewSignalLog(source.ID, json.RawMessage(`{}`), time.Now())
+		s.reject(ctx, entry, ErrInvalidSignal)
+		return nil, ErrInvalidSignal
+	}
+	return s.Publish(ctx, source, alert, payload)
+}
+
+// HandleChannelPost carries out a post to a Telegram channel as a signal of
+// every source reading the channel. Posts that aren't signals are ignored.
+func (s *Service) HandleChannelPost(ctx context.Context, chatID int64, text string) {
+	alert, ok := parseText(text)
+	if !ok {
+		return
+	}
+	sources, err := s.sources.ListByTelegramChat(ctx, chatID)
+	if err != nil {
+		log.Printf("Error loading signal sources of Telegram chat %d: %v", chatID, err)
+		return
+	}
+
+	payload, _ := json.Marshal(alert)
+	for _, source := range sources {
+		if _, err := s.Publish(ctx, source, alert, payload); err != nil {
+			log.Printf("Error handling signal of source %s: %v", source.ID, err)
+		}
+	}
+}
+
+// Publish carries out a signal of a source for each of its active
+// subscriptions and returns what it did for them. Internal producers, e.g.
+// indicators, publish their signals here too.
+func (s *Service) Publish(ctx context.Context, source *model.SignalSource, alert Alert, payload json.RawMessage) ([]*model.SignalLog, error) {
+	now := time.Now()
+	entry := model.NewSignalLog(source.ID, payload, now)
+	s.touch(ctx, source, now)
+
+	sig, err := resolve(source, alert)
+	entry.Action, entry.Strategy, entry.Market = sig.action, sig.strategy, sig.market
+	if err != nil {
+		s.reject(ctx, entry, err)
+		return nil, err
+	}
+
+	subscriptions, err := s.subscriptions.ListBySource(ctx, source.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load subscriptions: %w", err)
+	}
+
+	var logs []*model.SignalLog
+	for _, sub := range subscriptions {
+		if !follows(sub, sig) {
+			continue
+		}
+		l := *entry
+		l.ID = uuid.New()
+		l.SubscriptionID, l.UserID = &sub.ID, &sub.UserID
+		l.OrderIDs = []uuid.UUID{}
+		s.follow(ctx, sub, sig, &l)
+		s.record(ctx, &l)
+		logs = append(logs, &l)
+	}
+	if len(logs) == 0 {
+		s.record(ctx, entry)
+		logs = append(logs, entry)
+	}
+	return logs, nil
+}
+
+// resolve checks a signal against its source, running named strategies
+func resolve(source *model.SignalSource, alert Alert) (signal, error) {
+	sig := signal{strategy: alert.Strategy, amount: alert.Amount}
+	action, err := parseAction(alert)
+	if err != nil {
+		return sig, err
+	}
+	sig.action = action
+	ticker := alert.Ticker
+
+	if action == model.SignalActionStrategy {
+		strategy, ok := source.Strategy(alert.Strategy)
+		if !ok {
+			return sig, ErrUnknownStrategy
+		}
+		sig.action = strategy.Action
+		if strategy.Market != "" {
+			ticker = strategy.Market
+		}
+		if strategy.Amount > 0 && alert.Amount == 0 {
+			sig.amount = strategy.Amount
+		}
+	}
+
+	market, err := ParseMarket(ticker)
+	if err != nil {
+		return sig, err
+	}
+	sig.market = market
+	if len(source.Markets) > 0 && !slices.Contains(source.Markets, market) {
+		return sig, ErrMarketNotAllowed
+	}
+	if sig.amount < 0 {
+		return sig, ErrInvalidAmount
+	}
+	return sig, nil
+}
+
+// follows reports whether a subscription follows a signal
+func follows(sub *model.SignalSubscription, sig signal) bool {
+	return (sub.Market == "" || sub.Market == sig.market) &&
+		(sub.Strategy == "" || sub.Strategy == sig.strategy)
+}
+
+// follow places a subscription's orders for a signal within its risk caps
+// and records the outcome in entry. Signals for the same subscription are
+// handled one at a time so caps can't be raced past.
+func (s *Service) follow(ctx context.Context, sub *model.SignalSubscription, sig signal, entry *model.SignalLog) {
+	lock, _ := s.locks.LoadOrStore(sub.ID, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	var orders []*model.Order
+	var err error
+	switch sig.action {
+	case model.SignalActionOpen:
+		orders, err = s.open(ctx, sub, sig)
+	case model.SignalActionClose:
+		orders, err = s.close(ctx, sub, sig.market)
+	}
+	for _, order := range orders {
+		entry.OrderIDs = append(entry.OrderIDs, order.ID)
+	}
+
+	var signalErr *SignalError
+	switch {
+	case err == nil:
+		entry.Status = model.SignalLogExecuted
+	case len(orders) > 0:
+		// Some positions closed before one failed
+		entry.Status = model.SignalLogExecuted
+		entry.Error = err.Error()
+	case errors.As(err, &signalErr):
+		entry.Status = model.SignalLogRejected
+		entry.Error = err.Error()
+	default:
+		entry.Status = model.SignalLogFailed
+		entry.Error = err.Error()
+	}
+}
+
+// open buys the signal's KRW amount of its market at the current price, if
+// the subscription's caps allow it
+func (s *Service) open(ctx context.Context, sub *model.SignalSubscription, sig signal) ([]*model.Order, error) {
+	amount := sig.amount
+	if amount == 0 {
+		amount = sub.OpenAmount
+	}
+	if amount < model.MinOrderAmount {
+		return nil, ErrInvalidAmount
+	}
+	if sub.MaxOrderAmount > 0 && amount > sub.MaxOrderAmount {
+		return nil, ErrOrderCap
+	}
+	if err := s.checkDailyOrders(ctx, sub); err != nil {
+		return nil, err
+	}
+	if sub.MaxPositionAmount > 0 {
+		exposure, err := s.exposure(ctx, sub)
+		if err != nil {
+			return nil, err
+		}
+		if exposure+amount > sub.MaxPositionAmount {
+			return nil, ErrPositionCap
+		}
+	}
+
+	tickers, err := s.tickers.GetTicker(ctx, []string{sig.market})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get price: %w", err)
+	}
+	if len(tickers) == 0 || tickers[0].TradePrice <= 0 {
+		return nil, fmt.Errorf("no price for %s", sig.market)
+	}
+	price := tickers[0].TradePrice
+
+	order, err := s.placer.PlaceOrder(ctx, sub.UserID, trading.PlaceOrderRequest{
+		Market:   sig.market,
+		Side:     model.OrderSideBid,
+		Type:     model.OrderTypeMarket,
+		Quantity: amount / price,
+		Price:    &price,
+		Source:   model.OrderSourceSignal,
+		SourceID: &sub.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []*model.Order{order}, nil
+}
+
+// close sells the open positions the subscription opened in market. Closes
+// aren't capped, as they only reduce risk. Positions already sold are kept
+// if a later one fails.
+func (s *Service) close(ctx context.Context, sub *model.SignalSubscription, market string) ([]*model.Order, error) {
+	positions, _, err := s.holdings(ctx, sub)
+	if err != nil {
+		return nil, err
+	}
+
+	var orders []*model.Order
+	for _, position := range positions {
+		if position.Market != market {
+			continue
+		}
+		order, err := s.placer.PlaceOrder(ctx, sub.UserID, trading.PlaceOrderRequest{
+			Market:     market,
+			Side:       model.OrderSideAsk,
+			Type:       model.OrderTypeMarket,
+			Quantity:   position.Quantity,
+			PositionID: &position.ID,
+			Source:     model.OrderSourceSignal,
+			SourceID:   &sub.ID,
+		})
+		if err != nil {
+			return orders, err
+		}
+		orders = append(orders, order)
+	}
+	if len(orders) == 0 {
+		return nil, ErrNoPosition
+	}
+	return orders, nil
+}
+
+// checkDailyOrders rejects orders past the subscription's daily cap
+func (s *Service) checkDailyOrders(ctx context.Context, sub *model.SignalSubscription) error {
+	if sub.MaxDailyOrders == 0 {
+		return nil
+	}
+	day := time.Now().UTC().Truncate(24 * time.Hour)
+	count, err := s.logs.CountOrders(ctx, sub.ID, day)
+	if err != nil {
+		return err
+	}
+	if count >= sub.MaxDailyOrders {
+		return ErrDailyOrderCap
+	}
+	return nil
+}
+
+// exposure returns the KRW the subscription has committed: the cost of the
+// positions it opened that are still open and the funds of its open buys
+func (s *Service) exposure(ctx context.Context, sub *model.SignalSubscription) (float64, error) {
+	positions, pending, err := s.holdings(ctx, sub)
+	if err != nil {
+		return 0, err
+	}
+
+	var total float64
+	for _, position := range positions {
+		total += position.EntryPrice * position.Quantity
+	}
+	for _, order := range pending {
+		_, funds := order.Funds()
+		total += funds
+	}
+	return total, nil
+}
+
+// holdings returns the open positions a subscription's orders opened and
+// its buys that haven't filled yet
+func (s *Service) holdings(ctx context.Context, sub *model.SignalSubscription) ([]*model.Position, []*model.Order, error) {
+	orders, err := s.orders.ListByUser(ctx, sub.UserID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load orders: %w", err)
+	}
+
+	positionIDs := make(map[uuid.UUID]bool)
+	var pending []*model.Order
+	for _, order := range orders {
+		if order.Source != model.OrderSourceSignal || order.SourceID == nil || *order.SourceID != sub.ID ||
+			order.Side != model.OrderSideBid {
+			continue
+		}
+		if order.PositionID != nil {
+			positionIDs[*order.PositionID] = true
+		}
+		switch order.Status {
+		case model.OrderStatusPending, model.OrderStatusSubmitted, model.OrderStatusPartial:
+			pending = append(pending, order)
+		}
+	}
+	if len(positionIDs) == 0 {
+		return nil, pending, nil
+	}
+
+	all, err := s.positions.ListByUser(ctx, sub.UserID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load positions: %w", err)
+	}
+	var positions []*model.Position
+	for _, position := range all {
+		if positionIDs[position.ID] && position.Status == model.PositionStatusOpen && position.Quantity > 0 {
+			positions = append(positions, position)
+		}
+	}
+	return positions, pending, nil
+}
+
+// reject logs a signal its source couldn't carry out
+func (s *Service) reject(ctx context.Context, entry *model.SignalLog, cause error) {
+	entry.Status = model.SignalLogRejected
+	entry.Error = cause.Error()
+	s.record(ctx, entry)
+}
+
+func (s *Service) record(ctx context.Context, entry *model.SignalLog) {
+	if err := s.logs.Create(ctx, entry); err != nil {
+		log.Printf("Error logging signal of source %s: %v", entry.SourceID, err)
+	}
+}
+
+// touch records when a source last received a signal
+func (s *Service) touch(ctx context.Context, source *model.SignalSource, at time.Time) {
+	source.LastSignalAt = &at
+	if err := s.sources.Update(ctx, source); err != nil {
+		log.Printf("Error recording signal of source %s: %v", source.ID, err)
+	}
+}
@@ -0,0 +1,37 @@
+package signals
+
+import "errors"
+
+var (
+	// ErrInvalidToken is returned for tokens no active webhook source has
+	ErrInvalidToken = errors.New("invalid signal webhook token")
+
+	ErrInvalidName       = &SignalError{message: "name is required and must be at most 100 characters"}
+	ErrInvalidType       = &SignalError{message: "type must be webhook, telegram or indicator"}
+	ErrInvalidTelegram   = &SignalError{message: "telegram sources need the telegram_chat_id of a channel the bot is in"}
+	ErrInvalidIndicator  = &SignalError{message: "indicator sources need a market and an above or below level, with above higher than below"}
+	ErrNotWebhook        = &SignalError{message: "only webhook sources have a token"}
+	ErrInvalidMarket     = &SignalError{message: "market must be a KRW market, e.g. KRW-BTC, BTC/KRW or BTCKRW"}
+	ErrMarketNotAllowed  = &SignalError{message: "market is not one of the source's markets"}
+	ErrInvalidAmount     = &SignalError{message: "amount must be at least 5,000 KRW"}
+	ErrInvalidSignal     = &SignalError{message: "signal must be a JSON object"}
+	ErrInvalidAction     = &SignalError{message: "action must be open, close, strategy, buy or sell"}
+	ErrInvalidStrategy   = &SignalError{message: "strategies need a unique name and an open or close action"}
+	ErrTooManyStrategies = &SignalError{message: "a source can have at most 20 strategies"}
+	ErrUnknownStrategy   = &SignalError{message: "no strategy with that name"}
+	ErrInvalidCaps       = &SignalError{message: "risk caps must not be negative, and max_order_amount must allow open_amount"}
+	ErrNoPosition        = &SignalError{message: "the subscription has no open position in the market"}
+	ErrOrderCap          = &SignalError{message: "open exceeds the subscription's max_order_amount"}
+	ErrPositionCap       = &SignalError{message: "open would exceed the subscription's max_position_amount"}
+	ErrDailyOrderCap     = &SignalError{message: "the subscription reached its max_daily_orders"}
+)
+
+// SignalError represents an invalid source, subscription or signal, or a
+// signal a subscription's risk caps refused
+type SignalError struct {
+	message string
+}
+
+func (e *SignalError) Error() string {
+	return e.message
+}
@@ -0,0 +1,175 @@
+package signals
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/service/pricefeed"
+	"github.com/sungminna/upbit-trading-platform/pkg/recovery"
+)
+
+const (
+	// publishTimeout bounds carrying out a single indicator signal
+	publishTimeout = 30 * time.Second
+	// emitQueueSize is how many indicator signals can wait to be published
+	emitQueueSize = 256
+)
+
+// emission is a signal an indicator emitted
+type emission struct {
+	source *model.SignalSource
+	alert  Alert
+	price  float64
+}
+
+// Start loads active indicator sources and starts evaluating them on the
+// price feed. Without a feed it does nothing.
+func (s *Service) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.isRunning || s.feed == nil {
+		return nil
+	}
+
+	sources, err := s.sources.ListActive(ctx, model.SignalSourceIndicator)
+	if err != nil {
+		return fmt.Errorf("failed to load indicator sources: %w", err)
+	}
+	for _, source := range sources {
+		s.add(source)
+	}
+
+	s.emitted = make(chan emission, emitQueueSize)
+	s.done = make(chan struct{})
+	go s.publishAll(s.emitted, s.done)
+
+	s.unsubscribe = s.feed.Subscribe(pricefeed.AllMarkets, s.onPrice)
+	s.isRunning = true
+	return nil
+}
+
+// Stop stops evaluating indicators and waits for emitted signals to be
+// carried out
+func (s *Service) Stop() {
+	s.mu.Lock()
+	if !s.isRunning {
+		s.mu.Unlock()
+		return
+	}
+	s.unsubscribe()
+	for id, untrack := range s.untrack {
+		untrack()
+		delete(s.untrack, id)
+	}
+	clear(s.indicators)
+	s.isRunning = false
+	close(s.emitted)
+	done := s.done
+	s.mu.Unlock()
+
+	<-done
+}
+
+// watch starts evaluating a source if it is an active indicator
+func (s *Service) watch(source *model.SignalSource) {
+	if source.Type != model.SignalSourceIndicator || !source.Active {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.isRunning {
+		s.add(source)
+	}
+}
+
+// unwatch stops evaluating a source
+func (s *Service) unwatch(source *model.SignalSource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.remove(source)
+}
+
+// add starts evaluating an indicator source; s.mu must be held
+func (s *Service) add(source *model.SignalSource) {
+	if source.Indicator == nil {
+		return
+	}
+	src := *source
+	s.indicators[src.ID] = &src
+	if s.poller != nil {
+		if _, tracked := s.untrack[src.ID]; !tracked {
+			s.untrack[src.ID] = s.poller.Track(src.Indicator.Market)
+		}
+	}
+}
+
+// remove stops evaluating an indicator source; s.mu must be held
+func (s *Service) remove(source *model.SignalSource) {
+	delete(s.indicators, source.ID)
+	if untrack, ok := s.untrack[source.ID]; ok {
+		untrack()
+		delete(s.untrack, source.ID)
+	}
+}
+
+// onPrice emits the signals of the market's indicators whose level the price
+// crossed since the previous update. They are carried out off the feed's
+// goroutine.
+func (s *Service) onPrice(update pricefeed.PriceUpdate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, seen := s.lastPrice[update.Market]
+	s.lastPrice[update.Market] = update.Price
+	if !seen || !s.isRunning {
+		return
+	}
+
+	for _, source := range s.indicators {
+		indicator := source.Indicator
+		if indicator.Market != update.Market {
+			continue
+		}
+
+		var action model.SignalAction
+		switch {
+		case indicator.Above > 0 && previous < indicator.Above && update.Price >= indicator.Above:
+			action = model.SignalActionOpen
+		case indicator.Below > 0 && previous > indicator.Below && update.Price <= indicator.Below:
+			action = model.SignalActionClose
+		default:
+			continue
+		}
+
+		select {
+		case s.emitted <- emission{source: source, alert: Alert{Action: string(action), Ticker: update.Market}, price: update.Price}:
+		default:
+			log.Printf("Dropping signal of indicator source %s: queue is full", source.ID)
+		}
+	}
+}
+
+// publishAll carries out emitted signals in order until emitted is closed
+func (s *Service) publishAll(emitted <-chan emission, done chan<- struct{}) {
+	defer close(done)
+	for e := range emitted {
+		recovery.Do("signals.publish", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+			defer cancel()
+			payload, _ := json.Marshal(map[string]any{
+				"action": e.alert.Action,
+				"ticker": e.alert.Ticker,
+				"price":  e.price,
+			})
+			source := *e.source
+			if _, err := s.Publish(ctx, &source, e.alert, payload); err != nil {
+				log.Printf("Error handling signal of indicator source %s: %v", source.ID, err)
+			}
+		})
+	}
+}
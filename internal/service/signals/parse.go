@@ -0,0 +1,99 @@
+package signals
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/pkg/symbol"
+)
+
+// parseAction returns the action a signal asks for
+func parseAction(alert Alert) (model.SignalAction, error) {
+	switch strings.ToLower(strings.TrimSpace(alert.Action)) {
+	case "open", "buy", "long":
+		return model.SignalActionOpen, nil
+	case "close", "sell", "exit":
+		return model.SignalActionClose, nil
+	case "strategy":
+		return model.SignalActionStrategy, nil
+	case "":
+		if alert.Strategy != "" {
+			return model.SignalActionStrategy, nil
+		}
+	}
+	return "", ErrInvalidAction
+}
+
+// parseText reads a signal from a chat post: either a JSON alert or a line
+// such as "BUY KRW-BTC", "BUY BTCKRW 50000", "SELL BTC/KRW" or "STRATEGY
+// btc-breakout". Posts that are neither, e.g. commentary, aren't signals.
+func parseText(text string) (Alert, bool) {
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "{") {
+		var alert Alert
+		if err := json.Unmarshal([]byte(text), &alert); err != nil {
+			return Alert{}, false
+		}
+		return alert, true
+	}
+
+	line, _, _ := strings.Cut(text, "\n")
+	fields := strings.Fields(line)
+	if len(fields) < 2 || len(fields) > 3 {
+		return Alert{}, false
+	}
+	alert := Alert{Action: fields[0]}
+	if strings.EqualFold(fields[0], "strategy") {
+		alert.Strategy = fields[1]
+		if len(fields) == 3 {
+			alert.Ticker = fields[2]
+		}
+	} else {
+		alert.Ticker = fields[1]
+		if len(fields) == 3 {
+			amount, err := strconv.ParseFloat(fields[2], 64)
+			if err != nil {
+				return Alert{}, false
+			}
+			alert.Amount = amount
+		}
+	}
+	if _, err := parseAction(alert); err != nil {
+		return Alert{}, false
+	}
+	return alert, true
+}
+
+// ParseMarket returns the Upbit KRW market of a ticker given as an Upbit
+// code (KRW-BTC), a symbol (BTC/KRW) or a TradingView ticker with or without
+// its exchange prefix (UPBIT:BTCKRW)
+func ParseMarket(ticker string) (string, error) {
+	ticker = strings.ToUpper(strings.TrimSpace(ticker))
+	if _, rest, ok := strings.Cut(ticker, ":"); ok {
+		ticker = rest
+	}
+
+	var s symbol.Symbol
+	switch {
+	case strings.Contains(ticker, "-"):
+		var ok bool
+		if s, ok = (symbol.UpbitFormat{}).Parse(ticker); !ok {
+			return "", ErrInvalidMarket
+		}
+	case strings.Contains(ticker, "/"):
+		var err error
+		if s, err = symbol.Parse(ticker); err != nil {
+			return "", ErrInvalidMarket
+		}
+	case len(ticker) > 3 && strings.HasSuffix(ticker, "KRW"):
+		s = symbol.Symbol{Base: strings.TrimSuffix(ticker, "KRW"), Quote: "KRW"}
+	default:
+		return "", ErrInvalidMarket
+	}
+	if s.Quote != "KRW" {
+		return "", ErrInvalidMarket
+	}
+	return symbol.UpbitFormat{}.Code(s), nil
+}
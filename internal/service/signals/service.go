@@ -0,0 +1,441 @@
+// Package signals turns trading signals from external providers and internal
+// indicators into orders. Signals arrive from sources: inbound webhooks such
+// as TradingView alerts, Telegram channels and price level indicators. Users
+// trade on a source through subscriptions with their own order size and risk
+// caps, and every signal is logged with what it did.
+package signals
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/pricefeed"
+	"github.com/sungminna/upbit-trading-platform/internal/service/trading"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/quotation"
+)
+
+const (
+	// DefaultLogLimit is how many signal logs are listed by default
+	DefaultLogLimit = 50
+	maxNameLength   = 100
+	maxStrategies   = 20
+	tokenPrefix     = "sig_"
+)
+
+// TickerSource provides current prices; gateway.QuotationAPI satisfies it
+type TickerSource interface {
+	GetTicker(ctx context.Context, markets []string) ([]quotation.Ticker, error)
+}
+
+// OrderPlacer places orders; trading.Engine satisfies it
+type OrderPlacer interface {
+	PlaceOrder(ctx context.Context, userID uuid.UUID, req trading.PlaceOrderRequest) (*model.Order, error)
+}
+
+// Alert is a signal as providers send it. TradingView alerts can fill it from
+// placeholders, e.g. {"action": "{{strategy.order.action}}", "ticker":
+// "{{ticker}}"}.
+type Alert struct {
+	// Action is open, close or strategy; TradingView's buy and sell mean open
+	// and close. Strategy when empty and Strategy is set.
+	Action   string  `json:"action"`
+	Ticker   string  `json:"ticker"`   // KRW-BTC, BTC/KRW, BTCKRW or UPBIT:BTCKRW
+	Strategy string  `json:"strategy"` // Name of the source strategy to run
+	Amount   float64 `json:"amount"`   // KRW to spend on opens; the subscription's OpenAmount when zero
+}
+
+// Service manages signal sources and subscriptions and carries out the
+// signals sources receive for their subscribers. Orders go through the
+// engine, so halts, risk limits and funds checks apply on top of each
+// subscription's caps.
+type Service struct {
+	sources       repository.SignalSourceRepository
+	subscriptions repository.SignalSubscriptionRepository
+	logs          repository.SignalLogRepository
+	positions     repository.PositionRepository
+	orders        repository.OrderRepository
+	placer        OrderPlacer
+	tickers       TickerSource
+	locks         sync.Map // *sync.Mutex by subscription ID; serializes cap checks
+
+	// Indicator sources, evaluated on the price feed once started
+	feed        *pricefeed.Feed   // Optional
+	poller      *pricefeed.Poller // Optional; keeps indicator markets polled
+	indicators  map[uuid.UUID]*model.SignalSource
+	untrack     map[uuid.UUID]func()
+	lastPrice   map[string]float64
+	unsubscribe func()
+	mu          sync.Mutex
+	isRunning   bool
+	emitted     chan emission // Published in order by a single worker
+	done        chan struct{}
+}
+
+// NewService creates a new signal service
+func NewService(
+	sources repository.SignalSourceRepository,
+	subscriptions repository.SignalSubscriptionRepository,
+	logs repository.SignalLogRepository,
+	positions repository.PositionRepository,
+	orders repository.OrderRepository,
+	placer OrderPlacer,
+	tickers TickerSource,
+) *Service {
+	return &Service{
+		sources:       sources,
+		subscriptions: subscriptions,
+		logs:          logs,
+		positions:     positions,
+		orders:        orders,
+		placer:        placer,
+		tickers:       tickers,
+		indicators:    make(map[uuid.UUID]*model.SignalSource),
+		untrack:       make(map[uuid.UUID]func()),
+		lastPrice:     make(map[string]float64),
+	}
+}
+
+// WithPriceFeed evaluates indicator sources on a price feed once started.
+// poller may be nil when another component keeps the feed updated.
+func (s *Service) WithPriceFeed(feed *pricefeed.Feed, poller *pricefeed.Poller) *Service {
+	s.feed = feed
+	s.poller = poller
+	return s
+}
+
+// CreateSource validates and stores a new source. The token of webhook
+// sources is returned only here.
+func (s *Service) CreateSource(ctx context.Context, source *model.SignalSource) (*model.SignalSource, string, error) {
+	if err := validateSource(source); err != nil {
+		return nil, "", err
+	}
+
+	var token string
+	source.TokenHash = ""
+	if source.Type == model.SignalSourceWebhook {
+		var err error
+		if token, source.TokenHash, err = newToken(); err != nil {
+			return nil, "", err
+		}
+	}
+
+	now := time.Now()
+	source.ID = uuid.New()
+	source.Active = true
+	source.LastSignalAt = nil
+	source.CreatedAt = now
+	source.UpdatedAt = now
+	if err := s.sources.Create(ctx, source); err != nil {
+		return nil, "", err
+	}
+
+	s.watch(source)
+	return source, token, nil
+}
+
+// GetSource returns one of the sources the user owns
+func (s *Service) GetSource(ctx context.Context, userID, id uuid.UUID) (*model.SignalSource, error) {
+	source, err := s.sources.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if source.UserID != userID {
+		return nil, repository.ErrNotFound
+	}
+	return source, nil
+}
+
+// ListSources returns the sources the user owns, oldest first
+func (s *Service) ListSources(ctx context.Context, userID uuid.UUID) ([]*model.SignalSource, error) {
+	return s.sources.ListByUser(ctx, userID)
+}
+
+// ListPublicSources returns the active sources every user can subscribe to
+func (s *Service) ListPublicSources(ctx context.Context) ([]*model.SignalSource, error) {
+	return s.sources.ListPublic(ctx)
+}
+
+// DeleteSource removes one of the user's sources with every subscription to
+// it and its logs
+func (s *Service) DeleteSource(ctx context.Context, userID, id uuid.UUID) error {
+	source, err := s.GetSource(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.sources.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.unwatch(source)
+	return nil
+}
+
+// SetSourceActive pauses or resumes one of the user's sources. Paused
+// sources receive no signals.
+func (s *Service) SetSourceActive(ctx context.Context, userID, id uuid.UUID, active bool) (*model.SignalSource, error) {
+	source, err := s.GetSource(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	source.Active = active
+	source.UpdatedAt = time.Now()
+	if err := s.sources.Update(ctx, source); err != nil {
+		return nil, err
+	}
+
+	if active {
+		s.watch(source)
+	} else {
+		s.unwatch(source)
+	}
+	return source, nil
+}
+
+// RotateToken replaces a webhook source's token, e.g. after it leaked, and
+// returns the new one. The old token stops working immediately.
+func (s *Service) RotateToken(ctx context.Context, userID, id uuid.UUID) (string, error) {
+	source, err := s.GetSource(ctx, userID, id)
+	if err != nil {
+		return "", err
+	}
+	if source.Type != model.SignalSourceWebhook {
+		return "", ErrNotWebhook
+	}
+	token, hash, err := newToken()
+	if err != nil {
+		return "", err
+	}
+	source.TokenHash = hash
+	source.UpdatedAt = time.Now()
+	if err := s.sources.Update(ctx, source); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// SourceLogs returns the latest signals one of the user's sources received
+// and what they did for every subscriber, newest first
+func (s *Service) SourceLogs(ctx context.Context, userID, id uuid.UUID, limit int) ([]*model.SignalLog, error) {
+	if _, err := s.GetSource(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = DefaultLogLimit
+	}
+	return s.logs.ListBySource(ctx, id, limit)
+}
+
+// Subscribe validates and stores a new subscription. Users can subscribe to
+// their own sources and to public ones.
+func (s *Service) Subscribe(ctx context.Context, sub *model.SignalSubscription) (*model.SignalSubscription, error) {
+	source, err := s.sources.GetByID(ctx, sub.SourceID)
+	if err != nil {
+		return nil, err
+	}
+	if source.UserID != sub.UserID && !source.Public {
+		return nil, repository.ErrNotFound
+	}
+	if err := validateSubscription(sub, source); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	sub.ID = uuid.New()
+	sub.Active = true
+	sub.CreatedAt = now
+	sub.UpdatedAt = now
+	if err := s.subscriptions.Create(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// GetSubscription returns one of the user's subscriptions
+func (s *Service) GetSubscription(ctx context.Context, userID, id uuid.UUID) (*model.SignalSubscription, error) {
+	sub, err := s.subscriptions.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sub.UserID != userID {
+		return nil, repository.ErrNotFound
+	}
+	return sub, nil
+}
+
+// ListSubscriptions returns the user's subscriptions, oldest first
+func (s *Service) ListSubscriptions(ctx context.Context, userID uuid.UUID) ([]*model.SignalSubscription, error) {
+	return s.subscriptions.ListByUser(ctx, userID)
+}
+
+// Unsubscribe removes one of the user's subscriptions. Positions it opened
+// are left open.
+func (s *Service) Unsubscribe(ctx context.Context, userID, id uuid.UUID) error {
+	if _, err := s.GetSubscription(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.subscriptions.Delete(ctx, id)
+}
+
+// SetSubscriptionActive pauses or resumes one of the user's subscriptions
+func (s *Service) SetSubscriptionActive(ctx context.Context, userID, id uuid.UUID, active bool) (*model.SignalSubscription, error) {
+	sub, err := s.GetSubscription(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	sub.Active = active
+	sub.UpdatedAt = time.Now()
+	if err := s.subscriptions.Update(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// SubscriptionLogs returns the latest signals one of the user's
+// subscriptions followed and what they did, newest first
+func (s *Service) SubscriptionLogs(ctx context.Context, userID, id uuid.UUID, limit int) ([]*model.SignalLog, error) {
+	if _, err := s.GetSubscription(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = DefaultLogLimit
+	}
+	return s.logs.ListBySubscription(ctx, id, limit)
+}
+
+// validateSource checks and normalizes a new source
+func validateSource(source *model.SignalSource) error {
+	source.Name = strings.TrimSpace(source.Name)
+	if source.Name == "" || len(source.Name) > maxNameLength {
+		return ErrInvalidName
+	}
+
+	markets, err := parseMarkets(source.Markets)
+	if err != nil {
+		return err
+	}
+	source.Markets = markets
+
+	switch source.Type {
+	case model.SignalSourceWebhook:
+		source.TelegramChatID, source.Indicator = nil, nil
+	case model.SignalSourceTelegram:
+		if source.TelegramChatID == nil || *source.TelegramChatID == 0 {
+			return ErrInvalidTelegram
+		}
+		source.Indicator = nil
+	case model.SignalSourceIndicator:
+		indicator := source.Indicator
+		if indicator == nil || indicator.Above < 0 || indicator.Below < 0 ||
+			(indicator.Above == 0 && indicator.Below == 0) ||
+			(indicator.Above > 0 && indicator.Below > 0 && indicator.Above <= indicator.Below) {
+			return ErrInvalidIndicator
+		}
+		market, err := ParseMarket(indicator.Market)
+		if err != nil {
+			return err
+		}
+		indicator.Market = market
+		source.Markets = []string{market}
+		source.TelegramChatID = nil
+	default:
+		return ErrInvalidType
+	}
+
+	if len(source.Strategies) > maxStrategies {
+		return ErrTooManyStrategies
+	}
+	names := make(map[string]bool, len(source.Strategies))
+	for i := range source.Strategies {
+		strategy := &source.Strategies[i]
+		strategy.Name = strings.TrimSpace(strategy.Name)
+		if strategy.Name == "" || names[strategy.Name] ||
+			(strategy.Action != model.SignalActionOpen && strategy.Action != model.SignalActionClose) {
+			return ErrInvalidStrategy
+		}
+		names[strategy.Name] = true
+
+		if strategy.Market != "" {
+			market, err := ParseMarket(strategy.Market)
+			if err != nil {
+				return err
+			}
+			if len(source.Markets) > 0 && !slices.Contains(source.Markets, market) {
+				return ErrMarketNotAllowed
+			}
+			strategy.Market = market
+		}
+		if strategy.Amount != 0 && strategy.Amount < model.MinOrderAmount {
+			return ErrInvalidAmount
+		}
+	}
+	return nil
+}
+
+// validateSubscription checks and normalizes a new subscription to source
+func validateSubscription(sub *model.SignalSubscription, source *model.SignalSource) error {
+	if sub.Market != "" {
+		market, err := ParseMarket(sub.Market)
+		if err != nil {
+			return err
+		}
+		if len(source.Markets) > 0 && !slices.Contains(source.Markets, market) {
+			return ErrMarketNotAllowed
+		}
+		sub.Market = market
+	}
+	sub.Strategy = strings.TrimSpace(sub.Strategy)
+	if sub.Strategy != "" {
+		if _, ok := source.Strategy(sub.Strategy); !ok {
+			return ErrUnknownStrategy
+		}
+	}
+	if sub.OpenAmount < model.MinOrderAmount {
+		return ErrInvalidAmount
+	}
+	if sub.MaxOrderAmount < 0 || sub.MaxPositionAmount < 0 || sub.MaxDailyOrders < 0 ||
+		(sub.MaxOrderAmount > 0 && sub.MaxOrderAmount < sub.OpenAmount) {
+		return ErrInvalidCaps
+	}
+	return nil
+}
+
+// parseMarkets normalizes a list of markets, dropping duplicates
+func parseMarkets(list []string) ([]string, error) {
+	markets := make([]string, 0, len(list))
+	for _, m := range list {
+		market, err := ParseMarket(m)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(markets, market) {
+			markets = append(markets, market)
+		}
+	}
+	return markets, nil
+}
+
+// newToken returns a random 256-bit token and its hash
+func newToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("failed to generate signal webhook token: %w", err)
+	}
+	token := tokenPrefix + hex.EncodeToString(buf)
+	return token, hashToken(token), nil
+}
+
+// hashToken returns the hex SHA-256 of a token. Tokens are looked up by hash,
+// so a lookup's timing reveals nothing about stored tokens.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,324 @@
+package signals
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+	"github.com/sungminna/upbit-trading-platform/internal/service/trading"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/quotation"
+)
+
+type staticTickers map[string]float64
+
+func (s staticTickers) GetTicker(ctx context.Context, markets []string) ([]quotation.Ticker, error) {
+	var tickers []quotation.Ticker
+	for _, market := range markets {
+		if price, ok := s[market]; ok {
+			tickers = append(tickers, quotation.Ticker{Market: market, TradePrice: price})
+		}
+	}
+	return tickers, nil
+}
+
+// storingPlacer stores placed orders, so subscriptions' exposure can be
+// derived from them
+type storingPlacer struct {
+	store  *memory.Store
+	mu     sync.Mutex
+	placed []trading.PlaceOrderRequest
+}
+
+func (p *storingPlacer) PlaceOrder(ctx context.Context, userID uuid.UUID, req trading.PlaceOrderRequest) (*model.Order, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.placed = append(p.placed, req)
+	order := model.NewOrder(userID, req.Market, req.Side, req.Type, req.Quantity, req.Price, time.Now())
+	order.PositionID, order.Source, order.SourceID = req.PositionID, req.Source, req.SourceID
+	return order, p.store.Orders().Create(ctx, order)
+}
+
+var prices = staticTickers{"KRW-BTC": 100000000, "KRW-ETH": 5000000}
+
+func newTestService() (*Service, *memory.Store, *storingPlacer) {
+	store := memory.NewStore()
+	placer := &storingPlacer{store: store}
+	service := NewService(store.SignalSources(), store.SignalSubscriptions(), store.SignalLogs(),
+		store.Positions(), store.Orders(), placer, prices)
+	return service, store, placer
+}
+
+func TestParseMarket(t *testing.T) {
+	tests := map[string]string{
+		"KRW-BTC":      "KRW-BTC",
+		"krw-eth":      "KRW-ETH",
+		"BTC/KRW":      "KRW-BTC",
+		"BTCKRW":       "KRW-BTC",
+		"UPBIT:XRPKRW": "KRW-XRP",
+	}
+	for ticker, want := range tests {
+		got, err := ParseMarket(ticker)
+		require.NoError(t, err, ticker)
+		assert.Equal(t, want, got, ticker)
+	}
+
+	for _, ticker := range []string{"", "KRW", "BTC-ETH", "ETH/BTC", "BTCUSDT"} {
+		_, err := ParseMarket(ticker)
+		assert.ErrorIs(t, err, ErrInvalidMarket, ticker)
+	}
+}
+
+func TestParseText(t *testing.T) {
+	alert, ok := parseText("BUY BTCKRW 50000\nbreakout on the 4h")
+	require.True(t, ok)
+	assert.Equal(t, Alert{Action: "BUY", Ticker: "BTCKRW", Amount: 50000}, alert)
+
+	alert, ok = parseText(`{"action": "sell", "ticker": "KRW-ETH"}`)
+	require.True(t, ok)
+	assert.Equal(t, Alert{Action: "sell", Ticker: "KRW-ETH"}, alert)
+
+	alert, ok = parseText("strategy eth-dip")
+	require.True(t, ok)
+	assert.Equal(t, "eth-dip", alert.Strategy)
+
+	for _, text := range []string{"", "gm everyone", "HOLD KRW-BTC", "BUY KRW-BTC lots"} {
+		_, ok := parseText(text)
+		assert.False(t, ok, text)
+	}
+}
+
+func TestService_CreateSourceValidates(t *testing.T) {
+	ctx := context.Background()
+	service, _, _ := newTestService()
+	userID := uuid.New()
+	chatID := int64(-100123)
+
+	tests := []struct {
+		source *model.SignalSource
+		err    error
+	}{
+		{&model.SignalSource{Type: model.SignalSourceWebhook}, ErrInvalidName},
+		{&model.SignalSource{Name: "x", Type: "email"}, ErrInvalidType},
+		{&model.SignalSource{Name: "x", Type: model.SignalSourceWebhook, Markets: []string{"BTC-ETH"}}, ErrInvalidMarket},
+		{&model.SignalSource{Name: "x", Type: model.SignalSourceTelegram}, ErrInvalidTelegram},
+		{&model.SignalSource{Name: "x", Type: model.SignalSourceIndicator, Indicator: &model.SignalIndicator{Market: "KRW-BTC"}}, ErrInvalidIndicator},
+		{&model.SignalSource{Name: "x", Type: model.SignalSourceIndicator, Indicator: &model.SignalIndicator{Market: "KRW-BTC", Above: 90, Below: 100}}, ErrInvalidIndicator},
+		{&model.SignalSource{Name: "x", Type: model.SignalSourceWebhook, Strategies: []model.SignalStrategy{{Name: "a", Action: "hold"}}}, ErrInvalidStrategy},
+		{&model.SignalSource{Name: "x", Type: model.SignalSourceWebhook, Markets: []string{"KRW-BTC"}, Strategies: []model.SignalStrategy{
+			{Name: "a", Action: model.SignalActionOpen, Market: "KRW-ETH"},
+		}}, ErrMarketNotAllowed},
+	}
+	for _, tt := range tests {
+		tt.source.UserID = userID
+		_, _, err := service.CreateSource(ctx, tt.source)
+		assert.ErrorIs(t, err, tt.err)
+	}
+
+	webhook, token, err := service.CreateSource(ctx, &model.SignalSource{
+		UserID: userID, Name: " tv ", Type: model.SignalSourceWebhook, Markets: []string{"BTCKRW", "KRW-BTC"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "tv", webhook.Name)
+	assert.Equal(t, []string{"KRW-BTC"}, webhook.Markets)
+	assert.Equal(t, hashToken(token), webhook.TokenHash)
+
+	channel, token, err := service.CreateSource(ctx, &model.SignalSource{
+		UserID: userID, Name: "calls", Type: model.SignalSourceTelegram, TelegramChatID: &chatID,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, token)
+	_, err = service.RotateToken(ctx, userID, channel.ID)
+	assert.ErrorIs(t, err, ErrNotWebhook)
+}
+
+func TestService_Subscribe(t *testing.T) {
+	ctx := context.Background()
+	service, _, _ := newTestService()
+	owner, other := uuid.New(), uuid.New()
+
+	private, _, err := service.CreateSource(ctx, &model.SignalSource{UserID: owner, Name: "mine", Type: model.SignalSourceWebhook})
+	require.NoError(t, err)
+	public, _, err := service.CreateSource(ctx, &model.SignalSource{
+		UserID: owner, Name: "shared", Type: model.SignalSourceWebhook, Markets: []string{"KRW-BTC"}, Public: true,
+	})
+	require.NoError(t, err)
+
+	_, err = service.Subscribe(ctx, &model.SignalSubscription{UserID: other, SourceID: private.ID, OpenAmount: 10000})
+	assert.Error(t, err)
+	_, err = service.Subscribe(ctx, &model.SignalSubscription{UserID: other, SourceID: public.ID, OpenAmount: 1000})
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+	_, err = service.Subscribe(ctx, &model.SignalSubscription{UserID: other, SourceID: public.ID, OpenAmount: 10000, MaxOrderAmount: 5000})
+	assert.ErrorIs(t, err, ErrInvalidCaps)
+	_, err = service.Subscribe(ctx, &model.SignalSubscription{UserID: other, SourceID: public.ID, OpenAmount: 10000, Market: "KRW-ETH"})
+	assert.ErrorIs(t, err, ErrMarketNotAllowed)
+
+	sub, err := service.Subscribe(ctx, &model.SignalSubscription{UserID: other, SourceID: public.ID, OpenAmount: 10000, Market: "BTCKRW"})
+	require.NoError(t, err)
+	assert.Equal(t, "KRW-BTC", sub.Market)
+	assert.True(t, sub.Active)
+}
+
+func TestService_HandleWebhook(t *testing.T) {
+	ctx := context.Background()
+	service, store, placer := newTestService()
+	owner, follower := uuid.New(), uuid.New()
+
+	source, token, err := service.CreateSource(ctx, &model.SignalSource{
+		UserID: owner, Name: "tv", Type: model.SignalSourceWebhook, Markets: []string{"KRW-BTC", "KRW-ETH"}, Public: true,
+		Strategies: []model.SignalStrategy{{Name: "eth-dip", Action: model.SignalActionOpen, Market: "KRW-ETH", Amount: 50000}},
+	})
+	require.NoError(t, err)
+	ownerSub, err := service.Subscribe(ctx, &model.SignalSubscription{UserID: owner, SourceID: source.ID, OpenAmount: 100000})
+	require.NoError(t, err)
+	followerSub, err := service.Subscribe(ctx, &model.SignalSubscription{
+		UserID: follower, SourceID: source.ID, Market: "KRW-BTC", OpenAmount: 20000, MaxDailyOrders: 1,
+	})
+	require.NoError(t, err)
+
+	_, err = service.HandleWebhook(ctx, "sig_wrong", json.RawMessage(`{"action": "buy", "ticker": "BTCKRW"}`))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, err = service.HandleWebhook(ctx, token, json.RawMessage(`not json`))
+	assert.ErrorIs(t, err, ErrInvalidSignal)
+	_, err = service.HandleWebhook(ctx, token, json.RawMessage(`{"action": "buy", "ticker": "XRPKRW"}`))
+	assert.ErrorIs(t, err, ErrMarketNotAllowed)
+	_, err = service.HandleWebhook(ctx, token, json.RawMessage(`{"strategy": "unknown"}`))
+	assert.ErrorIs(t, err, ErrUnknownStrategy)
+	assert.Empty(t, placer.placed)
+
+	// Both subscriptions follow a BTC buy, each with its own amount
+	logs, err := service.HandleWebhook(ctx, token, json.RawMessage(`{"action": "buy", "ticker": "UPBIT:BTCKRW"}`))
+	require.NoError(t, err)
+	require.Len(t, logs, 2)
+	for _, l := range logs {
+		assert.Equal(t, model.SignalLogExecuted, l.Status)
+		assert.Len(t, l.OrderIDs, 1)
+	}
+	require.Len(t, placer.placed, 2)
+	assert.InDelta(t, 0.001, placer.placed[0].Quantity, 1e-12)
+	assert.Equal(t, ownerSub.ID, *placer.placed[0].SourceID)
+	assert.InDelta(t, 0.0002, placer.placed[1].Quantity, 1e-12)
+	assert.Equal(t, model.OrderSourceSignal, placer.placed[1].Source)
+
+	// The follower only trades BTC and has used its daily order
+	logs, err = service.HandleWebhook(ctx, token, json.RawMessage(`{"strategy": "eth-dip"}`))
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Equal(t, ownerSub.ID, *logs[0].SubscriptionID)
+	assert.InDelta(t, 0.01, placer.placed[2].Quantity, 1e-12)
+
+	logs, err = service.HandleWebhook(ctx, token, json.RawMessage(`{"action": "buy", "ticker": "KRW-BTC"}`))
+	require.NoError(t, err)
+	require.Len(t, logs, 2)
+	assert.Equal(t, model.SignalLogExecuted, logs[0].Status)
+	assert.Equal(t, model.SignalLogRejected, logs[1].Status)
+	assert.Equal(t, ErrDailyOrderCap.Error(), logs[1].Error)
+
+	// Closes only sell positions the subscription opened
+	orders, err := store.Orders().ListByUser(ctx, owner)
+	require.NoError(t, err)
+	var opened *model.Position
+	for _, order := range orders {
+		if order.Market == "KRW-BTC" && opened == nil {
+			opened = model.NewPosition(owner, "KRW-BTC", model.PositionSideLong, 100000000, 0.001, time.Now())
+			require.NoError(t, store.Positions().Create(ctx, opened))
+			order.PositionID = &opened.ID
+			order.Status = model.OrderStatusFilled
+			require.NoError(t, store.Orders().Update(ctx, order))
+		}
+	}
+	manual := model.NewPosition(owner, "KRW-BTC", model.PositionSideLong, 100000000, 0.5, time.Now())
+	require.NoError(t, store.Positions().Create(ctx, manual))
+
+	logs, err = service.HandleWebhook(ctx, token, json.RawMessage(`{"action": "sell", "ticker": "KRW-BTC"}`))
+	require.NoError(t, err)
+	require.Len(t, logs, 2)
+	assert.Equal(t, model.SignalLogExecuted, logs[0].Status)
+	assert.Equal(t, model.SignalLogRejected, logs[1].Status) // The follower's buy never filled
+	req := placer.placed[len(placer.placed)-1]
+	assert.Equal(t, model.OrderSideAsk, req.Side)
+	assert.Equal(t, opened.ID, *req.PositionID)
+
+	// Every signal is logged for the owner, including invalid ones
+	sourceLogs, err := service.SourceLogs(ctx, owner, source.ID, 0)
+	require.NoError(t, err)
+	assert.Len(t, sourceLogs, 10)
+	followerLogs, err := service.SubscriptionLogs(ctx, follower, followerSub.ID, 0)
+	require.NoError(t, err)
+	assert.Len(t, followerLogs, 3)
+	_, err = service.SourceLogs(ctx, follower, source.ID, 0)
+	assert.Error(t, err)
+
+	// Paused sources and rotated tokens are rejected
+	_, err = service.SetSourceActive(ctx, owner, source.ID, false)
+	require.NoError(t, err)
+	_, err = service.HandleWebhook(ctx, token, json.RawMessage(`{"action": "buy", "ticker": "BTCKRW"}`))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, err = service.SetSourceActive(ctx, owner, source.ID, true)
+	require.NoError(t, err)
+	rotated, err := service.RotateToken(ctx, owner, source.ID)
+	require.NoError(t, err)
+	_, err = service.HandleWebhook(ctx, token, json.RawMessage(`{"action": "buy", "ticker": "BTCKRW"}`))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, err = service.HandleWebhook(ctx, rotated, json.RawMessage(`{"action": "buy", "ticker": "BTCKRW"}`))
+	assert.NoError(t, err)
+}
+
+func TestService_PositionCap(t *testing.T) {
+	ctx := context.Background()
+	service, _, placer := newTestService()
+	userID := uuid.New()
+
+	source, token, err := service.CreateSource(ctx, &model.SignalSource{UserID: userID, Name: "tv", Type: model.SignalSourceWebhook})
+	require.NoError(t, err)
+	_, err = service.Subscribe(ctx, &model.SignalSubscription{
+		UserID: userID, SourceID: source.ID, OpenAmount: 10000, MaxPositionAmount: 25000,
+	})
+	require.NoError(t, err)
+
+	// Unfilled buys count towards the cap
+	for range 2 {
+		logs, err := service.HandleWebhook(ctx, token, json.RawMessage(`{"action": "buy", "ticker": "KRW-BTC"}`))
+		require.NoError(t, err)
+		assert.Equal(t, model.SignalLogExecuted, logs[0].Status)
+	}
+	logs, err := service.HandleWebhook(ctx, token, json.RawMessage(`{"action": "buy", "ticker": "KRW-BTC"}`))
+	require.NoError(t, err)
+	assert.Equal(t, model.SignalLogRejected, logs[0].Status)
+	assert.Equal(t, ErrPositionCap.Error(), logs[0].Error)
+	assert.Len(t, placer.placed, 2)
+}
+
+func TestService_HandleChannelPost(t *testing.T) {
+	ctx := context.Background()
+	service, _, placer := newTestService()
+	userID := uuid.New()
+	chatID := int64(-100123)
+
+	source, _, err := service.CreateSource(ctx, &model.SignalSource{
+		UserID: userID, Name: "calls", Type: model.SignalSourceTelegram, TelegramChatID: &chatID,
+	})
+	require.NoError(t, err)
+	sub, err := service.Subscribe(ctx, &model.SignalSubscription{UserID: userID, SourceID: source.ID, OpenAmount: 10000})
+	require.NoError(t, err)
+
+	service.HandleChannelPost(ctx, chatID, "gm, big day ahead")
+	service.HandleChannelPost(ctx, -100999, "BUY KRW-BTC")
+	assert.Empty(t, placer.placed)
+
+	service.HandleChannelPost(ctx, chatID, "BUY ETHKRW 20000")
+	require.Len(t, placer.placed, 1)
+	assert.Equal(t, "KRW-ETH", placer.placed[0].Market)
+	assert.InDelta(t, 0.004, placer.placed[0].Quantity, 1e-12)
+
+	logs, err := service.SubscriptionLogs(ctx, userID, sub.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Equal(t, "KRW-ETH", logs[0].Market)
+}
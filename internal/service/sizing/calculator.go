@@ -0,0 +1,112 @@
+package sizing
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// defaultInterval is the candle interval market data is measured on
+const defaultInterval = model.CandleInterval1d
+
+// CandleSource provides recent candles; gateway.QuotationAPI satisfies it
+type CandleSource interface {
+	GetCandles(ctx context.Context, market string, interval model.CandleInterval, count int) ([]model.Candle, error)
+}
+
+// Request asks for a position size. ATR, Volatility and EntryPrice are
+// measured from Market's candles when they aren't given.
+type Request struct {
+	Config
+	Equity     float64              `json:"equity"`
+	EntryPrice float64              `json:"entry_price"`
+	ATR        float64              `json:"atr,omitempty"`
+	Volatility float64              `json:"volatility,omitempty"`
+	Market     string               `json:"market,omitempty"`
+	Interval   model.CandleInterval `json:"interval,omitempty"` // Defaults to 1d
+}
+
+// Calculator sizes positions, filling in market data from recent candles
+type Calculator struct {
+	candles CandleSource
+}
+
+// NewCalculator creates a new calculator; candles may be nil, in which case
+// every figure must be given explicitly
+func NewCalculator(candles CandleSource) *Calculator {
+	return &Calculator{candles: candles}
+}
+
+// Calculate computes the position size for req
+func (c *Calculator) Calculate(ctx context.Context, req Request) (*Result, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	needsATR := req.NeedsATR() && req.ATR == 0
+	needsVolatility := req.NeedsVolatility() && req.Volatility == 0
+	if needsATR || needsVolatility || req.EntryPrice == 0 {
+		if req.Market == "" || c.candles == nil {
+			if needsATR {
+				return nil, ErrNoStopDistance
+			}
+			if needsVolatility {
+				return nil, ErrMarketDataRequired
+			}
+			return nil, ErrInvalidEquity
+		}
+
+		interval := req.Interval
+		if interval == "" {
+			interval = defaultInterval
+		}
+		if interval.Duration() == 0 {
+			return nil, fmt.Errorf("%w: unsupported interval %q", ErrInvalidSizing, interval)
+		}
+
+		period := req.PeriodOrDefault()
+		candles, err := c.candles.GetCandles(ctx, req.Market, interval, period+1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load candles: %w", err)
+		}
+		if len(candles) == 0 {
+			return nil, ErrNotEnoughCandles
+		}
+		sort.Slice(candles, func(i, j int) bool {
+			return candles[i].Timestamp.Before(candles[j].Timestamp)
+		})
+
+		if req.EntryPrice == 0 {
+			req.EntryPrice = candles[len(candles)-1].ClosePrice
+		}
+		if needsATR {
+			if req.ATR, err = ATR(candles, period); err != nil {
+				return nil, err
+			}
+		}
+		if needsVolatility {
+			if req.Volatility, err = Volatility(candles, period, periodsPerYear(interval)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return Size(req.Config, Inputs{
+		Equity:     req.Equity,
+		EntryPrice: req.EntryPrice,
+		ATR:        req.ATR,
+		Volatility: req.Volatility,
+	})
+}
+
+// periodsPerYear returns how many candles of an interval fit in a year;
+// crypto markets trade around the clock
+func periodsPerYear(interval model.CandleInterval) float64 {
+	if interval.Duration() == 0 {
+		return 0
+	}
+	return float64(365*24*time.Hour) / float64(interval.Duration())
+}
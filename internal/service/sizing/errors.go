@@ -0,0 +1,22 @@
+package sizing
+
+var (
+	ErrUnknownMethod      = &SizingError{message: "unknown sizing method"}
+	ErrInvalidEquity      = &SizingError{message: "equity and entry price must be positive"}
+	ErrInvalidRisk        = &SizingError{message: "risk_percent must be in (0, 100]"}
+	ErrNoStopDistance     = &SizingError{message: "a stop distance or ATR is required"}
+	ErrInvalidKelly       = &SizingError{message: "win_rate must be in (0, 1) and payoff_ratio positive"}
+	ErrInvalidVolatility  = &SizingError{message: "target_volatility and volatility must be positive"}
+	ErrInvalidSizing      = &SizingError{message: "sizing parameters must not be negative"}
+	ErrNotEnoughCandles   = &SizingError{message: "not enough candles to measure volatility"}
+	ErrMarketDataRequired = &SizingError{message: "market data is unavailable; pass atr or volatility explicitly"}
+)
+
+// SizingError represents invalid sizing parameters
+type SizingError struct {
+	message string
+}
+
+func (e *SizingError) Error() string {
+	return e.message
+}
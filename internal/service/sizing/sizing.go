@@ -0,0 +1,243 @@
+// Package sizing computes order quantities from account equity, the risk
+// taken per trade and the market's volatility
+package sizing
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// Method is a position sizing rule
+type Method string
+
+const (
+	// MethodFixedFractional risks RiskPercent of equity between entry and stop
+	MethodFixedFractional Method = "fixed_fractional"
+	// MethodKelly bets a fraction of the Kelly criterion for the strategy's
+	// win rate and payoff ratio
+	MethodKelly Method = "kelly"
+	// MethodVolatility scales the position so its annualized volatility
+	// matches TargetVolatility of equity
+	MethodVolatility Method = "volatility"
+)
+
+// Defaults for unset parameters
+const (
+	defaultATRMultiple        = 2
+	defaultKellyFraction      = 0.5
+	defaultMaxPositionPercent = 100
+	defaultPeriod             = 14
+)
+
+// quantityPrecision is the number of decimals Upbit accepts in a volume
+const quantityPrecision = 1e8
+
+// Config selects a sizing method and its parameters
+type Config struct {
+	Method             Method  `json:"method"`
+	RiskPercent        float64 `json:"risk_percent,omitempty"`         // Fixed fractional: percent of equity lost at the stop
+	StopDistance       float64 `json:"stop_distance,omitempty"`        // KRW between entry and stop
+	ATRMultiple        float64 `json:"atr_multiple,omitempty"`         // Stop distance in ATRs when StopDistance is zero; defaults to 2
+	WinRate            float64 `json:"win_rate,omitempty"`             // Kelly: probability that a trade wins
+	PayoffRatio        float64 `json:"payoff_ratio,omitempty"`         // Kelly: average win divided by average loss
+	KellyFraction      float64 `json:"kelly_fraction,omitempty"`       // Kelly: share of full Kelly to bet; defaults to 0.5
+	TargetVolatility   float64 `json:"target_volatility,omitempty"`    // Volatility: annualized, e.g. 0.2 for 20%
+	MaxPositionPercent float64 `json:"max_position_percent,omitempty"` // Caps the notional as a percent of equity; defaults to 100
+	Period             int     `json:"period,omitempty"`               // Candles ATR and volatility are measured over; defaults to 14
+}
+
+// Inputs are the account and market figures a size is computed from
+type Inputs struct {
+	Equity     float64
+	EntryPrice float64
+	ATR        float64 // Average true range in KRW; used when StopDistance is zero
+	Volatility float64 // Annualized volatility of returns; volatility method only
+}
+
+// Result is a computed position size
+type Result struct {
+	Method          Method  `json:"method"`
+	Quantity        float64 `json:"quantity"`
+	Notional        float64 `json:"notional"`
+	PositionPercent float64 `json:"position_percent"` // Notional as a percent of equity
+	StopDistance    float64 `json:"stop_distance,omitempty"`
+	StopPrice       float64 `json:"stop_price,omitempty"`
+	RiskAmount      float64 `json:"risk_amount,omitempty"` // KRW lost if the stop is hit
+	Capped          bool    `json:"capped"`                // MaxPositionPercent limited the size
+}
+
+// Validate checks the parameters the method needs
+func (c Config) Validate() error {
+	if c.RiskPercent < 0 || c.StopDistance < 0 || c.ATRMultiple < 0 || c.KellyFraction < 0 ||
+		c.TargetVolatility < 0 || c.MaxPositionPercent < 0 || c.Period < 0 {
+		return ErrInvalidSizing
+	}
+
+	switch c.Method {
+	case MethodFixedFractional:
+		if c.RiskPercent <= 0 || c.RiskPercent > 100 {
+			return ErrInvalidRisk
+		}
+	case MethodKelly:
+		if c.WinRate <= 0 || c.WinRate >= 1 || c.PayoffRatio <= 0 {
+			return ErrInvalidKelly
+		}
+	case MethodVolatility:
+		if c.TargetVolatility <= 0 {
+			return ErrInvalidVolatility
+		}
+	default:
+		return ErrUnknownMethod
+	}
+	return nil
+}
+
+// NeedsATR reports whether the stop distance has to come from the ATR
+func (c Config) NeedsATR() bool {
+	return c.Method == MethodFixedFractional && c.StopDistance == 0
+}
+
+// NeedsVolatility reports whether the method sizes by measured volatility
+func (c Config) NeedsVolatility() bool {
+	return c.Method == MethodVolatility
+}
+
+// PeriodOrDefault returns the number of candles to measure ATR and
+// volatility over
+func (c Config) PeriodOrDefault() int {
+	if c.Period > 0 {
+		return c.Period
+	}
+	return defaultPeriod
+}
+
+// Size computes the quantity to buy at in.EntryPrice. With a stop distance,
+// from StopDistance or ATRMultiple × ATR, Kelly sizes the amount risked at the
+// stop; without one it sizes the notional.
+func Size(cfg Config, in Inputs) (*Result, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if in.Equity <= 0 || in.EntryPrice <= 0 {
+		return nil, ErrInvalidEquity
+	}
+
+	result := &Result{Method: cfg.Method, StopDistance: stopDistance(cfg, in.ATR)}
+
+	var quantity float64
+	switch cfg.Method {
+	case MethodFixedFractional:
+		if result.StopDistance <= 0 {
+			return nil, ErrNoStopDistance
+		}
+		quantity = in.Equity * cfg.RiskPercent / 100 / result.StopDistance
+	case MethodKelly:
+		fraction := kellyFraction(cfg)
+		if result.StopDistance > 0 {
+			quantity = in.Equity * fraction / result.StopDistance
+		} else {
+			quantity = in.Equity * fraction / in.EntryPrice
+		}
+	case MethodVolatility:
+		if in.Volatility <= 0 {
+			return nil, ErrInvalidVolatility
+		}
+		quantity = in.Equity * cfg.TargetVolatility / in.Volatility / in.EntryPrice
+	}
+	quantity = math.Max(quantity, 0)
+
+	maxPercent := cfg.MaxPositionPercent
+	if maxPercent == 0 {
+		maxPercent = defaultMaxPositionPercent
+	}
+	if maxQuantity := in.Equity * maxPercent / 100 / in.EntryPrice; quantity > maxQuantity {
+		quantity = maxQuantity
+		result.Capped = true
+	}
+
+	// Round down to what Upbit accepts, tolerating floating point error
+	result.Quantity = math.Floor(quantity*quantityPrecision+1e-6) / quantityPrecision
+	result.Notional = result.Quantity * in.EntryPrice
+	result.PositionPercent = result.Notional / in.Equity * 100
+	if result.StopDistance > 0 {
+		result.StopPrice = math.Max(in.EntryPrice-result.StopDistance, 0)
+		result.RiskAmount = result.Quantity * result.StopDistance
+	}
+	return result, nil
+}
+
+// stopDistance returns the explicit stop distance, or ATRMultiple × atr
+func stopDistance(cfg Config, atr float64) float64 {
+	if cfg.StopDistance > 0 {
+		return cfg.StopDistance
+	}
+	if atr <= 0 {
+		return 0
+	}
+	multiple := cfg.ATRMultiple
+	if multiple == 0 {
+		multiple = defaultATRMultiple
+	}
+	return atr * multiple
+}
+
+// kellyFraction returns the share of equity to bet, which is zero when the
+// strategy has no edge
+func kellyFraction(cfg Config) float64 {
+	full := cfg.WinRate - (1-cfg.WinRate)/cfg.PayoffRatio
+	if full <= 0 {
+		return 0
+	}
+	fraction := cfg.KellyFraction
+	if fraction == 0 {
+		fraction = defaultKellyFraction
+	}
+	return full * fraction
+}
+
+// ATR returns the average true range of the last period candles, which must
+// be oldest first. The candle before them supplies the first previous close.
+func ATR(candles []model.Candle, period int) (float64, error) {
+	if period <= 0 || len(candles) < period+1 {
+		return 0, fmt.Errorf("%w: ATR(%d) needs %d candles", ErrNotEnoughCandles, period, period+1)
+	}
+
+	recent := candles[len(candles)-period-1:]
+	var sum float64
+	for i := 1; i < len(recent); i++ {
+		c, prevClose := recent[i], recent[i-1].ClosePrice
+		sum += math.Max(c.HighPrice-c.LowPrice, math.Max(math.Abs(c.HighPrice-prevClose), math.Abs(c.LowPrice-prevClose)))
+	}
+	return sum / float64(period), nil
+}
+
+// Volatility returns the annualized standard deviation of the log returns of
+// the last period candles, which must be oldest first
+func Volatility(candles []model.Candle, period int, periodsPerYear float64) (float64, error) {
+	if period < 2 || len(candles) < period+1 {
+		return 0, fmt.Errorf("%w: volatility needs at least 3 candles", ErrNotEnoughCandles)
+	}
+
+	recent := candles[len(candles)-period-1:]
+	returns := make([]float64, 0, period)
+	var mean float64
+	for i := 1; i < len(recent); i++ {
+		if recent[i-1].ClosePrice <= 0 || recent[i].ClosePrice <= 0 {
+			return 0, fmt.Errorf("%w: candle without a close price", ErrNotEnoughCandles)
+		}
+		r := math.Log(recent[i].ClosePrice / recent[i-1].ClosePrice)
+		returns = append(returns, r)
+		mean += r
+	}
+	mean /= float64(len(returns))
+
+	var variance float64
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	variance /= float64(len(returns) - 1)
+
+	return math.Sqrt(variance * periodsPerYear), nil
+}
@@ -0,0 +1,155 @@
+package sizing
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// rangeCandles returns daily candles closing at closes, each trading spread
+// around its close
+func rangeCandles(spread float64, closes ...float64) []model.Candle {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	candles := make([]model.Candle, len(closes))
+	for i, c := range closes {
+		candles[i] = model.Candle{
+			Timestamp:  start.AddDate(0, 0, i),
+			HighPrice:  c + spread/2,
+			LowPrice:   c - spread/2,
+			ClosePrice: c,
+		}
+	}
+	return candles
+}
+
+func TestSize_FixedFractional(t *testing.T) {
+	// Risking 1% of 10M with a 500 KRW stop buys 200 units
+	result, err := Size(Config{Method: MethodFixedFractional, RiskPercent: 1, StopDistance: 500},
+		Inputs{Equity: 10000000, EntryPrice: 10000})
+	require.NoError(t, err)
+	assert.InDelta(t, 200, result.Quantity, 1e-9)
+	assert.InDelta(t, 100000, result.RiskAmount, 1e-6)
+	assert.Equal(t, 9500.0, result.StopPrice)
+	assert.InDelta(t, 20, result.PositionPercent, 1e-9)
+	assert.False(t, result.Capped)
+
+	// Without a stop distance the stop is 2 ATRs away
+	result, err = Size(Config{Method: MethodFixedFractional, RiskPercent: 1},
+		Inputs{Equity: 10000000, EntryPrice: 10000, ATR: 250})
+	require.NoError(t, err)
+	assert.InDelta(t, 200, result.Quantity, 1e-9)
+
+	_, err = Size(Config{Method: MethodFixedFractional, RiskPercent: 1}, Inputs{Equity: 10000000, EntryPrice: 10000})
+	assert.ErrorIs(t, err, ErrNoStopDistance)
+
+	// A tight stop is capped at the whole account
+	result, err = Size(Config{Method: MethodFixedFractional, RiskPercent: 2, StopDistance: 10},
+		Inputs{Equity: 1000000, EntryPrice: 10000})
+	require.NoError(t, err)
+	assert.True(t, result.Capped)
+	assert.InDelta(t, 100, result.Quantity, 1e-9)
+}
+
+func TestSize_Kelly(t *testing.T) {
+	// Full Kelly for a 60% win rate at 1:1 is 20%; half Kelly is 10%
+	result, err := Size(Config{Method: MethodKelly, WinRate: 0.6, PayoffRatio: 1},
+		Inputs{Equity: 1000000, EntryPrice: 1000})
+	require.NoError(t, err)
+	assert.InDelta(t, 100, result.Quantity, 1e-6)
+	assert.Zero(t, result.RiskAmount)
+
+	// No edge means no position
+	result, err = Size(Config{Method: MethodKelly, WinRate: 0.4, PayoffRatio: 1},
+		Inputs{Equity: 1000000, EntryPrice: 1000})
+	require.NoError(t, err)
+	assert.Zero(t, result.Quantity)
+
+	_, err = Size(Config{Method: MethodKelly, WinRate: 1, PayoffRatio: 1}, Inputs{Equity: 1000000, EntryPrice: 1000})
+	assert.ErrorIs(t, err, ErrInvalidKelly)
+}
+
+func TestSize_Volatility(t *testing.T) {
+	// Targeting 20% on an asset with 80% volatility holds a quarter of equity
+	result, err := Size(Config{Method: MethodVolatility, TargetVolatility: 0.2},
+		Inputs{Equity: 1000000, EntryPrice: 1000, Volatility: 0.8})
+	require.NoError(t, err)
+	assert.InDelta(t, 250, result.Quantity, 1e-6)
+	assert.InDelta(t, 25, result.PositionPercent, 1e-6)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	assert.ErrorIs(t, Config{Method: "martingale"}.Validate(), ErrUnknownMethod)
+	assert.ErrorIs(t, Config{Method: MethodFixedFractional, RiskPercent: 150}.Validate(), ErrInvalidRisk)
+	assert.ErrorIs(t, Config{Method: MethodVolatility}.Validate(), ErrInvalidVolatility)
+	assert.ErrorIs(t, Config{Method: MethodFixedFractional, RiskPercent: 1, StopDistance: -1}.Validate(), ErrInvalidSizing)
+}
+
+func TestATR(t *testing.T) {
+	// Gaps between closes are smaller than the candle ranges
+	atr, err := ATR(rangeCandles(100, 1000, 1010, 1020, 1030), 3)
+	require.NoError(t, err)
+	assert.InDelta(t, 100, atr, 1e-9)
+
+	// A gap beyond the previous close widens the true range
+	atr, err = ATR(rangeCandles(100, 1000, 1200), 1)
+	require.NoError(t, err)
+	assert.InDelta(t, 250, atr, 1e-9)
+
+	_, err = ATR(rangeCandles(100, 1000, 1010), 2)
+	assert.ErrorIs(t, err, ErrNotEnoughCandles)
+}
+
+func TestVolatility(t *testing.T) {
+	flat, err := Volatility(rangeCandles(0, 100, 100, 100), 2, 365)
+	require.NoError(t, err)
+	assert.Zero(t, flat)
+
+	// Alternating ±r log returns have a sample deviation of r·√(n/(n-1))
+	r := 0.01
+	closes := []float64{100, 100 * math.Exp(r), 100, 100 * math.Exp(r), 100}
+	vol, err := Volatility(rangeCandles(0, closes...), 4, 365)
+	require.NoError(t, err)
+	assert.InDelta(t, r*math.Sqrt(4.0/3)*math.Sqrt(365), vol, 1e-9)
+}
+
+// stubCandles returns fixed candles newest first, like the Upbit API
+type stubCandles struct {
+	candles []model.Candle
+	count   int
+}
+
+func (s *stubCandles) GetCandles(ctx context.Context, market string, interval model.CandleInterval, count int) ([]model.Candle, error) {
+	s.count = count
+	result := make([]model.Candle, len(s.candles))
+	for i, c := range s.candles {
+		result[len(s.candles)-1-i] = c
+	}
+	return result, nil
+}
+
+func TestCalculator_MeasuresMarketData(t *testing.T) {
+	candles := &stubCandles{candles: rangeCandles(100, 1000, 1010, 1020, 1030)}
+	calculator := NewCalculator(candles)
+
+	result, err := calculator.Calculate(context.Background(), Request{
+		Config: Config{Method: MethodFixedFractional, RiskPercent: 1, Period: 3},
+		Equity: 1000000,
+		Market: "KRW-BTC",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 4, candles.count)
+	assert.Equal(t, 200.0, result.StopDistance, "2 ATRs of 100")
+	assert.Equal(t, 830.0, result.StopPrice, "entry defaults to the last close")
+	assert.InDelta(t, 50, result.Quantity, 1e-9)
+
+	_, err = NewCalculator(nil).Calculate(context.Background(), Request{
+		Config: Config{Method: MethodFixedFractional, RiskPercent: 1},
+		Equity: 1000000, EntryPrice: 1000,
+	})
+	assert.ErrorIs(t, err, ErrNoStopDistance)
+}
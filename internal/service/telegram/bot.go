@@ -0,0 +1,276 @@
+// Package telegram runs the Telegram bot: chat commands for linked users and
+// delivery of notifications to their chats
+package telegram
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/notification"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/quotation"
+	"github.com/sungminna/upbit-trading-platform/pkg/cache"
+	"github.com/sungminna/upbit-trading-platform/pkg/recovery"
+	"github.com/sungminna/upbit-trading-platform/pkg/telegram"
+)
+
+const (
+	// LinkCodeTTL is how long a one-time link code stays valid
+	LinkCodeTTL = 10 * time.Minute
+
+	linkCodeLength   = 8
+	linkCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789" // No 0/O or 1/I
+	linkCodeKey      = "telegram:link:"
+
+	pollTimeout = 30 * time.Second
+	retryDelay  = 5 * time.Second
+	// commandTimeout bounds handling a single chat command
+	commandTimeout = 15 * time.Second
+)
+
+// BotAPI is the part of the Telegram Bot API the bot uses
+type BotAPI interface {
+	GetUpdates(ctx context.Context, offset int64, timeout time.Duration) ([]telegram.Update, error)
+	SendMessage(ctx context.Context, chatID int64, text string) error
+}
+
+// OrderCanceller cancels open orders; trading.Engine satisfies it
+type OrderCanceller interface {
+	CancelOrder(ctx context.Context, userID, orderID uuid.UUID) (*model.Order, error)
+}
+
+// TickerSource provides current prices; gateway.QuotationAPI satisfies it
+type TickerSource interface {
+	GetTicker(ctx context.Context, markets []string) ([]quotation.Ticker, error)
+}
+
+// ChannelPostHandler handles posts to channels the bot reads;
+// signals.Service satisfies it
+type ChannelPostHandler interface {
+	HandleChannelPost(ctx context.Context, chatID int64, text string)
+}
+
+// Bot long-polls Telegram for chat commands. Only one instance may poll a bot
+// token at a time, so run it on a single API instance.
+type Bot struct {
+	api       BotAPI
+	links     repository.TelegramLinkRepository
+	codes     cache.Cache
+	positions repository.PositionRepository
+	orders    repository.OrderRepository
+	canceller OrderCanceller // Optional; /cancel is unavailable when nil
+	tickers   TickerSource
+	posts     ChannelPostHandler // Optional; channel posts are ignored when nil
+	mu        sync.Mutex
+	isRunning bool
+	cancel    context.CancelFunc
+	done      chan struct{}
+}
+
+var _ notification.Channel = (*Bot)(nil)
+
+// NewBot creates a new Telegram bot
+func NewBot(
+	api BotAPI,
+	links repository.TelegramLinkRepository,
+	codes cache.Cache,
+	positions repository.PositionRepository,
+	orders repository.OrderRepository,
+	canceller OrderCanceller,
+	tickers TickerSource,
+) *Bot {
+	return &Bot{
+		api:       api,
+		links:     links,
+		codes:     codes,
+		positions: positions,
+		orders:    orders,
+		canceller: canceller,
+		tickers:   tickers,
+	}
+}
+
+// WithChannelPosts hands posts to channels the bot is in to handler, e.g. to
+// read them as trading signals
+func (b *Bot) WithChannelPosts(handler ChannelPostHandler) *Bot {
+	b.posts = handler
+	return b
+}
+
+// NewLinkCode creates a one-time code the user sends to the bot with /link
+func (b *Bot) NewLinkCode(ctx context.Context, userID uuid.UUID) (string, time.Time, error) {
+	buf := make([]byte, linkCodeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to generate link code: %w", err)
+	}
+	for i := range buf {
+		buf[i] = linkCodeAlphabet[int(buf[i])%len(linkCodeAlphabet)]
+	}
+	code := string(buf)
+
+	if err := b.codes.Set(ctx, linkCodeKey+code, []byte(userID.String()), LinkCodeTTL); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to store link code: %w", err)
+	}
+	return code, time.Now().Add(LinkCodeTTL), nil
+}
+
+// Start starts polling for chat commands
+func (b *Bot) Start(ctx context.Context) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.isRunning {
+		return
+	}
+	b.isRunning = true
+
+	ctx, b.cancel = context.WithCancel(ctx)
+	b.done = make(chan struct{})
+	go b.run(ctx, b.done)
+}
+
+// Stop stops polling and waits for the command being handled
+func (b *Bot) Stop() {
+	b.mu.Lock()
+	if !b.isRunning {
+		b.mu.Unlock()
+		return
+	}
+	b.cancel()
+	b.isRunning = false
+	done := b.done
+	b.mu.Unlock()
+
+	<-done
+}
+
+func (b *Bot) run(ctx context.Context, done chan<- struct{}) {
+	defer close(done)
+
+	var offset int64
+	for {
+		updates, err := b.api.GetUpdates(ctx, offset, pollTimeout)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.Printf("Error polling Telegram updates: %v", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(retryDelay):
+			}
+			continue
+		}
+
+		// The offset moves past an update before it is handled, so one that
+		// panics is dropped rather than handled again forever
+		for _, update := range updates {
+			offset = update.UpdateID + 1
+			recovery.Do("telegram.update", func() {
+				if update.Message != nil {
+					b.HandleMessage(ctx, update.Message)
+				}
+				if update.ChannelPost != nil && b.posts != nil {
+					b.handleChannelPost(ctx, update.ChannelPost)
+				}
+			})
+		}
+	}
+}
+
+// HandleMessage runs a chat command and replies to the chat
+func (b *Bot) HandleMessage(ctx context.Context, msg *telegram.Message) {
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	reply := b.execute(ctx, msg)
+	if reply == "" {
+		return
+	}
+	if err := b.api.SendMessage(ctx, msg.Chat.ID, reply); err != nil {
+		log.Printf("Error replying to Telegram chat %d: %v", msg.Chat.ID, err)
+	}
+}
+
+func (b *Bot) handleChannelPost(ctx context.Context, post *telegram.Message) {
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+	b.posts.HandleChannelPost(ctx, post.Chat.ID, post.Text)
+}
+
+// execute runs a command and returns the reply
+func (b *Bot) execute(ctx context.Context, msg *telegram.Message) string {
+	fields := strings.Fields(msg.Text)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		return ""
+	}
+	// Commands in groups may be addressed as /command@botname
+	command, _, _ := strings.Cut(fields[0], "@")
+	args := fields[1:]
+
+	switch command {
+	case "/start", "/help":
+		return helpText
+	case "/link":
+		return b.link(ctx, msg, args)
+	}
+
+	link, err := b.links.GetByChatID(ctx, msg.Chat.ID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return "This chat isn't linked to an account. Create a link code in the app and send /link <code>."
+	}
+	if err != nil {
+		log.Printf("Error loading Telegram link of chat %d: %v", msg.Chat.ID, err)
+		return failedReply
+	}
+
+	switch command {
+	case "/unlink":
+		return b.unlink(ctx, link)
+	case "/positions":
+		return b.listPositions(ctx, link.UserID)
+	case "/pnl":
+		return b.pnl(ctx, link.UserID)
+	case "/orders":
+		return b.listOrders(ctx, link.UserID)
+	case "/cancel":
+		return b.cancelOrder(ctx, link.UserID, args)
+	}
+	return "Unknown command. Send /help for the list of commands."
+}
+
+const helpText = `Commands:
+/link <code> - link this chat to your account
+/unlink - unlink this chat
+/positions - open positions
+/pnl - realized and unrealized profit and loss
+/orders - open orders
+/cancel <order id> - cancel an open order`
+
+// Name returns the notification channel name
+func (b *Bot) Name() string {
+	return "telegram"
+}
+
+// Send delivers a notification to the user's linked chat. Users without a
+// linked chat are skipped.
+func (b *Bot) Send(ctx context.Context, n *model.Notification) error {
+	link, err := b.links.GetByUserID(ctx, n.UserID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return b.api.SendMessage(ctx, link.ChatID, n.Title+"\n"+n.Message)
+}
@@ -0,0 +1,177 @@
+package telegram
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/quotation"
+	"github.com/sungminna/upbit-trading-platform/pkg/cache"
+	"github.com/sungminna/upbit-trading-platform/pkg/telegram"
+)
+
+type stubAPI struct {
+	mu   sync.Mutex
+	sent map[int64][]string
+}
+
+func (a *stubAPI) GetUpdates(ctx context.Context, offset int64, timeout time.Duration) ([]telegram.Update, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (a *stubAPI) SendMessage(ctx context.Context, chatID int64, text string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.sent == nil {
+		a.sent = make(map[int64][]string)
+	}
+	a.sent[chatID] = append(a.sent[chatID], text)
+	return nil
+}
+
+func (a *stubAPI) last(chatID int64) string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	sent := a.sent[chatID]
+	if len(sent) == 0 {
+		return ""
+	}
+	return sent[len(sent)-1]
+}
+
+type stubTickers map[string]float64
+
+func (s stubTickers) GetTicker(ctx context.Context, markets []string) ([]quotation.Ticker, error) {
+	var tickers []quotation.Ticker
+	for _, market := range markets {
+		tickers = append(tickers, quotation.Ticker{Market: market, TradePrice: s[market]})
+	}
+	return tickers, nil
+}
+
+type stubCanceller struct {
+	orders repository.OrderRepository
+}
+
+func (c stubCanceller) CancelOrder(ctx context.Context, userID, orderID uuid.UUID) (*model.Order, error) {
+	order, err := c.orders.GetByID(ctx, orderID)
+	if err != nil || order.UserID != userID {
+		return nil, repository.ErrNotFound
+	}
+	return order, nil
+}
+
+func newTestBot() (*Bot, *stubAPI, *memory.Store) {
+	store := memory.NewStore()
+	api := &stubAPI{}
+	bot := NewBot(api, store.TelegramLinks(), cache.NewMemoryCache(), store.Positions(), store.Orders(),
+		stubCanceller{orders: store.Orders()}, stubTickers{"KRW-BTC": 110000000})
+	return bot, api, store
+}
+
+func send(bot *Bot, chatID int64, text string) {
+	bot.HandleMessage(context.Background(), &telegram.Message{
+		Chat: telegram.Chat{ID: chatID},
+		From: &telegram.User{Username: "trader"},
+		Text: text,
+	})
+}
+
+func TestBot_LinkWithOneTimeCode(t *testing.T) {
+	bot, api, store := newTestBot()
+	ctx := context.Background()
+	userID := uuid.New()
+
+	send(bot, 1, "/positions")
+	assert.Contains(t, api.last(1), "isn't linked")
+
+	code, expiresAt, err := bot.NewLinkCode(ctx, userID)
+	require.NoError(t, err)
+	assert.Len(t, code, linkCodeLength)
+	assert.True(t, expiresAt.After(time.Now()))
+
+	send(bot, 1, "/link "+code)
+	assert.Contains(t, api.last(1), "Linked")
+
+	link, err := store.TelegramLinks().GetByChatID(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, userID, link.UserID)
+	assert.Equal(t, "trader", link.Username)
+
+	// Codes only work once
+	send(bot, 2, "/link "+code)
+	assert.Contains(t, api.last(2), "invalid or has expired")
+
+	send(bot, 1, "/unlink")
+	_, err = store.TelegramLinks().GetByUserID(ctx, userID)
+	assert.ErrorIs(t, err, repository.ErrNotFound)
+}
+
+func TestBot_PositionsAndOrders(t *testing.T) {
+	bot, api, store := newTestBot()
+	ctx := context.Background()
+	userID := uuid.New()
+	require.NoError(t, store.TelegramLinks().Save(ctx, &model.TelegramLink{UserID: userID, ChatID: 1, LinkedAt: time.Now()}))
+
+	position := model.NewPosition(userID, "KRW-BTC", model.PositionSideLong, 100000000, 0.01, time.Now())
+	require.NoError(t, store.Positions().Create(ctx, position))
+
+	send(bot, 1, "/positions@upbit_bot")
+	assert.Contains(t, api.last(1), "KRW-BTC")
+	assert.Contains(t, api.last(1), "+100,000 KRW")
+
+	send(bot, 1, "/pnl")
+	assert.Contains(t, api.last(1), "Unrealized: +100,000 KRW")
+
+	price := 90000000.0
+	order := model.NewOrder(userID, "KRW-BTC", model.OrderSideBid, model.OrderTypeLimit, 0.01, &price, time.Now())
+	order.Status = model.OrderStatusSubmitted
+	require.NoError(t, store.Orders().Create(ctx, order))
+
+	send(bot, 1, "/orders")
+	assert.Contains(t, api.last(1), order.ID.String())
+
+	send(bot, 1, "/cancel "+order.ID.String())
+	assert.Contains(t, api.last(1), "Cancellation requested")
+
+	// Orders of other users aren't visible
+	send(bot, 1, "/cancel "+uuid.New().String())
+	assert.Equal(t, "Order not found.", api.last(1))
+}
+
+func TestBot_SendSkipsUnlinkedUsers(t *testing.T) {
+	bot, api, store := newTestBot()
+	ctx := context.Background()
+	userID := uuid.New()
+
+	n := model.NewNotification(userID, model.NotificationPriceAlert, "KRW-BTC above 100,000,000", "Price is 100,500,000", nil, time.Now())
+	require.NoError(t, bot.Send(ctx, n))
+	assert.Empty(t, api.sent)
+
+	require.NoError(t, store.TelegramLinks().Save(ctx, &model.TelegramLink{UserID: userID, ChatID: 7, LinkedAt: time.Now()}))
+	require.NoError(t, bot.Send(ctx, n))
+	assert.Equal(t, "KRW-BTC above 100,000,000\nPrice is 100,500,000", api.last(7))
+}
+
+func TestBot_StartStop(t *testing.T) {
+	bot, _, _ := newTestBot()
+	bot.Start(context.Background())
+	bot.Stop()
+	bot.Stop()
+}
+
+func TestFormatKRW(t *testing.T) {
+	assert.Equal(t, "0", formatKRW(0))
+	assert.Equal(t, "999", formatKRW(999))
+	assert.Equal(t, "1,000", formatKRW(1000))
+	assert.Equal(t, "-1,234,567", formatKRW(-1234567))
+	assert.Equal(t, "+1,000", formatSignedKRW(1000))
+}
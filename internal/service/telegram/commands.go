@@ -0,0 +1,226 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/trading"
+	"github.com/sungminna/upbit-trading-platform/pkg/cache"
+	"github.com/sungminna/upbit-trading-platform/pkg/telegram"
+)
+
+const failedReply = "Something went wrong, please try again."
+
+// link redeems a one-time code and links the chat to the code's user
+func (b *Bot) link(ctx context.Context, msg *telegram.Message, args []string) string {
+	if len(args) != 1 {
+		return "Usage: /link <code>"
+	}
+	key := linkCodeKey + strings.ToUpper(args[0])
+
+	value, err := b.codes.Get(ctx, key)
+	if errors.Is(err, cache.ErrCacheMiss) {
+		return "That code is invalid or has expired. Create a new one in the app."
+	}
+	if err != nil {
+		log.Printf("Error loading Telegram link code: %v", err)
+		return failedReply
+	}
+	userID, err := uuid.ParseBytes(value)
+	if err != nil {
+		return "That code is invalid or has expired. Create a new one in the app."
+	}
+	if err := b.codes.Delete(ctx, key); err != nil {
+		log.Printf("Error deleting Telegram link code: %v", err)
+	}
+
+	link := &model.TelegramLink{UserID: userID, ChatID: msg.Chat.ID, LinkedAt: time.Now()}
+	if msg.From != nil {
+		link.Username = msg.From.Username
+	}
+	if err := b.links.Save(ctx, link); err != nil {
+		log.Printf("Error saving Telegram link: %v", err)
+		return failedReply
+	}
+	return "Linked! Send /help to see what you can do."
+}
+
+func (b *Bot) unlink(ctx context.Context, link *model.TelegramLink) string {
+	if err := b.links.DeleteByUserID(ctx, link.UserID); err != nil && !errors.Is(err, repository.ErrNotFound) {
+		log.Printf("Error deleting Telegram link: %v", err)
+		return failedReply
+	}
+	return "Unlinked. You won't receive notifications here anymore."
+}
+
+func (b *Bot) listPositions(ctx context.Context, userID uuid.UUID) string {
+	positions, prices, err := b.openPositions(ctx, userID)
+	if err != nil {
+		log.Printf("Error loading positions for Telegram: %v", err)
+		return failedReply
+	}
+	if len(positions) == 0 {
+		return "No open positions."
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Open positions:")
+	for _, p := range positions {
+		fmt.Fprintf(&sb, "\n%s %s %g @ %s", p.Market, p.Side, p.Quantity, formatKRW(p.EntryPrice))
+		if price, ok := prices[p.Market]; ok {
+			fmt.Fprintf(&sb, " → %s (%s KRW)", formatKRW(price), formatSignedKRW(p.CalculateUnrealizedPnL(price)))
+		}
+	}
+	return sb.String()
+}
+
+func (b *Bot) pnl(ctx context.Context, userID uuid.UUID) string {
+	positions, err := b.positions.ListByUser(ctx, userID)
+	if err != nil {
+		log.Printf("Error loading positions for Telegram: %v", err)
+		return failedReply
+	}
+	open, prices, err := b.openPositions(ctx, userID)
+	if err != nil {
+		log.Printf("Error loading prices for Telegram: %v", err)
+		return failedReply
+	}
+
+	var realized, unrealized float64
+	for _, p := range positions {
+		realized += p.RealizedPnL
+	}
+	for _, p := range open {
+		if price, ok := prices[p.Market]; ok {
+			unrealized += p.CalculateUnrealizedPnL(price)
+		}
+	}
+
+	return fmt.Sprintf("Realized: %s KRW\nUnrealized: %s KRW\nTotal: %s KRW",
+		formatSignedKRW(realized), formatSignedKRW(unrealized), formatSignedKRW(realized+unrealized))
+}
+
+// openPositions returns the user's open positions and the current price of
+// their markets
+func (b *Bot) openPositions(ctx context.Context, userID uuid.UUID) ([]*model.Position, map[string]float64, error) {
+	positions, err := b.positions.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var open []*model.Position
+	var markets []string
+	seen := make(map[string]bool)
+	for _, p := range positions {
+		if p.Status != model.PositionStatusOpen {
+			continue
+		}
+		open = append(open, p)
+		if !seen[p.Market] {
+			seen[p.Market] = true
+			markets = append(markets, p.Market)
+		}
+	}
+
+	prices := make(map[string]float64, len(markets))
+	if len(markets) == 0 {
+		return open, prices, nil
+	}
+	tickers, err := b.tickers.GetTicker(ctx, markets)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, t := range tickers {
+		prices[t.Market] = t.TradePrice
+	}
+	return open, prices, nil
+}
+
+func (b *Bot) listOrders(ctx context.Context, userID uuid.UUID) string {
+	orders, err := b.orders.ListByUser(ctx, userID)
+	if err != nil {
+		log.Printf("Error loading orders for Telegram: %v", err)
+		return failedReply
+	}
+
+	var sb strings.Builder
+	for _, o := range orders {
+		if o.Status != model.OrderStatusSubmitted && o.Status != model.OrderStatusPartial && o.Status != model.OrderStatusScheduled {
+			continue
+		}
+		if sb.Len() == 0 {
+			sb.WriteString("Open orders:")
+		}
+		fmt.Fprintf(&sb, "\n%s %s %s %g/%g", o.Market, o.Side, o.Type, o.ExecutedQuantity, o.Quantity)
+		if o.Price != nil {
+			fmt.Fprintf(&sb, " @ %s", formatKRW(*o.Price))
+		}
+		if o.Status == model.OrderStatusScheduled && o.ActivateAt != nil {
+			fmt.Fprintf(&sb, ", scheduled for %s", o.ActivateAt.UTC().Format("2006-01-02 15:04 UTC"))
+		}
+		fmt.Fprintf(&sb, "\n  %s", o.ID)
+	}
+	if sb.Len() == 0 {
+		return "No open orders."
+	}
+	return sb.String()
+}
+
+func (b *Bot) cancelOrder(ctx context.Context, userID uuid.UUID, args []string) string {
+	if b.canceller == nil {
+		return "Order cancellation isn't available."
+	}
+	if len(args) != 1 {
+		return "Usage: /cancel <order id>"
+	}
+	orderID, err := uuid.Parse(args[0])
+	if err != nil {
+		return "That isn't a valid order id. Send /orders to list them."
+	}
+
+	order, err := b.canceller.CancelOrder(ctx, userID, orderID)
+	var tradingErr *trading.TradingError
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return "Order not found."
+	case errors.As(err, &tradingErr):
+		return "Can't cancel: " + err.Error() + "."
+	case err != nil:
+		log.Printf("Error cancelling order %s from Telegram: %v", orderID, err)
+		return "Failed to cancel the order, please try again."
+	}
+	return fmt.Sprintf("Cancellation requested for %s %s order %s.", order.Market, order.Side, order.ID)
+}
+
+// formatKRW formats an amount with thousands separators, e.g. 100,000,000
+func formatKRW(v float64) string {
+	s := fmt.Sprintf("%.0f", v)
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+
+	var sb strings.Builder
+	for i, c := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteRune(c)
+	}
+	return sign + sb.String()
+}
+
+// formatSignedKRW formats an amount like formatKRW with an explicit sign
+func formatSignedKRW(v float64) string {
+	if v >= 0.5 {
+		return "+" + formatKRW(v)
+	}
+	return formatKRW(v)
+}
@@ -0,0 +1,91 @@
+package trading
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/exchange"
+	"github.com/sungminna/upbit-trading-platform/pkg/clock"
+)
+
+// Circuit breaker defaults
+const (
+	defaultBreakerThreshold = 5 // Consecutive connectivity failures
+	defaultBreakerCooldown  = 30 * time.Second
+)
+
+// circuitBreaker stops calls to the exchange after consecutive connectivity
+// failures. Once the cooldown has passed, calls are let through again as
+// trials; a success closes the breaker and a failure restarts the cooldown.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	clock     clock.Clock
+	mu        sync.Mutex
+	failures  int
+	open      bool
+	openedAt  time.Time
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		clock:     clock.Real,
+	}
+}
+
+// allow reports whether a call may be made
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !b.open || b.clock.Now().Sub(b.openedAt) >= b.cooldown
+}
+
+// record records the outcome of a call and reports whether it opened or
+// closed the breaker. Errors the exchange answered, such as a rejected order,
+// show it is reachable and count as successes.
+func (b *circuitBreaker) record(err error) (opened, closed bool) {
+	if errors.Is(err, context.Canceled) {
+		return false, false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !isConnectivityError(err) {
+		b.failures = 0
+		if b.open {
+			b.open = false
+			return false, true
+		}
+		return false, false
+	}
+
+	b.failures++
+	if b.open {
+		b.openedAt = b.clock.Now()
+		return false, false
+	}
+	if b.failures >= b.threshold {
+		b.open = true
+		b.openedAt = b.clock.Now()
+		return true, false
+	}
+	return false, false
+}
+
+// isConnectivityError reports whether err means the exchange couldn't be
+// reached or is failing, as opposed to rejecting a request
+func isConnectivityError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var apiErr *exchange.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.IsTemporary()
+	}
+	return true
+}
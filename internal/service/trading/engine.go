@@ -0,0 +1,778 @@
+package trading
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/gateway"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/notification"
+	"github.com/sungminna/upbit-trading-platform/internal/service/queue"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/exchange"
+	"github.com/sungminna/upbit-trading-platform/pkg/cache"
+	"github.com/sungminna/upbit-trading-platform/pkg/clock"
+	"github.com/sungminna/upbit-trading-platform/pkg/recovery"
+)
+
+const (
+	defaultPollInterval = 2 * time.Second
+	// executionLockTTL bounds how long a crashed instance can block fill processing
+	executionLockTTL = 30 * time.Second
+	// submitTimeout bounds submitting an order in the background, which
+	// outlives the request that placed it
+	submitTimeout = 45 * time.Second
+	// syncTimeout bounds syncing one open order
+	syncTimeout = 30 * time.Second
+	// recordTimeout bounds recording the outcome of work that may have run
+	// out of time itself
+	recordTimeout = 10 * time.Second
+)
+
+// Engine submits orders to Upbit and applies their fills to orders and positions
+type Engine struct {
+	orders            repository.OrderRepository
+	apiKeys           repository.UserAPIKeyRepository
+	uow               repository.UnitOfWork
+	locker            cache.Locker
+	newClient         gateway.ExchangeClientFactory
+	clients           map[uuid.UUID]gateway.ExchangeAPI
+	breaker           *circuitBreaker
+	notifier          notification.Notifier            // Optional
+	risk              RiskChecker                      // Optional
+	halts             repository.TradingHaltRepository // Optional
+	balances          BalanceSource                    // Optional
+	velocityDefaults  model.VelocityLimits
+	velocityOverrides repository.VelocityLimitRepository // Optional; admin overrides of the defaults
+	orderbooks        OrderbookSource                    // Optional; enables exit protection
+	exitProtection    ExitProtection
+	accountingMethod  model.AccountingMethod                // Of new positions
+	queue             *queue.Queue                          // Optional; submits orders through the durable job queue
+	maintenance       MaintenanceMonitor                    // Optional
+	positions         repository.PositionRepository         // Optional; serializes sells of a position
+	strategies        repository.StrategyTemplateRepository // Optional; lets API orders name a strategy
+	rejectedKeys      map[uuid.UUID]bool                    // Users already told their API key was rejected
+	degraded          map[uuid.UUID]bool                    // Users told about the current exchange outage
+	pollInterval      time.Duration
+	clock             clock.Clock
+	mu                sync.RWMutex
+	isRunning         bool
+	stopChan          chan struct{}
+}
+
+// RiskChecker checks new orders against pre-trade risk limits; risk.Service
+// satisfies it. Check may shrink order.Quantity to fit the limits.
+type RiskChecker interface {
+	Check(ctx context.Context, order *model.Order) error
+}
+
+// PlaceOrderRequest represents a request to place an order.
+// Upbit market buys are specified in KRW, so Price is required for them as well
+// and the order spends Quantity * Price.
+type PlaceOrderRequest struct {
+	Market     string          `json:"market" binding:"required"`
+	Side       model.OrderSide `json:"side" binding:"required"`
+	Type       model.OrderType `json:"type" binding:"required"`
+	Quantity   float64         `json:"quantity"`
+	Price      *float64        `json:"price,omitempty"`
+	PositionID *uuid.UUID      `json:"position_id,omitempty"`
+	// ActivateAt schedules the order: it is stored now and submitted once the
+	// time has passed. A time already past places the order at once.
+	ActivateAt *time.Time `json:"activate_at,omitempty"`
+	// Set by the automations that place orders; requests from the API are
+	// always the user's
+	Source   model.OrderSource `json:"-"`
+	SourceID *uuid.UUID        `json:"-"`
+	// StrategyID attributes the order to the strategy placing it; it is
+	// stored on the order and its position events and returned with both.
+	// From the API it must name a template of the strategy catalog.
+	StrategyID *uuid.UUID `json:"strategy_id,omitempty"`
+}
+
+// NewEngine creates a new trading engine
+func NewEngine(
+	orders repository.OrderRepository,
+	apiKeys repository.UserAPIKeyRepository,
+	uow repository.UnitOfWork,
+	locker cache.Locker,
+	newClient gateway.ExchangeClientFactory,
+) *Engine {
+	return &Engine{
+		orders:           orders,
+		apiKeys:          apiKeys,
+		uow:              uow,
+		locker:           locker,
+		newClient:        newClient,
+		clients:          make(map[uuid.UUID]gateway.ExchangeAPI),
+		breaker:          newCircuitBreaker(defaultBreakerThreshold, defaultBreakerCooldown),
+		rejectedKeys:     make(map[uuid.UUID]bool),
+		degraded:         make(map[uuid.UUID]bool),
+		pollInterval:     defaultPollInterval,
+		clock:            clock.Real,
+		accountingMethod: model.AccountingAverage,
+		stopChan:         make(chan struct{}),
+	}
+}
+
+// WithNotifier makes the engine notify users of failed orders, rejected API
+// keys and exchange outages
+func (e *Engine) WithNotifier(notifier notification.Notifier) *Engine {
+	e.notifier = notifier
+	return e
+}
+
+// WithRiskChecker makes the engine check new orders against risk limits
+// before storing them
+func (e *Engine) WithRiskChecker(risk RiskChecker) *Engine {
+	e.risk = risk
+	return e
+}
+
+// WithAccountingMethod sets how new positions account for realized PnL.
+// Open positions keep the method they were opened with.
+func (e *Engine) WithAccountingMethod(method model.AccountingMethod) *Engine {
+	e.accountingMethod = method
+	return e
+}
+
+// WithClock sets the clock orders are stamped, scheduled, halted and limited
+// by, e.g. a fake one in tests
+func (e *Engine) WithClock(c clock.Clock) *Engine {
+	e.clock = c
+	e.breaker.clock = c
+	return e
+}
+
+// Start starts monitoring submitted orders for fills
+func (e *Engine) Start(ctx context.Context) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.isRunning {
+		return
+	}
+	e.isRunning = true
+
+	recovery.Go("trading.monitor", func() { e.monitorOrders(ctx) })
+}
+
+// Stop stops the order monitor
+func (e *Engine) Stop() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !e.isRunning {
+		return
+	}
+
+	close(e.stopChan)
+	e.isRunning = false
+}
+
+// PlaceOrder validates and stores an order, then submits it to the exchange asynchronously
+func (e *Engine) PlaceOrder(ctx context.Context, userID uuid.UUID, req PlaceOrderRequest) (*model.Order, error) {
+	if err := validatePlaceOrderRequest(req, e.clock.Now()); err != nil {
+		return nil, err
+	}
+	if err := e.checkHalt(ctx, userID); err != nil {
+		return nil, err
+	}
+	if err := e.checkVelocity(ctx, userID); err != nil {
+		return nil, err
+	}
+	if err := e.checkStrategy(ctx, req); err != nil {
+		return nil, err
+	}
+
+	// Velocity limits and schedules are checked against the engine's clock
+	order := model.NewOrder(userID, req.Market, req.Side, req.Type, req.Quantity, req.Price, e.clock.Now())
+	order.PositionID = req.PositionID
+	if req.Source != "" {
+		order.Source = req.Source
+		order.SourceID = req.SourceID
+	}
+	order.StrategyID = req.StrategyID
+	if req.ActivateAt != nil && req.ActivateAt.After(e.clock.Now()) {
+		if err := e.scheduleOrder(ctx, order, *req.ActivateAt); err != nil {
+			return nil, err
+		}
+		return order, nil
+	}
+	if err := e.checkMaintenance(order.Market); err != nil {
+		return nil, err
+	}
+
+	// Sells of a position are placed one at a time until stored, so each
+	// sees the others
+	if e.positions != nil && order.PositionID != nil && order.Side == model.OrderSideAsk {
+		lock, err := e.lockPosition(ctx, *order.PositionID)
+		if err != nil {
+			return nil, err
+		}
+		defer lock.Release(ctx)
+		if err := e.fitToPosition(ctx, order); err != nil {
+			return nil, err
+		}
+	}
+
+	if e.risk != nil {
+		if err := e.risk.Check(ctx, order); err != nil {
+			return nil, err
+		}
+	}
+	if err := e.checkFunds(ctx, order); err != nil {
+		return nil, err
+	}
+	e.protectExit(ctx, order)
+
+	if e.queue != nil {
+		if err := e.enqueueOrder(ctx, order); err != nil {
+			return nil, err
+		}
+		return order, nil
+	}
+
+	if err := e.orders.Create(ctx, order); err != nil {
+		return nil, err
+	}
+
+	// Submit a copy so the caller can safely read the returned order
+	submitted := *order
+	recovery.Go("trading.execute", func() { e.executeOrder(ctx, &submitted) })
+
+	return order, nil
+}
+
+// GetOrder returns one of the user's orders
+func (e *Engine) GetOrder(ctx context.Context, userID, orderID uuid.UUID) (*model.Order, error) {
+	order, err := e.orders.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != userID {
+		return nil, repository.ErrNotFound
+	}
+	return order, nil
+}
+
+// CancelOrder requests cancellation of one of the user's open orders. The
+// order is marked cancelled, with any partial fills applied, when the monitor
+// next syncs it. Scheduled orders are cancelled at once.
+func (e *Engine) CancelOrder(ctx context.Context, userID, orderID uuid.UUID) (*model.Order, error) {
+	order, err := e.orders.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.UserID != userID {
+		return nil, repository.ErrNotFound
+	}
+	if order.Status == model.OrderStatusScheduled {
+		return e.cancelScheduledOrder(ctx, order)
+	}
+	if order.ExchangeOrderID == nil || (order.Status != model.OrderStatusSubmitted && order.Status != model.OrderStatusPartial) {
+		return nil, ErrOrderNotOpen
+	}
+
+	client, err := e.clientFor(ctx, order.UserID)
+	if err != nil {
+		return nil, err
+	}
+	err = e.callExchange(order.UserID, func() error {
+		_, err := client.CancelOrder(ctx, *order.ExchangeOrderID)
+		return err
+	})
+	if err == ErrExchangeDown {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel order: %w", err)
+	}
+
+	return order, nil
+}
+
+// executeOrder submits a stored order to the exchange, failing it if that
+// doesn't work. It runs in the background after the request that placed the
+// order returned, so it keeps ctx's values but not its cancellation, and is
+// bounded by submitTimeout instead.
+func (e *Engine) executeOrder(ctx context.Context, order *model.Order) {
+	ctx, cancel := detach(ctx, submitTimeout)
+	defer cancel()
+	err := e.submitOrder(ctx, order)
+	if errors.Is(err, ErrPlacementUnknown) {
+		// It may be live on Upbit; the monitor looks it up again later
+		log.Printf("Order %s left pending: %v", order.ID, err)
+		return
+	}
+	if err != nil {
+		e.failOrder(ctx, order, err)
+	}
+}
+
+// detach returns a context with ctx's values that isn't cancelled with it,
+// bounded by timeout, for work that must finish after its caller is gone
+func detach(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), timeout)
+}
+
+// submitOrder submits a stored order to the exchange, unless trading was
+// halted or maintenance began since it was accepted
+func (e *Engine) submitOrder(ctx context.Context, order *model.Order) error {
+	if err := e.checkHalt(ctx, order.UserID); err != nil {
+		return err
+	}
+	if err := e.checkMaintenance(order.Market); err != nil {
+		return err
+	}
+
+	client, err := e.clientFor(ctx, order.UserID)
+	if err != nil {
+		return err
+	}
+
+	var resp *exchange.OrderResponse
+	err = e.callExchange(order.UserID, func() (err error) {
+		resp, err = client.PlaceOrder(ctx, buildOrderRequest(order))
+		return err
+	})
+	if err != nil && placementUncertain(err) {
+		resp, err = e.findPlaced(ctx, client, order, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	e.recordSubmitted(ctx, order, resp)
+	return nil
+}
+
+// recordSubmitted records that an order was placed on the exchange as resp
+func (e *Engine) recordSubmitted(ctx context.Context, order *model.Order, resp *exchange.OrderResponse) {
+	// Placed on the exchange, so record it even if the submission timed out
+	ctx, cancel := detach(ctx, recordTimeout)
+	defer cancel()
+
+	order.ExchangeOrderID = &resp.UUID
+	if err := order.Transition(model.OrderStatusSubmitted, e.clock.Now()); err != nil {
+		// Placed on the exchange all the same; the monitor syncs its fills
+		log.Printf("Error recording submission of order %s: %v", order.ID, err)
+	}
+
+	err := e.uow.Do(ctx, func(tx repository.Tx) error {
+		if err := tx.Orders().Update(ctx, order); err != nil {
+			return err
+		}
+		return writeOrderEvent(ctx, tx, order)
+	})
+	if err != nil {
+		log.Printf("Error updating submitted order %s: %v", order.ID, err)
+	}
+	e.invalidateBalances(ctx, order.UserID)
+}
+
+// failOrder marks an order as failed and notifies its user
+func (e *Engine) failOrder(ctx context.Context, order *model.Order, cause error) {
+	// Often called because ctx ran out, which mustn't keep the failure from
+	// being recorded
+	ctx, cancel := detach(ctx, recordTimeout)
+	defer cancel()
+
+	log.Printf("Order %s failed: %v", order.ID, cause)
+	if err := order.Transition(model.OrderStatusFailed, e.clock.Now()); err != nil {
+		log.Printf("Error failing order %s: %v", order.ID, err)
+		return
+	}
+	e.notifyOrderFailed(order, cause)
+
+	err := e.uow.Do(ctx, func(tx repository.Tx) error {
+		if err := tx.Orders().Update(ctx, order); err != nil {
+			return err
+		}
+		return writeOrderEvent(ctx, tx, order)
+	})
+	if err != nil {
+		log.Printf("Error marking order %s as failed: %v", order.ID, err)
+	}
+}
+
+// monitorOrders periodically polls open orders for fills
+func (e *Engine) monitorOrders(ctx context.Context) {
+	ticker := time.NewTicker(e.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-e.stopChan:
+			return
+		case <-ticker.C:
+			e.activateDueOrders(ctx)
+			e.syncOpenOrders(ctx)
+			e.resolveUnsubmittedOrders(ctx)
+		}
+	}
+}
+
+// syncOpenOrders fetches the exchange state of every open order and applies it
+func (e *Engine) syncOpenOrders(ctx context.Context) {
+	if !e.breaker.allow() {
+		return // Upbit is unreachable; retry after the cooldown
+	}
+
+	orders, err := e.orders.ListOpen(ctx)
+	if err != nil {
+		log.Printf("Error listing open orders: %v", err)
+		return
+	}
+
+	// A panic on one order doesn't hold up the others
+	for _, order := range orders {
+		recovery.Do("trading.sync", func() {
+			ctx, cancel := context.WithTimeout(ctx, syncTimeout)
+			defer cancel()
+			if err := e.syncOrder(ctx, order); err != nil {
+				log.Printf("Error syncing order %s: %v", order.ID, err)
+			}
+		})
+	}
+}
+
+// syncOrder fetches and applies the exchange state of a single order. Fills are
+// applied under a per-position lock so concurrent instances never interleave
+// read-modify-write cycles on the same position.
+func (e *Engine) syncOrder(ctx context.Context, order *model.Order) error {
+	lock, err := e.locker.Obtain(ctx, executionLockKey(order), executionLockTTL)
+	if err == cache.ErrLockNotObtained {
+		return nil // Another worker is processing this position
+	}
+	if err != nil {
+		return err
+	}
+	defer lock.Release(ctx)
+
+	// From the primary, so fills applied by the instance that held the lock
+	// before are seen and not applied twice
+	order, err = e.orders.GetCurrent(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+	if (order.Status != model.OrderStatusSubmitted && order.Status != model.OrderStatusPartial) || order.ExchangeOrderID == nil {
+		return nil // Completed or cancelled since it was listed
+	}
+
+	client, err := e.clientFor(ctx, order.UserID)
+	if err != nil {
+		return err
+	}
+
+	var resp *exchange.OrderResponse
+	err = e.callExchange(order.UserID, func() (err error) {
+		resp, err = client.GetOrder(ctx, *order.ExchangeOrderID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return e.processOrderUpdate(ctx, order, resp)
+}
+
+// executionLockKey returns the lock key serializing fills of an order's position
+func executionLockKey(order *model.Order) string {
+	if order.PositionID != nil {
+		return "position:" + order.PositionID.String()
+	}
+	return "order:" + order.ID.String()
+}
+
+// processOrderUpdate applies the exchange state of an order. The execution,
+// position and order writes happen in a single transaction so a failure
+// midway never leaves PnL out of sync with the recorded fills.
+func (e *Engine) processOrderUpdate(ctx context.Context, order *model.Order, resp *exchange.OrderResponse) error {
+	executedQty, err := parseDecimal(resp.ExecutedVolume)
+	if err != nil {
+		return fmt.Errorf("invalid executed volume: %w", err)
+	}
+
+	filledQty := executedQty - order.ExecutedQuantity
+	status := exchange.ConvertOrderStatus(resp.State)
+	if filledQty <= 0 && status == order.Status {
+		return nil
+	}
+
+	defer e.invalidateBalances(ctx, order.UserID)
+
+	return e.uow.Do(ctx, func(tx repository.Tx) error {
+		now := e.clock.Now()
+		if filledQty > 0 {
+			previous, err := tx.Executions().ListByOrder(ctx, order.ID)
+			if err != nil {
+				return err
+			}
+
+			price, fee, err := fillPriceAndFee(order, resp, previous, filledQty)
+			if err != nil {
+				return err
+			}
+
+			execution := model.NewOrderExecution(order.ID, price, filledQty, fee, now)
+			if err := tx.Executions().Create(ctx, execution); err != nil {
+				return err
+			}
+
+			if err := applyFillToPosition(ctx, tx, order, price, filledQty, e.accountingMethod, now); err != nil {
+				return err
+			}
+
+			order.UpdateExecution(filledQty, now)
+		}
+
+		switch status {
+		case model.OrderStatusFilled:
+			// Market buys are sized in KRW, so the executed volume rarely matches exactly
+			if !order.IsComplete() {
+				if err := order.Transition(model.OrderStatusFilled, now); err != nil {
+					return err
+				}
+			}
+		case model.OrderStatusCancelled:
+			if err := order.Transition(model.OrderStatusCancelled, now); err != nil {
+				return err
+			}
+		}
+		order.UpdatedAt = now
+
+		if err := tx.Orders().Update(ctx, order); err != nil {
+			return err
+		}
+
+		return writeOrderEvent(ctx, tx, order)
+	})
+}
+
+// writeOrderEvent records the order's transition to its new status in the
+// outbox, dated when the order was last updated
+func writeOrderEvent(ctx context.Context, tx repository.Tx, order *model.Order) error {
+	var eventType string
+	switch order.Status {
+	case model.OrderStatusPending:
+		eventType = model.EventOrderActivated
+	case model.OrderStatusSubmitted:
+		eventType = model.EventOrderSubmitted
+	case model.OrderStatusPartial:
+		eventType = model.EventOrderPartial
+	case model.OrderStatusFilled:
+		eventType = model.EventOrderFilled
+	case model.OrderStatusCancelled:
+		eventType = model.EventOrderCancelled
+	case model.OrderStatusFailed:
+		eventType = model.EventOrderFailed
+	default:
+		return nil
+	}
+
+	event, err := model.NewOrderEvent(eventType, order, order.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	return tx.Outbox().Create(ctx, event)
+}
+
+// applyFillToPosition opens, increases or reduces the order's position at
+// now. New positions use the given accounting method.
+func applyFillToPosition(ctx context.Context, tx repository.Tx, order *model.Order, price, qty float64, method model.AccountingMethod, now time.Time) error {
+	if order.PositionID == nil {
+		// Sells that aren't attached to a position are not tracked
+		if order.Side != model.OrderSideBid {
+			return nil
+		}
+
+		position := model.NewPosition(order.UserID, order.Market, model.PositionSideLong, price, qty, now)
+		position.AccountingMethod = method
+		if err := tx.Positions().Create(ctx, position); err != nil {
+			return err
+		}
+		order.PositionID = &position.ID
+		return recordPositionEvent(ctx, tx, position, model.PositionEventOpened, order, price, qty, now)
+	}
+
+	position, err := tx.Positions().GetByID(ctx, *order.PositionID)
+	if err != nil {
+		return fmt.Errorf("failed to load position %s: %w", *order.PositionID, err)
+	}
+
+	eventType := model.PositionEventIncreased
+	if order.Side == model.OrderSideBid {
+		position.UpdateQuantity(qty, price, now)
+	} else {
+		position.ReduceQuantity(qty, price, now)
+		eventType = model.PositionEventReduced
+		if position.Status == model.PositionStatusClosed {
+			eventType = model.PositionEventClosed
+		}
+	}
+
+	if err := tx.Positions().Update(ctx, position); err != nil {
+		return err
+	}
+	if err := CancelAutomations(ctx, tx, position, price); err != nil {
+		return err
+	}
+	return recordPositionEvent(ctx, tx, position, eventType, order, price, qty, now)
+}
+
+// recordPositionEvent adds a fill to the position's history. The first fill
+// of a strategy's order also records that the strategy attached to it.
+func recordPositionEvent(ctx context.Context, tx repository.Tx, position *model.Position, eventType model.PositionEventType, order *model.Order, price, qty float64, now time.Time) error {
+	if order.StrategyID != nil {
+		events, err := tx.PositionEvents().ListByPosition(ctx, position.ID)
+		if err != nil {
+			return err
+		}
+		attached := slices.ContainsFunc(events, func(e *model.PositionEvent) bool {
+			return e.Type == model.PositionEventStrategyAttached && e.StrategyID != nil && *e.StrategyID == *order.StrategyID
+		})
+		if !attached {
+			if err := tx.PositionEvents().Create(ctx, model.NewPositionEvent(position, model.PositionEventStrategyAttached, order, 0, 0, now)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.PositionEvents().Create(ctx, model.NewPositionEvent(position, eventType, order, price, qty, now))
+}
+
+// fillPriceAndFee derives the price and fee of the newly filled quantity from
+// the cumulative trades and fees reported by the exchange
+func fillPriceAndFee(order *model.Order, resp *exchange.OrderResponse, previous []*model.OrderExecution, filledQty float64) (float64, float64, error) {
+	var prevTotal, prevFee float64
+	for _, execution := range previous {
+		prevTotal += execution.Total
+		prevFee += execution.Fee
+	}
+
+	paidFee, err := parseDecimal(resp.PaidFee)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid paid fee: %w", err)
+	}
+	fee := paidFee - prevFee
+
+	if len(resp.Trades) == 0 {
+		if order.Price == nil {
+			return 0, 0, fmt.Errorf("no trades reported for order %s", order.ID)
+		}
+		return *order.Price, fee, nil
+	}
+
+	var funds float64
+	for _, trade := range resp.Trades {
+		f, err := parseDecimal(trade.Funds)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid trade funds: %w", err)
+		}
+		funds += f
+	}
+
+	return (funds - prevTotal) / filledQty, fee, nil
+}
+
+// clientFor returns a cached exchange client for a user
+func (e *Engine) clientFor(ctx context.Context, userID uuid.UUID) (gateway.ExchangeAPI, error) {
+	e.mu.RLock()
+	client, exists := e.clients[userID]
+	e.mu.RUnlock()
+
+	if exists {
+		return client, nil
+	}
+
+	key, err := e.apiKeys.GetActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load API key: %w", err)
+	}
+
+	client = e.newClient(key.AccessKey, key.SecretKey)
+
+	e.mu.Lock()
+	e.clients[userID] = client
+	e.mu.Unlock()
+
+	return client, nil
+}
+
+// InvalidateClient drops a user's cached exchange client, e.g. after the API key was rotated
+func (e *Engine) InvalidateClient(userID uuid.UUID) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.clients, userID)
+	delete(e.rejectedKeys, userID)
+}
+
+// validatePlaceOrderRequest validates an order request placed at now
+func validatePlaceOrderRequest(req PlaceOrderRequest, now time.Time) error {
+	if req.Side != model.OrderSideBid && req.Side != model.OrderSideAsk {
+		return ErrInvalidSide
+	}
+	if req.Type != model.OrderTypeLimit && req.Type != model.OrderTypeMarket {
+		return ErrInvalidType
+	}
+	if req.Quantity <= 0 {
+		return ErrInvalidQuantity
+	}
+	if req.Price == nil && (req.Type == model.OrderTypeLimit || req.Side == model.OrderSideBid) {
+		return ErrPriceRequired
+	}
+	if req.ActivateAt != nil && req.ActivateAt.Sub(now) > maxScheduleAhead {
+		return ErrInvalidActivateAt
+	}
+	return nil
+}
+
+// buildOrderRequest converts an order to an Upbit order request
+func buildOrderRequest(order *model.Order) exchange.OrderRequest {
+	req := exchange.OrderRequest{
+		Market: order.Market,
+		Side:   string(order.Side),
+		// Finds the order again if placing it gets no answer
+		Identifier: order.ID.String(),
+	}
+
+	switch {
+	case order.Type == model.OrderTypeLimit:
+		volume := formatDecimal(order.Quantity)
+		price := formatDecimal(*order.Price)
+		req.OrdType = "limit"
+		req.Volume = &volume
+		req.Price = &price
+	case order.Side == model.OrderSideBid:
+		// Market buy: Upbit expects the total KRW amount to spend
+		funds := formatDecimal(order.Quantity * *order.Price)
+		req.OrdType = "price"
+		req.Price = &funds
+	default:
+		volume := formatDecimal(order.Quantity)
+		req.OrdType = "market"
+		req.Volume = &volume
+	}
+
+	return req
+}
+
+func parseDecimal(s string) (float64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+func formatDecimal(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
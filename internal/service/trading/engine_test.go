@@ -0,0 +1,569 @@
+package trading
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/gateway"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/exchange"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/fake"
+	"github.com/sungminna/upbit-trading-platform/pkg/cache"
+)
+
+func newTestEngine() (*Engine, *memory.Store) {
+	store := memory.NewStore()
+	engine := NewEngine(store.Orders(), store.APIKeys(), store, cache.NewMemoryCache(), gateway.NewUpbitExchangeClient)
+	return engine, store
+}
+
+func submittedOrder(t *testing.T, store *memory.Store, side model.OrderSide, qty, price float64, positionID *uuid.UUID) *model.Order {
+	order := model.NewOrder(uuid.New(), "KRW-BTC", side, model.OrderTypeLimit, qty, &price, time.Now())
+	exchangeID := uuid.New().String()
+	order.ExchangeOrderID = &exchangeID
+	order.Status = model.OrderStatusSubmitted
+	order.PositionID = positionID
+	require.NoError(t, store.Orders().Create(context.Background(), order))
+	return order
+}
+
+func TestEngine_ProcessOrderUpdate_OpensPositionOnBuyFill(t *testing.T) {
+	engine, store := newTestEngine()
+	ctx := context.Background()
+	order := submittedOrder(t, store, model.OrderSideBid, 0.01, 100000000, nil)
+
+	err := engine.processOrderUpdate(ctx, order, &exchange.OrderResponse{
+		State:          "done",
+		ExecutedVolume: "0.01",
+		PaidFee:        "500",
+		Trades:         []exchange.Trade{{Funds: "1000000", Volume: "0.01"}},
+	})
+	require.NoError(t, err)
+
+	stored, err := store.Orders().GetByID(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderStatusFilled, stored.Status)
+	require.NotNil(t, stored.PositionID)
+
+	position, err := store.Positions().GetByID(ctx, *stored.PositionID)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.01, position.Quantity, 1e-12)
+	assert.InDelta(t, 100000000, position.EntryPrice, 1e-6)
+
+	executions, err := store.Executions().ListByOrder(ctx, order.ID)
+	require.NoError(t, err)
+	require.Len(t, executions, 1)
+	assert.InDelta(t, 500, executions[0].Fee, 1e-9)
+
+	events, err := store.Outbox().ListPending(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, model.EventOrderFilled, events[0].EventType)
+}
+
+func TestEngine_ProcessOrderUpdate_PartialFills(t *testing.T) {
+	engine, store := newTestEngine()
+	ctx := context.Background()
+	order := submittedOrder(t, store, model.OrderSideBid, 0.01, 100000000, nil)
+
+	require.NoError(t, engine.processOrderUpdate(ctx, order, &exchange.OrderResponse{
+		State:          "wait",
+		ExecutedVolume: "0.004",
+		PaidFee:        "200",
+		Trades:         []exchange.Trade{{Funds: "400000", Volume: "0.004"}},
+	}))
+	assert.Equal(t, model.OrderStatusPartial, order.Status)
+
+	require.NoError(t, engine.processOrderUpdate(ctx, order, &exchange.OrderResponse{
+		State:          "done",
+		ExecutedVolume: "0.01",
+		PaidFee:        "500",
+		Trades: []exchange.Trade{
+			{Funds: "400000", Volume: "0.004"},
+			{Funds: "600000", Volume: "0.006"},
+		},
+	}))
+
+	executions, err := store.Executions().ListByOrder(ctx, order.ID)
+	require.NoError(t, err)
+	require.Len(t, executions, 2)
+	assert.InDelta(t, 0.006, executions[1].Quantity, 1e-12)
+	assert.InDelta(t, 300, executions[1].Fee, 1e-9)
+
+	position, err := store.Positions().GetByID(ctx, *order.PositionID)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.01, position.Quantity, 1e-12)
+	assert.Equal(t, model.OrderStatusFilled, order.Status)
+}
+
+func TestEngine_ProcessOrderUpdate_SellRealizesPnL(t *testing.T) {
+	engine, store := newTestEngine()
+	ctx := context.Background()
+
+	position := model.NewPosition(uuid.New(), "KRW-BTC", model.PositionSideLong, 100000000, 0.01, time.Now())
+	require.NoError(t, store.Positions().Create(ctx, position))
+	order := submittedOrder(t, store, model.OrderSideAsk, 0.01, 110000000, &position.ID)
+
+	require.NoError(t, engine.processOrderUpdate(ctx, order, &exchange.OrderResponse{
+		State:          "done",
+		ExecutedVolume: "0.01",
+		PaidFee:        "550",
+		Trades:         []exchange.Trade{{Funds: "1100000", Volume: "0.01"}},
+	}))
+
+	stored, err := store.Positions().GetByID(ctx, position.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.PositionStatusClosed, stored.Status)
+	assert.InDelta(t, 100000, stored.RealizedPnL, 1e-6)
+}
+
+func TestEngine_ProcessOrderUpdate_ClosingCancelsTheGuard(t *testing.T) {
+	engine, store := newTestEngine()
+	ctx := context.Background()
+
+	position := model.NewPosition(uuid.New(), "KRW-BTC", model.PositionSideLong, 100000000, 0.01, time.Now())
+	require.NoError(t, store.Positions().Create(ctx, position))
+	require.NoError(t, store.DrawdownGuards().Save(ctx, model.NewDrawdownGuard(position, 10, 30, time.Now())))
+	order := submittedOrder(t, store, model.OrderSideAsk, 0.01, 110000000, &position.ID)
+
+	require.NoError(t, engine.processOrderUpdate(ctx, order, &exchange.OrderResponse{
+		State:          "done",
+		ExecutedVolume: "0.01",
+		Trades:         []exchange.Trade{{Funds: "1100000", Volume: "0.01"}},
+	}))
+
+	guard, err := store.DrawdownGuards().GetByPosition(ctx, position.ID)
+	require.NoError(t, err)
+	assert.False(t, guard.Active)
+	assert.Nil(t, guard.TriggeredAt)
+
+	events, err := store.Outbox().ListPending(ctx, 10)
+	require.NoError(t, err)
+	var types []string
+	for _, event := range events {
+		types = append(types, event.EventType)
+	}
+	assert.ElementsMatch(t, []string{model.EventDrawdownGuardCancelled, model.EventOrderFilled}, types)
+}
+
+func TestEngine_ProcessOrderUpdate_FIFOLots(t *testing.T) {
+	engine, store := newTestEngine()
+	engine.WithAccountingMethod(model.AccountingFIFO)
+	ctx := context.Background()
+
+	buy := submittedOrder(t, store, model.OrderSideBid, 0.01, 100000000, nil)
+	require.NoError(t, engine.processOrderUpdate(ctx, buy, &exchange.OrderResponse{
+		State: "done", ExecutedVolume: "0.01", Trades: []exchange.Trade{{Funds: "1000000", Volume: "0.01"}},
+	}))
+	more := submittedOrder(t, store, model.OrderSideBid, 0.01, 120000000, buy.PositionID)
+	require.NoError(t, engine.processOrderUpdate(ctx, more, &exchange.OrderResponse{
+		State: "done", ExecutedVolume: "0.01", Trades: []exchange.Trade{{Funds: "1200000", Volume: "0.01"}},
+	}))
+	sell := submittedOrder(t, store, model.OrderSideAsk, 0.01, 115000000, buy.PositionID)
+	require.NoError(t, engine.processOrderUpdate(ctx, sell, &exchange.OrderResponse{
+		State: "done", ExecutedVolume: "0.01", Trades: []exchange.Trade{{Funds: "1150000", Volume: "0.01"}},
+	}))
+
+	position, err := store.Positions().GetByID(ctx, *buy.PositionID)
+	require.NoError(t, err)
+	assert.Equal(t, model.AccountingFIFO, position.AccountingMethod)
+	assert.InDelta(t, 150000, position.RealizedPnL, 1e-6, "sold against the oldest lot")
+	require.Len(t, position.Lots, 1)
+	assert.InDelta(t, 120000000, position.EntryPrice, 1e-6)
+	assert.InDelta(t, 0.01, position.Quantity, 1e-12)
+}
+
+func TestEngine_ProcessOrderUpdate_RecordsPositionHistory(t *testing.T) {
+	engine, store := newTestEngine()
+	ctx := context.Background()
+	strategyID := uuid.New()
+
+	buy := submittedOrder(t, store, model.OrderSideBid, 0.01, 100000000, nil)
+	require.NoError(t, engine.processOrderUpdate(ctx, buy, &exchange.OrderResponse{
+		State: "done", ExecutedVolume: "0.01", Trades: []exchange.Trade{{Funds: "1000000", Volume: "0.01"}},
+	}))
+	more := submittedOrder(t, store, model.OrderSideBid, 0.01, 120000000, buy.PositionID)
+	more.StrategyID = &strategyID
+	require.NoError(t, engine.processOrderUpdate(ctx, more, &exchange.OrderResponse{
+		State: "done", ExecutedVolume: "0.01", Trades: []exchange.Trade{{Funds: "1200000", Volume: "0.01"}},
+	}))
+	for _, fill := range []exchange.Trade{{Funds: "575000", Volume: "0.005"}, {Funds: "1725000", Volume: "0.015"}} {
+		qty, err := strconv.ParseFloat(fill.Volume, 64)
+		require.NoError(t, err)
+		sell := submittedOrder(t, store, model.OrderSideAsk, qty, 115000000, buy.PositionID)
+		sell.StrategyID = &strategyID
+		require.NoError(t, engine.processOrderUpdate(ctx, sell, &exchange.OrderResponse{
+			State: "done", ExecutedVolume: fill.Volume, Trades: []exchange.Trade{fill},
+		}))
+	}
+
+	events, err := store.PositionEvents().ListByPosition(ctx, *buy.PositionID)
+	require.NoError(t, err)
+	var types []model.PositionEventType
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	assert.Equal(t, []model.PositionEventType{
+		model.PositionEventOpened,
+		model.PositionEventStrategyAttached,
+		model.PositionEventIncreased,
+		model.PositionEventReduced,
+		model.PositionEventClosed,
+	}, types, "the strategy attaches once")
+
+	assert.Equal(t, buy.ID, *events[0].OrderID)
+	assert.InDelta(t, 0.01, events[0].PositionQuantity, 1e-12)
+	assert.Equal(t, strategyID, *events[1].StrategyID)
+	assert.InDelta(t, 120000000, events[2].Price, 1e-6)
+	assert.InDelta(t, 110000000, events[2].EntryPrice, 1e-6)
+	assert.InDelta(t, 0.015, events[3].PositionQuantity, 1e-12)
+	assert.InDelta(t, 0, events[4].PositionQuantity, 1e-12)
+}
+
+func TestEngine_ProcessOrderUpdate_RollsBackOnFailure(t *testing.T) {
+	engine, store := newTestEngine()
+	ctx := context.Background()
+
+	missingPosition := uuid.New()
+	order := submittedOrder(t, store, model.OrderSideAsk, 0.01, 110000000, &missingPosition)
+
+	err := engine.processOrderUpdate(ctx, order, &exchange.OrderResponse{
+		State:          "done",
+		ExecutedVolume: "0.01",
+		Trades:         []exchange.Trade{{Funds: "1100000", Volume: "0.01"}},
+	})
+	require.Error(t, err)
+
+	executions, err := store.Executions().ListByOrder(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Empty(t, executions)
+
+	stored, err := store.Orders().GetByID(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderStatusSubmitted, stored.Status)
+	assert.Zero(t, stored.ExecutedQuantity)
+}
+
+func TestEngine_ProcessOrderUpdate_RejectsIllegalTransitions(t *testing.T) {
+	engine, store := newTestEngine()
+	ctx := context.Background()
+	order := submittedOrder(t, store, model.OrderSideBid, 0.01, 100000000, nil)
+	order.Status = model.OrderStatusFailed
+	require.NoError(t, store.Orders().Update(ctx, order))
+
+	err := engine.processOrderUpdate(ctx, order, &exchange.OrderResponse{State: "cancel", ExecutedVolume: "0"})
+	var transitionErr *model.OrderTransitionError
+	require.ErrorAs(t, err, &transitionErr)
+	assert.Equal(t, model.OrderStatusFailed, transitionErr.From)
+	assert.Equal(t, model.OrderStatusCancelled, transitionErr.To)
+
+	stored, err := store.Orders().GetByID(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderStatusFailed, stored.Status)
+	events, err := store.Outbox().ListPending(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func TestEngine_PlaceOrderAgainstFakeExchange(t *testing.T) {
+	server := fake.NewServer("access", "secret")
+	defer server.Close()
+
+	store := memory.NewStore()
+	engine := NewEngine(store.Orders(), store.APIKeys(), store, cache.NewMemoryCache(),
+		func(accessKey, secretKey string) gateway.ExchangeAPI {
+			return exchange.NewClientWithBaseURL(accessKey, secretKey, server.URL())
+		})
+
+	ctx := context.Background()
+	userID := uuid.New()
+	require.NoError(t, store.APIKeys().Create(ctx, model.NewUserAPIKey(userID, "access", "secret", "test", time.Now())))
+
+	price := 100000000.0
+	order, err := engine.PlaceOrder(ctx, userID, PlaceOrderRequest{
+		Market:   "KRW-BTC",
+		Side:     model.OrderSideBid,
+		Type:     model.OrderTypeLimit,
+		Quantity: 0.01,
+		Price:    &price,
+	})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		stored, err := store.Orders().GetByID(ctx, order.ID)
+		return err == nil && stored.Status == model.OrderStatusSubmitted
+	}, time.Second, 10*time.Millisecond)
+
+	engine.syncOpenOrders(ctx)
+
+	stored, err := store.Orders().GetByID(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderStatusFilled, stored.Status)
+	require.NotNil(t, stored.PositionID)
+
+	// Every transition is in the outbox
+	events, err := store.Outbox().ListPending(ctx, 10)
+	require.NoError(t, err)
+	var types []string
+	for _, event := range events {
+		types = append(types, event.EventType)
+	}
+	assert.Equal(t, []string{model.EventOrderSubmitted, model.EventOrderFilled}, types)
+
+	positions, err := store.Positions().ListByUser(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, positions, 1)
+	assert.InDelta(t, 0.01, positions[0].Quantity, 1e-12)
+}
+
+func TestEngine_SyncRereadsTheOrderUnderTheLock(t *testing.T) {
+	engine, store, server, userID := newFakeExchangeEngine(t)
+	ctx := context.Background()
+
+	price := 100000000.0
+	order, err := engine.PlaceOrder(ctx, userID, PlaceOrderRequest{
+		Market: "KRW-BTC", Side: model.OrderSideBid, Type: model.OrderTypeLimit, Quantity: 0.01, Price: &price,
+	})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		stored, err := store.Orders().GetByID(ctx, order.ID)
+		return err == nil && stored.Status == model.OrderStatusSubmitted
+	}, time.Second, 10*time.Millisecond)
+
+	// Listed before another instance applied the fill
+	stale, err := store.Orders().GetByID(ctx, order.ID)
+	require.NoError(t, err)
+	require.NoError(t, server.Fill(*stale.ExchangeOrderID, 0.01, price))
+	engine.syncOpenOrders(ctx)
+
+	require.NoError(t, engine.syncOrder(ctx, stale))
+
+	executions, err := store.Executions().ListByOrder(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Len(t, executions, 1)
+	positions, err := store.Positions().ListByUser(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, positions, 1)
+	assert.InDelta(t, 0.01, positions[0].Quantity, 1e-12)
+}
+
+func TestEngine_PlaceOrderRecordsItsStrategy(t *testing.T) {
+	engine, store, _, userID := newFakeExchangeEngine(t)
+	engine.WithStrategies(store.StrategyTemplates())
+	ctx := context.Background()
+	template := &model.StrategyTemplate{
+		ID: uuid.New(), PublisherID: uuid.New(), Name: "Trail 10%", Type: model.StrategyTemplateDrawdownGuard,
+		Params: map[string]float64{"trail_percent": 10}, CreatedAt: time.Now(), UpdatedAt: time.Now(),
+	}
+	require.NoError(t, store.StrategyTemplates().Create(ctx, template))
+	strategyID := template.ID
+
+	price := 100000000.0
+	order, err := engine.PlaceOrder(ctx, userID, PlaceOrderRequest{
+		Market: "KRW-BTC", Side: model.OrderSideBid, Type: model.OrderTypeLimit, Quantity: 0.01, Price: &price,
+		StrategyID: &strategyID,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, order.StrategyID)
+	assert.Equal(t, strategyID, *order.StrategyID)
+
+	stored, err := store.Orders().GetByID(ctx, order.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.StrategyID)
+	assert.Equal(t, strategyID, *stored.StrategyID)
+
+	// The order APIs return it
+	data, err := json.Marshal(stored)
+	require.NoError(t, err)
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(data, &body))
+	assert.Equal(t, strategyID.String(), body["strategy_id"])
+}
+
+func TestEngine_PlaceOrderRejectsUnknownStrategies(t *testing.T) {
+	engine, store, _, userID := newFakeExchangeEngine(t)
+	ctx := context.Background()
+	strategyID := uuid.New()
+	price := 100000000.0
+	req := PlaceOrderRequest{
+		Market: "KRW-BTC", Side: model.OrderSideBid, Type: model.OrderTypeLimit, Quantity: 0.01, Price: &price,
+		StrategyID: &strategyID,
+	}
+
+	// Without the catalog, or naming a template that isn't in it
+	_, err := engine.PlaceOrder(ctx, userID, req)
+	assert.ErrorIs(t, err, ErrUnknownStrategy)
+	engine.WithStrategies(store.StrategyTemplates())
+	_, err = engine.PlaceOrder(ctx, userID, req)
+	assert.ErrorIs(t, err, ErrUnknownStrategy)
+
+	// Automations keep the strategy of a deleted template
+	req.Source = model.OrderSourceDrawdownGuard
+	order, err := engine.PlaceOrder(ctx, userID, req)
+	require.NoError(t, err)
+	assert.Equal(t, strategyID, *order.StrategyID)
+}
+
+func TestEngine_SubmitsAfterTheRequestEnds(t *testing.T) {
+	server := fake.NewServer("access", "secret")
+	defer server.Close()
+
+	store := memory.NewStore()
+	engine := NewEngine(store.Orders(), store.APIKeys(), store, cache.NewMemoryCache(),
+		func(accessKey, secretKey string) gateway.ExchangeAPI {
+			return exchange.NewClientWithBaseURL(accessKey, secretKey, server.URL())
+		})
+
+	userID := uuid.New()
+	require.NoError(t, store.APIKeys().Create(context.Background(), model.NewUserAPIKey(userID, "access", "secret", "test", time.Now())))
+
+	// The handler's context is cancelled as soon as it responds
+	ctx, cancel := context.WithCancel(context.Background())
+	price := 100000000.0
+	order, err := engine.PlaceOrder(ctx, userID, PlaceOrderRequest{
+		Market:   "KRW-BTC",
+		Side:     model.OrderSideBid,
+		Type:     model.OrderTypeLimit,
+		Quantity: 0.01,
+		Price:    &price,
+	})
+	cancel()
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		stored, err := store.Orders().GetByID(context.Background(), order.ID)
+		return err == nil && stored.Status == model.OrderStatusSubmitted
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestEngine_CancelOrderAgainstFakeExchange(t *testing.T) {
+	server := fake.NewServer("access", "secret")
+	defer server.Close()
+	server.SetFillMode(fake.FillManually)
+
+	store := memory.NewStore()
+	engine := NewEngine(store.Orders(), store.APIKeys(), store, cache.NewMemoryCache(),
+		func(accessKey, secretKey string) gateway.ExchangeAPI {
+			return exchange.NewClientWithBaseURL(accessKey, secretKey, server.URL())
+		})
+
+	ctx := context.Background()
+	userID := uuid.New()
+	require.NoError(t, store.APIKeys().Create(ctx, model.NewUserAPIKey(userID, "access", "secret", "test", time.Now())))
+
+	price := 100000000.0
+	order, err := engine.PlaceOrder(ctx, userID, PlaceOrderRequest{
+		Market:   "KRW-BTC",
+		Side:     model.OrderSideBid,
+		Type:     model.OrderTypeLimit,
+		Quantity: 0.01,
+		Price:    &price,
+	})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		stored, err := store.Orders().GetByID(ctx, order.ID)
+		return err == nil && stored.Status == model.OrderStatusSubmitted
+	}, time.Second, 10*time.Millisecond)
+
+	_, err = engine.CancelOrder(ctx, uuid.New(), order.ID)
+	assert.ErrorIs(t, err, repository.ErrNotFound, "other users can't cancel it")
+
+	_, err = engine.CancelOrder(ctx, userID, order.ID)
+	require.NoError(t, err)
+
+	engine.syncOpenOrders(ctx)
+	stored, err := store.Orders().GetByID(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderStatusCancelled, stored.Status)
+
+	_, err = engine.CancelOrder(ctx, userID, order.ID)
+	assert.ErrorIs(t, err, ErrOrderNotOpen)
+}
+
+type riskCheckerFunc func(ctx context.Context, order *model.Order) error
+
+func (f riskCheckerFunc) Check(ctx context.Context, order *model.Order) error {
+	return f(ctx, order)
+}
+
+func TestEngine_PlaceOrderAppliesRiskChecks(t *testing.T) {
+	engine, store := newTestEngine()
+	ctx := context.Background()
+	rejected := errors.New("too large")
+	engine.WithRiskChecker(riskCheckerFunc(func(ctx context.Context, order *model.Order) error {
+		if order.Market == "KRW-ETH" {
+			return rejected
+		}
+		order.Quantity /= 2
+		return nil
+	}))
+
+	userID := uuid.New()
+	price := 100000000.0
+	_, err := engine.PlaceOrder(ctx, userID, PlaceOrderRequest{Market: "KRW-ETH", Side: model.OrderSideBid, Type: model.OrderTypeLimit, Quantity: 1, Price: &price})
+	assert.ErrorIs(t, err, rejected)
+
+	order, err := engine.PlaceOrder(ctx, userID, PlaceOrderRequest{Market: "KRW-BTC", Side: model.OrderSideBid, Type: model.OrderTypeLimit, Quantity: 1, Price: &price})
+	require.NoError(t, err)
+	assert.Equal(t, 0.5, order.Quantity)
+
+	orders, err := store.Orders().ListByUser(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	assert.Equal(t, "KRW-BTC", orders[0].Market)
+}
+
+func TestValidatePlaceOrderRequest(t *testing.T) {
+	price := 100000000.0
+
+	tests := []struct {
+		name    string
+		req     PlaceOrderRequest
+		wantErr error
+	}{
+		{
+			name: "valid limit order",
+			req:  PlaceOrderRequest{Market: "KRW-BTC", Side: model.OrderSideBid, Type: model.OrderTypeLimit, Quantity: 0.01, Price: &price},
+		},
+		{
+			name: "valid market sell without price",
+			req:  PlaceOrderRequest{Market: "KRW-BTC", Side: model.OrderSideAsk, Type: model.OrderTypeMarket, Quantity: 0.01},
+		},
+		{
+			name:    "market buy requires price",
+			req:     PlaceOrderRequest{Market: "KRW-BTC", Side: model.OrderSideBid, Type: model.OrderTypeMarket, Quantity: 0.01},
+			wantErr: ErrPriceRequired,
+		},
+		{
+			name:    "quantity must be positive",
+			req:     PlaceOrderRequest{Market: "KRW-BTC", Side: model.OrderSideAsk, Type: model.OrderTypeMarket},
+			wantErr: ErrInvalidQuantity,
+		},
+		{
+			name:    "invalid side",
+			req:     PlaceOrderRequest{Market: "KRW-BTC", Side: "buy", Type: model.OrderTypeMarket, Quantity: 1},
+			wantErr: ErrInvalidSide,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePlaceOrderRequest(tt.req, time.Now())
+			if tt.wantErr == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package trading
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+var (
+	ErrInvalidQuantity   = &TradingError{message: "quantity must be positive"}
+	ErrPriceRequired     = &TradingError{message: "price is required for limit orders and market buys"}
+	ErrInvalidSide       = &TradingError{message: "side must be bid or ask"}
+	ErrInvalidType       = &TradingError{message: "type must be limit or market"}
+	ErrOrderNotOpen      = &TradingError{message: "order is not open on the exchange"}
+	ErrExchangeDown      = &TradingError{message: "exchange is unavailable, try again later"}
+	ErrTradingHalted     = &TradingError{message: "trading is halted"}
+	ErrNotHalted         = &TradingError{message: "trading is not halted"}
+	ErrHaltsDisabled     = &TradingError{message: "trading halts are not configured"}
+	ErrInsufficientFunds = &TradingError{message: "insufficient funds"}
+	ErrInvalidActivateAt = &TradingError{message: "activate_at must be within 30 days"}
+	ErrOrderActivating   = &TradingError{message: "order is being activated, try again shortly"}
+	ErrMaintenance       = &TradingError{message: "Upbit is under maintenance, try again later"}
+	ErrPositionClosing   = &TradingError{message: "position is already closed or being sold"}
+	ErrPositionBusy      = &TradingError{message: "another order is being placed for the position, try again shortly"}
+	ErrUnknownStrategy   = &TradingError{message: "strategy_id must name a strategy template"}
+
+	ErrSubmissionInterrupted = &TradingError{message: "order submission was interrupted and may have reached the exchange; check your open orders before placing it again"}
+	ErrSubmissionLost        = &TradingError{message: "order submission was interrupted before it reached the exchange; place it again if you still want it"}
+	ErrPlacementUnknown      = &TradingError{message: "the exchange did not answer whether it took the order"}
+
+	ErrVelocityLimit          = &TradingError{message: "order rate limit reached"}
+	ErrInvalidVelocityLimits  = &TradingError{message: "velocity limits must not be negative"}
+	ErrVelocityLimitsDisabled = &TradingError{message: "velocity limits are not configured"}
+)
+
+// TradingError represents a trading engine error
+type TradingError struct {
+	message string
+}
+
+func (e *TradingError) Error() string {
+	return e.message
+}
+
+// InsufficientFundsError reports an order the user's free balance can't cover.
+// It matches ErrInsufficientFunds with errors.Is.
+type InsufficientFundsError struct {
+	Currency  string  `json:"currency"`
+	Required  float64 `json:"required"`
+	Available float64 `json:"available"`
+}
+
+func (e *InsufficientFundsError) Error() string {
+	return fmt.Sprintf("insufficient funds: order needs %s %s, %s available",
+		formatDecimal(e.Required), e.Currency, formatDecimal(e.Available))
+}
+
+// Is reports whether target is ErrInsufficientFunds
+func (e *InsufficientFundsError) Is(target error) bool {
+	return target == ErrInsufficientFunds
+}
+
+// VelocityLimitError reports an order over the user's order rate limit. It
+// matches ErrVelocityLimit with errors.Is.
+type VelocityLimitError struct {
+	Limit      int           `json:"limit"`
+	Window     time.Duration `json:"window"`
+	RetryAfter time.Duration `json:"retry_after"`
+}
+
+func (e *VelocityLimitError) Error() string {
+	return fmt.Sprintf("order rate limit reached: at most %d orders per %s, retry in %s",
+		e.Limit, windowName(e.Window), e.RetryAfter.Round(time.Second))
+}
+
+// Is reports whether target is ErrVelocityLimit
+func (e *VelocityLimitError) Is(target error) bool {
+	return target == ErrVelocityLimit
+}
+
+// MaintenanceError reports an order for a market under Upbit maintenance. It
+// matches ErrMaintenance and, as the exchange can't take orders, ErrExchangeDown
+// with errors.Is.
+type MaintenanceError struct {
+	Maintenance model.Maintenance `json:"maintenance"`
+}
+
+func (e *MaintenanceError) Error() string {
+	if e.Maintenance.Until != nil {
+		return fmt.Sprintf("Upbit is under maintenance until %s: %s", e.Maintenance.Until.Format(time.RFC3339), e.Maintenance.Reason)
+	}
+	return fmt.Sprintf("Upbit is under maintenance: %s", e.Maintenance.Reason)
+}
+
+// Is reports whether target is ErrMaintenance or ErrExchangeDown
+func (e *MaintenanceError) Is(target error) bool {
+	return target == ErrMaintenance || target == ErrExchangeDown
+}
+
+func windowName(window time.Duration) string {
+	switch window {
+	case time.Minute:
+		return "minute"
+	case time.Hour:
+		return "hour"
+	}
+	return window.String()
+}
@@ -0,0 +1,103 @@
+package trading
+
+import (
+	"context"
+	"log"
+	"math"
+	"strings"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// OrderbookSource provides current orderbooks; gateway.QuotationAPI satisfies it
+type OrderbookSource interface {
+	GetOrderbook(ctx context.Context, market string) (*model.Orderbook, error)
+}
+
+// ExitProtection converts market sells into aggressive limit sells while a
+// market's spread is wide, so thin books can't fill an exit far below the
+// best bid. Whatever the limit doesn't fill stays on the book.
+type ExitProtection struct {
+	MaxSpreadBps float64 // Spread, in basis points of the mid price, above which market sells are converted
+	CrossBps     float64 // How far below the best bid the limit price is set, in basis points
+}
+
+// WithExitProtection makes the engine convert market sells on KRW markets
+// into limit sells when the spread exceeds protection.MaxSpreadBps
+func (e *Engine) WithExitProtection(orderbooks OrderbookSource, protection ExitProtection) *Engine {
+	e.orderbooks = orderbooks
+	e.exitProtection = protection
+	return e
+}
+
+// protectExit converts a market sell into a limit sell crossing the spread
+// by CrossBps when the spread is too wide. The order is left as it is when
+// the orderbook can't be loaded.
+func (e *Engine) protectExit(ctx context.Context, order *model.Order) {
+	if e.orderbooks == nil || e.exitProtection.MaxSpreadBps <= 0 {
+		return
+	}
+	if order.Side != model.OrderSideAsk || order.Type != model.OrderTypeMarket || !strings.HasPrefix(order.Market, "KRW-") {
+		return
+	}
+
+	orderbook, err := e.orderbooks.GetOrderbook(ctx, order.Market)
+	if err != nil {
+		log.Printf("Skipping exit protection of order for user %s: %v", order.UserID, err)
+		return
+	}
+	if len(orderbook.OrderbookUnits) == 0 {
+		return
+	}
+
+	best := orderbook.OrderbookUnits[0]
+	if best.BidPrice <= 0 || best.AskPrice <= 0 {
+		return
+	}
+	spread := (best.AskPrice - best.BidPrice) / ((best.AskPrice + best.BidPrice) / 2) * 10000
+	if spread <= e.exitProtection.MaxSpreadBps {
+		return
+	}
+
+	price := FloorToTick(best.BidPrice * (1 - e.exitProtection.CrossBps/10000))
+	if price <= 0 {
+		return
+	}
+	order.Type = model.OrderTypeLimit
+	order.Price = &price
+	log.Printf("Converted market sell of %s to a limit at %s: spread is %.0f bps", order.Market, formatDecimal(price), spread)
+}
+
+// krwTickSizes are Upbit's KRW market price units, by the lowest price they
+// apply from
+var krwTickSizes = []struct {
+	from float64
+	tick float64
+}{
+	{2000000, 1000},
+	{1000000, 500},
+	{500000, 100},
+	{100000, 50},
+	{10000, 10},
+	{1000, 1},
+	{100, 0.1},
+	{10, 0.01},
+	{1, 0.001},
+	{0.1, 0.0001},
+	{0.01, 0.00001},
+	{0.001, 0.000001},
+	{0.0001, 0.0000001},
+	{0, 0.00000001},
+}
+
+// FloorToTick rounds a KRW price down to a valid Upbit price unit
+func FloorToTick(price float64) float64 {
+	for _, t := range krwTickSizes {
+		if price >= t.from {
+			// Round away float noise before flooring so exact multiples stay put
+			units := math.Floor(math.Round(price/t.tick*1e6) / 1e6)
+			return math.Round(units*t.tick*1e8) / 1e8
+		}
+	}
+	return 0
+}
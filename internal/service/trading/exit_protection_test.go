@@ -0,0 +1,56 @@
+package trading
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// staticOrderbook serves the same top of book for every market
+type staticOrderbook struct {
+	bid, ask float64
+}
+
+func (s *staticOrderbook) GetOrderbook(ctx context.Context, market string) (*model.Orderbook, error) {
+	return &model.Orderbook{Market: market, OrderbookUnits: []model.OrderbookUnit{{AskPrice: s.ask, BidPrice: s.bid}}}, nil
+}
+
+func TestEngine_ExitProtection(t *testing.T) {
+	engine, _ := newTestEngine()
+	book := &staticOrderbook{bid: 99950000, ask: 100000000}
+	engine.WithExitProtection(book, ExitProtection{MaxSpreadBps: 20, CrossBps: 50})
+	ctx := context.Background()
+	sell := PlaceOrderRequest{Market: "KRW-BTC", Side: model.OrderSideAsk, Type: model.OrderTypeMarket, Quantity: 0.1}
+
+	// A 5 bps spread leaves market sells alone
+	order, err := engine.PlaceOrder(ctx, uuid.New(), sell)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderTypeMarket, order.Type)
+	assert.Nil(t, order.Price)
+
+	// At 100 bps the sell becomes a limit 50 bps under the bid, on a 1,000 KRW tick
+	book.bid = 99000000
+	order, err = engine.PlaceOrder(ctx, uuid.New(), sell)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderTypeLimit, order.Type)
+	require.NotNil(t, order.Price)
+	assert.Equal(t, 98505000.0, *order.Price)
+
+	// Market buys are never converted
+	price := 100000000.0
+	order, err = engine.PlaceOrder(ctx, uuid.New(), PlaceOrderRequest{Market: "KRW-BTC", Side: model.OrderSideBid, Type: model.OrderTypeMarket, Quantity: 0.1, Price: &price})
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderTypeMarket, order.Type)
+}
+
+func TestFloorToTick(t *testing.T) {
+	assert.Equal(t, 98505000.0, FloorToTick(98505000.9))
+	assert.Equal(t, 1234500.0, FloorToTick(1234999))
+	assert.Equal(t, 5430.0, FloorToTick(5430.7))
+	assert.Equal(t, 123.4, FloorToTick(123.45))
+	assert.Equal(t, 0.01234, FloorToTick(0.012345))
+}
@@ -0,0 +1,68 @@
+package trading
+
+import (
+	"context"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// BalanceSource provides users' cached exchange balances; balance.Service
+// satisfies it
+type BalanceSource interface {
+	Balances(ctx context.Context, userID uuid.UUID) (*model.AccountBalances, error)
+	Invalidate(ctx context.Context, userID uuid.UUID)
+}
+
+// WithBalances makes the engine reject orders the user can't fund before
+// storing them, instead of letting them fail at the exchange
+func (e *Engine) WithBalances(balances BalanceSource) *Engine {
+	e.balances = balances
+	return e
+}
+
+// checkFunds compares what the order needs with the user's available balance:
+// the free balance less what their accepted but not yet submitted orders will
+// lock. Funds of submitted orders are already locked in the synced balances.
+// The check fails open when balances can't be loaded; the exchange still
+// rejects the order.
+func (e *Engine) checkFunds(ctx context.Context, order *model.Order) error {
+	if e.balances == nil {
+		return nil
+	}
+
+	balances, err := e.balances.Balances(ctx, order.UserID)
+	if err != nil {
+		log.Printf("Skipping balance check of order for user %s: %v", order.UserID, err)
+		return nil
+	}
+
+	orders, err := e.orders.ListOpenByUser(ctx, order.UserID)
+	if err != nil {
+		return err
+	}
+
+	currency, required := order.Funds()
+	available := model.NewAvailableBalances(balances, orders).Available(currency)
+	if required > available {
+		return &InsufficientFundsError{Currency: currency, Required: required, Available: available}
+	}
+	return nil
+}
+
+// invalidateBalances drops the user's cached balances after an order changed them
+func (e *Engine) invalidateBalances(ctx context.Context, userID uuid.UUID) {
+	if e.balances != nil {
+		e.balances.Invalidate(ctx, userID)
+	}
+}
+
+// baseCurrency returns the traded coin of a market, e.g. BTC for KRW-BTC
+func baseCurrency(market string) string {
+	if i := strings.IndexByte(market, '-'); i >= 0 {
+		return market[i+1:]
+	}
+	return market
+}
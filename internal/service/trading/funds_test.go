@@ -0,0 +1,94 @@
+package trading
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/exchange"
+)
+
+// staticBalances serves fixed balances and counts invalidations
+type staticBalances struct {
+	balances    *model.AccountBalances
+	invalidated int
+}
+
+func (s *staticBalances) Balances(ctx context.Context, userID uuid.UUID) (*model.AccountBalances, error) {
+	return s.balances, nil
+}
+
+func (s *staticBalances) Invalidate(ctx context.Context, userID uuid.UUID) {
+	s.invalidated++
+}
+
+func TestEngine_CheckFunds(t *testing.T) {
+	engine, store := newTestEngine()
+	ctx := context.Background()
+	userID := uuid.New()
+	engine.WithBalances(&staticBalances{balances: &model.AccountBalances{
+		UserID: userID,
+		Balances: []model.Balance{
+			{Currency: "KRW", Balance: 1000000, Locked: 500000},
+			{Currency: "BTC", Balance: 0.5},
+		},
+	}})
+
+	price := 100000.0
+	newOrder := func(side model.OrderSide, qty float64) *model.Order {
+		return model.NewOrder(userID, "KRW-BTC", side, model.OrderTypeLimit, qty, &price, time.Now())
+	}
+
+	// 9 units cost 900,450 KRW with the fee; locked KRW doesn't count
+	assert.NoError(t, engine.checkFunds(ctx, newOrder(model.OrderSideBid, 9)))
+	err := engine.checkFunds(ctx, newOrder(model.OrderSideBid, 10))
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+	var fundsErr *InsufficientFundsError
+	require.ErrorAs(t, err, &fundsErr)
+	assert.Equal(t, "KRW", fundsErr.Currency)
+	assert.InDelta(t, 1000500, fundsErr.Required, 1e-6)
+	assert.Equal(t, 1000000.0, fundsErr.Available)
+
+	// A pending buy not yet submitted reserves its funds
+	require.NoError(t, store.Orders().Create(ctx, newOrder(model.OrderSideBid, 5)))
+	assert.ErrorIs(t, engine.checkFunds(ctx, newOrder(model.OrderSideBid, 5)), ErrInsufficientFunds)
+
+	// Sells need the coin
+	assert.NoError(t, engine.checkFunds(ctx, newOrder(model.OrderSideAsk, 0.5)))
+	assert.ErrorIs(t, engine.checkFunds(ctx, newOrder(model.OrderSideAsk, 0.6)), ErrInsufficientFunds)
+}
+
+func TestEngine_PlaceOrderRejectsUnfundedOrders(t *testing.T) {
+	engine, store := newTestEngine()
+	ctx := context.Background()
+	userID := uuid.New()
+	engine.WithBalances(&staticBalances{balances: &model.AccountBalances{UserID: userID}})
+
+	price := 100000.0
+	_, err := engine.PlaceOrder(ctx, userID, PlaceOrderRequest{Market: "KRW-BTC", Side: model.OrderSideBid, Type: model.OrderTypeLimit, Quantity: 1, Price: &price})
+	assert.ErrorIs(t, err, ErrInsufficientFunds)
+
+	orders, err := store.Orders().ListByUser(ctx, userID)
+	require.NoError(t, err)
+	assert.Empty(t, orders)
+}
+
+func TestEngine_ProcessOrderUpdateInvalidatesBalances(t *testing.T) {
+	engine, store := newTestEngine()
+	balances := &staticBalances{}
+	engine.WithBalances(balances)
+
+	order := submittedOrder(t, store, model.OrderSideBid, 1, 100000, nil)
+	err := engine.processOrderUpdate(context.Background(), order, &exchange.OrderResponse{
+		State:          "done",
+		ExecutedVolume: "1",
+		PaidFee:        "50",
+		Trades:         []exchange.Trade{{Funds: "100000", Volume: "1"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, balances.invalidated)
+}
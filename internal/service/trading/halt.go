@@ -0,0 +1,128 @@
+package trading
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// HaltResult reports a new halt and the resting orders it cancelled
+type HaltResult struct {
+	Halt         *model.TradingHalt `json:"halt"`
+	Cancelled    int                `json:"cancelled"`
+	CancelErrors []string           `json:"cancel_errors,omitempty"`
+}
+
+// WithHalts enables the kill switch: halted users, or everyone under a global
+// halt, can't place orders until trading is resumed
+func (e *Engine) WithHalts(halts repository.TradingHaltRepository) *Engine {
+	e.halts = halts
+	return e
+}
+
+// Halt stops order placement for a user, or for every user when userID is
+// uuid.Nil, until Resume is called. Orders that were accepted but not yet
+// submitted fail. With cancelOrders, resting orders are cancelled as well;
+// orders that fail to cancel are reported in the result.
+func (e *Engine) Halt(ctx context.Context, userID uuid.UUID, reason, haltedBy string, cancelOrders bool) (*HaltResult, error) {
+	if e.halts == nil {
+		return nil, ErrHaltsDisabled
+	}
+
+	halt := &model.TradingHalt{
+		UserID:    userID,
+		Reason:    reason,
+		HaltedBy:  haltedBy,
+		CreatedAt: e.clock.Now(),
+	}
+	if err := e.halts.Save(ctx, halt); err != nil {
+		return nil, err
+	}
+	if halt.IsGlobal() {
+		log.Printf("Trading halted for all users by %s: %s", haltedBy, reason)
+	} else {
+		log.Printf("Trading halted for user %s by %s: %s", userID, haltedBy, reason)
+	}
+
+	result := &HaltResult{Halt: halt}
+	if !cancelOrders {
+		return result, nil
+	}
+
+	orders, err := e.orders.ListOpen(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("trading halted, but failed to list open orders: %w", err)
+	}
+	for _, order := range orders {
+		if !halt.IsGlobal() && order.UserID != userID {
+			continue
+		}
+		if _, err := e.CancelOrder(ctx, order.UserID, order.ID); err != nil {
+			result.CancelErrors = append(result.CancelErrors, fmt.Sprintf("order %s: %v", order.ID, err))
+			continue
+		}
+		result.Cancelled++
+	}
+	return result, nil
+}
+
+// Resume lifts the halt of a user, or the global halt for uuid.Nil. A user's
+// own resume doesn't lift a global halt.
+func (e *Engine) Resume(ctx context.Context, userID uuid.UUID) error {
+	if e.halts == nil {
+		return ErrHaltsDisabled
+	}
+	if err := e.halts.Delete(ctx, userID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrNotHalted
+		}
+		return err
+	}
+
+	if userID == uuid.Nil {
+		log.Printf("Trading resumed for all users")
+	} else {
+		log.Printf("Trading resumed for user %s", userID)
+	}
+	return nil
+}
+
+// ActiveHalt returns the halt blocking a user's orders, the global halt
+// first, or nil if the user may trade
+func (e *Engine) ActiveHalt(ctx context.Context, userID uuid.UUID) (*model.TradingHalt, error) {
+	if e.halts == nil {
+		return nil, nil
+	}
+
+	for _, id := range []uuid.UUID{uuid.Nil, userID} {
+		halt, err := e.halts.Get(ctx, id)
+		if errors.Is(err, repository.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to check trading halts: %w", err)
+		}
+		return halt, nil
+	}
+	return nil, nil
+}
+
+// checkHalt returns ErrTradingHalted if a halt blocks the user's orders
+func (e *Engine) checkHalt(ctx context.Context, userID uuid.UUID) error {
+	halt, err := e.ActiveHalt(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if halt == nil {
+		return nil
+	}
+	if halt.Reason == "" {
+		return ErrTradingHalted
+	}
+	return fmt.Errorf("%w: %s", ErrTradingHalted, halt.Reason)
+}
@@ -0,0 +1,89 @@
+package trading
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/gateway"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/exchange"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/fake"
+	"github.com/sungminna/upbit-trading-platform/pkg/cache"
+)
+
+func TestEngine_HaltBlocksOrdersUntilResumed(t *testing.T) {
+	engine, store := newTestEngine()
+	engine.WithHalts(store.TradingHalts())
+	ctx := context.Background()
+	userID, otherID := uuid.New(), uuid.New()
+	price := 100000000.0
+	req := PlaceOrderRequest{Market: "KRW-BTC", Side: model.OrderSideBid, Type: model.OrderTypeLimit, Quantity: 0.01, Price: &price}
+
+	_, err := engine.Halt(ctx, userID, "investigating fills", model.HaltedByUser, false)
+	require.NoError(t, err)
+
+	_, err = engine.PlaceOrder(ctx, userID, req)
+	assert.ErrorIs(t, err, ErrTradingHalted)
+	assert.ErrorContains(t, err, "investigating fills")
+	_, err = engine.PlaceOrder(ctx, otherID, req)
+	assert.NoError(t, err, "other users keep trading")
+
+	// A global halt blocks everyone, and users can't lift it
+	_, err = engine.Halt(ctx, uuid.Nil, "exchange incident", model.HaltedByAdmin, false)
+	require.NoError(t, err)
+	require.NoError(t, engine.Resume(ctx, userID))
+	_, err = engine.PlaceOrder(ctx, userID, req)
+	assert.ErrorIs(t, err, ErrTradingHalted)
+	_, err = engine.PlaceOrder(ctx, otherID, req)
+	assert.ErrorIs(t, err, ErrTradingHalted)
+
+	require.NoError(t, engine.Resume(ctx, uuid.Nil))
+	assert.ErrorIs(t, engine.Resume(ctx, uuid.Nil), ErrNotHalted)
+	_, err = engine.PlaceOrder(ctx, userID, req)
+	assert.NoError(t, err)
+}
+
+func TestEngine_HaltCancelsRestingOrders(t *testing.T) {
+	server := fake.NewServer("access", "secret")
+	defer server.Close()
+	server.SetFillMode(fake.FillManually)
+
+	store := memory.NewStore()
+	engine := NewEngine(store.Orders(), store.APIKeys(), store, cache.NewMemoryCache(),
+		func(accessKey, secretKey string) gateway.ExchangeAPI {
+			return exchange.NewClientWithBaseURL(accessKey, secretKey, server.URL())
+		}).WithHalts(store.TradingHalts())
+
+	ctx := context.Background()
+	userID := uuid.New()
+	require.NoError(t, store.APIKeys().Create(ctx, model.NewUserAPIKey(userID, "access", "secret", "test", time.Now())))
+
+	price := 100000000.0
+	order, err := engine.PlaceOrder(ctx, userID, PlaceOrderRequest{
+		Market:   "KRW-BTC",
+		Side:     model.OrderSideBid,
+		Type:     model.OrderTypeLimit,
+		Quantity: 0.01,
+		Price:    &price,
+	})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		stored, err := store.Orders().GetByID(ctx, order.ID)
+		return err == nil && stored.Status == model.OrderStatusSubmitted
+	}, time.Second, 10*time.Millisecond)
+
+	result, err := engine.Halt(ctx, userID, "", model.HaltedByUser, true)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Cancelled)
+	assert.Empty(t, result.CancelErrors)
+
+	engine.syncOpenOrders(ctx)
+	stored, err := store.Orders().GetByID(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderStatusCancelled, stored.Status)
+}
@@ -0,0 +1,37 @@
+package trading
+
+import "github.com/sungminna/upbit-trading-platform/internal/domain/model"
+
+// MaintenanceMonitor tracks Upbit maintenance; maintenance.Detector satisfies it
+type MaintenanceMonitor interface {
+	// Maintenance returns the maintenance blocking orders for a market, or nil
+	Maintenance(market string) *model.Maintenance
+	// Observe records the error of an exchange request, which may show that
+	// maintenance began
+	Observe(err error)
+}
+
+// WithMaintenance makes the engine hold orders during Upbit maintenance: new
+// orders are rejected, queued submissions are retried and scheduled orders
+// wait until it is over
+func (e *Engine) WithMaintenance(maintenance MaintenanceMonitor) *Engine {
+	e.maintenance = maintenance
+	return e
+}
+
+// maintenanceOf returns the maintenance blocking orders for a market, or nil
+func (e *Engine) maintenanceOf(market string) *model.Maintenance {
+	if e.maintenance == nil {
+		return nil
+	}
+	return e.maintenance.Maintenance(market)
+}
+
+// checkMaintenance returns a MaintenanceError while the market is under
+// maintenance
+func (e *Engine) checkMaintenance(market string) error {
+	if m := e.maintenanceOf(market); m != nil {
+		return &MaintenanceError{Maintenance: *m}
+	}
+	return nil
+}
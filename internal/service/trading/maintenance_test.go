@@ -0,0 +1,58 @@
+package trading
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/service/queue"
+)
+
+type stubMaintenance map[string]*model.Maintenance
+
+func (s stubMaintenance) Maintenance(market string) *model.Maintenance {
+	return s[market]
+}
+
+func (s stubMaintenance) Observe(err error) {}
+
+func TestEngine_HoldsOrdersDuringMaintenance(t *testing.T) {
+	engine, store := newTestEngine()
+	engine.WithQueue(queue.NewQueue(store.Jobs()))
+	ctx := context.Background()
+	until := time.Now().Add(time.Hour)
+	maintenance := stubMaintenance{"KRW-BTC": {Market: "KRW-BTC", Reason: "wallet upgrade", Since: time.Now(), Until: &until}}
+	engine.WithMaintenance(maintenance)
+
+	price := 100000000.0
+	req := PlaceOrderRequest{Market: "KRW-BTC", Side: model.OrderSideBid, Type: model.OrderTypeLimit, Quantity: 0.01, Price: &price}
+	_, err := engine.PlaceOrder(ctx, uuid.New(), req)
+	assert.ErrorIs(t, err, ErrMaintenance)
+	assert.ErrorIs(t, err, ErrExchangeDown, "queued submissions retry it like an outage")
+	assert.ErrorContains(t, err, "wallet upgrade")
+
+	req.Market = "KRW-ETH"
+	_, err = engine.PlaceOrder(ctx, uuid.New(), req)
+	assert.NoError(t, err, "other markets keep trading")
+
+	// Scheduled orders wait for the maintenance to end
+	order := scheduleOrder(t, engine, time.Now().Add(time.Hour))
+	due := time.Now().Add(-time.Second)
+	order.ActivateAt = &due
+	require.NoError(t, store.Orders().Update(ctx, order))
+
+	engine.activateDueOrders(ctx)
+	stored, err := store.Orders().GetByID(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderStatusScheduled, stored.Status)
+
+	delete(maintenance, "KRW-BTC")
+	engine.activateDueOrders(ctx)
+	stored, err = store.Orders().GetByID(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderStatusPending, stored.Status)
+}
@@ -0,0 +1,152 @@
+package trading
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/exchange"
+	"github.com/sungminna/upbit-trading-platform/pkg/recovery"
+)
+
+// notifyTimeout bounds the delivery of a single notification
+const notifyTimeout = 15 * time.Second
+
+// callExchange runs an exchange request through the circuit breaker and
+// notifies users when Upbit becomes unreachable or rejects their API key
+func (e *Engine) callExchange(userID uuid.UUID, call func() error) error {
+	if !e.breaker.allow() {
+		return ErrExchangeDown
+	}
+
+	err := call()
+	if err != nil && e.maintenance != nil {
+		e.maintenance.Observe(err)
+	}
+
+	opened, closed := e.breaker.record(err)
+	if opened {
+		log.Printf("Upbit circuit breaker opened after repeated failures: %v", err)
+		// Users are told about maintenance by the maintenance detector
+		if e.maintenanceOf("") == nil {
+			e.notifyDegraded(userID)
+		}
+	}
+	if closed {
+		log.Printf("Upbit circuit breaker closed")
+		e.notifyRestored()
+	}
+
+	var apiErr *exchange.APIError
+	switch {
+	case errors.As(err, &apiErr) && apiErr.IsAuth():
+		e.keyRejected(userID, apiErr)
+	case err == nil:
+		e.mu.Lock()
+		delete(e.rejectedKeys, userID)
+		e.mu.Unlock()
+	}
+	return err
+}
+
+// keyRejected notifies a user the first time Upbit rejects their API key.
+// The user is told again only after a successful request or a key change.
+func (e *Engine) keyRejected(userID uuid.UUID, apiErr *exchange.APIError) {
+	e.mu.Lock()
+	notified := e.rejectedKeys[userID]
+	e.rejectedKeys[userID] = true
+	e.mu.Unlock()
+
+	if notified {
+		return
+	}
+	log.Printf("Upbit rejected the API key of user %s: %v", userID, apiErr)
+	e.notify(model.NewNotification(userID, model.NotificationAPIKeyRejected, "API key rejected",
+		fmt.Sprintf("Upbit rejected your API key (%s). Orders can't be placed or tracked until it is replaced.", failureReason(apiErr)),
+		map[string]any{"reason": failureReason(apiErr)}, e.clock.Now(),
+	))
+}
+
+func (e *Engine) notifyOrderFailed(order *model.Order, cause error) {
+	reason := failureReason(cause)
+	n := model.NewNotification(order.UserID, model.NotificationOrderFailed, "Order failed",
+		fmt.Sprintf("Your %s %s order for %g failed: %s", order.Market, order.Side, order.Quantity, reason),
+		map[string]any{"order_id": order.ID, "market": order.Market, "reason": reason}, e.clock.Now(),
+	)
+	n.DedupKey = order.ID.String()
+	e.notify(n)
+}
+
+// notifyDegraded tells the users with open orders, and the user whose request
+// opened the breaker, that Upbit is unreachable
+func (e *Engine) notifyDegraded(userID uuid.UUID) {
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+
+	users := map[uuid.UUID]bool{userID: true}
+	orders, err := e.orders.ListOpen(ctx)
+	if err != nil {
+		log.Printf("Error listing open orders for outage notifications: %v", err)
+	}
+	for _, order := range orders {
+		users[order.UserID] = true
+	}
+
+	e.mu.Lock()
+	for id := range users {
+		if e.degraded[id] {
+			delete(users, id)
+			continue
+		}
+		e.degraded[id] = true
+	}
+	e.mu.Unlock()
+
+	for id := range users {
+		e.notify(model.NewNotification(id, model.NotificationExchangeDegraded, "Upbit unreachable",
+			"Requests to Upbit are failing. New orders fail and open orders aren't updated until the connection recovers.", nil, e.clock.Now()))
+	}
+}
+
+// notifyRestored tells the users notified of the outage that it is over
+func (e *Engine) notifyRestored() {
+	e.mu.Lock()
+	users := e.degraded
+	e.degraded = make(map[uuid.UUID]bool)
+	e.mu.Unlock()
+
+	for id := range users {
+		e.notify(model.NewNotification(id, model.NotificationExchangeRestored, "Upbit reachable again",
+			"The connection to Upbit recovered and open orders are being updated again.", nil, e.clock.Now()))
+	}
+}
+
+// notify delivers a notification in the background so exchange calls never
+// wait on notification channels
+func (e *Engine) notify(n *model.Notification) {
+	if e.notifier == nil {
+		return
+	}
+
+	recovery.Go("trading.notify", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+		defer cancel()
+
+		if err := e.notifier.Notify(ctx, n); err != nil {
+			log.Printf("Error delivering %s notification to user %s: %v", n.Type, n.UserID, err)
+		}
+	})
+}
+
+// failureReason describes an error for users, preferring Upbit's own message
+func failureReason(err error) string {
+	var apiErr *exchange.APIError
+	if errors.As(err, &apiErr) && apiErr.Message != "" {
+		return apiErr.Message
+	}
+	return err.Error()
+}
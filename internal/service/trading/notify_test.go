@@ -0,0 +1,116 @@
+package trading
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/gateway"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/exchange"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/fake"
+	"github.com/sungminna/upbit-trading-platform/pkg/cache"
+	"github.com/sungminna/upbit-trading-platform/pkg/clock"
+)
+
+type recordingNotifier struct {
+	mu   sync.Mutex
+	sent []*model.Notification
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, notification *model.Notification) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sent = append(n.sent, notification)
+	return nil
+}
+
+func (n *recordingNotifier) types() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	var types []string
+	for _, notification := range n.sent {
+		types = append(types, notification.Type)
+	}
+	return types
+}
+
+func TestEngine_NotifiesFailedOrdersAndRejectedKeys(t *testing.T) {
+	server := fake.NewServer("access", "secret")
+	defer server.Close()
+
+	store := memory.NewStore()
+	notifier := &recordingNotifier{}
+	engine := NewEngine(store.Orders(), store.APIKeys(), store, cache.NewMemoryCache(),
+		func(accessKey, secretKey string) gateway.ExchangeAPI {
+			return exchange.NewClientWithBaseURL(accessKey, secretKey, server.URL())
+		}).WithNotifier(notifier)
+
+	ctx := context.Background()
+	userID := uuid.New()
+	require.NoError(t, store.APIKeys().Create(ctx, model.NewUserAPIKey(userID, "access", "secret", "test", time.Now())))
+
+	price := 100000000.0
+	req := PlaceOrderRequest{Market: "KRW-BTC", Side: model.OrderSideBid, Type: model.OrderTypeLimit, Quantity: 0.01, Price: &price}
+	for range 2 {
+		server.FailNext(http.StatusUnauthorized, "expired_access_key", "API키가 만료되었습니다.")
+		_, err := engine.PlaceOrder(ctx, userID, req)
+		require.NoError(t, err)
+	}
+
+	// Both orders fail, but the key rejection is only reported once
+	require.Eventually(t, func() bool { return len(notifier.types()) == 3 }, time.Second, 10*time.Millisecond)
+	assert.ElementsMatch(t, []string{
+		model.NotificationAPIKeyRejected,
+		model.NotificationOrderFailed,
+		model.NotificationOrderFailed,
+	}, notifier.types())
+
+	orders, err := store.Orders().ListByUser(ctx, userID)
+	require.NoError(t, err)
+	for _, order := range orders {
+		assert.Equal(t, model.OrderStatusFailed, order.Status)
+	}
+}
+
+func TestEngine_CircuitBreakerNotifiesOutages(t *testing.T) {
+	engine, store := newTestEngine()
+	notifier := &recordingNotifier{}
+	engine.WithNotifier(notifier)
+	now := clock.NewFake(time.Now())
+	engine.breaker = newCircuitBreaker(2, time.Minute)
+	engine.WithClock(now)
+
+	userID := uuid.New()
+	submittedOrder(t, store, model.OrderSideBid, 0.01, 100000000, nil)
+
+	unreachable := errors.New("connection refused")
+	call := func(err error) error {
+		return engine.callExchange(userID, func() error { return err })
+	}
+
+	assert.Equal(t, unreachable, call(unreachable))
+	// A rejected request shows Upbit is reachable and resets the count
+	assert.Error(t, call(&exchange.APIError{StatusCode: http.StatusBadRequest, Name: "insufficient_funds_bid"}))
+	assert.Equal(t, unreachable, call(unreachable))
+	assert.Empty(t, notifier.types())
+
+	assert.Equal(t, unreachable, call(unreachable))
+	assert.Equal(t, ErrExchangeDown, call(nil), "calls fail fast while the breaker is open")
+
+	// The user whose request tripped the breaker and the owner of the open order
+	require.Eventually(t, func() bool { return len(notifier.types()) == 2 }, time.Second, time.Millisecond)
+	assert.Equal(t, []string{model.NotificationExchangeDegraded, model.NotificationExchangeDegraded}, notifier.types())
+
+	now.Advance(time.Minute)
+	assert.NoError(t, call(nil))
+	require.Eventually(t, func() bool { return len(notifier.types()) == 4 }, time.Second, time.Millisecond)
+	assert.Equal(t, model.NotificationExchangeRestored, notifier.types()[3])
+}
@@ -0,0 +1,169 @@
+package trading
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/gateway"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/exchange"
+	"github.com/sungminna/upbit-trading-platform/pkg/cache"
+	"github.com/sungminna/upbit-trading-platform/pkg/recovery"
+)
+
+// orphanAge is how long an order may stay pending without an exchange order
+// ID before the monitor asks Upbit about it. It outlasts submitTimeout and
+// submitRetryWindow, so no submission of the order is still under way.
+const orphanAge = 5 * time.Minute
+
+// placementUncertain reports whether a failed placement may have reached
+// Upbit all the same: it timed out, lost its connection or hit a server
+// error, rather than being turned away
+func placementUncertain(err error) bool {
+	if errors.Is(err, ErrExchangeDown) {
+		return false // Never sent
+	}
+	var apiErr *exchange.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError
+	}
+	return true
+}
+
+// findPlaced asks Upbit for an order whose placement failed with the
+// uncertain error cause, and returns it if Upbit took the order after all.
+// Upbit may still be processing a placement it hasn't answered, so not
+// finding the order is no proof it wasn't placed: the order is left pending
+// with ErrPlacementUnknown for the monitor to ask about again. Clients that
+// can't look orders up fail with cause, as before lookups existed.
+func (e *Engine) findPlaced(ctx context.Context, client gateway.ExchangeAPI, order *model.Order, cause error) (*exchange.OrderResponse, error) {
+	resp, err := e.lookupPlaced(ctx, client, order)
+	switch {
+	case errors.Is(err, errors.ErrUnsupported):
+		return nil, cause
+	case err != nil:
+		return nil, fmt.Errorf("%w: %v; looking it up failed: %v", ErrPlacementUnknown, cause, err)
+	case resp == nil:
+		return nil, fmt.Errorf("%w: %v", ErrPlacementUnknown, cause)
+	}
+	log.Printf("Adopting order %s, placed on Upbit as %s despite: %v", order.ID, resp.UUID, cause)
+	return resp, nil
+}
+
+// lookupPlaced asks the exchange for an order by the identifier it was
+// placed with. It returns nil without an error if the exchange doesn't have
+// the order, and errors.ErrUnsupported if the client can't look orders up.
+func (e *Engine) lookupPlaced(ctx context.Context, client gateway.ExchangeAPI, order *model.Order) (*exchange.OrderResponse, error) {
+	lookup, ok := client.(gateway.OrderLookupAPI)
+	if !ok {
+		return nil, errors.ErrUnsupported
+	}
+
+	// Often called because the placement ran out of time
+	ctx, cancel := detach(ctx, recordTimeout)
+	defer cancel()
+
+	var resp *exchange.OrderResponse
+	err := e.callExchange(order.UserID, func() (err error) {
+		resp, err = lookup.GetOrderByIdentifier(ctx, order.ID.String())
+		return err
+	})
+	var apiErr *exchange.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	return resp, err
+}
+
+// resolveInterrupted settles an order whose queued submission was
+// interrupted. Submitting it again could place it twice, so Upbit is asked
+// for it instead; if Upbit can't be asked, the order fails and the user is
+// told to check their open orders.
+func (e *Engine) resolveInterrupted(ctx context.Context, order *model.Order) {
+	client, err := e.clientFor(ctx, order.UserID)
+	if err != nil {
+		e.failOrder(ctx, order, ErrSubmissionInterrupted)
+		return
+	}
+
+	resp, err := e.findPlaced(ctx, client, order, ErrSubmissionInterrupted)
+	switch {
+	case errors.Is(err, ErrPlacementUnknown):
+		log.Printf("Order %s left pending: %v", order.ID, err)
+	case err != nil:
+		e.failOrder(ctx, order, err)
+	default:
+		e.recordSubmitted(ctx, order, resp)
+	}
+}
+
+// resolveUnsubmittedOrders settles the orders left pending without an
+// exchange order ID for longer than any submission takes: their placement
+// got no answer, or the instance submitting them stopped. Orders Upbit has
+// are adopted and synced like any other; the rest fail.
+func (e *Engine) resolveUnsubmittedOrders(ctx context.Context) {
+	if !e.breaker.allow() {
+		return
+	}
+
+	orders, err := e.orders.ListUnsubmitted(ctx, e.clock.Now().Add(-orphanAge))
+	if err != nil {
+		log.Printf("Error listing unsubmitted orders: %v", err)
+		return
+	}
+
+	for _, order := range orders {
+		recovery.Do("trading.resolve", func() {
+			ctx, cancel := context.WithTimeout(ctx, syncTimeout)
+			defer cancel()
+			if err := e.resolveOrder(ctx, order); err != nil {
+				log.Printf("Error resolving unsubmitted order %s: %v", order.ID, err)
+			}
+		})
+	}
+}
+
+// resolveOrder adopts an unsubmitted order if Upbit has it and fails it
+// otherwise. The execution lock keeps other instances from resolving it too.
+func (e *Engine) resolveOrder(ctx context.Context, order *model.Order) error {
+	lock, err := e.locker.Obtain(ctx, executionLockKey(order), executionLockTTL)
+	if err == cache.ErrLockNotObtained {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer lock.Release(ctx)
+
+	// From the primary, so a submission recorded by the instance that held the
+	// lock before is seen and the order is not failed or adopted twice
+	order, err = e.orders.GetCurrent(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+	if order.Status != model.OrderStatusPending || order.ExchangeOrderID != nil {
+		return nil // Resolved since it was listed
+	}
+
+	client, err := e.clientFor(ctx, order.UserID)
+	if err != nil {
+		return err
+	}
+	resp, err := e.lookupPlaced(ctx, client, order)
+	switch {
+	case errors.Is(err, errors.ErrUnsupported):
+		e.failOrder(ctx, order, ErrSubmissionInterrupted)
+	case err != nil:
+		return err
+	case resp == nil:
+		e.failOrder(ctx, order, ErrSubmissionLost)
+	default:
+		log.Printf("Adopting order %s, found on Upbit as %s", order.ID, resp.UUID)
+		e.recordSubmitted(ctx, order, resp)
+	}
+	return nil
+}
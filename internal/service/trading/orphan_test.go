@@ -0,0 +1,99 @@
+package trading
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/gateway"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/exchange"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/fake"
+	"github.com/sungminna/upbit-trading-platform/pkg/cache"
+	"github.com/sungminna/upbit-trading-platform/pkg/clock"
+)
+
+func newFakeExchangeEngine(t *testing.T) (*Engine, *memory.Store, *fake.Server, uuid.UUID) {
+	server := fake.NewServer("access", "secret")
+	t.Cleanup(server.Close)
+	server.SetFillMode(fake.FillManually)
+
+	store := memory.NewStore()
+	engine := NewEngine(store.Orders(), store.APIKeys(), store, cache.NewMemoryCache(),
+		func(accessKey, secretKey string) gateway.ExchangeAPI {
+			return exchange.NewClientWithBaseURL(accessKey, secretKey, server.URL())
+		})
+
+	userID := uuid.New()
+	require.NoError(t, store.APIKeys().Create(context.Background(), model.NewUserAPIKey(userID, "access", "secret", "test", time.Now())))
+	return engine, store, server, userID
+}
+
+func TestEngine_AdoptsOrderPlacedWithoutAnAnswer(t *testing.T) {
+	engine, store, server, userID := newFakeExchangeEngine(t)
+	ctx := context.Background()
+
+	// Upbit takes the order but the response never arrives
+	server.DropNextOrderResponse()
+	price := 100000000.0
+	order, err := engine.PlaceOrder(ctx, userID, PlaceOrderRequest{
+		Market: "KRW-BTC", Side: model.OrderSideBid, Type: model.OrderTypeLimit, Quantity: 0.01, Price: &price,
+	})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		stored, err := store.Orders().GetByID(ctx, order.ID)
+		return err == nil && stored.Status == model.OrderStatusSubmitted
+	}, time.Second, 10*time.Millisecond)
+
+	placed := server.Orders()
+	require.Len(t, placed, 1, "the order isn't placed twice")
+	assert.Equal(t, order.ID.String(), placed[0].Identifier)
+	stored, err := store.Orders().GetByID(ctx, order.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.ExchangeOrderID)
+	assert.Equal(t, placed[0].UUID, *stored.ExchangeOrderID)
+}
+
+func TestEngine_ResolvesUnsubmittedOrders(t *testing.T) {
+	engine, store, server, userID := newFakeExchangeEngine(t)
+	now := clock.NewFake(time.Now())
+	engine.WithClock(now)
+	ctx := context.Background()
+
+	price := 100000000.0
+	pending := func() *model.Order {
+		order := model.NewOrder(userID, "KRW-BTC", model.OrderSideBid, model.OrderTypeLimit, 0.01, &price, time.Now())
+		order.CreatedAt, order.UpdatedAt = now.Now(), now.Now()
+		require.NoError(t, store.Orders().Create(ctx, order))
+		return order
+	}
+
+	// One order reached Upbit before its submission stopped, one didn't
+	placed, lost := pending(), pending()
+	_, err := exchange.NewClientWithBaseURL("access", "secret", server.URL()).PlaceOrder(ctx, buildOrderRequest(placed))
+	require.NoError(t, err)
+
+	engine.resolveUnsubmittedOrders(ctx)
+	stored, err := store.Orders().GetByID(ctx, lost.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderStatusPending, stored.Status, "submissions may still be under way")
+
+	now.Advance(orphanAge + time.Second)
+	engine.resolveUnsubmittedOrders(ctx)
+
+	stored, err = store.Orders().GetByID(ctx, placed.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderStatusSubmitted, stored.Status)
+	require.NotNil(t, stored.ExchangeOrderID)
+	assert.Equal(t, server.Orders()[0].UUID, *stored.ExchangeOrderID)
+
+	stored, err = store.Orders().GetByID(ctx, lost.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderStatusFailed, stored.Status)
+	assert.Len(t, server.Orders(), 1)
+}
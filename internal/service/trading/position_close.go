@@ -0,0 +1,46 @@
+package trading
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// CancelAutomations cancels what still acts on a position that just closed,
+// in the transaction closing it: its active drawdown guard, which trailing
+// stops and the strategies cloned from templates are. The guard stops being
+// evaluated when the cancellation's event is dispatched, rather than on its
+// next price. price is what the position closed at.
+func CancelAutomations(ctx context.Context, tx repository.Tx, position *model.Position, price float64) error {
+	if position.Status != model.PositionStatusClosed || position.ClosedAt == nil {
+		return nil
+	}
+
+	guard, err := tx.DrawdownGuards().GetByPosition(ctx, position.ID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !guard.Active {
+		return nil // Triggered, e.g. by the exit that closed the position
+	}
+
+	guard.Cancel(*position.ClosedAt)
+	if err := tx.DrawdownGuards().Save(ctx, guard); err != nil {
+		return err
+	}
+	update := model.NewDrawdownGuardUpdate(guard, model.DrawdownGuardCancelled, price, *position.ClosedAt)
+	if err := tx.DrawdownGuardUpdates().Create(ctx, update); err != nil {
+		return err
+	}
+
+	event, err := model.NewDrawdownGuardEvent(model.EventDrawdownGuardCancelled, guard, *position.ClosedAt)
+	if err != nil {
+		return err
+	}
+	return tx.Outbox().Create(ctx, event)
+}
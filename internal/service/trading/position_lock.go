@@ -0,0 +1,90 @@
+package trading
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/pkg/cache"
+)
+
+const (
+	// positionLockWait is how long a sell waits for other orders being placed
+	// against its position
+	positionLockWait = 5 * time.Second
+	// positionLockRetry is how often a waiting sell retries the lock
+	positionLockRetry = 20 * time.Millisecond
+)
+
+// WithPositions makes the engine place sells of a position one at a time,
+// across instances, and fit each to what the position's other open sells
+// leave of it. Automations that exit the same position concurrently, e.g. a
+// drawdown guard and a signal close, then can't sell it twice.
+func (e *Engine) WithPositions(positions repository.PositionRepository) *Engine {
+	e.positions = positions
+	return e
+}
+
+// positionLockKey returns the lock key serializing orders placed against a
+// position. Fills of the position use their own lock, so placing a sell
+// doesn't hold up applying them.
+func positionLockKey(positionID uuid.UUID) string {
+	return "position-orders:" + positionID.String()
+}
+
+// lockPosition obtains the lock on placing orders against a position,
+// waiting up to positionLockWait for other placements to finish
+func (e *Engine) lockPosition(ctx context.Context, positionID uuid.UUID) (cache.Lock, error) {
+	deadline := e.clock.Now().Add(positionLockWait)
+	for {
+		lock, err := e.locker.Obtain(ctx, positionLockKey(positionID), executionLockTTL)
+		if !errors.Is(err, cache.ErrLockNotObtained) {
+			return lock, err
+		}
+		if e.clock.Now().After(deadline) {
+			return nil, ErrPositionBusy
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(positionLockRetry):
+		}
+	}
+}
+
+// fitToPosition shrinks a sell to the quantity of its position not already
+// being sold by the position's other open orders, and rejects it when none
+// is left. The position's lock must be held.
+func (e *Engine) fitToPosition(ctx context.Context, order *model.Order) error {
+	// Like the sells below, from the primary: a fill applied just before the
+	// lock was taken may not have reached a replica yet
+	position, err := e.positions.GetCurrent(ctx, *order.PositionID)
+	if err != nil {
+		return err
+	}
+	if position.UserID != order.UserID {
+		return repository.ErrNotFound
+	}
+	if position.Status != model.PositionStatusOpen {
+		return ErrPositionClosing
+	}
+
+	// A sell stored just before the lock was taken is included too
+	sells, err := e.orders.ListOpenSellsByPosition(ctx, position.ID)
+	if err != nil {
+		return err
+	}
+	remaining := position.Quantity
+	for _, o := range sells {
+		remaining -= o.Quantity - o.ExecutedQuantity
+	}
+
+	if remaining <= 0 {
+		return ErrPositionClosing
+	}
+	order.Quantity = min(order.Quantity, remaining)
+	return nil
+}
@@ -0,0 +1,100 @@
+package trading
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/service/queue"
+)
+
+func TestEngine_PlaceOrderFitsSellsToPosition(t *testing.T) {
+	engine, store := newTestEngine()
+	engine.WithQueue(queue.NewQueue(store.Jobs())).WithPositions(store.Positions())
+	ctx := context.Background()
+	userID := uuid.New()
+
+	position := model.NewPosition(userID, "KRW-BTC", model.PositionSideLong, 100000000, 1, time.Now())
+	require.NoError(t, store.Positions().Create(ctx, position))
+	sell := func(qty float64) (*model.Order, error) {
+		return engine.PlaceOrder(ctx, userID, PlaceOrderRequest{
+			Market: "KRW-BTC", Side: model.OrderSideAsk, Type: model.OrderTypeMarket, Quantity: qty, PositionID: &position.ID,
+		})
+	}
+
+	order, err := sell(0.6)
+	require.NoError(t, err)
+	assert.Equal(t, 0.6, order.Quantity)
+
+	// The open sell leaves 0.4 of the position
+	order, err = sell(0.6)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.4, order.Quantity, 1e-12)
+
+	_, err = sell(0.1)
+	assert.ErrorIs(t, err, ErrPositionClosing)
+
+	// Other users can't sell the position
+	_, err = engine.PlaceOrder(ctx, uuid.New(), PlaceOrderRequest{
+		Market: "KRW-BTC", Side: model.OrderSideAsk, Type: model.OrderTypeMarket, Quantity: 1, PositionID: &position.ID,
+	})
+	assert.Error(t, err)
+}
+
+func TestEngine_ConcurrentExitsSellPositionOnce(t *testing.T) {
+	engine, store := newTestEngine()
+	engine.WithQueue(queue.NewQueue(store.Jobs())).WithPositions(store.Positions())
+	ctx := context.Background()
+	userID := uuid.New()
+
+	position := model.NewPosition(userID, "KRW-BTC", model.PositionSideLong, 100000000, 1, time.Now())
+	require.NoError(t, store.Positions().Create(ctx, position))
+
+	var wg sync.WaitGroup
+	errs := make([]error, 10)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = engine.PlaceOrder(ctx, userID, PlaceOrderRequest{
+				Market: "KRW-BTC", Side: model.OrderSideAsk, Type: model.OrderTypeMarket, Quantity: 1, PositionID: &position.ID,
+				Source: model.OrderSourceDrawdownGuard, SourceID: &position.ID,
+			})
+		}()
+	}
+	wg.Wait()
+
+	placed := 0
+	for _, err := range errs {
+		if err == nil {
+			placed++
+		} else {
+			assert.ErrorIs(t, err, ErrPositionClosing)
+		}
+	}
+	assert.Equal(t, 1, placed)
+
+	orders, err := store.Orders().ListByUser(ctx, userID)
+	require.NoError(t, err)
+	assert.Len(t, orders, 1)
+}
+
+func TestEngine_LockPositionStopsOnCancel(t *testing.T) {
+	engine, _ := newTestEngine()
+	ctx := context.Background()
+	positionID := uuid.New()
+
+	lock, err := engine.lockPosition(ctx, positionID)
+	require.NoError(t, err)
+	defer lock.Release(ctx)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = engine.lockPosition(cancelled, positionID)
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,101 @@
+package trading
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/queue"
+)
+
+const (
+	// SubmitOrderJob is the kind of queued job that submits an order
+	SubmitOrderJob = "order.submit"
+	// submitAttempts bounds how often submission is retried while the
+	// exchange is down
+	submitAttempts = 5
+	// submitRetryWindow is how long after an order was placed it may still
+	// be submitted. Past it the user is better off deciding again.
+	submitRetryWindow = 2 * time.Minute
+)
+
+// submitOrderPayload is the payload of a SubmitOrderJob
+type submitOrderPayload struct {
+	OrderID uuid.UUID `json:"order_id"`
+}
+
+// WithQueue makes the engine submit orders through the durable job queue, so
+// an order accepted before a restart is still submitted after it, and an
+// exchange outage is retried instead of failing the order at once
+func (e *Engine) WithQueue(q *queue.Queue) *Engine {
+	e.queue = q
+	q.Handle(SubmitOrderJob, e.runSubmitJob)
+	return e
+}
+
+// enqueueOrder stores a new order together with the job submitting it
+func (e *Engine) enqueueOrder(ctx context.Context, order *model.Order) error {
+	job, err := queue.NewJob(SubmitOrderJob, submitOrderPayload{OrderID: order.ID}, submitAttempts, e.clock.Now())
+	if err != nil {
+		return err
+	}
+	return e.uow.Do(ctx, func(tx repository.Tx) error {
+		if err := tx.Orders().Create(ctx, order); err != nil {
+			return err
+		}
+		return tx.Jobs().Enqueue(ctx, job)
+	})
+}
+
+// runSubmitJob submits the job's order unless it was already handled. Only
+// exchange outages are retried; any other error fails the order, except a
+// placement Upbit didn't answer, which the monitor settles.
+func (e *Engine) runSubmitJob(ctx context.Context, job *model.QueuedJob) error {
+	var payload submitOrderPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return fmt.Errorf("invalid %s payload: %w", SubmitOrderJob, err)
+	}
+
+	// The order is committed with its job, so it is read from the primary;
+	// not finding it there is retried rather than taken as handled
+	order, err := e.orders.GetCurrent(ctx, payload.OrderID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("order %s of %s job not found: %w", payload.OrderID, SubmitOrderJob, err)
+	}
+	if err != nil {
+		return err
+	}
+	if order.Status != model.OrderStatusPending {
+		return nil
+	}
+
+	// The interrupted attempt may have placed the order without recording
+	// it; submitting again could place it twice
+	if job.Interrupted {
+		e.resolveInterrupted(ctx, order)
+		return nil
+	}
+
+	err = e.submitOrder(ctx, order)
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, ErrPlacementUnknown) {
+		// It may be live on Upbit; the monitor looks it up again later
+		return nil
+	}
+	placedAt := order.CreatedAt
+	if order.ActivateAt != nil {
+		placedAt = *order.ActivateAt
+	}
+	if errors.Is(err, ErrExchangeDown) && job.Attempts < job.MaxAttempts && e.clock.Now().Sub(placedAt) < submitRetryWindow {
+		return err
+	}
+	e.failOrder(ctx, order, err)
+	return nil
+}
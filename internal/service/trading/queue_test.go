@@ -0,0 +1,82 @@
+package trading
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/queue"
+)
+
+func TestEngine_PlaceOrderEnqueuesSubmission(t *testing.T) {
+	engine, store := newTestEngine()
+	engine.WithQueue(queue.NewQueue(store.Jobs()))
+	ctx := context.Background()
+
+	price := 100000000.0
+	order, err := engine.PlaceOrder(ctx, uuid.New(), PlaceOrderRequest{
+		Market: "KRW-BTC", Side: model.OrderSideBid, Type: model.OrderTypeLimit, Quantity: 0.01, Price: &price,
+	})
+	require.NoError(t, err)
+
+	jobs, err := store.Jobs().List(ctx, repository.JobQueueFilter{Kind: SubmitOrderJob})
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.JSONEq(t, `{"order_id":"`+order.ID.String()+`"}`, string(jobs[0].Payload))
+
+	stored, err := store.Orders().GetByID(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderStatusPending, stored.Status)
+}
+
+func TestEngine_InterruptedSubmissionFailsTheOrder(t *testing.T) {
+	engine, store := newTestEngine()
+	q := queue.NewQueue(store.Jobs())
+	engine.WithQueue(q)
+	ctx := context.Background()
+
+	price := 100000000.0
+	order, err := engine.PlaceOrder(ctx, uuid.New(), PlaceOrderRequest{
+		Market: "KRW-BTC", Side: model.OrderSideBid, Type: model.OrderTypeLimit, Quantity: 0.01, Price: &price,
+	})
+	require.NoError(t, err)
+
+	// A worker claims the job and dies before recording the outcome
+	now := time.Now()
+	claimed, err := store.Jobs().Claim(ctx, []string{SubmitOrderJob}, now, now.Add(-time.Second), 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+
+	require.NoError(t, q.RunDue(ctx))
+
+	stored, err := store.Orders().GetByID(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderStatusFailed, stored.Status)
+
+	job, err := store.Jobs().GetByID(ctx, claimed[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.QueuedJobSucceeded, job.Status)
+	assert.True(t, job.Interrupted)
+}
+
+func TestEngine_SubmitJobRetriesMissingOrder(t *testing.T) {
+	engine, store := newTestEngine()
+	q := queue.NewQueue(store.Jobs())
+	engine.WithQueue(q)
+	ctx := context.Background()
+
+	job, err := q.Enqueue(ctx, SubmitOrderJob, submitOrderPayload{OrderID: uuid.New()}, submitAttempts)
+	require.NoError(t, err)
+
+	require.NoError(t, q.RunDue(ctx))
+
+	stored, err := store.Jobs().GetByID(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.QueuedJobPending, stored.Status)
+	assert.Contains(t, stored.LastError, "not found")
+}
@@ -0,0 +1,180 @@
+package trading
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/queue"
+	"github.com/sungminna/upbit-trading-platform/pkg/cache"
+	"github.com/sungminna/upbit-trading-platform/pkg/recovery"
+)
+
+// maxScheduleAhead bounds how far ahead an order may be scheduled
+const maxScheduleAhead = 30 * 24 * time.Hour
+
+// scheduleOrder stores an order to be activated at the given time. Risk,
+// funds and exit protection are checked, and sells fitted to their
+// position, on activation, against the account as it is then.
+func (e *Engine) scheduleOrder(ctx context.Context, order *model.Order, activateAt time.Time) error {
+	order.Status = model.OrderStatusScheduled
+	order.ActivateAt = &activateAt
+	return e.orders.Create(ctx, order)
+}
+
+// activateDueOrders activates the scheduled orders whose time has come. While
+// the exchange is unreachable or their market under maintenance they wait
+// rather than fail.
+func (e *Engine) activateDueOrders(ctx context.Context) {
+	if !e.breaker.allow() {
+		return
+	}
+
+	orders, err := e.orders.ListDue(ctx, e.clock.Now())
+	if err != nil {
+		log.Printf("Error listing due orders: %v", err)
+		return
+	}
+
+	for _, order := range orders {
+		if e.maintenanceOf(order.Market) != nil {
+			continue
+		}
+		recovery.Do("trading.activate", func() {
+			if err := e.activateOrder(ctx, order.ID); err != nil {
+				log.Printf("Error activating order %s: %v", order.ID, err)
+			}
+		})
+	}
+}
+
+// activateOrder runs a scheduled order through the checks new orders go
+// through and submits it, or fails it if a check rejects it. The activation
+// lock keeps other instances and cancellation from acting on it meanwhile.
+func (e *Engine) activateOrder(ctx context.Context, orderID uuid.UUID) error {
+	lock, err := e.locker.Obtain(ctx, activationLockKey(orderID), executionLockTTL)
+	if err == cache.ErrLockNotObtained {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer lock.Release(ctx)
+
+	// Read from the primary: a replica may not yet show a cancellation or
+	// another instance's activation made before the lock was released
+	order, err := e.orders.GetCurrent(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if order.Status != model.OrderStatusScheduled {
+		return nil // Cancelled, or activated by another instance
+	}
+
+	// Sells of a position are fitted to it as PlaceOrder fits them, holding
+	// its lock until the order is stored as pending so placements see it
+	if e.positions != nil && order.PositionID != nil && order.Side == model.OrderSideAsk {
+		positionLock, err := e.lockPosition(ctx, *order.PositionID)
+		if err != nil {
+			return err // Retried on the next pass
+		}
+		defer positionLock.Release(ctx)
+		if err := e.fitToPosition(ctx, order); err != nil {
+			e.failOrder(ctx, order, err)
+			return nil
+		}
+	}
+	if err := e.checkActivation(ctx, order); err != nil {
+		e.failOrder(ctx, order, err)
+		return nil
+	}
+	e.protectExit(ctx, order)
+	if err := order.Transition(model.OrderStatusPending, e.clock.Now()); err != nil {
+		return err
+	}
+
+	if e.queue != nil {
+		job, err := queue.NewJob(SubmitOrderJob, submitOrderPayload{OrderID: order.ID}, submitAttempts, e.clock.Now())
+		if err != nil {
+			return err
+		}
+		return e.uow.Do(ctx, func(tx repository.Tx) error {
+			if err := tx.Orders().Update(ctx, order); err != nil {
+				return err
+			}
+			if err := writeOrderEvent(ctx, tx, order); err != nil {
+				return err
+			}
+			return tx.Jobs().Enqueue(ctx, job)
+		})
+	}
+
+	err = e.uow.Do(ctx, func(tx repository.Tx) error {
+		if err := tx.Orders().Update(ctx, order); err != nil {
+			return err
+		}
+		return writeOrderEvent(ctx, tx, order)
+	})
+	if err != nil {
+		return err
+	}
+	recovery.Go("trading.execute", func() { e.executeOrder(ctx, order) })
+	return nil
+}
+
+// checkActivation checks a scheduled order as PlaceOrder checks new orders.
+// The velocity limit was applied when it was scheduled.
+func (e *Engine) checkActivation(ctx context.Context, order *model.Order) error {
+	if err := e.checkHalt(ctx, order.UserID); err != nil {
+		return err
+	}
+	if e.risk != nil {
+		if err := e.risk.Check(ctx, order); err != nil {
+			return err
+		}
+	}
+	return e.checkFunds(ctx, order)
+}
+
+// cancelScheduledOrder cancels an order before it is activated
+func (e *Engine) cancelScheduledOrder(ctx context.Context, order *model.Order) (*model.Order, error) {
+	lock, err := e.locker.Obtain(ctx, activationLockKey(order.ID), executionLockTTL)
+	if err == cache.ErrLockNotObtained {
+		return nil, ErrOrderActivating
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Release(ctx)
+
+	order, err = e.orders.GetCurrent(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != model.OrderStatusScheduled {
+		return nil, ErrOrderNotOpen
+	}
+
+	if err := order.Transition(model.OrderStatusCancelled, e.clock.Now()); err != nil {
+		return nil, err
+	}
+	err = e.uow.Do(ctx, func(tx repository.Tx) error {
+		if err := tx.Orders().Update(ctx, order); err != nil {
+			return err
+		}
+		return writeOrderEvent(ctx, tx, order)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return order, nil
+}
+
+// activationLockKey returns the lock key serializing a scheduled order's
+// activation and cancellation
+func activationLockKey(orderID uuid.UUID) string {
+	return "activate:" + orderID.String()
+}
@@ -0,0 +1,124 @@
+package trading
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/queue"
+	"github.com/sungminna/upbit-trading-platform/pkg/clock"
+)
+
+func scheduleOrder(t *testing.T, engine *Engine, activateAt time.Time) *model.Order {
+	price := 100000000.0
+	order, err := engine.PlaceOrder(context.Background(), uuid.New(), PlaceOrderRequest{
+		Market: "KRW-BTC", Side: model.OrderSideBid, Type: model.OrderTypeLimit, Quantity: 0.01, Price: &price,
+		ActivateAt: &activateAt,
+	})
+	require.NoError(t, err)
+	return order
+}
+
+func TestEngine_ActivatesScheduledOrders(t *testing.T) {
+	engine, store := newTestEngine()
+	now := clock.NewFake(time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC))
+	engine.WithQueue(queue.NewQueue(store.Jobs())).WithClock(now)
+	ctx := context.Background()
+
+	order := scheduleOrder(t, engine, now.Now().Add(time.Hour))
+	assert.Equal(t, model.OrderStatusScheduled, order.Status)
+
+	now.Advance(59 * time.Minute)
+	engine.activateDueOrders(ctx)
+	jobs, err := store.Jobs().List(ctx, repository.JobQueueFilter{Kind: SubmitOrderJob})
+	require.NoError(t, err)
+	assert.Empty(t, jobs, "not due yet")
+
+	now.Advance(time.Minute)
+	engine.activateDueOrders(ctx)
+	stored, err := store.Orders().GetByID(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderStatusPending, stored.Status)
+	assert.Equal(t, now.Now(), stored.UpdatedAt)
+	jobs, err = store.Jobs().List(ctx, repository.JobQueueFilter{Kind: SubmitOrderJob})
+	require.NoError(t, err)
+	assert.Len(t, jobs, 1)
+}
+
+func TestEngine_ActivationFitsSellsToPosition(t *testing.T) {
+	engine, store := newTestEngine()
+	now := clock.NewFake(time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC))
+	engine.WithQueue(queue.NewQueue(store.Jobs())).WithPositions(store.Positions()).WithClock(now)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	position := model.NewPosition(userID, "KRW-BTC", model.PositionSideLong, 100000000, 1, now.Now())
+	require.NoError(t, store.Positions().Create(ctx, position))
+	sell := func(qty float64, activateAt *time.Time) *model.Order {
+		order, err := engine.PlaceOrder(ctx, userID, PlaceOrderRequest{
+			Market: "KRW-BTC", Side: model.OrderSideAsk, Type: model.OrderTypeMarket, Quantity: qty,
+			PositionID: &position.ID, ActivateAt: activateAt,
+		})
+		require.NoError(t, err)
+		return order
+	}
+
+	first, second := now.Now().Add(time.Hour), now.Now().Add(time.Hour+time.Minute)
+	partial := sell(0.8, &first)
+	rest := sell(0.5, &second)
+	// Placed meanwhile, before either is activated
+	sell(0.6, nil)
+
+	now.Advance(time.Hour + time.Minute)
+	engine.activateDueOrders(ctx)
+
+	// The immediate sell leaves 0.4 of the position for the scheduled sell
+	// activated first, and nothing for the other
+	stored, err := store.Orders().GetByID(ctx, partial.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderStatusPending, stored.Status)
+	assert.InDelta(t, 0.4, stored.Quantity, 1e-12)
+	stored, err = store.Orders().GetByID(ctx, rest.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderStatusFailed, stored.Status)
+}
+
+func TestEngine_CancelsScheduledOrders(t *testing.T) {
+	engine, store := newTestEngine()
+	engine.WithQueue(queue.NewQueue(store.Jobs()))
+	ctx := context.Background()
+
+	order := scheduleOrder(t, engine, time.Now().Add(time.Hour))
+	cancelled, err := engine.CancelOrder(ctx, order.UserID, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderStatusCancelled, cancelled.Status)
+
+	due := time.Now().Add(-time.Second)
+	cancelled.ActivateAt = &due
+	require.NoError(t, store.Orders().Update(ctx, cancelled))
+	engine.activateDueOrders(ctx)
+
+	stored, err := store.Orders().GetByID(ctx, order.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.OrderStatusCancelled, stored.Status)
+
+	_, err = engine.CancelOrder(ctx, order.UserID, order.ID)
+	assert.ErrorIs(t, err, ErrOrderNotOpen)
+}
+
+func TestEngine_RejectsFarScheduledOrders(t *testing.T) {
+	engine, _ := newTestEngine()
+
+	price := 100000000.0
+	activateAt := time.Now().Add(maxScheduleAhead + time.Hour)
+	_, err := engine.PlaceOrder(context.Background(), uuid.New(), PlaceOrderRequest{
+		Market: "KRW-BTC", Side: model.OrderSideBid, Type: model.OrderTypeLimit, Quantity: 0.01, Price: &price,
+		ActivateAt: &activateAt,
+	})
+	assert.ErrorIs(t, err, ErrInvalidActivateAt)
+}
@@ -0,0 +1,34 @@
+package trading
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// WithStrategies lets users attribute their orders to a template of the
+// strategy catalog. Without it, orders from the API can't name a strategy.
+func (e *Engine) WithStrategies(templates repository.StrategyTemplateRepository) *Engine {
+	e.strategies = templates
+	return e
+}
+
+// checkStrategy rejects a user's order naming a strategy that isn't in the
+// catalog. Automations aren't checked: a guard keeps its template's ID after
+// the template is deleted, and its exits still belong to that strategy.
+func (e *Engine) checkStrategy(ctx context.Context, req PlaceOrderRequest) error {
+	if req.StrategyID == nil || req.Source != "" {
+		return nil
+	}
+	if e.strategies == nil {
+		return ErrUnknownStrategy
+	}
+	if _, err := e.strategies.GetByID(ctx, *req.StrategyID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrUnknownStrategy
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package trading
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+)
+
+// WithVelocityLimits caps how many orders each user may place per minute and
+// per hour. defaults apply to every user without an override; admins set
+// overrides through SetVelocityOverride.
+func (e *Engine) WithVelocityLimits(defaults model.VelocityLimits, overrides repository.VelocityLimitRepository) *Engine {
+	e.velocityDefaults = defaults
+	e.velocityOverrides = overrides
+	return e
+}
+
+// VelocityLimits returns the limits that apply to a user
+func (e *Engine) VelocityLimits(ctx context.Context, userID uuid.UUID) (*model.VelocityLimits, error) {
+	if e.velocityOverrides != nil {
+		limits, err := e.velocityOverrides.Get(ctx, userID)
+		if err == nil {
+			limits.Override = true
+			return limits, nil
+		}
+		if !errors.Is(err, repository.ErrNotFound) {
+			return nil, fmt.Errorf("failed to load velocity limits: %w", err)
+		}
+	}
+
+	limits := e.velocityDefaults
+	limits.UserID = userID
+	return &limits, nil
+}
+
+// SetVelocityOverride replaces the platform defaults for one user, e.g. to
+// let a market maker place more orders or to throttle a misbehaving bot
+func (e *Engine) SetVelocityOverride(ctx context.Context, limits *model.VelocityLimits) error {
+	if e.velocityOverrides == nil {
+		return ErrVelocityLimitsDisabled
+	}
+	if limits.PerMinute < 0 || limits.PerHour < 0 {
+		return ErrInvalidVelocityLimits
+	}
+
+	limits.Override = true
+	limits.UpdatedAt = e.clock.Now()
+	return e.velocityOverrides.Save(ctx, limits)
+}
+
+// ClearVelocityOverride returns a user to the platform defaults
+func (e *Engine) ClearVelocityOverride(ctx context.Context, userID uuid.UUID) error {
+	if e.velocityOverrides == nil {
+		return ErrVelocityLimitsDisabled
+	}
+	return e.velocityOverrides.Delete(ctx, userID)
+}
+
+// checkVelocity rejects an order if the user already placed as many orders
+// as a limit allows within its window. Concurrent orders on several instances
+// can overshoot a limit by a few orders.
+func (e *Engine) checkVelocity(ctx context.Context, userID uuid.UUID) error {
+	limits, err := e.VelocityLimits(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if limits.PerMinute == 0 && limits.PerHour == 0 {
+		return nil
+	}
+
+	now := e.clock.Now()
+	for _, w := range []struct {
+		window time.Duration
+		limit  int
+	}{
+		{time.Minute, limits.PerMinute},
+		{time.Hour, limits.PerHour},
+	} {
+		if w.limit == 0 {
+			continue
+		}
+
+		// Counted on the primary, so orders placed a moment ago count
+		placed, oldest, err := e.orders.CountCreatedSince(ctx, userID, now.Add(-w.window))
+		if err != nil {
+			return err
+		}
+		if placed >= w.limit {
+			return &VelocityLimitError{
+				Limit:      w.limit,
+				Window:     w.window,
+				RetryAfter: oldest.Add(w.window).Sub(now),
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package trading
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+func TestEngine_VelocityLimits(t *testing.T) {
+	engine, store := newTestEngine()
+	engine.WithVelocityLimits(model.VelocityLimits{PerMinute: 3, PerHour: 3}, store.VelocityLimits())
+	ctx := context.Background()
+	userID, otherID := uuid.New(), uuid.New()
+	price := 100000000.0
+	req := PlaceOrderRequest{Market: "KRW-BTC", Side: model.OrderSideBid, Type: model.OrderTypeLimit, Quantity: 0.01, Price: &price}
+
+	// An order placed two minutes ago counts against the hour only
+	old := model.NewOrder(userID, "KRW-BTC", model.OrderSideBid, model.OrderTypeLimit, 0.01, &price, time.Now())
+	old.CreatedAt = time.Now().Add(-2 * time.Minute)
+	require.NoError(t, store.Orders().Create(ctx, old))
+
+	for range 2 {
+		_, err := engine.PlaceOrder(ctx, userID, req)
+		require.NoError(t, err)
+	}
+	_, err := engine.PlaceOrder(ctx, userID, req)
+	var limitErr *VelocityLimitError
+	require.ErrorAs(t, err, &limitErr)
+	assert.ErrorIs(t, err, ErrVelocityLimit)
+	assert.Equal(t, time.Hour, limitErr.Window)
+	assert.Equal(t, 3, limitErr.Limit)
+	assert.InDelta(t, 58*time.Minute, limitErr.RetryAfter, float64(time.Second))
+
+	_, err = engine.PlaceOrder(ctx, otherID, req)
+	assert.NoError(t, err, "limits are per user")
+
+	// An admin override lifts the hourly cap
+	require.NoError(t, engine.SetVelocityOverride(ctx, &model.VelocityLimits{UserID: userID, PerMinute: 5, Reason: "market maker"}))
+	limits, err := engine.VelocityLimits(ctx, userID)
+	require.NoError(t, err)
+	assert.True(t, limits.Override)
+	_, err = engine.PlaceOrder(ctx, userID, req)
+	assert.NoError(t, err)
+
+	assert.ErrorIs(t, engine.SetVelocityOverride(ctx, &model.VelocityLimits{UserID: userID, PerHour: -1}), ErrInvalidVelocityLimits)
+	require.NoError(t, engine.ClearVelocityOverride(ctx, userID))
+	limits, err = engine.VelocityLimits(ctx, userID)
+	require.NoError(t, err)
+	assert.False(t, limits.Override)
+	assert.Equal(t, 3, limits.PerHour)
+}
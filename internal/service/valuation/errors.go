@@ -0,0 +1,15 @@
+package valuation
+
+var (
+	ErrUnsupportedCurrency = &ValuationError{message: "unsupported display currency"}
+	ErrInvalidMarket       = &ValuationError{message: "reference market must be a KRW market, e.g. KRW-USDT"}
+)
+
+// ValuationError represents an invalid valuation request
+type ValuationError struct {
+	message string
+}
+
+func (e *ValuationError) Error() string {
+	return e.message
+}
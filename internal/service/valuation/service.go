@@ -0,0 +1,234 @@
+// Package valuation converts KRW values into display currencies such as USD
+// or BTC. Rates come from the price of a reference KRW market on Upbit, e.g.
+// KRW-USDT for USD, now and over the period a report covers.
+package valuation
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/quotation"
+)
+
+const (
+	// Base is the currency every value is kept in
+	Base = "KRW"
+	// rateTTL is how long a current rate is reused
+	rateTTL = 10 * time.Second
+	// hourlyHistory is the longest period priced with hourly rates; longer
+	// ones use daily rates
+	hourlyHistory = 14 * 24 * time.Hour
+)
+
+// DefaultMarkets are the reference markets of the built-in display
+// currencies. Tether stands in for the dollar, as Upbit has no KRW-USD market.
+var DefaultMarkets = map[string]string{
+	"USD": "KRW-USDT",
+	"BTC": "KRW-BTC",
+}
+
+// PriceSource provides reference prices; gateway.QuotationAPI satisfies it
+type PriceSource interface {
+	GetTicker(ctx context.Context, markets []string) ([]quotation.Ticker, error)
+	GetCandleRange(ctx context.Context, market string, interval model.CandleInterval, from, to time.Time) ([]model.Candle, error)
+}
+
+// Quote converts KRW values into a display currency. A nil Quote leaves
+// values in KRW.
+type Quote struct {
+	Currency string         `json:"currency"`
+	Market   string         `json:"market,omitempty"` // Reference market
+	Rate     float64        `json:"rate"`             // KRW per unit, now
+	PricedAt time.Time      `json:"priced_at"`
+	history  []model.Candle // Oldest first
+}
+
+// Code returns the quote's currency, KRW for a nil Quote
+func (q *Quote) Code() string {
+	if q == nil {
+		return Base
+	}
+	return q.Currency
+}
+
+// RateAt returns the KRW per unit at a time: the close of the last reference
+// candle starting before it. Times without history, or from when the quote
+// was priced, use the current rate.
+func (q *Quote) RateAt(at time.Time) float64 {
+	if q == nil {
+		return 1
+	}
+	if len(q.history) == 0 || !at.Before(q.PricedAt) {
+		return q.Rate
+	}
+	i := sort.Search(len(q.history), func(i int) bool {
+		return !q.history[i].Timestamp.Before(at)
+	})
+	if i == 0 {
+		return q.history[0].OpenPrice
+	}
+	return q.history[i-1].ClosePrice
+}
+
+// Convert converts a KRW value at a time into the quote's currency
+func (q *Quote) Convert(krw float64, at time.Time) float64 {
+	if q == nil {
+		return krw
+	}
+	rate := q.RateAt(at)
+	if rate <= 0 {
+		return 0
+	}
+	return krw / rate
+}
+
+// IsBase reports whether a display currency leaves values in KRW; empty
+// means KRW
+func IsBase(currency string) bool {
+	return currency == "" || strings.EqualFold(currency, Base)
+}
+
+type cachedRate struct {
+	rate     float64
+	pricedAt time.Time
+}
+
+// Service quotes display currencies
+type Service struct {
+	prices  PriceSource
+	markets map[string]string // Reference market by currency
+
+	mu    sync.Mutex
+	rates map[string]cachedRate
+}
+
+// NewService creates a valuation service with the default display currencies
+func NewService(prices PriceSource) *Service {
+	s := &Service{
+		prices:  prices,
+		markets: make(map[string]string),
+		rates:   make(map[string]cachedRate),
+	}
+	for currency, market := range DefaultMarkets {
+		s.markets[currency] = market
+	}
+	return s
+}
+
+// WithMarket adds a display currency, or replaces the reference market of
+// one, priced by a KRW market
+func (s *Service) WithMarket(currency, market string) (*Service, error) {
+	currency, market = strings.ToUpper(currency), strings.ToUpper(market)
+	if currency == "" || IsBase(currency) || !strings.HasPrefix(market, Base+"-") || len(market) == len(Base)+1 {
+		return nil, ErrInvalidMarket
+	}
+	s.markets[currency] = market
+	return s, nil
+}
+
+// Currencies returns the display currencies, KRW first
+func (s *Service) Currencies() []string {
+	currencies := make([]string, 0, len(s.markets))
+	for currency := range s.markets {
+		currencies = append(currencies, currency)
+	}
+	slices.Sort(currencies)
+	return append([]string{Base}, currencies...)
+}
+
+// Quote returns the current rate of a display currency with its history over
+// [from, to), so values can be converted at the time they were recorded. A
+// zero from skips the history. KRW quotes are nil.
+func (s *Service) Quote(ctx context.Context, currency string, from, to time.Time) (*Quote, error) {
+	if IsBase(currency) {
+		return nil, nil
+	}
+	currency = strings.ToUpper(currency)
+	market, ok := s.markets[currency]
+	if !ok {
+		return nil, ErrUnsupportedCurrency
+	}
+
+	rate, err := s.currentRate(ctx, market)
+	if err != nil {
+		return nil, err
+	}
+	quote := &Quote{Currency: currency, Market: market, Rate: rate.rate, PricedAt: rate.pricedAt}
+
+	if !from.IsZero() && from.Before(to) {
+		interval := model.CandleInterval1h
+		if to.Sub(from) > hourlyHistory {
+			interval = model.CandleInterval1d
+		}
+		candles, err := s.prices.GetCandleRange(ctx, market, interval, from.Add(-interval.Duration()), to)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s rates: %w", currency, err)
+		}
+		quote.history = slices.Clone(candles)
+		sort.Slice(quote.history, func(i, j int) bool {
+			return quote.history[i].Timestamp.Before(quote.history[j].Timestamp)
+		})
+	}
+	return quote, nil
+}
+
+// Rates returns the current rate of every display currency but KRW
+func (s *Service) Rates(ctx context.Context) ([]*Quote, error) {
+	currencies := s.Currencies()[1:]
+	markets := make([]string, len(currencies))
+	for i, currency := range currencies {
+		markets[i] = s.markets[currency]
+	}
+	tickers, err := s.prices.GetTicker(ctx, markets)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rates: %w", err)
+	}
+
+	now := time.Now()
+	prices := make(map[string]float64, len(tickers))
+	s.mu.Lock()
+	for _, ticker := range tickers {
+		prices[ticker.Market] = ticker.TradePrice
+		s.rates[ticker.Market] = cachedRate{rate: ticker.TradePrice, pricedAt: now}
+	}
+	s.mu.Unlock()
+
+	quotes := make([]*Quote, 0, len(currencies))
+	for i, currency := range currencies {
+		if price, ok := prices[markets[i]]; ok && price > 0 {
+			quotes = append(quotes, &Quote{Currency: currency, Market: markets[i], Rate: price, PricedAt: now})
+		}
+	}
+	return quotes, nil
+}
+
+// currentRate returns the price of a reference market, reusing prices
+// fetched in the last rateTTL
+func (s *Service) currentRate(ctx context.Context, market string) (cachedRate, error) {
+	s.mu.Lock()
+	cached, ok := s.rates[market]
+	s.mu.Unlock()
+	if ok && time.Since(cached.pricedAt) < rateTTL {
+		return cached, nil
+	}
+
+	tickers, err := s.prices.GetTicker(ctx, []string{market})
+	if err != nil {
+		return cachedRate{}, fmt.Errorf("failed to get %s price: %w", market, err)
+	}
+	if len(tickers) == 0 || tickers[0].TradePrice <= 0 {
+		return cachedRate{}, fmt.Errorf("no price for %s", market)
+	}
+
+	rate := cachedRate{rate: tickers[0].TradePrice, pricedAt: time.Now()}
+	s.mu.Lock()
+	s.rates[market] = rate
+	s.mu.Unlock()
+	return rate, nil
+}
@@ -0,0 +1,88 @@
+package valuation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/quotation"
+)
+
+type stubPrices struct {
+	prices  map[string]float64
+	candles []model.Candle
+	tickers int // GetTicker calls
+}
+
+func (s *stubPrices) GetTicker(ctx context.Context, markets []string) ([]quotation.Ticker, error) {
+	s.tickers++
+	var tickers []quotation.Ticker
+	for _, market := range markets {
+		if price, ok := s.prices[market]; ok {
+			tickers = append(tickers, quotation.Ticker{Market: market, TradePrice: price})
+		}
+	}
+	return tickers, nil
+}
+
+func (s *stubPrices) GetCandleRange(ctx context.Context, market string, interval model.CandleInterval, from, to time.Time) ([]model.Candle, error) {
+	return s.candles, nil
+}
+
+func TestService_Quote(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	prices := &stubPrices{
+		prices: map[string]float64{"KRW-USDT": 1400},
+		// Newest first, as Upbit returns them
+		candles: []model.Candle{
+			{Timestamp: start.Add(time.Hour), OpenPrice: 1310, ClosePrice: 1320},
+			{Timestamp: start, OpenPrice: 1300, ClosePrice: 1310},
+		},
+	}
+	service := NewService(prices)
+
+	krw, err := service.Quote(ctx, "krw", start, start.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Nil(t, krw)
+	assert.Equal(t, "KRW", krw.Code())
+	assert.Equal(t, 1000.0, krw.Convert(1000, start))
+
+	usd, err := service.Quote(ctx, "usd", start, start.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, "USD", usd.Code())
+	assert.Equal(t, "KRW-USDT", usd.Market)
+	assert.Equal(t, 1300.0, usd.RateAt(start), "the open before the first close")
+	assert.Equal(t, 1310.0, usd.RateAt(start.Add(30*time.Minute)))
+	assert.Equal(t, 1320.0, usd.RateAt(start.Add(3*time.Hour)))
+	assert.Equal(t, 1400.0, usd.RateAt(time.Now()), "now is the current rate")
+	assert.InDelta(t, 10, usd.Convert(13100, start.Add(30*time.Minute)), 1e-9)
+
+	_, err = service.Quote(ctx, "USD", time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, prices.tickers, "the current rate is reused")
+
+	_, err = service.Quote(ctx, "EUR", start, start.Add(time.Hour))
+	assert.ErrorIs(t, err, ErrUnsupportedCurrency)
+}
+
+func TestService_WithMarket(t *testing.T) {
+	prices := &stubPrices{prices: map[string]float64{"KRW-USDT": 1400, "KRW-BTC": 140000000, "KRW-ETH": 5000000}}
+	service, err := NewService(prices).WithMarket("eth", "krw-eth")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"KRW", "BTC", "ETH", "USD"}, service.Currencies())
+
+	rates, err := service.Rates(context.Background())
+	require.NoError(t, err)
+	require.Len(t, rates, 3)
+	assert.Equal(t, "ETH", rates[1].Currency)
+	assert.Equal(t, 5000000.0, rates[1].Rate)
+
+	for _, bad := range [][2]string{{"KRW", "KRW-BTC"}, {"ETH", "BTC-ETH"}, {"ETH", "KRW-"}, {"", "KRW-ETH"}} {
+		_, err := service.WithMarket(bad[0], bad[1])
+		assert.ErrorIs(t, err, ErrInvalidMarket, bad)
+	}
+}
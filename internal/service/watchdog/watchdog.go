@@ -0,0 +1,292 @@
+// Package watchdog detects automated trading that misbehaves and pauses it
+package watchdog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/notification"
+	"github.com/sungminna/upbit-trading-platform/internal/service/scheduler"
+	"github.com/sungminna/upbit-trading-platform/internal/service/trading"
+	"github.com/sungminna/upbit-trading-platform/pkg/cache"
+)
+
+const (
+	checkInterval = time.Minute
+	claimKey      = "watchdog:"
+	// quantityTolerance absorbs float rounding when comparing quantities
+	quantityTolerance = 1e-8
+)
+
+// Anomaly kinds
+const (
+	AnomalyDuplicateExit = "duplicate_exit" // Open sells exceed the position
+	AnomalyFailedExits   = "failed_exits"   // A position's exits keep failing
+	AnomalyTriggerRate   = "trigger_rate"   // Drawdown guards fire too often
+)
+
+// Config holds the anomaly thresholds
+type Config struct {
+	FailedExits        int // Failed exits of a position within FailedExitWindow; 0 disables
+	FailedExitWindow   time.Duration
+	MaxTriggersPerHour int  // Drawdown guard triggers per user; 0 disables
+	HaltOnAnomaly      bool // Halt the user's trading, pausing their automation
+}
+
+// DefaultConfig returns the default thresholds
+func DefaultConfig() Config {
+	return Config{
+		FailedExits:        3,
+		FailedExitWindow:   time.Hour,
+		MaxTriggersPerHour: 5,
+		HaltOnAnomaly:      true,
+	}
+}
+
+// Anomaly is suspicious trading found for a user
+type Anomaly struct {
+	Kind       string     `json:"kind"`
+	UserID     uuid.UUID  `json:"user_id"`
+	PositionID *uuid.UUID `json:"position_id,omitempty"`
+	Market     string     `json:"market,omitempty"`
+	Detail     string     `json:"detail"`
+}
+
+// subject identifies what the anomaly is about, so each is acted on once
+func (a Anomaly) subject() string {
+	if a.PositionID != nil {
+		return a.PositionID.String()
+	}
+	return a.UserID.String()
+}
+
+// Halter halts a user's trading; trading.Engine satisfies it
+type Halter interface {
+	Halt(ctx context.Context, userID uuid.UUID, reason, haltedBy string, cancelOrders bool) (*trading.HaltResult, error)
+	ActiveHalt(ctx context.Context, userID uuid.UUID) (*model.TradingHalt, error)
+}
+
+// Watchdog periodically inspects the orders and positions of users with an
+// active API key. Anomalies are reported to the user and, unless disabled,
+// halt their trading until they resume it.
+type Watchdog struct {
+	config    Config
+	apiKeys   repository.UserAPIKeyRepository
+	orders    repository.OrderRepository
+	positions repository.PositionRepository
+	guards    repository.DrawdownGuardRepository // Optional; enables the trigger rate check
+	halter    Halter
+	notifier  notification.Notifier // Optional
+	claims    cache.Cache           // Claims anomalies so instances act on each only once
+}
+
+// NewWatchdog creates a new watchdog
+func NewWatchdog(
+	config Config,
+	apiKeys repository.UserAPIKeyRepository,
+	orders repository.OrderRepository,
+	positions repository.PositionRepository,
+	halter Halter,
+	notifier notification.Notifier,
+	claims cache.Cache,
+) *Watchdog {
+	return &Watchdog{
+		config:    config,
+		apiKeys:   apiKeys,
+		orders:    orders,
+		positions: positions,
+		halter:    halter,
+		notifier:  notifier,
+		claims:    claims,
+	}
+}
+
+// WithGuards enables checking how often drawdown guards trigger
+func (w *Watchdog) WithGuards(guards repository.DrawdownGuardRepository) *Watchdog {
+	w.guards = guards
+	return w
+}
+
+// Job returns the job looking for anomalies
+func (w *Watchdog) Job() scheduler.Job {
+	return scheduler.Job{
+		Name:     "trading-watchdog",
+		Schedule: scheduler.Every(checkInterval),
+		Run:      w.Evaluate,
+	}
+}
+
+// Evaluate inspects every user with an active API key and acts on new
+// anomalies. A failure for one user doesn't stop the others.
+func (w *Watchdog) Evaluate(ctx context.Context, now time.Time) error {
+	userIDs, err := w.apiKeys.ListActiveUserIDs(ctx)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, userID := range userIDs {
+		anomalies, err := w.Inspect(ctx, userID, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("user %s: %w", userID, err))
+			continue
+		}
+		for _, anomaly := range anomalies {
+			if err := w.act(ctx, anomaly, now); err != nil {
+				errs = append(errs, fmt.Errorf("user %s: %w", userID, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Inspect returns the user's current anomalies
+func (w *Watchdog) Inspect(ctx context.Context, userID uuid.UUID, now time.Time) ([]Anomaly, error) {
+	positions, err := w.positions.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list positions: %w", err)
+	}
+	orders, err := w.orders.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list orders: %w", err)
+	}
+
+	// Sum the open and recently failed exits of each position
+	selling := make(map[uuid.UUID]float64)
+	failed := make(map[uuid.UUID]int)
+	since := now.Add(-w.config.FailedExitWindow)
+	for _, o := range orders {
+		if o.Side != model.OrderSideAsk || o.PositionID == nil {
+			continue
+		}
+		switch {
+		case isOpen(o):
+			selling[*o.PositionID] += o.Quantity - o.ExecutedQuantity
+		case o.Status == model.OrderStatusFailed && !o.UpdatedAt.Before(since):
+			failed[*o.PositionID]++
+		}
+	}
+
+	var anomalies []Anomaly
+	for _, p := range positions {
+		if p.Status != model.PositionStatusOpen {
+			continue
+		}
+		if qty := selling[p.ID]; qty > p.Quantity+quantityTolerance {
+			anomalies = append(anomalies, Anomaly{
+				Kind:       AnomalyDuplicateExit,
+				UserID:     userID,
+				PositionID: &p.ID,
+				Market:     p.Market,
+				Detail:     fmt.Sprintf("open sells of %g exceed the position of %g", qty, p.Quantity),
+			})
+		}
+		if n := failed[p.ID]; w.config.FailedExits > 0 && n >= w.config.FailedExits {
+			anomalies = append(anomalies, Anomaly{
+				Kind:       AnomalyFailedExits,
+				UserID:     userID,
+				PositionID: &p.ID,
+				Market:     p.Market,
+				Detail:     fmt.Sprintf("%d exits failed in the last %s", n, w.config.FailedExitWindow),
+			})
+		}
+	}
+
+	if w.guards != nil && w.config.MaxTriggersPerHour > 0 {
+		guards, err := w.guards.ListByUser(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list drawdown guards: %w", err)
+		}
+		var triggers int
+		for _, g := range guards {
+			if !g.DryRun && g.TriggeredAt != nil && g.TriggeredAt.After(now.Add(-time.Hour)) {
+				triggers++
+			}
+		}
+		if triggers > w.config.MaxTriggersPerHour {
+			anomalies = append(anomalies, Anomaly{
+				Kind:   AnomalyTriggerRate,
+				UserID: userID,
+				Detail: fmt.Sprintf("drawdown guards triggered %d times in the last hour", triggers),
+			})
+		}
+	}
+	return anomalies, nil
+}
+
+// act alerts the user of an anomaly and halts their trading. Each anomaly is
+// acted on once per FailedExitWindow, or per hour if that is shorter.
+func (w *Watchdog) act(ctx context.Context, anomaly Anomaly, now time.Time) error {
+	ttl := max(w.config.FailedExitWindow, time.Hour)
+	key := claimKey + anomaly.Kind + ":" + anomaly.subject()
+	claimed, err := w.claims.SetNX(ctx, key, []byte{1}, ttl)
+	if err != nil || !claimed {
+		return err
+	}
+	log.Printf("Watchdog found %s for user %s: %s", anomaly.Kind, anomaly.UserID, anomaly.Detail)
+
+	var haltErr error
+	halted := false
+	if w.config.HaltOnAnomaly {
+		halted, haltErr = w.halt(ctx, anomaly)
+	}
+	w.notify(ctx, anomaly, halted, now)
+	return haltErr
+}
+
+// halt halts the user's trading unless it already is
+func (w *Watchdog) halt(ctx context.Context, anomaly Anomaly) (bool, error) {
+	active, err := w.halter.ActiveHalt(ctx, anomaly.UserID)
+	if err != nil {
+		return false, err
+	}
+	if active != nil {
+		return true, nil
+	}
+
+	reason := fmt.Sprintf("watchdog: %s", anomaly.Detail)
+	if _, err := w.halter.Halt(ctx, anomaly.UserID, reason, model.HaltedByWatchdog, false); err != nil {
+		return false, fmt.Errorf("failed to halt trading: %w", err)
+	}
+	return true, nil
+}
+
+func (w *Watchdog) notify(ctx context.Context, anomaly Anomaly, halted bool, now time.Time) {
+	if w.notifier == nil {
+		return
+	}
+
+	message := anomaly.Detail
+	if anomaly.Market != "" {
+		message = fmt.Sprintf("%s: %s", anomaly.Market, anomaly.Detail)
+	}
+	message += "."
+	if halted {
+		message += " Trading is halted until you resume it."
+	}
+
+	data := map[string]any{"kind": anomaly.Kind, "halted": halted}
+	if anomaly.PositionID != nil {
+		data["position_id"] = *anomaly.PositionID
+		data["market"] = anomaly.Market
+	}
+	n := model.NewNotification(anomaly.UserID, model.NotificationWatchdog, "Trading anomaly detected", message, data, now)
+	n.DedupKey = anomaly.Kind + ":" + anomaly.subject()
+	if err := w.notifier.Notify(ctx, n); err != nil {
+		log.Printf("Error notifying user %s of %s: %v", anomaly.UserID, anomaly.Kind, err)
+	}
+}
+
+func isOpen(order *model.Order) bool {
+	switch order.Status {
+	case model.OrderStatusPending, model.OrderStatusSubmitted, model.OrderStatusPartial:
+		return true
+	}
+	return false
+}
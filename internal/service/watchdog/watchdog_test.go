@@ -0,0 +1,119 @@
+package watchdog
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+	"github.com/sungminna/upbit-trading-platform/internal/service/trading"
+	"github.com/sungminna/upbit-trading-platform/pkg/cache"
+)
+
+type recordingNotifier struct {
+	sent []*model.Notification
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, notification *model.Notification) error {
+	n.sent = append(n.sent, notification)
+	return nil
+}
+
+type recordingHalter struct {
+	halts []*model.TradingHalt
+}
+
+func (h *recordingHalter) Halt(ctx context.Context, userID uuid.UUID, reason, haltedBy string, cancelOrders bool) (*trading.HaltResult, error) {
+	halt := &model.TradingHalt{UserID: userID, Reason: reason, HaltedBy: haltedBy, CreatedAt: time.Now()}
+	h.halts = append(h.halts, halt)
+	return &trading.HaltResult{Halt: halt}, nil
+}
+
+func (h *recordingHalter) ActiveHalt(ctx context.Context, userID uuid.UUID) (*model.TradingHalt, error) {
+	for _, halt := range h.halts {
+		if halt.UserID == userID {
+			return halt, nil
+		}
+	}
+	return nil, nil
+}
+
+func exitOrder(t *testing.T, store *memory.Store, position *model.Position, qty float64, status model.OrderStatus) {
+	t.Helper()
+	order := model.NewOrder(position.UserID, position.Market, model.OrderSideAsk, model.OrderTypeMarket, qty, nil, time.Now())
+	order.PositionID = &position.ID
+	order.Status = status
+	require.NoError(t, store.Orders().Create(context.Background(), order))
+}
+
+func TestWatchdog_DuplicateExitHaltsOnce(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	userID := uuid.New()
+	require.NoError(t, store.APIKeys().Create(ctx, model.NewUserAPIKey(userID, "access", "secret", "", time.Now())))
+
+	position := model.NewPosition(userID, "KRW-BTC", model.PositionSideLong, 100, 1, time.Now())
+	require.NoError(t, store.Positions().Create(ctx, position))
+	exitOrder(t, store, position, 1, model.OrderStatusSubmitted)
+	exitOrder(t, store, position, 1, model.OrderStatusPending)
+	exitOrder(t, store, position, 1, model.OrderStatusCancelled)
+
+	halter, notifier := &recordingHalter{}, &recordingNotifier{}
+	w := NewWatchdog(DefaultConfig(), store.APIKeys(), store.Orders(), store.Positions(), halter, notifier, cache.NewMemoryCache())
+
+	anomalies, err := w.Inspect(ctx, userID, time.Now())
+	require.NoError(t, err)
+	require.Len(t, anomalies, 1)
+	assert.Equal(t, AnomalyDuplicateExit, anomalies[0].Kind)
+	assert.Equal(t, position.ID, *anomalies[0].PositionID)
+
+	require.NoError(t, w.Evaluate(ctx, time.Now()))
+	require.NoError(t, w.Evaluate(ctx, time.Now()))
+
+	require.Len(t, halter.halts, 1, "each anomaly is acted on once")
+	assert.Equal(t, model.HaltedByWatchdog, halter.halts[0].HaltedBy)
+	require.Len(t, notifier.sent, 1)
+	assert.Equal(t, model.NotificationWatchdog, notifier.sent[0].Type)
+	assert.Equal(t, true, notifier.sent[0].Data["halted"])
+}
+
+func TestWatchdog_FailedExitsAndTriggerRate(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	userID := uuid.New()
+	now := time.Now()
+
+	position := model.NewPosition(userID, "KRW-ETH", model.PositionSideLong, 10, 5, time.Now())
+	require.NoError(t, store.Positions().Create(ctx, position))
+	for range 2 {
+		exitOrder(t, store, position, 5, model.OrderStatusFailed)
+	}
+
+	config := DefaultConfig()
+	config.MaxTriggersPerHour = 1
+	w := NewWatchdog(config, store.APIKeys(), store.Orders(), store.Positions(), &recordingHalter{}, nil, cache.NewMemoryCache()).
+		WithGuards(store.DrawdownGuards())
+
+	anomalies, err := w.Inspect(ctx, userID, now)
+	require.NoError(t, err)
+	assert.Empty(t, anomalies, "two failures are under the limit of three")
+
+	exitOrder(t, store, position, 5, model.OrderStatusFailed)
+	for _, triggeredAt := range []time.Time{now.Add(-2 * time.Hour), now.Add(-time.Minute), now.Add(-time.Second)} {
+		guarded := model.NewPosition(userID, "KRW-XRP", model.PositionSideLong, 1, 1, time.Now())
+		guard := model.NewDrawdownGuard(guarded, 10, 30, time.Now())
+		guard.Trigger(0.8, triggeredAt)
+		require.NoError(t, store.DrawdownGuards().Save(ctx, guard))
+	}
+
+	anomalies, err = w.Inspect(ctx, userID, now)
+	require.NoError(t, err)
+	require.Len(t, anomalies, 2)
+	assert.Equal(t, AnomalyFailedExits, anomalies[0].Kind)
+	assert.Equal(t, AnomalyTriggerRate, anomalies[1].Kind)
+	assert.Nil(t, anomalies[1].PositionID)
+}
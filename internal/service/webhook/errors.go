@@ -0,0 +1,17 @@
+package webhook
+
+var (
+	ErrInvalidURL         = &WebhookError{message: "webhook URL must be an absolute http or https URL"}
+	ErrInvalidRetryPolicy = &WebhookError{message: "max_attempts must be 1-20 and initial_backoff 1-3600 seconds"}
+	ErrTooManyWebhooks    = &WebhookError{message: "too many webhooks"}
+	ErrPrivateTarget      = &WebhookError{message: "webhook URL resolves to a private address"}
+)
+
+// WebhookError represents a webhook validation error
+type WebhookError struct {
+	message string
+}
+
+func (e *WebhookError) Error() string {
+	return e.message
+}
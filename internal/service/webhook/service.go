@@ -0,0 +1,429 @@
+// Package webhook delivers events to users' HTTP endpoints with signed,
+// retried deliveries. Every attempt runs as a job on the durable job queue.
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/service/notification"
+	"github.com/sungminna/upbit-trading-platform/internal/service/queue"
+	"github.com/sungminna/upbit-trading-platform/pkg/clock"
+)
+
+// Retry policy defaults and bounds
+const (
+	DefaultMaxAttempts    = 8
+	DefaultInitialBackoff = 30 // Seconds
+	maxAttemptsLimit      = 20
+	initialBackoffLimit   = 3600
+	maxBackoff            = 6 * time.Hour
+)
+
+const (
+	// DeliverJob is the kind of queued job that attempts a delivery
+	DeliverJob         = "webhook.deliver"
+	maxWebhooksPerUser = 10
+)
+
+// deliverPayload is the payload of a DeliverJob
+type deliverPayload struct {
+	DeliveryID uuid.UUID `json:"delivery_id"`
+}
+
+// Config is the user-settable part of a webhook
+type Config struct {
+	URL            string   `json:"url"`
+	Events         []string `json:"events"`          // Every event when empty
+	MaxAttempts    int      `json:"max_attempts"`    // Defaults to 8
+	InitialBackoff int      `json:"initial_backoff"` // Seconds; defaults to 30
+}
+
+// Envelope is the JSON body of every delivery
+type Envelope struct {
+	ID        uuid.UUID       `json:"id"` // Event ID; stable across retries and redeliveries
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Service manages webhooks and delivers events to them from the job queue
+type Service struct {
+	webhooks   repository.WebhookRepository
+	deliveries repository.WebhookDeliveryRepository
+	uow        repository.UnitOfWork
+	sender     *sender
+	clock      clock.Clock
+}
+
+var _ notification.Channel = (*Service)(nil)
+
+// NewService creates a new webhook service delivering through q. Unless
+// allowPrivate is set, deliveries to loopback, private and link-local
+// addresses are refused.
+func NewService(webhooks repository.WebhookRepository, deliveries repository.WebhookDeliveryRepository, uow repository.UnitOfWork, q *queue.Queue, allowPrivate bool) *Service {
+	s := &Service{
+		webhooks:   webhooks,
+		deliveries: deliveries,
+		uow:        uow,
+		sender:     newSender(allowPrivate),
+		clock:      clock.Real,
+	}
+	q.Handle(DeliverJob, s.runDeliverJob)
+	return s
+}
+
+// WithClock sets the clock webhooks and deliveries are dated and retried by
+func (s *Service) WithClock(c clock.Clock) *Service {
+	s.clock = c
+	return s
+}
+
+// Create registers a webhook with a new signing secret
+func (s *Service) Create(ctx context.Context, userID uuid.UUID, cfg Config) (*model.Webhook, error) {
+	if err := validate(&cfg); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.webhooks.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxWebhooksPerUser {
+		return nil, ErrTooManyWebhooks
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.clock.Now()
+	webhook := &model.Webhook{
+		ID:             uuid.New(),
+		UserID:         userID,
+		URL:            cfg.URL,
+		Secret:         secret,
+		Events:         cfg.Events,
+		MaxAttempts:    cfg.MaxAttempts,
+		InitialBackoff: cfg.InitialBackoff,
+		Active:         true,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := s.webhooks.Create(ctx, webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// Get returns one of the user's webhooks
+func (s *Service) Get(ctx context.Context, userID, id uuid.UUID) (*model.Webhook, error) {
+	webhook, err := s.webhooks.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if webhook.UserID != userID {
+		return nil, repository.ErrNotFound
+	}
+	return webhook, nil
+}
+
+// List returns the user's webhooks, newest first
+func (s *Service) List(ctx context.Context, userID uuid.UUID) ([]*model.Webhook, error) {
+	return s.webhooks.ListByUser(ctx, userID)
+}
+
+// Update replaces a webhook's URL, events and retry policy. Pending
+// deliveries follow the new policy from their next attempt.
+func (s *Service) Update(ctx context.Context, userID, id uuid.UUID, cfg Config, active bool) (*model.Webhook, error) {
+	if err := validate(&cfg); err != nil {
+		return nil, err
+	}
+
+	webhook, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	webhook.URL = cfg.URL
+	webhook.Events = cfg.Events
+	webhook.MaxAttempts = cfg.MaxAttempts
+	webhook.InitialBackoff = cfg.InitialBackoff
+	webhook.Active = active
+	webhook.UpdatedAt = s.clock.Now()
+	if err := s.webhooks.Update(ctx, webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// RotateSecret replaces a webhook's signing secret. Deliveries are signed
+// with the new secret from their next attempt.
+func (s *Service) RotateSecret(ctx context.Context, userID, id uuid.UUID) (*model.Webhook, error) {
+	webhook, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		return nil, err
+	}
+	webhook.Secret = secret
+	webhook.UpdatedAt = s.clock.Now()
+	if err := s.webhooks.Update(ctx, webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// Delete removes one of the user's webhooks and its deliveries
+func (s *Service) Delete(ctx context.Context, userID, id uuid.UUID) error {
+	if _, err := s.Get(ctx, userID, id); err != nil {
+		return err
+	}
+	return s.webhooks.Delete(ctx, id)
+}
+
+// Deliveries returns the deliveries of one of the user's webhooks, newest first
+func (s *Service) Deliveries(ctx context.Context, userID, id uuid.UUID, filter repository.WebhookDeliveryFilter) ([]*model.WebhookDelivery, error) {
+	if _, err := s.Get(ctx, userID, id); err != nil {
+		return nil, err
+	}
+	return s.deliveries.ListByWebhook(ctx, id, filter)
+}
+
+// Redeliver queues a new delivery of a past delivery's payload, e.g. to
+// replay a dead delivery once the endpoint is fixed
+func (s *Service) Redeliver(ctx context.Context, userID, webhookID, deliveryID uuid.UUID) (*model.WebhookDelivery, error) {
+	if _, err := s.Get(ctx, userID, webhookID); err != nil {
+		return nil, err
+	}
+
+	previous, err := s.deliveries.GetByID(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if previous.WebhookID != webhookID {
+		return nil, repository.ErrNotFound
+	}
+
+	delivery := model.NewWebhookDelivery(webhookID, previous.EventType, previous.Payload, s.clock.Now())
+	err = s.uow.Do(ctx, func(tx repository.Tx) error {
+		return createDelivery(ctx, tx, delivery)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// Enqueue queues an event for every active webhook of the user subscribed to
+// its type
+func (s *Service) Enqueue(ctx context.Context, userID uuid.UUID, eventType string, eventID uuid.UUID, data any) error {
+	webhooks, err := s.webhooks.ListByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	var payload []byte
+	var deliveries []*model.WebhookDelivery
+	for _, webhook := range webhooks {
+		if !webhook.Active || !webhook.Wants(eventType) {
+			continue
+		}
+
+		if payload == nil {
+			raw, ok := data.(json.RawMessage)
+			if !ok {
+				if raw, err = json.Marshal(data); err != nil {
+					return fmt.Errorf("failed to encode webhook event: %w", err)
+				}
+			}
+			envelope := Envelope{ID: eventID, Type: eventType, CreatedAt: s.clock.Now(), Data: raw}
+			if payload, err = json.Marshal(envelope); err != nil {
+				return fmt.Errorf("failed to encode webhook event: %w", err)
+			}
+		}
+		deliveries = append(deliveries, model.NewWebhookDelivery(webhook.ID, eventType, payload, s.clock.Now()))
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	return s.uow.Do(ctx, func(tx repository.Tx) error {
+		for _, delivery := range deliveries {
+			if err := createDelivery(ctx, tx, delivery); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// createDelivery stores a delivery together with the job of its first attempt
+func createDelivery(ctx context.Context, tx repository.Tx, delivery *model.WebhookDelivery) error {
+	if err := tx.WebhookDeliveries().Create(ctx, delivery); err != nil {
+		return err
+	}
+	return enqueueAttempt(ctx, tx, delivery)
+}
+
+// enqueueAttempt queues the job attempting a delivery at its next attempt time
+func enqueueAttempt(ctx context.Context, tx repository.Tx, delivery *model.WebhookDelivery) error {
+	job, err := queue.NewJob(DeliverJob, deliverPayload{DeliveryID: delivery.ID}, queue.DefaultMaxAttempts, delivery.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	if delivery.NextAttemptAt != nil {
+		job.RunAt = *delivery.NextAttemptAt
+	}
+	return tx.Jobs().Enqueue(ctx, job)
+}
+
+// Name returns the notification channel name
+func (s *Service) Name() string {
+	return "webhook"
+}
+
+// Send queues a notification for the user's webhooks
+func (s *Service) Send(ctx context.Context, n *model.Notification) error {
+	return s.Enqueue(ctx, n.UserID, n.Type, n.ID, n)
+}
+
+// HandleEvent queues order events from the event bus for the order owner's
+// webhooks. Other events are ignored.
+func (s *Service) HandleEvent(ctx context.Context, event *model.OutboxEvent) error {
+	if event.AggregateType != "order" {
+		return nil
+	}
+
+	var order struct {
+		UserID uuid.UUID `json:"user_id"`
+	}
+	if err := json.Unmarshal(event.Payload, &order); err != nil {
+		return fmt.Errorf("failed to decode order event: %w", err)
+	}
+	return s.Enqueue(ctx, order.UserID, event.EventType, event.ID, event.Payload)
+}
+
+// runDeliverJob attempts the job's delivery unless it is no longer pending.
+// Failed attempts are retried by the webhook's own policy, as a new job, so
+// the job only fails when the outcome can't be recorded.
+func (s *Service) runDeliverJob(ctx context.Context, job *model.QueuedJob) error {
+	var payload deliverPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return fmt.Errorf("invalid %s payload: %w", DeliverJob, err)
+	}
+
+	delivery, err := s.deliveries.GetByID(ctx, payload.DeliveryID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil // Deleted along with its webhook
+	}
+	if err != nil {
+		return err
+	}
+	if delivery.Status != model.WebhookDeliveryPending {
+		return nil
+	}
+	return s.attempt(ctx, delivery)
+}
+
+// attempt sends a delivery once and records the outcome, queueing a retry or
+// marking it dead when it fails
+func (s *Service) attempt(ctx context.Context, delivery *model.WebhookDelivery) error {
+	webhook, err := s.webhooks.GetByID(ctx, delivery.WebhookID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil // Deleted along with its deliveries
+	}
+	if err != nil {
+		return err
+	}
+
+	now := s.clock.Now()
+	delivery.Attempts++
+	delivery.UpdatedAt = now
+
+	if !webhook.Active {
+		delivery.Status = model.WebhookDeliveryDead
+		delivery.LastError = "webhook is disabled"
+		delivery.NextAttemptAt = nil
+		return s.deliveries.Update(ctx, delivery)
+	}
+
+	statusCode, err := s.sender.send(ctx, webhook, delivery, now)
+	delivery.LastStatusCode = statusCode
+	switch {
+	case err == nil:
+		delivery.Status = model.WebhookDeliverySucceeded
+		delivery.LastError = ""
+		delivery.NextAttemptAt = nil
+		delivery.DeliveredAt = &now
+	case delivery.Attempts >= webhook.MaxAttempts:
+		delivery.Status = model.WebhookDeliveryDead
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = nil
+	default:
+		next := now.Add(Backoff(webhook.InitialBackoff, delivery.Attempts))
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = &next
+		return s.uow.Do(ctx, func(tx repository.Tx) error {
+			if err := tx.WebhookDeliveries().Update(ctx, delivery); err != nil {
+				return err
+			}
+			return enqueueAttempt(ctx, tx, delivery)
+		})
+	}
+
+	return s.deliveries.Update(ctx, delivery)
+}
+
+// Backoff returns the wait after the given number of failed attempts: the
+// initial backoff in seconds, doubled after every further failure, capped at
+// 6 hours
+func Backoff(initialBackoff, attempts int) time.Duration {
+	wait := time.Duration(initialBackoff) * time.Second
+	for i := 1; i < attempts && wait < maxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, maxBackoff)
+}
+
+// validate applies retry policy defaults and checks the config
+func validate(cfg *Config) error {
+	u, err := url.Parse(cfg.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidURL
+	}
+
+	if cfg.MaxAttempts == 0 {
+		cfg.MaxAttempts = DefaultMaxAttempts
+	}
+	if cfg.InitialBackoff == 0 {
+		cfg.InitialBackoff = DefaultInitialBackoff
+	}
+	if cfg.MaxAttempts < 1 || cfg.MaxAttempts > maxAttemptsLimit ||
+		cfg.InitialBackoff < 1 || cfg.InitialBackoff > initialBackoffLimit {
+		return ErrInvalidRetryPolicy
+	}
+	return nil
+}
+
+// newSecret returns a random 256-bit signing secret
+func newSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
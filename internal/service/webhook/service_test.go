@@ -0,0 +1,182 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+	"github.com/sungminna/upbit-trading-platform/internal/service/queue"
+	"github.com/sungminna/upbit-trading-platform/pkg/clock"
+	"github.com/sungminna/upbit-trading-platform/pkg/webhook"
+)
+
+// receiver records deliveries and answers with a scripted status
+type receiver struct {
+	mu       sync.Mutex
+	status   int
+	bodies   [][]byte
+	headers  []http.Header
+	received int
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bodies = append(r.bodies, body)
+	r.headers = append(r.headers, req.Header.Clone())
+	r.received++
+	w.WriteHeader(r.status)
+}
+
+func newTestService(t *testing.T, status int) (*Service, *queue.Queue, *receiver, *httptest.Server, *memory.Store) {
+	t.Helper()
+	rec := &receiver{status: status}
+	server := httptest.NewServer(rec)
+	t.Cleanup(server.Close)
+
+	store := memory.NewStore()
+	q := queue.NewQueue(store.Jobs())
+	return NewService(store.Webhooks(), store.WebhookDeliveries(), store, q, true), q, rec, server, store
+}
+
+func TestService_DeliversSignedEvents(t *testing.T) {
+	service, q, rec, server, _ := newTestService(t, http.StatusOK)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	hook, err := service.Create(ctx, userID, Config{URL: server.URL, Events: []string{model.NotificationPriceAlert}})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultMaxAttempts, hook.MaxAttempts)
+
+	alert := model.NewNotification(userID, model.NotificationPriceAlert, "KRW-BTC above 100,000,000", "", nil, time.Now())
+	require.NoError(t, service.Send(ctx, alert))
+	// Not subscribed
+	require.NoError(t, service.Send(ctx, model.NewNotification(userID, model.NotificationDailySummary, "Daily summary", "", nil, time.Now())))
+
+	require.NoError(t, q.RunDue(ctx))
+	require.Equal(t, 1, rec.received)
+
+	body, header := rec.bodies[0], rec.headers[0]
+	assert.NoError(t, webhook.Verify(hook.Secret, header.Get(webhook.SignatureHeader), body, time.Now(), time.Minute))
+	assert.Equal(t, model.NotificationPriceAlert, header.Get(webhook.EventHeader))
+
+	var envelope Envelope
+	require.NoError(t, json.Unmarshal(body, &envelope))
+	assert.Equal(t, alert.ID, envelope.ID)
+
+	deliveries, err := service.Deliveries(ctx, userID, hook.ID, repository.WebhookDeliveryFilter{})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, model.WebhookDeliverySucceeded, deliveries[0].Status)
+	assert.Equal(t, header.Get(webhook.DeliveryHeader), deliveries[0].ID.String())
+
+	_, err = service.Deliveries(ctx, uuid.New(), hook.ID, repository.WebhookDeliveryFilter{})
+	assert.ErrorIs(t, err, repository.ErrNotFound, "other users can't see deliveries")
+}
+
+func TestService_RetriesThenDeadLetters(t *testing.T) {
+	service, _, rec, server, store := newTestService(t, http.StatusInternalServerError)
+	now := clock.NewFake(time.Now())
+	q := queue.NewQueue(store.Jobs()).WithClock(now)
+	service = NewService(store.Webhooks(), store.WebhookDeliveries(), store, q, true).WithClock(now)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	hook, err := service.Create(ctx, userID, Config{URL: server.URL, MaxAttempts: 2, InitialBackoff: 60})
+	require.NoError(t, err)
+	require.NoError(t, service.Send(ctx, model.NewNotification(userID, model.NotificationOrderFailed, "Order failed", "", nil, time.Now())))
+
+	require.NoError(t, q.RunDue(ctx))
+	deliveries, err := service.Deliveries(ctx, userID, hook.ID, repository.WebhookDeliveryFilter{})
+	require.NoError(t, err)
+	d := deliveries[0]
+	assert.Equal(t, model.WebhookDeliveryPending, d.Status)
+	assert.Equal(t, 1, d.Attempts)
+	assert.Equal(t, http.StatusInternalServerError, d.LastStatusCode)
+	assert.Equal(t, now.Now().Add(time.Minute), *d.NextAttemptAt)
+
+	// Not due until the backoff has passed
+	require.NoError(t, q.RunDue(ctx))
+	assert.Equal(t, 1, rec.received)
+
+	now.Advance(2 * time.Minute)
+	require.NoError(t, q.RunDue(ctx))
+
+	dead, err := service.Deliveries(ctx, userID, hook.ID, repository.WebhookDeliveryFilter{Status: model.WebhookDeliveryDead})
+	require.NoError(t, err)
+	require.Len(t, dead, 1)
+	assert.Equal(t, 2, dead[0].Attempts)
+	assert.Nil(t, dead[0].NextAttemptAt)
+
+	// Replaying a dead delivery queues a fresh one with the same payload
+	redelivered, err := service.Redeliver(ctx, userID, hook.ID, dead[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.WebhookDeliveryPending, redelivered.Status)
+	assert.JSONEq(t, string(dead[0].Payload), string(redelivered.Payload))
+}
+
+func TestService_RefusesPrivateTargets(t *testing.T) {
+	_, q, rec, server, store := newTestService(t, http.StatusOK)
+	service := NewService(store.Webhooks(), store.WebhookDeliveries(), store, q, false)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	hook, err := service.Create(ctx, userID, Config{URL: server.URL, MaxAttempts: 1})
+	require.NoError(t, err)
+	require.NoError(t, service.Send(ctx, model.NewNotification(userID, model.NotificationOrderFailed, "Order failed", "", nil, time.Now())))
+	require.NoError(t, q.RunDue(ctx))
+
+	assert.Zero(t, rec.received)
+	deliveries, err := service.Deliveries(ctx, userID, hook.ID, repository.WebhookDeliveryFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, model.WebhookDeliveryDead, deliveries[0].Status)
+	assert.Contains(t, deliveries[0].LastError, ErrPrivateTarget.Error())
+}
+
+func TestService_HandleOrderEvent(t *testing.T) {
+	service, q, rec, server, _ := newTestService(t, http.StatusOK)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	_, err := service.Create(ctx, userID, Config{URL: server.URL, Events: []string{model.EventOrderFilled}})
+	require.NoError(t, err)
+
+	price := 100000000.0
+	event, err := model.NewOrderEvent(model.EventOrderFilled, model.NewOrder(userID, "KRW-BTC", model.OrderSideBid, model.OrderTypeLimit, 0.01, &price, time.Now()), time.Now())
+	require.NoError(t, err)
+	require.NoError(t, service.HandleEvent(ctx, event))
+	require.NoError(t, q.RunDue(ctx))
+
+	require.Equal(t, 1, rec.received)
+	var envelope Envelope
+	require.NoError(t, json.Unmarshal(rec.bodies[0], &envelope))
+	assert.Equal(t, model.EventOrderFilled, envelope.Type)
+	assert.JSONEq(t, string(event.Payload), string(envelope.Data))
+}
+
+func TestValidate(t *testing.T) {
+	assert.ErrorIs(t, validate(&Config{URL: "ftp://example.com"}), ErrInvalidURL)
+	assert.ErrorIs(t, validate(&Config{URL: "/relative"}), ErrInvalidURL)
+	assert.ErrorIs(t, validate(&Config{URL: "https://example.com", MaxAttempts: 21}), ErrInvalidRetryPolicy)
+	assert.ErrorIs(t, validate(&Config{URL: "https://example.com", InitialBackoff: -1}), ErrInvalidRetryPolicy)
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, Backoff(30, 1))
+	assert.Equal(t, 60*time.Second, Backoff(30, 2))
+	assert.Equal(t, 240*time.Second, Backoff(30, 4))
+	assert.Equal(t, 6*time.Hour, Backoff(3600, 20))
+}
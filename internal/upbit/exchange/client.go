@@ -15,30 +15,61 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/pkg/callstats"
+	"github.com/sungminna/upbit-trading-platform/pkg/httppool"
 	"github.com/sungminna/upbit-trading-platform/pkg/ratelimit"
 )
 
 const (
-	baseURL = "https://api.upbit.com/v1"
+	DefaultBaseURL = "https://api.upbit.com/v1"
 )
 
+// RateLimitMetrics records the rate limiting of every exchange client. Upbit
+// limits each API key separately, so the token level is that of the key that
+// made the most recent call.
+var RateLimitMetrics = ratelimit.NewMetrics()
+
+// CallStats records the outcome and latency of every exchange client's calls
+var CallStats = callstats.NewRecorder()
+
+// Transport is shared by every exchange client, so users' clients reuse one
+// pool of connections and TLS sessions to Upbit
+var Transport = httppool.NewTransport(httppool.DefaultConfig())
+
+// Timeouts bound each exchange call, on top of the caller's context. Orders
+// get longer, since a placement cut short may still have reached Upbit.
+var Timeouts = httppool.Timeouts{
+	Default: 10 * time.Second,
+	Endpoints: map[string]time.Duration{
+		"POST /orders": 20 * time.Second,
+	},
+}
+
 // Client represents Upbit Exchange API client
 type Client struct {
 	accessKey   string
 	secretKey   string
+	baseURL     string
 	httpClient  *http.Client
 	rateLimiter *ratelimit.RateLimiter
 }
 
 // NewClient creates a new Exchange API client
 func NewClient(accessKey, secretKey string) *Client {
+	return NewClientWithBaseURL(accessKey, secretKey, DefaultBaseURL)
+}
+
+// NewClientWithBaseURL creates a new Exchange API client against a custom
+// endpoint, e.g. the fake Upbit server used in integration tests
+func NewClientWithBaseURL(accessKey, secretKey, baseURL string) *Client {
 	return &Client{
 		accessKey: accessKey,
 		secretKey: secretKey,
+		baseURL:   baseURL,
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Transport: Transport,
 		},
-		rateLimiter: ratelimit.NewRateLimiter(8), // Upbit allows 8 requests/sec for exchange API
+		rateLimiter: ratelimit.NewRateLimiter(8).WithMetrics(RateLimitMetrics), // Upbit allows 8 requests/sec for exchange API
 	}
 }
 
@@ -69,6 +100,39 @@ type OrderResponse struct {
 	Locked          string    `json:"locked"`
 	ExecutedVolume  string    `json:"executed_volume"`
 	TradesCount     int       `json:"trades_count"`
+	Trades          []Trade   `json:"trades,omitempty"` // Only populated by GetOrder
+	Identifier      string    `json:"identifier,omitempty"`
+}
+
+// Trade represents a single fill of an order
+type Trade struct {
+	Market    string    `json:"market"`
+	UUID      string    `json:"uuid"`
+	Price     string    `json:"price"`
+	Volume    string    `json:"volume"`
+	Funds     string    `json:"funds"`
+	Side      string    `json:"side"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Transfer represents a deposit or withdrawal
+type Transfer struct {
+	Type            string     `json:"type"` // deposit or withdraw
+	UUID            string     `json:"uuid"`
+	Currency        string     `json:"currency"`
+	TxID            string     `json:"txid"`
+	State           string     `json:"state"`
+	CreatedAt       time.Time  `json:"created_at"`
+	DoneAt          *time.Time `json:"done_at"`
+	Amount          string     `json:"amount"`
+	Fee             string     `json:"fee"`
+	TransactionType string     `json:"transaction_type"`
+}
+
+// APIKey represents one of the account's API keys
+type APIKey struct {
+	AccessKey string    `json:"access_key"`
+	ExpireAt  time.Time `json:"expire_at"`
 }
 
 // OrderRequest represents a request to place an order
@@ -78,6 +142,9 @@ type OrderRequest struct {
 	Volume *string `json:"volume,omitempty"`
 	Price  *string `json:"price,omitempty"`
 	OrdType string `json:"ord_type"`
+	// Identifier is the client's own unique ID for the order, which finds
+	// it again when placing it never got an answer
+	Identifier string `json:"identifier,omitempty"`
 }
 
 // GetAccounts retrieves all account balances
@@ -124,6 +191,9 @@ func (c *Client) PlaceOrder(ctx context.Context, req OrderRequest) (*OrderRespon
 	if req.Price != nil {
 		params["price"] = *req.Price
 	}
+	if req.Identifier != "" {
+		params["identifier"] = req.Identifier
+	}
 
 	token, err := c.generateToken(params)
 	if err != nil {
@@ -182,6 +252,39 @@ func (c *Client) GetOrder(ctx context.Context, orderUUID string) (*OrderResponse
 	return &orderResp, nil
 }
 
+// GetOrderByIdentifier retrieves the order placed with the given
+// identifier, so an order whose placement got no answer can be found
+func (c *Client) GetOrderByIdentifier(ctx context.Context, identifier string) (*OrderResponse, error) {
+	if err := c.rateLimiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	params := map[string]string{
+		"identifier": identifier,
+	}
+
+	token, err := c.generateToken(params)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Add("identifier", identifier)
+
+	resp, err := c.doRequest(ctx, "GET", "/order?"+query.Encode(), nil, token)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var orderResp OrderResponse
+	if err := json.NewDecoder(resp.Body).Decode(&orderResp); err != nil {
+		return nil, fmt.Errorf("failed to decode order response: %w", err)
+	}
+
+	return &orderResp, nil
+}
+
 // CancelOrder cancels an existing order
 func (c *Client) CancelOrder(ctx context.Context, orderUUID string) (*OrderResponse, error) {
 	if err := c.rateLimiter.Wait(ctx); err != nil {
@@ -248,6 +351,74 @@ func (c *Client) GetOrders(ctx context.Context, market string, state string) ([]
 	return orders, nil
 }
 
+// GetDeposits retrieves the most recent deposits of a currency
+func (c *Client) GetDeposits(ctx context.Context, currency string) ([]Transfer, error) {
+	return c.getTransfers(ctx, "/deposits", currency)
+}
+
+// GetWithdraws retrieves the most recent withdrawals of a currency
+func (c *Client) GetWithdraws(ctx context.Context, currency string) ([]Transfer, error) {
+	return c.getTransfers(ctx, "/withdraws", currency)
+}
+
+// getTransfers lists the transfers of a currency from a deposits or
+// withdrawals endpoint, newest first
+func (c *Client) getTransfers(ctx context.Context, path, currency string) ([]Transfer, error) {
+	if err := c.rateLimiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	params := map[string]string{
+		"currency": currency,
+	}
+
+	token, err := c.generateToken(params)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Add("currency", currency)
+
+	resp, err := c.doRequest(ctx, "GET", path+"?"+query.Encode(), nil, token)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var transfers []Transfer
+	if err := json.NewDecoder(resp.Body).Decode(&transfers); err != nil {
+		return nil, fmt.Errorf("failed to decode transfers: %w", err)
+	}
+
+	return transfers, nil
+}
+
+// GetAPIKeys retrieves the account's API keys with their expiry dates
+func (c *Client) GetAPIKeys(ctx context.Context) ([]APIKey, error) {
+	if err := c.rateLimiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	token, err := c.generateToken(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doRequest(ctx, "GET", "/api_keys", nil, token)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var keys []APIKey
+	if err := json.NewDecoder(resp.Body).Decode(&keys); err != nil {
+		return nil, fmt.Errorf("failed to decode API keys: %w", err)
+	}
+
+	return keys, nil
+}
+
 // generateToken generates JWT token for authentication
 func (c *Client) generateToken(params map[string]string) (string, error) {
 	claims := jwt.MapClaims{
@@ -281,7 +452,7 @@ func (c *Client) generateToken(params map[string]string) (string, error) {
 
 // doRequest performs HTTP request with authentication
 func (c *Client) doRequest(ctx context.Context, method, path string, body io.Reader, token string) (*http.Response, error) {
-	req, err := http.NewRequestWithContext(ctx, method, baseURL+path, body)
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -290,15 +461,19 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body io.Rea
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+token)
 
-	resp, err := c.httpClient.Do(req)
+	endpoint := callstats.Endpoint(method, path)
+	start := time.Now()
+	resp, err := Timeouts.Do(c.httpClient, req, endpoint)
 	if err != nil {
+		CallStats.Record(endpoint, 0, err, time.Since(start))
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
+	CallStats.Record(endpoint, resp.StatusCode, nil, time.Since(start))
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		bodyBytes, _ := io.ReadAll(resp.Body)
 		resp.Body.Close()
-		return nil, fmt.Errorf("API error: status=%d, body=%s", resp.StatusCode, string(bodyBytes))
+		return nil, newAPIError(resp.StatusCode, bodyBytes)
 	}
 
 	return resp, nil
@@ -331,7 +506,7 @@ func ConvertOrderResponseToModel(resp *OrderResponse, userID uuid.UUID) (*model.
 		Market:          resp.Market,
 		Side:            side,
 		Type:            orderType,
-		Status:          convertOrderStatus(resp.State),
+		Status:          ConvertOrderStatus(resp.State),
 		ExchangeOrderID: &resp.UUID,
 		CreatedAt:       resp.CreatedAt,
 		UpdatedAt:       time.Now(),
@@ -340,7 +515,8 @@ func ConvertOrderResponseToModel(resp *OrderResponse, userID uuid.UUID) (*model.
 	return order, nil
 }
 
-func convertOrderStatus(state string) model.OrderStatus {
+// ConvertOrderStatus maps an Upbit order state to the domain order status
+func ConvertOrderStatus(state string) model.OrderStatus {
 	switch state {
 	case "wait":
 		return model.OrderStatusSubmitted
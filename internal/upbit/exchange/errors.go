@@ -0,0 +1,71 @@
+package exchange
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// authErrorNames are the Upbit error names returned for rejected credentials
+var authErrorNames = map[string]bool{
+	"invalid_query_payload": true,
+	"jwt_verification":      true,
+	"expired_access_key":    true,
+	"nonce_used":            true,
+	"no_authorization_i_p":  true,
+	"out_of_scope":          true,
+	"invalid_access_key":    true,
+}
+
+// APIError is an error response from the Upbit API
+type APIError struct {
+	StatusCode int
+	Name       string // e.g. "invalid_access_key"; empty if the body wasn't an Upbit error
+	Message    string
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error: status=%d, body=%s", e.StatusCode, e.Body)
+}
+
+// IsAuth reports whether Upbit rejected the API key, e.g. because it was
+// revoked, expired or lacks a permission
+func (e *APIError) IsAuth() bool {
+	return e.StatusCode == http.StatusUnauthorized || authErrorNames[e.Name]
+}
+
+// IsTemporary reports whether the request may succeed when retried later
+func (e *APIError) IsTemporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// IsMaintenance reports whether Upbit is down for maintenance: it answers
+// 503 Service Unavailable, or its message mentions maintenance ("점검")
+func (e *APIError) IsMaintenance() bool {
+	return e.StatusCode == http.StatusServiceUnavailable || isMaintenanceMessage(e.Message)
+}
+
+// isMaintenanceMessage reports whether an Upbit error message announces
+// maintenance, e.g. "서버 점검 중입니다"
+func isMaintenanceMessage(message string) bool {
+	return strings.Contains(message, "점검") || strings.Contains(strings.ToLower(message), "maintenance")
+}
+
+// newAPIError parses an Upbit error body: {"error": {"name": ..., "message": ...}}
+func newAPIError(statusCode int, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: statusCode, Body: string(body)}
+
+	var payload struct {
+		Error struct {
+			Name    string `json:"name"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if json.Unmarshal(body, &payload) == nil {
+		apiErr.Name = payload.Error.Name
+		apiErr.Message = payload.Error.Message
+	}
+	return apiErr
+}
@@ -0,0 +1,18 @@
+package fake
+
+import (
+	"context"
+	"net/url"
+)
+
+type paramsKey struct{}
+
+func withParams(ctx context.Context, params url.Values) context.Context {
+	return context.WithValue(ctx, paramsKey{}, params)
+}
+
+// paramsFrom returns the request parameters parsed by the authentication step
+func paramsFrom(ctx context.Context) url.Values {
+	params, _ := ctx.Value(paramsKey{}).(url.Values)
+	return params
+}
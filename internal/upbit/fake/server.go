@@ -0,0 +1,585 @@
+// Package fake provides an in-process fake of the Upbit exchange API and
+// websocket feed for deterministic integration tests.
+package fake
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/exchange"
+)
+
+// FeeRate is the fee charged on every fill, matching Upbit's KRW market fee
+const FeeRate = 0.0005
+
+// FillMode controls how placed orders are filled
+type FillMode int
+
+const (
+	// FillImmediately fills orders completely as soon as they are placed
+	FillImmediately FillMode = iota
+	// FillManually leaves orders resting until Fill or Cancel is called
+	FillManually
+)
+
+// Server is a fake Upbit server
+type Server struct {
+	server    *httptest.Server
+	accessKey string
+	secretKey string
+	upgrader  websocket.Upgrader
+
+	mu       sync.Mutex
+	fillMode FillMode
+	prices   map[string]float64
+	accounts []exchange.Account
+	orders   map[string]*exchange.OrderResponse
+	orderSeq []string // order UUIDs in placement order
+	failures []apiError
+	dropNext bool // drop the response to the next placed order
+	subs     map[*websocket.Conn]*subscription
+	requests int
+}
+
+type apiError struct {
+	status  int
+	name    string
+	message string
+}
+
+type subscription struct {
+	mu    sync.Mutex // serializes writes to the connection
+	types map[string]map[string]bool
+}
+
+// NewServer starts a fake Upbit server accepting the given API credentials
+func NewServer(accessKey, secretKey string) *Server {
+	s := &Server{
+		accessKey: accessKey,
+		secretKey: secretKey,
+		prices:    make(map[string]float64),
+		orders:    make(map[string]*exchange.OrderResponse),
+		subs:      make(map[*websocket.Conn]*subscription),
+		// Upbit compresses frames for clients that negotiate it
+		upgrader: websocket.Upgrader{EnableCompression: true},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/accounts", s.authenticated(s.handleAccounts))
+	mux.HandleFunc("/v1/orders", s.authenticated(s.handleOrders))
+	mux.HandleFunc("/v1/order", s.authenticated(s.handleOrder))
+	mux.HandleFunc("/websocket/v1", s.handleWebSocket)
+
+	s.server = httptest.NewServer(mux)
+	return s
+}
+
+// URL returns the REST API base URL, suitable for exchange.NewClientWithBaseURL
+func (s *Server) URL() string {
+	return s.server.URL + "/v1"
+}
+
+// WebSocketURL returns the websocket endpoint, suitable for websocket.NewClientWithURL
+func (s *Server) WebSocketURL() string {
+	return "ws" + strings.TrimPrefix(s.server.URL, "http") + "/websocket/v1"
+}
+
+// Close shuts the server down
+func (s *Server) Close() {
+	s.mu.Lock()
+	for conn := range s.subs {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.server.Close()
+}
+
+// SetFillMode changes how subsequently placed orders are filled
+func (s *Server) SetFillMode(mode FillMode) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fillMode = mode
+}
+
+// SetPrice sets the price market orders fill at
+func (s *Server) SetPrice(market string, price float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prices[market] = price
+}
+
+// SetAccounts sets the balances returned by /accounts
+func (s *Server) SetAccounts(accounts []exchange.Account) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.accounts = accounts
+}
+
+// FailNext makes the next authenticated request fail with an Upbit style error
+func (s *Server) FailNext(status int, name, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, apiError{status: status, name: name, message: message})
+}
+
+// DropNextOrderResponse makes the server accept the next order but drop the
+// connection instead of responding, like a placement that times out after
+// reaching Upbit
+func (s *Server) DropNextOrderResponse() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dropNext = true
+}
+
+// Requests returns the number of authenticated requests served
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// Orders returns a snapshot of all orders in placement order
+func (s *Server) Orders() []exchange.OrderResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	orders := make([]exchange.OrderResponse, 0, len(s.orderSeq))
+	for _, id := range s.orderSeq {
+		orders = append(orders, *s.orders[id])
+	}
+	return orders
+}
+
+// Fill executes volume of a resting order at price. A price of zero fills at
+// the order's limit price or the market price set with SetPrice.
+func (s *Server) Fill(orderUUID string, volume, price float64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order, exists := s.orders[orderUUID]
+	if !exists {
+		return fmt.Errorf("order %s not found", orderUUID)
+	}
+	if order.State != "wait" {
+		return fmt.Errorf("order %s is %s", orderUUID, order.State)
+	}
+
+	if price == 0 {
+		price = s.fillPrice(order)
+	}
+	s.fill(order, volume, price)
+	return nil
+}
+
+// Cancel cancels a resting order as if the user cancelled it on Upbit
+func (s *Server) Cancel(orderUUID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order, exists := s.orders[orderUUID]
+	if !exists {
+		return fmt.Errorf("order %s not found", orderUUID)
+	}
+	order.State = "cancel"
+	return nil
+}
+
+// Publish sends a message to every websocket client subscribed to its type and code.
+// msg is marshalled as JSON and must carry "type" and "code" fields.
+func (s *Server) Publish(msgType, code string, msg interface{}) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	s.mu.Lock()
+	var targets []*websocket.Conn
+	for conn, sub := range s.subs {
+		sub.mu.Lock()
+		if sub.types[msgType][code] {
+			targets = append(targets, conn)
+		}
+		sub.mu.Unlock()
+	}
+	s.mu.Unlock()
+
+	for _, conn := range targets {
+		s.write(conn, data)
+	}
+	return nil
+}
+
+// Subscribers returns the number of websocket clients subscribed to a type and code
+func (s *Server) Subscribers(msgType, code string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := 0
+	for _, sub := range s.subs {
+		sub.mu.Lock()
+		if sub.types[msgType][code] {
+			count++
+		}
+		sub.mu.Unlock()
+	}
+	return count
+}
+
+func (s *Server) write(conn *websocket.Conn, data []byte) {
+	s.mu.Lock()
+	sub, exists := s.subs[conn]
+	s.mu.Unlock()
+	if !exists {
+		return
+	}
+
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	// Upbit sends JSON payloads as binary frames
+	conn.WriteMessage(websocket.BinaryMessage, data)
+}
+
+// authenticated verifies the Upbit JWT, including the query hash, and serves
+// scripted failures before delegating to next
+func (s *Server) authenticated(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params, err := requestParams(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_query_payload", err.Error())
+			return
+		}
+
+		if err := s.verifyToken(r, params); err != nil {
+			writeError(w, http.StatusUnauthorized, "invalid_access_key", err.Error())
+			return
+		}
+
+		s.mu.Lock()
+		s.requests++
+		var failure *apiError
+		if len(s.failures) > 0 {
+			failure = &s.failures[0]
+			s.failures = s.failures[1:]
+		}
+		s.mu.Unlock()
+
+		if failure != nil {
+			writeError(w, failure.status, failure.name, failure.message)
+			return
+		}
+
+		next(w, r.WithContext(withParams(r.Context(), params)))
+	}
+}
+
+func (s *Server) verifyToken(r *http.Request, params url.Values) error {
+	tokenString, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return fmt.Errorf("missing bearer token")
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(s.secretKey), nil
+	})
+	if err != nil {
+		return fmt.Errorf("invalid token: %w", err)
+	}
+
+	if claims["access_key"] != s.accessKey {
+		return fmt.Errorf("unknown access key")
+	}
+
+	if len(params) > 0 {
+		hash := sha512.Sum512([]byte(params.Encode()))
+		if claims["query_hash"] != hex.EncodeToString(hash[:]) {
+			return fmt.Errorf("query hash mismatch")
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) handleAccounts(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	accounts := append([]exchange.Account{}, s.accounts...)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, accounts)
+}
+
+func (s *Server) handleOrders(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		s.placeOrder(w, r)
+	case http.MethodGet:
+		s.listOrders(w, r)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleOrder(w http.ResponseWriter, r *http.Request) {
+	params := paramsFrom(r.Context())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order, exists := s.orders[params.Get("uuid")]
+	if identifier := params.Get("identifier"); identifier != "" {
+		order, exists = s.findByIdentifier(identifier)
+	}
+	if !exists {
+		writeError(w, http.StatusNotFound, "order_not_found", "주문을 찾지 못했습니다.")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, order)
+	case http.MethodDelete:
+		if order.State != "wait" {
+			writeError(w, http.StatusBadRequest, "order_not_found", "이미 체결되었거나 취소된 주문입니다.")
+			return
+		}
+		order.State = "cancel"
+		writeJSON(w, http.StatusOK, order)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) placeOrder(w http.ResponseWriter, r *http.Request) {
+	params := paramsFrom(r.Context())
+
+	order := &exchange.OrderResponse{
+		UUID:       uuid.New().String(),
+		Side:       params.Get("side"),
+		OrdType:    params.Get("ord_type"),
+		State:      "wait",
+		Market:     params.Get("market"),
+		CreatedAt:  time.Now(),
+		PaidFee:    "0",
+		Locked:     "0",
+		Identifier: params.Get("identifier"),
+	}
+	if v := params.Get("price"); v != "" {
+		order.Price = &v
+	}
+	if v := params.Get("volume"); v != "" {
+		order.Volume = &v
+		order.RemainingVolume = &v
+	}
+	order.ExecutedVolume = "0"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	price := s.fillPrice(order)
+	if order.OrdType == "price" {
+		// Market buy: convert the KRW amount to a volume at the current price
+		if price == 0 {
+			writeError(w, http.StatusBadRequest, "invalid_price", "no market price set")
+			return
+		}
+		volume := formatDecimal(parseDecimal(*order.Price) / price)
+		order.Volume = &volume
+		order.RemainingVolume = &volume
+	}
+	if order.Volume == nil {
+		writeError(w, http.StatusBadRequest, "invalid_volume", "volume is required")
+		return
+	}
+
+	s.orders[order.UUID] = order
+	s.orderSeq = append(s.orderSeq, order.UUID)
+	if s.dropNext {
+		s.dropNext = false
+		panic(http.ErrAbortHandler)
+	}
+
+	// Respond with the state at placement time, like Upbit does
+	placed := *order
+	if s.fillMode == FillImmediately && price > 0 {
+		s.fill(order, parseDecimal(*order.Volume), price)
+	}
+
+	writeJSON(w, http.StatusCreated, placed)
+}
+
+// findByIdentifier returns the order placed with identifier. Callers must
+// hold s.mu.
+func (s *Server) findByIdentifier(identifier string) (*exchange.OrderResponse, bool) {
+	for _, order := range s.orders {
+		if order.Identifier == identifier {
+			return order, true
+		}
+	}
+	return nil, false
+}
+
+func (s *Server) listOrders(w http.ResponseWriter, r *http.Request) {
+	params := paramsFrom(r.Context())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	orders := []exchange.OrderResponse{}
+	for _, id := range s.orderSeq {
+		order := s.orders[id]
+		if market := params.Get("market"); market != "" && order.Market != market {
+			continue
+		}
+		if state := params.Get("state"); state != "" && order.State != state {
+			continue
+		}
+		orders = append(orders, *order)
+	}
+
+	writeJSON(w, http.StatusOK, orders)
+}
+
+// fillPrice returns the limit price of an order or the current market price
+func (s *Server) fillPrice(order *exchange.OrderResponse) float64 {
+	if order.OrdType == "limit" && order.Price != nil {
+		return parseDecimal(*order.Price)
+	}
+	return s.prices[order.Market]
+}
+
+// fill applies an execution to an order. Callers must hold s.mu.
+func (s *Server) fill(order *exchange.OrderResponse, volume, price float64) {
+	remaining := parseDecimal(*order.RemainingVolume)
+	if volume > remaining {
+		volume = remaining
+	}
+
+	funds := volume * price
+	remaining -= volume
+	executed := parseDecimal(order.ExecutedVolume) + volume
+	paidFee := parseDecimal(order.PaidFee) + funds*FeeRate
+
+	remainingStr := formatDecimal(remaining)
+	order.RemainingVolume = &remainingStr
+	order.ExecutedVolume = formatDecimal(executed)
+	order.PaidFee = formatDecimal(paidFee)
+	order.Trades = append(order.Trades, exchange.Trade{
+		Market:    order.Market,
+		UUID:      uuid.New().String(),
+		Price:     formatDecimal(price),
+		Volume:    formatDecimal(volume),
+		Funds:     formatDecimal(funds),
+		Side:      order.Side,
+		CreatedAt: time.Now(),
+	})
+	order.TradesCount = len(order.Trades)
+
+	if remaining <= 0 {
+		order.State = "done"
+	}
+}
+
+func (s *Server) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	sub := &subscription{types: make(map[string]map[string]bool)}
+	s.mu.Lock()
+	s.subs[conn] = sub
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.subs, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		// Subscription requests are arrays of a ticket followed by type fields
+		var fields []map[string]interface{}
+		if err := json.Unmarshal(data, &fields); err != nil {
+			continue
+		}
+
+		sub.mu.Lock()
+		for _, field := range fields {
+			msgType, _ := field["type"].(string)
+			codes, _ := field["codes"].([]interface{})
+			if msgType == "" {
+				continue
+			}
+			if sub.types[msgType] == nil {
+				sub.types[msgType] = make(map[string]bool)
+			}
+			for _, code := range codes {
+				if c, ok := code.(string); ok {
+					sub.types[msgType][c] = true
+				}
+			}
+		}
+		sub.mu.Unlock()
+	}
+}
+
+// requestParams extracts the parameters covered by the query hash
+func requestParams(r *http.Request) (url.Values, error) {
+	if r.Method != http.MethodPost {
+		return r.URL.Query(), nil
+	}
+
+	var body map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("invalid body: %w", err)
+	}
+
+	params := url.Values{}
+	for k, v := range body {
+		params.Add(k, v)
+	}
+	return params, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, name, message string) {
+	writeJSON(w, status, map[string]interface{}{
+		"error": map[string]string{"name": name, "message": message},
+	})
+}
+
+func parseDecimal(s string) float64 {
+	v, _ := strconv.ParseFloat(s, 64)
+	return v
+}
+
+func formatDecimal(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,204 @@
+package fake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/exchange"
+	upbitws "github.com/sungminna/upbit-trading-platform/internal/upbit/websocket"
+)
+
+const (
+	testAccessKey = "access"
+	testSecretKey = "secret"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestServer_PlaceOrderFillsImmediately(t *testing.T) {
+	server := NewServer(testAccessKey, testSecretKey)
+	defer server.Close()
+
+	client := exchange.NewClientWithBaseURL(testAccessKey, testSecretKey, server.URL())
+	ctx := context.Background()
+
+	placed, err := client.PlaceOrder(ctx, exchange.OrderRequest{
+		Market:  "KRW-BTC",
+		Side:    "bid",
+		OrdType: "limit",
+		Volume:  strPtr("0.01"),
+		Price:   strPtr("100000000"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "wait", placed.State)
+
+	order, err := client.GetOrder(ctx, placed.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, "done", order.State)
+	assert.Equal(t, "0.01", order.ExecutedVolume)
+	assert.Equal(t, "500", order.PaidFee)
+	require.Len(t, order.Trades, 1)
+	assert.Equal(t, "1000000", order.Trades[0].Funds)
+}
+
+func TestServer_ManualPartialFillsAndCancel(t *testing.T) {
+	server := NewServer(testAccessKey, testSecretKey)
+	defer server.Close()
+	server.SetFillMode(FillManually)
+	server.SetPrice("KRW-BTC", 100000000)
+
+	client := exchange.NewClientWithBaseURL(testAccessKey, testSecretKey, server.URL())
+	ctx := context.Background()
+
+	placed, err := client.PlaceOrder(ctx, exchange.OrderRequest{
+		Market:  "KRW-BTC",
+		Side:    "ask",
+		OrdType: "market",
+		Volume:  strPtr("0.01"),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, server.Fill(placed.UUID, 0.004, 0))
+
+	order, err := client.GetOrder(ctx, placed.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, "wait", order.State)
+	assert.Equal(t, "0.004", order.ExecutedVolume)
+
+	open, err := client.GetOrders(ctx, "KRW-BTC", "wait")
+	require.NoError(t, err)
+	assert.Len(t, open, 1)
+
+	cancelled, err := client.CancelOrder(ctx, placed.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, "cancel", cancelled.State)
+	assert.Error(t, server.Fill(placed.UUID, 0.006, 0))
+}
+
+func TestServer_MarketBuyUsesFunds(t *testing.T) {
+	server := NewServer(testAccessKey, testSecretKey)
+	defer server.Close()
+	server.SetPrice("KRW-BTC", 100000000)
+
+	client := exchange.NewClientWithBaseURL(testAccessKey, testSecretKey, server.URL())
+	ctx := context.Background()
+
+	placed, err := client.PlaceOrder(ctx, exchange.OrderRequest{
+		Market:  "KRW-BTC",
+		Side:    "bid",
+		OrdType: "price",
+		Price:   strPtr("1000000"),
+	})
+	require.NoError(t, err)
+
+	order, err := client.GetOrder(ctx, placed.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, "done", order.State)
+	assert.Equal(t, "0.01", order.ExecutedVolume)
+}
+
+func TestServer_Authentication(t *testing.T) {
+	server := NewServer(testAccessKey, testSecretKey)
+	defer server.Close()
+
+	client := exchange.NewClientWithBaseURL(testAccessKey, "wrong-secret", server.URL())
+	_, err := client.GetAccounts(context.Background())
+	assert.ErrorContains(t, err, "status=401")
+}
+
+func TestServer_FailNext(t *testing.T) {
+	server := NewServer(testAccessKey, testSecretKey)
+	defer server.Close()
+	server.SetAccounts([]exchange.Account{{Currency: "KRW", Balance: "1000000", Locked: "0"}})
+
+	client := exchange.NewClientWithBaseURL(testAccessKey, testSecretKey, server.URL())
+	ctx := context.Background()
+
+	server.FailNext(429, "too_many_requests", "rate limited")
+	_, err := client.GetAccounts(ctx)
+	assert.ErrorContains(t, err, "status=429")
+
+	accounts, err := client.GetAccounts(ctx)
+	require.NoError(t, err)
+	require.Len(t, accounts, 1)
+	assert.Equal(t, "1000000", accounts[0].Balance)
+}
+
+func TestServer_WebSocketFeed(t *testing.T) {
+	server := NewServer(testAccessKey, testSecretKey)
+	defer server.Close()
+
+	client := upbitws.NewClientWithURL(server.WebSocketURL())
+	require.NoError(t, client.Connect())
+	defer client.Close()
+
+	received := make(chan upbitws.TickerMessage, 1)
+	client.OnTicker(func(msg interface{}) error {
+		received <- msg.(upbitws.TickerMessage)
+		return nil
+	})
+	require.NoError(t, client.Subscribe(upbitws.MessageTypeTicker, []string{"KRW-BTC"}))
+
+	require.Eventually(t, func() bool {
+		return server.Subscribers("ticker", "KRW-BTC") == 1
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, server.Publish("ticker", "KRW-BTC", upbitws.TickerMessage{
+		Type:       "ticker",
+		Code:       "KRW-BTC",
+		TradePrice: 100000000,
+	}))
+
+	select {
+	case msg := <-received:
+		assert.Equal(t, 100000000.0, msg.TradePrice)
+	case <-time.After(time.Second):
+		t.Fatal("ticker message not received")
+	}
+}
+
+func TestServer_WebSocketSimpleFormat(t *testing.T) {
+	server := NewServer(testAccessKey, testSecretKey)
+	defer server.Close()
+
+	client := upbitws.NewClientWithURL(server.WebSocketURL()).WithFormat(upbitws.FormatSimple)
+	require.NoError(t, client.Connect())
+	defer client.Close()
+
+	received := make(chan upbitws.TradeMessage, 1)
+	client.OnTrade(func(msg interface{}) error {
+		received <- msg.(upbitws.TradeMessage)
+		return nil
+	})
+	require.NoError(t, client.Subscribe(upbitws.MessageTypeTrade, []string{"KRW-BTC"}))
+
+	require.Eventually(t, func() bool {
+		return server.Subscribers("trade", "KRW-BTC") == 1
+	}, time.Second, 10*time.Millisecond)
+
+	// A trade as Upbit sends it in the SIMPLE format
+	require.NoError(t, server.Publish("trade", "KRW-BTC", map[string]interface{}{
+		"ty": "trade", "cd": "KRW-BTC", "tp": 100000000.0, "tv": 0.0021, "ab": "BID",
+		"pcp": 99000000.0, "c": "RISE", "cp": 1000000.0, "td": "2025-01-02", "ttm": "03:04:05",
+		"ttms": 1735787045000, "tms": 1735787045123, "sid": 17357870450000000, "st": "REALTIME",
+	}))
+
+	select {
+	case msg := <-received:
+		assert.Equal(t, "trade", msg.Type)
+		assert.Equal(t, "KRW-BTC", msg.Code)
+		assert.Equal(t, 100000000.0, msg.TradePrice)
+		assert.Equal(t, 0.0021, msg.TradeVolume)
+		assert.Equal(t, "BID", msg.AskBid)
+		assert.Equal(t, "2025-01-02", msg.TradeDate)
+		assert.Equal(t, int64(17357870450000000), msg.SequentialID)
+		assert.Equal(t, "REALTIME", msg.StreamType)
+	case <-time.After(time.Second):
+		t.Fatal("trade message not received")
+	}
+}
@@ -0,0 +1,93 @@
+package paper
+
+import (
+	"context"
+	"time"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/gateway"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/exchange"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/sim"
+)
+
+var (
+	_ gateway.ExchangeAPI    = (*Client)(nil)
+	_ gateway.OrderLookupAPI = (*Client)(nil)
+)
+
+// Client is a paper API key's view of its paper account
+type Client struct {
+	exchange  *Exchange
+	accessKey string
+}
+
+// GetAccounts returns the account's balances, quote currency first
+func (c *Client) GetAccounts(ctx context.Context) ([]exchange.Account, error) {
+	var balances []exchange.Account
+	err := c.exchange.do(ctx, c.accessKey, func(account *sim.Account, now time.Time) error {
+		balances = account.Balances()
+		return nil
+	})
+	return balances, err
+}
+
+// PlaceOrder reserves the order's funds and matches it against the market's
+// live orderbook. Limit orders rest until the book crosses their price;
+// whatever part of a market order the book can't fill is cancelled.
+func (c *Client) PlaceOrder(ctx context.Context, req exchange.OrderRequest) (*exchange.OrderResponse, error) {
+	book, err := c.exchange.book(ctx, req.Market)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp *exchange.OrderResponse
+	err = c.exchange.do(ctx, c.accessKey, func(account *sim.Account, now time.Time) error {
+		var err error
+		resp, err = account.Place(req, book, now)
+		return err
+	})
+	return resp, err
+}
+
+// GetOrder returns one of the account's orders with its trades
+func (c *Client) GetOrder(ctx context.Context, orderUUID string) (*exchange.OrderResponse, error) {
+	var resp *exchange.OrderResponse
+	err := c.exchange.do(ctx, c.accessKey, func(account *sim.Account, now time.Time) error {
+		var err error
+		resp, err = account.Order(orderUUID)
+		return err
+	})
+	return resp, err
+}
+
+// GetOrderByIdentifier returns the account's order placed with identifier
+func (c *Client) GetOrderByIdentifier(ctx context.Context, identifier string) (*exchange.OrderResponse, error) {
+	var resp *exchange.OrderResponse
+	err := c.exchange.do(ctx, c.accessKey, func(account *sim.Account, now time.Time) error {
+		var err error
+		resp, err = account.OrderByIdentifier(identifier)
+		return err
+	})
+	return resp, err
+}
+
+// CancelOrder cancels one of the account's resting orders
+func (c *Client) CancelOrder(ctx context.Context, orderUUID string) (*exchange.OrderResponse, error) {
+	var resp *exchange.OrderResponse
+	err := c.exchange.do(ctx, c.accessKey, func(account *sim.Account, now time.Time) error {
+		var err error
+		resp, err = account.Cancel(orderUUID)
+		return err
+	})
+	return resp, err
+}
+
+// GetOrders returns the account's orders in a market and state, either of
+// which may be empty to match all
+func (c *Client) GetOrders(ctx context.Context, market string, state string) ([]exchange.OrderResponse, error) {
+	var orders []exchange.OrderResponse
+	err := c.exchange.do(ctx, c.accessKey, func(account *sim.Account, now time.Time) error {
+		orders = account.List(market, state)
+		return nil
+	})
+	return orders, err
+}
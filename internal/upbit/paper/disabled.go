@@ -0,0 +1,45 @@
+package paper
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/gateway"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/exchange"
+)
+
+var _ gateway.ExchangeAPI = liveDisabled{}
+
+// NoLive is an ExchangeClientFactory for deployments that must never reach
+// Upbit with real funds: its clients reject every call. Pass it to Factory so
+// only paper keys trade.
+func NoLive(accessKey, secretKey string) gateway.ExchangeAPI {
+	return liveDisabled{}
+}
+
+// liveDisabled is a live API key's client where live trading is disabled
+type liveDisabled struct{}
+
+func (liveDisabled) err() error {
+	return apiError(http.StatusForbidden, "live_trading_disabled", "live trading is disabled on this server; use a paper API key")
+}
+
+func (d liveDisabled) GetAccounts(ctx context.Context) ([]exchange.Account, error) {
+	return nil, d.err()
+}
+
+func (d liveDisabled) PlaceOrder(ctx context.Context, req exchange.OrderRequest) (*exchange.OrderResponse, error) {
+	return nil, d.err()
+}
+
+func (d liveDisabled) GetOrder(ctx context.Context, orderUUID string) (*exchange.OrderResponse, error) {
+	return nil, d.err()
+}
+
+func (d liveDisabled) CancelOrder(ctx context.Context, orderUUID string) (*exchange.OrderResponse, error) {
+	return nil, d.err()
+}
+
+func (d liveDisabled) GetOrders(ctx context.Context, market string, state string) ([]exchange.OrderResponse, error) {
+	return nil, d.err()
+}
@@ -0,0 +1,257 @@
+// Package paper trades users' paper accounts: simulated exchange accounts
+// holding virtual funds, persisted with the platform's other data. Orders
+// are matched against Upbit's live orderbooks, so paper trades fill like
+// real ones would have, without risking real funds.
+package paper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/gateway"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/repository"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/exchange"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/sim"
+	"github.com/sungminna/upbit-trading-platform/pkg/cache"
+)
+
+const (
+	// KeyPrefix marks the access keys of paper API keys
+	KeyPrefix = "paper-"
+	// DefaultBalance is the virtual KRW paper accounts open with by default
+	DefaultBalance = sim.DefaultBalance
+	// Retention is how long ended orders are kept in a paper account
+	Retention = 7 * 24 * time.Hour
+	// bookTTL is how long a fetched orderbook is reused for matching
+	bookTTL = time.Second
+	// lockTTL bounds how long an account stays locked by a crashed instance
+	lockTTL = 10 * time.Second
+	// lockWait is how long a request waits for others on the same account
+	lockWait = 5 * time.Second
+	// lockRetry is how often a waiting request retries the account's lock
+	lockRetry = 20 * time.Millisecond
+)
+
+// IsPaperKey reports whether an access key is a paper API key's
+func IsPaperKey(accessKey string) bool {
+	return strings.HasPrefix(accessKey, KeyPrefix)
+}
+
+// NewAccessKey generates the access key of a new paper API key
+func NewAccessKey() string {
+	return KeyPrefix + uuid.New().String()
+}
+
+// NewState returns the state of a paper account holding balance KRW
+func NewState(balance float64) (json.RawMessage, error) {
+	return json.Marshal(sim.NewAccount(balance))
+}
+
+// BookSource provides live orderbooks; gateway.QuotationAPI satisfies it
+type BookSource interface {
+	GetOrderbook(ctx context.Context, market string) (*model.Orderbook, error)
+}
+
+type cachedBook struct {
+	book      *model.Orderbook
+	fetchedAt time.Time
+}
+
+// Exchange trades paper accounts. Requests on the same account are
+// serialized across instances with a lock, as each one rewrites the
+// account's state.
+type Exchange struct {
+	accounts repository.PaperAccountRepository
+	books    BookSource
+	locker   cache.Locker
+	now      func() time.Time
+
+	mu     sync.Mutex
+	cached map[string]cachedBook // By market
+}
+
+// NewExchange creates a paper exchange matching orders against books
+func NewExchange(accounts repository.PaperAccountRepository, books BookSource, locker cache.Locker) *Exchange {
+	return &Exchange{
+		accounts: accounts,
+		books:    books,
+		locker:   locker,
+		now:      time.Now,
+		cached:   make(map[string]cachedBook),
+	}
+}
+
+// Factory returns an ExchangeClientFactory trading paper API keys on the
+// exchange and any other key with live
+func (e *Exchange) Factory(live gateway.ExchangeClientFactory) gateway.ExchangeClientFactory {
+	return func(accessKey, secretKey string) gateway.ExchangeAPI {
+		if IsPaperKey(accessKey) {
+			return e.NewClient(accessKey, secretKey)
+		}
+		return live(accessKey, secretKey)
+	}
+}
+
+// NewClient is an ExchangeClientFactory trading the paper account of a
+// paper API key
+func (e *Exchange) NewClient(accessKey, secretKey string) gateway.ExchangeAPI {
+	return &Client{exchange: e, accessKey: accessKey}
+}
+
+// Reset empties a paper account back to its starting balance, cancelling
+// its orders
+func (e *Exchange) Reset(ctx context.Context, account *model.PaperAccount) error {
+	lock, err := e.lock(ctx, account.AccessKey)
+	if err != nil {
+		return err
+	}
+	defer lock.Release(context.WithoutCancel(ctx))
+
+	state, err := NewState(account.StartingBalance)
+	if err != nil {
+		return fmt.Errorf("failed to reset paper account: %w", err)
+	}
+	now := e.now()
+	account.State = state
+	account.ResetAt = &now
+	account.UpdatedAt = now
+	return e.accounts.Update(ctx, account)
+}
+
+// Balance returns the KRW held by a paper account, available or reserved
+// by open orders
+func Balance(account *model.PaperAccount) (float64, error) {
+	var state sim.Account
+	if err := json.Unmarshal(account.State, &state); err != nil {
+		return 0, fmt.Errorf("invalid paper account state: %w", err)
+	}
+	h, ok := state.Holdings["KRW"]
+	if !ok {
+		return 0, nil
+	}
+	return h.Balance + h.Locked, nil
+}
+
+// do runs op on the paper account of an access key, after matching its
+// resting orders against the current orderbooks, and saves the account if
+// it changed
+func (e *Exchange) do(ctx context.Context, accessKey string, op func(account *sim.Account, now time.Time) error) error {
+	lock, err := e.lock(ctx, accessKey)
+	if err != nil {
+		return err
+	}
+	defer lock.Release(context.WithoutCancel(ctx))
+
+	paperAccount, err := e.accounts.GetByAccessKey(ctx, accessKey)
+	if errors.Is(err, repository.ErrNotFound) {
+		return apiError(http.StatusUnauthorized, "invalid_access_key", "잘못된 엑세스 키입니다.")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load paper account: %w", err)
+	}
+	var account sim.Account
+	if err := json.Unmarshal(paperAccount.State, &account); err != nil {
+		return fmt.Errorf("invalid paper account state: %w", err)
+	}
+
+	now := e.now()
+	books := make(map[string]*model.Orderbook)
+	for _, market := range account.WaitingMarkets() {
+		// A market whose book can't be fetched is matched on a later request
+		if book, err := e.book(ctx, market); err == nil {
+			books[market] = book
+		}
+	}
+	account.MatchWaiting(func(market string) (*model.Orderbook, bool) {
+		book, ok := books[market]
+		return book, ok
+	}, now)
+
+	opErr := op(&account, now)
+	account.Prune(now.Add(-Retention))
+
+	state, err := json.Marshal(&account)
+	if err != nil {
+		return fmt.Errorf("failed to save paper account: %w", err)
+	}
+	if !jsonEqual(state, paperAccount.State) {
+		paperAccount.State = state
+		paperAccount.UpdatedAt = now
+		if err := e.accounts.Update(ctx, paperAccount); err != nil {
+			return fmt.Errorf("failed to save paper account: %w", err)
+		}
+	}
+	return opErr
+}
+
+// lock obtains the lock on a paper account, waiting up to lockWait for
+// other requests on it to finish. Callers that give up see Upbit's rate
+// limit error, so they retry like they would on Upbit.
+func (e *Exchange) lock(ctx context.Context, accessKey string) (cache.Lock, error) {
+	deadline := time.Now().Add(lockWait)
+	for {
+		lock, err := e.locker.Obtain(ctx, "paper-account:"+accessKey, lockTTL)
+		if !errors.Is(err, cache.ErrLockNotObtained) {
+			return lock, err
+		}
+		if time.Now().After(deadline) {
+			return nil, apiError(http.StatusTooManyRequests, "too_many_requests", "paper account is busy")
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(lockRetry):
+		}
+	}
+}
+
+// book returns a market's live orderbook, reusing books fetched in the last
+// bookTTL
+func (e *Exchange) book(ctx context.Context, market string) (*model.Orderbook, error) {
+	e.mu.Lock()
+	cached, ok := e.cached[market]
+	e.mu.Unlock()
+	if ok && e.now().Sub(cached.fetchedAt) < bookTTL {
+		return cached.book, nil
+	}
+
+	book, err := e.books.GetOrderbook(ctx, market)
+	if err != nil {
+		return nil, err
+	}
+	if len(book.OrderbookUnits) == 0 {
+		return nil, apiError(http.StatusNotFound, "market_does_not_exist", "no orderbook for "+market)
+	}
+
+	e.mu.Lock()
+	e.cached[market] = cachedBook{book: book, fetchedAt: e.now()}
+	e.mu.Unlock()
+	return book, nil
+}
+
+// jsonEqual reports whether two JSON documents are equal, ignoring how they
+// were formatted, e.g. by a JSONB column
+func jsonEqual(a, b []byte) bool {
+	var x, y any
+	if json.Unmarshal(a, &x) != nil || json.Unmarshal(b, &y) != nil {
+		return false
+	}
+	ja, _ := json.Marshal(x)
+	jb, _ := json.Marshal(y)
+	return string(ja) == string(jb)
+}
+
+// apiError builds the error the real client returns for an Upbit error
+// response
+func apiError(status int, name, message string) *exchange.APIError {
+	body, _ := json.Marshal(map[string]any{"error": map[string]string{"name": name, "message": message}})
+	return &exchange.APIError{StatusCode: status, Name: name, Message: message, Body: string(body)}
+}
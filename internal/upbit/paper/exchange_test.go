@@ -0,0 +1,161 @@
+package paper
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/gateway"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/infrastructure/memory"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/exchange"
+	"github.com/sungminna/upbit-trading-platform/pkg/cache"
+)
+
+// stubBooks serves a one-level book per market around a price the test moves
+type stubBooks struct {
+	prices  map[string]float64
+	fetches int
+	stamp   int64
+}
+
+func (s *stubBooks) GetOrderbook(ctx context.Context, market string) (*model.Orderbook, error) {
+	s.fetches++
+	s.stamp++
+	price := s.prices[market]
+	return &model.Orderbook{
+		Market:    market,
+		Timestamp: s.stamp,
+		OrderbookUnits: []model.OrderbookUnit{
+			{AskPrice: price + 1000, BidPrice: price - 1000, AskSize: 10, BidSize: 10},
+		},
+	}, nil
+}
+
+func newTestExchange(t *testing.T) (*Exchange, *stubBooks, *model.PaperAccount, *time.Time) {
+	t.Helper()
+	store := memory.NewStore()
+	books := &stubBooks{prices: map[string]float64{"KRW-BTC": 100_000_000}}
+	e := NewExchange(store.PaperAccounts(), books, cache.NewMemoryCache())
+	clock := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	e.now = func() time.Time { return clock }
+
+	state, err := NewState(1_000_000)
+	require.NoError(t, err)
+	account := &model.PaperAccount{
+		ID:              uuid.New(),
+		UserID:          uuid.New(),
+		AccessKey:       NewAccessKey(),
+		StartingBalance: 1_000_000,
+		State:           state,
+		CreatedAt:       clock,
+		UpdatedAt:       clock,
+	}
+	require.NoError(t, store.PaperAccounts().Create(context.Background(), account))
+	return e, books, account, &clock
+}
+
+func ptr(s string) *string { return &s }
+
+func TestClient_RestingOrderFillsWhenTheBookMoves(t *testing.T) {
+	e, books, account, clock := newTestExchange(t)
+	ctx := context.Background()
+
+	placed, err := e.NewClient(account.AccessKey, "").PlaceOrder(ctx, exchange.OrderRequest{
+		Market: "KRW-BTC", Side: "bid", OrdType: "limit", Price: ptr("95000000"), Volume: ptr("0.01"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "wait", placed.State)
+
+	// A new client sees the persisted order; the book hasn't crossed yet
+	client := e.NewClient(account.AccessKey, "")
+	order, err := client.GetOrder(ctx, placed.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, "wait", order.State)
+
+	balances, err := client.GetAccounts(ctx)
+	require.NoError(t, err)
+	require.Len(t, balances, 1)
+	assert.Equal(t, "KRW", balances[0].Currency)
+	assert.Equal(t, "49525", balances[0].Balance)
+
+	books.prices["KRW-BTC"] = 93_000_000
+	*clock = clock.Add(2 * time.Second)
+	order, err = client.GetOrder(ctx, placed.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, "done", order.State)
+	require.Len(t, order.Trades, 1)
+	assert.Equal(t, "93001000", order.Trades[0].Price)
+
+	balances, err = client.GetAccounts(ctx)
+	require.NoError(t, err)
+	require.Len(t, balances, 2)
+	assert.Equal(t, "BTC", balances[1].Currency)
+	assert.Equal(t, "0.01", balances[1].Balance)
+}
+
+func TestClient_ReusesRecentBooks(t *testing.T) {
+	e, books, account, _ := newTestExchange(t)
+	client := e.NewClient(account.AccessKey, "")
+	ctx := context.Background()
+
+	for range 3 {
+		_, err := client.PlaceOrder(ctx, exchange.OrderRequest{
+			Market: "KRW-BTC", Side: "bid", OrdType: "limit", Price: ptr("90000000"), Volume: ptr("0.001"),
+		})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, books.fetches)
+}
+
+func TestExchange_Reset(t *testing.T) {
+	e, _, account, _ := newTestExchange(t)
+	ctx := context.Background()
+
+	_, err := e.NewClient(account.AccessKey, "").PlaceOrder(ctx, exchange.OrderRequest{
+		Market: "KRW-BTC", Side: "bid", OrdType: "price", Price: ptr("500000"),
+	})
+	require.NoError(t, err)
+
+	saved, err := e.accounts.GetByAccessKey(ctx, account.AccessKey)
+	require.NoError(t, err)
+	balance, err := Balance(saved)
+	require.NoError(t, err)
+	assert.Less(t, balance, 1_000_000.0)
+
+	require.NoError(t, e.Reset(ctx, saved))
+	saved, err = e.accounts.GetByAccessKey(ctx, account.AccessKey)
+	require.NoError(t, err)
+	balance, err = Balance(saved)
+	require.NoError(t, err)
+	assert.Equal(t, 1_000_000.0, balance)
+	assert.NotNil(t, saved.ResetAt)
+
+	orders, err := e.NewClient(account.AccessKey, "").GetOrders(ctx, "", "")
+	require.NoError(t, err)
+	assert.Empty(t, orders)
+}
+
+func TestExchange_Factory(t *testing.T) {
+	e, _, account, _ := newTestExchange(t)
+	var live []string
+	factory := e.Factory(func(accessKey, secretKey string) gateway.ExchangeAPI {
+		live = append(live, accessKey)
+		return nil
+	})
+
+	_, ok := factory(account.AccessKey, "").(*Client)
+	assert.True(t, ok)
+	factory("upbit-key", "secret")
+	assert.Equal(t, []string{"upbit-key"}, live)
+
+	_, err := factory(KeyPrefix+"unknown", "").GetAccounts(context.Background())
+	var apiErr *exchange.APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+}
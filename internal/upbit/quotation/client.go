@@ -10,6 +10,8 @@ import (
 	"time"
 
 	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/pkg/callstats"
+	"github.com/sungminna/upbit-trading-platform/pkg/httppool"
 	"github.com/sungminna/upbit-trading-platform/pkg/ratelimit"
 )
 
@@ -17,6 +19,18 @@ const (
 	baseURL = "https://api.upbit.com/v1"
 )
 
+// RateLimitMetrics records the rate limiting of quotation API calls
+var RateLimitMetrics = ratelimit.NewMetrics()
+
+// CallStats records the outcome and latency of quotation API calls
+var CallStats = callstats.NewRecorder()
+
+// Transport is shared by every quotation client
+var Transport = httppool.NewTransport(httppool.DefaultConfig())
+
+// Timeouts bound each quotation call, on top of the caller's context
+var Timeouts = httppool.Timeouts{Default: 10 * time.Second}
+
 // Client represents Upbit Quotation API client
 type Client struct {
 	httpClient  *http.Client
@@ -27,9 +41,9 @@ type Client struct {
 func NewClient() *Client {
 	return &Client{
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
+			Transport: Transport,
 		},
-		rateLimiter: ratelimit.NewRateLimiter(30), // Upbit allows 30 requests/sec for quotation API
+		rateLimiter: ratelimit.NewRateLimiter(30).WithMetrics(RateLimitMetrics), // Upbit allows 30 requests/sec for quotation API
 	}
 }
 
@@ -187,6 +201,73 @@ func (c *Client) GetCandleRange(ctx context.Context, market string, interval mod
 	return allCandles, nil
 }
 
+// StreamCandleRange calls fn with the candles starting in [from, to), oldest
+// first, a page of at most 200 at a time, so long ranges are never held in
+// memory at once. It stops at the first error fn returns.
+func (c *Client) StreamCandleRange(ctx context.Context, market string, interval model.CandleInterval, from, to time.Time, fn func([]model.Candle) error) error {
+	d := interval.Duration()
+	if d == 0 {
+		return fmt.Errorf("unsupported candle interval %q", interval)
+	}
+
+	const maxCount = 200 // Upbit's max count per request
+	for start := from; start.Before(to); {
+		end := start.Add(maxCount * d)
+		if end.After(to) {
+			end = to
+		}
+
+		candles, err := c.getCandlesBefore(ctx, market, interval, end, maxCount)
+		if err != nil {
+			return err
+		}
+
+		// Upbit returns the candles before end newest first
+		page := make([]model.Candle, 0, len(candles))
+		for i := len(candles) - 1; i >= 0; i-- {
+			if !candles[i].Timestamp.Before(start) {
+				page = append(page, candles[i])
+			}
+		}
+		if len(page) > 0 {
+			if err := fn(page); err != nil {
+				return err
+			}
+		}
+		start = end
+	}
+	return nil
+}
+
+// getCandlesBefore retrieves up to count candles starting before to, newest
+// first
+func (c *Client) getCandlesBefore(ctx context.Context, market string, interval model.CandleInterval, to time.Time, count int) ([]model.Candle, error) {
+	if err := c.rateLimiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Add("market", market)
+	params.Add("to", to.UTC().Format("2006-01-02T15:04:05"))
+	params.Add("count", fmt.Sprintf("%d", count))
+
+	resp, err := c.doRequest(ctx, "GET", c.getCandleEndpoint(interval)+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var candles []model.Candle
+	if err := json.NewDecoder(resp.Body).Decode(&candles); err != nil {
+		return nil, fmt.Errorf("failed to decode candles: %w", err)
+	}
+	for i := range candles {
+		candles[i].Market = market
+		candles[i].Interval = interval
+	}
+	return candles, nil
+}
+
 // GetOrderbook retrieves current orderbook
 func (c *Client) GetOrderbook(ctx context.Context, market string) (*model.Orderbook, error) {
 	if err := c.rateLimiter.Wait(ctx); err != nil {
@@ -248,15 +329,19 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body io.Rea
 
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	endpoint := callstats.Endpoint(method, path)
+	start := time.Now()
+	resp, err := Timeouts.Do(c.httpClient, req, endpoint)
 	if err != nil {
+		CallStats.Record(endpoint, 0, err, time.Since(start))
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
+	CallStats.Record(endpoint, resp.StatusCode, nil, time.Since(start))
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		resp.Body.Close()
-		return nil, fmt.Errorf("API error: status=%d, body=%s", resp.StatusCode, string(body))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	return resp, nil
@@ -265,6 +350,8 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body io.Rea
 // getCandleEndpoint returns the appropriate endpoint for candle interval
 func (c *Client) getCandleEndpoint(interval model.CandleInterval) string {
 	switch interval {
+	case model.CandleInterval1s:
+		return "/candles/seconds"
 	case model.CandleInterval1m:
 		return "/candles/minutes/1"
 	case model.CandleInterval3m:
@@ -0,0 +1,24 @@
+package quotation
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIError is an error response from the Upbit quotation API
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error: status=%d, body=%s", e.StatusCode, e.Body)
+}
+
+// IsMaintenance reports whether Upbit is down for maintenance: it answers
+// 503 Service Unavailable, or its error mentions maintenance ("점검")
+func (e *APIError) IsMaintenance() bool {
+	return e.StatusCode == http.StatusServiceUnavailable ||
+		strings.Contains(e.Body, "점검") || strings.Contains(strings.ToLower(e.Body), "maintenance")
+}
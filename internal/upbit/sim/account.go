@@ -0,0 +1,369 @@
+package sim
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/exchange"
+)
+
+// Account is the balances and orders of one API key on a simulated
+// exchange. It marshals to JSON, so accounts can outlive the process, e.g.
+// paper trading accounts.
+type Account struct {
+	Holdings map[string]*Holding `json:"holdings"` // By currency
+	Orders   []*Order            `json:"orders"`   // In placement order
+}
+
+// Holding is an account's balance of one currency
+type Holding struct {
+	Balance     float64 `json:"balance"` // Available
+	Locked      float64 `json:"locked"`  // Reserved by open orders
+	AvgBuyPrice float64 `json:"avg_buy_price"`
+}
+
+// Order is an order of an account
+type Order struct {
+	Resp      exchange.OrderResponse `json:"resp"`
+	Base      string                 `json:"base"`      // Currency bought or sold
+	Quote     string                 `json:"quote"`     // Currency paid or received
+	Price     float64                `json:"price"`     // Limit price, or the quote to spend on a market buy
+	Remaining float64                `json:"remaining"` // Volume, or quote for market buys
+	Executed  float64                `json:"executed"`
+	PaidFee   float64                `json:"paid_fee"`
+	Locked    float64                `json:"locked"`     // Still reserved from the account's balance
+	MatchedAt int64                  `json:"matched_at"` // Timestamp of the book last matched against
+}
+
+// BookFunc returns the current orderbook of a market
+type BookFunc func(market string) (*model.Orderbook, bool)
+
+// NewAccount opens an account holding balance KRW
+func NewAccount(balance float64) *Account {
+	return &Account{Holdings: map[string]*Holding{"KRW": {Balance: balance}}}
+}
+
+// Balances returns the account's balances as Upbit reports them, quote
+// currency first
+func (a *Account) Balances() []exchange.Account {
+	currencies := make([]string, 0, len(a.Holdings))
+	for currency, h := range a.Holdings {
+		if currency == "KRW" || h.Balance > 0 || h.Locked > 0 {
+			currencies = append(currencies, currency)
+		}
+	}
+	sort.Slice(currencies, func(i, j int) bool {
+		if (currencies[i] == "KRW") != (currencies[j] == "KRW") {
+			return currencies[i] == "KRW"
+		}
+		return currencies[i] < currencies[j]
+	})
+
+	accounts := make([]exchange.Account, 0, len(currencies))
+	for _, currency := range currencies {
+		h := a.holding(currency)
+		accounts = append(accounts, exchange.Account{
+			Currency:     currency,
+			Balance:      formatDecimal(h.Balance),
+			Locked:       formatDecimal(h.Locked),
+			AvgBuyPrice:  formatDecimal(h.AvgBuyPrice),
+			UnitCurrency: "KRW",
+		})
+	}
+	return accounts
+}
+
+// Place reserves an order's funds and matches it against book. Limit orders
+// rest until the book crosses their price; whatever part of a market order
+// the book can't fill is cancelled. The response is the order at placement
+// time, like Upbit's.
+func (a *Account) Place(req exchange.OrderRequest, book *model.Orderbook, now time.Time) (*exchange.OrderResponse, error) {
+	quote, base, ok := strings.Cut(req.Market, "-")
+	if !ok {
+		return nil, apiError(http.StatusBadRequest, "invalid_market", "invalid market "+req.Market)
+	}
+
+	o := &Order{Base: base, Quote: quote}
+	switch {
+	case req.Side == "bid" && req.OrdType == "limit", req.Side == "ask" && req.OrdType == "limit":
+		o.Price = parsePositive(req.Price)
+		o.Remaining = parsePositive(req.Volume)
+	case req.Side == "bid" && req.OrdType == "price":
+		o.Price = parsePositive(req.Price)
+		o.Remaining = o.Price
+	case req.Side == "ask" && req.OrdType == "market":
+		o.Remaining = parsePositive(req.Volume)
+	default:
+		return nil, apiError(http.StatusBadRequest, "invalid_ord_type", fmt.Sprintf("unsupported %s %s order", req.Side, req.OrdType))
+	}
+	if o.Remaining <= 0 || (req.OrdType != "market" && o.Price <= 0) {
+		return nil, apiError(http.StatusBadRequest, "invalid_volume", "price and volume must be positive")
+	}
+
+	// Bids reserve the quote to pay, fee included; asks the volume to sell
+	currency, reserve := base, o.Remaining
+	if req.Side == "bid" {
+		currency, reserve = quote, o.Remaining*(1+FeeRate)
+		if req.OrdType == "limit" {
+			reserve = o.Price * o.Remaining * (1 + FeeRate)
+		}
+	}
+	h := a.holding(currency)
+	if h.Balance < reserve {
+		return nil, apiError(http.StatusBadRequest, "insufficient_funds_"+req.Side, "주문가능한 금액("+currency+")이 부족합니다.")
+	}
+	h.Balance -= reserve
+	h.Locked += reserve
+	o.Locked = reserve
+
+	o.Resp = exchange.OrderResponse{
+		UUID:       uuid.New().String(),
+		Side:       req.Side,
+		OrdType:    req.OrdType,
+		Price:      req.Price,
+		State:      "wait",
+		Market:     req.Market,
+		CreatedAt:  now,
+		Volume:     req.Volume,
+		Identifier: req.Identifier,
+	}
+	a.Orders = append(a.Orders, o)
+
+	placed := o.Response(false)
+	a.match(o, book, now)
+	if o.Resp.State == "wait" && req.OrdType != "limit" {
+		a.close(o, "cancel")
+	}
+	return &placed, nil
+}
+
+// MatchWaiting matches the account's resting orders against the current
+// orderbooks
+func (a *Account) MatchWaiting(books BookFunc, now time.Time) {
+	for _, o := range a.Orders {
+		if o.Resp.State != "wait" {
+			continue
+		}
+		if book, ok := books(o.Resp.Market); ok {
+			a.match(o, book, now)
+		}
+	}
+}
+
+// WaitingMarkets returns the markets the account has resting orders in
+func (a *Account) WaitingMarkets() []string {
+	var markets []string
+	seen := make(map[string]bool)
+	for _, o := range a.Orders {
+		if o.Resp.State == "wait" && !seen[o.Resp.Market] {
+			seen[o.Resp.Market] = true
+			markets = append(markets, o.Resp.Market)
+		}
+	}
+	return markets
+}
+
+// Order returns one of the account's orders with its trades
+func (a *Account) Order(orderUUID string) (*exchange.OrderResponse, error) {
+	o, ok := a.find(orderUUID)
+	if !ok {
+		return nil, apiError(http.StatusNotFound, "order_not_found", "주문을 찾지 못했습니다.")
+	}
+	resp := o.Response(true)
+	return &resp, nil
+}
+
+// OrderByIdentifier returns the account's order placed with identifier,
+// with its trades
+func (a *Account) OrderByIdentifier(identifier string) (*exchange.OrderResponse, error) {
+	for _, o := range a.Orders {
+		if identifier != "" && o.Resp.Identifier == identifier {
+			resp := o.Response(true)
+			return &resp, nil
+		}
+	}
+	return nil, apiError(http.StatusNotFound, "order_not_found", "주문을 찾지 못했습니다.")
+}
+
+// Cancel cancels one of the account's resting orders
+func (a *Account) Cancel(orderUUID string) (*exchange.OrderResponse, error) {
+	o, ok := a.find(orderUUID)
+	if !ok {
+		return nil, apiError(http.StatusNotFound, "order_not_found", "주문을 찾지 못했습니다.")
+	}
+	if o.Resp.State != "wait" {
+		return nil, apiError(http.StatusBadRequest, "order_not_found", "이미 체결되었거나 취소된 주문입니다.")
+	}
+	a.close(o, "cancel")
+	resp := o.Response(false)
+	return &resp, nil
+}
+
+// List returns the account's orders in a market and state, either of which
+// may be empty to match all
+func (a *Account) List(market, state string) []exchange.OrderResponse {
+	orders := []exchange.OrderResponse{}
+	for _, o := range a.Orders {
+		if (market != "" && o.Resp.Market != market) || (state != "" && o.Resp.State != state) {
+			continue
+		}
+		orders = append(orders, o.Response(false))
+	}
+	return orders
+}
+
+// Prune forgets orders that ended before cutoff, keeping persisted accounts
+// small. Resting orders are kept.
+func (a *Account) Prune(cutoff time.Time) {
+	kept := a.Orders[:0]
+	for _, o := range a.Orders {
+		if o.Resp.State == "wait" || !o.endedAt().Before(cutoff) {
+			kept = append(kept, o)
+		}
+	}
+	clear(a.Orders[len(kept):])
+	a.Orders = kept
+}
+
+func (a *Account) find(orderUUID string) (*Order, bool) {
+	for _, o := range a.Orders {
+		if o.Resp.UUID == orderUUID {
+			return o, true
+		}
+	}
+	return nil, false
+}
+
+// holding returns the account's holding of a currency
+func (a *Account) holding(currency string) *Holding {
+	if a.Holdings == nil {
+		a.Holdings = make(map[string]*Holding)
+	}
+	h, ok := a.Holdings[currency]
+	if !ok {
+		h = &Holding{}
+		a.Holdings[currency] = h
+	}
+	return h
+}
+
+// match fills as much of an order as book allows. Each book's liquidity is
+// offered to an order once, so a resting order only fills further when the
+// book moves.
+func (a *Account) match(o *Order, book *model.Orderbook, now time.Time) {
+	if book.Timestamp == o.MatchedAt {
+		return
+	}
+	o.MatchedAt = book.Timestamp
+
+	limit := o.Resp.OrdType == "limit"
+	for _, unit := range book.OrderbookUnits {
+		if o.Remaining <= dust {
+			break
+		}
+		if o.Resp.Side == "bid" {
+			if limit && unit.AskPrice > o.Price {
+				break
+			}
+			volume := min(unit.AskSize, o.Remaining)
+			if o.Resp.OrdType == "price" {
+				volume = min(unit.AskSize, o.Remaining/unit.AskPrice)
+			}
+			a.fill(o, volume, unit.AskPrice, now)
+		} else {
+			if limit && unit.BidPrice < o.Price {
+				break
+			}
+			a.fill(o, min(unit.BidSize, o.Remaining), unit.BidPrice, now)
+		}
+	}
+
+	if o.Remaining <= dust {
+		a.close(o, "done")
+	}
+}
+
+// fill executes volume of an order at price and settles it with the account
+func (a *Account) fill(o *Order, volume, price float64, now time.Time) {
+	if volume <= 0 {
+		return
+	}
+	funds := volume * price
+	fee := funds * FeeRate
+
+	base := a.holding(o.Base)
+	quote := a.holding(o.Quote)
+	if o.Resp.Side == "bid" {
+		quote.Locked -= funds + fee
+		o.Locked -= funds + fee
+		base.AvgBuyPrice = (base.AvgBuyPrice*(base.Balance+base.Locked) + funds) / (base.Balance + base.Locked + volume)
+		base.Balance += volume
+		if o.Resp.OrdType == "price" {
+			o.Remaining -= funds
+		} else {
+			o.Remaining -= volume
+		}
+	} else {
+		base.Locked -= volume
+		o.Locked -= volume
+		quote.Balance += funds - fee
+		o.Remaining -= volume
+	}
+
+	o.Executed += volume
+	o.PaidFee += fee
+	o.Resp.Trades = append(o.Resp.Trades, exchange.Trade{
+		Market:    o.Resp.Market,
+		UUID:      uuid.New().String(),
+		Price:     formatDecimal(price),
+		Volume:    formatDecimal(volume),
+		Funds:     formatDecimal(funds),
+		Side:      o.Resp.Side,
+		CreatedAt: now,
+	})
+}
+
+// close ends an order in state and releases what it still had reserved
+func (a *Account) close(o *Order, state string) {
+	o.Resp.State = state
+
+	currency := o.Base
+	if o.Resp.Side == "bid" {
+		currency = o.Quote
+	}
+	h := a.holding(currency)
+	h.Locked -= o.Locked
+	h.Balance += o.Locked
+	o.Locked = 0
+}
+
+// endedAt returns when an order last traded, or when it was placed
+func (o *Order) endedAt() time.Time {
+	if n := len(o.Resp.Trades); n > 0 {
+		return o.Resp.Trades[n-1].CreatedAt
+	}
+	return o.Resp.CreatedAt
+}
+
+// Response renders the order as Upbit reports it
+func (o *Order) Response(withTrades bool) exchange.OrderResponse {
+	resp := o.Resp
+	resp.ExecutedVolume = formatDecimal(o.Executed)
+	resp.PaidFee = formatDecimal(o.PaidFee)
+	resp.Locked = formatDecimal(o.Locked)
+	resp.TradesCount = len(o.Resp.Trades)
+	resp.Trades = nil
+	if withTrades {
+		resp.Trades = append([]exchange.Trade{}, o.Resp.Trades...)
+	}
+	if o.Resp.OrdType != "price" {
+		remaining := formatDecimal(max(o.Remaining, 0))
+		resp.RemainingVolume = &remaining
+	}
+	return resp
+}
@@ -0,0 +1,135 @@
+package sim
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/gateway"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/exchange"
+)
+
+// dust is the remaining volume or KRW below which an order counts as filled
+const dust = 1e-9
+
+var (
+	_ gateway.ExchangeAPI    = (*Client)(nil)
+	_ gateway.OrderLookupAPI = (*Client)(nil)
+	_ gateway.QuotationAPI   = (*Exchange)(nil)
+)
+
+// Client is one API key's view of a simulated exchange
+type Client struct {
+	exchange  *Exchange
+	accessKey string
+}
+
+// NewClient is an ExchangeClientFactory trading on the exchange. Any
+// credentials are accepted; each access key gets its own account.
+func (e *Exchange) NewClient(accessKey, secretKey string) gateway.ExchangeAPI {
+	return &Client{exchange: e, accessKey: accessKey}
+}
+
+// GetAccounts returns the account's balances, quote currency first
+func (c *Client) GetAccounts(ctx context.Context) ([]exchange.Account, error) {
+	e := c.exchange
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.matchResting()
+	return e.account(c.accessKey).Balances(), nil
+}
+
+// PlaceOrder reserves the order's funds and matches it against the current
+// orderbook. Limit orders rest until the book crosses their price; whatever
+// part of a market order the book can't fill is cancelled.
+func (c *Client) PlaceOrder(ctx context.Context, req exchange.OrderRequest) (*exchange.OrderResponse, error) {
+	e := c.exchange
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := e.now()
+	book, ok := e.book(req.Market, now)
+	if !ok {
+		return nil, apiError(http.StatusNotFound, "market_does_not_exist", "no recorded orderbook for "+req.Market)
+	}
+	return e.account(c.accessKey).Place(req, book, now)
+}
+
+// GetOrder returns one of the account's orders with its trades
+func (c *Client) GetOrder(ctx context.Context, orderUUID string) (*exchange.OrderResponse, error) {
+	e := c.exchange
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.matchResting()
+	return e.account(c.accessKey).Order(orderUUID)
+}
+
+// GetOrderByIdentifier returns the account's order placed with identifier
+func (c *Client) GetOrderByIdentifier(ctx context.Context, identifier string) (*exchange.OrderResponse, error) {
+	e := c.exchange
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.matchResting()
+	return e.account(c.accessKey).OrderByIdentifier(identifier)
+}
+
+// CancelOrder cancels one of the account's resting orders
+func (c *Client) CancelOrder(ctx context.Context, orderUUID string) (*exchange.OrderResponse, error) {
+	e := c.exchange
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.matchResting()
+	return e.account(c.accessKey).Cancel(orderUUID)
+}
+
+// GetOrders returns the account's orders in a market and state, either of
+// which may be empty to match all
+func (c *Client) GetOrders(ctx context.Context, market string, state string) ([]exchange.OrderResponse, error) {
+	e := c.exchange
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.matchResting()
+	return e.account(c.accessKey).List(market, state), nil
+}
+
+// matchResting matches every account's resting orders against the current
+// orderbooks. Callers must hold e.mu.
+func (e *Exchange) matchResting() {
+	now := e.now()
+	books := func(market string) (*model.Orderbook, bool) {
+		return e.book(market, now)
+	}
+	for _, account := range e.accounts {
+		account.MatchWaiting(books, now)
+	}
+}
+
+// apiError builds the error the real client returns for an Upbit error
+// response, so callers can't tell the simulation apart
+func apiError(status int, name, message string) *exchange.APIError {
+	body, _ := json.Marshal(map[string]any{"error": map[string]string{"name": name, "message": message}})
+	return &exchange.APIError{StatusCode: status, Name: name, Message: message, Body: string(body)}
+}
+
+// parsePositive parses an optional decimal, returning 0 if it is missing or invalid
+func parsePositive(s *string) float64 {
+	if s == nil {
+		return 0
+	}
+	v, err := strconv.ParseFloat(*s, 64)
+	if err != nil || v < 0 {
+		return 0
+	}
+	return v
+}
+
+func formatDecimal(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
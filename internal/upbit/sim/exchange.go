@@ -0,0 +1,166 @@
+// Package sim simulates the Upbit exchange in-process so the platform can run
+// offline, without Upbit credentials or network access. Prices come from
+// orderbook snapshots recorded from Upbit, replayed in a loop, and orders are
+// matched against the snapshot current when they are placed or checked.
+package sim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+const (
+	// FeeRate is the fee charged on every fill, matching Upbit's KRW market fee
+	FeeRate = 0.0005
+	// DefaultBalance is the KRW every new account starts with by default
+	DefaultBalance = 10_000_000
+	// snapshotGap is how long the last snapshot of a recording lasts before
+	// playback loops back to the first
+	snapshotGap = time.Second
+)
+
+// Exchange is a simulated exchange. Each API key has its own account, created
+// with the starting balance the first time the key is used.
+type Exchange struct {
+	books   map[string][]model.Orderbook // By market, oldest first
+	start   time.Time                    // Of the recording
+	span    time.Duration                // Of the recording; playback loops after it
+	started time.Time                    // When playback started
+	balance float64
+	now     func() time.Time
+
+	mu       sync.Mutex
+	accounts map[string]*Account // By access key
+}
+
+// NewExchange creates an exchange replaying the given orderbook snapshots,
+// with accounts starting with balance KRW
+func NewExchange(snapshots []model.Orderbook, balance float64) (*Exchange, error) {
+	e := &Exchange{
+		books:    make(map[string][]model.Orderbook),
+		balance:  balance,
+		now:      time.Now,
+		accounts: make(map[string]*Account),
+	}
+
+	var first, last int64
+	for _, book := range snapshots {
+		if book.Market == "" || len(book.OrderbookUnits) == 0 {
+			continue
+		}
+		e.books[book.Market] = append(e.books[book.Market], book)
+		if first == 0 || book.Timestamp < first {
+			first = book.Timestamp
+		}
+		last = max(last, book.Timestamp)
+	}
+	if len(e.books) == 0 {
+		return nil, errors.New("no orderbook snapshots to replay")
+	}
+
+	for _, books := range e.books {
+		sort.SliceStable(books, func(i, j int) bool { return books[i].Timestamp < books[j].Timestamp })
+	}
+	e.start = time.UnixMilli(first)
+	e.span = time.UnixMilli(last).Sub(e.start) + snapshotGap
+	e.started = e.now()
+	return e, nil
+}
+
+// Load creates an exchange replaying the orderbooks recorded in a file of
+// Upbit /v1/orderbook responses, one JSON object or array per line
+func Load(path string, balance float64) (*Exchange, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recorded orderbooks: %w", err)
+	}
+	defer f.Close()
+
+	var snapshots []model.Orderbook
+	dec := json.NewDecoder(f)
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read recorded orderbooks: %w", err)
+		}
+
+		if len(raw) > 0 && raw[0] == '[' {
+			var books []model.Orderbook
+			if err := json.Unmarshal(raw, &books); err != nil {
+				return nil, fmt.Errorf("invalid recorded orderbooks: %w", err)
+			}
+			snapshots = append(snapshots, books...)
+			continue
+		}
+		var book model.Orderbook
+		if err := json.Unmarshal(raw, &book); err != nil {
+			return nil, fmt.Errorf("invalid recorded orderbook: %w", err)
+		}
+		snapshots = append(snapshots, book)
+	}
+
+	return NewExchange(snapshots, balance)
+}
+
+// Markets returns the markets with recorded orderbooks, sorted
+func (e *Exchange) Markets() []string {
+	markets := make([]string, 0, len(e.books))
+	for market := range e.books {
+		markets = append(markets, market)
+	}
+	sort.Strings(markets)
+	return markets
+}
+
+// book returns the market's snapshot at t, replaying the recording in a loop
+// from when playback started. Times before the first snapshot of a market in
+// each loop get its last one.
+func (e *Exchange) book(market string, t time.Time) (*model.Orderbook, bool) {
+	books, ok := e.books[market]
+	if !ok {
+		return nil, false
+	}
+
+	offset := t.Sub(e.started) % e.span
+	if offset < 0 {
+		offset += e.span
+	}
+	at := e.start.Add(offset).UnixMilli()
+
+	i := sort.Search(len(books), func(i int) bool { return books[i].Timestamp > at })
+	if i == 0 {
+		i = len(books)
+	}
+	return &books[i-1], true
+}
+
+// price returns the market's mid price at t
+func (e *Exchange) price(market string, t time.Time) (float64, bool) {
+	book, ok := e.book(market, t)
+	if !ok {
+		return 0, false
+	}
+	best := book.OrderbookUnits[0]
+	return (best.AskPrice + best.BidPrice) / 2, true
+}
+
+// account returns the account of an API key, opening it if needed. Callers
+// must hold e.mu.
+func (e *Exchange) account(accessKey string) *Account {
+	account, ok := e.accounts[accessKey]
+	if !ok {
+		account = NewAccount(e.balance)
+		e.accounts[accessKey] = account
+	}
+	return account
+}
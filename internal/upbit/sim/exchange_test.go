@@ -0,0 +1,155 @@
+package sim
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/exchange"
+)
+
+// newTestExchange loads the recorded orderbooks with a clock the test moves
+func newTestExchange(t *testing.T) (*Exchange, *time.Time) {
+	t.Helper()
+	e, err := Load("testdata/orderbooks.jsonl", 1_000_000)
+	require.NoError(t, err)
+
+	clock := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	e.started = clock
+	e.now = func() time.Time { return clock }
+	return e, &clock
+}
+
+func ptr(s string) *string { return &s }
+
+func decimal(t *testing.T, s string) float64 {
+	t.Helper()
+	v, err := strconv.ParseFloat(s, 64)
+	require.NoError(t, err)
+	return v
+}
+
+func TestExchange_Replay(t *testing.T) {
+	e, clock := newTestExchange(t)
+	assert.Equal(t, []string{"KRW-BTC", "KRW-ETH"}, e.Markets())
+
+	price, _ := e.price("KRW-BTC", *clock)
+	assert.Equal(t, 99_950_000.0, price)
+	price, _ = e.price("KRW-BTC", clock.Add(1500*time.Millisecond))
+	assert.Equal(t, 100_950_000.0, price)
+	// The recording is two seconds long, then loops
+	price, _ = e.price("KRW-BTC", clock.Add(2500*time.Millisecond))
+	assert.Equal(t, 99_950_000.0, price)
+	// Before ETH's first snapshot in a loop its last one applies
+	price, _ = e.price("KRW-ETH", *clock)
+	assert.Equal(t, 4_995_000.0, price)
+}
+
+func TestClient_MarketBuyWalksTheBook(t *testing.T) {
+	e, _ := newTestExchange(t)
+	e.balance = 30_000_000
+	client := e.NewClient("access", "secret")
+	ctx := context.Background()
+
+	placed, err := client.PlaceOrder(ctx, exchange.OrderRequest{Market: "KRW-BTC", Side: "bid", OrdType: "price", Price: ptr("20000000")})
+	require.NoError(t, err)
+	assert.Equal(t, "wait", placed.State)
+
+	order, err := client.GetOrder(ctx, placed.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, "done", order.State)
+	require.Len(t, order.Trades, 2)
+	// 0.1 BTC at the best ask, then the remaining 10M KRW at the next level
+	assert.InDelta(t, 0.1+10_000_000.0/100_100_000, decimal(t, order.ExecutedVolume), 1e-12)
+	assert.InDelta(t, 10_000, decimal(t, order.PaidFee), 1e-6)
+
+	_, err = client.PlaceOrder(ctx, exchange.OrderRequest{Market: "KRW-BTC", Side: "bid", OrdType: "price", Price: ptr("10000000")})
+	var apiErr *exchange.APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.Equal(t, "insufficient_funds_bid", apiErr.Name)
+	assert.False(t, apiErr.IsTemporary())
+
+	accounts, err := client.GetAccounts(ctx)
+	require.NoError(t, err)
+	require.Len(t, accounts, 2)
+	assert.Equal(t, "KRW", accounts[0].Currency)
+	assert.InDelta(t, 9_990_000, decimal(t, accounts[0].Balance), 1e-6)
+	assert.Equal(t, "BTC", accounts[1].Currency)
+
+	// Other API keys have their own accounts
+	other, err := e.NewClient("other", "secret").GetAccounts(ctx)
+	require.NoError(t, err)
+	require.Len(t, other, 1)
+	assert.Equal(t, "30000000", other[0].Balance)
+}
+
+func TestClient_LimitOrderRestsUntilTheBookCrosses(t *testing.T) {
+	e, clock := newTestExchange(t)
+	e.balance = 100_000_000
+	client := e.NewClient("access", "secret")
+	ctx := context.Background()
+
+	_, err := client.PlaceOrder(ctx, exchange.OrderRequest{Market: "KRW-BTC", Side: "bid", OrdType: "price", Price: ptr("50000000")})
+	require.NoError(t, err)
+
+	sell, err := client.PlaceOrder(ctx, exchange.OrderRequest{Market: "KRW-BTC", Side: "ask", OrdType: "limit", Price: ptr("100850000"), Volume: ptr("0.15")})
+	require.NoError(t, err)
+	order, err := client.GetOrder(ctx, sell.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, "wait", order.State)
+	assert.Equal(t, "0.15", order.Locked)
+
+	// The book moves up through the limit: 0.1 fills at the best bid, the
+	// next level is below the limit
+	*clock = clock.Add(time.Second)
+	order, err = client.GetOrder(ctx, sell.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, "wait", order.State)
+	assert.InDelta(t, 0.1, decimal(t, order.ExecutedVolume), 1e-12)
+	assert.Equal(t, "100900000", order.Trades[0].Price)
+
+	// The same snapshot's liquidity isn't offered twice
+	order, err = client.GetOrder(ctx, sell.UUID)
+	require.NoError(t, err)
+	assert.Len(t, order.Trades, 1)
+
+	cancelled, err := client.CancelOrder(ctx, sell.UUID)
+	require.NoError(t, err)
+	assert.Equal(t, "cancel", cancelled.State)
+	open, err := client.GetOrders(ctx, "KRW-BTC", "wait")
+	require.NoError(t, err)
+	assert.Empty(t, open)
+}
+
+func TestExchange_Quotation(t *testing.T) {
+	e, clock := newTestExchange(t)
+	ctx := context.Background()
+	*clock = clock.Add(90 * time.Second)
+
+	candles, err := e.GetCandles(ctx, "KRW-BTC", model.CandleInterval1m, 3)
+	require.NoError(t, err)
+	require.Len(t, candles, 3)
+	assert.True(t, candles[0].Timestamp.After(candles[1].Timestamp))
+	// The current candle, sampled up to now
+	assert.Equal(t, 100_950_000.0, candles[0].HighPrice)
+	assert.Equal(t, 99_950_000.0, candles[0].LowPrice)
+
+	ranged, err := e.GetCandleRange(ctx, "KRW-BTC", model.CandleInterval1m, clock.Add(-5*time.Minute), *clock)
+	require.NoError(t, err)
+	assert.Len(t, ranged, 5)
+
+	tickers, err := e.GetTicker(ctx, []string{"KRW-ETH"})
+	require.NoError(t, err)
+	require.Len(t, tickers, 1)
+	assert.Equal(t, 4_995_000.0, tickers[0].TradePrice)
+
+	_, err = e.GetOrderbook(ctx, "KRW-XRP")
+	assert.Error(t, err)
+}
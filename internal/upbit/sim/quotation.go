@@ -0,0 +1,202 @@
+package sim
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/internal/upbit/quotation"
+)
+
+const (
+	// candleSamples is how many prices each candle's OHLC is taken from
+	candleSamples = 30
+	// maxCandles bounds the candles of a single range request
+	maxCandles = 100_000
+	// streamPageSize is how many candles StreamCandleRange passes at a time
+	streamPageSize = 200
+)
+
+// GetMarkets lists the markets with recorded orderbooks
+func (e *Exchange) GetMarkets(ctx context.Context) ([]quotation.Market, error) {
+	markets := make([]quotation.Market, 0, len(e.books))
+	for _, market := range e.Markets() {
+		markets = append(markets, quotation.Market{Market: market, KoreanName: market, EnglishName: market})
+	}
+	return markets, nil
+}
+
+// GetCandles returns the market's last count candles, newest first. Candles
+// are built from the mid prices of the replayed orderbooks and have no volume.
+func (e *Exchange) GetCandles(ctx context.Context, market string, interval model.CandleInterval, count int) ([]model.Candle, error) {
+	d := interval.Duration()
+	if d == 0 {
+		return nil, fmt.Errorf("unsupported candle interval %q", interval)
+	}
+	if _, ok := e.books[market]; !ok {
+		return nil, fmt.Errorf("no recorded orderbook for %s", market)
+	}
+
+	now := e.now()
+	latest := now.Truncate(d)
+	candles := make([]model.Candle, 0, count)
+	for i := 0; i < count; i++ {
+		candles = append(candles, e.candle(market, interval, latest.Add(-time.Duration(i)*d), now))
+	}
+	return candles, nil
+}
+
+// GetCandleRange returns the market's candles starting in [from, to), newest
+// first
+func (e *Exchange) GetCandleRange(ctx context.Context, market string, interval model.CandleInterval, from, to time.Time) ([]model.Candle, error) {
+	d := interval.Duration()
+	if d == 0 {
+		return nil, fmt.Errorf("unsupported candle interval %q", interval)
+	}
+	if _, ok := e.books[market]; !ok {
+		return nil, fmt.Errorf("no recorded orderbook for %s", market)
+	}
+
+	now := e.now()
+	if to.After(now) {
+		to = now
+	}
+	if to.Sub(from)/d > maxCandles {
+		return nil, fmt.Errorf("range covers more than %d candles", maxCandles)
+	}
+
+	var candles []model.Candle
+	for start := to.Truncate(d); !start.Before(from); start = start.Add(-d) {
+		if start.Before(to) {
+			candles = append(candles, e.candle(market, interval, start, now))
+		}
+	}
+	return candles, nil
+}
+
+// StreamCandleRange calls fn with the market's candles starting in
+// [from, to), oldest first, a page of at most streamPageSize at a time.
+// Unlike GetCandleRange the range isn't bounded.
+func (e *Exchange) StreamCandleRange(ctx context.Context, market string, interval model.CandleInterval, from, to time.Time, fn func([]model.Candle) error) error {
+	d := interval.Duration()
+	if d == 0 {
+		return fmt.Errorf("unsupported candle interval %q", interval)
+	}
+	if _, ok := e.books[market]; !ok {
+		return fmt.Errorf("no recorded orderbook for %s", market)
+	}
+
+	now := e.now()
+	if to.After(now) {
+		to = now
+	}
+
+	start := from.Truncate(d)
+	if start.Before(from) {
+		start = start.Add(d)
+	}
+	page := make([]model.Candle, 0, streamPageSize)
+	for ; start.Before(to); start = start.Add(d) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		page = append(page, e.candle(market, interval, start, now))
+		if len(page) == streamPageSize {
+			if err := fn(page); err != nil {
+				return err
+			}
+			page = make([]model.Candle, 0, streamPageSize)
+		}
+	}
+	if len(page) > 0 {
+		return fn(page)
+	}
+	return nil
+}
+
+// GetOrderbook returns the market's current orderbook
+func (e *Exchange) GetOrderbook(ctx context.Context, market string) (*model.Orderbook, error) {
+	now := e.now()
+	book, ok := e.book(market, now)
+	if !ok {
+		return nil, fmt.Errorf("no recorded orderbook for %s", market)
+	}
+
+	current := *book
+	current.OrderbookUnits = append([]model.OrderbookUnit{}, book.OrderbookUnits...)
+	current.Timestamp = now.UnixMilli()
+	return &current, nil
+}
+
+// GetTicker returns the markets' current mid prices, with the change since
+// the start of the UTC day
+func (e *Exchange) GetTicker(ctx context.Context, markets []string) ([]quotation.Ticker, error) {
+	now := e.now()
+	day := now.UTC().Truncate(24 * time.Hour)
+
+	tickers := make([]quotation.Ticker, 0, len(markets))
+	for _, market := range markets {
+		if _, ok := e.books[market]; !ok {
+			return nil, fmt.Errorf("no recorded orderbook for %s", market)
+		}
+		candle := e.candle(market, model.CandleInterval1d, day, now)
+
+		ticker := quotation.Ticker{
+			Market:           market,
+			TradeDate:        now.UTC().Format("20060102"),
+			TradeTime:        now.UTC().Format("150405"),
+			TradeTimestamp:   now.UnixMilli(),
+			OpeningPrice:     candle.OpenPrice,
+			HighPrice:        candle.HighPrice,
+			LowPrice:         candle.LowPrice,
+			TradePrice:       candle.ClosePrice,
+			PrevClosingPrice: candle.OpenPrice,
+			Timestamp:        now.UnixMilli(),
+		}
+		ticker.SignedChangePrice = ticker.TradePrice - ticker.OpeningPrice
+		ticker.ChangePrice = max(ticker.SignedChangePrice, -ticker.SignedChangePrice)
+		if ticker.OpeningPrice > 0 {
+			ticker.SignedChangeRate = ticker.SignedChangePrice / ticker.OpeningPrice
+			ticker.ChangeRate = max(ticker.SignedChangeRate, -ticker.SignedChangeRate)
+		}
+		switch {
+		case ticker.SignedChangePrice > 0:
+			ticker.Change = "RISE"
+		case ticker.SignedChangePrice < 0:
+			ticker.Change = "FALL"
+		default:
+			ticker.Change = "EVEN"
+		}
+		tickers = append(tickers, ticker)
+	}
+	return tickers, nil
+}
+
+// candle builds the candle starting at start from mid prices sampled across
+// it, up to now
+func (e *Exchange) candle(market string, interval model.CandleInterval, start, now time.Time) model.Candle {
+	end := start.Add(interval.Duration())
+	if end.After(now) {
+		end = now
+	}
+	step := max(end.Sub(start)/candleSamples, time.Millisecond)
+
+	candle := model.Candle{Market: market, Interval: interval, Timestamp: start}
+	for t := start; ; t = t.Add(step) {
+		if !t.Before(end) {
+			t = end
+		}
+		price, _ := e.price(market, t)
+		if candle.OpenPrice == 0 {
+			candle.OpenPrice, candle.HighPrice, candle.LowPrice = price, price, price
+		}
+		candle.HighPrice = max(candle.HighPrice, price)
+		candle.LowPrice = min(candle.LowPrice, price)
+		candle.ClosePrice = price
+		if !t.Before(end) {
+			break
+		}
+	}
+	return candle
+}
@@ -4,16 +4,18 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+	"github.com/sungminna/upbit-trading-platform/pkg/recovery"
 )
 
 const (
-	wsURL = "wss://api.upbit.com/websocket/v1"
+	DefaultURL = "wss://api.upbit.com/websocket/v1"
 )
 
 // MessageType represents the type of WebSocket message
@@ -27,18 +29,30 @@ const (
 
 // Client represents Upbit WebSocket client
 type Client struct {
+	url         string
 	conn        *websocket.Conn
 	mu          sync.RWMutex
-	handlers    map[MessageType][]MessageHandler
+	handlers    map[MessageType][]*dispatcher
+	policies    map[MessageType]DispatchPolicy
 	isConnected bool
 	reconnect   bool
+	format      Format   // Requested on subscribe; Upbit defaults to FormatDefault
+	recorder    Recorder // Optional; receives every decoded message verbatim
 	ctx         context.Context
 	cancel      context.CancelFunc
 }
 
-// MessageHandler is a callback function for WebSocket messages
+// MessageHandler is a callback function for WebSocket messages. Each handler
+// runs on its own goroutine, fed through a queue with the DispatchPolicy of
+// its message type.
 type MessageHandler func(interface{}) error
 
+// Recorder keeps raw messages, e.g. for bug reproduction and replay. Record
+// is called on the read loop, so it must not block.
+type Recorder interface {
+	Record(msg model.RawMessage)
+}
+
 // SubscribeRequest represents a WebSocket subscription request
 type SubscribeRequest struct {
 	Ticket string                   `json:"ticket"`
@@ -111,16 +125,43 @@ type OrderbookMessage struct {
 
 // NewClient creates a new WebSocket client
 func NewClient() *Client {
+	return NewClientWithURL(DefaultURL)
+}
+
+// NewClientWithURL creates a new WebSocket client against a custom endpoint,
+// e.g. the fake Upbit server used in integration tests
+func NewClientWithURL(url string) *Client {
 	ctx, cancel := context.WithCancel(context.Background())
 	return &Client{
-		handlers:  make(map[MessageType][]MessageHandler),
+		url:       url,
+		handlers:  make(map[MessageType][]*dispatcher),
+		policies:  maps.Clone(DefaultDispatchPolicies),
 		reconnect: true,
 		ctx:       ctx,
 		cancel:    cancel,
 	}
 }
 
-// Connect establishes WebSocket connection
+// WithFormat requests messages in the given format on subscribe. Messages of
+// either format are decoded into the same message structs.
+func (c *Client) WithFormat(format Format) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.format = format
+	return c
+}
+
+// WithRecorder passes every ticker, trade and orderbook message, as received,
+// to the recorder before it is dispatched
+func (c *Client) WithRecorder(recorder Recorder) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.recorder = recorder
+	return c
+}
+
+// Connect establishes WebSocket connection, negotiating per-message
+// compression
 func (c *Client) Connect() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -129,7 +170,9 @@ func (c *Client) Connect() error {
 		return nil
 	}
 
-	conn, _, err := websocket.DefaultDialer.Dial(wsURL, nil)
+	dialer := *websocket.DefaultDialer
+	dialer.EnableCompression = true
+	conn, _, err := dialer.Dial(c.url, nil)
 	if err != nil {
 		return fmt.Errorf("failed to connect to WebSocket: %w", err)
 	}
@@ -161,6 +204,9 @@ func (c *Client) Subscribe(msgType MessageType, markets []string) error {
 			"codes": markets,
 		},
 	}
+	if c.format != "" {
+		requests = append(requests, map[string]string{"format": string(c.format)})
+	}
 
 	if err := c.conn.WriteJSON(requests); err != nil {
 		return fmt.Errorf("failed to subscribe: %w", err)
@@ -169,25 +215,59 @@ func (c *Client) Subscribe(msgType MessageType, markets []string) error {
 	return nil
 }
 
-// OnTicker registers a handler for ticker messages
-func (c *Client) OnTicker(handler MessageHandler) {
+// WithDispatchPolicy sets how messages of a type are queued for handlers
+// registered after it
+func (c *Client) WithDispatchPolicy(msgType MessageType, policy DispatchPolicy) *Client {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.handlers[MessageTypeTicker] = append(c.handlers[MessageTypeTicker], handler)
+	c.policies[msgType] = policy
+	return c
+}
+
+// OnTicker registers a handler for ticker messages
+func (c *Client) OnTicker(handler MessageHandler) {
+	c.addHandler(MessageTypeTicker, handler)
 }
 
 // OnTrade registers a handler for trade messages
 func (c *Client) OnTrade(handler MessageHandler) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.handlers[MessageTypeTrade] = append(c.handlers[MessageTypeTrade], handler)
+	c.addHandler(MessageTypeTrade, handler)
 }
 
 // OnOrderbook registers a handler for orderbook messages
 func (c *Client) OnOrderbook(handler MessageHandler) {
+	c.addHandler(MessageTypeOrderbook, handler)
+}
+
+// addHandler starts delivering messages of a type to a handler until the
+// client is closed
+func (c *Client) addHandler(msgType MessageType, handler MessageHandler) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.handlers[MessageTypeOrderbook] = append(c.handlers[MessageTypeOrderbook], handler)
+
+	d := newDispatcher(handler, c.policies[msgType])
+	c.handlers[msgType] = append(c.handlers[msgType], d)
+	go d.run(c.ctx)
+}
+
+// Stats returns the delivered, dropped and queued messages of each type,
+// summed over its handlers
+func (c *Client) Stats() map[MessageType]DispatchStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	stats := make(map[MessageType]DispatchStats)
+	for msgType, dispatchers := range c.handlers {
+		var total DispatchStats
+		for _, d := range dispatchers {
+			s := d.stats()
+			total.Delivered += s.Delivered
+			total.Dropped += s.Dropped
+			total.Queued += s.Queued
+		}
+		stats[msgType] = total
+	}
+	return stats
 }
 
 // Close closes the WebSocket connection
@@ -231,50 +311,71 @@ func (c *Client) readMessages() {
 				return
 			}
 
-			c.handleMessage(message)
+			recovery.Do("websocket.message", func() { c.handleMessage(message) })
 		}
 	}
 }
 
-// handleMessage processes a single message
+// handleMessage records and dispatches a single message
 func (c *Client) handleMessage(data []byte) {
+	receivedAt := time.Now()
+	msgType, msg, err := Decode(data)
+	if err != nil || msg == nil {
+		return
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.recorder != nil {
+		c.recorder.Record(model.RawMessage{
+			ReceivedAt: receivedAt,
+			Type:       string(msgType),
+			Market:     messageCode(msg),
+			Payload:    data,
+		})
+	}
+
+	for _, d := range c.handlers[msgType] {
+		d.dispatch(msg)
+	}
+}
+
+// Decode decodes a message of either format into its message struct. Types
+// other than tickers, trades and orderbooks decode to a nil message.
+func Decode(data []byte) (MessageType, interface{}, error) {
 	var msgType struct {
-		Type string `json:"type"`
+		Type       string `json:"type"`
+		SimpleType string `json:"ty"` // SIMPLE format
 	}
 
 	if err := json.Unmarshal(data, &msgType); err != nil {
-		return
+		return "", nil, err
 	}
 
-	c.mu.RLock()
-	defer c.mu.RUnlock()
+	if msgType.SimpleType != "" {
+		msg, err := decodeSimple(MessageType(msgType.SimpleType), data)
+		return MessageType(msgType.SimpleType), msg, err
+	}
 
+	var msg interface{}
+	var err error
 	switch MessageType(msgType.Type) {
 	case MessageTypeTicker:
-		var msg TickerMessage
-		if err := json.Unmarshal(data, &msg); err != nil {
-			return
-		}
-		for _, handler := range c.handlers[MessageTypeTicker] {
-			handler(msg)
-		}
-
+		var ticker TickerMessage
+		err = json.Unmarshal(data, &ticker)
+		msg = ticker
 	case MessageTypeTrade:
-		var msg TradeMessage
-		if err := json.Unmarshal(data, &msg); err != nil {
-			return
-		}
-		for _, handler := range c.handlers[MessageTypeTrade] {
-			handler(msg)
-		}
-
+		var trade TradeMessage
+		err = json.Unmarshal(data, &trade)
+		msg = trade
 	case MessageTypeOrderbook:
-		var msg OrderbookMessage
-		if err := json.Unmarshal(data, &msg); err != nil {
-			return
-		}
-		for _, handler := range c.handlers[MessageTypeOrderbook] {
-			handler(msg)
-		}
+		var orderbook OrderbookMessage
+		err = json.Unmarshal(data, &orderbook)
+		msg = orderbook
+	}
+	if err != nil {
+		return "", nil, err
 	}
+	return MessageType(msgType.Type), msg, nil
 }
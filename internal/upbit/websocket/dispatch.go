@@ -0,0 +1,166 @@
+package websocket
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/sungminna/upbit-trading-platform/pkg/recovery"
+)
+
+// DropPolicy decides which messages are dropped when a handler falls behind
+type DropPolicy string
+
+const (
+	// DropOldest discards the oldest queued message to make room
+	DropOldest DropPolicy = "drop_oldest"
+	// DropNewest discards the message that doesn't fit
+	DropNewest DropPolicy = "drop_newest"
+	// KeepLatest queues only the latest message of each market, replacing
+	// the one waiting. It suits tickers and orderbooks, which supersede each
+	// other.
+	KeepLatest DropPolicy = "keep_latest"
+)
+
+// DispatchPolicy configures the queue between the read loop and a handler.
+// Handlers run on their own goroutine, so a slow handler drops messages
+// instead of stalling the socket until Upbit disconnects it.
+type DispatchPolicy struct {
+	Drop   DropPolicy `json:"drop"`
+	Buffer int        `json:"buffer"` // Queued messages; unused by KeepLatest
+}
+
+// DefaultDispatchPolicies are the policies of handlers registered without
+// WithDispatchPolicy: tickers and orderbooks keep the latest per market, and
+// trades queue up, dropping the oldest once 1024 are waiting
+var DefaultDispatchPolicies = map[MessageType]DispatchPolicy{
+	MessageTypeTicker:    {Drop: KeepLatest},
+	MessageTypeTrade:     {Drop: DropOldest, Buffer: 1024},
+	MessageTypeOrderbook: {Drop: KeepLatest},
+}
+
+// DispatchStats counts the messages of a type passed to its handlers
+type DispatchStats struct {
+	Delivered int64 `json:"delivered"`
+	Dropped   int64 `json:"dropped"`
+	Queued    int   `json:"queued"` // Waiting right now
+}
+
+// dispatcher queues messages for one handler and delivers them on its own
+// goroutine
+type dispatcher struct {
+	handler   MessageHandler
+	policy    DispatchPolicy
+	queue     chan interface{} // DropOldest and DropNewest
+	mu        sync.Mutex       // Guards latest and codes
+	latest    map[string]interface{}
+	codes     []string      // Of latest, in arrival order
+	ready     chan struct{} // Signals latest has messages
+	delivered atomic.Int64
+	dropped   atomic.Int64
+}
+
+func newDispatcher(handler MessageHandler, policy DispatchPolicy) *dispatcher {
+	d := &dispatcher{handler: handler, policy: policy}
+	if policy.Drop == KeepLatest {
+		d.latest = make(map[string]interface{})
+		d.ready = make(chan struct{}, 1)
+	} else {
+		d.queue = make(chan interface{}, max(policy.Buffer, 1))
+	}
+	return d
+}
+
+// dispatch queues a message without blocking, dropping one if the handler
+// is behind
+func (d *dispatcher) dispatch(msg interface{}) {
+	switch d.policy.Drop {
+	case KeepLatest:
+		code := messageCode(msg)
+		d.mu.Lock()
+		if _, waiting := d.latest[code]; waiting {
+			d.dropped.Add(1)
+		} else {
+			d.codes = append(d.codes, code)
+		}
+		d.latest[code] = msg
+		d.mu.Unlock()
+
+		select {
+		case d.ready <- struct{}{}:
+		default:
+		}
+
+	case DropNewest:
+		select {
+		case d.queue <- msg:
+		default:
+			d.dropped.Add(1)
+		}
+
+	default:
+		for {
+			select {
+			case d.queue <- msg:
+				return
+			default:
+			}
+			select {
+			case <-d.queue:
+				d.dropped.Add(1)
+			default:
+			}
+		}
+	}
+}
+
+// run delivers queued messages until ctx is done
+func (d *dispatcher) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg := <-d.queue:
+			d.deliver(msg)
+		case <-d.ready:
+			d.mu.Lock()
+			latest, codes := d.latest, d.codes
+			d.latest, d.codes = make(map[string]interface{}), nil
+			d.mu.Unlock()
+
+			for _, code := range codes {
+				d.deliver(latest[code])
+			}
+		}
+	}
+}
+
+// deliver hands a message to the handler. A handler that panics on a message
+// misses it but keeps receiving the next ones.
+func (d *dispatcher) deliver(msg interface{}) {
+	recovery.Do("websocket.handler", func() { d.handler(msg) })
+	d.delivered.Add(1)
+}
+
+func (d *dispatcher) stats() DispatchStats {
+	d.mu.Lock()
+	queued := len(d.latest)
+	d.mu.Unlock()
+	if d.queue != nil {
+		queued = len(d.queue)
+	}
+	return DispatchStats{Delivered: d.delivered.Load(), Dropped: d.dropped.Load(), Queued: queued}
+}
+
+// messageCode returns the market of a message
+func messageCode(msg interface{}) string {
+	switch m := msg.(type) {
+	case TickerMessage:
+		return m.Code
+	case TradeMessage:
+		return m.Code
+	case OrderbookMessage:
+		return m.Code
+	}
+	return ""
+}
@@ -0,0 +1,96 @@
+package websocket
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockedHandler records messages, blocking on the first until released
+type blockedHandler struct {
+	release chan struct{}
+	mu      sync.Mutex
+	got     []interface{}
+}
+
+func newBlockedHandler() *blockedHandler {
+	return &blockedHandler{release: make(chan struct{})}
+}
+
+func (h *blockedHandler) handle(msg interface{}) error {
+	<-h.release
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.got = append(h.got, msg)
+	return nil
+}
+
+func (h *blockedHandler) received() []interface{} {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]interface{}(nil), h.got...)
+}
+
+func TestDispatcher_KeepLatestCoalescesPerMarket(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	h := newBlockedHandler()
+	d := newDispatcher(h.handle, DispatchPolicy{Drop: KeepLatest})
+	go d.run(ctx)
+
+	d.dispatch(TickerMessage{Code: "KRW-BTC", TradePrice: 1})
+	require.Eventually(t, func() bool { return d.stats().Queued == 0 }, time.Second, time.Millisecond, "handler picked up the first")
+
+	// While the handler is stuck, tickers replace the waiting one per market
+	d.dispatch(TickerMessage{Code: "KRW-BTC", TradePrice: 2})
+	d.dispatch(TickerMessage{Code: "KRW-ETH", TradePrice: 10})
+	d.dispatch(TickerMessage{Code: "KRW-BTC", TradePrice: 3})
+	assert.Equal(t, DispatchStats{Dropped: 1, Queued: 2}, d.stats())
+
+	close(h.release)
+	require.Eventually(t, func() bool { return len(h.received()) == 3 }, time.Second, time.Millisecond)
+	assert.Equal(t, []interface{}{
+		TickerMessage{Code: "KRW-BTC", TradePrice: 1},
+		TickerMessage{Code: "KRW-BTC", TradePrice: 3},
+		TickerMessage{Code: "KRW-ETH", TradePrice: 10},
+	}, h.received())
+	assert.Equal(t, DispatchStats{Delivered: 3, Dropped: 1}, d.stats())
+}
+
+func TestDispatcher_DropPolicies(t *testing.T) {
+	tests := []struct {
+		drop DropPolicy
+		want []int64
+	}{
+		{DropOldest, []int64{1, 3, 4}},
+		{DropNewest, []int64{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.drop), func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			h := newBlockedHandler()
+			d := newDispatcher(h.handle, DispatchPolicy{Drop: tt.drop, Buffer: 2})
+			go d.run(ctx)
+
+			d.dispatch(TradeMessage{SequentialID: 1})
+			require.Eventually(t, func() bool { return d.stats().Queued == 0 }, time.Second, time.Millisecond)
+			for id := int64(2); id <= 4; id++ {
+				d.dispatch(TradeMessage{SequentialID: id})
+			}
+			assert.Equal(t, DispatchStats{Dropped: 1, Queued: 2}, d.stats())
+
+			close(h.release)
+			require.Eventually(t, func() bool { return len(h.received()) == 3 }, time.Second, time.Millisecond)
+			var ids []int64
+			for _, msg := range h.received() {
+				ids = append(ids, msg.(TradeMessage).SequentialID)
+			}
+			assert.Equal(t, tt.want, ids)
+		})
+	}
+}
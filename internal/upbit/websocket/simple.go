@@ -0,0 +1,180 @@
+package websocket
+
+import (
+	"encoding/json"
+
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+// Format is the field naming of the messages Upbit sends
+type Format string
+
+const (
+	// FormatDefault sends full field names, e.g. "trade_price"
+	FormatDefault Format = "DEFAULT"
+	// FormatSimple sends abbreviated field names, e.g. "tp", which cuts the
+	// size of high-volume streams roughly in half
+	FormatSimple Format = "SIMPLE"
+)
+
+// simpleTicker is a ticker message in the SIMPLE format
+type simpleTicker struct {
+	Type               string  `json:"ty"`
+	Code               string  `json:"cd"`
+	OpeningPrice       float64 `json:"op"`
+	HighPrice          float64 `json:"hp"`
+	LowPrice           float64 `json:"lp"`
+	TradePrice         float64 `json:"tp"`
+	PrevClosingPrice   float64 `json:"pcp"`
+	Change             string  `json:"c"`
+	ChangePrice        float64 `json:"cp"`
+	SignedChangePrice  float64 `json:"scp"`
+	ChangeRate         float64 `json:"cr"`
+	SignedChangeRate   float64 `json:"scr"`
+	TradeVolume        float64 `json:"tv"`
+	AccTradeVolume     float64 `json:"atv"`
+	AccTradeVolume24h  float64 `json:"atv24h"`
+	AccTradePrice      float64 `json:"atp"`
+	AccTradePrice24h   float64 `json:"atp24h"`
+	TradeDate          string  `json:"tdt"`
+	TradeTime          string  `json:"ttm"`
+	TradeTimestamp     int64   `json:"ttms"`
+	AskBid             string  `json:"ab"`
+	AccAskVolume       float64 `json:"aav"`
+	AccBidVolume       float64 `json:"abv"`
+	Highest52WeekPrice float64 `json:"h52wp"`
+	Highest52WeekDate  string  `json:"h52wdt"`
+	Lowest52WeekPrice  float64 `json:"l52wp"`
+	Lowest52WeekDate   string  `json:"l52wdt"`
+	Timestamp          int64   `json:"tms"`
+	StreamType         string  `json:"st"`
+}
+
+func (m *simpleTicker) message() TickerMessage {
+	return TickerMessage{
+		Type:               m.Type,
+		Code:               m.Code,
+		OpeningPrice:       m.OpeningPrice,
+		HighPrice:          m.HighPrice,
+		LowPrice:           m.LowPrice,
+		TradePrice:         m.TradePrice,
+		PrevClosingPrice:   m.PrevClosingPrice,
+		Change:             m.Change,
+		ChangePrice:        m.ChangePrice,
+		SignedChangePrice:  m.SignedChangePrice,
+		ChangeRate:         m.ChangeRate,
+		SignedChangeRate:   m.SignedChangeRate,
+		TradeVolume:        m.TradeVolume,
+		AccTradeVolume:     m.AccTradeVolume,
+		AccTradeVolume24h:  m.AccTradeVolume24h,
+		AccTradePrice:      m.AccTradePrice,
+		AccTradePrice24h:   m.AccTradePrice24h,
+		TradeDate:          m.TradeDate,
+		TradeTime:          m.TradeTime,
+		TradeTimestamp:     m.TradeTimestamp,
+		AskBid:             m.AskBid,
+		AccAskVolume:       m.AccAskVolume,
+		AccBidVolume:       m.AccBidVolume,
+		Highest52WeekPrice: m.Highest52WeekPrice,
+		Highest52WeekDate:  m.Highest52WeekDate,
+		Lowest52WeekPrice:  m.Lowest52WeekPrice,
+		Lowest52WeekDate:   m.Lowest52WeekDate,
+		Timestamp:          m.Timestamp,
+		StreamType:         m.StreamType,
+	}
+}
+
+// simpleTrade is a trade message in the SIMPLE format
+type simpleTrade struct {
+	Type             string  `json:"ty"`
+	Code             string  `json:"cd"`
+	TradePrice       float64 `json:"tp"`
+	TradeVolume      float64 `json:"tv"`
+	AskBid           string  `json:"ab"`
+	PrevClosingPrice float64 `json:"pcp"`
+	Change           string  `json:"c"`
+	ChangePrice      float64 `json:"cp"`
+	TradeDate        string  `json:"td"`
+	TradeTime        string  `json:"ttm"`
+	TradeTimestamp   int64   `json:"ttms"`
+	Timestamp        int64   `json:"tms"`
+	SequentialID     int64   `json:"sid"`
+	StreamType       string  `json:"st"`
+}
+
+func (m *simpleTrade) message() TradeMessage {
+	return TradeMessage{
+		Type:             m.Type,
+		Code:             m.Code,
+		TradePrice:       m.TradePrice,
+		TradeVolume:      m.TradeVolume,
+		AskBid:           m.AskBid,
+		PrevClosingPrice: m.PrevClosingPrice,
+		Change:           m.Change,
+		ChangePrice:      m.ChangePrice,
+		TradeDate:        m.TradeDate,
+		TradeTime:        m.TradeTime,
+		TradeTimestamp:   m.TradeTimestamp,
+		Timestamp:        m.Timestamp,
+		SequentialID:     m.SequentialID,
+		StreamType:       m.StreamType,
+	}
+}
+
+// simpleOrderbook is an orderbook message in the SIMPLE format
+type simpleOrderbook struct {
+	Type         string  `json:"ty"`
+	Code         string  `json:"cd"`
+	TotalAskSize float64 `json:"tas"`
+	TotalBidSize float64 `json:"tbs"`
+	Units        []struct {
+		AskPrice float64 `json:"ap"`
+		BidPrice float64 `json:"bp"`
+		AskSize  float64 `json:"as"`
+		BidSize  float64 `json:"bs"`
+	} `json:"obu"`
+	Timestamp  int64  `json:"tms"`
+	StreamType string `json:"st"`
+}
+
+func (m *simpleOrderbook) message() OrderbookMessage {
+	units := make([]model.OrderbookUnit, len(m.Units))
+	for i, u := range m.Units {
+		units[i] = model.OrderbookUnit{AskPrice: u.AskPrice, BidPrice: u.BidPrice, AskSize: u.AskSize, BidSize: u.BidSize}
+	}
+	return OrderbookMessage{
+		Type:           m.Type,
+		Code:           m.Code,
+		TotalAskSize:   m.TotalAskSize,
+		TotalBidSize:   m.TotalBidSize,
+		OrderbookUnits: units,
+		Timestamp:      m.Timestamp,
+		StreamType:     m.StreamType,
+	}
+}
+
+// decodeSimple decodes a SIMPLE format message of the given type into the
+// message struct of the DEFAULT format
+func decodeSimple(msgType MessageType, data []byte) (interface{}, error) {
+	switch msgType {
+	case MessageTypeTicker:
+		var msg simpleTicker
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return nil, err
+		}
+		return msg.message(), nil
+	case MessageTypeTrade:
+		var msg simpleTrade
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return nil, err
+		}
+		return msg.message(), nil
+	case MessageTypeOrderbook:
+		var msg simpleOrderbook
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return nil, err
+		}
+		return msg.message(), nil
+	}
+	return nil, nil
+}
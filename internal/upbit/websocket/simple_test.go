@@ -0,0 +1,33 @@
+package websocket
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/sungminna/upbit-trading-platform/internal/domain/model"
+)
+
+func TestDecodeSimple(t *testing.T) {
+	msg, err := decodeSimple(MessageTypeOrderbook, []byte(`{"ty":"orderbook","cd":"KRW-BTC","tas":1.5,"tbs":2.5,
+		"obu":[{"ap":100100000,"bp":100000000,"as":0.5,"bs":0.7}],"tms":1735787045123,"st":"SNAPSHOT"}`))
+	require.NoError(t, err)
+	assert.Equal(t, OrderbookMessage{
+		Type:           "orderbook",
+		Code:           "KRW-BTC",
+		TotalAskSize:   1.5,
+		TotalBidSize:   2.5,
+		OrderbookUnits: []model.OrderbookUnit{{AskPrice: 100100000, BidPrice: 100000000, AskSize: 0.5, BidSize: 0.7}},
+		Timestamp:      1735787045123,
+		StreamType:     "SNAPSHOT",
+	}, msg)
+
+	msg, err = decodeSimple(MessageTypeTicker, []byte(`{"ty":"ticker","cd":"KRW-ETH","tp":5000000,"scr":-0.012,"atp24h":123456789.5,"tdt":"20250102"}`))
+	require.NoError(t, err)
+	ticker := msg.(TickerMessage)
+	assert.Equal(t, "KRW-ETH", ticker.Code)
+	assert.Equal(t, 5000000.0, ticker.TradePrice)
+	assert.Equal(t, -0.012, ticker.SignedChangeRate)
+	assert.Equal(t, 123456789.5, ticker.AccTradePrice24h)
+	assert.Equal(t, "20250102", ticker.TradeDate)
+}
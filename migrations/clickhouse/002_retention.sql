@@ -0,0 +1,68 @@
+-- Monthly partitions and default retention for market data.
+-- The server re-applies retention from its configuration on startup
+-- (see CLICKHOUSE_*_RETENTION); the TTLs below are the defaults.
+
+-- Candles are already partitioned by month; expire fine-grained intervals sooner
+ALTER TABLE candles MODIFY TTL
+    timestamp + INTERVAL 30 DAY DELETE WHERE interval = '1m',
+    timestamp + INTERVAL 90 DAY DELETE WHERE interval IN ('3m', '5m'),
+    timestamp + INTERVAL 180 DAY DELETE WHERE interval IN ('15m', '30m'),
+    timestamp + INTERVAL 730 DAY DELETE WHERE interval IN ('1h', '4h');
+
+-- The partition key can't be altered in place, so tick-level tables are
+-- rebuilt with monthly partitions and swapped in
+
+CREATE TABLE IF NOT EXISTS ticks_monthly AS ticks
+ENGINE = MergeTree()
+PARTITION BY toYYYYMM(toDateTime(intDiv(timestamp, 1000)))
+ORDER BY (market, timestamp, sequential_id)
+TTL toDateTime(intDiv(timestamp, 1000)) + INTERVAL 7 DAY
+SETTINGS index_granularity = 8192;
+
+INSERT INTO ticks_monthly SELECT * FROM ticks;
+EXCHANGE TABLES ticks AND ticks_monthly;
+DROP TABLE ticks_monthly;
+
+CREATE TABLE IF NOT EXISTS orderbook_snapshots_monthly AS orderbook_snapshots
+ENGINE = MergeTree()
+PARTITION BY toYYYYMM(toDateTime(intDiv(timestamp, 1000)))
+ORDER BY (market, timestamp)
+TTL toDateTime(intDiv(timestamp, 1000)) + INTERVAL 3 DAY
+SETTINGS index_granularity = 8192;
+
+INSERT INTO orderbook_snapshots_monthly SELECT * FROM orderbook_snapshots;
+EXCHANGE TABLES orderbook_snapshots AND orderbook_snapshots_monthly;
+DROP TABLE orderbook_snapshots_monthly;
+
+CREATE TABLE IF NOT EXISTS tickers_monthly AS tickers
+ENGINE = MergeTree()
+PARTITION BY toYYYYMM(toDateTime(intDiv(timestamp, 1000)))
+ORDER BY (market, timestamp)
+TTL toDateTime(intDiv(timestamp, 1000)) + INTERVAL 30 DAY
+SETTINGS index_granularity = 8192;
+
+INSERT INTO tickers_monthly SELECT * FROM tickers;
+EXCHANGE TABLES tickers AND tickers_monthly;
+DROP TABLE tickers_monthly;
+
+-- Recreate the 1-minute view against the rebuilt ticks table, backfilling
+-- from the retained ticks
+DROP VIEW IF EXISTS candles_1m_mv;
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS candles_1m_mv
+ENGINE = MergeTree()
+PARTITION BY toYYYYMM(candle_time)
+ORDER BY (market, candle_time)
+TTL candle_time + INTERVAL 30 DAY
+POPULATE
+AS SELECT
+    market,
+    toStartOfMinute(toDateTime(timestamp / 1000)) as candle_time,
+    argMin(trade_price, timestamp) as opening_price,
+    max(trade_price) as high_price,
+    min(trade_price) as low_price,
+    argMax(trade_price, timestamp) as trade_price,
+    sum(trade_volume) as volume,
+    sum(trade_price * trade_volume) as acc_trade_price
+FROM ticks
+GROUP BY market, candle_time;
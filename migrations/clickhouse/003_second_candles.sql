@@ -0,0 +1,9 @@
+-- 1-second candles are stored in the candles table with interval = '1s'.
+-- They are the most numerous rows by far, so they expire first; the rest of
+-- the TTL restates 002_retention.sql.
+ALTER TABLE candles MODIFY TTL
+    timestamp + INTERVAL 7 DAY DELETE WHERE interval = '1s',
+    timestamp + INTERVAL 30 DAY DELETE WHERE interval = '1m',
+    timestamp + INTERVAL 90 DAY DELETE WHERE interval IN ('3m', '5m'),
+    timestamp + INTERVAL 180 DAY DELETE WHERE interval IN ('15m', '30m'),
+    timestamp + INTERVAL 730 DAY DELETE WHERE interval IN ('1h', '4h');
@@ -0,0 +1,12 @@
+-- Raw Upbit websocket messages, recorded for bug reproduction and replay.
+-- Payloads are JSON frames that compress well, so they get a stronger codec.
+CREATE TABLE IF NOT EXISTS websocket_messages (
+    received_at DateTime64(3, 'UTC'),
+    type LowCardinality(String),
+    market LowCardinality(String),
+    payload String CODEC(ZSTD(3))
+) ENGINE = MergeTree()
+PARTITION BY toYYYYMMDD(received_at)
+ORDER BY (market, received_at)
+TTL toDateTime(received_at) + INTERVAL 3 DAY
+SETTINGS index_granularity = 8192;
@@ -0,0 +1,29 @@
+-- Cross-instance cache invalidation. Every instance LISTENs on the
+-- "invalidation" channel; changes to API keys and strategies are announced
+-- by triggers so no code path can forget to notify.
+
+CREATE OR REPLACE FUNCTION notify_invalidation()
+RETURNS TRIGGER AS $$
+DECLARE
+    rec RECORD;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        rec := OLD;
+    ELSE
+        rec := NEW;
+    END IF;
+
+    PERFORM pg_notify('invalidation', json_build_object(
+        'kind', TG_ARGV[0],
+        'id', rec.id,
+        'user_id', rec.user_id
+    )::text);
+    RETURN rec;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER notify_user_api_keys_invalidation AFTER INSERT OR UPDATE OR DELETE ON user_api_keys
+    FOR EACH ROW EXECUTE FUNCTION notify_invalidation('api_key');
+
+CREATE TRIGGER notify_trading_strategies_invalidation AFTER UPDATE OR DELETE ON trading_strategies
+    FOR EACH ROW EXECUTE FUNCTION notify_invalidation('strategy');
@@ -0,0 +1,16 @@
+-- Periodic account snapshots powering equity curves and time-weighted returns
+CREATE TABLE account_snapshots (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    period VARCHAR(10) NOT NULL CHECK (period IN ('daily', 'hourly')),
+    taken_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    cash_balance DECIMAL(20, 8) NOT NULL,
+    holdings_value DECIMAL(20, 8) NOT NULL,
+    equity DECIMAL(20, 8) NOT NULL,
+    unrealized_pnl DECIMAL(20, 8) NOT NULL DEFAULT 0,
+    balances JSONB NOT NULL DEFAULT '[]',
+    positions JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    -- One snapshot per user and period; lets several instances run the job
+    UNIQUE (user_id, period, taken_at)
+);
@@ -0,0 +1,34 @@
+-- Stored backtest runs so tuning sessions can be listed and compared later
+CREATE TABLE backtest_runs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    market VARCHAR(20) NOT NULL,
+    candle_interval VARCHAR(10) NOT NULL,
+    strategy VARCHAR(50) NOT NULL,
+    params JSONB NOT NULL DEFAULT '{}',
+    from_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    to_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    config JSONB NOT NULL,
+    initial_capital DECIMAL(20, 8) NOT NULL,
+    final_equity DECIMAL(20, 8) NOT NULL,
+    total_fees DECIMAL(20, 8) NOT NULL DEFAULT 0,
+    slippage DECIMAL(20, 8) NOT NULL DEFAULT 0,
+    metrics JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_backtest_runs_user_market ON backtest_runs(user_id, market, created_at DESC);
+
+CREATE TABLE backtest_trades (
+    id BIGSERIAL PRIMARY KEY,
+    run_id UUID NOT NULL REFERENCES backtest_runs(id) ON DELETE CASCADE,
+    entry_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    exit_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    entry_price DECIMAL(20, 8) NOT NULL,
+    exit_price DECIMAL(20, 8) NOT NULL,
+    quantity DECIMAL(20, 8) NOT NULL,
+    fees DECIMAL(20, 8) NOT NULL DEFAULT 0,
+    pnl DECIMAL(20, 8) NOT NULL
+);
+
+CREATE INDEX idx_backtest_trades_run_id ON backtest_trades(run_id, entry_time);
@@ -0,0 +1,17 @@
+-- Price alerts evaluated against the shared price feed
+CREATE TABLE price_alerts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    market VARCHAR(20) NOT NULL,
+    condition VARCHAR(10) NOT NULL CHECK (condition IN ('above', 'below')),
+    price DECIMAL(20, 8) NOT NULL CHECK (price > 0),
+    mode VARCHAR(10) NOT NULL CHECK (mode IN ('once', 'recurring')),
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    trigger_count INTEGER NOT NULL DEFAULT 0,
+    last_triggered_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_price_alerts_user_id ON price_alerts(user_id, created_at DESC);
+CREATE INDEX idx_price_alerts_active ON price_alerts(market) WHERE active;
@@ -0,0 +1,7 @@
+-- Telegram chats linked to user accounts for bot commands and notifications
+CREATE TABLE telegram_links (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    chat_id BIGINT NOT NULL UNIQUE,
+    username VARCHAR(64) NOT NULL DEFAULT '',
+    linked_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
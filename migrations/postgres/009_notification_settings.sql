@@ -0,0 +1,11 @@
+-- Per-user notification preferences, e.g. the daily PnL summary
+CREATE TABLE notification_settings (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    daily_summary BOOLEAN NOT NULL DEFAULT FALSE,
+    summary_channel VARCHAR(32) NOT NULL DEFAULT '',
+    summary_time VARCHAR(5) NOT NULL DEFAULT '09:00',
+    timezone VARCHAR(64) NOT NULL DEFAULT 'Asia/Seoul',
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_notification_settings_daily_summary ON notification_settings(user_id) WHERE daily_summary;
@@ -0,0 +1,10 @@
+-- Per-user pre-trade risk limits; zero disables a limit
+CREATE TABLE risk_limits (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    max_order_notional DECIMAL(20, 8) NOT NULL DEFAULT 0,
+    max_open_positions INTEGER NOT NULL DEFAULT 0,
+    max_market_exposure DECIMAL(20, 8) NOT NULL DEFAULT 0,
+    max_total_exposure DECIMAL(20, 8) NOT NULL DEFAULT 0,
+    downsize BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
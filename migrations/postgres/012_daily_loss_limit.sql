@@ -0,0 +1,18 @@
+-- Daily loss limit with a configurable trading day boundary
+ALTER TABLE risk_limits
+    ADD COLUMN daily_loss_limit DECIMAL(20, 8) NOT NULL DEFAULT 0,
+    ADD COLUMN flatten_on_loss BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN day_start VARCHAR(5) NOT NULL DEFAULT '09:00',
+    ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT 'Asia/Seoul';
+
+CREATE INDEX idx_risk_limits_daily_loss ON risk_limits(user_id) WHERE daily_loss_limit > 0;
+
+-- PnL of each user's current trading day
+CREATE TABLE risk_state (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    day TIMESTAMP WITH TIME ZONE NOT NULL,
+    start_pnl DECIMAL(20, 8) NOT NULL DEFAULT 0,
+    day_pnl DECIMAL(20, 8) NOT NULL DEFAULT 0,
+    halted_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
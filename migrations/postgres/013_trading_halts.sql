@@ -0,0 +1,8 @@
+-- Kill switches: a row per halted user, plus the nil UUID for a global halt.
+-- Halts stay until they are explicitly lifted.
+CREATE TABLE trading_halts (
+    user_id UUID PRIMARY KEY,
+    reason TEXT NOT NULL DEFAULT '',
+    halted_by VARCHAR(16) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,3 @@
+-- Maximum share of equity held in a single market, in percent
+ALTER TABLE risk_limits
+    ADD COLUMN max_concentration DECIMAL(20, 8) NOT NULL DEFAULT 0;
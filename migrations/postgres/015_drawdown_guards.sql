@@ -0,0 +1,17 @@
+-- Drawdown guards force-exit a position that falls too far below its peak
+CREATE TABLE drawdown_guards (
+    position_id UUID PRIMARY KEY REFERENCES positions(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    market VARCHAR(20) NOT NULL,
+    max_drawdown DECIMAL(20, 8) NOT NULL CHECK (max_drawdown > 0 AND max_drawdown < 100),
+    peak_price DECIMAL(20, 8) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    triggered_at TIMESTAMP WITH TIME ZONE,
+    trigger_price DECIMAL(20, 8),
+    exit_order_id UUID REFERENCES orders(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_drawdown_guards_user_id ON drawdown_guards(user_id, created_at DESC);
+CREATE INDEX idx_drawdown_guards_active ON drawdown_guards(market) WHERE active;
@@ -0,0 +1,3 @@
+-- Drawdown guards ignore prices older than max_price_age seconds
+ALTER TABLE drawdown_guards
+    ADD COLUMN max_price_age INTEGER NOT NULL DEFAULT 30 CHECK (max_price_age > 0);
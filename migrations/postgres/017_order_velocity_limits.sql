@@ -0,0 +1,9 @@
+-- Admin overrides of the platform's order velocity limits, a row per user.
+-- 0 leaves a window unlimited.
+CREATE TABLE order_velocity_limits (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    per_minute INTEGER NOT NULL DEFAULT 0 CHECK (per_minute >= 0),
+    per_hour INTEGER NOT NULL DEFAULT 0 CHECK (per_hour >= 0),
+    reason TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,8 @@
+-- Lot tracking for positions. accounting_method decides which lots a sale
+-- consumes (average, fifo or lifo) and is fixed when the position opens.
+-- Positions opened before this migration have no lots and are treated as a
+-- single lot at their average entry price.
+ALTER TABLE positions
+    ADD COLUMN accounting_method VARCHAR(16) NOT NULL DEFAULT 'average'
+        CHECK (accounting_method IN ('average', 'fifo', 'lifo')),
+    ADD COLUMN lots JSONB NOT NULL DEFAULT '[]';
@@ -0,0 +1,17 @@
+-- Target allocations users want their accounts rebalanced to, a row per user.
+-- weights is a JSON array of {"currency", "percent"} summing to 100; KRW is
+-- cash. drift_threshold (percentage points) and interval_hours trigger
+-- automatic rebalances; 0 disables either.
+CREATE TABLE target_portfolios (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    weights JSONB NOT NULL,
+    drift_threshold DECIMAL(6, 2) NOT NULL DEFAULT 0 CHECK (drift_threshold >= 0),
+    interval_hours INTEGER NOT NULL DEFAULT 0 CHECK (interval_hours >= 0),
+    enabled BOOLEAN NOT NULL DEFAULT false,
+    pending_buys BOOLEAN NOT NULL DEFAULT false,
+    last_rebalanced_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_target_portfolios_enabled ON target_portfolios(enabled) WHERE enabled;
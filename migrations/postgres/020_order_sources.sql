@@ -0,0 +1,9 @@
+-- What placed each order, so PnL can be attributed to the user or the
+-- automation responsible. source_id identifies the automation instance where
+-- there can be several, e.g. the position a drawdown guard protects. Orders
+-- placed before this migration are attributed to the user.
+ALTER TABLE orders
+    ADD COLUMN source VARCHAR(32) NOT NULL DEFAULT 'user',
+    ADD COLUMN source_id UUID;
+
+CREATE INDEX idx_orders_user_source ON orders(user_id, source);
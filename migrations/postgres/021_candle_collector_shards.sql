@@ -0,0 +1,18 @@
+-- Coordination table of the sharded candle collector, a row per candle
+-- interval and shard. Markets are spread over shards by hash; a worker
+-- collects a shard while it holds the lease (owner, lease_until) and records
+-- the progress of each pass.
+CREATE TABLE candle_collector_shards (
+    candle_interval VARCHAR(10) NOT NULL,
+    shard INTEGER NOT NULL CHECK (shard >= 0),
+    shards INTEGER NOT NULL CHECK (shards > shard),
+    owner VARCHAR(255) NOT NULL DEFAULT '',
+    lease_until TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT 'epoch',
+    markets INTEGER NOT NULL DEFAULT 0,
+    candles INTEGER NOT NULL DEFAULT 0,
+    failures INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    last_collected_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (candle_interval, shard)
+);
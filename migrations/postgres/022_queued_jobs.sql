@@ -0,0 +1,20 @@
+-- Durable job queue for asynchronous work. Workers claim due jobs with SKIP
+-- LOCKED; while a job runs, run_at is its lease, and a running job whose lease
+-- expired is claimed again with interrupted set.
+CREATE TABLE queued_jobs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    kind VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('pending', 'running', 'succeeded', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL CHECK (max_attempts > 0),
+    interrupted BOOLEAN NOT NULL DEFAULT false,
+    last_error TEXT NOT NULL DEFAULT '',
+    run_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    finished_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_queued_jobs_due ON queued_jobs(run_at) WHERE status IN ('pending', 'running');
+CREATE INDEX idx_queued_jobs_kind ON queued_jobs(kind, status, created_at DESC);
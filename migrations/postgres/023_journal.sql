@@ -0,0 +1,12 @@
+-- Users' journal of their positions and orders: free-form tags to filter and
+-- group reports by, e.g. "swing" or "bot-x", and notes
+ALTER TABLE positions
+    ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN notes TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE orders
+    ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN notes TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX idx_positions_tags ON positions USING GIN (tags);
+CREATE INDEX idx_orders_tags ON orders USING GIN (tags);
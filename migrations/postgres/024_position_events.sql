@@ -0,0 +1,20 @@
+-- Lifecycle of positions: every fill that opens, increases, reduces or closes
+-- a position, and the first fill of each strategy trading it, with the
+-- position's state right after. Positions opened before this migration have
+-- no history.
+CREATE TABLE position_events (
+    id UUID PRIMARY KEY,
+    position_id UUID NOT NULL REFERENCES positions(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL,
+    order_id UUID REFERENCES orders(id) ON DELETE SET NULL,
+    source VARCHAR(32) NOT NULL DEFAULT 'user',
+    source_id UUID,
+    price DECIMAL(20, 8) NOT NULL,
+    quantity DECIMAL(20, 8) NOT NULL,
+    position_quantity DECIMAL(20, 8) NOT NULL,
+    entry_price DECIMAL(20, 8) NOT NULL,
+    realized_pnl DECIMAL(20, 8) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_position_events_position_id ON position_events(position_id, created_at);
@@ -0,0 +1,11 @@
+-- Progress of the candle collector's historical backfills, a row per market
+-- and interval. Candles from from_time up to cursor are stored, so an
+-- interrupted backfill resumes at the cursor instead of starting over.
+CREATE TABLE candle_backfills (
+    market VARCHAR(20) NOT NULL,
+    candle_interval VARCHAR(10) NOT NULL,
+    from_time TIMESTAMP WITH TIME ZONE NOT NULL,
+    cursor TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (market, candle_interval)
+);
@@ -0,0 +1,4 @@
+-- Drawdown guards with a confirm interval only trigger on the close of a
+-- candle of that interval; empty acts on every price
+ALTER TABLE drawdown_guards
+    ADD COLUMN confirm_interval VARCHAR(10) NOT NULL DEFAULT '';
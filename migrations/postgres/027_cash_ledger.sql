@@ -0,0 +1,16 @@
+-- KRW cash movements derived from exchange transfers and platform executions,
+-- so a user's cash can be reconciled with their exchange balance
+CREATE TABLE cash_ledger_entries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    entry_type VARCHAR(20) NOT NULL CHECK (entry_type IN ('opening_balance', 'deposit', 'withdrawal', 'fee', 'trade_settlement')),
+    amount DECIMAL(20, 8) NOT NULL,
+    reference VARCHAR(100) NOT NULL,
+    order_id UUID REFERENCES orders(id) ON DELETE SET NULL,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    -- Entries are derived repeatedly; each source is recorded once
+    UNIQUE (user_id, entry_type, reference)
+);
+
+CREATE INDEX idx_cash_ledger_entries_user_occurred ON cash_ledger_entries(user_id, occurred_at DESC);
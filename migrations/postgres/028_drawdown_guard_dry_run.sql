@@ -0,0 +1,3 @@
+-- Dry run drawdown guards record and notify their triggers without selling
+ALTER TABLE drawdown_guards
+    ADD COLUMN dry_run BOOLEAN NOT NULL DEFAULT FALSE;
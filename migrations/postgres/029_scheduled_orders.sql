@@ -0,0 +1,9 @@
+-- Orders can be scheduled: they are stored as 'scheduled' and submitted by
+-- the engine once activate_at has passed
+ALTER TABLE orders DROP CONSTRAINT orders_status_check;
+ALTER TABLE orders
+    ADD CONSTRAINT orders_status_check
+        CHECK (status IN ('scheduled', 'pending', 'submitted', 'partial', 'filled', 'cancelled', 'failed')),
+    ADD COLUMN activate_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_orders_scheduled ON orders(activate_at) WHERE status = 'scheduled';
@@ -0,0 +1,30 @@
+-- Market orders placed on a schedule, e.g. weekly buys, and their runs
+CREATE TABLE recurring_orders (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    market VARCHAR(20) NOT NULL,
+    side VARCHAR(10) NOT NULL CHECK (side IN ('bid', 'ask')),
+    amount DECIMAL(20, 8) NOT NULL CHECK (amount > 0),
+    schedule VARCHAR(100) NOT NULL,
+    time_zone VARCHAR(64) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    next_run_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_run_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_recurring_orders_user ON recurring_orders(user_id);
+CREATE INDEX idx_recurring_orders_due ON recurring_orders(next_run_at) WHERE active;
+
+CREATE TABLE recurring_order_runs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    recurring_order_id UUID NOT NULL REFERENCES recurring_orders(id) ON DELETE CASCADE,
+    scheduled_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(10) NOT NULL CHECK (status IN ('placed', 'failed', 'skipped')),
+    order_id UUID REFERENCES orders(id) ON DELETE SET NULL,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    -- Claimed by the instance that places the run's order
+    UNIQUE (recurring_order_id, scheduled_at)
+);
@@ -0,0 +1,12 @@
+-- Announced Upbit maintenance, during which orders for the market wait
+CREATE TABLE maintenance_windows (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    market VARCHAR(20) NOT NULL DEFAULT '', -- Empty for every market
+    reason TEXT NOT NULL DEFAULT '',
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX idx_maintenance_windows_ends_at ON maintenance_windows(ends_at);
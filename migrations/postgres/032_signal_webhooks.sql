@@ -0,0 +1,17 @@
+-- Inbound webhooks external signal providers, e.g. TradingView alerts, call
+-- to open and close positions. Only the SHA-256 of each token is stored.
+CREATE TABLE signal_webhooks (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    markets TEXT[] NOT NULL DEFAULT '{}',
+    open_amount DECIMAL(20, 8) NOT NULL CHECK (open_amount > 0),
+    strategies JSONB NOT NULL DEFAULT '[]',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    last_triggered_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_signal_webhooks_user ON signal_webhooks(user_id);
@@ -0,0 +1,58 @@
+-- Signal webhooks become one kind of signal source, next to Telegram channels
+-- and price level indicators. Users trade on a source's signals through
+-- subscriptions with their own order size and risk caps, and every signal
+-- received is logged with what it did.
+ALTER TABLE signal_webhooks RENAME TO signal_sources;
+ALTER INDEX idx_signal_webhooks_user RENAME TO idx_signal_sources_user;
+ALTER TABLE signal_sources RENAME COLUMN last_triggered_at TO last_signal_at;
+ALTER TABLE signal_sources
+    ADD COLUMN type VARCHAR(20) NOT NULL DEFAULT 'webhook' CHECK (type IN ('webhook', 'telegram', 'indicator')),
+    ADD COLUMN telegram_chat_id BIGINT,
+    ADD COLUMN indicator JSONB,
+    ADD COLUMN public BOOLEAN NOT NULL DEFAULT FALSE,
+    ALTER COLUMN token_hash DROP NOT NULL;
+
+CREATE INDEX idx_signal_sources_telegram ON signal_sources(telegram_chat_id) WHERE active AND telegram_chat_id IS NOT NULL;
+CREATE INDEX idx_signal_sources_public ON signal_sources(created_at) WHERE active AND public;
+
+CREATE TABLE signal_subscriptions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source_id UUID NOT NULL REFERENCES signal_sources(id) ON DELETE CASCADE,
+    market VARCHAR(20) NOT NULL DEFAULT '',
+    strategy VARCHAR(100) NOT NULL DEFAULT '',
+    open_amount DECIMAL(20, 8) NOT NULL CHECK (open_amount > 0),
+    max_order_amount DECIMAL(20, 8) NOT NULL DEFAULT 0 CHECK (max_order_amount >= 0),
+    max_position_amount DECIMAL(20, 8) NOT NULL DEFAULT 0 CHECK (max_position_amount >= 0),
+    max_daily_orders INTEGER NOT NULL DEFAULT 0 CHECK (max_daily_orders >= 0),
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_signal_subscriptions_user ON signal_subscriptions(user_id);
+CREATE INDEX idx_signal_subscriptions_source ON signal_subscriptions(source_id) WHERE active;
+
+-- Webhook owners keep trading on their alerts at the amount they chose
+INSERT INTO signal_subscriptions (user_id, source_id, open_amount, active, created_at, updated_at)
+SELECT user_id, id, open_amount, active, created_at, updated_at FROM signal_sources;
+
+ALTER TABLE signal_sources DROP COLUMN open_amount;
+
+CREATE TABLE signal_logs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    source_id UUID NOT NULL REFERENCES signal_sources(id) ON DELETE CASCADE,
+    subscription_id UUID REFERENCES signal_subscriptions(id) ON DELETE SET NULL,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    payload JSONB NOT NULL,
+    action VARCHAR(10) NOT NULL DEFAULT '',
+    market VARCHAR(20) NOT NULL DEFAULT '',
+    strategy VARCHAR(100) NOT NULL DEFAULT '',
+    status VARCHAR(10) NOT NULL CHECK (status IN ('executed', 'rejected', 'failed', 'ignored')),
+    order_ids UUID[] NOT NULL DEFAULT '{}',
+    error TEXT NOT NULL DEFAULT '',
+    received_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_signal_logs_source ON signal_logs(source_id, received_at DESC);
+CREATE INDEX idx_signal_logs_subscription ON signal_logs(subscription_id, received_at DESC) WHERE subscription_id IS NOT NULL;
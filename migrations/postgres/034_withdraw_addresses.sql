@@ -0,0 +1,22 @@
+-- Users' withdrawal address books. Only confirmed addresses past their hold
+-- are whitelisted for withdrawals through the platform.
+CREATE TABLE withdraw_addresses (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    currency VARCHAR(20) NOT NULL,
+    net_type VARCHAR(20) NOT NULL,
+    address VARCHAR(256) NOT NULL,
+    secondary_address VARCHAR(256) NOT NULL DEFAULT '', -- Destination tag or memo
+    label VARCHAR(50) NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'confirmed')),
+    code_hash VARCHAR(64) NOT NULL DEFAULT '',
+    code_expires_at TIMESTAMP WITH TIME ZONE,
+    code_attempts INTEGER NOT NULL DEFAULT 0,
+    confirmed_at TIMESTAMP WITH TIME ZONE,
+    usable_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, currency, net_type, address, secondary_address)
+);
+
+CREATE INDEX idx_withdraw_addresses_user ON withdraw_addresses(user_id, created_at);
@@ -0,0 +1,15 @@
+-- Paper trading accounts. Each is traded through a paper API key of its
+-- user, which coexists with the user's Upbit keys; whichever key is active
+-- decides whether the user trades on paper or live.
+ALTER TABLE user_api_keys ADD COLUMN paper BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE paper_accounts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    access_key VARCHAR(255) NOT NULL UNIQUE,
+    starting_balance DECIMAL(20, 8) NOT NULL,
+    state JSONB NOT NULL, -- Balances and orders as the simulated exchange keeps them
+    reset_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,18 @@
+-- Shared catalog of strategy templates. Templates hold only a strategy's
+-- type and parameters; the publisher is kept for permission checks and never
+-- shown to other users.
+CREATE TABLE strategy_templates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    publisher_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    type VARCHAR(30) NOT NULL,
+    params JSONB NOT NULL DEFAULT '{}',
+    backtest JSONB, -- Stats of a backtest the publisher shared
+    usage_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_strategy_templates_usage ON strategy_templates(usage_count DESC, created_at DESC);
+CREATE INDEX idx_strategy_templates_publisher ON strategy_templates(publisher_id);
@@ -0,0 +1,19 @@
+-- Trading records moved out of the live tables by the retention policy, as
+-- the JSON of the original row
+CREATE TABLE archived_records (
+    kind VARCHAR(20) NOT NULL,
+    id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    data JSONB NOT NULL,
+    archived_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (kind, id)
+);
+
+CREATE INDEX idx_archived_records_user ON archived_records(user_id, archived_at DESC);
+
+-- Finds expired records: cancelled and failed orders without fills, and
+-- triggered drawdown guards
+CREATE INDEX idx_orders_unfilled_final ON orders(updated_at)
+    WHERE status IN ('cancelled', 'failed') AND executed_quantity = 0;
+CREATE INDEX idx_drawdown_guards_triggered ON drawdown_guards(triggered_at)
+    WHERE triggered_at IS NOT NULL;
@@ -0,0 +1,4 @@
+-- When Upbit API keys expire, as reported by Upbit. Keys expire a year after
+-- they are issued; users are reminded before and their trading is halted
+-- once a key lapses.
+ALTER TABLE user_api_keys ADD COLUMN expires_at TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,14 @@
+-- Drawdown guards' trailing state changes, so users can see how the trigger
+-- level moved. Updates go with their guard.
+CREATE TABLE drawdown_guard_updates (
+    id UUID PRIMARY KEY,
+    position_id UUID NOT NULL REFERENCES drawdown_guards(position_id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL,
+    price DECIMAL(20, 8) NOT NULL,
+    peak_price DECIMAL(20, 8) NOT NULL,
+    max_drawdown DECIMAL(20, 8) NOT NULL,
+    trigger_level DECIMAL(20, 8) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_drawdown_guard_updates_position_id ON drawdown_guard_updates(position_id, created_at);
@@ -0,0 +1,14 @@
+-- Orders users place often, saved under a name to place by it
+CREATE TABLE order_presets (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    market VARCHAR(20) NOT NULL,
+    side VARCHAR(10) NOT NULL CHECK (side IN ('bid', 'ask')),
+    type VARCHAR(10) NOT NULL CHECK (type IN ('limit', 'market')),
+    size_rule VARCHAR(10) NOT NULL CHECK (size_rule IN ('quantity', 'amount')),
+    size DECIMAL(20, 8) NOT NULL CHECK (size > 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (user_id, name)
+);
@@ -0,0 +1,9 @@
+-- The strategy that placed an order, for attribution and cleanup. Drawdown
+-- guards cloned from a strategy template place their exits as that
+-- strategy; position events record it with the fill.
+ALTER TABLE drawdown_guards
+    ADD COLUMN template_id UUID REFERENCES strategy_templates(id) ON DELETE SET NULL;
+ALTER TABLE orders ADD COLUMN strategy_id UUID;
+ALTER TABLE position_events ADD COLUMN strategy_id UUID;
+
+CREATE INDEX idx_orders_strategy_id ON orders(strategy_id) WHERE strategy_id IS NOT NULL;
@@ -0,0 +1,4 @@
+-- The engine checks every new order against the user's open orders, the
+-- open sells of its position and the orders placed in the last hour.
+CREATE INDEX idx_orders_user_created_at ON orders(user_id, created_at);
+CREATE INDEX idx_orders_user_open ON orders(user_id) WHERE status IN ('pending', 'submitted', 'partial');
@@ -0,0 +1,7 @@
+-- How a preset's order is executed by default: whole, split into equal
+-- slices placed at once, or as a TWAP placing one slice every slice_interval
+-- seconds
+ALTER TABLE order_presets
+    ADD COLUMN algorithm VARCHAR(10) NOT NULL DEFAULT 'whole' CHECK (algorithm IN ('whole', 'split', 'twap')),
+    ADD COLUMN slices INT NOT NULL DEFAULT 1 CHECK (slices > 0),
+    ADD COLUMN slice_interval INT NOT NULL DEFAULT 0 CHECK (slice_interval >= 0);
@@ -0,0 +1,9 @@
+-- Webhook delivery attempts run as jobs on the durable job queue. Pending
+-- deliveries get the job of their next attempt, and the index the delivery
+-- poller claimed them by is dropped.
+INSERT INTO queued_jobs (kind, payload, status, max_attempts, run_at)
+SELECT 'webhook.deliver', jsonb_build_object('delivery_id', id), 'pending', 5, COALESCE(next_attempt_at, CURRENT_TIMESTAMP)
+FROM webhook_deliveries
+WHERE status = 'pending';
+
+DROP INDEX idx_webhook_deliveries_due;
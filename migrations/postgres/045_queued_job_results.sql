@@ -0,0 +1,12 @@
+-- Jobs users start, such as backtests and exports, belong to them, and their
+-- output is kept as a result to download once the job succeeds.
+ALTER TABLE queued_jobs ADD COLUMN user_id UUID REFERENCES users(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_queued_jobs_user_id ON queued_jobs(user_id, created_at DESC) WHERE user_id IS NOT NULL;
+
+CREATE TABLE queued_job_results (
+    job_id UUID PRIMARY KEY REFERENCES queued_jobs(id) ON DELETE CASCADE,
+    content_type VARCHAR(100) NOT NULL,
+    data BYTEA NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
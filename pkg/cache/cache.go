@@ -0,0 +1,49 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Cache is a key/value store with per-key expiry
+type Cache interface {
+	// Get returns the cached value or ErrCacheMiss
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// SetNX stores the value only if the key doesn't exist yet and reports whether it was stored.
+	// It is the building block for idempotency keys.
+	SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Locker obtains mutually exclusive locks that expire after a TTL
+type Locker interface {
+	// Obtain acquires the lock or returns ErrLockNotObtained if it is held by someone else
+	Obtain(ctx context.Context, key string, ttl time.Duration) (Lock, error)
+}
+
+// Lock is an acquired lock
+type Lock interface {
+	// Release releases the lock if it is still owned by the caller
+	Release(ctx context.Context) error
+}
+
+// Store is a Cache that also hands out locks
+type Store interface {
+	Cache
+	Locker
+}
+
+var (
+	ErrCacheMiss       = &CacheError{message: "cache miss"}
+	ErrLockNotObtained = &CacheError{message: "lock not obtained"}
+)
+
+// CacheError represents a cache error
+type CacheError struct {
+	message string
+}
+
+func (e *CacheError) Error() string {
+	return e.message
+}
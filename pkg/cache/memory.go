@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MemoryCache is an in-process Cache and Locker for single-instance deployments
+type MemoryCache struct {
+	items map[string]memoryItem
+	mu    sync.Mutex
+}
+
+type memoryItem struct {
+	value     []byte
+	expiresAt time.Time // zero means no expiry
+}
+
+func (i memoryItem) expired(now time.Time) bool {
+	return !i.expiresAt.IsZero() && now.After(i.expiresAt)
+}
+
+// NewMemoryCache creates a new in-memory cache
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{
+		items: make(map[string]memoryItem),
+	}
+}
+
+// Get returns the cached value
+func (c *MemoryCache) Get(ctx context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	item, exists := c.items[key]
+	if !exists || item.expired(time.Now()) {
+		delete(c.items, key)
+		return nil, ErrCacheMiss
+	}
+
+	return item.value, nil
+}
+
+// Set stores a value
+func (c *MemoryCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items[key] = newMemoryItem(value, ttl)
+	return nil
+}
+
+// SetNX stores a value if the key doesn't exist
+func (c *MemoryCache) SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if item, exists := c.items[key]; exists && !item.expired(time.Now()) {
+		return false, nil
+	}
+
+	c.items[key] = newMemoryItem(value, ttl)
+	return true, nil
+}
+
+// Delete removes a value
+func (c *MemoryCache) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.items, key)
+	return nil
+}
+
+// Obtain acquires a lock
+func (c *MemoryCache) Obtain(ctx context.Context, key string, ttl time.Duration) (Lock, error) {
+	token := []byte(uuid.New().String())
+
+	ok, err := c.SetNX(ctx, lockKey(key), token, ttl)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrLockNotObtained
+	}
+
+	return &memoryLock{cache: c, key: lockKey(key), token: string(token)}, nil
+}
+
+type memoryLock struct {
+	cache *MemoryCache
+	key   string
+	token string
+}
+
+// Release releases the lock if the token still matches
+func (l *memoryLock) Release(ctx context.Context) error {
+	l.cache.mu.Lock()
+	defer l.cache.mu.Unlock()
+
+	if item, exists := l.cache.items[l.key]; exists && string(item.value) == l.token {
+		delete(l.cache.items, l.key)
+	}
+	return nil
+}
+
+func newMemoryItem(value []byte, ttl time.Duration) memoryItem {
+	item := memoryItem{value: value}
+	if ttl > 0 {
+		item.expiresAt = time.Now().Add(ttl)
+	}
+	return item
+}
+
+func lockKey(key string) string {
+	return "lock:" + key
+}
@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryCache_GetSet(t *testing.T) {
+	c := NewMemoryCache()
+	ctx := context.Background()
+
+	_, err := c.Get(ctx, "missing")
+	assert.ErrorIs(t, err, ErrCacheMiss)
+
+	require.NoError(t, c.Set(ctx, "key", []byte("value"), time.Minute))
+	value, err := c.Get(ctx, "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+
+	require.NoError(t, c.Delete(ctx, "key"))
+	_, err = c.Get(ctx, "key")
+	assert.ErrorIs(t, err, ErrCacheMiss)
+}
+
+func TestMemoryCache_Expiry(t *testing.T) {
+	c := NewMemoryCache()
+	ctx := context.Background()
+
+	require.NoError(t, c.Set(ctx, "key", []byte("value"), 10*time.Millisecond))
+	time.Sleep(20 * time.Millisecond)
+
+	_, err := c.Get(ctx, "key")
+	assert.ErrorIs(t, err, ErrCacheMiss)
+}
+
+func TestMemoryCache_SetNX(t *testing.T) {
+	c := NewMemoryCache()
+	ctx := context.Background()
+
+	ok, err := c.SetNX(ctx, "idempotency", []byte("1"), time.Minute)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = c.SetNX(ctx, "idempotency", []byte("2"), time.Minute)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestMemoryCache_Lock(t *testing.T) {
+	c := NewMemoryCache()
+	ctx := context.Background()
+
+	lock, err := c.Obtain(ctx, "position", time.Minute)
+	require.NoError(t, err)
+
+	_, err = c.Obtain(ctx, "position", time.Minute)
+	assert.ErrorIs(t, err, ErrLockNotObtained)
+
+	require.NoError(t, lock.Release(ctx))
+
+	_, err = c.Obtain(ctx, "position", time.Minute)
+	assert.NoError(t, err)
+}
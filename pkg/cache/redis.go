@@ -0,0 +1,110 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// releaseScript deletes the lock only if it still holds our token
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// RedisCache is a Redis-backed Cache and Locker shared by all instances
+type RedisCache struct {
+	client *redis.Client
+}
+
+// NewRedisCache connects to Redis and verifies connectivity
+func NewRedisCache(ctx context.Context, addr, password string, db int) (*RedisCache, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+		DB:       db,
+	})
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to ping redis: %w", err)
+	}
+
+	return &RedisCache{client: client}, nil
+}
+
+// Get returns the cached value
+func (c *RedisCache) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := c.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrCacheMiss
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", key, err)
+	}
+	return value, nil
+}
+
+// Set stores a value
+func (c *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := c.client.Set(ctx, key, value, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set %s: %w", key, err)
+	}
+	return nil
+}
+
+// SetNX stores a value if the key doesn't exist
+func (c *RedisCache) SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	ok, err := c.client.SetNX(ctx, key, value, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to setnx %s: %w", key, err)
+	}
+	return ok, nil
+}
+
+// Delete removes a value
+func (c *RedisCache) Delete(ctx context.Context, key string) error {
+	if err := c.client.Del(ctx, key).Err(); err != nil {
+		return fmt.Errorf("failed to delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// Obtain acquires a lock using SET NX with a random token
+func (c *RedisCache) Obtain(ctx context.Context, key string, ttl time.Duration) (Lock, error) {
+	token := uuid.New().String()
+
+	ok, err := c.client.SetNX(ctx, lockKey(key), token, ttl).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain lock %s: %w", key, err)
+	}
+	if !ok {
+		return nil, ErrLockNotObtained
+	}
+
+	return &redisLock{client: c.client, key: lockKey(key), token: token}, nil
+}
+
+// Close closes the Redis connection
+func (c *RedisCache) Close() error {
+	return c.client.Close()
+}
+
+type redisLock struct {
+	client *redis.Client
+	key    string
+	token  string
+}
+
+// Release releases the lock if the token still matches
+func (l *redisLock) Release(ctx context.Context) error {
+	if err := releaseScript.Run(ctx, l.client, []string{l.key}, l.token).Err(); err != nil {
+		return fmt.Errorf("failed to release lock %s: %w", l.key, err)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+// Package callstats records the outcomes and latencies of calls to an
+// external HTTP API, by endpoint, to spot a degrading upstream early
+package callstats
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LatencyBucketsMs are the upper bounds of the latency histogram buckets.
+// Slower calls fall in a final overflow bucket.
+var LatencyBucketsMs = []int64{50, 100, 250, 500, 1000, 2500, 5000}
+
+// Recorder counts the calls of one API by endpoint. It is safe for
+// concurrent use.
+type Recorder struct {
+	mu        sync.Mutex
+	endpoints map[string]*endpointStats
+}
+
+type endpointStats struct {
+	calls        int64
+	success      int64
+	clientErrors int64
+	rateLimited  int64
+	serverErrors int64
+	failed       int64
+	totalLatency time.Duration
+	maxLatency   time.Duration
+	buckets      []int64
+	lastError    string
+	lastErrorAt  time.Time
+}
+
+// EndpointSnapshot is a point-in-time copy of an endpoint's call statistics
+type EndpointSnapshot struct {
+	Endpoint     string  `json:"endpoint"`
+	Calls        int64   `json:"calls"`
+	Success      int64   `json:"success"`       // 2xx responses
+	ClientErrors int64   `json:"client_errors"` // 4xx responses other than 429
+	RateLimited  int64   `json:"rate_limited"`  // 429 responses
+	ServerErrors int64   `json:"server_errors"` // 5xx responses
+	Failed       int64   `json:"failed"`        // No response, e.g. timeouts and connection errors
+	ErrorRate    float64 `json:"error_rate"`    // Share of calls that did not succeed
+	AvgLatencyMs int64   `json:"avg_latency_ms"`
+	MaxLatencyMs int64   `json:"max_latency_ms"`
+	// LatencyBuckets counts calls by latency; bucket i holds calls slower than
+	// bucket i-1's bound and no slower than LatencyBucketsMs[i], and the last
+	// bucket holds the rest
+	LatencyBuckets []int64    `json:"latency_buckets"`
+	LastError      string     `json:"last_error,omitempty"`
+	LastErrorAt    *time.Time `json:"last_error_at,omitempty"`
+}
+
+// NewRecorder creates an empty recorder
+func NewRecorder() *Recorder {
+	return &Recorder{endpoints: make(map[string]*endpointStats)}
+}
+
+// Endpoint names a call by method and path. Query strings are dropped so
+// calls to the same resource are counted together.
+func Endpoint(method, path string) string {
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	return method + " " + path
+}
+
+// Record counts a call that got a response with status, or none when err is
+// set
+func (r *Recorder) Record(endpoint string, status int, err error, latency time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, exists := r.endpoints[endpoint]
+	if !exists {
+		s = &endpointStats{buckets: make([]int64, len(LatencyBucketsMs)+1)}
+		r.endpoints[endpoint] = s
+	}
+
+	s.calls++
+	s.totalLatency += latency
+	s.maxLatency = max(s.maxLatency, latency)
+	bucket := sort.Search(len(LatencyBucketsMs), func(i int) bool { return latency <= time.Duration(LatencyBucketsMs[i])*time.Millisecond })
+	s.buckets[bucket]++
+
+	switch {
+	case err != nil:
+		s.failed++
+		s.lastError = err.Error()
+	case status >= 200 && status < 300:
+		s.success++
+		return
+	case status == 429:
+		s.rateLimited++
+		s.lastError = "429 Too Many Requests"
+	case status >= 500:
+		s.serverErrors++
+		s.lastError = "server error " + strconv.Itoa(status)
+	default:
+		s.clientErrors++
+		s.lastError = "client error " + strconv.Itoa(status)
+	}
+	s.lastErrorAt = time.Now()
+}
+
+// Snapshot returns the statistics of every endpoint called, sorted by endpoint
+func (r *Recorder) Snapshot() []EndpointSnapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	snapshots := make([]EndpointSnapshot, 0, len(r.endpoints))
+	for endpoint, s := range r.endpoints {
+		snapshot := EndpointSnapshot{
+			Endpoint:       endpoint,
+			Calls:          s.calls,
+			Success:        s.success,
+			ClientErrors:   s.clientErrors,
+			RateLimited:    s.rateLimited,
+			ServerErrors:   s.serverErrors,
+			Failed:         s.failed,
+			MaxLatencyMs:   s.maxLatency.Milliseconds(),
+			LatencyBuckets: append([]int64(nil), s.buckets...),
+			LastError:      s.lastError,
+		}
+		if s.calls > 0 {
+			snapshot.ErrorRate = float64(s.calls-s.success) / float64(s.calls)
+			snapshot.AvgLatencyMs = (s.totalLatency / time.Duration(s.calls)).Milliseconds()
+		}
+		if !s.lastErrorAt.IsZero() {
+			at := s.lastErrorAt
+			snapshot.LastErrorAt = &at
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Endpoint < snapshots[j].Endpoint })
+	return snapshots
+}
@@ -0,0 +1,45 @@
+package callstats
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpoint(t *testing.T) {
+	assert.Equal(t, "GET /order", Endpoint("GET", "/order?uuid=abc"))
+	assert.Equal(t, "GET /candles/minutes/1", Endpoint("GET", "/candles/minutes/1"))
+}
+
+func TestRecorder(t *testing.T) {
+	r := NewRecorder()
+	orders := Endpoint("POST", "/orders")
+	r.Record(orders, 201, nil, 40*time.Millisecond)
+	r.Record(orders, 429, nil, 80*time.Millisecond)
+	r.Record(orders, 400, nil, 300*time.Millisecond)
+	r.Record(orders, 503, nil, 2*time.Second)
+	r.Record(orders, 0, errors.New("timeout"), 30*time.Second)
+	r.Record(Endpoint("GET", "/accounts"), 200, nil, 10*time.Millisecond)
+
+	snapshots := r.Snapshot()
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, "GET /accounts", snapshots[0].Endpoint)
+	assert.Zero(t, snapshots[0].ErrorRate)
+	assert.Empty(t, snapshots[0].LastError)
+
+	s := snapshots[1]
+	assert.Equal(t, int64(5), s.Calls)
+	assert.Equal(t, int64(1), s.Success)
+	assert.Equal(t, int64(1), s.RateLimited)
+	assert.Equal(t, int64(1), s.ClientErrors)
+	assert.Equal(t, int64(1), s.ServerErrors)
+	assert.Equal(t, int64(1), s.Failed)
+	assert.InDelta(t, 0.8, s.ErrorRate, 1e-9)
+	assert.Equal(t, int64(30000), s.MaxLatencyMs)
+	assert.Equal(t, []int64{1, 1, 0, 1, 0, 1, 0, 1}, s.LatencyBuckets)
+	assert.Equal(t, "timeout", s.LastError)
+	assert.NotNil(t, s.LastErrorAt)
+}
@@ -0,0 +1,93 @@
+package clickhouse
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConn_Query(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) == "SELECT 1" {
+			w.Write([]byte("1\n"))
+			return
+		}
+
+		assert.Equal(t, "upbit", r.Header.Get("X-ClickHouse-User"))
+		assert.Equal(t, "secret", r.Header.Get("X-ClickHouse-Key"))
+		assert.Equal(t, "upbit_trading", r.URL.Query().Get("database"))
+		assert.Equal(t, "KRW-BTC", r.URL.Query().Get("param_market"))
+		assert.Equal(t, "SELECT market, count() AS n FROM ticks WHERE market = {market:String} GROUP BY market FORMAT JSON", string(body))
+
+		w.Write([]byte(`{"meta":[],"data":[{"market":"KRW-BTC","n":42}],"rows":1}`))
+	}))
+	defer server.Close()
+
+	conn, err := NewConn(context.Background(), DefaultConfig("http://upbit:secret@"+server.Listener.Addr().String()+"/upbit_trading"))
+	require.NoError(t, err)
+
+	var rows []struct {
+		Market string `json:"market"`
+		N      uint64 `json:"n"`
+	}
+	err = conn.Query(context.Background(), &rows,
+		"SELECT market, count() AS n FROM ticks WHERE market = {market:String} GROUP BY market",
+		map[string]any{"market": "KRW-BTC"})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "KRW-BTC", rows[0].Market)
+	assert.Equal(t, uint64(42), rows[0].N)
+}
+
+func TestConn_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) == "SELECT 1" {
+			return
+		}
+		http.Error(w, "Code: 60. DB::Exception: Table doesn't exist", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	conn, err := NewConn(context.Background(), DefaultConfig(server.URL))
+	require.NoError(t, err)
+
+	err = conn.Exec(context.Background(), "ALTER TABLE missing MATERIALIZE TTL", nil)
+	var chErr *Error
+	require.ErrorAs(t, err, &chErr)
+	assert.Equal(t, http.StatusNotFound, chErr.StatusCode)
+	assert.Contains(t, chErr.Message, "Table doesn't exist")
+}
+
+func TestNewConn_RejectsNativeDSN(t *testing.T) {
+	_, err := NewConn(context.Background(), DefaultConfig("tcp://localhost:9000?database=upbit_trading"))
+	assert.Error(t, err)
+}
+
+func TestConn_Insert(t *testing.T) {
+	var inserted string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "SELECT 1" {
+			inserted = string(body)
+		}
+	}))
+	defer server.Close()
+
+	conn, err := NewConn(context.Background(), DefaultConfig(server.URL))
+	require.NoError(t, err)
+
+	type row struct {
+		Market string  `json:"market"`
+		Price  float64 `json:"price"`
+	}
+	err = conn.Insert(context.Background(), "ticks", []any{row{"KRW-BTC", 1}, row{"KRW-ETH", 2}})
+	require.NoError(t, err)
+	assert.Equal(t, "INSERT INTO ticks FORMAT JSONEachRow\n{\"market\":\"KRW-BTC\",\"price\":1}\n{\"market\":\"KRW-ETH\",\"price\":2}\n", inserted)
+}
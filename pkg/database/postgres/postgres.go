@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sungminna/upbit-trading-platform/pkg/database"
+)
+
+// Config holds PostgreSQL pool settings
+type Config struct {
+	DSN               string
+	MaxConns          int32
+	MinConns          int32
+	MaxConnLifetime   time.Duration
+	MaxConnIdleTime   time.Duration
+	HealthCheckPeriod time.Duration // How often idle connections are pinged
+	ConnectAttempts   int           // Startup attempts before giving up
+	RetryDelay        time.Duration // Initial delay between startup attempts, doubled each time
+}
+
+// DefaultConfig returns the default pool settings for a DSN
+func DefaultConfig(dsn string) Config {
+	return Config{
+		DSN:               dsn,
+		MaxConns:          20,
+		MinConns:          2,
+		MaxConnLifetime:   1 * time.Hour,
+		MaxConnIdleTime:   30 * time.Minute,
+		HealthCheckPeriod: 1 * time.Minute,
+		ConnectAttempts:   10,
+		RetryDelay:        1 * time.Second,
+	}
+}
+
+// NewPool creates a new PostgreSQL connection pool and waits, with backoff,
+// until the database accepts connections
+func NewPool(ctx context.Context, cfg Config) (*pgxpool.Pool, error) {
+	poolConfig, err := pgxpool.ParseConfig(cfg.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse postgres DSN: %w", err)
+	}
+
+	if cfg.MaxConns > 0 {
+		poolConfig.MaxConns = cfg.MaxConns
+	}
+	if cfg.MinConns > 0 {
+		poolConfig.MinConns = cfg.MinConns
+	}
+	if cfg.MaxConnLifetime > 0 {
+		poolConfig.MaxConnLifetime = cfg.MaxConnLifetime
+	}
+	if cfg.MaxConnIdleTime > 0 {
+		poolConfig.MaxConnIdleTime = cfg.MaxConnIdleTime
+	}
+	if cfg.HealthCheckPeriod > 0 {
+		poolConfig.HealthCheckPeriod = cfg.HealthCheckPeriod
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create postgres pool: %w", err)
+	}
+
+	err = database.Retry(ctx, "postgres", cfg.ConnectAttempts, cfg.RetryDelay, func(ctx context.Context) error {
+		return pool.Ping(ctx)
+	})
+	if err != nil {
+		pool.Close()
+		return nil, err
+	}
+
+	return pool, nil
+}
@@ -0,0 +1,46 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// maxRetryDelay caps the exponential backoff between attempts
+const maxRetryDelay = 30 * time.Second
+
+// Retry calls fn until it succeeds, the attempts are exhausted or ctx is done,
+// doubling the delay between attempts. It lets the server tolerate databases
+// that start after it, as commonly happens with docker-compose.
+func Retry(ctx context.Context, name string, attempts int, delay time.Duration, fn func(ctx context.Context) error) error {
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = fn(ctx); err == nil {
+			return nil
+		}
+
+		if attempt == attempts {
+			break
+		}
+
+		log.Printf("Connecting to %s failed (attempt %d/%d), retrying in %s: %v", name, attempt, attempts, delay, err)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+		if delay > maxRetryDelay {
+			delay = maxRetryDelay
+		}
+	}
+
+	return fmt.Errorf("failed to connect to %s after %d attempts: %w", name, attempts, err)
+}
@@ -0,0 +1,46 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetry_SucceedsAfterFailures(t *testing.T) {
+	calls := 0
+	err := Retry(context.Background(), "test", 5, time.Millisecond, func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return errors.New("not ready")
+		}
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+func TestRetry_GivesUp(t *testing.T) {
+	calls := 0
+	err := Retry(context.Background(), "test", 3, time.Millisecond, func(ctx context.Context) error {
+		calls++
+		return errors.New("not ready")
+	})
+
+	assert.ErrorContains(t, err, "after 3 attempts")
+	assert.Equal(t, 3, calls)
+}
+
+func TestRetry_StopsOnContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := Retry(ctx, "test", 3, time.Second, func(ctx context.Context) error {
+		return errors.New("not ready")
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,155 @@
+// Package httppool provides an HTTP transport tuned for many clients of one
+// upstream API. Clients sharing it reuse its idle connections and TLS
+// sessions instead of each dialing their own, and it counts how its
+// connections are used. Timeouts bound each call by endpoint.
+package httppool
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Config tunes a transport's connection pool
+type Config struct {
+	MaxIdleConns        int           // Across hosts
+	MaxIdleConnsPerHost int           // Go's default of 2 makes busy clients dial for most requests
+	MaxConnsPerHost     int           // Including busy ones; 0 is unlimited
+	IdleConnTimeout     time.Duration // How long idle connections are kept
+	TLSSessionCacheSize int           // Sessions kept for resuming TLS handshakes
+}
+
+// DefaultConfig returns the pool settings for the Upbit APIs, which a few
+// hosts serve to every client
+func DefaultConfig() Config {
+	return Config{
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 32,
+		IdleConnTimeout:     90 * time.Second,
+		TLSSessionCacheSize: 64,
+	}
+}
+
+// Transport is an http.RoundTripper pooling connections across the clients
+// sharing it. It is safe for concurrent use.
+type Transport struct {
+	base  *http.Transport
+	trace *httptrace.ClientTrace
+
+	open          atomic.Int64
+	dials         atomic.Int64
+	dialErrors    atomic.Int64
+	requests      atomic.Int64
+	inFlight      atomic.Int64
+	reused        atomic.Int64
+	tlsHandshakes atomic.Int64
+	tlsResumed    atomic.Int64
+}
+
+// Snapshot is a point-in-time copy of a transport's connection metrics
+type Snapshot struct {
+	Open          int64   `json:"open"`        // Connections open now, busy or idle
+	Dials         int64   `json:"dials"`       // Connections opened
+	DialErrors    int64   `json:"dial_errors"` // Connections that failed to open
+	Requests      int64   `json:"requests"`
+	InFlight      int64   `json:"in_flight"`      // Requests waiting for a response now
+	Reused        int64   `json:"reused"`         // Requests sent on a connection used before
+	ReuseRate     float64 `json:"reuse_rate"`     // Share of requests sent on a reused connection
+	TLSHandshakes int64   `json:"tls_handshakes"` // Completed handshakes
+	TLSResumed    int64   `json:"tls_resumed"`    // Handshakes that resumed a cached session
+}
+
+// NewTransport creates a transport with the given pool settings
+func NewTransport(cfg Config) *Transport {
+	t := &Transport{}
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	t.base = &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, err := dialer.DialContext(ctx, network, addr)
+			if err != nil {
+				t.dialErrors.Add(1)
+				return nil, err
+			}
+			t.dials.Add(1)
+			t.open.Add(1)
+			return &trackedConn{Conn: conn, closed: func() { t.open.Add(-1) }}, nil
+		},
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          cfg.MaxIdleConns,
+		MaxIdleConnsPerHost:   cfg.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       cfg.MaxConnsPerHost,
+		IdleConnTimeout:       cfg.IdleConnTimeout,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+		TLSClientConfig: &tls.Config{
+			ClientSessionCache: tls.NewLRUClientSessionCache(cfg.TLSSessionCacheSize),
+		},
+	}
+	t.trace = &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				t.reused.Add(1)
+			}
+		},
+		TLSHandshakeDone: func(state tls.ConnectionState, err error) {
+			if err != nil {
+				return
+			}
+			t.tlsHandshakes.Add(1)
+			if state.DidResume {
+				t.tlsResumed.Add(1)
+			}
+		},
+	}
+	return t
+}
+
+// RoundTrip sends the request on a pooled connection
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests.Add(1)
+	t.inFlight.Add(1)
+	defer t.inFlight.Add(-1)
+
+	return t.base.RoundTrip(req.WithContext(httptrace.WithClientTrace(req.Context(), t.trace)))
+}
+
+// CloseIdleConnections closes the connections no request is using
+func (t *Transport) CloseIdleConnections() {
+	t.base.CloseIdleConnections()
+}
+
+// Snapshot returns the current connection metrics
+func (t *Transport) Snapshot() Snapshot {
+	s := Snapshot{
+		Open:          t.open.Load(),
+		Dials:         t.dials.Load(),
+		DialErrors:    t.dialErrors.Load(),
+		Requests:      t.requests.Load(),
+		InFlight:      t.inFlight.Load(),
+		Reused:        t.reused.Load(),
+		TLSHandshakes: t.tlsHandshakes.Load(),
+		TLSResumed:    t.tlsResumed.Load(),
+	}
+	if s.Requests > 0 {
+		s.ReuseRate = float64(s.Reused) / float64(s.Requests)
+	}
+	return s
+}
+
+// trackedConn reports when a connection closes, once
+type trackedConn struct {
+	net.Conn
+	once   sync.Once
+	closed func()
+}
+
+func (c *trackedConn) Close() error {
+	c.once.Do(c.closed)
+	return c.Conn.Close()
+}
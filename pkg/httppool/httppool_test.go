@@ -0,0 +1,123 @@
+package httppool
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransport_SharesConnectionsAcrossClients(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	transport := NewTransport(DefaultConfig())
+	get := func(client *http.Client) {
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+
+	// Clients of different users reuse each other's idle connections
+	for range 3 {
+		get(&http.Client{Transport: transport})
+	}
+	s := transport.Snapshot()
+	assert.Equal(t, int64(1), s.Dials)
+	assert.Equal(t, int64(1), s.Open)
+	assert.Equal(t, int64(3), s.Requests)
+	assert.Equal(t, int64(2), s.Reused)
+	assert.InDelta(t, 2.0/3, s.ReuseRate, 1e-9)
+	assert.Zero(t, s.InFlight)
+
+	transport.CloseIdleConnections()
+	assert.Zero(t, transport.Snapshot().Open)
+}
+
+func TestTransport_KeepsIdleConnectionsPerHost(t *testing.T) {
+	release := make(chan struct{})
+	var arrived sync.WaitGroup
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		arrived.Done()
+		<-release
+	}))
+	defer server.Close()
+
+	cfg := DefaultConfig()
+	cfg.MaxIdleConnsPerHost = 4
+	transport := NewTransport(cfg)
+	client := &http.Client{Transport: transport}
+
+	// Concurrent requests each open a connection; up to 4 stay idle for reuse
+	const concurrent = 6
+	arrived.Add(concurrent)
+	var done sync.WaitGroup
+	for range concurrent {
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			resp, err := client.Get(server.URL)
+			if err == nil {
+				io.Copy(io.Discard, resp.Body)
+				resp.Body.Close()
+			}
+		}()
+	}
+	arrived.Wait()
+	assert.Equal(t, int64(concurrent), transport.Snapshot().InFlight)
+	close(release)
+	done.Wait()
+
+	s := transport.Snapshot()
+	assert.Equal(t, int64(concurrent), s.Dials)
+	assert.Equal(t, int64(4), s.Open)
+}
+
+func TestTimeouts_BoundCallsByEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	timeouts := Timeouts{Default: time.Second, Endpoints: map[string]time.Duration{"GET /slow": 20 * time.Millisecond}}
+	assert.Equal(t, time.Second, timeouts.For("GET /fast"))
+	client := &http.Client{Transport: NewTransport(DefaultConfig())}
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/slow", nil)
+	require.NoError(t, err)
+	_, err = timeouts.Do(client, req, "GET /slow")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// The body stays readable until closed
+	req, err = http.NewRequest(http.MethodGet, server.URL+"/fast", nil)
+	require.NoError(t, err)
+	resp, err := timeouts.Do(client, req, "GET /fast")
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", string(body))
+	require.NoError(t, resp.Body.Close())
+
+	// So does cancelling the caller's context, e.g. when its request is aborted
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/fast", nil)
+	require.NoError(t, err)
+	_, err = timeouts.Do(client, req, "GET /fast")
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,50 @@
+package httppool
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Timeouts bounds each call of an API by endpoint, named as
+// callstats.Endpoint names them (e.g. "POST /orders"). Endpoints not listed
+// get Default.
+type Timeouts struct {
+	Default   time.Duration
+	Endpoints map[string]time.Duration
+}
+
+// For returns the timeout of an endpoint
+func (t Timeouts) For(endpoint string) time.Duration {
+	if timeout, ok := t.Endpoints[endpoint]; ok {
+		return timeout
+	}
+	return t.Default
+}
+
+// Do sends the request bounded by the endpoint's timeout, on top of any
+// deadline or cancellation of the request's own context. The timeout covers
+// reading the body, which ends it when closed.
+func (t Timeouts) Do(client *http.Client, req *http.Request, endpoint string) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(req.Context(), t.For(endpoint))
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelBody releases a call's context once its body is closed
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -1,13 +1,27 @@
 package jwt
 
 import (
+	"crypto/rsa"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"math/big"
+	"sort"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 )
 
+// DefaultKeyID is the ID of the key passed to NewManager. Tokens without a
+// kid header, issued before keys had IDs, are verified with it.
+const DefaultKeyID = "default"
+
+var (
+	ErrUnknownKey    = errors.New("unknown signing key")
+	ErrVerifyOnlyKey = errors.New("key can only verify tokens")
+)
+
 // Claims represents JWT claims
 type Claims struct {
 	UserID uuid.UUID `json:"user_id"`
@@ -15,18 +29,77 @@ type Claims struct {
 	jwt.RegisteredClaims
 }
 
-// Manager handles JWT token operations
+// key is a key tokens are signed or verified with
+type key struct {
+	method jwt.SigningMethod
+	sign   any // nil for keys that only verify
+	verify any
+}
+
+// Manager handles JWT token operations. It signs with one key and verifies
+// with every key it holds, so keys can be rotated without invalidating
+// tokens signed with the previous one.
 type Manager struct {
-	secretKey []byte
-	expiry    time.Duration
+	keys       map[string]*key // By key ID
+	signingKey string
+	issuer     string   // Optional
+	audience   []string // Optional
+	expiry     time.Duration
 }
 
-// NewManager creates a new JWT manager
+// NewManager creates a new JWT manager signing with an HS256 secret
 func NewManager(secretKey string, expiry time.Duration) *Manager {
-	return &Manager{
-		secretKey: []byte(secretKey),
-		expiry:    expiry,
+	m := &Manager{
+		keys:       make(map[string]*key),
+		signingKey: DefaultKeyID,
+		expiry:     expiry,
+	}
+	return m.AddHMACKey(DefaultKeyID, secretKey)
+}
+
+// WithIssuer sets the issuer of new tokens and requires it of verified ones
+func (m *Manager) WithIssuer(issuer string) *Manager {
+	m.issuer = issuer
+	return m
+}
+
+// WithAudience sets the audience of new tokens. Verified tokens must be
+// meant for at least one of them.
+func (m *Manager) WithAudience(audience ...string) *Manager {
+	m.audience = audience
+	return m
+}
+
+// AddHMACKey adds an HS256 secret under a key ID
+func (m *Manager) AddHMACKey(id, secret string) *Manager {
+	m.keys[id] = &key{method: jwt.SigningMethodHS256, sign: []byte(secret), verify: []byte(secret)}
+	return m
+}
+
+// AddRSAKey adds an RS256 private key under a key ID
+func (m *Manager) AddRSAKey(id string, private *rsa.PrivateKey) *Manager {
+	m.keys[id] = &key{method: jwt.SigningMethodRS256, sign: private, verify: &private.PublicKey}
+	return m
+}
+
+// AddRSAPublicKey adds an RS256 public key under a key ID, e.g. another
+// instance's key that is being rotated in. It only verifies tokens.
+func (m *Manager) AddRSAPublicKey(id string, public *rsa.PublicKey) *Manager {
+	m.keys[id] = &key{method: jwt.SigningMethodRS256, verify: public}
+	return m
+}
+
+// SignWith makes the manager sign new tokens with the key with the given ID
+func (m *Manager) SignWith(id string) error {
+	k, ok := m.keys[id]
+	if !ok {
+		return fmt.Errorf("%w %q", ErrUnknownKey, id)
 	}
+	if k.sign == nil {
+		return fmt.Errorf("%w: %q", ErrVerifyOnlyKey, id)
+	}
+	m.signingKey = id
+	return nil
 }
 
 // Generate generates a new JWT token
@@ -36,14 +109,18 @@ func (m *Manager) Generate(userID uuid.UUID, email string) (string, error) {
 		UserID: userID,
 		Email:  email,
 		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    m.issuer,
+			Audience:  m.audience,
 			ExpiresAt: jwt.NewNumericDate(now.Add(m.expiry)),
 			IssuedAt:  jwt.NewNumericDate(now),
 			NotBefore: jwt.NewNumericDate(now),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedToken, err := token.SignedString(m.secretKey)
+	k := m.keys[m.signingKey]
+	token := jwt.NewWithClaims(k.method, claims)
+	token.Header["kid"] = m.signingKey
+	signedToken, err := token.SignedString(k.sign)
 	if err != nil {
 		return "", fmt.Errorf("failed to sign token: %w", err)
 	}
@@ -53,12 +130,30 @@ func (m *Manager) Generate(userID uuid.UUID, email string) (string, error) {
 
 // Verify verifies and parses a JWT token
 func (m *Manager) Verify(tokenString string) (*Claims, error) {
+	var options []jwt.ParserOption
+	if m.issuer != "" {
+		options = append(options, jwt.WithIssuer(m.issuer))
+	}
+	if len(m.audience) > 0 {
+		options = append(options, jwt.WithAudience(m.audience...))
+	}
+
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		id := DefaultKeyID
+		if kid, ok := token.Header["kid"].(string); ok {
+			id = kid
+		}
+		k, ok := m.keys[id]
+		if !ok {
+			return nil, fmt.Errorf("%w %q", ErrUnknownKey, id)
+		}
+		// The key decides the algorithm, so an RS256 public key can't be
+		// used as an HMAC secret
+		if token.Method.Alg() != k.method.Alg() {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return m.secretKey, nil
-	})
+		return k.verify, nil
+	}, options...)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse token: %w", err)
@@ -70,3 +165,41 @@ func (m *Manager) Verify(tokenString string) (*Claims, error) {
 
 	return nil, fmt.Errorf("invalid token")
 }
+
+// JWK is a public key in JSON Web Key format
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Modulus   string `json:"n"`
+	Exponent  string `json:"e"`
+}
+
+// JWKS is a JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the RSA public keys tokens are verified with, sorted by key
+// ID, so other services can verify tokens themselves. HMAC secrets are never
+// published.
+func (m *Manager) JWKS() JWKS {
+	set := JWKS{Keys: []JWK{}}
+	for id, k := range m.keys {
+		public, ok := k.verify.(*rsa.PublicKey)
+		if !ok {
+			continue
+		}
+		set.Keys = append(set.Keys, JWK{
+			KeyType:   "RSA",
+			KeyID:     id,
+			Use:       "sig",
+			Algorithm: k.method.Alg(),
+			Modulus:   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+			Exponent:  base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+		})
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].KeyID < set.Keys[j].KeyID })
+	return set
+}
@@ -0,0 +1,90 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManager_RotatesKeys(t *testing.T) {
+	old := NewManager("old-secret", time.Hour)
+	oldToken, err := old.Generate(uuid.New(), "a@example.com")
+	require.NoError(t, err)
+
+	rotated := NewManager("old-secret", time.Hour).AddHMACKey("2025-06", "new-secret")
+	require.NoError(t, rotated.SignWith("2025-06"))
+	newToken, err := rotated.Generate(uuid.New(), "b@example.com")
+	require.NoError(t, err)
+
+	// Tokens signed with either key verify during the rotation
+	_, err = rotated.Verify(oldToken)
+	require.NoError(t, err)
+	claims, err := rotated.Verify(newToken)
+	require.NoError(t, err)
+	assert.Equal(t, "b@example.com", claims.Email)
+
+	// Instances that don't know the new key reject its tokens
+	_, err = old.Verify(newToken)
+	assert.ErrorIs(t, err, ErrUnknownKey)
+
+	assert.ErrorIs(t, rotated.SignWith("missing"), ErrUnknownKey)
+}
+
+func TestManager_IssuerAndAudience(t *testing.T) {
+	m := NewManager("secret", time.Hour).WithIssuer("upbit-trading").WithAudience("api")
+	token, err := m.Generate(uuid.New(), "a@example.com")
+	require.NoError(t, err)
+
+	claims, err := m.Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, "upbit-trading", claims.Issuer)
+	assert.Equal(t, jwt.ClaimStrings{"api"}, claims.Audience)
+
+	_, err = NewManager("secret", time.Hour).WithIssuer("other").Verify(token)
+	assert.ErrorIs(t, err, jwt.ErrTokenInvalidIssuer)
+	_, err = NewManager("secret", time.Hour).WithAudience("admin").Verify(token)
+	assert.ErrorIs(t, err, jwt.ErrTokenInvalidAudience)
+}
+
+func TestManager_RS256(t *testing.T) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	signer := NewManager("secret", time.Hour).AddRSAKey("rsa-1", private)
+	require.NoError(t, signer.SignWith("rsa-1"))
+	token, err := signer.Generate(uuid.New(), "a@example.com")
+	require.NoError(t, err)
+
+	// Another service holding only the public key can verify, but not sign
+	verifier := NewManager("unrelated", time.Hour).AddRSAPublicKey("rsa-1", &private.PublicKey)
+	_, err = verifier.Verify(token)
+	require.NoError(t, err)
+	assert.ErrorIs(t, verifier.SignWith("rsa-1"), ErrVerifyOnlyKey)
+
+	jwks := signer.JWKS()
+	require.Len(t, jwks.Keys, 1)
+	assert.Equal(t, "rsa-1", jwks.Keys[0].KeyID)
+	assert.Equal(t, "RS256", jwks.Keys[0].Algorithm)
+	assert.Equal(t, "AQAB", jwks.Keys[0].Exponent)
+}
+
+func TestManager_RejectsAlgorithmSwitch(t *testing.T) {
+	private, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	m := NewManager("secret", time.Hour).AddRSAKey("rsa-1", private)
+
+	// An HS256 token claiming the RSA key's ID must not verify
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, &Claims{Email: "a@example.com"})
+	forged.Header["kid"] = "rsa-1"
+	signed, err := forged.SignedString([]byte("secret"))
+	require.NoError(t, err)
+
+	_, err = m.Verify(signed)
+	assert.Error(t, err)
+}
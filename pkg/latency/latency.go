@@ -0,0 +1,126 @@
+// Package latency records how far local processing trails the exchange, as
+// histograms per pipeline stage, to quantify how stale decisions are
+package latency
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// BucketsMs are the upper bounds of the histogram buckets. Slower
+// observations fall in a final overflow bucket.
+var BucketsMs = []int64{10, 50, 100, 250, 500, 1000, 2500, 5000, 10000}
+
+// Recorder keeps a histogram per stage, e.g. "feed.ticker" for the delay
+// between a trade on Upbit and the feed receiving its price. It is safe for
+// concurrent use.
+type Recorder struct {
+	mu     sync.Mutex
+	stages map[string]*histogram
+}
+
+type histogram struct {
+	count   int64
+	total   time.Duration
+	max     time.Duration
+	skewed  int64
+	buckets []int64
+}
+
+// StageSnapshot is a point-in-time copy of a stage's histogram
+type StageSnapshot struct {
+	Stage string `json:"stage"`
+	Count int64  `json:"count"`
+	AvgMs int64  `json:"avg_ms"`
+	MaxMs int64  `json:"max_ms"`
+	P50Ms int64  `json:"p50_ms"` // Upper bound of the bucket holding the median
+	P99Ms int64  `json:"p99_ms"` // Upper bound of the bucket holding the 99th percentile; -1 when in the overflow bucket
+	// Skewed counts observations that came out negative, i.e. the local clock
+	// is behind Upbit's. They are recorded as zero.
+	Skewed int64 `json:"skewed"`
+	// Buckets counts observations; bucket i holds those slower than bucket
+	// i-1's bound and no slower than BucketsMs[i], the last holds the rest
+	Buckets []int64 `json:"buckets"`
+}
+
+// NewRecorder creates an empty recorder
+func NewRecorder() *Recorder {
+	return &Recorder{stages: make(map[string]*histogram)}
+}
+
+// Record adds an observation to a stage
+func (r *Recorder) Record(stage string, d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	h, exists := r.stages[stage]
+	if !exists {
+		h = &histogram{buckets: make([]int64, len(BucketsMs)+1)}
+		r.stages[stage] = h
+	}
+
+	if d < 0 {
+		h.skewed++
+		d = 0
+	}
+	h.count++
+	h.total += d
+	h.max = max(h.max, d)
+	bucket := sort.Search(len(BucketsMs), func(i int) bool { return d <= time.Duration(BucketsMs[i])*time.Millisecond })
+	h.buckets[bucket]++
+}
+
+// Since records the time from an exchange timestamp to now, unless the
+// timestamp is unknown
+func (r *Recorder) Since(stage string, exchangeTime, now time.Time) {
+	if exchangeTime.IsZero() {
+		return
+	}
+	r.Record(stage, now.Sub(exchangeTime))
+}
+
+// Snapshot returns the histogram of every stage, sorted by stage
+func (r *Recorder) Snapshot() []StageSnapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	snapshots := make([]StageSnapshot, 0, len(r.stages))
+	for stage, h := range r.stages {
+		snapshot := StageSnapshot{
+			Stage:   stage,
+			Count:   h.count,
+			MaxMs:   h.max.Milliseconds(),
+			P50Ms:   h.quantile(0.5),
+			P99Ms:   h.quantile(0.99),
+			Skewed:  h.skewed,
+			Buckets: append([]int64(nil), h.buckets...),
+		}
+		if h.count > 0 {
+			snapshot.AvgMs = (h.total / time.Duration(h.count)).Milliseconds()
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Stage < snapshots[j].Stage })
+	return snapshots
+}
+
+// quantile returns the upper bound of the bucket holding the quantile q, or
+// -1 when it falls in the overflow bucket
+func (h *histogram) quantile(q float64) int64 {
+	if h.count == 0 {
+		return 0
+	}
+
+	target := int64(q*float64(h.count-1)) + 1
+	var seen int64
+	for i, n := range h.buckets {
+		if seen += n; seen >= target {
+			if i == len(BucketsMs) {
+				return -1
+			}
+			return BucketsMs[i]
+		}
+	}
+	return -1
+}
@@ -0,0 +1,39 @@
+package latency
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder_Snapshot(t *testing.T) {
+	r := NewRecorder()
+	now := time.Now()
+	for i := 0; i < 98; i++ {
+		r.Since("feed.ticker", now.Add(-40*time.Millisecond), now)
+	}
+	r.Record("feed.ticker", 2*time.Second)
+	r.Record("feed.ticker", -5*time.Millisecond)
+	r.Since("feed.ticker", time.Time{}, now) // Unknown exchange time
+	r.Record("guard.trigger", 20*time.Second)
+
+	snapshots := r.Snapshot()
+	require.Len(t, snapshots, 2)
+
+	feed := snapshots[0]
+	assert.Equal(t, "feed.ticker", feed.Stage)
+	assert.Equal(t, int64(100), feed.Count)
+	assert.Equal(t, int64(1), feed.Skewed)
+	assert.Equal(t, int64(2000), feed.MaxMs)
+	assert.Equal(t, int64(50), feed.P50Ms)
+	assert.Equal(t, int64(50), feed.P99Ms)
+	assert.Equal(t, int64(1), feed.Buckets[0], "negative delays count as zero")
+	assert.Equal(t, int64(98), feed.Buckets[1])
+	assert.Equal(t, int64(1), feed.Buckets[6])
+
+	guard := snapshots[1]
+	assert.Equal(t, int64(-1), guard.P50Ms, "beyond the last bucket")
+	assert.Equal(t, int64(1), guard.Buckets[len(BucketsMs)])
+}
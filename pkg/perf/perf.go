@@ -0,0 +1,251 @@
+// Package perf computes trading performance metrics from an equity curve and
+// a list of closed trades. It is shared by backtests and live portfolio analytics.
+package perf
+
+import (
+	"math"
+	"time"
+)
+
+// DefaultPeriodsPerYear suits daily equity points; crypto trades every day
+const DefaultPeriodsPerYear = 365
+
+// EquityPoint is the account value at a point in time
+type EquityPoint struct {
+	Time   time.Time `json:"time"`
+	Equity float64   `json:"equity"`
+}
+
+// Trade is a closed round trip
+type Trade struct {
+	EntryTime time.Time `json:"entry_time"`
+	ExitTime  time.Time `json:"exit_time"`
+	PnL       float64   `json:"pnl"`
+}
+
+// Options configures metric computation
+type Options struct {
+	PeriodsPerYear float64 // Equity points per year, used to annualize; DefaultPeriodsPerYear when zero
+	RiskFreeRate   float64 // Annual risk-free rate, e.g. 0.03
+}
+
+// Metrics summarizes performance. Ratios are fractions, so 0.1 means 10%.
+type Metrics struct {
+	TotalReturn      float64       `json:"total_return"`
+	CAGR             float64       `json:"cagr"`
+	MaxDrawdown      float64       `json:"max_drawdown"` // Largest peak-to-trough decline, as a positive fraction
+	Sharpe           float64       `json:"sharpe"`
+	Sortino          float64       `json:"sortino"`
+	Trades           int           `json:"trades"`
+	WinRate          float64       `json:"win_rate"`
+	ProfitFactor     float64       `json:"profit_factor"` // Gross profit / gross loss; 0 when there are no losing trades
+	AvgTradeDuration time.Duration `json:"avg_trade_duration"`
+}
+
+// Compute calculates metrics for an equity curve, which must be in time order
+// and sampled at a regular interval, and the trades closed over it
+func Compute(equity []EquityPoint, trades []Trade, opts Options) Metrics {
+	if opts.PeriodsPerYear <= 0 {
+		opts.PeriodsPerYear = DefaultPeriodsPerYear
+	}
+
+	var m Metrics
+	if len(equity) >= 2 {
+		first, last := equity[0], equity[len(equity)-1]
+		if first.Equity > 0 {
+			m.TotalReturn = last.Equity/first.Equity - 1
+			m.CAGR = cagr(first, last)
+		}
+
+		m.MaxDrawdown = MaxDrawdown(equity)
+
+		returns := Returns(equity)
+		m.Sharpe = sharpe(returns, opts)
+		m.Sortino = sortino(returns, opts)
+	}
+
+	m.Trades = len(trades)
+	if len(trades) > 0 {
+		var wins int
+		var grossProfit, grossLoss float64
+		var totalDuration time.Duration
+		for _, t := range trades {
+			if t.PnL > 0 {
+				wins++
+				grossProfit += t.PnL
+			} else {
+				grossLoss -= t.PnL
+			}
+			totalDuration += t.ExitTime.Sub(t.EntryTime)
+		}
+
+		m.WinRate = float64(wins) / float64(len(trades))
+		if grossLoss > 0 {
+			m.ProfitFactor = grossProfit / grossLoss
+		}
+		m.AvgTradeDuration = totalDuration / time.Duration(len(trades))
+	}
+
+	return m
+}
+
+// Comparison relates an equity curve to a benchmark's. Ratios are fractions.
+type Comparison struct {
+	Return         float64 `json:"return"`          // Total return of the benchmark
+	RelativeReturn float64 `json:"relative_return"` // Total return less the benchmark's
+	Beta           float64 `json:"beta"`            // Sensitivity of per-period returns to the benchmark's
+	Correlation    float64 `json:"correlation"`
+}
+
+// Compare compares an equity curve with a benchmark curve sampled at the
+// same times, such as the price of a market
+func Compare(equity, benchmark []EquityPoint) Comparison {
+	var c Comparison
+	if len(equity) < 2 || len(equity) != len(benchmark) {
+		return c
+	}
+	if first := benchmark[0].Equity; first > 0 {
+		c.Return = benchmark[len(benchmark)-1].Equity/first - 1
+	}
+	if first := equity[0].Equity; first > 0 {
+		c.RelativeReturn = equity[len(equity)-1].Equity/first - 1 - c.Return
+	}
+
+	returns, benchmarkReturns := Returns(equity), Returns(benchmark)
+	if len(returns) < 2 {
+		return c
+	}
+	cov := covariance(returns, benchmarkReturns)
+	if sd := stddev(benchmarkReturns); sd > 0 {
+		c.Beta = cov / (sd * sd)
+		if sdp := stddev(returns); sdp > 0 {
+			c.Correlation = cov / (sd * sdp)
+		}
+	}
+	return c
+}
+
+// Correlation returns the Pearson correlation of two equally long series of
+// returns, or 0 when it is undefined because a series is flat or too short
+func Correlation(a, b []float64) float64 {
+	if len(a) < 2 || len(a) != len(b) {
+		return 0
+	}
+	sa, sb := stddev(a), stddev(b)
+	if sa == 0 || sb == 0 {
+		return 0
+	}
+	return covariance(a, b) / (sa * sb)
+}
+
+// Returns converts an equity curve into simple per-period returns
+func Returns(equity []EquityPoint) []float64 {
+	if len(equity) < 2 {
+		return nil
+	}
+
+	returns := make([]float64, 0, len(equity)-1)
+	for i := 1; i < len(equity); i++ {
+		prev := equity[i-1].Equity
+		if prev == 0 {
+			returns = append(returns, 0)
+			continue
+		}
+		returns = append(returns, equity[i].Equity/prev-1)
+	}
+	return returns
+}
+
+// MaxDrawdown returns the largest peak-to-trough decline as a positive fraction
+func MaxDrawdown(equity []EquityPoint) float64 {
+	var peak, maxDrawdown float64
+	for _, p := range equity {
+		if p.Equity > peak {
+			peak = p.Equity
+		}
+		if peak > 0 {
+			if dd := (peak - p.Equity) / peak; dd > maxDrawdown {
+				maxDrawdown = dd
+			}
+		}
+	}
+	return maxDrawdown
+}
+
+func cagr(first, last EquityPoint) float64 {
+	years := last.Time.Sub(first.Time).Hours() / (24 * 365.25)
+	if years <= 0 || last.Equity <= 0 {
+		return 0
+	}
+	return math.Pow(last.Equity/first.Equity, 1/years) - 1
+}
+
+// sharpe returns the annualized Sharpe ratio of per-period returns
+func sharpe(returns []float64, opts Options) float64 {
+	if len(returns) < 2 {
+		return 0
+	}
+
+	rf := opts.RiskFreeRate / opts.PeriodsPerYear
+	excess := make([]float64, len(returns))
+	for i, r := range returns {
+		excess[i] = r - rf
+	}
+
+	sd := stddev(excess)
+	if sd == 0 {
+		return 0
+	}
+	return mean(excess) / sd * math.Sqrt(opts.PeriodsPerYear)
+}
+
+// sortino is like sharpe but only penalizes returns below the risk-free rate
+func sortino(returns []float64, opts Options) float64 {
+	if len(returns) < 2 {
+		return 0
+	}
+
+	rf := opts.RiskFreeRate / opts.PeriodsPerYear
+	var sum, downside float64
+	for _, r := range returns {
+		excess := r - rf
+		sum += excess
+		if excess < 0 {
+			downside += excess * excess
+		}
+	}
+
+	dd := math.Sqrt(downside / float64(len(returns)))
+	if dd == 0 {
+		return 0
+	}
+	return sum / float64(len(returns)) / dd * math.Sqrt(opts.PeriodsPerYear)
+}
+
+func mean(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// stddev returns the sample standard deviation
+func stddev(values []float64) float64 {
+	m := mean(values)
+	var sum float64
+	for _, v := range values {
+		sum += (v - m) * (v - m)
+	}
+	return math.Sqrt(sum / float64(len(values)-1))
+}
+
+// covariance returns the sample covariance of two equally long series
+func covariance(a, b []float64) float64 {
+	ma, mb := mean(a), mean(b)
+	var sum float64
+	for i := range a {
+		sum += (a[i] - ma) * (b[i] - mb)
+	}
+	return sum / float64(len(a)-1)
+}
@@ -0,0 +1,102 @@
+package perf
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func curve(start time.Time, step time.Duration, values ...float64) []EquityPoint {
+	points := make([]EquityPoint, len(values))
+	for i, v := range values {
+		points[i] = EquityPoint{Time: start.Add(time.Duration(i) * step), Equity: v}
+	}
+	return points
+}
+
+func TestCompute_Returns(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	equity := []EquityPoint{
+		{Time: start, Equity: 1000},
+		{Time: start.Add(365 * 24 * time.Hour), Equity: 1100},
+		{Time: start.Add(2 * 365 * 24 * time.Hour), Equity: 1210},
+	}
+
+	m := Compute(equity, nil, Options{})
+
+	assert.InDelta(t, 0.21, m.TotalReturn, 1e-9)
+	// Two 10% years; the leap-year day shifts CAGR slightly below 10%
+	assert.InDelta(t, 0.10, m.CAGR, 1e-3)
+	assert.Zero(t, m.MaxDrawdown)
+}
+
+func TestMaxDrawdown(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	equity := curve(start, 24*time.Hour, 100, 120, 90, 110, 60, 130)
+
+	// Peak 120 to trough 60
+	assert.InDelta(t, 0.5, MaxDrawdown(equity), 1e-9)
+}
+
+func TestCompute_SharpeAndSortino(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	equity := curve(start, 24*time.Hour, 100, 101, 100, 102, 101, 103)
+
+	m := Compute(equity, nil, Options{PeriodsPerYear: 365})
+	returns := Returns(equity)
+
+	expectedSharpe := mean(returns) / stddev(returns) * math.Sqrt(365)
+	assert.InDelta(t, expectedSharpe, m.Sharpe, 1e-9)
+	assert.Greater(t, m.Sortino, m.Sharpe, "few losing days should make Sortino exceed Sharpe")
+
+	// A constant curve has no volatility
+	flat := Compute(curve(start, 24*time.Hour, 100, 100, 100), nil, Options{})
+	assert.Zero(t, flat.Sharpe)
+	assert.Zero(t, flat.Sortino)
+}
+
+func TestCompute_Trades(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	trades := []Trade{
+		{EntryTime: start, ExitTime: start.Add(2 * time.Hour), PnL: 300},
+		{EntryTime: start, ExitTime: start.Add(4 * time.Hour), PnL: -100},
+		{EntryTime: start, ExitTime: start.Add(6 * time.Hour), PnL: 100},
+		{EntryTime: start, ExitTime: start.Add(4 * time.Hour), PnL: -100},
+	}
+
+	m := Compute(nil, trades, Options{})
+
+	assert.Equal(t, 4, m.Trades)
+	assert.InDelta(t, 0.5, m.WinRate, 1e-9)
+	assert.InDelta(t, 2.0, m.ProfitFactor, 1e-9)
+	assert.Equal(t, 4*time.Hour, m.AvgTradeDuration)
+}
+
+func TestCompute_Empty(t *testing.T) {
+	assert.Equal(t, Metrics{}, Compute(nil, nil, Options{}))
+}
+
+func TestCompare(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	benchmark := curve(start, 24*time.Hour, 100, 110, 99, 108.9)
+	// Twice the benchmark's daily moves
+	equity := curve(start, 24*time.Hour, 1000, 1200, 960, 1152)
+
+	c := Compare(equity, benchmark)
+	assert.InDelta(t, 0.089, c.Return, 1e-9)
+	assert.InDelta(t, 0.152-0.089, c.RelativeReturn, 1e-9)
+	assert.InDelta(t, 2, c.Beta, 1e-9)
+	assert.InDelta(t, 1, c.Correlation, 1e-9)
+
+	assert.Equal(t, Comparison{}, Compare(equity, benchmark[:2]), "misaligned curves")
+}
+
+func TestCorrelation(t *testing.T) {
+	a := []float64{0.01, -0.02, 0.03, 0}
+	assert.InDelta(t, 1, Correlation(a, []float64{0.02, -0.04, 0.06, 0}), 1e-9)
+	assert.InDelta(t, -1, Correlation(a, []float64{-0.01, 0.02, -0.03, 0}), 1e-9)
+	assert.Zero(t, Correlation(a, []float64{0, 0, 0, 0}), "flat series")
+	assert.Zero(t, Correlation(a, a[:3]), "misaligned series")
+}
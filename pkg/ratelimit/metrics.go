@@ -0,0 +1,81 @@
+package ratelimit
+
+import (
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// Metrics counts the calls of one or more rate limiters sharing it, e.g. the
+// limiters of every user's exchange client. It is safe for concurrent use.
+type Metrics struct {
+	calls      atomic.Int64 // Allowed, at once or after waiting
+	throttled  atomic.Int64 // Had to wait
+	rejected   atomic.Int64 // Refused by Allow
+	waiting    atomic.Int64 // Blocked in Wait right now
+	totalWait  atomic.Int64 // Nanoseconds
+	maxWait    atomic.Int64 // Nanoseconds
+	lastTokens atomic.Uint64
+}
+
+// MetricsSnapshot is a point-in-time copy of rate limiter metrics
+type MetricsSnapshot struct {
+	Calls     int64 `json:"calls"`
+	Throttled int64 `json:"throttled"`
+	Rejected  int64 `json:"rejected"`
+	// Waiting is how many calls are queued behind the limit right now
+	Waiting     int64 `json:"waiting"`
+	TotalWaitMs int64 `json:"total_wait_ms"`
+	AvgWaitMs   int64 `json:"avg_wait_ms"` // Of throttled calls
+	MaxWaitMs   int64 `json:"max_wait_ms"`
+	// Tokens is the weight left in the bucket after the most recent call. A
+	// level near zero means callers are about to be throttled.
+	Tokens float64 `json:"tokens"`
+}
+
+// NewMetrics creates an empty set of rate limiter metrics
+func NewMetrics() *Metrics {
+	return &Metrics{}
+}
+
+// Snapshot returns the current metrics
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	totalWait := time.Duration(m.totalWait.Load())
+	s := MetricsSnapshot{
+		Calls:       m.calls.Load(),
+		Throttled:   m.throttled.Load(),
+		Rejected:    m.rejected.Load(),
+		Waiting:     m.waiting.Load(),
+		TotalWaitMs: totalWait.Milliseconds(),
+		MaxWaitMs:   time.Duration(m.maxWait.Load()).Milliseconds(),
+		Tokens:      math.Float64frombits(m.lastTokens.Load()),
+	}
+	if s.Throttled > 0 {
+		s.AvgWaitMs = (totalWait / time.Duration(s.Throttled)).Milliseconds()
+	}
+	return s
+}
+
+func (m *Metrics) recordAllow(allowed bool, tokens float64) {
+	if allowed {
+		m.calls.Add(1)
+	} else {
+		m.rejected.Add(1)
+	}
+	m.lastTokens.Store(math.Float64bits(tokens))
+}
+
+func (m *Metrics) recordWait(waited time.Duration, throttled bool, tokens float64) {
+	m.calls.Add(1)
+	if throttled {
+		m.throttled.Add(1)
+		m.totalWait.Add(int64(waited))
+		for {
+			current := m.maxWait.Load()
+			if int64(waited) <= current || m.maxWait.CompareAndSwap(current, int64(waited)) {
+				break
+			}
+		}
+	}
+	m.lastTokens.Store(math.Float64bits(tokens))
+}
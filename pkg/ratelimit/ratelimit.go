@@ -3,6 +3,7 @@ package ratelimit
 import (
 	"context"
 	"sync"
+	"time"
 
 	"golang.org/x/time/rate"
 )
@@ -10,24 +11,107 @@ import (
 // RateLimiter wraps golang.org/x/time/rate.Limiter for API rate limiting
 type RateLimiter struct {
 	limiter *rate.Limiter
+	metrics *Metrics // Optional
 	mu      sync.Mutex
 }
 
-// NewRateLimiter creates a new rate limiter with the specified requests per second
+// NewRateLimiter creates a new rate limiter with the specified requests per
+// second. Up to one second's worth of requests may burst after it was idle.
 func NewRateLimiter(requestsPerSecond int) *RateLimiter {
 	return &RateLimiter{
 		limiter: rate.NewLimiter(rate.Limit(requestsPerSecond), requestsPerSecond),
 	}
 }
 
+// WithBurst sets how many weight units may be spent at once after the
+// limiter was idle. It also bounds the weight of a single call. The limiter
+// starts full.
+func (rl *RateLimiter) WithBurst(burst int) *RateLimiter {
+	rl.limiter = rate.NewLimiter(rl.limiter.Limit(), burst)
+	return rl
+}
+
+// WithMetrics makes the limiter record its calls, waits and token level in
+// metrics, which other limiters may share
+func (rl *RateLimiter) WithMetrics(metrics *Metrics) *RateLimiter {
+	rl.metrics = metrics
+	return rl
+}
+
 // Allow checks if a request can proceed without blocking
 func (rl *RateLimiter) Allow() bool {
-	return rl.limiter.Allow()
+	return rl.AllowN(1)
+}
+
+// AllowN checks if a call of the given weight can proceed without blocking
+func (rl *RateLimiter) AllowN(weight int) bool {
+	now := time.Now()
+	allowed := rl.limiter.AllowN(now, weight)
+	if rl.metrics != nil {
+		rl.metrics.recordAllow(allowed, rl.limiter.TokensAt(now))
+	}
+	return allowed
 }
 
 // Wait blocks until the rate limiter allows a request or context is cancelled
 func (rl *RateLimiter) Wait(ctx context.Context) error {
-	return rl.limiter.Wait(ctx)
+	return rl.WaitN(ctx, 1)
+}
+
+// WaitN blocks until the rate limiter allows a call of the given weight or
+// context is cancelled
+func (rl *RateLimiter) WaitN(ctx context.Context, weight int) error {
+	if weight > rl.limiter.Burst() {
+		return ErrWeightExceedsBurst
+	}
+	if rl.metrics == nil {
+		return rl.limiter.WaitN(ctx, weight)
+	}
+
+	start := time.Now()
+	throttled := rl.limiter.TokensAt(start) < float64(weight)
+	if throttled {
+		rl.metrics.waiting.Add(1)
+		defer rl.metrics.waiting.Add(-1)
+	}
+	if err := rl.limiter.WaitN(ctx, weight); err != nil {
+		return err
+	}
+	rl.metrics.recordWait(time.Since(start), throttled, rl.limiter.Tokens())
+	return nil
+}
+
+// Tokens returns the weight that can be spent right now without waiting
+func (rl *RateLimiter) Tokens() float64 {
+	return rl.limiter.Tokens()
+}
+
+// Reserve reserves a call of the given weight and reports when it may be
+// made, so callers can schedule calls instead of blocking on them. The call
+// counts against the limit from when it is reserved; cancel the reservation
+// if it isn't made.
+func (rl *RateLimiter) Reserve(weight int) (*Reservation, error) {
+	r := rl.limiter.ReserveN(time.Now(), weight)
+	if !r.OK() {
+		return nil, ErrWeightExceedsBurst
+	}
+	return &Reservation{reservation: r}, nil
+}
+
+// Reservation is a call reserved with a rate limiter
+type Reservation struct {
+	reservation *rate.Reservation
+}
+
+// Delay returns how long to wait before making the reserved call
+func (r *Reservation) Delay() time.Duration {
+	return r.reservation.Delay()
+}
+
+// Cancel returns the reserved weight to the limiter, as far as later calls
+// haven't been scheduled on it
+func (r *Reservation) Cancel() {
+	r.reservation.Cancel()
 }
 
 // MultiRateLimiter manages multiple rate limiters for different API categories
@@ -69,6 +153,34 @@ func (mrl *MultiRateLimiter) Wait(ctx context.Context, category string) error {
 	return limiter.Wait(ctx)
 }
 
+// WaitN blocks until the rate limiter of the specified category allows a
+// call of the given weight
+func (mrl *MultiRateLimiter) WaitN(ctx context.Context, category string, weight int) error {
+	mrl.mu.RLock()
+	limiter, exists := mrl.limiters[category]
+	mrl.mu.RUnlock()
+
+	if !exists {
+		return ErrCategoryNotFound
+	}
+
+	return limiter.WaitN(ctx, weight)
+}
+
+// Reserve reserves a call of the given weight with the rate limiter of the
+// specified category
+func (mrl *MultiRateLimiter) Reserve(category string, weight int) (*Reservation, error) {
+	mrl.mu.RLock()
+	limiter, exists := mrl.limiters[category]
+	mrl.mu.RUnlock()
+
+	if !exists {
+		return nil, ErrCategoryNotFound
+	}
+
+	return limiter.Reserve(weight)
+}
+
 // Add adds a new rate limiter for a category
 func (mrl *MultiRateLimiter) Add(category string, limiter *RateLimiter) {
 	mrl.mu.Lock()
@@ -76,8 +188,13 @@ func (mrl *MultiRateLimiter) Add(category string, limiter *RateLimiter) {
 	mrl.limiters[category] = limiter
 }
 
-// ErrCategoryNotFound is returned when the specified rate limiter category doesn't exist
-var ErrCategoryNotFound = &RateLimitError{message: "rate limiter category not found"}
+var (
+	// ErrCategoryNotFound is returned when the specified rate limiter category doesn't exist
+	ErrCategoryNotFound = &RateLimitError{message: "rate limiter category not found"}
+	// ErrWeightExceedsBurst is returned for calls heavier than the limiter's burst,
+	// which could never proceed
+	ErrWeightExceedsBurst = &RateLimitError{message: "call weight exceeds the rate limiter burst"}
+)
 
 // RateLimitError represents a rate limiting error
 type RateLimitError struct {
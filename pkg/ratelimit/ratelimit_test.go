@@ -129,3 +129,67 @@ func TestMultiRateLimiter(t *testing.T) {
 	// Test non-existent limiter
 	assert.False(t, multi.Allow("nonexistent"))
 }
+
+func TestRateLimiter_Weights(t *testing.T) {
+	limiter := NewRateLimiter(10)
+
+	assert.True(t, limiter.AllowN(6))
+	assert.False(t, limiter.AllowN(6))
+	assert.True(t, limiter.AllowN(4))
+
+	err := limiter.WaitN(context.Background(), 11)
+	assert.ErrorIs(t, err, ErrWeightExceedsBurst)
+}
+
+func TestRateLimiter_Burst(t *testing.T) {
+	limiter := NewRateLimiter(10).WithBurst(20)
+
+	passed := 0
+	for i := 0; i < 30; i++ {
+		if limiter.Allow() {
+			passed++
+		}
+	}
+	assert.Equal(t, 20, passed)
+}
+
+func TestRateLimiter_Reserve(t *testing.T) {
+	limiter := NewRateLimiter(10)
+
+	r, err := limiter.Reserve(10)
+	assert.NoError(t, err)
+	assert.Zero(t, r.Delay())
+
+	// The bucket is empty, so the next 5 units take half a second to refill
+	r, err = limiter.Reserve(5)
+	assert.NoError(t, err)
+	assert.InDelta(t, 500*time.Millisecond, r.Delay(), float64(50*time.Millisecond))
+
+	// Cancelling gives the units back
+	r.Cancel()
+	r, err = limiter.Reserve(5)
+	assert.NoError(t, err)
+	assert.InDelta(t, 500*time.Millisecond, r.Delay(), float64(50*time.Millisecond))
+
+	_, err = limiter.Reserve(11)
+	assert.ErrorIs(t, err, ErrWeightExceedsBurst)
+}
+
+func TestRateLimiter_Metrics(t *testing.T) {
+	metrics := NewMetrics()
+	limiter := NewRateLimiter(100).WithBurst(2).WithMetrics(metrics)
+	ctx := context.Background()
+
+	assert.NoError(t, limiter.WaitN(ctx, 2))
+	assert.False(t, limiter.Allow())
+	assert.NoError(t, limiter.Wait(ctx))
+
+	s := metrics.Snapshot()
+	assert.Equal(t, int64(2), s.Calls)
+	assert.Equal(t, int64(1), s.Throttled)
+	assert.Equal(t, int64(1), s.Rejected)
+	assert.Zero(t, s.Waiting)
+	assert.Equal(t, s.MaxWaitMs, s.AvgWaitMs)
+	assert.Equal(t, s.MaxWaitMs, s.TotalWaitMs)
+	assert.Less(t, s.Tokens, 1.0)
+}
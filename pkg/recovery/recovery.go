@@ -0,0 +1,116 @@
+// Package recovery keeps a panic in a background goroutine from killing the
+// process. Panics are recovered, logged with their stack and counted by
+// task, so one bad market, job or message fails alone.
+package recovery
+
+import (
+	"fmt"
+	"log"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Panics counts the panics recovered by Go, Do and Recover
+var Panics = NewRecorder()
+
+// Go runs fn in a new goroutine, recovering a panic in it as task
+func Go(task string, fn func()) {
+	go func() {
+		defer Recover(task)
+		fn()
+	}()
+}
+
+// Do runs fn, recovering a panic in it as task, and reports whether it
+// panicked. Workers wrap each unit of work in it so a panic skips the unit
+// rather than stopping the worker.
+func Do(task string, fn func()) (panicked bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			Panics.record(task, r, debug.Stack())
+			panicked = true
+		}
+	}()
+	fn()
+	return false
+}
+
+// Call runs fn, recovering a panic in it as task and returning it as an
+// error, for work whose failures are collected
+func Call(task string, fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			Panics.record(task, r, debug.Stack())
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn()
+}
+
+// Recover recovers a panic of the calling goroutine as task. It must be
+// deferred directly: defer recovery.Recover("task").
+func Recover(task string) {
+	if r := recover(); r != nil {
+		Panics.record(task, r, debug.Stack())
+	}
+}
+
+// Recorder counts recovered panics by task. It is safe for concurrent use.
+type Recorder struct {
+	mu    sync.Mutex
+	tasks map[string]*taskStats
+}
+
+type taskStats struct {
+	panics      int64
+	lastPanic   string
+	lastPanicAt time.Time
+}
+
+// TaskSnapshot is a point-in-time copy of a task's recovered panics
+type TaskSnapshot struct {
+	Task        string    `json:"task"`
+	Panics      int64     `json:"panics"`
+	LastPanic   string    `json:"last_panic"`
+	LastPanicAt time.Time `json:"last_panic_at"`
+}
+
+// NewRecorder creates an empty panic recorder
+func NewRecorder() *Recorder {
+	return &Recorder{tasks: make(map[string]*taskStats)}
+}
+
+func (r *Recorder) record(task string, value any, stack []byte) {
+	log.Printf("Recovered panic task=%s panic=%q\n%s", task, fmt.Sprint(value), stack)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats, ok := r.tasks[task]
+	if !ok {
+		stats = &taskStats{}
+		r.tasks[task] = stats
+	}
+	stats.panics++
+	stats.lastPanic = fmt.Sprint(value)
+	stats.lastPanicAt = time.Now()
+}
+
+// Snapshot returns the tasks that panicked, by task name
+func (r *Recorder) Snapshot() []TaskSnapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	snapshots := make([]TaskSnapshot, 0, len(r.tasks))
+	for task, stats := range r.tasks {
+		snapshots = append(snapshots, TaskSnapshot{
+			Task:        task,
+			Panics:      stats.panics,
+			LastPanic:   stats.lastPanic,
+			LastPanicAt: stats.lastPanicAt,
+		})
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Task < snapshots[j].Task })
+	return snapshots
+}
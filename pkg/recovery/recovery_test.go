@@ -0,0 +1,69 @@
+package recovery
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func snapshotOf(t *testing.T, task string) TaskSnapshot {
+	t.Helper()
+	for _, s := range Panics.Snapshot() {
+		if s.Task == task {
+			return s
+		}
+	}
+	t.Fatalf("no panics recorded for %s", task)
+	return TaskSnapshot{}
+}
+
+func TestDo(t *testing.T) {
+	ran := 0
+	for i := 0; i < 3; i++ {
+		panicked := Do("test.do", func() {
+			ran++
+			if ran == 2 {
+				panic("bad market")
+			}
+		})
+		assert.Equal(t, ran == 2, panicked)
+	}
+	assert.Equal(t, 3, ran, "a panic skips only its own unit of work")
+
+	s := snapshotOf(t, "test.do")
+	assert.Equal(t, int64(1), s.Panics)
+	assert.Equal(t, "bad market", s.LastPanic)
+	assert.False(t, s.LastPanicAt.IsZero())
+}
+
+func TestCall(t *testing.T) {
+	err := Call("test.call", func() error { panic("bad payload") })
+	assert.EqualError(t, err, "panic: bad payload")
+	assert.Equal(t, int64(1), snapshotOf(t, "test.call").Panics)
+
+	assert.NoError(t, Call("test.call", func() error { return nil }))
+}
+
+func TestGo(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	Go("test.go", func() {
+		defer wg.Done()
+		var m map[string]int
+		m["x"] = 1
+	})
+	wg.Wait()
+
+	require.Eventually(t, func() bool {
+		for _, s := range Panics.Snapshot() {
+			if s.Task == "test.go" {
+				return true
+			}
+		}
+		return false
+	}, time.Second, time.Millisecond)
+	assert.Contains(t, snapshotOf(t, "test.go").LastPanic, "nil map")
+}
@@ -0,0 +1,174 @@
+// Package symbol maps exchange-neutral instrument identifiers such as
+// BTC/KRW to the market codes of each exchange, such as Upbit's KRW-BTC
+package symbol
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Upbit is the name Upbit's format is registered under
+const Upbit = "upbit"
+
+var (
+	ErrInvalidSymbol   = errors.New("symbol must be BASE/QUOTE, e.g. BTC/KRW")
+	ErrUnknownExchange = errors.New("unknown exchange")
+)
+
+// Symbol is an exchange-neutral instrument: Base priced in Quote
+type Symbol struct {
+	Base  string
+	Quote string
+}
+
+// Parse parses a normalized symbol such as BTC/KRW, case-insensitively
+func Parse(s string) (Symbol, error) {
+	base, quote, ok := strings.Cut(strings.ToUpper(strings.TrimSpace(s)), "/")
+	if !ok || !validAsset(base) || !validAsset(quote) {
+		return Symbol{}, ErrInvalidSymbol
+	}
+	return Symbol{Base: base, Quote: quote}, nil
+}
+
+// String returns the normalized form, e.g. BTC/KRW
+func (s Symbol) String() string {
+	return s.Base + "/" + s.Quote
+}
+
+// validAsset reports whether s is a plausible asset ticker
+func validAsset(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// Format converts between symbols and one exchange's market codes
+type Format interface {
+	Code(s Symbol) string
+	Parse(code string) (Symbol, bool)
+}
+
+// UpbitFormat writes markets quote first, e.g. KRW-BTC
+type UpbitFormat struct{}
+
+// Code returns the Upbit market code of a symbol
+func (UpbitFormat) Code(s Symbol) string {
+	return s.Quote + "-" + s.Base
+}
+
+// Parse parses an Upbit market code
+func (UpbitFormat) Parse(code string) (Symbol, bool) {
+	quote, base, ok := strings.Cut(strings.ToUpper(code), "-")
+	if !ok || !validAsset(base) || !validAsset(quote) {
+		return Symbol{}, false
+	}
+	return Symbol{Base: base, Quote: quote}, true
+}
+
+// exchange is a registered format with the codes that don't follow it
+type exchange struct {
+	format  Format
+	codes   map[Symbol]string
+	symbols map[string]Symbol
+}
+
+// Registry maps symbols to the market codes of registered exchanges. It is
+// safe for concurrent use.
+type Registry struct {
+	exchanges map[string]*exchange
+	mu        sync.RWMutex
+}
+
+// NewRegistry creates a registry with Upbit's format registered
+func NewRegistry() *Registry {
+	r := &Registry{exchanges: make(map[string]*exchange)}
+	r.Register(Upbit, UpbitFormat{})
+	return r
+}
+
+// Register adds an exchange or replaces its format
+func (r *Registry) Register(name string, format Format) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.exchanges[name] = &exchange{
+		format:  format,
+		codes:   make(map[Symbol]string),
+		symbols: make(map[string]Symbol),
+	}
+}
+
+// Alias maps a symbol to a code its exchange's format wouldn't produce, for
+// assets an exchange lists under another ticker
+func (r *Registry) Alias(name string, s Symbol, code string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ex, ok := r.exchanges[name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownExchange, name)
+	}
+	ex.codes[s] = code
+	ex.symbols[code] = s
+	return nil
+}
+
+// Code returns the exchange's market code of a symbol
+func (r *Registry) Code(name string, s Symbol) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ex, ok := r.exchanges[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownExchange, name)
+	}
+	if code, ok := ex.codes[s]; ok {
+		return code, nil
+	}
+	return ex.format.Code(s), nil
+}
+
+// Symbol returns the symbol of one of the exchange's market codes
+func (r *Registry) Symbol(name, code string) (Symbol, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	ex, ok := r.exchanges[name]
+	if !ok {
+		return Symbol{}, fmt.Errorf("%w: %s", ErrUnknownExchange, name)
+	}
+	if s, ok := ex.symbols[code]; ok {
+		return s, nil
+	}
+	s, ok := ex.format.Parse(code)
+	if !ok {
+		return Symbol{}, fmt.Errorf("invalid %s market code %q", name, code)
+	}
+	return s, nil
+}
+
+// Resolve returns the exchange's market code for either a normalized symbol
+// or a code already in the exchange's format, so configs and requests can
+// use whichever they like
+func (r *Registry) Resolve(name, market string) (string, error) {
+	if !strings.Contains(market, "/") {
+		if _, err := r.Symbol(name, market); err != nil {
+			return "", err
+		}
+		return market, nil
+	}
+
+	s, err := Parse(market)
+	if err != nil {
+		return "", err
+	}
+	return r.Code(name, s)
+}
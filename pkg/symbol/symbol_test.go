@@ -0,0 +1,53 @@
+package symbol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	s, err := Parse(" btc/krw ")
+	require.NoError(t, err)
+	assert.Equal(t, Symbol{Base: "BTC", Quote: "KRW"}, s)
+	assert.Equal(t, "BTC/KRW", s.String())
+
+	for _, invalid := range []string{"", "BTC", "BTC/", "/KRW", "BTC-KRW", "BT C/KRW"} {
+		_, err := Parse(invalid)
+		assert.ErrorIs(t, err, ErrInvalidSymbol, invalid)
+	}
+}
+
+func TestRegistry_Resolve(t *testing.T) {
+	r := NewRegistry()
+
+	code, err := r.Resolve(Upbit, "BTC/KRW")
+	require.NoError(t, err)
+	assert.Equal(t, "KRW-BTC", code)
+
+	code, err = r.Resolve(Upbit, "KRW-ETH")
+	require.NoError(t, err)
+	assert.Equal(t, "KRW-ETH", code)
+
+	_, err = r.Resolve(Upbit, "KRWBTC")
+	assert.Error(t, err)
+	_, err = r.Resolve("bithumb", "BTC/KRW")
+	assert.ErrorIs(t, err, ErrUnknownExchange)
+}
+
+func TestRegistry_Alias(t *testing.T) {
+	r := NewRegistry()
+	usdt := Symbol{Base: "BTC", Quote: "USDT"}
+	require.NoError(t, r.Alias(Upbit, usdt, "USDT-XBT"))
+
+	code, err := r.Code(Upbit, usdt)
+	require.NoError(t, err)
+	assert.Equal(t, "USDT-XBT", code)
+
+	s, err := r.Symbol(Upbit, "USDT-XBT")
+	require.NoError(t, err)
+	assert.Equal(t, usdt, s)
+
+	assert.ErrorIs(t, r.Alias("bithumb", usdt, "BTC_USDT"), ErrUnknownExchange)
+}
@@ -0,0 +1,144 @@
+// Package telegram is a minimal Telegram Bot API client covering long-polling
+// for updates and sending text messages
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// DefaultBaseURL is the Telegram Bot API endpoint
+const DefaultBaseURL = "https://api.telegram.org"
+
+// Update is an incoming update; only messages are supported
+type Update struct {
+	UpdateID int64    `json:"update_id"`
+	Message  *Message `json:"message,omitempty"`
+	// ChannelPost is a post to a channel the bot is an admin of
+	ChannelPost *Message `json:"channel_post,omitempty"`
+}
+
+// Message is a chat message
+type Message struct {
+	MessageID int64  `json:"message_id"`
+	From      *User  `json:"from,omitempty"`
+	Chat      Chat   `json:"chat"`
+	Date      int64  `json:"date"`
+	Text      string `json:"text,omitempty"`
+}
+
+// User is a Telegram user or bot
+type User struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username,omitempty"`
+}
+
+// Chat is the chat a message belongs to
+type Chat struct {
+	ID   int64  `json:"id"`
+	Type string `json:"type"` // e.g., "private"
+}
+
+// Error is an error returned by the Bot API
+type Error struct {
+	Code        int
+	Description string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("telegram error %d: %s", e.Code, e.Description)
+}
+
+// Client calls the Bot API of a single bot
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient creates a new client for the bot with the given token
+func NewClient(token string) *Client {
+	return NewClientWithBaseURL(token, DefaultBaseURL)
+}
+
+// NewClientWithBaseURL creates a new client against a custom endpoint, e.g. a
+// test server
+func NewClientWithBaseURL(token, baseURL string) *Client {
+	return &Client{
+		baseURL: baseURL,
+		token:   token,
+		// Long polls hold the request open; callers bound them with the context
+		httpClient: &http.Client{},
+	}
+}
+
+// GetUpdates long-polls for updates after offset, waiting up to timeout for
+// one to arrive. Pass the last update ID + 1 as offset to acknowledge updates.
+func (c *Client) GetUpdates(ctx context.Context, offset int64, timeout time.Duration) ([]Update, error) {
+	var updates []Update
+	err := c.call(ctx, "getUpdates", map[string]any{
+		"offset":          offset,
+		"timeout":         int(timeout.Seconds()),
+		"allowed_updates": []string{"message", "channel_post"},
+	}, &updates)
+	return updates, err
+}
+
+// SendMessage sends a plain text message to a chat
+func (c *Client) SendMessage(ctx context.Context, chatID int64, text string) error {
+	return c.call(ctx, "sendMessage", map[string]any{
+		"chat_id": chatID,
+		"text":    text,
+	}, nil)
+}
+
+// call invokes a Bot API method and decodes its result into dest
+func (c *Client) call(ctx context.Context, method string, params map[string]any, dest any) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/bot%s/%s", c.baseURL, c.token, method), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// The URL contains the token; don't let it leak into logs
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("failed to call %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		OK          bool            `json:"ok"`
+		Result      json.RawMessage `json:"result"`
+		ErrorCode   int             `json:"error_code"`
+		Description string          `json:"description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", method, err)
+	}
+	if !result.OK {
+		return &Error{Code: result.ErrorCode, Description: result.Description}
+	}
+
+	if dest != nil {
+		if err := json.Unmarshal(result.Result, dest); err != nil {
+			return fmt.Errorf("failed to decode %s result: %w", method, err)
+		}
+	}
+	return nil
+}
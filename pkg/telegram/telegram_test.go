@@ -0,0 +1,58 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_GetUpdatesAndSendMessage(t *testing.T) {
+	var sent map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/bottoken/getUpdates":
+			var params map[string]any
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&params))
+			assert.Equal(t, 42.0, params["offset"])
+			assert.Equal(t, 30.0, params["timeout"])
+			w.Write([]byte(`{"ok":true,"result":[{"update_id":42,"message":{"message_id":1,"chat":{"id":7,"type":"private"},"text":"/help"}}]}`))
+		case "/bottoken/sendMessage":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&sent))
+			w.Write([]byte(`{"ok":true,"result":{}}`))
+		default:
+			w.Write([]byte(`{"ok":false,"error_code":404,"description":"Not Found"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClientWithBaseURL("token", server.URL)
+	ctx := context.Background()
+
+	updates, err := client.GetUpdates(ctx, 42, 30*time.Second)
+	require.NoError(t, err)
+	require.Len(t, updates, 1)
+	assert.Equal(t, int64(7), updates[0].Message.Chat.ID)
+	assert.Equal(t, "/help", updates[0].Message.Text)
+
+	require.NoError(t, client.SendMessage(ctx, 7, "hello"))
+	assert.Equal(t, map[string]any{"chat_id": 7.0, "text": "hello"}, sent)
+}
+
+func TestClient_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"ok":false,"error_code":403,"description":"Forbidden: bot was blocked by the user"}`))
+	}))
+	defer server.Close()
+
+	err := NewClientWithBaseURL("token", server.URL).SendMessage(context.Background(), 7, "hello")
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, 403, apiErr.Code)
+}
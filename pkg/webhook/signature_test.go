@@ -0,0 +1,22 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	body := []byte(`{"type":"order.filled"}`)
+	sentAt := time.Unix(1767225600, 0)
+	header := Sign("secret", sentAt, body)
+
+	assert.Regexp(t, `^t=1767225600,v1=[0-9a-f]{64}$`, header)
+	assert.NoError(t, Verify("secret", header, body, sentAt.Add(time.Minute), 5*time.Minute))
+
+	assert.ErrorIs(t, Verify("other", header, body, sentAt, 0), ErrInvalidSignature)
+	assert.ErrorIs(t, Verify("secret", header, []byte(`{}`), sentAt, 0), ErrInvalidSignature)
+	assert.ErrorIs(t, Verify("secret", "v1=abc", body, sentAt, 0), ErrInvalidSignature)
+	assert.ErrorIs(t, Verify("secret", header, body, sentAt.Add(time.Hour), 5*time.Minute), ErrExpiredSignature)
+}